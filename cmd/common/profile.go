@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+	"sync"
+
+	internalcommon "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ProfileFlag is the name of the flag that contains the profiles to collect
+	ProfileFlag = "profile"
+	// ProfileDirFlag is the name of the flag that contains the directory where the profiles are written
+	ProfileDirFlag = "profile-dir"
+	// CPUProfile collects a pprof CPU profile
+	CPUProfile = "cpu"
+	// MemProfile collects a pprof heap profile at the end of the command
+	MemProfile = "mem"
+	// GoroutineProfile collects a pprof goroutine profile at the end of the command
+	GoroutineProfile = "goroutine"
+	// TraceProfile collects an execution trace, which contains the regions of the slowest steps, like the translators
+	TraceProfile = "trace"
+)
+
+// ProfileOptions are the valid values of the profile flag
+var ProfileOptions = []string{CPUProfile, MemProfile, GoroutineProfile, TraceProfile}
+
+// ProfileFlags contains the flags used to profile a command
+type ProfileFlags struct {
+	// Profiles are the profiles to collect
+	Profiles []string
+	// ProfileDir is the directory where the profiles are written
+	ProfileDir string
+}
+
+// AddProfileFlags adds the flags needed to profile the command
+func AddProfileFlags(cmd *cobra.Command, flags *ProfileFlags) {
+	cmd.Flags().StringSliceVar(&flags.Profiles, ProfileFlag, []string{}, "Collect profiles for performance debugging. Valid values are "+strings.Join(ProfileOptions, ", ")+".")
+	cmd.Flags().StringVar(&flags.ProfileDir, ProfileDirFlag, ".", "Specify the directory where the profiles are written.")
+}
+
+// StartProfiling starts collecting the profiles. It returns the function which stops collecting them, writes them and closes their files.
+// The profiles are also written when the command exits using log.Fatal.
+func StartProfiling(flags ProfileFlags) (func() error, error) {
+	if len(flags.Profiles) == 0 {
+		return func() error { return nil }, nil
+	}
+	for _, profile := range flags.Profiles {
+		if !internalcommon.IsStringPresent(ProfileOptions, profile) {
+			return nil, fmt.Errorf("invalid value %s for --%s . Valid values are %s", profile, ProfileFlag, strings.Join(ProfileOptions, ", "))
+		}
+	}
+	if err := os.MkdirAll(flags.ProfileDir, internalcommon.DefaultDirectoryPermission); err != nil {
+		return nil, fmt.Errorf("failed to create the profile directory at path %s . Error: %q", flags.ProfileDir, err)
+	}
+	getProfilePath := func(profile, ext string) string {
+		return filepath.Join(flags.ProfileDir, types.AppNameShort+"-"+profile+ext)
+	}
+	stops := []func() error{}
+	stopAll := func() error {
+		var firstErr error
+		for _, stop := range stops {
+			if err := stop(); err != nil {
+				log.Error(err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		return firstErr
+	}
+	if internalcommon.IsStringPresent(flags.Profiles, CPUProfile) {
+		cpuPath := getProfilePath(CPUProfile, ".pprof")
+		cpuFile, err := os.Create(cpuPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the CPU profile at path %s . Error: %q", cpuPath, err)
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			cpuFile.Close()
+			return nil, fmt.Errorf("failed to start the CPU profile. Error: %q", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				return fmt.Errorf("failed to close the CPU profile at path %s . Error: %q", cpuPath, err)
+			}
+			log.Infof("The CPU profile can be found at [%s].", cpuPath)
+			return nil
+		})
+	}
+	if internalcommon.IsStringPresent(flags.Profiles, TraceProfile) {
+		tracePath := getProfilePath(TraceProfile, ".out")
+		traceFile, err := os.Create(tracePath)
+		if err != nil {
+			stopAll()
+			return nil, fmt.Errorf("failed to create the execution trace at path %s . Error: %q", tracePath, err)
+		}
+		if err := trace.Start(traceFile); err != nil {
+			traceFile.Close()
+			stopAll()
+			return nil, fmt.Errorf("failed to start the execution trace. Error: %q", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			if err := traceFile.Close(); err != nil {
+				return fmt.Errorf("failed to close the execution trace at path %s . Error: %q", tracePath, err)
+			}
+			log.Infof("The execution trace can be found at [%s].", tracePath)
+			return nil
+		})
+	}
+	for _, profile := range []string{MemProfile, GoroutineProfile} {
+		if !internalcommon.IsStringPresent(flags.Profiles, profile) {
+			continue
+		}
+		profile := profile
+		stops = append(stops, func() error {
+			return writeProfile(profile, getProfilePath(profile, ".pprof"))
+		})
+	}
+	once := sync.Once{}
+	var stopErr error
+	stop := func() error {
+		once.Do(func() { stopErr = stopAll() })
+		return stopErr
+	}
+	log.RegisterExitHandler(func() { stop() })
+	return stop, nil
+}
+
+// writeProfile writes the heap or goroutine profile as it is at the end of the command
+func writeProfile(profile, profilePath string) error {
+	name := "goroutine"
+	if profile == MemProfile {
+		// Get up to date statistics of the allocations
+		runtime.GC()
+		name = "heap"
+	}
+	profileFile, err := os.Create(profilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create the %s profile at path %s . Error: %q", profile, profilePath, err)
+	}
+	if err := pprof.Lookup(name).WriteTo(profileFile, 0); err != nil {
+		profileFile.Close()
+		return fmt.Errorf("failed to write the %s profile to the file at path %s . Error: %q", profile, profilePath, err)
+	}
+	if err := profileFile.Close(); err != nil {
+		return fmt.Errorf("failed to close the %s profile at path %s . Error: %q", profile, profilePath, err)
+	}
+	log.Infof("The %s profile can be found at [%s].", profile, profilePath)
+	return nil
+}
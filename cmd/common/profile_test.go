@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/types"
+)
+
+// getOpenFiles returns the files opened by the process, or nil if they can not be listed
+func getOpenFiles() map[string]bool {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil
+	}
+	openFiles := map[string]bool{}
+	for _, fd := range fds {
+		if path, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil {
+			openFiles[path] = true
+		}
+	}
+	return openFiles
+}
+
+func TestStartProfiling(t *testing.T) {
+	t.Run("cpu and heap profiles are written and closed", func(t *testing.T) {
+		// The open files are listed using their real paths
+		tempDir, err := filepath.EvalSymlinks(t.TempDir())
+		if err != nil {
+			t.Fatalf("Failed to resolve the temporary directory. Error: %q", err)
+		}
+		profileDir := filepath.Join(tempDir, "profiles")
+		stop, err := StartProfiling(ProfileFlags{Profiles: []string{CPUProfile, MemProfile}, ProfileDir: profileDir})
+		if err != nil {
+			t.Fatalf("Failed to start profiling. Error: %q", err)
+		}
+		cpuPath := filepath.Join(profileDir, types.AppNameShort+"-"+CPUProfile+".pprof")
+		memPath := filepath.Join(profileDir, types.AppNameShort+"-"+MemProfile+".pprof")
+		if openFiles := getOpenFiles(); openFiles != nil && !openFiles[cpuPath] {
+			t.Fatalf("Expected the CPU profile at path %s to be open while profiling", cpuPath)
+		}
+		if err := stop(); err != nil {
+			t.Fatalf("Failed to stop profiling. Error: %q", err)
+		}
+		for _, profilePath := range []string{cpuPath, memPath} {
+			fi, err := os.Stat(profilePath)
+			if err != nil || fi.Size() == 0 {
+				t.Fatalf("Expected the profile at path %s to be written. Error: %v", profilePath, err)
+			}
+		}
+		if openFiles := getOpenFiles(); openFiles[cpuPath] || openFiles[memPath] {
+			t.Fatalf("Expected the profiles to be closed. Open files: %v", openFiles)
+		}
+		if err := stop(); err != nil {
+			t.Fatalf("Expected stopping again to do nothing. Error: %q", err)
+		}
+	})
+
+	t.Run("no profiles", func(t *testing.T) {
+		profileDir := filepath.Join(t.TempDir(), "profiles")
+		stop, err := StartProfiling(ProfileFlags{ProfileDir: profileDir})
+		if err != nil || stop() != nil {
+			t.Fatalf("Expected no error without profiles. Error: %v", err)
+		}
+		if _, err := os.Stat(profileDir); err == nil {
+			t.Fatalf("Expected the profile directory not to be created without profiles")
+		}
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		if _, err := StartProfiling(ProfileFlags{Profiles: []string{"block"}, ProfileDir: t.TempDir()}); err == nil {
+			t.Fatalf("Expected an error for the invalid profile")
+		}
+	})
+
+	// A file is in the way of the profile directory
+	filePath := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(filePath, []byte("not a directory"), 0644); err != nil {
+		t.Fatalf("Failed to write the file at path %s . Error: %q", filePath, err)
+	}
+
+	t.Run("bad profile directory", func(t *testing.T) {
+		if _, err := StartProfiling(ProfileFlags{Profiles: []string{CPUProfile}, ProfileDir: filepath.Join(filePath, "profiles")}); err == nil {
+			t.Fatalf("Expected an error for the profile directory inside a file")
+		}
+	})
+
+	t.Run("bad heap profile path", func(t *testing.T) {
+		profileDir := filepath.Join(t.TempDir(), "profiles")
+		stop, err := StartProfiling(ProfileFlags{Profiles: []string{MemProfile}, ProfileDir: profileDir})
+		if err != nil {
+			t.Fatalf("Failed to start profiling. Error: %q", err)
+		}
+		if err := os.Remove(profileDir); err != nil {
+			t.Fatalf("Failed to remove the profile directory. Error: %q", err)
+		}
+		if err := os.Rename(filePath, profileDir); err != nil {
+			t.Fatalf("Failed to replace the profile directory with a file. Error: %q", err)
+		}
+		if err := stop(); err == nil {
+			t.Fatalf("Expected an error for the heap profile which could not be written")
+		}
+	})
+}
@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	internalcommon "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// RunInContainerFlag is the name of the flag that runs the selected command inside the move2kube container image
+	RunInContainerFlag = "run-in-container"
+	// ContainerImageFlag is the name of the flag that contains the image used by RunInContainerFlag
+	ContainerImageFlag = "container-image"
+	// MountDockerSocketFlag is the name of the flag that mounts the docker socket into the container used by RunInContainerFlag
+	MountDockerSocketFlag = "mount-docker-socket"
+	// DefaultContainerImage is the official move2kube image
+	DefaultContainerImage = internalcommon.DefaultRegistryURL + "/konveyor/" + types.AppName + ":latest"
+
+	dockerSocketPath = "/var/run/docker.sock"
+)
+
+// ContainerFlags contains the flags used to run move2kube inside a container
+type ContainerFlags struct {
+	// RunInContainer tells us whether to run the command inside the move2kube container image
+	RunInContainer bool
+	// Image is the move2kube image to use
+	Image string
+	// MountDockerSocket tells us whether to make the docker daemon of the host available inside the container
+	MountDockerSocket bool
+}
+
+// pathFlags are the flags across all commands whose values are paths on the host
+var pathFlags = []string{SourceFlag, OutputFlag, PlanFlag, QACacheFlag, ConfigFlag, TransformsFlag, ProfileDirFlag, "artifacts"}
+
+// AddContainerFlags adds the flags needed to run move2kube inside a container
+func AddContainerFlags(cmd *cobra.Command, flags *ContainerFlags) {
+	cmd.PersistentFlags().BoolVar(&flags.RunInContainer, RunInContainerFlag, false, "Run the command inside the move2kube container image. Useful when pack, cf, s2i, etc. are not installed locally.")
+	cmd.PersistentFlags().StringVar(&flags.Image, ContainerImageFlag, DefaultContainerImage, "The move2kube image to use with --"+RunInContainerFlag+".")
+	cmd.PersistentFlags().BoolVar(&flags.MountDockerSocket, MountDockerSocketFlag, false, "Mount the docker socket into the container when using --"+RunInContainerFlag+". Required for CNB containerization.")
+}
+
+// RunInContainer runs the current command line inside the move2kube container image with the current working directory
+// and all the paths given on the command line bind-mounted at the same locations. It exits with the exit code of the container.
+func RunInContainer(cmd *cobra.Command, flags ContainerFlags) {
+	engine := ""
+	for _, e := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(e); err == nil {
+			engine = e
+			break
+		}
+	}
+	if engine == "" {
+		log.Fatalf("Unable to find docker or podman. One of them is required for --%s", RunInContainerFlag)
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Failed to get the current working directory. Error: %q", err)
+	}
+	args := []string{"run", "--rm", "-i"}
+	if isTerminal(os.Stdin) {
+		args = append(args, "-t")
+	}
+	if engine == "docker" && runtime.GOOS == "linux" {
+		// Avoid root owned output files on the host
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	for _, mount := range getContainerMounts(pwd, getPathFlagValues(cmd)) {
+		args = append(args, "-v", mount+":"+mount)
+	}
+	if flags.MountDockerSocket {
+		args = append(args, "-v", dockerSocketPath+":"+dockerSocketPath)
+	}
+	args = append(args, "-w", pwd, flags.Image, types.AppName)
+	args = append(args, RemoveContainerFlags(os.Args[1:])...)
+	log.Infof("Running inside the container image %s using %s", flags.Image, engine)
+	log.Debugf("Executing %s %s", engine, strings.Join(args, " "))
+	c := exec.Command(engine, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	exitCode := 0
+	if err := c.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else {
+			log.Errorf("Failed to run the container. Error: %q", err)
+			exitCode = 1
+		}
+	}
+	os.RemoveAll(internalcommon.TempPath)
+	os.Exit(exitCode)
+}
+
+// RemoveContainerFlags removes the flags specific to running inside a container from the command line arguments
+func RemoveContainerFlags(args []string) []string {
+	boolFlags := []string{RunInContainerFlag, MountDockerSocketFlag}
+	valueFlags := []string{ContainerImageFlag}
+	filtered := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			filtered = append(filtered, args[i:]...)
+			break
+		}
+		name := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0]
+		if !strings.HasPrefix(arg, "--") {
+			filtered = append(filtered, arg)
+			continue
+		}
+		if internalcommon.IsStringPresent(boolFlags, name) {
+			continue
+		}
+		if internalcommon.IsStringPresent(valueFlags, name) {
+			if !strings.Contains(arg, "=") {
+				i++
+			}
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
+
+func getPathFlagValues(cmd *cobra.Command) []string {
+	paths := []string{}
+	for _, name := range pathFlags {
+		f := cmd.Flags().Lookup(name)
+		if f == nil || !f.Changed {
+			continue
+		}
+		switch f.Value.Type() {
+		case "string":
+			if v, err := cmd.Flags().GetString(name); err == nil {
+				paths = append(paths, v)
+			}
+		case "stringSlice":
+			if vs, err := cmd.Flags().GetStringSlice(name); err == nil {
+				paths = append(paths, vs...)
+			}
+		case "stringArray":
+			if vs, err := cmd.Flags().GetStringArray(name); err == nil {
+				paths = append(paths, vs...)
+			}
+		}
+	}
+	return paths
+}
+
+// getContainerMounts returns the host directories that need to be mounted so that the working directory and all the given paths are accessible.
+// A directory is not mounted if one of its parents is mounted, and the root of the file system is never mounted.
+func getContainerMounts(pwd string, paths []string) []string {
+	mounts := []string{pwd}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			log.Warnf("Failed to make the path %s absolute. It might not be accessible inside the container. Error: %q", path, err)
+			continue
+		}
+		// The path might not exist yet (eg: output directory), so mount the closest existing parent.
+		for {
+			if _, err := os.Stat(absPath); err == nil {
+				break
+			}
+			absPath = filepath.Dir(absPath)
+			if isFileSystemRoot(absPath) {
+				break
+			}
+		}
+		if fi, err := os.Stat(absPath); err == nil && !fi.IsDir() {
+			absPath = filepath.Dir(absPath)
+		}
+		if isFileSystemRoot(absPath) {
+			// Mounting the root of the host would expose all of it to the container
+			log.Warnf("The path %s has no existing parent directory other than the root %s . It is not mounted and might not be accessible inside the container.", path, absPath)
+			continue
+		}
+		covered := false
+		for _, mount := range mounts {
+			if internalcommon.IsParent(absPath, mount) {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		// The mounts inside the path are not needed anymore
+		remainingMounts := []string{}
+		for _, mount := range mounts {
+			if !internalcommon.IsParent(mount, absPath) {
+				remainingMounts = append(remainingMounts, mount)
+			}
+		}
+		mounts = append(remainingMounts, absPath)
+	}
+	return mounts
+}
+
+// isFileSystemRoot returns true if the absolute path is the root of the file system, or of a volume on Windows
+func isFileSystemRoot(absPath string) bool {
+	return filepath.Dir(absPath) == absPath
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
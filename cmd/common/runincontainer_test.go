@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRemoveContainerFlags(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "no container flags", args: []string{"translate", "-s", "src", "--qaskip"}, want: []string{"translate", "-s", "src", "--qaskip"}},
+		{name: "bool flags", args: []string{"translate", "--run-in-container", "-s", "src", "--mount-docker-socket=true"}, want: []string{"translate", "-s", "src"}},
+		{name: "value flag with equals", args: []string{"translate", "--container-image=quay.io/myns/move2kube:v1", "-o", "out"}, want: []string{"translate", "-o", "out"}},
+		{name: "value flag with separate value", args: []string{"translate", "--container-image", "quay.io/myns/move2kube:v1", "-o", "out"}, want: []string{"translate", "-o", "out"}},
+		{name: "stops at the double dash", args: []string{"translate", "--run-in-container", "--", "--run-in-container", "--container-image", "img"}, want: []string{"translate", "--", "--run-in-container", "--container-image", "img"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := RemoveContainerFlags(testCase.args); !reflect.DeepEqual(actual, testCase.want) {
+				t.Fatalf("Expected the args %v . Actual: %v", testCase.want, actual)
+			}
+		})
+	}
+}
+
+func TestGetContainerMounts(t *testing.T) {
+	pwd := filepath.Join(t.TempDir(), "work")
+	other := t.TempDir()
+	for _, dir := range []string{filepath.Join(pwd, "src"), filepath.Join(other, "data")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create the directory %s . Error: %q", dir, err)
+		}
+	}
+	configPath := filepath.Join(other, "data", "config.yaml")
+	if err := ioutil.WriteFile(configPath, []byte("move2kube: {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the file %s . Error: %q", configPath, err)
+	}
+	testCases := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{name: "no paths", paths: nil, want: []string{pwd}},
+		{name: "empty path", paths: []string{""}, want: []string{pwd}},
+		{name: "path inside the working directory", paths: []string{filepath.Join(pwd, "src")}, want: []string{pwd}},
+		{name: "file", paths: []string{configPath}, want: []string{pwd, filepath.Join(other, "data")}},
+		{name: "output path that does not exist yet", paths: []string{filepath.Join(other, "output", "myproject")}, want: []string{pwd, other}},
+		{name: "duplicate paths", paths: []string{filepath.Join(other, "data"), filepath.Join(other, "data") + string(os.PathSeparator), configPath}, want: []string{pwd, filepath.Join(other, "data")}},
+		{name: "nested paths with the parent last", paths: []string{filepath.Join(other, "data"), other}, want: []string{pwd, other}},
+		{name: "parent of the working directory", paths: []string{filepath.Dir(pwd)}, want: []string{filepath.Dir(pwd)}},
+		{name: "path without an existing parent", paths: []string{filepath.Join(string(os.PathSeparator), "m2k-missing-dir", "output")}, want: []string{pwd}},
+		{name: "root of the file system", paths: []string{string(os.PathSeparator)}, want: []string{pwd}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := getContainerMounts(pwd, testCase.paths); !reflect.DeepEqual(actual, testCase.want) {
+				t.Fatalf("Expected the mounts %v . Actual: %v", testCase.want, actual)
+			}
+		})
+	}
+}
@@ -21,9 +21,11 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	internalcommon "github.com/konveyor/move2kube/internal/common"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 )
 
 const (
@@ -41,6 +43,16 @@ const (
 	IgnoreEnvFlag = "ignoreenv"
 	// QASkipFlag is the name of the flag that lets you skip all the question answers
 	QASkipFlag = "qaskip"
+	// QADisableFlag is the name of the flag that contains the list of QA categories whose questions are answered using the defaults manifest
+	QADisableFlag = "qa-disable"
+	// QADefaultsFlag is the name of the flag that contains the path to the defaults manifest
+	QADefaultsFlag = "qa-defaults"
+	// QAFileIOFlag is the name of the flag that contains the directory where the questions and answers are exchanged as json files
+	QAFileIOFlag = "qa-file-io"
+	// QAStorageFlag is the name of the flag that contains the location where the config and the cache written by the QA engine are kept
+	QAStorageFlag = "qa-storage"
+	// OnlyDeferredFlag is the name of the flag that reruns the translation asking only the questions whose answers were deferred
+	OnlyDeferredFlag = "only-deferred"
 	// ConfigFlag is the name of the flag that contains list of config files
 	ConfigFlag = "config"
 	// SetConfigFlag is the name of the flag that contains list of key-value configs
@@ -51,8 +63,63 @@ const (
 	OverwriteFlag = "overwrite"
 	// TransformsFlag is the name of the flag that lets you specify a list of paths to transformations scripts
 	TransformsFlag = "transforms"
+	// ToolTimeoutFlag is the name of the flag that sets the maximum time an external tool invocation is allowed to run
+	ToolTimeoutFlag = "tooltimeout"
+	// ToolMaxOutputFlag is the name of the flag that sets the maximum number of bytes captured from the output of an external tool
+	ToolMaxOutputFlag = "toolmaxoutput"
+	// ToolRetriesFlag is the name of the flag that sets the number of times an external tool invocation which timed out or could not be started is retried
+	ToolRetriesFlag = "toolretries"
+	// OfflineFlag is the name of the flag that disables the commands that need internet access
+	OfflineFlag = "offline"
+	// ChannelFlag is the name of the flag that contains the release channel used to look for new versions
+	ChannelFlag = "channel"
+	// SignFlag is the name of the flag that contains the tool used to sign the packaged artifacts
+	SignFlag = "sign"
+	// SignKeyFlag is the name of the flag that contains the key used to sign the packaged artifacts
+	SignKeyFlag = "sign-key"
+	// OnServiceErrorFlag is the name of the flag that contains how a failed step of a service is handled during the translation
+	OnServiceErrorFlag = "on-service-error"
+	// IncludeKindsFlag is the name of the flag that contains the kinds of the kubernetes resources that are translated
+	IncludeKindsFlag = "include-kinds"
+	// ExcludeKindsFlag is the name of the flag that contains the kinds of the kubernetes resources that are not translated
+	ExcludeKindsFlag = "exclude-kinds"
+	// IncludeNamespacesFlag is the name of the flag that contains the namespaces of the kubernetes resources that are translated
+	IncludeNamespacesFlag = "include-namespaces"
+	// ExcludeNamespacesFlag is the name of the flag that contains the namespaces of the kubernetes resources that are not translated
+	ExcludeNamespacesFlag = "exclude-namespaces"
+	// ScrubRulesFlag is the name of the flag that contains the scrub rules applied to the kubernetes resources
+	ScrubRulesFlag = "scrub-rules"
+	// ScrubAllowFlag is the name of the flag that contains the annotations, labels and finalizers that are never scrubbed
+	ScrubAllowFlag = "scrub-allow"
+	// ScrubDenyFlag is the name of the flag that contains the annotations, labels and finalizers that are always scrubbed
+	ScrubDenyFlag = "scrub-deny"
+	// OutputFormatFlag is the name of the flag that contains the format of the plan and the collected metadata
+	OutputFormatFlag = "output-format"
+	// SecretScanFlag is the name of the flag that contains how the credentials found in the sources copied into the build contexts are handled
+	SecretScanFlag = "secret-scan"
+	// AnalysisFlag is the name of the flag that imports the results of rule engines, like the Konveyor analyzer, into the plan
+	AnalysisFlag = "analysis"
+	// LicenseScanFlag is the name of the flag that enables the summary of the licenses of the dependencies of the services in the report
+	LicenseScanFlag = "license-scan"
+	// BuildImagesFlag is the name of the flag that builds the new images locally after the artifacts are generated
+	BuildImagesFlag = "build-images"
+	// PlanWorkersFlag is the name of the flag that contains the maximum number of translators and directories whose services are detected at the same time
+	PlanWorkersFlag = "plan-workers"
+	// WatchFlag is the name of the flag that keeps updating the plan when the source directory changes
+	WatchFlag = "watch"
+	// WatchIntervalFlag is the name of the flag that contains how often the source directory is checked for changes
+	WatchIntervalFlag = "watch-interval"
 )
 
+// OnServiceErrorOptions are the valid values of the OnServiceErrorFlag
+var OnServiceErrorOptions = []string{internalcommon.AskOnServiceError, internalcommon.RetryOnServiceError, internalcommon.SkipOnServiceError, internalcommon.AbortOnServiceError}
+
+// SecretScanOptions are the valid values of the SecretScanFlag
+var SecretScanOptions = []string{internalcommon.WarnSecretScanMode, internalcommon.BlockSecretScanMode, internalcommon.OffSecretScanMode}
+
+// OutputFormatOptions are the valid values of the OutputFormatFlag
+var OutputFormatOptions = []string{internalcommon.YamlOutputFormat, internalcommon.JSONOutputFormat}
+
 //TranslateFlags to store values from command line paramters
 type TranslateFlags struct {
 	//IgnoreEnv tells us whether to use data collected from the local machine
@@ -73,6 +140,16 @@ type TranslateFlags struct {
 	Setconfigs []string
 	//Qaskip lets you skip all the question answers
 	Qaskip bool
+	// QADisable contains the QA categories whose questions are answered using the defaults manifest
+	QADisable []string
+	// QADefaults contains the path to the defaults manifest
+	QADefaults string
+	// QAFileIODir contains the directory where the questions and answers are exchanged as json files
+	QAFileIODir string
+	// QAStorage contains the location where the config and the cache written by the QA engine are kept
+	QAStorage string
+	// OnlyDeferred reuses the answers of the previous run in the output directory and asks only the deferred questions
+	OnlyDeferred bool
 	// Overwrite lets you overwrite the output directory if it exists
 	Overwrite bool
 	//PreSets contains a list of preset configurations
@@ -128,6 +205,50 @@ func CheckOutputPath(outpath string, overwrite bool) {
 	log.Infof("Output directory %s exists. The contents might get overwritten.", outpath)
 }
 
+// CheckOnServiceError checks the handling of the failed steps of the services
+func CheckOnServiceError() {
+	if !internalcommon.IsStringPresent(OnServiceErrorOptions, internalcommon.OnServiceError) {
+		log.Fatalf("Invalid value %s for --%s . Valid values are %s", internalcommon.OnServiceError, OnServiceErrorFlag, strings.Join(OnServiceErrorOptions, ", "))
+	}
+}
+
+// CheckSecretScan checks the handling of the credentials found in the sources
+func CheckSecretScan() {
+	if !internalcommon.IsStringPresent(SecretScanOptions, internalcommon.SecretScanMode) {
+		log.Fatalf("Invalid value %s for --%s . Valid values are %s", internalcommon.SecretScanMode, SecretScanFlag, strings.Join(SecretScanOptions, ", "))
+	}
+}
+
+// CheckError exits if there is an error, logging the remediation hint of the error if it has an error code
+func CheckError(err error, format string, args ...interface{}) {
+	if err == nil {
+		return
+	}
+	if codedErr := internalcommon.AsError(err); codedErr != nil {
+		log.Fatal(codedErr.Details())
+	}
+	log.Fatalf("%s Error: %q", fmt.Sprintf(format, args...), err)
+}
+
+// CheckOutputFormat exits if the output format is not valid
+func CheckOutputFormat(outputFormat string) {
+	if !internalcommon.IsStringPresent(OutputFormatOptions, outputFormat) {
+		log.Fatalf("Invalid value %s for --%s . Valid values are %s", outputFormat, OutputFormatFlag, strings.Join(OutputFormatOptions, ", "))
+	}
+}
+
+// AddK8sFilterFlags adds the flags which select the kubernetes resources that are translated using their kinds and
+// namespaces, and the flags which scrub their annotations, labels and finalizers
+func AddK8sFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&internalcommon.K8sIncludeKinds, IncludeKindsFlag, []string{}, "Specify the kinds of the kubernetes resources to translate. By default, the kinds are asked.")
+	cmd.Flags().StringSliceVar(&internalcommon.K8sExcludeKinds, ExcludeKindsFlag, []string{}, "Specify the kinds of the kubernetes resources to skip, like Event and Endpoints.")
+	cmd.Flags().StringSliceVar(&internalcommon.K8sIncludeNamespaces, IncludeNamespacesFlag, []string{}, "Specify the namespaces, or their glob patterns, of the kubernetes resources to translate. By default, the namespaces are asked.")
+	cmd.Flags().StringSliceVar(&internalcommon.K8sExcludeNamespaces, ExcludeNamespacesFlag, []string{}, "Specify the namespaces, or their glob patterns, of the kubernetes resources to skip, like kube-system and openshift-*.")
+	cmd.Flags().StringSliceVar(&internalcommon.K8sScrubRules, ScrubRulesFlag, []string{}, "Specify the rules which scrub the annotations, labels and finalizers specific to the source cluster, like kubectl, cloud-load-balancers, finalizers and tooling. By default, the rules are asked.")
+	cmd.Flags().StringSliceVar(&internalcommon.K8sScrubAllow, ScrubAllowFlag, []string{}, "Specify the keys, or their glob patterns, of the annotations, labels and finalizers to keep even if a scrub rule matches them.")
+	cmd.Flags().StringSliceVar(&internalcommon.K8sScrubDeny, ScrubDenyFlag, []string{}, "Specify the keys, or their glob patterns, of the annotations, labels and finalizers to remove, like example.com/*.")
+}
+
 // NormalizePaths cleans the paths and makes them absolute
 func NormalizePaths(paths []string) ([]string, error) {
 	newPaths := []string{}
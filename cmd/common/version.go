@@ -18,25 +18,70 @@ package common
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/move2kube"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+func versionCheckHandler(channel, planfile string) {
+	fmt.Println(move2kube.GetVersion(false))
+	if _, err := os.Stat(planfile); err == nil {
+		if err := move2kube.CheckPlanCompatibility(planfile); err != nil {
+			log.Warn(err)
+		} else {
+			log.Infof("The plan file at path %s is compatible with this version.", planfile)
+		}
+	}
+	release, err := move2kube.GetLatestRelease(channel)
+	if err != nil {
+		log.Fatalf("Failed to check for new versions. Error: %q", err)
+	}
+	newer, err := move2kube.IsNewerRelease(release)
+	if err != nil {
+		log.Fatalf("Failed to compare the versions. Error: %q", err)
+	}
+	if newer {
+		log.Infof("A newer version %s is available in the %s channel. Use the upgrade command to install it.", release.TagName, channel)
+	} else {
+		log.Infof("This is the latest version in the %s channel.", channel)
+	}
+}
+
 // GetVersionCommand returns the version
 func GetVersionCommand() *cobra.Command {
 	viper.AutomaticEnv()
 
 	long := false
+	check := false
+	channel := ""
+	planfile := ""
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version information",
 		Long:  "Print the version information",
-		Run:   func(*cobra.Command, []string) { fmt.Println(move2kube.GetVersion(long)) },
+		Run: func(*cobra.Command, []string) {
+			if check {
+				versionCheckHandler(channel, planfile)
+				return
+			}
+			fmt.Println(move2kube.GetVersion(long))
+		},
 	}
 
 	versionCmd.Flags().BoolVarP(&long, "long", "l", false, "print the version details")
+	versionCmd.Flags().BoolVar(&check, "check", false, "check for a newer version and the compatibility of the plan file")
+	versionCmd.Flags().StringVar(&channel, ChannelFlag, move2kube.StableReleaseChannel, "release channel to check. Valid values are "+move2kube.StableReleaseChannel+" and "+move2kube.PreReleaseChannel)
+	versionCmd.Flags().StringVarP(&planfile, PlanFlag, "p", common.DefaultPlanFile, "plan file to check for compatibility")
+
+	if err := versionCmd.RegisterFlagCompletionFunc(ChannelFlag, func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{move2kube.StableReleaseChannel, move2kube.PreReleaseChannel}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		panic(err)
+	}
 
 	return versionCmd
 }
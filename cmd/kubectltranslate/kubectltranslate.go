@@ -58,7 +58,11 @@ func translateHandler(cmd *cobra.Command, flags cmdcommon.TranslateFlags) {
 	if err := os.MkdirAll(flags.Outpath, common.DefaultDirectoryPermission); err != nil {
 		log.Fatalf("Failed to create the output directory at path %s Error: %q", flags.Outpath, err)
 	}
-	qaengine.StartEngine(flags.Qaskip, qaport, qadisablecli)
+	qaengine.StartEngine(flags.Qaskip, qaport, qadisablecli, flags.QAFileIODir)
+	qaengine.SetupSuppressions(flags.QADisable, flags.QADefaults)
+	if err := qaengine.SetupStorage(flags.QAStorage); err != nil {
+		log.Fatalf("Failed to set up the QA storage. Error: %q", err)
+	}
 	qaengine.SetupConfigFile(flags.Outpath, flags.Setconfigs, flags.Configs, flags.PreSets)
 	qaengine.SetupCacheFile(flags.Outpath, flags.Qacaches)
 	if err := qaengine.WriteStoresToDisk(); err != nil {
@@ -66,19 +70,24 @@ func translateHandler(cmd *cobra.Command, flags cmdcommon.TranslateFlags) {
 	}
 
 	// Plan
-	plan := move2kube.CreatePlan(flags.Srcpath, flags.Name, true)
-	plan = move2kube.CuratePlan(plan)
+	plan, err := move2kube.CreatePlan(flags.Srcpath, flags.Name, true)
+	cmdcommon.CheckError(err, "Failed to plan the source directory %s .", flags.Srcpath)
+	plan, err = move2kube.CuratePlan(plan)
+	cmdcommon.CheckError(err, "Failed to curate the plan.")
 
 	// Translate
 	normalizedTransformPaths, err := cmdcommon.NormalizePaths(flags.TransformPaths)
 	if err != nil {
 		log.Fatalf("Failed to clean the paths:\n%+v\nError: %q", flags.TransformPaths, err)
 	}
-	move2kube.Translate(plan, flags.Outpath, qadisablecli, normalizedTransformPaths)
+	cmdcommon.CheckError(move2kube.Translate(plan, flags.Outpath, qadisablecli, normalizedTransformPaths), "Failed to translate the plan.")
 	log.Infof("Translated target artifacts can be found at [%s].", flags.Outpath)
 }
 
 func main() {
+	if err := common.LoadDefaults(os.Getenv(common.DefaultsFileEnvVar)); err != nil {
+		log.Fatalf("Failed to load the defaults. Error: %q", err)
+	}
 	// Setup
 	must := func(err error) {
 		if err != nil {
@@ -117,10 +126,18 @@ For more documentation and support for this plugin and Move2Kube, visit https://
 	translateCmd.Flags().StringSliceVarP(&flags.Configs, cmdcommon.ConfigFlag, "f", []string{}, "Specify config file locations")
 	translateCmd.Flags().StringSliceVarP(&flags.PreSets, cmdcommon.PreSetFlag, "r", []string{}, "Specify preset config to use")
 	translateCmd.Flags().BoolVar(&flags.Qaskip, cmdcommon.QASkipFlag, false, "Enable/disable the default answers to questions posed in QA sub-system. If disabled, you will have to answer the questions posed by QA during interaction.")
+	translateCmd.Flags().StringSliceVar(&flags.QADisable, cmdcommon.QADisableFlag, []string{}, "Specify the QA categories, like storages or target.imageregistry, whose questions are answered using the defaults manifest. Fails if a question of these categories has no default.")
+	translateCmd.Flags().StringVar(&flags.QAFileIODir, cmdcommon.QAFileIOFlag, "", "Specify a directory where each question is written as a json file and the answer is read from a json file, for UIs and scripts.")
+	translateCmd.Flags().StringVar(&flags.QAStorage, cmdcommon.QAStorageFlag, "", "Specify where the config and the QA cache are kept, so that the answers survive the engine. Valid values are file:// for the output directory (the default), configmap://<namespace>/<name>, secret://<namespace>/<name> and an http(s):// URL under which the files are read with GET and written with PUT.")
+	translateCmd.Flags().StringVar(&flags.QADefaults, cmdcommon.QADefaultsFlag, "", "Specify the defaults manifest, a config file which must have answers for all the categories disabled using --"+cmdcommon.QADisableFlag+".")
 	translateCmd.Flags().BoolVarP(&flags.Overwrite, cmdcommon.OverwriteFlag, "", false, "Overwrite the output directory if it exists. By default we don't overwrite.")
 	translateCmd.Flags().StringArrayVarP(&flags.Setconfigs, cmdcommon.SetConfigFlag, "k", []string{}, "Specify config key-value pairs")
 	translateCmd.Flags().StringSliceVarP(&flags.TransformPaths, cmdcommon.TransformsFlag, "t", []string{}, "Specify paths to the transformation scripts to apply. Can be the path to a script or the path to a folder containing the scripts.")
 
+	// Advanced options
+	translateCmd.Flags().DurationVar(&common.CommandTimeout, cmdcommon.ToolTimeoutFlag, common.DefaultCommandTimeout, "Maximum time an external tool (docker, pack, cf, kubectl, etc.) is allowed to run before it is killed. Set to 0 to disable.")
+	translateCmd.Flags().IntVar(&common.CommandRetries, cmdcommon.ToolRetriesFlag, common.DefaultCommandRetries, "Number of times an external tool invocation which timed out or could not be started is retried. Tools which exit with an error are not retried.")
+
 	must(translateCmd.MarkFlagRequired(cmdcommon.SourceFlag))
 
 	translateCmd.AddCommand(cmdcommon.GetVersionCommand())
@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	cmdcommon "github.com/konveyor/move2kube/cmd/common"
+	"github.com/konveyor/move2kube/internal/collector"
+	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/move2kube"
 	"github.com/konveyor/move2kube/types"
 	log "github.com/sirupsen/logrus"
@@ -30,9 +32,12 @@ import (
 )
 
 type collectFlags struct {
-	annotations string
-	outpath     string
-	srcpath     string
+	annotations  string
+	outpath      string
+	srcpath      string
+	outputFormat string
+	cluster      collector.ClusterCollectorOptions
+	heroku       collector.HerokuCollectorOptions
 }
 
 func collectHandler(flags collectFlags) {
@@ -40,6 +45,7 @@ func collectHandler(flags collectFlags) {
 	annotations := flags.annotations
 	outpath := flags.outpath
 	srcpath := flags.srcpath
+	cmdcommon.CheckOutputFormat(flags.outputFormat)
 
 	if outpath != "" {
 		if outpath, err = filepath.Abs(outpath); err != nil {
@@ -61,6 +67,9 @@ func collectHandler(flags collectFlags) {
 		}
 	}
 	outpath = filepath.Join(filepath.Clean(outpath), types.AppNameShort+"_collect")
+	collector.ClusterOptions = flags.cluster
+	collector.HerokuOptions = flags.heroku
+	collector.OutputFormat = flags.outputFormat
 	if annotations == "" {
 		move2kube.Collect(srcpath, outpath, []string{})
 	} else {
@@ -74,15 +83,33 @@ func getCollectCommand() *cobra.Command {
 
 	flags := collectFlags{}
 	collectCmd := &cobra.Command{
-		Use:   "collect",
-		Short: "Collect and process metadata from multiple sources.",
-		Long:  "Collect metadata from multiple sources (cluster, image repo etc.), filter and summarize it into a yaml.",
-		Run:   func(*cobra.Command, []string) { collectHandler(flags) },
+		Use:     "collect",
+		Short:   "Collect and process metadata from multiple sources.",
+		Long:    "Collect metadata from multiple sources (cluster, image repo etc.), filter and summarize it into a yaml.",
+		Example: examples["cluster"],
+		Run:     func(*cobra.Command, []string) { collectHandler(flags) },
 	}
 
 	collectCmd.Flags().StringVarP(&flags.annotations, "annotations", "a", "", "Specify annotations to select collector subset.")
 	collectCmd.Flags().StringVarP(&flags.outpath, cmdcommon.OutputFlag, "o", ".", "Specify output directory for collect.")
 	collectCmd.Flags().StringVarP(&flags.srcpath, cmdcommon.SourceFlag, "s", "", "Specify source directory for the artifacts to be considered while collecting.")
+	collectCmd.Flags().StringVar(&flags.outputFormat, cmdcommon.OutputFormatFlag, common.YamlOutputFormat, "Specify the format of the collected metadata files. Valid values are "+strings.Join(cmdcommon.OutputFormatOptions, ", ")+".")
+
+	// Cluster options
+	collectCmd.Flags().StringVar(&flags.cluster.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use for collecting cluster metadata.")
+	collectCmd.Flags().StringVar(&flags.cluster.Context, "context", "", "The kubeconfig context to use for collecting cluster metadata. Defaults to the current context.")
+	collectCmd.Flags().StringVar(&flags.cluster.Impersonate, "as", "", "Username to impersonate while collecting cluster metadata.")
+	collectCmd.Flags().StringSliceVar(&flags.cluster.ImpersonateGroups, "asgroup", nil, "Group to impersonate while collecting cluster metadata. Can be repeated to specify multiple groups.")
+	collectCmd.Flags().Float32Var(&flags.cluster.QPS, "qps", collector.DefaultClusterQPS, "Maximum queries per second to the cluster API server.")
+	collectCmd.Flags().IntVar(&flags.cluster.Burst, "burst", collector.DefaultClusterBurst, "Maximum burst of queries to the cluster API server.")
+	collectCmd.Flags().BoolVar(&flags.cluster.WorkloadUsage, "cluster-usage", false, "Collect the cpu and memory used by the pods of the workloads from the metrics API of the cluster, to fill the resources of the matching services when planning.")
+
+	// Heroku options
+	collectCmd.Flags().BoolVar(&flags.heroku.ConfigVarValues, "heroku-config-values", false, "Collect the values of the config vars of the Heroku apps, instead of only their names. The values may contain secrets.")
+
+	if err := collectCmd.RegisterFlagCompletionFunc(cmdcommon.OutputFormatFlag, fixedCompletion(cmdcommon.OutputFormatOptions...)); err != nil {
+		panic(err)
+	}
 
 	return collectCmd
 }
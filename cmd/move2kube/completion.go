@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	cmdcommon "github.com/konveyor/move2kube/cmd/common"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	bashShell       = "bash"
+	zshShell        = "zsh"
+	fishShell       = "fish"
+	powershellShell = "powershell"
+)
+
+func completionHandler(cmd *cobra.Command, shell string) {
+	var err error
+	root := cmd.Root()
+	switch shell {
+	case bashShell:
+		err = root.GenBashCompletion(os.Stdout)
+	case zshShell:
+		err = root.GenZshCompletion(os.Stdout)
+	case fishShell:
+		err = root.GenFishCompletion(os.Stdout, true)
+	case powershellShell:
+		err = root.GenPowerShellCompletion(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Failed to generate the %s completion script. Error: %q", shell, err)
+	}
+}
+
+func getCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [" + bashShell + "|" + zshShell + "|" + fishShell + "|" + powershellShell + "]",
+		Short: "Generate the shell completion script",
+		Long: `Generate the shell completion script. Besides the commands and flags, the completions suggest
+the services in the plan file, the cluster profiles, the preset configs and the config keys.
+
+Bash:
+  $ source <(move2kube completion bash)
+
+Zsh:
+  $ move2kube completion zsh > "${fpath[1]}/_move2kube"
+
+Fish:
+  $ move2kube completion fish > ~/.config/fish/completions/move2kube.fish
+
+PowerShell:
+  PS> move2kube completion powershell | Out-String | Invoke-Expression
+`,
+		ValidArgs:             []string{bashShell, zshShell, fishShell, powershellShell},
+		Args:                  cobra.ExactValidArgs(1),
+		DisableFlagsInUseLine: true,
+		Run:                   func(cmd *cobra.Command, args []string) { completionHandler(cmd, args[0]) },
+	}
+}
+
+// getPlanFileForCompletion returns the plan file given to the command, falling back to the plan file in the current directory
+func getPlanFileForCompletion(cmd *cobra.Command) string {
+	if flag := cmd.Flags().Lookup(cmdcommon.PlanFlag); flag != nil && flag.Value.String() != "" {
+		return flag.Value.String()
+	}
+	return common.DefaultPlanFile
+}
+
+// fixedCompletion completes the flag with a fixed list of values
+func fixedCompletion(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completePresets(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return move2kube.GetPresetsForCompletion(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSetConfig completes the keys of the key=value pairs and, after the =, the values that are known for the key
+func completeSetConfig(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	planFile := getPlanFileForCompletion(cmd)
+	if idx := strings.Index(toComplete, "="); idx != -1 {
+		key := toComplete[:idx]
+		values := []string{}
+		switch key {
+		case common.ConfigTargetClusterTypeKey:
+			values = move2kube.GetClusterTypesForCompletion(planFile)
+		case common.ConfigServicesNamesKey, common.ConfigServicesExposeKey:
+			values = move2kube.GetServiceNamesForCompletion(planFile)
+		}
+		completions := []string{}
+		for _, value := range values {
+			completions = append(completions, key+"="+value)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions := []string{}
+	for _, key := range move2kube.GetQAKeysForCompletion(planFile) {
+		completions = append(completions, key+"=")
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/internal/move2kube"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	contextFlag       = "context"
+	namespaceFlag     = "namespace"
+	helmFlag          = "helm"
+	buildFlag         = "build"
+	registryURLFlag   = "registry-url"
+	registryNSFlag    = "registry-namespace"
+	deployTimeoutFlag = "timeout"
+)
+
+type deployFlags struct {
+	artifactspath string
+	options       move2kube.DeployOptions
+}
+
+func deployHandler(flags deployFlags) {
+	artifactspath, err := filepath.Abs(flags.artifactspath)
+	if err != nil {
+		log.Fatalf("Failed to make the directory path %q absolute. Error: %q", flags.artifactspath, err)
+	}
+	if fi, err := os.Stat(artifactspath); err != nil || !fi.IsDir() {
+		log.Fatalf("The artifacts path %s is not a directory. Error: %q", artifactspath, err)
+	}
+	if err := move2kube.Deploy(artifactspath, flags.options, os.Stdout); err != nil {
+		log.Fatalf("Failed to deploy the artifacts in the directory %s . Error: %q", artifactspath, err)
+	}
+	log.Infof("The artifacts in the directory %s were deployed.", artifactspath)
+}
+
+func getDeployCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	viper.AutomaticEnv()
+
+	flags := deployFlags{}
+	deployCmd := &cobra.Command{
+		Use:     "deploy",
+		Short:   "Deploy the generated artifacts to a cluster",
+		Long:    "Build and push the new images, or verify that the images exist, then apply the yamls or install the helm chart generated by Move2Kube, and wait for the workloads to become ready.",
+		Example: examples["deploy"],
+		Run:     func(*cobra.Command, []string) { deployHandler(flags) },
+	}
+
+	deployCmd.Flags().StringVarP(&flags.artifactspath, artifactsPath, "a", ".", "Specify directory containing the artifacts generated by Move2Kube.")
+	deployCmd.Flags().StringVar(&flags.options.Context, contextFlag, "", "The kubeconfig context of the target cluster. Defaults to the current context.")
+	deployCmd.Flags().StringVarP(&flags.options.Namespace, namespaceFlag, "n", "", "The namespace of the resources which do not specify one. Defaults to the namespace of the context.")
+	deployCmd.Flags().BoolVar(&flags.options.Helm, helmFlag, false, "Install the helm chart instead of applying the yamls.")
+	deployCmd.Flags().BoolVar(&flags.options.Build, buildFlag, false, "Build and push the new images using the scripts in the artifacts directory, instead of verifying that the images exist.")
+	deployCmd.Flags().StringVar(&flags.options.RegistryURL, registryURLFlag, "", "The registry the new images are pushed to with --build. Defaults to the registry chosen during the translation.")
+	deployCmd.Flags().StringVar(&flags.options.RegistryNamespace, registryNSFlag, "", "The namespace in the registry the new images are pushed to with --build. Defaults to the namespace chosen during the translation.")
+	deployCmd.Flags().DurationVar(&flags.options.Timeout, deployTimeoutFlag, move2kube.DefaultDeployTimeout, "Maximum time to wait for each workload to become ready.")
+
+	must(deployCmd.MarkFlagRequired(artifactsPath))
+
+	return deployCmd
+}
@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// examples contains the end-to-end flows shown by the examples command, keyed by topic
+var examples = map[string]string{
+	"translate": `# Translate the source code in the src directory in one step, answering the questions interactively
+move2kube translate -s src
+
+# Translate without asking any questions, using the default answers
+move2kube translate -s src --qaskip
+
+# Translate into the out directory, replacing it if it exists
+move2kube translate -s src -o out --overwrite`,
+
+	"migrate": `# Plan, translate and package the source code in the src directory, answering the questions interactively
+move2kube migrate -s src
+
+# Migrate without asking any questions, using the default answers
+move2kube migrate -s src --yes
+
+# Migrate into the out directory without packaging the artifacts
+move2kube migrate -s src -o out --no-package`,
+
+	"plan": `# Create a plan for the source code in the src directory
+move2kube plan -s src -n myproject
+
+# Check the plan after editing it
+move2kube plan lint -p m2k.plan
+
+# Upgrade a plan written by an older version of move2kube
+move2kube plan upgrade -p m2k.plan
+
+# Merge the plans of the teams sharing a monorepo into one plan
+move2kube plan merge -p m2k.plan -n shop frontend/m2k.plan backend/m2k.plan
+
+# Compare the changes the services need on several target clusters before choosing one
+move2kube plan compare -p m2k.plan -t Kubernetes,Openshift,AWS-EKS -o compatibility.md
+
+# Rename a service and regenerate the artifacts of an earlier translation
+move2kube plan rename-service db postgres -p m2k.plan -o myproject
+
+# Translate using the edited plan
+move2kube translate -p m2k.plan`,
+
+	"cloudfoundry": `# Log in to cloud foundry and collect the metadata of the running apps
+cf login -a <api endpoint>
+move2kube collect -a cf
+
+# Copy the collected metadata into the source directory and translate
+cp -r m2k_collect src/
+move2kube translate -s src`,
+
+	"cluster": `# Collect the metadata of the target cluster using the current kubeconfig context
+move2kube collect -a k8s
+
+# Copy the collected metadata into the source directory and target the cluster
+cp -r m2k_collect src/
+move2kube translate -s src
+
+# Target one of the built-in cluster profiles instead
+move2kube translate -s src -k move2kube.target.clustertype=Openshift`,
+
+	"qa": `# Answer the questions using a config file instead of interactively
+move2kube translate -s src -f m2kconfig.yaml
+
+# Set individual answers on the command line
+move2kube translate -s src -k move2kube.target.clustertype=Openshift -k 'move2kube.services."web".urlpath=/web'
+
+# Reuse the answers of a previous run
+move2kube translate -s src -q out/m2kqacache.yaml
+
+# Use a preset config
+move2kube translate -s src -r dc`,
+
+	"container": `# Run inside the move2kube image when tools like pack or cf are not installed locally
+move2kube translate -s src --run-in-container
+
+# Make the local docker daemon available for CNB containerization
+move2kube translate -s src --run-in-container --mount-docker-socket`,
+
+	"package": `# Translate and package the artifacts into a tar.gz archive with its checksum
+move2kube translate -s src -o out --package tar.gz
+
+# Sign the archive using gpg
+move2kube translate -s src -o out --package tar.gz --sign gpg --sign-key me@example.com
+
+# Package previously translated artifacts into a zip archive
+move2kube package-output -a out --format zip`,
+
+	"deploy": `# Apply the yamls to the cluster of the staging context, after verifying that the images exist
+move2kube deploy -a out --context staging
+
+# Build and push the new images to another registry, then install the helm chart in the myapp namespace
+move2kube deploy -a out --build --registry-url quay.io --registry-namespace myorg --helm -n myapp
+
+# Wait up to 10 minutes for each workload to become ready
+move2kube deploy -a out --timeout 10m`,
+
+	"kube2kube": `# Rewrite the yamls in the k8s directory for a cluster upgraded from Kubernetes 1.21 to 1.25
+move2kube kube2kube -s k8s --from 1.21 --to 1.25 -n upgraded
+
+# Validate the rewritten yamls against the upgraded cluster of the current kubectl context
+./upgraded/tests/validate.sh`,
+}
+
+func getExampleTopics() []string {
+	topics := []string{}
+	for topic := range examples {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func examplesHandler(args []string) {
+	if len(args) == 0 {
+		for _, topic := range getExampleTopics() {
+			fmt.Printf("# --- %s ---\n%s\n\n", topic, examples[topic])
+		}
+		return
+	}
+	example, ok := examples[args[0]]
+	if !ok {
+		log.Fatalf("Unknown topic %s . Valid topics are %s", args[0], strings.Join(getExampleTopics(), ", "))
+	}
+	fmt.Println(example)
+}
+
+func getExamplesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "examples [topic]",
+		Short:     "Show examples of end-to-end flows",
+		Long:      "Show examples of end-to-end flows. Valid topics are " + strings.Join(getExampleTopics(), ", ") + ". All the examples are shown if no topic is given.",
+		ValidArgs: getExampleTopics(),
+		Args:      cobra.MaximumNArgs(1),
+		Run:       func(_ *cobra.Command, args []string) { examplesHandler(args) },
+	}
+}
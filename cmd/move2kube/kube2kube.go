@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+
+	cmdcommon "github.com/konveyor/move2kube/cmd/common"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	fromVersionFlag = "from"
+	toVersionFlag   = "to"
+)
+
+type kube2KubeFlags struct {
+	srcpath     string
+	outpath     string
+	name        string
+	fromVersion string
+	toVersion   string
+	overwrite   bool
+}
+
+func kube2KubeHandler(flags kube2KubeFlags) {
+	var err error
+	if flags.srcpath, err = filepath.Abs(flags.srcpath); err != nil {
+		log.Fatalf("Failed to make the source directory path %q absolute. Error: %q", flags.srcpath, err)
+	}
+	if flags.outpath, err = filepath.Abs(flags.outpath); err != nil {
+		log.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
+	}
+	cmdcommon.CheckSourcePath(flags.srcpath)
+	outpath := filepath.Join(flags.outpath, flags.name)
+	cmdcommon.CheckOutputPath(outpath, flags.overwrite)
+	upgrades, err := move2kube.UpgradeK8sManifests(flags.srcpath, outpath, flags.fromVersion, flags.toVersion)
+	if err != nil {
+		log.Fatalf("Failed to upgrade the manifests in the directory %s . Error: %q", flags.srcpath, err)
+	}
+	rewritten, manual := 0, 0
+	for _, upgrade := range upgrades {
+		if upgrade.Rewritten {
+			rewritten++
+		} else if upgrade.Removed {
+			manual++
+		}
+	}
+	log.Infof("Rewrote %d resources using APIs removed in Kubernetes %s. %d resources need attention, and %d use deprecated APIs.", rewritten, flags.toVersion, manual, len(upgrades)-rewritten-manual)
+	log.Infof("The upgraded manifests can be found at [%s]. Refer to %s for the deprecations.", filepath.Join(outpath, move2kube.KubeUpgradeManifestsDir), filepath.Join(outpath, move2kube.KubeUpgradeReportFile))
+}
+
+func getKube2KubeCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	viper.AutomaticEnv()
+
+	flags := kube2KubeFlags{}
+	kube2KubeCmd := &cobra.Command{
+		Use:     "kube2kube",
+		Short:   "Upgrade Kubernetes manifests to a newer version of Kubernetes",
+		Long:    "Rewrite the Kubernetes yamls in the source directory whose API versions are removed in the target version of Kubernetes, and write a deprecation report and a script which validates the manifests against the upgraded cluster. No containerization is done.",
+		Example: examples["kube2kube"],
+		Run:     func(*cobra.Command, []string) { kube2KubeHandler(flags) },
+	}
+
+	kube2KubeCmd.Flags().StringVarP(&flags.srcpath, cmdcommon.SourceFlag, "s", "", "Specify the directory containing the Kubernetes yamls.")
+	kube2KubeCmd.Flags().StringVarP(&flags.outpath, cmdcommon.OutputFlag, "o", ".", "Path for output. Default will be directory with the project name.")
+	kube2KubeCmd.Flags().StringVarP(&flags.name, cmdcommon.NameFlag, "n", common.DefaultProjectName, "Specify the project name.")
+	kube2KubeCmd.Flags().StringVar(&flags.fromVersion, fromVersionFlag, "", "Specify the version of Kubernetes the yamls are currently deployed to. Eg: 1.21")
+	kube2KubeCmd.Flags().StringVar(&flags.toVersion, toVersionFlag, "", "Specify the version of Kubernetes the cluster is upgraded to. Eg: 1.25")
+	kube2KubeCmd.Flags().BoolVar(&flags.overwrite, cmdcommon.OverwriteFlag, false, "Overwrite the output directory if it exists. By default we don't overwrite.")
+
+	must(kube2KubeCmd.MarkFlagRequired(cmdcommon.SourceFlag))
+	must(kube2KubeCmd.MarkFlagRequired(fromVersionFlag))
+	must(kube2KubeCmd.MarkFlagRequired(toVersionFlag))
+
+	return kube2KubeCmd
+}
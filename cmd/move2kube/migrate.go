@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	cmdcommon "github.com/konveyor/move2kube/cmd/common"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/pkg/move2kube"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	yesFlag       = "yes"
+	noPackageFlag = "no-package"
+)
+
+type migrateFlags struct {
+	srcpath    string
+	outpath    string
+	name       string
+	yes        bool
+	overwrite  bool
+	format     string
+	noPackage  bool
+	configs    []string
+	setconfigs []string
+	presets    []string
+}
+
+func migrateHandler(flags migrateFlags) {
+	cmdcommon.CheckOnServiceError()
+	cmdcommon.CheckSecretScan()
+	srcpath, err := filepath.Abs(flags.srcpath)
+	if err != nil {
+		log.Fatalf("Failed to make the source directory path %q absolute. Error: %q", flags.srcpath, err)
+	}
+	outpath, err := filepath.Abs(flags.outpath)
+	if err != nil {
+		log.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
+	}
+	format := flags.format
+	if flags.noPackage {
+		format = ""
+	} else if format != common.TarGzArchiveFormat && format != common.ZipArchiveFormat {
+		log.Fatalf("Invalid package format %s . Valid values are %s and %s", format, common.TarGzArchiveFormat, common.ZipArchiveFormat)
+	}
+
+	cmdcommon.CheckSourcePath(srcpath)
+	outpath = filepath.Join(outpath, flags.name)
+	cmdcommon.CheckOutputPath(outpath, flags.overwrite)
+	if srcpath == outpath || common.IsParent(outpath, srcpath) || common.IsParent(srcpath, outpath) {
+		log.Fatalf("The source path %s and output path %s overlap.", srcpath, outpath)
+	}
+	qaOptions := move2kube.QAOptions{SkipQA: flags.yes, Configs: flags.setconfigs, ConfigFiles: flags.configs, Presets: flags.presets}
+	cmdcommon.CheckError(move2kube.SetupQA(outpath, qaOptions), "Failed to set up the QA engine.")
+
+	result, err := move2kube.Migrate(srcpath, flags.name, outpath, format)
+	cmdcommon.CheckError(err, "Failed to migrate the source directory %s .", srcpath)
+	fmt.Printf("Plan:      %s\n", result.PlanPath)
+	fmt.Printf("Artifacts: %s\n", result.OutputPath)
+	fmt.Printf("Report:    %s\n", result.ReportPath)
+	if result.ArchivePath != "" {
+		fmt.Printf("Package:   %s\n", result.ArchivePath)
+	}
+	fmt.Printf("Answers:   %s\n", filepath.Join(outpath, common.ConfigFile))
+}
+
+func getMigrateCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	viper.AutomaticEnv()
+
+	flags := migrateFlags{}
+	migrateCmd := &cobra.Command{
+		Use:     "migrate",
+		Short:   "Plan, translate and package in one step",
+		Long:    "Plan the source directory, ask the questions, translate and package the artifacts in one step. Use --yes to accept the default answers.",
+		Example: examples["migrate"],
+		Args:    cobra.NoArgs,
+		Run:     func(*cobra.Command, []string) { migrateHandler(flags) },
+	}
+
+	migrateCmd.Flags().StringVarP(&flags.srcpath, cmdcommon.SourceFlag, "s", "", "Specify source directory to migrate.")
+	migrateCmd.Flags().StringVarP(&flags.outpath, cmdcommon.OutputFlag, "o", ".", "Path for output. The artifacts are written to the directory with the project name.")
+	migrateCmd.Flags().StringVarP(&flags.name, cmdcommon.NameFlag, "n", common.DefaultProjectName, "Specify the project name.")
+	migrateCmd.Flags().BoolVarP(&flags.yes, yesFlag, "y", false, "Accept the default answers instead of asking the questions.")
+	migrateCmd.Flags().BoolVar(&flags.overwrite, cmdcommon.OverwriteFlag, false, "Overwrite the output directory if it exists. By default we don't overwrite.")
+	migrateCmd.Flags().StringVar(&flags.format, formatFlag, common.TarGzArchiveFormat, "Specify the format of the package. Valid values are "+common.TarGzArchiveFormat+" and "+common.ZipArchiveFormat+".")
+	migrateCmd.Flags().BoolVar(&flags.noPackage, noPackageFlag, false, "Do not package the artifacts.")
+	migrateCmd.Flags().StringSliceVarP(&flags.configs, cmdcommon.ConfigFlag, "f", []string{}, "Specify config file locations")
+	migrateCmd.Flags().StringSliceVarP(&flags.presets, cmdcommon.PreSetFlag, "r", []string{}, "Specify preset config to use")
+	migrateCmd.Flags().StringArrayVarP(&flags.setconfigs, cmdcommon.SetConfigFlag, "k", []string{}, "Specify config key-value pairs")
+	migrateCmd.Flags().StringVar(&common.OnServiceError, cmdcommon.OnServiceErrorFlag, common.AskOnServiceError, "Specify how a failed step of a service, like its containerization, is handled. Valid values are "+strings.Join(cmdcommon.OnServiceErrorOptions, ", ")+". With ask, the question defaults to skip.")
+	migrateCmd.Flags().StringVar(&common.SecretScanMode, cmdcommon.SecretScanFlag, common.WarnSecretScanMode, "Specify how the credentials, like AWS keys or private keys, found in the sources copied into the build contexts are handled. Valid values are "+strings.Join(cmdcommon.SecretScanOptions, ", ")+". With block, the sources are not copied.")
+	cmdcommon.AddK8sFilterFlags(migrateCmd)
+
+	must(migrateCmd.MarkFlagRequired(cmdcommon.SourceFlag))
+	must(migrateCmd.RegisterFlagCompletionFunc(cmdcommon.SetConfigFlag, completeSetConfig))
+	must(migrateCmd.RegisterFlagCompletionFunc(cmdcommon.PreSetFlag, completePresets))
+	must(migrateCmd.RegisterFlagCompletionFunc(formatFlag, fixedCompletion(common.TarGzArchiveFormat, common.ZipArchiveFormat)))
+	must(migrateCmd.RegisterFlagCompletionFunc(cmdcommon.OnServiceErrorFlag, fixedCompletion(cmdcommon.OnServiceErrorOptions...)))
+	must(migrateCmd.RegisterFlagCompletionFunc(cmdcommon.SecretScanFlag, fixedCompletion(cmdcommon.SecretScanOptions...)))
+
+	return migrateCmd
+}
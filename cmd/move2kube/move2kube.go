@@ -28,7 +28,11 @@ import (
 )
 
 func main() {
+	if err := common.LoadDefaults(os.Getenv(common.DefaultsFileEnvVar)); err != nil {
+		log.Fatalf("Failed to load the defaults. Error: %q", err)
+	}
 	verbose := false
+	containerFlags := cmdcommon.ContainerFlags{}
 
 	// RootCmd root level flags and commands
 	rootCmd := &cobra.Command{
@@ -40,10 +44,13 @@ Even if the app does not use any of the above, or even if it is not containerize
 
 For more documentation and support, visit https://move2kube.konveyor.io/
 `,
-		PersistentPreRunE: func(*cobra.Command, []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 			if verbose {
 				log.SetLevel(log.DebugLevel)
 			}
+			if containerFlags.RunInContainer {
+				cmdcommon.RunInContainer(cmd, containerFlags)
+			}
 			return nil
 		},
 	}
@@ -59,11 +66,23 @@ For more documentation and support, visit https://move2kube.konveyor.io/
 	}
 
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().DurationVar(&common.CommandTimeout, cmdcommon.ToolTimeoutFlag, common.DefaultCommandTimeout, "Maximum time an external tool (docker, pack, cf, kubectl, etc.) is allowed to run before it is killed. Set to 0 to disable.")
+	rootCmd.PersistentFlags().IntVar(&common.CommandMaxOutputSize, cmdcommon.ToolMaxOutputFlag, common.DefaultCommandMaxOutputSize, "Maximum number of bytes captured from the output of an external tool. Set to 0 to disable.")
+	rootCmd.PersistentFlags().IntVar(&common.CommandRetries, cmdcommon.ToolRetriesFlag, common.DefaultCommandRetries, "Number of times an external tool invocation which timed out or could not be started is retried. Tools which exit with an error are not retried.")
+	rootCmd.PersistentFlags().BoolVar(&common.Offline, cmdcommon.OfflineFlag, false, "Disable the commands that need internet access, like checking for new versions.")
+	cmdcommon.AddContainerFlags(rootCmd, &containerFlags)
 	rootCmd.AddCommand(cmdcommon.GetVersionCommand())
 	rootCmd.AddCommand(getCollectCommand())
 	rootCmd.AddCommand(getPlanCommand())
 	rootCmd.AddCommand(getTranslateCommand())
+	rootCmd.AddCommand(getMigrateCommand())
+	rootCmd.AddCommand(getKube2KubeCommand())
 	rootCmd.AddCommand(getValidateCommand())
+	rootCmd.AddCommand(getPackageOutputCommand())
+	rootCmd.AddCommand(getDeployCommand())
+	rootCmd.AddCommand(getUpgradeCommand())
+	rootCmd.AddCommand(getCompletionCommand())
+	rootCmd.AddCommand(getExamplesCommand())
 
 	assetsPath, tempPath, err := common.CreateAssetsData()
 	if err != nil {
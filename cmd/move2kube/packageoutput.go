@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	cmdcommon "github.com/konveyor/move2kube/cmd/common"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	formatFlag = "format"
+)
+
+type packageOutputFlags struct {
+	artifactspath string
+	outpath       string
+	format        string
+	sign          string
+	signKey       string
+}
+
+func packageOutputHandler(flags packageOutputFlags) {
+	artifactspath, err := filepath.Abs(flags.artifactspath)
+	if err != nil {
+		log.Fatalf("Failed to make the directory path %q absolute. Error: %q", flags.artifactspath, err)
+	}
+	outpath, err := filepath.Abs(flags.outpath)
+	if err != nil {
+		log.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
+	}
+	if fi, err := os.Stat(artifactspath); err != nil || !fi.IsDir() {
+		log.Fatalf("The artifacts path %s is not a directory. Error: %q", artifactspath, err)
+	}
+	archivePath, err := move2kube.PackageOutput(artifactspath, outpath, flags.format, flags.sign, flags.signKey)
+	if err != nil {
+		log.Fatalf("Failed to package the artifacts in the directory %s . Error: %q", artifactspath, err)
+	}
+	log.Infof("The packaged artifacts can be found at [%s].", archivePath)
+}
+
+func getPackageOutputCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	viper.AutomaticEnv()
+
+	flags := packageOutputFlags{}
+	packageOutputCmd := &cobra.Command{
+		Use:     "package-output",
+		Short:   "Package the generated artifacts into an archive",
+		Long:    "Package the artifacts generated by Move2Kube into a tar.gz or zip archive along with a file containing its sha256 checksum.",
+		Example: examples["package"],
+		Run:     func(*cobra.Command, []string) { packageOutputHandler(flags) },
+	}
+
+	packageOutputCmd.Flags().StringVarP(&flags.artifactspath, artifactsPath, "a", ".", "Specify directory containing the artifacts generated by Move2Kube.")
+	packageOutputCmd.Flags().StringVarP(&flags.outpath, cmdcommon.OutputFlag, "o", ".", "Specify the directory where the archive should be created.")
+	packageOutputCmd.Flags().StringVar(&flags.format, formatFlag, common.TarGzArchiveFormat, "Specify the format of the archive. Valid values are "+common.TarGzArchiveFormat+" and "+common.ZipArchiveFormat+".")
+	packageOutputCmd.Flags().StringVar(&flags.sign, cmdcommon.SignFlag, "", "Sign the archive using "+move2kube.GPGSigner+" or "+move2kube.CosignSigner+".")
+	packageOutputCmd.Flags().StringVar(&flags.signKey, cmdcommon.SignKeyFlag, "", "Specify the key used to sign the archive. It is the key id for gpg and the path to the key for cosign.")
+
+	must(packageOutputCmd.MarkFlagRequired(artifactsPath))
+	must(packageOutputCmd.RegisterFlagCompletionFunc(formatFlag, fixedCompletion(common.TarGzArchiveFormat, common.ZipArchiveFormat)))
+	must(packageOutputCmd.RegisterFlagCompletionFunc(cmdcommon.SignFlag, fixedCompletion(move2kube.GPGSigner, move2kube.CosignSigner)))
+
+	return packageOutputCmd
+}
@@ -17,31 +17,46 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	cmdcommon "github.com/konveyor/move2kube/cmd/common"
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/move2kube"
+	"github.com/konveyor/move2kube/internal/qaengine"
 	plantypes "github.com/konveyor/move2kube/types/plan"
+	projecttypes "github.com/konveyor/move2kube/types/project"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 type planFlags struct {
-	planfile string
-	srcpath  string
-	name     string
+	cmdcommon.ProfileFlags
+	planfile      string
+	srcpath       string
+	name          string
+	outputFormat  string
+	watch         bool
+	watchInterval time.Duration
 }
 
 func planHandler(flags planFlags) {
+	stopProfiling, err := cmdcommon.StartProfiling(flags.ProfileFlags)
+	if err != nil {
+		log.Fatalf("Failed to start profiling. Error: %q", err)
+	}
+	defer stopProfiling()
 	// Check if this is even a directory
-	var err error
 	planfile := flags.planfile
 	srcpath := flags.srcpath
 	name := flags.name
+	cmdcommon.CheckOutputFormat(flags.outputFormat)
 
 	planfile, err = filepath.Abs(planfile)
 	if err != nil {
@@ -72,14 +87,335 @@ func planHandler(flags planFlags) {
 		planfile = filepath.Join(planfile, common.DefaultPlanFile)
 	}
 
-	p := move2kube.CreatePlan(srcpath, name, false)
-	if err = plantypes.WritePlan(planfile, p); err != nil {
+	if err := move2kube.RunHooks(srcpath, projecttypes.PrePlanHookPhase, srcpath); err != nil {
+		log.Fatalf("Failed to run the hooks before planning. Error: %q", err)
+	}
+	if flags.watch {
+		watchPlan(srcpath, name, planfile, flags)
+		return
+	}
+	p, err := move2kube.CreatePlan(srcpath, name, false)
+	cmdcommon.CheckError(err, "Failed to plan the source directory %s .", srcpath)
+	if err = plantypes.WritePlanWithFormat(planfile, p, flags.outputFormat); err != nil {
 		log.Errorf("Unable to write plan file (%s) : %s", planfile, err)
 		return
 	}
+	if err := move2kube.RunHooks(srcpath, projecttypes.PostPlanHookPhase, planfile); err != nil {
+		log.Fatalf("Failed to run the hooks after planning. Error: %q", err)
+	}
 	log.Infof("Plan can be found at [%s].", planfile)
 }
 
+// watchPlan keeps updating the plan file when the source directory changes and prints the changes as json lines
+func watchPlan(srcpath, name, planfile string, flags planFlags) {
+	if flags.watchInterval <= 0 {
+		log.Fatalf("The watch interval should be positive. Actual: %s", flags.watchInterval)
+	}
+	watcher, events, err := move2kube.NewPlanWatcher(srcpath, name, planfile)
+	cmdcommon.CheckError(err, "Failed to plan the source directory %s .", srcpath)
+	if err := plantypes.WritePlanWithFormat(planfile, watcher.GetPlan(), flags.outputFormat); err != nil {
+		log.Fatalf("Unable to write the plan file at path %s . Error: %q", planfile, err)
+	}
+	printPlanEvents(events)
+	log.Infof("Watching the source directory %s for changes. The plan can be found at [%s].", srcpath, planfile)
+	ticker := time.NewTicker(flags.watchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !watcher.Changed() {
+			continue
+		}
+		// The plan file is read again to keep the edits made since it was written
+		p, err := plantypes.ReadPlan(planfile)
+		if err != nil {
+			log.Warnf("Failed to read the plan file at path %s . Using the detected plan instead. Error: %q", planfile, err)
+			p = watcher.GetPlan()
+		}
+		events := watcher.Update(&p)
+		if len(events) == 0 {
+			continue
+		}
+		if err := plantypes.WritePlanWithFormat(planfile, p, flags.outputFormat); err != nil {
+			log.Errorf("Unable to write the plan file at path %s . Error: %q", planfile, err)
+			continue
+		}
+		printPlanEvents(events)
+	}
+}
+
+func printPlanEvents(events []move2kube.PlanEvent) {
+	for _, event := range events {
+		eventBytes, err := json.Marshal(event)
+		if err != nil {
+			log.Errorf("Failed to marshal the plan event %+v to json. Error: %q", event, err)
+			continue
+		}
+		fmt.Println(string(eventBytes))
+	}
+}
+
+type planLintFlags struct {
+	planfile string
+}
+
+func planLintHandler(flags planLintFlags) {
+	planfile, err := filepath.Abs(flags.planfile)
+	if err != nil {
+		log.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+	}
+	validationErrors, err := move2kube.LintPlan(planfile)
+	if err != nil {
+		log.Fatalf("Failed to lint the plan file at path %s . Error: %q", planfile, err)
+	}
+	for _, validationError := range validationErrors {
+		fmt.Printf("%s:%s\n", planfile, validationError.Error())
+	}
+	if len(validationErrors) > 0 {
+		log.Fatalf("Found %d problems in the plan file at path %s", len(validationErrors), planfile)
+	}
+	log.Infof("The plan file at path %s is valid.", planfile)
+}
+
+type planUpgradeFlags struct {
+	planfile string
+}
+
+func planUpgradeHandler(flags planUpgradeFlags) {
+	planfile, err := filepath.Abs(flags.planfile)
+	if err != nil {
+		log.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+	}
+	apiVersion, err := move2kube.UpgradePlan(planfile)
+	if err != nil {
+		log.Fatalf("Failed to upgrade the plan file at path %s . Error: %q", planfile, err)
+	}
+	if apiVersion == plantypes.SchemeGroupVersion.String() {
+		log.Infof("The plan file at path %s already has the latest apiVersion %s .", planfile, apiVersion)
+		return
+	}
+	log.Infof("Upgraded the plan file at path %s from the apiVersion %s to %s .", planfile, apiVersion, plantypes.SchemeGroupVersion.String())
+}
+
+type planMergeFlags struct {
+	planfile string
+	name     string
+}
+
+func planMergeHandler(flags planMergeFlags, planfiles []string) {
+	planfile, err := filepath.Abs(flags.planfile)
+	if err != nil {
+		log.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+	}
+	p, err := plantypes.MergePlanFiles(flags.name, planfiles)
+	if err != nil {
+		log.Fatalf("Failed to merge the plan files %v . Error: %q", planfiles, err)
+	}
+	if err := plantypes.WritePlan(planfile, p); err != nil {
+		log.Fatalf("Unable to write the plan file at path %s . Error: %q", planfile, err)
+	}
+	log.Infof("Merged %d plan files with %d services into the plan file at path %s .", len(planfiles), len(p.Spec.Inputs.Services), planfile)
+}
+
+type planRenameFlags struct {
+	planfile string
+	outpath  string
+	configs  []string
+}
+
+func planRenameServiceHandler(flags planRenameFlags, serviceName, newServiceName string) {
+	planfile, outpath := getPlanRenamePaths(flags)
+	configs, caches := move2kube.GetOutputQAFiles(outpath)
+	configs = append(configs, flags.configs...)
+	p, err := move2kube.RenameService(planfile, serviceName, newServiceName, configs, caches)
+	if err != nil {
+		log.Fatalf("Failed to rename the service %s to %s . Error: %q", serviceName, newServiceName, err)
+	}
+	log.Infof("Renamed the service %s to %s in the plan file at path %s .", serviceName, newServiceName, planfile)
+	regenerateOutput(p, outpath, configs)
+}
+
+func planRenameImageHandler(flags planRenameFlags, image, newImage string) {
+	planfile, outpath := getPlanRenamePaths(flags)
+	p, serviceNames, err := move2kube.RenameImage(planfile, image, newImage)
+	if err != nil {
+		log.Fatalf("Failed to rename the image %s to %s . Error: %q", image, newImage, err)
+	}
+	log.Infof("Renamed the image %s to %s for the services %s in the plan file at path %s .", image, newImage, strings.Join(serviceNames, ", "), planfile)
+	configs, _ := move2kube.GetOutputQAFiles(outpath)
+	regenerateOutput(p, outpath, configs)
+}
+
+func getPlanRenamePaths(flags planRenameFlags) (string, string) {
+	planfile, err := filepath.Abs(flags.planfile)
+	if err != nil {
+		log.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+	}
+	if flags.outpath == "" {
+		return planfile, ""
+	}
+	outpath, err := filepath.Abs(flags.outpath)
+	if err != nil {
+		log.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.outpath, err)
+	}
+	if _, err := os.Stat(outpath); err != nil {
+		log.Fatalf("Failed to access the output directory at path %s . Error: %q", outpath, err)
+	}
+	return planfile, outpath
+}
+
+// regenerateOutput translates the renamed plan into the output directory of an earlier translation, using the recorded answers
+func regenerateOutput(p plantypes.Plan, outpath string, configs []string) {
+	if outpath == "" {
+		return
+	}
+	log.Infof("Regenerating the artifacts in the output directory at path %s using the answers recorded in %v .", outpath, configs)
+	qaengine.StartEngine(true, 0, false, "")
+	qaengine.SetupConfigFile(outpath, nil, configs, nil)
+	qaengine.SetupCacheFile(outpath, nil)
+	if err := qaengine.WriteStoresToDisk(); err != nil {
+		log.Warnf("Failed to write the stores to disk. Error: %q", err)
+	}
+	cmdcommon.CheckError(move2kube.Translate(p, outpath, false, nil), "Failed to regenerate the artifacts in the output directory at path %s .", outpath)
+	log.Infof("Regenerated the artifacts in the output directory at path %s . See scripts/prune.sh to delete the resources of the old names from the cluster.", outpath)
+}
+
+const (
+	targetsFlag = "targets"
+)
+
+type planCompareFlags struct {
+	planfile string
+	targets  []string
+	outfile  string
+	qacaches []string
+}
+
+func planCompareHandler(flags planCompareFlags) {
+	planfile, err := filepath.Abs(flags.planfile)
+	if err != nil {
+		log.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.planfile, err)
+	}
+	p, err := plantypes.ReadPlan(planfile)
+	if err != nil {
+		log.Fatalf("Unable to read the plan file at path %s . Error: %q", planfile, err)
+	}
+	// The questions are answered using the recorded answers, or else the defaults, since nothing is written
+	qaengine.StartEngine(true, 0, false, "")
+	qaengine.AddCaches(flags.qacaches)
+	compatibilities, err := move2kube.CheckTargetCompatibility(p, flags.targets)
+	if err != nil {
+		log.Fatalf("Failed to check the compatibility of the plan at path %s with the target clusters %v . Error: %q", planfile, flags.targets, err)
+	}
+	matrix := move2kube.GetCompatibilityMatrix(compatibilities)
+	if flags.outfile == "" {
+		fmt.Print(matrix)
+		return
+	}
+	if err := ioutil.WriteFile(flags.outfile, []byte(matrix), common.DefaultFilePermission); err != nil {
+		log.Fatalf("Failed to write the compatibility matrix to the file at path %s . Error: %q", flags.outfile, err)
+	}
+	log.Infof("Wrote the compatibility matrix of the plan with the target clusters %s to the file at path %s .", strings.Join(flags.targets, ", "), flags.outfile)
+}
+
+func planSchemaHandler() {
+	schemaBytes, err := json.MarshalIndent(move2kube.GetPlanJSONSchema(), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal the JSON schema of the plan. Error: %q", err)
+	}
+	fmt.Println(string(schemaBytes))
+}
+
+func getPlanLintCommand() *cobra.Command {
+	flags := planLintFlags{}
+	planLintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Validate a plan file",
+		Long:  "Validate a plan file against the JSON schema of the plan and print the position of every problem",
+		Run:   func(*cobra.Command, []string) { planLintHandler(flags) },
+	}
+	planLintCmd.Flags().StringVarP(&flags.planfile, cmdcommon.PlanFlag, "p", common.DefaultPlanFile, "Specify the plan file to validate.")
+	return planLintCmd
+}
+
+func getPlanUpgradeCommand() *cobra.Command {
+	flags := planUpgradeFlags{}
+	planUpgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade a plan file to the latest plan version",
+		Long:  "Migrate a plan file written by an older version of move2kube to the latest plan version and rewrite it. A copy of the old plan file is kept next to it.",
+		Run:   func(*cobra.Command, []string) { planUpgradeHandler(flags) },
+	}
+	planUpgradeCmd.Flags().StringVarP(&flags.planfile, cmdcommon.PlanFlag, "p", common.DefaultPlanFile, "Specify the plan file to upgrade.")
+	return planUpgradeCmd
+}
+
+func getPlanMergeCommand() *cobra.Command {
+	flags := planMergeFlags{}
+	planMergeCmd := &cobra.Command{
+		Use:   "merge [plan files...]",
+		Short: "Merge several plan files into one plan file",
+		Long:  "Merge several plan files, like the plans of the teams sharing a monorepo, into one plan file. The root directory of the merged plan is the common ancestor of the root directories of the plans.",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   func(_ *cobra.Command, args []string) { planMergeHandler(flags, args) },
+	}
+	planMergeCmd.Flags().StringVarP(&flags.planfile, cmdcommon.PlanFlag, "p", common.DefaultPlanFile, "Specify the file path to save the merged plan to.")
+	planMergeCmd.Flags().StringVarP(&flags.name, cmdcommon.NameFlag, "n", "", "Specify the project name of the merged plan. Defaults to the name of the first plan.")
+	return planMergeCmd
+}
+
+func getPlanRenameCommands() []*cobra.Command {
+	flags := planRenameFlags{}
+	planRenameServiceCmd := &cobra.Command{
+		Use:   "rename-service [service name] [new service name]",
+		Short: "Rename a service of a plan file",
+		Long:  "Rename a service of a plan file, along with the references of the other services to it and the answers recorded about it. If the output directory of an earlier translation is given, the artifacts are regenerated.",
+		Args:  cobra.ExactArgs(2),
+		Run:   func(_ *cobra.Command, args []string) { planRenameServiceHandler(flags, args[0], args[1]) },
+	}
+	planRenameImageCmd := &cobra.Command{
+		Use:   "rename-image [image] [new image]",
+		Short: "Rename an image used by the services of a plan file",
+		Long:  "Change the image of the services of a plan file which use the image. If the output directory of an earlier translation is given, the artifacts are regenerated.",
+		Args:  cobra.ExactArgs(2),
+		Run:   func(_ *cobra.Command, args []string) { planRenameImageHandler(flags, args[0], args[1]) },
+	}
+	for _, cmd := range []*cobra.Command{planRenameServiceCmd, planRenameImageCmd} {
+		cmd.Flags().StringVarP(&flags.planfile, cmdcommon.PlanFlag, "p", common.DefaultPlanFile, "Specify the plan file to rename in.")
+		cmd.Flags().StringVarP(&flags.outpath, cmdcommon.OutputFlag, "o", "", "Specify the output directory of an earlier translation of the plan, to rename in its recorded answers and regenerate its artifacts.")
+	}
+	planRenameServiceCmd.Flags().StringSliceVarP(&flags.configs, cmdcommon.ConfigFlag, "f", []string{}, "Specify other config files whose answers about the service are renamed.")
+	return []*cobra.Command{planRenameServiceCmd, planRenameImageCmd}
+}
+
+func getPlanCompareCommand() *cobra.Command {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	flags := planCompareFlags{}
+	planCompareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the target cluster types for a plan",
+		Long:  "Translate a plan without writing any artifacts and check the objects generated for each target cluster type. The compatibility matrix shows the kinds generated for each service and the number of changes they need, like unsupported kinds, violated constraints and missing storage classes, followed by the changes.",
+		Run:   func(*cobra.Command, []string) { planCompareHandler(flags) },
+	}
+	planCompareCmd.Flags().StringVarP(&flags.planfile, cmdcommon.PlanFlag, "p", common.DefaultPlanFile, "Specify the plan file to compare the target clusters for.")
+	planCompareCmd.Flags().StringSliceVarP(&flags.targets, targetsFlag, "t", []string{}, "Specify the target cluster types to compare, like Kubernetes, Openshift or AWS-EKS, or the names of the collected cluster metadata.")
+	planCompareCmd.Flags().StringVarP(&flags.outfile, cmdcommon.OutputFlag, "o", "", "Specify the file to write the compatibility matrix to. Defaults to the standard output.")
+	planCompareCmd.Flags().StringSliceVarP(&flags.qacaches, cmdcommon.QACacheFlag, "q", []string{}, "Specify qa cache files, like the one of an earlier translation, whose answers are used instead of the defaults.")
+	must(planCompareCmd.MarkFlagRequired(targetsFlag))
+	return planCompareCmd
+}
+
+func getPlanSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema of the plan",
+		Long:  "Print the JSON schema of the plan. Editors can use it to validate and autocomplete plan files.",
+		Run:   func(*cobra.Command, []string) { planSchemaHandler() },
+	}
+}
+
 func getPlanCommand() *cobra.Command {
 	must := func(err error) {
 		if err != nil {
@@ -90,17 +426,33 @@ func getPlanCommand() *cobra.Command {
 
 	flags := planFlags{}
 	planCmd := &cobra.Command{
-		Use:   "plan",
-		Short: "Plan out a move",
-		Long:  "Discover and create a plan file based on an input directory",
-		Run:   func(*cobra.Command, []string) { planHandler(flags) },
+		Use:     "plan",
+		Short:   "Plan out a move",
+		Long:    "Discover and create a plan file based on an input directory",
+		Example: examples["plan"],
+		Run:     func(*cobra.Command, []string) { planHandler(flags) },
 	}
 
 	planCmd.Flags().StringVarP(&flags.srcpath, cmdcommon.SourceFlag, "s", ".", "Specify source directory.")
 	planCmd.Flags().StringVarP(&flags.planfile, cmdcommon.PlanFlag, "p", common.DefaultPlanFile, "Specify a file path to save plan to.")
 	planCmd.Flags().StringVarP(&flags.name, cmdcommon.NameFlag, "n", common.DefaultProjectName, "Specify the project name.")
+	planCmd.Flags().StringVar(&flags.outputFormat, cmdcommon.OutputFormatFlag, common.YamlOutputFormat, "Specify the format of the plan file. Valid values are "+strings.Join(cmdcommon.OutputFormatOptions, ", ")+".")
+	planCmd.Flags().IntVar(&common.PlanWorkers, cmdcommon.PlanWorkersFlag, common.PlanWorkers, "Maximum number of translators and directories whose services are detected at the same time during planning. Defaults to the number of CPUs.")
+	planCmd.Flags().StringSliceVar(&common.AnalysisPaths, cmdcommon.AnalysisFlag, []string{}, "Specify the results of rule engines, like the output.yaml of the Konveyor analyzer, whose issues are added to the services in the plan and the report.")
+	planCmd.Flags().BoolVar(&flags.watch, cmdcommon.WatchFlag, false, "Keep running and update the plan file when the source directory changes. The changes of the services are printed as json lines.")
+	planCmd.Flags().DurationVar(&flags.watchInterval, cmdcommon.WatchIntervalFlag, 2*time.Second, "Specify how often the source directory is checked for changes in watch mode.")
+	cmdcommon.AddProfileFlags(planCmd, &flags.ProfileFlags)
 
 	must(planCmd.MarkFlagRequired(cmdcommon.SourceFlag))
+	must(planCmd.RegisterFlagCompletionFunc(cmdcommon.ProfileFlag, fixedCompletion(cmdcommon.ProfileOptions...)))
+	must(planCmd.RegisterFlagCompletionFunc(cmdcommon.OutputFormatFlag, fixedCompletion(cmdcommon.OutputFormatOptions...)))
+
+	planCmd.AddCommand(getPlanLintCommand())
+	planCmd.AddCommand(getPlanSchemaCommand())
+	planCmd.AddCommand(getPlanUpgradeCommand())
+	planCmd.AddCommand(getPlanMergeCommand())
+	planCmd.AddCommand(getPlanCompareCommand())
+	planCmd.AddCommand(getPlanRenameCommands()...)
 
 	return planCmd
 }
@@ -19,12 +19,14 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	cmdcommon "github.com/konveyor/move2kube/cmd/common"
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/move2kube"
 	"github.com/konveyor/move2kube/internal/qaengine"
 	"github.com/konveyor/move2kube/types/plan"
+	projecttypes "github.com/konveyor/move2kube/types/project"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -32,20 +34,29 @@ import (
 
 type translateFlags struct {
 	cmdcommon.TranslateFlags
+	cmdcommon.ProfileFlags
 	curate       bool
 	qadisablecli bool
 	qaport       int
+	pkg          string
+	sign         string
+	signKey      string
 }
 
 const (
 	curateFlag       = "curate"
 	qadisablecliFlag = "qadisablecli"
 	qaportFlag       = "qaport"
+	packageFlag      = "package"
 )
 
 func translateHandler(cmd *cobra.Command, flags translateFlags) {
+	stopProfiling, err := cmdcommon.StartProfiling(flags.ProfileFlags)
+	if err != nil {
+		log.Fatalf("Failed to start profiling. Error: %q", err)
+	}
+	defer stopProfiling()
 	// Setup
-	var err error
 
 	if flags.Planfile, err = filepath.Abs(flags.Planfile); err != nil {
 		log.Fatalf("Failed to make the plan file path %q absolute. Error: %q", flags.Planfile, err)
@@ -59,8 +70,17 @@ func translateHandler(cmd *cobra.Command, flags translateFlags) {
 		log.Fatalf("Failed to make the output directory path %q absolute. Error: %q", flags.Outpath, err)
 	}
 
+	if flags.pkg != "" && flags.pkg != common.TarGzArchiveFormat && flags.pkg != common.ZipArchiveFormat {
+		log.Fatalf("Invalid package format %s . Valid values are %s and %s", flags.pkg, common.TarGzArchiveFormat, common.ZipArchiveFormat)
+	}
+	if flags.sign != "" && flags.pkg == "" {
+		log.Fatalf("Signing requires the translated artifacts to be packaged using --%s", packageFlag)
+	}
+
 	// Global settings
 	common.IgnoreEnvironment = flags.IgnoreEnv
+	cmdcommon.CheckOnServiceError()
+	cmdcommon.CheckSecretScan()
 	// Global settings
 
 	// Parameter cleaning and curate plan
@@ -82,14 +102,21 @@ func translateHandler(cmd *cobra.Command, flags translateFlags) {
 		// Global settings
 		cmdcommon.CheckSourcePath(flags.Srcpath)
 		flags.Outpath = filepath.Join(flags.Outpath, flags.Name)
-		cmdcommon.CheckOutputPath(flags.Outpath, flags.Overwrite)
+		cmdcommon.CheckOutputPath(flags.Outpath, flags.Overwrite || flags.OnlyDeferred)
 		if flags.Srcpath == flags.Outpath || common.IsParent(flags.Outpath, flags.Srcpath) || common.IsParent(flags.Srcpath, flags.Outpath) {
 			log.Fatalf("The source path %s and output path %s overlap.", flags.Srcpath, flags.Outpath)
 		}
 		if err := os.MkdirAll(flags.Outpath, common.DefaultDirectoryPermission); err != nil {
 			log.Fatalf("Failed to create the output directory at path %s Error: %q", flags.Outpath, err)
 		}
-		qaengine.StartEngine(flags.Qaskip, flags.qaport, flags.qadisablecli)
+		qaengine.StartEngine(flags.Qaskip, flags.qaport, flags.qadisablecli, flags.QAFileIODir)
+		qaengine.SetupSuppressions(flags.QADisable, flags.QADefaults)
+		if err := qaengine.SetupStorage(flags.QAStorage); err != nil {
+			log.Fatalf("Failed to set up the QA storage. Error: %q", err)
+		}
+		if err := qaengine.SetupDeferredFile(flags.Outpath, flags.OnlyDeferred); err != nil {
+			log.Fatalf("Failed to set up the deferred answers. Error: %q", err)
+		}
 		qaengine.SetupConfigFile(flags.Outpath, flags.Setconfigs, flags.Configs, flags.PreSets)
 		qaengine.SetupCacheFile(flags.Outpath, flags.Qacaches)
 		if err := qaengine.WriteStoresToDisk(); err != nil {
@@ -98,16 +125,40 @@ func translateHandler(cmd *cobra.Command, flags translateFlags) {
 		// Global settings
 
 		log.Debugf("Creating a new plan.")
-		p = move2kube.CreatePlan(flags.Srcpath, flags.Name, true)
-		p = move2kube.CuratePlan(p)
+		if err := move2kube.RunHooks(flags.Srcpath, projecttypes.PrePlanHookPhase, flags.Srcpath); err != nil {
+			log.Fatalf("Failed to run the hooks before planning. Error: %q", err)
+		}
+		p, err = move2kube.CreatePlan(flags.Srcpath, flags.Name, true)
+		cmdcommon.CheckError(err, "Failed to plan the source directory %s .", flags.Srcpath)
+		p, err = move2kube.CuratePlan(p)
+		cmdcommon.CheckError(err, "Failed to curate the plan.")
+		// The hooks get the path of the plan, so the plan is written to the output directory
+		if move2kube.HasHooks(flags.Srcpath, projecttypes.PostPlanHookPhase, projecttypes.PreTranslateHookPhase, projecttypes.PostTranslateHookPhase) {
+			flags.Planfile = filepath.Join(flags.Outpath, common.DefaultPlanFile)
+			if err := plan.WritePlan(flags.Planfile, p); err != nil {
+				log.Fatalf("Failed to write the plan to the file at path %s . Error: %q", flags.Planfile, err)
+			}
+			if err := move2kube.RunHooks(flags.Srcpath, projecttypes.PostPlanHookPhase, flags.Planfile); err != nil {
+				log.Fatalf("Failed to run the hooks after planning. Error: %q", err)
+			}
+		}
 	} else {
 		log.Infof("Detected a plan file at path %s. Will translate using this plan.", flags.Planfile)
 		if p, err = plan.ReadPlan(flags.Planfile); err != nil {
 			log.Fatalf("Unable to read the plan at path %s Error: %q", flags.Planfile, err)
 		}
+		if err := move2kube.ValidatePlanServices(p); err != nil {
+			log.Fatal(common.NewError(common.InvalidPlanErrorCode, err, "The plan at path %s is invalid.", flags.Planfile).Details())
+		}
+		if err := p.Spec.Outputs.ValidateTargets(); err != nil {
+			log.Fatal(common.NewError(common.InvalidPlanErrorCode, err, "The plan at path %s is invalid.", flags.Planfile).Details())
+		}
+		if err := p.Spec.ValidateProfiles(); err != nil {
+			log.Fatal(common.NewError(common.InvalidPlanErrorCode, err, "The plan at path %s is invalid.", flags.Planfile).Details())
+		}
 		if len(p.Spec.Inputs.Services) == 0 {
-			if len(p.Spec.Inputs.K8sFiles) == 0 {
-				log.Fatalf("Failed to find any services. Aborting.")
+			if len(p.Spec.Inputs.K8sFiles) == 0 && len(p.Spec.Inputs.HelmCharts) == 0 {
+				log.Fatal(common.NewError(common.NoServicesFoundErrorCode, nil, "Failed to find any services. Aborting.").Details())
 			} else {
 				log.Infof("No services found. Proceeding for kubernetes artifacts translation.")
 			}
@@ -124,14 +175,21 @@ func translateHandler(cmd *cobra.Command, flags translateFlags) {
 		// Global settings
 		cmdcommon.CheckSourcePath(p.Spec.Inputs.RootDir)
 		flags.Outpath = filepath.Join(flags.Outpath, p.Name)
-		cmdcommon.CheckOutputPath(flags.Outpath, flags.Overwrite)
+		cmdcommon.CheckOutputPath(flags.Outpath, flags.Overwrite || flags.OnlyDeferred)
 		if p.Spec.Inputs.RootDir == flags.Outpath || common.IsParent(flags.Outpath, p.Spec.Inputs.RootDir) || common.IsParent(p.Spec.Inputs.RootDir, flags.Outpath) {
 			log.Fatalf("The source path %s and output path %s overlap.", p.Spec.Inputs.RootDir, flags.Outpath)
 		}
 		if err := os.MkdirAll(flags.Outpath, common.DefaultDirectoryPermission); err != nil {
 			log.Fatalf("Failed to create the output directory at path %s Error: %q", flags.Outpath, err)
 		}
-		qaengine.StartEngine(flags.Qaskip, flags.qaport, flags.qadisablecli)
+		qaengine.StartEngine(flags.Qaskip, flags.qaport, flags.qadisablecli, flags.QAFileIODir)
+		qaengine.SetupSuppressions(flags.QADisable, flags.QADefaults)
+		if err := qaengine.SetupStorage(flags.QAStorage); err != nil {
+			log.Fatalf("Failed to set up the QA storage. Error: %q", err)
+		}
+		if err := qaengine.SetupDeferredFile(flags.Outpath, flags.OnlyDeferred); err != nil {
+			log.Fatalf("Failed to set up the deferred answers. Error: %q", err)
+		}
 		qaengine.SetupConfigFile(flags.Outpath, flags.Setconfigs, flags.Configs, flags.PreSets)
 		qaengine.SetupCacheFile(flags.Outpath, flags.Qacaches)
 		if err := qaengine.WriteStoresToDisk(); err != nil {
@@ -140,7 +198,8 @@ func translateHandler(cmd *cobra.Command, flags translateFlags) {
 		// Global settings
 
 		if flags.curate {
-			p = move2kube.CuratePlan(p)
+			p, err = move2kube.CuratePlan(p)
+			cmdcommon.CheckError(err, "Failed to curate the plan.")
 		}
 	}
 
@@ -149,8 +208,24 @@ func translateHandler(cmd *cobra.Command, flags translateFlags) {
 	if err != nil {
 		log.Fatalf("Failed to clean the paths:\n%+v\nError: %q", flags.TransformPaths, err)
 	}
-	move2kube.Translate(p, flags.Outpath, flags.qadisablecli, normalizedTransformPaths)
+	if err := move2kube.RunHooks(p.Spec.Inputs.RootDir, projecttypes.PreTranslateHookPhase, flags.Planfile, flags.Outpath); err != nil {
+		log.Fatalf("Failed to run the hooks before translating. Error: %q", err)
+	}
+	cmdcommon.CheckError(move2kube.Translate(p, flags.Outpath, flags.qadisablecli, normalizedTransformPaths), "Failed to translate the plan.")
+	if err := move2kube.RunHooks(p.Spec.Inputs.RootDir, projecttypes.PostTranslateHookPhase, flags.Planfile, flags.Outpath); err != nil {
+		log.Fatalf("Failed to run the hooks after translating. Error: %q", err)
+	}
 	log.Infof("Translated target artifacts can be found at [%s].", flags.Outpath)
+	if deferred := qaengine.GetDeferredProblems(); len(deferred) > 0 {
+		log.Warnf("The answers to %d questions were deferred and placeholders were used in the output. Fill in the answers in %s or run translate again with --%s to be asked only these questions.", len(deferred), filepath.Join(flags.Outpath, common.QADeferredFile), cmdcommon.OnlyDeferredFlag)
+	}
+	if flags.pkg != "" {
+		archivePath, err := move2kube.PackageOutput(flags.Outpath, filepath.Dir(flags.Outpath), flags.pkg, flags.sign, flags.signKey)
+		if err != nil {
+			log.Fatalf("Failed to package the translated artifacts. Error: %q", err)
+		}
+		log.Infof("The packaged artifacts can be found at [%s].", archivePath)
+	}
 }
 
 func getTranslateCommand() *cobra.Command {
@@ -163,10 +238,11 @@ func getTranslateCommand() *cobra.Command {
 
 	flags := translateFlags{}
 	translateCmd := &cobra.Command{
-		Use:   "translate",
-		Short: "Translate using move2kube plan",
-		Long:  "Translate artifacts using move2kube plan",
-		Run:   func(cmd *cobra.Command, _ []string) { translateHandler(cmd, flags) },
+		Use:     "translate",
+		Short:   "Translate using move2kube plan",
+		Long:    "Translate artifacts using move2kube plan",
+		Example: examples["translate"],
+		Run:     func(cmd *cobra.Command, _ []string) { translateHandler(cmd, flags) },
 	}
 
 	// Basic options
@@ -180,10 +256,26 @@ func getTranslateCommand() *cobra.Command {
 	translateCmd.Flags().StringSliceVarP(&flags.Configs, cmdcommon.ConfigFlag, "f", []string{}, "Specify config file locations")
 	translateCmd.Flags().StringSliceVarP(&flags.PreSets, cmdcommon.PreSetFlag, "r", []string{}, "Specify preset config to use")
 	translateCmd.Flags().StringArrayVarP(&flags.Setconfigs, cmdcommon.SetConfigFlag, "k", []string{}, "Specify config key-value pairs")
+	translateCmd.Flags().StringVar(&flags.pkg, packageFlag, "", "Package the translated artifacts into an archive. Valid values are "+common.TarGzArchiveFormat+" and "+common.ZipArchiveFormat+".")
+	translateCmd.Flags().StringVar(&flags.sign, cmdcommon.SignFlag, "", "Sign the package using "+move2kube.GPGSigner+" or "+move2kube.CosignSigner+". Requires --"+packageFlag+".")
+	translateCmd.Flags().StringVar(&flags.signKey, cmdcommon.SignKeyFlag, "", "Specify the key used to sign the package. It is the key id for gpg and the path to the key for cosign.")
 	translateCmd.Flags().StringSliceVarP(&flags.TransformPaths, cmdcommon.TransformsFlag, "t", []string{}, "Specify paths to the transformation scripts to apply. Can be the path to a script or the path to a folder containing the scripts.")
+	translateCmd.Flags().BoolVar(&common.BuildImages, cmdcommon.BuildImagesFlag, false, "Build the new images locally using docker or podman after the artifacts are generated, and record their digests in the manifest.")
 
 	// Advanced options
 	translateCmd.Flags().BoolVar(&flags.IgnoreEnv, cmdcommon.IgnoreEnvFlag, false, "Ignore data from local machine.")
+	translateCmd.Flags().StringVar(&common.OnServiceError, cmdcommon.OnServiceErrorFlag, common.AskOnServiceError, "Specify how a failed step of a service, like its containerization, is handled. Valid values are "+strings.Join(cmdcommon.OnServiceErrorOptions, ", ")+". With ask, the question defaults to skip.")
+	translateCmd.Flags().StringVar(&common.SecretScanMode, cmdcommon.SecretScanFlag, common.WarnSecretScanMode, "Specify how the credentials, like AWS keys or private keys, found in the sources copied into the build contexts are handled. Valid values are "+strings.Join(cmdcommon.SecretScanOptions, ", ")+". With block, the sources are not copied.")
+	translateCmd.Flags().BoolVar(&common.LicenseScan, cmdcommon.LicenseScanFlag, false, "Summarize the licenses of the dependencies in the go.mod, package.json, requirements.txt and pom.xml files of each service, and the provenance of its source, in the report.")
+	translateCmd.Flags().IntVar(&common.PlanWorkers, cmdcommon.PlanWorkersFlag, common.PlanWorkers, "Maximum number of translators and directories whose services are detected at the same time during planning. Defaults to the number of CPUs.")
+	translateCmd.Flags().StringSliceVar(&common.AnalysisPaths, cmdcommon.AnalysisFlag, []string{}, "Specify the results of rule engines, like the output.yaml of the Konveyor analyzer, whose issues are added to the services in the plan and the report.")
+	translateCmd.Flags().StringSliceVar(&flags.QADisable, cmdcommon.QADisableFlag, []string{}, "Specify the QA categories, like storages or target.imageregistry, whose questions are answered using the defaults manifest. Fails if a question of these categories has no default.")
+	translateCmd.Flags().StringVar(&flags.QAFileIODir, cmdcommon.QAFileIOFlag, "", "Specify a directory where each question is written as a json file and the answer is read from a json file, for UIs and scripts.")
+	translateCmd.Flags().StringVar(&flags.QAStorage, cmdcommon.QAStorageFlag, "", "Specify where the config and the QA cache are kept, so that the answers survive the engine. Valid values are file:// for the output directory (the default), configmap://<namespace>/<name>, secret://<namespace>/<name> and an http(s):// URL under which the files are read with GET and written with PUT.")
+	translateCmd.Flags().BoolVar(&flags.OnlyDeferred, cmdcommon.OnlyDeferredFlag, false, "Translate again into the output directory of a previous run, reusing its answers and asking only the questions whose answers were deferred.")
+	translateCmd.Flags().StringVar(&flags.QADefaults, cmdcommon.QADefaultsFlag, "", "Specify the defaults manifest, a config file which must have answers for all the categories disabled using --"+cmdcommon.QADisableFlag+".")
+	cmdcommon.AddK8sFilterFlags(translateCmd)
+	cmdcommon.AddProfileFlags(translateCmd, &flags.ProfileFlags)
 
 	// Hidden options
 	translateCmd.Flags().BoolVar(&flags.qadisablecli, qadisablecliFlag, false, "Enable/disable the QA Cli sub-system. Without this system, you will have to use the REST API to interact.")
@@ -193,5 +285,14 @@ func getTranslateCommand() *cobra.Command {
 	must(translateCmd.Flags().MarkHidden(qadisablecliFlag))
 	must(translateCmd.Flags().MarkHidden(qaportFlag))
 
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.SetConfigFlag, completeSetConfig))
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.PreSetFlag, completePresets))
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.QADisableFlag, fixedCompletion("services", "storages", "sources", "target", "repo", "containerization")))
+	must(translateCmd.RegisterFlagCompletionFunc(packageFlag, fixedCompletion(common.TarGzArchiveFormat, common.ZipArchiveFormat)))
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.SignFlag, fixedCompletion(move2kube.GPGSigner, move2kube.CosignSigner)))
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.OnServiceErrorFlag, fixedCompletion(cmdcommon.OnServiceErrorOptions...)))
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.SecretScanFlag, fixedCompletion(cmdcommon.SecretScanOptions...)))
+	must(translateCmd.RegisterFlagCompletionFunc(cmdcommon.ProfileFlag, fixedCompletion(cmdcommon.ProfileOptions...)))
+
 	return translateCmd
 }
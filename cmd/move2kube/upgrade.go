@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	cmdcommon "github.com/konveyor/move2kube/cmd/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type upgradeFlags struct {
+	channel string
+	force   bool
+}
+
+func upgradeHandler(flags upgradeFlags) {
+	release, err := move2kube.GetLatestRelease(flags.channel)
+	if err != nil {
+		log.Fatalf("Failed to find the latest release. Error: %q", err)
+	}
+	newer, err := move2kube.IsNewerRelease(release)
+	if err != nil {
+		log.Fatalf("Failed to compare the versions. Error: %q", err)
+	}
+	if !newer && !flags.force {
+		log.Infof("Already using the latest version in the %s channel.", flags.channel)
+		return
+	}
+	log.Infof("Upgrading to version %s", release.TagName)
+	if err := move2kube.Upgrade(release); err != nil {
+		log.Fatalf("Failed to upgrade to version %s . Error: %q", release.TagName, err)
+	}
+}
+
+func getUpgradeCommand() *cobra.Command {
+	viper.AutomaticEnv()
+
+	flags := upgradeFlags{}
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade to the latest version",
+		Long:  "Download the latest release in the channel, verify it using the published sha256 checksum and replace the running binary with it.",
+		Run:   func(*cobra.Command, []string) { upgradeHandler(flags) },
+	}
+
+	upgradeCmd.Flags().StringVar(&flags.channel, cmdcommon.ChannelFlag, move2kube.StableReleaseChannel, "Specify the release channel. Valid values are "+move2kube.StableReleaseChannel+" and "+move2kube.PreReleaseChannel+".")
+	upgradeCmd.Flags().BoolVar(&flags.force, "force", false, "Reinstall the latest release even if it is not newer than the running binary.")
+
+	if err := upgradeCmd.RegisterFlagCompletionFunc(cmdcommon.ChannelFlag, fixedCompletion(move2kube.StableReleaseChannel, move2kube.PreReleaseChannel)); err != nil {
+		panic(err)
+	}
+
+	return upgradeCmd
+}
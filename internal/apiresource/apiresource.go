@@ -160,6 +160,10 @@ func (o *APIResource) getClusterSupportedKinds(cluster collecttypes.ClusterMetad
 	kinds := o.IAPIResource.getSupportedKinds()
 	supportedKinds := []string{}
 	for _, kind := range kinds {
+		if kind == deploymentConfigKind && !cluster.IsDeploymentConfigPreferred() && cluster.GetSupportedVersions(common.DeploymentKind) != nil {
+			// Newer OpenShift versions recommend Deployments over DeploymentConfigs
+			continue
+		}
 		if cluster.GetSupportedVersions(kind) != nil {
 			supportedKinds = append(supportedKinds, kind)
 		}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/dapr"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// daprComponentTODOKey lists the steps to be done manually before deploying a Dapr component
+	daprComponentTODOKey = common.TODOAnnotation + "daprcomponent"
+	// defaultDaprComponentVersion is the version of the components whose version is not known
+	defaultDaprComponentVersion = "v1"
+)
+
+// DaprComponent handles the Dapr components connecting the Dapr sidecars of the services to their backing services
+type DaprComponent struct {
+}
+
+// getSupportedKinds returns kinds supported by DaprComponent
+func (*DaprComponent) getSupportedKinds() []string {
+	return []string{dapr.ComponentKind}
+}
+
+// createNewResources converts IR to runtime objects
+func (d *DaprComponent) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string) []runtime.Object {
+	objs := []runtime.Object{}
+	for _, component := range ir.DaprComponents {
+		objs = append(objs, d.createComponent(component))
+	}
+	if len(objs) > 0 && !common.IsStringPresent(supportedKinds, dapr.ComponentKind) {
+		log.Warnf("Dapr does not seem to be installed on the target cluster. Install it before deploying the generated Dapr components.")
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds converts kinds to cluster supported kinds
+func (d *DaprComponent) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, ir irtypes.EnhancedIR) ([]runtime.Object, bool) {
+	// Component is a common kind, so the group is checked too
+	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Kind == dapr.ComponentKind && gvk.Group == dapr.SchemeGroupVersion.Group {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+// createComponent creates the Dapr component. The metadata read from secrets refers to the secrets in the same namespace.
+func (d *DaprComponent) createComponent(component irtypes.DaprComponent) *dapr.Component {
+	version := component.Version
+	if version == "" {
+		version = defaultDaprComponentVersion
+	}
+	metadata := []dapr.MetadataItem{}
+	for _, item := range component.Metadata {
+		if item.SecretName != "" {
+			metadata = append(metadata, dapr.MetadataItem{Name: item.Name, SecretKeyRef: &dapr.SecretKeyRef{Name: item.SecretName, Key: item.SecretKey}})
+			continue
+		}
+		metadata = append(metadata, dapr.MetadataItem{Name: item.Name, Value: item.Value})
+	}
+	meta := metav1.ObjectMeta{Name: component.Name}
+	if component.TODO != "" {
+		meta.Annotations = map[string]string{daprComponentTODOKey: component.TODO}
+	}
+	log.Debugf("Created Dapr component %s", component.Name)
+	return &dapr.Component{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       dapr.ComponentKind,
+			APIVersion: dapr.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: dapr.ComponentSpec{
+			Type:     component.Type,
+			Version:  version,
+			Metadata: metadata,
+		},
+	}
+}
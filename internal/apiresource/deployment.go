@@ -34,7 +34,7 @@ import (
 	core "k8s.io/kubernetes/pkg/apis/core"
 )
 
-//TODO: Add support for replicaset, cronjob and statefulset
+//TODO: Add support for replicaset and statefulset
 
 const (
 	// podKind defines Pod Kind
@@ -47,6 +47,8 @@ const (
 	replicationControllerKind string = "ReplicationController"
 	// daemonSetKind defines DaemonSet Kind
 	daemonSetKind string = "DaemonSet"
+	// cronJobKind defines CronJob Kind
+	cronJobKind string = "CronJob"
 )
 
 // Deployment handles all objects like a Deployment
@@ -55,7 +57,7 @@ type Deployment struct {
 
 // getSupportedKinds returns kinds supported by the deployment
 func (d *Deployment) getSupportedKinds() []string {
-	return []string{podKind, jobKind, common.DeploymentKind, deploymentConfigKind, replicationControllerKind}
+	return []string{podKind, jobKind, cronJobKind, common.DeploymentKind, deploymentConfigKind, replicationControllerKind}
 }
 
 // createNewResources converts ir to runtime object
@@ -63,7 +65,14 @@ func (d *Deployment) createNewResources(ir irtypes.EnhancedIR, supportedKinds []
 	objs := []runtime.Object{}
 	for _, service := range ir.Services {
 		var obj runtime.Object
-		if service.Daemon {
+		if service.Schedule != "" {
+			if common.IsStringPresent(supportedKinds, cronJobKind) {
+				obj = d.createCronJob(service, ir.TargetClusterSpec)
+			} else {
+				log.Errorf("Could not find a valid resource type in cluster to create a cron job. Creating a Job for the service %s instead.", service.Name)
+				obj = d.createJob(service, ir.TargetClusterSpec)
+			}
+		} else if service.Daemon {
 			if !common.IsStringPresent(supportedKinds, daemonSetKind) {
 				log.Errorf("Creating Daemonset even though not supported by target cluster.")
 			}
@@ -72,11 +81,14 @@ func (d *Deployment) createNewResources(ir irtypes.EnhancedIR, supportedKinds []
 			if common.IsStringPresent(supportedKinds, jobKind) {
 				obj = d.createJob(service, ir.TargetClusterSpec)
 			} else {
-				log.Errorf("Could not find a valid resource type in cluster to create a job/pod.")
+				log.Errorf("Could not find a valid resource type in cluster to create a job. Creating a Pod for the service %s instead.", service.Name)
+				pod := d.createPod(service, ir.TargetClusterSpec)
+				pod.Spec.RestartPolicy = core.RestartPolicyOnFailure
+				obj = pod
 			}
-			pod := d.createPod(service, ir.TargetClusterSpec)
-			pod.Spec.RestartPolicy = core.RestartPolicyOnFailure
-			obj = pod
+		} else if ir.IsRolloutEnabled() {
+			// The Rollout api resource creates the Argo Rollout instead
+			continue
 		} else if common.IsStringPresent(supportedKinds, deploymentConfigKind) {
 			obj = d.createDeploymentConfig(service, ir.TargetClusterSpec)
 		} else if common.IsStringPresent(supportedKinds, common.DeploymentKind) {
@@ -176,7 +188,26 @@ func (d *Deployment) createDeployment(service irtypes.Service, cluster collectty
 	podSpec = d.convertVolumesKindsByPolicy(podSpec, cluster)
 	podSpec.RestartPolicy = core.RestartPolicyAlways
 	log.Debugf("Created deployment for %s", service.Name)
-	return d.toDeployment(meta, podSpec, int32(service.Replicas), cluster)
+	deployment := d.toDeployment(meta, podSpec, int32(service.Replicas), cluster)
+	if service.UpdateStrategy != nil {
+		deployment.Spec.Strategy = getDeploymentStrategy(*service.UpdateStrategy)
+		deployment.Spec.MinReadySeconds = service.UpdateStrategy.MinReadySeconds
+	}
+	return deployment
+}
+
+// getDeploymentStrategy returns the strategy replacing the pods of the deployment in batches, or all at once
+func getDeploymentStrategy(updateStrategy irtypes.UpdateStrategy) apps.DeploymentStrategy {
+	if updateStrategy.Recreate {
+		return apps.DeploymentStrategy{Type: apps.RecreateDeploymentStrategyType}
+	}
+	return apps.DeploymentStrategy{
+		Type: apps.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &apps.RollingUpdateDeployment{
+			MaxSurge:       updateStrategy.MaxSurge,
+			MaxUnavailable: updateStrategy.MaxUnavailable,
+		},
+	}
 }
 
 func (d *Deployment) createDeploymentConfig(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) *okdappsv1.DeploymentConfig {
@@ -247,6 +278,10 @@ func (d *Deployment) createJob(service irtypes.Service, cluster collecttypes.Clu
 	podspec := service.PodSpec
 	podspec = d.convertVolumesKindsByPolicy(podspec, cluster)
 	podspec.RestartPolicy = core.RestartPolicyOnFailure
+	// The pods which should not be restarted are replaced by new pods, up to the backoff limit
+	if service.RestartPolicy == core.RestartPolicyNever {
+		podspec.RestartPolicy = core.RestartPolicyNever
+	}
 	meta := metav1.ObjectMeta{
 		Name:        service.Name,
 		Labels:      getPodLabels(service.Name, service.Networks),
@@ -259,6 +294,7 @@ func (d *Deployment) createJob(service irtypes.Service, cluster collecttypes.Clu
 		},
 		ObjectMeta: meta,
 		Spec: batch.JobSpec{
+			BackoffLimit: service.BackoffLimit,
 			Template: core.PodTemplateSpec{
 				ObjectMeta: meta,
 				Spec:       podspec,
@@ -268,6 +304,24 @@ func (d *Deployment) createJob(service irtypes.Service, cluster collecttypes.Clu
 	return &pod
 }
 
+func (d *Deployment) createCronJob(service irtypes.Service, cluster collecttypes.ClusterMetadataSpec) *batch.CronJob {
+	job := d.createJob(service, cluster)
+	return &batch.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       cronJobKind,
+			APIVersion: batch.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: job.ObjectMeta,
+		Spec: batch.CronJobSpec{
+			Schedule: service.Schedule,
+			JobTemplate: batch.JobTemplateSpec{
+				ObjectMeta: job.ObjectMeta,
+				Spec:       job.Spec,
+			},
+		},
+	}
+}
+
 // Conversions section
 
 func (d *Deployment) toDeploymentConfig(meta metav1.ObjectMeta, podspec core.PodSpec, replicas int32, cluster collecttypes.ClusterMetadataSpec) *okdappsv1.DeploymentConfig {
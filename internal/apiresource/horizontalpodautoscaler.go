@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/argorollouts"
+	okdappsv1 "github.com/openshift/api/apps/v1"
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	autoscaling "k8s.io/kubernetes/pkg/apis/autoscaling"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// horizontalPodAutoscalerKind defines HorizontalPodAutoscaler Kind
+	horizontalPodAutoscalerKind = "HorizontalPodAutoscaler"
+	// autoscalingTODOKey is used to list the scaling rules which have to be configured manually
+	autoscalingTODOKey = common.TODOAnnotation + "autoscaling"
+)
+
+// HorizontalPodAutoscaler handles the autoscalers of the services
+type HorizontalPodAutoscaler struct {
+}
+
+// getSupportedKinds returns kinds supported by HorizontalPodAutoscaler
+func (*HorizontalPodAutoscaler) getSupportedKinds() []string {
+	return []string{horizontalPodAutoscalerKind}
+}
+
+// createNewResources converts IR to runtime objects
+func (h *HorizontalPodAutoscaler) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string) []runtime.Object {
+	objs := []runtime.Object{}
+	for _, service := range ir.Services {
+		// The services scaled on the events of KEDA triggers get their autoscaler from the ScaledObject
+		if service.Autoscaling == nil || len(service.Autoscaling.Triggers) > 0 || service.Daemon || !service.IsLongRunning() {
+			continue
+		}
+		if !common.IsStringPresent(supportedKinds, horizontalPodAutoscalerKind) {
+			log.Warnf("Could not find a valid resource type in cluster to create the autoscaler of the service %s", service.Name)
+			continue
+		}
+		objs = append(objs, h.createHorizontalPodAutoscaler(service, ir, supportedKinds))
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds converts kinds to cluster supported kinds
+func (h *HorizontalPodAutoscaler) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, ir irtypes.EnhancedIR) ([]runtime.Object, bool) {
+	if common.IsStringPresent(h.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+// getScaleTargetRef returns the workload of the service, created by the Deployment or Rollout api resources
+func getScaleTargetRef(service irtypes.Service, ir irtypes.EnhancedIR, supportedKinds []string) autoscaling.CrossVersionObjectReference {
+	if ir.IsRolloutEnabled() {
+		return autoscaling.CrossVersionObjectReference{Kind: argorollouts.RolloutKind, Name: service.Name, APIVersion: argorollouts.SchemeGroupVersion.String()}
+	}
+	if common.IsStringPresent(supportedKinds, deploymentConfigKind) {
+		return autoscaling.CrossVersionObjectReference{Kind: deploymentConfigKind, Name: service.Name, APIVersion: okdappsv1.SchemeGroupVersion.String()}
+	}
+	return autoscaling.CrossVersionObjectReference{Kind: common.DeploymentKind, Name: service.Name, APIVersion: appsv1.SchemeGroupVersion.String()}
+}
+
+func (h *HorizontalPodAutoscaler) createHorizontalPodAutoscaler(service irtypes.Service, ir irtypes.EnhancedIR, supportedKinds []string) *autoscaling.HorizontalPodAutoscaler {
+	scaleTargetRef := getScaleTargetRef(service, ir, supportedKinds)
+	minReplicas := int32(service.Autoscaling.MinReplicas)
+	metrics := []autoscaling.MetricSpec{}
+	todos := []string{}
+	for _, metric := range service.Autoscaling.Metrics {
+		target := autoscaling.MetricTarget{Type: autoscaling.UtilizationMetricType}
+		if metric.AverageValue != nil {
+			averageValue := metric.AverageValue.DeepCopy()
+			target = autoscaling.MetricTarget{Type: autoscaling.AverageValueMetricType, AverageValue: &averageValue}
+		} else {
+			averageUtilization := metric.AverageUtilization
+			target.AverageUtilization = &averageUtilization
+			if !h.hasResourceRequests(service, metric.Resource) {
+				todos = append(todos, fmt.Sprintf("Set the %s requests of the containers, since the utilization is relative to the requests.", metric.Resource))
+			}
+		}
+		metrics = append(metrics, autoscaling.MetricSpec{
+			Type:     autoscaling.ResourceMetricSourceType,
+			Resource: &autoscaling.ResourceMetricSource{Name: metric.Resource, Target: target},
+		})
+	}
+	if len(service.Autoscaling.UnsupportedRules) > 0 {
+		todo := fmt.Sprintf("The scaling rules %s could not be converted. Scale on them using custom metrics or a KEDA ScaledObject, like one with a prometheus trigger for the throughput.", strings.Join(service.Autoscaling.UnsupportedRules, ", "))
+		if len(metrics) == 0 {
+			todo += " Until then, the autoscaler scales on the cpu utilization."
+		}
+		todos = append(todos, todo)
+	}
+	meta := metav1.ObjectMeta{
+		Name:   service.Name,
+		Labels: getServiceLabels(service.Name),
+	}
+	if len(todos) > 0 {
+		meta.Annotations = map[string]string{autoscalingTODOKey: strings.Join(todos, " ")}
+	}
+	log.Debugf("Created horizontal pod autoscaler for %s", service.Name)
+	return &autoscaling.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       horizontalPodAutoscalerKind,
+			APIVersion: autoscaling.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: autoscaling.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: scaleTargetRef,
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    int32(service.Autoscaling.MaxReplicas),
+			Metrics:        metrics,
+		},
+	}
+}
+
+// hasResourceRequests returns true if all the containers of the service request the resource.
+// The requests default to the limits if only the limits are set.
+func (h *HorizontalPodAutoscaler) hasResourceRequests(service irtypes.Service, resourceName core.ResourceName) bool {
+	for _, container := range service.Containers {
+		_, hasRequest := container.Resources.Requests[resourceName]
+		_, hasLimit := container.Resources.Limits[resourceName]
+		if !hasRequest && !hasLimit {
+			return false
+		}
+	}
+	return true
+}
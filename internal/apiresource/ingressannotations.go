@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"fmt"
+	"strconv"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+)
+
+const (
+	nginxAnnotationPrefix   = "nginx.ingress.kubernetes.io/"
+	haproxyAnnotationPrefix = "haproxy-ingress.github.io/"
+	traefikAnnotationPrefix = "traefik.ingress.kubernetes.io/"
+	albAnnotationPrefix     = "alb.ingress.kubernetes.io/"
+
+	ingressAffinityAnnotation          = nginxAnnotationPrefix + "affinity"
+	ingressSessionCookieNameAnnotation = nginxAnnotationPrefix + "session-cookie-name"
+	ingressBackendProtocolAnnotation   = nginxAnnotationPrefix + "backend-protocol"
+	ingressProxyReadTimeoutAnnotation  = nginxAnnotationPrefix + "proxy-read-timeout"
+	ingressProxySendTimeoutAnnotation  = nginxAnnotationPrefix + "proxy-send-timeout"
+	traefikServersSchemeAnnotation     = traefikAnnotationPrefix + "service.serversscheme"
+	traefikStickyCookieAnnotation      = traefikAnnotationPrefix + "service.sticky.cookie"
+	traefikStickyCookieNameAnnotation  = traefikAnnotationPrefix + "service.sticky.cookie.name"
+
+	albIngressClassName   = "alb"
+	istioIngressClassName = "istio"
+)
+
+// ingressSettings are the settings of an ingress, which are configured using the annotations of the ingress controller
+type ingressSettings struct {
+	Timeout        int    // Seconds after which the idle connections to the backends are closed
+	BodySize       string // Maximum size of the request bodies, like 10m
+	StickySessions bool
+	TLSRedirect    bool
+	GRPC           bool
+}
+
+// ingressAnnotationPack holds the ingress class and the annotations used by an ingress controller for the settings of an ingress
+type ingressAnnotationPack struct {
+	className   string
+	annotations map[string]string
+	// unsupported are the settings which cannot be configured using annotations, along with how to configure them
+	unsupported []string
+}
+
+// getIngressAnnotationPack returns the annotations that configure the ingress for the controller. The NGINX ingress controller is used by default.
+func getIngressAnnotationPack(controller irtypes.IngressControllerType, settings ingressSettings) ingressAnnotationPack {
+	pack := ingressAnnotationPack{annotations: map[string]string{}}
+	timeout := strconv.Itoa(settings.Timeout)
+	switch controller {
+	case irtypes.NginxIngressController, "":
+		if settings.Timeout > 0 {
+			pack.annotations[ingressProxyReadTimeoutAnnotation] = timeout
+			pack.annotations[ingressProxySendTimeoutAnnotation] = timeout
+		}
+		if settings.BodySize != "" {
+			pack.annotations[nginxAnnotationPrefix+"proxy-body-size"] = settings.BodySize
+		}
+		if settings.StickySessions {
+			pack.annotations[ingressAffinityAnnotation] = "cookie"
+			pack.annotations[ingressSessionCookieNameAnnotation] = stickySessionCookieName
+		}
+		if settings.TLSRedirect {
+			pack.annotations[nginxAnnotationPrefix+"ssl-redirect"] = "true"
+		}
+		if settings.GRPC {
+			pack.annotations[ingressBackendProtocolAnnotation] = "GRPC"
+		}
+	case irtypes.HAProxyIngressController:
+		if settings.Timeout > 0 {
+			pack.annotations[haproxyAnnotationPrefix+"timeout-server"] = timeout + "s"
+			pack.annotations[haproxyAnnotationPrefix+"timeout-tunnel"] = timeout + "s"
+		}
+		if settings.BodySize != "" {
+			pack.annotations[haproxyAnnotationPrefix+"proxy-body-size"] = settings.BodySize
+		}
+		if settings.StickySessions {
+			pack.annotations[haproxyAnnotationPrefix+"affinity"] = "cookie"
+			pack.annotations[haproxyAnnotationPrefix+"session-cookie-name"] = stickySessionCookieName
+		}
+		if settings.TLSRedirect {
+			pack.annotations[haproxyAnnotationPrefix+"ssl-redirect"] = "true"
+		}
+		if settings.GRPC {
+			pack.annotations[haproxyAnnotationPrefix+"backend-protocol"] = "h2"
+		}
+	case irtypes.TraefikIngressController:
+		// The sticky sessions and the gRPC backends are configured using the annotations of the services
+		if settings.Timeout > 0 {
+			pack.unsupported = append(pack.unsupported, fmt.Sprintf("timeout of %ss: set the respondingTimeouts of the entrypoint in the static configuration of traefik", timeout))
+		}
+		if settings.BodySize != "" {
+			pack.unsupported = append(pack.unsupported, fmt.Sprintf("body size of %s: add a buffering middleware and reference it in the annotation %s", settings.BodySize, traefikAnnotationPrefix+"router.middlewares"))
+		}
+		if settings.TLSRedirect {
+			pack.annotations[traefikAnnotationPrefix+"router.tls"] = "true"
+			pack.unsupported = append(pack.unsupported, "TLS redirect: add a redirectScheme middleware to the http entrypoint")
+		}
+	case irtypes.ALBIngressController:
+		pack.className = albIngressClassName
+		pack.annotations[albAnnotationPrefix+"scheme"] = "internet-facing"
+		pack.annotations[albAnnotationPrefix+"target-type"] = "ip"
+		if settings.Timeout > 0 {
+			pack.annotations[albAnnotationPrefix+"load-balancer-attributes"] = "idle_timeout.timeout_seconds=" + timeout
+		}
+		// The body size is not limited by the application load balancers
+		if settings.StickySessions {
+			pack.annotations[albAnnotationPrefix+"target-group-attributes"] = "stickiness.enabled=true,stickiness.type=lb_cookie"
+		}
+		if settings.TLSRedirect {
+			pack.annotations[albAnnotationPrefix+"listen-ports"] = `[{"HTTP": 80}, {"HTTPS": 443}]`
+			pack.annotations[albAnnotationPrefix+"ssl-redirect"] = "443"
+		}
+		if settings.GRPC {
+			pack.annotations[albAnnotationPrefix+"backend-protocol-version"] = "GRPC"
+		}
+	case irtypes.IstioIngressController:
+		pack.className = istioIngressClassName
+		if settings.Timeout > 0 {
+			pack.unsupported = append(pack.unsupported, fmt.Sprintf("timeout of %ss: set the timeout of the http routes in a VirtualService", timeout))
+		}
+		if settings.StickySessions {
+			pack.unsupported = append(pack.unsupported, "sticky sessions: set the consistentHash of the loadBalancer in a DestinationRule")
+		}
+		if settings.TLSRedirect {
+			pack.unsupported = append(pack.unsupported, "TLS redirect: set httpsRedirect in the tls of the server of the istio ingress Gateway")
+		}
+	default:
+		if settings.Timeout > 0 {
+			pack.unsupported = append(pack.unsupported, fmt.Sprintf("timeout of %ss", timeout))
+		}
+		if settings.BodySize != "" {
+			pack.unsupported = append(pack.unsupported, "body size of "+settings.BodySize)
+		}
+		if settings.StickySessions {
+			pack.unsupported = append(pack.unsupported, "sticky sessions")
+		}
+		if settings.TLSRedirect {
+			pack.unsupported = append(pack.unsupported, "TLS redirect")
+		}
+		if settings.GRPC {
+			pack.unsupported = append(pack.unsupported, "gRPC backends")
+		}
+	}
+	return pack
+}
+
+// hasGRPCAnnotation returns true if the gRPC backends are configured using an annotation of the ingress, which applies to all its backends
+func hasGRPCAnnotation(controller irtypes.IngressControllerType) bool {
+	return controller == irtypes.NginxIngressController || controller == irtypes.HAProxyIngressController || controller == irtypes.ALBIngressController
+}
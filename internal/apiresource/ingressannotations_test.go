@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	networking "k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func TestGetIngressAnnotationPack(t *testing.T) {
+	settings := ingressSettings{Timeout: 120, BodySize: "10m", StickySessions: true, TLSRedirect: true}
+	testcases := []struct {
+		controller      irtypes.IngressControllerType
+		wantClassName   string
+		wantAnnotations map[string]string
+		wantUnsupported int
+	}{
+		{
+			controller: irtypes.NginxIngressController,
+			wantAnnotations: map[string]string{
+				"nginx.ingress.kubernetes.io/proxy-read-timeout":  "120",
+				"nginx.ingress.kubernetes.io/proxy-send-timeout":  "120",
+				"nginx.ingress.kubernetes.io/proxy-body-size":     "10m",
+				"nginx.ingress.kubernetes.io/affinity":            "cookie",
+				"nginx.ingress.kubernetes.io/session-cookie-name": stickySessionCookieName,
+				"nginx.ingress.kubernetes.io/ssl-redirect":        "true",
+			},
+		},
+		{
+			controller: irtypes.HAProxyIngressController,
+			wantAnnotations: map[string]string{
+				"haproxy-ingress.github.io/timeout-server":      "120s",
+				"haproxy-ingress.github.io/timeout-tunnel":      "120s",
+				"haproxy-ingress.github.io/proxy-body-size":     "10m",
+				"haproxy-ingress.github.io/affinity":            "cookie",
+				"haproxy-ingress.github.io/session-cookie-name": stickySessionCookieName,
+				"haproxy-ingress.github.io/ssl-redirect":        "true",
+			},
+		},
+		{
+			controller:      irtypes.TraefikIngressController,
+			wantAnnotations: map[string]string{"traefik.ingress.kubernetes.io/router.tls": "true"},
+			wantUnsupported: 3,
+		},
+		{
+			controller:    irtypes.ALBIngressController,
+			wantClassName: albIngressClassName,
+			wantAnnotations: map[string]string{
+				"alb.ingress.kubernetes.io/scheme":                   "internet-facing",
+				"alb.ingress.kubernetes.io/target-type":              "ip",
+				"alb.ingress.kubernetes.io/load-balancer-attributes": "idle_timeout.timeout_seconds=120",
+				"alb.ingress.kubernetes.io/target-group-attributes":  "stickiness.enabled=true,stickiness.type=lb_cookie",
+				"alb.ingress.kubernetes.io/listen-ports":             `[{"HTTP": 80}, {"HTTPS": 443}]`,
+				"alb.ingress.kubernetes.io/ssl-redirect":             "443",
+			},
+		},
+		{
+			controller:      irtypes.IstioIngressController,
+			wantClassName:   istioIngressClassName,
+			wantAnnotations: map[string]string{},
+			wantUnsupported: 3,
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(string(testcase.controller), func(t *testing.T) {
+			pack := getIngressAnnotationPack(testcase.controller, settings)
+			if pack.className != testcase.wantClassName {
+				t.Fatalf("Expected the ingress class %q. Actual: %q", testcase.wantClassName, pack.className)
+			}
+			if !reflect.DeepEqual(pack.annotations, testcase.wantAnnotations) {
+				t.Fatalf("Failed to get the annotations. Expected: %+v Actual: %+v", testcase.wantAnnotations, pack.annotations)
+			}
+			if len(pack.unsupported) != testcase.wantUnsupported {
+				t.Fatalf("Expected %d unsupported settings. Actual: %v", testcase.wantUnsupported, pack.unsupported)
+			}
+		})
+	}
+}
+
+func TestCreateIngressWithAnnotationPack(t *testing.T) {
+	ir := getIRWithExposedServices(true)
+	ir.GatewayClassName = ""
+	ir.IngressController = irtypes.IstioIngressController
+	ir.IngressTimeout = 30
+	objs := (&Service{}).createNewResources(ir, []string{common.ServiceKind, common.IngressKind})
+	ingresses := getObjectsOfKind(objs, common.IngressKind)
+	if len(ingresses) != 1 {
+		t.Fatalf("Expected 1 ingress. Actual: %+v", ingresses)
+	}
+	ingress := ingresses[0].(*networking.Ingress)
+	if ingress.Spec.IngressClassName == nil || *ingress.Spec.IngressClassName != istioIngressClassName {
+		t.Fatalf("Expected the ingress class %s. Actual: %+v", istioIngressClassName, ingress.Spec)
+	}
+	if ingress.Annotations[ingressTODOKey] == "" {
+		t.Fatalf("Expected a TODO for the timeout which cannot be configured using annotations. Actual: %+v", ingress.Annotations)
+	}
+}
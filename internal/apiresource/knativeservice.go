@@ -19,6 +19,7 @@ package apiresource
 import (
 	"github.com/konveyor/move2kube/internal/k8sschema"
 	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/spf13/cast"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	core "k8s.io/kubernetes/pkg/apis/core"
@@ -28,6 +29,10 @@ import (
 const (
 	// knativeServiceKind defines the KNative service kind
 	knativeServiceKind string = "Service"
+	// knativeMinScaleAnnotation sets the minimum number of pods of a revision
+	knativeMinScaleAnnotation = "autoscaling.knative.dev/minScale"
+	// knativeMaxScaleAnnotation sets the maximum number of pods of a revision
+	knativeMaxScaleAnnotation = "autoscaling.knative.dev/maxScale"
 )
 
 // KnativeService handles the Knative service object
@@ -41,6 +46,16 @@ func (d *KnativeService) createNewResources(ir irtypes.EnhancedIR, supportedKind
 	for _, service := range ir.Services {
 		podSpec := service.PodSpec
 		podSpec.RestartPolicy = core.RestartPolicyAlways
+		template := knativev1.RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Annotations: getKnativeScaleAnnotations(service)},
+			Spec: knativev1.RevisionSpec{
+				PodSpec: k8sschema.ConvertToV1PodSpec(&podSpec),
+			},
+		}
+		if service.ContainerConcurrency > 0 {
+			containerConcurrency := int64(service.ContainerConcurrency)
+			template.Spec.ContainerConcurrency = &containerConcurrency
+		}
 		knativeservice := &knativev1.Service{
 			TypeMeta: metav1.TypeMeta{
 				Kind:       knativeServiceKind,
@@ -53,11 +68,7 @@ func (d *KnativeService) createNewResources(ir irtypes.EnhancedIR, supportedKind
 			},
 			Spec: knativev1.ServiceSpec{
 				ConfigurationSpec: knativev1.ConfigurationSpec{
-					Template: knativev1.RevisionTemplateSpec{
-						Spec: knativev1.RevisionSpec{
-							PodSpec: k8sschema.ConvertToV1PodSpec(&podSpec),
-						},
-					},
+					Template: template,
 				},
 			},
 		}
@@ -66,6 +77,18 @@ func (d *KnativeService) createNewResources(ir irtypes.EnhancedIR, supportedKind
 	return objs
 }
 
+// getKnativeScaleAnnotations returns the annotations setting the bounds of the autoscaling of the revisions. Knative scales
+// on the concurrent requests, so the metrics of the service are not used.
+func getKnativeScaleAnnotations(service irtypes.Service) map[string]string {
+	if service.Autoscaling == nil {
+		return nil
+	}
+	return map[string]string{
+		knativeMinScaleAnnotation: cast.ToString(service.Autoscaling.MinReplicas),
+		knativeMaxScaleAnnotation: cast.ToString(service.Autoscaling.MaxReplicas),
+	}
+}
+
 // convertToClusterSupportedKinds converts kinds to cluster supported kinds
 func (d *KnativeService) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR) ([]runtime.Object, bool) {
 	if d1, ok := obj.(*knativev1.Service); ok {
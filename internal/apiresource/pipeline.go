@@ -36,6 +36,7 @@ const (
 	gitRepoURLPlaceholder     = "<TODO: insert git repo url>"
 	contextPathPlaceholder    = "<TODO: insert path to the directory containing Dockerfile>"
 	dockerfilePathPlaceholder = "<TODO: insert path to the Dockerfile>"
+	cacheRepoSuffix           = "-cache"
 )
 
 // Pipeline handles all objects like a Tekton pipeline.
@@ -139,6 +140,11 @@ func (*Pipeline) createNewResource(irpipeline tekton.Pipeline, ir irtypes.Enhanc
 					{Name: "IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "$(params.image-registry-url)/" + imageName}},
 					{Name: "DOCKERFILE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: dockerfilePath}},
 					{Name: "CONTEXT", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: contextPath}},
+					// The layers are cached in a repo per image, so that the builds after the first one are not cold
+					{Name: "EXTRA_ARGS", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeArray, ArrayVal: []string{
+						"--cache=true",
+						"--cache-repo=$(params.image-registry-url)/" + getCacheRepoName(imageName),
+					}}},
 				},
 			}
 			tasks = append(tasks, cloneTask, buildPushTask)
@@ -158,6 +164,14 @@ func (*Pipeline) createNewResource(irpipeline tekton.Pipeline, ir irtypes.Enhanc
 	return pipeline
 }
 
+// getCacheRepoName returns the name of the repo used to cache the layers of the image
+func getCacheRepoName(imageName string) string {
+	ref := common.ParseImageReference(imageName)
+	ref.Tag = ""
+	ref.Digest = ""
+	return ref.String() + cacheRepoSuffix
+}
+
 // convertToClusterSupportedKinds converts the object to supported types if possible.
 func (p *Pipeline) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, _ irtypes.EnhancedIR) ([]runtime.Object, bool) {
 	if common.IsStringPresent(p.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/k8sschema"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/argorollouts"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// analysisServiceNameArg is the argument of the analysis templates that contains the service running the new version
+	analysisServiceNameArg = "service-name"
+	canaryPauseDuration    = "5m"
+)
+
+// Rollout handles the Argo Rollouts that replace the Deployments when using canary or blue green updates
+type Rollout struct {
+}
+
+// getSupportedKinds returns kinds supported by Rollout
+func (*Rollout) getSupportedKinds() []string {
+	return []string{argorollouts.RolloutKind, argorollouts.AnalysisTemplateKind}
+}
+
+// createNewResources converts IR to runtime objects
+func (r *Rollout) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string) []runtime.Object {
+	objs := []runtime.Object{}
+	if !ir.IsRolloutEnabled() {
+		return objs
+	}
+	for _, service := range ir.Services {
+		if !service.IsLongRunning() {
+			continue
+		}
+		analysisTemplate := r.createAnalysisTemplate(service, ir)
+		objs = append(objs, r.createRollout(service, analysisTemplate.Name, ir), analysisTemplate)
+	}
+	if len(objs) > 0 && !common.IsStringPresent(supportedKinds, argorollouts.RolloutKind) {
+		log.Warnf("Argo Rollouts does not seem to be installed on the target cluster. Install it before deploying the generated Rollouts.")
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds converts kinds to cluster supported kinds
+func (r *Rollout) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, ir irtypes.EnhancedIR) ([]runtime.Object, bool) {
+	if common.IsStringPresent(r.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+func (r *Rollout) createRollout(service irtypes.Service, analysisTemplateName string, ir irtypes.EnhancedIR) *argorollouts.Rollout {
+	meta := metav1.ObjectMeta{
+		Name:        service.Name,
+		Labels:      getPodLabels(service.Name, service.Networks),
+		Annotations: getAnnotations(service),
+	}
+	podSpec := new(Deployment).convertVolumesKindsByPolicy(service.PodSpec, ir.TargetClusterSpec)
+	podSpec.RestartPolicy = core.RestartPolicyAlways
+	replicas := int32(service.Replicas)
+	analysis := &argorollouts.RolloutAnalysis{
+		Templates: []argorollouts.RolloutAnalysisTemplate{{TemplateName: analysisTemplateName}},
+		Args:      []argorollouts.AnalysisRunArgument{{Name: analysisServiceNameArg, Value: getRolloutServiceName(service.Name, ir.DeploymentStrategy)}},
+	}
+	strategy := argorollouts.RolloutStrategy{}
+	if ir.DeploymentStrategy == irtypes.BlueGreenDeploymentStrategy {
+		strategy.BlueGreen = &argorollouts.BlueGreenStrategy{
+			ActiveService:        service.Name,
+			PreviewService:       getRolloutServiceName(service.Name, ir.DeploymentStrategy),
+			PrePromotionAnalysis: analysis,
+		}
+	} else {
+		firstWeight, secondWeight := int32(20), int32(50)
+		strategy.Canary = &argorollouts.CanaryStrategy{
+			CanaryService: getRolloutServiceName(service.Name, ir.DeploymentStrategy),
+			StableService: service.Name,
+			Steps: []argorollouts.CanaryStep{
+				{SetWeight: &firstWeight},
+				{Analysis: analysis},
+				{SetWeight: &secondWeight},
+				{Pause: &argorollouts.RolloutPause{Duration: canaryPauseDuration}},
+			},
+		}
+	}
+	log.Debugf("Created rollout for %s", service.Name)
+	return &argorollouts.Rollout{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       argorollouts.RolloutKind,
+			APIVersion: argorollouts.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: argorollouts.RolloutSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: getServiceLabels(service.Name),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: meta,
+				Spec:       k8sschema.ConvertToV1PodSpec(&podSpec),
+			},
+			Strategy: strategy,
+		},
+	}
+}
+
+// createAnalysisTemplate creates a stub analysis template that queries the metrics endpoint of the new version
+func (r *Rollout) createAnalysisTemplate(service irtypes.Service, ir irtypes.EnhancedIR) *argorollouts.AnalysisTemplate {
+	port := int32(common.DefaultServicePort)
+	if len(service.ServiceToPodPortForwardings) > 0 {
+		port = service.ServiceToPodPortForwardings[0].ServicePort.Number
+	}
+	count, failureLimit := int32(3), int32(1)
+	name := service.Name + "-metrics"
+	return &argorollouts.AnalysisTemplate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       argorollouts.AnalysisTemplateKind,
+			APIVersion: argorollouts.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getServiceLabels(service.Name),
+			Annotations: map[string]string{
+				common.TODOAnnotation + "analysis": "Replace the success condition with a check on the metrics of the service. Eg: the error rate.",
+			},
+		},
+		Spec: argorollouts.AnalysisTemplateSpec{
+			Args: []argorollouts.Argument{{Name: analysisServiceNameArg}},
+			Metrics: []argorollouts.Metric{{
+				Name:             "metrics-endpoint",
+				Interval:         "30s",
+				Count:            &count,
+				FailureLimit:     &failureLimit,
+				SuccessCondition: "result != nil",
+				Provider: argorollouts.MetricProvider{
+					Web: &argorollouts.WebMetric{
+						URL:            fmt.Sprintf("http://{{args.%s}}:%d%s", analysisServiceNameArg, port, ir.MetricsPath),
+						JSONPath:       "{$}",
+						TimeoutSeconds: 10,
+					},
+				},
+			}},
+		},
+	}
+}
+
+// getRolloutServiceName returns the name of the service that sends traffic only to the new version
+func getRolloutServiceName(serviceName string, strategy irtypes.DeploymentStrategyType) string {
+	if strategy == irtypes.BlueGreenDeploymentStrategy {
+		return serviceName + "-preview"
+	}
+	return serviceName + "-canary"
+}
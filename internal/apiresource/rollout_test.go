@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/argorollouts"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func getIRWithStrategy(strategy irtypes.DeploymentStrategyType) irtypes.EnhancedIR {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	ir.Name = "myproject"
+	ir.DeploymentStrategy = strategy
+	ir.MetricsPath = "/metrics"
+	web := irtypes.NewServiceWithName("web")
+	web.Replicas = 2
+	web.Containers = []core.Container{{Name: "web", Image: "web:latest"}}
+	web.AddPortForwarding(irtypes.Port{Number: 8080}, irtypes.Port{Number: 8080})
+	ir.Services[web.Name] = web
+	job := irtypes.NewServiceWithName("migrate")
+	job.RestartPolicy = core.RestartPolicyOnFailure
+	job.Containers = []core.Container{{Name: "migrate", Image: "migrate:latest"}}
+	ir.Services[job.Name] = job
+	return irtypes.NewEnhancedIRFromIR(ir)
+}
+
+func TestCreateRollouts(t *testing.T) {
+	supportedKinds := []string{argorollouts.RolloutKind, argorollouts.AnalysisTemplateKind}
+
+	t.Run("rolling update does not create rollouts", func(t *testing.T) {
+		objs := (&Rollout{}).createNewResources(getIRWithStrategy(irtypes.RollingUpdateDeploymentStrategy), supportedKinds)
+		if len(objs) != 0 {
+			t.Fatalf("Expected no objects. Actual: %+v", objs)
+		}
+		deployments := (&Deployment{}).createNewResources(getIRWithStrategy(irtypes.RollingUpdateDeploymentStrategy), []string{common.DeploymentKind, jobKind})
+		if len(getObjectsOfKind(deployments, common.DeploymentKind)) != 1 {
+			t.Fatalf("Expected a deployment for the web service. Actual: %+v", deployments)
+		}
+	})
+
+	t.Run("canary", func(t *testing.T) {
+		ir := getIRWithStrategy(irtypes.CanaryDeploymentStrategy)
+		objs := (&Rollout{}).createNewResources(ir, supportedKinds)
+		rollouts := getObjectsOfKind(objs, argorollouts.RolloutKind)
+		if len(rollouts) != 1 {
+			t.Fatalf("Expected a rollout only for the long running service. Actual: %+v", rollouts)
+		}
+		rollout := rollouts[0].(*argorollouts.Rollout)
+		if rollout.Spec.Strategy.Canary == nil || rollout.Spec.Strategy.BlueGreen != nil {
+			t.Fatalf("Expected a canary strategy. Actual: %+v", rollout.Spec.Strategy)
+		}
+		if rollout.Spec.Strategy.Canary.CanaryService != "web-canary" || rollout.Spec.Strategy.Canary.StableService != "web" {
+			t.Fatalf("Failed to set the canary and stable services properly. Actual: %+v", rollout.Spec.Strategy.Canary)
+		}
+		if *rollout.Spec.Replicas != 2 || rollout.Spec.Template.Spec.Containers[0].Image != "web:latest" {
+			t.Fatalf("Failed to create the rollout from the service properly. Actual: %+v", rollout.Spec)
+		}
+		templates := getObjectsOfKind(objs, argorollouts.AnalysisTemplateKind)
+		if len(templates) != 1 {
+			t.Fatalf("Expected 1 analysis template. Actual: %+v", templates)
+		}
+		template := templates[0].(*argorollouts.AnalysisTemplate)
+		if url := template.Spec.Metrics[0].Provider.Web.URL; url != "http://{{args.service-name}}:8080/metrics" {
+			t.Fatalf("Failed to wire the analysis template to the metrics endpoint. Actual: %s", url)
+		}
+		deployments := (&Deployment{}).createNewResources(ir, []string{common.DeploymentKind, jobKind})
+		if len(getObjectsOfKind(deployments, common.DeploymentKind)) != 0 {
+			t.Fatalf("Expected no deployments when using rollouts. Actual: %+v", deployments)
+		}
+		services := getObjectsOfKind((&Service{}).createNewResources(ir, []string{common.ServiceKind}), common.ServiceKind)
+		if len(services) != 3 {
+			t.Fatalf("Expected the canary service along with the services. Actual: %+v", services)
+		}
+	})
+
+	t.Run("blue green", func(t *testing.T) {
+		objs := (&Rollout{}).createNewResources(getIRWithStrategy(irtypes.BlueGreenDeploymentStrategy), supportedKinds)
+		rollout := getObjectsOfKind(objs, argorollouts.RolloutKind)[0].(*argorollouts.Rollout)
+		blueGreen := rollout.Spec.Strategy.BlueGreen
+		if blueGreen == nil || blueGreen.ActiveService != "web" || blueGreen.PreviewService != "web-preview" {
+			t.Fatalf("Failed to create the blue green strategy properly. Actual: %+v", rollout.Spec.Strategy)
+		}
+		if blueGreen.PrePromotionAnalysis == nil || blueGreen.PrePromotionAnalysis.Args[0].Value != "web-preview" {
+			t.Fatalf("Expected the analysis to run against the preview service. Actual: %+v", blueGreen.PrePromotionAnalysis)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/keda"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// kedaUtilizationMetricType is the metric type of the cpu and memory triggers targeting a percentage of the requests
+	kedaUtilizationMetricType = "Utilization"
+	// kedaAverageValueMetricType is the metric type of the cpu and memory triggers targeting an absolute value
+	kedaAverageValueMetricType = "AverageValue"
+)
+
+// ScaledObject handles the KEDA ScaledObjects of the services scaled on the events of triggers, like the length of a queue
+type ScaledObject struct {
+}
+
+// getSupportedKinds returns kinds supported by ScaledObject
+func (*ScaledObject) getSupportedKinds() []string {
+	return []string{keda.ScaledObjectKind, keda.TriggerAuthenticationKind}
+}
+
+// createNewResources converts IR to runtime objects
+func (s *ScaledObject) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string) []runtime.Object {
+	objs := []runtime.Object{}
+	for _, service := range ir.Services {
+		if service.Autoscaling == nil || len(service.Autoscaling.Triggers) == 0 || service.Daemon || !service.IsLongRunning() {
+			continue
+		}
+		objs = append(objs, s.createScaledObject(service, ir, supportedKinds))
+		for _, trigger := range service.Autoscaling.Triggers {
+			if len(trigger.SecretRefs) > 0 {
+				objs = append(objs, s.createTriggerAuthentication(service, trigger))
+			}
+		}
+	}
+	if len(objs) > 0 && !common.IsStringPresent(supportedKinds, keda.ScaledObjectKind) {
+		log.Warnf("KEDA does not seem to be installed on the target cluster. Install it before deploying the generated ScaledObjects.")
+	}
+	return objs
+}
+
+// convertToClusterSupportedKinds converts kinds to cluster supported kinds
+func (s *ScaledObject) convertToClusterSupportedKinds(obj runtime.Object, supportedKinds []string, otherobjs []runtime.Object, ir irtypes.EnhancedIR) ([]runtime.Object, bool) {
+	if common.IsStringPresent(s.getSupportedKinds(), obj.GetObjectKind().GroupVersionKind().Kind) {
+		return []runtime.Object{obj}, true
+	}
+	return nil, false
+}
+
+// createScaledObject creates the ScaledObject scaling the workload of the service on its triggers, and on its cpu and memory metrics
+func (s *ScaledObject) createScaledObject(service irtypes.Service, ir irtypes.EnhancedIR, supportedKinds []string) *keda.ScaledObject {
+	scaleTargetRef := getScaleTargetRef(service, ir, supportedKinds)
+	minReplicas := int32(service.Autoscaling.MinReplicas)
+	maxReplicas := int32(service.Autoscaling.MaxReplicas)
+	triggers := []keda.ScaleTriggers{}
+	for _, metric := range service.Autoscaling.Metrics {
+		trigger := keda.ScaleTriggers{Type: string(metric.Resource), MetricType: kedaUtilizationMetricType, Metadata: map[string]string{"value": cast.ToString(metric.AverageUtilization)}}
+		if metric.AverageValue != nil {
+			trigger = keda.ScaleTriggers{Type: string(metric.Resource), MetricType: kedaAverageValueMetricType, Metadata: map[string]string{"value": metric.AverageValue.String()}}
+		}
+		triggers = append(triggers, trigger)
+	}
+	for _, trigger := range service.Autoscaling.Triggers {
+		scaleTrigger := keda.ScaleTriggers{Type: trigger.Type, Name: trigger.Name, Metadata: common.MergeStringMaps(trigger.Metadata, nil)}
+		if len(trigger.SecretRefs) > 0 {
+			scaleTrigger.AuthenticationRef = &keda.ScaledObjectAuthRef{Name: getTriggerAuthenticationName(service.Name, trigger)}
+		}
+		triggers = append(triggers, scaleTrigger)
+	}
+	meta := metav1.ObjectMeta{
+		Name:   service.Name,
+		Labels: getServiceLabels(service.Name),
+	}
+	if len(service.Autoscaling.UnsupportedRules) > 0 {
+		meta.Annotations = map[string]string{autoscalingTODOKey: fmt.Sprintf("The scaling rules %s could not be converted. Add the KEDA triggers scaling on them.", strings.Join(service.Autoscaling.UnsupportedRules, ", "))}
+	}
+	log.Debugf("Created scaled object for %s", service.Name)
+	return &keda.ScaledObject{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       keda.ScaledObjectKind,
+			APIVersion: keda.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: keda.ScaledObjectSpec{
+			ScaleTargetRef:  &keda.ScaleTarget{Name: scaleTargetRef.Name, Kind: scaleTargetRef.Kind, APIVersion: scaleTargetRef.APIVersion},
+			MinReplicaCount: &minReplicas,
+			MaxReplicaCount: &maxReplicas,
+			Triggers:        triggers,
+		},
+	}
+}
+
+// createTriggerAuthentication creates the TriggerAuthentication setting the parameters of the trigger from secrets
+func (s *ScaledObject) createTriggerAuthentication(service irtypes.Service, trigger irtypes.AutoscalingTrigger) *keda.TriggerAuthentication {
+	secretTargetRefs := []keda.AuthSecretTargetRef{}
+	for _, secretRef := range trigger.SecretRefs {
+		secretTargetRefs = append(secretTargetRefs, keda.AuthSecretTargetRef{Parameter: secretRef.Parameter, Name: secretRef.SecretName, Key: secretRef.Key})
+	}
+	return &keda.TriggerAuthentication{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       keda.TriggerAuthenticationKind,
+			APIVersion: keda.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   getTriggerAuthenticationName(service.Name, trigger),
+			Labels: getServiceLabels(service.Name),
+		},
+		Spec: keda.TriggerAuthenticationSpec{SecretTargetRef: secretTargetRefs},
+	}
+}
+
+func getTriggerAuthenticationName(serviceName string, trigger irtypes.AutoscalingTrigger) string {
+	name := trigger.Name
+	if name == "" {
+		name = trigger.Type
+	}
+	return common.MakeFileNameCompliant(serviceName + "-" + name)
+}
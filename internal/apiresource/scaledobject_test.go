@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/keda"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	autoscaling "k8s.io/kubernetes/pkg/apis/autoscaling"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestCreateScaledObjects(t *testing.T) {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	worker := irtypes.NewServiceWithName("worker")
+	worker.Containers = []core.Container{{Name: "worker", Image: "worker:latest"}}
+	worker.Autoscaling = &irtypes.Autoscaling{
+		MaxReplicas: 5,
+		Metrics:     []irtypes.AutoscalingMetric{{Resource: core.ResourceCPU, AverageUtilization: 70}},
+		Triggers: []irtypes.AutoscalingTrigger{{
+			Name:       "queue",
+			Type:       "azure-servicebus",
+			Metadata:   map[string]string{"queueName": "orders"},
+			SecretRefs: []irtypes.AutoscalingSecretRef{{Parameter: "connection", SecretName: "worker-secrets", Key: "queue-connection"}},
+		}},
+	}
+	ir.Services[worker.Name] = worker
+	web := irtypes.NewServiceWithName("web")
+	web.Containers = []core.Container{{Name: "web", Image: "web:latest"}}
+	web.Autoscaling = &irtypes.Autoscaling{MinReplicas: 1, MaxReplicas: 3}
+	ir.Services[web.Name] = web
+	enhancedIR := irtypes.NewEnhancedIRFromIR(ir)
+	supportedKinds := []string{common.DeploymentKind, horizontalPodAutoscalerKind, keda.ScaledObjectKind, keda.TriggerAuthenticationKind}
+
+	objs := (&ScaledObject{}).createNewResources(enhancedIR, supportedKinds)
+	scaledObjects := getObjectsOfKind(objs, keda.ScaledObjectKind)
+	if len(scaledObjects) != 1 || len(getObjectsOfKind(objs, keda.TriggerAuthenticationKind)) != 1 {
+		t.Fatalf("Expected a scaled object and a trigger authentication for the worker. Actual: %+v", objs)
+	}
+	scaledObject := scaledObjects[0].(*keda.ScaledObject)
+	if scaledObject.Name != "worker" || *scaledObject.Spec.MinReplicaCount != 0 || *scaledObject.Spec.MaxReplicaCount != 5 || scaledObject.Spec.ScaleTargetRef.Kind != common.DeploymentKind {
+		t.Fatalf("Failed to set the target and the bounds of the scaled object. Actual: %+v", scaledObject.Spec)
+	}
+	triggers := scaledObject.Spec.Triggers
+	if len(triggers) != 2 || triggers[0].Type != "cpu" || triggers[0].Metadata["value"] != "70" || triggers[1].AuthenticationRef.Name != "worker-queue" {
+		t.Fatalf("Failed to set the triggers of the scaled object. Actual: %+v", triggers)
+	}
+
+	hpas := (&HorizontalPodAutoscaler{}).createNewResources(enhancedIR, supportedKinds)
+	if len(hpas) != 1 || hpas[0].(*autoscaling.HorizontalPodAutoscaler).Name != "web" {
+		t.Fatalf("Expected an autoscaler only for the service without triggers. Actual: %+v", hpas)
+	}
+}
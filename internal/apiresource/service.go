@@ -18,10 +18,13 @@ package apiresource
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/k8sschema"
 	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/gatewayapi"
 	okdroutev1 "github.com/openshift/api/route/v1"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
@@ -34,6 +37,21 @@ import (
 
 const (
 	routeKind = "Route"
+
+	routeCookieNameAnnotation = "router.openshift.io/cookie_name"
+	stickySessionCookieName   = "m2kroute"
+	wildcardTLSSecretName     = "<TODO: fill the tls secret for the wildcard domains>"
+
+	routeTimeoutAnnotation  = "haproxy.router.openshift.io/timeout"
+	webSocketIngressTimeout = 3600 // seconds
+	webSocketRouteTimeout   = "1h"
+	grpcIngressSuffix       = "-grpc"
+	ingressTODOKey          = common.TODOAnnotation + "ingress"
+	// The standard application protocols of the service ports for cleartext HTTP/2 and WebSocket
+	grpcAppProtocol      = "kubernetes.io/h2c"
+	webSocketAppProtocol = "kubernetes.io/ws"
+	// externalServiceSuffix is the suffix of the services which expose the non HTTP ports outside the cluster
+	externalServiceSuffix = "-external"
 )
 
 // Service handles all objects related to a service
@@ -42,18 +60,26 @@ type Service struct {
 
 // getSupportedKinds returns supported kinds
 func (d *Service) getSupportedKinds() []string {
-	return []string{common.ServiceKind, common.IngressKind, routeKind}
+	return []string{common.ServiceKind, common.IngressKind, routeKind, gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind}
 }
 
 // createNewResources converts IR to runtime objects
 func (d *Service) createNewResources(ir irtypes.EnhancedIR, supportedKinds []string) []runtime.Object {
 	objs := []runtime.Object{}
 	ingressEnabled := false
+	gatewayAPIEnabled := ir.IsGatewayAPIEnabled() && common.IsStringPresent(supportedKinds, gatewayapi.GatewayKind) && common.IsStringPresent(supportedKinds, gatewayapi.HTTPRouteKind)
 	for _, service := range ir.Services {
 		exposeobjectcreated := false
 		if service.HasValidAnnotation(common.ExposeSelector) || service.OnlyIngress {
 			// Create services depending on whether the service needs to be externally exposed
-			if common.IsStringPresent(supportedKinds, routeKind) {
+			if gatewayAPIEnabled {
+				//Create HTTPRoute and the gateway if it is not shared
+				if !ir.SharedGateway {
+					objs = append(objs, d.createGateway(service.Name, ir))
+				}
+				objs = append(objs, d.createHTTPRoute(service, ir))
+				exposeobjectcreated = true
+			} else if common.IsStringPresent(supportedKinds, routeKind) {
 				//Create Route
 				routeObjs := d.createRoutes(service, ir)
 				for _, routeObj := range routeObjs {
@@ -74,25 +100,59 @@ func (d *Service) createNewResources(ir irtypes.EnhancedIR, supportedKinds []str
 			}
 			continue
 		}
+		if service.Schedule != "" {
+			// The pods of the cron jobs are not reached through services
+			continue
+		}
 		if !common.IsStringPresent(supportedKinds, common.ServiceKind) {
 			log.Errorf("Could not find a valid resource type in cluster to create a Service")
 			continue
 		}
 		if exposeobjectcreated || !service.HasValidAnnotation(common.ExposeSelector) {
 			//Create clusterip service
-			obj := d.createService(service, core.ServiceTypeClusterIP)
+			obj := d.createService(service, core.ServiceTypeClusterIP, ir)
 			objs = append(objs, obj)
 		} else {
 			//Create Nodeport service - TODO: Should it be load balancer or Nodeport? Should it be QA?
-			obj := d.createService(service, core.ServiceTypeNodePort)
+			obj := d.createService(service, core.ServiceTypeNodePort, ir)
+			objs = append(objs, obj)
+		}
+		if ir.IsRolloutEnabled() && service.IsLongRunning() {
+			//Create the canary or preview service used by the rollout
+			obj := d.createService(service, core.ServiceTypeClusterIP, ir)
+			obj.Name = getRolloutServiceName(service.Name, ir.DeploymentStrategy)
+			objs = append(objs, obj)
+		}
+		if obj := d.createExternalService(service, ir); obj != nil {
 			objs = append(objs, obj)
 		}
 	}
 
-	// Create one ingress for all services
+	// Create one ingress for all services. With the nginx ingress controller the gRPC services get their own ingress,
+	// since the backend protocol is configured for the whole ingress.
 	if ingressEnabled {
-		obj := d.createIngress(ir)
-		objs = append(objs, obj)
+		grpcServices, otherServices := false, false
+		for _, service := range ir.Services {
+			if !service.HasValidAnnotation(common.ExposeSelector) {
+				continue
+			}
+			if hasGRPCIngress(service, ir) {
+				grpcServices = true
+			} else {
+				otherServices = true
+			}
+		}
+		if otherServices || !grpcServices {
+			objs = append(objs, d.createIngress(ir, false))
+		}
+		if grpcServices {
+			objs = append(objs, d.createIngress(ir, true))
+		}
+	}
+
+	// Create one gateway for all services
+	if gatewayAPIEnabled && ir.SharedGateway {
+		objs = append(objs, d.createGateway(ir.Name, ir))
 	}
 
 	return objs
@@ -439,50 +499,100 @@ func (d *Service) createRoute(service irtypes.Service, port core.ServicePort, pa
 			Ingress: ingressArray,
 		},
 	}
+	if service.StickySessions || service.Protocol == irtypes.WebSocketServiceProtocol {
+		route.Annotations = map[string]string{}
+	}
+	if service.StickySessions {
+		route.Annotations[routeCookieNameAnnotation] = service.Name
+	}
+	if service.Protocol == irtypes.WebSocketServiceProtocol {
+		// The idle WebSocket connections are closed by the router after the default timeout of 30s
+		route.Annotations[routeTimeoutAnnotation] = webSocketRouteTimeout
+	}
 	return route
 }
 
-// createIngress creates a single ingress for all services
-//TODO: Only supports fan-out. Virtual named hosting is not supported yet.
-func (d *Service) createIngress(ir irtypes.EnhancedIR) *networking.Ingress {
+// hasGRPCIngress returns true if the service is exposed using the ingress of the gRPC services
+func hasGRPCIngress(service irtypes.Service, ir irtypes.EnhancedIR) bool {
+	return hasGRPCAnnotation(ir.IngressController) && service.Protocol == irtypes.GRPCServiceProtocol
+}
+
+// createIngress creates a single ingress for all services, or for the gRPC services if grpc is true
+//TODO: Only supports fan-out, along with the wildcard hosts of the services. Virtual named hosting is not supported yet.
+func (d *Service) createIngress(ir irtypes.EnhancedIR, grpc bool) *networking.Ingress {
 	pathType := networking.PathTypePrefix
 
 	// Create the fan-out paths
 	httpIngressPaths := []networking.HTTPIngressPath{}
+	wildcardRules := []networking.IngressRule{}
+	wildcardHosts := []string{}
+	stickySessions := false
+	webSockets := false
 	for _, service := range ir.Services {
-		if !service.HasValidAnnotation(common.ExposeSelector) {
+		if !service.HasValidAnnotation(common.ExposeSelector) || hasGRPCIngress(service, ir) != grpc {
 			continue
 		}
+		stickySessions = stickySessions || service.StickySessions
+		webSockets = webSockets || service.Protocol == irtypes.WebSocketServiceProtocol
 		backendServiceName := service.BackendServiceName
 		if service.BackendServiceName == "" {
 			backendServiceName = service.Name
 		}
 		servicePorts := d.getServicePorts(service)
-		pathPrefix := service.ServiceRelPath
-		for _, servicePort := range servicePorts {
-			path := pathPrefix
-			if len(servicePorts) > 1 {
-				// All ports cannot be exposed as /ServiceRelPath because they will clash
-				path = pathPrefix + "/" + servicePort.Name
+		for _, pathPrefix := range append([]string{service.ServiceRelPath}, service.IngressPaths...) {
+			for _, servicePort := range servicePorts {
+				path := pathPrefix
+				if len(servicePorts) > 1 {
+					// All ports cannot be exposed as /ServiceRelPath because they will clash
+					path = pathPrefix + "/" + servicePort.Name
+					if servicePort.Name == "" {
+						path = pathPrefix + "/" + cast.ToString(servicePort.Port)
+					}
+				}
+				backendPort := networking.ServiceBackendPort{Name: servicePort.Name}
 				if servicePort.Name == "" {
-					path = pathPrefix + "/" + cast.ToString(servicePort.Port)
+					backendPort = networking.ServiceBackendPort{Number: servicePort.Port}
+				}
+				httpIngressPath := networking.HTTPIngressPath{
+					Path:     path,
+					PathType: &pathType,
+					Backend: networking.IngressBackend{
+						Service: &networking.IngressServiceBackend{
+							Name: backendServiceName,
+							Port: backendPort,
+						},
+					},
 				}
+				httpIngressPaths = append(httpIngressPaths, httpIngressPath)
 			}
-			backendPort := networking.ServiceBackendPort{Name: servicePort.Name}
-			if servicePort.Name == "" {
-				backendPort = networking.ServiceBackendPort{Number: servicePort.Port}
+		}
+		// The service is served at the root of its wildcard hosts, like it was on the wildcard routes of the source platform
+		for _, host := range service.WildcardHosts {
+			if len(servicePorts) == 0 || common.IsStringPresent(wildcardHosts, host) {
+				continue
+			}
+			backendPort := networking.ServiceBackendPort{Name: servicePorts[0].Name}
+			if servicePorts[0].Name == "" {
+				backendPort = networking.ServiceBackendPort{Number: servicePorts[0].Port}
 			}
-			httpIngressPath := networking.HTTPIngressPath{
-				Path:     path,
-				PathType: &pathType,
-				Backend: networking.IngressBackend{
-					Service: &networking.IngressServiceBackend{
-						Name: backendServiceName,
-						Port: backendPort,
+			wildcardHosts = append(wildcardHosts, host)
+			wildcardRules = append(wildcardRules, networking.IngressRule{
+				Host: host,
+				IngressRuleValue: networking.IngressRuleValue{
+					HTTP: &networking.HTTPIngressRuleValue{
+						Paths: []networking.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networking.IngressBackend{
+								Service: &networking.IngressServiceBackend{
+									Name: backendServiceName,
+									Port: backendPort,
+								},
+							},
+						}},
 					},
 				},
-			}
-			httpIngressPaths = append(httpIngressPaths, httpIngressPath)
+			})
 		}
 	}
 
@@ -497,6 +607,8 @@ func (d *Service) createIngress(ir irtypes.EnhancedIR) *networking.Ingress {
 			},
 		},
 	}
+	sort.Slice(wildcardRules, func(i, j int) bool { return wildcardRules[i].Host < wildcardRules[j].Host })
+	rules = append(rules, wildcardRules...)
 
 	ingressName := ir.Name
 	if len(ir.Services) == 1 {
@@ -504,6 +616,9 @@ func (d *Service) createIngress(ir irtypes.EnhancedIR) *networking.Ingress {
 			ingressName = service.Name
 		}
 	}
+	if grpc {
+		ingressName += grpcIngressSuffix
+	}
 	ingress := networking.Ingress{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       common.IngressKind,
@@ -515,18 +630,136 @@ func (d *Service) createIngress(ir irtypes.EnhancedIR) *networking.Ingress {
 		},
 		Spec: networking.IngressSpec{Rules: rules},
 	}
+	// The annotations are configured for the whole ingress, so they apply to all the services behind it
+	settings := ingressSettings{
+		Timeout:        ir.IngressTimeout,
+		BodySize:       ir.IngressBodySize,
+		StickySessions: stickySessions,
+		TLSRedirect:    ir.IsIngressTLSEnabled(),
+		GRPC:           grpc,
+	}
+	// The idle WebSocket connections must not be closed after the default timeouts of the controllers
+	if webSockets && settings.Timeout < webSocketIngressTimeout {
+		settings.Timeout = webSocketIngressTimeout
+	}
+	pack := getIngressAnnotationPack(ir.IngressController, settings)
+	if len(pack.annotations) > 0 {
+		ingress.Annotations = pack.annotations
+	}
+	if pack.className != "" {
+		className := pack.className
+		ingress.Spec.IngressClassName = &className
+	}
+	if len(pack.unsupported) > 0 {
+		if ingress.Annotations == nil {
+			ingress.Annotations = map[string]string{}
+		}
+		ingress.Annotations[ingressTODOKey] = "Configure manually: " + strings.Join(pack.unsupported, ". ")
+		log.Warnf("The ingress %s has to be configured manually for the %s ingress controller: %s", ingressName, ir.IngressController, strings.Join(pack.unsupported, ". "))
+	}
+	if grpc && !ir.IsIngressTLSEnabled() {
+		log.Warnf("gRPC is usually served by the ingress controllers only over TLS. Configure the TLS secret of the ingress %s", ingressName)
+	}
 	// If TLS enabled, then add the TLS secret name and the host to the ingress.
 	// Otherwise, skip the TLS section.
 	if ir.IsIngressTLSEnabled() {
 		tls := []networking.IngressTLS{{Hosts: []string{ir.TargetClusterSpec.Host}, SecretName: ir.IngressTLSSecretName}}
+		if len(wildcardHosts) > 0 {
+			sort.Strings(wildcardHosts)
+			tls = append(tls, networking.IngressTLS{Hosts: wildcardHosts, SecretName: wildcardTLSSecretName})
+		}
 		ingress.Spec.TLS = tls
 	}
 
 	return &ingress
 }
 
+// createGateway creates a gateway listening for HTTP (and HTTPS if TLS is enabled) on the target cluster host
+func (d *Service) createGateway(name string, ir irtypes.EnhancedIR) *gatewayapi.Gateway {
+	listeners := []gatewayapi.Listener{{Name: "http", Hostname: ir.TargetClusterSpec.Host, Port: 80, Protocol: gatewayapi.HTTPProtocolType}}
+	if ir.IsIngressTLSEnabled() {
+		listeners = append(listeners, gatewayapi.Listener{
+			Name:     "https",
+			Hostname: ir.TargetClusterSpec.Host,
+			Port:     443,
+			Protocol: gatewayapi.HTTPSProtocolType,
+			TLS:      &gatewayapi.GatewayTLSConfig{CertificateRefs: []gatewayapi.SecretObjectReference{{Name: ir.IngressTLSSecretName}}},
+		})
+	}
+	return &gatewayapi.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapi.GatewayKind,
+			APIVersion: getGatewayAPIVersion(gatewayapi.GatewayKind, ir),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getServiceLabels(name),
+		},
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: ir.GatewayClassName,
+			Listeners:        listeners,
+		},
+	}
+}
+
+// getGatewayAPIVersion returns the most preferred version of the Gateway API supported by the target cluster for the kind
+func getGatewayAPIVersion(kind string, ir irtypes.EnhancedIR) string {
+	gv, _ := gatewayapi.GetPreferredGroupVersion(ir.TargetClusterSpec.GetSupportedVersions(kind))
+	return gv.String()
+}
+
+// createHTTPRoute creates a HTTPRoute that attaches the service to its gateway
+func (d *Service) createHTTPRoute(service irtypes.Service, ir irtypes.EnhancedIR) *gatewayapi.HTTPRoute {
+	gatewayName := service.Name
+	if ir.SharedGateway {
+		gatewayName = ir.Name
+	}
+	backendServiceName := service.BackendServiceName
+	if backendServiceName == "" {
+		backendServiceName = service.Name
+	}
+	rules := []gatewayapi.HTTPRouteRule{}
+	servicePorts := d.getServicePorts(service)
+	pathPrefix := service.ServiceRelPath
+	for _, servicePort := range servicePorts {
+		path := pathPrefix
+		if len(servicePorts) > 1 {
+			// All ports cannot be exposed as /ServiceRelPath because they will clash
+			path = pathPrefix + "/" + servicePort.Name
+			if servicePort.Name == "" {
+				path = pathPrefix + "/" + cast.ToString(servicePort.Port)
+			}
+		}
+		if path == "" {
+			path = "/"
+		}
+		rules = append(rules, gatewayapi.HTTPRouteRule{
+			Matches:     []gatewayapi.HTTPRouteMatch{{Path: &gatewayapi.HTTPPathMatch{Type: gatewayapi.PathMatchPathPrefix, Value: path}}},
+			BackendRefs: []gatewayapi.HTTPBackendRef{{Name: backendServiceName, Port: servicePort.Port}},
+		})
+	}
+	route := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapi.HTTPRouteKind,
+			APIVersion: getGatewayAPIVersion(gatewayapi.HTTPRouteKind, ir),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   service.Name,
+			Labels: getServiceLabels(service.Name),
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			ParentRefs: []gatewayapi.ParentReference{{Name: gatewayName}},
+			Rules:      rules,
+		},
+	}
+	if ir.TargetClusterSpec.Host != "" {
+		route.Spec.Hostnames = []string{ir.TargetClusterSpec.Host}
+	}
+	return route
+}
+
 // createService creates a service
-func (d *Service) createService(service irtypes.Service, serviceType core.ServiceType) *core.Service {
+func (d *Service) createService(service irtypes.Service, serviceType core.ServiceType, ir irtypes.EnhancedIR) *core.Service {
 	ports := d.getServicePorts(service)
 	svc := &core.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -547,6 +780,65 @@ func (d *Service) createService(service irtypes.Service, serviceType core.Servic
 	if len(ports) == 0 {
 		svc.Spec.ClusterIP = "None"
 	}
+	if service.StickySessions {
+		svc.Spec.SessionAffinity = core.ServiceAffinityClientIP
+		// Traefik configures the sticky sessions on the services instead of the ingresses
+		if ir.IngressController == irtypes.TraefikIngressController {
+			svc.Annotations[traefikStickyCookieAnnotation] = "true"
+			svc.Annotations[traefikStickyCookieNameAnnotation] = stickySessionCookieName
+		}
+	}
+	appProtocol := ""
+	switch service.Protocol {
+	case irtypes.GRPCServiceProtocol:
+		appProtocol = grpcAppProtocol
+		if ir.IngressController == irtypes.TraefikIngressController {
+			svc.Annotations[traefikServersSchemeAnnotation] = "h2c"
+		}
+	case irtypes.WebSocketServiceProtocol:
+		appProtocol = webSocketAppProtocol
+	}
+	if appProtocol != "" {
+		for i := range svc.Spec.Ports {
+			svc.Spec.Ports[i].AppProtocol = &appProtocol
+		}
+	}
+	for _, nonHTTPPort := range service.NonHTTPPorts {
+		for i := range svc.Spec.Ports {
+			if svc.Spec.Ports[i].Port == nonHTTPPort.Number {
+				svc.Spec.Ports[i].Protocol = nonHTTPPort.Protocol
+				svc.Spec.Ports[i].AppProtocol = nil
+			}
+		}
+	}
+	return svc
+}
+
+// createExternalService creates a service of type LoadBalancer or NodePort which exposes only the non HTTP ports of the service
+func (d *Service) createExternalService(service irtypes.Service, ir irtypes.EnhancedIR) *core.Service {
+	serviceType := core.ServiceTypeLoadBalancer
+	switch service.NonHTTPExposure {
+	case irtypes.LoadBalancerPortExposure:
+	case irtypes.NodePortPortExposure:
+		serviceType = core.ServiceTypeNodePort
+	default:
+		return nil
+	}
+	if len(service.NonHTTPPorts) == 0 {
+		return nil
+	}
+	svc := d.createService(service, serviceType, ir)
+	svc.Name = service.Name + externalServiceSuffix
+	svc.Spec.SessionAffinity = ""
+	ports := []core.ServicePort{}
+	for _, port := range svc.Spec.Ports {
+		for _, nonHTTPPort := range service.NonHTTPPorts {
+			if port.Port == nonHTTPPort.Number {
+				ports = append(ports, port)
+			}
+		}
+	}
+	svc.Spec.Ports = ports
 	return svc
 }
 
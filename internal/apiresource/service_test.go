@@ -0,0 +1,244 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresource
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/gatewayapi"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/kubernetes/pkg/apis/core"
+	networking "k8s.io/kubernetes/pkg/apis/networking"
+)
+
+func getIRWithExposedServices(sharedGateway bool) irtypes.EnhancedIR {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	ir.Name = "myproject"
+	ir.TargetClusterSpec.Host = "myproject.example.com"
+	ir.GatewayClassName = "istio"
+	ir.SharedGateway = sharedGateway
+	for _, name := range []string{"svc1", "svc2"} {
+		svc := irtypes.NewServiceWithName(name)
+		svc.Annotations = map[string]string{common.ExposeSelector: common.AnnotationLabelValue}
+		svc.ServiceRelPath = "/" + name
+		svc.AddPortForwarding(irtypes.Port{Number: 8080}, irtypes.Port{Number: 8080})
+		ir.Services[name] = svc
+	}
+	return irtypes.NewEnhancedIRFromIR(ir)
+}
+
+func getObjectsOfKind(objs []runtime.Object, kind string) []runtime.Object {
+	filtered := []runtime.Object{}
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Kind == kind {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+func TestCreateGatewayAPIResources(t *testing.T) {
+	supportedKinds := []string{common.ServiceKind, common.IngressKind, gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind}
+
+	t.Run("shared gateway", func(t *testing.T) {
+		objs := (&Service{}).createNewResources(getIRWithExposedServices(true), supportedKinds)
+		if ingresses := getObjectsOfKind(objs, common.IngressKind); len(ingresses) != 0 {
+			t.Fatalf("Expected no ingresses. Actual: %+v", ingresses)
+		}
+		gateways := getObjectsOfKind(objs, gatewayapi.GatewayKind)
+		if len(gateways) != 1 {
+			t.Fatalf("Expected 1 shared gateway. Actual: %+v", gateways)
+		}
+		gateway := gateways[0].(*gatewayapi.Gateway)
+		if gateway.Name != "myproject" || gateway.Spec.GatewayClassName != "istio" {
+			t.Fatalf("Failed to create the gateway properly. Actual: %+v", gateway)
+		}
+		routes := getObjectsOfKind(objs, gatewayapi.HTTPRouteKind)
+		if len(routes) != 2 {
+			t.Fatalf("Expected 2 routes. Actual: %+v", routes)
+		}
+		for _, obj := range routes {
+			route := obj.(*gatewayapi.HTTPRoute)
+			if len(route.Spec.ParentRefs) != 1 || route.Spec.ParentRefs[0].Name != "myproject" {
+				t.Fatalf("The route should be attached to the shared gateway. Actual: %+v", route)
+			}
+			if len(route.Spec.Rules) != 1 || route.Spec.Rules[0].Matches[0].Path.Value != "/"+route.Name || route.Spec.Rules[0].BackendRefs[0].Port != 8080 {
+				t.Fatalf("Failed to create the route rules properly. Actual: %+v", route.Spec.Rules)
+			}
+		}
+	})
+
+	t.Run("per service gateways", func(t *testing.T) {
+		objs := (&Service{}).createNewResources(getIRWithExposedServices(false), supportedKinds)
+		if gateways := getObjectsOfKind(objs, gatewayapi.GatewayKind); len(gateways) != 2 {
+			t.Fatalf("Expected 2 gateways. Actual: %+v", gateways)
+		}
+		for _, obj := range getObjectsOfKind(objs, gatewayapi.HTTPRouteKind) {
+			route := obj.(*gatewayapi.HTTPRoute)
+			if route.Spec.ParentRefs[0].Name != route.Name {
+				t.Fatalf("The route should be attached to the gateway of its service. Actual: %+v", route)
+			}
+		}
+	})
+
+	t.Run("cluster serving only v1beta1", func(t *testing.T) {
+		ir := getIRWithExposedServices(true)
+		ir.TargetClusterSpec.APIKindVersionMap = map[string][]string{
+			gatewayapi.GatewayKind:   {"gateway.networking.k8s.io/v1beta1", "gateway.networking.k8s.io/v1alpha2"},
+			gatewayapi.HTTPRouteKind: {"gateway.networking.k8s.io/v1alpha2", "gateway.networking.k8s.io/v1beta1"},
+		}
+		objs := (&Service{}).createNewResources(ir, supportedKinds)
+		gatewayAPIObjs := append(getObjectsOfKind(objs, gatewayapi.GatewayKind), getObjectsOfKind(objs, gatewayapi.HTTPRouteKind)...)
+		if len(gatewayAPIObjs) != 3 {
+			t.Fatalf("Expected 1 gateway and 2 routes. Actual: %+v", gatewayAPIObjs)
+		}
+		for _, obj := range gatewayAPIObjs {
+			if apiVersion := obj.GetObjectKind().GroupVersionKind().GroupVersion().String(); apiVersion != "gateway.networking.k8s.io/v1beta1" {
+				t.Fatalf("Expected the version supported by the cluster. Actual: %s", apiVersion)
+			}
+		}
+	})
+
+	t.Run("gateway api not supported by the cluster", func(t *testing.T) {
+		objs := (&Service{}).createNewResources(getIRWithExposedServices(true), []string{common.ServiceKind, common.IngressKind})
+		if gateways := getObjectsOfKind(objs, gatewayapi.GatewayKind); len(gateways) != 0 {
+			t.Fatalf("Expected no gateways. Actual: %+v", gateways)
+		}
+		if ingresses := getObjectsOfKind(objs, common.IngressKind); len(ingresses) != 1 {
+			t.Fatalf("Expected 1 ingress. Actual: %+v", ingresses)
+		}
+	})
+}
+
+func TestCreateStickySessionResources(t *testing.T) {
+	ir := getIRWithExposedServices(true)
+	ir.GatewayClassName = ""
+	svc := ir.Services["svc1"]
+	svc.StickySessions = true
+	ir.Services["svc1"] = svc
+	objs := (&Service{}).createNewResources(ir, []string{common.ServiceKind, common.IngressKind})
+	for _, obj := range getObjectsOfKind(objs, common.ServiceKind) {
+		service := obj.(*core.Service)
+		wantAffinity := core.ServiceAffinity("")
+		if service.Name == "svc1" {
+			wantAffinity = core.ServiceAffinityClientIP
+		}
+		if service.Spec.SessionAffinity != wantAffinity {
+			t.Fatalf("Expected the session affinity of %s to be %q. Actual: %q", service.Name, wantAffinity, service.Spec.SessionAffinity)
+		}
+	}
+	ingresses := getObjectsOfKind(objs, common.IngressKind)
+	if len(ingresses) != 1 {
+		t.Fatalf("Expected 1 ingress. Actual: %+v", ingresses)
+	}
+	if ingress := ingresses[0].(*networking.Ingress); ingress.Annotations[ingressAffinityAnnotation] != "cookie" {
+		t.Fatalf("Expected the ingress to use cookie affinity. Actual annotations: %+v", ingress.Annotations)
+	}
+}
+
+func TestCreateWildcardIngressRules(t *testing.T) {
+	ir := getIRWithExposedServices(true)
+	ir.GatewayClassName = ""
+	svc := ir.Services["svc2"]
+	svc.WildcardHosts = []string{"*.apps.example.com"}
+	ir.Services["svc2"] = svc
+	objs := (&Service{}).createNewResources(ir, []string{common.ServiceKind, common.IngressKind})
+	ingresses := getObjectsOfKind(objs, common.IngressKind)
+	if len(ingresses) != 1 {
+		t.Fatalf("Expected 1 ingress. Actual: %+v", ingresses)
+	}
+	rules := ingresses[0].(*networking.Ingress).Spec.Rules
+	if len(rules) != 2 || rules[0].Host != "myproject.example.com" || len(rules[0].HTTP.Paths) != 2 {
+		t.Fatalf("Expected the fan-out rule for the cluster host and a rule for the wildcard host. Actual: %+v", rules)
+	}
+	if rules[1].Host != "*.apps.example.com" || len(rules[1].HTTP.Paths) != 1 || rules[1].HTTP.Paths[0].Path != "/" || rules[1].HTTP.Paths[0].Backend.Service.Name != "svc2" {
+		t.Fatalf("Expected the wildcard host to be routed to the root of svc2. Actual: %+v", rules[1])
+	}
+}
+
+func TestCreateProtocolResources(t *testing.T) {
+	ir := getIRWithExposedServices(true)
+	ir.GatewayClassName = ""
+	ir.IngressController = irtypes.NginxIngressController
+	svc := ir.Services["svc1"]
+	svc.Protocol = irtypes.GRPCServiceProtocol
+	ir.Services["svc1"] = svc
+	svc = ir.Services["svc2"]
+	svc.Protocol = irtypes.WebSocketServiceProtocol
+	ir.Services["svc2"] = svc
+	objs := (&Service{}).createNewResources(ir, []string{common.ServiceKind, common.IngressKind})
+	for _, obj := range getObjectsOfKind(objs, common.ServiceKind) {
+		service := obj.(*core.Service)
+		want := grpcAppProtocol
+		if service.Name == "svc2" {
+			want = webSocketAppProtocol
+		}
+		if port := service.Spec.Ports[0]; port.AppProtocol == nil || *port.AppProtocol != want {
+			t.Fatalf("Expected the app protocol of %s to be %s. Actual: %+v", service.Name, want, port)
+		}
+	}
+	ingresses := getObjectsOfKind(objs, common.IngressKind)
+	if len(ingresses) != 2 {
+		t.Fatalf("Expected a separate ingress for the gRPC service. Actual: %+v", ingresses)
+	}
+	for _, obj := range ingresses {
+		ingress := obj.(*networking.Ingress)
+		paths := ingress.Spec.Rules[0].HTTP.Paths
+		if ingress.Name == "myproject"+grpcIngressSuffix {
+			if ingress.Annotations[ingressBackendProtocolAnnotation] != "GRPC" || len(paths) != 1 || paths[0].Backend.Service.Name != "svc1" {
+				t.Fatalf("Expected the gRPC ingress to route to svc1 using the GRPC backend protocol. Actual: %+v", ingress)
+			}
+			continue
+		}
+		if ingress.Annotations[ingressProxyReadTimeoutAnnotation] != "3600" || len(paths) != 1 || paths[0].Backend.Service.Name != "svc2" {
+			t.Fatalf("Expected the ingress to route to svc2 with the WebSocket timeouts. Actual: %+v", ingress)
+		}
+	}
+}
+
+func TestCreateExternalService(t *testing.T) {
+	ir := getIRWithExposedServices(true)
+	ir.GatewayClassName = ""
+	svc := ir.Services["svc1"]
+	delete(svc.Annotations, common.ExposeSelector)
+	svc.AddPortForwarding(irtypes.Port{Number: 5432}, irtypes.Port{Number: 5432})
+	svc.AddPortForwarding(irtypes.Port{Number: 53}, irtypes.Port{Number: 53})
+	svc.NonHTTPPorts = []irtypes.NonHTTPPort{{Number: 5432, Protocol: core.ProtocolTCP, Hint: "PostgreSQL"}, {Number: 53, Protocol: core.ProtocolUDP, Hint: "DNS"}}
+	svc.NonHTTPExposure = irtypes.LoadBalancerPortExposure
+	ir.Services["svc1"] = svc
+	objs := (&Service{}).createNewResources(ir, []string{common.ServiceKind, common.IngressKind})
+	var external *core.Service
+	for _, obj := range getObjectsOfKind(objs, common.ServiceKind) {
+		if service := obj.(*core.Service); service.Name == "svc1"+externalServiceSuffix {
+			external = service
+		}
+	}
+	if external == nil {
+		t.Fatalf("Expected an external service for the non HTTP ports of svc1. Actual: %+v", objs)
+	}
+	if external.Spec.Type != core.ServiceTypeLoadBalancer || len(external.Spec.Ports) != 2 {
+		t.Fatalf("Expected a load balancer service with only the non HTTP ports. Actual: %+v", external.Spec)
+	}
+	for _, port := range external.Spec.Ports {
+		if port.Port == 53 && port.Protocol != core.ProtocolUDP {
+			t.Fatalf("Expected the protocol of the port 53 to be UDP. Actual: %+v", port)
+		}
+	}
+}
@@ -78,6 +78,9 @@ func (s *Storage) createConfigMap(st irtypes.Storage) *core.ConfigMap {
 		},
 		Data: data,
 	}
+	if st.Immutable {
+		configMap.Immutable = &st.Immutable
+	}
 	return configMap
 }
 
@@ -0,0 +1,35 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Takes as input the source directory and returns error if it is not a WebSphere or WebLogic app
+error() {
+    echo "$@" 1>&2
+}
+
+# is_traditional_app succeeds if there are WebSphere or WebLogic deployment descriptors in the source directory or the WAR file
+is_traditional_app() {
+    descriptors='(ibm-(web|application|ejb-jar)-(bnd|ext)\.xm[il]|weblogic(-application|-ejb-jar)?\.xml)$'
+    find "$1" -type f | grep -qE "/$descriptors" && return 0
+    unzip -l "$2" 2>/dev/null | grep -qE "/$descriptors"
+}
+
+main() {
+    [ ! -e "$2" ] && exit 1
+    is_traditional_app "$1" "$2" || exit 1
+    [ "$#" -gt 2 ] && error 'there are multiple WAR files. taking only the first one: '"$2"
+    printf '{"port":8080, "war_path":"%s", "preferred":true}' "$(basename "$2")"
+}
+
+main "$1" "$1/"*.war
@@ -0,0 +1,408 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// cfAPIEnvVar can be used to specify the CF API endpoint instead of reading it from the cf CLI config
+	cfAPIEnvVar = "CF_API"
+	// cfAccessTokenEnvVar can be used to specify the access token instead of reading it from the cf CLI config
+	cfAccessTokenEnvVar = "CF_ACCESS_TOKEN"
+	// cfAutoscalerAPIEnvVar can be used to specify the App Autoscaler API endpoint instead of deriving it from the CF API endpoint
+	cfAutoscalerAPIEnvVar = "CF_AUTOSCALER_API"
+	// cfHomeEnvVar is the environment variable used by the cf CLI to override the location of the .cf directory
+	cfHomeEnvVar    = "CF_HOME"
+	cfAPIPageSize   = 100
+	cfAPIRetries    = 3
+	cfDefaultClient = "cf"
+)
+
+// CfAPIError is returned when the CF API responds with an unsuccessful status code
+type CfAPIError struct {
+	StatusCode int
+	URL        string
+	Errors     []sourcetypes.CfV3Error
+}
+
+func (e *CfAPIError) Error() string {
+	details := []string{}
+	for _, cfErr := range e.Errors {
+		details = append(details, fmt.Sprintf("%s (%d): %s", cfErr.Title, cfErr.Code, cfErr.Detail))
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("the CF API request to %s failed with status code %d", e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("the CF API request to %s failed with status code %d : %s", e.URL, e.StatusCode, strings.Join(details, ", "))
+}
+
+// cfAPIClient talks to the Cloud Foundry v3 API without requiring the cf CLI
+type cfAPIClient struct {
+	config sourcetypes.CfConfig
+	client *http.Client
+	// autoscalerUnavailable is set when the App Autoscaler API cannot be reached, to avoid retrying it for every app
+	autoscalerUnavailable bool
+}
+
+// newCfAPIClient creates a client using the environment variables or the cf CLI config file for authentication
+func newCfAPIClient() (*cfAPIClient, error) {
+	config := sourcetypes.CfConfig{}
+	configPath, err := getCfConfigPath()
+	if err != nil {
+		log.Debugf("Unable to find the cf config file. Error: %q", err)
+	} else if err := common.ReadJSON(configPath, &config); err != nil {
+		log.Debugf("Unable to read the cf config file at path %s . Error: %q", configPath, err)
+	}
+	if api := os.Getenv(cfAPIEnvVar); api != "" {
+		config.Target = api
+	}
+	if token := os.Getenv(cfAccessTokenEnvVar); token != "" {
+		config.AccessToken = token
+	}
+	if config.Target == "" || config.AccessToken == "" {
+		return nil, fmt.Errorf("no CF API endpoint or access token found. Either login using the cf CLI or set the %s and %s environment variables", cfAPIEnvVar, cfAccessTokenEnvVar)
+	}
+	return newCfAPIClientFromConfig(config), nil
+}
+
+func newCfAPIClientFromConfig(config sourcetypes.CfConfig) *cfAPIClient {
+	config.Target = strings.TrimSuffix(config.Target, "/")
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.SSLDisabled {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	client := &http.Client{Transport: transport}
+	if common.CommandTimeout > 0 {
+		client.Timeout = common.CommandTimeout
+	}
+	return &cfAPIClient{config: config, client: client}
+}
+
+func getCfConfigPath() (string, error) {
+	cfHome := os.Getenv(cfHomeEnvVar)
+	if cfHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		cfHome = homeDir
+	}
+	configPath := filepath.Join(cfHome, ".cf", "config.json")
+	if _, err := os.Stat(configPath); err != nil {
+		return "", err
+	}
+	return configPath, nil
+}
+
+// get fetches a single resource. The path can be relative to the API endpoint or an absolute URL.
+func (c *cfAPIClient) get(path string, out interface{}) error {
+	reqURL := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		reqURL = c.config.Target + path
+	}
+	body, err := c.getWithRetries(reqURL)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse the response from %s . Error: %q", reqURL, err)
+	}
+	return nil
+}
+
+// list fetches all the pages of a list endpoint and returns the resources
+func (c *cfAPIClient) list(path string) ([]json.RawMessage, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	next := fmt.Sprintf("%s%sper_page=%d", path, separator, cfAPIPageSize)
+	resources := []json.RawMessage{}
+	for next != "" {
+		page := struct {
+			Pagination sourcetypes.CfV3Pagination `json:"pagination"`
+			Resources  []json.RawMessage          `json:"resources"`
+		}{}
+		if err := c.get(next, &page); err != nil {
+			return resources, err
+		}
+		resources = append(resources, page.Resources...)
+		next = ""
+		if page.Pagination.Next != nil {
+			next = page.Pagination.Next.Href
+		}
+	}
+	return resources, nil
+}
+
+func (c *cfAPIClient) getWithRetries(reqURL string) ([]byte, error) {
+	var lastErr error
+	refreshed := false
+	for attempt := 0; attempt <= cfAPIRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(attempt) * time.Second
+			log.Debugf("Retrying the CF API request to %s in %s . Previous error: %q", reqURL, backoff, lastErr)
+			time.Sleep(backoff)
+		}
+		body, statusCode, err := c.doGet(reqURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if statusCode >= 200 && statusCode < 300 {
+			return body, nil
+		}
+		apiErr := &CfAPIError{StatusCode: statusCode, URL: reqURL}
+		cfErrs := sourcetypes.CfV3Errors{}
+		if err := json.Unmarshal(body, &cfErrs); err == nil {
+			apiErr.Errors = cfErrs.Errors
+		}
+		lastErr = apiErr
+		if statusCode == http.StatusUnauthorized && !refreshed {
+			refreshed = true
+			if err := c.refreshToken(); err != nil {
+				log.Debugf("Failed to refresh the CF access token. Error: %q", err)
+				return nil, apiErr
+			}
+			attempt--
+			continue
+		}
+		if statusCode != http.StatusTooManyRequests && statusCode < 500 {
+			return nil, apiErr
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *cfAPIClient) doGet(reqURL string) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	token := c.config.AccessToken
+	if !strings.HasPrefix(strings.ToLower(token), "bearer ") {
+		token = "bearer " + token
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
+
+// refreshToken gets a new access token from UAA using the refresh token
+func (c *cfAPIClient) refreshToken() error {
+	if c.config.RefreshToken == "" || c.config.UaaEndpoint == "" {
+		return fmt.Errorf("no refresh token or UAA endpoint available")
+	}
+	clientID := c.config.UAAOAuthClient
+	if clientID == "" {
+		clientID = cfDefaultClient
+	}
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", c.config.RefreshToken)
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(c.config.UaaEndpoint, "/")+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(clientID, c.config.UAAOAuthClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the token refresh failed with status code %d", resp.StatusCode)
+	}
+	tokens := struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return err
+	}
+	c.config.AccessToken = tokens.TokenType + " " + tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		c.config.RefreshToken = tokens.RefreshToken
+	}
+	return nil
+}
+
+// getApps returns all the apps visible to the user
+func (c *cfAPIClient) getApps() ([]sourcetypes.CfV3App, error) {
+	apps := []sourcetypes.CfV3App{}
+	resources, err := c.list("/v3/apps")
+	if err != nil {
+		return apps, err
+	}
+	for _, resource := range resources {
+		app := sourcetypes.CfV3App{}
+		if err := json.Unmarshal(resource, &app); err != nil {
+			log.Warnf("Failed to parse the CF app %s . Error: %q", string(resource), err)
+			continue
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// getProcesses returns the processes of an app
+func (c *cfAPIClient) getProcesses(appGUID string) ([]sourcetypes.CfV3Process, error) {
+	processes := []sourcetypes.CfV3Process{}
+	resources, err := c.list("/v3/apps/" + appGUID + "/processes")
+	if err != nil {
+		return processes, err
+	}
+	for _, resource := range resources {
+		process := sourcetypes.CfV3Process{}
+		if err := json.Unmarshal(resource, &process); err != nil {
+			log.Warnf("Failed to parse the CF process %s . Error: %q", string(resource), err)
+			continue
+		}
+		processes = append(processes, process)
+	}
+	return processes, nil
+}
+
+// getRoutes returns the routes mapped to an app
+func (c *cfAPIClient) getRoutes(appGUID string) ([]sourcetypes.CfV3Route, error) {
+	routes := []sourcetypes.CfV3Route{}
+	resources, err := c.list("/v3/apps/" + appGUID + "/routes")
+	if err != nil {
+		return routes, err
+	}
+	for _, resource := range resources {
+		route := sourcetypes.CfV3Route{}
+		if err := json.Unmarshal(resource, &route); err != nil {
+			log.Warnf("Failed to parse the CF route %s . Error: %q", string(resource), err)
+			continue
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// getRouteServiceURLs returns the URLs of the route services bound to a route
+func (c *cfAPIClient) getRouteServiceURLs(routeGUID string) ([]string, error) {
+	urls := []string{}
+	resources, err := c.list("/v3/service_route_bindings?route_guids=" + url.QueryEscape(routeGUID))
+	if err != nil {
+		return urls, err
+	}
+	for _, resource := range resources {
+		binding := sourcetypes.CfV3ServiceRouteBinding{}
+		if err := json.Unmarshal(resource, &binding); err != nil {
+			log.Warnf("Failed to parse the CF service route binding %s . Error: %q", string(resource), err)
+			continue
+		}
+		if binding.RouteServiceURL != "" {
+			urls = append(urls, binding.RouteServiceURL)
+		}
+	}
+	return urls, nil
+}
+
+// getAutoscalerPolicy returns the scaling policy of an app, or nil if the app has no policy
+func (c *cfAPIClient) getAutoscalerPolicy(appGUID string) (*sourcetypes.CfAutoscalerPolicy, error) {
+	if c.autoscalerUnavailable {
+		return nil, nil
+	}
+	endpoint := c.getAutoscalerEndpoint()
+	if endpoint == "" {
+		c.autoscalerUnavailable = true
+		return nil, fmt.Errorf("unable to find the App Autoscaler API endpoint. Set the %s environment variable", cfAutoscalerAPIEnvVar)
+	}
+	policy := sourcetypes.CfAutoscalerPolicy{}
+	if err := c.get(endpoint+"/v1/apps/"+appGUID+"/policy", &policy); err != nil {
+		var apiErr *CfAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		c.autoscalerUnavailable = true
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// getAutoscalerEndpoint returns the App Autoscaler API endpoint, which is deployed next to the CF API as autoscaler.<system domain>
+func (c *cfAPIClient) getAutoscalerEndpoint() string {
+	if endpoint := os.Getenv(cfAutoscalerAPIEnvVar); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	targetURL, err := url.Parse(c.config.Target)
+	if err != nil || !strings.HasPrefix(targetURL.Host, "api.") {
+		return ""
+	}
+	targetURL.Host = "autoscaler." + strings.TrimPrefix(targetURL.Host, "api.")
+	return targetURL.String()
+}
+
+// getCurrentDroplet returns the current droplet of an app
+func (c *cfAPIClient) getCurrentDroplet(appGUID string) (sourcetypes.CfV3Droplet, error) {
+	droplet := sourcetypes.CfV3Droplet{}
+	err := c.get("/v3/apps/"+appGUID+"/droplets/current", &droplet)
+	return droplet, err
+}
+
+// getEnvironmentVariables returns the user provided environment variables of an app
+func (c *cfAPIClient) getEnvironmentVariables(appGUID string) (map[string]string, error) {
+	envs := sourcetypes.CfV3EnvironmentVariables{}
+	err := c.get("/v3/apps/"+appGUID+"/environment_variables", &envs)
+	return envs.Var, err
+}
+
+// getBuildpacks returns all the buildpacks installed in the CF instance
+func (c *cfAPIClient) getBuildpacks() ([]sourcetypes.CfV3Buildpack, error) {
+	buildpacks := []sourcetypes.CfV3Buildpack{}
+	resources, err := c.list("/v3/buildpacks")
+	if err != nil {
+		return buildpacks, err
+	}
+	for _, resource := range resources {
+		buildpack := sourcetypes.CfV3Buildpack{}
+		if err := json.Unmarshal(resource, &buildpack); err != nil {
+			log.Warnf("Failed to parse the CF buildpack %s . Error: %q", string(resource), err)
+			continue
+		}
+		buildpacks = append(buildpacks, buildpack)
+	}
+	return buildpacks, nil
+}
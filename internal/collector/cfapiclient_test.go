@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
+)
+
+func TestCfAPIClient(t *testing.T) {
+	t.Run("list follows the pagination links", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "bearer token1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(w, `{"pagination":{"next":null},"resources":[{"guid":"2","name":"app2"}]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"pagination":{"next":{"href":"%s/v3/apps?page=2"}},"resources":[{"guid":"1","name":"app1","lifecycle":{"type":"buildpack","data":{"buildpacks":["java_buildpack"]}}}]}`, server.URL)
+		}))
+		defer server.Close()
+		client := newCfAPIClientFromConfig(sourcetypes.CfConfig{Target: server.URL, AccessToken: "bearer token1"})
+		apps, err := client.getApps()
+		if err != nil {
+			t.Fatalf("Failed to get the apps. Error: %q", err)
+		}
+		if len(apps) != 2 || apps[0].Name != "app1" || apps[1].Name != "app2" {
+			t.Fatalf("Failed to get all the pages. Actual: %+v", apps)
+		}
+		if len(apps[0].Lifecycle.Data.Buildpacks) != 1 || apps[0].Lifecycle.Data.Buildpacks[0] != "java_buildpack" {
+			t.Fatalf("Failed to parse the lifecycle. Actual: %+v", apps[0])
+		}
+	})
+
+	t.Run("token is refreshed on unauthorized", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/oauth/token" {
+				if err := r.ParseForm(); err != nil || r.Form.Get("refresh_token") != "refresh1" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"access_token":"token2","refresh_token":"refresh2","token_type":"bearer"}`)
+				return
+			}
+			if r.Header.Get("Authorization") != "bearer token2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"errors":[{"code":1000,"title":"CF-InvalidAuthToken","detail":"Invalid Auth Token"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"pagination":{"next":null},"resources":[{"name":"java_buildpack"}]}`)
+		}))
+		defer server.Close()
+		client := newCfAPIClientFromConfig(sourcetypes.CfConfig{Target: server.URL, AccessToken: "bearer expired", RefreshToken: "refresh1", UaaEndpoint: server.URL})
+		buildpacks, err := client.getBuildpacks()
+		if err != nil {
+			t.Fatalf("Failed to get the buildpacks. Error: %q", err)
+		}
+		if len(buildpacks) != 1 || buildpacks[0].Name != "java_buildpack" {
+			t.Fatalf("Failed to get the buildpacks. Actual: %+v", buildpacks)
+		}
+	})
+
+	t.Run("autoscaler policy", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/apps/1/policy" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"instance_min_count":1,"instance_max_count":4,"scaling_rules":[{"metric_type":"cpu","threshold":80,"operator":">=","adjustment":"+1"}]}`)
+		}))
+		defer server.Close()
+		os.Setenv(cfAutoscalerAPIEnvVar, server.URL)
+		defer os.Unsetenv(cfAutoscalerAPIEnvVar)
+		client := newCfAPIClientFromConfig(sourcetypes.CfConfig{Target: "https://api.example.com", AccessToken: "token1"})
+		policy, err := client.getAutoscalerPolicy("1")
+		if err != nil || policy == nil {
+			t.Fatalf("Failed to get the policy. Error: %q", err)
+		}
+		if policy.InstanceMaxCount != 4 || len(policy.ScalingRules) != 1 || policy.ScalingRules[0].MetricType != "cpu" {
+			t.Fatalf("Failed to parse the policy. Actual: %+v", policy)
+		}
+		if policy, err := client.getAutoscalerPolicy("2"); err != nil || policy != nil {
+			t.Fatalf("Expected no policy for an app without a policy. Actual: %+v Error: %q", policy, err)
+		}
+	})
+
+	t.Run("errors from the api are structured", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":10010,"title":"CF-ResourceNotFound","detail":"App not found"}]}`)
+		}))
+		defer server.Close()
+		client := newCfAPIClientFromConfig(sourcetypes.CfConfig{Target: server.URL, AccessToken: "token1"})
+		_, err := client.getCurrentDroplet("1")
+		var apiErr *CfAPIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected a CfAPIError. Actual: %T %q", err, err)
+		}
+		if apiErr.StatusCode != http.StatusNotFound || len(apiErr.Errors) != 1 || apiErr.Errors[0].Code != 10010 {
+			t.Fatalf("Failed to parse the error. Actual: %+v", apiErr)
+		}
+	})
+}
@@ -17,9 +17,7 @@ limitations under the License.
 package collector
 
 import (
-	"encoding/json"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
@@ -38,21 +36,17 @@ func (c *CfAppsCollector) GetAnnotations() []string {
 	return annotations
 }
 
-//Collect gets the cf app metadata by querying the cf app. Assumes that the authentication with cluster is already done.
+//Collect gets the cf app metadata by querying the CF API. Assumes that the authentication with the CF API is already done.
 func (c *CfAppsCollector) Collect(inputPath string, outputPath string) error {
-
-	//To run: cf curl /v2/apps/
-	cmd := exec.Command("cf", "curl", "/v2/apps")
-	output, err := cmd.Output()
+	client, err := newCfAPIClient()
 	if err != nil {
-		log.Errorf("%s", err.Error())
-		return err
+		codedErr := common.NewError(common.CFAuthMissingErrorCode, err, "Unable to create the CF API client.")
+		log.Error(codedErr.Details())
+		return codedErr
 	}
-	log.Debugf("Cf Curl output %s", output)
-	sourcecfinstanceapps := sourcetypes.CfInstanceApps{}
-	err = json.Unmarshal([]byte(output), &sourcecfinstanceapps)
+	apps, err := client.getApps()
 	if err != nil {
-		log.Errorf("Error in unmarshalling yaml: %s. Skipping.", err)
+		log.Errorf("Unable to get the apps from the CF API : %s", err)
 		return err
 	}
 	outputPath = filepath.Join(outputPath, "cf")
@@ -64,33 +58,16 @@ func (c *CfAppsCollector) Collect(inputPath string, outputPath string) error {
 	cfinstanceapps.Spec.CfApplications = []collecttypes.CfApplication{}
 	fileName := "instanceapps_"
 
-	log.Debugf("Detected %d apps", len(sourcecfinstanceapps.CfResources))
-	for _, sourcecfapp := range sourcecfinstanceapps.CfResources {
-		app := collecttypes.CfApplication{}
-		app.Name = sourcecfapp.CfAppEntity.Name
-		log.Debugf("Reading info about %s", app.Name)
-
-		if sourcecfapp.CfAppEntity.Buildpack != "null" {
-			app.Buildpack = sourcecfapp.CfAppEntity.Buildpack
-		}
-		if sourcecfapp.CfAppEntity.DetectedBuildpack != "null" {
-			app.DetectedBuildpack = sourcecfapp.CfAppEntity.DetectedBuildpack
-		}
-		if sourcecfapp.CfAppEntity.DockerImage != "null" {
-			app.DockerImage = sourcecfapp.CfAppEntity.DockerImage
-		}
-		app.Instances = sourcecfapp.CfAppEntity.Instances
-		app.Memory = sourcecfapp.CfAppEntity.Memory
-		app.Env = sourcecfapp.CfAppEntity.Env
-		app.Ports = sourcecfapp.CfAppEntity.Ports
-		cfinstanceapps.Spec.CfApplications = append(cfinstanceapps.Spec.CfApplications, app)
-
-		fileName = fileName + app.Name
+	log.Debugf("Detected %d apps", len(apps))
+	for _, sourcecfapp := range apps {
+		log.Debugf("Reading info about %s", sourcecfapp.Name)
+		cfinstanceapps.Spec.CfApplications = append(cfinstanceapps.Spec.CfApplications, getCfApplication(client, sourcecfapp))
+		fileName = fileName + sourcecfapp.Name
 	}
 
 	if fileName != "" {
-		outputPath = filepath.Join(outputPath, common.NormalizeForFilename(fileName)+".yaml")
-		err = common.WriteYaml(outputPath, cfinstanceapps)
+		outputPath = filepath.Join(outputPath, common.NormalizeForFilename(fileName)+common.GetOutputFormatExt(OutputFormat))
+		err = common.WriteYamlOrJSON(outputPath, cfinstanceapps, OutputFormat)
 		if err != nil {
 			log.Errorf("Unable to write collect output : %s", err)
 		}
@@ -99,3 +76,92 @@ func (c *CfAppsCollector) Collect(inputPath string, outputPath string) error {
 
 	return nil
 }
+
+func getCfApplication(client *cfAPIClient, sourcecfapp sourcetypes.CfV3App) collecttypes.CfApplication {
+	app := collecttypes.CfApplication{Name: sourcecfapp.Name}
+	if len(sourcecfapp.Lifecycle.Data.Buildpacks) > 0 {
+		app.Buildpack = sourcecfapp.Lifecycle.Data.Buildpacks[0]
+	}
+	if droplet, err := client.getCurrentDroplet(sourcecfapp.GUID); err != nil {
+		log.Debugf("Unable to get the current droplet of the app %s : %s", sourcecfapp.Name, err)
+	} else {
+		if sourcecfapp.Lifecycle.Type == "docker" {
+			app.DockerImage = droplet.Image
+		}
+		if len(droplet.Buildpacks) > 0 {
+			app.DetectedBuildpack = droplet.Buildpacks[0].DetectOutput
+			if app.DetectedBuildpack == "" {
+				app.DetectedBuildpack = droplet.Buildpacks[0].BuildpackName
+			}
+		}
+	}
+	if processes, err := client.getProcesses(sourcecfapp.GUID); err != nil {
+		log.Warnf("Unable to get the processes of the app %s : %s", sourcecfapp.Name, err)
+	} else {
+		for _, process := range processes {
+			if process.Type == "web" || len(processes) == 1 {
+				app.Instances = process.Instances
+				app.Memory = process.MemoryInMB
+				break
+			}
+		}
+	}
+	if envs, err := client.getEnvironmentVariables(sourcecfapp.GUID); err != nil {
+		log.Warnf("Unable to get the environment variables of the app %s : %s", sourcecfapp.Name, err)
+	} else if len(envs) > 0 {
+		app.Env = envs
+	}
+	if routes, err := client.getRoutes(sourcecfapp.GUID); err != nil {
+		log.Warnf("Unable to get the routes of the app %s : %s", sourcecfapp.Name, err)
+	} else {
+		for _, route := range routes {
+			if route.URL != "" {
+				app.Routes = append(app.Routes, route.URL)
+			}
+			if routeServiceURLs, err := client.getRouteServiceURLs(route.GUID); err != nil {
+				log.Warnf("Unable to get the route services of the route %s : %s", route.URL, err)
+			} else {
+				app.RouteServices = append(app.RouteServices, routeServiceURLs...)
+			}
+			for _, destination := range route.Destinations {
+				if destination.App.GUID != sourcecfapp.GUID || destination.Port == 0 {
+					continue
+				}
+				if !isInt32Present(app.Ports, destination.Port) {
+					app.Ports = append(app.Ports, destination.Port)
+				}
+			}
+		}
+	}
+	if policy, err := client.getAutoscalerPolicy(sourcecfapp.GUID); err != nil {
+		log.Debugf("Unable to get the autoscaling policy of the app %s : %s", sourcecfapp.Name, err)
+	} else if policy != nil {
+		app.Autoscaling = getCfAutoscaling(*policy)
+	}
+	return app
+}
+
+func getCfAutoscaling(policy sourcetypes.CfAutoscalerPolicy) *collecttypes.CfAutoscaling {
+	autoscaling := &collecttypes.CfAutoscaling{InstanceMinCount: policy.InstanceMinCount, InstanceMaxCount: policy.InstanceMaxCount}
+	for _, rule := range policy.ScalingRules {
+		autoscaling.ScalingRules = append(autoscaling.ScalingRules, collecttypes.CfScalingRule{
+			MetricType: rule.MetricType,
+			Threshold:  rule.Threshold,
+			Operator:   rule.Operator,
+			Adjustment: rule.Adjustment,
+		})
+	}
+	if policy.Schedules != nil {
+		autoscaling.Scheduled = len(policy.Schedules.RecurringSchedule) > 0 || len(policy.Schedules.SpecificDate) > 0
+	}
+	return autoscaling
+}
+
+func isInt32Present(list []int32, value int32) bool {
+	for _, val := range list {
+		if val == value {
+			return true
+		}
+	}
+	return false
+}
@@ -17,14 +17,10 @@ limitations under the License.
 package collector
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
-	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
 	"github.com/konveyor/move2kube/internal/common"
 	containerize "github.com/konveyor/move2kube/internal/containerizer"
 	source "github.com/konveyor/move2kube/internal/source"
@@ -68,8 +64,8 @@ func (c *CFContainerTypesCollector) Collect(inputDirectory string, outputPath st
 		fileName = fileName + buildpackName
 	}
 	if fileName != "" {
-		outputPath = filepath.Join(outputPath, common.NormalizeForFilename(fileName)+".yaml")
-		err := common.WriteYaml(outputPath, cfcontainerizers)
+		outputPath = filepath.Join(outputPath, common.NormalizeForFilename(fileName)+common.GetOutputFormatExt(OutputFormat))
+		err := common.WriteYamlOrJSON(outputPath, cfcontainerizers, OutputFormat)
 		if err != nil {
 			log.Errorf("Unable to write cf container type output %s : %s", fileName, err)
 		}
@@ -136,51 +132,45 @@ func getCfbuildpackNames(inputPath string) []string {
 }
 
 func getAllCfInstanceBuildpacks() ([]string, error) {
-	var buildpacks []string
-	cmd := exec.Command("cf", "buildpacks")
-	outputStr, err := cmd.Output()
+	client, err := newCfAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	cfbuildpacks, err := client.getBuildpacks()
 	if err != nil {
 		log.Warnf("Error while getting buildpacks : %s", err)
 		return nil, err
 	}
-	lines := strings.Split(string(outputStr), "\n")
-	for _, line := range lines {
-		if line == "Getting buildpacks..." {
-			continue
-		}
-		buildpackmatches := strings.Fields(string(line))
-		if len(buildpackmatches) == 0 || buildpackmatches[0] == "buildpack" {
-			continue
-		}
-		buildpacks = append(buildpacks, buildpackmatches[0])
-
+	buildpacks := []string{}
+	for _, cfbuildpack := range cfbuildpacks {
+		buildpacks = append(buildpacks, cfbuildpack.Name)
 	}
 	return buildpacks, nil
 }
 
 func getAllCfAppBuildpacks() ([]string, error) {
-	var buildpacks []string
-	cmd := exec.Command("cf", "curl", "/v2/apps")
-	output, err := cmd.Output()
+	client, err := newCfAPIClient()
 	if err != nil {
-		log.Errorf("%s", err.Error())
 		return nil, err
 	}
-	log.Debugf("Cf Curl output %s", output)
-	sourcecfinstanceapps := sourcetypes.CfInstanceApps{}
-	err = json.Unmarshal([]byte(output), &sourcecfinstanceapps)
+	apps, err := client.getApps()
 	if err != nil {
-		log.Errorf("Error in unmarshalling yaml: %s. Skipping", err)
+		log.Errorf("Unable to get the apps from the CF API : %s", err)
 		return nil, err
 	}
-
-	log.Debugf("Detected %d apps", len(sourcecfinstanceapps.CfResources))
-	for _, sourcecfapp := range sourcecfinstanceapps.CfResources {
-		if sourcecfapp.CfAppEntity.Buildpack != "" {
-			buildpacks = append(buildpacks, sourcecfapp.CfAppEntity.Buildpack)
+	log.Debugf("Detected %d apps", len(apps))
+	var buildpacks []string
+	for _, app := range apps {
+		buildpacks = append(buildpacks, app.Lifecycle.Data.Buildpacks...)
+		droplet, err := client.getCurrentDroplet(app.GUID)
+		if err != nil {
+			log.Debugf("Unable to get the current droplet of the app %s : %s", app.Name, err)
+			continue
 		}
-		if sourcecfapp.CfAppEntity.DetectedBuildpack != "" {
-			buildpacks = append(buildpacks, sourcecfapp.CfAppEntity.DetectedBuildpack)
+		for _, bp := range droplet.Buildpacks {
+			if bp.DetectOutput != "" {
+				buildpacks = append(buildpacks, bp.DetectOutput)
+			}
 		}
 	}
 	return buildpacks, nil
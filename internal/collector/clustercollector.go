@@ -17,31 +17,69 @@ limitations under the License.
 package collector
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
-	"runtime/debug"
 	"sort"
 	"strings"
+	"sync"
 
 	semver "github.com/Masterminds/semver/v3"
 	"github.com/konveyor/move2kube/internal/common"
-	"github.com/konveyor/move2kube/internal/k8sschema"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	log "github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
 	cgdiscovery "k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // See issue https://github.com/kubernetes/client-go/issues/345
+	"k8s.io/client-go/rest"
 	cgclientcmd "k8s.io/client-go/tools/clientcmd"
 )
 
+const (
+	// DefaultClusterQPS is the default maximum queries per second to the cluster API server
+	DefaultClusterQPS float32 = 50
+	// DefaultClusterBurst is the default maximum burst of queries to the cluster API server
+	DefaultClusterBurst = 100
+	// openshiftRouteGroup is the group that is only served by OpenShift clusters
+	openshiftRouteGroup = "route.openshift.io"
+	// maxConcurrentDiscoveryRequests is the maximum number of group versions whose resources are fetched in parallel
+	maxConcurrentDiscoveryRequests = 10
+	mebibyte                       = 1024 * 1024
+)
+
+// ClusterCollectorOptions contains the options used to connect to the cluster
+type ClusterCollectorOptions struct {
+	// Kubeconfig is the path to the kubeconfig file. Uses the default loading rules if empty.
+	Kubeconfig string
+	// Context is the kubeconfig context to use. Uses the current context if empty.
+	Context string
+	// Impersonate is the user to impersonate
+	Impersonate string
+	// ImpersonateGroups are the groups to impersonate
+	ImpersonateGroups []string
+	// QPS is the maximum queries per second to the API server
+	QPS float32
+	// Burst is the maximum burst of queries to the API server
+	Burst int
+	// WorkloadUsage collects the cpu and memory used by the pods of the workloads from the metrics API
+	WorkloadUsage bool
+}
+
+// ClusterOptions are the options used by the ClusterCollector to connect to the cluster
+var ClusterOptions = ClusterCollectorOptions{QPS: DefaultClusterQPS, Burst: DefaultClusterBurst}
+
 //ClusterCollector Implements Collector interface
 type ClusterCollector struct {
-	clusterCmd string
 }
 
 // GetAnnotations returns annotations on which this collector should be invoked
@@ -58,152 +96,254 @@ func (c *ClusterCollector) Collect(inputPath string, outputPath string) error {
 		log.Errorf("Unable to create output directory at path %q Error: %q", outputPath, err)
 		return err
 	}
-	cmd := c.getClusterCommand()
-	if cmd == "" {
-		errStr := "No kubectl or oc in path. Add kubectl to path and rerun to collect data about the cluster in context."
-		log.Warnf(errStr)
-		return fmt.Errorf(errStr)
-	}
-	name, err := c.getClusterContextName()
+	name, cfg, err := c.getClusterConfig(ClusterOptions)
 	if err != nil {
-		log.Warnf("Unable to access cluster in context : %s", err)
-		return err
+		codedErr := common.NewError(common.ClusterAccessFailedErrorCode, err, "Unable to access the cluster in context.")
+		log.Warn(codedErr.Details())
+		return codedErr
 	}
 	clusterMd := collecttypes.NewClusterMetadata(name)
-	if clusterMd.Spec.StorageClasses, err = c.getStorageClasses(); err != nil {
+	if clusterMd.Spec.StorageClasses, err = c.getStorageClasses(cfg); err != nil {
 		//If no storage classes, this will be an empty array
 		clusterMd.Spec.StorageClasses = []string{}
 	}
 
-	clusterMd.Spec.APIKindVersionMap, err = c.collectUsingAPI()
+	clusterMd.Spec.APIKindVersionMap, err = c.collectUsingAPI(cfg)
 	if err != nil {
-		log.Warnf("Failed to collect using the API. Error: %q . Falling back to using the CLI.", err)
-		clusterMd.Spec.APIKindVersionMap, err = c.collectUsingCLI()
-		if err != nil {
-			log.Warnf("Failed to collect using the CLI. Error: %q", err)
-			return err
+		if errDesc := c.interpretError(err); errDesc != "" {
+			log.Warnf("Failed to collect using the API. %s", errDesc)
+		} else {
+			log.Warnf("Failed to collect using the API. Error: %q", err)
 		}
+		return err
 	}
 
 	c.groupOrderPolicy(&clusterMd.Spec.APIKindVersionMap)
+	c.detectFlavor(cfg, &clusterMd.Spec)
+	if ClusterOptions.WorkloadUsage {
+		clusterMd.Spec.WorkloadUsage = c.getWorkloadUsage(cfg)
+	}
 	//c.VersionOrderPolicy(&clusterMd.APIKindVersionMap)
 
-	outputPath = filepath.Join(outputPath, common.NormalizeForFilename(clusterMd.Name)+".yaml")
-	return common.WriteYaml(outputPath, clusterMd)
+	outputPath = filepath.Join(outputPath, common.NormalizeForFilename(clusterMd.Name)+common.GetOutputFormatExt(OutputFormat))
+	return common.WriteYamlOrJSON(outputPath, clusterMd, OutputFormat)
 }
 
-func (c *ClusterCollector) getClusterCommand() string {
-	if c.clusterCmd != "" {
-		return c.clusterCmd
+// getClusterConfig returns the name of the selected context and the client config for it
+func (c *ClusterCollector) getClusterConfig(opts ClusterCollectorOptions) (string, *rest.Config, error) {
+	rules := cgclientcmd.NewDefaultClientConfigLoadingRules()
+	if opts.Kubeconfig != "" {
+		rules.ExplicitPath = opts.Kubeconfig
+	}
+	overrides := &cgclientcmd.ConfigOverrides{CurrentContext: opts.Context}
+	overrides.AuthInfo.Impersonate = opts.Impersonate
+	overrides.AuthInfo.ImpersonateGroups = opts.ImpersonateGroups
+	clientConfig := cgclientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		log.Warnf("Failed to load the kubeconfig. Error: %q", err)
+		return "", nil, err
 	}
-
-	cmd := "kubectl"
-	_, err := exec.LookPath(cmd)
-	if err == nil {
-		c.clusterCmd = cmd
-		return c.clusterCmd
+	name := rawConfig.CurrentContext
+	if opts.Context != "" {
+		name = opts.Context
 	}
-	log.Warnf("Unable to find the %s command. Error: %q", cmd, err)
-
-	cmd = "oc"
-	_, err = exec.LookPath(cmd)
-	if err == nil {
-		c.clusterCmd = cmd
-		return c.clusterCmd
+	if _, ok := rawConfig.Contexts[name]; !ok {
+		contexts := []string{}
+		for context := range rawConfig.Contexts {
+			contexts = append(contexts, context)
+		}
+		sort.Strings(contexts)
+		return "", nil, fmt.Errorf("the context %q does not exist in the kubeconfig. Available contexts: %v", name, contexts)
 	}
-	log.Warnf("Unable to find the %s command. Error: %q", cmd, err)
-
-	return ""
-}
-
-func (c *ClusterCollector) getClusterContextName() (string, error) {
-	cmd := exec.Command(c.getClusterCommand(), "config", "current-context")
-	name, err := cmd.Output()
-	return string(name), err
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		log.Warnf("Failed to get the config for the cluster API client. Error: %q", err)
+		return "", nil, err
+	}
+	if opts.QPS > 0 {
+		cfg.QPS = opts.QPS
+	}
+	if opts.Burst > 0 {
+		cfg.Burst = opts.Burst
+	}
+	if common.CommandTimeout > 0 {
+		cfg.Timeout = common.CommandTimeout
+	}
+	return name, cfg, nil
 }
 
-func (c *ClusterCollector) getStorageClasses() ([]string, error) {
-	ccmd := c.getClusterCommand()
-	cmd := exec.Command(ccmd, "get", "sc", "-o", "yaml")
-	yamlOutput, err := cmd.CombinedOutput()
+func (c *ClusterCollector) getStorageClasses(cfg *rest.Config) ([]string, error) {
+	clientset, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
-		errDesc := c.interpretError(string(yamlOutput))
-		if errDesc != "" {
-			log.Warnf("Error while running %s. %s", ccmd, errDesc)
+		log.Warnf("Failed to create the cluster API client. Error: %q", err)
+		return nil, err
+	}
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
+	scList, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if errDesc := c.interpretError(err); errDesc != "" {
+			log.Warnf("Error while fetching storage classes. %s", errDesc)
 		} else {
-			log.Warnf("Error while fetching storage classes using command [%s]", cmd)
+			log.Warnf("Error while fetching storage classes. Error: %q", err)
 		}
 		return nil, err
 	}
+	storageClasses := []string{}
+	for _, sc := range scList.Items {
+		storageClasses = append(storageClasses, sc.Name)
+	}
+	return storageClasses, nil
+}
 
-	fileContents := map[string]interface{}{}
-	err = yaml.Unmarshal(yamlOutput, &fileContents)
-	if err != nil {
-		log.Errorf("Error in unmarshalling yaml: %s. Skipping.", err)
-		return nil, err
+func (c *ClusterCollector) interpretError(err error) string {
+	if apierrors.IsUnauthorized(err) {
+		return "Please login to the cluster before running collect. (e.g. oc login <cluster url> --token=<token string>) or configure the cluster authentication with the following instructions: [https://kubernetes.io/docs/reference/kubectl/cheatsheet/#kubectl-context-and-configuration]"
 	}
+	if apierrors.IsForbidden(err) {
+		return fmt.Sprintf("The user does not have the permissions required to discover the cluster. Error: %q", err)
+	}
+	return ""
+}
 
-	scArray := fileContents["items"].([]interface{})
-	storageClasses := []string{}
+// detectFlavor records the Kubernetes version and the distribution running on the cluster
+func (c *ClusterCollector) detectFlavor(cfg *rest.Config, spec *collecttypes.ClusterMetadataSpec) {
+	api, err := cgdiscovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		log.Warnf("Failed to get the discovery client for the cluster. Error: %q", err)
+		return
+	}
+	if serverVersion, err := api.ServerVersion(); err != nil {
+		log.Warnf("Failed to get the Kubernetes version of the cluster. Error: %q", err)
+	} else {
+		spec.KubernetesVersion = strings.TrimPrefix(serverVersion.GitVersion, "v")
+	}
+	spec.Flavor = collecttypes.KubernetesClusterFlavor
+	spec.FlavorVersion = ""
+	for _, gv := range spec.GetSupportedVersions("Route") {
+		if gvObj, err := schema.ParseGroupVersion(gv); err == nil && gvObj.Group == openshiftRouteGroup {
+			spec.Flavor = collecttypes.OpenShiftClusterFlavor
+			break
+		}
+	}
+	if spec.Flavor != collecttypes.OpenShiftClusterFlavor {
+		return
+	}
+	spec.FlavorVersion = c.getOpenShiftVersion(cfg, api)
+	log.Infof("Detected OpenShift version %q on the cluster", spec.FlavorVersion)
+}
 
-	for _, sc := range scArray {
-		if mapSC, ok := sc.(map[string]interface{}); ok {
-			storageClasses = append(storageClasses, mapSC["metadata"].(map[string]interface{})["name"].(string))
+// getOpenShiftVersion returns the version reported by the cluster version operator (OpenShift 4)
+// or by the /version/openshift endpoint (OpenShift 3). Returns an empty string if neither is available.
+func (c *ClusterCollector) getOpenShiftVersion(cfg *rest.Config, api cgdiscovery.DiscoveryInterface) string {
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
+	if dynamicClient, err := dynamic.NewForConfig(cfg); err == nil {
+		clusterVersionGVR := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+		clusterVersion, err := dynamicClient.Resource(clusterVersionGVR).Get(ctx, "version", metav1.GetOptions{})
+		if err == nil {
+			if v, ok, _ := unstructured.NestedString(clusterVersion.Object, "status", "desired", "version"); ok && v != "" {
+				return v
+			}
 		} else {
-			log.Warnf("Unknown type detected in cluster metadata [%T]", mapSC)
+			log.Debugf("Failed to get the cluster version from the cluster version operator. Error: %q", err)
 		}
 	}
-
-	return storageClasses, nil
+	data, err := api.RESTClient().Get().AbsPath("/version/openshift").Do(ctx).Raw()
+	if err != nil {
+		log.Debugf("Failed to get the OpenShift version. Error: %q", err)
+		return ""
+	}
+	info := version.Info{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		log.Debugf("Failed to parse the OpenShift version %s . Error: %q", string(data), err)
+		return ""
+	}
+	return strings.TrimPrefix(info.GitVersion, "v")
 }
 
-func (c *ClusterCollector) interpretError(cmdOutput string) string {
-	errorTerms := []string{"Unauthorized", "Username"}
+// podNameSuffixRegex matches the suffixes added to the names of the pods of the deployments, the daemon sets and the stateful sets
+var podNameSuffixRegex = regexp.MustCompile(`((-[a-z0-9]{8,10})?-[a-z0-9]{5}|-[0-9]+)$`)
 
-	for _, e := range errorTerms {
-		if c.getClusterCommand() == "oc" && strings.Contains(cmdOutput, e) {
-			return "Please login to cluster before running collect. (e.g. oc login <cluster url> --token=<token string>)"
-		} else if c.getClusterCommand() == "kubectl" && strings.Contains(cmdOutput, e) {
-			return "Please configure the cluster authentication with following instructions: [https://kubernetes.io/docs/reference/kubectl/cheatsheet/#kubectl-context-and-configuration]"
+// getWorkloadUsage returns the highest cpu and memory used by a pod of each workload, according to the metrics API
+func (c *ClusterCollector) getWorkloadUsage(cfg *rest.Config) []collecttypes.WorkloadUsage {
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		log.Warnf("Failed to create the cluster API client. Error: %q", err)
+		return nil
+	}
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
+	podMetricsGVR := schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	podMetricsList, err := dynamicClient.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Warnf("Failed to collect the usage of the workloads from the metrics API. Check that the metrics server is installed. Error: %q", err)
+		return nil
+	}
+	cpus := map[string]resource.Quantity{}
+	memories := map[string]resource.Quantity{}
+	workloads := map[string]collecttypes.WorkloadUsage{}
+	for _, podMetrics := range podMetricsList.Items {
+		cpu, memory := resource.Quantity{}, resource.Quantity{}
+		containers, _, _ := unstructured.NestedSlice(podMetrics.Object, "containers")
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usage, _, _ := unstructured.NestedStringMap(containerMap, "usage")
+			if quantity, err := resource.ParseQuantity(usage["cpu"]); err == nil {
+				cpu.Add(quantity)
+			}
+			if quantity, err := resource.ParseQuantity(usage["memory"]); err == nil {
+				memory.Add(quantity)
+			}
+		}
+		workload := collecttypes.WorkloadUsage{Name: getWorkloadName(podMetrics.GetName(), podMetrics.GetLabels()), Namespace: podMetrics.GetNamespace()}
+		key := workload.Namespace + "/" + workload.Name
+		workloads[key] = workload
+		if peak, ok := cpus[key]; !ok || cpu.Cmp(peak) > 0 {
+			cpus[key] = cpu
 		}
+		if peak, ok := memories[key]; !ok || memory.Cmp(peak) > 0 {
+			memories[key] = memory
+		}
+	}
+	keys := []string{}
+	for key := range workloads {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	usages := []collecttypes.WorkloadUsage{}
+	for _, key := range keys {
+		workload := workloads[key]
+		cpu, memory := cpus[key], memories[key]
+		// The usage is rounded up to millicores and mebibytes
+		workload.CPU = resource.NewMilliQuantity(cpu.MilliValue(), resource.DecimalSI).String()
+		workload.Memory = resource.NewQuantity((memory.Value()+mebibyte-1)/mebibyte*mebibyte, resource.BinarySI).String()
+		usages = append(usages, workload)
+	}
+	log.Infof("Collected the usage of %d workloads from the metrics API", len(usages))
+	return usages
+}
 
-	return ""
+// getWorkloadName returns the name of the workload running the pod, using its labels or its name
+func getWorkloadName(podName string, labels map[string]string) string {
+	for _, label := range []string{"app.kubernetes.io/name", "app"} {
+		if name := labels[label]; name != "" {
+			return name
+		}
+	}
+	return podNameSuffixRegex.ReplaceAllString(podName, "")
 }
 
 func (c ClusterCollector) getGlobalGroupOrder() []string {
 	return []string{`^.+\.k8s\.io$`, `^apps$`, `^policy$`, `^extensions$`, `^.+\.openshift\.io$`}
 }
 
-func (c *ClusterCollector) getAPI() (*cgdiscovery.DiscoveryClient, error) {
-	rules := cgclientcmd.NewDefaultClientConfigLoadingRules()
-	cfg, err := cgclientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &cgclientcmd.ConfigOverrides{}).ClientConfig()
-	if err != nil {
-		log.Warnf("Failed to get the default config for the cluster API client. Error: %q", err)
-		return nil, err
-	}
-	return cgdiscovery.NewDiscoveryClientForConfig(cfg)
-}
-
-func (c *ClusterCollector) getPreferredResourceUsingAPI(api *cgdiscovery.DiscoveryClient) ([]schema.GroupVersion, error) {
-	defer func() []schema.GroupVersion {
-		if rErr := recover(); rErr != nil {
-			log.Errorf("Recovered from error in getPreferredResourceUsingAPI [%s]", rErr)
-			return nil
-		}
-		return []schema.GroupVersion{}
-	}()
-	debug.SetPanicOnFault(true)
+func (c *ClusterCollector) getPreferredGroupVersions(apiGroupList *metav1.APIGroupList) []schema.GroupVersion {
 	var gvList []schema.GroupVersion
-	if api == nil {
-		log.Errorf("API object is null")
-		return nil, fmt.Errorf("API object is null")
-	}
-	apiGroupList, err := api.ServerGroups()
-	if err != nil {
-		log.Errorf("API request for server-group list failed")
-		return nil, err
-	}
 	for _, group := range apiGroupList.Groups {
 		preferredGV, err := schema.ParseGroupVersion(group.PreferredVersion.GroupVersion)
 		if err != nil {
@@ -231,33 +371,46 @@ func (c *ClusterCollector) getPreferredResourceUsingAPI(api *cgdiscovery.Discove
 			gvList = append(gvList, gv)
 		}
 	}
-	return gvList, nil
+	return gvList
 }
 
-func (c *ClusterCollector) getKindsForGroups(api *cgdiscovery.DiscoveryClient) (map[string][]schema.GroupVersion, error) {
-	defer func() map[string][]schema.GroupVersion {
-		if rErr := recover(); rErr != nil {
-			log.Errorf("Recovered from error in getKindsForGroups [%s]", rErr)
-			return nil
+// getKindsForGroups fetches the resources of all the group versions concurrently.
+// Group versions that fail (eg: an unavailable aggregated API) are skipped.
+func (c *ClusterCollector) getKindsForGroups(api cgdiscovery.DiscoveryInterface, apiGroupList *metav1.APIGroupList) (map[string][]schema.GroupVersion, error) {
+	gvs := []string{}
+	for _, group := range apiGroupList.Groups {
+		for _, version := range group.Versions {
+			gvs = append(gvs, version.GroupVersion)
 		}
-		var emptyMap map[string][]schema.GroupVersion
-		return emptyMap
-	}()
-
-	mapKind := map[string][]schema.GroupVersion{}
-
-	_, apiResourceList, err := api.ServerGroupsAndResources()
-	if err != nil {
-		return nil, err
 	}
+	resourceLists := make([]*metav1.APIResourceList, len(gvs))
+	errs := make([]error, len(gvs))
+	semaphore := make(chan struct{}, maxConcurrentDiscoveryRequests)
+	var wg sync.WaitGroup
+	for i, gv := range gvs {
+		wg.Add(1)
+		go func(i int, gv string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			resourceLists[i], errs[i] = api.ServerResourcesForGroupVersion(gv)
+		}(i, gv)
+	}
+	wg.Wait()
 
-	for _, rscListObj := range apiResourceList {
-		gvObj, err := schema.ParseGroupVersion(rscListObj.GroupVersion)
-		for err != nil {
-			log.Warnf("Ignoring group-version [%s]. Could not parse it", rscListObj.GroupVersion)
+	mapKind := map[string][]schema.GroupVersion{}
+	failed := 0
+	for i, rscListObj := range resourceLists {
+		if errs[i] != nil {
+			log.Warnf("Failed to get the resources for the group-version [%s]. Skipping. Error: %q", gvs[i], errs[i])
+			failed++
+			continue
+		}
+		gvObj, err := schema.ParseGroupVersion(gvs[i])
+		if err != nil {
+			log.Warnf("Ignoring group-version [%s]. Could not parse it", gvs[i])
 			continue
 		}
-
 		for _, rscObj := range rscListObj.APIResources {
 			if gvList, ok := mapKind[rscObj.Kind]; ok {
 				if !gvExists(gvList, gvObj) {
@@ -266,11 +419,12 @@ func (c *ClusterCollector) getKindsForGroups(api *cgdiscovery.DiscoveryClient) (
 				mapKind[rscObj.Kind] = gvList
 			} else {
 				mapKind[rscObj.Kind] = []schema.GroupVersion{gvObj}
-
 			}
 		}
 	}
-
+	if len(gvs) > 0 && failed == len(gvs) {
+		return nil, errs[0]
+	}
 	return mapKind, nil
 }
 
@@ -298,24 +452,27 @@ func (c *ClusterCollector) sortGroupVersionByPreference(prefGVList []schema.Grou
 	}
 }
 
-func (c *ClusterCollector) collectUsingAPI() (map[string][]string, error) {
-	api, err := c.getAPI()
+func (c *ClusterCollector) collectUsingAPI(cfg *rest.Config) (map[string][]string, error) {
+	api, err := cgdiscovery.NewDiscoveryClientForConfig(cfg)
 	if err != nil {
-		log.Warnf("Failed to api handle for cluster")
+		log.Warnf("Failed to get the discovery client for the cluster. Error: %q", err)
 		return nil, err
 	}
 
-	gvList, err := c.getPreferredResourceUsingAPI(api)
-	errStr := "Failed to retrieve preferred group information from cluster"
+	apiGroupList, err := api.ServerGroups()
 	if err != nil {
-		log.Warnf(errStr)
+		log.Warnf("API request for server-group list failed")
 		return nil, err
-	} else if len(gvList) == 0 {
+	}
+
+	gvList := c.getPreferredGroupVersions(apiGroupList)
+	errStr := "Failed to retrieve preferred group information from cluster"
+	if len(gvList) == 0 {
 		log.Warnf(errStr)
 		return nil, fmt.Errorf(errStr)
 	}
 
-	mapKind, err := c.getKindsForGroups(api)
+	mapKind, err := c.getKindsForGroups(api, apiGroupList)
 	errStr = "Failed to retrieve <kind, group-version> information from cluster"
 	if err != nil {
 		log.Warnf(errStr)
@@ -488,154 +645,6 @@ func (c *ClusterCollector) clusterByGroupsAndSortVersions(gvList []string) []str
 	return sortedGVList
 }
 
-func (c *ClusterCollector) collectUsingCLI() (map[string][]string, error) {
-	cmd := exec.Command("bash", "-c", c.getClusterCommand()+" api-resources -o name")
-	output, err := cmd.Output()
-	if err != nil {
-		log.Errorf("Error while running kubectl api-resources: %s", err)
-		return nil, err
-	}
-	log.Debugf("Got kind information for cluster")
-	nameList := strings.Split(string(output), "\n")
-	mapKind := map[string][]schema.GroupVersion{}
-	for _, name := range nameList {
-		tmpArray := strings.Split(name, ".")
-		if len(tmpArray) > 0 {
-			name := tmpArray[0]
-			kind, gvStr, err := c.getGVKUsingNameCLI(name)
-			if err != nil {
-				log.Debugf("Erroring parsing kind from CLI output")
-				continue
-			}
-			group := ""
-			for i, tmp := range tmpArray {
-				if i == 1 {
-					group = tmp
-				} else if i > 1 {
-					tmp = strings.TrimSpace(tmp)
-					group = group + "." + tmp
-				}
-			}
-
-			if group != "" {
-				if groupArray, ok := mapKind[kind]; ok {
-					groupArray = append(groupArray, schema.GroupVersion{Group: group, Version: ""})
-					mapKind[kind] = groupArray
-				} else {
-					mapKind[kind] = []schema.GroupVersion{{Group: group, Version: ""}}
-				}
-			} else {
-				mapKind[kind] = []schema.GroupVersion{{Group: "", Version: gvStr}}
-			}
-		}
-	}
-
-	apiMd := map[string][]string{}
-
-	for kind, availableGroupList := range mapKind {
-		if len(availableGroupList) == 1 {
-			singletonObj := availableGroupList[0]
-			if strings.Compare(singletonObj.Group, "") == 0 {
-				apiMd[kind] = []string{singletonObj.Version}
-				continue
-			}
-		}
-		if len(availableGroupList) > 0 {
-			gvList := c.getPreferredGVUsingCLI(kind, availableGroupList)
-			apiMd[kind] = gvList
-		} else {
-			log.Warnf("Empty group for kind [%s]", kind)
-		}
-	}
-
-	return apiMd, nil
-}
-
-func (c *ClusterCollector) getPreferredGVUsingCLI(kind string, availableGroupList []schema.GroupVersion) []string {
-	scheme := k8sschema.GetSchema()
-	var gvList []string
-	for _, gvObj := range availableGroupList {
-		prioritizedGVList := scheme.PrioritizedVersionsForGroup(gvObj.Group)
-		if len(prioritizedGVList) > 0 {
-			for _, gv := range prioritizedGVList {
-				isSupported, err := c.isSupportedGV(kind, gv.String())
-				if isSupported {
-					gvList = append(gvList, gv.String())
-				} else {
-					log.Debugf("Group version not found by CLI for kind [%s] : %s", kind, err)
-				}
-			}
-		} else {
-			_, gvStr, err := c.getGVKUsingNameCLI(kind)
-			if err == nil {
-				gvList = append(gvList, gvStr)
-			}
-		}
-	}
-
-	return gvList
-}
-
-func (c *ClusterCollector) isSupportedGV(kind string, gvStr string) (bool, error) {
-	cmd := exec.Command("bash", "-c", c.getClusterCommand()+" explain "+kind+" --api-version="+gvStr+" --recursive")
-	output, err := cmd.Output()
-	if err != nil {
-		log.Debugf("Error while running %s for verifying [%s]\n", c.getClusterCommand(), gvStr)
-		return false, err
-	}
-
-	lines := strings.Split(string(output), "\n")
-
-	if len(lines) < 2 {
-		return false, fmt.Errorf("Description incomplete")
-	}
-
-	if strings.Contains(lines[1], "VERSION") {
-		return true, nil
-	}
-
-	return false, fmt.Errorf("GV [%s] not found", gvStr)
-}
-
-func (c *ClusterCollector) getGVKUsingNameCLI(name string) (string, string, error) {
-	cmd := exec.Command("bash", "-c", c.getClusterCommand()+" explain "+name)
-	output, err := cmd.Output()
-	if err != nil {
-		//log.Errorf("Error while running kubectl: %s\n", err)
-		return "", "", err
-	}
-
-	var gvk schema.GroupVersionKind
-
-	lines := strings.Split(string(output), "\n")
-
-	if len(lines) < 2 {
-		return "", "", fmt.Errorf("Description incomplete")
-	}
-
-	if strings.Contains(lines[0], "KIND") {
-		tmpArray := strings.Split(lines[0], ":")
-		gvk.Kind = strings.TrimSpace(tmpArray[1])
-	} else {
-		return "", "", err
-	}
-
-	if strings.Contains(lines[1], "VERSION") {
-		tmpArray := strings.Split(lines[1], ":")
-		tmpGV := strings.TrimSpace(tmpArray[1])
-		tmpGVLines := strings.Split(tmpGV, "/")
-		if len(tmpGVLines) == 2 {
-			gvk.Group = tmpGVLines[0]
-			gvk.Version = tmpGVLines[1]
-		} else {
-			gvk.Group = ""
-			gvk.Version = tmpGVLines[0]
-		}
-	}
-
-	return gvk.Kind, gvk.GroupVersion().String(), nil
-}
-
 //GVExists looks up group version from list
 func gvExists(gvList []schema.GroupVersion, gvKey schema.GroupVersion) bool {
 	for _, gv := range gvList {
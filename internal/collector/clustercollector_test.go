@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	"k8s.io/client-go/rest"
+)
+
+func TestClusterCollector(t *testing.T) {
+	t.Run("context selection and impersonation", func(t *testing.T) {
+		kubeconfig := filepath.Join(t.TempDir(), "config")
+		data := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: user1
+- name: prod
+  context:
+    cluster: prod
+    user: user1
+users:
+- name: user1
+  user:
+    token: token1
+`
+		if err := os.WriteFile(kubeconfig, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write the kubeconfig. Error: %q", err)
+		}
+		c := ClusterCollector{}
+		opts := ClusterCollectorOptions{Kubeconfig: kubeconfig, Context: "prod", Impersonate: "admin", ImpersonateGroups: []string{"system:masters"}, QPS: 20, Burst: 40}
+		name, cfg, err := c.getClusterConfig(opts)
+		if err != nil {
+			t.Fatalf("Failed to get the cluster config. Error: %q", err)
+		}
+		if name != "prod" || cfg.Host != "https://prod.example.com" {
+			t.Fatalf("Failed to select the context. Actual: %s %s", name, cfg.Host)
+		}
+		if cfg.Impersonate.UserName != "admin" || !reflect.DeepEqual(cfg.Impersonate.Groups, []string{"system:masters"}) {
+			t.Fatalf("Failed to set the impersonation. Actual: %+v", cfg.Impersonate)
+		}
+		if cfg.QPS != 20 || cfg.Burst != 40 {
+			t.Fatalf("Failed to set the QPS and burst. Actual: %f %d", cfg.QPS, cfg.Burst)
+		}
+		if _, _, err := c.getClusterConfig(ClusterCollectorOptions{Kubeconfig: kubeconfig, Context: "missing"}); err == nil {
+			t.Fatal("Should have failed since the context does not exist.")
+		}
+	})
+
+	t.Run("discovery of kinds and versions", func(t *testing.T) {
+		responses := map[string]string{
+			"/api":          `{"kind":"APIVersions","versions":["v1"]}`,
+			"/apis":         `{"kind":"APIGroupList","apiVersion":"v1","groups":[{"name":"apps","versions":[{"groupVersion":"apps/v1","version":"v1"}],"preferredVersion":{"groupVersion":"apps/v1","version":"v1"}},{"name":"metrics.k8s.io","versions":[{"groupVersion":"metrics.k8s.io/v1beta1","version":"v1beta1"}],"preferredVersion":{"groupVersion":"metrics.k8s.io/v1beta1","version":"v1beta1"}}]}`,
+			"/api/v1":       `{"kind":"APIResourceList","groupVersion":"v1","resources":[{"name":"pods","namespaced":true,"kind":"Pod","verbs":["get","list"]}]}`,
+			"/apis/apps/v1": `{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"apps/v1","resources":[{"name":"deployments","namespaced":true,"kind":"Deployment","verbs":["get","list"]}]}`,
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp, ok := responses[r.URL.Path]
+			if !ok {
+				// simulates an unavailable aggregated API
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, resp)
+		}))
+		defer server.Close()
+		c := ClusterCollector{}
+		kindVersions, err := c.collectUsingAPI(&rest.Config{Host: server.URL})
+		if err != nil {
+			t.Fatalf("Failed to collect using the API. Error: %q", err)
+		}
+		want := map[string][]string{"Pod": {"v1"}, "Deployment": {"apps/v1"}}
+		if !reflect.DeepEqual(kindVersions, want) {
+			t.Fatalf("Failed to discover the kinds properly. Expected: %v Actual: %v", want, kindVersions)
+		}
+	})
+	t.Run("openshift flavor detection", func(t *testing.T) {
+		responses := map[string]string{
+			"/version": `{"major":"1","minor":"19","gitVersion":"v1.19.0+9c69bdc"}`,
+			"/apis/config.openshift.io/v1/clusterversions/version": `{"apiVersion":"config.openshift.io/v1","kind":"ClusterVersion","metadata":{"name":"version"},"status":{"desired":{"version":"4.6.8"}}}`,
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp, ok := responses[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, resp)
+		}))
+		defer server.Close()
+		c := ClusterCollector{}
+		spec := collecttypes.NewClusterMetadata("").Spec
+		spec.APIKindVersionMap = map[string][]string{"Route": {"route.openshift.io/v1"}}
+		c.detectFlavor(&rest.Config{Host: server.URL}, &spec)
+		if spec.Flavor != collecttypes.OpenShiftClusterFlavor || spec.FlavorVersion != "4.6.8" || spec.KubernetesVersion != "1.19.0+9c69bdc" {
+			t.Fatalf("Failed to detect the flavor properly. Actual: %s %s %s", spec.Flavor, spec.FlavorVersion, spec.KubernetesVersion)
+		}
+		spec.APIKindVersionMap = map[string][]string{"Deployment": {"apps/v1"}}
+		c.detectFlavor(&rest.Config{Host: server.URL}, &spec)
+		if spec.Flavor != collecttypes.KubernetesClusterFlavor {
+			t.Fatalf("Expected the flavor to be %s. Actual: %s", collecttypes.KubernetesClusterFlavor, spec.Flavor)
+		}
+	})
+	t.Run("usage of the workloads from the metrics API", func(t *testing.T) {
+		podMetrics := `{"kind":"PodMetricsList","apiVersion":"metrics.k8s.io/v1beta1","metadata":{},"items":[` +
+			`{"metadata":{"name":"web-7d4b9c8f6-x2x9z","namespace":"shop"},"containers":[{"name":"web","usage":{"cpu":"120000001n","memory":"200Mi"}},{"name":"proxy","usage":{"cpu":"10m","memory":"20Mi"}}]},` +
+			`{"metadata":{"name":"web-7d4b9c8f6-k8s2q","namespace":"shop"},"containers":[{"name":"web","usage":{"cpu":"50m","memory":"300000Ki"}}]},` +
+			`{"metadata":{"name":"db-0","namespace":"shop","labels":{"app":"postgres"}},"containers":[{"name":"db","usage":{"cpu":"1","memory":"1Gi"}}]}]}`
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/apis/metrics.k8s.io/v1beta1/pods" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, podMetrics)
+		}))
+		defer server.Close()
+		c := ClusterCollector{}
+		usage := c.getWorkloadUsage(&rest.Config{Host: server.URL})
+		want := []collecttypes.WorkloadUsage{
+			{Name: "postgres", Namespace: "shop", CPU: "1", Memory: "1Gi"},
+			{Name: "web", Namespace: "shop", CPU: "131m", Memory: "293Mi"},
+		}
+		if !reflect.DeepEqual(usage, want) {
+			t.Fatalf("Failed to collect the usage of the workloads. Expected: %+v Actual: %+v", want, usage)
+		}
+	})
+}
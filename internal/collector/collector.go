@@ -16,6 +16,13 @@ limitations under the License.
 
 package collector
 
+import (
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+// OutputFormat is the format, yaml or json, of the files written by the collectors
+var OutputFormat = common.YamlOutputFormat
+
 //Collector defines interface for collecting data from data sources
 type Collector interface {
 	Collect(inputDirectory string, outputPath string) error
@@ -24,6 +31,6 @@ type Collector interface {
 
 // GetCollectors returns different collectors
 func GetCollectors() ([]Collector, error) {
-	collectors := []Collector{new(ClusterCollector), new(ImagesCollector), new(CFContainerTypesCollector), new(CfAppsCollector)}
+	collectors := []Collector{new(ClusterCollector), new(ImagesCollector), new(CFContainerTypesCollector), new(CfAppsCollector), new(HerokuAppsCollector)}
 	return collectors, nil
 }
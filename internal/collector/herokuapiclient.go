@@ -0,0 +1,310 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// herokuAPIKeyEnvVar can be used to specify the API key instead of reading it from the netrc file written by the heroku CLI
+	herokuAPIKeyEnvVar = "HEROKU_API_KEY"
+	// herokuAPIEnvVar can be used to specify the Heroku platform API endpoint
+	herokuAPIEnvVar     = "HEROKU_API"
+	herokuDefaultAPI    = "https://api.heroku.com"
+	herokuAPIHost       = "api.heroku.com"
+	herokuAPIRetries    = 3
+	herokuAcceptHeader  = "application/vnd.heroku+json; version=3"
+	herokuNextRangeName = "Next-Range"
+)
+
+// HerokuAPIError is returned when the Heroku platform API responds with an unsuccessful status code
+type HerokuAPIError struct {
+	StatusCode int
+	URL        string
+	ID         string
+	Message    string
+}
+
+func (e *HerokuAPIError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("the Heroku API request to %s failed with status code %d", e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("the Heroku API request to %s failed with status code %d : %s: %s", e.URL, e.StatusCode, e.ID, e.Message)
+}
+
+// herokuAPIClient talks to the Heroku platform API without requiring the heroku CLI
+type herokuAPIClient struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// newHerokuAPIClient creates a client using the environment variables or the netrc file written by heroku login for authentication
+func newHerokuAPIClient() (*herokuAPIClient, error) {
+	endpoint := herokuDefaultAPI
+	if api := os.Getenv(herokuAPIEnvVar); api != "" {
+		endpoint = api
+	}
+	apiKey := os.Getenv(herokuAPIKeyEnvVar)
+	if apiKey == "" {
+		netrcPath, err := getNetrcPath()
+		if err != nil {
+			log.Debugf("Unable to find the netrc file. Error: %q", err)
+		} else if apiKey, err = getNetrcPassword(netrcPath, herokuAPIHost); err != nil {
+			log.Debugf("Unable to read the netrc file at path %s . Error: %q", netrcPath, err)
+		}
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("no Heroku API key found. Either login using the heroku CLI or set the %s environment variable", herokuAPIKeyEnvVar)
+	}
+	return newHerokuAPIClientFromKey(endpoint, apiKey), nil
+}
+
+func newHerokuAPIClientFromKey(endpoint, apiKey string) *herokuAPIClient {
+	client := &http.Client{}
+	if common.CommandTimeout > 0 {
+		client.Timeout = common.CommandTimeout
+	}
+	return &herokuAPIClient{endpoint: strings.TrimSuffix(endpoint, "/"), apiKey: apiKey, client: client}
+}
+
+func getNetrcPath() (string, error) {
+	if netrcPath := os.Getenv("NETRC"); netrcPath != "" {
+		return netrcPath, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{".netrc", "_netrc"} {
+		netrcPath := filepath.Join(homeDir, name)
+		if _, err := os.Stat(netrcPath); err == nil {
+			return netrcPath, nil
+		}
+	}
+	return "", fmt.Errorf("no netrc file found in the home directory %s", homeDir)
+}
+
+// getNetrcPassword returns the password of the machine in the netrc file
+func getNetrcPassword(netrcPath, machine string) (string, error) {
+	f, err := os.Open(netrcPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Split(bufio.ScanWords)
+	inMachine := false
+	for scanner.Scan() {
+		switch scanner.Text() {
+		case "machine":
+			inMachine = scanner.Scan() && scanner.Text() == machine
+		case "default":
+			inMachine = false
+		case "password":
+			if scanner.Scan() && inMachine {
+				return scanner.Text(), nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no password found for the machine %s", machine)
+}
+
+// get fetches a single resource
+func (c *herokuAPIClient) get(path string, out interface{}) error {
+	body, _, err := c.getWithRetries(c.endpoint+path, "")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse the response from %s . Error: %q", c.endpoint+path, err)
+	}
+	return nil
+}
+
+// list fetches all the ranges of a list endpoint and returns the resources
+func (c *herokuAPIClient) list(path string) ([]json.RawMessage, error) {
+	resources := []json.RawMessage{}
+	nextRange := ""
+	for {
+		body, header, err := c.getWithRetries(c.endpoint+path, nextRange)
+		if err != nil {
+			return resources, err
+		}
+		page := []json.RawMessage{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return resources, fmt.Errorf("failed to parse the response from %s . Error: %q", c.endpoint+path, err)
+		}
+		resources = append(resources, page...)
+		nextRange = header.Get(herokuNextRangeName)
+		if nextRange == "" {
+			return resources, nil
+		}
+	}
+}
+
+func (c *herokuAPIClient) getWithRetries(reqURL, reqRange string) ([]byte, http.Header, error) {
+	var lastErr error
+	for attempt := 0; attempt <= herokuAPIRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(attempt) * time.Second
+			log.Debugf("Retrying the Heroku API request to %s in %s . Previous error: %q", reqURL, backoff, lastErr)
+			time.Sleep(backoff)
+		}
+		req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		req.Header.Set("Accept", herokuAcceptHeader)
+		if reqRange != "" {
+			req.Header.Set("Range", reqRange)
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// The lists which have more resources than the range respond with partial content
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return body, resp.Header, nil
+		}
+		apiErr := &HerokuAPIError{StatusCode: resp.StatusCode, URL: reqURL}
+		herokuErr := sourcetypes.HerokuError{}
+		if err := json.Unmarshal(body, &herokuErr); err == nil {
+			apiErr.ID = herokuErr.ID
+			apiErr.Message = herokuErr.Message
+		}
+		lastErr = apiErr
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return nil, nil, apiErr
+		}
+	}
+	return nil, nil, lastErr
+}
+
+// getApps returns all the apps visible to the user
+func (c *herokuAPIClient) getApps() ([]sourcetypes.HerokuApp, error) {
+	apps := []sourcetypes.HerokuApp{}
+	resources, err := c.list("/apps")
+	if err != nil {
+		return apps, err
+	}
+	for _, resource := range resources {
+		app := sourcetypes.HerokuApp{}
+		if err := json.Unmarshal(resource, &app); err != nil {
+			log.Warnf("Failed to parse the Heroku app %s . Error: %q", string(resource), err)
+			continue
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// getConfigVars returns the config vars of an app
+func (c *herokuAPIClient) getConfigVars(appID string) (map[string]string, error) {
+	configVars := map[string]string{}
+	err := c.get("/apps/"+url.PathEscape(appID)+"/config-vars", &configVars)
+	return configVars, err
+}
+
+// getFormation returns the process types of an app, with their number and size of dynos
+func (c *herokuAPIClient) getFormation(appID string) ([]sourcetypes.HerokuFormation, error) {
+	formation := []sourcetypes.HerokuFormation{}
+	resources, err := c.list("/apps/" + url.PathEscape(appID) + "/formation")
+	if err != nil {
+		return formation, err
+	}
+	for _, resource := range resources {
+		processType := sourcetypes.HerokuFormation{}
+		if err := json.Unmarshal(resource, &processType); err != nil {
+			log.Warnf("Failed to parse the Heroku formation %s . Error: %q", string(resource), err)
+			continue
+		}
+		formation = append(formation, processType)
+	}
+	return formation, nil
+}
+
+// getAddOns returns the add-ons attached to an app
+func (c *herokuAPIClient) getAddOns(appID string) ([]sourcetypes.HerokuAddOn, error) {
+	addOns := []sourcetypes.HerokuAddOn{}
+	resources, err := c.list("/apps/" + url.PathEscape(appID) + "/addons")
+	if err != nil {
+		return addOns, err
+	}
+	for _, resource := range resources {
+		addOn := sourcetypes.HerokuAddOn{}
+		if err := json.Unmarshal(resource, &addOn); err != nil {
+			log.Warnf("Failed to parse the Heroku add-on %s . Error: %q", string(resource), err)
+			continue
+		}
+		addOns = append(addOns, addOn)
+	}
+	return addOns, nil
+}
+
+// getBuildpacks returns the URLs of the buildpacks building an app, in the order in which they are run
+func (c *herokuAPIClient) getBuildpacks(appID string) ([]string, error) {
+	installations := []sourcetypes.HerokuBuildpackInstallation{}
+	resources, err := c.list("/apps/" + url.PathEscape(appID) + "/buildpack-installations")
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		installation := sourcetypes.HerokuBuildpackInstallation{}
+		if err := json.Unmarshal(resource, &installation); err != nil {
+			log.Warnf("Failed to parse the Heroku buildpack installation %s . Error: %q", string(resource), err)
+			continue
+		}
+		installations = append(installations, installation)
+	}
+	sort.SliceStable(installations, func(i, j int) bool { return installations[i].Ordinal < installations[j].Ordinal })
+	buildpacks := []string{}
+	for _, installation := range installations {
+		buildpack := installation.Buildpack.Name
+		if buildpack == "" {
+			buildpack = installation.Buildpack.URL
+		}
+		buildpacks = append(buildpacks, buildpack)
+	}
+	return buildpacks, nil
+}
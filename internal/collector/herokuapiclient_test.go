@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+)
+
+func TestHerokuAPIClient(t *testing.T) {
+	t.Run("list follows the next ranges", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer key1" || r.Header.Get("Accept") != herokuAcceptHeader {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"id":"unauthorized","message":"Invalid credentials provided."}`)
+				return
+			}
+			if r.Header.Get("Range") == "id ]app1..; max=1" {
+				fmt.Fprint(w, `[{"id":"2","name":"app2","stack":{"name":"heroku-20"}}]`)
+				return
+			}
+			w.Header().Set(herokuNextRangeName, "id ]app1..; max=1")
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, `[{"id":"1","name":"app1","stack":{"name":"heroku-22"},"region":{"name":"eu"}}]`)
+		}))
+		defer server.Close()
+		client := newHerokuAPIClientFromKey(server.URL, "key1")
+		apps, err := client.getApps()
+		if err != nil {
+			t.Fatalf("Failed to get the apps. Error: %q", err)
+		}
+		if len(apps) != 2 || apps[0].Name != "app1" || apps[0].Stack.Name != "heroku-22" || apps[0].Region.Name != "eu" || apps[1].Name != "app2" {
+			t.Fatalf("Failed to get all the ranges. Actual: %+v", apps)
+		}
+	})
+
+	t.Run("buildpacks are ordered", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"ordinal":1,"buildpack":{"url":"https://github.com/heroku/heroku-buildpack-nodejs"}},{"ordinal":0,"buildpack":{"url":"urn:buildpack:heroku/python","name":"heroku/python"}}]`)
+		}))
+		defer server.Close()
+		client := newHerokuAPIClientFromKey(server.URL, "key1")
+		buildpacks, err := client.getBuildpacks("1")
+		if err != nil {
+			t.Fatalf("Failed to get the buildpacks. Error: %q", err)
+		}
+		if len(buildpacks) != 2 || buildpacks[0] != "heroku/python" || buildpacks[1] != "https://github.com/heroku/heroku-buildpack-nodejs" {
+			t.Fatalf("Failed to get the buildpacks in order. Actual: %v", buildpacks)
+		}
+	})
+
+	t.Run("errors from the api are structured", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"id":"forbidden","message":"You do not have access to the app."}`)
+		}))
+		defer server.Close()
+		client := newHerokuAPIClientFromKey(server.URL, "key1")
+		_, err := client.getConfigVars("1")
+		var apiErr *HerokuAPIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected a HerokuAPIError. Actual: %T %q", err, err)
+		}
+		if apiErr.StatusCode != http.StatusForbidden || apiErr.ID != "forbidden" {
+			t.Fatalf("Failed to parse the error. Actual: %+v", apiErr)
+		}
+	})
+
+	t.Run("api key is read from the netrc file", func(t *testing.T) {
+		netrcPath := filepath.Join(t.TempDir(), ".netrc")
+		netrc := "machine git.heroku.com\n  login user@example.com\n  password gitkey\nmachine api.heroku.com\n  login user@example.com\n  password apikey\n"
+		if err := ioutil.WriteFile(netrcPath, []byte(netrc), 0600); err != nil {
+			t.Fatalf("Failed to write the netrc file. Error: %q", err)
+		}
+		password, err := getNetrcPassword(netrcPath, herokuAPIHost)
+		if err != nil || password != "apikey" {
+			t.Fatalf("Failed to read the API key. Actual: %s Error: %q", password, err)
+		}
+	})
+}
+
+func TestGetHerokuAddOn(t *testing.T) {
+	addOn := sourcetypes.HerokuAddOn{Name: "redis-shallow-123", ConfigVars: []string{"REDIS_URL"}}
+	addOn.AddOnService.Name = "heroku-redis"
+	if got := getHerokuAddOn(addOn); got.Service != "heroku-redis" || got.Replacement != collecttypes.HerokuAddOnReplacements["heroku-redis"] || len(got.ConfigVars) != 1 {
+		t.Fatalf("Failed to suggest the replacement of the add-on. Actual: %+v", got)
+	}
+	addOn.AddOnService.Name = "unknown-addon"
+	if got := getHerokuAddOn(addOn); got.Replacement != collecttypes.HerokuDefaultAddOnReplacement {
+		t.Fatalf("Expected the default replacement for an unknown add-on. Actual: %+v", got)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	sourcetypes "github.com/konveyor/move2kube/internal/collector/sourcetypes"
+	"github.com/konveyor/move2kube/internal/common"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	log "github.com/sirupsen/logrus"
+)
+
+// HerokuCollectorOptions contains the options used to collect the heroku apps
+type HerokuCollectorOptions struct {
+	// ConfigVarValues collects the values of the config vars, which may contain secrets. Only the names are collected otherwise.
+	ConfigVarValues bool
+}
+
+// HerokuOptions are the options used by the HerokuAppsCollector
+var HerokuOptions = HerokuCollectorOptions{}
+
+// HerokuAppsCollector collects the heroku apps
+type HerokuAppsCollector struct {
+}
+
+// GetAnnotations returns annotations on which this collector should be invoked
+func (c *HerokuAppsCollector) GetAnnotations() []string {
+	annotations := []string{"heroku"}
+	return annotations
+}
+
+//Collect gets the heroku app metadata by querying the Heroku platform API
+func (c *HerokuAppsCollector) Collect(inputPath string, outputPath string) error {
+	client, err := newHerokuAPIClient()
+	if err != nil {
+		codedErr := common.NewError(common.HerokuAuthMissingErrorCode, err, "Unable to create the Heroku API client.")
+		log.Error(codedErr.Details())
+		return codedErr
+	}
+	apps, err := client.getApps()
+	if err != nil {
+		log.Errorf("Unable to get the apps from the Heroku API : %s", err)
+		return err
+	}
+	outputPath = filepath.Join(outputPath, "heroku")
+	err = os.MkdirAll(outputPath, common.DefaultDirectoryPermission)
+	if err != nil {
+		log.Errorf("Unable to create outputPath %s : %s", outputPath, err)
+	}
+	herokuapps := collecttypes.NewHerokuApps()
+	herokuapps.Spec.HerokuApplications = []collecttypes.HerokuApplication{}
+	log.Debugf("Detected %d apps", len(apps))
+	for _, sourceapp := range apps {
+		log.Debugf("Reading info about %s", sourceapp.Name)
+		herokuapps.Spec.HerokuApplications = append(herokuapps.Spec.HerokuApplications, getHerokuApplication(client, sourceapp, HerokuOptions.ConfigVarValues))
+	}
+	if !HerokuOptions.ConfigVarValues {
+		log.Infof("Only the names of the config vars were collected. Use --heroku-config-values to also collect their values, which may contain secrets.")
+	}
+	outputPath = filepath.Join(outputPath, "herokuapps"+common.GetOutputFormatExt(OutputFormat))
+	err = common.WriteYamlOrJSON(outputPath, herokuapps, OutputFormat)
+	if err != nil {
+		log.Errorf("Unable to write collect output : %s", err)
+	}
+	return err
+}
+
+func getHerokuApplication(client *herokuAPIClient, sourceapp sourcetypes.HerokuApp, collectValues bool) collecttypes.HerokuApplication {
+	app := collecttypes.HerokuApplication{Name: sourceapp.Name, Stack: sourceapp.Stack.Name, Region: sourceapp.Region.Name}
+	if buildpacks, err := client.getBuildpacks(sourceapp.ID); err != nil {
+		log.Warnf("Unable to get the buildpacks of the app %s : %s", sourceapp.Name, err)
+	} else {
+		app.Buildpacks = buildpacks
+	}
+	if configVars, err := client.getConfigVars(sourceapp.ID); err != nil {
+		log.Warnf("Unable to get the config vars of the app %s : %s", sourceapp.Name, err)
+	} else if len(configVars) > 0 {
+		app.ConfigVars = map[string]string{}
+		for name, value := range configVars {
+			if !collectValues {
+				value = ""
+			}
+			app.ConfigVars[name] = value
+		}
+	}
+	if formation, err := client.getFormation(sourceapp.ID); err != nil {
+		log.Warnf("Unable to get the formation of the app %s : %s", sourceapp.Name, err)
+	} else {
+		for _, processType := range formation {
+			app.Formation = append(app.Formation, collecttypes.HerokuProcessType{
+				Type:     processType.Type,
+				Quantity: processType.Quantity,
+				Size:     processType.Size,
+				Command:  processType.Command,
+			})
+		}
+		sort.Slice(app.Formation, func(i, j int) bool { return app.Formation[i].Type < app.Formation[j].Type })
+	}
+	if addOns, err := client.getAddOns(sourceapp.ID); err != nil {
+		log.Warnf("Unable to get the add-ons of the app %s : %s", sourceapp.Name, err)
+	} else {
+		for _, addOn := range addOns {
+			app.AddOns = append(app.AddOns, getHerokuAddOn(addOn))
+		}
+	}
+	return app
+}
+
+// getHerokuAddOn returns the add-on along with the suggested replacement of its service
+func getHerokuAddOn(addOn sourcetypes.HerokuAddOn) collecttypes.HerokuAddOn {
+	return collecttypes.HerokuAddOn{
+		Name:        addOn.Name,
+		Service:     addOn.AddOnService.Name,
+		Plan:        addOn.Plan.Name,
+		ConfigVars:  addOn.ConfigVars,
+		Replacement: collecttypes.GetHerokuAddOnReplacement(addOn.AddOnService.Name),
+	}
+}
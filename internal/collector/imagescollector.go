@@ -19,7 +19,6 @@ package collector
 import (
 	"encoding/json"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -72,8 +71,8 @@ func (c *ImagesCollector) Collect(inputDirectory string, outputPath string) erro
 					}
 				}
 			}
-			imagefile := filepath.Join(outputPath, common.NormalizeForFilename(shortesttag)+".yaml")
-			err := common.WriteYaml(imagefile, imageInfo)
+			imagefile := filepath.Join(outputPath, common.NormalizeForFilename(shortesttag)+common.GetOutputFormatExt(OutputFormat))
+			err := common.WriteYamlOrJSON(imagefile, imageInfo, OutputFormat)
 			log.Errorf("Unable to write file %s : %s", imagefile, err)
 		}
 	}
@@ -82,8 +81,7 @@ func (c *ImagesCollector) Collect(inputDirectory string, outputPath string) erro
 }
 
 func getDockerInspectResult(imageName string) ([]byte, error) {
-	cmd := exec.Command("docker", "inspect", imageName)
-	jsonOutput, err := cmd.CombinedOutput()
+	jsonOutput, err := common.RunCommandCombinedOutput("", "docker", "inspect", imageName)
 	if err != nil {
 		if strings.Contains(string(jsonOutput), "permission denied") {
 			log.Warnf("Error while running docker-inspect due to lack of permissions")
@@ -135,8 +133,7 @@ func getImageNames(inputPath string) ([]string, error) {
 }
 
 func getAllImageNames() ([]string, error) {
-	cmd := exec.Command("bash", "-c", "docker image list --format '{{.Repository}}:{{.Tag}}'")
-	outputStr, err := cmd.Output()
+	outputStr, err := common.RunCommand("", "docker", "image", "list", "--format", "{{.Repository}}:{{.Tag}}")
 	if err != nil {
 		log.Warnf("Error while running docker image list : %s", err)
 		return nil, err
@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcetypes
+
+import "encoding/json"
+
+// CfConfig is the subset of the cf CLI config file (~/.cf/config.json) needed to talk to the CF API
+type CfConfig struct {
+	Target               string `json:"Target"`
+	AccessToken          string `json:"AccessToken"`
+	RefreshToken         string `json:"RefreshToken"`
+	UaaEndpoint          string `json:"UaaEndpoint"`
+	SSLDisabled          bool   `json:"SSLDisabled"`
+	UAAOAuthClient       string `json:"UAAOAuthClient"`
+	UAAOAuthClientSecret string `json:"UAAOAuthClientSecret"`
+}
+
+// CfV3Pagination is the pagination section of a CF v3 list response
+type CfV3Pagination struct {
+	TotalResults int `json:"total_results"`
+	Next         *struct {
+		Href string `json:"href"`
+	} `json:"next"`
+}
+
+// CfV3Error is a single error returned by the CF v3 API
+type CfV3Error struct {
+	Code   int    `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// CfV3Errors is the body of an error response from the CF v3 API
+type CfV3Errors struct {
+	Errors []CfV3Error `json:"errors"`
+}
+
+// CfV3App is an app returned by the CF v3 API
+type CfV3App struct {
+	GUID      string `json:"guid"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Lifecycle struct {
+		Type string `json:"type"`
+		Data struct {
+			Buildpacks []string `json:"buildpacks"`
+			Stack      string   `json:"stack"`
+		} `json:"data"`
+	} `json:"lifecycle"`
+}
+
+// CfV3Process is a process of an app returned by the CF v3 API
+type CfV3Process struct {
+	GUID       string `json:"guid"`
+	Type       string `json:"type"`
+	Command    string `json:"command"`
+	Instances  int    `json:"instances"`
+	MemoryInMB int64  `json:"memory_in_mb"`
+	DiskInMB   int64  `json:"disk_in_mb"`
+}
+
+// CfV3Route is a route returned by the CF v3 API
+type CfV3Route struct {
+	GUID         string `json:"guid"`
+	URL          string `json:"url"`
+	Destinations []struct {
+		App struct {
+			GUID string `json:"guid"`
+		} `json:"app"`
+		Port int32 `json:"port"`
+	} `json:"destinations"`
+}
+
+// CfV3ServiceRouteBinding binds a route service to a route. The requests to the route are proxied through the route service.
+type CfV3ServiceRouteBinding struct {
+	GUID            string `json:"guid"`
+	RouteServiceURL string `json:"route_service_url"`
+}
+
+// CfAutoscalerPolicy is the scaling policy of an app returned by the App Autoscaler API
+type CfAutoscalerPolicy struct {
+	InstanceMinCount int `json:"instance_min_count"`
+	InstanceMaxCount int `json:"instance_max_count"`
+	ScalingRules     []struct {
+		MetricType         string `json:"metric_type"`
+		BreachDurationSecs int    `json:"breach_duration_secs"`
+		Threshold          int64  `json:"threshold"`
+		Operator           string `json:"operator"`
+		CoolDownSecs       int    `json:"cool_down_secs"`
+		Adjustment         string `json:"adjustment"`
+	} `json:"scaling_rules"`
+	Schedules *struct {
+		RecurringSchedule []json.RawMessage `json:"recurring_schedule"`
+		SpecificDate      []json.RawMessage `json:"specific_date"`
+	} `json:"schedules"`
+}
+
+// CfV3Buildpack is a buildpack returned by the CF v3 API
+type CfV3Buildpack struct {
+	Name     string `json:"name"`
+	Stack    string `json:"stack"`
+	Position int    `json:"position"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// CfV3Droplet is a droplet returned by the CF v3 API
+type CfV3Droplet struct {
+	Image      string `json:"image"`
+	Buildpacks []struct {
+		Name          string `json:"name"`
+		BuildpackName string `json:"buildpack_name"`
+		DetectOutput  string `json:"detect_output"`
+	} `json:"buildpacks"`
+}
+
+// CfV3EnvironmentVariables is the environment variables of an app returned by the CF v3 API
+type CfV3EnvironmentVariables struct {
+	Var map[string]string `json:"var"`
+}
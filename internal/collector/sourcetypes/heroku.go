@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcetypes
+
+// HerokuError is the body of an error response from the Heroku platform API
+type HerokuError struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// HerokuApp is an app returned by the Heroku platform API
+type HerokuApp struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Stack struct {
+		Name string `json:"name"`
+	} `json:"stack"`
+	Region struct {
+		Name string `json:"name"`
+	} `json:"region"`
+}
+
+// HerokuFormation is the number and the size of the dynos running a process type of an app
+type HerokuFormation struct {
+	Type     string `json:"type"`
+	Quantity int    `json:"quantity"`
+	Size     string `json:"size"`
+	Command  string `json:"command"`
+}
+
+// HerokuAddOn is an add-on attached to an app, like a database
+type HerokuAddOn struct {
+	Name         string `json:"name"`
+	AddOnService struct {
+		Name string `json:"name"`
+	} `json:"addon_service"`
+	Plan struct {
+		Name string `json:"name"`
+	} `json:"plan"`
+	ConfigVars []string `json:"config_vars"`
+}
+
+// HerokuBuildpackInstallation is a buildpack building an app
+type HerokuBuildpackInstallation struct {
+	Ordinal   int `json:"ordinal"`
+	Buildpack struct {
+		URL  string `json:"url"`
+		Name string `json:"name"`
+	} `json:"buildpack"`
+}
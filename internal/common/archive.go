@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TarGzArchiveFormat is the format of gzip compressed tar archives
+	TarGzArchiveFormat = "tar.gz"
+	// ZipArchiveFormat is the format of zip archives
+	ZipArchiveFormat = "zip"
+	// SHA256SumSuffix is the suffix of the files containing the checksum of an archive
+	SHA256SumSuffix = ".sha256sum"
+)
+
+// CopyPath copies the file or the directory at src to dst.
+// Directories are copied recursively, symbolic links are recreated and the permissions are preserved.
+func CopyPath(dst, src string) error {
+	return filepath.WalkDir(src, func(currPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, currPath)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(currPath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			log.Debugf("Skipping the file at path %s since it is not a regular file", currPath)
+			return nil
+		}
+		return copyFileWithPermission(target, currPath, info.Mode().Perm())
+	})
+}
+
+// CreateArchive creates an archive of the directory at source in the given format. The paths in the archive start with the name of the directory.
+func CreateArchive(target, source, format string) error {
+	switch format {
+	case TarGzArchiveFormat:
+		return CreateTarGz(target, source)
+	case ZipArchiveFormat:
+		return CreateZip(target, source)
+	}
+	return fmt.Errorf("unsupported archive format %s . Supported formats are %s and %s", format, TarGzArchiveFormat, ZipArchiveFormat)
+}
+
+// CreateTarGz creates a gzip compressed tar archive of the directory at source. The paths in the archive start with the name of the directory.
+func CreateTarGz(target, source string) error {
+	archiveFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to create the archive at path %s . Error: %q", target, err)
+	}
+	defer archiveFile.Close()
+	gw := gzip.NewWriter(archiveFile)
+	tw := tar.NewWriter(gw)
+	err = walkArchiveEntries(source, func(name, currPath string, info os.FileInfo) error {
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			var err error
+			if link, err = os.Readlink(currPath); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileContents(tw, currPath)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add the files in the directory %s to the archive at path %s . Error: %q", source, target, err)
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return archiveFile.Close()
+}
+
+// CreateZip creates a zip archive of the directory at source. The paths in the archive start with the name of the directory.
+func CreateZip(target, source string) error {
+	archiveFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to create the archive at path %s . Error: %q", target, err)
+	}
+	defer archiveFile.Close()
+	zw := zip.NewWriter(archiveFile)
+	err = walkArchiveEntries(source, func(name, currPath string, info os.FileInfo) error {
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			_, err := zw.CreateHeader(hdr)
+			return err
+		}
+		hdr.Method = zip.Deflate
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(currPath)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, link)
+			return err
+		}
+		return copyFileContents(w, currPath)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add the files in the directory %s to the archive at path %s . Error: %q", source, target, err)
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return archiveFile.Close()
+}
+
+// CreateSHA256SumFile writes the checksum of the file at source to target in the same format as the output of shasum -a 256
+func CreateSHA256SumFile(source, target string) error {
+	checksum, err := GetFileSHA256Hash(source)
+	if err != nil {
+		return fmt.Errorf("failed to calculate the checksum of the file at path %s . Error: %q", source, err)
+	}
+	if err := ioutil.WriteFile(target, []byte(checksum+"  "+filepath.Base(source)), DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the checksum to the file at path %s . Error: %q", target, err)
+	}
+	return nil
+}
+
+// walkArchiveEntries calls addEntry for every file in the directory with the slash separated name it should have in an archive
+func walkArchiveEntries(source string, addEntry func(name, currPath string, info os.FileInfo) error) error {
+	source = filepath.Clean(source)
+	baseName := filepath.Base(source)
+	return filepath.WalkDir(source, func(currPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(source, currPath)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() && info.Mode()&os.ModeSymlink == 0 {
+			log.Debugf("Skipping the file at path %s since it is not a regular file", currPath)
+			return nil
+		}
+		name := path.Join(baseName, filepath.ToSlash(relPath))
+		if info.IsDir() {
+			name += "/"
+		}
+		return addEntry(name, currPath, info)
+	})
+}
+
+func copyFileContents(w io.Writer, src string) error {
+	srcfile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcfile.Close()
+	_, err = io.Copy(w, srcfile)
+	return err
+}
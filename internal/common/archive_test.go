@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func createArchiveTestDir(t *testing.T) string {
+	source := filepath.Join(t.TempDir(), "myproject")
+	if err := os.MkdirAll(filepath.Join(source, "scripts"), 0755); err != nil {
+		t.Fatalf("Failed to create the test directory. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(source, "deploy.yaml"), []byte("kind: Deployment"), 0644); err != nil {
+		t.Fatalf("Failed to create the test file. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(source, "scripts", "deploy.sh"), []byte("kubectl apply -f ."), 0755); err != nil {
+		t.Fatalf("Failed to create the test file. Error: %q", err)
+	}
+	return source
+}
+
+func TestCopyPath(t *testing.T) {
+	source := createArchiveTestDir(t)
+	target := filepath.Join(t.TempDir(), "copy")
+	if err := CopyPath(target, source); err != nil {
+		t.Fatalf("Failed to copy the directory. Error: %q", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(target, "deploy.yaml"))
+	if err != nil || string(data) != "kind: Deployment" {
+		t.Fatalf("Failed to copy the contents of the file. Actual: %q Error: %q", string(data), err)
+	}
+	info, err := os.Stat(filepath.Join(target, "scripts", "deploy.sh"))
+	if err != nil {
+		t.Fatalf("Failed to copy the nested file. Error: %q", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Fatalf("Expected the permission of the executable to be preserved. Actual: %o", info.Mode().Perm())
+	}
+}
+
+func TestCreateArchive(t *testing.T) {
+	source := createArchiveTestDir(t)
+	want := []string{"myproject/", "myproject/deploy.yaml", "myproject/scripts/", "myproject/scripts/deploy.sh"}
+
+	t.Run("tar.gz archive", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "myproject.tar.gz")
+		if err := CreateArchive(target, source, TarGzArchiveFormat); err != nil {
+			t.Fatalf("Failed to create the archive. Error: %q", err)
+		}
+		archiveFile, err := os.Open(target)
+		if err != nil {
+			t.Fatalf("Failed to open the archive. Error: %q", err)
+		}
+		defer archiveFile.Close()
+		gr, err := gzip.NewReader(archiveFile)
+		if err != nil {
+			t.Fatalf("Failed to decompress the archive. Error: %q", err)
+		}
+		tr := tar.NewReader(gr)
+		names := []string{}
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read the archive. Error: %q", err)
+			}
+			names = append(names, hdr.Name)
+			if hdr.Name == "myproject/scripts/deploy.sh" && os.FileMode(hdr.Mode).Perm() != 0755 {
+				t.Fatalf("Expected the permission of the executable to be preserved. Actual: %o", hdr.Mode)
+			}
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Fatalf("Expected the archive to contain %v . Actual: %v", want, names)
+		}
+	})
+
+	t.Run("zip archive", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "myproject.zip")
+		if err := CreateArchive(target, source, ZipArchiveFormat); err != nil {
+			t.Fatalf("Failed to create the archive. Error: %q", err)
+		}
+		zr, err := zip.OpenReader(target)
+		if err != nil {
+			t.Fatalf("Failed to open the archive. Error: %q", err)
+		}
+		defer zr.Close()
+		names := []string{}
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Fatalf("Expected the archive to contain %v . Actual: %v", want, names)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		if err := CreateArchive(filepath.Join(t.TempDir(), "myproject.rar"), source, "rar"); err == nil {
+			t.Fatalf("Expected an error for an unsupported format")
+		}
+	})
+}
@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// CommandTimeout is the maximum time an external tool invocation is allowed to run. Zero or less disables the timeout.
+	CommandTimeout = DefaultCommandTimeout
+	// CommandMaxOutputSize is the maximum number of bytes captured from the output of an external tool. Zero or less disables the limit.
+	CommandMaxOutputSize = DefaultCommandMaxOutputSize
+	// CommandRetries is the number of times an external tool invocation which timed out or could not be started is retried
+	CommandRetries = DefaultCommandRetries
+)
+
+// CommandTimeoutError is returned when an external tool does not finish within its timeout
+type CommandTimeoutError struct {
+	Command    string
+	Timeout    time.Duration
+	LastOutput string
+}
+
+func (e *CommandTimeoutError) Error() string {
+	msg := fmt.Sprintf("the command [%s] did not finish within %s and was killed. The tool might be hung, waiting on an unresponsive daemon or on interactive input", e.Command, e.Timeout)
+	if e.LastOutput != "" {
+		msg += ". Last output:\n" + e.LastOutput
+	}
+	return msg
+}
+
+// limitedBuffer is an io.Writer that stores at most limit bytes and silently discards the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+	remaining := b.limit - b.buf.Len()
+	if remaining <= 0 {
+		b.truncated = b.truncated || len(p) > 0
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		b.buf.Write(p[:remaining])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// GetCommandContext returns a context that expires after CommandTimeout.
+// It can be used for tools that are invoked through a client library instead of a binary.
+func GetCommandContext() (context.Context, context.CancelFunc) {
+	return getCommandContext(CommandTimeout)
+}
+
+func getCommandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// RunCommand runs an external tool in the given directory and returns its standard output.
+// The invocation is subject to CommandTimeout, CommandMaxOutputSize and CommandRetries.
+func RunCommand(dir string, name string, args ...string) ([]byte, error) {
+	return runCommandWithRetries(dir, false, CommandTimeout, name, args)
+}
+
+// RunCommandCombinedOutput runs an external tool in the given directory and returns its combined standard output and standard error.
+// The invocation is subject to CommandTimeout, CommandMaxOutputSize and CommandRetries.
+func RunCommandCombinedOutput(dir string, name string, args ...string) ([]byte, error) {
+	return runCommandWithRetries(dir, true, CommandTimeout, name, args)
+}
+
+// RunCommandCombinedOutputWithTimeout is RunCommandCombinedOutput with a timeout other than CommandTimeout,
+// for the tools which wait for a long running operation, like helm --wait. Zero or less disables the timeout.
+func RunCommandCombinedOutputWithTimeout(timeout time.Duration, dir string, name string, args ...string) ([]byte, error) {
+	return runCommandWithRetries(dir, true, timeout, name, args)
+}
+
+func runCommandWithRetries(dir string, combined bool, timeout time.Duration, name string, args []string) ([]byte, error) {
+	var output []byte
+	var err error
+	for attempt := 0; attempt <= CommandRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(attempt) * time.Second
+			log.Debugf("Retrying the command [%s] in %s (attempt %d of %d). Previous error: %q", getCommandString(name, args), backoff, attempt, CommandRetries, err)
+			time.Sleep(backoff)
+		}
+		output, err = runCommandOnce(dir, combined, timeout, name, args)
+		if err == nil || !isRetriableCommandError(err) {
+			return output, err
+		}
+	}
+	return output, err
+}
+
+// transientStartErrors are the errors starting a command which may not happen again, like running out of processes or file descriptors
+var transientStartErrors = []error{syscall.EAGAIN, syscall.EINTR, syscall.ENOMEM, syscall.EMFILE, syscall.ENFILE, syscall.ETXTBSY}
+
+// isRetriableCommandError returns true if the command timed out or could not be started for a transient reason.
+// A command which exited with a non zero status is not retried, since it would fail the same way again.
+func isRetriableCommandError(err error) bool {
+	var timeoutErr *CommandTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false
+	}
+	for _, transientErr := range transientStartErrors {
+		if errors.Is(err, transientErr) {
+			return true
+		}
+	}
+	return false
+}
+
+func runCommandOnce(dir string, combined bool, timeout time.Duration, name string, args []string) ([]byte, error) {
+	ctx, cancel := getCommandContext(timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	stdout := &limitedBuffer{limit: CommandMaxOutputSize}
+	stderr := &limitedBuffer{limit: CommandMaxOutputSize}
+	cmd.Stdout = stdout
+	if combined {
+		cmd.Stderr = stdout
+	} else {
+		cmd.Stderr = stderr
+	}
+	cmdStr := getCommandString(name, args)
+	log.Debugf("Executing the command [%s] in the directory %s", cmdStr, dir)
+	start := time.Now()
+	err := cmd.Run()
+	if stdout.truncated || stderr.truncated {
+		log.Warnf("The output of the command [%s] exceeded %d bytes and was truncated.", cmdStr, CommandMaxOutputSize)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		timeoutErr := &CommandTimeoutError{Command: cmdStr, Timeout: time.Since(start).Round(time.Second), LastOutput: getLastLines(stdout.buf.String()+stderr.buf.String(), 10)}
+		log.Warn(timeoutErr)
+		return stdout.buf.Bytes(), timeoutErr
+	}
+	if err != nil && stderr.buf.Len() > 0 {
+		log.Debugf("The command [%s] failed. Stderr:\n%s", cmdStr, stderr.buf.String())
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitErr.Stderr = stderr.buf.Bytes()
+		}
+	}
+	return stdout.buf.Bytes(), err
+}
+
+func getCommandString(name string, args []string) string {
+	return strings.TrimSpace(name + " " + strings.Join(args, " "))
+}
+
+func getLastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	defer func(timeout time.Duration, maxOutput, retries int) {
+		common.CommandTimeout = timeout
+		common.CommandMaxOutputSize = maxOutput
+		common.CommandRetries = retries
+	}(common.CommandTimeout, common.CommandMaxOutputSize, common.CommandRetries)
+
+	t.Run("normal use case", func(t *testing.T) {
+		output, err := common.RunCommand("", "sh", "-c", "echo hello")
+		if err != nil {
+			t.Fatalf("Failed to run the command. Error: %q", err)
+		}
+		if string(output) != "hello\n" {
+			t.Fatalf("Expected the output to be %q. Actual: %q", "hello\n", string(output))
+		}
+	})
+
+	t.Run("combined output contains stderr", func(t *testing.T) {
+		output, err := common.RunCommandCombinedOutput("", "sh", "-c", "echo oops 1>&2")
+		if err != nil {
+			t.Fatalf("Failed to run the command. Error: %q", err)
+		}
+		if string(output) != "oops\n" {
+			t.Fatalf("Expected the output to be %q. Actual: %q", "oops\n", string(output))
+		}
+	})
+
+	t.Run("hung command is killed after the timeout", func(t *testing.T) {
+		common.CommandTimeout = 500 * time.Millisecond
+		defer func() { common.CommandTimeout = common.DefaultCommandTimeout }()
+		start := time.Now()
+		_, err := common.RunCommand("", "sleep", "10")
+		if err == nil {
+			t.Fatal("Should have failed since the command runs longer than the timeout.")
+		}
+		var timeoutErr *common.CommandTimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("Expected a timeout error. Actual: %T %q", err, err)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("The command was not killed in time. Took %s", time.Since(start))
+		}
+	})
+
+	t.Run("command with its own timeout is not killed after the default timeout", func(t *testing.T) {
+		common.CommandTimeout = 200 * time.Millisecond
+		defer func() { common.CommandTimeout = common.DefaultCommandTimeout }()
+		output, err := common.RunCommandCombinedOutputWithTimeout(5*time.Second, "", "sh", "-c", "sleep 1; echo done")
+		if err != nil {
+			t.Fatalf("Failed to run the command. Error: %q", err)
+		}
+		if string(output) != "done\n" {
+			t.Fatalf("Expected the output to be %q. Actual: %q", "done\n", string(output))
+		}
+	})
+
+	t.Run("output is truncated to the limit", func(t *testing.T) {
+		common.CommandMaxOutputSize = 4
+		defer func() { common.CommandMaxOutputSize = common.DefaultCommandMaxOutputSize }()
+		output, err := common.RunCommand("", "sh", "-c", "echo 0123456789")
+		if err != nil {
+			t.Fatalf("Failed to run the command. Error: %q", err)
+		}
+		if string(output) != "0123" {
+			t.Fatalf("Expected the output to be %q. Actual: %q", "0123", string(output))
+		}
+	})
+
+	t.Run("missing binary is not retried", func(t *testing.T) {
+		common.CommandRetries = 3
+		defer func() { common.CommandRetries = common.DefaultCommandRetries }()
+		start := time.Now()
+		if _, err := common.RunCommand("", "this-binary-does-not-exist-m2k"); err == nil {
+			t.Fatal("Should have failed since the binary does not exist.")
+		}
+		if time.Since(start) > time.Second {
+			t.Fatalf("The missing binary should not have been retried. Took %s", time.Since(start))
+		}
+	})
+
+	t.Run("non zero exit is returned without retrying", func(t *testing.T) {
+		common.CommandRetries = 3
+		defer func() { common.CommandRetries = common.DefaultCommandRetries }()
+		runsPath := filepath.Join(t.TempDir(), "runs")
+		_, err := common.RunCommand("", "sh", "-c", "echo run >> "+runsPath+"; exit 3")
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+			t.Fatalf("Expected an exit error with the exit code 3. Actual: %T %q", err, err)
+		}
+		if runs := getCommandRuns(t, runsPath); runs != 1 {
+			t.Fatalf("Expected the failed command to be run once. Actual: %d", runs)
+		}
+	})
+
+	t.Run("timed out command is retried", func(t *testing.T) {
+		common.CommandRetries = 1
+		common.CommandTimeout = 300 * time.Millisecond
+		defer func() {
+			common.CommandRetries = common.DefaultCommandRetries
+			common.CommandTimeout = common.DefaultCommandTimeout
+		}()
+		runsPath := filepath.Join(t.TempDir(), "runs")
+		_, err := common.RunCommand("", "sh", "-c", "echo run >> "+runsPath+"; exec sleep 10")
+		var timeoutErr *common.CommandTimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("Expected a timeout error. Actual: %T %q", err, err)
+		}
+		if runs := getCommandRuns(t, runsPath); runs != 2 {
+			t.Fatalf("Expected the timed out command to be run twice. Actual: %d", runs)
+		}
+	})
+}
+
+func getCommandRuns(t *testing.T, runsPath string) int {
+	runs, err := ioutil.ReadFile(runsPath)
+	if err != nil {
+		t.Fatalf("Failed to read the runs of the command at path %s . Error: %q", runsPath, err)
+	}
+	return strings.Count(string(runs), "run\n")
+}
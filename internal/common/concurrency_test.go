@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	t.Run("every index is run once and the results are in order", func(t *testing.T) {
+		results := make([]int, 100)
+		common.RunConcurrently(len(results), 8, func(i int) {
+			results[i] += i * i
+		})
+		for i, result := range results {
+			if result != i*i {
+				t.Fatalf("Expected the result %d at the index %d. Actual: %d", i*i, i, result)
+			}
+		}
+	})
+
+	t.Run("at most the number of workers run at the same time", func(t *testing.T) {
+		var running, maxRunning int32
+		common.RunConcurrently(50, 3, func(i int) {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			for j := 0; j < 1000; j++ {
+				_ = j * i
+			}
+			atomic.AddInt32(&running, -1)
+		})
+		if maxRunning > 3 {
+			t.Fatalf("Expected at most 3 calls running at the same time. Actual: %d", maxRunning)
+		}
+	})
+
+	t.Run("no calls", func(t *testing.T) {
+		common.RunConcurrently(0, 4, func(i int) {
+			t.Fatalf("Expected no calls. Actual: a call with the index %d", i)
+		})
+	})
+}
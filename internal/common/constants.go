@@ -17,18 +17,27 @@ limitations under the License.
 package common
 
 import (
-	"os"
 	"path/filepath"
+	"runtime"
+	"time"
 
 	"github.com/konveyor/move2kube/types"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
-	// DefaultProjectName represents the short app name
-	DefaultProjectName string = "myproject"
 	// DefaultPlanFile defines default name for plan file
 	DefaultPlanFile string = types.AppNameShort + ".plan"
+	// YamlOutputFormat writes the plan and the collected metadata as yaml
+	YamlOutputFormat = "yaml"
+	// JSONOutputFormat writes the plan and the collected metadata as json, using the same field names as yaml
+	JSONOutputFormat = "json"
+	// WarnSecretScanMode logs the credentials found in the sources copied into the build contexts
+	WarnSecretScanMode = "warn"
+	// BlockSecretScanMode does not copy the sources into the build contexts if credentials are found in them
+	BlockSecretScanMode = "block"
+	// OffSecretScanMode disables the scan of the sources for credentials
+	OffSecretScanMode = "off"
 	// TempDirPrefix defines the prefix of the temp directory
 	TempDirPrefix string = types.AppNameShort + "-"
 	// AssetsDir defines the dir of the assets temp directory
@@ -47,32 +56,62 @@ const (
 	VolumePrefix string = "vol"
 	// DefaultStorageClassName defines the default storage class to be used
 	DefaultStorageClassName string = "default"
-	// DefaultDirectoryPermission defines the default permission used when a directory is created
-	DefaultDirectoryPermission os.FileMode = 0755
-	// DefaultExecutablePermission defines the default permission used when an executable file is created
-	DefaultExecutablePermission os.FileMode = 0744
-	// DefaultFilePermission defines the default permission used when a non-executable file is created
-	DefaultFilePermission os.FileMode = 0644
+	// DefaultCPURequest is the cpu request used for containers when the target cluster requires resource requests
+	DefaultCPURequest string = "250m"
+	// DefaultMemoryRequest is the memory request used for containers when the target cluster requires resource requests
+	DefaultMemoryRequest string = "512Mi"
 	// DefaultRegistryURL points to the default registry url that will be used
 	DefaultRegistryURL string = "quay.io"
+	// DefaultDockerHubRegistry is the registry of the images that do not specify a registry
+	DefaultDockerHubRegistry string = "docker.io"
 	// ImagePullSecretPrefix is the prefix that will be prepended to pull secret name
 	ImagePullSecretPrefix string = "imagepullsecret"
 	// QACacheFile defines the location of the QA cache file
 	QACacheFile string = types.AppNameShort + "qacache.yaml"
+	// QADeferredFile defines the location of the file collecting the questions whose answers were deferred
+	QADeferredFile string = types.AppNameShort + "qadeferred.yaml"
 	// ConfigFile defines the location of the config file
 	ConfigFile string = types.AppNameShort + "config.yaml"
-	// DefaultClusterType defines the default cluster type chosen by plan
-	DefaultClusterType string = "Kubernetes"
-	// IgnoreFilename is the name of the file containing the ignore rules and exceptions
-	IgnoreFilename string = "." + types.AppNameShort + "ignore"
+	// ManifestFile defines the location of the file containing the checksums of the generated artifacts
+	ManifestFile string = types.AppNameShort + "manifest.yaml"
+	// ProjectConfigFile defines the location of the project config file in the source directory
+	ProjectConfigFile string = types.AppNameShort + "project.yaml"
+	// CustomizationsDir defines the location of the customizations in the source directory
+	CustomizationsDir string = types.AppNameShort + "customizations"
+	// CustomTemplatesDir defines the directory in the customizations containing the templates rendered into the output directory
+	CustomTemplatesDir string = "templates"
+	// ReportFile defines the location of the file summarizing the generated artifacts and the next steps
+	ReportFile string = types.AppNameShort + "report.md"
+	// ProgressFile defines the location of the file recording the services whose artifacts are committed to the output directory
+	ProgressFile string = types.AppNameShort + "progress.yaml"
+	// HistoryDir defines the directory in the output directory containing the snapshots of the resources generated by each translation
+	HistoryDir string = types.AppNameShort + "history"
+	// StagingDir defines the directory in the output directory where the artifacts of a service are staged before being committed
+	StagingDir string = "." + types.AppNameShort + "staging"
 	// ExposeSelector tag is used to annotate services that are externally exposed
 	ExposeSelector string = types.GroupName + "/service.expose"
+	// ProtocolAnnotation is used to label the services, like in docker compose files, with the protocol they serve (grpc or websocket)
+	ProtocolAnnotation string = types.GroupName + "/service.protocol"
 	// AnnotationLabelValue represents the value when an annotation is valid
 	AnnotationLabelValue string = "true"
 	// DefaultServicePort is the default port that will be added to a service.
 	DefaultServicePort = 8080
 	// TODOAnnotation is used to annotate with TODO tasks
 	TODOAnnotation string = types.GroupName + "/todo."
+	// ConfigHashAnnotation is used to annotate the services with the hash of the config maps they use, so that they are rolled out when the config changes
+	ConfigHashAnnotation string = types.GroupName + "/config.hash"
+	// DaprEnabledAnnotation is used to inject the Dapr sidecar into the pods of a service
+	DaprEnabledAnnotation string = "dapr.io/enabled"
+	// DaprAppIDAnnotation is used to set the id of the app, with which other apps invoke it through Dapr
+	DaprAppIDAnnotation string = "dapr.io/app-id"
+	// DaprAppPortAnnotation is used to set the port on which the Dapr sidecar calls the app
+	DaprAppPortAnnotation string = "dapr.io/app-port"
+	// DefaultCommandTimeout is the default maximum time an external tool (pack, docker, cf, kubectl, etc.) is allowed to run
+	DefaultCommandTimeout time.Duration = 10 * time.Minute
+	// DefaultCommandMaxOutputSize is the default maximum number of bytes captured from the output of an external tool
+	DefaultCommandMaxOutputSize int = 64 * 1024 * 1024
+	// DefaultCommandRetries is the default number of times an external tool invocation which timed out or could not be started is retried
+	DefaultCommandRetries int = 0
 )
 
 const (
@@ -116,14 +155,48 @@ const (
 	ConfigRepoKeyPathsKey = ConfigRepoKeysKey + d + "paths"
 	//ConfigSourceTypesKey represents source type Key
 	ConfigSourceTypesKey = ConfigSourcesKey + d + "types"
+	//ConfigK8sSourceKey represents the Key of the kubernetes resources used as source
+	ConfigK8sSourceKey = ConfigSourcesKey + d + "kubernetes"
+	//ConfigK8sSourceKindsKey represents the Key for selecting the kinds of the kubernetes resources that are translated
+	ConfigK8sSourceKindsKey = ConfigK8sSourceKey + d + "kinds"
+	//ConfigK8sSourceNamespacesKey represents the Key for selecting the namespaces of the kubernetes resources that are translated
+	ConfigK8sSourceNamespacesKey = ConfigK8sSourceKey + d + "namespaces"
+	//ConfigK8sSourceScrubKey represents the Key of the rules which scrub the annotations, labels and finalizers of the kubernetes resources
+	ConfigK8sSourceScrubKey = ConfigK8sSourceKey + d + "scrub"
+	//ConfigK8sSourceScrubRulesKey represents the Key for selecting the scrub rules that are applied
+	ConfigK8sSourceScrubRulesKey = ConfigK8sSourceScrubKey + d + "rules"
+	//ConfigK8sSourceTemplatesKey represents the Key of the parameters of the OpenShift templates that are processed
+	ConfigK8sSourceTemplatesKey = ConfigK8sSourceKey + d + "templates"
 	//ConfigIngressKey represents Ingress Key
 	ConfigIngressKey = ConfigTargetKey + d + "ingress"
 	//ConfigIngressHostKey represents Ingress host Key
 	ConfigIngressHostKey = ConfigIngressKey + d + "host"
 	//ConfigIngressTLSKey represents ingress tls Key
 	ConfigIngressTLSKey = ConfigIngressKey + d + "tls"
+	//ConfigIngressControllerKey represents the ingress controller Key
+	ConfigIngressControllerKey = ConfigIngressKey + d + "controller"
+	//ConfigIngressTimeoutKey represents the ingress timeout Key
+	ConfigIngressTimeoutKey = ConfigIngressKey + d + "timeout"
+	//ConfigIngressBodySizeKey represents the ingress maximum body size Key
+	ConfigIngressBodySizeKey = ConfigIngressKey + d + "bodysize"
+	//ConfigGatewayKey represents Gateway API Key
+	ConfigGatewayKey = ConfigTargetKey + d + "gateway"
+	//ConfigGatewayEnableKey represents the key for using the Gateway API instead of ingress
+	ConfigGatewayEnableKey = ConfigGatewayKey + d + "enable"
+	//ConfigGatewayClassNameKey represents the gateway class name Key
+	ConfigGatewayClassNameKey = ConfigGatewayKey + d + "classname"
+	//ConfigGatewayModeKey represents the key for choosing between shared and per-service gateways
+	ConfigGatewayModeKey = ConfigGatewayKey + d + "mode"
+	//ConfigRolloutsKey represents Argo Rollouts Key
+	ConfigRolloutsKey = ConfigTargetKey + d + "rollouts"
+	//ConfigRolloutsStrategyKey represents the deployment strategy Key
+	ConfigRolloutsStrategyKey = ConfigRolloutsKey + d + "strategy"
+	//ConfigRolloutsMetricsPathKey represents the metrics path Key used by the analysis templates
+	ConfigRolloutsMetricsPathKey = ConfigRolloutsKey + d + "metricspath"
 	//ConfigTargetClusterTypeKey represents target cluster type key
 	ConfigTargetClusterTypeKey = ConfigTargetKey + d + "clustertype"
+	//ConfigTargetNamespacesKey represents the Key for mapping the namespaces of the kubernetes resources to the target namespaces
+	ConfigTargetNamespacesKey = ConfigTargetKey + d + "namespaces"
 	//ConfigImageRegistryKey represents image registry Key
 	ConfigImageRegistryKey = ConfigTargetKey + d + "imageregistry"
 	//ConfigImageRegistryURLKey represents image registry url Key
@@ -138,16 +211,84 @@ const (
 	ConfigImageRegistryUserNameKey = ConfigImageRegistryKey + d + "username"
 	//ConfigImageRegistryPasswordKey represents image registry login Password Key
 	ConfigImageRegistryPasswordKey = ConfigImageRegistryKey + d + "password"
-	//ConfigStoragesPVCForHostPathKey represents key for PVC for Host Path
-	ConfigStoragesPVCForHostPathKey = ConfigStoragesKey + d + "pvcforhostpath"
+	//ConfigImageRegistryRewriteKey represents the key for rewriting the images of the kubernetes artifacts to the image registry
+	ConfigImageRegistryRewriteKey = ConfigImageRegistryKey + d + "rewrite"
+	//ConfigImageRegistryPreserveDigestsKey represents the key for keeping the digests of the rewritten images
+	ConfigImageRegistryPreserveDigestsKey = ConfigImageRegistryKey + d + "preservedigests"
 	//ConfigStoragesPerClaimStorageClassKey represents key for having different storage class for claim
 	ConfigStoragesPerClaimStorageClassKey = ConfigStoragesKey + d + "perclaimstorageclass"
+	//ConfigStoragesMigrateKey represents the key for selecting the persistent volume claims whose data is moved to the target cluster
+	ConfigStoragesMigrateKey = ConfigStoragesKey + d + "migrate"
+	//ConfigStoragesImmutableConfigMapsKey represents the key for generating immutable config maps
+	ConfigStoragesImmutableConfigMapsKey = ConfigStoragesKey + d + "immutableconfigmaps"
 	//ConfigServicesNamesKey represents Storages Key
 	ConfigServicesNamesKey = ConfigServicesKey + d + Special + d + "enable"
 	//ConfigContainerizationTypesKey represents source type Key
 	ConfigContainerizationTypesKey = ConfigContainerizationKeySegment + d + "types"
 	//ConfigServicesExposeKey represents Services Expose Key
 	ConfigServicesExposeKey = ConfigServicesKey + d + Special + d + "expose"
+	//ConfigServicesBuildStepsKey represents the Key for selecting the services whose image is built from multiple build contexts
+	ConfigServicesBuildStepsKey = ConfigServicesKey + d + Special + d + "buildsteps"
+	//ConfigBuildStepsKeySegment represents the per service build steps Key segment
+	ConfigBuildStepsKeySegment = "buildsteps"
+	//ConfigStaticSiteKeySegment represents the per service static site Key segment
+	ConfigStaticSiteKeySegment = "staticsite"
+	//ConfigProtocolKeySegment represents the per service protocol Key segment
+	ConfigProtocolKeySegment = "protocol"
+	//ConfigPortExposureKeySegment represents the per service non HTTP port exposure Key segment
+	ConfigPortExposureKeySegment = "portexposure"
+	//ConfigSessionsKeySegment represents the per service session handling Key segment
+	ConfigSessionsKeySegment = "sessions"
+	//ConfigStoragesHostPathKeySegment represents the per host path Key segment for choosing how the host path is translated
+	ConfigStoragesHostPathKeySegment = "hostpath"
+	//ConfigStoragesContentPathKeySegment represents the per storage Key segment for the path of the file with the content of an external secret or config
+	ConfigStoragesContentPathKeySegment = "contentpath"
+	//ConfigEnvKeySegment represents the per service env vars Key segment
+	ConfigEnvKeySegment = "env"
+	//ConfigSecretEnvKeySegment represents the per service Key segment of the env vars stored in a secret
+	ConfigSecretEnvKeySegment = "secretenv"
+	//ConfigOnErrorKeySegment represents the per service Key segment of the handling of the failed translation steps
+	ConfigOnErrorKeySegment = "onerror"
+	//ConfigSessionStoreKey represents the session store Key
+	ConfigSessionStoreKey = ConfigTargetKey + d + "sessionstore"
+	//ConfigSessionStoreImageKey represents the session store image Key
+	ConfigSessionStoreImageKey = ConfigSessionStoreKey + d + "image"
+	//ConfigSchedulingKey represents the Key of the priority classes and the QoS of the services
+	ConfigSchedulingKey = ConfigTargetKey + d + "scheduling"
+	//ConfigSchedulingEnableKey represents the Key for enabling the priority classes and the QoS of the services
+	ConfigSchedulingEnableKey = ConfigSchedulingKey + d + "enable"
+	//ConfigTierKeySegment represents the per service Key segment for selecting the tier of the service
+	ConfigTierKeySegment = "tier"
+	//ConfigSchedulingPriorityClassKeySegment represents the per tier Key segment of the priority class
+	ConfigSchedulingPriorityClassKeySegment = "priorityclass"
+	//ConfigSchedulingQoSKeySegment represents the per tier Key segment of the QoS class
+	ConfigSchedulingQoSKeySegment = "qos"
+	//ConfigSchedulingCPUKeySegment represents the per tier Key segment of the CPU request of the containers
+	ConfigSchedulingCPUKeySegment = "cpu"
+	//ConfigSchedulingMemoryKeySegment represents the per tier Key segment of the memory request of the containers
+	ConfigSchedulingMemoryKeySegment = "memory"
+	//ConfigTerminationGracePeriodKeySegment represents the per service Key segment of the termination grace period
+	ConfigTerminationGracePeriodKeySegment = "terminationgraceperiod"
+	//ConfigPreStopSleepKeySegment represents the per service Key segment for adding a preStop hook which waits before stopping the containers
+	ConfigPreStopSleepKeySegment = "prestopsleep"
+	//ConfigCronJobsKeySegment represents the per service Key segment for converting the crontab entries of the service to cron jobs
+	ConfigCronJobsKeySegment = "cronjobs"
+	//ConfigProcessesKeySegment represents the per service Key segment for splitting the processes run by a process manager
+	ConfigProcessesKeySegment = "processes"
+	//ConfigTargetTimezoneKey represents the Key of the timezone of the services which use the timezone of the host
+	ConfigTargetTimezoneKey = ConfigTargetKey + d + "timezone"
+	//ConfigTargetLocaleKey represents the Key of the locale of the services which use the locale of the host
+	ConfigTargetLocaleKey = ConfigTargetKey + d + "locale"
+	//ConfigDevExperienceToolsKey represents the Key of the tools for which the configs of the developer workflow are generated
+	ConfigDevExperienceToolsKey = ConfigTargetKey + d + "devexperience" + d + "tools"
+	//ConfigDaprKeySegment represents the per service Key segment for injecting the Dapr sidecar
+	ConfigDaprKeySegment = "dapr"
+	//ConfigDaprComponentsKey represents the Key of the Dapr components used by the services
+	ConfigDaprComponentsKey = ConfigTargetKey + d + "dapr" + d + "components"
+	//ConfigDaprBackendKeySegment represents the per Dapr component Key segment of the backing service
+	ConfigDaprBackendKeySegment = "backend"
+	//ConfigDaprHostKeySegment represents the per Dapr component Key segment of the host of the external backing service
+	ConfigDaprHostKeySegment = "host"
 )
 
 var (
@@ -155,8 +296,38 @@ var (
 	DefaultPVCSize, _ = resource.ParseQuantity("100Mi")
 	// IgnoreEnvironment indicates whether to ignore the current environment or not
 	IgnoreEnvironment = false
+	// Offline indicates whether the commands that need internet access should be disabled
+	Offline = false
+	// K8sIncludeKinds are the kinds of the kubernetes resources that are translated. If empty, the kinds are asked.
+	K8sIncludeKinds = []string{}
+	// K8sExcludeKinds are the kinds of the kubernetes resources that are not translated
+	K8sExcludeKinds = []string{}
+	// K8sIncludeNamespaces are the namespaces, or the glob patterns of the namespaces, of the kubernetes resources that are translated
+	K8sIncludeNamespaces = []string{}
+	// K8sExcludeNamespaces are the namespaces, or the glob patterns of the namespaces, of the kubernetes resources that are not translated
+	K8sExcludeNamespaces = []string{}
+	// K8sScrubRules are the names of the scrub rules applied to the kubernetes resources. If empty, the rules are asked.
+	K8sScrubRules = []string{}
+	// K8sScrubAllow are the keys, or the glob patterns of the keys, of the annotations, labels and finalizers that are never scrubbed
+	K8sScrubAllow = []string{}
+	// K8sScrubDeny are the keys, or the glob patterns of the keys, of the annotations, labels and finalizers that are always scrubbed
+	K8sScrubDeny = []string{}
+	// PlanWorkers is the maximum number of translators and directories whose services are detected at the same time during planning
+	PlanWorkers = runtime.NumCPU()
+	// BuildImages indicates whether the new images are built locally after the artifacts are generated
+	BuildImages = false
+	// AnalysisPaths are the paths of the results of the rule engines, like the output of the Konveyor analyzer, imported into the plan
+	AnalysisPaths = []string{}
+	// LicenseScan indicates whether the licenses of the dependencies of the services are summarized in the report
+	LicenseScan = false
+	// SecretScanMode is how the credentials found in the sources copied into the build contexts are handled
+	SecretScanMode = WarnSecretScanMode
+	// Move2KubeFileExts are the extensions of the files, like the collected metadata, which can be written as yaml or json
+	Move2KubeFileExts = []string{".yml", ".yaml", ".json"}
 	// TempPath defines where all app data get stored during execution
 	TempPath = TempDirPrefix + "temp"
 	// AssetsPath defines where all assets get stored during execution
 	AssetsPath = filepath.Join(TempPath, AssetsDir)
+	// ProjectTemplateData is the template data model of the project, which all the templates can access using the project function
+	ProjectTemplateData interface{}
 )
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultsFileEnvVar is the environment variable that contains the path of the defaults file
+	DefaultsFileEnvVar = "M2K_DEFAULTS_FILE"
+	// ProjectNameEnvVar overrides the default project name
+	ProjectNameEnvVar = "M2K_PROJECT_NAME"
+	// ClusterTypeEnvVar overrides the default cluster type
+	ClusterTypeEnvVar = "M2K_CLUSTER_TYPE"
+	// DirectoryPermissionEnvVar overrides the permission of the created directories. Eg: 0755
+	DirectoryPermissionEnvVar = "M2K_DIRECTORY_PERMISSION"
+	// ExecutablePermissionEnvVar overrides the permission of the created executable files. Eg: 0744
+	ExecutablePermissionEnvVar = "M2K_EXECUTABLE_PERMISSION"
+	// FilePermissionEnvVar overrides the permission of the created non-executable files. Eg: 0644
+	FilePermissionEnvVar = "M2K_FILE_PERMISSION"
+	// IgnoreFilenameEnvVar overrides the name of the ignore files
+	IgnoreFilenameEnvVar = "M2K_IGNORE_FILENAME"
+	// IgnoreDirectoriesEnvVar overrides the directories that are always ignored. It is a comma separated list.
+	IgnoreDirectoriesEnvVar = "M2K_IGNORE_DIRECTORIES"
+)
+
+var (
+	// DefaultProjectName represents the short app name
+	DefaultProjectName = "myproject"
+	// DefaultClusterType defines the default cluster type chosen by plan
+	DefaultClusterType = "Kubernetes"
+	// DefaultDirectoryPermission defines the default permission used when a directory is created
+	DefaultDirectoryPermission os.FileMode = 0755
+	// DefaultExecutablePermission defines the default permission used when an executable file is created
+	DefaultExecutablePermission os.FileMode = 0744
+	// DefaultFilePermission defines the default permission used when a non-executable file is created
+	DefaultFilePermission os.FileMode = 0644
+	// IgnoreFilename is the name of the file containing the ignore rules and exceptions
+	IgnoreFilename = "." + types.AppNameShort + "ignore"
+	// IgnoreDirectories are the names of the directories that are always ignored while looking for services
+	IgnoreDirectories = []string{}
+)
+
+// Defaults are the defaults that an organization can change using a defaults file, environment variables or the library API.
+// Empty fields keep the current value.
+type Defaults struct {
+	ProjectName          string   `yaml:"projectName,omitempty"`
+	ClusterType          string   `yaml:"clusterType,omitempty"`
+	DirectoryPermission  string   `yaml:"directoryPermission,omitempty"`
+	ExecutablePermission string   `yaml:"executablePermission,omitempty"`
+	FilePermission       string   `yaml:"filePermission,omitempty"`
+	IgnoreFilename       string   `yaml:"ignoreFilename,omitempty"`
+	IgnoreDirectories    []string `yaml:"ignoreDirectories,omitempty"`
+}
+
+// GetDefaults returns the defaults currently in use
+func GetDefaults() Defaults {
+	return Defaults{
+		ProjectName:          DefaultProjectName,
+		ClusterType:          DefaultClusterType,
+		DirectoryPermission:  formatPermission(DefaultDirectoryPermission),
+		ExecutablePermission: formatPermission(DefaultExecutablePermission),
+		FilePermission:       formatPermission(DefaultFilePermission),
+		IgnoreFilename:       IgnoreFilename,
+		IgnoreDirectories:    append([]string{}, IgnoreDirectories...),
+	}
+}
+
+// SetDefaults changes the defaults. The permissions are octal strings. Eg: 0644
+// Nothing is changed if any of the values is invalid.
+func SetDefaults(defaults Defaults) error {
+	directoryPermission, err := parsePermission(defaults.DirectoryPermission, DefaultDirectoryPermission)
+	if err != nil {
+		return fmt.Errorf("invalid directory permission %s . Error: %q", defaults.DirectoryPermission, err)
+	}
+	executablePermission, err := parsePermission(defaults.ExecutablePermission, DefaultExecutablePermission)
+	if err != nil {
+		return fmt.Errorf("invalid executable permission %s . Error: %q", defaults.ExecutablePermission, err)
+	}
+	filePermission, err := parsePermission(defaults.FilePermission, DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("invalid file permission %s . Error: %q", defaults.FilePermission, err)
+	}
+	if defaults.ProjectName != "" {
+		DefaultProjectName = defaults.ProjectName
+	}
+	if defaults.ClusterType != "" {
+		DefaultClusterType = defaults.ClusterType
+	}
+	if defaults.IgnoreFilename != "" {
+		IgnoreFilename = defaults.IgnoreFilename
+	}
+	if defaults.IgnoreDirectories != nil {
+		IgnoreDirectories = defaults.IgnoreDirectories
+	}
+	DefaultDirectoryPermission = directoryPermission
+	DefaultExecutablePermission = executablePermission
+	DefaultFilePermission = filePermission
+	return nil
+}
+
+// LoadDefaults changes the defaults using the defaults file at the given path, if any, and then the environment variables
+func LoadDefaults(path string) error {
+	if path != "" {
+		defaults := Defaults{}
+		if err := ReadYaml(path, &defaults); err != nil {
+			log.Errorf("Failed to read the defaults file at path %s . Error: %q", path, err)
+			return err
+		}
+		if err := SetDefaults(defaults); err != nil {
+			log.Errorf("Failed to use the defaults in the file at path %s . Error: %q", path, err)
+			return err
+		}
+	}
+	if err := SetDefaults(getDefaultsFromEnv()); err != nil {
+		log.Errorf("Failed to use the defaults in the environment variables. Error: %q", err)
+		return err
+	}
+	return nil
+}
+
+func getDefaultsFromEnv() Defaults {
+	defaults := Defaults{
+		ProjectName:          os.Getenv(ProjectNameEnvVar),
+		ClusterType:          os.Getenv(ClusterTypeEnvVar),
+		DirectoryPermission:  os.Getenv(DirectoryPermissionEnvVar),
+		ExecutablePermission: os.Getenv(ExecutablePermissionEnvVar),
+		FilePermission:       os.Getenv(FilePermissionEnvVar),
+		IgnoreFilename:       os.Getenv(IgnoreFilenameEnvVar),
+	}
+	if ignoreDirectories, ok := os.LookupEnv(IgnoreDirectoriesEnvVar); ok {
+		defaults.IgnoreDirectories = []string{}
+		for _, dir := range strings.Split(ignoreDirectories, ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				defaults.IgnoreDirectories = append(defaults.IgnoreDirectories, dir)
+			}
+		}
+	}
+	return defaults
+}
+
+func parsePermission(permission string, current os.FileMode) (os.FileMode, error) {
+	if permission == "" {
+		return current, nil
+	}
+	mode, err := strconv.ParseUint(permission, 8, 32)
+	if err != nil {
+		return current, err
+	}
+	if os.FileMode(mode)&^os.ModePerm != 0 {
+		return current, fmt.Errorf("only the permission bits can be set")
+	}
+	return os.FileMode(mode), nil
+}
+
+func formatPermission(permission os.FileMode) string {
+	return fmt.Sprintf("%04o", uint32(permission))
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	original := common.GetDefaults()
+	defer func() {
+		if err := common.SetDefaults(original); err != nil {
+			t.Fatalf("Failed to restore the defaults. Error: %q", err)
+		}
+	}()
+
+	t.Run("defaults file with environment overrides", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "defaults.yaml")
+		content := "projectName: acme\nclusterType: IBM-Openshift\nfilePermission: \"0640\"\nignoreDirectories:\n  - node_modules\n"
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write the defaults file. Error: %q", err)
+		}
+		os.Setenv(common.ProjectNameEnvVar, "fromenv")
+		defer os.Unsetenv(common.ProjectNameEnvVar)
+		os.Setenv(common.IgnoreDirectoriesEnvVar, "node_modules, .git")
+		defer os.Unsetenv(common.IgnoreDirectoriesEnvVar)
+
+		if err := common.LoadDefaults(path); err != nil {
+			t.Fatalf("Failed to load the defaults. Error: %q", err)
+		}
+		if common.DefaultProjectName != "fromenv" {
+			t.Fatalf("The environment should override the defaults file. Actual project name: %s", common.DefaultProjectName)
+		}
+		if common.DefaultClusterType != "IBM-Openshift" {
+			t.Fatalf("Failed to load the cluster type. Actual: %s", common.DefaultClusterType)
+		}
+		if common.DefaultFilePermission != 0640 || common.GetDefaults().DirectoryPermission != original.DirectoryPermission {
+			t.Fatalf("Failed to load the permissions. Actual: file %o directory %o", common.DefaultFilePermission, common.DefaultDirectoryPermission)
+		}
+		if want := []string{"node_modules", ".git"}; !reflect.DeepEqual(common.IgnoreDirectories, want) {
+			t.Fatalf("Failed to load the ignore directories. Expected: %v Actual: %v", want, common.IgnoreDirectories)
+		}
+	})
+
+	t.Run("invalid permission does not change anything", func(t *testing.T) {
+		current := common.GetDefaults()
+		if err := common.SetDefaults(common.Defaults{ProjectName: "other", FilePermission: "rw-r--r--"}); err == nil {
+			t.Fatal("Should have failed since the file permission is not an octal number.")
+		}
+		if !reflect.DeepEqual(common.GetDefaults(), current) {
+			t.Fatalf("The defaults should not have changed. Expected: %+v Actual: %+v", current, common.GetDefaults())
+		}
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetDependencyLevels returns the level of each name in the dependency graph. The names without dependencies are at
+// level 0, and the others one level above their highest dependency, so that sorting by level puts the dependencies
+// first. The dependencies which are not keys of the graph are ignored. It fails if there is a cycle.
+func GetDependencyLevels(dependencies map[string][]string) (map[string]int, error) {
+	levels := map[string]int{}
+	visiting := map[string]bool{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if _, ok := levels[name]; ok {
+			return nil
+		}
+		if visiting[name] {
+			for i, visited := range path {
+				if visited == name {
+					path = path[i:]
+					break
+				}
+			}
+			return fmt.Errorf("the services %s depend on each other", strings.Join(append(path, name), " -> "))
+		}
+		path = append(path, name)
+		visiting[name] = true
+		level := 0
+		for _, dependency := range dependencies[name] {
+			if _, ok := dependencies[dependency]; !ok {
+				continue
+			}
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+			if levels[dependency]+1 > level {
+				level = levels[dependency] + 1
+			}
+		}
+		visiting[name] = false
+		levels[name] = level
+		return nil
+	}
+	names := []string{}
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return levels, err
+		}
+	}
+	return levels, nil
+}
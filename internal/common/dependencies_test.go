@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestGetDependencyLevels(t *testing.T) {
+	levels, err := common.GetDependencyLevels(map[string][]string{
+		"web":    {"api", "cache"},
+		"api":    {"db", "external-queue"},
+		"worker": {"db"},
+		"db":     nil,
+		"cache":  {},
+	})
+	want := map[string]int{"db": 0, "cache": 0, "api": 1, "worker": 1, "web": 2}
+	if err != nil || !reflect.DeepEqual(levels, want) {
+		t.Fatalf("Failed to get the levels of the dependencies. Expected: %v Actual: %v Error: %v", want, levels, err)
+	}
+
+	_, err = common.GetDependencyLevels(map[string][]string{"web": {"api"}, "api": {"db"}, "db": {"api"}})
+	if err == nil || err.Error() != "the services api -> db -> api depend on each other" {
+		t.Fatalf("Expected an error for the cycle. Actual: %v", err)
+	}
+}
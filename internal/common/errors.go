@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrorCode identifies a kind of failure. The codes are stable, so that they can be scripted around.
+type ErrorCode string
+
+const (
+	// RegistryAuthMissingErrorCode is used when the registry of the new images has no credentials
+	RegistryAuthMissingErrorCode ErrorCode = "M2K-IMG-001"
+	// ImageBuildFailedErrorCode is used when a new image cannot be built locally
+	ImageBuildFailedErrorCode ErrorCode = "M2K-IMG-002"
+	// ImageNotFoundErrorCode is used when an image used by the artifacts is not found in its registry during the deployment
+	ImageNotFoundErrorCode ErrorCode = "M2K-IMG-003"
+	// NoServicesFoundErrorCode is used when no services or kubernetes artifacts are found in the source directory
+	NoServicesFoundErrorCode ErrorCode = "M2K-SRC-001"
+	// InvalidPlanErrorCode is used when the plan file is invalid
+	InvalidPlanErrorCode ErrorCode = "M2K-PLN-001"
+	// ContainerizationFailedErrorCode is used when a service cannot be containerized
+	ContainerizationFailedErrorCode ErrorCode = "M2K-CTR-001"
+	// TranslationFailedErrorCode is used when the plan cannot be translated
+	TranslationFailedErrorCode ErrorCode = "M2K-TRN-001"
+	// ClusterAccessFailedErrorCode is used when the cluster metadata cannot be collected
+	ClusterAccessFailedErrorCode ErrorCode = "M2K-CLS-001"
+	// CFAuthMissingErrorCode is used when there is no CF API endpoint or access token
+	CFAuthMissingErrorCode ErrorCode = "M2K-CF-001"
+	// HerokuAuthMissingErrorCode is used when there is no Heroku API key
+	HerokuAuthMissingErrorCode ErrorCode = "M2K-HRK-001"
+	// QADefaultsMissingErrorCode is used when the defaults manifest has no answers for the disabled QA categories
+	QADefaultsMissingErrorCode ErrorCode = "M2K-QA-001"
+	// ToolTimeoutErrorCode is used when an external tool does not finish in time
+	ToolTimeoutErrorCode ErrorCode = "M2K-TOOL-001"
+	// ToolNotFoundErrorCode is used when an external tool is not installed
+	ToolNotFoundErrorCode ErrorCode = "M2K-TOOL-002"
+	// UnresolvedReferenceErrorCode is used when a kubernetes resource refers to a resource missing in its target namespace
+	UnresolvedReferenceErrorCode ErrorCode = "M2K-K8S-001"
+	// UnmappedAnnotationErrorCode is used when an annotation of the source cloud provider has no equivalent on the target cloud provider
+	UnmappedAnnotationErrorCode ErrorCode = "M2K-K8S-002"
+	// ConstraintViolationErrorCode is used when a generated resource violates a constraint of the target cluster
+	ConstraintViolationErrorCode ErrorCode = "M2K-K8S-003"
+	// SecretsFoundErrorCode is used when credentials are found in the sources copied into the build contexts
+	SecretsFoundErrorCode ErrorCode = "M2K-SRC-002"
+	// UnconvertibleResourceErrorCode is used when a resource of an infrastructure template, like a database of a CloudFormation template, has no equivalent in the translated resources
+	UnconvertibleResourceErrorCode ErrorCode = "M2K-SRC-003"
+	// DeploymentNotReadyErrorCode is used when a deployed workload does not become ready within the timeout
+	DeploymentNotReadyErrorCode ErrorCode = "M2K-DEP-001"
+)
+
+const (
+	// AskOnServiceError asks whether to retry the failed step of a service, skip the service or abort the translation
+	AskOnServiceError = "ask"
+	// RetryOnServiceError retries the failed step of a service up to ServiceErrorRetries times and then skips the service
+	RetryOnServiceError = "retry"
+	// SkipOnServiceError skips the service whose step failed
+	SkipOnServiceError = "skip"
+	// AbortOnServiceError aborts the translation when a step of a service fails
+	AbortOnServiceError = "abort"
+	// ServiceErrorRetries is the maximum number of times a failed step of a service is retried
+	ServiceErrorRetries = 3
+)
+
+// OnServiceError is how a failed step of a service, like its containerization, is handled during the translation
+var OnServiceError = AskOnServiceError
+
+// ErrorCodeRemediations are the remediation hints of the error codes
+var ErrorCodeRemediations = map[ErrorCode]string{
+	RegistryAuthMissingErrorCode:    "Login to the registry using docker login, or answer the registry login question with the credentials or an existing pull secret, and translate again.",
+	ImageBuildFailedErrorCode:       "Run the build script of the image to see its full output, and fix the Dockerfile or the source, or translate without --build-images and build the images later.",
+	ImageNotFoundErrorCode:          "Deploy using --build to build and push the images, or push the images to the registry using the scripts in the output directory.",
+	NoServicesFoundErrorCode:        "Check that the source directory contains the source code, docker compose files, CF manifests or kubernetes yamls, and that they are not excluded by a .m2kignore file.",
+	InvalidPlanErrorCode:            "Run the plan lint command to list the problems of the plan, fix them or plan again.",
+	ContainerizationFailedErrorCode: "Choose another container build type for the service in the plan, or add a Dockerfile to its source directory.",
+	TranslationFailedErrorCode:      "Check that the source directory in the plan exists and is readable, or plan again.",
+	ClusterAccessFailedErrorCode:    "Check the kubeconfig context and the credentials using kubectl, or select another context.",
+	CFAuthMissingErrorCode:          "Login using the cf CLI, or set the CF API endpoint and access token environment variables.",
+	HerokuAuthMissingErrorCode:      "Login using the heroku CLI, or set the HEROKU_API_KEY environment variable.",
+	QADefaultsMissingErrorCode:      "Add the answers of the disabled QA categories to the defaults manifest, or stop disabling the categories.",
+	ToolTimeoutErrorCode:            "Increase the timeout of the external tools using --tooltimeout, or check the network access of the tool.",
+	ToolNotFoundErrorCode:           "Install the tool and add it to the PATH, or run move2kube using --run-in-container.",
+	UnresolvedReferenceErrorCode:    "Add the referenced resource to the target namespace, or map the namespaces of both resources to the same target namespace.",
+	UnmappedAnnotationErrorCode:     "Configure the equivalent feature of the target cloud provider manually, like a BackendConfig on GKE, if the service or ingress needs it.",
+	ConstraintViolationErrorCode:    "Change the generated resource to satisfy the constraint before deploying it, or set the action of the restriction to fix in the constraints of the cluster metadata.",
+	SecretsFoundErrorCode:           "Remove the credentials from the sources and pass them to the containers using secrets, or list the false positives in a .m2ksecretsallow file in the source directory.",
+	UnconvertibleResourceErrorCode:  "Provision the resource outside the cluster, using the tools of the cloud provider or a Kubernetes operator, and pass its endpoint and credentials to the services using config maps and secrets.",
+	DeploymentNotReadyErrorCode:     "Check the events and the logs of the pods of the workload using kubectl describe and kubectl logs, or deploy using a longer --timeout.",
+}
+
+// Error is a failure with an error code and a remediation hint
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+	// Warning is true if the migration can continue, like when the generated artifacts need manual changes
+	Warning bool
+}
+
+// NewError creates an error with the code. The cause is optional.
+func NewError(code ErrorCode, cause error, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// Error returns the code, the message and the cause of the error
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("[%s] %s Error: %q", e.Code, e.Message, e.Err)
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Remediation returns the remediation hint of the error code
+func (e *Error) Remediation() string {
+	return ErrorCodeRemediations[e.Code]
+}
+
+// Details returns the error along with its remediation hint, for the logs
+func (e *Error) Details() string {
+	if remediation := e.Remediation(); remediation != "" {
+		return e.Error() + " Remediation: " + remediation
+	}
+	return e.Error()
+}
+
+// AsError returns the error with a code in the chain of the error. The timeouts and the missing binaries of the external
+// tools get their own codes. It returns nil if the error has no code.
+func AsError(err error) *Error {
+	var codedErr *Error
+	if errors.As(err, &codedErr) {
+		return codedErr
+	}
+	var timeoutErr *CommandTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return NewError(ToolTimeoutErrorCode, err, "The command %s timed out.", timeoutErr.Command)
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return NewError(ToolNotFoundErrorCode, err, "An external tool was not found.")
+	}
+	return nil
+}
+
+var (
+	reportedErrors      = []*Error{}
+	reportedErrorsMutex = sync.Mutex{}
+)
+
+// ReportError logs the error along with its remediation hint and records it for the report of the translation
+func ReportError(err *Error) {
+	if err.Warning {
+		log.Warn(err.Details())
+	} else {
+		log.Error(err.Details())
+	}
+	reportedErrorsMutex.Lock()
+	defer reportedErrorsMutex.Unlock()
+	reportedErrors = append(reportedErrors, err)
+}
+
+// ResetReportedErrors forgets the errors reported so far, at the start of a translation or of the translation of a target
+func ResetReportedErrors() {
+	reportedErrorsMutex.Lock()
+	defer reportedErrorsMutex.Unlock()
+	reportedErrors = []*Error{}
+}
+
+// GetReportedErrors returns the errors reported so far
+func GetReportedErrors() []*Error {
+	reportedErrorsMutex.Lock()
+	defer reportedErrorsMutex.Unlock()
+	return append([]*Error{}, reportedErrors...)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestError(t *testing.T) {
+	t.Run("every error code has a remediation", func(t *testing.T) {
+		codes := []common.ErrorCode{
+			common.RegistryAuthMissingErrorCode, common.ImageBuildFailedErrorCode, common.ImageNotFoundErrorCode, common.NoServicesFoundErrorCode, common.InvalidPlanErrorCode,
+			common.ContainerizationFailedErrorCode, common.TranslationFailedErrorCode, common.ClusterAccessFailedErrorCode,
+			common.CFAuthMissingErrorCode, common.HerokuAuthMissingErrorCode, common.QADefaultsMissingErrorCode, common.ToolTimeoutErrorCode, common.ToolNotFoundErrorCode,
+			common.UnresolvedReferenceErrorCode, common.UnmappedAnnotationErrorCode, common.ConstraintViolationErrorCode, common.SecretsFoundErrorCode, common.UnconvertibleResourceErrorCode, common.DeploymentNotReadyErrorCode,
+		}
+		for _, code := range codes {
+			if common.ErrorCodeRemediations[code] == "" {
+				t.Fatalf("Expected a remediation for the error code %s", code)
+			}
+		}
+	})
+
+	t.Run("the code is found in the chain of the error", func(t *testing.T) {
+		cause := errors.New("unauthorized")
+		err := fmt.Errorf("collection failed: %w", common.NewError(common.ClusterAccessFailedErrorCode, cause, "Unable to access the cluster."))
+		codedErr := common.AsError(err)
+		if codedErr == nil || codedErr.Code != common.ClusterAccessFailedErrorCode || !errors.Is(err, cause) {
+			t.Fatalf("Expected the error code %s . Actual: %v", common.ClusterAccessFailedErrorCode, codedErr)
+		}
+		if details := codedErr.Details(); !strings.HasPrefix(details, "[M2K-CLS-001] Unable to access the cluster.") || !strings.Contains(details, "Remediation: ") {
+			t.Fatalf("Expected the details to have the code and the remediation. Actual: %s", details)
+		}
+	})
+
+	t.Run("the errors of the external tools get their codes", func(t *testing.T) {
+		timeoutErr := fmt.Errorf("build failed: %w", &common.CommandTimeoutError{Command: "docker build ."})
+		if codedErr := common.AsError(timeoutErr); codedErr == nil || codedErr.Code != common.ToolTimeoutErrorCode {
+			t.Fatalf("Expected the error code %s . Actual: %v", common.ToolTimeoutErrorCode, codedErr)
+		}
+		notFoundErr := &exec.Error{Name: "pack", Err: exec.ErrNotFound}
+		if codedErr := common.AsError(notFoundErr); codedErr == nil || codedErr.Code != common.ToolNotFoundErrorCode {
+			t.Fatalf("Expected the error code %s . Actual: %v", common.ToolNotFoundErrorCode, codedErr)
+		}
+		if codedErr := common.AsError(errors.New("other")); codedErr != nil {
+			t.Fatalf("Expected no error code. Actual: %v", codedErr)
+		}
+	})
+
+	t.Run("the reported errors are kept for the report", func(t *testing.T) {
+		err := common.NewError(common.RegistryAuthMissingErrorCode, nil, "No credentials.")
+		err.Warning = true
+		common.ReportError(err)
+		reported := common.GetReportedErrors()
+		if len(reported) == 0 || reported[len(reported)-1] != err {
+			t.Fatalf("Expected the error to be reported. Actual: %v", reported)
+		}
+	})
+}
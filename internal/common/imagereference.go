@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+)
+
+// ImageReference is an image reference of the form registry/namespace/repository:tag@digest .
+// All the components except the repository are optional.
+type ImageReference struct {
+	Registry   string // The registry domain, like quay.io or localhost:5000
+	Namespace  string // The path between the registry and the repository, which can contain slashes
+	Repository string // The last component of the path
+	Tag        string
+	Digest     string // The digest including the algorithm, like sha256:...
+}
+
+// ParseImageReference parses an image reference. The first component of the path is the registry only if
+// it contains a dot or a colon or is localhost, like docker does, so that docker hub images like library/nginx
+// are not mistaken for images in a registry.
+func ParseImageReference(image string) ImageReference {
+	ref := ImageReference{}
+	name := image
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		ref.Digest = name[idx+1:]
+		name = name[:idx]
+	}
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		ref.Tag = name[idx+1:]
+		name = name[:idx]
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		parts = parts[1:]
+	}
+	ref.Repository = parts[len(parts)-1]
+	ref.Namespace = strings.Join(parts[:len(parts)-1], "/")
+	return ref
+}
+
+// Name returns the path of the image without the registry, the tag and the digest
+func (ref ImageReference) Name() string {
+	if ref.Namespace == "" {
+		return ref.Repository
+	}
+	return ref.Namespace + "/" + ref.Repository
+}
+
+// String returns the image reference in the form registry/namespace/repository:tag@digest
+func (ref ImageReference) String() string {
+	image := ref.Name()
+	if ref.Registry != "" {
+		image = ref.Registry + "/" + image
+	}
+	if ref.Tag != "" {
+		image += ":" + ref.Tag
+	}
+	if ref.Digest != "" {
+		image += "@" + ref.Digest
+	}
+	return image
+}
+
+// WithRegistry returns the image reference moved to the registry and the namespace. The repository, the tag and
+// the digest are retained. An empty registry or namespace removes it from the image reference.
+func (ref ImageReference) WithRegistry(registry string, namespace string) ImageReference {
+	ref.Registry = registry
+	ref.Namespace = namespace
+	return ref
+}
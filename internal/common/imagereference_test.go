@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestParseImageReference(t *testing.T) {
+	digest := "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+	testcases := map[string]common.ImageReference{
+		"nginx":                                   {Repository: "nginx"},
+		"library/nginx:1.19":                      {Namespace: "library", Repository: "nginx", Tag: "1.19"},
+		"quay.io/konveyor/move2kube:latest":       {Registry: "quay.io", Namespace: "konveyor", Repository: "move2kube", Tag: "latest"},
+		"localhost:5000/myapp":                    {Registry: "localhost:5000", Repository: "myapp"},
+		"registry.example.com/a/b/c:v1@" + digest: {Registry: "registry.example.com", Namespace: "a/b", Repository: "c", Tag: "v1", Digest: digest},
+		"nginx@" + digest:                         {Repository: "nginx", Digest: digest},
+	}
+	for image, want := range testcases {
+		got := common.ParseImageReference(image)
+		if got != want {
+			t.Errorf("Failed to parse the image reference %s . Expected: %+v Actual: %+v", image, want, got)
+		}
+		if got.String() != image {
+			t.Errorf("Expected the image reference %s to be unchanged after parsing. Actual: %s", image, got.String())
+		}
+	}
+}
+
+func TestImageReferenceWithRegistry(t *testing.T) {
+	digest := "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+	ref := common.ParseImageReference("localhost:5000/dev/myapp:v2@" + digest)
+	want := "quay.io/myproject/myapp:v2@" + digest
+	if got := ref.WithRegistry("quay.io", "myproject").String(); got != want {
+		t.Fatalf("Expected only the registry and the namespace to be re-targeted. Expected: %s Actual: %s", want, got)
+	}
+	if name, tag := common.GetImageNameAndTag("myapp@" + digest); name != "myapp" || tag != "latest" {
+		t.Fatalf("Expected the digest not to be mistaken for the tag. Actual: %s %s", name, tag)
+	}
+}
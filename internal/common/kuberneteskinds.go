@@ -21,6 +21,10 @@ const (
 	ServiceKind = "Service"
 	// DeploymentKind defines Deployment Kind
 	DeploymentKind = "Deployment"
+	// StatefulSetKind defines StatefulSet Kind
+	StatefulSetKind = "StatefulSet"
 	// IngressKind defines Ingress Kind
 	IngressKind = "Ingress"
+	// RouteKind defines Route Kind
+	RouteKind = "Route"
 )
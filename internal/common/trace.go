@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"runtime/trace"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TraceRegion marks a step, like a translator or the containerization of a service, as a region in the execution trace
+// and logs its duration. It returns the function which ends the region.
+func TraceRegion(name string) func() {
+	start := time.Now()
+	region := trace.StartRegion(context.Background(), name)
+	return func() {
+		region.End()
+		log.Debugf("%s took %s", name, time.Since(start))
+	}
+}
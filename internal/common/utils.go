@@ -143,18 +143,20 @@ func YamlAttrPresent(path string, attr string) (bool, interface{}) {
 
 // GetImageNameAndTag splits an image full name and returns the image name and tag
 func GetImageNameAndTag(image string) (string, string) {
-	parts := strings.Split(image, "/")
-	imageAndTag := strings.Split(parts[len(parts)-1], ":")
-	imageName := imageAndTag[0]
-	var tag string
-	if len(imageAndTag) == 1 {
+	ref := ParseImageReference(image)
+	if ref.Tag == "" {
 		// no tag, assume latest
-		tag = "latest"
-	} else {
-		tag = imageAndTag[1]
+		return ref.Repository, "latest"
 	}
+	return ref.Repository, ref.Tag
+}
 
-	return imageName, tag
+// GetImageRegistry returns the registry of an image. Images without a registry are pulled from docker hub.
+func GetImageRegistry(image string) string {
+	if registry := ParseImageReference(image).Registry; registry != "" {
+		return registry
+	}
+	return DefaultDockerHubRegistry
 }
 
 // ObjectToYamlBytes encodes an object to yaml
@@ -183,6 +185,51 @@ func WriteYaml(outputPath string, data interface{}) error {
 	return ioutil.WriteFile(outputPath, yamlBytes, DefaultFilePermission)
 }
 
+// YamlObjectToJSONBytes encodes an object to json, using the field names of its yaml encoding
+func YamlObjectToJSONBytes(data interface{}) ([]byte, error) {
+	yamlBytes, err := ObjectToYamlBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err := yaml.Unmarshal(yamlBytes, &obj); err != nil {
+		log.Errorf("Failed to decode the yaml encoding of the object. Error: %q", err)
+		return nil, err
+	}
+	jsonBytes, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to encode the object to json. Error: %q", err)
+		return nil, err
+	}
+	return append(jsonBytes, '\n'), nil
+}
+
+// WriteYamlObjectAsJSON encodes the object as json, using the field names of its yaml encoding, and writes it to a file.
+// The file can be read using ReadYaml and ReadMove2KubeYaml, since json is a subset of yaml.
+func WriteYamlObjectAsJSON(outputPath string, data interface{}) error {
+	jsonBytes, err := YamlObjectToJSONBytes(data)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(outputPath, jsonBytes, DefaultFilePermission)
+}
+
+// WriteYamlOrJSON writes the object to a file as json if the output format is json, else as yaml
+func WriteYamlOrJSON(outputPath string, data interface{}, outputFormat string) error {
+	if outputFormat == JSONOutputFormat {
+		return WriteYamlObjectAsJSON(outputPath, data)
+	}
+	return WriteYaml(outputPath, data)
+}
+
+// GetOutputFormatExt returns the file extension of the output format
+func GetOutputFormatExt(outputFormat string) string {
+	if outputFormat == JSONOutputFormat {
+		return ".json"
+	}
+	return ".yaml"
+}
+
 // ReadYaml reads an yaml into an object
 func ReadYaml(file string, data interface{}) error {
 	yamlFile, err := ioutil.ReadFile(file)
@@ -214,6 +261,7 @@ func ReadYaml(file string, data interface{}) error {
 // ReadMove2KubeYaml reads move2kube specific yaml files (like m2k.plan) into an struct.
 // It checks if apiVersion to see if the group is move2kube and also reports if the
 // version is different from the expected version.
+// The files written as json by WriteYamlObjectAsJSON are read the same way, since json is a subset of yaml.
 func ReadMove2KubeYaml(path string, out interface{}) error {
 	yamlData, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -351,10 +399,15 @@ func MergeIntSlices(slice1 []int, slice2 []int) []int {
 	return slice1
 }
 
+// ParseTemplate parses a template. All the templates can access the template data model of the project using the project function.
+func ParseTemplate(tpl string) (*template.Template, error) {
+	return template.New("").Funcs(template.FuncMap{"project": func() interface{} { return ProjectTemplateData }}).Parse(tpl)
+}
+
 // GetStringFromTemplate returns string for a template
 func GetStringFromTemplate(tpl string, config interface{}) (string, error) {
 	var tplbuffer bytes.Buffer
-	var packageTemplate = template.Must(template.New("").Parse(tpl))
+	var packageTemplate = template.Must(ParseTemplate(tpl))
 	err := packageTemplate.Execute(&tplbuffer, config)
 	if err != nil {
 		log.Warnf("Unable to translate template %q to string using the data %v", tpl, config)
@@ -366,7 +419,7 @@ func GetStringFromTemplate(tpl string, config interface{}) (string, error) {
 // WriteTemplateToFile writes a templated string to a file
 func WriteTemplateToFile(tpl string, config interface{}, writepath string, filemode os.FileMode) error {
 	var tplbuffer bytes.Buffer
-	var packageTemplate = template.Must(template.New("").Parse(tpl))
+	var packageTemplate = template.Must(ParseTemplate(tpl))
 	err := packageTemplate.Execute(&tplbuffer, config)
 	if err != nil {
 		log.Warnf("Unable to translate template %q to string using the data %v", tpl, config)
@@ -448,6 +501,20 @@ func GetSHA256Hash(s string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))
 }
 
+// GetFileSHA256Hash returns the SHA256 hash of the contents of the file encoded as a 64 char hexadecimal string.
+func GetFileSHA256Hash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
+
 // MakeStringDNSNameCompliant makes the string into a valid DNS name.
 func MakeStringDNSNameCompliant(s string) string {
 	name := strings.ToLower(s)
@@ -592,6 +659,11 @@ func CreateAssetsData() (assetsPath string, tempPath string, err error) {
 // The dst file will be truncated if it exists.
 // Returns an error if it failed to copy all the bytes.
 func CopyFile(dst, src string) error {
+	return copyFileWithPermission(dst, src, DefaultFilePermission)
+}
+
+// copyFileWithPermission copies a file from src to dst and creates dst with the given permission if it does not exist
+func copyFileWithPermission(dst, src string, permission os.FileMode) error {
 	srcfile, err := os.Open(src)
 	if err != nil {
 		return fmt.Errorf("failed to open the source file at path %q Error: %q", src, err)
@@ -604,7 +676,7 @@ func CopyFile(dst, src string) error {
 	}
 	srcfilesize := srcfileinfo.Size()
 
-	dstfile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFilePermission)
+	dstfile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, permission)
 	if err != nil {
 		return fmt.Errorf("failed to create the destination file at path %q Error: %q", dst, err)
 	}
@@ -685,6 +757,19 @@ func GetGitRepoDetails(path, remoteName string) (remoteURLs []string, branch str
 	return remoteURLs, branch, repoDir, nil
 }
 
+// GetGitCommit returns the commit checked out in the git repo containing the path.
+func GetGitCommit(path string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", err
+	}
+	ref, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return ref.Hash().String(), nil
+}
+
 // GetGitRepoName returns the remote repo's name and context.
 func GetGitRepoName(path string) (repo string, root string) {
 	r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
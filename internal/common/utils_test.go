@@ -366,6 +366,31 @@ func TestWriteJSON(t *testing.T) {
 	})
 }
 
+func TestWriteYamlObjectAsJSON(t *testing.T) {
+	path1 := filepath.Join(t.TempDir(), "foobar.json")
+	data1 := struct {
+		Foo   string            `yaml:"foo"`
+		Bar   int               `yaml:"bar,omitempty"`
+		Names map[string]string `yaml:"names"`
+	}{Foo: "contents1", Names: map[string]string{"a": "b"}}
+	if err := common.WriteYamlOrJSON(path1, data1, common.JSONOutputFormat); err != nil {
+		t.Fatal("Failed to write the data", data1, "to the file path", path1, ". Error:", err)
+	}
+	want := "{\n  \"foo\": \"contents1\",\n  \"names\": {\n    \"a\": \"b\"\n  }\n}\n"
+	jsondata, err := ioutil.ReadFile(path1)
+	if err != nil {
+		t.Fatal("Failed to read the file we just wrote. Error:", err)
+	}
+	if string(jsondata) != want {
+		t.Fatal("Failed to encode the data to json using the yaml field names. Expected:", want, "Actual:", string(jsondata))
+	}
+	data2 := data1
+	data2.Foo = ""
+	if err := common.ReadYaml(path1, &data2); err != nil || data2.Foo != "contents1" || data2.Names["a"] != "b" {
+		t.Fatal("Failed to read the json file as yaml. Actual:", data2, "Error:", err)
+	}
+}
+
 func TestReadJSON(t *testing.T) {
 	log.SetLevel(log.DebugLevel)
 
@@ -634,6 +659,18 @@ func TestGetStringFromTemplate(t *testing.T) {
 	}
 }
 
+func TestGetStringFromTemplateWithProjectData(t *testing.T) {
+	common.ProjectTemplateData = struct{ Name string }{"myproject"}
+	defer func() { common.ProjectTemplateData = nil }()
+	filled, err := common.GetStringFromTemplate("{{ project.Name }}-{{ .ID }}", struct{ ID int }{42})
+	if err != nil {
+		t.Fatalf("Failed to fill the template using the project data. Error: %q", err)
+	}
+	if filled != "myproject-42" {
+		t.Fatalf("Failed to fill the template using the project data. Expected: %q Actual: %q", "myproject-42", filled)
+	}
+}
+
 func TestWriteTemplateToFile(t *testing.T) {
 	log.SetLevel(log.DebugLevel)
 
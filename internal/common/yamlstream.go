@@ -0,0 +1,195 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrStopStreaming can be returned by the handler of the YAML documents to stop reading the stream without an error
+var ErrStopStreaming = errors.New("stop streaming the YAML documents")
+
+// yamlStream splits a stream into YAML documents one line at a time
+type yamlStream struct {
+	handle func(doc []byte) error
+	// doc is the current document, without the items if it is a list
+	doc bytes.Buffer
+	// item is the current item of the list
+	item bytes.Buffer
+	// inItems is true while reading the top level items of a list
+	inItems bool
+	// itemsLine is the line of the items key, which is kept in the document if the items are not a sequence
+	itemsLine string
+	// itemIndent is the indentation of the dashes of the items, or -1 if the first item was not seen yet
+	itemIndent int
+	// hasItems is true if the items of the current document were streamed
+	hasItems bool
+}
+
+// StreamYAMLFile streams the YAML documents in the file at the path. See StreamYAMLDocuments.
+func StreamYAMLFile(path string, handle func(doc []byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return StreamYAMLDocuments(f, handle)
+}
+
+// StreamYAMLDocuments reads the YAML documents in the stream one at a time and calls handle with each of them.
+// The items of a list, like the dumps of kubectl get -o yaml, are handled as separate documents, so that neither
+// the file nor a huge list is ever held in memory. The stream is not parsed, so the documents can be invalid YAML.
+// Returning ErrStopStreaming from handle stops reading the stream.
+func StreamYAMLDocuments(r io.Reader, handle func(doc []byte) error) error {
+	s := &yamlStream{handle: handle, itemIndent: -1}
+	reader := bufio.NewReader(r)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			if err := s.addLine(line); err != nil {
+				if err == ErrStopStreaming {
+					return nil
+				}
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	if err := s.endDocument(); err != nil && err != ErrStopStreaming {
+		return err
+	}
+	return nil
+}
+
+func (s *yamlStream) addLine(line string) error {
+	trimmed := strings.TrimRight(line, " \t\r\n")
+	if trimmed == "---" || strings.HasPrefix(trimmed, "--- ") || trimmed == "..." {
+		return s.endDocument()
+	}
+	if s.inItems {
+		isContent := strings.TrimSpace(trimmed) != "" && !strings.HasPrefix(strings.TrimSpace(trimmed), "#")
+		if !isContent {
+			s.item.WriteString(line)
+			return nil
+		}
+		indent := len(trimmed) - len(strings.TrimLeft(trimmed, " "))
+		isDash := strings.HasPrefix(trimmed[indent:], "- ") || trimmed[indent:] == "-"
+		if s.itemIndent == -1 {
+			if !isDash {
+				// The items are not a sequence, so they are kept in the document
+				s.doc.WriteString(s.itemsLine)
+				s.doc.Write(s.item.Bytes())
+				s.item.Reset()
+				s.inItems = false
+				s.doc.WriteString(line)
+				return nil
+			}
+			s.itemIndent = indent
+		}
+		if indent == s.itemIndent && isDash {
+			if err := s.endItem(); err != nil {
+				return err
+			}
+			if strings.TrimSpace(trimmed[indent+1:]) != "" {
+				s.item.WriteString(strings.Repeat(" ", s.itemIndent+2))
+				s.item.WriteString(line[s.itemIndent+2:])
+			}
+			s.hasItems = true
+			return nil
+		}
+		if indent > s.itemIndent {
+			s.item.WriteString(line)
+			return nil
+		}
+		// The line after the items
+		if err := s.endItem(); err != nil {
+			return err
+		}
+		s.inItems = false
+	}
+	if trimmed == "items:" {
+		s.inItems = true
+		s.itemsLine = line
+		s.itemIndent = -1
+		return nil
+	}
+	s.doc.WriteString(line)
+	return nil
+}
+
+// endItem handles the current item of the list after removing the indentation of the items
+func (s *yamlStream) endItem() error {
+	if s.item.Len() == 0 {
+		return nil
+	}
+	indent := s.itemIndent + 2
+	item := bytes.Buffer{}
+	for _, line := range strings.SplitAfter(s.item.String(), "\n") {
+		if len(line)-len(strings.TrimLeft(line, " ")) >= indent {
+			line = line[indent:]
+		}
+		item.WriteString(line)
+	}
+	s.item.Reset()
+	if !hasYAMLContent(item.Bytes()) {
+		return nil
+	}
+	return s.handle(item.Bytes())
+}
+
+// endDocument handles the current document, unless its items were already handled
+func (s *yamlStream) endDocument() error {
+	if s.inItems {
+		if s.itemIndent == -1 {
+			s.doc.WriteString(s.itemsLine)
+			s.item.Reset()
+		} else if err := s.endItem(); err != nil {
+			return err
+		}
+	}
+	doc := append([]byte{}, s.doc.Bytes()...)
+	hasItems := s.hasItems
+	s.doc.Reset()
+	s.inItems = false
+	s.itemIndent = -1
+	s.hasItems = false
+	if hasItems || !hasYAMLContent(doc) {
+		return nil
+	}
+	return s.handle(doc)
+}
+
+// hasYAMLContent returns true if the document has lines other than blank lines and comments
+func hasYAMLContent(doc []byte) bool {
+	for _, line := range strings.Split(string(doc), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return true
+		}
+	}
+	return false
+}
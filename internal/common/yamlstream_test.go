@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+func TestStreamYAMLDocuments(t *testing.T) {
+	stream := func(t *testing.T, input string) []string {
+		docs := []string{}
+		err := common.StreamYAMLDocuments(strings.NewReader(input), func(doc []byte) error {
+			docs = append(docs, string(doc))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to stream the YAML documents. Error: %q", err)
+		}
+		return docs
+	}
+
+	t.Run("multiple documents", func(t *testing.T) {
+		input := "---\napiVersion: v1\nkind: Service\n---\n# only a comment\n---\napiVersion: v1\nkind: Pod\n...\n"
+		want := []string{"apiVersion: v1\nkind: Service\n", "apiVersion: v1\nkind: Pod\n"}
+		if docs := stream(t, input); !reflect.DeepEqual(docs, want) {
+			t.Fatalf("Failed to split the documents. Expected: %q Actual: %q", want, docs)
+		}
+	})
+
+	t.Run("items of a list dumped by kubectl", func(t *testing.T) {
+		input := `apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: svc1
+  spec:
+    ports:
+    - port: 80
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    annotations:
+      note: |
+        multi
+        line
+    name: svc1
+kind: List
+metadata:
+  resourceVersion: ""
+---
+apiVersion: v1
+kind: ConfigMap
+`
+		want := []string{
+			"apiVersion: v1\nkind: Service\nmetadata:\n  name: svc1\nspec:\n  ports:\n  - port: 80\n",
+			"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  annotations:\n    note: |\n      multi\n      line\n  name: svc1\n",
+			"apiVersion: v1\nkind: ConfigMap\n",
+		}
+		if docs := stream(t, input); !reflect.DeepEqual(docs, want) {
+			t.Fatalf("Failed to stream the items of the list. Expected: %q Actual: %q", want, docs)
+		}
+	})
+
+	t.Run("indented items at the end of the file", func(t *testing.T) {
+		input := "kind: List\nitems:\n  - kind: Service\n    metadata:\n      name: svc1\n  -\n    kind: Pod"
+		want := []string{"kind: Service\nmetadata:\n  name: svc1\n", "kind: Pod"}
+		if docs := stream(t, input); !reflect.DeepEqual(docs, want) {
+			t.Fatalf("Failed to stream the items of the list. Expected: %q Actual: %q", want, docs)
+		}
+	})
+
+	t.Run("items which are not a sequence", func(t *testing.T) {
+		input := "kind: Custom\nitems:\n  key: value\nspec: {}\n"
+		want := []string{input}
+		if docs := stream(t, input); !reflect.DeepEqual(docs, want) {
+			t.Fatalf("Expected the document to be kept. Expected: %q Actual: %q", want, docs)
+		}
+	})
+
+	t.Run("stop streaming", func(t *testing.T) {
+		count := 0
+		err := common.StreamYAMLDocuments(strings.NewReader("kind: A\n---\nkind: B\n---\nkind: C\n"), func(doc []byte) error {
+			count++
+			if count == 2 {
+				return common.ErrStopStreaming
+			}
+			return nil
+		})
+		if err != nil || count != 2 {
+			t.Fatalf("Expected the stream to stop after 2 documents. Actual: %d Error: %v", count, err)
+		}
+	})
+}
@@ -25,12 +25,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/ioutils"
+	"github.com/konveyor/move2kube/internal/common"
 	"github.com/spf13/cast"
 
 	log "github.com/sirupsen/logrus"
@@ -38,6 +40,8 @@ import (
 
 type dockerEngine struct {
 	availableImages map[string]bool
+	// pullMutex guards the available images, so that an image is pulled only once when the detectors run concurrently
+	pullMutex sync.Mutex
 }
 
 func newDockerEngine() *dockerEngine {
@@ -47,10 +51,13 @@ func newDockerEngine() *dockerEngine {
 }
 
 func (e *dockerEngine) pullImage(image string) bool {
+	e.pullMutex.Lock()
+	defer e.pullMutex.Unlock()
 	if a, ok := e.availableImages[image]; ok {
 		return a
 	}
-	ctx := context.Background()
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		log.Debugf("Unable to pull image %s : %s", image, err)
@@ -76,7 +83,8 @@ func (e *dockerEngine) RunContainer(image string, cmd string, volsrc string, vol
 		log.Debugf("Unable to pull image using docker : %s", image)
 		return "", false, fmt.Errorf("Unable to pull image")
 	}
-	ctx := context.Background()
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		log.Debugf("Error during docker client creation : %s", err)
@@ -111,7 +119,7 @@ func (e *dockerEngine) RunContainer(image string, cmd string, volsrc string, vol
 			return "", false, err
 		}
 		log.Debugf("Container %s created with image %s with no volumes", resp.ID, image)
-		defer cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+		defer cli.ContainerRemove(context.Background(), resp.ID, types.ContainerRemoveOptions{Force: true})
 		if volsrc != "" && voldest != "" {
 			err = copyDir(ctx, cli, resp.ID, volsrc, voldest)
 			if err != nil {
@@ -122,7 +130,7 @@ func (e *dockerEngine) RunContainer(image string, cmd string, volsrc string, vol
 		}
 	}
 	log.Debugf("Container %s created with image %s", resp.ID, image)
-	defer cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+	defer cli.ContainerRemove(context.Background(), resp.ID, types.ContainerRemoveOptions{Force: true})
 	if err = cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
 		log.Debugf("Error during container startup of container %s : %s", resp.ID, err)
 		return "", false, err
@@ -135,6 +143,11 @@ func (e *dockerEngine) RunContainer(image string, cmd string, volsrc string, vol
 	select {
 	case err := <-errCh:
 		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				err = &common.CommandTimeoutError{Command: "docker run " + image + " " + cmd, Timeout: common.CommandTimeout}
+				log.Warn(err)
+				return "", true, err
+			}
 			log.Debugf("Error during waiting for container : %s", err)
 			return "", false, err
 		}
@@ -160,7 +173,8 @@ func (e *dockerEngine) RunContainer(image string, cmd string, volsrc string, vol
 
 // InspectImage returns inspect output for an image
 func (e *dockerEngine) InspectImage(image string) (types.ImageInspect, error) {
-	ctx := context.Background()
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return types.ImageInspect{}, err
@@ -17,6 +17,8 @@ limitations under the License.
 package containerexec
 
 import (
+	"sync"
+
 	"github.com/docker/docker/api/types"
 )
 
@@ -25,7 +27,7 @@ const (
 )
 
 var (
-	inited               = false
+	initOnce             = sync.Once{}
 	workingEngine Engine = nil
 	engines              = []Engine{newDockerEngine(), newPodmanEngine()}
 )
@@ -43,10 +45,8 @@ func initContainerEngine() {
 
 // GetEngine gets a working container engine
 func GetEngine() Engine {
-	if !inited {
-		initContainerEngine()
-		inited = true
-	}
+	// The engine can be requested by the planners running concurrently
+	initOnce.Do(initContainerEngine)
 	return workingEngine
 }
 
@@ -19,14 +19,17 @@ package containerexec
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"sync"
 
 	"github.com/docker/docker/api/types"
+	"github.com/konveyor/move2kube/internal/common"
 	log "github.com/sirupsen/logrus"
 )
 
 type podmanEngine struct {
 	availableImages map[string]bool
+	// pullMutex guards the available images, so that an image is pulled only once when the detectors run concurrently
+	pullMutex sync.Mutex
 }
 
 func newPodmanEngine() *podmanEngine {
@@ -37,9 +40,8 @@ func newPodmanEngine() *podmanEngine {
 
 // InspectImage returns inspect output for an image using Podman
 func (e *podmanEngine) InspectImage(image string) (t types.ImageInspect, err error) {
-	inspectcmd := exec.Command("podman", "inspect", image)
 	log.Debugf("Inspecting image %s", image)
-	output, err := inspectcmd.CombinedOutput()
+	output, err := common.RunCommandCombinedOutput("", "podman", "inspect", image)
 	if err != nil {
 		log.Debugf("Unable to inspect image %s : %s, %s", image, err, output)
 		return t, err
@@ -53,12 +55,13 @@ func (e *podmanEngine) InspectImage(image string) (t types.ImageInspect, err err
 }
 
 func (e *podmanEngine) pullImage(image string) bool {
+	e.pullMutex.Lock()
+	defer e.pullMutex.Unlock()
 	if a, ok := e.availableImages[image]; ok {
 		return a
 	}
-	pullcmd := exec.Command("podman", "pull", image)
 	log.Debugf("Pulling image %s", image)
-	output, err := pullcmd.CombinedOutput()
+	output, err := common.RunCommandCombinedOutput("", "podman", "pull", image)
 	if err != nil {
 		log.Warnf("Error while pulling builder %s : %s : %s", image, err, output)
 		e.availableImages[image] = false
@@ -82,9 +85,8 @@ func (e *podmanEngine) RunContainer(image string, cmd string, volsrc string, vol
 	if cmd != "" {
 		args = append(args, cmd)
 	}
-	detectcmd := exec.Command("podman", args...)
 	log.Debugf("Running detect on image %s", image)
-	o, err := detectcmd.CombinedOutput()
+	o, err := common.RunCommandCombinedOutput("", "podman", args...)
 	if err != nil {
 		log.Debugf("Detect failed %s : %s : %s", image, err, output)
 		return string(o), false, err
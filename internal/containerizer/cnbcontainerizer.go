@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/containerexec"
@@ -30,7 +31,7 @@ import (
 )
 
 var (
-	cnbwarnlongwait = false
+	cnbwarnlongwait = sync.Once{}
 )
 
 // CNBContainerizer implements Containerizer interface
@@ -60,7 +61,10 @@ type buildpackInfo struct {
 }
 
 // Cache
-var cnbcache = map[string][]string{}
+var (
+	cnbcache      = map[string][]string{}
+	cnbcacheMutex = sync.Mutex{}
+)
 
 // Init initializes the containerizer
 func (d *CNBContainerizer) Init(path string) {
@@ -69,15 +73,17 @@ func (d *CNBContainerizer) Init(path string) {
 }
 
 func logCNBLongWait() {
-	if !cnbwarnlongwait {
+	cnbwarnlongwait.Do(func() {
 		log.Warn("This could take a few minutes to complete.")
-		cnbwarnlongwait = true
-	}
+	})
 }
 
 // GetTargetOptions gets all possible target options for a path
 func (d *CNBContainerizer) GetTargetOptions(plan plantypes.Plan, path string) []string {
-	if options, ok := cnbcache[path]; ok {
+	cnbcacheMutex.Lock()
+	options, ok := cnbcache[path]
+	cnbcacheMutex.Unlock()
+	if ok {
 		return options
 	}
 	if containerexec.GetEngine() == nil {
@@ -90,7 +96,9 @@ func (d *CNBContainerizer) GetTargetOptions(plan plantypes.Plan, path string) []
 			supportedbuilders = append(supportedbuilders, builder)
 		}
 	}
+	cnbcacheMutex.Lock()
 	cnbcache[path] = supportedbuilders
+	cnbcacheMutex.Unlock()
 	return supportedbuilders
 }
 
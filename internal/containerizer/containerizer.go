@@ -39,6 +39,7 @@ type Containerizer interface {
 type ContainerizationOption struct {
 	ContainerizationType plantypes.ContainerBuildTypeValue
 	TargetOptions        []string
+	Detection            plantypes.Detection
 }
 
 const (
@@ -98,9 +99,14 @@ func GetContainerizationOptions(plan plantypes.Plan, sourcepath string) []Contai
 	cops := []ContainerizationOption{}
 	for _, containerizer := range containerizers {
 		if targetOptions := containerizer.GetTargetOptions(plan, sourcepath); len(targetOptions) != 0 {
+			ports := []int{}
+			if portDetector, ok := containerizer.(portDetector); ok {
+				ports = portDetector.getDetectedPorts(sourcepath, targetOptions)
+			}
 			cops = append(cops, ContainerizationOption{
 				ContainerizationType: containerizer.GetContainerBuildStrategy(),
 				TargetOptions:        targetOptions,
+				Detection:            GetDetection(containerizer.GetContainerBuildStrategy(), sourcepath, targetOptions, ports),
 			})
 		}
 	}
@@ -114,7 +120,9 @@ func GetContainer(plan plantypes.Plan, service plantypes.Service) (irtypes.Conta
 			continue
 		}
 		log.Debugf("Containerizing %s using %s", service.ServiceName, service.ContainerBuildType)
+		endRegion := common.TraceRegion(fmt.Sprintf("containerize %s using %s", service.ServiceName, service.ContainerBuildType))
 		container, err := containerizer.GetContainer(plan, service)
+		endRegion()
 		if err != nil {
 			log.Errorf("Error during containerization : %s", err)
 			return container, err
@@ -17,6 +17,8 @@ limitations under the License.
 package containerizer_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -44,3 +46,23 @@ func TestComesBefore(t *testing.T) {
 		t.Fatalf("Failed to sort the service options properly. Difference:\n%s", cmp.Diff(want, serviceOptions))
 	}
 }
+
+func TestGetDetection(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to create the package.json file. Error: %q", err)
+	}
+	t.Run("unambiguous detection with evidence", func(t *testing.T) {
+		detection := containerizer.GetDetection(plantypes.DockerFileContainerBuildTypeValue, dir, []string{"/assets/dockerfiles/nodejs"}, []int{8080})
+		want := plantypes.Detection{Confidence: 80, Evidence: []string{"package.json", "NewDockerfile detector nodejs"}, Ports: []int{8080}}
+		if !cmp.Equal(detection, want) {
+			t.Fatalf("Failed to get the detection. Difference:\n%s", cmp.Diff(want, detection))
+		}
+	})
+	t.Run("ambiguous detection without evidence", func(t *testing.T) {
+		detection := containerizer.GetDetection(plantypes.S2IContainerBuildTypeValue, t.TempDir(), []string{"/assets/s2i/java", "/assets/s2i/nodejs"}, nil)
+		if detection.Confidence != 40 {
+			t.Fatalf("Expected the confidence to be lowered for the missing evidence and the ambiguity. Actual: %d", detection.Confidence)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const (
+	minDetectionConfidence = 10
+	maxDetectionConfidence = 100
+	// noEvidencePenalty is subtracted when none of the well known files of a language or framework is in the directory
+	noEvidencePenalty = 20
+	// ambiguityPenalty is subtracted for every target option after the first one
+	ambiguityPenalty = 10
+)
+
+// detectionConfidences are the confidences of the container build types, when the detection is unambiguous
+var detectionConfidences = map[plantypes.ContainerBuildTypeValue]int{
+	plantypes.ReuseDockerFileContainerBuildTypeValue: 100,
+	plantypes.DockerFileContainerBuildTypeValue:      80,
+	plantypes.S2IContainerBuildTypeValue:             70,
+	plantypes.CNBContainerBuildTypeValue:             60,
+	plantypes.ReuseContainerBuildTypeValue:           50,
+	plantypes.ManualContainerBuildTypeValue:          minDetectionConfidence,
+}
+
+// evidenceFiles are the well known files of the languages and frameworks the containerizers detect
+var evidenceFiles = []string{
+	"Dockerfile",
+	"package.json",
+	"pom.xml",
+	"build.gradle",
+	"requirements.txt",
+	"setup.py",
+	"Pipfile",
+	"go.mod",
+	"Gemfile",
+	"composer.json",
+	"Cargo.toml",
+	"global.json",
+	"index.php",
+	"index.html",
+}
+
+// portDetector is implemented by the containerizers that can infer the ports of the source from their detection
+type portDetector interface {
+	getDetectedPorts(path string, targetOptions []string) []int
+}
+
+// GetDetection returns the confidence and the evidence of detecting the container build type for the source directory
+func GetDetection(containerBuildType plantypes.ContainerBuildTypeValue, path string, targetOptions []string, ports []int) plantypes.Detection {
+	detection := plantypes.Detection{Confidence: detectionConfidences[containerBuildType], Ports: ports}
+	for _, evidenceFile := range evidenceFiles {
+		if _, err := os.Stat(filepath.Join(path, evidenceFile)); err == nil {
+			detection.Evidence = append(detection.Evidence, evidenceFile)
+		}
+	}
+	if len(detection.Evidence) == 0 {
+		detection.Confidence -= noEvidencePenalty
+	}
+	for _, targetOption := range targetOptions {
+		detection.Evidence = append(detection.Evidence, string(containerBuildType)+" detector "+filepath.Base(targetOption))
+	}
+	if len(targetOptions) > 1 {
+		detection.Confidence -= ambiguityPenalty * (len(targetOptions) - 1)
+	}
+	if detection.Confidence < minDetectionConfidence {
+		detection.Confidence = minDetectionConfidence
+	}
+	if detection.Confidence > maxDetectionConfidence {
+		detection.Confidence = maxDetectionConfidence
+	}
+	sort.Ints(detection.Ports)
+	return detection
+}
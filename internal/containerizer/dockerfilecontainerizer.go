@@ -21,9 +21,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/containerizer/scripts"
@@ -35,7 +35,10 @@ import (
 
 // DockerfileContainerizer implements Containerizer interface
 type DockerfileContainerizer struct {
-	dfcontainerizers []string //Paths to directories containing containerizers
+	dfcontainerizers []string          //Paths to directories containing containerizers
+	detectOutputs    map[string]string //[containerizer directory:source directory] Outputs of the detect scripts
+	// detectOutputsMutex guards the outputs, since the directories are detected concurrently during planning
+	detectOutputsMutex sync.Mutex
 }
 
 const (
@@ -69,18 +72,66 @@ func (d *DockerfileContainerizer) GetTargetOptions(_ plantypes.Plan, path string
 			continue
 		}
 		log.Debugf("Output of Dockerfile containerizer detect script %s : %s", dfcontainerizer, output)
+		d.detectOutputsMutex.Lock()
+		if d.detectOutputs == nil {
+			d.detectOutputs = map[string]string{}
+		}
+		d.detectOutputs[dfcontainerizer+":"+path] = output
+		d.detectOutputsMutex.Unlock()
+		if isPreferredDetectOutput(output) {
+			// The containerizers specific to the source, like the ones modernizing the WebSphere apps, come before the generic ones
+			targetOptions = append([]string{dfcontainerizer}, targetOptions...)
+			continue
+		}
 		targetOptions = append(targetOptions, dfcontainerizer)
 	}
 	return targetOptions
 }
 
+// isPreferredDetectOutput returns true if the detect script asks for its containerizer to be preferred over the others matching the source directory
+func isPreferredDetectOutput(output string) bool {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output), &m); err != nil {
+		return false
+	}
+	preferred, ok := m["preferred"].(bool)
+	return ok && preferred
+}
+
+// getDetectedPorts returns the ports in the outputs of the detect scripts that matched the source directory
+func (d *DockerfileContainerizer) getDetectedPorts(path string, targetOptions []string) []int {
+	ports := []int{}
+	for _, targetOption := range targetOptions {
+		d.detectOutputsMutex.Lock()
+		output, ok := d.detectOutputs[targetOption+":"+path]
+		d.detectOutputsMutex.Unlock()
+		if !ok {
+			continue
+		}
+		m := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(output), &m); err != nil {
+			log.Debugf("Unable to unmarshal the output of the detect script at path %q Output: %q Error: %q", targetOption, output, err)
+			continue
+		}
+		records := []interface{}{m}
+		if segments, ok := m["segments"].([]interface{}); ok {
+			records = append(records, segments...)
+		}
+		for _, record := range records {
+			if rec, ok := record.(map[string]interface{}); ok {
+				if port, ok := rec["port"].(float64); ok && !common.IsIntPresent(ports, int(port)) {
+					ports = append(ports, int(port))
+				}
+			}
+		}
+	}
+	return ports
+}
+
 func (*DockerfileContainerizer) detect(scriptDir string, directory string) (string, error) {
 	scriptPath := filepath.Join(scriptDir, dockerfileDetectScript)
-	cmd := exec.Command(scriptPath, directory)
-	cmd.Dir = scriptDir
-	cmd.Stderr = os.Stderr
-	log.Debugf("Executing detect script %s on %s : %s", scriptDir, directory, cmd)
-	outputBytes, err := cmd.Output()
+	log.Debugf("Executing detect script %s on %s", scriptPath, directory)
+	outputBytes, err := common.RunCommand(scriptDir, scriptPath, directory)
 	return string(outputBytes), err
 }
 
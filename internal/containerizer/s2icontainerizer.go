@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -76,11 +75,8 @@ func (d *S2IContainerizer) GetTargetOptions(_ plantypes.Plan, path string) []str
 
 func (*S2IContainerizer) detect(scriptDir string, directory string) (string, error) {
 	scriptPath := filepath.Join(scriptDir, s2iDetectScript)
-	cmd := exec.Command(scriptPath, directory)
-	cmd.Dir = scriptDir
-	cmd.Stderr = os.Stderr
-	log.Debugf("Executing detect script %s on %s : %s", scriptDir, directory, cmd)
-	outputBytes, err := cmd.Output()
+	log.Debugf("Executing detect script %s on %s", scriptPath, directory)
+	outputBytes, err := common.RunCommand(scriptDir, scriptPath, directory)
 	return string(outputBytes), err
 }
 
@@ -52,6 +52,43 @@ pack build {{ .ImageName }} -B {{ .Builder }}
 #   limitations under the License.
 
 docker build -f {{ .Dockerfilename }} -t {{ .ImageName }} {{ .Context }}
+`
+
+	LibertyServer_xml = `<?xml version="1.0" encoding="UTF-8"?>
+<!--
+   Copyright IBM Corporation 2020
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+        http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+-->
+<server description="{{ .ServiceName }}">
+    <featureManager>
+        <feature>javaee-8.0</feature>
+    </featureManager>
+{{- if .DataSources }}
+
+    <!-- The JDBC drivers have to be copied to /config/lib in the image -->
+    <library id="m2kJDBCLib">
+        <fileset dir="${server.config.dir}/lib" includes="*.jar"/>
+    </library>
+{{- range .DataSources }}
+
+    <dataSource id="{{ .EnvVarPrefix }}" jndiName="{{ .Name }}">
+        <jdbcDriver libraryRef="m2kJDBCLib"/>
+        <properties URL="${env.{{ .EnvVarPrefix }}_URL}" user="${env.{{ .EnvVarPrefix }}_USERNAME}" password="${env.{{ .EnvVarPrefix }}_PASSWORD}"/>
+    </dataSource>
+{{- end }}
+{{- end }}
+</server>
 `
 
 	S2IBuilder_sh = `#   Copyright IBM Corporation 2020
@@ -69,6 +106,59 @@ docker build -f {{ .Dockerfilename }} -t {{ .ImageName }} {{ .Context }}
 #   limitations under the License.
 
 s2i build . {{ .Builder }} {{ .ImageName }}
+`
+
+	StaticSite_Dockerfile = `#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+FROM registry.access.redhat.com/ubi8/nodejs-14 AS build
+COPY --chown=1001:0 . .
+RUN npm install && npm run build
+{{- if .Nginx }}
+
+FROM registry.access.redhat.com/ubi8/nginx-118
+COPY --from=build /opt/app-root/src/{{ .BuildDir }} .
+EXPOSE {{ .Port }}
+CMD nginx -g "daemon off;"
+{{- end }}
+`
+
+	WildFlyServer_cli = `#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+embed-server --std-out=echo --server-config=standalone.xml
+{{- if .DataSources }}
+
+# The JDBC driver has to be installed as the module m2kjdbc, for example by copying it to m2kserverconfig and running:
+# module add --name=m2kjdbc --resources=/opt/jboss/wildfly/m2kserverconfig/driver.jar --dependencies=javax.api,javax.transaction.api
+/subsystem=datasources/jdbc-driver=m2kjdbc:add(driver-name=m2kjdbc,driver-module-name=m2kjdbc)
+{{- range .DataSources }}
+data-source add --name={{ .EnvVarPrefix }} --jndi-name=java:/{{ .Name }} --driver-name=m2kjdbc --connection-url=${env.{{ .EnvVarPrefix }}_URL} --user-name=${env.{{ .EnvVarPrefix }}_USERNAME} --password=${env.{{ .EnvVarPrefix }}_PASSWORD}
+{{- end }}
+{{- end }}
+stop-embedded-server
 `
 
 )
\ No newline at end of file
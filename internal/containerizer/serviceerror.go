@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizer
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunServiceStep runs a step of the translation of a service, like its containerization. When the step fails, it is
+// retried, the service is skipped or the translation is aborted, based on common.OnServiceError. The error returned
+// after skipping the service is already reported and recorded in the progress, so the caller only has to skip the service.
+func RunServiceStep(serviceName, step string, run func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil {
+			return nil
+		}
+		codedErr := common.AsError(err)
+		if codedErr == nil {
+			codedErr = common.NewError(common.ContainerizationFailedErrorCode, err, "Unable to %s the service %s .", step, serviceName)
+		}
+		action := common.OnServiceError
+		if action == common.AskOnServiceError {
+			key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigOnErrorKeySegment
+			desc := fmt.Sprintf("Unable to %s the service %s . What do you want to do?", step, serviceName)
+			hints := []string{codedErr.Details(), fmt.Sprintf("The step is retried at most %d times.", common.ServiceErrorRetries)}
+			action = qaengine.FetchSelectAnswer(key, desc, hints, common.SkipOnServiceError, []string{common.RetryOnServiceError, common.SkipOnServiceError, common.AbortOnServiceError})
+		}
+		switch action {
+		case common.RetryOnServiceError:
+			if attempt <= common.ServiceErrorRetries {
+				log.Warnf("Unable to %s the service %s . Retrying. Attempt %d of %d. Error: %q", step, serviceName, attempt, common.ServiceErrorRetries, err)
+				continue
+			}
+			log.Warnf("Unable to %s the service %s after %d retries. Skipping the service.", step, serviceName, common.ServiceErrorRetries)
+		case common.AbortOnServiceError:
+			log.Fatal(codedErr.Details())
+		}
+		common.ReportError(codedErr)
+		failService(serviceName)
+		return codedErr
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+)
+
+func TestRunServiceStep(t *testing.T) {
+	defer func(onServiceError string) { common.OnServiceError = onServiceError }(common.OnServiceError)
+
+	t.Run("retry until the step succeeds", func(t *testing.T) {
+		common.OnServiceError = common.RetryOnServiceError
+		attempts := 0
+		err := containerizer.RunServiceStep("svc1", "containerize", func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("temporary failure")
+			}
+			return nil
+		})
+		if err != nil || attempts != 3 {
+			t.Fatalf("Expected the step to succeed after 3 attempts. Actual: %d attempts Error: %v", attempts, err)
+		}
+	})
+
+	t.Run("skip the service after the retries", func(t *testing.T) {
+		common.OnServiceError = common.RetryOnServiceError
+		attempts := 0
+		err := containerizer.RunServiceStep("svc1", "containerize", func() error {
+			attempts++
+			return errors.New("permanent failure")
+		})
+		if attempts != common.ServiceErrorRetries+1 {
+			t.Fatalf("Expected %d attempts. Actual: %d", common.ServiceErrorRetries+1, attempts)
+		}
+		if codedErr := common.AsError(err); codedErr == nil || codedErr.Code != common.ContainerizationFailedErrorCode {
+			t.Fatalf("Expected the error code %s . Actual: %v", common.ContainerizationFailedErrorCode, err)
+		}
+	})
+
+	t.Run("skip the service without retrying", func(t *testing.T) {
+		common.OnServiceError = common.SkipOnServiceError
+		attempts := 0
+		err := containerizer.RunServiceStep("svc1", "containerize", func() error {
+			attempts++
+			return errors.New("failure")
+		})
+		if err == nil || attempts != 1 {
+			t.Fatalf("Expected the service to be skipped after 1 attempt. Actual: %d attempts Error: %v", attempts, err)
+		}
+	})
+}
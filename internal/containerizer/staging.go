@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+// outputStage commits the artifacts of each service to the output directory as soon as the service is containerized
+var outputStage = struct {
+	outputPath string
+	progress   outputtypes.Progress
+}{}
+
+// InitOutputStage starts recording the progress of the translation of the services of the plan in the output directory.
+// The staging area left by an interrupted translation is removed.
+func InitOutputStage(plan plantypes.Plan, outputPath string) {
+	if previous, err := outputtypes.ReadProgress(outputPath); err == nil && previous.Spec.Status == outputtypes.InProgressProgressStatus {
+		log.Warnf("The previous translation in the output directory %s was interrupted. The services %v were committed and the services %v remained.", outputPath, previous.Spec.Committed, previous.Spec.Remaining)
+	}
+	stagingPath := filepath.Join(outputPath, common.StagingDir)
+	if err := os.RemoveAll(stagingPath); err != nil {
+		log.Warnf("Failed to remove the staging directory at path %s . Error: %q", stagingPath, err)
+	}
+	services := []string{}
+	for serviceName := range plan.Spec.Inputs.Services {
+		services = append(services, serviceName)
+	}
+	sort.Strings(services)
+	outputStage.outputPath = outputPath
+	outputStage.progress = outputtypes.NewProgress(plan.Name, services)
+	writeProgress()
+}
+
+// CommitServiceArtifacts writes the new files of the container of the service to the staging area and then moves them
+// into the source directory of the output. The service is recorded as committed only after all its files are moved.
+func CommitServiceArtifacts(serviceName string, container irtypes.Container) error {
+	if outputStage.outputPath == "" {
+		return nil
+	}
+	stagingPath := filepath.Join(outputStage.outputPath, common.StagingDir, serviceName)
+	if err := os.RemoveAll(stagingPath); err != nil {
+		log.Errorf("Failed to remove the staging directory at path %s . Error: %q", stagingPath, err)
+		return err
+	}
+	relPaths := []string{}
+	for relPath, contents := range container.NewFiles {
+		stagedPath := filepath.Join(stagingPath, relPath)
+		if err := os.MkdirAll(filepath.Dir(stagedPath), common.DefaultDirectoryPermission); err != nil {
+			log.Errorf("Failed to create the directory %s . Error: %q", filepath.Dir(stagedPath), err)
+			return err
+		}
+		fileperm := common.DefaultFilePermission
+		if filepath.Ext(stagedPath) == ".sh" {
+			fileperm = common.DefaultExecutablePermission
+		}
+		if err := ioutil.WriteFile(stagedPath, []byte(contents), fileperm); err != nil {
+			log.Errorf("Failed to stage the file at path %s . Error: %q", stagedPath, err)
+			return err
+		}
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+	sourcePath := filepath.Join(outputStage.outputPath, common.SourceDir)
+	for _, relPath := range relPaths {
+		committedPath := filepath.Join(sourcePath, relPath)
+		if err := os.MkdirAll(filepath.Dir(committedPath), common.DefaultDirectoryPermission); err != nil {
+			log.Errorf("Failed to create the directory %s . Error: %q", filepath.Dir(committedPath), err)
+			return err
+		}
+		if err := os.Rename(filepath.Join(stagingPath, relPath), committedPath); err != nil {
+			log.Errorf("Failed to commit the file at path %s . Error: %q", committedPath, err)
+			return err
+		}
+	}
+	if err := os.RemoveAll(stagingPath); err != nil {
+		log.Warnf("Failed to remove the staging directory at path %s . Error: %q", stagingPath, err)
+	}
+	outputStage.progress.Commit(serviceName)
+	writeProgress()
+	log.Debugf("Committed %d files of the service %s", len(relPaths), serviceName)
+	return nil
+}
+
+// FinishOutputStage records that the translation wrote all its artifacts and removes the staging area
+func FinishOutputStage() {
+	if outputStage.outputPath == "" {
+		return
+	}
+	stagingPath := filepath.Join(outputStage.outputPath, common.StagingDir)
+	if err := os.RemoveAll(stagingPath); err != nil {
+		log.Warnf("Failed to remove the staging directory at path %s . Error: %q", stagingPath, err)
+	}
+	outputStage.progress.Complete()
+	writeProgress()
+	outputStage.outputPath = ""
+}
+
+// failService records that the service was skipped
+func failService(serviceName string) {
+	if outputStage.outputPath == "" {
+		return
+	}
+	outputStage.progress.Fail(serviceName)
+	writeProgress()
+}
+
+func writeProgress() {
+	if err := os.MkdirAll(outputStage.outputPath, common.DefaultDirectoryPermission); err != nil {
+		log.Warnf("Failed to create the output directory at path %s . Error: %q", outputStage.outputPath, err)
+		return
+	}
+	if err := outputtypes.WriteProgress(outputStage.outputPath, outputStage.progress); err != nil {
+		log.Warnf("Failed to write the progress of the translation to the output directory %s . Error: %q", outputStage.outputPath, err)
+	}
+}
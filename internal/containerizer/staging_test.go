@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizer_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestCommitServiceArtifacts(t *testing.T) {
+	defer func(onServiceError string) { common.OnServiceError = onServiceError }(common.OnServiceError)
+	common.OnServiceError = common.SkipOnServiceError
+
+	outputPath := t.TempDir()
+	// The staging area of an interrupted translation is removed
+	leftover := filepath.Join(outputPath, common.StagingDir, "svc1", "Dockerfile")
+	if err := os.MkdirAll(filepath.Dir(leftover), common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Failed to create the staging directory. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(leftover, []byte("FROM partial"), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the staged file. Error: %q", err)
+	}
+
+	plan := plantypes.NewPlan()
+	plan.Name = "myproject"
+	for _, serviceName := range []string{"svc1", "svc2", "svc3", "svc4"} {
+		plan.Spec.Inputs.Services[serviceName] = []plantypes.Service{plantypes.NewService(serviceName, plantypes.Any2KubeTranslation)}
+	}
+	containerizer.InitOutputStage(plan, outputPath)
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Fatalf("Expected the staging area of the interrupted translation to be removed. Error: %v", err)
+	}
+
+	container := irtypes.NewContainer(plantypes.DockerFileContainerBuildTypeValue, "svc1", true)
+	container.AddFile(filepath.Join("svc1", "Dockerfile"), "FROM alpine")
+	container.AddFile(filepath.Join("svc1", "svc1dockerbuild.sh"), "docker build .")
+	if err := containerizer.CommitServiceArtifacts("svc1", container); err != nil {
+		t.Fatalf("Failed to commit the artifacts of svc1. Error: %q", err)
+	}
+	if err := containerizer.RunServiceStep("svc2", "containerize", func() error { return errors.New("failure") }); err == nil {
+		t.Fatal("Expected svc2 to be skipped")
+	}
+
+	dockerfilePath := filepath.Join(outputPath, common.SourceDir, "svc1", "Dockerfile")
+	if contents, err := ioutil.ReadFile(dockerfilePath); err != nil || string(contents) != "FROM alpine" {
+		t.Fatalf("Expected the Dockerfile of svc1 to be committed. Actual: %q Error: %v", contents, err)
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, common.StagingDir, "svc1")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the staging area of svc1 to be removed. Error: %v", err)
+	}
+
+	progress, err := outputtypes.ReadProgress(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read the progress. Error: %q", err)
+	}
+	want := outputtypes.ProgressSpec{
+		Status:    outputtypes.InProgressProgressStatus,
+		Committed: []string{"svc1"},
+		Failed:    []string{"svc2"},
+		Remaining: []string{"svc3", "svc4"},
+	}
+	if !cmp.Equal(progress.Spec, want, cmpopts.EquateEmpty()) {
+		t.Fatalf("Failed to record the progress of the interrupted translation. Expected: %+v Actual: %+v", want, progress.Spec)
+	}
+
+	containerizer.FinishOutputStage()
+	if progress, err = outputtypes.ReadProgress(outputPath); err != nil {
+		t.Fatalf("Failed to read the progress. Error: %q", err)
+	}
+	want = outputtypes.ProgressSpec{
+		Status:    outputtypes.CompletedProgressStatus,
+		Committed: []string{"svc1", "svc3", "svc4"},
+		Failed:    []string{"svc2"},
+	}
+	if !cmp.Equal(progress.Spec, want, cmpopts.EquateEmpty()) {
+		t.Fatalf("Failed to record the progress of the completed translation. Expected: %+v Actual: %+v", want, progress.Spec)
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, common.StagingDir)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the staging directory to be removed. Error: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// cloudAnnotation is an annotation of a cloud provider. If the value is empty, the annotation has any value.
+type cloudAnnotation struct {
+	key   string
+	value string
+}
+
+// cloudAnnotationPrefixes are the prefixes of the annotations of the services and ingresses of each cloud provider
+var cloudAnnotationPrefixes = map[collecttypes.ClusterFlavor][]string{
+	collecttypes.EKSClusterFlavor: {"service.beta.kubernetes.io/aws-", "alb.ingress.kubernetes.io/"},
+	collecttypes.GKEClusterFlavor: {"cloud.google.com/", "networking.gke.io/", "ingress.gcp.kubernetes.io/", "kubernetes.io/ingress.global-static-ip-name"},
+	collecttypes.AKSClusterFlavor: {"service.beta.kubernetes.io/azure-", "appgw.ingress.kubernetes.io/"},
+}
+
+// cloudAnnotationMappings are the equivalent annotations of the cloud providers. Each mapping is a feature of the load
+// balancers, like an internal load balancer, and the annotations which enable it on each cloud provider.
+var cloudAnnotationMappings = []map[collecttypes.ClusterFlavor]cloudAnnotation{{
+	// Internal load balancer
+	collecttypes.EKSClusterFlavor: {key: "service.beta.kubernetes.io/aws-load-balancer-internal", value: "true"},
+	collecttypes.GKEClusterFlavor: {key: "networking.gke.io/load-balancer-type", value: "Internal"},
+	collecttypes.AKSClusterFlavor: {key: "service.beta.kubernetes.io/azure-load-balancer-internal", value: "true"},
+}, {
+	// Subnet of the internal load balancer
+	collecttypes.EKSClusterFlavor: {key: "service.beta.kubernetes.io/aws-load-balancer-subnets"},
+	collecttypes.GKEClusterFlavor: {key: "networking.gke.io/internal-load-balancer-subnet"},
+	collecttypes.AKSClusterFlavor: {key: "service.beta.kubernetes.io/azure-load-balancer-internal-subnet"},
+}, {
+	// Health check path of the load balancer
+	collecttypes.EKSClusterFlavor: {key: "service.beta.kubernetes.io/aws-load-balancer-healthcheck-path"},
+	collecttypes.AKSClusterFlavor: {key: "service.beta.kubernetes.io/azure-load-balancer-health-probe-request-path"},
+}, {
+	// Pods as the targets of the ingress, instead of the nodes
+	collecttypes.EKSClusterFlavor: {key: "alb.ingress.kubernetes.io/target-type", value: "ip"},
+	collecttypes.GKEClusterFlavor: {key: "cloud.google.com/neg", value: `{"ingress": true}`},
+}, {
+	// Internal ingress
+	collecttypes.EKSClusterFlavor: {key: "alb.ingress.kubernetes.io/scheme", value: "internal"},
+	collecttypes.AKSClusterFlavor: {key: "appgw.ingress.kubernetes.io/use-private-ip", value: "true"},
+}, {
+	// Redirect from http to https
+	collecttypes.AKSClusterFlavor: {key: "appgw.ingress.kubernetes.io/ssl-redirect", value: "true"},
+	collecttypes.EKSClusterFlavor: {key: "alb.ingress.kubernetes.io/ssl-redirect", value: "443"},
+}}
+
+// cloudAnnotationCustomizer converts the annotations of the services and ingresses of the source cloud provider to the
+// equivalent annotations of the cloud provider of the target cluster
+type cloudAnnotationCustomizer struct {
+}
+
+// customize converts the annotations of the kubernetes resources used as source, if the target cluster is on a cloud
+func (cc *cloudAnnotationCustomizer) customize(ir *irtypes.IR) error {
+	targetFlavor := ir.TargetClusterSpec.Flavor
+	if _, ok := cloudAnnotationPrefixes[targetFlavor]; !ok {
+		log.Debugf("The annotations of the cloud providers are not converted for the target cluster flavor %q", targetFlavor)
+		return nil
+	}
+	for _, unmapped := range convertCloudAnnotations(ir.CachedObjects, targetFlavor) {
+		err := common.NewError(common.UnmappedAnnotationErrorCode, nil, "%s", unmapped)
+		err.Warning = true
+		common.ReportError(err)
+	}
+	return nil
+}
+
+// convertCloudAnnotations replaces the annotations of the other cloud providers on the services and ingresses with the
+// equivalent annotations of the target cloud provider. The annotations without an equivalent are removed and returned.
+func convertCloudAnnotations(objs []runtime.Object, targetFlavor collecttypes.ClusterFlavor) []string {
+	unmapped := []string{}
+	for _, obj := range objs {
+		kind := getKind(obj)
+		if kind != serviceKind && kind != ingressKind {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		annotations := accessor.GetAnnotations()
+		keys := []string{}
+		for key := range annotations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sourceFlavor, ok := getCloudFlavor(key)
+			if !ok || sourceFlavor == targetFlavor {
+				continue
+			}
+			value := annotations[key]
+			delete(annotations, key)
+			target, ok := getEquivalentCloudAnnotation(cloudAnnotation{key: key, value: value}, sourceFlavor, targetFlavor)
+			if !ok {
+				unmapped = append(unmapped, fmt.Sprintf("The annotation %s of the %s %s has no equivalent on %s and was removed.", key, kind, accessor.GetName(), targetFlavor))
+				continue
+			}
+			if target.value == "" {
+				target.value = value
+			}
+			log.Debugf("Converted the annotation %s of the %s %s to %s", key, kind, accessor.GetName(), target.key)
+			annotations[target.key] = target.value
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		accessor.SetAnnotations(annotations)
+	}
+	return unmapped
+}
+
+// getCloudFlavor returns the cloud provider of the annotation
+func getCloudFlavor(key string) (collecttypes.ClusterFlavor, bool) {
+	for flavor, prefixes := range cloudAnnotationPrefixes {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return flavor, true
+			}
+		}
+	}
+	return "", false
+}
+
+// getEquivalentCloudAnnotation returns the annotation of the target cloud provider equivalent to the annotation
+func getEquivalentCloudAnnotation(annotation cloudAnnotation, sourceFlavor, targetFlavor collecttypes.ClusterFlavor) (cloudAnnotation, bool) {
+	for _, mapping := range cloudAnnotationMappings {
+		source, ok := mapping[sourceFlavor]
+		if !ok || source.key != annotation.key {
+			continue
+		}
+		if source.value != "" && !strings.EqualFold(source.value, annotation.value) {
+			continue
+		}
+		target, ok := mapping[targetFlavor]
+		return target, ok
+	}
+	return cloudAnnotation{}, false
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"reflect"
+	"testing"
+
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestConvertCloudAnnotations(t *testing.T) {
+	service := &corev1.Service{}
+	service.Name = "web"
+	service.Annotations = map[string]string{
+		"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+		"service.beta.kubernetes.io/aws-load-balancer-subnets":  "subnet-1",
+		"service.beta.kubernetes.io/aws-load-balancer-ssl-cert": "arn:aws:acm:cert",
+		"team": "payments",
+	}
+	ingress := &networkingv1.Ingress{}
+	ingress.Name = "web"
+	ingress.Annotations = map[string]string{"alb.ingress.kubernetes.io/target-type": "ip"}
+	configMap := &corev1.ConfigMap{}
+	configMap.Annotations = map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}
+
+	unmapped := convertCloudAnnotations([]runtime.Object{service, ingress, configMap}, collecttypes.GKEClusterFlavor)
+
+	wantService := map[string]string{
+		"networking.gke.io/load-balancer-type":            "Internal",
+		"networking.gke.io/internal-load-balancer-subnet": "subnet-1",
+		"team": "payments",
+	}
+	if !reflect.DeepEqual(service.Annotations, wantService) {
+		t.Fatalf("Expected the annotations of the service to be converted. Expected: %+v Actual: %+v", wantService, service.Annotations)
+	}
+	if !reflect.DeepEqual(ingress.Annotations, map[string]string{"cloud.google.com/neg": `{"ingress": true}`}) {
+		t.Fatalf("Expected the annotations of the ingress to be converted. Actual: %+v", ingress.Annotations)
+	}
+	if len(configMap.Annotations) != 1 {
+		t.Fatalf("Expected only the annotations of the services and ingresses to be converted. Actual: %+v", configMap.Annotations)
+	}
+	// The certificate of the AWS load balancer has no equivalent on GKE
+	if len(unmapped) != 1 {
+		t.Fatalf("Expected 1 annotation without an equivalent. Actual: %q", unmapped)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// cronTODOKey flags the services whose crontab entries are converted to cron jobs
+	cronTODOKey = common.TODOAnnotation + "cron"
+)
+
+// cronJobCustomizer converts the entries of the crontabs run by cron inside the containers to CronJobs, since the
+// entries are not run when the pod is not running, and are run by every replica
+type cronJobCustomizer struct {
+}
+
+// customize asks whether to convert the crontab entries of each service to CronJobs
+func (cc *cronJobCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if len(service.CronEntries) > 0 {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigCronJobsKeySegment
+		desc := fmt.Sprintf("The image of the service %s runs cron with %d crontab entries. Should each entry be converted to a CronJob using the same image?", serviceName, len(service.CronEntries))
+		hints := []string{"Running cron inside a container is an anti-pattern: the entries are run by every replica, and their failures and logs are not visible in the cluster."}
+		if !qaengine.FetchBoolAnswer(key, desc, hints, true) {
+			continue
+		}
+		for i, entry := range service.CronEntries {
+			cronJobName := common.NormalizeForServiceName(fmt.Sprintf("%s-cron-%d", serviceName, i+1))
+			if _, ok := ir.Services[cronJobName]; ok {
+				log.Warnf("Unable to convert the crontab entry %q of the service %s to a CronJob, since the service %s already exists.", entry.Schedule+" "+entry.Command, serviceName, cronJobName)
+				continue
+			}
+			ir.Services[cronJobName] = getCronJobService(service, cronJobName, entry)
+			log.Debugf("Converted the crontab entry %q of the service %s to the CronJob %s", entry.Schedule+" "+entry.Command, serviceName, cronJobName)
+		}
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+			cronTODOKey: "The crontab entries are run by CronJobs. Remove cron and the crontabs from the image, or remove the service if it only runs cron.",
+		})
+		service.CronEntries = nil
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
+
+// getCronJobService returns a service which runs the command of the crontab entry on its schedule, in the main container of the service
+func getCronJobService(service irtypes.Service, name string, entry irtypes.CronEntry) irtypes.Service {
+	cronJob := irtypes.NewServiceWithName(name)
+	cronJob.PodSpec = *service.PodSpec.DeepCopy()
+	cronJob.RestartPolicy = core.RestartPolicyOnFailure
+	cronJob.Schedule = entry.Schedule
+	cronJob.Networks = service.Networks
+	cronJob.Annotations = common.MergeStringMaps(nil, service.Annotations)
+	delete(cronJob.Annotations, common.ExposeSelector)
+	if len(cronJob.Containers) > 0 {
+		container := cronJob.Containers[0]
+		container.Name = name
+		container.Command = []string{"/bin/sh", "-c", entry.Command}
+		container.Args = nil
+		container.Ports = nil
+		container.LivenessProbe = nil
+		container.ReadinessProbe = nil
+		container.StartupProbe = nil
+		container.Env = append(container.Env, entry.Env...)
+		cronJob.Containers = []core.Container{container}
+	}
+	return cronJob
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"reflect"
+	"testing"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetCronJobService(t *testing.T) {
+	service := irtypes.NewServiceWithName("web")
+	service.Annotations = map[string]string{common.ExposeSelector: "true"}
+	service.Containers = []core.Container{{
+		Name:          "web",
+		Image:         "web:latest",
+		Args:          []string{"cron", "-f"},
+		Ports:         []core.ContainerPort{{ContainerPort: 8080}},
+		LivenessProbe: &core.Probe{},
+		Env:           []core.EnvVar{{Name: "DB_HOST", Value: "db"}},
+	}}
+	entry := irtypes.CronEntry{Schedule: "@daily", Command: "/app/report.sh", Env: []core.EnvVar{{Name: "PATH", Value: "/app/bin:/bin"}}}
+
+	cronJob := getCronJobService(service, "web-cron-1", entry)
+	if cronJob.Schedule != "@daily" || cronJob.RestartPolicy != core.RestartPolicyOnFailure {
+		t.Fatalf("Expected a cron job run daily. Actual: %+v", cronJob)
+	}
+	if cronJob.HasValidAnnotation(common.ExposeSelector) {
+		t.Fatalf("Expected the cron job not to be exposed")
+	}
+	if len(cronJob.Containers) != 1 {
+		t.Fatalf("Expected 1 container. Actual: %+v", cronJob.Containers)
+	}
+	container := cronJob.Containers[0]
+	wantEnv := []core.EnvVar{{Name: "DB_HOST", Value: "db"}, {Name: "PATH", Value: "/app/bin:/bin"}}
+	if container.Image != "web:latest" || !reflect.DeepEqual(container.Command, []string{"/bin/sh", "-c", "/app/report.sh"}) || container.Args != nil || container.Ports != nil || container.LivenessProbe != nil || !reflect.DeepEqual(container.Env, wantEnv) {
+		t.Fatalf("Failed to get the container of the cron job properly. Actual: %+v", container)
+	}
+	if len(service.Containers[0].Env) != 1 || service.Containers[0].Ports == nil {
+		t.Fatalf("Expected the service to be unchanged. Actual: %+v", service.Containers[0])
+	}
+}
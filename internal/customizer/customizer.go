@@ -28,7 +28,7 @@ type customizer interface {
 
 //GetCustomizers gets the customizers registered with it
 func getCustomizers() []customizer {
-	return []customizer{new(registryCustomizer), new(storageCustomizer), new(ingressCustomizer)}
+	return []customizer{new(namespaceCustomizer), new(cloudAnnotationCustomizer), new(imageRewriteCustomizer), new(registryCustomizer), new(cronJobCustomizer), new(processCustomizer), new(timezoneCustomizer), new(storageCustomizer), new(volumeMigrationCustomizer), new(envCustomizer), new(sessionCustomizer), new(daprCustomizer), new(protocolCustomizer), new(portExposureCustomizer), new(rolloutCustomizer), new(schedulingCustomizer), new(lifecycleCustomizer), new(ingressCustomizer), new(devExperienceCustomizer)}
 }
 
 //Customize invokes the customizes based on the customizer options
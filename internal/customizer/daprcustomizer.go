@@ -0,0 +1,316 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	daprStateStoreComponent = "statestore"
+	daprPubSubComponent     = "pubsub"
+
+	daprStateBuildingBlock  = "state"
+	daprPubSubBuildingBlock = "pubsub"
+
+	daprKeepComponentOption   = "Keep the component of the source"
+	daprExternalBackendPrefix = "An external "
+
+	daprSourceComponentTODO = "The metadata of the component was copied from the source. Check that it points to the backing services of the target cluster."
+)
+
+// daprBackend is a backing service that can be used by a Dapr building block
+type daprBackend struct {
+	name          string
+	componentType string
+	images        []string // Images of the backing service, used to find the services running it in the cluster
+	port          int32
+	addressKey    string // Metadata holding the address of the backing service
+	addressFormat string // Format of the address, given the host and the port
+	// addressInSecret is true when the address is a connection string which may hold credentials
+	addressInSecret bool
+	// secretKey is the metadata holding the credentials of the external backing service
+	secretKey   string
+	metadata    map[string]string
+	serviceTODO string // Step to be done manually when the backing service runs in the cluster
+}
+
+// daprBackends are the backing services which can be chosen, per Dapr building block
+var daprBackends = map[string][]daprBackend{
+	daprStateBuildingBlock: {
+		{name: "Redis", componentType: "state.redis", images: []string{"redis"}, port: 6379, addressKey: "redisHost", addressFormat: "%s:%d", secretKey: "redisPassword"},
+		{name: "PostgreSQL", componentType: "state.postgresql", images: []string{"postgres"}, port: 5432, addressKey: "connectionString", addressFormat: "host=%s port=%d user=postgres password= dbname=postgres", addressInSecret: true, serviceTODO: "Set the user, the password and the database of the connectionString metadata."},
+		{name: "MongoDB", componentType: "state.mongodb", images: []string{"mongo"}, port: 27017, addressKey: "host", addressFormat: "%s:%d", secretKey: "password"},
+	},
+	daprPubSubBuildingBlock: {
+		{name: "Redis Streams", componentType: "pubsub.redis", images: []string{"redis"}, port: 6379, addressKey: "redisHost", addressFormat: "%s:%d", secretKey: "redisPassword"},
+		{name: "Kafka", componentType: "pubsub.kafka", images: []string{"kafka"}, port: 9092, addressKey: "brokers", addressFormat: "%s:%d", metadata: map[string]string{"authType": "none"}},
+		{name: "RabbitMQ", componentType: "pubsub.rabbitmq", images: []string{"rabbitmq"}, port: 5672, addressKey: "connectionString", addressFormat: "amqp://%s:%d", addressInSecret: true},
+	},
+}
+
+// daprBuildingBlockNames are the descriptions of the building blocks used in the questions
+var daprBuildingBlockNames = map[string]string{
+	daprStateBuildingBlock:  "state store",
+	daprPubSubBuildingBlock: "pub/sub",
+}
+
+// daprBackendService is a service of the cluster running a backing service
+type daprBackendService struct {
+	name string
+	port int32
+}
+
+// daprCustomizer injects the Dapr sidecar into the pods of the services which use Dapr, and creates the Dapr components
+// connecting the sidecars to the backing services of the target cluster
+type daprCustomizer struct {
+}
+
+// customize asks which services get the Dapr sidecar, and which backing service is used by each Dapr component
+func (dc *daprCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if len(service.DaprHints) > 0 || service.HasValidAnnotation(common.DaprEnabledAnnotation) {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	daprServiceNames := []string{}
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		if !service.HasValidAnnotation(common.DaprEnabledAnnotation) {
+			key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigDaprKeySegment
+			desc := fmt.Sprintf("The service %s seems to use Dapr. Should the Dapr sidecar be injected into its pods?", serviceName)
+			hints := []string{"Found: " + strings.Join(service.DaprHints, ", ")}
+			if !qaengine.FetchBoolAnswer(key, desc, hints, true) {
+				continue
+			}
+			service.Annotations = common.MergeStringMaps(service.Annotations, getDaprSidecarAnnotations(service))
+			ir.Services[serviceName] = service
+		}
+		daprServiceNames = append(daprServiceNames, serviceName)
+	}
+	if len(daprServiceNames) == 0 {
+		return nil
+	}
+	if len(ir.DaprComponents) == 0 {
+		desc := "Which Dapr components do the services use?"
+		hints := []string{"No Dapr component was found in the source. A backing service is chosen for each component."}
+		defaults := []string{daprStateStoreComponent, daprPubSubComponent}
+		for _, name := range qaengine.FetchMultiSelectAnswer(common.ConfigDaprComponentsKey, desc, hints, defaults, defaults) {
+			ir.DaprComponents = append(ir.DaprComponents, irtypes.DaprComponent{Name: name})
+		}
+	}
+	for i, component := range ir.DaprComponents {
+		ir.DaprComponents[i] = dc.configureComponent(ir, component)
+	}
+	log.Warnf("The services %s use Dapr. Install Dapr on the target cluster before deploying them.", strings.Join(daprServiceNames, ", "))
+	return nil
+}
+
+// configureComponent asks which backing service is used by the component, among the services of the cluster and the external services
+func (dc *daprCustomizer) configureComponent(ir *irtypes.IR, component irtypes.DaprComponent) irtypes.DaprComponent {
+	block := getDaprBuildingBlock(component)
+	backends, ok := daprBackends[block]
+	if !ok {
+		if component.TODO == "" {
+			component.TODO = daprSourceComponentTODO
+		}
+		return component
+	}
+	options := []string{}
+	optionBackends := map[string]daprBackend{}
+	optionServices := map[string]daprBackendService{}
+	def := ""
+	for _, backend := range backends {
+		for _, backendService := range getDaprBackendServices(ir, backend) {
+			option := fmt.Sprintf("%s running as the service %s", backend.name, backendService.name)
+			options = append(options, option)
+			optionBackends[option] = backend
+			optionServices[option] = backendService
+			if def == "" && backend.componentType == component.Type {
+				def = option
+			}
+		}
+	}
+	for _, backend := range backends {
+		option := daprExternalBackendPrefix + backend.name
+		options = append(options, option)
+		optionBackends[option] = backend
+	}
+	if component.Type != "" {
+		options = append(options, daprKeepComponentOption)
+		if def == "" {
+			def = daprKeepComponentOption
+		}
+	}
+	if def == "" {
+		def = options[0]
+	}
+	key := common.ConfigDaprComponentsKey + common.Delim + `"` + component.Name + `"` + common.Delim + common.ConfigDaprBackendKeySegment
+	desc := fmt.Sprintf("Which backing service should the Dapr %s component %s use?", daprBuildingBlockNames[block], component.Name)
+	hints := []string{"The backing services running in the cluster are found using their images."}
+	answer := qaengine.FetchSelectAnswer(key, desc, hints, def, options)
+	if answer == daprKeepComponentOption {
+		component.TODO = daprSourceComponentTODO
+		return component
+	}
+	backend, ok := optionBackends[answer]
+	if !ok {
+		log.Warnf("Unknown backing service %s for the Dapr component %s. Keeping the component.", answer, component.Name)
+		return component
+	}
+	if backendService, ok := optionServices[answer]; ok {
+		return getDaprServiceComponent(component.Name, backend, backendService)
+	}
+	hostKey := common.ConfigDaprComponentsKey + common.Delim + `"` + component.Name + `"` + common.Delim + common.ConfigDaprHostKeySegment
+	hostDesc := fmt.Sprintf("Provide the host of the external %s used by the Dapr component %s", backend.name, component.Name)
+	host := qaengine.FetchStringAnswer(hostKey, hostDesc, []string{"Leave it empty to set it later."}, "")
+	externalComponent, secret := getDaprExternalComponent(component.Name, backend, host)
+	if len(secret.Content) > 0 {
+		ir.AddStorage(secret)
+	}
+	return externalComponent
+}
+
+// getDaprSidecarAnnotations returns the annotations injecting the Dapr sidecar, which calls the app on its first port
+func getDaprSidecarAnnotations(service irtypes.Service) map[string]string {
+	annotations := map[string]string{
+		common.DaprEnabledAnnotation: common.AnnotationLabelValue,
+		common.DaprAppIDAnnotation:   service.Name,
+	}
+	if len(service.Containers) > 0 && len(service.Containers[0].Ports) > 0 {
+		annotations[common.DaprAppPortAnnotation] = strconv.Itoa(int(service.Containers[0].Ports[0].ContainerPort))
+	}
+	return annotations
+}
+
+// getDaprBuildingBlock returns the building block of the component, using its type if known, else its name
+func getDaprBuildingBlock(component irtypes.DaprComponent) string {
+	if component.Type != "" {
+		return strings.SplitN(component.Type, ".", 2)[0]
+	}
+	if strings.Contains(component.Name, daprPubSubComponent) {
+		return daprPubSubBuildingBlock
+	}
+	return daprStateBuildingBlock
+}
+
+// getDaprBackendServices returns the services whose image is the image of the backing service, along with the port on which it is served
+func getDaprBackendServices(ir *irtypes.IR, backend daprBackend) []daprBackendService {
+	backendServices := []daprBackendService{}
+	for serviceName, service := range ir.Services {
+		for _, container := range service.Containers {
+			if !isDaprBackendImage(container.Image, backend) {
+				continue
+			}
+			port := backend.port
+			if len(container.Ports) > 0 {
+				port = container.Ports[0].ContainerPort
+			}
+			for _, containerPort := range container.Ports {
+				if containerPort.ContainerPort == backend.port {
+					port = backend.port
+				}
+			}
+			backendServices = append(backendServices, daprBackendService{name: serviceName, port: port})
+			break
+		}
+	}
+	sort.Slice(backendServices, func(i, j int) bool { return backendServices[i].name < backendServices[j].name })
+	return backendServices
+}
+
+// isDaprBackendImage checks if the name of the image, without the registry and the tag, contains one of the images of the backing service
+func isDaprBackendImage(image string, backend daprBackend) bool {
+	name := strings.ToLower(strings.SplitN(image, "@", 2)[0])
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	name = path.Base(name)
+	for _, backendImage := range backend.images {
+		if strings.Contains(name, backendImage) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDaprServiceComponent returns the component using the backing service running in the cluster
+func getDaprServiceComponent(name string, backend daprBackend, backendService daprBackendService) irtypes.DaprComponent {
+	component := irtypes.DaprComponent{Name: name, Type: backend.componentType, Version: "v1", TODO: backend.serviceTODO}
+	component.Metadata = append(component.Metadata, irtypes.DaprMetadata{Name: backend.addressKey, Value: fmt.Sprintf(backend.addressFormat, backendService.name, backendService.port)})
+	component.Metadata = append(component.Metadata, getDaprBackendMetadata(backend)...)
+	return component
+}
+
+// getDaprExternalComponent returns the component using an external backing service, along with the secret holding its credentials
+func getDaprExternalComponent(name string, backend daprBackend, host string) (irtypes.DaprComponent, irtypes.Storage) {
+	secretName := common.NormalizeForServiceName(name + "-secrets")
+	secret := irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: map[string][]byte{}}
+	component := irtypes.DaprComponent{Name: name, Type: backend.componentType, Version: "v1"}
+	todos := []string{}
+	address := ""
+	if host != "" {
+		address = fmt.Sprintf(backend.addressFormat, host, backend.port)
+	}
+	if backend.addressInSecret {
+		secret.Content[backend.addressKey] = []byte(address)
+		component.Metadata = append(component.Metadata, irtypes.DaprMetadata{Name: backend.addressKey, SecretName: secretName, SecretKey: backend.addressKey})
+		if address == "" {
+			todos = append(todos, fmt.Sprintf("Set the %s of the external %s in the secret %s.", backend.addressKey, backend.name, secretName))
+		} else {
+			todos = append(todos, fmt.Sprintf("Check the %s of the external %s in the secret %s.", backend.addressKey, backend.name, secretName))
+		}
+	} else {
+		component.Metadata = append(component.Metadata, irtypes.DaprMetadata{Name: backend.addressKey, Value: address})
+		if address == "" {
+			todos = append(todos, fmt.Sprintf("Set the %s metadata to the address of the external %s.", backend.addressKey, backend.name))
+		}
+	}
+	if backend.secretKey != "" {
+		secret.Content[backend.secretKey] = []byte{}
+		component.Metadata = append(component.Metadata, irtypes.DaprMetadata{Name: backend.secretKey, SecretName: secretName, SecretKey: backend.secretKey})
+		todos = append(todos, fmt.Sprintf("Set the %s of the external %s in the secret %s.", backend.secretKey, backend.name, secretName))
+	}
+	component.Metadata = append(component.Metadata, getDaprBackendMetadata(backend)...)
+	component.TODO = strings.Join(todos, " ")
+	return component, secret
+}
+
+// getDaprBackendMetadata returns the fixed metadata of the backing service, sorted by name
+func getDaprBackendMetadata(backend daprBackend) []irtypes.DaprMetadata {
+	names := []string{}
+	for name := range backend.metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metadata := []irtypes.DaprMetadata{}
+	for _, name := range names {
+		metadata = append(metadata, irtypes.DaprMetadata{Name: name, Value: backend.metadata[name]})
+	}
+	return metadata
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"reflect"
+	"testing"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetDaprSidecarAnnotations(t *testing.T) {
+	service := irtypes.NewServiceWithName("tickets")
+	service.Containers = []core.Container{{Name: "tickets", Ports: []core.ContainerPort{{ContainerPort: 3000}}}}
+	want := map[string]string{common.DaprEnabledAnnotation: "true", common.DaprAppIDAnnotation: "tickets", common.DaprAppPortAnnotation: "3000"}
+	if annotations := getDaprSidecarAnnotations(service); !reflect.DeepEqual(annotations, want) {
+		t.Fatalf("Failed to get the Dapr sidecar annotations. Expected: %v Actual: %v", want, annotations)
+	}
+}
+
+func TestGetDaprBackendServices(t *testing.T) {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	for name, image := range map[string]string{"cache": "docker.io/bitnami/redis:6.2", "queue": "rabbitmq:3-management", "tickets": "tickets:latest"} {
+		service := irtypes.NewServiceWithName(name)
+		service.Containers = []core.Container{{Name: name, Image: image}}
+		ir.Services[name] = service
+	}
+	redis := daprBackends[daprStateBuildingBlock][0]
+	want := []daprBackendService{{name: "cache", port: 6379}}
+	if backendServices := getDaprBackendServices(&ir, redis); !reflect.DeepEqual(backendServices, want) {
+		t.Fatalf("Failed to find the Redis services. Expected: %+v Actual: %+v", want, backendServices)
+	}
+	if building := getDaprBuildingBlock(irtypes.DaprComponent{Name: "orders-pubsub"}); building != daprPubSubBuildingBlock {
+		t.Fatalf("Expected the pub/sub building block. Actual: %s", building)
+	}
+
+	component := getDaprServiceComponent("statestore", redis, want[0])
+	wantComponent := irtypes.DaprComponent{Name: "statestore", Type: "state.redis", Version: "v1", Metadata: []irtypes.DaprMetadata{{Name: "redisHost", Value: "cache:6379"}}}
+	if !reflect.DeepEqual(component, wantComponent) {
+		t.Fatalf("Failed to create the component using the Redis service. Expected: %+v Actual: %+v", wantComponent, component)
+	}
+}
+
+func TestGetDaprExternalComponent(t *testing.T) {
+	rabbitmq := daprBackends[daprPubSubBuildingBlock][2]
+	component, secret := getDaprExternalComponent("pubsub", rabbitmq, "mq.example.com")
+	wantMetadata := []irtypes.DaprMetadata{{Name: "connectionString", SecretName: "pubsub-secrets", SecretKey: "connectionString"}}
+	if component.Type != "pubsub.rabbitmq" || !reflect.DeepEqual(component.Metadata, wantMetadata) || component.TODO == "" {
+		t.Fatalf("Failed to create the component using the external RabbitMQ. Actual: %+v", component)
+	}
+	if secret.Name != "pubsub-secrets" || string(secret.Content["connectionString"]) != "amqp://mq.example.com:5672" {
+		t.Fatalf("Failed to store the connection string in the secret. Actual: %+v", secret)
+	}
+
+	kafka := daprBackends[daprPubSubBuildingBlock][1]
+	component, secret = getDaprExternalComponent("pubsub", kafka, "")
+	wantMetadata = []irtypes.DaprMetadata{{Name: "brokers"}, {Name: "authType", Value: "none"}}
+	if !reflect.DeepEqual(component.Metadata, wantMetadata) || component.TODO != "Set the brokers metadata to the address of the external Kafka." || len(secret.Content) != 0 {
+		t.Fatalf("Failed to create the component using the external Kafka. Actual: %+v %+v", component, secret)
+	}
+}
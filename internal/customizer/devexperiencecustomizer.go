@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+// devExperienceCustomizer chooses the tools for which the configs of the inner loop developer workflow are generated
+type devExperienceCustomizer struct {
+}
+
+// customize asks for the developer workflow tools, if any image is built using a Dockerfile
+func (dc *devExperienceCustomizer) customize(ir *irtypes.IR) error {
+	anyDockerfiles := false
+	for _, container := range ir.Containers {
+		if container.New && (container.ContainerBuildType == plantypes.DockerFileContainerBuildTypeValue || container.ContainerBuildType == plantypes.ReuseDockerFileContainerBuildTypeValue) {
+			anyDockerfiles = true
+			break
+		}
+	}
+	if !anyDockerfiles {
+		return nil
+	}
+	tools := []string{string(irtypes.SkaffoldDevTool), string(irtypes.TiltDevTool), string(irtypes.DevSpaceDevTool), string(irtypes.ComposeDevTool), string(irtypes.DevContainerDevTool)}
+	hints := []string{
+		"The configs are written to the root of the output directory. Skaffold, Tilt and DevSpace rebuild the images using the Dockerfiles and redeploy the yamls on each change.",
+		"DockerCompose and DevContainer run the services locally, with the source mounted for hot reload and the debug ports published.",
+	}
+	answers := qaengine.FetchMultiSelectAnswer(common.ConfigDevExperienceToolsKey, "Select the tools for which the configs of the developer workflow should be generated", hints, []string{string(irtypes.SkaffoldDevTool)}, tools)
+	ir.DevTools = []irtypes.DevToolType{}
+	for _, answer := range answers {
+		ir.DevTools = append(ir.DevTools, irtypes.DevToolType(answer))
+	}
+	return nil
+}
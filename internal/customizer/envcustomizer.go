@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	envSecretSuffix = "-env"
+)
+
+// secretEnvRegex matches the names of the env vars that are likely to contain secrets
+var secretEnvRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private|credential)`)
+
+//envCustomizer lets the user review the env vars of the services, since the source artifacts often contain stale or per developer values
+type envCustomizer struct {
+}
+
+//customize asks the user to edit the env vars of each service and to select the ones that should be stored in a secret
+func (ec *envCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		origNames, origValues := ec.getEnvVars(service)
+		if len(origNames) == 0 {
+			continue
+		}
+		lines := []string{}
+		for _, name := range origNames {
+			lines = append(lines, name+"="+origValues[name])
+		}
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigEnvKeySegment
+		desc := fmt.Sprintf("Edit the environment variables of the service %s:", serviceName)
+		hints := []string{"One variable per line in the format NAME=value. Remove a line to remove the variable.", "The values come from the source artifacts and may be stale or specific to a developer machine."}
+		answer := qaengine.FetchMultilineAnswer(key, desc, hints, strings.Join(lines, "\n"))
+		names, values := ec.parseEnvVars(serviceName, answer)
+		secretNames := []string{}
+		if len(names) > 0 {
+			defSecretNames := []string{}
+			for _, name := range names {
+				if secretEnvRegex.MatchString(name) {
+					defSecretNames = append(defSecretNames, name)
+				}
+			}
+			key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigSecretEnvKeySegment
+			desc := fmt.Sprintf("Select the environment variables of the service %s that should be stored in a secret:", serviceName)
+			hints := []string{"The selected variables are read from a secret instead of being written in the deployment."}
+			secretNames = qaengine.FetchMultiSelectAnswer(key, desc, hints, defSecretNames, names)
+		}
+		ec.setEnvVars(ir, &service, origValues, names, values, secretNames)
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
+
+// getEnvVars returns the names, in the order in which they appear, and the values of the env vars of all the containers of the service.
+// The env vars which get their values from other sources are not returned.
+func (ec *envCustomizer) getEnvVars(service irtypes.Service) ([]string, map[string]string) {
+	names := []string{}
+	values := map[string]string{}
+	for _, container := range service.Containers {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				continue
+			}
+			if _, ok := values[env.Name]; !ok {
+				names = append(names, env.Name)
+			}
+			values[env.Name] = env.Value
+		}
+	}
+	return names, values
+}
+
+// parseEnvVars parses the NAME=value lines of the answer
+func (ec *envCustomizer) parseEnvVars(serviceName, answer string) ([]string, map[string]string) {
+	names := []string{}
+	values := map[string]string{}
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			log.Warnf("Ignoring the invalid environment variable %q of the service %s . Expected the format NAME=value", line, serviceName)
+			continue
+		}
+		name := strings.TrimSpace(line[:idx])
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+		values[name] = line[idx+1:]
+	}
+	return names, values
+}
+
+// setEnvVars replaces the env vars of the containers of the service with the edited ones.
+// The env vars added by the user are added to all the containers and the secret env vars are moved into a secret.
+func (ec *envCustomizer) setEnvVars(ir *irtypes.IR, service *irtypes.Service, origValues map[string]string, names []string, values map[string]string, secretNames []string) {
+	secretName := common.MakeFileNameCompliant(service.Name + envSecretSuffix)
+	getEnvVar := func(name string) core.EnvVar {
+		if common.IsStringPresent(secretNames, name) {
+			return core.EnvVar{Name: name, ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: secretName},
+				Key:                  name,
+			}}}
+		}
+		return core.EnvVar{Name: name, Value: values[name]}
+	}
+	for i, container := range service.Containers {
+		envs := []core.EnvVar{}
+		seen := map[string]bool{}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				envs = append(envs, env)
+				continue
+			}
+			if _, ok := values[env.Name]; !ok || seen[env.Name] {
+				continue
+			}
+			seen[env.Name] = true
+			envs = append(envs, getEnvVar(env.Name))
+		}
+		for _, name := range names {
+			if _, ok := origValues[name]; !ok && !seen[name] {
+				envs = append(envs, getEnvVar(name))
+			}
+		}
+		service.Containers[i].Env = envs
+	}
+	if len(secretNames) == 0 {
+		return
+	}
+	content := map[string][]byte{}
+	for _, name := range secretNames {
+		content[name] = []byte(values[name])
+	}
+	ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: content})
+	log.Debugf("Moved the environment variables %v of the service %s into the secret %s", secretNames, service.Name, secretName)
+}
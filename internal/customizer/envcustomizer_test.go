@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestParseEnvVars(t *testing.T) {
+	answer := "PORT=8080\n\n# a comment\n  DB_URL = postgres://db:5432/tickets?ssl=true  \ninvalid\n=novalue\nEMPTY=\nPORT=9090\n"
+	names, values := new(envCustomizer).parseEnvVars("svc1", answer)
+	wantNames := []string{"PORT", "DB_URL", "EMPTY"}
+	wantValues := map[string]string{"PORT": "9090", "DB_URL": " postgres://db:5432/tickets?ssl=true", "EMPTY": ""}
+	if !reflect.DeepEqual(names, wantNames) || !reflect.DeepEqual(values, wantValues) {
+		t.Fatalf("Expected the env vars %v %v . Actual: %v %v", wantNames, wantValues, names, values)
+	}
+}
+
+func TestEnvCustomizer(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	config := `move2kube:
+  services:
+    svc1:
+      env: |
+        PORT=9090
+        DB_PASSWORD=s3cret
+        # added by the user
+        LOG_LEVEL=debug
+      secretenv:
+        - DB_PASSWORD
+`
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write the config file %s . Error: %q", configPath, err)
+	}
+	qaengine.AddEngine(qaengine.NewDefaultEngine())
+	qaengine.SetupConfigFile(t.TempDir(), nil, []string{configPath}, nil)
+
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	service := irtypes.NewServiceWithName("svc1")
+	podName := &core.EnvVarSource{FieldRef: &core.ObjectFieldSelector{FieldPath: "metadata.name"}}
+	service.Containers = []core.Container{{
+		Name: "svc1",
+		Env: []core.EnvVar{
+			{Name: "PORT", Value: "8080"},
+			{Name: "DB_PASSWORD", Value: "password"},
+			{Name: "DEV_HOME", Value: "/home/dev"},
+			{Name: "POD_NAME", ValueFrom: podName},
+		},
+	}}
+	ir.Services["svc1"] = service
+
+	if err := new(envCustomizer).customize(&ir); err != nil {
+		t.Fatalf("Failed to customize the env vars. Error: %q", err)
+	}
+	wantEnv := []core.EnvVar{
+		{Name: "PORT", Value: "9090"},
+		{Name: "DB_PASSWORD", ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+			LocalObjectReference: core.LocalObjectReference{Name: "svc1-env"},
+			Key:                  "DB_PASSWORD",
+		}}},
+		{Name: "POD_NAME", ValueFrom: podName},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	}
+	if env := ir.Services["svc1"].Containers[0].Env; !reflect.DeepEqual(env, wantEnv) {
+		t.Fatalf("Expected the env vars %+v . Actual: %+v", wantEnv, env)
+	}
+	wantStorages := []irtypes.Storage{{Name: "svc1-env", StorageType: irtypes.SecretKind, Content: map[string][]byte{"DB_PASSWORD": []byte("s3cret")}}}
+	if !reflect.DeepEqual(ir.Storages, wantStorages) {
+		t.Fatalf("Expected the storages %+v . Actual: %+v", wantStorages, ir.Storages)
+	}
+}
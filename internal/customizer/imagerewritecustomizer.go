@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// containerListKeys are the keys of the lists of containers in a pod spec
+var containerListKeys = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// imageRewriteCustomizer rewrites the images of the existing kubernetes artifacts to a new registry, so that the
+// artifacts can be moved between clusters which do not have access to the same registries
+type imageRewriteCustomizer struct {
+}
+
+// customize asks whether the images of the kubernetes artifacts should be moved to a new registry and rewrites them
+func (ic *imageRewriteCustomizer) customize(ir *irtypes.IR) error {
+	images := []string{}
+	for _, obj := range ir.CachedObjects {
+		updatePodSpecs(obj, func(podSpec map[string]interface{}) bool {
+			for _, container := range getPodSpecContainers(podSpec) {
+				if image, ok := container["image"].(string); ok && image != "" {
+					images = append(images, image)
+				}
+			}
+			return false
+		})
+	}
+	if len(images) == 0 {
+		return nil
+	}
+	images = common.UniqueStrings(images)
+	hints := []string{"The images will have to be copied to the new registry using the scripts/mirrorimages.sh script.", "Images used: " + strings.Join(images, ", ")}
+	if !qaengine.FetchBoolAnswer(common.ConfigImageRegistryRewriteKey, "Rewrite the images of the Kubernetes artifacts to use a new image registry?", hints, false) {
+		return nil
+	}
+	if ir.Kubernetes.RegistryURL == "" {
+		ir.Kubernetes.RegistryURL = qaengine.FetchStringAnswer(common.ConfigImageRegistryURLKey, "Enter the name of the image registry : ", []string{"You can always change it later by changing the yamls."}, common.DefaultRegistryURL)
+	}
+	if ir.Kubernetes.RegistryNamespace == "" {
+		ir.Kubernetes.RegistryNamespace = qaengine.FetchStringAnswer(common.ConfigImageRegistryNamespaceKey, "Enter the namespace where the new images should be pushed : ", []string{"Ex : " + ir.Name}, ir.Name)
+	}
+	preserveDigests := qaengine.FetchBoolAnswer(common.ConfigImageRegistryPreserveDigestsKey, "Keep the digests of the images which are pinned to a digest?", []string{"The digests stay the same if the images are copied without modifications."}, true)
+	mirrors := rewriteImages(ir.CachedObjects, ir.Kubernetes.RegistryURL, ir.Kubernetes.RegistryNamespace, preserveDigests)
+	if ir.ImageMirrors == nil {
+		ir.ImageMirrors = map[string]string{}
+	}
+	for source, target := range mirrors {
+		ir.ImageMirrors[source] = target
+	}
+	log.Debugf("Rewrote the images of the kubernetes artifacts to the registry %s/%s : %v", ir.Kubernetes.RegistryURL, ir.Kubernetes.RegistryNamespace, mirrors)
+	return nil
+}
+
+// rewriteImages moves the images of the objects to the registry and the namespace, retaining the repositories and the tags,
+// and returns the images which have to be copied to the new registry. The digests are retained only if preserveDigests is true.
+func rewriteImages(objs []runtime.Object, registry string, namespace string, preserveDigests bool) map[string]string {
+	mirrors := map[string]string{}
+	for i, obj := range objs {
+		objs[i] = updatePodSpecs(obj, func(podSpec map[string]interface{}) bool {
+			updated := false
+			for _, container := range getPodSpecContainers(podSpec) {
+				image, ok := container["image"].(string)
+				if !ok || image == "" {
+					continue
+				}
+				ref := common.ParseImageReference(image)
+				if ref.Registry == registry && ref.Namespace == namespace {
+					continue
+				}
+				target := ref.WithRegistry(registry, namespace)
+				if !preserveDigests && target.Digest != "" {
+					target.Digest = ""
+					if target.Tag == "" {
+						target.Tag = "latest"
+					}
+				}
+				container["image"] = target.String()
+				mirrors[image] = target.String()
+				updated = true
+			}
+			return updated
+		})
+	}
+	return mirrors
+}
+
+// addImagePullSecrets adds the pull secrets of the registries of the images to the pod specs of the objects
+func addImagePullSecrets(objs []runtime.Object, imagePullSecrets map[string]string) {
+	for i, obj := range objs {
+		objs[i] = updatePodSpecs(obj, func(podSpec map[string]interface{}) bool {
+			secrets, _ := podSpec["imagePullSecrets"].([]interface{})
+			updated := false
+			for _, container := range getPodSpecContainers(podSpec) {
+				image, ok := container["image"].(string)
+				if !ok {
+					continue
+				}
+				ps, ok := imagePullSecrets[common.GetImageRegistry(image)]
+				if !ok || ps == "" {
+					continue
+				}
+				found := false
+				for _, secret := range secrets {
+					if secretMap, ok := secret.(map[string]interface{}); ok && secretMap["name"] == ps {
+						found = true
+						break
+					}
+				}
+				if !found {
+					secrets = append(secrets, map[string]interface{}{"name": ps})
+					updated = true
+				}
+			}
+			if updated {
+				podSpec["imagePullSecrets"] = secrets
+			}
+			return updated
+		})
+	}
+}
+
+// updatePodSpecs calls the update function on all the pod specs in the object, like the pod template of a deployment,
+// and returns the updated object. The object is returned unchanged if it has no pod specs or the update function changed nothing.
+func updatePodSpecs(obj runtime.Object, update func(podSpec map[string]interface{}) bool) runtime.Object {
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		log.Debugf("Failed to convert the object %+v to unstructured. Error: %q", obj.GetObjectKind(), err)
+		return obj
+	}
+	if !walkPodSpecs(unstructuredObj, update) {
+		return obj
+	}
+	newObj := obj.DeepCopyObject()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj, newObj); err != nil {
+		log.Errorf("Failed to update the pod spec of the object %+v . Error: %q", obj.GetObjectKind(), err)
+		return obj
+	}
+	return newObj
+}
+
+// walkPodSpecs calls the update function on the maps which contain a list of containers and returns true if any of them were updated
+func walkPodSpecs(value interface{}, update func(podSpec map[string]interface{}) bool) bool {
+	updated := false
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if _, ok := value["containers"].([]interface{}); ok {
+			return update(value)
+		}
+		for _, v := range value {
+			if walkPodSpecs(v, update) {
+				updated = true
+			}
+		}
+	case []interface{}:
+		for _, v := range value {
+			if walkPodSpecs(v, update) {
+				updated = true
+			}
+		}
+	}
+	return updated
+}
+
+// getPodSpecContainers returns all the containers of the pod spec, including the init containers
+func getPodSpecContainers(podSpec map[string]interface{}) []map[string]interface{} {
+	containers := []map[string]interface{}{}
+	for _, key := range containerListKeys {
+		list, _ := podSpec[key].([]interface{})
+		for _, container := range list {
+			if containerMap, ok := container.(map[string]interface{}); ok {
+				containers = append(containers, containerMap)
+			}
+		}
+	}
+	return containers
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRewriteImages(t *testing.T) {
+	digest := "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+	deployment := &appsv1.Deployment{}
+	deployment.Name = "web"
+	deployment.Spec.Template.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Image: "busybox@" + digest}},
+		Containers:     []corev1.Container{{Name: "web", Image: "old.registry.com/team/web:v1"}},
+	}
+	service := &corev1.Service{}
+	objs := []runtime.Object{deployment, service}
+
+	mirrors := rewriteImages(objs, "quay.io", "myproject", true)
+
+	want := map[string]string{
+		"busybox@" + digest:            "quay.io/myproject/busybox@" + digest,
+		"old.registry.com/team/web:v1": "quay.io/myproject/web:v1",
+	}
+	if len(mirrors) != len(want) {
+		t.Fatalf("Expected the mirrors %v . Actual: %v", want, mirrors)
+	}
+	for source, target := range want {
+		if mirrors[source] != target {
+			t.Fatalf("Expected the image %s to be mirrored to %s . Actual: %s", source, target, mirrors[source])
+		}
+	}
+	newDeployment, ok := objs[0].(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("Expected the deployment to retain its type. Actual: %T", objs[0])
+	}
+	if image := newDeployment.Spec.Template.Spec.InitContainers[0].Image; image != want["busybox@"+digest] {
+		t.Fatalf("Expected the init container image to be rewritten. Actual: %s", image)
+	}
+	if deployment.Spec.Template.Spec.Containers[0].Image != "old.registry.com/team/web:v1" {
+		t.Fatalf("Expected the original object not to be modified")
+	}
+
+	addImagePullSecrets(objs, map[string]string{"quay.io": "imagepullsecretquay-io"})
+	newDeployment = objs[0].(*appsv1.Deployment)
+	if secrets := newDeployment.Spec.Template.Spec.ImagePullSecrets; len(secrets) != 1 || secrets[0].Name != "imagepullsecretquay-io" {
+		t.Fatalf("Expected the pull secret of the new registry to be added once. Actual: %+v", secrets)
+	}
+}
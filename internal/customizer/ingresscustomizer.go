@@ -17,9 +17,23 @@ limitations under the License.
 package customizer
 
 import (
+	"strconv"
+	"strings"
+
 	common "github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/qaengine"
 	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/gatewayapi"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultGatewayClassName = "default"
+	sharedGatewayMode       = "shared"
+	perServiceGatewayMode   = "perservice"
+	routeKind               = "Route"
+	defaultIngressTimeout   = "60"
+	defaultIngressBodySize  = "10m"
 )
 
 //ingressCustomizer customizes ingress host
@@ -42,10 +56,56 @@ func (ic *ingressCustomizer) customize(ir *irtypes.IR) error {
 		host, tlsSecret := ic.configureHostAndTLS(ir.Name)
 		ir.TargetClusterSpec.Host = host
 		ir.IngressTLSSecretName = tlsSecret
+		ic.configureGatewayAPI(ir)
+		ic.configureIngressController(ir)
 	}
 	return nil
 }
 
+// configureIngressController asks for the ingress controller, which decides the annotations used to configure the ingresses,
+// along with the timeout and the maximum body size of the ingresses
+func (ic ingressCustomizer) configureIngressController(ir *irtypes.IR) {
+	if ir.IsGatewayAPIEnabled() || len(ir.TargetClusterSpec.GetSupportedVersions(routeKind)) > 0 {
+		return
+	}
+	controllers := []string{
+		string(irtypes.NginxIngressController),
+		string(irtypes.HAProxyIngressController),
+		string(irtypes.TraefikIngressController),
+		string(irtypes.ALBIngressController),
+		string(irtypes.IstioIngressController),
+		string(irtypes.OtherIngressController),
+	}
+	controller := qaengine.FetchSelectAnswer(common.ConfigIngressControllerKey, "Select the ingress controller of the target cluster", []string{"The timeouts, body size, sticky sessions, TLS redirect and gRPC backends are configured using annotations specific to the ingress controller."}, string(irtypes.NginxIngressController), controllers)
+	ir.IngressController = irtypes.IngressControllerType(controller)
+	timeout := qaengine.FetchStringAnswer(common.ConfigIngressTimeoutKey, "Provide the timeout of the ingress in seconds", []string{"The idle connections to the services are closed after the timeout. Leave it empty to use the default of the ingress controller."}, defaultIngressTimeout)
+	if timeout = strings.TrimSpace(timeout); timeout != "" {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil || seconds <= 0 {
+			log.Warnf("Ignoring the invalid ingress timeout %s . It should be a number of seconds.", timeout)
+		} else {
+			ir.IngressTimeout = seconds
+		}
+	}
+	ir.IngressBodySize = strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigIngressBodySizeKey, "Provide the maximum size of the request bodies accepted by the ingress", []string{"Like 10m. Leave it empty to use the default of the ingress controller."}, defaultIngressBodySize))
+}
+
+
+// configureGatewayAPI asks whether to use the Gateway API instead of Ingress, if the target cluster has the Gateway API CRDs
+func (ic ingressCustomizer) configureGatewayAPI(ir *irtypes.IR) {
+	for _, kind := range []string{gatewayapi.GatewayKind, gatewayapi.HTTPRouteKind} {
+		if _, ok := gatewayapi.GetPreferredGroupVersion(ir.TargetClusterSpec.GetSupportedVersions(kind)); !ok {
+			return
+		}
+	}
+	if !qaengine.FetchBoolAnswer(common.ConfigGatewayEnableKey, "The target cluster supports the Gateway API. Use Gateway and HTTPRoute instead of Ingress?", []string{"The Gateway API is the successor of Ingress"}, false) {
+		return
+	}
+	ir.GatewayClassName = qaengine.FetchStringAnswer(common.ConfigGatewayClassNameKey, "Provide the gateway class name", []string{"The gateway class decides the controller that implements the gateways. Use [kubectl get gatewayclass] to list the available classes."}, defaultGatewayClassName)
+	mode := qaengine.FetchSelectAnswer(common.ConfigGatewayModeKey, "Select the gateway mode", []string{"A shared gateway is used by the routes of all the services, otherwise each service gets its own gateway."}, sharedGatewayMode, []string{sharedGatewayMode, perServiceGatewayMode})
+	ir.SharedGateway = mode == sharedGatewayMode
+}
+
 func (ic ingressCustomizer) configureHostAndTLS(name string) (string, string) {
 	defaultSubDomain := name + ".com"
 
@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// defaultTerminationGracePeriodSeconds is the termination grace period used by Kubernetes when none is set
+	defaultTerminationGracePeriodSeconds = 30
+	// preStopSleepSeconds is the time the preStop hook waits, so that the pod is removed from the endpoints of its services before it is stopped
+	preStopSleepSeconds = 5
+)
+
+// lifecycleCustomizer sets the termination grace period and the preStop hooks of the services whose shutdown could
+// not be derived from the source
+type lifecycleCustomizer struct {
+}
+
+// customize asks for the termination grace period and the preStop hook of the long running services which neither have
+// a termination grace period from the source nor seem to handle SIGTERM
+func (lc *lifecycleCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if !service.IsLongRunning() || service.TerminationGracePeriodSeconds != nil {
+			continue
+		}
+		if len(service.ShutdownHints) > 0 {
+			log.Debugf("The service %s shuts down gracefully on SIGTERM : %s", serviceName, strings.Join(service.ShutdownHints, ", "))
+			continue
+		}
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		serviceKey := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim
+		desc := fmt.Sprintf("Enter the termination grace period of the service %s, in seconds :", serviceName)
+		hints := []string{"No handling of SIGTERM was found in the source. The containers are killed if they are still running at the end of the termination grace period."}
+		answer := qaengine.FetchStringAnswer(serviceKey+common.ConfigTerminationGracePeriodKeySegment, desc, hints, cast.ToString(defaultTerminationGracePeriodSeconds))
+		terminationGracePeriodSeconds, err := cast.ToInt64E(strings.TrimSpace(answer))
+		if err != nil || terminationGracePeriodSeconds < 0 {
+			log.Warnf("Invalid termination grace period %s for the service %s. Using %d seconds instead. Error: %q", answer, serviceName, defaultTerminationGracePeriodSeconds, err)
+			terminationGracePeriodSeconds = defaultTerminationGracePeriodSeconds
+		}
+		desc = fmt.Sprintf("Should the containers of the service %s wait %d seconds before being stopped, so that the in-flight requests can finish?", serviceName, preStopSleepSeconds)
+		hints = []string{"A preStop hook running sleep is added. The images of the containers need a shell."}
+		if qaengine.FetchBoolAnswer(serviceKey+common.ConfigPreStopSleepKeySegment, desc, hints, true) {
+			addPreStopSleep(&service)
+			if terminationGracePeriodSeconds <= preStopSleepSeconds {
+				terminationGracePeriodSeconds += preStopSleepSeconds
+			}
+		}
+		service.TerminationGracePeriodSeconds = &terminationGracePeriodSeconds
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
+
+// addPreStopSleep adds a preStop hook which sleeps to the containers of the service that do not have a preStop hook
+func addPreStopSleep(service *irtypes.Service) {
+	for i, container := range service.Containers {
+		if container.Lifecycle == nil {
+			service.Containers[i].Lifecycle = &core.Lifecycle{}
+		} else if container.Lifecycle.PreStop != nil {
+			continue
+		}
+		service.Containers[i].Lifecycle.PreStop = &core.Handler{Exec: &core.ExecAction{Command: []string{"/bin/sh", "-c", fmt.Sprintf("sleep %d", preStopSleepSeconds)}}}
+	}
+}
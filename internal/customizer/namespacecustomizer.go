@@ -0,0 +1,304 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// namespaceNameLabel is set by the cluster on every namespace to its name
+	namespaceNameLabel  = "kubernetes.io/metadata.name"
+	serviceKind         = "Service"
+	serviceAccountKind  = "ServiceAccount"
+	defaultServiceAcct  = "default"
+	roleBindingKind     = "RoleBinding"
+	clusterRoleBinding  = "ClusterRoleBinding"
+	networkPolicyKind   = "NetworkPolicy"
+	ingressKind         = "Ingress"
+	externalNameService = "ExternalName"
+)
+
+// namespaceCustomizer maps the namespaces of the kubernetes resources used as source to the target namespaces, to
+// consolidate several namespaces into one or to rename them, and rewrites the references across the namespaces
+type namespaceCustomizer struct {
+}
+
+// customize asks for the target namespace of each namespace of the kubernetes resources and rewrites the resources
+func (nc *namespaceCustomizer) customize(ir *irtypes.IR) error {
+	namespaces := []string{}
+	for _, obj := range ir.CachedObjects {
+		if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() != "" && !common.IsStringPresent(namespaces, accessor.GetNamespace()) {
+			namespaces = append(namespaces, accessor.GetNamespace())
+		}
+	}
+	if len(namespaces) == 0 {
+		return nil
+	}
+	sort.Strings(namespaces)
+	mapping := map[string]string{}
+	renamed := false
+	for _, namespace := range namespaces {
+		key := common.ConfigTargetNamespacesKey + common.Delim + `"` + namespace + `"`
+		desc := fmt.Sprintf("Enter the target namespace of the resources in the namespace %s :", namespace)
+		hints := []string{"Enter the same target namespace for several namespaces to consolidate them into one namespace."}
+		target := strings.TrimSpace(qaengine.FetchStringAnswer(key, desc, hints, namespace))
+		if target == "" {
+			target = namespace
+		}
+		mapping[namespace] = target
+		if target != namespace {
+			renamed = true
+		}
+	}
+	if !renamed {
+		return nil
+	}
+	objs, unresolved := mapNamespaces(ir.CachedObjects, mapping)
+	ir.CachedObjects = objs
+	for _, reference := range unresolved {
+		err := common.NewError(common.UnresolvedReferenceErrorCode, nil, "%s", reference)
+		err.Warning = true
+		common.ReportError(err)
+	}
+	log.Debugf("Mapped the namespaces of the kubernetes resources using %v", mapping)
+	return nil
+}
+
+// mapNamespaces moves the objects to their target namespaces and rewrites the namespaces in the subjects of the role
+// bindings, the namespace selectors of the network policies and the cluster DNS names of the services. It returns the
+// updated objects along with the references which cannot be resolved in the target namespaces, like the services of
+// the ingresses and the service accounts of the pods and role bindings.
+func mapNamespaces(objs []runtime.Object, mapping map[string]string) ([]runtime.Object, []string) {
+	dnsRewriter := getServiceDNSRewriter(mapping)
+	unstructuredObjs := make([]map[string]interface{}, len(objs))
+	// existing contains the kind, the target namespace and the name of every object
+	existing := map[string]bool{}
+	for i, obj := range objs {
+		unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			log.Debugf("Failed to convert the object %+v to unstructured. Error: %q", obj.GetObjectKind(), err)
+			continue
+		}
+		metadata, _ := unstructuredObj["metadata"].(map[string]interface{})
+		namespace, _ := metadata["namespace"].(string)
+		if target, ok := mapping[namespace]; ok {
+			metadata["namespace"] = target
+			namespace = target
+		}
+		name, _ := metadata["name"].(string)
+		id := getResourceID(getKind(obj), namespace, name)
+		if existing[id] {
+			log.Warnf("More than one %s is named %s in the target namespace %s . Only one of them can be deployed.", getKind(obj), name, namespace)
+		}
+		existing[id] = true
+		unstructuredObjs[i] = unstructuredObj
+	}
+	unresolved := []string{}
+	addUnresolved := func(format string, args ...interface{}) {
+		unresolved = append(unresolved, fmt.Sprintf(format, args...))
+	}
+	for i, obj := range objs {
+		unstructuredObj := unstructuredObjs[i]
+		if unstructuredObj == nil {
+			continue
+		}
+		kind := getKind(obj)
+		metadata, _ := unstructuredObj["metadata"].(map[string]interface{})
+		namespace, _ := metadata["namespace"].(string)
+		name, _ := metadata["name"].(string)
+		spec, _ := unstructuredObj["spec"].(map[string]interface{})
+		switch kind {
+		case roleBindingKind, clusterRoleBinding:
+			subjects, _ := unstructuredObj["subjects"].([]interface{})
+			for _, subject := range subjects {
+				subjectMap, ok := subject.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				subjectNamespace, _ := subjectMap["namespace"].(string)
+				target, ok := mapping[subjectNamespace]
+				if !ok {
+					continue
+				}
+				subjectMap["namespace"] = target
+				subjectName, _ := subjectMap["name"].(string)
+				if subjectMap["kind"] == serviceAccountKind && subjectName != defaultServiceAcct && !existing[getResourceID(serviceAccountKind, target, subjectName)] {
+					addUnresolved("The %s %s refers to the service account %s in the namespace %s , which is not found in the target namespace %s .", kind, name, subjectName, subjectNamespace, target)
+				}
+			}
+		case networkPolicyKind:
+			for _, rulesKey := range []string{"ingress", "egress"} {
+				rules, _ := spec[rulesKey].([]interface{})
+				for _, rule := range rules {
+					ruleMap, _ := rule.(map[string]interface{})
+					for _, peersKey := range []string{"from", "to"} {
+						peers, _ := ruleMap[peersKey].([]interface{})
+						for _, peer := range peers {
+							peerMap, _ := peer.(map[string]interface{})
+							if selector, ok := peerMap["namespaceSelector"].(map[string]interface{}); ok && !mapNamespaceSelector(selector, mapping) {
+								addUnresolved("The %s %s in the namespace %s selects the namespaces using labels other than %s , which may not match the target namespaces.", kind, name, namespace, namespaceNameLabel)
+							}
+						}
+					}
+				}
+			}
+		case ingressKind:
+			for _, serviceName := range getIngressServiceNames(spec) {
+				if !existing[getResourceID(serviceKind, namespace, serviceName)] {
+					addUnresolved("The %s %s refers to the service %s , which is not found in the target namespace %s .", kind, name, serviceName, namespace)
+				}
+			}
+		case serviceKind:
+			if spec["type"] == externalNameService {
+				if externalName, ok := spec["externalName"].(string); ok {
+					spec["externalName"] = dnsRewriter(externalName)
+				}
+			}
+		}
+		walkPodSpecs(unstructuredObj, func(podSpec map[string]interface{}) bool {
+			serviceAccountName, _ := podSpec["serviceAccountName"].(string)
+			if serviceAccountName != "" && serviceAccountName != defaultServiceAcct && !existing[getResourceID(serviceAccountKind, namespace, serviceAccountName)] {
+				addUnresolved("The %s %s uses the service account %s , which is not found in the target namespace %s .", kind, name, serviceAccountName, namespace)
+			}
+			for _, container := range getPodSpecContainers(podSpec) {
+				env, _ := container["env"].([]interface{})
+				for _, envVar := range env {
+					if envVarMap, ok := envVar.(map[string]interface{}); ok {
+						if value, ok := envVarMap["value"].(string); ok {
+							envVarMap["value"] = dnsRewriter(value)
+						}
+					}
+				}
+			}
+			return true
+		})
+		newObj := obj.DeepCopyObject()
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj, newObj); err != nil {
+			log.Errorf("Failed to update the namespace of the object %+v . Error: %q", obj.GetObjectKind(), err)
+			continue
+		}
+		objs[i] = newObj
+	}
+	return objs, unresolved
+}
+
+// mapNamespaceSelector maps the namespace names in the selector. It returns false if the selector uses other labels.
+func mapNamespaceSelector(selector map[string]interface{}, mapping map[string]string) bool {
+	onlyNames := true
+	matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+	for key, value := range matchLabels {
+		if key != namespaceNameLabel {
+			onlyNames = false
+			continue
+		}
+		if target, ok := mapping[fmt.Sprintf("%v", value)]; ok {
+			matchLabels[key] = target
+		}
+	}
+	matchExpressions, _ := selector["matchExpressions"].([]interface{})
+	for _, expression := range matchExpressions {
+		expressionMap, _ := expression.(map[string]interface{})
+		if expressionMap["key"] != namespaceNameLabel {
+			onlyNames = false
+			continue
+		}
+		values, _ := expressionMap["values"].([]interface{})
+		for j, value := range values {
+			if target, ok := mapping[fmt.Sprintf("%v", value)]; ok {
+				values[j] = target
+			}
+		}
+	}
+	// An empty selector selects all the namespaces
+	return onlyNames
+}
+
+// getIngressServiceNames returns the names of the backend services of the ingress, for all the versions of the ingress
+func getIngressServiceNames(spec map[string]interface{}) []string {
+	backends := []interface{}{spec["backend"], spec["defaultBackend"]}
+	rules, _ := spec["rules"].([]interface{})
+	for _, rule := range rules {
+		ruleMap, _ := rule.(map[string]interface{})
+		http, _ := ruleMap["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for _, path := range paths {
+			pathMap, _ := path.(map[string]interface{})
+			backends = append(backends, pathMap["backend"])
+		}
+	}
+	serviceNames := []string{}
+	for _, backend := range backends {
+		backendMap, ok := backend.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		serviceName, _ := backendMap["serviceName"].(string)
+		if service, ok := backendMap["service"].(map[string]interface{}); ok {
+			serviceName, _ = service["name"].(string)
+		}
+		if serviceName != "" && !common.IsStringPresent(serviceNames, serviceName) {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	return serviceNames
+}
+
+// getServiceDNSRewriter returns the function which rewrites the cluster DNS names of the services, like
+// db.orders.svc.cluster.local, to the target namespaces
+func getServiceDNSRewriter(mapping map[string]string) func(string) string {
+	renamed := []string{}
+	for namespace, target := range mapping {
+		if namespace != target {
+			renamed = append(renamed, regexp.QuoteMeta(namespace))
+		}
+	}
+	if len(renamed) == 0 {
+		return func(s string) string { return s }
+	}
+	sort.Strings(renamed)
+	dnsRegex := regexp.MustCompile(`\.(` + strings.Join(renamed, "|") + `)\.svc\b`)
+	return func(s string) string {
+		return dnsRegex.ReplaceAllStringFunc(s, func(match string) string {
+			namespace := strings.TrimSuffix(strings.TrimPrefix(match, "."), ".svc")
+			return "." + mapping[namespace] + ".svc"
+		})
+	}
+}
+
+// getKind returns the kind of the object, or the name of its type if the kind is not set
+func getKind(obj runtime.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
+
+func getResourceID(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMapNamespaces(t *testing.T) {
+	mapping := map[string]string{"orders": "shop", "payments": "shop", "web": "web"}
+
+	deployment := &appsv1.Deployment{}
+	deployment.Name = "orders"
+	deployment.Namespace = "orders"
+	deployment.Spec.Template.Spec = corev1.PodSpec{
+		ServiceAccountName: "orders-sa",
+		Containers: []corev1.Container{{
+			Name: "orders",
+			Env:  []corev1.EnvVar{{Name: "PAYMENTS_URL", Value: "http://payments.payments.svc.cluster.local:8080"}},
+		}},
+	}
+	serviceAccount := &corev1.ServiceAccount{}
+	serviceAccount.Name = "orders-sa"
+	serviceAccount.Namespace = "orders"
+	service := &corev1.Service{}
+	service.Name = "payments"
+	service.Namespace = "payments"
+	roleBinding := &rbacv1.RoleBinding{}
+	roleBinding.Name = "readers"
+	roleBinding.Namespace = "payments"
+	roleBinding.Subjects = []rbacv1.Subject{
+		{Kind: "ServiceAccount", Name: "orders-sa", Namespace: "orders"},
+		{Kind: "ServiceAccount", Name: "monitor", Namespace: "payments"},
+	}
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	networkPolicy.Name = "allow-orders"
+	networkPolicy.Namespace = "payments"
+	networkPolicy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{
+		From: []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "orders"}}},
+			{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "orders"}}},
+		},
+	}}
+	ingress := &networkingv1beta1.Ingress{}
+	ingress.Name = "web"
+	ingress.Namespace = "web"
+	ingress.Spec.Backend = &networkingv1beta1.IngressBackend{ServiceName: "payments"}
+	objs := []runtime.Object{deployment, serviceAccount, service, roleBinding, networkPolicy, ingress}
+
+	objs, unresolved := mapNamespaces(objs, mapping)
+
+	newDeployment := objs[0].(*appsv1.Deployment)
+	if newDeployment.Namespace != "shop" {
+		t.Fatalf("Expected the deployment to be moved to the namespace shop. Actual: %s", newDeployment.Namespace)
+	}
+	if value := newDeployment.Spec.Template.Spec.Containers[0].Env[0].Value; value != "http://payments.shop.svc.cluster.local:8080" {
+		t.Fatalf("Expected the service DNS name to be rewritten. Actual: %s", value)
+	}
+	if deployment.Namespace != "orders" {
+		t.Fatalf("Expected the original object not to be modified")
+	}
+	newRoleBinding := objs[3].(*rbacv1.RoleBinding)
+	for _, subject := range newRoleBinding.Subjects {
+		if subject.Namespace != "shop" {
+			t.Fatalf("Expected the namespace of the subject %s to be rewritten. Actual: %s", subject.Name, subject.Namespace)
+		}
+	}
+	newNetworkPolicy := objs[4].(*networkingv1.NetworkPolicy)
+	if name := newNetworkPolicy.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"]; name != "shop" {
+		t.Fatalf("Expected the namespace selector to be rewritten. Actual: %s", name)
+	}
+	if objs[5].(*networkingv1beta1.Ingress).Namespace != "web" {
+		t.Fatalf("Expected the ingress to stay in the namespace web")
+	}
+	// The monitor service account, the selector using the team label and the payments service of the ingress
+	if len(unresolved) != 3 {
+		t.Fatalf("Expected 3 unresolved references. Actual: %q", unresolved)
+	}
+}
+
+func TestGetServiceDNSRewriter(t *testing.T) {
+	rewrite := getServiceDNSRewriter(map[string]string{"a": "b", "c": "c"})
+	tcs := map[string]string{
+		"db.a.svc":                  "db.b.svc",
+		"db.a.svc.cluster.local:80": "db.b.svc.cluster.local:80",
+		"db.c.svc":                  "db.c.svc",
+		"db.ab.svc":                 "db.ab.svc",
+		"a.svc":                     "a.svc",
+	}
+	for input, want := range tcs {
+		if actual := rewrite(input); actual != want {
+			t.Fatalf("Expected %s to be rewritten to %s . Actual: %s", input, want, actual)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// wellKnownNonHTTPPorts are the ports of the common services that do not serve HTTP
+var wellKnownNonHTTPPorts = map[int32]string{
+	21:    "FTP",
+	22:    "SSH",
+	25:    "SMTP",
+	53:    "DNS",
+	389:   "LDAP",
+	1433:  "SQL Server",
+	1521:  "Oracle",
+	1883:  "MQTT",
+	2181:  "ZooKeeper",
+	3306:  "MySQL",
+	5432:  "PostgreSQL",
+	5672:  "AMQP",
+	6379:  "Redis",
+	8883:  "MQTT over TLS",
+	9042:  "Cassandra",
+	9092:  "Kafka",
+	11211: "Memcached",
+	27017: "MongoDB",
+	61616: "ActiveMQ",
+}
+
+//portExposureCustomizer handles the services with ports that do not serve HTTP, which cannot be exposed using the HTTP ingress paths
+type portExposureCustomizer struct {
+}
+
+//customize asks how the non HTTP ports of the services are exposed
+func (pc *portExposureCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		service.NonHTTPPorts = getNonHTTPPorts(service)
+		ir.Services[serviceName] = service
+		if len(service.NonHTTPPorts) > 0 {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		ports := []string{}
+		for _, port := range service.NonHTTPPorts {
+			ports = append(ports, fmt.Sprintf("%d/%s (%s)", port.Number, port.Protocol, port.Hint))
+		}
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigPortExposureKeySegment
+		desc := fmt.Sprintf("The service %s has ports that do not serve HTTP. How should they be exposed?", serviceName)
+		hints := []string{
+			"Ports: " + strings.Join(ports, ", "),
+			string(irtypes.ClusterIPPortExposure) + " exposes the ports only inside the cluster.",
+			string(irtypes.LoadBalancerPortExposure) + " and " + string(irtypes.NodePortPortExposure) + " add another service of that type for the ports.",
+			string(irtypes.IngressNginxPortExposure) + " exposes the ports on the ingress-nginx controller using its tcp-services and udp-services config maps.",
+		}
+		exposures := []string{string(irtypes.ClusterIPPortExposure), string(irtypes.LoadBalancerPortExposure), string(irtypes.NodePortPortExposure), string(irtypes.IngressNginxPortExposure)}
+		planExposure, allPortsInPlan := getPlanPortExposure(service)
+		if planExposure != "" && allPortsInPlan {
+			log.Debugf("The plan exposes the non HTTP ports of the service %s using %s", serviceName, planExposure)
+			service.NonHTTPExposure = planExposure
+		} else {
+			defaultExposure := irtypes.ClusterIPPortExposure
+			if planExposure != "" {
+				defaultExposure = planExposure
+			}
+			service.NonHTTPExposure = irtypes.PortExposureType(qaengine.FetchSelectAnswer(key, desc, hints, string(defaultExposure), exposures))
+		}
+		if service.NonHTTPExposure == irtypes.IngressNginxPortExposure {
+			for _, port := range service.NonHTTPPorts {
+				ir.IngressNginxPorts = append(ir.IngressNginxPorts, irtypes.IngressNginxPort{ServiceName: serviceName, NonHTTPPort: port})
+			}
+		}
+		// The service cannot be reached using the HTTP paths of the ingress when none of its ports serve HTTP
+		if len(service.NonHTTPPorts) == len(service.ServiceToPodPortForwardings) && service.HasValidAnnotation(common.ExposeSelector) {
+			log.Infof("The service %s does not serve HTTP. It is not exposed using the ingress.", serviceName)
+			delete(service.Annotations, common.ExposeSelector)
+		}
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
+
+// getNonHTTPPorts returns the ports of the service which are either UDP or SCTP ports, or the ports of well known services that do not serve HTTP.
+// The ports which the plan exposes using the ingress always serve HTTP, and the ports which the plan exposes otherwise never do.
+func getNonHTTPPorts(service irtypes.Service) []irtypes.NonHTTPPort {
+	ports := []irtypes.NonHTTPPort{}
+	for _, forwarding := range service.ServiceToPodPortForwardings {
+		port := irtypes.NonHTTPPort{Number: forwarding.ServicePort.Number, Protocol: core.ProtocolTCP}
+		for _, container := range service.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.ContainerPort == forwarding.PodPort.Number && containerPort.Protocol != "" && containerPort.Protocol != core.ProtocolTCP {
+					port.Protocol = containerPort.Protocol
+					port.Hint = string(containerPort.Protocol) + " port"
+				}
+			}
+		}
+		if name, ok := wellKnownNonHTTPPorts[port.Number]; ok {
+			port.Hint = name
+		}
+		if planPort, ok := service.GetPlanPort(forwarding.PodPort.Number, port.Protocol); ok && planPort.Expose != "" {
+			if planPort.Expose == plantypes.IngressPortExpose {
+				continue
+			}
+			if port.Hint == "" {
+				port.Hint = "exposed using " + string(planPort.Expose) + " in the plan"
+			}
+		}
+		if port.Hint != "" {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// getPlanPortExposure returns the exposure of the non HTTP ports of the service in the plan, if they all have the same one,
+// and whether the plan sets the exposure of all of them
+func getPlanPortExposure(service irtypes.Service) (irtypes.PortExposureType, bool) {
+	exposure := irtypes.PortExposureType("")
+	allPortsInPlan := true
+	for _, port := range service.NonHTTPPorts {
+		podPort := port.Number
+		for _, forwarding := range service.ServiceToPodPortForwardings {
+			if forwarding.ServicePort.Number == port.Number {
+				podPort = forwarding.PodPort.Number
+				break
+			}
+		}
+		planPort, ok := service.GetPlanPort(podPort, port.Protocol)
+		if !ok || planPort.Expose == "" {
+			allPortsInPlan = false
+			continue
+		}
+		if exposure != "" && exposure != irtypes.PortExposureType(planPort.Expose) {
+			return "", false
+		}
+		exposure = irtypes.PortExposureType(planPort.Expose)
+	}
+	return exposure, allPortsInPlan
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetNonHTTPPorts(t *testing.T) {
+	service := irtypes.NewServiceWithName("svc1")
+	service.AddPortForwarding(irtypes.Port{Number: 8080}, irtypes.Port{Number: 8080})
+	service.AddPortForwarding(irtypes.Port{Number: 5432}, irtypes.Port{Number: 5432})
+	service.AddPortForwarding(irtypes.Port{Number: 9000}, irtypes.Port{Number: 9001})
+	service.Containers = []core.Container{{Name: "svc1", Ports: []core.ContainerPort{{ContainerPort: 8080, Protocol: core.ProtocolTCP}, {ContainerPort: 9001, Protocol: core.ProtocolUDP}}}}
+
+	ports := getNonHTTPPorts(service)
+	want := []irtypes.NonHTTPPort{{Number: 5432, Protocol: core.ProtocolTCP, Hint: "PostgreSQL"}, {Number: 9000, Protocol: core.ProtocolUDP, Hint: "UDP port"}}
+	if len(ports) != len(want) {
+		t.Fatalf("Expected the non HTTP ports %+v . Actual: %+v", want, ports)
+	}
+	for i, port := range ports {
+		if port != want[i] {
+			t.Fatalf("Expected the non HTTP ports %+v . Actual: %+v", want, ports)
+		}
+	}
+}
+
+func TestGetNonHTTPPortsFromPlan(t *testing.T) {
+	service := irtypes.NewServiceWithName("svc1")
+	service.AddPortForwarding(irtypes.Port{Number: 5432}, irtypes.Port{Number: 5432})
+	service.AddPortForwarding(irtypes.Port{Number: 7000}, irtypes.Port{Number: 7000})
+	service.AddPortForwarding(irtypes.Port{Number: 8080}, irtypes.Port{Number: 8080})
+	service.Containers = []core.Container{{Name: "svc1"}}
+	service.Ports = []plantypes.Port{{ContainerPort: 5432, Expose: plantypes.IngressPortExpose}, {ContainerPort: 7000, Expose: plantypes.LoadBalancerPortExpose}}
+
+	service.NonHTTPPorts = getNonHTTPPorts(service)
+	want := []irtypes.NonHTTPPort{{Number: 7000, Protocol: core.ProtocolTCP, Hint: "exposed using LoadBalancer in the plan"}}
+	if len(service.NonHTTPPorts) != len(want) || service.NonHTTPPorts[0] != want[0] {
+		t.Fatalf("Expected the non HTTP ports %+v . Actual: %+v", want, service.NonHTTPPorts)
+	}
+	if exposure, allPortsInPlan := getPlanPortExposure(service); exposure != irtypes.LoadBalancerPortExposure || !allPortsInPlan {
+		t.Fatalf("Expected the plan to expose the ports using %s . Actual: %s %t", irtypes.LoadBalancerPortExposure, exposure, allPortsInPlan)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	splitIntoServicesOption   = "Split into separate deployments"
+	splitIntoContainersOption = "Split into separate containers of the same pod"
+	keepProcessesOption       = "Keep the process manager"
+
+	// webProcessName is the process which serves the ports of the service, if there is one
+	webProcessName = "web"
+)
+
+// processCustomizer splits the processes run by a process manager, like supervisord or foreman, in the container of a
+// service, so that Kubernetes restarts, scales and collects the logs of each process
+type processCustomizer struct {
+}
+
+// customize asks how to run the processes of each service which runs a process manager
+func (pc *processCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if len(service.Processes) > 1 && len(service.Containers) > 0 {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		processNames := []string{}
+		for _, process := range service.Processes {
+			processNames = append(processNames, process.Name)
+		}
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigProcessesKeySegment
+		desc := fmt.Sprintf("The service %s runs the processes %s using %s. How should the processes be run?", serviceName, strings.Join(processNames, ", "), service.ProcessManager)
+		hints := []string{
+			"Separate deployments can be scaled and rolled out independently. The processes other than the web process do not serve the ports of the service.",
+			"Separate containers of the same pod share the volumes and the network, like the processes do now.",
+		}
+		answer := qaengine.FetchSelectAnswer(key, desc, hints, splitIntoServicesOption, []string{splitIntoServicesOption, splitIntoContainersOption, keepProcessesOption})
+		switch answer {
+		case splitIntoServicesOption:
+			for name, processService := range splitProcessesIntoServices(service) {
+				if _, ok := ir.Services[name]; ok && name != serviceName {
+					log.Warnf("Unable to split the process %s of the service %s into a separate deployment, since the service %s already exists.", strings.TrimPrefix(name, serviceName+"-"), serviceName, name)
+					continue
+				}
+				ir.Services[name] = processService
+			}
+		case splitIntoContainersOption:
+			service = splitProcessesIntoContainers(service)
+			ir.Services[serviceName] = service
+		default:
+			log.Debugf("Keeping the process manager %s of the service %s", service.ProcessManager, serviceName)
+		}
+	}
+	return nil
+}
+
+// getWebProcessIndex returns the index of the process which serves the ports of the service. It is the web process if there is one, else the first process.
+func getWebProcessIndex(processes []irtypes.Process) int {
+	for i, process := range processes {
+		if process.Name == webProcessName {
+			return i
+		}
+	}
+	return 0
+}
+
+// getProcessContainer returns the main container of the service running only the process
+func getProcessContainer(service irtypes.Service, name string, process irtypes.Process, keepPorts bool) core.Container {
+	container := *service.Containers[0].DeepCopy()
+	container.Name = name
+	container.Command = []string{"/bin/sh", "-c", process.Command}
+	container.Args = nil
+	if process.WorkingDir != "" {
+		container.WorkingDir = process.WorkingDir
+	}
+	container.Env = append(container.Env, process.Env...)
+	if !keepPorts {
+		container.Ports = nil
+		container.LivenessProbe = nil
+		container.ReadinessProbe = nil
+		container.StartupProbe = nil
+	}
+	return container
+}
+
+// splitProcessesIntoServices returns the services running each process. The web process is run by the service itself,
+// and the other processes by copies of the service which share its config and volumes, but not its ports.
+func splitProcessesIntoServices(service irtypes.Service) map[string]irtypes.Service {
+	services := map[string]irtypes.Service{}
+	webIndex := getWebProcessIndex(service.Processes)
+	for i, process := range service.Processes {
+		if i == webIndex {
+			continue
+		}
+		name := common.NormalizeForServiceName(service.Name + "-" + process.Name)
+		processService := irtypes.NewServiceWithName(name)
+		processService.PodSpec = *service.PodSpec.DeepCopy()
+		processService.ServiceRelPath = ""
+		processService.Annotations = common.MergeStringMaps(service.Annotations, nil)
+		delete(processService.Annotations, common.ExposeSelector)
+		processService.Labels = common.MergeStringMaps(service.Labels, nil)
+		processService.Networks = append([]string{}, service.Networks...)
+		processService.Replicas = service.Replicas
+		processService.Containers = []core.Container{getProcessContainer(service, name, process, false)}
+		services[name] = processService
+	}
+	container := getProcessContainer(service, service.Containers[0].Name, service.Processes[webIndex], true)
+	service.Containers = append([]core.Container{container}, service.Containers[1:]...)
+	service.Processes = nil
+	services[service.Name] = service
+	return services
+}
+
+// splitProcessesIntoContainers returns the service running each process in a separate container of the pod. Only the
+// container of the web process serves the ports.
+func splitProcessesIntoContainers(service irtypes.Service) irtypes.Service {
+	webIndex := getWebProcessIndex(service.Processes)
+	containers := []core.Container{}
+	for i, process := range service.Processes {
+		name := common.NormalizeForServiceName(process.Name)
+		if i == webIndex {
+			name = service.Containers[0].Name
+		}
+		containers = append(containers, getProcessContainer(service, name, process, i == webIndex))
+	}
+	service.Containers = append(containers, service.Containers[1:]...)
+	service.Processes = nil
+	return service
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func getProcessManagerService() irtypes.Service {
+	service := irtypes.NewServiceWithName("app")
+	service.Containers = []core.Container{{
+		Name:  "app",
+		Image: "app:latest",
+		Args:  []string{"supervisord", "-n"},
+		Ports: []core.ContainerPort{{ContainerPort: 8000}},
+		Env:   []core.EnvVar{{Name: "DB_HOST", Value: "db"}},
+	}}
+	service.ProcessManager = "supervisord"
+	service.Processes = []irtypes.Process{
+		{Name: "worker", Command: "celery -A app worker"},
+		{Name: "web", Command: "gunicorn app:app", WorkingDir: "/app"},
+	}
+	return service
+}
+
+func TestSplitProcessesIntoServices(t *testing.T) {
+	services := splitProcessesIntoServices(getProcessManagerService())
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 services. Actual: %+v", services)
+	}
+	web := services["app"].Containers[0]
+	if !reflect.DeepEqual(web.Command, []string{"/bin/sh", "-c", "gunicorn app:app"}) || web.WorkingDir != "/app" || len(web.Ports) != 1 || web.Args != nil {
+		t.Fatalf("Expected the service to run the web process on its ports. Actual: %+v", web)
+	}
+	worker, ok := services["app-worker"]
+	if !ok || len(worker.Containers) != 1 {
+		t.Fatalf("Expected the service app-worker running the worker process. Actual: %+v", services)
+	}
+	container := worker.Containers[0]
+	if container.Name != "app-worker" || !reflect.DeepEqual(container.Command, []string{"/bin/sh", "-c", "celery -A app worker"}) || container.Ports != nil || len(container.Env) != 1 {
+		t.Fatalf("Expected the worker container to share the config but not the ports. Actual: %+v", container)
+	}
+}
+
+func TestSplitProcessesIntoContainers(t *testing.T) {
+	service := splitProcessesIntoContainers(getProcessManagerService())
+	if len(service.Containers) != 2 || service.Processes != nil {
+		t.Fatalf("Expected 2 containers. Actual: %+v", service.Containers)
+	}
+	if service.Containers[0].Name != "worker" || service.Containers[0].Ports != nil {
+		t.Fatalf("Expected the worker container without ports. Actual: %+v", service.Containers[0])
+	}
+	if service.Containers[1].Name != "app" || len(service.Containers[1].Ports) != 1 {
+		t.Fatalf("Expected the web container to keep the name and the ports of the service. Actual: %+v", service.Containers[1])
+	}
+}
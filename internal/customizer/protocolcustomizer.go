@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+)
+
+const httpProtocolOption = "http"
+
+//protocolCustomizer confirms the protocols detected for the services
+type protocolCustomizer struct {
+}
+
+//customize asks for the protocol of the services that have protocol hints
+func (pc *protocolCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if len(service.ProtocolHints) > 0 {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigProtocolKeySegment
+		desc := fmt.Sprintf("The service %s seems to serve %s. Select the protocol of the service:", serviceName, service.Protocol)
+		hints := []string{"Found: " + strings.Join(service.ProtocolHints, ", "), "The services, ingresses and routes are configured for gRPC (HTTP/2) or for the long lived WebSocket connections."}
+		options := []string{string(irtypes.GRPCServiceProtocol), string(irtypes.WebSocketServiceProtocol), httpProtocolOption}
+		protocol := qaengine.FetchSelectAnswer(key, desc, hints, string(service.Protocol), options)
+		service.Protocol = irtypes.ServiceProtocol(protocol)
+		if protocol == httpProtocolOption {
+			service.Protocol = irtypes.HTTPServiceProtocol
+		}
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
@@ -20,7 +20,6 @@ import (
 	"bytes"
 	"fmt"
 	"net/url"
-	"strings"
 
 	dockercliconfig "github.com/docker/cli/cli/config"
 	dockercliconfigfile "github.com/docker/cli/cli/config/configfile"
@@ -54,15 +53,35 @@ func (rc *registryCustomizer) customize(ir *irtypes.IR) error {
 	for _, service := range ir.Services {
 		for _, container := range service.Containers {
 			if !common.IsStringPresent(newimages, container.Image) {
-				parts := strings.Split(container.Image, "/")
-				if len(parts) == 3 {
-					registryList = append(registryList, parts[0])
-					usedRegistries = append(usedRegistries, parts[0])
+				if registry := common.ParseImageReference(container.Image).Registry; registry != "" {
+					registryList = append(registryList, registry)
+					usedRegistries = append(usedRegistries, registry)
 				}
 			}
 		}
 	}
 
+	// The images of the kubernetes artifacts
+	for _, obj := range ir.CachedObjects {
+		updatePodSpecs(obj, func(podSpec map[string]interface{}) bool {
+			for _, container := range getPodSpecContainers(podSpec) {
+				image, _ := container["image"].(string)
+				if registry := common.ParseImageReference(image).Registry; registry != "" && !common.IsStringPresent(usedRegistries, registry) {
+					registryList = append(registryList, registry)
+					usedRegistries = append(usedRegistries, registry)
+				}
+			}
+			return false
+		})
+	}
+
+	for registry := range ir.RegistryUsernames {
+		if !common.IsStringPresent(usedRegistries, registry) {
+			registryList = append(registryList, registry)
+			usedRegistries = append(usedRegistries, registry)
+		}
+	}
+
 	registryAuthList := map[string]string{} //Registry url and auth
 	defreg := ""
 	if !common.IgnoreEnvironment {
@@ -127,25 +146,36 @@ func (rc *registryCustomizer) customize(ir *irtypes.IR) error {
 		const useExistingPullSecret = "Use existing pull secret"
 		authOptions := []string{useExistingPullSecret, noAuthLogin, userLogin}
 		if auth, ok := registryAuthList[ir.Kubernetes.RegistryURL]; ok {
-			imagePullSecrets[registry] = common.ImagePullSecretPrefix + common.MakeFileNameCompliant(imagePullSecrets[registry])
+			imagePullSecrets[registry] = common.ImagePullSecretPrefix + common.MakeFileNameCompliant(registry)
 			dauth.Auth = auth
 			authOptions = append(authOptions, dockerConfigLogin)
 		}
 
-		auth := qaengine.FetchSelectAnswer(common.ConfigImageRegistryLoginTypeKey, fmt.Sprintf("[%s] What type of container registry login do you want to use?", registry), []string{"Docker login from config mode, will use the default config from your local machine."}, noAuthLogin, authOptions)
+		defAuth, defUsername := noAuthLogin, "iamapikey"
+		if username, ok := ir.RegistryUsernames[registry]; ok {
+			// The source artifacts already say that the images are pulled using this username
+			defAuth, defUsername = userLogin, username
+		}
+		auth := qaengine.FetchSelectAnswer(common.ConfigImageRegistryLoginTypeKey, fmt.Sprintf("[%s] What type of container registry login do you want to use?", registry), []string{"Docker login from config mode, will use the default config from your local machine."}, defAuth, authOptions)
 		if auth == noAuthLogin {
 			dauth.Auth = ""
+			if registry == ir.Kubernetes.RegistryURL && len(newimages) != 0 {
+				err := common.NewError(common.RegistryAuthMissingErrorCode, nil, "The new images are pushed to the registry %s , which has no credentials. The pods cannot pull the images if the registry is private.", registry)
+				err.Warning = true
+				common.ReportError(err)
+			}
 		} else if auth == useExistingPullSecret {
 			ps := qaengine.FetchStringAnswer(common.ConfigImageRegistryPullSecretKey, fmt.Sprintf("[%s] Enter the name of the pull secret : ", registry), []string{"The pull secret should exist in the namespace where you will be deploying the application."}, "")
 			imagePullSecrets[registry] = ps
 		} else if auth != dockerConfigLogin {
-			un := qaengine.FetchStringAnswer(common.ConfigImageRegistryUserNameKey, fmt.Sprintf("[%s] Enter the container registry username : ", registry), []string{"Enter username for container registry login"}, "iamapikey")
+			un := qaengine.FetchStringAnswer(common.ConfigImageRegistryUserNameKey, fmt.Sprintf("[%s] Enter the container registry username : ", registry), []string{"Enter username for container registry login"}, defUsername)
 			dauth.Username = un
+			imagePullSecrets[registry] = common.ImagePullSecretPrefix + common.MakeFileNameCompliant(registry)
 			dauth.Password = qaengine.FetchPasswordAnswer(common.ConfigImageRegistryPasswordKey, fmt.Sprintf("[%s] Enter the container registry password : ", registry), []string{"Enter password for container registry login."})
 		}
 		if dauth != (types.AuthConfig{}) {
 			dconfigfile := dockercliconfigfile.ConfigFile{
-				AuthConfigs: map[string]dockerclitypes.AuthConfig{ir.Kubernetes.RegistryURL: dauth},
+				AuthConfigs: map[string]dockerclitypes.AuthConfig{registry: dauth},
 			}
 			dconfigbuffer := new(bytes.Buffer)
 			err := dconfigfile.SaveToWriter(dconfigbuffer)
@@ -168,33 +198,32 @@ func (rc *registryCustomizer) customize(ir *irtypes.IR) error {
 	for _, service := range ir.Services {
 		for i, serviceContainer := range service.Containers {
 			if common.IsStringPresent(newimages, serviceContainer.Image) {
-				image, tag := common.GetImageNameAndTag(serviceContainer.Image)
-				if ir.Kubernetes.RegistryURL != "" && ir.Kubernetes.RegistryNamespace != "" {
-					serviceContainer.Image = ir.Kubernetes.RegistryURL + "/" + ir.Kubernetes.RegistryNamespace + "/" + image + ":" + tag
-				} else if ir.Kubernetes.RegistryNamespace != "" {
-					serviceContainer.Image = ir.Kubernetes.RegistryNamespace + "/" + image + ":" + tag
-				} else {
-					serviceContainer.Image = image + ":" + tag
+				// Only the registry and the namespace are re-targeted, the tag and the digest are retained
+				ref := common.ParseImageReference(serviceContainer.Image)
+				if ref.Tag == "" && ref.Digest == "" {
+					ref.Tag = "latest"
 				}
+				registryURL := ""
+				if ir.Kubernetes.RegistryNamespace != "" {
+					registryURL = ir.Kubernetes.RegistryURL
+				}
+				serviceContainer.Image = ref.WithRegistry(registryURL, ir.Kubernetes.RegistryNamespace).String()
 				service.Containers[i] = serviceContainer
 			}
-			parts := strings.Split(serviceContainer.Image, "/")
-			if len(parts) == 3 {
-				reg := parts[0]
-				if ps, ok := imagePullSecrets[reg]; ok {
-					found := false
-					for _, eps := range service.ImagePullSecrets {
-						if eps.Name == ps {
-							found = true
-						}
-					}
-					if !found {
-						service.ImagePullSecrets = append(service.ImagePullSecrets, core.LocalObjectReference{Name: ps})
+			if ps, ok := imagePullSecrets[common.GetImageRegistry(serviceContainer.Image)]; ok && ps != "" {
+				found := false
+				for _, eps := range service.ImagePullSecrets {
+					if eps.Name == ps {
+						found = true
 					}
 				}
+				if !found {
+					service.ImagePullSecrets = append(service.ImagePullSecrets, core.LocalObjectReference{Name: ps})
+				}
 			}
 		}
 		ir.Services[service.Name] = service
 	}
+	addImagePullSecrets(ir.CachedObjects, imagePullSecrets)
 	return nil
 }
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+)
+
+const (
+	defaultMetricsPath = "/metrics"
+)
+
+//rolloutCustomizer chooses between Deployments and Argo Rollouts
+type rolloutCustomizer struct {
+}
+
+//customize asks for the deployment strategy and the metrics endpoint used to analyze the new versions
+func (rc *rolloutCustomizer) customize(ir *irtypes.IR) error {
+	anyLongRunningServices := false
+	for _, service := range ir.Services {
+		if service.IsLongRunning() {
+			anyLongRunningServices = true
+			break
+		}
+	}
+	if !anyLongRunningServices {
+		return nil
+	}
+	strategies := []string{string(irtypes.RollingUpdateDeploymentStrategy), string(irtypes.CanaryDeploymentStrategy), string(irtypes.BlueGreenDeploymentStrategy)}
+	hints := []string{"Canary and BlueGreen generate Argo Rollouts instead of Deployments and require Argo Rollouts to be installed on the cluster."}
+	strategy := qaengine.FetchSelectAnswer(common.ConfigRolloutsStrategyKey, "Select the deployment strategy", hints, string(irtypes.RollingUpdateDeploymentStrategy), strategies)
+	ir.DeploymentStrategy = irtypes.DeploymentStrategyType(strategy)
+	if !ir.IsRolloutEnabled() {
+		return nil
+	}
+	metricsPath := qaengine.FetchStringAnswer(common.ConfigRolloutsMetricsPathKey, "Provide the path of the metrics endpoint of the services", []string{"The analysis templates query this endpoint on the new version before shifting more traffic to it."}, defaultMetricsPath)
+	if !strings.HasPrefix(metricsPath, "/") {
+		metricsPath = "/" + metricsPath
+	}
+	ir.MetricsPath = metricsPath
+	return nil
+}
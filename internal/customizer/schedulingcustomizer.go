@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// serviceTier groups the services which are scheduled alike
+type serviceTier string
+
+const (
+	frontendTier serviceTier = "frontend"
+	backendTier  serviceTier = "backend"
+	batchTier    serviceTier = "batch"
+)
+
+// qosClass is the quality of service class of the pods, which is decided by the requests and limits of their containers
+type qosClass string
+
+const (
+	// guaranteedQoS sets the limits equal to the requests, so the pods are evicted last
+	guaranteedQoS qosClass = "Guaranteed"
+	// burstableQoS sets the limits above the requests, so the containers can use the idle resources of the nodes
+	burstableQoS qosClass = "Burstable"
+)
+
+// tierScheduling contains the defaults of a tier
+type tierScheduling struct {
+	priority int32
+	qos      qosClass
+	cpu      string
+	memory   string
+}
+
+var tierDefaults = map[serviceTier]tierScheduling{
+	frontendTier: {priority: 100000, qos: guaranteedQoS, cpu: "500m", memory: "512Mi"},
+	backendTier:  {priority: 50000, qos: burstableQoS, cpu: "250m", memory: "256Mi"},
+	batchTier:    {priority: 10000, qos: burstableQoS, cpu: "250m", memory: "256Mi"},
+}
+
+// schedulingCustomizer assigns priority classes to the services of each tier and sets the requests and limits of their
+// containers to achieve the QoS class selected for the tier
+type schedulingCustomizer struct {
+}
+
+// customize asks for the tier of each service, and for the priority class, the QoS class and the requests of each tier
+func (sc *schedulingCustomizer) customize(ir *irtypes.IR) error {
+	if len(ir.Services) == 0 {
+		return nil
+	}
+	hints := []string{"The services are grouped into the frontend, backend and batch tiers. Each tier gets a priority class and the requests and limits of the Guaranteed or Burstable QoS class."}
+	if !qaengine.FetchBoolAnswer(common.ConfigSchedulingEnableKey, "Do you want to configure the priority classes and the QoS of the services?", hints, false) {
+		return nil
+	}
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	tiers := map[serviceTier][]string{}
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		def := backendTier
+		if !service.IsLongRunning() && !service.Daemon {
+			def = batchTier
+		} else if service.HasValidAnnotation(common.ExposeSelector) {
+			def = frontendTier
+		}
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigTierKeySegment
+		desc := fmt.Sprintf("Select the tier of the service %s :", serviceName)
+		tier := serviceTier(qaengine.FetchSelectAnswer(key, desc, []string{"The exposed services are frontend by default and the jobs are batch."}, string(def), []string{string(frontendTier), string(backendTier), string(batchTier)}))
+		if _, ok := tierDefaults[tier]; !ok {
+			tier = def
+		}
+		tiers[tier] = append(tiers[tier], serviceName)
+	}
+	for _, tier := range []serviceTier{frontendTier, backendTier, batchTier} {
+		if len(tiers[tier]) == 0 {
+			continue
+		}
+		defaults := tierDefaults[tier]
+		tierKey := common.ConfigSchedulingKey + common.Delim + `"` + string(tier) + `"` + common.Delim
+		generatedPriorityClassName := ir.Name + "-" + string(tier)
+		priorityClassName := strings.TrimSpace(qaengine.FetchStringAnswer(tierKey+common.ConfigSchedulingPriorityClassKeySegment, fmt.Sprintf("Enter the priority class of the %s tier :", tier), []string{fmt.Sprintf("The priority class %s is generated. Enter the name of a priority class of the cluster to use it instead, or leave it empty to use none.", generatedPriorityClassName)}, generatedPriorityClassName))
+		if priorityClassName == generatedPriorityClassName {
+			ir.CachedObjects = append(ir.CachedObjects, createPriorityClass(priorityClassName, defaults.priority, tier))
+		}
+		qos := qosClass(qaengine.FetchSelectAnswer(tierKey+common.ConfigSchedulingQoSKeySegment, fmt.Sprintf("Select the QoS class of the %s tier :", tier), []string{"Guaranteed pods are evicted last, while Burstable pods can use the idle resources of the nodes."}, string(defaults.qos), []string{string(guaranteedQoS), string(burstableQoS)}))
+		cpu, err := resource.ParseQuantity(qaengine.FetchStringAnswer(tierKey+common.ConfigSchedulingCPUKeySegment, fmt.Sprintf("Enter the CPU request of the containers in the %s tier :", tier), []string{"The existing requests of the containers are kept."}, defaults.cpu))
+		if err != nil {
+			log.Warnf("Invalid CPU request for the %s tier. Using %s instead. Error: %q", tier, defaults.cpu, err)
+			cpu = resource.MustParse(defaults.cpu)
+		}
+		memory, err := resource.ParseQuantity(qaengine.FetchStringAnswer(tierKey+common.ConfigSchedulingMemoryKeySegment, fmt.Sprintf("Enter the memory request of the containers in the %s tier :", tier), []string{"The existing requests of the containers are kept."}, defaults.memory))
+		if err != nil {
+			log.Warnf("Invalid memory request for the %s tier. Using %s instead. Error: %q", tier, defaults.memory, err)
+			memory = resource.MustParse(defaults.memory)
+		}
+		for _, serviceName := range tiers[tier] {
+			service := ir.Services[serviceName]
+			service.PriorityClassName = priorityClassName
+			for i, container := range service.Containers {
+				service.Containers[i].Resources = getQoSResources(container.Resources, qos, core.ResourceList{core.ResourceCPU: cpu, core.ResourceMemory: memory})
+			}
+			ir.Services[serviceName] = service
+		}
+	}
+	return nil
+}
+
+// createPriorityClass returns the priority class of the tier
+func createPriorityClass(name string, priority int32, tier serviceTier) *schedulingv1.PriorityClass {
+	return &schedulingv1.PriorityClass{
+		TypeMeta:    metav1.TypeMeta{Kind: "PriorityClass", APIVersion: schedulingv1.SchemeGroupVersion.String()},
+		ObjectMeta:  metav1.ObjectMeta{Name: name},
+		Value:       priority,
+		Description: fmt.Sprintf("The priority of the services in the %s tier", tier),
+	}
+}
+
+// getQoSResources returns the requests and limits which achieve the QoS class. The existing requests are kept, and the
+// existing limits are used as the requests if there are none. The limits are equal to the requests for the Guaranteed
+// QoS class, and twice the requests for the Burstable QoS class, unless the existing limits are higher.
+func getQoSResources(resources core.ResourceRequirements, qos qosClass, defaultRequests core.ResourceList) core.ResourceRequirements {
+	newResources := core.ResourceRequirements{Requests: core.ResourceList{}, Limits: core.ResourceList{}}
+	for name, defaultRequest := range defaultRequests {
+		request, ok := resources.Requests[name]
+		if !ok {
+			if request, ok = resources.Limits[name]; !ok {
+				request = defaultRequest
+			}
+		}
+		newResources.Requests[name] = request.DeepCopy()
+		if qos == guaranteedQoS {
+			newResources.Limits[name] = request.DeepCopy()
+			continue
+		}
+		limit := request.DeepCopy()
+		limit.Add(request)
+		if existingLimit, ok := resources.Limits[name]; ok && existingLimit.Cmp(request) > 0 {
+			limit = existingLimit.DeepCopy()
+		}
+		newResources.Limits[name] = limit
+	}
+	return newResources
+}
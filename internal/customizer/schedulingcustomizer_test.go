@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetQoSResources(t *testing.T) {
+	defaults := core.ResourceList{core.ResourceCPU: resource.MustParse("250m"), core.ResourceMemory: resource.MustParse("256Mi")}
+	existing := core.ResourceRequirements{
+		Requests: core.ResourceList{core.ResourceCPU: resource.MustParse("100m")},
+		Limits:   core.ResourceList{core.ResourceCPU: resource.MustParse("1"), core.ResourceMemory: resource.MustParse("1Gi")},
+	}
+
+	t.Run("guaranteed QoS sets the limits equal to the requests", func(t *testing.T) {
+		resources := getQoSResources(existing, guaranteedQoS, defaults)
+		wants := map[core.ResourceName]string{core.ResourceCPU: "100m", core.ResourceMemory: "1Gi"}
+		for name, want := range wants {
+			request := resources.Requests[name]
+			limit := resources.Limits[name]
+			if request.Cmp(resource.MustParse(want)) != 0 || limit.Cmp(resource.MustParse(want)) != 0 {
+				t.Fatalf("Expected the request and limit of %s to be %s . Actual: %s and %s", name, want, request.String(), limit.String())
+			}
+		}
+	})
+
+	t.Run("burstable QoS uses the existing limits as the requests and doubles the requests", func(t *testing.T) {
+		resources := getQoSResources(core.ResourceRequirements{Limits: core.ResourceList{core.ResourceCPU: resource.MustParse("1")}}, burstableQoS, defaults)
+		wants := map[core.ResourceName][2]string{core.ResourceCPU: {"1", "2"}, core.ResourceMemory: {"256Mi", "512Mi"}}
+		for name, want := range wants {
+			request := resources.Requests[name]
+			limit := resources.Limits[name]
+			if request.Cmp(resource.MustParse(want[0])) != 0 || limit.Cmp(resource.MustParse(want[1])) != 0 {
+				t.Fatalf("Expected the request and limit of %s to be %v . Actual: %s and %s", name, want, request.String(), limit.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	stickySessionsOption = "Use sticky sessions"
+	sessionStoreOption   = "Add a Redis session store"
+	ignoreSessionsOption = "Do nothing"
+
+	defaultSessionStoreImage = "redis:6-alpine"
+	sessionStorePort         = 6379
+	sessionStoreEnvVar       = "SESSION_STORE_URL"
+	sessionStoreTODOKey      = common.TODOAnnotation + "sessionstore"
+
+	expressSessionStoreStep = "Use connect-redis as the express-session store and connect it to $" + sessionStoreEnvVar
+	javaSessionStoreStep    = "Add Spring Session Data Redis (or a Redis session manager for the servlet container) and connect it to $" + sessionStoreEnvVar
+)
+
+//sessionCustomizer handles the services that keep user sessions in memory
+type sessionCustomizer struct {
+}
+
+//customize asks how to handle the user sessions of the services that have session hints
+func (sc *sessionCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName, service := range ir.Services {
+		if len(service.SessionHints) > 0 {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	storeName := ""
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		key := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigSessionsKeySegment
+		desc := fmt.Sprintf("The service %s seems to keep user sessions in memory. How should the sessions be handled when running multiple replicas?", serviceName)
+		hints := []string{"Found: " + strings.Join(service.SessionHints, ", "), "Sticky sessions route all the requests of a client to the same pod, but the sessions are lost when the pod restarts."}
+		switch qaengine.FetchSelectAnswer(key, desc, hints, stickySessionsOption, []string{stickySessionsOption, sessionStoreOption, ignoreSessionsOption}) {
+		case stickySessionsOption:
+			service.StickySessions = true
+		case sessionStoreOption:
+			if storeName == "" {
+				storeName = sc.addSessionStore(ir)
+			}
+			sc.useSessionStore(&service, storeName)
+		}
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
+
+// addSessionStore adds a Redis deployment that is shared by all the services that need a session store
+func (sc *sessionCustomizer) addSessionStore(ir *irtypes.IR) string {
+	name := common.NormalizeForServiceName(ir.Name + "-sessionstore")
+	image := qaengine.FetchStringAnswer(common.ConfigSessionStoreImageKey, "Provide the image for the Redis session store", []string{"The session store is shared by all the services that use it."}, defaultSessionStoreImage)
+	store := irtypes.NewServiceWithName(name)
+	store.ServiceRelPath = ""
+	store.Containers = []core.Container{{
+		Name:  name,
+		Image: image,
+		Ports: []core.ContainerPort{{ContainerPort: sessionStorePort}},
+	}}
+	store.AddPortForwarding(irtypes.Port{Number: sessionStorePort}, irtypes.Port{Number: sessionStorePort})
+	ir.Services[name] = store
+	return name
+}
+
+// useSessionStore points the service to the session store and lists the code changes that have to be done manually
+func (sc *sessionCustomizer) useSessionStore(service *irtypes.Service, storeName string) {
+	storeURL := fmt.Sprintf("redis://%s:%d", storeName, sessionStorePort)
+	for i := range service.Containers {
+		service.Containers[i].Env = append(service.Containers[i].Env, core.EnvVar{Name: sessionStoreEnvVar, Value: storeURL})
+	}
+	steps := []string{}
+	for _, hint := range service.SessionHints {
+		step := javaSessionStoreStep
+		if strings.Contains(hint, "express-session") {
+			step = expressSessionStoreStep
+		}
+		if !common.IsStringPresent(steps, step) {
+			steps = append(steps, step)
+		}
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[sessionStoreTODOKey] = strings.Join(steps, ". ")
+	log.Warnf("The service %s has to be changed manually to store the sessions in %s : %s", service.Name, storeURL, strings.Join(steps, ". "))
+}
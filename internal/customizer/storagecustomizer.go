@@ -17,12 +17,21 @@ limitations under the License.
 package customizer
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/qaengine"
 	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
 	core "k8s.io/kubernetes/pkg/apis/core"
 )
 
@@ -33,12 +42,19 @@ type storageCustomizer struct {
 
 const (
 	alloption string = "Apply for all"
+	// maxConfigMapSize is the maximum size of the data in a config map
+	maxConfigMapSize = 1024 * 1024
+	// maxHostPathContentSize is the maximum size of the files at a host path, which are split into several config maps
+	maxHostPathContentSize = 8 * maxConfigMapSize
+	// hostPathTODOKey flags the services whose host paths are retained
+	hostPathTODOKey = common.TODOAnnotation + "hostpath"
 )
 
 //customize customizes the storage
 func (ic *storageCustomizer) customize(ir *irtypes.IR) error {
 	ic.ir = ir
-	ic.convertHostPathToPVC()
+	ic.remediateHostPaths()
+	ic.makeConfigMapsImmutable()
 
 	if len(ic.ir.Storages) == 0 {
 		log.Debugf("Empty storage list. Nothing to customize.")
@@ -86,58 +102,425 @@ func (ic *storageCustomizer) customize(ir *irtypes.IR) error {
 	return nil
 }
 
-func (ic *storageCustomizer) convertHostPathToPVC() {
-	hostPathsVisited := map[string]string{}
-	for _, service := range ic.ir.Services {
-		log.Debugf("Service %s has %d volumes", service.Name, len(service.Volumes))
+// hostPathUsage contains the users of a host path
+type hostPathUsage struct {
+	users    []string
+	readOnly bool
+	content  map[string][]byte // the files at the host path, if they fit in config maps
+	isFile   bool
+	// oversized is true if the files at the host path are too large for config maps
+	oversized bool
+}
+
+// remediateHostPaths asks how each host path should be translated, since host paths tie the pods to the files on the nodes.
+// The decisions recorded in the plan are reused without asking.
+func (ic *storageCustomizer) remediateHostPaths() {
+	usages := map[string]*hostPathUsage{}
+	getUsage := func(hostPath string) *hostPathUsage {
+		usage, ok := usages[hostPath]
+		if !ok {
+			usage = &hostPathUsage{readOnly: true}
+			usages[hostPath] = usage
+		}
+		return usage
+	}
+	serviceNames := []string{}
+	for serviceName := range ic.ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ic.ir.Services[serviceName]
+		for _, v := range service.Volumes {
+			if v.HostPath == nil {
+				continue
+			}
+			log.Debugf("Detected host path [%+v]", v)
+			usage := getUsage(v.HostPath.Path)
+			usage.users = append(usage.users, serviceName)
+			for _, container := range service.Containers {
+				for _, mount := range container.VolumeMounts {
+					if mount.Name == v.Name && !mount.ReadOnly {
+						usage.readOnly = false
+					}
+				}
+			}
+		}
+	}
+	for _, obj := range ic.ir.CachedObjects {
+		objName := obj.GetObjectKind().GroupVersionKind().Kind
+		if accessor, err := meta.Accessor(obj); err == nil {
+			objName += " " + accessor.GetName()
+		}
+		updatePodSpecs(obj, func(podSpec map[string]interface{}) bool {
+			for _, hostPath := range getPodSpecHostPaths(podSpec) {
+				usage := getUsage(hostPath)
+				usage.users = append(usage.users, objName)
+				// The files are on the nodes of the source cluster, so they can't be put in a config map
+				usage.readOnly = false
+			}
+			return false
+		})
+	}
+	if len(usages) == 0 {
+		return
+	}
+	hostPaths := []string{}
+	for hostPath := range usages {
+		hostPaths = append(hostPaths, hostPath)
+	}
+	sort.Strings(hostPaths)
+	if ic.ir.HostPathRemediations == nil {
+		ic.ir.HostPathRemediations = map[string]plantypes.HostPathRemediationTypeValue{}
+	}
+	for _, hostPath := range hostPaths {
+		usage := usages[hostPath]
+		if usage.readOnly {
+			usage.content, usage.isFile, usage.oversized = loadHostPathContent(hostPath)
+		}
+		remediation, ok := ic.ir.HostPathRemediations[hostPath]
+		if !ok || (remediation == plantypes.ConfigMapHostPathRemediation && usage.content == nil) {
+			remediation = ic.selectHostPathRemediation(hostPath, *usage)
+		}
+		ic.ir.HostPathRemediations[hostPath] = remediation
+		log.Debugf("The host path %s used by %v is translated to a %s", hostPath, usage.users, remediation)
+	}
+	ic.applyHostPathRemediations(usages)
+}
+
+// selectHostPathRemediation asks how the host path should be translated
+func (ic *storageCustomizer) selectHostPathRemediation(hostPath string, usage hostPathUsage) plantypes.HostPathRemediationTypeValue {
+	options := []string{string(plantypes.PVCHostPathRemediation)}
+	def := plantypes.PVCHostPathRemediation
+	if usage.content != nil {
+		options = append(options, string(plantypes.ConfigMapHostPathRemediation))
+		def = plantypes.ConfigMapHostPathRemediation
+	}
+	options = append(options, string(plantypes.EmptyDirHostPathRemediation), string(plantypes.RetainHostPathRemediation))
+	desc := fmt.Sprintf("How should the host path %s used by %s be translated?", hostPath, strings.Join(usage.users, ", "))
+	hints := []string{
+		"PersistentVolumeClaim keeps the data across restarts. ConfigMap holds read-only config files. EmptyDir is scratch space deleted along with the pod.",
+		"HostPath keeps the pods tied to the files on the nodes. Choose it only if the files exist on every node.",
+	}
+	if usage.content != nil {
+		if parts := len(splitConfigMapContent(usage.content)); parts > 1 {
+			hints = append(hints, fmt.Sprintf("The host path is mounted read-only. Its files are split into %d config maps, which are mounted together using a projected volume.", parts))
+		} else {
+			hints = append(hints, "The host path is mounted read-only and its files fit in a config map.")
+		}
+	}
+	if usage.oversized {
+		hints = append(hints, "The files at the host path are too large for config maps. Copy them into the image using a COPY instruction in the Dockerfile, or use a PersistentVolumeClaim.")
+	}
+	qaKey := common.ConfigStoragesKey + common.Delim + `"` + hostPath + `"` + common.Delim + common.ConfigStoragesHostPathKeySegment
+	return plantypes.HostPathRemediationTypeValue(qaengine.FetchSelectAnswer(qaKey, desc, hints, string(def), options))
+}
+
+// applyHostPathRemediations replaces the host path volumes according to the decisions
+func (ic *storageCustomizer) applyHostPathRemediations(usages map[string]*hostPathUsage) {
+	storageNames := map[string]string{} // [host path][name of the pvc or config map]
+	for serviceName, service := range ic.ir.Services {
 		for vi, v := range service.Volumes {
-			if v.HostPath != nil {
-				if name, ok := hostPathsVisited[v.HostPath.Path]; !ok {
-					hostPathsVisited[v.HostPath.Path] = ""
-					log.Debugf("Detected host path [%+v]", v)
-					if !ic.shouldHostPathBeRetained(v.HostPath.Path) {
-						hostPathsVisited[v.HostPath.Path] = v.Name
-						v.VolumeSource = core.VolumeSource{
-							PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
-								ClaimName: v.Name,
-							}}
-						service.Volumes[vi] = v
-						ic.ir.Services[service.Name] = service
-						storageObj := irtypes.Storage{
-							StorageType: irtypes.PVCKind,
-							Name:        v.Name,
-							PersistentVolumeClaimSpec: core.PersistentVolumeClaimSpec{
-								VolumeName: v.Name,
-								Resources: core.ResourceRequirements{
-									Requests: core.ResourceList{
-										core.ResourceStorage: common.DefaultPVCSize,
-									},
-								},
-							}}
-						ic.ir.AddStorage(storageObj)
-					} else {
-						log.Debugf("Host path [%s] is retained", v.HostPath.Path)
+			if v.HostPath == nil {
+				continue
+			}
+			hostPath := v.HostPath.Path
+			remediation := ic.ir.HostPathRemediations[hostPath]
+			if remediation == plantypes.RetainHostPathRemediation {
+				log.Debugf("Host path [%s] is retained", hostPath)
+				service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+					hostPathTODOKey + "." + v.Name: fmt.Sprintf("The host path %s is retained. Make sure that it exists on all the nodes where the pods can run.", hostPath),
+				})
+				continue
+			}
+			if remediation == plantypes.EmptyDirHostPathRemediation {
+				v.VolumeSource = core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}
+				service.Volumes[vi] = v
+				continue
+			}
+			name, ok := storageNames[hostPath]
+			if !ok {
+				name = v.Name
+				storageNames[hostPath] = name
+				ic.addHostPathStorage(name, remediation, usages[hostPath])
+			}
+			if remediation == plantypes.ConfigMapHostPathRemediation {
+				v.VolumeSource = getConfigMapVolumeSource(name, usages[hostPath].content)
+				if usages[hostPath].isFile {
+					// A single file is mounted using a sub path, so that the other files in the directory it is mounted in are retained
+					for ci, container := range service.Containers {
+						for mi, mount := range container.VolumeMounts {
+							if mount.Name == v.Name {
+								service.Containers[ci].VolumeMounts[mi].SubPath = filepath.Base(hostPath)
+							}
+						}
 					}
-				} else {
-					v.VolumeSource = core.VolumeSource{
-						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
-							ClaimName: name,
-						}}
-					service.Volumes[vi] = v
-					ic.ir.Services[service.Name] = service
 				}
+			} else {
+				v.VolumeSource = core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: name}}
+			}
+			service.Volumes[vi] = v
+		}
+		ic.ir.Services[serviceName] = service
+	}
+	for i, obj := range ic.ir.CachedObjects {
+		ic.ir.CachedObjects[i] = updatePodSpecs(obj, func(podSpec map[string]interface{}) bool {
+			updated := false
+			volumes, _ := podSpec["volumes"].([]interface{})
+			for _, volume := range volumes {
+				volumeMap, ok := volume.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				hostPath, ok := getVolumeHostPath(volumeMap)
+				if !ok {
+					continue
+				}
+				switch ic.ir.HostPathRemediations[hostPath] {
+				case plantypes.EmptyDirHostPathRemediation:
+					delete(volumeMap, "hostPath")
+					volumeMap["emptyDir"] = map[string]interface{}{}
+					updated = true
+				case plantypes.PVCHostPathRemediation:
+					name, ok := storageNames[hostPath]
+					if !ok {
+						name, _ = volumeMap["name"].(string)
+						storageNames[hostPath] = name
+						ic.addHostPathStorage(name, plantypes.PVCHostPathRemediation, usages[hostPath])
+					}
+					delete(volumeMap, "hostPath")
+					volumeMap["persistentVolumeClaim"] = map[string]interface{}{"claimName": name}
+					updated = true
+				}
+			}
+			return updated
+		})
+	}
+}
+
+// addHostPathStorage adds the persistent volume claim or the config map that replaces a host path
+func (ic *storageCustomizer) addHostPathStorage(name string, remediation plantypes.HostPathRemediationTypeValue, usage *hostPathUsage) {
+	if remediation == plantypes.ConfigMapHostPathRemediation {
+		parts := splitConfigMapContent(usage.content)
+		if len(parts) == 1 {
+			ic.ir.AddStorage(irtypes.Storage{StorageType: irtypes.ConfigMapKind, Name: name, Content: usage.content})
+			return
+		}
+		for i, part := range parts {
+			ic.ir.AddStorage(irtypes.Storage{StorageType: irtypes.ConfigMapKind, Name: getConfigMapPartName(name, i), Content: part})
+		}
+		return
+	}
+	ic.ir.AddStorage(irtypes.Storage{
+		StorageType: irtypes.PVCKind,
+		Name:        name,
+		PersistentVolumeClaimSpec: core.PersistentVolumeClaimSpec{
+			VolumeName: name,
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: common.DefaultPVCSize,
+				},
+			},
+		}})
+}
+
+// getPodSpecHostPaths returns the host paths of the volumes of the pod spec
+func getPodSpecHostPaths(podSpec map[string]interface{}) []string {
+	hostPaths := []string{}
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, volume := range volumes {
+		if volumeMap, ok := volume.(map[string]interface{}); ok {
+			if hostPath, ok := getVolumeHostPath(volumeMap); ok {
+				hostPaths = append(hostPaths, hostPath)
+			}
+		}
+	}
+	return hostPaths
+}
+
+// getVolumeHostPath returns the host path of the volume, if it is a host path volume
+func getVolumeHostPath(volume map[string]interface{}) (string, bool) {
+	hostPathSource, ok := volume["hostPath"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	hostPath, ok := hostPathSource["path"].(string)
+	return hostPath, ok && hostPath != ""
+}
+
+// loadHostPathContent returns the files at the host path if they fit in config maps, along with whether the host path is
+// a file and whether the files are too large for config maps. Directories with sub directories are not loaded, since the
+// files of a config map are in a single directory.
+func loadHostPathContent(hostPath string) (map[string][]byte, bool, bool) {
+	finfo, err := os.Stat(hostPath)
+	if err != nil {
+		log.Debugf("Unable to access the host path %s . Error: %q", hostPath, err)
+		return nil, false, false
+	}
+	filePaths := []string{hostPath}
+	if finfo.IsDir() {
+		finfos, err := ioutil.ReadDir(hostPath)
+		if err != nil {
+			log.Debugf("Unable to read the directory at the host path %s . Error: %q", hostPath, err)
+			return nil, false, false
+		}
+		filePaths = []string{}
+		for _, finfo := range finfos {
+			if !finfo.Mode().IsRegular() {
+				return nil, false, false
 			}
+			filePaths = append(filePaths, filepath.Join(hostPath, finfo.Name()))
+		}
+	}
+	content := map[string][]byte{}
+	size := 0
+	for _, filePath := range filePaths {
+		key := filepath.Base(filePath)
+		if len(validation.IsConfigMapKey(key)) > 0 {
+			return nil, false, false
+		}
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			log.Debugf("Unable to read the file at path %s . Error: %q", filePath, err)
+			return nil, false, false
+		}
+		// A file larger than a config map can't be split
+		if size += len(data); size > maxHostPathContentSize || len(data) > maxConfigMapSize {
+			log.Warnf("The files at the host path %s are too large for config maps. Copy them into the image, or use a persistent volume claim.", hostPath)
+			return nil, false, true
 		}
+		content[key] = data
 	}
+	return content, !finfo.IsDir(), false
 }
 
-func (ic storageCustomizer) shouldHostPathBeRetained(hostPath string) bool {
-	// if filepath.IsAbs(hostPath) {
-	// 	return true
-	// }
+// splitConfigMapContent splits the files into parts which fit in config maps
+func splitConfigMapContent(content map[string][]byte) []map[string][]byte {
+	keys := []string{}
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []map[string][]byte{{}}
+	size := 0
+	for _, key := range keys {
+		data := content[key]
+		if size+len(data) > maxConfigMapSize && len(parts[len(parts)-1]) > 0 {
+			parts = append(parts, map[string][]byte{})
+			size = 0
+		}
+		parts[len(parts)-1][key] = data
+		size += len(data)
+	}
+	return parts
+}
+
+// getConfigMapPartName returns the name of the config map containing a part of the files
+func getConfigMapPartName(name string, part int) string {
+	return fmt.Sprintf("%s-%d", name, part+1)
+}
+
+// getConfigMapVolumeSource returns the volume source which mounts the config maps containing the files. The files split
+// into several config maps are mounted together using a projected volume.
+func getConfigMapVolumeSource(name string, content map[string][]byte) core.VolumeSource {
+	parts := splitConfigMapContent(content)
+	if len(parts) <= 1 {
+		return core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: name}}}
+	}
+	sources := []core.VolumeProjection{}
+	for i := range parts {
+		sources = append(sources, core.VolumeProjection{ConfigMap: &core.ConfigMapProjection{LocalObjectReference: core.LocalObjectReference{Name: getConfigMapPartName(name, i)}}})
+	}
+	return core.VolumeSource{Projected: &core.ProjectedVolumeSource{Sources: sources}}
+}
+
+// makeConfigMapsImmutable asks whether the config maps should be immutable. The services using the immutable config
+// maps are annotated with the hash of their content, so that the services are rolled out when the config changes.
+func (ic *storageCustomizer) makeConfigMapsImmutable() {
+	configMaps := map[string]map[string][]byte{}
+	for _, storage := range ic.ir.Storages {
+		if storage.StorageType == irtypes.ConfigMapKind {
+			configMaps[storage.Name] = storage.Content
+		}
+	}
+	if len(configMaps) == 0 {
+		return
+	}
+	hints := []string{
+		"Immutable config maps are protected from accidental updates and reduce the load on the API server. To change the config, the config map has to be deleted and created again.",
+		"The services using the config maps are annotated with the hash of the config, so that they are rolled out when the config changes.",
+	}
+	if !qaengine.FetchBoolAnswer(common.ConfigStoragesImmutableConfigMapsKey, "Do you want to generate immutable config maps?", hints, false) {
+		return
+	}
+	for i, storage := range ic.ir.Storages {
+		if storage.StorageType == irtypes.ConfigMapKind {
+			ic.ir.Storages[i].Immutable = true
+		}
+	}
+	for serviceName, service := range ic.ir.Services {
+		names := getPodSpecConfigMapNames(service.PodSpec)
+		hash := sha256.New()
+		found := false
+		for _, name := range names {
+			content, ok := configMaps[name]
+			if !ok {
+				continue
+			}
+			found = true
+			keys := []string{}
+			for key := range content {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			fmt.Fprintf(hash, "%s\n", name)
+			for _, key := range keys {
+				fmt.Fprintf(hash, "%s\n%d\n", key, len(content[key]))
+				hash.Write(content[key])
+			}
+		}
+		if !found {
+			continue
+		}
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{common.ConfigHashAnnotation: fmt.Sprintf("%x", hash.Sum(nil))})
+		ic.ir.Services[serviceName] = service
+	}
+}
 
-	ans := qaengine.FetchBoolAnswer(common.ConfigStoragesPVCForHostPathKey, fmt.Sprintf("Do you want to create PVC for host path [%s]?:", hostPath), []string{"Use PVC for persistent storage wherever applicable"}, false)
-	return !ans
+// getPodSpecConfigMapNames returns the sorted names of the config maps used by the volumes and the containers of the pod spec
+func getPodSpecConfigMapNames(podSpec core.PodSpec) []string {
+	names := []string{}
+	add := func(name string) {
+		if name != "" && !common.IsStringPresent(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		if volume.ConfigMap != nil {
+			add(volume.ConfigMap.Name)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(source.ConfigMap.Name)
+				}
+			}
+		}
+	}
+	for _, container := range append(append([]core.Container{}, podSpec.InitContainers...), podSpec.Containers...) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				add(envFrom.ConfigMapRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				add(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (ic storageCustomizer) shouldConfigureSeparately(claims []string) bool {
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestRemediateHostPaths(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "move2kube-hostpath")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory. Error: %q", err)
+	}
+	defer os.RemoveAll(tempDir)
+	configPath := filepath.Join(tempDir, "nginx.conf")
+	if err := ioutil.WriteFile(configPath, []byte("worker_processes 1;"), 0644); err != nil {
+		t.Fatalf("Failed to write the config file. Error: %q", err)
+	}
+	dataPath := filepath.Join(tempDir, "data")
+	cachePath := "/var/cache/app"
+
+	service := irtypes.NewServiceWithName("web")
+	service.Volumes = []core.Volume{
+		{Name: "config", VolumeSource: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: configPath}}},
+		{Name: "data", VolumeSource: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: dataPath}}},
+	}
+	service.Containers = []core.Container{{Name: "web", VolumeMounts: []core.VolumeMount{
+		{Name: "config", MountPath: "/etc/nginx/nginx.conf", ReadOnly: true},
+		{Name: "data", MountPath: "/data"},
+	}}}
+	deployment := &appsv1.Deployment{}
+	deployment.Name = "worker"
+	deployment.Spec.Template.Spec = corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "worker", Image: "worker:v1"}},
+		Volumes:    []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cachePath}}}},
+	}
+	ir := irtypes.IR{
+		Services:      map[string]irtypes.Service{"web": service},
+		CachedObjects: []runtime.Object{deployment},
+		HostPathRemediations: map[string]plantypes.HostPathRemediationTypeValue{
+			configPath: plantypes.ConfigMapHostPathRemediation,
+			dataPath:   plantypes.PVCHostPathRemediation,
+			cachePath:  plantypes.EmptyDirHostPathRemediation,
+		},
+	}
+	ic := storageCustomizer{ir: &ir}
+
+	ic.remediateHostPaths()
+
+	volumes := ir.Services["web"].Volumes
+	if volumes[0].ConfigMap == nil || volumes[0].ConfigMap.Name != "config" {
+		t.Fatalf("Expected the read-only host path to be translated to a config map. Actual: %+v", volumes[0].VolumeSource)
+	}
+	if subPath := ir.Services["web"].Containers[0].VolumeMounts[0].SubPath; subPath != "nginx.conf" {
+		t.Fatalf("Expected the config file to be mounted using a sub path. Actual: %s", subPath)
+	}
+	if volumes[1].PersistentVolumeClaim == nil || volumes[1].PersistentVolumeClaim.ClaimName != "data" {
+		t.Fatalf("Expected the host path to be translated to a persistent volume claim. Actual: %+v", volumes[1].VolumeSource)
+	}
+	if len(ir.Storages) != 2 {
+		t.Fatalf("Expected a config map and a persistent volume claim. Actual: %+v", ir.Storages)
+	}
+	for _, storage := range ir.Storages {
+		if storage.StorageType == irtypes.ConfigMapKind && string(storage.Content["nginx.conf"]) != "worker_processes 1;" {
+			t.Fatalf("Expected the config map to contain the config file. Actual: %+v", storage.Content)
+		}
+	}
+	newDeployment, ok := ir.CachedObjects[0].(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("Expected the deployment to retain its type. Actual: %T", ir.CachedObjects[0])
+	}
+	if volume := newDeployment.Spec.Template.Spec.Volumes[0]; volume.HostPath != nil || volume.EmptyDir == nil {
+		t.Fatalf("Expected the host path of the deployment to be translated to an empty dir. Actual: %+v", volume.VolumeSource)
+	}
+}
+
+func TestSplitConfigMapContent(t *testing.T) {
+	content := map[string][]byte{
+		"a.json": make([]byte, maxConfigMapSize/2),
+		"b.json": make([]byte, maxConfigMapSize/2),
+		"c.json": make([]byte, maxConfigMapSize/2),
+	}
+
+	parts := splitConfigMapContent(content)
+
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 1 {
+		t.Fatalf("Expected the files to be split into 2 config maps. Actual: %d", len(parts))
+	}
+	source := getConfigMapVolumeSource("data", content)
+	if source.Projected == nil || len(source.Projected.Sources) != 2 || source.Projected.Sources[1].ConfigMap.Name != "data-2" {
+		t.Fatalf("Expected a projected volume of the config maps. Actual: %+v", source)
+	}
+	if source := getConfigMapVolumeSource("config", map[string][]byte{"nginx.conf": []byte("worker_processes 1;")}); source.ConfigMap == nil || source.ConfigMap.Name != "config" {
+		t.Fatalf("Expected a config map volume. Actual: %+v", source)
+	}
+}
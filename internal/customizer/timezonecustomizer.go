@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	common "github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	timezoneEnvVar = "TZ"
+	localeEnvVar   = "LANG"
+
+	defaultTimezone = "UTC"
+	defaultLocale   = "C.UTF-8"
+
+	zoneinfoDir       = "/usr/share/zoneinfo"
+	localtimeHostPath = "/etc/localtime"
+
+	// hostFilesTODOKey flags the services which use files of the host that do not exist in the pods
+	hostFilesTODOKey = common.TODOAnnotation + "hostfiles"
+)
+
+var (
+	// timezoneHostPaths are the host paths which are mounted to use the timezone of the host
+	timezoneHostPaths = []string{localtimeHostPath, "/etc/timezone", zoneinfoDir}
+	// localeHostPaths are the host paths which are mounted to use the locale of the host
+	localeHostPaths = []string{"/etc/locale.conf", "/etc/default/locale"}
+	// kubeletHostPaths are the host paths which are managed by the kubelet in the pods, and must not be mounted from the nodes
+	kubeletHostPaths = map[string]string{
+		"/etc/hosts":       "Use the hostAliases of the pod spec to add entries to /etc/hosts.",
+		"/etc/resolv.conf": "Use the dnsConfig of the pod spec to change the DNS resolution.",
+		"/etc/hostname":    "Use the hostname of the pod spec to change the hostname.",
+	}
+)
+
+// timezoneCustomizer replaces the host paths mounted for the timezone and the locale of the host by environment
+// variables, and flags the other files of the host used by the services
+type timezoneCustomizer struct {
+}
+
+// customize asks for the timezone and the locale of the services that mount them from the host
+func (tc *timezoneCustomizer) customize(ir *irtypes.IR) error {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	timezone, locale := "", ""
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		usesTimezone, usesLocale := false, false
+		todos := map[string]string{}
+		for _, volume := range append([]core.Volume{}, service.Volumes...) {
+			if volume.HostPath == nil {
+				continue
+			}
+			hostPath := path.Clean(volume.HostPath.Path)
+			switch {
+			case isHostPathIn(hostPath, timezoneHostPaths):
+				usesTimezone = true
+				removeVolume(&service, volume.Name)
+			case isHostPathIn(hostPath, localeHostPaths):
+				usesLocale = true
+				removeVolume(&service, volume.Name)
+			case kubeletHostPaths[hostPath] != "":
+				log.Warnf("The service %s mounts the host file %s, which is managed by Kubernetes. The mount is removed. %s", serviceName, hostPath, kubeletHostPaths[hostPath])
+				todos[hostFilesTODOKey+"."+volume.Name] = fmt.Sprintf("The mount of the host file %s is removed. %s", hostPath, kubeletHostPaths[hostPath])
+				removeVolume(&service, volume.Name)
+			case strings.HasPrefix(hostPath, "/etc/"):
+				log.Warnf("The service %s mounts the host file %s, which does not exist on the nodes of the target cluster unless it is copied there.", serviceName, hostPath)
+				todos[hostFilesTODOKey+"."+volume.Name] = fmt.Sprintf("The host file %s is not available in the target cluster. Copy it into the image, or put it in a config map or a secret.", hostPath)
+			}
+		}
+		if usesTimezone {
+			if timezone == "" {
+				timezone = tc.getTimezone(ir)
+			}
+			setEnvIfAbsent(&service, timezoneEnvVar, timezone)
+			todos[hostFilesTODOKey+".timezone"] = fmt.Sprintf("The timezone of the host is replaced by the %s environment variable. The image needs the timezone database, like the tzdata package.", timezoneEnvVar)
+		}
+		if usesLocale {
+			if locale == "" {
+				locale = tc.getLocale(ir)
+			}
+			setEnvIfAbsent(&service, localeEnvVar, locale)
+			todos[hostFilesTODOKey+".locale"] = fmt.Sprintf("The locale of the host is replaced by the %s environment variable. The locale has to be installed in the image.", localeEnvVar)
+		}
+		if len(todos) > 0 {
+			service.Annotations = common.MergeStringMaps(service.Annotations, todos)
+		}
+		ir.Services[serviceName] = service
+	}
+	return nil
+}
+
+// getTimezone asks for the timezone of the services. The default is the timezone already used by a service, else the timezone of this machine.
+func (tc *timezoneCustomizer) getTimezone(ir *irtypes.IR) string {
+	def := getEnvOfServices(ir, timezoneEnvVar)
+	if def == "" {
+		def = getTimezoneOfLocaltime(localtimeHostPath)
+	}
+	desc := "Enter the timezone of the services which use the timezone of the host :"
+	hints := []string{"Use the name of a timezone of the tz database, like Europe/Berlin."}
+	return strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigTargetTimezoneKey, desc, hints, def))
+}
+
+// getLocale asks for the locale of the services. The default is the locale already used by a service.
+func (tc *timezoneCustomizer) getLocale(ir *irtypes.IR) string {
+	def := getEnvOfServices(ir, localeEnvVar)
+	if def == "" {
+		def = defaultLocale
+	}
+	desc := "Enter the locale of the services which use the locale of the host :"
+	hints := []string{"For example en_US.UTF-8. The locale has to be installed in the images."}
+	return strings.TrimSpace(qaengine.FetchStringAnswer(common.ConfigTargetLocaleKey, desc, hints, def))
+}
+
+// getTimezoneOfLocaltime returns the timezone pointed to by the localtime symlink, like /etc/localtime -> /usr/share/zoneinfo/Europe/Berlin
+func getTimezoneOfLocaltime(localtime string) string {
+	target, err := filepath.EvalSymlinks(localtime)
+	if err != nil {
+		log.Debugf("Failed to get the timezone of %s . Error: %q", localtime, err)
+		return defaultTimezone
+	}
+	if idx := strings.Index(target, "zoneinfo/"); idx != -1 {
+		return strings.TrimPrefix(target[idx+len("zoneinfo/"):], "posix/")
+	}
+	return defaultTimezone
+}
+
+// getEnvOfServices returns the first value of the environment variable in the containers of the services
+func getEnvOfServices(ir *irtypes.IR, name string) string {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		for _, container := range ir.Services[serviceName].Containers {
+			for _, env := range container.Env {
+				if env.Name == name && env.Value != "" {
+					return env.Value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// isHostPathIn checks if the host path is one of the paths or inside one of them
+func isHostPathIn(hostPath string, paths []string) bool {
+	for _, p := range paths {
+		if hostPath == p || strings.HasPrefix(hostPath, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// removeVolume removes the volume and its mounts from the service
+func removeVolume(service *irtypes.Service, volumeName string) {
+	volumes := []core.Volume{}
+	for _, volume := range service.Volumes {
+		if volume.Name != volumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	service.Volumes = volumes
+	for i, container := range service.Containers {
+		mounts := []core.VolumeMount{}
+		for _, mount := range container.VolumeMounts {
+			if mount.Name != volumeName {
+				mounts = append(mounts, mount)
+			}
+		}
+		service.Containers[i].VolumeMounts = mounts
+	}
+}
+
+// setEnvIfAbsent sets the environment variable in the containers of the service that do not have it
+func setEnvIfAbsent(service *irtypes.Service, name, value string) {
+	for i, container := range service.Containers {
+		found := false
+		for _, env := range container.Env {
+			if env.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			service.Containers[i].Env = append(service.Containers[i].Env, core.EnvVar{Name: name, Value: value})
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetTimezoneOfLocaltime(t *testing.T) {
+	dir := t.TempDir()
+	zoneinfo := filepath.Join(dir, "usr", "share", "zoneinfo", "Europe")
+	if err := os.MkdirAll(zoneinfo, 0755); err != nil {
+		t.Fatalf("Failed to create the directory %s . Error: %q", zoneinfo, err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(zoneinfo, "Berlin"), []byte("TZif"), 0644); err != nil {
+		t.Fatalf("Failed to write the timezone file. Error: %q", err)
+	}
+	localtime := filepath.Join(dir, "localtime")
+	if err := os.Symlink(filepath.Join(zoneinfo, "Berlin"), localtime); err != nil {
+		t.Skipf("Symlinks are not supported. Error: %q", err)
+	}
+	if timezone := getTimezoneOfLocaltime(localtime); timezone != "Europe/Berlin" {
+		t.Fatalf("Expected the timezone Europe/Berlin . Actual: %s", timezone)
+	}
+	if timezone := getTimezoneOfLocaltime(filepath.Join(dir, "missing")); timezone != defaultTimezone {
+		t.Fatalf("Expected the default timezone %s . Actual: %s", defaultTimezone, timezone)
+	}
+}
+
+func TestRemoveVolume(t *testing.T) {
+	service := irtypes.NewServiceWithName("svc1")
+	service.Volumes = []core.Volume{
+		{Name: "localtime", VolumeSource: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/etc/localtime"}}},
+		{Name: "data", VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}},
+	}
+	service.Containers = []core.Container{{Name: "svc1", VolumeMounts: []core.VolumeMount{{Name: "localtime", MountPath: "/etc/localtime"}, {Name: "data", MountPath: "/data"}}}}
+
+	if !isHostPathIn("/usr/share/zoneinfo/Europe/Berlin", timezoneHostPaths) || isHostPathIn("/etc/localtime.bak", timezoneHostPaths) {
+		t.Fatalf("Failed to detect the timezone host paths")
+	}
+	removeVolume(&service, "localtime")
+	if len(service.Volumes) != 1 || service.Volumes[0].Name != "data" {
+		t.Fatalf("Expected only the data volume. Actual: %+v", service.Volumes)
+	}
+	if mounts := service.Containers[0].VolumeMounts; len(mounts) != 1 || mounts[0].Name != "data" {
+		t.Fatalf("Expected only the data volume mount. Actual: %+v", mounts)
+	}
+}
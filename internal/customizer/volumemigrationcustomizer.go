@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// defaultNamespace is the namespace of the objects which do not specify one
+	defaultNamespace = "default"
+)
+
+// volumeMigrationCustomizer selects the persistent volume claims of the kubernetes artifacts whose data has to move
+// along with the workloads, since the volumes of the source cluster are not accessible from the target cluster
+type volumeMigrationCustomizer struct {
+}
+
+// customize asks which persistent volume claims have to be migrated and the storage classes they use in the target cluster.
+// The migrated claims are removed from the artifacts, since the migration manifests restore them along with their data.
+func (vc *volumeMigrationCustomizer) customize(ir *irtypes.IR) error {
+	claims := map[string]irtypes.VolumeMigration{}
+	claimIDs := []string{}
+	for _, obj := range ir.CachedObjects {
+		if claim, ok := getVolumeMigration(obj); ok {
+			id := claim.Namespace + "/" + claim.ClaimName
+			claims[id] = claim
+			claimIDs = append(claimIDs, id)
+		}
+	}
+	if len(claimIDs) == 0 {
+		return nil
+	}
+	hints := []string{"The data of the selected claims is moved using volume snapshots or velero. The other claims are created empty in the target cluster."}
+	selectedIDs := qaengine.FetchMultiSelectAnswer(common.ConfigStoragesMigrateKey, "Select the persistent volume claims whose data has to be moved to the target cluster:", hints, claimIDs, claimIDs)
+	if len(selectedIDs) == 0 {
+		return nil
+	}
+	for _, id := range selectedIDs {
+		claim, ok := claims[id]
+		if !ok {
+			continue
+		}
+		claim.TargetStorageClass = selectTargetStorageClass(ir.TargetClusterSpec.StorageClasses, claim)
+		ir.VolumeMigrations = append(ir.VolumeMigrations, claim)
+	}
+	objs := []runtime.Object{}
+	for _, obj := range ir.CachedObjects {
+		if claim, ok := getVolumeMigration(obj); ok && common.IsStringPresent(selectedIDs, claim.Namespace+"/"+claim.ClaimName) {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	ir.CachedObjects = objs
+	log.Debugf("Persistent volume claims to be migrated : %+v", ir.VolumeMigrations)
+	return nil
+}
+
+// selectTargetStorageClass asks which storage class of the target cluster the claim should use.
+// The storage class of the source cluster is the default if the target cluster has it.
+func selectTargetStorageClass(storageClasses []string, claim irtypes.VolumeMigration) string {
+	if len(storageClasses) == 0 {
+		log.Warnf("No storage classes available in the cluster. The claim %s/%s retains the storage class %q", claim.Namespace, claim.ClaimName, claim.SourceStorageClass)
+		return claim.SourceStorageClass
+	}
+	def := storageClasses[0]
+	if common.IsStringPresent(storageClasses, claim.SourceStorageClass) {
+		def = claim.SourceStorageClass
+	}
+	desc := fmt.Sprintf("Which storage class to use for the migrated persistent volume claim [%s] in the target cluster?", claim.ClaimName)
+	hints := []string{fmt.Sprintf("The claim uses the storage class %q in the source cluster.", claim.SourceStorageClass)}
+	qaKey := common.ConfigStoragesKey + common.Delim + `"` + claim.ClaimName + `"` + common.Delim + "storageclass"
+	return qaengine.FetchSelectAnswer(qaKey, desc, hints, def, storageClasses)
+}
+
+// getVolumeMigration returns the details of the object if it is a persistent volume claim
+func getVolumeMigration(obj runtime.Object) (irtypes.VolumeMigration, bool) {
+	if obj.GetObjectKind().GroupVersionKind().Kind != string(irtypes.PVCKind) {
+		return irtypes.VolumeMigration{}, false
+	}
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		log.Debugf("Failed to convert the object %+v to unstructured. Error: %q", obj.GetObjectKind(), err)
+		return irtypes.VolumeMigration{}, false
+	}
+	claim := irtypes.VolumeMigration{}
+	claim.ClaimName, _, _ = unstructured.NestedString(unstructuredObj, "metadata", "name")
+	claim.Namespace, _, _ = unstructured.NestedString(unstructuredObj, "metadata", "namespace")
+	if claim.Namespace == "" {
+		claim.Namespace = defaultNamespace
+	}
+	claim.SourceStorageClass, _, _ = unstructured.NestedString(unstructuredObj, "spec", "storageClassName")
+	claim.Size, _, _ = unstructured.NestedString(unstructuredObj, "spec", "resources", "requests", "storage")
+	if claim.Size == "" {
+		claim.Size = common.DefaultPVCSize.String()
+	}
+	claim.AccessModes, _, _ = unstructured.NestedStringSlice(unstructuredObj, "spec", "accessModes")
+	return claim, claim.ClaimName != ""
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customizer
+
+import (
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetVolumeMigration(t *testing.T) {
+	storageClass := "gp2"
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Kind = string(irtypes.PVCKind)
+	pvc.APIVersion = "v1"
+	pvc.Name = "data"
+	pvc.Spec.StorageClassName = &storageClass
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}
+
+	claim, ok := getVolumeMigration(pvc)
+	if !ok {
+		t.Fatalf("Expected the persistent volume claim to be detected")
+	}
+	want := irtypes.VolumeMigration{ClaimName: "data", Namespace: defaultNamespace, SourceStorageClass: "gp2", Size: "5Gi"}
+	if claim.ClaimName != want.ClaimName || claim.Namespace != want.Namespace || claim.SourceStorageClass != want.SourceStorageClass || claim.Size != want.Size {
+		t.Fatalf("Expected the claim %+v . Actual: %+v", want, claim)
+	}
+	if len(claim.AccessModes) != 1 || claim.AccessModes[0] != string(corev1.ReadWriteOnce) {
+		t.Fatalf("Expected the access modes to be retained. Actual: %v", claim.AccessModes)
+	}
+
+	if _, ok := getVolumeMigration(&corev1.Service{}); ok {
+		t.Fatalf("Expected a service not to be detected as a persistent volume claim")
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema generates JSON schemas from the yaml tags of go types and validates yaml documents against them
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Draft07 is the version of the JSON schema specification used by the generated schemas
+	Draft07 = "http://json-schema.org/draft-07/schema#"
+)
+
+// Types of the JSON schema
+const (
+	ObjectType  = "object"
+	ArrayType   = "array"
+	StringType  = "string"
+	IntegerType = "integer"
+	NumberType  = "number"
+	BooleanType = "boolean"
+)
+
+// Schema is the subset of a JSON schema that is required to describe the yaml files of move2kube
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties is either false or the schema of the values of the properties that are not in Properties
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema     `json:"propertyNames,omitempty"`
+	Items                *Schema     `json:"items,omitempty"`
+	Enum                 []string    `json:"enum,omitempty"`
+}
+
+// ValidationError is a violation of the schema found at a position in the yaml document
+type ValidationError struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+// Error returns the error as a string
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// Reflect creates the schema of a go type using its yaml tags. The enums contain the allowed values of string types.
+// Fields without omitempty are required and structs do not allow unknown fields.
+// Structs which recursively contain themselves are described as objects without properties at the point of recursion.
+func Reflect(t reflect.Type, enums map[reflect.Type][]string) *Schema {
+	return reflectType(t, enums, map[reflect.Type]bool{})
+}
+
+// reflectType creates the schema of a go type. The structs being reflected are tracked to stop at recursive types.
+func reflectType(t reflect.Type, enums map[reflect.Type][]string, reflecting map[reflect.Type]bool) *Schema {
+	if t.Kind() == reflect.Ptr {
+		return reflectType(t.Elem(), enums, reflecting)
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if reflecting[t] {
+			return &Schema{Type: ObjectType}
+		}
+		reflecting[t] = true
+		defer delete(reflecting, t)
+		schema := &Schema{Type: ObjectType, Properties: map[string]*Schema{}, AdditionalProperties: false}
+		reflectFields(t, enums, schema, reflecting)
+		return schema
+	case reflect.Map:
+		schema := &Schema{Type: ObjectType, AdditionalProperties: reflectType(t.Elem(), enums, reflecting)}
+		if keyEnum, ok := enums[t.Key()]; ok {
+			schema.PropertyNames = &Schema{Type: StringType, Enum: keyEnum}
+		}
+		return schema
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: StringType}
+		}
+		return &Schema{Type: ArrayType, Items: reflectType(t.Elem(), enums, reflecting)}
+	case reflect.String:
+		return &Schema{Type: StringType, Enum: enums[t]}
+	case reflect.Bool:
+		return &Schema{Type: BooleanType}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: IntegerType}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: NumberType}
+	}
+	return &Schema{}
+}
+
+// reflectFields adds the fields of the struct, including the inlined ones, to the schema
+func reflectFields(t reflect.Type, enums map[reflect.Type][]string, schema *Schema, reflecting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		tagParts := strings.Split(tag, ",")
+		name, options := tagParts[0], tagParts[1:]
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if hasOption(options, "inline") && fieldType.Kind() == reflect.Struct {
+			reflectFields(fieldType, enums, schema, reflecting)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		schema.Properties[name] = reflectType(field.Type, enums, reflecting)
+		if !hasOption(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the yaml document against the schema
+func (s *Schema) Validate(node *yaml.Node) []ValidationError {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	return s.validate(node, "")
+}
+
+func (s *Schema) validate(node *yaml.Node, path string) []ValidationError {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		// null values are decoded as the zero value of the type
+		return nil
+	}
+	newError := func(format string, args ...interface{}) []ValidationError {
+		return []ValidationError{{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+	switch s.Type {
+	case ObjectType:
+		if node.Kind != yaml.MappingNode {
+			return newError("expected an object but found %s", describeNode(node))
+		}
+		return s.validateObject(node, path)
+	case ArrayType:
+		if node.Kind != yaml.SequenceNode {
+			return newError("expected an array but found %s", describeNode(node))
+		}
+		errs := []ValidationError{}
+		for i, item := range node.Content {
+			errs = append(errs, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case StringType:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+			return newError("expected a string but found %s", describeNode(node))
+		}
+		if len(s.Enum) > 0 && !common.IsStringPresent(s.Enum, node.Value) {
+			return newError("%q is not one of %s", node.Value, strings.Join(s.Enum, ", "))
+		}
+	case BooleanType:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return newError("expected a boolean but found %s", describeNode(node))
+		}
+	case IntegerType:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return newError("expected an integer but found %s", describeNode(node))
+		}
+	case NumberType:
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			return newError("expected a number but found %s", describeNode(node))
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(node *yaml.Node, path string) []ValidationError {
+	errs := []ValidationError{}
+	found := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		found[key] = true
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		if s.PropertyNames != nil && len(s.PropertyNames.Enum) > 0 && !common.IsStringPresent(s.PropertyNames.Enum, key) {
+			errs = append(errs, ValidationError{Line: keyNode.Line, Column: keyNode.Column, Path: keyPath, Message: fmt.Sprintf("%q is not one of %s", key, strings.Join(s.PropertyNames.Enum, ", "))})
+			continue
+		}
+		if propSchema, ok := s.Properties[key]; ok {
+			errs = append(errs, propSchema.validate(valueNode, keyPath)...)
+			continue
+		}
+		switch additionalProperties := s.AdditionalProperties.(type) {
+		case bool:
+			if !additionalProperties {
+				errs = append(errs, ValidationError{Line: keyNode.Line, Column: keyNode.Column, Path: keyPath, Message: fmt.Sprintf("unknown field %q", key)})
+			}
+		case *Schema:
+			errs = append(errs, additionalProperties.validate(valueNode, keyPath)...)
+		}
+	}
+	required := append([]string{}, s.Required...)
+	sort.Strings(required)
+	for _, name := range required {
+		if !found[name] {
+			errs = append(errs, ValidationError{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf("missing required field %q", name)})
+		}
+	}
+	return errs
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "an array"
+	}
+	return fmt.Sprintf("%q (%s)", node.Value, strings.TrimPrefix(node.Tag, "!!"))
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+type testMeta struct {
+	Name string `yaml:"name"`
+}
+
+type testBuildType string
+
+type testObject struct {
+	testMeta  `yaml:",inline"`
+	BuildType testBuildType              `yaml:"buildType"`
+	Replicas  int                        `yaml:"replicas,omitempty"`
+	Enabled   bool                       `yaml:"enabled,omitempty"`
+	Artifacts map[testBuildType][]string `yaml:"artifacts,omitempty"`
+}
+
+func TestReflect(t *testing.T) {
+	schema := jsonschema.Reflect(reflect.TypeOf(testObject{}), map[reflect.Type][]string{reflect.TypeOf(testBuildType("")): {"Reuse", "CNB"}})
+	if schema.Type != jsonschema.ObjectType || schema.AdditionalProperties != false {
+		t.Fatalf("Expected a closed object. Actual: %+v", schema)
+	}
+	for _, name := range []string{"name", "buildType", "replicas", "enabled", "artifacts"} {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("Expected the property %s to be present. Actual: %+v", name, schema.Properties)
+		}
+	}
+	if want := []string{"name", "buildType"}; !reflect.DeepEqual(schema.Required, want) {
+		t.Errorf("Expected the required properties to be %v. Actual: %v", want, schema.Required)
+	}
+	if want := []string{"Reuse", "CNB"}; !reflect.DeepEqual(schema.Properties["buildType"].Enum, want) {
+		t.Errorf("Expected the enum to be %v. Actual: %v", want, schema.Properties["buildType"].Enum)
+	}
+	artifacts := schema.Properties["artifacts"]
+	if artifacts.PropertyNames == nil || artifacts.AdditionalProperties.(*jsonschema.Schema).Type != jsonschema.ArrayType {
+		t.Errorf("Expected the map to have restricted keys and array values. Actual: %+v", artifacts)
+	}
+}
+
+type testRecursiveObject struct {
+	Name     string                `yaml:"name"`
+	Children []testRecursiveObject `yaml:"children,omitempty"`
+}
+
+func TestReflectRecursiveType(t *testing.T) {
+	schema := jsonschema.Reflect(reflect.TypeOf(testRecursiveObject{}), nil)
+	children := schema.Properties["children"]
+	if children == nil || children.Items == nil || children.Items.Type != jsonschema.ObjectType || children.Items.Properties != nil {
+		t.Fatalf("Expected the recursive field to be an array of objects without properties. Actual: %+v", children)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := jsonschema.Reflect(reflect.TypeOf(testObject{}), map[reflect.Type][]string{reflect.TypeOf(testBuildType("")): {"Reuse", "CNB"}})
+
+	t.Run("valid document", func(t *testing.T) {
+		document := yaml.Node{}
+		if err := yaml.Unmarshal([]byte("name: svc1\nbuildType: CNB\nreplicas: 2\nartifacts:\n  Reuse: [a, b]\n"), &document); err != nil {
+			t.Fatalf("Failed to parse the document. Error: %q", err)
+		}
+		if errs := schema.Validate(&document); len(errs) != 0 {
+			t.Fatalf("Expected no errors. Actual: %v", errs)
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		document := yaml.Node{}
+		if err := yaml.Unmarshal([]byte("buildType: Docker\nreplicas: two\nunknown: true\nartifacts:\n  Other: []\n"), &document); err != nil {
+			t.Fatalf("Failed to parse the document. Error: %q", err)
+		}
+		want := []jsonschema.ValidationError{
+			{Line: 1, Column: 12, Path: "buildType", Message: `"Docker" is not one of Reuse, CNB`},
+			{Line: 2, Column: 11, Path: "replicas", Message: `expected an integer but found "two" (str)`},
+			{Line: 3, Column: 1, Path: "unknown", Message: `unknown field "unknown"`},
+			{Line: 5, Column: 3, Path: "artifacts.Other", Message: `"Other" is not one of Reuse, CNB`},
+			{Line: 1, Column: 1, Path: "", Message: `missing required field "name"`},
+		}
+		if errs := schema.Validate(&document); !reflect.DeepEqual(errs, want) {
+			t.Fatalf("Expected the errors to be %v. Actual: %v", want, errs)
+		}
+	})
+}
@@ -69,6 +69,10 @@ func convertToSupportedVersion(obj runtime.Object, clusterSpec collecttypes.Clus
 	if kind == common.ServiceKind && objgv.Group == knativev1.SchemeGroupVersion.Group {
 		return obj, nil
 	}
+	if !scheme.Recognizes(objgvk) && common.IsStringPresent(versions, objgv.String()) {
+		// Types that are not registered in the scheme (Eg: Gateway API) cannot be converted, but the cluster supports them as is.
+		return obj, nil
+	}
 	for _, v := range versions {
 		gv, err := schema.ParseGroupVersion(v)
 		if err != nil {
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sschema
+
+import (
+	semver "github.com/Masterminds/semver/v3"
+)
+
+// APIDeprecation is an API version of a kind which is deprecated, and removed in a later version of Kubernetes
+type APIDeprecation struct {
+	GroupVersion string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	// Replacement is the group version to migrate to. It is empty if the kind was removed without a replacement.
+	Replacement string
+	// SameSchemaAs is a group version known to the scheme whose schema is the same as the replacement, which the
+	// objects are converted through when the scheme does not know the replacement. Eg: autoscaling/v2beta2 for autoscaling/v2
+	SameSchemaAs string
+	// Notes are the fields and the defaults which changed in the replacement
+	Notes []string
+}
+
+var (
+	workloadSelectorNote = "spec.selector is required, and is no longer defaulted from the labels of the pod template"
+	deploymentNotes      = []string{
+		workloadSelectorNote,
+		"spec.revisionHistoryLimit defaults to 10 instead of keeping all the old replica sets",
+		"spec.progressDeadlineSeconds defaults to 600",
+		"the maxSurge and maxUnavailable of the rolling update default to 25% instead of 1",
+		"spec.rollbackTo was removed, use kubectl rollout undo instead",
+	}
+	ingressNotes = []string{
+		"spec.backend was renamed to spec.defaultBackend",
+		"the backends use service.name and service.port instead of serviceName and servicePort",
+		"the pathType of each path is required, and is set to Prefix when missing",
+		"the kubernetes.io/ingress.class annotation is replaced by spec.ingressClassName",
+	}
+	webhookNotes = []string{
+		"admissionReviewVersions and sideEffects are required, and sideEffects must be None or NoneOnDryRun",
+		"failurePolicy defaults to Fail instead of Ignore",
+		"matchPolicy defaults to Equivalent instead of Exact",
+		"timeoutSeconds defaults to 10 instead of 30",
+	}
+	podSecurityPolicyNotes = []string{
+		"PodSecurityPolicy has no replacement. Use the Pod Security admission labels on the namespaces, or a policy engine like Gatekeeper or Kyverno",
+	}
+
+	// apiDeprecations are the API versions removed from Kubernetes, from https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+	apiDeprecations = []APIDeprecation{
+		{GroupVersion: "extensions/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: deploymentNotes},
+		{GroupVersion: "apps/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: deploymentNotes},
+		{GroupVersion: "apps/v1beta2", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{workloadSelectorNote}},
+		{GroupVersion: "extensions/v1beta1", Kind: "DaemonSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{
+			workloadSelectorNote,
+			"spec.updateStrategy defaults to RollingUpdate instead of OnDelete",
+			"spec.templateGeneration was removed",
+		}},
+		{GroupVersion: "apps/v1beta2", Kind: "DaemonSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{workloadSelectorNote}},
+		{GroupVersion: "extensions/v1beta1", Kind: "ReplicaSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{workloadSelectorNote}},
+		{GroupVersion: "apps/v1beta2", Kind: "ReplicaSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{workloadSelectorNote}},
+		{GroupVersion: "apps/v1beta1", Kind: "StatefulSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{
+			workloadSelectorNote,
+			"spec.updateStrategy defaults to RollingUpdate instead of OnDelete",
+		}},
+		{GroupVersion: "apps/v1beta2", Kind: "StatefulSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", Notes: []string{workloadSelectorNote}},
+		{GroupVersion: "extensions/v1beta1", Kind: "NetworkPolicy", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "networking.k8s.io/v1"},
+		{GroupVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.11", RemovedIn: "1.16", Replacement: "policy/v1beta1"},
+
+		{GroupVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+		{GroupVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+		{GroupVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+		{GroupVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+		{GroupVersion: "extensions/v1beta1", Kind: "Ingress", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", Notes: ingressNotes},
+		{GroupVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", Notes: ingressNotes},
+		{GroupVersion: "networking.k8s.io/v1beta1", Kind: "IngressClass", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1"},
+		{GroupVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "apiextensions.k8s.io/v1", Notes: []string{
+			"spec.versions[*].schema replaces spec.validation, and a structural schema is required for each version",
+			"spec.preserveUnknownFields defaults to false, so the fields which are not in the schema are pruned",
+			"spec.version and spec.additionalPrinterColumns were moved into spec.versions",
+		}},
+		{GroupVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1", Notes: append(webhookNotes, "reinvocationPolicy defaults to Never")},
+		{GroupVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1", Notes: webhookNotes},
+		{GroupVersion: "apiregistration.k8s.io/v1beta1", Kind: "APIService", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "apiregistration.k8s.io/v1"},
+		{GroupVersion: "certificates.k8s.io/v1beta1", Kind: "CertificateSigningRequest", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "certificates.k8s.io/v1", Notes: []string{
+			"spec.signerName and spec.usages are required",
+		}},
+		{GroupVersion: "coordination.k8s.io/v1beta1", Kind: "Lease", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "coordination.k8s.io/v1"},
+		{GroupVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "scheduling.k8s.io/v1"},
+		{GroupVersion: "storage.k8s.io/v1beta1", Kind: "CSIDriver", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+		{GroupVersion: "storage.k8s.io/v1beta1", Kind: "CSINode", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+		{GroupVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+		{GroupVersion: "storage.k8s.io/v1beta1", Kind: "VolumeAttachment", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+
+		{GroupVersion: "batch/v1beta1", Kind: "CronJob", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "batch/v1", SameSchemaAs: "batch/v1beta1"},
+		{GroupVersion: "discovery.k8s.io/v1beta1", Kind: "EndpointSlice", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "discovery.k8s.io/v1", Notes: []string{
+			"the topology of the endpoints was replaced by nodeName and zone",
+		}},
+		{GroupVersion: "events.k8s.io/v1beta1", Kind: "Event", DeprecatedIn: "1.19", RemovedIn: "1.25", Replacement: "events.k8s.io/v1"},
+		{GroupVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.22", RemovedIn: "1.25", Replacement: "autoscaling/v2", SameSchemaAs: "autoscaling/v2beta2", Notes: []string{
+			"the targetAverageUtilization and targetAverageValue of the metrics were moved into target",
+		}},
+		{GroupVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "policy/v1", SameSchemaAs: "policy/v1beta1", Notes: []string{
+			"an empty spec.selector selects all the pods of the namespace instead of none",
+		}},
+		{GroupVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.21", RemovedIn: "1.25", Notes: podSecurityPolicyNotes},
+		{GroupVersion: "node.k8s.io/v1beta1", Kind: "RuntimeClass", DeprecatedIn: "1.20", RemovedIn: "1.25", Replacement: "node.k8s.io/v1"},
+
+		{GroupVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "autoscaling/v2", SameSchemaAs: "autoscaling/v2beta2"},
+		{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "FlowSchema", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1beta3"},
+		{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1beta3", Notes: []string{
+			"assuredConcurrencyShares was renamed to nominalConcurrencyShares in v1beta3",
+		}},
+		{GroupVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", DeprecatedIn: "1.24", RemovedIn: "1.27", Replacement: "storage.k8s.io/v1"},
+		{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "FlowSchema", DeprecatedIn: "1.26", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+		{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.26", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1", Notes: []string{
+			"assuredConcurrencyShares was renamed to nominalConcurrencyShares",
+		}},
+		{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "FlowSchema", DeprecatedIn: "1.29", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+		{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.29", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	}
+)
+
+// GetAPIDeprecation returns the deprecation of the api version of the kind, and false if the api version is not deprecated
+func GetAPIDeprecation(apiVersion, kind string) (APIDeprecation, bool) {
+	for _, deprecation := range apiDeprecations {
+		if deprecation.GroupVersion == apiVersion && deprecation.Kind == kind {
+			return deprecation, true
+		}
+	}
+	return APIDeprecation{}, false
+}
+
+// IsDeprecatedIn returns true if the api version is deprecated in the version of Kubernetes
+func (d APIDeprecation) IsDeprecatedIn(version *semver.Version) bool {
+	return !version.LessThan(semver.MustParse(d.DeprecatedIn))
+}
+
+// IsRemovedIn returns true if the api version is no longer served by the version of Kubernetes
+func (d APIDeprecation) IsRemovedIn(version *semver.Version) bool {
+	return !version.LessThan(semver.MustParse(d.RemovedIn))
+}
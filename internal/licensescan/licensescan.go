@@ -0,0 +1,457 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package licensescan
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// GoEcosystem is the ecosystem of the dependencies in go.mod
+	GoEcosystem = "go"
+	// NpmEcosystem is the ecosystem of the dependencies in package.json
+	NpmEcosystem = "npm"
+	// PythonEcosystem is the ecosystem of the dependencies in requirements.txt
+	PythonEcosystem = "python"
+	// MavenEcosystem is the ecosystem of the dependencies in pom.xml
+	MavenEcosystem = "maven"
+	// UnknownLicense is used when the license of a dependency is not found in the source directory or the local caches
+	UnknownLicense = "unknown"
+)
+
+var (
+	licenseFilePrefixes = []string{"LICENSE", "LICENCE", "COPYING"}
+	// copyleftLicensePrefixes are the SPDX ids of the licenses which have obligations when the software is distributed, like in an image
+	copyleftLicensePrefixes = []string{"GPL", "AGPL", "LGPL", "MPL", "EPL", "CDDL", "EUPL", "OSL", "CPAL"}
+	requirementNameRegex    = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	requirementVersionRegex = regexp.MustCompile(`==\s*([^\s;,#]+)`)
+)
+
+// Dependency is a dependency of a service along with its license
+type Dependency struct {
+	Name      string
+	Version   string
+	Ecosystem string
+	// License is the SPDX id of the license, or UnknownLicense
+	License string
+}
+
+// Inventory is the license and the provenance of the source of a service, and the licenses of its dependencies
+type Inventory struct {
+	// License is the SPDX id of the license of the source, or UnknownLicense
+	License string
+	// Repo and Commit are the remote and the commit of the git repo containing the source, if there is one
+	Repo         string
+	Commit       string
+	Dependencies []Dependency
+}
+
+// GetLicenseCounts returns the number of dependencies using each license
+func (inventory Inventory) GetLicenseCounts() map[string]int {
+	counts := map[string]int{}
+	for _, dependency := range inventory.Dependencies {
+		counts[dependency.License]++
+	}
+	return counts
+}
+
+// GetCopyleftDependencies returns the dependencies whose licenses have obligations when they are distributed
+func (inventory Inventory) GetCopyleftDependencies() []Dependency {
+	dependencies := []Dependency{}
+	for _, dependency := range inventory.Dependencies {
+		if IsCopyleft(dependency.License) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
+
+// IsCopyleft returns true if the license has obligations when the software is distributed
+func IsCopyleft(license string) bool {
+	for _, prefix := range copyleftLicensePrefixes {
+		if strings.HasPrefix(strings.ToUpper(license), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanService inventories the licenses of the dependencies in the go.mod, package.json, requirements.txt and pom.xml
+// files of the source directories of a service. The licenses are looked up in the vendored dependencies and, unless
+// the environment is ignored, in the local caches of the package managers. Nothing is downloaded.
+func ScanService(sourceDirs []string) Inventory {
+	inventory := Inventory{License: UnknownLicense, Dependencies: []Dependency{}}
+	for _, dir := range sourceDirs {
+		if inventory.License == UnknownLicense {
+			inventory.License = getLicenseInDir(dir)
+		}
+		if inventory.Repo == "" {
+			inventory.Repo, inventory.Commit = getProvenance(dir)
+		}
+		inventory.Dependencies = append(inventory.Dependencies, getGoDependencies(dir)...)
+		inventory.Dependencies = append(inventory.Dependencies, getNpmDependencies(dir)...)
+		inventory.Dependencies = append(inventory.Dependencies, getPythonDependencies(dir)...)
+		inventory.Dependencies = append(inventory.Dependencies, getMavenDependencies(dir)...)
+	}
+	sort.SliceStable(inventory.Dependencies, func(i, j int) bool {
+		if inventory.Dependencies[i].Ecosystem != inventory.Dependencies[j].Ecosystem {
+			return inventory.Dependencies[i].Ecosystem < inventory.Dependencies[j].Ecosystem
+		}
+		return inventory.Dependencies[i].Name < inventory.Dependencies[j].Name
+	})
+	return inventory
+}
+
+// getProvenance returns the origin remote url and the commit of the git repo containing the directory
+func getProvenance(dir string) (string, string) {
+	remoteURLs, _, _, err := common.GetGitRepoDetails(dir, "origin")
+	if err != nil {
+		return "", ""
+	}
+	repoURL := ""
+	if len(remoteURLs) > 0 {
+		repoURL = remoteURLs[0]
+	}
+	commit, err := common.GetGitCommit(dir)
+	if err != nil {
+		log.Debugf("Unable to get the commit of the git repo containing the directory %s . Error: %q", dir, err)
+	}
+	return repoURL, commit
+}
+
+// IdentifyLicense returns the SPDX id of the license with the text or the name, or UnknownLicense
+func IdentifyLicense(text string) string {
+	upper := strings.ToUpper(text)
+	switch {
+	case strings.Contains(upper, "AFFERO GENERAL PUBLIC LICENSE") || strings.Contains(upper, "AGPL"):
+		return "AGPL-3.0"
+	case strings.Contains(upper, "LESSER GENERAL PUBLIC LICENSE") || strings.Contains(upper, "LIBRARY GENERAL PUBLIC LICENSE") || strings.Contains(upper, "LGPL"):
+		if strings.Contains(upper, "VERSION 3") || strings.Contains(upper, "LGPLV3") || strings.Contains(upper, "LGPL-3") {
+			return "LGPL-3.0"
+		}
+		return "LGPL-2.1"
+	case strings.Contains(upper, "GNU GENERAL PUBLIC LICENSE") || strings.Contains(upper, "GPL"):
+		if !strings.Contains(upper, "VERSION 3") && (strings.Contains(upper, "VERSION 2") || strings.Contains(upper, "GPLV2") || strings.Contains(upper, "GPL-2")) {
+			return "GPL-2.0"
+		}
+		return "GPL-3.0"
+	case strings.Contains(upper, "MOZILLA PUBLIC LICENSE"):
+		return "MPL-2.0"
+	case strings.Contains(upper, "ECLIPSE PUBLIC LICENSE"):
+		if strings.Contains(upper, "1.0") || strings.Contains(upper, "VERSION 1") {
+			return "EPL-1.0"
+		}
+		return "EPL-2.0"
+	case strings.Contains(upper, "COMMON DEVELOPMENT AND DISTRIBUTION LICENSE"):
+		return "CDDL-1.0"
+	case strings.Contains(upper, "APACHE LICENSE") || strings.Contains(upper, "APACHE SOFTWARE LICENSE") || strings.Contains(upper, "APACHE-2.0") || strings.Contains(upper, "APACHE 2"):
+		return "Apache-2.0"
+	case strings.Contains(upper, "PERMISSION IS HEREBY GRANTED, FREE OF CHARGE") || strings.Contains(upper, "MIT LICENSE") || upper == "MIT":
+		return "MIT"
+	case strings.Contains(upper, "REDISTRIBUTION AND USE IN SOURCE AND BINARY FORMS"):
+		if strings.Contains(upper, "NEITHER THE NAME") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case strings.Contains(upper, "BSD"):
+		if strings.Contains(upper, "2") {
+			return "BSD-2-Clause"
+		}
+		return "BSD-3-Clause"
+	case strings.Contains(upper, "PERMISSION TO USE, COPY, MODIFY, AND/OR DISTRIBUTE THIS SOFTWARE FOR ANY PURPOSE") || upper == "ISC" || strings.Contains(upper, "ISC LICENSE"):
+		return "ISC"
+	case strings.Contains(upper, "FREE AND UNENCUMBERED SOFTWARE RELEASED INTO THE PUBLIC DOMAIN"):
+		return "Unlicense"
+	}
+	return UnknownLicense
+}
+
+// getLicenseInDir identifies the license file in the directory
+func getLicenseInDir(dir string) string {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return UnknownLicense
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		for _, prefix := range licenseFilePrefixes {
+			if !strings.HasPrefix(strings.ToUpper(file.Name()), prefix) {
+				continue
+			}
+			content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				log.Debugf("Failed to read the license file %s . Error: %q", filepath.Join(dir, file.Name()), err)
+				continue
+			}
+			if license := IdentifyLicense(string(content)); license != UnknownLicense {
+				return license
+			}
+		}
+	}
+	return UnknownLicense
+}
+
+// getGoDependencies returns the modules required in the go.mod file of the directory
+func getGoDependencies(dir string) []Dependency {
+	content, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+	dependencies := []Dependency{}
+	inRequireBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(strings.SplitN(line, "//", 2)[0])
+		fields := strings.Fields(line)
+		switch {
+		case line == "require (":
+			inRequireBlock = true
+			continue
+		case inRequireBlock && line == ")":
+			inRequireBlock = false
+			continue
+		case !inRequireBlock && len(fields) == 3 && fields[0] == "require":
+			fields = fields[1:]
+		case !inRequireBlock || len(fields) != 2:
+			continue
+		}
+		dependency := Dependency{Name: fields[0], Version: fields[1], Ecosystem: GoEcosystem}
+		dependency.License = getLicenseInDir(filepath.Join(dir, "vendor", filepath.FromSlash(dependency.Name)))
+		if dependency.License == UnknownLicense && !common.IgnoreEnvironment {
+			if modCache := getGoModCache(); modCache != "" {
+				dependency.License = getLicenseInDir(filepath.Join(modCache, filepath.FromSlash(escapeGoModulePath(dependency.Name)+"@"+dependency.Version)))
+			}
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+func getGoModCache() string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache
+	}
+	if goPath := os.Getenv("GOPATH"); goPath != "" {
+		return filepath.Join(filepath.SplitList(goPath)[0], "pkg", "mod")
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(homeDir, "go", "pkg", "mod")
+	}
+	return ""
+}
+
+// escapeGoModulePath escapes the upper case letters of the module path, like the module cache does
+func escapeGoModulePath(modulePath string) string {
+	escaped := strings.Builder{}
+	for _, c := range modulePath {
+		if c >= 'A' && c <= 'Z' {
+			escaped.WriteRune('!')
+			escaped.WriteRune(c + 'a' - 'A')
+			continue
+		}
+		escaped.WriteRune(c)
+	}
+	return escaped.String()
+}
+
+type packageJSON struct {
+	Dependencies map[string]string `json:"dependencies"`
+	License      interface{}       `json:"license"`
+}
+
+// getNpmDependencies returns the dependencies in the package.json file of the directory. The dev dependencies are not
+// included, since they are usually not in the image.
+func getNpmDependencies(dir string) []Dependency {
+	pkg := packageJSON{}
+	if err := readPackageJSON(filepath.Join(dir, "package.json"), &pkg); err != nil {
+		return nil
+	}
+	dependencies := []Dependency{}
+	for name, version := range pkg.Dependencies {
+		dependency := Dependency{Name: name, Version: version, Ecosystem: NpmEcosystem, License: UnknownLicense}
+		moduleDir := filepath.Join(dir, "node_modules", filepath.FromSlash(name))
+		modulePkg := packageJSON{}
+		if err := readPackageJSON(filepath.Join(moduleDir, "package.json"), &modulePkg); err == nil {
+			dependency.License = getNpmLicense(modulePkg.License)
+		}
+		if dependency.License == UnknownLicense {
+			dependency.License = getLicenseInDir(moduleDir)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+func readPackageJSON(path string, pkg *packageJSON) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, pkg); err != nil {
+		log.Debugf("Failed to parse the file at path %s . Error: %q", path, err)
+		return err
+	}
+	return nil
+}
+
+// getNpmLicense returns the SPDX expression in the license field, which is either a string or an object with a type in older packages
+func getNpmLicense(license interface{}) string {
+	switch license := license.(type) {
+	case string:
+		if license != "" {
+			return license
+		}
+	case map[string]interface{}:
+		if licenseType, ok := license["type"].(string); ok && licenseType != "" {
+			return licenseType
+		}
+	}
+	return UnknownLicense
+}
+
+// getPythonDependencies returns the packages in the requirements.txt file of the directory. The licenses are read from the
+// metadata of the packages installed in a virtual environment in the directory.
+func getPythonDependencies(dir string) []Dependency {
+	content, err := ioutil.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if err != nil {
+		return nil
+	}
+	installedLicenses := getInstalledPythonLicenses(dir)
+	dependencies := []Dependency{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(strings.SplitN(line, "#", 2)[0])
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		match := requirementNameRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		dependency := Dependency{Name: match[1], Ecosystem: PythonEcosystem, License: UnknownLicense}
+		if version := requirementVersionRegex.FindStringSubmatch(line); version != nil {
+			dependency.Version = version[1]
+		}
+		if license, ok := installedLicenses[normalizePythonName(dependency.Name)]; ok {
+			dependency.License = license
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+func normalizePythonName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+}
+
+// getInstalledPythonLicenses returns the licenses of the packages installed in the virtual environments in the directory
+func getInstalledPythonLicenses(dir string) map[string]string {
+	licenses := map[string]string{}
+	metadataPaths, err := filepath.Glob(filepath.Join(dir, "*", "lib", "python*", "site-packages", "*.dist-info", "METADATA"))
+	if err != nil {
+		return licenses
+	}
+	for _, metadataPath := range metadataPaths {
+		content, err := ioutil.ReadFile(metadataPath)
+		if err != nil {
+			continue
+		}
+		name, license := "", UnknownLicense
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				// The headers end at the first empty line
+				break
+			}
+			switch {
+			case strings.HasPrefix(line, "Name:"):
+				name = normalizePythonName(strings.TrimSpace(strings.TrimPrefix(line, "Name:")))
+			case strings.HasPrefix(line, "License:") && license == UnknownLicense:
+				license = IdentifyLicense(strings.TrimSpace(strings.TrimPrefix(line, "License:")))
+			case strings.HasPrefix(line, "Classifier: License ::"):
+				if classified := IdentifyLicense(line[strings.LastIndex(line, "::")+2:]); classified != UnknownLicense {
+					license = classified
+				}
+			}
+		}
+		if name != "" {
+			licenses[name] = license
+		}
+	}
+	return licenses
+}
+
+type mavenProject struct {
+	Dependencies []struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Scope      string `xml:"scope"`
+	} `xml:"dependencies>dependency"`
+	Licenses []struct {
+		Name string `xml:"name"`
+	} `xml:"licenses>license"`
+}
+
+// getMavenDependencies returns the dependencies in the pom.xml file of the directory. The test and provided dependencies
+// are not included, since they are not in the image. The licenses are read from the poms in the local maven repository.
+func getMavenDependencies(dir string) []Dependency {
+	project := mavenProject{}
+	if err := readPom(filepath.Join(dir, "pom.xml"), &project); err != nil {
+		return nil
+	}
+	dependencies := []Dependency{}
+	for _, mavenDependency := range project.Dependencies {
+		if mavenDependency.Scope == "test" || mavenDependency.Scope == "provided" {
+			continue
+		}
+		dependency := Dependency{Name: mavenDependency.GroupID + ":" + mavenDependency.ArtifactID, Version: mavenDependency.Version, Ecosystem: MavenEcosystem, License: UnknownLicense}
+		if !common.IgnoreEnvironment && mavenDependency.Version != "" && !strings.Contains(mavenDependency.Version, "${") {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				pomPath := filepath.Join(homeDir, ".m2", "repository", filepath.Join(strings.Split(mavenDependency.GroupID, ".")...), mavenDependency.ArtifactID, mavenDependency.Version, mavenDependency.ArtifactID+"-"+mavenDependency.Version+".pom")
+				dependencyProject := mavenProject{}
+				if err := readPom(pomPath, &dependencyProject); err == nil && len(dependencyProject.Licenses) > 0 {
+					dependency.License = IdentifyLicense(dependencyProject.Licenses[0].Name)
+				}
+			}
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+func readPom(path string, project *mavenProject) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(content, project); err != nil {
+		log.Debugf("Failed to parse the pom at path %s . Error: %q", path, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package licensescan_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/licensescan"
+)
+
+func TestIdentifyLicense(t *testing.T) {
+	testcases := map[string]string{
+		"Permission is hereby granted, free of charge, to any person obtaining a copy": "MIT",
+		"Apache License\nVersion 2.0, January 2004":                                    "Apache-2.0",
+		"The Apache Software License, Version 2.0":                                     "Apache-2.0",
+		"GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991":                             "GPL-2.0",
+		"GNU Lesser General Public License":                                            "LGPL-2.1",
+		"Redistribution and use in source and binary forms ... Neither the name of":    "BSD-3-Clause",
+		"Eclipse Public License 2.0":                                                   "EPL-2.0",
+		"All rights reserved.":                                                         licensescan.UnknownLicense,
+	}
+	for text, want := range testcases {
+		if license := licensescan.IdentifyLicense(text); license != want {
+			t.Fatalf("Failed to identify the license %q. Expected: %s Actual: %s", text, want, license)
+		}
+	}
+}
+
+func TestScanService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "licensescan")
+	if err != nil {
+		t.Fatalf("Failed to create the temp directory. Error: %q", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"LICENSE":                              "Apache License\nVersion 2.0, January 2004\n",
+		"go.mod":                               "module example.com/app\n\ngo 1.16\n\nrequire github.com/pkg/errors v0.9.1\n\nrequire (\n\tgithub.com/sirupsen/logrus v1.8.1 // indirect\n)\n",
+		"vendor/github.com/pkg/errors/LICENSE": "Redistribution and use in source and binary forms, with or without modification",
+		"package.json":                         `{"name": "app", "license": "Apache-2.0", "dependencies": {"express": "^4.17.1", "left-pad": "1.3.0"}, "devDependencies": {"mocha": "^9.0.0"}}`,
+		"node_modules/express/package.json":    `{"name": "express", "license": "MIT"}`,
+		"node_modules/left-pad/package.json":   `{"name": "left-pad", "license": {"type": "WTFPL"}}`,
+		"requirements.txt":                     "# web\nFlask==2.0.1\nmysql-connector-python>=8.0 ; python_version > '3'\n-r other.txt\n",
+		".venv/lib/python3.9/site-packages/mysql_connector_python-8.0.26.dist-info/METADATA": "Metadata-Version: 2.1\nName: mysql-connector-python\nLicense: GNU GPLv2 (with FOSS License Exception)\n\nMySQL driver\n",
+		"pom.xml": `<project><dependencies>
+  <dependency><groupId>org.springframework</groupId><artifactId>spring-core</artifactId><version>5.3.9</version></dependency>
+  <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13</version><scope>test</scope></dependency>
+</dependencies></project>`,
+	}
+	for relPath, content := range files {
+		path := filepath.Join(dir, relPath)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatalf("Failed to create the directory of the file %s . Error: %q", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), os.ModePerm); err != nil {
+			t.Fatalf("Failed to write the file %s . Error: %q", path, err)
+		}
+	}
+	ignoreEnvironment := common.IgnoreEnvironment
+	common.IgnoreEnvironment = true
+	defer func() { common.IgnoreEnvironment = ignoreEnvironment }()
+
+	inventory := licensescan.ScanService([]string{dir})
+	if inventory.License != "Apache-2.0" {
+		t.Fatalf("Failed to identify the license of the source. Expected: Apache-2.0 Actual: %s", inventory.License)
+	}
+	want := []licensescan.Dependency{
+		{Name: "github.com/pkg/errors", Version: "v0.9.1", Ecosystem: licensescan.GoEcosystem, License: "BSD-2-Clause"},
+		{Name: "github.com/sirupsen/logrus", Version: "v1.8.1", Ecosystem: licensescan.GoEcosystem, License: licensescan.UnknownLicense},
+		{Name: "org.springframework:spring-core", Version: "5.3.9", Ecosystem: licensescan.MavenEcosystem, License: licensescan.UnknownLicense},
+		{Name: "express", Version: "^4.17.1", Ecosystem: licensescan.NpmEcosystem, License: "MIT"},
+		{Name: "left-pad", Version: "1.3.0", Ecosystem: licensescan.NpmEcosystem, License: "WTFPL"},
+		{Name: "Flask", Version: "2.0.1", Ecosystem: licensescan.PythonEcosystem, License: licensescan.UnknownLicense},
+		{Name: "mysql-connector-python", Ecosystem: licensescan.PythonEcosystem, License: "GPL-2.0"},
+	}
+	if !reflect.DeepEqual(inventory.Dependencies, want) {
+		t.Fatalf("Failed to inventory the dependencies. Expected: %+v Actual: %+v", want, inventory.Dependencies)
+	}
+	if copyleft := inventory.GetCopyleftDependencies(); len(copyleft) != 1 || copyleft[0].Name != "mysql-connector-python" {
+		t.Fatalf("Failed to find the copyleft dependencies. Actual: %+v", copyleft)
+	}
+}
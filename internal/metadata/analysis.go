@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// analyzerOutputFile is the file written by the Konveyor analyzer in its output directory
+	analyzerOutputFile = "output.yaml"
+)
+
+// analysisCategoryOrder is the order of the issues in the plan, from the ones blocking the migration to the others
+var analysisCategoryOrder = []plantypes.AnalysisCategoryValue{plantypes.MandatoryAnalysisCategory, plantypes.PotentialAnalysisCategory, plantypes.OptionalAnalysisCategory}
+
+// analyzerRuleSet is a rule set in the output of the Konveyor analyzer, which is written as yaml or json
+type analyzerRuleSet struct {
+	Name       string                       `yaml:"name"`
+	Violations map[string]analyzerViolation `yaml:"violations"`
+}
+
+// analyzerViolation is a rule violated in the analyzed source
+type analyzerViolation struct {
+	Description string             `yaml:"description"`
+	Category    string             `yaml:"category"`
+	Effort      int                `yaml:"effort"`
+	Incidents   []analyzerIncident `yaml:"incidents"`
+}
+
+// analyzerIncident is a place in the analyzed source violating a rule
+type analyzerIncident struct {
+	URI string `yaml:"uri"`
+}
+
+// AnalysisLoader adds the issues found by rule engines, like the Konveyor analyzer, to the services whose source they are in
+type AnalysisLoader struct {
+}
+
+// UpdatePlan adds the issues in the results set using the analysis flag to the services of the plan
+func (analysisLoader *AnalysisLoader) UpdatePlan(inputPath string, plan *plantypes.Plan) error {
+	if len(common.AnalysisPaths) == 0 {
+		return nil
+	}
+	analyses := map[string]plantypes.Analysis{}
+	for _, analysisPath := range common.AnalysisPaths {
+		ruleSets, err := readAnalyzerOutput(analysisPath)
+		if err != nil {
+			return err
+		}
+		addAnalyzerRuleSets(analyses, ruleSets, *plan)
+	}
+	for serviceName, services := range plan.Spec.Inputs.Services {
+		analysis := analyses[serviceName]
+		sortAnalysisIssues(analysis.Issues)
+		for i := range services {
+			services[i].Analysis = analysis
+		}
+		if len(analysis.Issues) > 0 {
+			log.Debugf("The analysis found %d issues with an effort of %d in the service %s", len(analysis.Issues), analysis.Effort, serviceName)
+		}
+	}
+	return nil
+}
+
+// LoadToIR does nothing, since the issues are added to the report from the plan
+func (analysisLoader *AnalysisLoader) LoadToIR(p plantypes.Plan, ir *irtypes.IR) error {
+	return nil
+}
+
+// readAnalyzerOutput reads the output of the Konveyor analyzer, or the output.yaml in its output directory
+func readAnalyzerOutput(analysisPath string) ([]analyzerRuleSet, error) {
+	if fi, err := os.Stat(analysisPath); err == nil && fi.IsDir() {
+		analysisPath = filepath.Join(analysisPath, analyzerOutputFile)
+	}
+	ruleSets := []analyzerRuleSet{}
+	if err := common.ReadYaml(analysisPath, &ruleSets); err != nil {
+		return nil, fmt.Errorf("failed to read the analysis results at path %s . Error: %q", analysisPath, err)
+	}
+	return ruleSets, nil
+}
+
+// addAnalyzerRuleSets adds the violations of the rule sets to the analyses of the services whose source has their incidents
+func addAnalyzerRuleSets(analyses map[string]plantypes.Analysis, ruleSets []analyzerRuleSet, plan plantypes.Plan) {
+	for _, ruleSet := range ruleSets {
+		ruleIDs := []string{}
+		for ruleID := range ruleSet.Violations {
+			ruleIDs = append(ruleIDs, ruleID)
+		}
+		sort.Strings(ruleIDs)
+		for _, ruleID := range ruleIDs {
+			violation := ruleSet.Violations[ruleID]
+			incidents := map[string]int{}
+			for _, incident := range violation.Incidents {
+				serviceName := getAnalyzedServiceName(incident.URI, plan)
+				if serviceName == "" {
+					log.Debugf("The incident of the rule %s at %s is not in the source of a service", ruleID, incident.URI)
+					continue
+				}
+				incidents[serviceName]++
+			}
+			for serviceName, count := range incidents {
+				analysis := analyses[serviceName]
+				analysis.Issues = append(analysis.Issues, plantypes.AnalysisIssue{
+					RuleSet:     ruleSet.Name,
+					RuleID:      ruleID,
+					Description: strings.TrimSpace(violation.Description),
+					Category:    getAnalysisCategory(violation.Category),
+					Effort:      violation.Effort,
+					Incidents:   count,
+				})
+				analysis.Effort += violation.Effort * count
+				analyses[serviceName] = analysis
+			}
+		}
+	}
+}
+
+// getAnalysisCategory returns the category of an issue, or no category if the rule engine uses another one
+func getAnalysisCategory(category string) plantypes.AnalysisCategoryValue {
+	for _, c := range analysisCategoryOrder {
+		if strings.EqualFold(category, string(c)) {
+			return c
+		}
+	}
+	return ""
+}
+
+// getAnalyzedServiceName returns the service whose source directory is the deepest one containing the file of an incident.
+// The source is often analyzed at another path, like /opt/input/source in the container of the analyzer, so the relative
+// paths of the source directories are looked for in the paths outside the root directory.
+func getAnalyzedServiceName(uri string, plan plantypes.Plan) string {
+	incidentPath := uri
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		incidentPath = u.Path
+	}
+	incidentPath = filepath.ToSlash(filepath.Clean(incidentPath))
+	relIncidentPath := ""
+	if relPath, err := filepath.Rel(plan.Spec.Inputs.RootDir, filepath.FromSlash(incidentPath)); err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(os.PathSeparator)) {
+		relIncidentPath = filepath.ToSlash(relPath)
+	}
+	serviceNames := []string{}
+	for serviceName := range plan.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	matchedServiceName := ""
+	matchedDir := ""
+	for _, serviceName := range serviceNames {
+		services := plan.Spec.Inputs.Services[serviceName]
+		if len(services) == 0 {
+			continue
+		}
+		for _, sourceDir := range services[0].SourceArtifacts[plantypes.SourceDirectoryArtifactType] {
+			relDir, err := plan.GetRelativePath(sourceDir)
+			if err != nil {
+				continue
+			}
+			relDir = filepath.ToSlash(relDir)
+			matched := relDir == "."
+			if relIncidentPath != "" {
+				matched = matched || strings.HasPrefix(relIncidentPath, relDir+"/")
+			} else {
+				matched = matched || strings.Contains(incidentPath, "/"+relDir+"/")
+			}
+			if matched && (matchedServiceName == "" || len(relDir) > len(matchedDir)) {
+				matchedServiceName = serviceName
+				matchedDir = relDir
+			}
+		}
+	}
+	return matchedServiceName
+}
+
+// sortAnalysisIssues sorts the issues by category, then by the effort of all their incidents
+func sortAnalysisIssues(issues []plantypes.AnalysisIssue) {
+	categoryIndex := func(category plantypes.AnalysisCategoryValue) int {
+		for i, c := range analysisCategoryOrder {
+			if c == category {
+				return i
+			}
+		}
+		return len(analysisCategoryOrder)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if ci, cj := categoryIndex(issues[i].Category), categoryIndex(issues[j].Category); ci != cj {
+			return ci < cj
+		}
+		return issues[i].Effort*issues[i].Incidents > issues[j].Effort*issues[j].Incidents
+	})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/metadata"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const testAnalyzerOutput = `- name: cloud-readiness
+  violations:
+    local-storage-00001:
+      description: "File system - Java IO"
+      category: optional
+      effort: 1
+      incidents:
+        - uri: file:///opt/input/source/tickets/src/main/java/com/acme/Export.java
+          lineNumber: 12
+        - uri: file:///opt/input/source/orders/src/main/java/com/acme/Archive.java
+          lineNumber: 40
+    jni-native-code-00000:
+      description: "Java native libraries (JNI, JNA)"
+      category: mandatory
+      effort: 7
+      incidents:
+        - uri: file:///opt/input/source/tickets/src/main/java/com/acme/Native.java
+          lineNumber: 3
+        - uri: file:///opt/input/source/tickets/src/main/java/com/acme/Native.java
+          lineNumber: 9
+        - uri: file:///opt/input/source/shared/Util.java
+          lineNumber: 1
+- name: eap7
+  violations:
+    hibernate-00010:
+      description: "Hibernate 5 API changes"
+      category: potential
+      effort: 3
+      incidents:
+        - uri: file:///opt/input/source/orders/pom.xml
+          lineNumber: 20
+`
+
+func TestAnalysisLoader(t *testing.T) {
+	dir := t.TempDir()
+	analysisPath := filepath.Join(dir, "output.yaml")
+	if err := ioutil.WriteFile(analysisPath, []byte(testAnalyzerOutput), 0644); err != nil {
+		t.Fatalf("Failed to write the analysis results. Error: %q", err)
+	}
+	defer func(analysisPaths []string) { common.AnalysisPaths = analysisPaths }(common.AnalysisPaths)
+	common.AnalysisPaths = []string{dir}
+
+	rootDir := filepath.Join(dir, "source")
+	plan := plantypes.NewPlan()
+	plan.Spec.Inputs.RootDir = rootDir
+	for _, serviceName := range []string{"orders", "tickets"} {
+		service := plantypes.NewService(serviceName, plantypes.Any2KubeTranslation)
+		service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] = []string{filepath.Join(rootDir, serviceName)}
+		plan.AddServicesToPlan([]plantypes.Service{service})
+	}
+	loader := metadata.AnalysisLoader{}
+	if err := loader.UpdatePlan(rootDir, &plan); err != nil {
+		t.Fatalf("Failed to import the analysis results. Error: %q", err)
+	}
+
+	want := map[string]plantypes.Analysis{
+		"tickets": {Effort: 15, Issues: []plantypes.AnalysisIssue{
+			{RuleSet: "cloud-readiness", RuleID: "jni-native-code-00000", Description: "Java native libraries (JNI, JNA)", Category: plantypes.MandatoryAnalysisCategory, Effort: 7, Incidents: 2},
+			{RuleSet: "cloud-readiness", RuleID: "local-storage-00001", Description: "File system - Java IO", Category: plantypes.OptionalAnalysisCategory, Effort: 1, Incidents: 1},
+		}},
+		"orders": {Effort: 4, Issues: []plantypes.AnalysisIssue{
+			{RuleSet: "eap7", RuleID: "hibernate-00010", Description: "Hibernate 5 API changes", Category: plantypes.PotentialAnalysisCategory, Effort: 3, Incidents: 1},
+			{RuleSet: "cloud-readiness", RuleID: "local-storage-00001", Description: "File system - Java IO", Category: plantypes.OptionalAnalysisCategory, Effort: 1, Incidents: 1},
+		}},
+	}
+	for serviceName, wantAnalysis := range want {
+		if analysis := plan.Spec.Inputs.Services[serviceName][0].Analysis; !reflect.DeepEqual(analysis, wantAnalysis) {
+			t.Fatalf("Failed to add the issues to the service %s . Expected: %+v Actual: %+v", serviceName, wantAnalysis, analysis)
+		}
+	}
+
+	common.AnalysisPaths = []string{filepath.Join(dir, "missing.yaml")}
+	if err := loader.UpdatePlan(rootDir, &plan); err == nil {
+		t.Fatalf("Expected an error for missing analysis results")
+	}
+}
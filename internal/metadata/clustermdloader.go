@@ -26,6 +26,7 @@ import (
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 //go:generate go run  ../../scripts/generator/generator.go clusters makemaps
@@ -36,7 +37,7 @@ type ClusterMDLoader struct {
 
 // UpdatePlan - output a plan based on the input directory contents
 func (clusterMDLoader *ClusterMDLoader) UpdatePlan(inputPath string, plan *plantypes.Plan) error {
-	filePaths, err := common.GetFilesByExt(inputPath, []string{".yml", ".yaml"})
+	filePaths, err := common.GetFilesByExt(inputPath, common.Move2KubeFileExts)
 	if err != nil {
 		log.Warnf("Failed to fetch the cluster metadata yamls at path %q Error: %q", inputPath, err)
 		return err
@@ -55,10 +56,29 @@ func (clusterMDLoader *ClusterMDLoader) UpdatePlan(inputPath string, plan *plant
 
 		//If there is a cluster-metadata available from collect, then set below flag to true
 		plan.Spec.Outputs.Kubernetes.IgnoreUnsupportedKinds = true
+		setResourcesFromUsage(plan, cm.Spec.WorkloadUsage)
 	}
 	return nil
 }
 
+// setResourcesFromUsage sets the requests of the services without requests to the usage of the workloads with the same names
+// collected from the cluster, and their memory limits to twice the memory used. The cpu is not limited, to avoid throttling.
+func setResourcesFromUsage(plan *plantypes.Plan, usages []collecttypes.WorkloadUsage) {
+	for _, usage := range usages {
+		services := plan.Spec.Inputs.Services[common.NormalizeForServiceName(usage.Name)]
+		for i := range services {
+			if !services[i].Resources.Requests.IsEmpty() {
+				continue
+			}
+			services[i].Resources.Requests = plantypes.ResourceQuantities{CPU: usage.CPU, Memory: usage.Memory}
+			if memory, err := resource.ParseQuantity(usage.Memory); err == nil && services[i].Resources.Limits.Memory == "" {
+				services[i].Resources.Limits.Memory = resource.NewQuantity(2*memory.Value(), resource.BinarySI).String()
+			}
+			log.Debugf("Set the requests of the service %s to the usage of the workload %s in the namespace %s", services[i].ServiceName, usage.Name, usage.Namespace)
+		}
+	}
+}
+
 // LoadToIR loads target cluster in IR
 func (clusterMDLoader *ClusterMDLoader) LoadToIR(plan plantypes.Plan, ir *irtypes.IR) error {
 	clusters := clusterMDLoader.GetClusters(plan)
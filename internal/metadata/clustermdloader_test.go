@@ -17,6 +17,7 @@ limitations under the License.
 package metadata_test
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -150,6 +151,48 @@ func TestUpdatePlan(t *testing.T) {
 			t.Fatalf("The updated plan is incorrect. Difference:\n%s", cmp.Diff(want, p))
 		}
 	})
+
+	t.Run("update plan with the usage of the workloads", func(t *testing.T) {
+		// Setup
+		inputPath := t.TempDir()
+		data := `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ClusterMetadata
+metadata:
+  name: prod
+spec:
+  workloadUsage:
+    - name: web
+      namespace: shop
+      cpu: 131m
+      memory: 293Mi
+    - name: db
+      namespace: shop
+      cpu: "1"
+      memory: 1Gi
+`
+		if err := os.WriteFile(filepath.Join(inputPath, "prod.yaml"), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write the cluster metadata. Error: %q", err)
+		}
+		p := plantypes.NewPlan()
+		web := plantypes.NewService("web", plantypes.Compose2KubeTranslation)
+		db := plantypes.NewService("db", plantypes.Compose2KubeTranslation)
+		db.Resources.Requests.Memory = "2Gi"
+		p.AddServicesToPlan([]plantypes.Service{web, db})
+		loader := metadata.ClusterMDLoader{}
+
+		// Test
+		if err := loader.UpdatePlan(inputPath, &p); err != nil {
+			t.Fatal("Failed to update the plan. Error:", err)
+		}
+		want := plantypes.ResourceSpec{Requests: plantypes.ResourceQuantities{CPU: "131m", Memory: "293Mi"}, Limits: plantypes.ResourceQuantities{Memory: "586Mi"}}
+		if actual := p.Spec.Inputs.Services["web"][0].Resources; !cmp.Equal(actual, want) {
+			t.Fatalf("Failed to set the resources from the usage. Difference:\n%s", cmp.Diff(want, actual))
+		}
+		want = plantypes.ResourceSpec{Requests: plantypes.ResourceQuantities{Memory: "2Gi"}}
+		if actual := p.Spec.Inputs.Services["db"][0].Resources; !cmp.Equal(actual, want) {
+			t.Fatalf("Expected the requests in the plan to be kept. Difference:\n%s", cmp.Diff(want, actual))
+		}
+	})
 }
 
 func TestLoadToIR(t *testing.T) {
@@ -20,6 +20,174 @@ package clusters
 
 var Constants= map[string]string{
 
+	`aws-eks-1_21_yaml` : `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ClusterMetadata
+metadata:
+  name: AWS-EKS-1.21
+spec:
+  storageClasses:
+    - gp2
+  flavor: EKS
+  kubernetesVersion: 1.21.0
+  apiKindVersionMap:
+    APIService:
+      - apiregistration.k8s.io/v1
+      - apiregistration.k8s.io/v1beta1
+    Binding:
+      - v1
+    CSIDriver:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    CSINode:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    CertificateSigningRequest:
+      - certificates.k8s.io/v1
+      - certificates.k8s.io/v1beta1
+    ClusterRole:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    ClusterRoleBinding:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    ComponentStatus:
+      - v1
+    ConfigMap:
+      - v1
+    ControllerRevision:
+      - apps/v1
+    CronJob:
+      - batch/v1
+      - batch/v1beta1
+    CustomResourceDefinition:
+      - apiextensions.k8s.io/v1
+      - apiextensions.k8s.io/v1beta1
+    DaemonSet:
+      - apps/v1
+    Deployment:
+      - apps/v1
+    ENIConfig:
+      - crd.k8s.amazonaws.com/v1alpha1
+    EndpointSlice:
+      - discovery.k8s.io/v1
+      - discovery.k8s.io/v1beta1
+    Endpoints:
+      - v1
+    Event:
+      - events.k8s.io/v1beta1
+      - v1
+    Eviction:
+      - v1
+    HorizontalPodAutoscaler:
+      - autoscaling/v1
+      - autoscaling/v2beta1
+      - autoscaling/v2beta2
+    Ingress:
+      - networking.k8s.io/v1
+      - networking.k8s.io/v1beta1
+      - extensions/v1beta1
+    IngressClass:
+      - networking.k8s.io/v1
+    Job:
+      - batch/v1
+    Lease:
+      - coordination.k8s.io/v1
+      - coordination.k8s.io/v1beta1
+    LimitRange:
+      - v1
+    LocalSubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    MutatingWebhookConfiguration:
+      - admissionregistration.k8s.io/v1
+      - admissionregistration.k8s.io/v1beta1
+    Namespace:
+      - v1
+    NetworkPolicy:
+      - networking.k8s.io/v1
+    Node:
+      - v1
+    NodeProxyOptions:
+      - v1
+    PersistentVolume:
+      - v1
+    PersistentVolumeClaim:
+      - v1
+    Pod:
+      - v1
+    PodAttachOptions:
+      - v1
+    PodDisruptionBudget:
+      - policy/v1
+      - policy/v1beta1
+    PodExecOptions:
+      - v1
+    PodPortForwardOptions:
+      - v1
+    PodProxyOptions:
+      - v1
+    PodSecurityPolicy:
+      - policy/v1beta1
+    PodTemplate:
+      - v1
+    PriorityClass:
+      - scheduling.k8s.io/v1
+      - scheduling.k8s.io/v1beta1
+    ReplicaSet:
+      - apps/v1
+    ReplicationController:
+      - v1
+    ResourceQuota:
+      - v1
+    Role:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    RoleBinding:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    RuntimeClass:
+      - node.k8s.io/v1
+      - node.k8s.io/v1beta1
+    Scale:
+      - apps/v1
+      - v1
+    Secret:
+      - v1
+    SecurityGroupPolicy:
+      - vpcresources.k8s.aws/v1beta1
+    SelfSubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    SelfSubjectRulesReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    Service:
+      - v1
+    ServiceAccount:
+      - v1
+    ServiceProxyOptions:
+      - v1
+    StatefulSet:
+      - apps/v1
+    StorageClass:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    SubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    TokenRequest:
+      - v1
+    TokenReview:
+      - authentication.k8s.io/v1
+      - authentication.k8s.io/v1beta1
+    ValidatingWebhookConfiguration:
+      - admissionregistration.k8s.io/v1
+      - admissionregistration.k8s.io/v1beta1
+    VolumeAttachment:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+`,
+
 	`aws-eks_yaml` : `apiVersion: move2kube.konveyor.io/v1alpha1
 kind: ClusterMetadata
 metadata:
@@ -27,6 +195,7 @@ metadata:
 spec:
   storageClasses:
     - gp2
+  flavor: EKS
   apiKindVersionMap:
     APIService:
       - apiregistration.k8s.io/v1
@@ -187,6 +356,7 @@ spec:
     - azurefile-premium
     - default
     - managed-premium
+  flavor: AKS
   apiKindVersionMap:
     APIService:
       - apiregistration.k8s.io/v1
@@ -339,6 +509,168 @@ spec:
       - storage.k8s.io/v1beta1
 `,
 
+	`gcp-gke-autopilot_yaml` : `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ClusterMetadata
+metadata:
+  name: GCP-GKE-Autopilot
+spec:
+  storageClasses:
+    - standard-rwo
+  flavor: GKE
+  kubernetesVersion: 1.21.0
+  constraints:
+    disallowPrivileged: true
+    disallowHostNetwork: true
+    disallowHostPath: true
+    requireResourceRequests: true
+    allowedCapabilities:
+      - AUDIT_WRITE
+      - CHOWN
+      - DAC_OVERRIDE
+      - FOWNER
+      - FSETID
+      - KILL
+      - MKNOD
+      - NET_BIND_SERVICE
+      - NET_RAW
+      - SETFCAP
+      - SETGID
+      - SETPCAP
+      - SETUID
+      - SYS_CHROOT
+      - SYS_PTRACE
+  apiKindVersionMap:
+    APIService:
+      - apiregistration.k8s.io/v1
+    BackendConfig:
+      - cloud.google.com/v1
+    Binding:
+      - v1
+    CSIDriver:
+      - storage.k8s.io/v1beta1
+    CSINode:
+      - storage.k8s.io/v1beta1
+    CertificateSigningRequest:
+      - certificates.k8s.io/v1beta1
+    ClusterRole:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    ClusterRoleBinding:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    ComponentStatus:
+      - v1
+    ConfigMap:
+      - v1
+    ControllerRevision:
+      - apps/v1
+    CronJob:
+      - batch/v1beta1
+    CustomResourceDefinition:
+      - apiextensions.k8s.io/v1
+    DaemonSet:
+      - apps/v1
+    Deployment:
+      - apps/v1
+    Endpoints:
+      - v1
+    Event:
+      - v1
+    HorizontalPodAutoscaler:
+      - autoscaling/v1
+      - autoscaling/v2beta1
+      - autoscaling/v2beta2
+    Ingress:
+      - networking.k8s.io/v1
+      - networking.k8s.io/v1beta1
+      - extensions/v1beta1
+    Job:
+      - batch/v1
+    Lease:
+      - coordination.k8s.io/v1beta1
+      - coordination.k8s.io/v1
+    LimitRange:
+      - v1
+    LocalSubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    ManagedCertificate:
+      - networking.gke.io/v1beta2
+    MutatingWebhookConfiguration:
+      - admissionregistration.k8s.io/v1beta1
+      - admissionregistration.k8s.io/v1
+    Namespace:
+      - v1
+    NetworkPolicy:
+      - networking.k8s.io/v1
+    Node:
+      - v1
+    PersistentVolume:
+      - v1
+    PersistentVolumeClaim:
+      - v1
+    Pod:
+      - v1
+    PodDisruptionBudget:
+      - policy/v1beta1
+    PodTemplate:
+      - v1
+    PriorityClass:
+      - scheduling.k8s.io/v1beta1
+      - scheduling.k8s.io/v1
+    ReplicaSet:
+      - apps/v1
+    ReplicationController:
+      - v1
+    ResourceQuota:
+      - v1
+    Role:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    RoleBinding:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    RuntimeClass:
+      - node.k8s.io/v1beta1
+    ScalingPolicy:
+      - scalingpolicy.kope.io/v1alpha1
+    Secret:
+      - v1
+    SelfSubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    SelfSubjectRulesReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    Service:
+      - v1
+    ServiceAccount:
+      - v1
+    StatefulSet:
+      - apps/v1
+    StorageClass:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    StorageState:
+      - migration.k8s.io/v1alpha1
+    StorageVersionMigration:
+      - migration.k8s.io/v1alpha1
+    SubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    TokenReview:
+      - authentication.k8s.io/v1
+      - authentication.k8s.io/v1beta1
+    UpdateInfo:
+      - nodemanagement.gke.io/v1alpha1
+    ValidatingWebhookConfiguration:
+      - admissionregistration.k8s.io/v1beta1
+      - admissionregistration.k8s.io/v1
+    VolumeAttachment:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+`,
+
 	`gcp-gke_yaml` : `apiVersion: move2kube.konveyor.io/v1alpha1
 kind: ClusterMetadata
 metadata:
@@ -346,6 +678,7 @@ metadata:
 spec:
   storageClasses:
     - standard
+  flavor: GKE
   apiKindVersionMap:
     APIService:
       - apiregistration.k8s.io/v1
@@ -702,6 +1035,7 @@ spec:
     - ibmc-file-retain-silver
     - ibmc-file-silver
     - ibmc-file-silver-gid
+  flavor: OpenShift
   apiKindVersionMap:
     APIService:
       - apiregistration.k8s.io/v1
@@ -1113,6 +1447,7 @@ metadata:
 spec:
   storageClasses:
     - default
+  flavor: OpenShift
   apiKindVersionMap:
     APIService:
       - apiregistration.k8s.io/v1
@@ -1380,4 +1715,154 @@ spec:
       - storage.k8s.io/v1beta1
 `,
 
+	`rancher-k3s_yaml` : `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: ClusterMetadata
+metadata:
+  name: Rancher-K3s
+spec:
+  storageClasses:
+    - local-path
+  flavor: K3s
+  kubernetesVersion: 1.21.0
+  apiKindVersionMap:
+    APIService:
+      - apiregistration.k8s.io/v1
+    Binding:
+      - v1
+    CSIDriver:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    CSINode:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    CertificateSigningRequest:
+      - certificates.k8s.io/v1
+      - certificates.k8s.io/v1beta1
+    ClusterRole:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    ClusterRoleBinding:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    ComponentStatus:
+      - v1
+    ConfigMap:
+      - v1
+    ControllerRevision:
+      - apps/v1
+    CronJob:
+      - batch/v1
+      - batch/v1beta1
+      - batch/v2alpha1
+    CustomResourceDefinition:
+      - apiextensions.k8s.io/v1
+    DaemonSet:
+      - apps/v1
+    Deployment:
+      - apps/v1
+    EndpointSlice:
+      - discovery.k8s.io/v1
+      - discovery.k8s.io/v1beta1
+    Endpoints:
+      - v1
+    Event:
+      - events.k8s.io/v1beta1
+      - v1
+    HelmChart:
+      - helm.cattle.io/v1
+    HelmChartConfig:
+      - helm.cattle.io/v1
+    HorizontalPodAutoscaler:
+      - autoscaling/v1
+      - autoscaling/v2beta1
+      - autoscaling/v2beta2
+    Ingress:
+      - networking.k8s.io/v1
+      - networking.k8s.io/v1beta1
+      - extensions/v1beta1
+    IngressClass:
+      - networking.k8s.io/v1
+      - networking.k8s.io/v1beta1
+    IngressRoute:
+      - traefik.containo.us/v1alpha1
+    Job:
+      - batch/v1
+    Lease:
+      - coordination.k8s.io/v1beta1
+      - coordination.k8s.io/v1
+    LimitRange:
+      - v1
+    LocalSubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    Middleware:
+      - traefik.containo.us/v1alpha1
+    MutatingWebhookConfiguration:
+      - admissionregistration.k8s.io/v1beta1
+      - admissionregistration.k8s.io/v1
+    Namespace:
+      - v1
+    NetworkPolicy:
+      - networking.k8s.io/v1
+    Node:
+      - v1
+    PersistentVolume:
+      - v1
+    PersistentVolumeClaim:
+      - v1
+    Pod:
+      - v1
+    PodDisruptionBudget:
+      - policy/v1
+      - policy/v1beta1
+    PodSecurityPolicy:
+      - policy/v1beta1
+    PodTemplate:
+      - v1
+    PriorityClass:
+      - scheduling.k8s.io/v1beta1
+      - scheduling.k8s.io/v1
+    ReplicaSet:
+      - apps/v1
+    ReplicationController:
+      - v1
+    ResourceQuota:
+      - v1
+    Role:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    RoleBinding:
+      - rbac.authorization.k8s.io/v1
+      - rbac.authorization.k8s.io/v1beta1
+    Secret:
+      - v1
+    SelfSubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    SelfSubjectRulesReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    Service:
+      - v1
+    ServiceAccount:
+      - v1
+    StatefulSet:
+      - apps/v1
+    StorageClass:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+    SubjectAccessReview:
+      - authorization.k8s.io/v1
+      - authorization.k8s.io/v1beta1
+    TokenReview:
+      - authentication.k8s.io/v1
+      - authentication.k8s.io/v1beta1
+    ValidatingWebhookConfiguration:
+      - admissionregistration.k8s.io/v1beta1
+      - admissionregistration.k8s.io/v1
+    VolumeAttachment:
+      - storage.k8s.io/v1
+      - storage.k8s.io/v1beta1
+`,
+
 }
\ No newline at end of file
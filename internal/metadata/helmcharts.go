@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	helmChartFileName = "Chart.yaml"
+	helmCommand       = "helm"
+)
+
+// getHelmCharts returns the Helm charts in the directory. The subcharts are rendered along with their parent charts, so they are skipped.
+func getHelmCharts(inputPath string) ([]plantypes.HelmChart, error) {
+	charts := []plantypes.HelmChart{}
+	chartFilePaths, err := common.GetFilesByName(inputPath, []string{helmChartFileName})
+	if err != nil {
+		return charts, err
+	}
+	chartDirs := []string{}
+	for _, chartFilePath := range chartFilePaths {
+		chartDirs = append(chartDirs, filepath.Dir(chartFilePath))
+	}
+	// The parent charts come before their subcharts, since their paths are prefixes of the paths of the subcharts
+	sort.Strings(chartDirs)
+	for _, chartDir := range chartDirs {
+		if isInHelmChart(chartDir, charts) {
+			log.Debugf("Skipping the subchart at path %s", chartDir)
+			continue
+		}
+		log.Debugf("Found a Helm chart at path %s", chartDir)
+		charts = append(charts, plantypes.HelmChart{Path: chartDir})
+	}
+	return charts, nil
+}
+
+// isInHelmChart checks if the path is inside the directory of one of the charts
+func isInHelmChart(path string, charts []plantypes.HelmChart) bool {
+	for _, chart := range charts {
+		if common.IsParent(path, chart.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// getHelmTemplateArgs returns the arguments of helm template rendering the chart
+func getHelmTemplateArgs(chart plantypes.HelmChart) []string {
+	releaseName := chart.ReleaseName
+	if releaseName == "" {
+		releaseName = common.MakeStringDNSLabelNameCompliant(filepath.Base(chart.Path))
+	}
+	args := []string{"template", releaseName, chart.Path}
+	if chart.Namespace != "" {
+		args = append(args, "--namespace", chart.Namespace)
+	}
+	for _, valuesFile := range chart.ValuesFiles {
+		args = append(args, "--values", valuesFile)
+	}
+	return args
+}
+
+// renderHelmChart renders the chart using helm template and returns the rendered yamls
+func renderHelmChart(chart plantypes.HelmChart) ([]byte, error) {
+	output, err := common.RunCommand(chart.Path, helmCommand, getHelmTemplateArgs(chart)...)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("the helm CLI is required to render the Helm chart at path %s . Install it and make sure it is in the PATH", chart.Path)
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to render the Helm chart at path %s . If the chart has dependencies, run helm dependency build first. Error: %q", chart.Path, string(exitErr.Stderr))
+		}
+		return nil, err
+	}
+	return output, nil
+}
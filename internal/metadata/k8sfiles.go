@@ -17,13 +17,15 @@ limitations under the License.
 package metadata
 
 import (
-	"io/ioutil"
+	"bytes"
+	"fmt"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/k8sschema"
 	irtypes "github.com/konveyor/move2kube/internal/types"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 )
 
@@ -35,56 +37,78 @@ type K8sFilesLoader struct {
 func (*K8sFilesLoader) UpdatePlan(inputPath string, plan *plantypes.Plan) error {
 	codecs := serializer.NewCodecFactory(k8sschema.GetSchema())
 
+	charts, err := getHelmCharts(inputPath)
+	if err != nil {
+		log.Errorf("Unable to fetch the Helm charts at path %q Error: %q", inputPath, err)
+		return err
+	}
+	plan.Spec.Inputs.HelmCharts = append(plan.Spec.Inputs.HelmCharts, charts...)
 	filePaths, err := common.GetFilesByExt(inputPath, []string{".yml", ".yaml"})
 	if err != nil {
 		log.Errorf("Unable to fetch yaml files at path %q Error: %q", inputPath, err)
 		return err
 	}
 	for _, filePath := range filePaths {
-		data, err := ioutil.ReadFile(filePath)
-		if err != nil {
-			log.Debugf("Failed to read the yaml file at path %q Error: %q", filePath, err)
-			continue
-		}
-		docs, err := common.SplitYAML(data)
-		if err != nil {
-			log.Debugf("Failed to split the file at path %q into YAML documents. Error: %q", filePath, err)
+		// The templates and the values of the charts are not kubernetes yamls
+		if isInHelmChart(filePath, charts) {
 			continue
 		}
-		for _, doc := range docs {
-			_, _, err = codecs.UniversalDeserializer().Decode(doc, nil, nil)
-			if err != nil {
-				continue
+		// The file is streamed until its first k8s resource, so huge files are not read into memory
+		err := common.StreamYAMLFile(filePath, func(doc []byte) error {
+			if _, _, err := codecs.UniversalDeserializer().Decode(doc, nil, nil); err != nil {
+				return nil
 			}
 			plan.Spec.Inputs.K8sFiles = append(plan.Spec.Inputs.K8sFiles, filePath)
-			break
+			return common.ErrStopStreaming
+		})
+		if err != nil {
+			log.Debugf("Failed to read the yaml file at path %q Error: %q", filePath, err)
 		}
 	}
 	return nil
 }
 
-// LoadToIR loads k8s files as cached objects
+// LoadToIR loads k8s files and the rendered Helm charts as cached objects, except the ones excluded using their kinds and namespaces and the ones
+// owned by other resources. The OpenShift resources the target cluster does not support are converted into vanilla kubernetes resources.
 func (*K8sFilesLoader) LoadToIR(plan plantypes.Plan, ir *irtypes.IR) error {
 	codecs := serializer.NewCodecFactory(k8sschema.GetSchema())
+	objs := []runtime.Object{}
+	decode := func(source string) func(doc []byte) error {
+		i := 0
+		return func(doc []byte) error {
+			defer func() { i++ }()
+			obj, _, err := codecs.UniversalDeserializer().Decode(doc, nil, nil)
+			if err != nil {
+				log.Errorf("Failed to decode the YAML document %d in %s as a k8s resource. Error: %q", i, source, err)
+				return nil
+			}
+			objs = append(objs, obj)
+			return nil
+		}
+	}
 	for _, filePath := range plan.Spec.Inputs.K8sFiles {
-		data, err := ioutil.ReadFile(filePath)
-		if err != nil {
+		// The documents, and the items of the lists, are decoded one at a time, so huge cluster dumps are not read into memory
+		if err := common.StreamYAMLFile(filePath, decode(fmt.Sprintf("file at path %q", filePath))); err != nil {
 			log.Errorf("Failed to read the k8s file at path %q Error: %q", filePath, err)
-			continue
 		}
-		docs, err := common.SplitYAML(data)
+	}
+	for _, chart := range plan.Spec.Inputs.HelmCharts {
+		rendered, err := renderHelmChart(chart)
 		if err != nil {
-			log.Debugf("Failed to split the file at path %q into YAML documents. Error: %q", filePath, err)
+			log.Errorf("Failed to render the Helm chart at path %q Error: %q", chart.Path, err)
 			continue
 		}
-		for i, doc := range docs {
-			obj, _, err := codecs.UniversalDeserializer().Decode(doc, nil, nil)
-			if err != nil {
-				log.Errorf("Failed to decode the YAML document %d in file at path %q as a k8s resource. Error: %q", i, filePath, err)
-				continue
-			}
-			ir.CachedObjects = append(ir.CachedObjects, obj)
+		if err := common.StreamYAMLDocuments(bytes.NewReader(rendered), decode(fmt.Sprintf("the Helm chart at path %q", chart.Path))); err != nil {
+			log.Errorf("Failed to read the rendered Helm chart at path %q Error: %q", chart.Path, err)
 		}
 	}
+	if len(objs) == 0 {
+		return nil
+	}
+	objs = convertOpenShiftResources(objs, ir.TargetClusterSpec)
+	filteredObjs := PruneDerivedResources(getK8sResourceFilter(objs).Filter(objs))
+	ScrubResources(filteredObjs, getScrubRules(filteredObjs))
+	log.Debugf("Selected %d of the %d kubernetes resources", len(filteredObjs), len(objs))
+	ir.CachedObjects = append(ir.CachedObjects, filteredObjs...)
 	return nil
 }
@@ -18,14 +18,24 @@ package metadata_test
 
 import (
 	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
+	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/metadata"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
 	plantypes "github.com/konveyor/move2kube/types/plan"
+	okdappsv1 "github.com/openshift/api/apps/v1"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type K8sFilesLoaderTestSuite struct {
@@ -105,6 +115,88 @@ func (s *K8sFilesLoaderTestSuite) TestValidInvalid() {
 	s.Equal(want, s.plan)
 }
 
+func (s *K8sFilesLoaderTestSuite) TestList() {
+	want := plantypes.NewPlan()
+	want.Spec.Inputs.K8sFiles = []string{"testdata/k8s/list/list.yaml"}
+	s.NoError(s.loader.UpdatePlan("testdata/k8s/list", &s.plan))
+	s.Equal(want, s.plan)
+	defer func(excludeKinds []string) { common.K8sExcludeKinds = excludeKinds }(common.K8sExcludeKinds)
+	common.K8sExcludeKinds = []string{"Endpoints"}
+	ir := irtypes.NewIR(s.plan)
+	s.NoError(s.loader.LoadToIR(s.plan, &ir))
+	s.Equal(2, len(ir.CachedObjects))
+}
+
+func (s *K8sFilesLoaderTestSuite) TestHelmChart() {
+	dir := s.T().TempDir()
+	chartDir := filepath.Join(dir, "tickets")
+	subchartDir := filepath.Join(chartDir, "charts", "redis")
+	for _, d := range []string{filepath.Join(chartDir, "templates"), filepath.Join(subchartDir, "templates")} {
+		s.NoError(os.MkdirAll(d, os.ModePerm))
+	}
+	s.NoError(ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("apiVersion: v2\nname: tickets\nversion: 0.1.0\n"), 0644))
+	s.NoError(ioutil.WriteFile(filepath.Join(subchartDir, "Chart.yaml"), []byte("apiVersion: v2\nname: redis\nversion: 0.1.0\n"), 0644))
+	s.copyfile("testdata/k8s/valid/valid.yaml", filepath.Join(chartDir, "templates", "valid.yaml"))
+	s.copyfile("testdata/k8s/valid/valid.yaml", filepath.Join(dir, "valid.yaml"))
+
+	want := plantypes.NewPlan()
+	want.Spec.Inputs.HelmCharts = []plantypes.HelmChart{{Path: chartDir}}
+	want.Spec.Inputs.K8sFiles = []string{filepath.Join(dir, "valid.yaml")}
+	s.NoError(s.loader.UpdatePlan(dir, &s.plan))
+	s.Equal(want, s.plan)
+
+	if _, err := exec.LookPath("helm"); err != nil {
+		s.T().Skip("The helm CLI is required to render the chart")
+	}
+	s.plan.Spec.Inputs.K8sFiles = nil
+	ir := irtypes.NewIR(s.plan)
+	s.NoError(s.loader.LoadToIR(s.plan, &ir))
+	s.Equal(1, len(ir.CachedObjects))
+}
+
+func (s *K8sFilesLoaderTestSuite) TestOpenShiftResources() {
+	qaengine.StartEngine(true, 0, false, "")
+	defer func(excludeKinds []string) { common.K8sExcludeKinds = excludeKinds }(common.K8sExcludeKinds)
+	common.K8sExcludeKinds = []string{"Endpoints"}
+	s.plan.Spec.Inputs.K8sFiles = []string{"testdata/k8s/openshift/template.yaml"}
+	getObjects := func(clusterSpec collecttypes.ClusterMetadataSpec) map[string]runtime.Object {
+		ir := irtypes.NewIR(s.plan)
+		ir.TargetClusterSpec = clusterSpec
+		s.NoError(s.loader.LoadToIR(s.plan, &ir))
+		objs := map[string]runtime.Object{}
+		for _, obj := range ir.CachedObjects {
+			objs[obj.GetObjectKind().GroupVersionKind().Kind] = obj
+		}
+		return objs
+	}
+
+	objs := getObjects(collecttypes.ClusterMetadataSpec{APIKindVersionMap: map[string][]string{"Deployment": {"apps/v1"}, "Ingress": {"networking.k8s.io/v1"}}})
+	for _, kind := range []string{"Template", "ImageStream", "BuildConfig"} {
+		s.NotContains(objs, kind)
+	}
+	s.Require().Contains(objs, "DeploymentConfig")
+	dc := objs["DeploymentConfig"].(*okdappsv1.DeploymentConfig)
+	s.Equal("shop", dc.Name)
+	s.Equal(int32(3), dc.Spec.Replicas)
+	s.Equal("shop", dc.Labels["template"])
+	s.Equal("shop:latest", dc.Spec.Template.Spec.Containers[0].Image)
+	s.Require().Contains(objs, "Secret")
+	s.Regexp("^[a-z0-9]{12}$", objs["Secret"].(*corev1.Secret).StringData["password"])
+	s.Contains(objs, "Route")
+	s.Require().Contains(objs, "Pipeline")
+	pipeline := objs["Pipeline"].(*v1beta1.Pipeline)
+	s.Equal("shop", pipeline.Name)
+	s.Require().Len(pipeline.Spec.Tasks, 2)
+	s.Equal("kaniko", pipeline.Spec.Tasks[1].TaskRef.Name)
+	s.Equal("$(params.image-registry-url)/shop:latest", pipeline.Spec.Tasks[1].Params[0].Value.StringVal)
+	s.Equal("web/Dockerfile.prod", pipeline.Spec.Tasks[1].Params[1].Value.StringVal)
+
+	objs = getObjects(collecttypes.ClusterMetadataSpec{APIKindVersionMap: map[string][]string{"Template": {"template.openshift.io/v1"}, "BuildConfig": {"build.openshift.io/v1"}}})
+	s.Contains(objs, "Template")
+	s.Contains(objs, "BuildConfig")
+	s.NotContains(objs, "Pipeline")
+}
+
 // TestK8sFilesLoader runs test suite
 func TestK8sFilesLoader(t *testing.T) {
 	suite.Run(t, new(K8sFilesLoaderTestSuite))
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	replicaSetKind = "ReplicaSet"
+	// podTemplateHashLabel is added by the deployment controller to the replica sets it generates
+	podTemplateHashLabel = "pod-template-hash"
+)
+
+// defaultExcludedKinds are the kinds of the resources which are populated by the cluster, instead of being deployed
+var defaultExcludedKinds = []string{"ComponentStatus", "ControllerRevision", "EndpointSlice", "Endpoints", "Event", "Lease", "Node"}
+
+// defaultExcludedNamespaces are the namespaces of the components of the cluster
+var defaultExcludedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease", "openshift", "openshift-*"}
+
+// K8sResourceFilter selects the kubernetes resources that are translated using their kinds and namespaces
+type K8sResourceFilter struct {
+	// IncludeKinds are the kinds that are translated. If empty, all the kinds except ExcludeKinds are translated.
+	IncludeKinds []string
+	// ExcludeKinds are the kinds that are not translated
+	ExcludeKinds []string
+	// IncludeNamespaces are the namespaces, or their glob patterns, that are translated. If empty, all the namespaces
+	// except ExcludeNamespaces are translated. The cluster scoped resources are always translated.
+	IncludeNamespaces []string
+	// ExcludeNamespaces are the namespaces, or their glob patterns, that are not translated
+	ExcludeNamespaces []string
+}
+
+// Filter returns the objects which are selected by the filter
+func (f K8sResourceFilter) Filter(objs []runtime.Object) []runtime.Object {
+	filtered := []runtime.Object{}
+	for _, obj := range objs {
+		if f.IsSelected(obj) {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+// IsSelected returns true if the object is selected by the filter. The replica sets generated by the deployments are
+// selected only if the replica sets are included explicitly.
+func (f K8sResourceFilter) IsSelected(obj runtime.Object) bool {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if len(f.IncludeKinds) > 0 && !containsFold(f.IncludeKinds, kind) {
+		return false
+	}
+	if containsFold(f.ExcludeKinds, kind) {
+		return false
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return true
+	}
+	if kind == replicaSetKind && !containsFold(f.IncludeKinds, replicaSetKind) {
+		if _, ok := accessor.GetLabels()[podTemplateHashLabel]; ok {
+			return false
+		}
+	}
+	namespace := accessor.GetNamespace()
+	if namespace == "" {
+		return true
+	}
+	if len(f.IncludeNamespaces) > 0 && !matchesAny(f.IncludeNamespaces, namespace) {
+		return false
+	}
+	return !matchesAny(f.ExcludeNamespaces, namespace)
+}
+
+// getK8sResourceFilter returns the filter set using the flags. The kinds and namespaces which are not set using the
+// flags are asked, with the resources populated by the cluster and the namespaces of the cluster components deselected.
+func getK8sResourceFilter(objs []runtime.Object) K8sResourceFilter {
+	filter := K8sResourceFilter{
+		IncludeKinds:      common.K8sIncludeKinds,
+		ExcludeKinds:      common.K8sExcludeKinds,
+		IncludeNamespaces: common.K8sIncludeNamespaces,
+		ExcludeNamespaces: common.K8sExcludeNamespaces,
+	}
+	kinds := []string{}
+	namespaces := []string{}
+	for _, obj := range objs {
+		if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" && !common.IsStringPresent(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+		if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() != "" && !common.IsStringPresent(namespaces, accessor.GetNamespace()) {
+			namespaces = append(namespaces, accessor.GetNamespace())
+		}
+	}
+	sort.Strings(kinds)
+	sort.Strings(namespaces)
+	if len(filter.IncludeKinds) == 0 && len(filter.ExcludeKinds) == 0 && len(kinds) > 0 {
+		defaultKinds := []string{}
+		for _, kind := range kinds {
+			if !common.IsStringPresent(defaultExcludedKinds, kind) {
+				defaultKinds = append(defaultKinds, kind)
+			}
+		}
+		hints := []string{"The resources populated by the cluster, like " + strings.Join(defaultExcludedKinds, ", ") + ", are deselected by default."}
+		selected := qaengine.FetchMultiSelectAnswer(common.ConfigK8sSourceKindsKey, "Select the kinds of the kubernetes resources to translate:", hints, defaultKinds, kinds)
+		for _, kind := range kinds {
+			if !common.IsStringPresent(selected, kind) {
+				filter.ExcludeKinds = append(filter.ExcludeKinds, kind)
+			}
+		}
+	}
+	if len(filter.IncludeNamespaces) == 0 && len(filter.ExcludeNamespaces) == 0 && len(namespaces) > 0 {
+		defaultNamespaces := []string{}
+		for _, namespace := range namespaces {
+			if !matchesAny(defaultExcludedNamespaces, namespace) {
+				defaultNamespaces = append(defaultNamespaces, namespace)
+			}
+		}
+		hints := []string{"The namespaces of the cluster components, like " + strings.Join(defaultExcludedNamespaces, ", ") + ", are deselected by default. The cluster scoped resources are always translated."}
+		selected := qaengine.FetchMultiSelectAnswer(common.ConfigK8sSourceNamespacesKey, "Select the namespaces of the kubernetes resources to translate:", hints, defaultNamespaces, namespaces)
+		for _, namespace := range namespaces {
+			if !common.IsStringPresent(selected, namespace) {
+				filter.ExcludeNamespaces = append(filter.ExcludeNamespaces, namespace)
+			}
+		}
+	}
+	log.Debugf("Filtering the kubernetes resources using %+v", filter)
+	return filter
+}
+
+// containsFold returns true if the list contains the value, ignoring the case
+func containsFold(list []string, value string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny returns true if the value matches any of the glob patterns
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, err := filepath.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
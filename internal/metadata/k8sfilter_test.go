@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/metadata"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestK8sResourceFilter(t *testing.T) {
+	newObjectMeta := func(name, namespace string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace}
+	}
+	service := &corev1.Service{TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"}, ObjectMeta: newObjectMeta("svc1", "default")}
+	event := &corev1.Event{TypeMeta: metav1.TypeMeta{Kind: "Event", APIVersion: "v1"}, ObjectMeta: newObjectMeta("svc1.123", "default")}
+	deployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, ObjectMeta: newObjectMeta("svc1", "default")}
+	generatedReplicaSet := &appsv1.ReplicaSet{TypeMeta: metav1.TypeMeta{Kind: "ReplicaSet", APIVersion: "apps/v1"}, ObjectMeta: newObjectMeta("svc1-5d4f7c", "default")}
+	generatedReplicaSet.Labels = map[string]string{"pod-template-hash": "5d4f7c"}
+	systemDeployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, ObjectMeta: newObjectMeta("coredns", "kube-system")}
+	operatorDeployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, ObjectMeta: newObjectMeta("console", "openshift-console")}
+	clusterRole := &rbacv1.ClusterRole{TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"}, ObjectMeta: newObjectMeta("reader", "")}
+	objs := []runtime.Object{service, event, deployment, generatedReplicaSet, systemDeployment, operatorDeployment, clusterRole}
+
+	testcases := []struct {
+		name   string
+		filter metadata.K8sResourceFilter
+		want   []runtime.Object
+	}{
+		{
+			name:   "exclude kinds and namespaces",
+			filter: metadata.K8sResourceFilter{ExcludeKinds: []string{"event"}, ExcludeNamespaces: []string{"kube-system", "openshift-*"}},
+			want:   []runtime.Object{service, deployment, clusterRole},
+		},
+		{
+			name:   "include kinds and namespaces",
+			filter: metadata.K8sResourceFilter{IncludeKinds: []string{"Deployment", "ClusterRole"}, IncludeNamespaces: []string{"openshift-*"}},
+			want:   []runtime.Object{operatorDeployment, clusterRole},
+		},
+		{
+			name:   "include the generated replica sets",
+			filter: metadata.K8sResourceFilter{IncludeKinds: []string{"ReplicaSet"}},
+			want:   []runtime.Object{generatedReplicaSet},
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			filtered := testcase.filter.Filter(objs)
+			if len(filtered) != len(testcase.want) {
+				t.Fatalf("Expected %d resources. Actual: %d", len(testcase.want), len(filtered))
+			}
+			for i := range filtered {
+				if filtered[i] != testcase.want[i] {
+					t.Fatalf("Expected the resource %d to be %+v . Actual: %+v", i, testcase.want[i], filtered[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"reflect"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// serverPopulatedAnnotations are the annotations, or the prefixes of the annotations, added by the cluster and kubectl
+var serverPopulatedAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/",
+	"pv.kubernetes.io/",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/",
+	"control-plane.alpha.kubernetes.io/leader",
+	"endpoints.kubernetes.io/last-change-trigger-time",
+}
+
+// PruneDerivedResources drops the resources which are owned by other resources, like the pods of a replica set or the
+// endpoint slices of a service, since they are recreated by their owners. The fields populated by the cluster, like the
+// status, the uid and the cluster ip of the services, are removed from the other resources, so that only the
+// declaratively managed resources and fields remain.
+func PruneDerivedResources(objs []runtime.Object) []runtime.Object {
+	pruned := []runtime.Object{}
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			pruned = append(pruned, obj)
+			continue
+		}
+		if ownerReferences := accessor.GetOwnerReferences(); len(ownerReferences) > 0 {
+			log.Debugf("Dropping the %s %s owned by the %s %s", obj.GetObjectKind().GroupVersionKind().Kind, accessor.GetName(), ownerReferences[0].Kind, ownerReferences[0].Name)
+			continue
+		}
+		removeServerPopulatedFields(obj, accessor)
+		pruned = append(pruned, obj)
+	}
+	return pruned
+}
+
+// removeServerPopulatedFields removes the metadata, the status and the fields of the spec populated by the cluster
+func removeServerPopulatedFields(obj runtime.Object, accessor metav1.Object) {
+	accessor.SetUID("")
+	accessor.SetResourceVersion("")
+	accessor.SetGeneration(0)
+	accessor.SetSelfLink("")
+	accessor.SetCreationTimestamp(metav1.Time{})
+	accessor.SetDeletionTimestamp(nil)
+	accessor.SetDeletionGracePeriodSeconds(nil)
+	accessor.SetManagedFields(nil)
+	if annotations := accessor.GetAnnotations(); len(annotations) > 0 {
+		for key := range annotations {
+			for _, serverPopulatedAnnotation := range serverPopulatedAnnotations {
+				if key == serverPopulatedAnnotation || (strings.HasSuffix(serverPopulatedAnnotation, "/") && strings.HasPrefix(key, serverPopulatedAnnotation)) {
+					delete(annotations, key)
+					break
+				}
+			}
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		accessor.SetAnnotations(annotations)
+	}
+	if objValue := reflect.ValueOf(obj); objValue.Kind() == reflect.Ptr && objValue.Elem().Kind() == reflect.Struct {
+		if status := objValue.Elem().FieldByName("Status"); status.IsValid() && status.CanSet() {
+			status.Set(reflect.Zero(status.Type()))
+		}
+	}
+	if service, ok := obj.(*corev1.Service); ok && service.Spec.ClusterIP != corev1.ClusterIPNone {
+		// The cluster ip is allocated by the cluster, except for the headless services
+		service.Spec.ClusterIP = ""
+	}
+	if claim, ok := obj.(*corev1.PersistentVolumeClaim); ok {
+		// The volume is bound by the cluster
+		claim.Spec.VolumeName = ""
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/metadata"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestPruneDerivedResources(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "svc1",
+			Namespace:         "default",
+			UID:               "a1b2",
+			ResourceVersion:   "1234",
+			Generation:        3,
+			CreationTimestamp: metav1.Now(),
+			ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision":                "3",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"team": "payments",
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 2, ReadyReplicas: 2},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{Kind: "ReplicaSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "svc1-5d4f7c",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "svc1"}},
+		},
+	}
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.12"},
+		Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}}},
+	}
+	headlessService := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone},
+	}
+
+	pruned := metadata.PruneDerivedResources([]runtime.Object{deployment, replicaSet, service, headlessService})
+	if len(pruned) != 3 || pruned[0] != deployment || pruned[1] != service || pruned[2] != headlessService {
+		t.Fatalf("Expected the owned replica set to be dropped. Actual: %+v", pruned)
+	}
+	if deployment.UID != "" || deployment.ResourceVersion != "" || deployment.Generation != 0 || !deployment.CreationTimestamp.IsZero() || deployment.ManagedFields != nil {
+		t.Fatalf("Expected the metadata populated by the cluster to be removed. Actual: %+v", deployment.ObjectMeta)
+	}
+	if len(deployment.Annotations) != 1 || deployment.Annotations["team"] != "payments" {
+		t.Fatalf("Expected only the annotations added by the cluster to be removed. Actual: %+v", deployment.Annotations)
+	}
+	if deployment.Status.Replicas != 0 || len(service.Status.LoadBalancer.Ingress) != 0 {
+		t.Fatalf("Expected the status to be removed. Actual: %+v %+v", deployment.Status, service.Status)
+	}
+	if service.Spec.ClusterIP != "" || headlessService.Spec.ClusterIP != corev1.ClusterIPNone {
+		t.Fatalf("Expected the cluster ip to be removed except for the headless service. Actual: %q %q", service.Spec.ClusterIP, headlessService.Spec.ClusterIP)
+	}
+}
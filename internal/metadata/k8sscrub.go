@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// customScrubRuleName is the name of the rule containing the patterns set using the flags
+	customScrubRuleName = "custom"
+)
+
+// ScrubRule removes the annotations, labels and finalizers which are specific to the source cluster, like the
+// annotations of the cloud load balancers, when migrating the kubernetes resources to another cluster.
+// The patterns are keys or glob patterns of the keys, like cloud.google.com/*.
+type ScrubRule struct {
+	Name        string
+	Description string
+	Annotations []string
+	Labels      []string
+	Finalizers  []string
+	// Allow are the keys, or the glob patterns of the keys, that are kept even though they match the rule
+	Allow []string
+}
+
+// DefaultScrubRules are the rules which can be applied to the kubernetes resources used as source
+var DefaultScrubRules = []ScrubRule{{
+	Name:        "kubectl",
+	Description: "the annotations added by kubectl, like kubectl.kubernetes.io/last-applied-configuration",
+	Annotations: []string{"kubectl.kubernetes.io/*"},
+}, {
+	Name:        "cloud-load-balancers",
+	Description: "the annotations configuring the load balancers and ingresses of the cloud providers",
+	Annotations: []string{
+		"service.beta.kubernetes.io/*",
+		"service.kubernetes.io/*",
+		"cloud.google.com/*",
+		"networking.gke.io/*",
+		"alb.ingress.kubernetes.io/*",
+	},
+}, {
+	Name:        "finalizers",
+	Description: "the finalizers, which block the deletion of the resources until the controllers of the source cluster clean up",
+	Finalizers:  []string{"*", "*/*"},
+}, {
+	Name:        "tooling",
+	Description: "the annotations and labels added by tools like Helm and Argo CD, which do not manage the resources in the target cluster",
+	Annotations: []string{"meta.helm.sh/*", "argocd.argoproj.io/*"},
+	Labels:      []string{"app.kubernetes.io/managed-by", "helm.sh/chart", "argocd.argoproj.io/instance"},
+}}
+
+// matches returns the keys which match the rule and are not allowed
+func (r ScrubRule) matches(patterns []string, keys []string) []string {
+	matched := []string{}
+	for _, key := range keys {
+		if matchesAny(patterns, key) && !matchesAny(r.Allow, key) {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
+
+// Scrub removes the annotations, labels and finalizers matching the rule from the object and returns their keys
+func (r ScrubRule) Scrub(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	removed := []string{}
+	if annotations := accessor.GetAnnotations(); len(annotations) > 0 {
+		for _, key := range r.matches(r.Annotations, getKeys(annotations)) {
+			delete(annotations, key)
+			removed = append(removed, key)
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		accessor.SetAnnotations(annotations)
+	}
+	if labels := accessor.GetLabels(); len(labels) > 0 {
+		for _, key := range r.matches(r.Labels, getKeys(labels)) {
+			delete(labels, key)
+			removed = append(removed, key)
+		}
+		if len(labels) == 0 {
+			labels = nil
+		}
+		accessor.SetLabels(labels)
+	}
+	if finalizers := accessor.GetFinalizers(); len(finalizers) > 0 {
+		scrubbed := r.matches(r.Finalizers, finalizers)
+		kept := []string{}
+		for _, finalizer := range finalizers {
+			if !common.IsStringPresent(scrubbed, finalizer) {
+				kept = append(kept, finalizer)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		accessor.SetFinalizers(kept)
+		removed = append(removed, scrubbed...)
+	}
+	return removed
+}
+
+// ScrubResources applies the rules to the objects
+func ScrubResources(objs []runtime.Object, rules []ScrubRule) {
+	for _, obj := range objs {
+		for _, rule := range rules {
+			if removed := rule.Scrub(obj); len(removed) > 0 {
+				log.Debugf("The scrub rule %s removed %v from the %s %s", rule.Name, removed, obj.GetObjectKind().GroupVersionKind().Kind, getName(obj))
+			}
+		}
+	}
+}
+
+// getScrubRules returns the rules which are applied to the objects. The rules, and the keys kept by each rule, which
+// are not set using the flags are asked. Only the rules matching some of the objects are asked.
+func getScrubRules(objs []runtime.Object) []ScrubRule {
+	rules := []ScrubRule{}
+	for _, rule := range DefaultScrubRules {
+		if len(common.K8sScrubRules) > 0 && !common.IsStringPresent(common.K8sScrubRules, rule.Name) {
+			continue
+		}
+		rule.Allow = append(append([]string{}, rule.Allow...), common.K8sScrubAllow...)
+		if len(getMatchedKeys(rule, objs)) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	if len(common.K8sScrubDeny) > 0 {
+		rules = append(rules, ScrubRule{
+			Name:        customScrubRuleName,
+			Description: "the annotations, labels and finalizers set using the flags",
+			Annotations: common.K8sScrubDeny,
+			Labels:      common.K8sScrubDeny,
+			Finalizers:  common.K8sScrubDeny,
+			Allow:       common.K8sScrubAllow,
+		})
+	}
+	if len(rules) == 0 || len(common.K8sScrubRules) > 0 {
+		return rules
+	}
+	names := []string{}
+	hints := []string{}
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+		hints = append(hints, fmt.Sprintf("%s : removes %s.", rule.Name, rule.Description))
+	}
+	selectedNames := qaengine.FetchMultiSelectAnswer(common.ConfigK8sSourceScrubRulesKey, "Select the rules which remove the annotations, labels and finalizers specific to the source cluster:", hints, names, names)
+	selected := []ScrubRule{}
+	for _, rule := range rules {
+		if !common.IsStringPresent(selectedNames, rule.Name) {
+			continue
+		}
+		if rule.Name != customScrubRuleName {
+			matched := getMatchedKeys(rule, objs)
+			key := common.ConfigK8sSourceScrubKey + common.Delim + `"` + rule.Name + `"` + common.Delim + "allow"
+			desc := fmt.Sprintf("Select the keys to keep, which are otherwise removed by the scrub rule %s:", rule.Name)
+			rule.Allow = append(rule.Allow, qaengine.FetchMultiSelectAnswer(key, desc, []string{"The keys which are not selected are removed."}, []string{}, matched)...)
+		}
+		selected = append(selected, rule)
+	}
+	return selected
+}
+
+// getMatchedKeys returns the keys of the annotations, labels and finalizers of the objects which match the rule
+func getMatchedKeys(rule ScrubRule, objs []runtime.Object) []string {
+	keys := []string{}
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		matched := append(rule.matches(rule.Annotations, getKeys(accessor.GetAnnotations())), rule.matches(rule.Labels, getKeys(accessor.GetLabels()))...)
+		for _, key := range append(matched, rule.matches(rule.Finalizers, accessor.GetFinalizers())...) {
+			if !common.IsStringPresent(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getKeys(m map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func getName(obj runtime.Object) string {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		return accessor.GetName()
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/metadata"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestScrubResources(t *testing.T) {
+	newService := func() *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc1",
+				Namespace: "default",
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration":      "{}",
+					"service.beta.kubernetes.io/aws-load-balancer-type":     "nlb",
+					"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+					"meta.helm.sh/release-name":                             "svc1",
+					"team":                                                  "payments",
+				},
+				Labels:     map[string]string{"app": "svc1", "app.kubernetes.io/managed-by": "Helm"},
+				Finalizers: []string{"service.kubernetes.io/load-balancer-cleanup"},
+			},
+		}
+	}
+
+	t.Run("default rules", func(t *testing.T) {
+		service := newService()
+		metadata.ScrubResources([]runtime.Object{service}, metadata.DefaultScrubRules)
+		if !reflect.DeepEqual(service.Annotations, map[string]string{"team": "payments"}) {
+			t.Fatalf("Expected only the annotations specific to the source cluster to be removed. Actual: %+v", service.Annotations)
+		}
+		if !reflect.DeepEqual(service.Labels, map[string]string{"app": "svc1"}) {
+			t.Fatalf("Expected the labels added by Helm to be removed. Actual: %+v", service.Labels)
+		}
+		if service.Finalizers != nil {
+			t.Fatalf("Expected the finalizers to be removed. Actual: %+v", service.Finalizers)
+		}
+	})
+
+	t.Run("allowed keys", func(t *testing.T) {
+		service := newService()
+		rule := metadata.DefaultScrubRules[1]
+		rule.Allow = []string{"service.beta.kubernetes.io/aws-load-balancer-type"}
+		removed := rule.Scrub(service)
+		if !reflect.DeepEqual(removed, []string{"service.beta.kubernetes.io/aws-load-balancer-internal"}) {
+			t.Fatalf("Expected only the annotation which is not allowed to be removed. Actual: %+v", removed)
+		}
+		if len(service.Annotations) != 4 || service.Finalizers == nil {
+			t.Fatalf("Expected the other annotations and the finalizers to be kept. Actual: %+v %+v", service.Annotations, service.Finalizers)
+		}
+	})
+}
@@ -29,6 +29,6 @@ type Loader interface {
 
 // GetLoaders returns planner for given format
 func GetLoaders() []Loader {
-	var planners = []Loader{new(ClusterMDLoader), new(K8sFilesLoader)}
+	var planners = []Loader{new(ClusterMDLoader), new(K8sFilesLoader), new(AnalysisLoader)}
 	return planners
 }
@@ -0,0 +1,370 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/k8sschema"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	okdappsv1 "github.com/openshift/api/apps/v1"
+	okdbuildv1 "github.com/openshift/api/build/v1"
+	okdimagev1 "github.com/openshift/api/image/v1"
+	templatev1 "github.com/openshift/api/template/v1"
+	log "github.com/sirupsen/logrus"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+const (
+	templateKind         = "Template"
+	buildConfigKind      = "BuildConfig"
+	imageStreamKind      = "ImageStream"
+	deploymentConfigKind = "DeploymentConfig"
+	imageStreamTagKind   = "ImageStreamTag"
+	dockerImageKind      = "DockerImage"
+	// templateGenerateExpression is the generator of the template parameters whose values are generated from the expression in From
+	templateGenerateExpression = "expression"
+	// openShiftPipelineWorkspace receives the git repo cloned by the pipelines converted from the build configs
+	openShiftPipelineWorkspace = "shared-data"
+	defaultOpenShiftGitRef     = "main"
+	defaultDockerfilePath      = "Dockerfile"
+)
+
+// openShiftRegistryHosts are the hosts of the internal registries of OpenShift, whose images are pushed by the build configs
+var openShiftRegistryHosts = []string{"image-registry.openshift-image-registry.svc", "docker-registry.default.svc"}
+
+// templateExpressionRegex matches the character classes and their lengths in the expressions generating the template parameters, like [a-zA-Z0-9]{16}
+var templateExpressionRegex = regexp.MustCompile(`\[([^\]]+)\]\{(\d+)\}`)
+
+// convertOpenShiftResources converts the OpenShift resources the target cluster does not support into vanilla kubernetes resources.
+// The templates are processed into their objects, the images of the deployment configs are resolved from their image change triggers,
+// the build configs are converted into Tekton pipelines and the image streams are dropped. The deployment configs and routes are
+// converted into deployments and ingresses along with the resources generated by the translation.
+func convertOpenShiftResources(objs []runtime.Object, clusterSpec collecttypes.ClusterMetadataSpec) []runtime.Object {
+	isSupported := func(kind string) bool { return len(clusterSpec.GetSupportedVersions(kind)) > 0 }
+	converted := []runtime.Object{}
+	for _, obj := range objs {
+		if template, ok := obj.(*templatev1.Template); ok && !isSupported(templateKind) {
+			converted = append(converted, processTemplate(*template)...)
+			continue
+		}
+		converted = append(converted, obj)
+	}
+	imageStreams := []okdimagev1.ImageStream{}
+	for _, obj := range converted {
+		if imageStream, ok := obj.(*okdimagev1.ImageStream); ok {
+			imageStreams = append(imageStreams, *imageStream)
+		}
+	}
+	objs = converted
+	converted = []runtime.Object{}
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *okdappsv1.DeploymentConfig:
+			if !isSupported(deploymentConfigKind) {
+				resolveDeploymentConfigImages(o, imageStreams)
+			}
+		case *okdbuildv1.BuildConfig:
+			if !isSupported(buildConfigKind) {
+				if pipeline, ok := buildConfigToPipeline(*o); ok {
+					obj = pipeline
+				}
+			}
+		case *okdimagev1.ImageStream:
+			if !isSupported(imageStreamKind) {
+				log.Debugf("Dropping the image stream %s , since the target cluster does not support image streams", o.Name)
+				continue
+			}
+		}
+		converted = append(converted, obj)
+	}
+	return converted
+}
+
+// processTemplate returns the objects of the template, with the parameters replaced by the answers to the questions about them
+func processTemplate(template templatev1.Template) []runtime.Object {
+	codecs := serializer.NewCodecFactory(k8sschema.GetSchema())
+	values := map[string]string{}
+	for _, parameter := range template.Parameters {
+		value := parameter.Value
+		if value == "" && parameter.Generate == templateGenerateExpression {
+			generated, err := generateTemplateValue(parameter.From)
+			if err != nil {
+				log.Warnf("Failed to generate the value of the parameter %s of the template %s using the expression %s . Error: %q", parameter.Name, template.Name, parameter.From, err)
+			}
+			value = generated
+		}
+		hints := []string{}
+		if parameter.Description != "" {
+			hints = append(hints, parameter.Description)
+		}
+		key := common.ConfigK8sSourceTemplatesKey + common.Delim + `"` + template.Name + `"` + common.Delim + "parameters" + common.Delim + parameter.Name
+		desc := fmt.Sprintf("Enter the value of the parameter %s of the OpenShift template %s :", parameter.Name, template.Name)
+		values[parameter.Name] = qaengine.FetchStringAnswer(key, desc, hints, value)
+		if values[parameter.Name] == "" && parameter.Required {
+			log.Warnf("The required parameter %s of the OpenShift template %s has no value", parameter.Name, template.Name)
+		}
+	}
+	objs := []runtime.Object{}
+	for i, rawObj := range template.Objects {
+		if len(rawObj.Raw) == 0 {
+			log.Warnf("Skipping the object %d of the OpenShift template %s , since it could not be read", i, template.Name)
+			continue
+		}
+		obj, _, err := codecs.UniversalDeserializer().Decode(replaceTemplateParameters(rawObj.Raw, values), nil, nil)
+		if err != nil {
+			log.Errorf("Failed to decode the object %d of the OpenShift template %s as a k8s resource. Error: %q", i, template.Name, err)
+			continue
+		}
+		if len(template.ObjectLabels) > 0 {
+			if accessor, err := meta.Accessor(obj); err == nil {
+				labels := accessor.GetLabels()
+				if labels == nil {
+					labels = map[string]string{}
+				}
+				for key, value := range template.ObjectLabels {
+					labels[key] = value
+				}
+				accessor.SetLabels(labels)
+			}
+		}
+		objs = append(objs, obj)
+	}
+	log.Debugf("Processed the OpenShift template %s into %d objects", template.Name, len(objs))
+	return objs
+}
+
+// replaceTemplateParameters replaces the ${NAME} references in the strings of the json object and the "${{NAME}}" references
+// which are replaced by non string values, like numbers and booleans
+func replaceTemplateParameters(raw []byte, values map[string]string) []byte {
+	for name, value := range values {
+		literal := value
+		if !json.Valid([]byte(value)) {
+			literalBytes, _ := json.Marshal(value)
+			literal = string(literalBytes)
+		}
+		raw = bytes.ReplaceAll(raw, []byte(`"${{`+name+`}}"`), []byte(literal))
+		quoted, _ := json.Marshal(value)
+		raw = bytes.ReplaceAll(raw, []byte("${"+name+"}"), quoted[1:len(quoted)-1])
+	}
+	return raw
+}
+
+// generateTemplateValue generates a value from an expression made of character classes and their lengths, like [a-zA-Z0-9]{16}
+func generateTemplateValue(expression string) (string, error) {
+	value := strings.Builder{}
+	last := 0
+	for _, match := range templateExpressionRegex.FindAllStringSubmatchIndex(expression, -1) {
+		value.WriteString(expression[last:match[0]])
+		last = match[1]
+		chars := expandCharacterClass(expression[match[2]:match[3]])
+		length, err := strconv.Atoi(expression[match[4]:match[5]])
+		if err != nil || len(chars) == 0 {
+			return value.String(), fmt.Errorf("the expression %s is not supported", expression)
+		}
+		for i := 0; i < length; i++ {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+			if err != nil {
+				return value.String(), err
+			}
+			value.WriteByte(chars[n.Int64()])
+		}
+	}
+	value.WriteString(expression[last:])
+	return value.String(), nil
+}
+
+// expandCharacterClass returns the characters of a character class, like a-zA-Z0-9, \w or \d
+func expandCharacterClass(class string) string {
+	chars := strings.Builder{}
+	for i := 0; i < len(class); i++ {
+		switch {
+		case class[i] == '\\' && i+1 < len(class):
+			i++
+			switch class[i] {
+			case 'w':
+				chars.WriteString("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_")
+			case 'd':
+				chars.WriteString("0123456789")
+			case 'a':
+				chars.WriteString("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+			default:
+				chars.WriteByte(class[i])
+			}
+		case i+2 < len(class) && class[i+1] == '-':
+			for c := class[i]; c <= class[i+2]; c++ {
+				chars.WriteByte(c)
+			}
+			i += 2
+		default:
+			chars.WriteByte(class[i])
+		}
+	}
+	return chars.String()
+}
+
+// resolveDeploymentConfigImages sets the images of the containers updated by the image change triggers, since the triggers
+// are lost when the deployment config is converted into a deployment
+func resolveDeploymentConfigImages(dc *okdappsv1.DeploymentConfig, imageStreams []okdimagev1.ImageStream) {
+	if dc.Spec.Template == nil {
+		return
+	}
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.Type != okdappsv1.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+		image := resolveImageReference(trigger.ImageChangeParams.From.Kind, trigger.ImageChangeParams.From.Name, imageStreams)
+		if image == "" {
+			continue
+		}
+		for i, container := range dc.Spec.Template.Spec.Containers {
+			if !common.IsStringPresent(trigger.ImageChangeParams.ContainerNames, container.Name) || !isOpenShiftBuiltImage(container.Image) {
+				continue
+			}
+			log.Debugf("Using the image %s for the container %s of the deployment config %s", image, container.Name, dc.Name)
+			dc.Spec.Template.Spec.Containers[i].Image = image
+		}
+	}
+}
+
+// isOpenShiftBuiltImage returns true if the image is empty or in the internal registry, which is the case for the images set by the triggers
+func isOpenShiftBuiltImage(image string) bool {
+	if strings.TrimSpace(image) == "" {
+		return true
+	}
+	for _, host := range openShiftRegistryHosts {
+		if strings.HasPrefix(image, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveImageReference returns the image an image stream tag refers to. The images built in the cluster keep the name of the tag.
+func resolveImageReference(kind, name string, imageStreams []okdimagev1.ImageStream) string {
+	switch kind {
+	case dockerImageKind:
+		return name
+	case imageStreamTagKind:
+		imageStreamName, tag := common.GetImageNameAndTag(name)
+		for _, imageStream := range imageStreams {
+			if imageStream.Name != imageStreamName {
+				continue
+			}
+			for _, tagReference := range imageStream.Spec.Tags {
+				if tagReference.Name == tag && tagReference.From != nil && tagReference.From.Kind == dockerImageKind {
+					return tagReference.From.Name
+				}
+			}
+		}
+		return imageStreamName + ":" + tag
+	}
+	return ""
+}
+
+// buildConfigToPipeline converts a build config building an image from a git repo into a Tekton pipeline which clones the
+// repo and builds and pushes the image using kaniko, for the Docker strategy, or s2i, for the Source strategy
+func buildConfigToPipeline(bc okdbuildv1.BuildConfig) (*v1beta1.Pipeline, bool) {
+	if bc.Spec.Source.Git == nil || bc.Spec.Output.To == nil {
+		log.Warnf("The build config %s is not converted into a pipeline, since it does not build from a git repo into an image", bc.Name)
+		return nil, false
+	}
+	image := resolveImageReference(bc.Spec.Output.To.Kind, bc.Spec.Output.To.Name, nil)
+	if image == "" {
+		log.Warnf("The build config %s is not converted into a pipeline, since its output %s %s is not supported", bc.Name, bc.Spec.Output.To.Kind, bc.Spec.Output.To.Name)
+		return nil, false
+	}
+	if ref := common.ParseImageReference(image); ref.Registry != "" && isOpenShiftBuiltImage(image) {
+		// The images pushed to the internal registry are pushed to the registry given to the pipeline instead
+		image = strings.TrimPrefix(image, ref.Registry+"/")
+	}
+	ref := bc.Spec.Source.Git.Ref
+	if ref == "" {
+		ref = defaultOpenShiftGitRef
+	}
+	contextDir := bc.Spec.Source.ContextDir
+	if contextDir == "" {
+		contextDir = "."
+	}
+	cloneTask := v1beta1.PipelineTask{
+		Name:       "clone",
+		TaskRef:    &v1beta1.TaskRef{Name: "git-clone"},
+		Workspaces: []v1beta1.WorkspacePipelineTaskBinding{{Name: "output", Workspace: openShiftPipelineWorkspace}},
+		Params: []v1beta1.Param{
+			{Name: "url", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: bc.Spec.Source.Git.URI}},
+			{Name: "revision", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: ref}},
+			{Name: "deleteExisting", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "true"}},
+		},
+	}
+	imageParam := v1beta1.Param{Name: "IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: "$(params.image-registry-url)/" + image}}
+	buildPushTask := v1beta1.PipelineTask{
+		Name:     "build-push",
+		RunAfter: []string{cloneTask.Name},
+	}
+	switch {
+	case bc.Spec.Strategy.DockerStrategy != nil:
+		dockerfilePath := bc.Spec.Strategy.DockerStrategy.DockerfilePath
+		if dockerfilePath == "" {
+			dockerfilePath = defaultDockerfilePath
+		}
+		buildPushTask.TaskRef = &v1beta1.TaskRef{Name: "kaniko"}
+		buildPushTask.Workspaces = []v1beta1.WorkspacePipelineTaskBinding{{Name: "source", Workspace: openShiftPipelineWorkspace}}
+		buildPushTask.Params = []v1beta1.Param{
+			imageParam,
+			{Name: "DOCKERFILE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: filepath.ToSlash(filepath.Join(contextDir, dockerfilePath))}},
+			{Name: "CONTEXT", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: contextDir}},
+		}
+	case bc.Spec.Strategy.SourceStrategy != nil:
+		builderImage := resolveImageReference(bc.Spec.Strategy.SourceStrategy.From.Kind, bc.Spec.Strategy.SourceStrategy.From.Name, nil)
+		buildPushTask.TaskRef = &v1beta1.TaskRef{Name: "s2i"}
+		buildPushTask.Workspaces = []v1beta1.WorkspacePipelineTaskBinding{{Name: "source", Workspace: openShiftPipelineWorkspace}}
+		buildPushTask.Params = []v1beta1.Param{
+			imageParam,
+			{Name: "BUILDER_IMAGE", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: builderImage}},
+			{Name: "PATH_CONTEXT", Value: v1beta1.ArrayOrString{Type: v1beta1.ParamTypeString, StringVal: contextDir}},
+		}
+	default:
+		log.Warnf("The build config %s is not converted into a pipeline, since its strategy %s is not supported", bc.Name, bc.Spec.Strategy.Type)
+		return nil, false
+	}
+	pipeline := new(v1beta1.Pipeline)
+	pipeline.TypeMeta = metav1.TypeMeta{Kind: "Pipeline", APIVersion: v1beta1.SchemeGroupVersion.String()}
+	pipeline.ObjectMeta = metav1.ObjectMeta{Name: bc.Name, Namespace: bc.Namespace, Labels: bc.Labels}
+	pipeline.Spec.Params = []v1beta1.ParamSpec{
+		{Name: "image-registry-url", Description: "registry-domain/namespace where the output image should be pushed.", Type: v1beta1.ParamTypeString},
+	}
+	pipeline.Spec.Workspaces = []v1beta1.PipelineWorkspaceDeclaration{
+		{Name: openShiftPipelineWorkspace, Description: "This workspace will receive the cloned git repo and be passed to the task building the image."},
+	}
+	pipeline.Spec.Tasks = []v1beta1.PipelineTask{cloneTask, buildPushTask}
+	log.Debugf("Converted the build config %s into a pipeline", bc.Name)
+	return pipeline, true
+}
@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	dockerBuildEngine = "docker"
+	podmanBuildEngine = "podman"
+	// buildOutputLines is the number of lines of the output of a failed build which are logged
+	buildOutputLines = 20
+)
+
+// buildImages runs the build scripts of the new images in the output directory, and returns the digests of the images which were built
+func buildImages(containers []irtypes.Container, outputPath string) []outputtypes.ManifestImage {
+	images := []outputtypes.ManifestImage{}
+	engine, cleanup, err := getBuildEngine()
+	if err != nil {
+		common.ReportError(common.NewError(common.ToolNotFoundErrorCode, err, "Unable to build the images, since neither %s nor %s was found.", dockerBuildEngine, podmanBuildEngine))
+		return images
+	}
+	defer cleanup()
+	for _, container := range containers {
+		scripts := getImageBuildScripts(container, outputPath)
+		if len(scripts) == 0 {
+			continue
+		}
+		imageName := container.ImageNames[0]
+		log.Infof("Building the image %s using %s", imageName, engine)
+		endRegion := common.TraceRegion("build " + imageName)
+		built := true
+		for _, script := range scripts {
+			output, err := common.RunCommandCombinedOutput(filepath.Dir(script), script)
+			if err != nil {
+				log.Warnf("The last lines of the output of the build script %s :\n%s", script, getLastLines(string(output), buildOutputLines))
+				relPath, _ := filepath.Rel(outputPath, script)
+				common.ReportError(common.NewError(common.ImageBuildFailedErrorCode, err, "Failed to build the image %s using the script %s .", imageName, filepath.ToSlash(relPath)))
+				built = false
+				break
+			}
+		}
+		endRegion()
+		if !built {
+			continue
+		}
+		digest, err := getImageDigest(engine, imageName)
+		if err != nil {
+			log.Warnf("Failed to get the digest of the image %s . Error: %q", imageName, err)
+			continue
+		}
+		log.Infof("Built the image %s with the digest %s", imageName, digest)
+		images = append(images, outputtypes.ManifestImage{Name: imageName, Digest: digest})
+	}
+	return images
+}
+
+// getBuildEngine returns the container engine used to build the images. Since the build scripts invoke docker, podman is
+// used through a docker shim added to the PATH when docker is not installed. The cleanup function removes the shim.
+func getBuildEngine() (engine string, cleanup func(), err error) {
+	cleanup = func() {}
+	if _, err := exec.LookPath(dockerBuildEngine); err == nil {
+		return dockerBuildEngine, cleanup, nil
+	}
+	podmanPath, err := exec.LookPath(podmanBuildEngine)
+	if err != nil {
+		return "", cleanup, err
+	}
+	shimDir, err := ioutil.TempDir("", common.TempDirPrefix+"docker-shim-")
+	if err != nil {
+		return "", cleanup, err
+	}
+	if err := os.Symlink(podmanPath, filepath.Join(shimDir, dockerBuildEngine)); err != nil {
+		os.RemoveAll(shimDir)
+		return "", cleanup, err
+	}
+	path := os.Getenv("PATH")
+	if err := os.Setenv("PATH", shimDir+string(os.PathListSeparator)+path); err != nil {
+		os.RemoveAll(shimDir)
+		return "", cleanup, err
+	}
+	cleanup = func() {
+		if err := os.Setenv("PATH", path); err != nil {
+			log.Warnf("Failed to restore the PATH environment variable. Error: %q", err)
+		}
+		os.RemoveAll(shimDir)
+	}
+	return podmanBuildEngine, cleanup, nil
+}
+
+// getImageBuildScripts returns the paths of the build scripts of a new image in the output directory
+func getImageBuildScripts(container irtypes.Container, outputPath string) []string {
+	scripts := []string{}
+	if !container.New || len(container.ImageNames) == 0 {
+		return scripts
+	}
+	for relPath := range container.NewFiles {
+		if filepath.Ext(relPath) == ".sh" {
+			scripts = append(scripts, filepath.Join(outputPath, common.SourceDir, relPath))
+		}
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// getImageDigest returns the digest of the config of the local image, which is its ID
+func getImageDigest(engine, imageName string) (string, error) {
+	output, err := common.RunCommand("", engine, "image", "inspect", "--format", "{{.Id}}", imageName)
+	if err != nil {
+		return "", err
+	}
+	digest := strings.TrimSpace(string(output))
+	if !strings.HasPrefix(digest, "sha256:") {
+		// podman prints the ID without the algorithm
+		digest = "sha256:" + digest
+	}
+	return digest, nil
+}
+
+func getLastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/common/deepcopy"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	"github.com/konveyor/move2kube/internal/metadata"
+	optimize "github.com/konveyor/move2kube/internal/optimizer"
+	"github.com/konveyor/move2kube/internal/source"
+	transform "github.com/konveyor/move2kube/internal/transformer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+// TargetCompatibility has the compatibility of the services of a plan with a target cluster type
+type TargetCompatibility struct {
+	Target   string
+	Services map[string]transform.ServiceCompatibility
+}
+
+// CheckTargetCompatibility translates the plan to the intermediate representation once, without writing any artifacts,
+// and checks the objects generated from it for each of the target cluster types
+func CheckTargetCompatibility(plan plantypes.Plan, targets []string) ([]TargetCompatibility, error) {
+	clusters := new(metadata.ClusterMDLoader).GetClusters(plan)
+	for _, target := range targets {
+		if _, ok := clusters[target]; !ok {
+			clusterNames := []string{}
+			for clusterName := range clusters {
+				clusterNames = append(clusterNames, clusterName)
+			}
+			sort.Strings(clusterNames)
+			return nil, fmt.Errorf("the target cluster type %s was not found. Valid types are %s", target, strings.Join(clusterNames, ", "))
+		}
+	}
+	containerBuildTypes := []string{}
+	for _, services := range plan.Spec.Inputs.Services {
+		if len(services) > 0 && !common.IsStringPresent(containerBuildTypes, string(services[0].ContainerBuildType)) {
+			containerBuildTypes = append(containerBuildTypes, string(services[0].ContainerBuildType))
+		}
+	}
+	// The output stage is not initialized, so the containerizers do not write any files
+	containerizer.InitContainerizers(plan.Spec.Inputs.RootDir, containerBuildTypes)
+	sourceIR, err := source.Translate(plan)
+	if err != nil {
+		return nil, err
+	}
+	compatibilities := []TargetCompatibility{}
+	for _, target := range targets {
+		// The resources used as source are loaded for each target, since the ones the target does not support are converted
+		targetIR := deepcopy.DeepCopy(sourceIR).(irtypes.IR)
+		targetIR.TargetClusterSpec = clusters[target].Spec
+		for _, metadataLoader := range metadata.GetLoaders() {
+			if _, ok := metadataLoader.(*metadata.ClusterMDLoader); ok {
+				continue
+			}
+			if err := metadataLoader.LoadToIR(plan, &targetIR); err != nil {
+				log.Warnf("Metadata loader [%T] failed. Error: %q", metadataLoader, err)
+			}
+		}
+		optimizedIR, err := optimize.Optimize(targetIR)
+		if err != nil {
+			log.Errorf("Error occurred while running the optimizers. Error: %q", err)
+			optimizedIR = targetIR
+		}
+		compatibilities = append(compatibilities, TargetCompatibility{Target: target, Services: transform.CheckCompatibility(optimizedIR)})
+	}
+	return compatibilities, nil
+}
+
+// GetCompatibilityMatrix returns a markdown table with a row for each service and a column for each target,
+// followed by the changes each service needs for each target
+func GetCompatibilityMatrix(compatibilities []TargetCompatibility) string {
+	serviceNames := []string{}
+	for _, compatibility := range compatibilities {
+		for serviceName := range compatibility.Services {
+			if !common.IsStringPresent(serviceNames, serviceName) {
+				serviceNames = append(serviceNames, serviceName)
+			}
+		}
+	}
+	sort.Strings(serviceNames)
+	getServiceTitle := func(serviceName string) string {
+		if serviceName == "" {
+			return "(other objects)"
+		}
+		return serviceName
+	}
+	matrix := strings.Builder{}
+	matrix.WriteString("| Service |")
+	for _, compatibility := range compatibilities {
+		matrix.WriteString(fmt.Sprintf(" %s |", compatibility.Target))
+	}
+	matrix.WriteString("\n|---|")
+	for range compatibilities {
+		matrix.WriteString("---|")
+	}
+	matrix.WriteString("\n")
+	for _, serviceName := range serviceNames {
+		matrix.WriteString(fmt.Sprintf("| %s |", getServiceTitle(serviceName)))
+		for _, compatibility := range compatibilities {
+			serviceCompatibility, ok := compatibility.Services[serviceName]
+			switch {
+			case !ok:
+				matrix.WriteString(" - |")
+			case serviceCompatibility.IsCompatible():
+				matrix.WriteString(fmt.Sprintf(" ok (%s) |", strings.Join(serviceCompatibility.Kinds, ", ")))
+			default:
+				matrix.WriteString(fmt.Sprintf(" %d changes (%s) |", len(serviceCompatibility.Incompatibilities), strings.Join(serviceCompatibility.Kinds, ", ")))
+			}
+		}
+		matrix.WriteString("\n")
+	}
+	for _, compatibility := range compatibilities {
+		changes := []string{}
+		for _, serviceName := range serviceNames {
+			for _, incompatibility := range compatibility.Services[serviceName].Incompatibilities {
+				changes = append(changes, fmt.Sprintf("- %s : %s\n", getServiceTitle(serviceName), incompatibility))
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		matrix.WriteString(fmt.Sprintf("\n## %s\n\n", compatibility.Target))
+		matrix.WriteString(strings.Join(changes, ""))
+	}
+	return matrix.String()
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/metadata"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+// serviceKeySegments are the segments of the QA keys that are asked for each service
+var serviceKeySegments = []string{
+	"containerization" + common.Delim + "type",
+	"containerization" + common.Delim + "target",
+	"urlpath",
+	common.ConfigBuildStepsKeySegment + common.Delim + "services",
+	common.ConfigBuildStepsKeySegment + common.Delim + "composition",
+	common.ConfigStaticSiteKeySegment + common.Delim + "serving",
+	common.ConfigStaticSiteKeySegment + common.Delim + "bucketurl",
+	common.ConfigStaticSiteKeySegment + common.Delim + "backend",
+	common.ConfigProtocolKeySegment,
+	common.ConfigPortExposureKeySegment,
+	common.ConfigOnErrorKeySegment,
+	common.ConfigTierKeySegment,
+	common.ConfigTerminationGracePeriodKeySegment,
+	common.ConfigPreStopSleepKeySegment,
+	common.ConfigCronJobsKeySegment,
+	common.ConfigProcessesKeySegment,
+	common.ConfigSessionsKeySegment,
+	common.ConfigEnvKeySegment,
+	common.ConfigSecretEnvKeySegment,
+	common.ConfigDaprKeySegment,
+}
+
+// globalKeys are the QA keys that are not specific to a service or a storage
+var globalKeys = []string{
+	common.ConfigServicesNamesKey,
+	common.ConfigServicesExposeKey,
+	common.ConfigServicesBuildStepsKey,
+	common.ConfigContainerizationTypesKey,
+	common.ConfigSourceTypesKey,
+	common.ConfigK8sSourceKindsKey,
+	common.ConfigK8sSourceNamespacesKey,
+	common.ConfigK8sSourceScrubRulesKey,
+	common.ConfigTargetClusterTypeKey,
+	common.ConfigIngressHostKey,
+	common.ConfigIngressTLSKey,
+	common.ConfigIngressControllerKey,
+	common.ConfigIngressTimeoutKey,
+	common.ConfigIngressBodySizeKey,
+	common.ConfigGatewayEnableKey,
+	common.ConfigGatewayClassNameKey,
+	common.ConfigGatewayModeKey,
+	common.ConfigRolloutsStrategyKey,
+	common.ConfigRolloutsMetricsPathKey,
+	common.ConfigImageRegistryURLKey,
+	common.ConfigImageRegistryNamespaceKey,
+	common.ConfigImageRegistryLoginTypeKey,
+	common.ConfigImageRegistryPullSecretKey,
+	common.ConfigImageRegistryUserNameKey,
+	common.ConfigImageRegistryPasswordKey,
+	common.ConfigImageRegistryRewriteKey,
+	common.ConfigImageRegistryPreserveDigestsKey,
+	common.ConfigStoragesPerClaimStorageClassKey,
+	common.ConfigStoragesMigrateKey,
+	common.ConfigStoragesImmutableConfigMapsKey,
+	common.ConfigSessionStoreImageKey,
+	common.ConfigSchedulingEnableKey,
+	common.ConfigTargetTimezoneKey,
+	common.ConfigTargetLocaleKey,
+	common.ConfigDaprComponentsKey,
+	common.ConfigDevExperienceToolsKey,
+	common.ConfigRepoLoadPubDomainsKey,
+	common.ConfigRepoLoadPubKey,
+	common.ConfigRepoLoadPrivKey,
+	common.ConfigRepoKeyPathsKey,
+}
+
+// readPlanForCompletion reads the plan file. A missing or invalid plan is not an error since completions are best effort.
+func readPlanForCompletion(planPath string) (plantypes.Plan, bool) {
+	plan := plantypes.NewPlan()
+	if err := common.ReadYaml(planPath, &plan); err != nil {
+		return plan, false
+	}
+	return plan, true
+}
+
+// GetServiceNamesForCompletion returns the names of the services in the plan file, if it exists
+func GetServiceNamesForCompletion(planPath string) []string {
+	plan, ok := readPlanForCompletion(planPath)
+	if !ok {
+		return nil
+	}
+	serviceNames := []string{}
+	for serviceName := range plan.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	return serviceNames
+}
+
+// GetQAKeysForCompletion returns the QA keys that can be set using the config flags, including the keys of the services in the plan file
+func GetQAKeysForCompletion(planPath string) []string {
+	keys := append([]string{}, globalKeys...)
+	for _, serviceName := range GetServiceNamesForCompletion(planPath) {
+		for _, segment := range serviceKeySegments {
+			keys = append(keys, common.ConfigServicesKey+common.Delim+`"`+serviceName+`"`+common.Delim+segment)
+		}
+	}
+	return keys
+}
+
+// GetClusterTypesForCompletion returns the names of the built-in cluster profiles and the clusters collected into the plan
+func GetClusterTypesForCompletion(planPath string) []string {
+	plan, _ := readPlanForCompletion(planPath)
+	clusterTypes := []string{}
+	for name := range new(metadata.ClusterMDLoader).GetClusters(plan) {
+		clusterTypes = append(clusterTypes, name)
+	}
+	sort.Strings(clusterTypes)
+	return clusterTypes
+}
+
+// GetPresetsForCompletion returns the names of the preset configs
+func GetPresetsForCompletion() []string {
+	presetPaths, err := filepath.Glob(filepath.Join(common.AssetsPath, "configs", "*.yaml"))
+	if err != nil {
+		return nil
+	}
+	presets := []string{}
+	for _, presetPath := range presetPaths {
+		presets = append(presets, strings.TrimSuffix(filepath.Base(presetPath), ".yaml"))
+	}
+	return presets
+}
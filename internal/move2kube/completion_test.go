@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetQAKeysForCompletion(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "m2k-completion-test-")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory. Error: %q", err)
+	}
+	defer os.RemoveAll(tempDir)
+	planPath := filepath.Join(tempDir, common.DefaultPlanFile)
+	plan := plantypes.NewPlan()
+	plan.Spec.Inputs.Services["web"] = []plantypes.Service{{ServiceName: "web"}}
+	plan.Spec.Inputs.Services["db"] = []plantypes.Service{{ServiceName: "db"}}
+	if err := common.WriteYaml(planPath, plan); err != nil {
+		t.Fatalf("Failed to write the plan file. Error: %q", err)
+	}
+
+	serviceNames := move2kube.GetServiceNamesForCompletion(planPath)
+	if len(serviceNames) != 2 || serviceNames[0] != "db" || serviceNames[1] != "web" {
+		t.Fatalf("Expected the services [db web] . Actual: %v", serviceNames)
+	}
+	urlPathKey := common.ConfigServicesKey + common.Delim + `"web"` + common.Delim + "urlpath"
+	keys := move2kube.GetQAKeysForCompletion(planPath)
+	found := map[string]bool{}
+	for _, key := range keys {
+		found[key] = true
+	}
+	for _, key := range []string{common.ConfigTargetClusterTypeKey, urlPathKey} {
+		if !found[key] {
+			t.Errorf("Expected the key %s in the completions %v", key, keys)
+		}
+	}
+
+	if keys := move2kube.GetQAKeysForCompletion(filepath.Join(tempDir, "missing.plan")); len(keys) == 0 {
+		t.Fatalf("Expected the global keys to be completed even if the plan file is missing")
+	}
+}
@@ -0,0 +1,373 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/types/argorollouts"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultDeployTimeout is the default maximum time to wait for each deployed workload to become ready
+	DefaultDeployTimeout = 5 * time.Minute
+	// deployCommandTimeoutMargin is added to the deploy timeout for the commands waiting for the workloads, so that
+	// they report why a workload is not ready before they are killed
+	deployCommandTimeoutMargin = time.Minute
+
+	readyDeployStatus    = "Ready"
+	notReadyDeployStatus = "NotReady"
+)
+
+// DeployOptions configures how the artifacts are deployed
+type DeployOptions struct {
+	// Context is the kubeconfig context of the target cluster. The current context is used if it is empty.
+	Context string
+	// Namespace is the namespace of the resources which do not specify one. The namespace of the context is used if it is empty.
+	Namespace string
+	// Helm installs the helm chart instead of applying the yamls
+	Helm bool
+	// Build builds and pushes the new images instead of verifying that they exist in their registries
+	Build bool
+	// RegistryURL and RegistryNamespace override the registry the new images are pushed to
+	RegistryURL       string
+	RegistryNamespace string
+	// Timeout is the maximum time to wait for each workload to become ready
+	Timeout time.Duration
+}
+
+// deployWorkload is a deployed resource whose readiness is waited for
+type deployWorkload struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// deployStatus is the readiness of a deployed workload
+type deployStatus struct {
+	deployWorkload
+	Status  string
+	Message string
+}
+
+// podSpec contains the part of a pod spec needed to find the images
+type podSpec struct {
+	InitContainers []struct {
+		Image string `yaml:"image"`
+	} `yaml:"initContainers"`
+	Containers []struct {
+		Image string `yaml:"image"`
+	} `yaml:"containers"`
+}
+
+type podTemplate struct {
+	Spec podSpec `yaml:"spec"`
+}
+
+// deployResource contains the fields of a kubernetes resource needed to deploy it
+type deployResource struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		podSpec     `yaml:",inline"`
+		Template    podTemplate `yaml:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template podTemplate `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+// Deploy builds and pushes the new images, or verifies that they exist, then applies the yamls or installs the helm
+// chart in the artifacts directory, waits for the workloads to become ready and prints their status to the writer
+func Deploy(artifactsPath string, options DeployOptions, w io.Writer) error {
+	manifest, err := outputtypes.ReadManifest(artifactsPath)
+	if err != nil {
+		return fmt.Errorf("the directory %s is not a complete output of move2kube, since it has no %s file. Error: %q", artifactsPath, common.ManifestFile, err)
+	}
+	if diff, err := manifest.Verify(artifactsPath); err != nil {
+		log.Warnf("Failed to verify the artifacts in the directory %s . Error: %q", artifactsPath, err)
+	} else if len(diff.Missing) > 0 {
+		log.Warnf("The files %s of the output are missing. The deployment might be incomplete.", strings.Join(diff.Missing, ", "))
+	}
+	yamlsPath := filepath.Join(artifactsPath, common.DeployDir, "yamls")
+	workloads, images, err := getDeployResources(yamlsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the yamls in the directory %s . Error: %q", yamlsPath, err)
+	}
+	tool := "kubectl"
+	if options.Helm {
+		tool = "helm"
+	}
+	for _, name := range []string{tool, "kubectl"} {
+		if _, err := exec.LookPath(name); err != nil {
+			err := common.NewError(common.ToolNotFoundErrorCode, err, "Unable to deploy the artifacts, since %s was not found.", name)
+			common.ReportError(err)
+			return err
+		}
+	}
+
+	if options.Build {
+		if err := buildAndPushImages(artifactsPath, options.RegistryURL, options.RegistryNamespace, getDeployCommandTimeout(options)); err != nil {
+			return err
+		}
+	} else if err := verifyImages(images); err != nil {
+		return err
+	}
+
+	if options.Helm {
+		chartPath := filepath.Join(artifactsPath, common.DeployDir, common.HelmDir, manifest.Name)
+		log.Infof("Installing the helm chart %s", chartPath)
+		args := append([]string{"upgrade", "-i", manifest.Name, chartPath, "--wait", "--timeout", options.Timeout.String()}, getKubeFlags("--kube-context", options.Context, options.Namespace)...)
+		if output, err := common.RunCommandCombinedOutputWithTimeout(getDeployCommandTimeout(options), artifactsPath, "helm", args...); err != nil {
+			log.Errorf("Failed to install the helm chart. Output:\n%s", string(output))
+			err := common.NewError(common.ClusterAccessFailedErrorCode, err, "Unable to install the helm chart %s .", chartPath)
+			common.ReportError(err)
+			return err
+		}
+	} else {
+		log.Infof("Applying the yamls in the directory %s", yamlsPath)
+		args := append([]string{"apply", "-f", yamlsPath}, getKubeFlags("--context", options.Context, options.Namespace)...)
+		if output, err := common.RunCommandCombinedOutput(artifactsPath, "kubectl", args...); err != nil {
+			log.Errorf("Failed to apply the yamls. Output:\n%s", string(output))
+			err := common.NewError(common.ClusterAccessFailedErrorCode, err, "Unable to apply the yamls in the directory %s .", yamlsPath)
+			common.ReportError(err)
+			return err
+		}
+	}
+
+	statuses := waitForWorkloads(workloads, options)
+	printDeployStatus(w, statuses)
+	notReady := []string{}
+	for _, status := range statuses {
+		if status.Status != readyDeployStatus {
+			notReady = append(notReady, strings.ToLower(status.Kind)+"/"+status.Name)
+			common.ReportError(common.NewError(common.DeploymentNotReadyErrorCode, fmt.Errorf("%s", status.Message), "The %s %s did not become ready within %s .", status.Kind, status.Name, options.Timeout))
+		}
+	}
+	if len(notReady) > 0 {
+		return fmt.Errorf("the workloads %s did not become ready", strings.Join(notReady, ", "))
+	}
+	return nil
+}
+
+// getDeployResources returns the workloads whose readiness can be waited for, and the images used by the yamls in the directory
+func getDeployResources(yamlsPath string) ([]deployWorkload, []string, error) {
+	workloads := []deployWorkload{}
+	imagesSet := map[string]bool{}
+	files, err := ioutil.ReadDir(yamlsPath)
+	if err != nil {
+		return workloads, nil, err
+	}
+	for _, file := range files {
+		if file.IsDir() || (filepath.Ext(file.Name()) != ".yaml" && filepath.Ext(file.Name()) != ".yml") {
+			continue
+		}
+		path := filepath.Join(yamlsPath, file.Name())
+		err := common.StreamYAMLFile(path, func(doc []byte) error {
+			resource := deployResource{}
+			if err := yaml.Unmarshal(doc, &resource); err != nil {
+				log.Debugf("Unable to parse a document of the file %s . Error: %q", path, err)
+				return nil
+			}
+			for _, spec := range []podSpec{resource.Spec.podSpec, resource.Spec.Template.Spec, resource.Spec.JobTemplate.Spec.Template.Spec} {
+				for _, container := range append(spec.InitContainers, spec.Containers...) {
+					if container.Image != "" {
+						imagesSet[container.Image] = true
+					}
+				}
+			}
+			switch resource.Kind {
+			case "Deployment", "StatefulSet", "DaemonSet", "Job", argorollouts.RolloutKind:
+				workloads = append(workloads, deployWorkload{Kind: resource.Kind, Name: resource.Metadata.Name, Namespace: resource.Metadata.Namespace})
+			}
+			return nil
+		})
+		if err != nil {
+			return workloads, nil, err
+		}
+	}
+	sort.SliceStable(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	images := []string{}
+	for image := range imagesSet {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return workloads, images, nil
+}
+
+// getDeployCommandTimeout returns the timeout of the commands which build the images or wait for the workloads, which is
+// the deploy timeout plus a margin. It is never shorter than the timeout of the other external tools.
+func getDeployCommandTimeout(options DeployOptions) time.Duration {
+	if common.CommandTimeout <= 0 {
+		return common.CommandTimeout
+	}
+	if timeout := options.Timeout + deployCommandTimeoutMargin; timeout > common.CommandTimeout {
+		return timeout
+	}
+	return common.CommandTimeout
+}
+
+// getKubeFlags returns the flags of kubectl or helm selecting the context and the namespace
+func getKubeFlags(contextFlag, context, namespace string) []string {
+	flags := []string{}
+	if context != "" {
+		flags = append(flags, contextFlag, context)
+	}
+	if namespace != "" {
+		flags = append(flags, "--namespace", namespace)
+	}
+	return flags
+}
+
+// buildAndPushImages runs the scripts in the output directory which build the new images and push them to the registry
+func buildAndPushImages(artifactsPath, registryURL, registryNamespace string, timeout time.Duration) error {
+	scriptsPath := filepath.Join(artifactsPath, common.ScriptsDir)
+	buildScript := filepath.Join(scriptsPath, "buildimages.sh")
+	pushScript := filepath.Join(scriptsPath, "pushimages.sh")
+	if _, err := os.Stat(buildScript); os.IsNotExist(err) {
+		log.Infof("There are no new images to build")
+		return nil
+	}
+	engine, cleanup, err := getBuildEngine()
+	if err != nil {
+		err := common.NewError(common.ToolNotFoundErrorCode, err, "Unable to build the images, since neither %s nor %s was found.", dockerBuildEngine, podmanBuildEngine)
+		common.ReportError(err)
+		return err
+	}
+	defer cleanup()
+	log.Infof("Building the images using %s", engine)
+	if output, err := common.RunCommandCombinedOutputWithTimeout(timeout, scriptsPath, buildScript); err != nil {
+		log.Errorf("The last lines of the output of the build script %s :\n%s", buildScript, getLastLines(string(output), buildOutputLines))
+		err := common.NewError(common.ImageBuildFailedErrorCode, err, "Failed to build the images using the script %s .", buildScript)
+		common.ReportError(err)
+		return err
+	}
+	if _, err := os.Stat(pushScript); os.IsNotExist(err) {
+		return nil
+	}
+	args := []string{}
+	if registryURL != "" && registryNamespace != "" {
+		args = append(args, registryURL, registryNamespace)
+	}
+	log.Infof("Pushing the images using %s", engine)
+	if output, err := common.RunCommandCombinedOutputWithTimeout(timeout, scriptsPath, pushScript, args...); err != nil {
+		log.Errorf("The last lines of the output of the push script %s :\n%s", pushScript, getLastLines(string(output), buildOutputLines))
+		err := common.NewError(common.RegistryAuthMissingErrorCode, err, "Failed to push the images using the script %s .", pushScript)
+		common.ReportError(err)
+		return err
+	}
+	return nil
+}
+
+// verifyImages checks that the images exist in their registries, without pulling them
+func verifyImages(images []string) error {
+	if len(images) == 0 {
+		return nil
+	}
+	engine := dockerBuildEngine
+	if _, err := exec.LookPath(engine); err != nil {
+		engine = podmanBuildEngine
+		if _, err := exec.LookPath(engine); err != nil {
+			log.Warnf("Unable to verify that the images exist, since neither %s nor %s was found.", dockerBuildEngine, podmanBuildEngine)
+			return nil
+		}
+	}
+	missing := []string{}
+	for _, image := range images {
+		if output, err := common.RunCommandCombinedOutput("", engine, "manifest", "inspect", image); err != nil {
+			log.Debugf("Unable to inspect the image %s . Output:\n%s", image, string(output))
+			common.ReportError(common.NewError(common.ImageNotFoundErrorCode, err, "The image %s was not found in its registry.", image))
+			missing = append(missing, image)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the images %s were not found", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// waitForWorkloads waits concurrently for the workloads to become ready and returns their status
+func waitForWorkloads(workloads []deployWorkload, options DeployOptions) []deployStatus {
+	statuses := make([]deployStatus, len(workloads))
+	common.RunConcurrently(len(workloads), len(workloads), func(i int) {
+		workload := workloads[i]
+		namespace := workload.Namespace
+		if namespace == "" {
+			namespace = options.Namespace
+		}
+		resource := strings.ToLower(workload.Kind) + "/" + workload.Name
+		timeout := "--timeout=" + options.Timeout.String()
+		args := []string{"rollout", "status", resource, timeout}
+		switch workload.Kind {
+		case "Job":
+			args = []string{"wait", "--for=condition=complete", resource, timeout}
+		case argorollouts.RolloutKind:
+			// kubectl rollout status does not support the Argo Rollouts
+			resource = "rollouts." + argorollouts.SchemeGroupVersion.Group + "/" + workload.Name
+			args = []string{"wait", "--for=condition=Available", resource, timeout}
+		}
+		args = append(args, getKubeFlags("--context", options.Context, namespace)...)
+		status := deployStatus{deployWorkload: workload, Status: readyDeployStatus}
+		output, err := common.RunCommandCombinedOutputWithTimeout(getDeployCommandTimeout(options), "", "kubectl", args...)
+		status.Message = getLastLines(strings.TrimSpace(string(output)), 1)
+		if err != nil {
+			status.Status = notReadyDeployStatus
+			if status.Message == "" {
+				status.Message = err.Error()
+			}
+		}
+		statuses[i] = status
+	})
+	return statuses
+}
+
+// printDeployStatus writes a table of the status of the workloads
+func printDeployStatus(w io.Writer, statuses []deployStatus) {
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No workloads were deployed.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tSTATUS\tMESSAGE")
+	for _, status := range statuses {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", status.Kind, status.Name, status.Status, status.Message)
+	}
+	tw.Flush()
+}
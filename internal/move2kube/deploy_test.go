@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+)
+
+const testDeployYamls = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: quay.io/myns/web:latest
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      containers:
+        - name: migrate
+          image: quay.io/myns/migrate:latest
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: canary
+spec:
+  template:
+    spec:
+      containers:
+        - name: canary
+          image: quay.io/myns/canary:latest
+`
+
+func TestDeploy(t *testing.T) {
+	if err := move2kube.Deploy(t.TempDir(), move2kube.DeployOptions{Timeout: time.Minute}, ioutil.Discard); err == nil {
+		t.Fatalf("Expected an error for a directory without a manifest")
+	}
+
+	// fake kubectl and docker, which record their arguments. The job never completes, and the rollout status of the deployment
+	// takes longer than the timeout of the external tools.
+	binPath := t.TempDir()
+	logPath := filepath.Join(binPath, "calls.log")
+	tools := map[string]string{
+		"kubectl": "#!/bin/sh\necho kubectl \"$@\" >> " + logPath + "\ncase \"$*\" in\n*job/*) echo 'error: timed out waiting for the condition'; exit 1;;\nrollout*) sleep 1;;\nesac\necho done\n",
+		"docker":  "#!/bin/sh\necho docker \"$@\" >> " + logPath + "\n",
+	}
+	for name, script := range tools {
+		if err := ioutil.WriteFile(filepath.Join(binPath, name), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write the fake %s. Error: %q", name, err)
+		}
+	}
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", binPath+string(os.PathListSeparator)+path)
+	retries := common.CommandRetries
+	defer func() { common.CommandRetries = retries }()
+	common.CommandRetries = 0
+	defer func() { common.CommandTimeout = common.DefaultCommandTimeout }()
+	common.CommandTimeout = 500 * time.Millisecond
+
+	artifactsPath := t.TempDir()
+	yamlsPath := filepath.Join(artifactsPath, common.DeployDir, "yamls")
+	if err := os.MkdirAll(yamlsPath, common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Failed to create the yamls directory. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(yamlsPath, "app.yaml"), []byte(testDeployYamls), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the yamls. Error: %q", err)
+	}
+	if err := outputtypes.WriteManifest(artifactsPath, outputtypes.NewManifest("myproject", "v0.1.0")); err != nil {
+		t.Fatalf("Failed to write the manifest. Error: %q", err)
+	}
+
+	out := bytes.Buffer{}
+	err := move2kube.Deploy(artifactsPath, move2kube.DeployOptions{Context: "staging", Timeout: time.Minute}, &out)
+	if err == nil || !strings.Contains(err.Error(), "job/migrate") {
+		t.Fatalf("Expected an error for the job which did not complete. Actual: %v", err)
+	}
+	calls, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read the calls of the fake tools. Error: %q", err)
+	}
+	for _, call := range []string{
+		"docker manifest inspect quay.io/myns/canary:latest",
+		"docker manifest inspect quay.io/myns/migrate:latest",
+		"docker manifest inspect quay.io/myns/web:latest",
+		"kubectl apply -f " + yamlsPath + " --context staging",
+		"kubectl rollout status deployment/web --timeout=1m0s --context staging",
+		"kubectl wait --for=condition=complete job/migrate --timeout=1m0s --context staging",
+		"kubectl wait --for=condition=Available rollouts.argoproj.io/canary --timeout=1m0s --context staging",
+	} {
+		if !strings.Contains(string(calls), call+"\n") {
+			t.Fatalf("Expected the call [%s]. Actual:\n%s", call, string(calls))
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "KIND") || !strings.Contains(lines[1], "Deployment  web      Ready") || !strings.Contains(lines[2], "Job         migrate  NotReady  error: timed out") || !strings.Contains(lines[3], "Rollout     canary   Ready") {
+		t.Fatalf("Failed to print the status of the workloads. Actual:\n%s", out.String())
+	}
+}
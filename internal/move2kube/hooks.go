@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	projecttypes "github.com/konveyor/move2kube/types/project"
+	log "github.com/sirupsen/logrus"
+)
+
+// HasHooks returns true if the project config file in the source directory has hooks for any of the phases
+func HasHooks(srcPath string, phases ...projecttypes.HookPhase) bool {
+	project, err := projecttypes.ReadProject(srcPath)
+	if err != nil {
+		return false
+	}
+	for _, phase := range phases {
+		if len(project.Spec.Hooks[phase]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RunHooks runs the hooks of the phase configured in the project config file in the source directory.
+// The arguments of the phase are appended to the command of every hook. It stops at the first hook that fails.
+func RunHooks(srcPath string, phase projecttypes.HookPhase, args ...string) error {
+	project, err := projecttypes.ReadProject(srcPath)
+	if err != nil {
+		return err
+	}
+	for _, hook := range project.Spec.Hooks[phase] {
+		if len(hook.Command) == 0 {
+			log.Warnf("Ignoring the %s hook %s since it has no command", phase, hook.Name)
+			continue
+		}
+		name := hook.Command[0]
+		if !filepath.IsAbs(name) && strings.ContainsAny(name, `/\`) {
+			name = filepath.Join(srcPath, name)
+		}
+		hookArgs := append(append([]string{}, hook.Command[1:]...), args...)
+		log.Infof("Running the %s hook %s", phase, hook.Name)
+		output, err := common.RunCommandCombinedOutput(srcPath, name, hookArgs...)
+		if len(output) > 0 {
+			log.Infof("Output of the %s hook %s :\n%s", phase, hook.Name, strings.TrimSpace(string(output)))
+		}
+		if err != nil {
+			return fmt.Errorf("the %s hook %s failed. Error: %q", phase, hook.Name, err)
+		}
+	}
+	return nil
+}
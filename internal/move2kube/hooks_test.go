@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	projecttypes "github.com/konveyor/move2kube/types/project"
+)
+
+func TestRunHooks(t *testing.T) {
+	srcPath := t.TempDir()
+	project := projecttypes.NewProject("myproject")
+	project.Spec.Hooks[projecttypes.PostPlanHookPhase] = []projecttypes.Hook{{Name: "record", Command: []string{"./record.sh"}}}
+	project.Spec.Hooks[projecttypes.PreTranslateHookPhase] = []projecttypes.Hook{{Name: "reject", Command: []string{"sh", "-c", "exit 1"}}}
+	if err := common.WriteYaml(filepath.Join(srcPath, common.ProjectConfigFile), project); err != nil {
+		t.Fatalf("Failed to write the project config file. Error: %q", err)
+	}
+	script := "#!/bin/sh\necho \"$1\" > recorded.txt\n"
+	if err := ioutil.WriteFile(filepath.Join(srcPath, "record.sh"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write the hook script. Error: %q", err)
+	}
+
+	if !move2kube.HasHooks(srcPath, projecttypes.PostPlanHookPhase) || move2kube.HasHooks(srcPath, projecttypes.PrePlanHookPhase) {
+		t.Fatalf("Expected hooks only for the configured phases")
+	}
+	if err := move2kube.RunHooks(srcPath, projecttypes.PrePlanHookPhase, srcPath); err != nil {
+		t.Fatalf("Expected no error for a phase without hooks. Error: %q", err)
+	}
+	if err := move2kube.RunHooks(srcPath, projecttypes.PostPlanHookPhase, "/tmp/m2k.plan"); err != nil {
+		t.Fatalf("Failed to run the post plan hook. Error: %q", err)
+	}
+	recorded, err := ioutil.ReadFile(filepath.Join(srcPath, "recorded.txt"))
+	if err != nil || string(recorded) != "/tmp/m2k.plan\n" {
+		t.Fatalf("Expected the hook to be run in the source directory with the plan path as the argument. Actual: %q Error: %v", string(recorded), err)
+	}
+	if err := move2kube.RunHooks(srcPath, projecttypes.PreTranslateHookPhase, "/tmp/m2k.plan", "/tmp/out"); err == nil {
+		t.Fatalf("Expected an error when a hook fails")
+	}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/k8sschema"
+	"github.com/konveyor/move2kube/internal/k8sschema/fixer"
+	"github.com/konveyor/move2kube/internal/transformer/templates"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+const (
+	// KubeUpgradeManifestsDir is the directory of the output with the rewritten manifests
+	KubeUpgradeManifestsDir = "manifests"
+	// KubeUpgradeReportFile is the deprecation report in the output
+	KubeUpgradeReportFile = "upgradereport.md"
+
+	kubeUpgradeTestsDir     = "tests"
+	kubeUpgradeValidateFile = "validate.sh"
+)
+
+// K8sAPIUpgrade is a resource of the manifests whose API is deprecated or removed in the target version of Kubernetes
+type K8sAPIUpgrade struct {
+	FilePath    string // Relative to the input directory
+	Kind        string
+	Name        string
+	APIVersion  string
+	Deprecation k8sschema.APIDeprecation
+	Removed     bool
+	// Replacement is the API version served by the target version, through the chain of replacements. It is empty if the
+	// kind was removed without a replacement.
+	Replacement string
+	// Rewritten is true if the resource was rewritten to the replacement in the output manifests
+	Rewritten bool
+	// Notes are the fields and the defaults which changed in the replacements
+	Notes []string
+}
+
+// Status describes the deprecation of the API, and what was done with the resource
+func (u K8sAPIUpgrade) Status() string {
+	switch {
+	case !u.Removed && u.Deprecation.Replacement == "":
+		return fmt.Sprintf("Deprecated in %s, and removed in %s without a replacement", u.Deprecation.DeprecatedIn, u.Deprecation.RemovedIn)
+	case !u.Removed:
+		return fmt.Sprintf("Deprecated in %s, and removed in %s. Migrate to %s", u.Deprecation.DeprecatedIn, u.Deprecation.RemovedIn, u.Deprecation.Replacement)
+	case u.Replacement == "":
+		return fmt.Sprintf("Removed in %s without a replacement. Left out of the manifests", u.Deprecation.RemovedIn)
+	case u.Rewritten:
+		return fmt.Sprintf("Removed in %s. Rewritten to %s", u.Deprecation.RemovedIn, u.Replacement)
+	default:
+		return fmt.Sprintf("Removed in %s. Rewrite it to %s manually", u.Deprecation.RemovedIn, u.Replacement)
+	}
+}
+
+// kubeUpgradeChange is the changes of the fields and the defaults of a kind in its replacement
+type kubeUpgradeChange struct {
+	Kind        string
+	APIVersion  string
+	Replacement string
+	Notes       []string
+}
+
+// UpgradeK8sManifests rewrites the yamls in the input directory, whose API versions are removed between the from and the
+// to versions of Kubernetes, into the manifests directory of the output. The documents which are not rewritten are copied
+// as they are. The deprecation report, and a script which validates the manifests against the upgraded cluster, are
+// written along with them. The containerization of the plan is not involved at all.
+func UpgradeK8sManifests(inputPath, outputPath, fromVersion, toVersion string) ([]K8sAPIUpgrade, error) {
+	from, err := semver.NewVersion(fromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("the source version %s is not a valid Kubernetes version. Error: %q", fromVersion, err)
+	}
+	to, err := semver.NewVersion(toVersion)
+	if err != nil {
+		return nil, fmt.Errorf("the target version %s is not a valid Kubernetes version. Error: %q", toVersion, err)
+	}
+	if !to.GreaterThan(from) {
+		return nil, fmt.Errorf("the target version %s is not newer than the source version %s", toVersion, fromVersion)
+	}
+	filePaths, err := common.GetFilesByExt(inputPath, []string{".yml", ".yaml"})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filePaths)
+	codecs := serializer.NewCodecFactory(k8sschema.GetSchema())
+	upgrades := []K8sAPIUpgrade{}
+	apiVersions := []string{}
+	for _, filePath := range filePaths {
+		relPath, err := filepath.Rel(inputPath, filePath)
+		if err != nil {
+			log.Errorf("Failed to make the path %s relative to the directory %s . Error: %q", filePath, inputPath, err)
+			continue
+		}
+		docs := []string{}
+		isK8sFile := false
+		err = common.StreamYAMLFile(filePath, func(doc []byte) error {
+			meta := struct {
+				APIVersion string `yaml:"apiVersion"`
+				Kind       string `yaml:"kind"`
+				Metadata   struct {
+					Name string `yaml:"name"`
+				} `yaml:"metadata"`
+			}{}
+			// The documents which are not k8s resources, like the templates of Helm charts, are kept as they are
+			if err := yaml.Unmarshal(doc, &meta); err != nil || meta.APIVersion == "" || meta.Kind == "" {
+				docs = append(docs, string(doc))
+				return nil
+			}
+			isK8sFile = true
+			deprecation, ok := k8sschema.GetAPIDeprecation(meta.APIVersion, meta.Kind)
+			if !ok || !deprecation.IsDeprecatedIn(to) {
+				docs = append(docs, string(doc))
+				apiVersions = append(apiVersions, meta.APIVersion)
+				return nil
+			}
+			upgrade := K8sAPIUpgrade{FilePath: relPath, Kind: meta.Kind, Name: meta.Metadata.Name, APIVersion: meta.APIVersion, Deprecation: deprecation, Removed: deprecation.IsRemovedIn(to), Notes: deprecation.Notes}
+			defer func() { upgrades = append(upgrades, upgrade) }()
+			if !upgrade.Removed {
+				docs = append(docs, string(doc))
+				apiVersions = append(apiVersions, meta.APIVersion)
+				return nil
+			}
+			// The replacement can itself be removed in the target version. Eg: extensions/v1beta1 PodSecurityPolicy
+			final := deprecation
+			for final.Replacement != "" {
+				next, ok := k8sschema.GetAPIDeprecation(final.Replacement, final.Kind)
+				if !ok || !next.IsRemovedIn(to) {
+					break
+				}
+				final = next
+				upgrade.Notes = append(upgrade.Notes, next.Notes...)
+			}
+			upgrade.Replacement = final.Replacement
+			if upgrade.Replacement == "" {
+				log.Warnf("The %s %s in %s was removed in Kubernetes %s without a replacement. It is left out of the manifests.", meta.Kind, meta.Metadata.Name, relPath, final.RemovedIn)
+				return nil
+			}
+			newDoc, err := rewriteK8sAPIVersion(codecs, doc, final)
+			if err != nil {
+				log.Warnf("Failed to rewrite the %s %s in %s to %s . Rewrite it manually. Error: %q", meta.Kind, meta.Metadata.Name, relPath, final.Replacement, err)
+				docs = append(docs, string(doc))
+				apiVersions = append(apiVersions, meta.APIVersion)
+				return nil
+			}
+			upgrade.Rewritten = true
+			docs = append(docs, string(newDoc))
+			apiVersions = append(apiVersions, final.Replacement)
+			return nil
+		})
+		if err != nil {
+			log.Errorf("Failed to read the yaml file at path %s . Error: %q", filePath, err)
+			continue
+		}
+		if !isK8sFile {
+			continue
+		}
+		if err := writeK8sManifest(filepath.Join(outputPath, KubeUpgradeManifestsDir, relPath), docs); err != nil {
+			log.Errorf("Failed to write the manifest %s . Error: %q", relPath, err)
+		}
+	}
+	if err := writeKubeUpgradeReport(outputPath, fromVersion, toVersion, upgrades); err != nil {
+		return upgrades, err
+	}
+	apiVersions = common.UniqueStrings(apiVersions)
+	sort.Strings(apiVersions)
+	validatePath := filepath.Join(outputPath, kubeUpgradeTestsDir, kubeUpgradeValidateFile)
+	if err := os.MkdirAll(filepath.Dir(validatePath), common.DefaultDirectoryPermission); err != nil {
+		return upgrades, err
+	}
+	if err := common.WriteTemplateToFile(templates.KubeUpgradeValidate_sh, struct {
+		ToVersion    string
+		ManifestsDir string
+		APIVersions  []string
+	}{
+		ToVersion:    toVersion,
+		ManifestsDir: KubeUpgradeManifestsDir,
+		APIVersions:  apiVersions,
+	}, validatePath, common.DefaultExecutablePermission); err != nil {
+		return upgrades, fmt.Errorf("failed to write the validation script at path %s . Error: %q", validatePath, err)
+	}
+	return upgrades, nil
+}
+
+// rewriteK8sAPIVersion converts the resource to the replacement of the deprecation, after running the fixers which set the
+// fields required by the newer versions, like the selector of the deployments
+func rewriteK8sAPIVersion(codecs serializer.CodecFactory, doc []byte, deprecation k8sschema.APIDeprecation) ([]byte, error) {
+	obj, _, err := codecs.UniversalDeserializer().Decode(doc, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := schema.ParseGroupVersion(deprecation.Replacement)
+	if err != nil {
+		return nil, err
+	}
+	fixedObj := fixer.Fix(obj)
+	newObj, err := k8sschema.ConvertToVersion(fixedObj, replacement)
+	if err != nil {
+		if deprecation.SameSchemaAs == "" {
+			return nil, err
+		}
+		// The scheme does not know the replacement, but knows a version with the same schema
+		sameSchemaAs, err := schema.ParseGroupVersion(deprecation.SameSchemaAs)
+		if err != nil {
+			return nil, err
+		}
+		if newObj, err = k8sschema.ConvertToVersion(fixedObj, sameSchemaAs); err != nil {
+			return nil, err
+		}
+		newObj.GetObjectKind().SetGroupVersionKind(replacement.WithKind(deprecation.Kind))
+	}
+	return common.MarshalObjToYaml(newObj)
+}
+
+// writeK8sManifest writes the yaml documents to the file
+func writeK8sManifest(path string, docs []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		if !strings.HasSuffix(doc, "\n") {
+			docs[i] = doc + "\n"
+		}
+	}
+	return ioutil.WriteFile(path, []byte(strings.Join(docs, "---\n")), common.DefaultFilePermission)
+}
+
+// writeKubeUpgradeReport writes the resources using deprecated APIs, and the changes of the fields and the defaults of their kinds
+func writeKubeUpgradeReport(outputPath, fromVersion, toVersion string, upgrades []K8sAPIUpgrade) error {
+	changes := []kubeUpgradeChange{}
+	for _, upgrade := range upgrades {
+		if len(upgrade.Notes) == 0 {
+			continue
+		}
+		replacement := upgrade.Replacement
+		if !upgrade.Removed {
+			replacement = upgrade.Deprecation.Replacement
+		}
+		found := false
+		for _, change := range changes {
+			if change.Kind == upgrade.Kind && change.APIVersion == upgrade.APIVersion && change.Replacement == replacement {
+				found = true
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, kubeUpgradeChange{Kind: upgrade.Kind, APIVersion: upgrade.APIVersion, Replacement: replacement, Notes: upgrade.Notes})
+		}
+	}
+	reportPath := filepath.Join(outputPath, KubeUpgradeReportFile)
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	if err := common.WriteTemplateToFile(templates.KubeUpgrade_md, struct {
+		FromVersion  string
+		ToVersion    string
+		ManifestsDir string
+		ValidatePath string
+		Upgrades     []K8sAPIUpgrade
+		Changes      []kubeUpgradeChange
+	}{
+		FromVersion:  fromVersion,
+		ToVersion:    toVersion,
+		ManifestsDir: KubeUpgradeManifestsDir,
+		ValidatePath: filepath.Join(kubeUpgradeTestsDir, kubeUpgradeValidateFile),
+		Upgrades:     upgrades,
+		Changes:      changes,
+	}, reportPath, common.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to write the upgrade report at path %s . Error: %q", reportPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/move2kube"
+)
+
+const testUpgradeYamls = `apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /
+            backend:
+              serviceName: web
+              servicePort: 8080
+---
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: restricted
+spec:
+  privileged: false
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  schedule: "0 * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: Never
+          containers:
+            - name: cleanup
+              image: quay.io/myns/cleanup:latest
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+    - port: 8080
+`
+
+func TestUpgradeK8sManifests(t *testing.T) {
+	inputPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(inputPath, "web"), 0755); err != nil {
+		t.Fatalf("Failed to create the input directory. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(inputPath, "web", "web.yaml"), []byte(testUpgradeYamls), 0644); err != nil {
+		t.Fatalf("Failed to write the yamls. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(inputPath, "values.yaml"), []byte("replicas: 2\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the values. Error: %q", err)
+	}
+	if _, err := move2kube.UpgradeK8sManifests(inputPath, t.TempDir(), "1.25", "1.21"); err == nil {
+		t.Fatalf("Expected an error for a target version older than the source version")
+	}
+
+	outputPath := t.TempDir()
+	upgrades, err := move2kube.UpgradeK8sManifests(inputPath, outputPath, "1.21", "1.22")
+	if err != nil {
+		t.Fatalf("Failed to upgrade the manifests. Error: %q", err)
+	}
+	if len(upgrades) != 3 {
+		t.Fatalf("Expected the ingress, the pod security policy and the cron job to be reported. Actual: %+v", upgrades)
+	}
+	if ingress := upgrades[0]; !ingress.Removed || !ingress.Rewritten || ingress.Replacement != "networking.k8s.io/v1" {
+		t.Fatalf("Failed to rewrite the ingress. Actual: %+v", ingress)
+	}
+	if psp := upgrades[1]; psp.Removed || psp.Status() != "Deprecated in 1.21, and removed in 1.25 without a replacement" {
+		t.Fatalf("Expected the pod security policy to only be deprecated. Actual: %s", psp.Status())
+	}
+	manifest, err := ioutil.ReadFile(filepath.Join(outputPath, move2kube.KubeUpgradeManifestsDir, "web", "web.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read the upgraded manifest. Error: %q", err)
+	}
+	for _, want := range []string{"apiVersion: networking.k8s.io/v1", "pathType: Prefix", "name: web", "apiVersion: policy/v1beta1", "apiVersion: batch/v1beta1", "port: 8080"} {
+		if !strings.Contains(string(manifest), want) {
+			t.Fatalf("Expected the upgraded manifest to contain %s . Actual:\n%s", want, manifest)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, move2kube.KubeUpgradeManifestsDir, "values.yaml")); err == nil {
+		t.Fatalf("Expected the yaml without any k8s resources to be skipped")
+	}
+	script, err := ioutil.ReadFile(filepath.Join(outputPath, "tests", "validate.sh"))
+	if err != nil || !strings.Contains(string(script), "grep -qx 'networking.k8s.io/v1'") || strings.Contains(string(script), "'extensions/v1beta1'") {
+		t.Fatalf("Failed to write the validation script. Error: %v Actual:\n%s", err, script)
+	}
+
+	outputPath = t.TempDir()
+	upgrades, err = move2kube.UpgradeK8sManifests(inputPath, outputPath, "1.21", "1.25")
+	if err != nil {
+		t.Fatalf("Failed to upgrade the manifests. Error: %q", err)
+	}
+	if psp := upgrades[1]; !psp.Removed || psp.Replacement != "" || psp.Rewritten {
+		t.Fatalf("Expected the pod security policy to be left out. Actual: %+v", psp)
+	}
+	if cronJob := upgrades[2]; !cronJob.Rewritten || cronJob.Replacement != "batch/v1" {
+		t.Fatalf("Failed to rewrite the cron job. Actual: %+v", cronJob)
+	}
+	manifest, err = ioutil.ReadFile(filepath.Join(outputPath, move2kube.KubeUpgradeManifestsDir, "web", "web.yaml"))
+	if err != nil || strings.Contains(string(manifest), "PodSecurityPolicy") || !strings.Contains(string(manifest), "apiVersion: batch/v1\n") {
+		t.Fatalf("Failed to upgrade the manifest to 1.25 . Error: %v Actual:\n%s", err, manifest)
+	}
+	report, err := ioutil.ReadFile(filepath.Join(outputPath, move2kube.KubeUpgradeReportFile))
+	if err != nil || !strings.Contains(string(report), "| web/web.yaml | PodSecurityPolicy | restricted | policy/v1beta1 | Removed in 1.25 without a replacement. Left out of the manifests |") {
+		t.Fatalf("Failed to write the upgrade report. Error: %v Actual:\n%s", err, report)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// GPGSigner signs the archives with a detached ASCII armored gpg signature
+	GPGSigner = "gpg"
+	// CosignSigner signs the archives using cosign sign-blob
+	CosignSigner = "cosign"
+)
+
+// PackageOutput creates an archive of the artifacts directory in the archive directory along with a file containing its checksum.
+// If a signer is given, the archive is also signed using the key, which can be empty to use the default key of the signer.
+func PackageOutput(artifactsPath, archiveDir, format, signer, signKey string) (string, error) {
+	if artifactsPath == archiveDir || common.IsParent(archiveDir, artifactsPath) {
+		return "", fmt.Errorf("the archive directory %s should not be inside the artifacts directory %s", archiveDir, artifactsPath)
+	}
+	if signer != "" && signer != GPGSigner && signer != CosignSigner {
+		return "", fmt.Errorf("unsupported signer %s . Supported signers are %s and %s", signer, GPGSigner, CosignSigner)
+	}
+	if err := os.MkdirAll(archiveDir, common.DefaultDirectoryPermission); err != nil {
+		log.Errorf("Failed to create the directory at path %s Error: %q", archiveDir, err)
+		return "", err
+	}
+	archivePath := filepath.Join(archiveDir, filepath.Base(artifactsPath)+"."+format)
+	if err := common.CreateArchive(archivePath, artifactsPath, format); err != nil {
+		log.Errorf("Failed to package the artifacts in the directory %s . Error: %q", artifactsPath, err)
+		return "", err
+	}
+	if err := common.CreateSHA256SumFile(archivePath, archivePath+common.SHA256SumSuffix); err != nil {
+		log.Errorf("Failed to write the checksum of the archive at path %s . Error: %q", archivePath, err)
+		return "", err
+	}
+	if signer == "" {
+		return archivePath, nil
+	}
+	if err := signArchive(archivePath, signer, signKey); err != nil {
+		log.Errorf("Failed to sign the archive at path %s using %s . Error: %q", archivePath, signer, err)
+		return "", err
+	}
+	return archivePath, nil
+}
+
+// signArchive writes a detached signature of the archive next to it
+func signArchive(archivePath, signer, signKey string) error {
+	var args []string
+	if signer == GPGSigner {
+		args = []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", archivePath + ".asc"}
+		if signKey != "" {
+			args = append(args, "--local-user", signKey)
+		}
+		args = append(args, archivePath)
+	} else {
+		args = []string{"sign-blob", "--output-signature", archivePath + ".sig"}
+		if signKey != "" {
+			args = append(args, "--key", signKey)
+		}
+		args = append(args, archivePath)
+	}
+	if _, err := common.RunCommandCombinedOutput(filepath.Dir(archivePath), signer, args...); err != nil {
+		return err
+	}
+	log.Infof("Signed the archive using %s", signer)
+	return nil
+}
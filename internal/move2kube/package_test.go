@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+)
+
+func TestPackageOutput(t *testing.T) {
+	artifactsPath := filepath.Join(t.TempDir(), "myproject")
+	if err := os.MkdirAll(filepath.Join(artifactsPath, "deploy"), common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Failed to create the artifacts directory. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(artifactsPath, "deploy", "web.yaml"), []byte("kind: Deployment\n"), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the artifacts. Error: %q", err)
+	}
+
+	// fake gpg and cosign, which record their arguments and write the signature
+	binPath := t.TempDir()
+	logPath := filepath.Join(binPath, "calls.log")
+	signer := "#!/bin/sh\necho $(basename $0) \"$@\" >> " + logPath + "\nwhile [ $# -gt 1 ]; do case \"$1\" in --output|--output-signature) echo signature > \"$2\";; esac; shift; done\n"
+	for _, name := range []string{move2kube.GPGSigner, move2kube.CosignSigner} {
+		if err := ioutil.WriteFile(filepath.Join(binPath, name), []byte(signer), 0755); err != nil {
+			t.Fatalf("Failed to write the fake %s. Error: %q", name, err)
+		}
+	}
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", binPath+string(os.PathListSeparator)+path)
+	retries := common.CommandRetries
+	defer func() { common.CommandRetries = retries }()
+	common.CommandRetries = 0
+
+	t.Run("archive with its checksum", func(t *testing.T) {
+		archiveDir := t.TempDir()
+		archivePath, err := move2kube.PackageOutput(artifactsPath, archiveDir, common.ZipArchiveFormat, "", "")
+		if err != nil {
+			t.Fatalf("Failed to package the artifacts. Error: %q", err)
+		}
+		if archivePath != filepath.Join(archiveDir, "myproject.zip") {
+			t.Fatalf("Expected the archive at path %s . Actual: %s", filepath.Join(archiveDir, "myproject.zip"), archivePath)
+		}
+		zr, err := zip.OpenReader(archivePath)
+		if err != nil {
+			t.Fatalf("Failed to open the archive. Error: %q", err)
+		}
+		defer zr.Close()
+		names := []string{}
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		if !strings.Contains(strings.Join(names, "\n")+"\n", "myproject/deploy/web.yaml\n") {
+			t.Fatalf("Expected the artifacts in the archive. Actual: %v", names)
+		}
+		checksum, err := common.GetFileSHA256Hash(archivePath)
+		if err != nil {
+			t.Fatalf("Failed to calculate the checksum of the archive. Error: %q", err)
+		}
+		sumFile, err := ioutil.ReadFile(archivePath + common.SHA256SumSuffix)
+		if err != nil || string(sumFile) != checksum+"  myproject.zip" {
+			t.Fatalf("Expected the checksum file to contain %q . Actual: %q Error: %v", checksum+"  myproject.zip", string(sumFile), err)
+		}
+		if _, err := os.Stat(logPath); err == nil {
+			t.Fatalf("Expected the archive not to be signed")
+		}
+	})
+
+	t.Run("archive signed using gpg and cosign", func(t *testing.T) {
+		archiveDir := t.TempDir()
+		gpgArchivePath, err := move2kube.PackageOutput(artifactsPath, archiveDir, common.TarGzArchiveFormat, move2kube.GPGSigner, "ops@example.com")
+		if err != nil {
+			t.Fatalf("Failed to package and sign the artifacts using gpg. Error: %q", err)
+		}
+		cosignArchivePath, err := move2kube.PackageOutput(artifactsPath, archiveDir, common.ZipArchiveFormat, move2kube.CosignSigner, "cosign.key")
+		if err != nil {
+			t.Fatalf("Failed to package and sign the artifacts using cosign. Error: %q", err)
+		}
+		for _, signaturePath := range []string{gpgArchivePath + ".asc", cosignArchivePath + ".sig"} {
+			if _, err := os.Stat(signaturePath); err != nil {
+				t.Fatalf("Expected the signature at path %s . Error: %q", signaturePath, err)
+			}
+		}
+		calls, err := ioutil.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("Failed to read the calls of the fake signers. Error: %q", err)
+		}
+		for _, call := range []string{
+			"gpg --batch --yes --armor --detach-sign --output " + gpgArchivePath + ".asc --local-user ops@example.com " + gpgArchivePath,
+			"cosign sign-blob --output-signature " + cosignArchivePath + ".sig --key cosign.key " + cosignArchivePath,
+		} {
+			if !strings.Contains(string(calls), call+"\n") {
+				t.Fatalf("Expected the call [%s]. Actual:\n%s", call, string(calls))
+			}
+		}
+	})
+
+	t.Run("unknown signer is rejected", func(t *testing.T) {
+		archiveDir := filepath.Join(t.TempDir(), "archives")
+		if _, err := move2kube.PackageOutput(artifactsPath, archiveDir, common.ZipArchiveFormat, "pgp", ""); err == nil {
+			t.Fatalf("Expected an error for the unknown signer")
+		}
+		if _, err := os.Stat(archiveDir); err == nil {
+			t.Fatalf("Expected no archive to be created for the unknown signer")
+		}
+	})
+
+	t.Run("archive directory inside the artifacts is rejected", func(t *testing.T) {
+		if _, err := move2kube.PackageOutput(artifactsPath, filepath.Join(artifactsPath, "archives"), common.ZipArchiveFormat, "", ""); err == nil {
+			t.Fatalf("Expected an error for the archive directory inside the artifacts directory")
+		}
+	})
+}
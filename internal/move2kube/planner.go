@@ -17,7 +17,13 @@ limitations under the License.
 package move2kube
 
 import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/containerizer"
@@ -28,8 +34,11 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// hostPortRegex matches the connection strings without a scheme, like redis:6379
+var hostPortRegex = regexp.MustCompile(`^([a-zA-Z0-9][a-zA-Z0-9.-]*):[0-9]+(/.*)?$`)
+
 //CreatePlan creates the plan from all planners
-func CreatePlan(inputPath string, prjName string, interactive bool) plantypes.Plan {
+func CreatePlan(inputPath string, prjName string, interactive bool) (plantypes.Plan, error) {
 	p := plantypes.NewPlan()
 	p.Name = prjName
 	p.Spec.Inputs.RootDir = inputPath
@@ -41,7 +50,7 @@ func CreatePlan(inputPath string, prjName string, interactive bool) plantypes.Pl
 		att = append(att, string(plantypes.Kube2KubeTranslation))
 		translationTypes := selectTranslators(att)
 		if len(translationTypes) == 0 {
-			log.Fatalf("No source was selected. Terminating.")
+			return p, fmt.Errorf("no source was selected")
 		}
 		selectedTranslationPlanners = []source.Translator{}
 		for _, tp := range source.GetTranslators() {
@@ -54,7 +63,7 @@ func CreatePlan(inputPath string, prjName string, interactive bool) plantypes.Pl
 			allowKube2Kube = false
 		}
 
-		if common.IsStringPresent(translationTypes, string(plantypes.Any2KubeTranslation)) || common.IsStringPresent(translationTypes, string(plantypes.CfManifest2KubeTranslation)) {
+		if common.IsStringPresent(translationTypes, string(plantypes.Any2KubeTranslation)) || common.IsStringPresent(translationTypes, string(plantypes.CfManifest2KubeTranslation)) || common.IsStringPresent(translationTypes, string(plantypes.Heroku2KubeTranslation)) || common.IsStringPresent(translationTypes, string(plantypes.AppEngine2KubeTranslation)) {
 			containerizer.InitContainerizers(p.Spec.Inputs.RootDir, selectContainerizationTypes(containerizer.GetAllContainerBuildStrategies()))
 		}
 	} else {
@@ -66,16 +75,27 @@ func CreatePlan(inputPath string, prjName string, interactive bool) plantypes.Pl
 	}
 
 	log.Infoln("Planning Translation")
+	// The translators are run concurrently, except Any2Kube which skips the directories of the services found by the
+	// others. The services are added to the plan in the order of the translators, so that the plan is deterministic.
+	independentPlanners := []source.Translator{}
+	dependentPlanners := []source.Translator{}
 	for _, l := range selectedTranslationPlanners {
-		log.Infof("[%T] Planning translation", l)
-		services, err := l.GetServiceOptions(inputPath, p)
-		if err != nil {
-			log.Warnf("[%T] Failed : %s", l, err)
+		if _, ok := l.(*source.Any2KubeTranslator); ok {
+			dependentPlanners = append(dependentPlanners, l)
 		} else {
-			p.AddServicesToPlan(services)
-			log.Infof("[%T] Done", l)
+			independentPlanners = append(independentPlanners, l)
 		}
 	}
+	plannedServices := make([][]plantypes.Service, len(independentPlanners))
+	common.RunConcurrently(len(independentPlanners), common.PlanWorkers, func(i int) {
+		plannedServices[i] = getServiceOptions(independentPlanners[i], inputPath, p)
+	})
+	for _, services := range plannedServices {
+		p.AddServicesToPlan(services)
+	}
+	for _, l := range dependentPlanners {
+		p.AddServicesToPlan(getServiceOptions(l, inputPath, p))
+	}
 	log.Infoln("Translation planning done")
 
 	// sort the service options in order of priority
@@ -86,6 +106,7 @@ func CreatePlan(inputPath string, prjName string, interactive bool) plantypes.Pl
 		})
 		log.Debugf("After sorting options of service: %s service options:\n%v", serviceName, serviceOptions)
 	}
+	resolveServiceDependencies(&p)
 
 	log.Infoln("Planning Metadata")
 	metadataPlanners := metadata.GetLoaders()
@@ -104,11 +125,112 @@ func CreatePlan(inputPath string, prjName string, interactive bool) plantypes.Pl
 		}
 	}
 	log.Infoln("Metadata planning done")
-	return p
+	return p, nil
+}
+
+// getServiceOptions returns the services found by the translator, along with the ports detected in their source
+func getServiceOptions(l source.Translator, inputPath string, p plantypes.Plan) []plantypes.Service {
+	log.Infof("[%T] Planning translation", l)
+	endRegion := common.TraceRegion(fmt.Sprintf("plan %T", l))
+	services, err := l.GetServiceOptions(inputPath, p)
+	endRegion()
+	if err != nil {
+		log.Warnf("[%T] Failed : %s", l, err)
+		return nil
+	}
+	addDetectedPorts(services)
+	log.Infof("[%T] Done", l)
+	return services
+}
+
+// addDetectedPorts adds the ports inferred from the source while detecting the container build type to the ports of the services
+func addDetectedPorts(services []plantypes.Service) {
+	for i := range services {
+		for _, port := range services[i].Detection.Ports {
+			services[i].AddPort(plantypes.Port{ContainerPort: int32(port)})
+		}
+	}
+}
+
+// resolveServiceDependencies drops the dependencies of the service options which are not services of the plan, like the
+// cf services which are not translated and the services which were deselected, and adds the services which are the hosts
+// of the connection strings in the env vars of the service options, unless that would make the services depend on each other
+func resolveServiceDependencies(p *plantypes.Plan) {
+	serviceNames := []string{}
+	for serviceName := range p.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	dependencies := map[string][]string{}
+	for _, serviceName := range serviceNames {
+		services := p.Spec.Inputs.Services[serviceName]
+		for i := range services {
+			dependsOn := []string{}
+			for _, dependency := range services[i].DependsOn {
+				if _, ok := p.Spec.Inputs.Services[dependency]; ok && dependency != serviceName && !common.IsStringPresent(dependsOn, dependency) {
+					dependsOn = append(dependsOn, dependency)
+				}
+			}
+			services[i].DependsOn = dependsOn
+		}
+		if len(services) > 0 {
+			dependencies[serviceName] = services[0].DependsOn
+		}
+	}
+	for _, serviceName := range serviceNames {
+		services := p.Spec.Inputs.Services[serviceName]
+		for i := range services {
+			for _, env := range services[i].Runtime.Env {
+				host := getConnectionHost(env)
+				if _, ok := p.Spec.Inputs.Services[host]; !ok || host == serviceName || common.IsStringPresent(services[i].DependsOn, host) {
+					continue
+				}
+				if i == 0 {
+					dependencies[serviceName] = append(append([]string{}, services[0].DependsOn...), host)
+					if _, err := common.GetDependencyLevels(dependencies); err != nil {
+						log.Debugf("Not adding the dependency of the service %s on the service %s found in the env var %s . Error: %q", serviceName, host, env.Name, err)
+						dependencies[serviceName] = services[0].DependsOn
+						continue
+					}
+				}
+				log.Debugf("The service %s depends on the service %s found in the env var %s", serviceName, host, env.Name)
+				services[i].DependsOn = append(services[i].DependsOn, host)
+			}
+		}
+	}
+}
+
+// getAnalysisHint returns a hint listing the mandatory issues found in the source of a service by the rule engines, which may block its containerization
+func getAnalysisHint(service plantypes.Service) string {
+	issues := service.Analysis.GetIssues(plantypes.MandatoryAnalysisCategory)
+	if len(issues) == 0 {
+		return ""
+	}
+	ruleIDs := []string{}
+	for _, issue := range issues {
+		ruleIDs = append(ruleIDs, issue.RuleID)
+	}
+	return fmt.Sprintf("The analysis of the source found %d mandatory issues, and an effort of %d story points for all the issues: %s", len(issues), service.Analysis.Effort, strings.Join(ruleIDs, ", "))
+}
+
+// getConnectionHost returns the host of the connection string in the env var, like postgres://user:password@db:5432/tickets
+// or redis:6379, or the value of the env vars naming hosts, like DB_HOST=db
+func getConnectionHost(env plantypes.EnvVar) string {
+	value := strings.TrimSpace(env.Value)
+	if strings.HasSuffix(strings.ToUpper(env.Name), "HOST") {
+		return strings.ToLower(value)
+	}
+	if u, err := url.Parse(value); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname())
+	}
+	if match := hostPortRegex.FindStringSubmatch(value); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
 }
 
 // CuratePlan allows curation the plan with the qa engine
-func CuratePlan(p plantypes.Plan) plantypes.Plan {
+func CuratePlan(p plantypes.Plan) (plantypes.Plan, error) {
 	if len(p.Spec.Inputs.Services) == 0 {
 		log.Debugf("No services found.")
 	}
@@ -132,8 +254,8 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 	}
 	p.Spec.Inputs.Services = planServices
 	if len(p.Spec.Inputs.Services) == 0 {
-		if len(p.Spec.Inputs.K8sFiles) == 0 {
-			log.Fatalf("Failed to find any services that support the selected translation types.")
+		if len(p.Spec.Inputs.K8sFiles) == 0 && len(p.Spec.Inputs.HelmCharts) == 0 {
+			return p, common.NewError(common.NoServicesFoundErrorCode, nil, "Failed to find any services that support the selected translation types.")
 		} else {
 			log.Debugf("Failed to find any services that support the selected translation types.")
 		}
@@ -150,8 +272,8 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 		planServices[s] = p.Spec.Inputs.Services[s]
 	}
 	if len(p.Spec.Inputs.Services) == 0 {
-		if len(p.Spec.Inputs.K8sFiles) == 0 {
-			log.Fatalf("All services were deselected. Aborting.")
+		if len(p.Spec.Inputs.K8sFiles) == 0 && len(p.Spec.Inputs.HelmCharts) == 0 {
+			return p, common.NewError(common.NoServicesFoundErrorCode, nil, "All services were deselected.")
 		} else {
 			log.Debugf("All services were deselected however some k8s files were detected.")
 		}
@@ -174,9 +296,13 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 	services := map[string][]plantypes.Service{}
 	for serviceName, serviceOptions := range p.Spec.Inputs.Services {
 		sConTypes := []string{}
+		detections := map[string]plantypes.Detection{}
 		for _, serviceOption := range serviceOptions {
 			if common.IsStringPresent(selectedConTypes, string(serviceOption.ContainerBuildType)) {
 				sConTypes = append(sConTypes, string(serviceOption.ContainerBuildType))
+				if _, ok := detections[string(serviceOption.ContainerBuildType)]; !ok {
+					detections[string(serviceOption.ContainerBuildType)] = serviceOption.Detection
+				}
 			}
 		}
 		// TODO: service options should be have unique container build types already so we don't need to make sConTypes unique.
@@ -188,8 +314,21 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 		}
 		selectedSConType := sConTypes[0]
 		if len(sConTypes) > 1 {
+			hints := []string{"Choose the containerization technique of interest."}
+			for _, sConType := range sConTypes {
+				detection := detections[sConType]
+				if detection.Confidence > detections[selectedSConType].Confidence {
+					selectedSConType = sConType
+				}
+				if detection.Confidence > 0 {
+					hints = append(hints, fmt.Sprintf("%s : detected with %d%% confidence from %s", sConType, detection.Confidence, strings.Join(detection.Evidence, ", ")))
+				}
+			}
+			if hint := getAnalysisHint(serviceOptions[0]); hint != "" {
+				hints = append(hints, hint)
+			}
 			qaKey := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + "containerization" + common.Delim + "type"
-			selectedSConType = qaengine.FetchSelectAnswer(qaKey, "Select containerization technique for service "+serviceName+":", []string{"Choose the containerization technique of interest."}, selectedSConType, sConTypes)
+			selectedSConType = qaengine.FetchSelectAnswer(qaKey, "Select containerization technique for service "+serviceName+":", hints, selectedSConType, sConTypes)
 		}
 
 		for _, serviceOption := range serviceOptions {
@@ -229,7 +368,11 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 				}
 			}
 			qaKey := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + "containerization" + common.Delim + "target"
-			selectedSConMode := qaengine.FetchSelectAnswer(qaKey, "Select containerization target for service "+serviceName+":", []string{"Choose the target that should be used for containerization."}, options[0], options)
+			hints := []string{"Choose the target that should be used for containerization."}
+			if hint := getAnalysisHint(serviceOption); hint != "" {
+				hints = append(hints, hint)
+			}
+			selectedSConMode := qaengine.FetchSelectAnswer(qaKey, "Select containerization target for service "+serviceName+":", hints, options[0], options)
 			if requiresConversion {
 				absOptionPath, err := p.GetAbsolutePath(selectedSConMode)
 				if err != nil {
@@ -244,6 +387,12 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 		}
 	}
 	p.Spec.Inputs.Services = services
+	selectStaticSiteServing(&p)
+	selectBuildSteps(&p)
+	resolveServiceDependencies(&p)
+	if err := ValidatePlanServices(p); err != nil {
+		log.Warnf("The curated plan is invalid. Error: %q", err)
+	}
 
 	// Choose cluster type to target
 	clusters := new(metadata.ClusterMDLoader).GetClusters(p)
@@ -255,7 +404,160 @@ func CuratePlan(p plantypes.Plan) plantypes.Plan {
 	p.Spec.Outputs.Kubernetes.TargetCluster.Type = clusterType
 	p.Spec.Outputs.Kubernetes.TargetCluster.Path = ""
 
-	return p
+	return p, nil
+}
+
+// selectBuildSteps asks for the services whose image is built from the build contexts of other services, like a frontend
+// built into the image of its backend, and moves those services into the build steps of the service
+func selectBuildSteps(p *plantypes.Plan) {
+	candidates := []string{}
+	for serviceName, services := range p.Spec.Inputs.Services {
+		if len(services) > 0 && len(services[0].BuildSteps.Steps) == 0 && services[0].StaticSite.Serving != plantypes.ObjectStorageStaticSiteServing &&
+			services[0].ContainerBuildType != plantypes.ReuseContainerBuildTypeValue && services[0].ContainerBuildType != plantypes.ManualContainerBuildTypeValue {
+			candidates = append(candidates, serviceName)
+		}
+	}
+	if len(candidates) < 2 {
+		return
+	}
+	sort.Strings(candidates)
+	selectedServices := qaengine.FetchMultiSelectAnswer(common.ConfigServicesBuildStepsKey, "Select the services whose image is built from multiple build contexts:", []string{"The services built before them, like a frontend or shared libraries, are selected next."}, []string{}, candidates)
+	usedSteps := map[string]bool{}
+	for _, serviceName := range selectedServices {
+		stepOptions := []string{}
+		for _, candidate := range candidates {
+			if candidate != serviceName && !usedSteps[candidate] && !common.IsStringPresent(selectedServices, candidate) {
+				stepOptions = append(stepOptions, candidate)
+			}
+		}
+		if len(stepOptions) == 0 {
+			continue
+		}
+		qaKey := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigBuildStepsKeySegment
+		stepNames := qaengine.FetchMultiSelectAnswer(qaKey+common.Delim+"services", "Select the services that are built before the service "+serviceName+" and included in it:", []string{"The selected services are no longer deployed on their own."}, []string{}, stepOptions)
+		if len(stepNames) == 0 {
+			continue
+		}
+		service := p.Spec.Inputs.Services[serviceName][0]
+		for _, stepName := range stepNames {
+			service.BuildSteps.Steps = append(service.BuildSteps.Steps, p.Spec.Inputs.Services[stepName][0])
+			usedSteps[stepName] = true
+			delete(p.Spec.Inputs.Services, stepName)
+		}
+		compositions := []string{string(plantypes.MultipleImagesBuildComposition)}
+		if service.IsDockerfileBuild() {
+			compositions = []string{string(plantypes.MultiStageBuildComposition), string(plantypes.MultipleImagesBuildComposition)}
+		}
+		composition := compositions[0]
+		if len(compositions) > 1 {
+			composition = qaengine.FetchSelectAnswer(qaKey+common.Delim+"composition", "Select how the build steps of the service "+serviceName+" are composed:", []string{string(plantypes.MultiStageBuildComposition) + " builds the steps as stages of the Dockerfile of the service, into one image.", string(plantypes.MultipleImagesBuildComposition) + " builds an image for every step, which runs as another container in the pods of the service."}, composition, compositions)
+		}
+		service.BuildSteps.Composition = plantypes.BuildCompositionTypeValue(composition)
+		if service.BuildSteps.Composition == plantypes.MultiStageBuildComposition {
+			service.BuildSteps.Artifacts = map[string][]string{}
+			for _, stepName := range stepNames {
+				artifactsKey := qaKey + common.Delim + `"` + stepName + `"` + common.Delim + "artifacts"
+				artifacts := qaengine.FetchMultilineAnswer(artifactsKey, "Enter the paths copied from the build step "+stepName+" into the image of the service "+serviceName+":", []string{"Enter one source:destination pair per line, like /app/dist:/app/public"}, "")
+				for _, artifact := range strings.Split(artifacts, "\n") {
+					if artifact = strings.TrimSpace(artifact); artifact != "" {
+						service.BuildSteps.Artifacts[stepName] = append(service.BuildSteps.Artifacts[stepName], artifact)
+					}
+				}
+			}
+		}
+		p.Spec.Inputs.Services[serviceName] = []plantypes.Service{service}
+	}
+}
+
+// selectStaticSiteServing asks how each of the detected static frontends is served
+func selectStaticSiteServing(p *plantypes.Plan) {
+	serviceNames := []string{}
+	backendNames := []string{}
+	for serviceName, services := range p.Spec.Inputs.Services {
+		if len(services) == 0 {
+			continue
+		}
+		if services[0].StaticSite.Framework != "" {
+			serviceNames = append(serviceNames, serviceName)
+		} else if services[0].IsDockerfileBuild() {
+			backendNames = append(backendNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	sort.Strings(backendNames)
+	for _, serviceName := range serviceNames {
+		service := p.Spec.Inputs.Services[serviceName][0]
+		qaKey := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"` + common.Delim + common.ConfigStaticSiteKeySegment
+		servings := []string{string(plantypes.NginxStaticSiteServing), string(plantypes.ObjectStorageStaticSiteServing)}
+		hints := []string{
+			string(plantypes.NginxStaticSiteServing) + " builds the site into an nginx image, which is the default route of the ingress.",
+			string(plantypes.ObjectStorageStaticSiteServing) + " generates a script which builds the site and uploads it to a bucket, to be served through a CDN.",
+		}
+		if len(backendNames) > 0 {
+			servings = append(servings, string(plantypes.BackendStaticSiteServing))
+			hints = append(hints, string(plantypes.BackendStaticSiteServing)+" builds the site as a stage of the Dockerfile of a backend service, which serves it.")
+		}
+		serving := qaengine.FetchSelectAnswer(qaKey+common.Delim+"serving", "Select how the "+service.StaticSite.Framework+" static site "+serviceName+" is served:", hints, string(plantypes.NginxStaticSiteServing), servings)
+		service.StaticSite.Serving = plantypes.StaticSiteServingTypeValue(serving)
+		switch service.StaticSite.Serving {
+		case plantypes.NginxStaticSiteServing:
+			service.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+			service.ServiceRelPath = "/"
+		case plantypes.ObjectStorageStaticSiteServing:
+			service.StaticSite.BucketURL = qaengine.FetchStringAnswer(qaKey+common.Delim+"bucketurl", "Enter the url of the bucket to which the static site "+serviceName+" is uploaded:", []string{"Like s3://my-bucket, gs://my-bucket or https://myaccount.blob.core.windows.net/mycontainer"}, "")
+		case plantypes.BackendStaticSiteServing:
+			service.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+			backendName := qaengine.FetchSelectAnswer(qaKey+common.Delim+"backend", "Select the backend service which serves the static site "+serviceName+":", []string{"The built files are copied into the ./public directory of the image of the backend."}, backendNames[0], backendNames)
+			backend, ok := p.Spec.Inputs.Services[backendName]
+			if !ok || len(backend) == 0 {
+				log.Errorf("Unable to find the backend service %s for the static site %s", backendName, serviceName)
+				continue
+			}
+			backendService := backend[0]
+			backendService.BuildSteps.Composition = plantypes.MultiStageBuildComposition
+			backendService.BuildSteps.Steps = append(backendService.BuildSteps.Steps, service)
+			if backendService.BuildSteps.Artifacts == nil {
+				backendService.BuildSteps.Artifacts = map[string][]string{}
+			}
+			backendService.BuildSteps.Artifacts[serviceName] = append(backendService.BuildSteps.Artifacts[serviceName], path.Join(source.StaticSiteBuildPath, filepath.ToSlash(service.StaticSite.BuildDir))+":./public")
+			p.Spec.Inputs.Services[backendName] = []plantypes.Service{backendService}
+			delete(p.Spec.Inputs.Services, serviceName)
+			continue
+		}
+		p.Spec.Inputs.Services[serviceName] = []plantypes.Service{service}
+	}
+}
+
+// ValidatePlanServices checks that the container build types of the services, which can be overridden by editing the plan,
+// are supported by their translation types, and that the services depend on services of the plan without cycles
+func ValidatePlanServices(p plantypes.Plan) error {
+	serviceNames := []string{}
+	for serviceName := range p.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		for _, service := range p.Spec.Inputs.Services[serviceName] {
+			if err := service.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	dependencies := map[string][]string{}
+	for _, serviceName := range serviceNames {
+		services := p.Spec.Inputs.Services[serviceName]
+		if len(services) == 0 {
+			continue
+		}
+		for _, dependency := range services[0].DependsOn {
+			if _, ok := p.Spec.Inputs.Services[dependency]; !ok {
+				return fmt.Errorf("the service %s depends on the service %s which is not in the plan", serviceName, dependency)
+			}
+		}
+		dependencies[serviceName] = services[0].DependsOn
+	}
+	_, err := common.GetDependencyLevels(dependencies)
+	return err
 }
 
 func selectTranslators(translationTypes []string) []string {
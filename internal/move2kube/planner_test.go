@@ -59,7 +59,10 @@ func TestCreatePlan(t *testing.T) {
 		containerizer.InitContainerizers(inputPath, nil)
 
 		// Test
-		p := move2kube.CreatePlan(inputPath, prjName, false)
+		p, err := move2kube.CreatePlan(inputPath, prjName, false)
+		if err != nil {
+			t.Fatalf("Failed to create the plan. Error: %q", err)
+		}
 		if !cmp.Equal(p, want) {
 			t.Fatalf("Failed to create the plan properly. Difference:\n%s", cmp.Diff(want, p))
 		}
@@ -93,7 +96,10 @@ func TestCreatePlan(t *testing.T) {
 		containerizer.InitContainerizers(inputPath, nil)
 
 		// Test
-		p := move2kube.CreatePlan(inputPath, prjName, false)
+		p, err := move2kube.CreatePlan(inputPath, prjName, false)
+		if err != nil {
+			t.Fatalf("Failed to create the plan. Error: %q", err)
+		}
 		if !cmp.Equal(p, want) {
 			t.Fatalf("Failed to create the plan properly. Difference:\n%s", cmp.Diff(want, p))
 		}
@@ -119,7 +125,10 @@ func TestCreatePlan(t *testing.T) {
 		containerizer.InitContainerizers(inputPath, nil)
 
 		// Test
-		actual := move2kube.CreatePlan(inputPath, prjName, false)
+		actual, err := move2kube.CreatePlan(inputPath, prjName, false)
+		if err != nil {
+			t.Fatalf("Failed to create the plan. Error: %q", err)
+		}
 		for _, services := range actual.Spec.Inputs.Services {
 			for i := range services {
 				services[i].RepoInfo = plantypes.RepoInfo{}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"io/ioutil"
+	"reflect"
+
+	"github.com/konveyor/move2kube/internal/jsonschema"
+	"github.com/konveyor/move2kube/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// GetPlanJSONSchema returns the JSON schema of the plan file
+func GetPlanJSONSchema() *jsonschema.Schema {
+	enums := map[reflect.Type][]string{
+		reflect.TypeOf(plantypes.TranslationTypeValue("")): {
+			string(plantypes.Compose2KubeTranslation),
+			string(plantypes.CfManifest2KubeTranslation),
+			string(plantypes.Any2KubeTranslation),
+			string(plantypes.Kube2KubeTranslation),
+			string(plantypes.Dockerfile2KubeTranslation),
+			string(plantypes.Heroku2KubeTranslation),
+			string(plantypes.CloudRun2KubeTranslation),
+			string(plantypes.AppEngine2KubeTranslation),
+			string(plantypes.Azure2KubeTranslation),
+			string(plantypes.Swarm2KubeTranslation),
+			string(plantypes.ECS2KubeTranslation),
+			string(plantypes.Nomad2KubeTranslation),
+			string(plantypes.CloudFormation2KubeTranslation),
+		},
+		reflect.TypeOf(plantypes.SourceTypeValue("")): {
+			string(plantypes.ComposeSourceTypeValue),
+			string(plantypes.DirectorySourceTypeValue),
+			string(plantypes.CfManifestSourceTypeValue),
+			string(plantypes.KNativeSourceTypeValue),
+			string(plantypes.K8sSourceTypeValue),
+			string(plantypes.HerokuSourceTypeValue),
+			string(plantypes.CloudRunSourceTypeValue),
+			string(plantypes.AppEngineSourceTypeValue),
+			string(plantypes.AzureContainerAppsSourceTypeValue),
+			string(plantypes.AzureContainerInstancesSourceTypeValue),
+			string(plantypes.SwarmStackSourceTypeValue),
+			string(plantypes.ECSSourceTypeValue),
+			string(plantypes.NomadSourceTypeValue),
+			string(plantypes.CloudFormationSourceTypeValue),
+		},
+		reflect.TypeOf(plantypes.ContainerBuildTypeValue("")): {
+			string(plantypes.DockerFileContainerBuildTypeValue),
+			string(plantypes.ReuseDockerFileContainerBuildTypeValue),
+			string(plantypes.ReuseContainerBuildTypeValue),
+			string(plantypes.CNBContainerBuildTypeValue),
+			string(plantypes.ManualContainerBuildTypeValue),
+			string(plantypes.S2IContainerBuildTypeValue),
+		},
+		reflect.TypeOf(plantypes.SourceArtifactTypeValue("")): {
+			string(plantypes.K8sFileArtifactType),
+			string(plantypes.KnativeFileArtifactType),
+			string(plantypes.ComposeFileArtifactType),
+			string(plantypes.ImageInfoArtifactType),
+			string(plantypes.CfManifestArtifactType),
+			string(plantypes.CfRunningManifestArtifactType),
+			string(plantypes.SourceDirectoryArtifactType),
+			string(plantypes.DockerfileArtifactType),
+			string(plantypes.ProcfileArtifactType),
+			string(plantypes.HerokuAppJSONArtifactType),
+			string(plantypes.HerokuYamlArtifactType),
+			string(plantypes.HerokuAppsArtifactType),
+			string(plantypes.CloudRunServiceArtifactType),
+			string(plantypes.AppEngineAppYamlArtifactType),
+			string(plantypes.AzureResourcesArtifactType),
+			string(plantypes.SwarmStackArtifactType),
+			string(plantypes.ECSTaskDefinitionArtifactType),
+			string(plantypes.ECSServiceArtifactType),
+			string(plantypes.NomadJobArtifactType),
+			string(plantypes.CloudFormationTemplateArtifactType),
+		},
+		reflect.TypeOf(plantypes.BuildArtifactTypeValue("")): {
+			string(plantypes.SourceDirectoryBuildArtifactType),
+		},
+		reflect.TypeOf(plantypes.TargetInfoArtifactTypeValue("")): {
+			string(plantypes.K8sClusterArtifactType),
+		},
+		reflect.TypeOf(plantypes.HostPathRemediationTypeValue("")): {
+			string(plantypes.PVCHostPathRemediation),
+			string(plantypes.ConfigMapHostPathRemediation),
+			string(plantypes.EmptyDirHostPathRemediation),
+			string(plantypes.RetainHostPathRemediation),
+		},
+		reflect.TypeOf(plantypes.BuildCompositionTypeValue("")): {
+			string(plantypes.MultiStageBuildComposition),
+			string(plantypes.MultipleImagesBuildComposition),
+		},
+		reflect.TypeOf(plantypes.StaticSiteServingTypeValue("")): {
+			string(plantypes.NginxStaticSiteServing),
+			string(plantypes.ObjectStorageStaticSiteServing),
+			string(plantypes.BackendStaticSiteServing),
+		},
+		reflect.TypeOf(plantypes.PortProtocolTypeValue("")): {
+			string(plantypes.TCPPortProtocol),
+			string(plantypes.UDPPortProtocol),
+			string(plantypes.SCTPPortProtocol),
+		},
+		reflect.TypeOf(plantypes.PortExposeTypeValue("")): {
+			string(plantypes.IngressPortExpose),
+			string(plantypes.ClusterIPPortExpose),
+			string(plantypes.LoadBalancerPortExpose),
+			string(plantypes.NodePortPortExpose),
+			string(plantypes.IngressNginxPortExpose),
+		},
+		reflect.TypeOf(plantypes.OutputTargetTypeValue("")): {
+			string(plantypes.KubernetesOutputTargetType),
+			string(plantypes.OpenShiftOutputTargetType),
+			string(plantypes.KnativeOutputTargetType),
+			string(plantypes.HelmOutputTargetType),
+		},
+		reflect.TypeOf(plantypes.AnalysisCategoryValue("")): {
+			string(plantypes.MandatoryAnalysisCategory),
+			string(plantypes.OptionalAnalysisCategory),
+			string(plantypes.PotentialAnalysisCategory),
+		},
+	}
+	schema := jsonschema.Reflect(reflect.TypeOf(plantypes.Plan{}), enums)
+	schema.Schema = jsonschema.Draft07
+	schema.Title = "Move2Kube plan"
+	schema.Description = "The plan file created by " + types.AppName + " plan and used by " + types.AppName + " translate"
+	schema.Properties["apiVersion"].Enum = []string{plantypes.SchemeGroupVersion.String()}
+	schema.Properties["kind"].Enum = []string{string(plantypes.PlanKind)}
+	return schema
+}
+
+// LintPlan validates the plan file against the JSON schema of the plan and returns the violations
+func LintPlan(path string) ([]jsonschema.ValidationError, error) {
+	planBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the plan file at path %s . Error: %q", path, err)
+		return nil, err
+	}
+	document := yaml.Node{}
+	if err := yaml.Unmarshal(planBytes, &document); err != nil {
+		log.Errorf("Failed to parse the plan file at path %s . Error: %q", path, err)
+		return nil, err
+	}
+	return GetPlanJSONSchema().Validate(&document), nil
+}
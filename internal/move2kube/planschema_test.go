@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestLintPlan(t *testing.T) {
+	setupAssets(t)
+	defer os.RemoveAll(common.TempPath)
+
+	srcPath, err := filepath.Abs(filepath.Join("testdata", "migrate"))
+	if err != nil {
+		t.Fatalf("Failed to make the source path absolute. Error: %q", err)
+	}
+	containerizer.InitContainerizers(srcPath, nil)
+	p, err := move2kube.CreatePlan(srcPath, "myproject", false)
+	if err != nil {
+		t.Fatalf("Failed to create the plan. Error: %q", err)
+	}
+	p.Spec.Outputs.Targets = []plantypes.OutputTarget{{Name: "helm", Type: plantypes.HelmOutputTargetType}}
+	planPath := filepath.Join(t.TempDir(), common.DefaultPlanFile)
+	if err := plantypes.WritePlan(planPath, p); err != nil {
+		t.Fatalf("Failed to write the plan to path %s . Error: %q", planPath, err)
+	}
+
+	t.Run("plan created by move2kube", func(t *testing.T) {
+		errs, err := move2kube.LintPlan(planPath)
+		if err != nil {
+			t.Fatalf("Failed to lint the plan at path %s . Error: %q", planPath, err)
+		}
+		if len(errs) != 0 {
+			t.Fatalf("Expected the plan to be valid. Actual: %+v", errs)
+		}
+	})
+
+	t.Run("invalid plan", func(t *testing.T) {
+		planBytes, err := ioutil.ReadFile(planPath)
+		if err != nil {
+			t.Fatalf("Failed to read the plan at path %s . Error: %q", planPath, err)
+		}
+		invalidPlan := strings.Replace(string(planBytes), "type: Helm", "type: Nomad", 1)
+		invalidPlan = strings.Replace(invalidPlan, "kind: Plan", "kind: Plan\nunknownField: true", 1)
+		invalidPlanPath := filepath.Join(t.TempDir(), common.DefaultPlanFile)
+		if err := ioutil.WriteFile(invalidPlanPath, []byte(invalidPlan), common.DefaultFilePermission); err != nil {
+			t.Fatalf("Failed to write the plan to path %s . Error: %q", invalidPlanPath, err)
+		}
+		errs, err := move2kube.LintPlan(invalidPlanPath)
+		if err != nil {
+			t.Fatalf("Failed to lint the plan at path %s . Error: %q", invalidPlanPath, err)
+		}
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		if len(errs) != 2 || !strings.Contains(messages[0]+messages[1], `"Nomad" is not one of Kubernetes, OpenShift, Knative, Helm`) || !strings.Contains(messages[0]+messages[1], "unknownField") {
+			t.Fatalf("Expected the unknown output target type and the unknown field to be reported. Actual: %v", messages)
+		}
+	})
+
+	t.Run("missing plan", func(t *testing.T) {
+		if _, err := move2kube.LintPlan(filepath.Join(t.TempDir(), common.DefaultPlanFile)); err == nil {
+			t.Fatalf("Expected an error for a missing plan file")
+		}
+	})
+}
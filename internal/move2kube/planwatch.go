@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+// PlanEventType is the type of a change of the plan while watching the source directory
+type PlanEventType string
+
+const (
+	// PlanCreatedEvent is emitted once the plan of the source directory is created
+	PlanCreatedEvent PlanEventType = "PlanCreated"
+	// ServiceAddedEvent is emitted when a new service is detected
+	ServiceAddedEvent PlanEventType = "ServiceAdded"
+	// ServiceUpdatedEvent is emitted when the detected options of a service change
+	ServiceUpdatedEvent PlanEventType = "ServiceUpdated"
+	// ServiceRemovedEvent is emitted when a service is no longer detected
+	ServiceRemovedEvent PlanEventType = "ServiceRemoved"
+)
+
+// PlanEvent is a change of the plan while watching the source directory
+type PlanEvent struct {
+	Type    PlanEventType `json:"type"`
+	Service string        `json:"service,omitempty"`
+	Time    time.Time     `json:"time"`
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// PlanWatcher updates a plan when the services detected in the source directory change
+type PlanWatcher struct {
+	srcpath  string
+	name     string
+	planfile string
+	detected plantypes.Plan
+	files    map[string]fileState
+}
+
+// NewPlanWatcher creates the plan of the source directory and returns a watcher for the later changes.
+// The plan file is ignored when it is inside the source directory.
+func NewPlanWatcher(srcpath, name, planfile string) (*PlanWatcher, []PlanEvent, error) {
+	w := &PlanWatcher{srcpath: srcpath, name: name, planfile: planfile}
+	w.files = w.getFileStates()
+	detected, err := CreatePlan(srcpath, name, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.detected = detected
+	return w, []PlanEvent{{Type: PlanCreatedEvent, Time: time.Now()}}, nil
+}
+
+// GetPlan returns the plan detected in the source directory
+func (w *PlanWatcher) GetPlan() plantypes.Plan {
+	return w.detected
+}
+
+// Changed returns true if a file was added, removed or modified in the source directory since the last check
+func (w *PlanWatcher) Changed() bool {
+	files := w.getFileStates()
+	if reflect.DeepEqual(files, w.files) {
+		return false
+	}
+	w.files = files
+	return true
+}
+
+// Update plans the source directory again and applies the changes of the detected services to the plan.
+// The services which did not change are kept as they are in the plan, so that the edits of the plan are preserved.
+func (w *PlanWatcher) Update(p *plantypes.Plan) []PlanEvent {
+	log.Debugf("The source directory %s changed. Planning again.", w.srcpath)
+	detected, err := CreatePlan(w.srcpath, w.name, false)
+	if err != nil {
+		log.Warnf("Failed to plan the source directory %s again. Keeping the plan unchanged. Error: %q", w.srcpath, err)
+		return nil
+	}
+	events := applyDetectedServices(p, w.detected, detected)
+	w.detected = detected
+	return events
+}
+
+// applyDetectedServices applies the difference between the previously and the newly detected services to the plan
+func applyDetectedServices(p *plantypes.Plan, oldDetected, newDetected plantypes.Plan) []PlanEvent {
+	serviceNames := []string{}
+	for serviceName := range oldDetected.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	for serviceName := range newDetected.Spec.Inputs.Services {
+		if _, ok := oldDetected.Spec.Inputs.Services[serviceName]; !ok {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	sort.Strings(serviceNames)
+	if p.Spec.Inputs.Services == nil {
+		p.Spec.Inputs.Services = map[string][]plantypes.Service{}
+	}
+	now := time.Now()
+	events := []PlanEvent{}
+	for _, serviceName := range serviceNames {
+		oldServices, wasDetected := oldDetected.Spec.Inputs.Services[serviceName]
+		newServices, isDetected := newDetected.Spec.Inputs.Services[serviceName]
+		switch {
+		case !isDetected:
+			delete(p.Spec.Inputs.Services, serviceName)
+			events = append(events, PlanEvent{Type: ServiceRemovedEvent, Service: serviceName, Time: now})
+		case !wasDetected:
+			p.Spec.Inputs.Services[serviceName] = newServices
+			events = append(events, PlanEvent{Type: ServiceAddedEvent, Service: serviceName, Time: now})
+		case !reflect.DeepEqual(oldServices, newServices):
+			p.Spec.Inputs.Services[serviceName] = newServices
+			events = append(events, PlanEvent{Type: ServiceUpdatedEvent, Service: serviceName, Time: now})
+		}
+	}
+	if !reflect.DeepEqual(oldDetected.Spec.Inputs.K8sFiles, newDetected.Spec.Inputs.K8sFiles) {
+		p.Spec.Inputs.K8sFiles = newDetected.Spec.Inputs.K8sFiles
+	}
+	if !reflect.DeepEqual(oldDetected.Spec.Inputs.HelmCharts, newDetected.Spec.Inputs.HelmCharts) {
+		p.Spec.Inputs.HelmCharts = newDetected.Spec.Inputs.HelmCharts
+	}
+	return events
+}
+
+// getFileStates returns the sizes and the modification times of the files in the source directory, skipping the hidden directories like .git
+func (w *PlanWatcher) getFileStates() map[string]fileState {
+	dir := w.srcpath
+	files := map[string]fileState{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Failed to access the path %s . Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == w.planfile {
+			return nil
+		}
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Failed to walk the directory %s . Error: %q", dir, err)
+	}
+	return files
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	"github.com/konveyor/move2kube/internal/move2kube"
+)
+
+func TestPlanWatcher(t *testing.T) {
+	setupAssets(t)
+	defer os.RemoveAll(common.TempPath)
+
+	inputPath := t.TempDir()
+	planfile := filepath.Join(inputPath, common.DefaultPlanFile)
+	containerizer.InitContainerizers(inputPath, nil)
+	watcher, events, err := move2kube.NewPlanWatcher(inputPath, "project1", planfile)
+	if err != nil {
+		t.Fatalf("Failed to create the plan watcher. Error: %q", err)
+	}
+	if len(events) != 1 || events[0].Type != move2kube.PlanCreatedEvent {
+		t.Fatalf("Expected the plan created event. Actual: %+v", events)
+	}
+	if err := ioutil.WriteFile(planfile, []byte("name: project1\n"), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the plan file. Error: %q", err)
+	}
+	if watcher.Changed() {
+		t.Fatalf("Expected the changes of the plan file to be ignored")
+	}
+
+	composePath := filepath.Join(inputPath, "docker-compose.yaml")
+	if err := ioutil.WriteFile(composePath, []byte("version: '3'\nservices:\n  web:\n    image: nginx:latest\n"), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the compose file. Error: %q", err)
+	}
+	if !watcher.Changed() {
+		t.Fatalf("Expected the new compose file to be detected")
+	}
+	p := watcher.GetPlan()
+	p.Spec.Outputs.Kubernetes.RegistryURL = "quay.io"
+	events = watcher.Update(&p)
+	if len(events) != 1 || events[0].Type != move2kube.ServiceAddedEvent || events[0].Service != "web" {
+		t.Fatalf("Expected the web service to be added. Actual: %+v", events)
+	}
+	if _, ok := p.Spec.Inputs.Services["web"]; !ok || p.Spec.Outputs.Kubernetes.RegistryURL != "quay.io" {
+		t.Fatalf("Failed to add the web service to the plan while keeping the edits. Actual: %+v", p.Spec)
+	}
+
+	if err := os.Remove(composePath); err != nil {
+		t.Fatalf("Failed to remove the compose file. Error: %q", err)
+	}
+	if !watcher.Changed() {
+		t.Fatalf("Expected the removal of the compose file to be detected")
+	}
+	events = watcher.Update(&p)
+	if len(events) != 1 || events[0].Type != move2kube.ServiceRemovedEvent || events[0].Service != "web" {
+		t.Fatalf("Expected the web service to be removed. Actual: %+v", events)
+	}
+	if _, ok := p.Spec.Inputs.Services["web"]; ok {
+		t.Fatalf("Failed to remove the web service from the plan. Actual: %+v", p.Spec.Inputs.Services)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// RenameService renames the service in the plan file, and in the answers about the services recorded in the config files
+// and the QA cache files, so that the answers still apply to the renamed service. It returns the renamed plan.
+func RenameService(planPath, serviceName, newServiceName string, configPaths, cachePaths []string) (plantypes.Plan, error) {
+	p, err := plantypes.ReadPlan(planPath)
+	if err != nil {
+		return p, fmt.Errorf("failed to read the plan file at path %s . Error: %q", planPath, err)
+	}
+	if err := p.RenameService(serviceName, newServiceName); err != nil {
+		return p, err
+	}
+	if err := ValidatePlanServices(p); err != nil {
+		return p, fmt.Errorf("the renamed plan is invalid. Error: %q", err)
+	}
+	if err := plantypes.WritePlan(planPath, p); err != nil {
+		return p, fmt.Errorf("failed to write the plan file at path %s . Error: %q", planPath, err)
+	}
+	for _, configPath := range configPaths {
+		if err := renameServiceInConfig(configPath, serviceName, newServiceName); err != nil {
+			return p, fmt.Errorf("failed to rename the service in the config file at path %s . Error: %q", configPath, err)
+		}
+	}
+	for _, cachePath := range cachePaths {
+		if err := renameServiceInCache(cachePath, serviceName, newServiceName); err != nil {
+			return p, fmt.Errorf("failed to rename the service in the QA cache file at path %s . Error: %q", cachePath, err)
+		}
+	}
+	return p, nil
+}
+
+// RenameImage changes the image of the services in the plan file which use the image.
+// It returns the renamed plan and the names of the services using the image.
+func RenameImage(planPath, image, newImage string) (plantypes.Plan, []string, error) {
+	p, err := plantypes.ReadPlan(planPath)
+	if err != nil {
+		return p, nil, fmt.Errorf("failed to read the plan file at path %s . Error: %q", planPath, err)
+	}
+	serviceNames, err := p.RenameImage(image, newImage)
+	if err != nil {
+		return p, nil, err
+	}
+	if err := plantypes.WritePlan(planPath, p); err != nil {
+		return p, nil, fmt.Errorf("failed to write the plan file at path %s . Error: %q", planPath, err)
+	}
+	return p, serviceNames, nil
+}
+
+// renameServiceInConfig moves the answers about the service to the new name of the service, and renames the service
+// in the answers about the services, like the services that are exposed
+func renameServiceInConfig(configPath, serviceName, newServiceName string) error {
+	configBytes, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(configBytes, &config); err != nil {
+		return err
+	}
+	baseConfig, ok := config[common.BaseKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	services, ok := baseConfig[strings.TrimPrefix(common.ConfigServicesKey, common.BaseKey+common.Delim)].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if answers, ok := services[serviceName]; ok {
+		delete(services, serviceName)
+		services[newServiceName] = answers
+	}
+	for key, answers := range services {
+		services[key] = renameServiceInAnswer(answers, serviceName, newServiceName)
+	}
+	log.Debugf("Renamed the service %s to %s in the config file at path %s", serviceName, newServiceName, configPath)
+	return common.WriteYaml(configPath, config)
+}
+
+// renameServiceInCache renames the service in the ids of the problems about the service and in the answers about the services
+func renameServiceInCache(cachePath, serviceName, newServiceName string) error {
+	cache := qatypes.NewCache(cachePath)
+	if err := cache.Load(); err != nil {
+		return err
+	}
+	oldKey := common.ConfigServicesKey + common.Delim + `"` + serviceName + `"`
+	newKey := common.ConfigServicesKey + common.Delim + `"` + newServiceName + `"`
+	for i, problem := range cache.Spec.Problems {
+		if !strings.HasPrefix(problem.ID, common.ConfigServicesKey+common.Delim) {
+			continue
+		}
+		if problem.ID == oldKey || strings.HasPrefix(problem.ID, oldKey+common.Delim) {
+			cache.Spec.Problems[i].ID = newKey + strings.TrimPrefix(problem.ID, oldKey)
+		}
+		cache.Spec.Problems[i].Answer = renameServiceInAnswer(problem.Answer, serviceName, newServiceName)
+		cache.Spec.Problems[i].Default = renameServiceInAnswer(problem.Default, serviceName, newServiceName)
+		for j, option := range problem.Options {
+			if option == serviceName {
+				cache.Spec.Problems[i].Options[j] = newServiceName
+			}
+		}
+	}
+	return cache.Write()
+}
+
+// renameServiceInAnswer renames the service in the answer, if it is the name of the service or a list of names of services
+func renameServiceInAnswer(answer interface{}, serviceName, newServiceName string) interface{} {
+	switch answer := answer.(type) {
+	case string:
+		if answer == serviceName {
+			return newServiceName
+		}
+	case []interface{}:
+		for i, value := range answer {
+			answer[i] = renameServiceInAnswer(value, serviceName, newServiceName)
+		}
+	case []string:
+		for i, value := range answer {
+			if value == serviceName {
+				answer[i] = newServiceName
+			}
+		}
+	case map[string]interface{}:
+		for key, value := range answer {
+			answer[key] = renameServiceInAnswer(value, serviceName, newServiceName)
+		}
+	}
+	return answer
+}
+
+// GetOutputQAFiles returns the config file and the QA cache file recorded by an earlier translation into the output directory, if they exist
+func GetOutputQAFiles(outputPath string) (configPaths, cachePaths []string) {
+	configPaths, cachePaths = []string{}, []string{}
+	if outputPath == "" {
+		return configPaths, cachePaths
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, common.ConfigFile)); err == nil {
+		configPaths = append(configPaths, filepath.Join(outputPath, common.ConfigFile))
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, common.QACacheFile)); err == nil {
+		cachePaths = append(cachePaths, filepath.Join(outputPath, common.QACacheFile))
+	}
+	return configPaths, cachePaths
+}
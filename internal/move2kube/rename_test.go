@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenameService(t *testing.T) {
+	dir := t.TempDir()
+	p := plantypes.NewPlan()
+	p.Name = "tickets"
+	p.Spec.Inputs.RootDir = dir
+	for _, serviceName := range []string{"db", "web"} {
+		service := plantypes.NewService(serviceName, plantypes.Compose2KubeTranslation)
+		service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+		service.Image = serviceName + ":latest"
+		if serviceName == "web" {
+			service.DependsOn = []string{"db"}
+		}
+		p.AddServicesToPlan([]plantypes.Service{service})
+	}
+	planPath := filepath.Join(dir, common.DefaultPlanFile)
+	if err := plantypes.WritePlan(planPath, p); err != nil {
+		t.Fatalf("Failed to write the plan. Error: %q", err)
+	}
+	outputPath := filepath.Join(dir, "output")
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Failed to create the output directory. Error: %q", err)
+	}
+	configPath := filepath.Join(outputPath, common.ConfigFile)
+	config := "move2kube:\n  services:\n    db:\n      containerization:\n        type: Reuse\n    expose:\n      - web\n      - db\n  target:\n    ingress:\n      host: db\n"
+	if err := os.WriteFile(configPath, []byte(config), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the config file. Error: %q", err)
+	}
+	cachePath := filepath.Join(outputPath, common.QACacheFile)
+	cache := qatypes.NewCache(cachePath)
+	cache.Spec.Problems = []qatypes.Problem{
+		{ID: `move2kube.services."db".containerization.type`, Type: qatypes.SelectSolutionFormType, Options: []string{"Reuse"}, Answer: "Reuse"},
+		{ID: "move2kube.services.expose", Type: qatypes.MultiSelectSolutionFormType, Options: []string{"web", "db"}, Answer: []interface{}{"web", "db"}},
+	}
+	if err := cache.Write(); err != nil {
+		t.Fatalf("Failed to write the cache file. Error: %q", err)
+	}
+
+	configs, caches := move2kube.GetOutputQAFiles(outputPath)
+	renamed, err := move2kube.RenameService(planPath, "db", "postgres", configs, caches)
+	if err != nil {
+		t.Fatalf("Failed to rename the service. Error: %q", err)
+	}
+	if _, ok := renamed.Spec.Inputs.Services["postgres"]; !ok || !reflect.DeepEqual(renamed.Spec.Inputs.Services["web"][0].DependsOn, []string{"postgres"}) {
+		t.Fatalf("Failed to rename the service in the plan. Actual: %+v", renamed.Spec.Inputs.Services)
+	}
+	written, err := plantypes.ReadPlan(planPath)
+	if err != nil {
+		t.Fatalf("Failed to read the renamed plan. Error: %q", err)
+	}
+	if _, ok := written.Spec.Inputs.Services["db"]; ok {
+		t.Fatalf("Expected the plan file to be rewritten. Actual: %+v", written.Spec.Inputs.Services)
+	}
+
+	configBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read the renamed config file. Error: %q", err)
+	}
+	actualConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal(configBytes, &actualConfig); err != nil {
+		t.Fatalf("Failed to parse the renamed config file. Error: %q", err)
+	}
+	wantConfig := map[string]interface{}{"move2kube": map[string]interface{}{
+		"services": map[string]interface{}{
+			"postgres": map[string]interface{}{"containerization": map[string]interface{}{"type": "Reuse"}},
+			"expose":   []interface{}{"web", "postgres"},
+		},
+		"target": map[string]interface{}{"ingress": map[string]interface{}{"host": "db"}},
+	}}
+	if !reflect.DeepEqual(actualConfig, wantConfig) {
+		t.Fatalf("Failed to rename the service in the config file. Expected: %+v Actual: %+v", wantConfig, actualConfig)
+	}
+
+	cache = qatypes.NewCache(cachePath)
+	if err := cache.Load(); err != nil {
+		t.Fatalf("Failed to read the renamed cache file. Error: %q", err)
+	}
+	if cache.Spec.Problems[0].ID != `move2kube.services."postgres".containerization.type` || !reflect.DeepEqual(cache.Spec.Problems[1].Answer, []interface{}{"web", "postgres"}) {
+		t.Fatalf("Failed to rename the service in the cache file. Actual: %+v", cache.Spec.Problems)
+	}
+
+	if _, err := move2kube.RenameService(planPath, "db", "mysql", nil, nil); err == nil {
+		t.Fatalf("Expected an error since the service was already renamed")
+	}
+}
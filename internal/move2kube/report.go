@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/licensescan"
+	"github.com/konveyor/move2kube/types"
+	"github.com/konveyor/move2kube/types/info"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+// writeReport writes a summary of the generated artifacts, the problems reported while generating them and the next steps
+// for the teams deploying them
+func writeReport(plan plantypes.Plan, outputPath string, reportedErrors []*common.Error) error {
+	nextSteps, err := GetNextSteps(outputPath)
+	if err != nil {
+		return err
+	}
+	report := strings.Builder{}
+	report.WriteString(fmt.Sprintf("# %s\n\n", plan.Name))
+	report.WriteString(fmt.Sprintf("Generated by %s %s.\n\n", types.AppName, info.GetVersion()))
+	report.WriteString("## Services\n\n")
+	serviceNames := []string{}
+	for serviceName := range plan.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		services := plan.Spec.Inputs.Services[serviceName]
+		if len(services) == 0 {
+			continue
+		}
+		report.WriteString(fmt.Sprintf("- %s : %s using %s\n", serviceName, services[0].TranslationType, services[0].ContainerBuildType))
+	}
+	analysisSummaries := []string{}
+	for _, serviceName := range serviceNames {
+		services := plan.Spec.Inputs.Services[serviceName]
+		if len(services) == 0 || len(services[0].Analysis.Issues) == 0 {
+			continue
+		}
+		analysisSummaries = append(analysisSummaries, getAnalysisSummary(serviceName, services[0].Analysis))
+	}
+	if len(analysisSummaries) > 0 {
+		report.WriteString("\n## Analysis\n\n")
+		report.WriteString(strings.Join(analysisSummaries, ""))
+	}
+	if common.LicenseScan {
+		report.WriteString("\n## Licenses\n\n")
+		for _, serviceName := range serviceNames {
+			services := plan.Spec.Inputs.Services[serviceName]
+			if len(services) == 0 {
+				continue
+			}
+			inventory := licensescan.ScanService(services[0].SourceArtifacts[plantypes.SourceDirectoryArtifactType])
+			report.WriteString(getLicenseSummary(serviceName, inventory))
+		}
+	}
+	if len(reportedErrors) > 0 {
+		report.WriteString("\n## Problems\n\n")
+		for _, reportedErr := range reportedErrors {
+			severity := "error"
+			if reportedErr.Warning {
+				severity = "warning"
+			}
+			report.WriteString(fmt.Sprintf("- %s (%s) : %s\n", reportedErr.Code, severity, reportedErr.Message))
+			if remediation := reportedErr.Remediation(); remediation != "" {
+				report.WriteString(fmt.Sprintf("  Remediation: %s\n", remediation))
+			}
+		}
+	}
+	report.WriteString("\n## Next steps\n\n")
+	if len(nextSteps) == 0 {
+		report.WriteString("None.\n")
+	}
+	for _, nextStep := range nextSteps {
+		relPath, err := filepath.Rel(outputPath, nextStep.FilePath)
+		if err != nil {
+			relPath = nextStep.FilePath
+		}
+		report.WriteString(fmt.Sprintf("- %s (%s) : %s\n", filepath.ToSlash(relPath), strings.TrimPrefix(nextStep.Key, common.TODOAnnotation), nextStep.Description))
+	}
+	reportPath := filepath.Join(outputPath, common.ReportFile)
+	if err := ioutil.WriteFile(reportPath, []byte(report.String()), common.DefaultFilePermission); err != nil {
+		log.Errorf("Failed to write the report to the file at path %s . Error: %q", reportPath, err)
+		return err
+	}
+	return nil
+}
+
+// getAnalysisSummary summarizes the issues found in the source of a service by the rule engines, and lists the mandatory ones
+func getAnalysisSummary(serviceName string, analysis plantypes.Analysis) string {
+	summary := strings.Builder{}
+	counts := []string{}
+	uncategorized := len(analysis.Issues)
+	for _, category := range []plantypes.AnalysisCategoryValue{plantypes.MandatoryAnalysisCategory, plantypes.PotentialAnalysisCategory, plantypes.OptionalAnalysisCategory} {
+		if issues := analysis.GetIssues(category); len(issues) > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", len(issues), category))
+			uncategorized -= len(issues)
+		}
+	}
+	if uncategorized > 0 {
+		counts = append(counts, fmt.Sprintf("%d uncategorized", uncategorized))
+	}
+	summary.WriteString(fmt.Sprintf("- %s : %s issues, with an effort of %d story points\n", serviceName, strings.Join(counts, ", "), analysis.Effort))
+	for _, issue := range analysis.GetIssues(plantypes.MandatoryAnalysisCategory) {
+		summary.WriteString(fmt.Sprintf("  - %s (%d incidents) : %s\n", issue.RuleID, issue.Incidents, strings.SplitN(issue.Description, "\n", 2)[0]))
+	}
+	return summary.String()
+}
+
+// getLicenseSummary summarizes the license and the provenance of the source of a service, and the licenses of its dependencies
+func getLicenseSummary(serviceName string, inventory licensescan.Inventory) string {
+	summary := strings.Builder{}
+	summary.WriteString(fmt.Sprintf("- %s : %s", serviceName, inventory.License))
+	if inventory.Repo != "" {
+		summary.WriteString(fmt.Sprintf(", from %s", inventory.Repo))
+	}
+	if inventory.Commit != "" {
+		summary.WriteString(fmt.Sprintf(" at commit %s", inventory.Commit))
+	}
+	summary.WriteString("\n")
+	if len(inventory.Dependencies) == 0 {
+		return summary.String()
+	}
+	counts := inventory.GetLicenseCounts()
+	licenses := []string{}
+	for license := range counts {
+		licenses = append(licenses, license)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		if counts[licenses[i]] != counts[licenses[j]] {
+			return counts[licenses[i]] > counts[licenses[j]]
+		}
+		return licenses[i] < licenses[j]
+	})
+	licenseCounts := []string{}
+	for _, license := range licenses {
+		licenseCounts = append(licenseCounts, fmt.Sprintf("%d %s", counts[license], license))
+	}
+	summary.WriteString(fmt.Sprintf("  Dependencies: %s\n", strings.Join(licenseCounts, ", ")))
+	if copyleftDependencies := inventory.GetCopyleftDependencies(); len(copyleftDependencies) > 0 {
+		names := []string{}
+		for _, dependency := range copyleftDependencies {
+			names = append(names, fmt.Sprintf("%s %s (%s, %s)", dependency.Name, dependency.Version, dependency.Ecosystem, dependency.License))
+		}
+		summary.WriteString(fmt.Sprintf("  Copyleft dependencies, which have obligations when the image is distributed: %s\n", strings.Join(names, ", ")))
+	}
+	return summary.String()
+}
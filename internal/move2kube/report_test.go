@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const testReportDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  annotations:
+    move2kube.konveyor.io/todo.resources: Set the resource requests of the container.
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+        - name: web
+          image: web:latest
+`
+
+func TestWriteReport(t *testing.T) {
+	outputPath := t.TempDir()
+	yamlsPath := filepath.Join(outputPath, "deploy", "yamls")
+	if err := os.MkdirAll(yamlsPath, common.DefaultDirectoryPermission); err != nil {
+		t.Fatalf("Failed to create the yamls directory. Error: %q", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(yamlsPath, "web-deployment.yaml"), []byte(testReportDeployment), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the yamls. Error: %q", err)
+	}
+	plan := plantypes.NewPlan()
+	plan.Name = "myproject"
+	plan.Spec.Inputs.Services = map[string][]plantypes.Service{
+		"web": {{ServiceName: "web", TranslationType: plantypes.Any2KubeTranslation, ContainerBuildType: plantypes.DockerFileContainerBuildTypeValue}},
+		"db":  {{ServiceName: "db", TranslationType: plantypes.Compose2KubeTranslation, ContainerBuildType: plantypes.ReuseContainerBuildTypeValue}},
+	}
+	reportedErrors := []*common.Error{{Code: common.UnconvertibleResourceErrorCode, Message: "The resource Queue of the type AWS::SQS::Queue is not translated.", Warning: true}}
+
+	if err := move2kube.WriteReport(plan, outputPath, reportedErrors); err != nil {
+		t.Fatalf("Failed to write the report. Error: %q", err)
+	}
+	report, err := ioutil.ReadFile(filepath.Join(outputPath, common.ReportFile))
+	if err != nil {
+		t.Fatalf("Failed to read the report. Error: %q", err)
+	}
+	for _, want := range []string{
+		"# myproject\n",
+		"## Services\n\n- db : DockerCompose using Reuse\n- web : Containerize using NewDockerfile\n",
+		"## Problems\n",
+		"- " + string(common.UnconvertibleResourceErrorCode) + " (warning) : The resource Queue of the type AWS::SQS::Queue is not translated.\n  Remediation: " + common.ErrorCodeRemediations[common.UnconvertibleResourceErrorCode] + "\n",
+		"## Next steps\n\n- deploy/yamls/web-deployment.yaml (resources) : Set the resource requests of the container.\n",
+	} {
+		if !strings.Contains(string(report), want) {
+			t.Fatalf("Expected the report to contain %q . Actual:\n%s", want, string(report))
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	customTemplateExt = ".tpl"
+)
+
+// getTemplateData returns the template data model of the project
+func getTemplateData(ir irtypes.IR) outputtypes.TemplateData {
+	data := outputtypes.TemplateData{
+		Name:     ir.Name,
+		Services: []outputtypes.TemplateService{},
+		Images:   []outputtypes.TemplateImage{},
+		Cluster: outputtypes.TemplateCluster{
+			Type:              ir.Kubernetes.TargetCluster.Type,
+			Host:              ir.TargetClusterSpec.Host,
+			StorageClasses:    ir.TargetClusterSpec.StorageClasses,
+			RegistryURL:       ir.Kubernetes.RegistryURL,
+			RegistryNamespace: ir.Kubernetes.RegistryNamespace,
+		},
+	}
+	for _, service := range ir.Services {
+		templateService := outputtypes.TemplateService{
+			Name:       service.Name,
+			Replicas:   service.Replicas,
+			Exposed:    service.HasValidAnnotation(common.ExposeSelector),
+			Containers: []outputtypes.TemplateContainer{},
+		}
+		if templateService.Exposed {
+			templateService.Path = service.ServiceRelPath
+		}
+		for _, container := range service.Containers {
+			templateContainer := outputtypes.TemplateContainer{Name: container.Name, Image: container.Image}
+			for _, port := range container.Ports {
+				templateContainer.Ports = append(templateContainer.Ports, port.ContainerPort)
+			}
+			for _, env := range container.Env {
+				if env.ValueFrom != nil {
+					continue
+				}
+				if templateContainer.Envs == nil {
+					templateContainer.Envs = map[string]string{}
+				}
+				templateContainer.Envs[env.Name] = env.Value
+			}
+			templateService.Containers = append(templateService.Containers, templateContainer)
+		}
+		data.Services = append(data.Services, templateService)
+	}
+	sort.Slice(data.Services, func(i, j int) bool { return data.Services[i].Name < data.Services[j].Name })
+	for _, container := range ir.Containers {
+		for _, imageName := range container.ImageNames {
+			data.Images = append(data.Images, outputtypes.TemplateImage{Name: imageName, New: container.New, BuildType: string(container.ContainerBuildType)})
+		}
+	}
+	sort.Slice(data.Images, func(i, j int) bool { return data.Images[i].Name < data.Images[j].Name })
+	return data
+}
+
+// renderCustomTemplates renders the templates in the templates directory of the customizations in the source directory
+// into the output directory, at the same relative paths. The .tpl extension is removed from the names of the rendered files.
+func renderCustomTemplates(srcPath string, outputPath string, data outputtypes.TemplateData) error {
+	templatesPath := filepath.Join(srcPath, common.CustomizationsDir, common.CustomTemplatesDir)
+	if _, err := os.Stat(templatesPath); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(templatesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(templatesPath, path)
+		if err != nil {
+			return err
+		}
+		tplData, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Errorf("Failed to read the custom template at path %s . Error: %q", path, err)
+			return err
+		}
+		tpl, err := common.ParseTemplate(string(tplData))
+		if err != nil {
+			log.Errorf("Failed to parse the custom template at path %s . Error: %q", path, err)
+			return err
+		}
+		var rendered bytes.Buffer
+		if err := tpl.Execute(&rendered, data); err != nil {
+			log.Errorf("Failed to render the custom template at path %s . Error: %q", path, err)
+			return err
+		}
+		renderedPath := filepath.Join(outputPath, strings.TrimSuffix(relPath, customTemplateExt))
+		if err := os.MkdirAll(filepath.Dir(renderedPath), common.DefaultDirectoryPermission); err != nil {
+			log.Errorf("Unable to create directory %s : %s", filepath.Dir(renderedPath), err)
+			return err
+		}
+		log.Debugf("Rendering the custom template %s to %s", path, renderedPath)
+		return ioutil.WriteFile(renderedPath, rendered.Bytes(), info.Mode().Perm())
+	})
+}
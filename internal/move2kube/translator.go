@@ -17,19 +17,52 @@ limitations under the License.
 package move2kube
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/containerizer"
 	customize "github.com/konveyor/move2kube/internal/customizer"
 	"github.com/konveyor/move2kube/internal/metadata"
 	optimize "github.com/konveyor/move2kube/internal/optimizer"
+	"github.com/konveyor/move2kube/internal/qaengine"
 	"github.com/konveyor/move2kube/internal/source"
 	transform "github.com/konveyor/move2kube/internal/transformer"
+	"github.com/konveyor/move2kube/types/info"
+	outputtypes "github.com/konveyor/move2kube/types/output"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	log "github.com/sirupsen/logrus"
 )
 
 // Translate translates the artifacts and writes output
-func Translate(plan plantypes.Plan, outputPath string, qadisablecli bool, transformPaths []string) {
+func Translate(plan plantypes.Plan, outputPath string, qadisablecli bool, transformPaths []string) error {
+	// The QA answers are written to the output directory, even when the targets are written to its subdirectories
+	configPath := filepath.Join(outputPath, common.ConfigFile)
+	// The problems found before the translation, like while planning, are in the report of every target
+	planningErrors := common.GetReportedErrors()
+	if len(plan.Spec.Outputs.Targets) == 0 {
+		return translateTarget(plan, outputPath, configPath, "", transformPaths, planningErrors)
+	}
+	// Each target is translated from the sources into its own subdirectory, so that the target cluster can change the generated artifacts
+	for _, target := range plan.Spec.Outputs.Targets {
+		log.Infof("Translating the output target %s of type %s", target.Name, target.Type)
+		targetPlan := plan
+		targetPlan.Spec.Outputs.Kubernetes = target.GetKubernetesOutput(plan.Spec.Outputs.Kubernetes)
+		if err := translateTarget(targetPlan, filepath.Join(outputPath, target.Name), configPath, target.Type, transformPaths, planningErrors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translateTarget translates the artifacts and writes the output of a target. All the transformers are run if the target type is empty.
+// The report of the target lists the planning errors and the problems found while translating the target.
+func translateTarget(plan plantypes.Plan, outputPath, configPath string, targetType plantypes.OutputTargetTypeValue, transformPaths []string, planningErrors []*common.Error) error {
+	common.ResetReportedErrors()
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the output directory at path %s . Error: %q", outputPath, err)
+	}
 	containerBuildTypes := []string{}
 	for _, services := range plan.Spec.Inputs.Services {
 		if len(services) > 0 && !common.IsStringPresent(containerBuildTypes, string(services[0].ContainerBuildType)) {
@@ -37,9 +70,10 @@ func Translate(plan plantypes.Plan, outputPath string, qadisablecli bool, transf
 		}
 	}
 	containerizer.InitContainerizers(plan.Spec.Inputs.RootDir, containerBuildTypes)
+	containerizer.InitOutputStage(plan, outputPath)
 	sourceIR, err := source.Translate(plan)
 	if err != nil {
-		log.Fatalf("Failed to translate the plan to intermediate representation. Error: %q", err)
+		return common.NewError(common.TranslationFailedErrorCode, err, "Failed to translate the plan to intermediate representation.")
 	}
 	log.Debugf("Total storages loaded : %d", len(sourceIR.Storages))
 
@@ -66,11 +100,13 @@ func Translate(plan plantypes.Plan, outputPath string, qadisablecli bool, transf
 	}
 	log.Debugf("Total services optimized : %d", len(optimizedIR.Services))
 
-	composeTransformer := transform.ComposeTransformer{}
-	if err := composeTransformer.Transform(optimizedIR); err != nil {
-		log.Errorf("Error while translating docker compose file. Error: %q", err)
-	} else if err := composeTransformer.WriteObjects(outputPath, nil); err != nil {
-		log.Errorf("Unable to write docker compose objects. Error: %q", err)
+	if targetType == "" || targetType == plantypes.KubernetesOutputTargetType || targetType == plantypes.OpenShiftOutputTargetType {
+		composeTransformer := transform.ComposeTransformer{}
+		if err := composeTransformer.Transform(optimizedIR); err != nil {
+			log.Errorf("Error while translating docker compose file. Error: %q", err)
+		} else if err := composeTransformer.WriteObjects(outputPath, nil); err != nil {
+			log.Errorf("Unable to write docker compose objects. Error: %q", err)
+		}
 	}
 
 	customizedIR, err := customize.Customize(optimizedIR)
@@ -79,10 +115,66 @@ func Translate(plan plantypes.Plan, outputPath string, qadisablecli bool, transf
 		customizedIR = optimizedIR
 	}
 	log.Debugf("Total storages customized : %d", len(customizedIR.Storages))
+	if len(customizedIR.HostPathRemediations) > 0 {
+		// The decisions are recorded in the plan, so that translating the plan again produces the same volumes
+		plan.Spec.Outputs.HostPathRemediations = customizedIR.HostPathRemediations
+		planPath := filepath.Join(outputPath, common.DefaultPlanFile)
+		if err := plantypes.WritePlan(planPath, plan); err != nil {
+			log.Warnf("Failed to write the plan with the decisions for the host paths to the file at path %s . Error: %q", planPath, err)
+		} else {
+			log.Infof("The plan with the decisions for the host paths is at path %s", planPath)
+		}
+	}
+
+	templateData := getTemplateData(customizedIR)
+	common.ProjectTemplateData = templateData
+	if err := transform.TransformTarget(customizedIR, outputPath, transformPaths, targetType); err != nil {
+		return common.NewError(common.TranslationFailedErrorCode, err, "Failed to transform the intermediate representation into the artifacts.")
+	}
+	if err := renderCustomTemplates(plan.Spec.Inputs.RootDir, outputPath, templateData); err != nil {
+		log.Errorf("Failed to render the custom templates. Error: %q", err)
+	}
 
-	if err := transform.Transform(customizedIR, outputPath, transformPaths); err != nil {
-		log.Fatalf("Error occurred while running the customizers. Error: %q", err)
+	containerizer.FinishOutputStage()
+	images := []outputtypes.ManifestImage{}
+	if common.BuildImages {
+		images = buildImages(customizedIR.Containers, outputPath)
+	}
+	reportedErrors := append(append([]*common.Error{}, planningErrors...), common.GetReportedErrors()...)
+	if err := writeReport(plan, outputPath, reportedErrors); err != nil {
+		log.Warnf("Failed to write the report of the generated artifacts. Error: %q", err)
+	}
+	if err := writeManifest(plan, outputPath, configPath, images); err != nil {
+		log.Warnf("Failed to write the manifest of the generated artifacts. Error: %q", err)
 	}
 
 	log.Info("Execution completed")
+	return nil
+}
+
+// writeManifest records the checksums of the generated artifacts along with the version, plan and QA answers used to generate them,
+// and the digests of the images built locally. The QA answers are read from the config file at configPath.
+func writeManifest(plan plantypes.Plan, outputPath, configPath string, images []outputtypes.ManifestImage) error {
+	if err := qaengine.WriteStoresToDisk(); err != nil {
+		log.Warnf("Failed to write the stores to disk. Error: %q", err)
+	}
+	manifest := outputtypes.NewManifest(plan.Name, info.GetVersion())
+	planHash, err := plan.GetSHA256Hash()
+	if err != nil {
+		log.Errorf("Failed to calculate the checksum of the plan. Error: %q", err)
+		return err
+	}
+	manifest.Spec.PlanSHA256 = planHash
+	if _, err := os.Stat(configPath); err == nil {
+		if manifest.Spec.QAAnswersSHA256, err = common.GetFileSHA256Hash(configPath); err != nil {
+			log.Errorf("Failed to calculate the checksum of the QA answers at path %s . Error: %q", configPath, err)
+			return err
+		}
+	}
+	if err := manifest.AddFiles(outputPath); err != nil {
+		log.Errorf("Failed to calculate the checksums of the files in the output directory %s . Error: %q", outputPath, err)
+		return err
+	}
+	manifest.Spec.Images = images
+	return outputtypes.WriteManifest(outputPath, manifest)
 }
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestWriteManifestOfTargets(t *testing.T) {
+	outputPath := t.TempDir()
+	configPath := filepath.Join(outputPath, common.ConfigFile)
+	if err := ioutil.WriteFile(configPath, []byte("move2kube:\n  target:\n    imageregistry:\n      url: quay.io\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the QA answers at path %s . Error: %q", configPath, err)
+	}
+	wantHash, err := common.GetFileSHA256Hash(configPath)
+	if err != nil {
+		t.Fatalf("Failed to calculate the checksum of the QA answers. Error: %q", err)
+	}
+	for _, target := range []string{"dev", "prod"} {
+		targetPath := filepath.Join(outputPath, target)
+		if err := os.MkdirAll(filepath.Join(targetPath, "deploy"), common.DefaultDirectoryPermission); err != nil {
+			t.Fatalf("Failed to create the output directory of the target %s . Error: %q", target, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(targetPath, "deploy", "web-deployment.yaml"), []byte("kind: Deployment\n"), 0644); err != nil {
+			t.Fatalf("Failed to write the output of the target %s . Error: %q", target, err)
+		}
+		if err := move2kube.WriteManifest(plantypes.NewPlan(), targetPath, configPath, nil); err != nil {
+			t.Fatalf("Failed to write the manifest of the target %s . Error: %q", target, err)
+		}
+		manifest, err := outputtypes.ReadManifest(targetPath)
+		if err != nil {
+			t.Fatalf("Failed to read the manifest of the target %s . Error: %q", target, err)
+		}
+		if manifest.Spec.QAAnswersSHA256 != wantHash {
+			t.Fatalf("Expected the checksum %s of the QA answers in the manifest of the target %s . Actual: %q", wantHash, target, manifest.Spec.QAAnswersSHA256)
+		}
+		if len(manifest.Spec.Files) != 1 || manifest.Spec.Files[0].Path != "deploy/web-deployment.yaml" {
+			t.Fatalf("Expected only the files of the target %s in its manifest. Actual: %+v", target, manifest.Spec.Files)
+		}
+	}
+}
+
+func TestTranslateReportsOfTargets(t *testing.T) {
+	setupAssets(t)
+	defer os.RemoveAll(common.TempPath)
+	qaengine.StartEngine(true, 0, false, "")
+
+	srcPath, err := filepath.Abs(filepath.Join("testdata", "migrate"))
+	if err != nil {
+		t.Fatalf("Failed to make the source path absolute. Error: %q", err)
+	}
+	common.ResetReportedErrors()
+	p, err := move2kube.CreatePlan(srcPath, "myproject", true)
+	if err != nil {
+		t.Fatalf("Failed to create the plan of the source directory %s . Error: %q", srcPath, err)
+	}
+	// The deployment of the source has no resource requests, which are required by GKE Autopilot
+	p.Spec.Outputs.Targets = []plantypes.OutputTarget{
+		{Name: "autopilot", Type: plantypes.KubernetesOutputTargetType, Kubernetes: plantypes.KubernetesOutput{TargetCluster: plantypes.TargetClusterType{Type: "GCP-GKE-Autopilot"}}},
+		{Name: "dev", Type: plantypes.KubernetesOutputTargetType},
+	}
+	planningErr := common.NewError(common.UnconvertibleResourceErrorCode, nil, "The resource Queue of the type AWS::SQS::Queue is not translated.")
+	planningErr.Warning = true
+	common.ReportError(planningErr)
+
+	outputPath := t.TempDir()
+	if err := move2kube.Translate(p, outputPath, true, nil); err != nil {
+		t.Fatalf("Failed to translate the plan. Error: %q", err)
+	}
+	violation := "- " + string(common.ConstraintViolationErrorCode) + " "
+	for _, target := range []struct {
+		name          string
+		wantViolation bool
+	}{{name: "autopilot", wantViolation: true}, {name: "dev", wantViolation: false}} {
+		reportPath := filepath.Join(outputPath, target.name, common.ReportFile)
+		report, err := ioutil.ReadFile(reportPath)
+		if err != nil {
+			t.Fatalf("Failed to read the report of the target %s . Error: %q", target.name, err)
+		}
+		if !strings.Contains(string(report), "- "+string(common.UnconvertibleResourceErrorCode)+" (warning) : "+planningErr.Message+"\n") {
+			t.Fatalf("Expected the problem found while planning in the report of the target %s . Actual:\n%s", target.name, string(report))
+		}
+		if strings.Contains(string(report), violation) != target.wantViolation {
+			t.Fatalf("Expected the constraint violations in the report of the target %s : %t . Actual:\n%s", target.name, target.wantViolation, string(report))
+		}
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	"github.com/konveyor/move2kube/types/info"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const (
+	// StableReleaseChannel contains only the releases that are not pre-releases
+	StableReleaseChannel = "stable"
+	// PreReleaseChannel contains all the releases including the pre-releases
+	PreReleaseChannel = "prerelease"
+	// ReleasesURLEnvVar overrides the URL used to list the releases. It can point to a mirror of the GitHub releases API.
+	ReleasesURLEnvVar  = "M2K_RELEASES_URL"
+	defaultReleasesURL = "https://api.github.com/repos/konveyor/move2kube/releases"
+	// maxDownloadSize limits the size of the downloaded archives
+	maxDownloadSize = 512 * 1024 * 1024
+)
+
+// Release is a published release of move2kube
+type Release struct {
+	TagName    string         `json:"tag_name"`
+	Prerelease bool           `json:"prerelease"`
+	Draft      bool           `json:"draft"`
+	Assets     []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release
+type ReleaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// GetLatestRelease returns the newest release in the channel
+func GetLatestRelease(channel string) (Release, error) {
+	if common.Offline {
+		return Release{}, fmt.Errorf("unable to look for new releases in offline mode")
+	}
+	if channel != StableReleaseChannel && channel != PreReleaseChannel {
+		return Release{}, fmt.Errorf("unsupported release channel %s . Supported channels are %s and %s", channel, StableReleaseChannel, PreReleaseChannel)
+	}
+	releasesURL := os.Getenv(ReleasesURLEnvVar)
+	if releasesURL == "" {
+		releasesURL = defaultReleasesURL
+	}
+	body, err := download(releasesURL)
+	if err != nil {
+		return Release{}, err
+	}
+	releases := []Release{}
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return Release{}, fmt.Errorf("failed to parse the releases from %s . Error: %q", releasesURL, err)
+	}
+	var latest *Release
+	var latestVersion *semver.Version
+	for i, release := range releases {
+		if release.Draft || (release.Prerelease && channel == StableReleaseChannel) {
+			continue
+		}
+		version, err := semver.NewVersion(release.TagName)
+		if err != nil {
+			log.Debugf("Ignoring the release %s since its tag is not a semantic version. Error: %q", release.TagName, err)
+			continue
+		}
+		if latestVersion == nil || version.GreaterThan(latestVersion) {
+			latest, latestVersion = &releases[i], version
+		}
+	}
+	if latest == nil {
+		return Release{}, fmt.Errorf("no releases found in the %s channel", channel)
+	}
+	return *latest, nil
+}
+
+// IsNewerRelease returns true if the release is newer than the running binary
+func IsNewerRelease(release Release) (bool, error) {
+	current, err := semver.NewVersion(info.GetVersion())
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the current version %s . Error: %q", info.GetVersion(), err)
+	}
+	latest, err := semver.NewVersion(release.TagName)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the version of the release %s . Error: %q", release.TagName, err)
+	}
+	return latest.GreaterThan(current), nil
+}
+
+// getPlanAPIVersion returns the apiVersion of the plan file without decoding the rest of the plan
+func getPlanAPIVersion(planPath string) (string, error) {
+	planBytes, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the plan file at path %s . Error: %q", planPath, err)
+	}
+	typeMeta := types.TypeMeta{}
+	if err := yaml.Unmarshal(planBytes, &typeMeta); err != nil {
+		return "", fmt.Errorf("failed to parse the plan file at path %s . Error: %q", planPath, err)
+	}
+	return typeMeta.APIVersion, nil
+}
+
+// CheckPlanCompatibility returns an error if the plan file was written for a different plan apiVersion than the one supported by the running binary
+func CheckPlanCompatibility(planPath string) error {
+	apiVersion, err := getPlanAPIVersion(planPath)
+	if err != nil {
+		return err
+	}
+	if apiVersion == plantypes.SchemeGroupVersion.String() {
+		return nil
+	}
+	if plantypes.IsOlderPlanVersion(apiVersion) {
+		return fmt.Errorf("the plan file at path %s has the older apiVersion %s . It is migrated to %s when read. Use the plan upgrade command to rewrite it", planPath, apiVersion, plantypes.SchemeGroupVersion.String())
+	}
+	return fmt.Errorf("the plan file at path %s has the apiVersion %s but this version of %s supports %s", planPath, apiVersion, types.AppName, plantypes.SchemeGroupVersion.String())
+}
+
+// UpgradePlan rewrites the plan file using the current plan apiVersion and returns the apiVersion the plan had.
+// A copy of the old plan file is kept next to it.
+func UpgradePlan(planPath string) (string, error) {
+	apiVersion, err := getPlanAPIVersion(planPath)
+	if err != nil {
+		return "", err
+	}
+	if apiVersion == plantypes.SchemeGroupVersion.String() {
+		return apiVersion, nil
+	}
+	plan, err := plantypes.ReadPlan(planPath)
+	if err != nil {
+		return apiVersion, fmt.Errorf("failed to read the plan file at path %s . Error: %q", planPath, err)
+	}
+	backupPath := planPath + "." + path.Base(apiVersion) + ".bak"
+	if err := common.CopyFile(backupPath, planPath); err != nil {
+		return apiVersion, fmt.Errorf("failed to copy the plan file at path %s to %s . Error: %q", planPath, backupPath, err)
+	}
+	if err := plantypes.WritePlan(planPath, plan); err != nil {
+		return apiVersion, fmt.Errorf("failed to write the plan file at path %s . Error: %q", planPath, err)
+	}
+	log.Infof("The old plan file was copied to %s", backupPath)
+	return apiVersion, nil
+}
+
+// Upgrade replaces the running binary with the one in the release after verifying its checksum
+func Upgrade(release Release) error {
+	format := common.TarGzArchiveFormat
+	if runtime.GOOS == "windows" {
+		format = common.ZipArchiveFormat
+	}
+	archiveName := fmt.Sprintf("%s-%s-%s-%s.%s", types.AppName, release.TagName, runtime.GOOS, runtime.GOARCH, format)
+	archiveURL, checksumURL := "", ""
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case archiveName:
+			archiveURL = asset.BrowserDownloadURL
+		case archiveName + common.SHA256SumSuffix:
+			checksumURL = asset.BrowserDownloadURL
+		}
+	}
+	if archiveURL == "" || checksumURL == "" {
+		return fmt.Errorf("the release %s does not contain the archive %s and its checksum", release.TagName, archiveName)
+	}
+	checksumFile, err := download(checksumURL)
+	if err != nil {
+		return err
+	}
+	checksumFields := strings.Fields(string(checksumFile))
+	if len(checksumFields) == 0 {
+		return fmt.Errorf("the checksum file at %s is empty", checksumURL)
+	}
+	archive, err := download(archiveURL)
+	if err != nil {
+		return err
+	}
+	if checksum := common.GetSHA256Hash(string(archive)); checksum != checksumFields[0] {
+		return fmt.Errorf("the checksum %s of the archive %s does not match the published checksum %s", checksum, archiveName, checksumFields[0])
+	}
+	log.Infof("Verified the checksum of %s", archiveName)
+	binName := types.AppName
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	binary, err := extractFile(archive, format, binName)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s from the archive %s . Error: %q", binName, archiveName, err)
+	}
+	return replaceExecutable(binary)
+}
+
+// download fetches the contents of the URL
+func download(url string) ([]byte, error) {
+	if common.Offline {
+		return nil, fmt.Errorf("unable to download %s in offline mode", url)
+	}
+	client := &http.Client{}
+	if common.CommandTimeout > 0 {
+		client.Timeout = common.CommandTimeout
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s . Error: %q", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s . Status: %s", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s . Error: %q", url, err)
+	}
+	return body, nil
+}
+
+// extractFile returns the contents of the first file in the archive with the given name
+func extractFile(archive []byte, format, name string) ([]byte, error) {
+	if format == common.ZipArchiveFormat {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != name || f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return ioutil.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("file not found")
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("file not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == name {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
+
+// replaceExecutable replaces the running binary. The old binary is renamed first since a running binary cannot be overwritten on Windows.
+func replaceExecutable(binary []byte) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the path of the running binary. Error: %q", err)
+	}
+	if exePath, err = filepath.EvalSymlinks(exePath); err != nil {
+		return fmt.Errorf("failed to resolve the path of the running binary. Error: %q", err)
+	}
+	newPath, oldPath := exePath+".new", exePath+".old"
+	if err := ioutil.WriteFile(newPath, binary, common.DefaultExecutablePermission); err != nil {
+		return fmt.Errorf("failed to write the new binary to %s . Error: %q", newPath, err)
+	}
+	_ = os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		_ = os.Remove(newPath)
+		return fmt.Errorf("failed to move the running binary at path %s . Error: %q", exePath, err)
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		if rerr := os.Rename(oldPath, exePath); rerr != nil {
+			log.Errorf("Failed to restore the old binary from %s . Error: %q", oldPath, rerr)
+		}
+		return fmt.Errorf("failed to move the new binary to %s . Error: %q", exePath, err)
+	}
+	if err := os.Remove(oldPath); err != nil {
+		log.Debugf("Failed to remove the old binary at path %s . Error: %q", oldPath, err)
+	}
+	log.Infof("Upgraded the binary at path %s", exePath)
+	return nil
+}
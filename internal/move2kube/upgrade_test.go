@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/move2kube"
+)
+
+func TestGetLatestRelease(t *testing.T) {
+	releases := `[
+		{"tag_name": "v0.3.0-beta.1", "prerelease": true, "draft": false},
+		{"tag_name": "v0.4.0", "prerelease": false, "draft": true},
+		{"tag_name": "v0.2.1", "prerelease": false, "draft": false},
+		{"tag_name": "nightly", "prerelease": false, "draft": false},
+		{"tag_name": "v0.2.0", "prerelease": false, "draft": false}
+	]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if _, err := w.Write([]byte(releases)); err != nil {
+			t.Errorf("Failed to write the response. Error: %q", err)
+		}
+	}))
+	defer server.Close()
+	os.Setenv(move2kube.ReleasesURLEnvVar, server.URL)
+	defer os.Unsetenv(move2kube.ReleasesURLEnvVar)
+
+	t.Run("stable channel", func(t *testing.T) {
+		release, err := move2kube.GetLatestRelease(move2kube.StableReleaseChannel)
+		if err != nil {
+			t.Fatalf("Failed to get the latest release. Error: %q", err)
+		}
+		if release.TagName != "v0.2.1" {
+			t.Fatalf("Expected the latest stable release to be v0.2.1 . Actual: %s", release.TagName)
+		}
+	})
+
+	t.Run("prerelease channel", func(t *testing.T) {
+		release, err := move2kube.GetLatestRelease(move2kube.PreReleaseChannel)
+		if err != nil {
+			t.Fatalf("Failed to get the latest release. Error: %q", err)
+		}
+		if release.TagName != "v0.3.0-beta.1" {
+			t.Fatalf("Expected the latest release to be v0.3.0-beta.1 . Actual: %s", release.TagName)
+		}
+	})
+
+	t.Run("offline mode", func(t *testing.T) {
+		common.Offline = true
+		defer func() { common.Offline = false }()
+		if _, err := move2kube.GetLatestRelease(move2kube.StableReleaseChannel); err == nil {
+			t.Fatalf("Expected an error in offline mode")
+		}
+	})
+}
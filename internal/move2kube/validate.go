@@ -17,50 +17,103 @@ limitations under the License.
 package move2kube
 
 import (
-	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/k8sschema"
+	outputtypes "github.com/konveyor/move2kube/types/output"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// NextStep is a manual step found in the annotations of the generated artifacts
+type NextStep struct {
+	FilePath    string
+	Key         string
+	Description string
+}
+
 // PrintValidate - Print validate output
 func PrintValidate(inputPath string) error {
+	printManualEdits(inputPath)
+	nextSteps, err := GetNextSteps(inputPath)
+	if err != nil {
+		return err
+	}
+	for _, nextStep := range nextSteps {
+		log.Infof("%s : %s", nextStep.Key, nextStep.Description)
+	}
+	return nil
+}
+
+// GetNextSteps aggregates the next steps from the annotations of the artifacts in the directory
+func GetNextSteps(inputPath string) ([]NextStep, error) {
 	codecs := serializer.NewCodecFactory(k8sschema.GetSchema())
 
 	filePaths, err := common.GetFilesByExt(inputPath, []string{".yml", ".yaml"})
 	if err != nil {
 		log.Errorf("Unable to fetch yaml files at path %q Error: %q", inputPath, err)
-		return err
+		return nil, err
 	}
+	nextSteps := []NextStep{}
 	for _, filePath := range filePaths {
-		data, err := ioutil.ReadFile(filePath)
-		if err != nil {
-			log.Debugf("Failed to read the yaml file at path %q Error: %q", filePath, err)
-			continue
-		}
-		docs, err := common.SplitYAML(data)
-		if err != nil {
-			log.Debugf("Failed to split the file at path %q into YAML documents. Error: %q", filePath, err)
-			continue
-		}
-		for _, doc := range docs {
+		err := common.StreamYAMLFile(filePath, func(doc []byte) error {
 			obj, _, err := codecs.UniversalDeserializer().Decode(doc, nil, nil)
 			if err != nil {
-				continue
+				return nil
 			}
 			objectMeta := reflect.ValueOf(obj).Elem().FieldByName("ObjectMeta").Interface().(metav1.ObjectMeta)
-			for k, v := range objectMeta.Annotations {
+			keys := []string{}
+			for k := range objectMeta.Annotations {
 				if strings.HasPrefix(k, common.TODOAnnotation) {
-					log.Infof("%s : %s", k, v)
+					keys = append(keys, k)
 				}
 			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				nextSteps = append(nextSteps, NextStep{FilePath: filePath, Key: k, Description: objectMeta.Annotations[k]})
+			}
+			return nil
+		})
+		if err != nil {
+			log.Debugf("Failed to read the yaml file at path %q Error: %q", filePath, err)
 		}
 	}
-	return nil
+	return nextSteps, nil
+}
+
+// printManualEdits prints the files that changed since the artifacts were generated, if the artifacts have a manifest
+func printManualEdits(inputPath string) {
+	if _, err := os.Stat(filepath.Join(inputPath, common.ManifestFile)); err != nil {
+		log.Debugf("No manifest found in the directory %s . Error: %q", inputPath, err)
+		return
+	}
+	manifest, err := outputtypes.ReadManifest(inputPath)
+	if err != nil {
+		return
+	}
+	diff, err := manifest.Verify(inputPath)
+	if err != nil {
+		log.Warnf("Failed to verify the artifacts in the directory %s using the manifest. Error: %q", inputPath, err)
+		return
+	}
+	if diff.IsEmpty() {
+		log.Infof("The artifacts have not changed since they were generated.")
+		return
+	}
+	for _, path := range diff.Modified {
+		log.Infof("Modified since generation : %s", path)
+	}
+	for _, path := range diff.Missing {
+		log.Infof("Removed since generation : %s", path)
+	}
+	for _, path := range diff.Added {
+		log.Infof("Added since generation : %s", path)
+	}
 }
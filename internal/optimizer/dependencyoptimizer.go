@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimize
+
+import (
+	"fmt"
+	"sort"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	waitForImage           = "busybox:1.33"
+	waitForContainerPrefix = "wait-for-"
+)
+
+// dependencyOptimizer adds init containers which wait for the services on which the service depends to accept connections
+type dependencyOptimizer struct {
+}
+
+func (opt *dependencyOptimizer) optimize(ir irtypes.IR) (irtypes.IR, error) {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		for _, dependency := range service.DependsOn {
+			dependencyService, ok := ir.Services[dependency]
+			if !ok {
+				log.Debugf("The service %s depends on the service %s which was not translated", serviceName, dependency)
+				continue
+			}
+			port := getDependencyPort(dependencyService)
+			if port == 0 {
+				log.Infof("Not waiting for the service %s before starting the service %s , since it has no ports", dependency, serviceName)
+				continue
+			}
+			name := waitForContainerPrefix + dependency
+			found := false
+			for _, initContainer := range service.InitContainers {
+				if initContainer.Name == name {
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+			service.InitContainers = append(service.InitContainers, core.Container{
+				Name:    name,
+				Image:   waitForImage,
+				Command: []string{"sh", "-c", fmt.Sprintf("until nc -z %s %d; do echo waiting for %s; sleep 2; done", dependency, port, dependency)},
+			})
+		}
+		ir.Services[serviceName] = service
+	}
+	return ir, nil
+}
+
+// getDependencyPort returns the port of the k8s service, or else of the containers, to which the dependent services connect
+func getDependencyPort(service irtypes.Service) int32 {
+	for _, forwarding := range service.ServiceToPodPortForwardings {
+		if forwarding.ServicePort.Number != 0 {
+			return forwarding.ServicePort.Number
+		}
+	}
+	for _, container := range service.Containers {
+		for _, port := range container.Ports {
+			return port.ContainerPort
+		}
+	}
+	return 0
+}
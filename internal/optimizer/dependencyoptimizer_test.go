@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimize
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestDependencyOptimizer(t *testing.T) {
+	ir := types.NewIR(plantypes.NewPlan())
+	db := types.NewServiceWithName("db")
+	db.Containers = []core.Container{{Name: "db", Ports: []core.ContainerPort{{ContainerPort: 5432}}}}
+	worker := types.NewServiceWithName("worker")
+	worker.Containers = []core.Container{{Name: "worker"}}
+	web := types.NewServiceWithName("web")
+	web.Containers = []core.Container{{Name: "web"}}
+	web.DependsOn = []string{"db", "worker", "cache"}
+	ir.Services = map[string]types.Service{db.Name: db, worker.Name: worker, web.Name: web}
+
+	opt := dependencyOptimizer{}
+	for i := 0; i < 2; i++ {
+		actual, err := opt.optimize(ir)
+		if err != nil {
+			t.Fatalf("Failed to optimize the IR. Error: %q", err)
+		}
+		initContainers := actual.Services["web"].InitContainers
+		if len(initContainers) != 1 || initContainers[0].Name != "wait-for-db" || initContainers[0].Image != waitForImage {
+			t.Fatalf("Expected a single init container waiting for the db service. Actual: %+v", initContainers)
+		}
+		wantCommand := "until nc -z db 5432; do echo waiting for db; sleep 2; done"
+		if len(initContainers[0].Command) != 3 || initContainers[0].Command[2] != wantCommand {
+			t.Fatalf("Failed to wait for the port of the db service. Expected: %s Actual: %v", wantCommand, initContainers[0].Command)
+		}
+		if len(actual.Services["db"].InitContainers) != 0 {
+			t.Fatalf("Expected no init containers for the db service. Actual: %+v", actual.Services["db"].InitContainers)
+		}
+		ir = actual
+	}
+}
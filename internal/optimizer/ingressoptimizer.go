@@ -42,7 +42,8 @@ func (opt *ingressOptimizer) optimize(ir irtypes.IR) (irtypes.IR, error) {
 	exposedServiceNames := []string{}
 	for serviceName, service := range ir.Services {
 		serviceNames = append(serviceNames, serviceName)
-		if service.ServiceRelPath != "" {
+		// The services whose ports are all exposed without the ingress in the plan are not exposed by default
+		if service.ServiceRelPath != "" && !service.HasOnlyNonIngressPorts() {
 			exposedServiceNames = append(exposedServiceNames, serviceName)
 		}
 	}
@@ -61,6 +62,13 @@ func (opt *ingressOptimizer) optimize(ir irtypes.IR) (irtypes.IR, error) {
 		if len(exposedServiceNames) == 1 {
 			hints = []string{"Since there's only one exposed service, the default path is /"}
 			exposedServiceRelPath = "/"
+		} else if ir.Services[exposedServiceName].ServiceRelPath == "/" {
+			hints = []string{"The service is the default route, like the nginx image of a static site, so the default path is /"}
+			exposedServiceRelPath = "/"
+		}
+		if contextRoot := ir.Services[exposedServiceName].ContextRoot; contextRoot != "" {
+			hints = []string{"The context root of the JEE app in its deployment descriptors is " + contextRoot}
+			exposedServiceRelPath = contextRoot
 		}
 		exposedServiceRelPath = qaengine.FetchStringAnswer(key, message, hints, exposedServiceRelPath)
 		log.Debugf("Exposing service %s on path %s", exposedServiceName, exposedServiceRelPath)
@@ -81,11 +81,6 @@ func (*portMergeOptimizer) gatherPorts(ir irtypes.IR, service irtypes.Service) m
 
 func (*portMergeOptimizer) exposePorts(service *irtypes.Service, portToContainerIdx map[int]int) {
 	for port, coreContainerIdx := range portToContainerIdx {
-		// Add the port to the k8s pod.
-		service.Containers[coreContainerIdx].Ports = append(service.Containers[coreContainerIdx].Ports, core.ContainerPort{ContainerPort: int32(port)})
-		// Forward the port on the k8s service to the k8s pod.
-		podPort := irtypes.Port{Number: int32(port)}
-		servicePort := podPort
-		service.AddPortForwarding(servicePort, podPort)
+		service.AddContainerPort(coreContainerIdx, core.ContainerPort{ContainerPort: int32(port)})
 	}
 }
@@ -17,8 +17,6 @@ limitations under the License.
 package parameterize
 
 import (
-	"strings"
-
 	"github.com/konveyor/move2kube/internal/common"
 	irtypes "github.com/konveyor/move2kube/internal/types"
 	outputtypes "github.com/konveyor/move2kube/types/output"
@@ -44,22 +42,25 @@ func (it imageNameParameterizer) parameterize(ir *irtypes.IR) error {
 			Containers: map[string]outputtypes.Container{},
 		}
 		for ci, serviceContainer := range service.Containers {
+			ref := common.ParseImageReference(serviceContainer.Image)
 			nImageName := ""
-			parts := strings.Split(serviceContainer.Image, "/")
-			if len(parts) == 3 {
-				nImageName += parts[0] + "/"
+			if ref.Registry != "" {
+				nImageName += ref.Registry + "/"
 			}
-			if len(parts) > 1 {
-				nImageName += parts[1] + "/"
+			if ref.Namespace != "" {
+				nImageName += ref.Namespace + "/"
 			}
 			if common.IsStringPresent(newimages, serviceContainer.Image) {
 				nImageName = outputtypes.ParameterRegistryPrefix
 			}
-			imageName := parts[len(parts)-1]
-			im, tag := common.GetImageNameAndTag(imageName)
+			_, tag := common.GetImageNameAndTag(serviceContainer.Image)
 			ir.Values.Services[service.Name].Containers[serviceContainer.Name] = outputtypes.Container{TagName: tag}
 			newTag := "{{ index .Values." + outputtypes.ServicesTag + " \"" + service.Name + "\" \"" + outputtypes.ContainersTag + "\" \"" + serviceContainer.Name + "\" \"" + outputtypes.ImageTagTag + "\"  }}"
-			nImageName += im + ":" + newTag
+			nImageName += ref.Repository + ":" + newTag
+			if ref.Digest != "" {
+				// The digest takes precedence over the tag, so the image stays pinned
+				nImageName += "@" + ref.Digest
+			}
 			serviceContainer.Image = nImageName
 			service.Containers[ci] = serviceContainer
 		}
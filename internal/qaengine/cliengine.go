@@ -21,10 +21,16 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/konveyor/move2kube/internal/common"
 	qatypes "github.com/konveyor/move2kube/types/qaengine"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	confirmYesOption = "Yes"
+	confirmNoOption  = "No"
+)
+
 // CliEngine handles the CLI based qa
 type CliEngine struct {
 }
@@ -77,7 +83,7 @@ func (*CliEngine) fetchSelectAnswer(prob qatypes.Problem) (qatypes.Problem, erro
 	}
 	prompt := &survey.Select{
 		Message: getQAMessage(prob),
-		Options: prob.Options,
+		Options: append(append([]string{}, prob.Options...), qatypes.DeferAnswer),
 		Default: def,
 	}
 	if err := survey.AskOne(prompt, &ans); err != nil {
@@ -91,30 +97,41 @@ func (*CliEngine) fetchMultiSelectAnswer(prob qatypes.Problem) (qatypes.Problem,
 	ans := []string{}
 	prompt := &survey.MultiSelect{
 		Message: getQAMessage(prob),
-		Options: prob.Options,
+		Options: append(append([]string{}, prob.Options...), qatypes.DeferAnswer),
 		Default: prob.Default,
 	}
 	tickIcon := func(icons *survey.IconSet) { icons.MarkedOption.Text = "[\u2713]" }
 	if err := survey.AskOne(prompt, &ans, survey.WithIcons(tickIcon)); err != nil {
 		log.Fatalf("Error while asking a question : %s", err)
 	}
+	if common.IsStringPresent(ans, qatypes.DeferAnswer) {
+		prob.Answer = qatypes.DeferAnswer
+		return prob, nil
+	}
 	prob.Answer = ans
 	return prob, nil
 }
 
 func (*CliEngine) fetchConfirmAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
-	var ans, def bool
-	if prob.Default != nil {
-		def = prob.Default.(bool)
+	// The confirm prompt only accepts yes or no, so the question is asked as a select to allow deferring it
+	var ans string
+	def := confirmNoOption
+	if prob.Default != nil && prob.Default.(bool) {
+		def = confirmYesOption
 	}
-	prompt := &survey.Confirm{
+	prompt := &survey.Select{
 		Message: getQAMessage(prob),
+		Options: []string{confirmYesOption, confirmNoOption, qatypes.DeferAnswer},
 		Default: def,
 	}
 	if err := survey.AskOne(prompt, &ans); err != nil {
 		log.Fatalf("Error while asking a question : %s", err)
 	}
-	prob.Answer = ans
+	if ans == qatypes.DeferAnswer {
+		prob.Answer = qatypes.DeferAnswer
+		return prob, nil
+	}
+	prob.Answer = ans == confirmYesOption
 	return prob, nil
 }
 
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// deferredStore collects the problems whose answers were deferred into the follow-up answers file
+	deferredStore *qatypes.Cache
+	// reusePreviousAnswers loads the cache written by the previous run instead of starting a new one
+	reusePreviousAnswers bool
+)
+
+// SetupDeferredFile collects the problems whose answers are deferred into the follow-up answers file in the output directory.
+// With onlyDeferred, the answers filled in the follow-up answers file and the cache of the previous run are reused, so that
+// only the deferred problems which are still unanswered are asked again. It should be called before SetupCacheFile.
+func SetupDeferredFile(outputPath string, onlyDeferred bool) error {
+	deferredPath := filepath.Join(outputPath, common.QADeferredFile)
+	if onlyDeferred {
+		e := &StoreEngine{store: qatypes.NewCacheInStorage(deferredPath, storage)}
+		if err := AddEngineHighestPriority(e); err != nil {
+			return fmt.Errorf("failed to read the deferred answers of the previous run from %s . Error: %q", deferredPath, err)
+		}
+		reusePreviousAnswers = true
+	}
+	deferredStore = qatypes.NewCacheInStorage(deferredPath, storage)
+	return deferredStore.Write()
+}
+
+// GetDeferredProblems returns the problems whose answers were deferred
+func GetDeferredProblems() []qatypes.Problem {
+	if deferredStore == nil {
+		return nil
+	}
+	return deferredStore.Spec.Problems
+}
+
+// deferProblem records the problem in the follow-up answers file and answers it with a placeholder. The placeholder is not
+// added to the config and the cache, so that the problem is asked again in the second pass.
+func deferProblem(prob qatypes.Problem) qatypes.Problem {
+	if deferredStore != nil {
+		if err := deferredStore.AddProblem(prob); err != nil {
+			log.Errorf("Failed to record the deferred problem %s . Error: %q", prob.ID, err)
+		}
+	}
+	prob.Answer = prob.GetDeferredAnswer()
+	log.Warnf("The answer to [%s] was deferred. Using %v until it is answered.", prob.Desc, prob.Answer)
+	return prob
+}
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+// deferringEngine defers the answers of its problems
+type deferringEngine struct {
+	ids []string
+}
+
+func (*deferringEngine) StartEngine() error {
+	return nil
+}
+
+func (*deferringEngine) IsInteractiveEngine() bool {
+	return false
+}
+
+func (e *deferringEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	if common.IsStringPresent(e.ids, prob.ID) {
+		err := prob.SetAnswer(qatypes.DeferAnswer)
+		return prob, err
+	}
+	return NewDefaultEngine().FetchAnswer(prob)
+}
+
+func TestDeferredAnswers(t *testing.T) {
+	registryKey := common.BaseKey + common.Delim + "registry"
+	namespaceKey := common.BaseKey + common.Delim + "namespace"
+	exposeKey := common.BaseKey + common.Delim + "expose"
+	outputPath := t.TempDir()
+	deferredPath := filepath.Join(outputPath, common.QADeferredFile)
+	reset := func() {
+		engines = []Engine{}
+		writeStores = []qatypes.Store{}
+		deferredStore = nil
+		reusePreviousAnswers = false
+	}
+	defer reset()
+
+	reset()
+	AddEngine(&deferringEngine{ids: []string{registryKey, exposeKey}})
+	if err := SetupDeferredFile(outputPath, false); err != nil {
+		t.Fatalf("Failed to set up the deferred answers. Error: %q", err)
+	}
+	SetupConfigFile(outputPath, nil, nil, nil)
+	SetupCacheFile(outputPath, nil)
+	if answer := FetchStringAnswer(registryKey, "Enter the registry : ", nil, "quay.io"); answer != qatypes.GetDeferredPlaceholder(registryKey) {
+		t.Fatalf("Expected the placeholder of the deferred answer. Actual: %s", answer)
+	}
+	if answer := FetchBoolAnswer(exposeKey, "Expose the service? ", nil, true); !answer {
+		t.Fatalf("Expected the default of the deferred confirm question. Actual: %v", answer)
+	}
+	if answer := FetchStringAnswer(namespaceKey, "Enter the namespace : ", nil, "apps"); answer != "apps" {
+		t.Fatalf("Failed to answer the question which was not deferred. Actual: %s", answer)
+	}
+	if deferred := GetDeferredProblems(); len(deferred) != 2 || deferred[0].ID != registryKey || deferred[0].Answer != nil {
+		t.Fatalf("Failed to record the deferred problems. Actual: %+v", deferred)
+	}
+
+	// The user fills in the answer of one of the deferred problems
+	followUp := qatypes.NewCache(deferredPath)
+	if err := followUp.Load(); err != nil {
+		t.Fatalf("Failed to load the follow-up answers file. Error: %q", err)
+	}
+	followUp.Spec.Problems[0].Answer = "us.icr.io"
+	if err := followUp.Write(); err != nil {
+		t.Fatalf("Failed to write the follow-up answers file. Error: %q", err)
+	}
+
+	reset()
+	AddEngine(&deferringEngine{ids: []string{exposeKey}})
+	if err := SetupDeferredFile(outputPath, true); err != nil {
+		t.Fatalf("Failed to set up the deferred answers of the previous run. Error: %q", err)
+	}
+	SetupConfigFile(outputPath, nil, nil, nil)
+	SetupCacheFile(outputPath, nil)
+	if answer := FetchStringAnswer(registryKey, "Enter the registry : ", nil, "quay.io"); answer != "us.icr.io" {
+		t.Fatalf("Failed to use the answer filled in the follow-up answers file. Actual: %s", answer)
+	}
+	if answer := FetchStringAnswer(namespaceKey, "Enter the namespace : ", nil, "default"); answer != "apps" {
+		t.Fatalf("Failed to reuse the answer of the previous run. Actual: %s", answer)
+	}
+	FetchBoolAnswer(exposeKey, "Expose the service? ", nil, true)
+	if deferred := GetDeferredProblems(); len(deferred) != 1 || deferred[0].ID != exposeKey {
+		t.Fatalf("Expected only the problem deferred again. Actual: %+v", deferred)
+	}
+
+	reset()
+	if err := SetupDeferredFile(t.TempDir(), true); err == nil {
+		t.Fatalf("Expected an error since there are no deferred answers of a previous run")
+	}
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package qaengine
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
 	qatypes "github.com/konveyor/move2kube/types/qaengine"
@@ -38,10 +41,12 @@ var (
 )
 
 // StartEngine starts the QA Engines
-func StartEngine(qaskip bool, qaport int, qadisablecli bool) {
+func StartEngine(qaskip bool, qaport int, qadisablecli bool, qafileiodir string) {
 	var e Engine
 	if qaskip {
 		e = NewDefaultEngine()
+	} else if qafileiodir != "" {
+		e = NewFileIOEngine(qafileiodir)
 	} else if !qadisablecli {
 		e = NewCliEngine()
 	} else {
@@ -62,7 +67,7 @@ func AddEngine(e Engine) {
 // AddEngineHighestPriority adds an engine to the list and sets it at highest priority
 func AddEngineHighestPriority(e Engine) error {
 	if err := e.StartEngine(); err != nil {
-		return fmt.Errorf("failed to start the engine: %T\n%v\nError: %s", e, e, err)
+		return fmt.Errorf("failed to start the engine: %T\n%v\nError: %w", e, e, err)
 	}
 	engines = append([]Engine{e}, engines...)
 	return nil
@@ -85,13 +90,51 @@ func AddCaches(cacheFiles []string) {
 // SetupCacheFile adds cache responders
 func SetupCacheFile(outputPath string, cacheFiles []string) {
 	writeCachePath := filepath.Join(outputPath, common.QACacheFile)
-	cache := qatypes.NewCache(writeCachePath)
+	if qatypes.IsFileStorage(storage) {
+		cache := qatypes.NewCache(writeCachePath)
+		if reusePreviousAnswers {
+			if err := cache.Load(); err != nil {
+				log.Warnf("Failed to load the cache of the previous run. Error: %q", err)
+			}
+		}
+		cache.Write()
+		writeStores = append(writeStores, cache)
+		cacheFiles = append(cacheFiles, writeCachePath)
+		AddCaches(cacheFiles)
+		return
+	}
+	// The answers already in the storage are reused, since the engine might not keep its filesystem between runs
+	cache := qatypes.NewCacheInStorage(writeCachePath, storage)
+	if err := cache.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warnf("Failed to load the cache from the storage. Error: %q", err)
+	}
 	cache.Write()
 	writeStores = append(writeStores, cache)
-	cacheFiles = append(cacheFiles, writeCachePath)
+	e := &StoreEngine{store: qatypes.NewCacheInStorage(writeCachePath, storage)}
+	if err := AddEngineHighestPriority(e); err != nil {
+		log.Errorf("Ignoring engine %T due to error : %s", e, err)
+	}
 	AddCaches(cacheFiles)
 }
 
+// SetupSuppressions disables the questions of the categories, which are answered using the defaults manifest instead.
+// It should be called before adding the config and cache responders, so that they still take precedence.
+func SetupSuppressions(categories []string, defaultsFile string) {
+	if len(categories) == 0 {
+		return
+	}
+	if defaultsFile == "" {
+		log.Fatalf("The disabled QA categories %s require a defaults manifest.", strings.Join(categories, ", "))
+	}
+	e := NewSuppressEngine(categories, defaultsFile)
+	if err := AddEngineHighestPriority(e); err != nil {
+		if codedErr := common.AsError(err); codedErr != nil {
+			log.Fatal(codedErr.Details())
+		}
+		log.Fatalf("Failed to disable the QA categories %s . Error: %q", strings.Join(categories, ", "), err)
+	}
+}
+
 // SetupConfigFile adds config responders - should be called only once
 func SetupConfigFile(outputPath string, configStrings, configFiles, presets []string) {
 	presetPaths := []string{}
@@ -100,7 +143,7 @@ func SetupConfigFile(outputPath string, configStrings, configFiles, presets []st
 		presetPaths = append(presetPaths, presetPath)
 	}
 	configFiles = append(presetPaths, configFiles...)
-	writeConfig := qatypes.NewConfig(filepath.Join(outputPath, common.ConfigFile), configStrings, configFiles)
+	writeConfig := qatypes.NewConfigInStorage(filepath.Join(outputPath, common.ConfigFile), configStrings, configFiles, storage)
 	writeStores = append(writeStores, writeConfig)
 	e := &StoreEngine{store: writeConfig}
 	if err := AddEngineHighestPriority(e); err != nil {
@@ -119,6 +162,9 @@ func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 	for _, e := range engines {
 		prob, err = e.FetchAnswer(prob)
 		if err != nil {
+			if _, ok := err.(*SuppressedProblemError); ok {
+				return prob, err
+			}
 			log.Debugf("Error while fetching answer using engine %T Error: %q", e, err)
 			continue
 		}
@@ -147,6 +193,9 @@ func FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
 			}
 		}
 	}
+	if prob.IsDeferred() {
+		return deferProblem(prob), nil
+	}
 	for _, writeStore := range writeStores {
 		writeStore.AddSolution(prob)
 	}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	fileIOQuestionSuffix = ".question.json"
+	fileIOAnswerSuffix   = ".answer.json"
+	fileIORejectedSuffix = ".rejected.json"
+	fileIOPollInterval   = 500 * time.Millisecond
+)
+
+// FileIOEngine handles qa using files, so that UIs and scripts can answer the questions without an HTTP server.
+// Each question is written to the directory as NNNN.question.json and the engine waits for NNNN.answer.json ,
+// which contains the problem or just {"answer": ...}. Invalid answers are renamed to NNNN.rejected.json .
+// The answer files should be written to a temporary file and renamed, so that the engine never reads a partial file.
+type FileIOEngine struct {
+	dir          string
+	pollInterval time.Duration
+	count        int
+}
+
+// NewFileIOEngine creates a new instance of the file io engine
+func NewFileIOEngine(dir string) *FileIOEngine {
+	return &FileIOEngine{dir: dir, pollInterval: fileIOPollInterval}
+}
+
+// StartEngine creates the directory where the questions and answers are exchanged
+func (f *FileIOEngine) StartEngine() error {
+	if err := os.MkdirAll(f.dir, common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("unable to create the QA directory %s : %s", f.dir, err)
+	}
+	// The questions are numbered after those of the earlier runs, so that their answers are not reused
+	questionPaths, err := filepath.Glob(filepath.Join(f.dir, "*"+fileIOQuestionSuffix))
+	if err != nil {
+		return err
+	}
+	f.count = len(questionPaths)
+	log.Infof("Started QA engine on the directory %s", f.dir)
+	return nil
+}
+
+// IsInteractiveEngine returns true if the engine interacts with the user
+func (*FileIOEngine) IsInteractiveEngine() bool {
+	return true
+}
+
+// FetchAnswer writes the problem to a question file and waits for the answer file
+func (f *FileIOEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	if err := ValidateProblem(prob); err != nil {
+		log.Errorf("the QA problem object is invalid. Error: %q", err)
+		return prob, err
+	}
+	if prob.Answer != nil {
+		return prob, nil
+	}
+	f.count++
+	prefix := filepath.Join(f.dir, fmt.Sprintf("%04d", f.count))
+	if err := writeJSONAtomically(prefix+fileIOQuestionSuffix, prob); err != nil {
+		return prob, err
+	}
+	log.Infof("Waiting for the answer to the question %s in the file %s", prob.ID, prefix+fileIOAnswerSuffix)
+	for {
+		answeredProb, err := f.readAnswer(prefix+fileIOAnswerSuffix, prob)
+		if err == nil {
+			return answeredProb, nil
+		}
+		if !os.IsNotExist(err) {
+			log.Errorf("Rejecting the answer to the question %s . Error: %q", prob.ID, err)
+			if err := os.Rename(prefix+fileIOAnswerSuffix, prefix+fileIORejectedSuffix); err != nil {
+				return prob, err
+			}
+		}
+		time.Sleep(f.pollInterval)
+	}
+}
+
+// readAnswer reads the answer file and sets the answer of the problem
+func (f *FileIOEngine) readAnswer(answerPath string, prob qatypes.Problem) (qatypes.Problem, error) {
+	data, err := ioutil.ReadFile(answerPath)
+	if err != nil {
+		return prob, err
+	}
+	answer := qatypes.Problem{}
+	if err := json.Unmarshal(data, &answer); err != nil {
+		return prob, fmt.Errorf("the answer is not valid json : %s", err)
+	}
+	if answer.ID != "" && answer.ID != prob.ID {
+		return prob, fmt.Errorf("the answer's problem ID doesn't match the question. Expected: %s Actual %s", prob.ID, answer.ID)
+	}
+	if err := prob.SetAnswer(answer.Answer); err != nil {
+		return prob, err
+	}
+	return prob, nil
+}
+
+// writeJSONAtomically writes the data as json to a temporary file and renames it, so that readers never see a partial file
+func writeJSONAtomically(path string, data interface{}) error {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempPath := path + ".tmp"
+	if err := ioutil.WriteFile(tempPath, jsonBytes, common.DefaultFilePermission); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
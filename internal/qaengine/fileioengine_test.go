@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestFileIOEngine(t *testing.T) {
+	dir := t.TempDir()
+	e := NewFileIOEngine(dir)
+	e.pollInterval = 10 * time.Millisecond
+	if err := e.StartEngine(); err != nil {
+		t.Fatalf("Failed to start the engine. Error: %q", err)
+	}
+	key := common.BaseKey + common.Delim + "select"
+	problem, err := qatypes.NewSelectProblem(key, "Which option?", nil, "a", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Failed to create the problem. Error: %q", err)
+	}
+
+	go func() {
+		questionPath := filepath.Join(dir, "0001"+fileIOQuestionSuffix)
+		for {
+			if _, err := os.Stat(questionPath); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		// An invalid answer is rejected and the engine keeps waiting
+		_ = ioutil.WriteFile(filepath.Join(dir, "0001"+fileIOAnswerSuffix), []byte(`{"answer": "c"}`), 0644)
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "0001"+fileIORejectedSuffix)); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		answer, _ := json.Marshal(map[string]interface{}{"id": key, "answer": "b"})
+		_ = writeJSONAtomically(filepath.Join(dir, "0001"+fileIOAnswerSuffix), json.RawMessage(answer))
+	}()
+
+	answered, err := e.FetchAnswer(problem)
+	if err != nil {
+		t.Fatalf("Failed to fetch the answer. Error: %q", err)
+	}
+	if answered.Answer != "b" {
+		t.Fatalf("Expected the answer from the answer file. Actual: %v", answered.Answer)
+	}
+	question := qatypes.Problem{}
+	questionData, err := ioutil.ReadFile(filepath.Join(dir, "0001"+fileIOQuestionSuffix))
+	if err != nil || json.Unmarshal(questionData, &question) != nil || question.ID != key {
+		t.Fatalf("Expected the question to be written as json. Actual: %s Error: %v", questionData, err)
+	}
+
+	restarted := NewFileIOEngine(dir)
+	if err := restarted.StartEngine(); err != nil || restarted.count != 1 {
+		t.Fatalf("Expected the questions to be numbered after those of the earlier runs. Actual count: %d Error: %v", restarted.count, err)
+	}
+}
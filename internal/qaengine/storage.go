@@ -0,0 +1,227 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	cgclientcmd "k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	configMapStorageScheme = "configmap"
+	secretStorageScheme    = "secret"
+)
+
+// storage keeps the config and the cache written by the engine
+var storage qatypes.Storage = qatypes.FileStorage{}
+
+// SetupStorage selects where the config and the cache written by the engine are kept. It should be called before setting up the config and cache files.
+// The location is file:// to keep them in the output directory, configmap://<namespace>/<name> or secret://<namespace>/<name>
+// to keep them in a config map or a secret of the cluster, or an http:// or https:// URL under which they are read with GET and written with PUT.
+func SetupStorage(location string) error {
+	if location == "" {
+		storage = qatypes.FileStorage{}
+		return nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return fmt.Errorf("failed to parse the QA storage location %s . Error: %q", location, err)
+	}
+	switch u.Scheme {
+	case "", "file":
+		storage = qatypes.FileStorage{}
+	case configMapStorageScheme, secretStorageScheme:
+		namespace, name := u.Host, strings.Trim(u.Path, "/")
+		if namespace == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("the QA storage location %s is not of the form %s://<namespace>/<name>", location, u.Scheme)
+		}
+		kubeStorage, err := newKubeStorage(namespace, name, u.Scheme == secretStorageScheme)
+		if err != nil {
+			return err
+		}
+		storage = kubeStorage
+	case "http", "https":
+		storage = newHTTPStorage(location)
+	default:
+		return fmt.Errorf("the QA storage location %s has the unsupported scheme %s . Supported schemes are file, %s, %s, http and https", location, u.Scheme, configMapStorageScheme, secretStorageScheme)
+	}
+	log.Debugf("Using the QA storage %T at %s", storage, location)
+	return nil
+}
+
+// kubeStorage keeps the files as the keys of a config map or a secret, named after the base names of the files
+type kubeStorage struct {
+	clientset kubernetes.Interface
+	namespace string
+	name      string
+	isSecret  bool
+}
+
+func newKubeStorage(namespace, name string, isSecret bool) (*kubeStorage, error) {
+	// The in-cluster config is used when there is no kubeconfig, so that the engine can run in a pod
+	rules := cgclientcmd.NewDefaultClientConfigLoadingRules()
+	cfg, err := cgclientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &cgclientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the config for the cluster API client. Error: %q", err)
+	}
+	if common.CommandTimeout > 0 {
+		cfg.Timeout = common.CommandTimeout
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cluster API client. Error: %q", err)
+	}
+	return &kubeStorage{clientset: clientset, namespace: namespace, name: name, isSecret: isSecret}, nil
+}
+
+// Read reads the key of the file from the config map or the secret
+func (s *kubeStorage) Read(path string) ([]byte, error) {
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
+	key := filepath.Base(path)
+	if s.isSecret {
+		secret, err := s.clientset.CoreV1().Secrets(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, s.toNotExist(err)
+		}
+		if data, ok := secret.Data[key]; ok {
+			return data, nil
+		}
+	} else {
+		configMap, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, s.toNotExist(err)
+		}
+		if data, ok := configMap.Data[key]; ok {
+			return []byte(data), nil
+		}
+	}
+	return nil, fmt.Errorf("the key %s is missing in %s/%s : %w", key, s.namespace, s.name, os.ErrNotExist)
+}
+
+// Write writes the file to its key in the config map or the secret, creating it if it does not exist
+func (s *kubeStorage) Write(path string, data []byte) error {
+	ctx, cancel := common.GetCommandContext()
+	defer cancel()
+	key := filepath.Base(path)
+	if s.isSecret {
+		secrets := s.clientset.CoreV1().Secrets(s.namespace)
+		secret, err := secrets.Get(ctx, s.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: s.name, Namespace: s.namespace}, Data: map[string][]byte{key: data}}
+			_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[key] = data
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	}
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: s.name, Namespace: s.namespace}, Data: map[string]string{key: string(data)}}
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = string(data)
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *kubeStorage) toNotExist(err error) error {
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%s/%s does not exist : %w", s.namespace, s.name, os.ErrNotExist)
+	}
+	return err
+}
+
+// httpStorage keeps the files under a URL, named after the base names of the files
+type httpStorage struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newHTTPStorage(baseURL string) *httpStorage {
+	client := &http.Client{}
+	if common.CommandTimeout > 0 {
+		client.Timeout = common.CommandTimeout
+	}
+	return &httpStorage{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+// Read gets the file from the URL
+func (s *httpStorage) Read(path string) ([]byte, error) {
+	fileURL := s.baseURL + "/" + url.PathEscape(filepath.Base(path))
+	resp, err := s.client.Get(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s was not found : %w", fileURL, os.ErrNotExist)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("the request to get %s failed with status code %d", fileURL, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Write puts the file to the URL
+func (s *httpStorage) Write(path string, data []byte) error {
+	fileURL := s.baseURL + "/" + url.PathEscape(filepath.Base(path))
+	req, err := http.NewRequest(http.MethodPut, fileURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the request to put %s failed with status code %d", fileURL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestHTTPStorage(t *testing.T) {
+	cacheData, err := ioutil.ReadFile("testdata/qaenginetest.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read the test cache. Error: %q", err)
+	}
+	mutex := sync.Mutex{}
+	files := map[string]string{common.QACacheFile: string(cacheData)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		name := strings.TrimPrefix(r.URL.Path, "/m2k/")
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := files[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(data))
+		case http.MethodPut:
+			data, _ := ioutil.ReadAll(r.Body)
+			files[name] = string(data)
+		}
+	}))
+	defer server.Close()
+	if err := SetupStorage(server.URL + "/m2k"); err != nil {
+		t.Fatalf("Failed to set up the HTTP storage. Error: %q", err)
+	}
+	defer SetupStorage("")
+
+	engines = []Engine{}
+	writeStores = []qatypes.Store{}
+	AddEngine(NewDefaultEngine())
+	outputPath := t.TempDir()
+	SetupConfigFile(outputPath, nil, nil, nil)
+	SetupCacheFile(outputPath, nil)
+
+	if answer := FetchStringAnswer(common.BaseKey+common.Delim+"input", "Enter the container registry username : ", nil, ""); answer != "testuser" {
+		t.Fatalf("Failed to reuse the answer in the storage. Actual: %s", answer)
+	}
+	if answer := FetchStringAnswer(common.BaseKey+common.Delim+"namespace", "Enter the namespace : ", nil, "apps"); answer != "apps" {
+		t.Fatalf("Failed to get the default answer. Actual: %s", answer)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !strings.Contains(files[common.QACacheFile], "testuser") || !strings.Contains(files[common.QACacheFile], "apps") {
+		t.Fatalf("Failed to write the answers to the cache in the storage. Actual:\n%s", files[common.QACacheFile])
+	}
+	if !strings.Contains(files[common.ConfigFile], "namespace: apps") {
+		t.Fatalf("Failed to write the answers to the config in the storage. Actual:\n%s", files[common.ConfigFile])
+	}
+	for _, name := range []string{common.QACacheFile, common.ConfigFile} {
+		if _, err := os.Stat(filepath.Join(outputPath, name)); err == nil {
+			t.Fatalf("Expected the file %s to be kept only in the storage", name)
+		}
+	}
+}
+
+func TestSetupStorage(t *testing.T) {
+	defer SetupStorage("")
+	for _, location := range []string{"configmap://m2k", "secret:///m2k-answers", "configmap://apps/m2k/answers", "s3://bucket/m2k"} {
+		if err := SetupStorage(location); err == nil {
+			t.Fatalf("Expected an error for the QA storage location %s", location)
+		}
+	}
+	if err := SetupStorage("file://"); err != nil || !qatypes.IsFileStorage(storage) {
+		t.Fatalf("Failed to set up the file storage. Error: %q", err)
+	}
+}
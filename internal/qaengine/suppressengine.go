@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+// SuppressEngine answers the questions of the disabled categories using a defaults manifest, so that headless runs
+// never wait for an answer. A category is a key prefix like storages or target.imageregistry .
+type SuppressEngine struct {
+	categories []string
+	defaults   *qatypes.Config
+}
+
+// SuppressedProblemError is returned for a question of a disabled category which has no default in the manifest
+type SuppressedProblemError struct {
+	Problem  qatypes.Problem
+	Category string
+}
+
+func (e *SuppressedProblemError) Error() string {
+	return fmt.Sprintf("the question %s [%s] of the disabled QA category %s has no answer in the defaults manifest. Add the key %s to the manifest.", e.Problem.ID, e.Problem.Desc, e.Category, e.Problem.ID)
+}
+
+// NewSuppressEngine creates a new instance of the suppress engine
+func NewSuppressEngine(categories []string, defaultsFile string) *SuppressEngine {
+	normalizedCategories := []string{}
+	for _, category := range categories {
+		category = strings.TrimPrefix(strings.TrimSpace(category), common.BaseKey+common.Delim)
+		if category != "" {
+			normalizedCategories = append(normalizedCategories, category)
+		}
+	}
+	return &SuppressEngine{
+		categories: normalizedCategories,
+		defaults:   qatypes.NewConfig("", nil, []string{defaultsFile}),
+	}
+}
+
+// StartEngine loads the defaults manifest and checks that it covers all the disabled categories
+func (se *SuppressEngine) StartEngine() error {
+	if err := se.defaults.Load(); err != nil {
+		return err
+	}
+	uncovered := []string{}
+	for _, category := range se.categories {
+		if _, ok := se.defaults.Get(common.BaseKey + common.Delim + category); !ok {
+			uncovered = append(uncovered, category)
+		}
+	}
+	if len(uncovered) > 0 {
+		return common.NewError(common.QADefaultsMissingErrorCode, nil, "The defaults manifest has no answers for the disabled QA categories %s", strings.Join(uncovered, ", "))
+	}
+	return nil
+}
+
+// IsInteractiveEngine returns true if the engine interacts with the user
+func (*SuppressEngine) IsInteractiveEngine() bool {
+	return false
+}
+
+// FetchAnswer fetches the answers of the questions of the disabled categories from the defaults manifest
+func (se *SuppressEngine) FetchAnswer(prob qatypes.Problem) (qatypes.Problem, error) {
+	category, ok := se.getCategory(prob.ID)
+	if !ok {
+		return prob, fmt.Errorf("the question %s is not in a disabled QA category", prob.ID)
+	}
+	answeredProb, err := se.defaults.GetSolution(prob)
+	if err != nil || answeredProb.Answer == nil {
+		return prob, &SuppressedProblemError{Problem: prob, Category: category}
+	}
+	return answeredProb, nil
+}
+
+// getCategory returns the disabled category the question belongs to
+func (se *SuppressEngine) getCategory(id string) (string, bool) {
+	for _, category := range se.categories {
+		key := common.BaseKey + common.Delim + category
+		if id == key || strings.HasPrefix(id, key+common.Delim) {
+			return category, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	qatypes "github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestSuppressEngine(t *testing.T) {
+	defaultsPath := "testdata/qadefaults.yaml"
+
+	t.Run("defaults manifest not covering a disabled category", func(t *testing.T) {
+		e := NewSuppressEngine([]string{"storages", "target.imageregistry"}, defaultsPath)
+		if err := e.StartEngine(); err == nil {
+			t.Fatalf("Expected an error since the manifest has no answers for target.imageregistry")
+		}
+	})
+
+	t.Run("question of a disabled category answered by the manifest", func(t *testing.T) {
+		engines = []Engine{}
+		AddEngine(NewSuppressEngine([]string{common.BaseKey + common.Delim + "storages"}, defaultsPath))
+		AddEngine(NewDefaultEngine())
+		key := common.ConfigStoragesKey + common.Delim + `"data"` + common.Delim + "storageclass"
+		problem, err := qatypes.NewSelectProblem(key, "Which storage class?", nil, "default", []string{"default", "gp2"})
+		if err != nil {
+			t.Fatalf("Failed to create the problem. Error: %q", err)
+		}
+		problem, err = FetchAnswer(problem)
+		if err != nil {
+			t.Fatalf("Failed to fetch the answer. Error: %q", err)
+		}
+		if problem.Answer != "gp2" {
+			t.Fatalf("Expected the answer from the defaults manifest. Actual: %v", problem.Answer)
+		}
+	})
+
+	t.Run("question of a disabled category without a default", func(t *testing.T) {
+		engines = []Engine{}
+		AddEngine(NewSuppressEngine([]string{"storages"}, defaultsPath))
+		AddEngine(NewDefaultEngine())
+		key := common.ConfigStoragesKey + common.Delim + `"logs"` + common.Delim + "storageclass"
+		problem, err := qatypes.NewSelectProblem(key, "Which storage class?", nil, "default", []string{"default", "gp2"})
+		if err != nil {
+			t.Fatalf("Failed to create the problem. Error: %q", err)
+		}
+		if _, err := FetchAnswer(problem); err == nil {
+			t.Fatalf("Expected an error instead of the default answer")
+		} else if _, ok := err.(*SuppressedProblemError); !ok {
+			t.Fatalf("Expected a suppressed problem error. Actual: %T", err)
+		}
+	})
+
+	t.Run("question of an enabled category", func(t *testing.T) {
+		engines = []Engine{}
+		AddEngine(NewSuppressEngine([]string{"storages"}, defaultsPath))
+		AddEngine(NewDefaultEngine())
+		problem, err := qatypes.NewInputProblem(common.ConfigImageRegistryURLKey, "Registry?", nil, "quay.io")
+		if err != nil {
+			t.Fatalf("Failed to create the problem. Error: %q", err)
+		}
+		if problem, err = FetchAnswer(problem); err != nil || problem.Answer != "quay.io" {
+			t.Fatalf("Expected the default answer. Actual: %v Error: %v", problem.Answer, err)
+		}
+	})
+}
@@ -18,8 +18,10 @@ package source
 
 import (
 	"bufio"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
@@ -52,37 +54,22 @@ func (any2KubeTranslator *Any2KubeTranslator) GetServiceOptions(inputPath string
 	}
 
 	ignoreDirectories, ignoreContents := any2KubeTranslator.getIgnorePaths(inputPath)
-
-	err := filepath.Walk(inputPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			log.Warnf("Skipping path %q due to error. Error: %q", path, err)
-			return nil
-		}
-		if !info.IsDir() {
-			return nil
-		}
-		if common.IsStringPresent(preContainerizedSourcePaths, path) {
-			return filepath.SkipDir //TODO: Should we go inside the directory in this case?
-		}
-		if common.IsStringPresent(ignoreDirectories, path) {
-			if common.IsStringPresent(ignoreContents, path) {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		containerizationOptions := containerizer.GetContainerizationOptions(plan, path)
-		if len(containerizationOptions) == 0 {
-			log.Debugf("No known containerization approach is supported for directory %q", path)
-			if common.IsStringPresent(ignoreContents, path) {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		for _, containerizationOption := range containerizationOptions {
+	dirsOptions := getContainerizationOptionsOfDirs(inputPath, plan, preContainerizedSourcePaths, ignoreDirectories, ignoreContents)
+	paths := []string{}
+	for path := range dirsOptions {
+		paths = append(paths, path)
+	}
+	sortInWalkOrder(paths)
+	for _, path := range paths {
+		for _, containerizationOption := range dirsOptions[path] {
 			serviceName := filepath.Base(path)
 			service := any2KubeTranslator.newService(serviceName)
 			service.ContainerBuildType = containerizationOption.ContainerizationType
 			service.ContainerizationTargetOptions = containerizationOption.TargetOptions
+			service.Detection = containerizationOption.Detection
+			if staticSite, ok := getStaticSite(path); ok {
+				service.StaticSite = staticSite
+			}
 			if !common.IsStringPresent(service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType], path) {
 				service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] = append(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType], path)
 				service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType] = append(service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType], path)
@@ -92,14 +79,83 @@ func (any2KubeTranslator *Any2KubeTranslator) GetServiceOptions(inputPath string
 			}
 			services = append(services, service)
 		}
-		return filepath.SkipDir // Skip all subdirectories when base directory is a valid package
-	})
+	}
+	return services, nil
+}
+
+// getContainerizationOptionsOfDirs visits the directories level by level and detects the containerization options of the
+// directories of each level concurrently. The subdirectories of a directory which can be containerized are not visited.
+func getContainerizationOptionsOfDirs(inputPath string, plan plantypes.Plan, skipDirectories, ignoreDirectories, ignoreContents []string) map[string][]containerizer.ContainerizationOption {
+	dirsOptions := map[string][]containerizer.ContainerizationOption{}
+	info, err := os.Lstat(inputPath)
+	if err != nil {
+		log.Warnf("Skipping path %q due to error. Error: %q", inputPath, err)
+		return dirsOptions
+	}
+	if !info.IsDir() {
+		return dirsOptions
+	}
+	level := []string{inputPath}
+	for len(level) > 0 {
+		nextLevel := []string{}
+		detectDirs := []string{}
+		for _, path := range level {
+			if common.IsStringPresent(skipDirectories, path) {
+				continue //TODO: Should we go inside the directory in this case?
+			}
+			if common.IsStringPresent(ignoreDirectories, path) {
+				if !common.IsStringPresent(ignoreContents, path) {
+					nextLevel = append(nextLevel, getSubDirectories(path)...)
+				}
+				continue
+			}
+			detectDirs = append(detectDirs, path)
+		}
+		detectedOptions := make([][]containerizer.ContainerizationOption, len(detectDirs))
+		common.RunConcurrently(len(detectDirs), common.PlanWorkers, func(i int) {
+			detectedOptions[i] = containerizer.GetContainerizationOptions(plan, detectDirs[i])
+		})
+		for i, path := range detectDirs {
+			if len(detectedOptions[i]) > 0 {
+				// Skip all subdirectories when base directory is a valid package
+				dirsOptions[path] = detectedOptions[i]
+				continue
+			}
+			log.Debugf("No known containerization approach is supported for directory %q", path)
+			if !common.IsStringPresent(ignoreContents, path) {
+				nextLevel = append(nextLevel, getSubDirectories(path)...)
+			}
+		}
+		level = nextLevel
+	}
+	return dirsOptions
+}
 
+// getSubDirectories returns the subdirectories of the directory, without following the symbolic links
+func getSubDirectories(dir string) []string {
+	subDirs := []string{}
+	files, err := ioutil.ReadDir(dir)
 	if err != nil {
-		log.Errorf("Error occurred while walking through the directory at path %q Error: %q", inputPath, err)
+		log.Warnf("Skipping path %q due to error. Error: %q", dir, err)
+		return subDirs
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			subDirs = append(subDirs, filepath.Join(dir, file.Name()))
+		}
 	}
+	return subDirs
+}
 
-	return services, err
+// sortInWalkOrder sorts the paths in the order in which filepath.Walk visits them. Walk compares the paths element by
+// element, which is the same as comparing the paths with the separators replaced by a character sorting before all others.
+func sortInWalkOrder(paths []string) {
+	walkOrderKey := func(path string) string {
+		return strings.Replace(path, string(os.PathSeparator), "\x00", -1)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return walkOrderKey(paths[i]) < walkOrderKey(paths[j])
+	})
 }
 
 // Translate translates artifacts to IR
@@ -110,27 +166,35 @@ func (any2KubeTranslator *Any2KubeTranslator) Translate(services []plantypes.Ser
 			continue
 		}
 		log.Debugf("Translating %s", service.ServiceName)
-		container, err := containerizer.GetContainer(plan, service)
+		if service.StaticSite.Serving == plantypes.ObjectStorageStaticSiteServing {
+			if err := addStaticSiteSync(&ir, plan, service); err != nil {
+				log.Errorf("Unable to translate the static site %s Error: %q", service.ServiceName, err)
+			}
+			continue
+		}
+		var container irtypes.Container
+		err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			if service.StaticSite.Serving == plantypes.NginxStaticSiteServing || service.StaticSite.Serving == plantypes.BackendStaticSiteServing {
+				container, err = getStaticSiteContainer(plan, service)
+			} else {
+				container, err = containerizer.GetContainer(plan, service)
+			}
+			return err
+		})
 		if err != nil {
-			log.Errorf("Unable to translate service %s Error: %q", service.ServiceName, err)
 			continue
 		}
+		if err := containerizer.CommitServiceArtifacts(service.ServiceName, container); err != nil {
+			log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+		}
 		ir.AddContainer(container)
 		serviceContainer := core.Container{Name: service.ServiceName}
 		serviceContainer.Image = service.Image
 		irService := irtypes.NewServiceFromPlanService(service)
-		serviceContainerPorts := []core.ContainerPort{}
+		irService.Containers = []core.Container{serviceContainer}
 		for _, port := range container.ExposedPorts {
-			// Add the port to the k8s pod.
-			serviceContainerPort := core.ContainerPort{ContainerPort: int32(port)}
-			serviceContainerPorts = append(serviceContainerPorts, serviceContainerPort)
-			// Forward the port on the k8s service to the k8s pod.
-			podPort := irtypes.Port{Number: int32(port)}
-			servicePort := podPort
-			irService.AddPortForwarding(servicePort, podPort)
+			irService.AddContainerPort(0, core.ContainerPort{ContainerPort: int32(port)})
 		}
-		serviceContainer.Ports = serviceContainerPorts
-		irService.Containers = []core.Container{serviceContainer}
 		ir.Services[service.ServiceName] = irService
 	}
 	return ir, nil
@@ -145,6 +209,23 @@ func (any2KubeTranslator *Any2KubeTranslator) newService(serviceName string) pla
 }
 
 func (*Any2KubeTranslator) getIgnorePaths(inputPath string) (ignoreDirectories []string, ignoreContents []string) {
+	if len(common.IgnoreDirectories) > 0 {
+		err := filepath.Walk(inputPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() && common.IsStringPresent(common.IgnoreDirectories, info.Name()) {
+				// Ignore both the directory and its contents
+				ignoreDirectories = append(ignoreDirectories, path)
+				ignoreContents = append(ignoreContents, path)
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			log.Warnf("Unable to look for the directories to ignore at path %q Error: %q", inputPath, err)
+		}
+	}
 	filePaths, err := common.GetFilesByName(inputPath, []string{common.IgnoreFilename})
 	if err != nil {
 		log.Warnf("Unable to fetch .m2kignore files at path %q Error: %q", inputPath, err)
@@ -0,0 +1,447 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	appEngineAppYamlName = "app.yaml"
+	// appEngineDefaultService is the name of the service of the apps which don't specify one
+	appEngineDefaultService = "default"
+	// appEngineFlexibleEnv is the env of the apps running on the flexible environment
+	appEngineFlexibleEnv = "flex"
+	// appEngineCustomRuntime is the runtime of the apps of the flexible environment built from their Dockerfile
+	appEngineCustomRuntime = "custom"
+	// appEngineCloudSQLSetting is the beta setting listing the Cloud SQL instances of an app of the flexible environment
+	appEngineCloudSQLSetting = "cloud_sql_instances"
+
+	// appEngineDefaultMaxInstances is the maximum number of instances when max_instances is not set. The flexible environment
+	// defaults to 20, and the standard environment has no maximum.
+	appEngineDefaultMaxInstances = 20
+	// appEngineDefaultFlexibleMinInstances is the minimum number of instances of the flexible environment when min_num_instances is not set
+	appEngineDefaultFlexibleMinInstances = 2
+	// appEngineDefaultCPUTarget is the cpu utilization above which App Engine adds instances when no target is set
+	appEngineDefaultCPUTarget = 0.6
+)
+
+var (
+	// appEngineInstanceClassMemory is the memory of each instance class of the standard environment
+	appEngineInstanceClassMemory = map[string]string{
+		"F1":    "384M",
+		"F2":    "768M",
+		"F4":    "1536M",
+		"F4_1G": "3072M",
+		"B1":    "384M",
+		"B2":    "768M",
+		"B4":    "1536M",
+		"B4_1G": "3072M",
+		"B8":    "3072M",
+	}
+)
+
+// AppEngineTranslator implements Translator interface for Google App Engine apps, described by an app.yaml
+type AppEngineTranslator struct {
+}
+
+// appEngineAppYaml contains the fields of the app.yaml of an App Engine app which are used in the translation
+type appEngineAppYaml struct {
+	Runtime       string            `yaml:"runtime"`
+	Env           string            `yaml:"env"`
+	Service       string            `yaml:"service"`
+	Entrypoint    string            `yaml:"entrypoint"`
+	InstanceClass string            `yaml:"instance_class"`
+	EnvVariables  map[string]string `yaml:"env_variables"`
+	Resources     struct {
+		CPU      float64 `yaml:"cpu"`
+		MemoryGB float64 `yaml:"memory_gb"`
+	} `yaml:"resources"`
+	AutomaticScaling *appEngineAutomaticScaling `yaml:"automatic_scaling"`
+	BasicScaling     *struct {
+		MaxInstances int    `yaml:"max_instances"`
+		IdleTimeout  string `yaml:"idle_timeout"`
+	} `yaml:"basic_scaling"`
+	ManualScaling *struct {
+		Instances int `yaml:"instances"`
+	} `yaml:"manual_scaling"`
+	VPCAccessConnector struct {
+		Name          string `yaml:"name"`
+		EgressSetting string `yaml:"egress_setting"`
+	} `yaml:"vpc_access_connector"`
+	BetaSettings    map[string]string  `yaml:"beta_settings"`
+	Handlers        []appEngineHandler `yaml:"handlers"`
+	InboundServices []string           `yaml:"inbound_services"`
+	LivenessCheck   struct {
+		Path string `yaml:"path"`
+	} `yaml:"liveness_check"`
+	ReadinessCheck struct {
+		Path string `yaml:"path"`
+	} `yaml:"readiness_check"`
+}
+
+// appEngineAutomaticScaling is the automatic scaling of the standard and the flexible environments
+type appEngineAutomaticScaling struct {
+	MinInstances                int     `yaml:"min_instances"`
+	MaxInstances                int     `yaml:"max_instances"`
+	MinNumInstances             int     `yaml:"min_num_instances"`
+	MaxNumInstances             int     `yaml:"max_num_instances"`
+	TargetCPUUtilization        float64 `yaml:"target_cpu_utilization"`
+	TargetThroughputUtilization float64 `yaml:"target_throughput_utilization"`
+	MaxConcurrentRequests       int     `yaml:"max_concurrent_requests"`
+	MinIdleInstances            string  `yaml:"min_idle_instances"`
+	MaxIdleInstances            string  `yaml:"max_idle_instances"`
+	MinPendingLatency           string  `yaml:"min_pending_latency"`
+	MaxPendingLatency           string  `yaml:"max_pending_latency"`
+	CPUUtilization              struct {
+		TargetUtilization float64 `yaml:"target_utilization"`
+	} `yaml:"cpu_utilization"`
+}
+
+// appEngineHandler routes the requests of the urls matching a pattern to the app or to static files
+type appEngineHandler struct {
+	URL         string `yaml:"url"`
+	StaticDir   string `yaml:"static_dir"`
+	StaticFiles string `yaml:"static_files"`
+	Login       string `yaml:"login"`
+}
+
+// GetTranslatorType returns the translator type
+func (*AppEngineTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.AppEngine2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the App Engine apps in the directories containing an app.yaml with a runtime
+func (appEngineTranslator *AppEngineTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByName(inputPath, []string{appEngineAppYamlName})
+	if err != nil {
+		log.Warnf("Unable to fetch the app.yaml files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	sort.Strings(filePaths)
+	for _, filePath := range filePaths {
+		appYaml, ok := readAppEngineAppYaml(filePath)
+		if !ok {
+			continue
+		}
+		appDir := filepath.Dir(filePath)
+		serviceName := filepath.Base(appDir)
+		if appYaml.Service != "" && appYaml.Service != appEngineDefaultService {
+			serviceName = appYaml.Service
+		}
+		serviceName = common.NormalizeForServiceName(serviceName)
+		newAppService := func() plantypes.Service {
+			service := appEngineTranslator.newService(serviceName)
+			service.AddSourceArtifact(plantypes.AppEngineAppYamlArtifactType, filePath)
+			service.AddSourceArtifact(plantypes.SourceDirectoryArtifactType, appDir)
+			service.AddBuildArtifact(plantypes.SourceDirectoryBuildArtifactType, appDir)
+			if foundRepo, err := service.GatherGitInfo(appDir, plan); foundRepo && err != nil {
+				log.Warnf("Error while parsing the git repo at path %q Error: %q", appDir, err)
+			}
+			return service
+		}
+		dockerfilePath := filepath.Join(appDir, "Dockerfile")
+		if _, err := os.Stat(dockerfilePath); err == nil && appYaml.Runtime == appEngineCustomRuntime {
+			service := newAppService()
+			service.ContainerBuildType = plantypes.ReuseDockerFileContainerBuildTypeValue
+			service.ContainerizationTargetOptions = []string{dockerfilePath}
+			service.AddSourceArtifact(plantypes.DockerfileArtifactType, dockerfilePath)
+			services = append(services, service)
+			continue
+		}
+		// The app is built by the Google Cloud buildpacks, so their builder is preferred over the detected containerization options
+		cnbService := newAppService()
+		cnbService.ContainerBuildType = plantypes.CNBContainerBuildTypeValue
+		cnbService.ContainerizationTargetOptions = []string{gcpBuildpacksBuilder}
+		otherServices := []plantypes.Service{}
+		for _, cop := range containerizer.GetContainerizationOptions(plan, appDir) {
+			if cop.ContainerizationType == plantypes.CNBContainerBuildTypeValue {
+				for _, builder := range cop.TargetOptions {
+					if !common.IsStringPresent(cnbService.ContainerizationTargetOptions, builder) {
+						cnbService.ContainerizationTargetOptions = append(cnbService.ContainerizationTargetOptions, builder)
+					}
+				}
+				cnbService.Detection = cop.Detection
+				continue
+			}
+			service := newAppService()
+			service.ContainerBuildType = cop.ContainerizationType
+			service.ContainerizationTargetOptions = cop.TargetOptions
+			service.Detection = cop.Detection
+			otherServices = append(otherServices, service)
+		}
+		services = append(append(services, cnbService), otherServices...)
+	}
+	return services, nil
+}
+
+// Translate translates the App Engine apps to IR
+func (appEngineTranslator *AppEngineTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != appEngineTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		paths := service.SourceArtifacts[plantypes.AppEngineAppYamlArtifactType]
+		if len(paths) == 0 {
+			log.Warnf("No app.yaml found for the service %s", service.ServiceName)
+			continue
+		}
+		appYaml, ok := readAppEngineAppYaml(paths[0])
+		if !ok {
+			log.Warnf("Unable to read the app.yaml at path %s", paths[0])
+			continue
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		if err := containerizer.CommitServiceArtifacts(service.ServiceName, container); err != nil {
+			log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+		}
+		ir.AddContainer(container)
+		irService := irtypes.NewServiceFromPlanService(service)
+		translateAppEngineApp(&ir, &irService, service.Image, appYaml)
+		ir.Services[service.ServiceName] = irService
+	}
+	return ir, nil
+}
+
+func (appEngineTranslator *AppEngineTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, appEngineTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.DirectorySourceTypeValue)
+	service.AddSourceType(plantypes.AppEngineSourceTypeValue)
+	service.UpdateContainerBuildPipeline = true
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// readAppEngineAppYaml reads the app.yaml at the path, and returns false if it has no runtime, since app.yaml is a common name
+func readAppEngineAppYaml(path string) (appEngineAppYaml, bool) {
+	appYaml := appEngineAppYaml{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the app.yaml at path %s . Error: %q", path, err)
+		return appYaml, false
+	}
+	if err := yaml.Unmarshal(data, &appYaml); err != nil {
+		log.Debugf("Unable to parse the app.yaml at path %s . Error: %q", path, err)
+		return appYaml, false
+	}
+	return appYaml, appYaml.Runtime != ""
+}
+
+// translateAppEngineApp sets the container, the scaling, the ingress paths and the TODOs of the service from the app.yaml.
+// The env_variables are added to the IR as a ConfigMap.
+func translateAppEngineApp(ir *irtypes.IR, service *irtypes.Service, image string, appYaml appEngineAppYaml) {
+	unsupported := []string{}
+	container := core.Container{Name: service.Name, Image: image}
+	if appYaml.Entrypoint != "" {
+		container.Command = []string{"/bin/sh", "-c", appYaml.Entrypoint}
+	}
+	port := int32(gcpDefaultPort)
+	container.Ports = []core.ContainerPort{{ContainerPort: port}}
+	service.AddPortForwarding(irtypes.Port{Number: port}, irtypes.Port{Number: port})
+	container.Env = []core.EnvVar{{Name: "PORT", Value: cast.ToString(port)}}
+	if len(appYaml.EnvVariables) > 0 {
+		name := common.MakeFileNameCompliant(service.Name + "-env")
+		content := map[string][]byte{}
+		for key, value := range appYaml.EnvVariables {
+			content[key] = []byte(value)
+		}
+		ir.AddStorage(irtypes.Storage{Name: name, StorageType: irtypes.ConfigMapKind, Content: content})
+		container.EnvFrom = append(container.EnvFrom, core.EnvFromSource{ConfigMapRef: &core.ConfigMapEnvSource{LocalObjectReference: core.LocalObjectReference{Name: name}}})
+	}
+	if memory, ok := appEngineInstanceClassMemory[strings.ToUpper(appYaml.InstanceClass)]; ok {
+		setMemoryLimit(&container, memory)
+	} else if appYaml.InstanceClass != "" {
+		unsupported = append(unsupported, "the instance class "+appYaml.InstanceClass)
+	}
+	if appYaml.Resources.MemoryGB > 0 {
+		setMemoryLimit(&container, fmt.Sprintf("%dM", int(appYaml.Resources.MemoryGB*1024)))
+	}
+	if appYaml.Resources.CPU > 0 {
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = core.ResourceList{}
+		}
+		container.Resources.Limits[core.ResourceCPU] = *resource.NewMilliQuantity(int64(appYaml.Resources.CPU*1000), resource.DecimalSI)
+	}
+	if appYaml.LivenessCheck.Path != "" {
+		container.LivenessProbe = &core.Probe{Handler: core.Handler{HTTPGet: &core.HTTPGetAction{Path: appYaml.LivenessCheck.Path, Port: intstr.FromInt(int(port))}}}
+	}
+	if appYaml.ReadinessCheck.Path != "" {
+		container.ReadinessProbe = &core.Probe{Handler: core.Handler{HTTPGet: &core.HTTPGetAction{Path: appYaml.ReadinessCheck.Path, Port: intstr.FromInt(int(port))}}}
+	}
+	service.Containers = []core.Container{container}
+
+	var scalingUnsupported []string
+	service.Replicas, service.Autoscaling, service.ContainerConcurrency, scalingUnsupported = getAppEngineScaling(appYaml)
+	unsupported = append(unsupported, scalingUnsupported...)
+	for _, handler := range appYaml.Handlers {
+		if handler.StaticDir != "" || handler.StaticFiles != "" {
+			unsupported = append(unsupported, "the static files handler of "+handler.URL)
+		}
+		if handler.Login != "" && handler.Login != "optional" {
+			unsupported = append(unsupported, fmt.Sprintf("the login %s of %s", handler.Login, handler.URL))
+		}
+	}
+	if paths := getAppEngineIngressPaths(service.ServiceRelPath, appYaml); len(paths) > 0 {
+		service.ServiceRelPath, service.IngressPaths = paths[0], paths[1:]
+	}
+	for _, inboundService := range appYaml.InboundServices {
+		unsupported = append(unsupported, "the inbound service "+inboundService)
+	}
+	addGCPTODOs(service, appYaml.VPCAccessConnector.Name, appYaml.VPCAccessConnector.EgressSetting, splitCloudSQLInstances(appYaml.BetaSettings[appEngineCloudSQLSetting]), unsupported)
+}
+
+// getAppEngineIngressPaths returns the ingress paths of the handlers routed to the app. The default service is exposed at the root,
+// like on App Engine, and the other services at their relative path. The handlers are regular expressions, so only their literal
+// prefix up to the last complete path segment is used, and a catch-all handler exposes the whole base path.
+func getAppEngineIngressPaths(serviceRelPath string, appYaml appEngineAppYaml) []string {
+	base := serviceRelPath
+	if appYaml.Service == "" || appYaml.Service == appEngineDefaultService {
+		base = "/"
+	}
+	paths := []string{}
+	for _, handler := range appYaml.Handlers {
+		if handler.StaticDir != "" || handler.StaticFiles != "" {
+			continue
+		}
+		prefix := getAppEngineURLPrefix(handler.URL)
+		if prefix == "" {
+			return []string{base}
+		}
+		path := strings.TrimSuffix(base, "/") + prefix
+		if !common.IsStringPresent(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return []string{base}
+	}
+	return paths
+}
+
+// getAppEngineURLPrefix returns the literal path prefix of the url regular expression of a handler, without the trailing slash
+func getAppEngineURLPrefix(url string) string {
+	prefix := ""
+	for i := 0; i < len(url); i++ {
+		c := url[i]
+		if c == '\\' && i+1 < len(url) && strings.ContainsRune("./-_", rune(url[i+1])) {
+			i++
+			prefix += string(url[i])
+			continue
+		}
+		if strings.ContainsRune(`\.*+?()[]{}|^$`, rune(c)) {
+			// The last segment is only partially literal
+			return strings.TrimSuffix(prefix[:strings.LastIndex(prefix, "/")+1], "/")
+		}
+		prefix += string(c)
+	}
+	return strings.TrimSuffix(prefix, "/")
+}
+
+// getAppEngineScaling returns the replicas, the autoscaling and the container concurrency of the app, along with the scaling
+// settings which could not be converted. The throughput, idle instances and pending latency targets have no equivalent.
+func getAppEngineScaling(appYaml appEngineAppYaml) (int, *irtypes.Autoscaling, int, []string) {
+	unsupported := []string{}
+	if appYaml.ManualScaling != nil {
+		replicas := appYaml.ManualScaling.Instances
+		if replicas < 1 {
+			replicas = 1
+		}
+		return replicas, nil, 0, unsupported
+	}
+	if appYaml.BasicScaling != nil {
+		autoscaling := &irtypes.Autoscaling{MinReplicas: 1, MaxReplicas: appYaml.BasicScaling.MaxInstances}
+		if autoscaling.MaxReplicas < 1 {
+			autoscaling.MaxReplicas = 1
+		}
+		rule := "scale to zero"
+		if appYaml.BasicScaling.IdleTimeout != "" {
+			rule += " after " + appYaml.BasicScaling.IdleTimeout
+		}
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, rule)
+		return autoscaling.MinReplicas, autoscaling, 0, unsupported
+	}
+	scaling := appEngineAutomaticScaling{}
+	if appYaml.AutomaticScaling != nil {
+		scaling = *appYaml.AutomaticScaling
+	}
+	autoscaling := &irtypes.Autoscaling{MinReplicas: scaling.MinInstances, MaxReplicas: scaling.MaxInstances}
+	cpuTarget := scaling.TargetCPUUtilization
+	if appYaml.Env == appEngineFlexibleEnv {
+		autoscaling.MinReplicas, autoscaling.MaxReplicas = scaling.MinNumInstances, scaling.MaxNumInstances
+		if autoscaling.MinReplicas == 0 {
+			autoscaling.MinReplicas = appEngineDefaultFlexibleMinInstances
+		}
+		cpuTarget = scaling.CPUUtilization.TargetUtilization
+	}
+	if cpuTarget == 0 {
+		cpuTarget = appEngineDefaultCPUTarget
+	}
+	autoscaling.Metrics = []irtypes.AutoscalingMetric{{Resource: core.ResourceCPU, AverageUtilization: int32(math.Round(cpuTarget * 100))}}
+	if autoscaling.MaxReplicas == 0 {
+		autoscaling.MaxReplicas = appEngineDefaultMaxInstances
+	}
+	if autoscaling.MinReplicas < 1 {
+		autoscaling.MinReplicas = 1
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, "scale to zero")
+	}
+	if autoscaling.MaxReplicas < autoscaling.MinReplicas {
+		autoscaling.MaxReplicas = autoscaling.MinReplicas
+	}
+	if scaling.MaxConcurrentRequests > 0 {
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, fmt.Sprintf("max_concurrent_requests %d", scaling.MaxConcurrentRequests))
+	}
+	if scaling.TargetThroughputUtilization > 0 {
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, fmt.Sprintf("target_throughput_utilization %g", scaling.TargetThroughputUtilization))
+	}
+	for setting, value := range map[string]string{
+		"min_idle_instances":  scaling.MinIdleInstances,
+		"max_idle_instances":  scaling.MaxIdleInstances,
+		"min_pending_latency": scaling.MinPendingLatency,
+		"max_pending_latency": scaling.MaxPendingLatency,
+	} {
+		if value != "" {
+			unsupported = append(unsupported, setting+" "+value)
+		}
+	}
+	return autoscaling.MinReplicas, autoscaling, scaling.MaxConcurrentRequests, unsupported
+}
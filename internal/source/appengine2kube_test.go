@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestAppEngineApp(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"tickets/app.yaml": `runtime: python39
+service: tickets
+entrypoint: gunicorn -b :$PORT main:app
+instance_class: F2
+env_variables:
+  BUCKET: tickets
+automatic_scaling:
+  min_instances: 2
+  max_instances: 8
+  target_cpu_utilization: 0.7
+  max_concurrent_requests: 50
+  max_pending_latency: 100ms
+vpc_access_connector:
+  name: projects/project/locations/us-central1/connectors/tickets
+handlers:
+  - url: /static
+    static_dir: static
+  - url: /.*
+    script: auto
+`,
+		"other/app.yaml": "name: not an App Engine app\n",
+	})
+	appYaml, ok := readAppEngineAppYaml(filepath.Join(dir, "tickets", "app.yaml"))
+	if !ok || appYaml.Service != "tickets" {
+		t.Fatalf("Failed to read the app.yaml. Actual: %+v", appYaml)
+	}
+	if _, ok := readAppEngineAppYaml(filepath.Join(dir, "other", "app.yaml")); ok {
+		t.Fatalf("Expected the app.yaml without a runtime to be ignored")
+	}
+
+	service := irtypes.NewServiceWithName("tickets")
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	translateAppEngineApp(&ir, &service, "tickets:latest", appYaml)
+	container := service.Containers[0]
+	if !reflect.DeepEqual(container.Command, []string{"/bin/sh", "-c", "gunicorn -b :$PORT main:app"}) || container.Ports[0].ContainerPort != gcpDefaultPort {
+		t.Fatalf("Failed to set the command and the port of the container. Actual: %+v", container)
+	}
+	if len(container.Env) != 1 || container.Env[0].Name != "PORT" {
+		t.Fatalf("Failed to set the env of the container. Actual: %+v", container.Env)
+	}
+	if len(container.EnvFrom) != 1 || container.EnvFrom[0].ConfigMapRef.Name != "tickets-env" {
+		t.Fatalf("Failed to read the env variables from a ConfigMap. Actual: %+v", container.EnvFrom)
+	}
+	if len(ir.Storages) != 1 || ir.Storages[0].StorageType != irtypes.ConfigMapKind || string(ir.Storages[0].Content["BUCKET"]) != "tickets" {
+		t.Fatalf("Failed to create the ConfigMap of the env variables. Actual: %+v", ir.Storages)
+	}
+	if service.ServiceRelPath != "/tickets" || len(service.IngressPaths) != 0 {
+		t.Fatalf("Failed to expose the catch-all handler at the path of the service. Actual: %s %v", service.ServiceRelPath, service.IngressPaths)
+	}
+	if memory := container.Resources.Limits[core.ResourceMemory]; memory.String() != "768Mi" {
+		t.Fatalf("Failed to set the memory of the F2 instance class. Actual: %s", memory.String())
+	}
+	wantAutoscaling := &irtypes.Autoscaling{
+		MinReplicas:      2,
+		MaxReplicas:      8,
+		Metrics:          []irtypes.AutoscalingMetric{{Resource: core.ResourceCPU, AverageUtilization: 70}},
+		UnsupportedRules: []string{"max_concurrent_requests 50"},
+	}
+	if !reflect.DeepEqual(service.Autoscaling, wantAutoscaling) || service.Replicas != 2 || service.ContainerConcurrency != 50 {
+		t.Fatalf("Failed to set the scaling of the service. Expected: %+v Actual: %+v", wantAutoscaling, service.Autoscaling)
+	}
+	if service.Annotations[gcpVPCTODOKey] == "" {
+		t.Fatalf("Expected a TODO for the VPC connector. Actual: %v", service.Annotations)
+	}
+	wantUnsupported := "The settings max_pending_latency 100ms, the static files handler of /static have no equivalent and were not translated."
+	if service.Annotations[gcpUnsupportedTODOKey] != wantUnsupported {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", wantUnsupported, service.Annotations[gcpUnsupportedTODOKey])
+	}
+}
+
+func TestGetAppEngineScaling(t *testing.T) {
+	replicas, autoscaling, _, _ := getAppEngineScaling(appEngineAppYaml{Runtime: "go116", ManualScaling: &struct {
+		Instances int `yaml:"instances"`
+	}{Instances: 3}})
+	if replicas != 3 || autoscaling != nil {
+		t.Fatalf("Failed to convert the manual scaling. Actual: %d %+v", replicas, autoscaling)
+	}
+	replicas, autoscaling, _, _ = getAppEngineScaling(appEngineAppYaml{Runtime: "custom", Env: appEngineFlexibleEnv})
+	if replicas != appEngineDefaultFlexibleMinInstances || autoscaling.MaxReplicas != appEngineDefaultMaxInstances || autoscaling.Metrics[0].AverageUtilization != 60 {
+		t.Fatalf("Failed to default the automatic scaling of the flexible environment. Actual: %d %+v", replicas, autoscaling)
+	}
+}
+
+func TestGetAppEngineIngressPaths(t *testing.T) {
+	appYaml := appEngineAppYaml{Runtime: "python39", Handlers: []appEngineHandler{
+		{URL: "/images", StaticDir: "images"},
+		{URL: "/api/.*"},
+		{URL: `/v1\.0/users/(\d+)`},
+		{URL: "/admin/"},
+		{URL: "/api/"},
+	}}
+	want := []string{"/api", "/v1.0/users", "/admin"}
+	if paths := getAppEngineIngressPaths("/app", appYaml); !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Failed to get the paths of the default service. Expected: %v Actual: %v", want, paths)
+	}
+	appYaml.Service = "tickets"
+	want = []string{"/tickets/api", "/tickets/v1.0/users", "/tickets/admin"}
+	if paths := getAppEngineIngressPaths("/tickets", appYaml); !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Failed to get the paths of the tickets service. Expected: %v Actual: %v", want, paths)
+	}
+	appYaml.Handlers = append(appYaml.Handlers, appEngineHandler{URL: "/.*"})
+	if paths := getAppEngineIngressPaths("/tickets", appYaml); !reflect.DeepEqual(paths, []string{"/tickets"}) {
+		t.Fatalf("Expected the catch-all handler to expose the whole service path. Actual: %v", paths)
+	}
+}
@@ -0,0 +1,727 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	azureContainerAppType   = "Microsoft.App/containerApps"
+	azureContainerGroupType = "Microsoft.ContainerInstance/containerGroups"
+
+	// azureDefaultMaxReplicas is the maximum number of replicas of a container app when maxReplicas is not set
+	azureDefaultMaxReplicas = 10
+	// azureDefaultConcurrentRequests is the number of concurrent requests of the default http scale rule of the container apps
+	azureDefaultConcurrentRequests = 10
+
+	// azureUnsupportedTODOKey lists the settings of the source which have no equivalent in the translated resources
+	azureUnsupportedTODOKey = common.TODOAnnotation + "azureunsupported"
+)
+
+// AzureTranslator implements Translator interface for Azure Container Apps and Azure Container Instances, described by
+// ARM templates, or by the resources exported using az containerapp show or az container export
+type AzureTranslator struct {
+}
+
+// azureDocument is an ARM template, or a single exported resource
+type azureDocument struct {
+	Resources     []azureResource `yaml:"resources"`
+	azureResource `yaml:",inline"`
+}
+
+// azureResource contains the fields of the container apps and of the container groups which are used in the translation
+type azureResource struct {
+	Type       string `yaml:"type"`
+	Name       string `yaml:"name"`
+	Properties struct {
+		// The properties of the container apps
+		Configuration azureContainerAppConfiguration `yaml:"configuration"`
+		Template      struct {
+			Containers []azureContainer `yaml:"containers"`
+			Scale      azureScale       `yaml:"scale"`
+			Volumes    []azureVolume    `yaml:"volumes"`
+		} `yaml:"template"`
+		// The properties of the container groups
+		Containers []struct {
+			Name       string         `yaml:"name"`
+			Properties azureContainer `yaml:"properties"`
+		} `yaml:"containers"`
+		IPAddress *struct {
+			Type         string      `yaml:"type"`
+			Ports        []azurePort `yaml:"ports"`
+			DNSNameLabel string      `yaml:"dnsNameLabel"`
+		} `yaml:"ipAddress"`
+		RestartPolicy            string        `yaml:"restartPolicy"`
+		Volumes                  []azureVolume `yaml:"volumes"`
+		ImageRegistryCredentials []struct {
+			Server string `yaml:"server"`
+		} `yaml:"imageRegistryCredentials"`
+	} `yaml:"properties"`
+}
+
+// azureContainerAppConfiguration is the configuration shared by the revisions of a container app
+type azureContainerAppConfiguration struct {
+	Secrets []struct {
+		Name        string `yaml:"name"`
+		Value       string `yaml:"value"`
+		KeyVaultURL string `yaml:"keyVaultUrl"`
+	} `yaml:"secrets"`
+	Ingress *struct {
+		External      bool   `yaml:"external"`
+		TargetPort    int32  `yaml:"targetPort"`
+		Transport     string `yaml:"transport"`
+		CustomDomains []struct {
+			Name string `yaml:"name"`
+		} `yaml:"customDomains"`
+		IPSecurityRestrictions []struct {
+			Name string `yaml:"name"`
+		} `yaml:"ipSecurityRestrictions"`
+		Traffic []struct {
+			RevisionName string `yaml:"revisionName"`
+			Weight       int    `yaml:"weight"`
+		} `yaml:"traffic"`
+		StickySessions *struct {
+			Affinity string `yaml:"affinity"`
+		} `yaml:"stickySessions"`
+	} `yaml:"ingress"`
+	Dapr *struct {
+		Enabled          bool   `yaml:"enabled"`
+		AppID            string `yaml:"appId"`
+		AppPort          int32  `yaml:"appPort"`
+		AppProtocol      string `yaml:"appProtocol"`
+		LogLevel         string `yaml:"logLevel"`
+		EnableAPILogging bool   `yaml:"enableApiLogging"`
+	} `yaml:"dapr"`
+}
+
+// azureContainer is a container of a container app or of a container group
+type azureContainer struct {
+	Name                 string             `yaml:"name"`
+	Image                string             `yaml:"image"`
+	Command              []string           `yaml:"command"`
+	Args                 []string           `yaml:"args"`
+	Env                  []azureEnvVar      `yaml:"env"`
+	EnvironmentVariables []azureEnvVar      `yaml:"environmentVariables"`
+	Ports                []azurePort        `yaml:"ports"`
+	Resources            azureResources     `yaml:"resources"`
+	Probes               []azureProbe       `yaml:"probes"`
+	LivenessProbe        *azureProbe        `yaml:"livenessProbe"`
+	ReadinessProbe       *azureProbe        `yaml:"readinessProbe"`
+	VolumeMounts         []azureVolumeMount `yaml:"volumeMounts"`
+}
+
+// azureEnvVar is an env var set to a value, to a secret of the container app, or to a secure value of the container group
+type azureEnvVar struct {
+	Name        string `yaml:"name"`
+	Value       string `yaml:"value"`
+	SecretRef   string `yaml:"secretRef"`
+	SecureValue string `yaml:"secureValue"`
+}
+
+// azurePort is a port of a container group
+type azurePort struct {
+	Port     int32  `yaml:"port"`
+	Protocol string `yaml:"protocol"`
+}
+
+// azureResources are the cpu and memory of a container app, or the requests and limits of a container group
+type azureResources struct {
+	CPU      float64              `yaml:"cpu"`
+	Memory   string               `yaml:"memory"`
+	Requests *azureResourceAmount `yaml:"requests"`
+	Limits   *azureResourceAmount `yaml:"limits"`
+}
+
+// azureResourceAmount is an amount of cpu and memory of a container group
+type azureResourceAmount struct {
+	CPU        float64 `yaml:"cpu"`
+	MemoryInGB float64 `yaml:"memoryInGB"`
+}
+
+// azureProbe is a probe of a container. The type is only set for the container apps.
+type azureProbe struct {
+	Type    string `yaml:"type"`
+	HTTPGet *struct {
+		Path string `yaml:"path"`
+		Port int32  `yaml:"port"`
+	} `yaml:"httpGet"`
+	TCPSocket *struct {
+		Port int32 `yaml:"port"`
+	} `yaml:"tcpSocket"`
+	Exec *struct {
+		Command []string `yaml:"command"`
+	} `yaml:"exec"`
+}
+
+// azureVolume is a volume of a container app or of a container group
+type azureVolume struct {
+	Name        string            `yaml:"name"`
+	StorageType string            `yaml:"storageType"`
+	EmptyDir    interface{}       `yaml:"emptyDir"`
+	Secret      map[string]string `yaml:"secret"`
+	AzureFile   *struct {
+		ShareName string `yaml:"shareName"`
+	} `yaml:"azureFile"`
+	GitRepo *struct {
+		Repository string `yaml:"repository"`
+	} `yaml:"gitRepo"`
+}
+
+// azureVolumeMount mounts a volume in a container. The volume is named by volumeName in the container apps, and by name in the container groups.
+type azureVolumeMount struct {
+	VolumeName string `yaml:"volumeName"`
+	Name       string `yaml:"name"`
+	MountPath  string `yaml:"mountPath"`
+	ReadOnly   bool   `yaml:"readOnly"`
+}
+
+// azureScale sets the number of replicas of a container app, and the rules on which it is scaled
+type azureScale struct {
+	MinReplicas *int             `yaml:"minReplicas"`
+	MaxReplicas int              `yaml:"maxReplicas"`
+	Rules       []azureScaleRule `yaml:"rules"`
+}
+
+// azureScaleRule is a scale rule of a container app. Only one of the rules should be set. The custom rules are KEDA scalers.
+type azureScaleRule struct {
+	Name   string `yaml:"name"`
+	Custom *struct {
+		Type     string               `yaml:"type"`
+		Metadata map[string]string    `yaml:"metadata"`
+		Auth     []azureScaleRuleAuth `yaml:"auth"`
+	} `yaml:"custom"`
+	HTTP *struct {
+		Metadata map[string]string `yaml:"metadata"`
+	} `yaml:"http"`
+	TCP *struct {
+		Metadata map[string]string `yaml:"metadata"`
+	} `yaml:"tcp"`
+	AzureQueue *struct {
+		QueueName   string               `yaml:"queueName"`
+		QueueLength int                  `yaml:"queueLength"`
+		Auth        []azureScaleRuleAuth `yaml:"auth"`
+	} `yaml:"azureQueue"`
+}
+
+// azureScaleRuleAuth sets a parameter of the scaler to a secret of the container app
+type azureScaleRuleAuth struct {
+	SecretRef        string `yaml:"secretRef"`
+	TriggerParameter string `yaml:"triggerParameter"`
+}
+
+// GetTranslatorType returns the translator type
+func (*AzureTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.Azure2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the container apps and the container groups in the ARM templates and the exported resources
+func (azureTranslator *AzureTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByExt(inputPath, []string{".json", ".yaml", ".yml", ".bicep"})
+	if err != nil {
+		log.Warnf("Unable to fetch the ARM templates at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	sort.Strings(filePaths)
+	serviceNames := []string{}
+	for _, filePath := range filePaths {
+		if filepath.Ext(filePath) == ".bicep" {
+			if data, err := ioutil.ReadFile(filePath); err == nil && (strings.Contains(string(data), azureContainerAppType) || strings.Contains(string(data), azureContainerGroupType)) {
+				log.Warnf("The bicep file %s contains Azure container apps or container groups. Compile it to an ARM template using az bicep build --file %s to translate them.", filePath, filepath.Base(filePath))
+			}
+			continue
+		}
+		for _, azureRes := range readAzureResources(filePath) {
+			serviceName := getAzureServiceName(azureRes, filePath)
+			if common.IsStringPresent(serviceNames, serviceName) {
+				log.Warnf("Ignoring the Azure resource %s in %s , since a service with the same name was already found", azureRes.Name, filePath)
+				continue
+			}
+			serviceNames = append(serviceNames, serviceName)
+			containers := getAzureContainers(azureRes)
+			service := azureTranslator.newService(serviceName)
+			if strings.EqualFold(azureRes.Type, azureContainerGroupType) {
+				service.AddSourceType(plantypes.AzureContainerInstancesSourceTypeValue)
+			} else {
+				service.AddSourceType(plantypes.AzureContainerAppsSourceTypeValue)
+			}
+			service.Image = containers[0].Image
+			service.AddSourceArtifact(plantypes.AzureResourcesArtifactType, filePath)
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// Translate translates the container apps and the container groups to IR
+func (azureTranslator *AzureTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != azureTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		var azureRes *azureResource
+		for _, path := range service.SourceArtifacts[plantypes.AzureResourcesArtifactType] {
+			for _, res := range readAzureResources(path) {
+				if getAzureServiceName(res, path) == service.ServiceName {
+					res := res
+					azureRes = &res
+					break
+				}
+			}
+		}
+		if azureRes == nil {
+			log.Warnf("No Azure container app or container group found for the service %s", service.ServiceName)
+			continue
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		ir.AddContainer(container)
+		for _, azureContainer := range getAzureContainers(*azureRes)[1:] {
+			ir.AddContainer(irtypes.NewContainer(plantypes.ReuseContainerBuildTypeValue, azureContainer.Image, false))
+		}
+		irService := irtypes.NewServiceFromPlanService(service)
+		if strings.EqualFold(azureRes.Type, azureContainerGroupType) {
+			translateAzureContainerGroup(&ir, &irService, service.Image, *azureRes)
+		} else {
+			translateAzureContainerApp(&ir, &irService, service.Image, *azureRes)
+		}
+		ir.Services[service.ServiceName] = irService
+	}
+	return ir, nil
+}
+
+func (azureTranslator *AzureTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, azureTranslator.GetTranslatorType())
+	service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+	service.UpdateContainerBuildPipeline = false
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// readAzureResources returns the container apps and the container groups with containers in the ARM template or the exported resource at the path
+func readAzureResources(path string) []azureResource {
+	azureResources := []azureResource{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the file at path %s . Error: %q", path, err)
+		return azureResources
+	}
+	doc := azureDocument{}
+	// JSON is valid yaml, so the ARM templates are parsed the same way as the exported yaml
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		log.Debugf("Unable to parse the file at path %s as an ARM template. Error: %q", path, err)
+		return azureResources
+	}
+	for _, azureRes := range append(doc.Resources, doc.azureResource) {
+		if !strings.EqualFold(azureRes.Type, azureContainerAppType) && !strings.EqualFold(azureRes.Type, azureContainerGroupType) {
+			continue
+		}
+		if len(getAzureContainers(azureRes)) == 0 {
+			log.Debugf("Ignoring the Azure resource %s in %s , since it has no containers", azureRes.Name, path)
+			continue
+		}
+		azureResources = append(azureResources, azureRes)
+	}
+	return azureResources
+}
+
+// getAzureServiceName returns the name of the service of the resource. The names which are ARM template expressions,
+// like [parameters('name')], are replaced by the name of the file.
+func getAzureServiceName(azureRes azureResource, path string) string {
+	name := azureRes.Name
+	if name == "" || strings.HasPrefix(name, "[") {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return common.NormalizeForServiceName(name)
+}
+
+// getAzureContainers returns the containers of the container app or of the container group
+func getAzureContainers(azureRes azureResource) []azureContainer {
+	if !strings.EqualFold(azureRes.Type, azureContainerGroupType) {
+		return azureRes.Properties.Template.Containers
+	}
+	containers := []azureContainer{}
+	for _, container := range azureRes.Properties.Containers {
+		container.Properties.Name = container.Name
+		containers = append(containers, container.Properties)
+	}
+	return containers
+}
+
+// translateAzureContainerApp sets the containers, the ingress, the scaling, the secrets and the Dapr sidecar of the service from the container app
+func translateAzureContainerApp(ir *irtypes.IR, service *irtypes.Service, image string, azureRes azureResource) {
+	config := azureRes.Properties.Configuration
+	unsupported := []string{}
+	secretName := common.MakeFileNameCompliant(service.Name + "-secrets")
+	secrets := map[string][]byte{}
+	unsetSecrets := []string{}
+	for _, secret := range config.Secrets {
+		secrets[secret.Name] = []byte(secret.Value)
+		if secret.Value == "" {
+			unsetSecrets = append(unsetSecrets, secret.Name)
+		}
+	}
+	if len(secrets) > 0 {
+		ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: secrets})
+	}
+	if len(unsetSecrets) > 0 {
+		sort.Strings(unsetSecrets)
+		unsupported = append(unsupported, fmt.Sprintf("the values of the secrets %s", strings.Join(unsetSecrets, ", ")))
+	}
+	for i, azureContainer := range azureRes.Properties.Template.Containers {
+		container := getAzureContainer(azureContainer, secretName)
+		if i == 0 {
+			container.Name = service.Name
+			container.Image = image
+		}
+		if azureContainer.Resources.CPU > 0 || azureContainer.Resources.Memory != "" {
+			container.Resources.Limits = core.ResourceList{}
+			if azureContainer.Resources.CPU > 0 {
+				container.Resources.Limits[core.ResourceCPU] = *resource.NewMilliQuantity(int64(azureContainer.Resources.CPU*1000), resource.DecimalSI)
+			}
+			if memory, err := resource.ParseQuantity(azureContainer.Resources.Memory); err == nil {
+				container.Resources.Limits[core.ResourceMemory] = memory
+			} else if azureContainer.Resources.Memory != "" {
+				log.Warnf("Ignoring the invalid memory %s of the container %s . Error: %q", azureContainer.Resources.Memory, container.Name, err)
+			}
+		}
+		for _, probe := range azureContainer.Probes {
+			switch strings.ToLower(probe.Type) {
+			case "liveness":
+				container.LivenessProbe = getAzureProbe(probe)
+			case "readiness":
+				container.ReadinessProbe = getAzureProbe(probe)
+			case "startup":
+				container.StartupProbe = getAzureProbe(probe)
+			}
+		}
+		service.Containers = append(service.Containers, container)
+	}
+	unsupported = append(unsupported, addAzureVolumes(ir, service, azureRes.Properties.Template.Volumes, secretName)...)
+
+	if ingress := config.Ingress; ingress != nil && ingress.TargetPort != 0 {
+		service.Containers[0].Ports = []core.ContainerPort{{ContainerPort: ingress.TargetPort}}
+		service.AddPortForwarding(irtypes.Port{Number: ingress.TargetPort}, irtypes.Port{Number: ingress.TargetPort})
+		if ingress.External {
+			service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{common.ExposeSelector: common.AnnotationLabelValue})
+		}
+		switch strings.ToLower(ingress.Transport) {
+		case "tcp":
+			service.NonHTTPPorts = append(service.NonHTTPPorts, irtypes.NonHTTPPort{Number: ingress.TargetPort, Protocol: core.ProtocolTCP, Hint: "the tcp transport of the ingress of the container app"})
+		case "http2":
+			unsupported = append(unsupported, "the http2 transport of the ingress")
+		}
+		if ingress.StickySessions != nil && strings.EqualFold(ingress.StickySessions.Affinity, "sticky") {
+			service.StickySessions = true
+		}
+		for _, domain := range ingress.CustomDomains {
+			unsupported = append(unsupported, "the custom domain "+domain.Name)
+		}
+		if len(ingress.IPSecurityRestrictions) > 0 {
+			unsupported = append(unsupported, "the IP security restrictions of the ingress")
+		}
+		if len(ingress.Traffic) > 1 {
+			unsupported = append(unsupported, "the traffic split between the revisions")
+		}
+	}
+	if dapr := config.Dapr; dapr != nil && dapr.Enabled {
+		service.Annotations = common.MergeStringMaps(service.Annotations, getDaprAnnotations(dapr.AppID, dapr.AppPort, dapr.AppProtocol, dapr.LogLevel, dapr.EnableAPILogging, service.Name))
+	}
+
+	scale := azureRes.Properties.Template.Scale
+	service.Autoscaling, service.ContainerConcurrency = getAzureAutoscaling(scale, secretName)
+	service.Replicas = service.Autoscaling.MinReplicas
+	if service.Replicas < 1 {
+		service.Replicas = 1
+	}
+	addAzureUnsupportedTODO(service, unsupported)
+}
+
+// translateAzureContainerGroup sets the containers, the ports, the restart policy and the volumes of the service from the container group
+func translateAzureContainerGroup(ir *irtypes.IR, service *irtypes.Service, image string, azureRes azureResource) {
+	properties := azureRes.Properties
+	unsupported := []string{}
+	secretName := common.MakeFileNameCompliant(service.Name + "-secrets")
+	secrets := map[string][]byte{}
+	for i, azureContainer := range getAzureContainers(azureRes) {
+		for _, env := range azureContainer.EnvironmentVariables {
+			if env.SecureValue != "" {
+				secrets[env.Name] = []byte(env.SecureValue)
+			}
+		}
+		container := getAzureContainer(azureContainer, secretName)
+		if i == 0 {
+			container.Name = service.Name
+			container.Image = image
+		}
+		for _, port := range azureContainer.Ports {
+			container.Ports = append(container.Ports, core.ContainerPort{ContainerPort: port.Port, Protocol: getAzureProtocol(port.Protocol)})
+		}
+		if requests := azureContainer.Resources.Requests; requests != nil {
+			container.Resources.Requests = getAzureResourceList(*requests)
+		}
+		if limits := azureContainer.Resources.Limits; limits != nil {
+			container.Resources.Limits = getAzureResourceList(*limits)
+		}
+		if azureContainer.LivenessProbe != nil {
+			container.LivenessProbe = getAzureProbe(*azureContainer.LivenessProbe)
+		}
+		if azureContainer.ReadinessProbe != nil {
+			container.ReadinessProbe = getAzureProbe(*azureContainer.ReadinessProbe)
+		}
+		service.Containers = append(service.Containers, container)
+	}
+	if len(secrets) > 0 {
+		ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: secrets})
+	}
+	unsupported = append(unsupported, addAzureVolumes(ir, service, properties.Volumes, secretName)...)
+	if ipAddress := properties.IPAddress; ipAddress != nil {
+		for _, port := range ipAddress.Ports {
+			service.AddPortForwarding(irtypes.Port{Number: port.Port}, irtypes.Port{Number: port.Port})
+			if protocol := getAzureProtocol(port.Protocol); protocol == core.ProtocolUDP {
+				service.NonHTTPPorts = append(service.NonHTTPPorts, irtypes.NonHTTPPort{Number: port.Port, Protocol: protocol, Hint: "the UDP port of the container group"})
+			}
+		}
+		if strings.EqualFold(ipAddress.Type, "Public") {
+			service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{common.ExposeSelector: common.AnnotationLabelValue})
+		}
+		if ipAddress.DNSNameLabel != "" {
+			unsupported = append(unsupported, "the DNS name label "+ipAddress.DNSNameLabel)
+		}
+	}
+	switch strings.ToLower(properties.RestartPolicy) {
+	case "onfailure":
+		service.RestartPolicy = core.RestartPolicyOnFailure
+	case "never":
+		service.RestartPolicy = core.RestartPolicyNever
+	}
+	for _, credentials := range properties.ImageRegistryCredentials {
+		unsupported = append(unsupported, "the credentials of the registry "+credentials.Server)
+	}
+	addAzureUnsupportedTODO(service, unsupported)
+}
+
+// getAzureContainer returns the container with its command and env. The env vars referring to secrets are read from the secret.
+func getAzureContainer(azureContainer azureContainer, secretName string) core.Container {
+	container := core.Container{Name: common.NormalizeForServiceName(azureContainer.Name), Image: azureContainer.Image, Command: azureContainer.Command, Args: azureContainer.Args}
+	for _, env := range append(azureContainer.Env, azureContainer.EnvironmentVariables...) {
+		secretKey := env.SecretRef
+		if env.SecureValue != "" {
+			secretKey = env.Name
+		}
+		if secretKey == "" {
+			container.Env = append(container.Env, core.EnvVar{Name: env.Name, Value: env.Value})
+			continue
+		}
+		container.Env = append(container.Env, core.EnvVar{Name: env.Name, ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+			LocalObjectReference: core.LocalObjectReference{Name: secretName},
+			Key:                  secretKey,
+		}}})
+	}
+	for _, volumeMount := range azureContainer.VolumeMounts {
+		name := volumeMount.VolumeName
+		if name == "" {
+			name = volumeMount.Name
+		}
+		container.VolumeMounts = append(container.VolumeMounts, core.VolumeMount{Name: common.NormalizeForServiceName(name), MountPath: volumeMount.MountPath, ReadOnly: volumeMount.ReadOnly})
+	}
+	return container
+}
+
+// getAzureProbe returns the probe checking the http path, the tcp port or the command
+func getAzureProbe(probe azureProbe) *core.Probe {
+	switch {
+	case probe.HTTPGet != nil:
+		return &core.Probe{Handler: core.Handler{HTTPGet: &core.HTTPGetAction{Path: probe.HTTPGet.Path, Port: intstr.FromInt(int(probe.HTTPGet.Port))}}}
+	case probe.TCPSocket != nil:
+		return &core.Probe{Handler: core.Handler{TCPSocket: &core.TCPSocketAction{Port: intstr.FromInt(int(probe.TCPSocket.Port))}}}
+	case probe.Exec != nil:
+		return &core.Probe{Handler: core.Handler{Exec: &core.ExecAction{Command: probe.Exec.Command}}}
+	}
+	return nil
+}
+
+// getAzureProtocol returns the protocol of a port of a container group, which defaults to TCP
+func getAzureProtocol(protocol string) core.Protocol {
+	if strings.EqualFold(protocol, string(core.ProtocolUDP)) {
+		return core.ProtocolUDP
+	}
+	return core.ProtocolTCP
+}
+
+// getAzureResourceList returns the cpu and memory of a container of a container group
+func getAzureResourceList(amount azureResourceAmount) core.ResourceList {
+	resources := core.ResourceList{}
+	if amount.CPU > 0 {
+		resources[core.ResourceCPU] = *resource.NewMilliQuantity(int64(amount.CPU*1000), resource.DecimalSI)
+	}
+	if amount.MemoryInGB > 0 {
+		resources[core.ResourceMemory] = *resource.NewQuantity(int64(amount.MemoryInGB*1024)*1024*1024, resource.BinarySI)
+	}
+	return resources
+}
+
+// addAzureVolumes adds the empty dir and the secret volumes to the service, and returns the volumes which have no equivalent.
+// The secret volumes of the container apps mount the secrets of the app, and those of the container groups have base64 encoded values.
+func addAzureVolumes(ir *irtypes.IR, service *irtypes.Service, volumes []azureVolume, appSecretName string) []string {
+	unsupported := []string{}
+	for _, volume := range volumes {
+		name := common.NormalizeForServiceName(volume.Name)
+		switch {
+		case strings.EqualFold(volume.StorageType, "EmptyDir") || (volume.StorageType == "" && volume.EmptyDir != nil):
+			service.Volumes = append(service.Volumes, core.Volume{Name: name, VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}})
+		case strings.EqualFold(volume.StorageType, "Secret"):
+			service.Volumes = append(service.Volumes, core.Volume{Name: name, VolumeSource: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: appSecretName}}})
+		case len(volume.Secret) > 0:
+			secretName := common.MakeFileNameCompliant(service.Name + "-" + volume.Name)
+			content := map[string][]byte{}
+			for key, value := range volume.Secret {
+				decoded, err := base64.StdEncoding.DecodeString(value)
+				if err != nil {
+					log.Warnf("The value of the key %s of the secret volume %s is not base64 encoded. Using it as is.", key, volume.Name)
+					decoded = []byte(value)
+				}
+				content[key] = decoded
+			}
+			ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: content})
+			service.Volumes = append(service.Volumes, core.Volume{Name: name, VolumeSource: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: secretName}}})
+		case volume.GitRepo != nil:
+			unsupported = append(unsupported, fmt.Sprintf("the git repo volume %s of %s", volume.Name, volume.GitRepo.Repository))
+		default:
+			unsupported = append(unsupported, fmt.Sprintf("the Azure Files volume %s", volume.Name))
+		}
+	}
+	return unsupported
+}
+
+// getAzureAutoscaling returns the autoscaling and the container concurrency of the container app. The custom scale rules are
+// KEDA scalers, and become triggers of a ScaledObject. The http and tcp rules become unsupported rules, and the concurrency is kept by Knative.
+// The app scales on the http concurrency when it has no rule.
+func getAzureAutoscaling(scale azureScale, secretName string) (*irtypes.Autoscaling, int) {
+	autoscaling := &irtypes.Autoscaling{MaxReplicas: scale.MaxReplicas}
+	if scale.MinReplicas != nil {
+		autoscaling.MinReplicas = *scale.MinReplicas
+	}
+	if autoscaling.MaxReplicas == 0 {
+		autoscaling.MaxReplicas = azureDefaultMaxReplicas
+	}
+	getSecretRefs := func(auths []azureScaleRuleAuth) []irtypes.AutoscalingSecretRef {
+		secretRefs := []irtypes.AutoscalingSecretRef{}
+		for _, auth := range auths {
+			secretRefs = append(secretRefs, irtypes.AutoscalingSecretRef{Parameter: auth.TriggerParameter, SecretName: secretName, Key: auth.SecretRef})
+		}
+		return secretRefs
+	}
+	concurrency := 0
+	rules := scale.Rules
+	if len(rules) == 0 {
+		rules = []azureScaleRule{{Name: "http", HTTP: &struct {
+			Metadata map[string]string `yaml:"metadata"`
+		}{Metadata: map[string]string{"concurrentRequests": cast.ToString(azureDefaultConcurrentRequests)}}}}
+	}
+	for _, rule := range rules {
+		switch {
+		case rule.Custom != nil:
+			autoscaling.Triggers = append(autoscaling.Triggers, irtypes.AutoscalingTrigger{
+				Name:       common.NormalizeForServiceName(rule.Name),
+				Type:       rule.Custom.Type,
+				Metadata:   common.MergeStringMaps(rule.Custom.Metadata, nil),
+				SecretRefs: getSecretRefs(rule.Custom.Auth),
+			})
+		case rule.AzureQueue != nil:
+			autoscaling.Triggers = append(autoscaling.Triggers, irtypes.AutoscalingTrigger{
+				Name:       common.NormalizeForServiceName(rule.Name),
+				Type:       "azure-queue",
+				Metadata:   map[string]string{"queueName": rule.AzureQueue.QueueName, "queueLength": cast.ToString(rule.AzureQueue.QueueLength)},
+				SecretRefs: getSecretRefs(rule.AzureQueue.Auth),
+			})
+		case rule.HTTP != nil:
+			concurrency = cast.ToInt(rule.HTTP.Metadata["concurrentRequests"])
+			autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, fmt.Sprintf("http concurrentRequests %d", concurrency))
+		case rule.TCP != nil:
+			autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, fmt.Sprintf("tcp concurrentConnections %s", rule.TCP.Metadata["concurrentConnections"]))
+		}
+	}
+	// KEDA scales the workloads with triggers to zero, but the horizontal pod autoscalers need a replica
+	if autoscaling.MinReplicas < 1 && len(autoscaling.Triggers) == 0 {
+		autoscaling.MinReplicas = 1
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, "scale to zero")
+	}
+	if autoscaling.MaxReplicas < autoscaling.MinReplicas {
+		autoscaling.MaxReplicas = autoscaling.MinReplicas
+	}
+	return autoscaling, concurrency
+}
+
+// getDaprAnnotations returns the annotations injecting the Dapr sidecar. The Dapr components are created by the customizer.
+func getDaprAnnotations(appID string, appPort int32, appProtocol, logLevel string, enableAPILogging bool, serviceName string) map[string]string {
+	if appID == "" {
+		appID = serviceName
+	}
+	annotations := map[string]string{
+		common.DaprEnabledAnnotation: common.AnnotationLabelValue,
+		common.DaprAppIDAnnotation:   appID,
+	}
+	if appPort != 0 {
+		annotations[common.DaprAppPortAnnotation] = cast.ToString(appPort)
+	}
+	if appProtocol != "" {
+		annotations["dapr.io/app-protocol"] = appProtocol
+	}
+	if logLevel != "" {
+		annotations["dapr.io/log-level"] = logLevel
+	}
+	if enableAPILogging {
+		annotations["dapr.io/enable-api-logging"] = "true"
+	}
+	return annotations
+}
+
+// addAzureUnsupportedTODO annotates the service with the settings which were lost in the translation
+func addAzureUnsupportedTODO(service *irtypes.Service, unsupported []string) {
+	if len(unsupported) == 0 {
+		return
+	}
+	sort.Strings(unsupported)
+	service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+		azureUnsupportedTODOKey: fmt.Sprintf("The settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", ")),
+	})
+}
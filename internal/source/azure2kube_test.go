@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const testAzureARMTemplate = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+  "resources": [
+    {
+      "type": "Microsoft.App/managedEnvironments",
+      "name": "tickets-env"
+    },
+    {
+      "type": "Microsoft.App/containerApps",
+      "name": "tickets",
+      "properties": {
+        "configuration": {
+          "secrets": [{"name": "queue-connection", "value": "Endpoint=sb://tickets"}, {"name": "db-password", "keyVaultUrl": "https://vault/secrets/db"}],
+          "ingress": {"external": true, "targetPort": 3000, "stickySessions": {"affinity": "sticky"}, "customDomains": [{"name": "tickets.example.com"}]},
+          "dapr": {"enabled": true, "appId": "tickets-api", "appPort": 3000}
+        },
+        "template": {
+          "containers": [{
+            "name": "api",
+            "image": "myregistry.azurecr.io/tickets:v1",
+            "env": [{"name": "NODE_ENV", "value": "production"}, {"name": "DB_PASSWORD", "secretRef": "db-password"}],
+            "resources": {"cpu": 0.5, "memory": "1Gi"},
+            "probes": [{"type": "Liveness", "httpGet": {"path": "/healthz", "port": 3000}}]
+          }],
+          "scale": {
+            "minReplicas": 0,
+            "maxReplicas": 5,
+            "rules": [{"name": "queue", "custom": {"type": "azure-servicebus", "metadata": {"queueName": "orders", "messageCount": "20"}, "auth": [{"secretRef": "queue-connection", "triggerParameter": "connection"}]}}]
+          }
+        }
+      }
+    }
+  ]
+}`
+
+const testAzureContainerGroup = `apiVersion: '2021-10-01'
+name: worker
+type: Microsoft.ContainerInstance/containerGroups
+properties:
+  restartPolicy: OnFailure
+  containers:
+    - name: worker
+      properties:
+        image: myregistry.azurecr.io/worker:v1
+        environmentVariables:
+          - name: MODE
+            value: batch
+          - name: API_KEY
+            secureValue: s3cr3t
+        resources:
+          requests:
+            cpu: 1
+            memoryInGB: 1.5
+        volumeMounts:
+          - name: config
+            mountPath: /etc/worker
+  volumes:
+    - name: config
+      secret:
+        settings.json: e30=
+    - name: data
+      azureFile:
+        shareName: data
+`
+
+func TestAzureContainerApp(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"tickets.json":      testAzureARMTemplate,
+		"aci/worker.yaml":   testAzureContainerGroup,
+		"other/values.yaml": "replicas: 2\n",
+	})
+	services, err := new(AzureTranslator).GetServiceOptions(dir, plantypes.NewPlan())
+	if err != nil {
+		t.Fatalf("Failed to get the services. Error: %q", err)
+	}
+	if len(services) != 2 || services[0].ServiceName != "worker" || services[1].ServiceName != "tickets" || services[1].Image != "myregistry.azurecr.io/tickets:v1" {
+		t.Fatalf("Expected the worker and the tickets services. Actual: %+v", services)
+	}
+	if !reflect.DeepEqual(services[1].SourceTypes, []plantypes.SourceTypeValue{plantypes.AzureContainerAppsSourceTypeValue}) {
+		t.Fatalf("Failed to set the source type of the container app. Actual: %v", services[1].SourceTypes)
+	}
+
+	azureRes := readAzureResources(filepath.Join(dir, "tickets.json"))
+	if len(azureRes) != 1 {
+		t.Fatalf("Expected only the container app of the template. Actual: %+v", azureRes)
+	}
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	service := irtypes.NewServiceWithName("tickets")
+	translateAzureContainerApp(&ir, &service, "myregistry.azurecr.io/tickets:v1", azureRes[0])
+	container := service.Containers[0]
+	if container.Name != "tickets" || container.Ports[0].ContainerPort != 3000 || container.Env[1].ValueFrom.SecretKeyRef.Key != "db-password" || container.LivenessProbe.HTTPGet.Path != "/healthz" {
+		t.Fatalf("Failed to translate the container. Actual: %+v", container)
+	}
+	if cpu, memory := container.Resources.Limits[core.ResourceCPU], container.Resources.Limits[core.ResourceMemory]; cpu.String() != "500m" || memory.String() != "1Gi" {
+		t.Fatalf("Failed to set the limits of the container. Actual: %+v", container.Resources.Limits)
+	}
+	if !service.HasValidAnnotation(common.ExposeSelector) || !service.StickySessions {
+		t.Fatalf("Failed to translate the ingress. Actual: %+v", service)
+	}
+	if service.Annotations[common.DaprAppIDAnnotation] != "tickets-api" || service.Annotations[common.DaprAppPortAnnotation] != "3000" || service.Annotations[common.DaprEnabledAnnotation] != "true" {
+		t.Fatalf("Failed to translate the Dapr sidecar. Actual: %v", service.Annotations)
+	}
+	if len(ir.Storages) != 1 || string(ir.Storages[0].Content["queue-connection"]) != "Endpoint=sb://tickets" {
+		t.Fatalf("Failed to store the secrets. Actual: %+v", ir.Storages)
+	}
+	wantAutoscaling := &irtypes.Autoscaling{
+		MinReplicas: 0,
+		MaxReplicas: 5,
+		Triggers: []irtypes.AutoscalingTrigger{{
+			Name:       "queue",
+			Type:       "azure-servicebus",
+			Metadata:   map[string]string{"queueName": "orders", "messageCount": "20"},
+			SecretRefs: []irtypes.AutoscalingSecretRef{{Parameter: "connection", SecretName: "tickets-secrets", Key: "queue-connection"}},
+		}},
+	}
+	if !reflect.DeepEqual(service.Autoscaling, wantAutoscaling) || service.Replicas != 1 {
+		t.Fatalf("Failed to translate the scale rules. Expected: %+v Actual: %+v", wantAutoscaling, service.Autoscaling)
+	}
+	wantUnsupported := "The settings the custom domain tickets.example.com, the values of the secrets db-password have no equivalent and were not translated."
+	if service.Annotations[azureUnsupportedTODOKey] != wantUnsupported {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", wantUnsupported, service.Annotations[azureUnsupportedTODOKey])
+	}
+}
+
+func TestAzureContainerGroup(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{"worker.yaml": testAzureContainerGroup})
+	azureRes := readAzureResources(filepath.Join(dir, "worker.yaml"))
+	if len(azureRes) != 1 {
+		t.Fatalf("Expected the container group. Actual: %+v", azureRes)
+	}
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	service := irtypes.NewServiceWithName("worker")
+	translateAzureContainerGroup(&ir, &service, "myregistry.azurecr.io/worker:v1", azureRes[0])
+	if service.RestartPolicy != core.RestartPolicyOnFailure {
+		t.Fatalf("Expected the container group to run as a job. Actual: %s", service.RestartPolicy)
+	}
+	container := service.Containers[0]
+	if container.Env[0].Value != "batch" || container.Env[1].ValueFrom.SecretKeyRef.Name != "worker-secrets" || container.Env[1].ValueFrom.SecretKeyRef.Key != "API_KEY" {
+		t.Fatalf("Failed to set the env of the container. Actual: %+v", container.Env)
+	}
+	if cpu, memory := container.Resources.Requests[core.ResourceCPU], container.Resources.Requests[core.ResourceMemory]; cpu.String() != "1" || memory.String() != "1536Mi" {
+		t.Fatalf("Failed to set the requests of the container. Actual: %+v", container.Resources.Requests)
+	}
+	if len(service.Volumes) != 1 || service.Volumes[0].Secret.SecretName != "worker-config" || len(container.VolumeMounts) != 1 {
+		t.Fatalf("Failed to mount the secret volume. Actual: %+v", service.Volumes)
+	}
+	if len(ir.Storages) != 2 || string(ir.Storages[0].Content["API_KEY"]) != "s3cr3t" || string(ir.Storages[1].Content["settings.json"]) != "{}" {
+		t.Fatalf("Failed to store the secure values and the secret volume. Actual: %+v", ir.Storages)
+	}
+	if service.Annotations[azureUnsupportedTODOKey] != "The settings the Azure Files volume data have no equivalent and were not translated." {
+		t.Fatalf("Failed to list the unsupported settings. Actual: %v", service.Annotations)
+	}
+}
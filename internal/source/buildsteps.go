@@ -0,0 +1,316 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer/scripts"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	dockerfilePrefix      = "Dockerfile."
+	dockerBuildScriptName = "-docker-build.sh"
+)
+
+// buildStage is a Dockerfile along with its build context, which becomes one or more stages of a multi-stage Dockerfile
+type buildStage struct {
+	name       string
+	dockerfile string
+	context    string
+}
+
+// composeBuildSteps composes the translated build steps with their services. The steps are either built as stages of the
+// Dockerfile of the service, or as separate images which run as additional containers in the pods of the service.
+func composeBuildSteps(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		if len(services) == 0 || len(services[0].BuildSteps.Steps) == 0 {
+			continue
+		}
+		service := services[0]
+		irService, ok := ir.Services[serviceName]
+		if !ok {
+			log.Warnf("The service %s was not translated. Ignoring its build steps.", serviceName)
+			continue
+		}
+		if service.BuildSteps.Composition == plantypes.MultiStageBuildComposition {
+			err := composeStages(ir, p, service)
+			if err == nil {
+				for _, step := range service.BuildSteps.Steps {
+					delete(ir.Services, step.ServiceName)
+				}
+				continue
+			}
+			log.Warnf("Unable to build the steps of the service %s as stages of its Dockerfile. Building an image for every step instead. Error: %q", serviceName, err)
+		}
+		for _, step := range service.BuildSteps.Steps {
+			stepService, ok := ir.Services[step.ServiceName]
+			if !ok {
+				log.Warnf("The build step %s of the service %s was not translated. Ignoring it.", step.ServiceName, serviceName)
+				continue
+			}
+			irService.Containers = append(irService.Containers, stepService.Containers...)
+			for _, forwarding := range stepService.ServiceToPodPortForwardings {
+				if err := irService.AddPortForwarding(forwarding.ServicePort, forwarding.PodPort); err != nil {
+					log.Debugf("Unable to forward the port of the build step %s . Error: %q", step.ServiceName, err)
+				}
+			}
+			delete(ir.Services, step.ServiceName)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// composeStages replaces the Dockerfile of the service with a multi-stage Dockerfile which builds the steps first.
+// The Dockerfile is written to the common directory of the build contexts, which is used as the build context of all the stages.
+func composeStages(ir *irtypes.IR, p plantypes.Plan, service plantypes.Service) error {
+	if !service.IsDockerfileBuild() {
+		return fmt.Errorf("the service %s and its build steps are not all built using Dockerfiles", service.ServiceName)
+	}
+	containerIdx := getContainerIndex(*ir, service.Image)
+	if containerIdx == -1 {
+		return fmt.Errorf("unable to find the container of the service %s with the image %s", service.ServiceName, service.Image)
+	}
+	stages := []buildStage{}
+	contexts := []string{}
+	for _, s := range append(append([]plantypes.Service{}, service.BuildSteps.Steps...), service) {
+		stage, err := getBuildStage(*ir, s)
+		if err != nil {
+			return err
+		}
+		stages = append(stages, stage)
+		contexts = append(contexts, stage.context)
+	}
+	commonContext := common.CleanAndFindCommonDirectory(contexts)
+
+	globalArgs := []string{}
+	stageLines := []string{}
+	for i, stage := range stages {
+		relContext, err := filepath.Rel(commonContext, stage.context)
+		if err != nil {
+			return err
+		}
+		isService := i == len(stages)-1
+		args, lines := rewriteDockerfileStages(stage.dockerfile, stage.name, filepath.ToSlash(relContext), !isService)
+		globalArgs = append(globalArgs, args...)
+		if isService {
+			stageLines = append(stageLines, "", "# Service "+stage.name)
+		} else {
+			stageLines = append(stageLines, "", "# Build step "+stage.name)
+		}
+		stageLines = append(stageLines, lines...)
+	}
+	for _, step := range service.BuildSteps.Steps {
+		artifacts := service.BuildSteps.Artifacts[step.ServiceName]
+		if len(artifacts) == 0 {
+			stageLines = append(stageLines, fmt.Sprintf("# TODO: copy the outputs of the build step %s into the image, like COPY --from=%s /app/dist /app/public", step.ServiceName, step.ServiceName))
+			continue
+		}
+		for _, artifact := range artifacts {
+			source, destination := artifact, artifact
+			if parts := strings.SplitN(artifact, ":", 2); len(parts) == 2 {
+				source, destination = parts[0], parts[1]
+			}
+			stageLines = append(stageLines, fmt.Sprintf("COPY --from=%s %s %s", step.ServiceName, source, destination))
+		}
+	}
+	dockerfileContents := strings.TrimLeft(strings.Join(append(common.UniqueStrings(globalArgs), stageLines...), "\n"), "\n") + "\n"
+
+	relCommonContext, err := p.GetRelativePath(commonContext)
+	if err != nil {
+		return err
+	}
+	dockerfileName := dockerfilePrefix + service.ServiceName
+	dockerBuildScript, err := common.GetStringFromTemplate(scripts.Dockerbuild_sh, struct {
+		Dockerfilename string
+		ImageName      string
+		Context        string
+	}{
+		Dockerfilename: dockerfileName,
+		ImageName:      service.Image,
+		Context:        ".",
+	})
+	if err != nil {
+		return err
+	}
+	container := ir.Containers[containerIdx]
+	for relPath := range container.NewFiles {
+		if filepath.Base(relPath) == dockerfileName || filepath.Base(relPath) == service.ServiceName+dockerBuildScriptName {
+			delete(container.NewFiles, relPath)
+		}
+	}
+	container.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+	container.AddFile(filepath.Join(relCommonContext, dockerfileName), dockerfileContents)
+	container.AddFile(filepath.Join(relCommonContext, service.ServiceName+dockerBuildScriptName), dockerBuildScript)
+	container.RepoInfo.TargetPath = filepath.Join(container.RepoInfo.GitRepoDir, relCommonContext, dockerfileName)
+	ir.Containers[containerIdx] = container
+	for _, step := range service.BuildSteps.Steps {
+		if stepIdx := getContainerIndex(*ir, step.Image); stepIdx != -1 && !common.IsStringPresent(ir.Containers[stepIdx].ImageNames, service.Image) {
+			ir.Containers = append(ir.Containers[:stepIdx], ir.Containers[stepIdx+1:]...)
+		}
+	}
+	return nil
+}
+
+// getContainerIndex returns the index of the container which builds the image, or -1
+func getContainerIndex(ir irtypes.IR, image string) int {
+	for i, container := range ir.Containers {
+		if common.IsStringPresent(container.ImageNames, image) {
+			return i
+		}
+	}
+	return -1
+}
+
+// getBuildStage returns the Dockerfile and the build context of a service built using a new or an existing Dockerfile
+func getBuildStage(ir irtypes.IR, service plantypes.Service) (buildStage, error) {
+	stage := buildStage{name: service.ServiceName}
+	if len(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType]) > 0 {
+		stage.context = service.SourceArtifacts[plantypes.SourceDirectoryArtifactType][0]
+	}
+	if len(service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType]) > 0 {
+		stage.context = service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType][0]
+	}
+	if service.ContainerBuildType == plantypes.ReuseDockerFileContainerBuildTypeValue {
+		if len(service.ContainerizationTargetOptions) == 0 {
+			return stage, fmt.Errorf("the service %s has no Dockerfile", service.ServiceName)
+		}
+		dockerfilePath := service.ContainerizationTargetOptions[0]
+		dockerfile, err := ioutil.ReadFile(dockerfilePath)
+		if err != nil {
+			return stage, fmt.Errorf("unable to read the Dockerfile of the service %s at path %s : %s", service.ServiceName, dockerfilePath, err)
+		}
+		stage.dockerfile = string(dockerfile)
+		if stage.context == "" {
+			stage.context = filepath.Dir(dockerfilePath)
+		}
+		return stage, nil
+	}
+	containerIdx := getContainerIndex(ir, service.Image)
+	if containerIdx == -1 {
+		return stage, fmt.Errorf("unable to find the container of the service %s with the image %s", service.ServiceName, service.Image)
+	}
+	for relPath, contents := range ir.Containers[containerIdx].NewFiles {
+		if filepath.Base(relPath) == dockerfilePrefix+service.ServiceName {
+			stage.dockerfile = contents
+		}
+	}
+	if stage.dockerfile == "" {
+		return stage, fmt.Errorf("unable to find the Dockerfile generated for the service %s", service.ServiceName)
+	}
+	if stage.context == "" {
+		return stage, fmt.Errorf("the service %s has no build context", service.ServiceName)
+	}
+	return stage, nil
+}
+
+// rewriteDockerfileStages prefixes the stage names of the Dockerfile with the name, so that the stages of the Dockerfiles
+// do not collide, and the sources copied from the build context with the path of the context relative to the common context.
+// If isStep is true, the last stage is named after the step, so that the service can copy its outputs.
+// The arguments declared before the first stage are returned separately, since they have to precede all the stages.
+func rewriteDockerfileStages(dockerfile, name, relContext string, isStep bool) (globalArgs []string, lines []string) {
+	allLines := strings.Split(strings.TrimRight(dockerfile, "\n"), "\n")
+	stageCount := 0
+	for _, line := range allLines {
+		if isInstruction(line, "FROM") {
+			stageCount++
+		}
+	}
+	stageNames := map[string]string{}
+	stageIdx := 0
+	for _, line := range allLines {
+		fields := strings.Fields(line)
+		switch {
+		case stageIdx == 0 && isInstruction(line, "ARG"):
+			globalArgs = append(globalArgs, line)
+			continue
+		case stageIdx == 0 && !isInstruction(line, "FROM"):
+			// Comments and parser directives of the Dockerfile before the first stage
+			lines = append(lines, line)
+			continue
+		case isInstruction(line, "FROM"):
+			stageIdx++
+			newName := name + "-" + strconv.Itoa(stageIdx)
+			if isStep && stageIdx == stageCount {
+				newName = name
+			}
+			from := []string{}
+			for i := 1; i < len(fields); i++ {
+				if strings.EqualFold(fields[i], "AS") && i+1 < len(fields) {
+					stageNames[fields[i+1]] = newName
+					break
+				}
+				if renamed, ok := stageNames[fields[i]]; ok {
+					from = append(from, renamed)
+					continue
+				}
+				from = append(from, fields[i])
+			}
+			lines = append(lines, fields[0]+" "+strings.Join(from, " ")+" AS "+newName)
+			continue
+		case isInstruction(line, "COPY") || isInstruction(line, "ADD"):
+			lines = append(lines, rewriteCopy(fields, stageNames, relContext))
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return globalArgs, lines
+}
+
+// rewriteCopy renames the stages copied from and prefixes the sources copied from the build context with the relative context
+func rewriteCopy(fields []string, stageNames map[string]string, relContext string) string {
+	rewritten := []string{fields[0]}
+	fromStage := false
+	args := []string{}
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "--") || len(args) > 0 {
+			args = append(args, field)
+			continue
+		}
+		if strings.HasPrefix(field, "--from=") {
+			fromStage = true
+			if renamed, ok := stageNames[strings.TrimPrefix(field, "--from=")]; ok {
+				field = "--from=" + renamed
+			}
+		}
+		rewritten = append(rewritten, field)
+	}
+	// The sources copied from other stages or images, the json form and the sources in the root of the common context are kept as is
+	if !fromStage && relContext != "." && len(args) > 1 && !strings.HasPrefix(args[0], "[") && !strings.HasSuffix(args[len(args)-1], "\\") {
+		for i := range args[:len(args)-1] {
+			if !strings.Contains(args[i], "://") {
+				args[i] = path.Join(relContext, args[i])
+			}
+		}
+	}
+	return strings.Join(append(rewritten, args...), " ")
+}
+
+// isInstruction returns true if the line of the Dockerfile is the instruction
+func isInstruction(line, instruction string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && strings.EqualFold(fields[0], instruction)
+}
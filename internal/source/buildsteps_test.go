@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestRewriteDockerfileStages(t *testing.T) {
+	dockerfile := `ARG NODE_VERSION=14
+FROM node:${NODE_VERSION} AS build
+COPY package.json ./
+RUN npm install && npm run build
+FROM nginx
+COPY --from=build /app/dist /usr/share/nginx/html
+`
+	globalArgs, lines := rewriteDockerfileStages(dockerfile, "frontend", "frontend", true)
+	if want := []string{"ARG NODE_VERSION=14"}; !reflect.DeepEqual(globalArgs, want) {
+		t.Fatalf("Expected the global arguments to be returned separately. Expected: %v Actual: %v", want, globalArgs)
+	}
+	want := []string{
+		"FROM node:${NODE_VERSION} AS frontend-1",
+		"COPY frontend/package.json ./",
+		"RUN npm install && npm run build",
+		"FROM nginx AS frontend",
+		"COPY --from=frontend-1 /app/dist /usr/share/nginx/html",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("Failed to rewrite the stages. Expected:\n%s\nActual:\n%s", strings.Join(want, "\n"), strings.Join(lines, "\n"))
+	}
+}
+
+func TestComposeBuildSteps(t *testing.T) {
+	rootDir := t.TempDir()
+	newService := func(name string) plantypes.Service {
+		service := plantypes.NewService(name, plantypes.Any2KubeTranslation)
+		service.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+		service.ContainerizationTargetOptions = []string{"/assets/dockerfiles/" + name}
+		service.AddSourceArtifact(plantypes.SourceDirectoryArtifactType, filepath.Join(rootDir, name))
+		return service
+	}
+	newIR := func(p plantypes.Plan) irtypes.IR {
+		ir := irtypes.NewIR(p)
+		for _, name := range []string{"frontend", "backend"} {
+			container := irtypes.NewContainer(plantypes.DockerFileContainerBuildTypeValue, name+":latest", true)
+			container.AddFile(filepath.Join(name, "Dockerfile."+name), "FROM base\nCOPY . .\n")
+			container.AddFile(filepath.Join(name, name+"-docker-build.sh"), "docker build")
+			ir.AddContainer(container)
+			ir.Services[name] = irtypes.Service{Name: name, PodSpec: core.PodSpec{Containers: []core.Container{{Name: name, Image: name + ":latest"}}}}
+		}
+		return ir
+	}
+	newPlan := func(composition plantypes.BuildCompositionTypeValue) plantypes.Plan {
+		p := plantypes.NewPlan()
+		p.Spec.Inputs.RootDir = rootDir
+		backend := newService("backend")
+		backend.BuildSteps = plantypes.BuildSteps{Composition: composition, Steps: []plantypes.Service{newService("frontend")}, Artifacts: map[string][]string{"frontend": {"/dist:/app/public"}}}
+		p.Spec.Inputs.Services["backend"] = []plantypes.Service{backend}
+		return p
+	}
+
+	t.Run("steps built as stages of the Dockerfile of the service", func(t *testing.T) {
+		p := newPlan(plantypes.MultiStageBuildComposition)
+		ir := newIR(p)
+		composeBuildSteps(&ir, p)
+		if _, ok := ir.Services["frontend"]; ok || len(ir.Containers) != 1 {
+			t.Fatalf("Expected only the image of the service to be built. Actual services: %d containers: %d", len(ir.Services), len(ir.Containers))
+		}
+		dockerfile, ok := ir.Containers[0].NewFiles["Dockerfile.backend"]
+		if !ok {
+			t.Fatalf("Expected the multi-stage Dockerfile in the common build context. Actual files: %v", ir.Containers[0].NewFiles)
+		}
+		for _, line := range []string{"FROM base AS frontend", "COPY frontend .", "FROM base AS backend-1", "COPY backend .", "COPY --from=frontend /dist /app/public"} {
+			if !strings.Contains(dockerfile, line+"\n") {
+				t.Errorf("Expected the line %q in the Dockerfile. Actual:\n%s", line, dockerfile)
+			}
+		}
+		if _, ok := ir.Containers[0].NewFiles[filepath.Join("backend", "Dockerfile.backend")]; ok {
+			t.Errorf("Expected the Dockerfile of the service to be replaced")
+		}
+	})
+
+	t.Run("steps built as separate images", func(t *testing.T) {
+		p := newPlan(plantypes.MultipleImagesBuildComposition)
+		ir := newIR(p)
+		composeBuildSteps(&ir, p)
+		if _, ok := ir.Services["frontend"]; ok || len(ir.Containers) != 2 {
+			t.Fatalf("Expected an image for every step. Actual services: %d containers: %d", len(ir.Services), len(ir.Containers))
+		}
+		if containers := ir.Services["backend"].Containers; len(containers) != 2 || containers[1].Name != "frontend" {
+			t.Fatalf("Expected the step to run as another container in the pods of the service. Actual: %+v", containers)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// cfCPUMetric is the cpu usage of an app instance as a percentage of its cpu entitlement
+	cfCPUMetric = "cpu"
+	// cfMemoryUtilMetric is the memory usage of an app instance as a percentage of its memory quota
+	cfMemoryUtilMetric = "memoryutil"
+	// cfMemoryUsedMetric is the memory usage of an app instance in MB
+	cfMemoryUsedMetric = "memoryused"
+)
+
+// getAutoscaling converts the App Autoscaler policy of a cf app. The scale out rules on cpu and memory become resource metrics.
+// The scale in rules are not needed, since the pods are removed when the usage is below the target.
+// The rules on other metrics, like throughput and responsetime, and the schedules are returned as unsupported rules.
+func getAutoscaling(policy collecttypes.CfAutoscaling) *irtypes.Autoscaling {
+	autoscaling := &irtypes.Autoscaling{MinReplicas: policy.InstanceMinCount, MaxReplicas: policy.InstanceMaxCount}
+	if autoscaling.MinReplicas < 1 {
+		autoscaling.MinReplicas = 1
+	}
+	if autoscaling.MaxReplicas < autoscaling.MinReplicas {
+		autoscaling.MaxReplicas = autoscaling.MinReplicas
+	}
+	for _, rule := range policy.ScalingRules {
+		if rule.Operator != ">" && rule.Operator != ">=" {
+			log.Debugf("Ignoring the scale in rule %s %s %d since the pods are removed when the usage is below the target", rule.MetricType, rule.Operator, rule.Threshold)
+			continue
+		}
+		metric := irtypes.AutoscalingMetric{}
+		switch rule.MetricType {
+		case cfCPUMetric:
+			metric = irtypes.AutoscalingMetric{Resource: core.ResourceCPU, AverageUtilization: int32(rule.Threshold)}
+		case cfMemoryUtilMetric:
+			metric = irtypes.AutoscalingMetric{Resource: core.ResourceMemory, AverageUtilization: int32(rule.Threshold)}
+		case cfMemoryUsedMetric:
+			metric = irtypes.AutoscalingMetric{Resource: core.ResourceMemory, AverageValue: resource.NewQuantity(rule.Threshold*1024*1024, resource.BinarySI)}
+		default:
+			autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, fmt.Sprintf("%s %s %d", rule.MetricType, rule.Operator, rule.Threshold))
+			continue
+		}
+		addAutoscalingMetric(autoscaling, metric)
+	}
+	if policy.Scheduled {
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, "schedules")
+	}
+	return autoscaling
+}
+
+// addAutoscalingMetric adds the metric. If there is already a metric of the same type for the resource, the lower target is kept, since the app scaled out when either rule was breached.
+func addAutoscalingMetric(autoscaling *irtypes.Autoscaling, metric irtypes.AutoscalingMetric) {
+	for i, existing := range autoscaling.Metrics {
+		if existing.Resource != metric.Resource || (existing.AverageValue == nil) != (metric.AverageValue == nil) {
+			continue
+		}
+		if metric.AverageValue != nil {
+			if metric.AverageValue.Cmp(*existing.AverageValue) < 0 {
+				autoscaling.Metrics[i] = metric
+			}
+		} else if metric.AverageUtilization < existing.AverageUtilization {
+			autoscaling.Metrics[i] = metric
+		}
+		return
+	}
+	autoscaling.Metrics = append(autoscaling.Metrics, metric)
+}
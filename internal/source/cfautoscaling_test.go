@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"testing"
+
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetAutoscaling(t *testing.T) {
+	policy := collecttypes.CfAutoscaling{
+		InstanceMinCount: 2,
+		InstanceMaxCount: 10,
+		ScalingRules: []collecttypes.CfScalingRule{
+			{MetricType: "cpu", Threshold: 80, Operator: ">=", Adjustment: "+1"},
+			{MetricType: "cpu", Threshold: 60, Operator: ">", Adjustment: "+2"},
+			{MetricType: "cpu", Threshold: 20, Operator: "<", Adjustment: "-1"},
+			{MetricType: "memoryused", Threshold: 512, Operator: ">", Adjustment: "+1"},
+			{MetricType: "throughput", Threshold: 100, Operator: ">", Adjustment: "+1"},
+		},
+		Scheduled: true,
+	}
+	autoscaling := getAutoscaling(policy)
+	if autoscaling.MinReplicas != 2 || autoscaling.MaxReplicas != 10 {
+		t.Fatalf("Expected the replicas to be between 2 and 10. Actual: %d and %d", autoscaling.MinReplicas, autoscaling.MaxReplicas)
+	}
+	if len(autoscaling.Metrics) != 2 {
+		t.Fatalf("Expected a cpu and a memory metric. Actual: %+v", autoscaling.Metrics)
+	}
+	if cpu := autoscaling.Metrics[0]; cpu.Resource != core.ResourceCPU || cpu.AverageUtilization != 60 {
+		t.Fatalf("Expected the lowest cpu threshold to be the target. Actual: %+v", cpu)
+	}
+	if memory := autoscaling.Metrics[1]; memory.Resource != core.ResourceMemory || memory.AverageValue == nil || memory.AverageValue.Cmp(resource.MustParse("512Mi")) != 0 {
+		t.Fatalf("Expected the memory target to be 512Mi. Actual: %+v", memory)
+	}
+	if len(autoscaling.UnsupportedRules) != 2 || autoscaling.UnsupportedRules[0] != "throughput > 100" || autoscaling.UnsupportedRules[1] != "schedules" {
+		t.Fatalf("Expected the throughput rule and the schedules to be unsupported. Actual: %v", autoscaling.UnsupportedRules)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"code.cloudfoundry.org/cli/util/manifest"
@@ -39,6 +40,9 @@ import (
 
 //go:generate go run  ../../scripts/generator/generator.go data
 
+// cfShutdownGracePeriodSeconds is the time given by Cloud Foundry to the app instances to shut down after SIGTERM, before they are killed
+const cfShutdownGracePeriodSeconds = 10
+
 // CfManifestTranslator implements Translator interface for CfManifest files
 type CfManifestTranslator struct {
 }
@@ -58,13 +62,19 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 		return services, err
 	}
 
+	metadataPaths, err := common.GetFilesByExt(inputPath, common.Move2KubeFileExts)
+	if err != nil {
+		log.Warnf("Unable to fetch the collected metadata files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+
 	// Load buildpack mappings, if available
 	cfContainerizers := collecttypes.CfContainerizers{}
 	err = yaml.Unmarshal([]byte(data.Cfbuildpacks_yaml), &cfContainerizers)
 	if err != nil {
 		log.Debugf("Not valid containerizer option data : %s", err)
 	}
-	for _, filePath := range filePaths {
+	for _, filePath := range metadataPaths {
 		containerizersMetadata := collecttypes.CfContainerizers{}
 		err := common.ReadMove2KubeYaml(filePath, &containerizersMetadata)
 		if err != nil {
@@ -77,7 +87,7 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 
 	// Load instance apps, if available
 	cfInstanceApps := map[string][]collecttypes.CfApplication{} //path
-	for _, filePath := range filePaths {
+	for _, filePath := range metadataPaths {
 		fileCfInstanceApps := collecttypes.CfInstanceApps{}
 		if err := common.ReadMove2KubeYaml(filePath, &fileCfInstanceApps); err != nil {
 			log.Debugf("Failed to read the yaml file at path %q Error: %q", filePath, err)
@@ -117,6 +127,8 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 			appinstancefilepath, appinstance := getCfInstanceApp(cfInstanceApps, applicationName)
 			if application.DockerImage != "" || appinstance.DockerImage != "" {
 				service := cfManifestTranslator.newService(applicationName)
+				service.Runtime = getCfManifestRuntime(application)
+				service.DependsOn = getCfManifestDependsOn(application)
 				service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
 				if application.DockerImage != "" {
 					service.Image = application.DockerImage
@@ -124,15 +136,27 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 					service.Image = appinstance.DockerImage
 				}
 				service.UpdateContainerBuildPipeline = false
+				service.AddSourceArtifact(plantypes.CfManifestArtifactType, filePath)
+				if appinstance.Name != "" {
+					service.AddSourceArtifact(plantypes.CfRunningManifestArtifactType, appinstancefilepath)
+				}
 				services = append(services, service)
 				appsCovered = append(appsCovered, applicationName)
 				continue
 			}
+			buildpacks := append([]string{}, application.Buildpacks...)
+			if application.Buildpack.IsSet {
+				buildpacks = append(buildpacks, application.Buildpack.Value)
+			}
+			buildpacks = append(buildpacks, appinstance.Buildpack, appinstance.DetectedBuildpack)
 			containerizationoptionsfound := false
-			for _, cop := range containerizer.GetContainerizationOptions(plan, fullbuilddirectory) {
+			for _, cop := range sortByBuildpacks(containerizer.GetContainerizationOptions(plan, fullbuilddirectory), buildpacks) {
 				service := cfManifestTranslator.newService(applicationName)
+				service.Runtime = getCfManifestRuntime(application)
+				service.DependsOn = getCfManifestDependsOn(application)
 				service.ContainerBuildType = cop.ContainerizationType
 				service.ContainerizationTargetOptions = cop.TargetOptions
+				service.Detection = cop.Detection
 				service.AddSourceArtifact(plantypes.CfManifestArtifactType, filePath)
 				if appinstance.Name != "" {
 					service.AddSourceArtifact(plantypes.CfRunningManifestArtifactType, appinstancefilepath)
@@ -165,6 +189,8 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 					continue
 				}
 				service := cfManifestTranslator.newService(applicationName)
+				service.Runtime = getCfManifestRuntime(application)
+				service.DependsOn = getCfManifestDependsOn(application)
 				service.ContainerBuildType = containerizer.ContainerBuildType
 				service.ContainerizationTargetOptions = containerizer.ContainerizationTargetOptions
 				service.AddSourceArtifact(plantypes.CfManifestArtifactType, filePath)
@@ -182,6 +208,8 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 			if !containerizationoptionsfound {
 				log.Warnf("No known containerization approach for %s even though it has a cf manifest %s; Defaulting to manual", fullbuilddirectory, filepath.Base(filePath))
 				service := cfManifestTranslator.newService(applicationName)
+				service.Runtime = getCfManifestRuntime(application)
+				service.DependsOn = getCfManifestDependsOn(application)
 				service.ContainerBuildType = plantypes.ManualContainerBuildTypeValue
 				service.AddSourceArtifact(plantypes.CfManifestArtifactType, filePath)
 				if !common.IsStringPresent(service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType], fullbuilddirectory) {
@@ -213,10 +241,11 @@ func (cfManifestTranslator *CfManifestTranslator) GetServiceOptions(inputPath st
 					} else {
 						containerizationoptionsfound := false
 						//TODO: Think whether we should include this for only runtime manifest file
-						for _, cop := range containerizer.GetContainerizationOptions(plan, fullbuilddirectory) {
+						for _, cop := range sortByBuildpacks(containerizer.GetContainerizationOptions(plan, fullbuilddirectory), []string{application.Buildpack, application.DetectedBuildpack}) {
 							service := cfManifestTranslator.newService(applicationName)
 							service.ContainerBuildType = cop.ContainerizationType
 							service.ContainerizationTargetOptions = cop.TargetOptions
+							service.Detection = cop.Detection
 							service.AddSourceArtifact(plantypes.CfRunningManifestArtifactType, appfilepath)
 							if !common.IsStringPresent(service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType], fullbuilddirectory) {
 								service.AddSourceArtifact(plantypes.SourceDirectoryArtifactType, fullbuilddirectory)
@@ -291,14 +320,23 @@ func (cfManifestTranslator *CfManifestTranslator) Translate(services []plantypes
 				continue
 			}
 			log.Debugf("Using cf manifest file at path %s to translate service %s", path, service.ServiceName)
-			container, err := containerizer.GetContainer(plan, service)
-			if err != nil {
-				log.Errorf("Failed to containerize service %s in cf manifest file at path %s Error: %q", service.ServiceName, path, err)
+			var container irtypes.Container
+			if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+				container, err = containerizer.GetContainer(plan, service)
+				return err
+			}); err != nil {
 				continue
 			}
+			if err := containerizer.CommitServiceArtifacts(service.ServiceName, container); err != nil {
+				log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+			}
 			ir.AddContainer(container)
 			application := applications[0]
+			if application.DockerImage != "" && application.DockerUsername != "" {
+				ir.RegistryUsernames[common.GetImageRegistry(application.DockerImage)] = application.DockerUsername
+			}
 			serviceConfig := irtypes.NewServiceFromPlanService(service)
+			serviceConfig.TerminationGracePeriodSeconds = getCfTerminationGracePeriodSeconds()
 			serviceContainer := core.Container{Name: service.ServiceName}
 			serviceContainer.Image = service.Image
 			for varname, value := range application.EnvironmentVariables {
@@ -352,16 +390,27 @@ func (cfManifestTranslator *CfManifestTranslator) Translate(services []plantypes
 				}
 			}
 			serviceConfig.Containers = []core.Container{serviceContainer}
+			addRoutes(&serviceConfig, append(getManifestRoutes(path, service.ServiceName), cfinstanceapp.Routes...), cfinstanceapp.RouteServices)
+			if cfinstanceapp.Autoscaling != nil {
+				serviceConfig.Autoscaling = getAutoscaling(*cfinstanceapp.Autoscaling)
+			}
 			ir.Services[service.ServiceName] = serviceConfig
+			addProcesses(&ir, path, service.ServiceName)
 		} else {
 			log.Debugf("No cf manifest file found for service %s", service.ServiceName)
-			container, err := containerizer.GetContainer(plan, service)
-			if err != nil {
-				log.Errorf("Failed to containerize service %s using cfmanifest translator. Error: %q", service.ServiceName, err)
+			var container irtypes.Container
+			if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+				container, err = containerizer.GetContainer(plan, service)
+				return err
+			}); err != nil {
 				continue
 			}
+			if err := containerizer.CommitServiceArtifacts(service.ServiceName, container); err != nil {
+				log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+			}
 			ir.AddContainer(container)
 			serviceConfig := irtypes.NewServiceFromPlanService(service)
+			serviceConfig.TerminationGracePeriodSeconds = getCfTerminationGracePeriodSeconds()
 			serviceContainer := core.Container{Name: service.ServiceName, Image: service.Image}
 			if cfinstanceapp.Instances != 0 {
 				serviceConfig.Replicas = cfinstanceapp.Instances
@@ -405,12 +454,112 @@ func (cfManifestTranslator *CfManifestTranslator) Translate(services []plantypes
 				}
 			}
 			serviceConfig.Containers = []core.Container{serviceContainer}
+			addRoutes(&serviceConfig, cfinstanceapp.Routes, cfinstanceapp.RouteServices)
+			if cfinstanceapp.Autoscaling != nil {
+				serviceConfig.Autoscaling = getAutoscaling(*cfinstanceapp.Autoscaling)
+			}
 			ir.Services[service.ServiceName] = serviceConfig
 		}
 	}
 	return ir, nil
 }
 
+// getCfTerminationGracePeriodSeconds returns the termination grace period which gives the pods the same time to shut down as on Cloud Foundry
+func getCfTerminationGracePeriodSeconds() *int64 {
+	terminationGracePeriodSeconds := int64(cfShutdownGracePeriodSeconds)
+	return &terminationGracePeriodSeconds
+}
+
+// getCfManifestRuntime returns the env vars of the app in the manifest. The env vars of the running app are not added, since they can contain credentials.
+func getCfManifestRuntime(application manifest.Application) plantypes.RuntimeSpec {
+	runtime := plantypes.RuntimeSpec{}
+	names := []string{}
+	for name := range application.EnvironmentVariables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		runtime.SetEnv(name, application.EnvironmentVariables[name])
+	}
+	return runtime
+}
+
+// getCfManifestDependsOn returns the services bound to the application. The bindings which are not translated to services of the plan are dropped while planning.
+func getCfManifestDependsOn(application manifest.Application) []string {
+	dependsOn := []string{}
+	for _, binding := range application.Services {
+		if binding = common.NormalizeForServiceName(binding); !common.IsStringPresent(dependsOn, binding) {
+			dependsOn = append(dependsOn, binding)
+		}
+	}
+	return dependsOn
+}
+
+// buildpackLanguages maps the languages of the cf buildpacks to the prefixes of the names of the Dockerfile and S2I containerizers
+var buildpackLanguages = map[string][]string{
+	"go":     {"golang"},
+	"java":   {"java"},
+	"nodejs": {"nodejs"},
+	"php":    {"php"},
+	"python": {"python", "django"},
+	"ruby":   {"ruby"},
+}
+
+// getBuildpackLanguage returns the language of a buildpack given by name, like nodejs_buildpack, or by url, like https://github.com/cloudfoundry/nodejs-buildpack.git#v1.7.0
+func getBuildpackLanguage(buildpack string) string {
+	name := strings.ToLower(strings.TrimSpace(buildpack))
+	if idx := strings.Index(name, "#"); idx != -1 {
+		name = name[:idx]
+	}
+	name = strings.TrimSuffix(filepath.Base(strings.TrimSuffix(name, "/")), ".git")
+	for _, suffix := range []string{"_buildpack", "-buildpack"} {
+		if idx := strings.Index(name, suffix); idx != -1 {
+			name = name[:idx]
+		}
+	}
+	if _, ok := buildpackLanguages[name]; !ok {
+		return ""
+	}
+	return name
+}
+
+// sortByBuildpacks moves the Dockerfile and S2I containerization options for the languages of the buildpacks to the front,
+// keeping only the targets for those languages, so that they are selected by default
+func sortByBuildpacks(cops []containerizer.ContainerizationOption, buildpacks []string) []containerizer.ContainerizationOption {
+	prefixes := []string{}
+	for _, buildpack := range buildpacks {
+		if language := getBuildpackLanguage(buildpack); language != "" {
+			prefixes = append(prefixes, buildpackLanguages[language]...)
+		}
+	}
+	if len(prefixes) == 0 {
+		return cops
+	}
+	matched := []containerizer.ContainerizationOption{}
+	others := []containerizer.ContainerizationOption{}
+	for _, cop := range cops {
+		if cop.ContainerizationType != plantypes.DockerFileContainerBuildTypeValue && cop.ContainerizationType != plantypes.S2IContainerBuildTypeValue {
+			others = append(others, cop)
+			continue
+		}
+		targetOptions := []string{}
+		for _, targetOption := range cop.TargetOptions {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(filepath.Base(targetOption), prefix) {
+					targetOptions = append(targetOptions, targetOption)
+					break
+				}
+			}
+		}
+		if len(targetOptions) == 0 {
+			others = append(others, cop)
+			continue
+		}
+		matched = append(matched, containerizer.ContainerizationOption{ContainerizationType: cop.ContainerizationType, TargetOptions: targetOptions})
+	}
+	return append(matched, others...)
+}
+
 func (cfManifestTranslator *CfManifestTranslator) newService(serviceName string) plantypes.Service {
 	service := plantypes.NewService(serviceName, cfManifestTranslator.GetTranslatorType())
 	service.AddSourceType(plantypes.DirectorySourceTypeValue)
@@ -422,25 +571,8 @@ func (cfManifestTranslator *CfManifestTranslator) newService(serviceName string)
 
 // ReadApplicationManifest reads an application manifest
 func ReadApplicationManifest(path string, serviceName string) ([]manifest.Application, []string, error) { // manifest, parameters
-	trimmedvariables, err := getMissingVariables(path)
+	rawManifest, trimmedvariables, err := interpolateManifest(path)
 	if err != nil {
-		log.Debugf("Unable to read as cf manifest %s : %s", path, err)
-		return nil, nil, err
-	}
-
-	rawManifest, err := ioutil.ReadFile(path)
-	if err != nil {
-		log.Errorf("Unable to read manifest file at path %q Error: %q", path, err)
-		return nil, nil, err
-	}
-	tpl := template.NewTemplate(rawManifest)
-	fileVars := template.StaticVariables{}
-	for _, variable := range trimmedvariables {
-		fileVars[variable] = "{{ index  .Values " + `"globalvariables" "` + variable + `"}}`
-	}
-	rawManifest, err = tpl.Evaluate(fileVars, nil, template.EvaluateOpts{ExpectAllKeys: true})
-	if err != nil {
-		log.Debugf("Interpolation Error %s", err)
 		return nil, nil, err
 	}
 
@@ -467,6 +599,32 @@ func ReadApplicationManifest(path string, serviceName string) ([]manifest.Applic
 	return applications, trimmedvariables, nil
 }
 
+// interpolateManifest replaces the variables in the manifest with references to the global variables in the helm values
+func interpolateManifest(path string) ([]byte, []string, error) {
+	trimmedvariables, err := getMissingVariables(path)
+	if err != nil {
+		log.Debugf("Unable to read as cf manifest %s : %s", path, err)
+		return nil, nil, err
+	}
+
+	rawManifest, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Unable to read manifest file at path %q Error: %q", path, err)
+		return nil, nil, err
+	}
+	tpl := template.NewTemplate(rawManifest)
+	fileVars := template.StaticVariables{}
+	for _, variable := range trimmedvariables {
+		fileVars[variable] = "{{ index  .Values " + `"globalvariables" "` + variable + `"}}`
+	}
+	rawManifest, err = tpl.Evaluate(fileVars, nil, template.EvaluateOpts{ExpectAllKeys: true})
+	if err != nil {
+		log.Debugf("Interpolation Error %s", err)
+		return nil, nil, err
+	}
+	return rawManifest, trimmedvariables, nil
+}
+
 func getMissingVariables(path string) ([]string, error) {
 	trimmedvariables := []string{}
 	_, err := manifest.ReadAndInterpolateManifest(path, []string{}, []template.VarKV{})
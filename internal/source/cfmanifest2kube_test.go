@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/containerizer"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetBuildpackLanguage(t *testing.T) {
+	testcases := map[string]string{
+		"nodejs_buildpack":       "nodejs",
+		"java_buildpack_offline": "java",
+		"https://github.com/cloudfoundry/python-buildpack.git#v1.7.0": "python",
+		"go_buildpack":     "go",
+		"binary_buildpack": "",
+		"":                 "",
+	}
+	for buildpack, want := range testcases {
+		if got := getBuildpackLanguage(buildpack); got != want {
+			t.Errorf("Expected the language of the buildpack %q to be %q . Actual: %q", buildpack, want, got)
+		}
+	}
+}
+
+func TestSortByBuildpacks(t *testing.T) {
+	cnb := containerizer.ContainerizationOption{ContainerizationType: plantypes.CNBContainerBuildTypeValue, TargetOptions: []string{"cloudfoundry/cnb:cflinuxfs3"}}
+	dockerfile := containerizer.ContainerizationOption{ContainerizationType: plantypes.DockerFileContainerBuildTypeValue, TargetOptions: []string{"/assets/dockerfiles/javamaven", "/assets/dockerfiles/nodejs"}}
+	cops := []containerizer.ContainerizationOption{cnb, dockerfile}
+
+	t.Run("buildpack with a matching containerizer", func(t *testing.T) {
+		want := []containerizer.ContainerizationOption{
+			{ContainerizationType: plantypes.DockerFileContainerBuildTypeValue, TargetOptions: []string{"/assets/dockerfiles/nodejs"}},
+			cnb,
+		}
+		if got := sortByBuildpacks(cops, []string{"", "nodejs_buildpack"}); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Expected the nodejs Dockerfile to be the first option. Expected: %+v Actual: %+v", want, got)
+		}
+	})
+
+	t.Run("unknown buildpack", func(t *testing.T) {
+		if got := sortByBuildpacks(cops, []string{"binary_buildpack"}); !reflect.DeepEqual(got, cops) {
+			t.Fatalf("Expected the options to be unchanged. Expected: %+v Actual: %+v", cops, got)
+		}
+	})
+}
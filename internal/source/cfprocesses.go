@@ -0,0 +1,181 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// webProcessType is the process that serves the routes of the app
+	webProcessType = "web"
+	// taskProcessType is a process that runs to completion
+	taskProcessType = "task"
+)
+
+// cfV3Manifest contains the fields of a CF v3 manifest that are not supported by the cf cli manifest package
+type cfV3Manifest struct {
+	Applications []cfV3Application `yaml:"applications"`
+}
+
+// cfV3Application is an app with multiple processes and sidecars
+type cfV3Application struct {
+	Name      string      `yaml:"name"`
+	Processes []cfProcess `yaml:"processes"`
+	Sidecars  []cfSidecar `yaml:"sidecars"`
+	Routes    []cfRoute   `yaml:"routes"`
+}
+
+// cfProcess is a process of an app. All the processes run the same droplet or image.
+type cfProcess struct {
+	Type      string `yaml:"type"`
+	Command   string `yaml:"command"`
+	Instances *int   `yaml:"instances"`
+	Memory    string `yaml:"memory"`
+}
+
+// cfSidecar is an additional process that runs in the same container as the processes of the given types
+type cfSidecar struct {
+	Name         string   `yaml:"name"`
+	ProcessTypes []string `yaml:"process_types"`
+	Command      string   `yaml:"command"`
+	Memory       string   `yaml:"memory"`
+}
+
+// readV3Application returns the processes, sidecars and routes of the app in the manifest
+func readV3Application(path string, appName string) (cfV3Application, error) {
+	rawManifest, _, err := interpolateManifest(path)
+	if err != nil {
+		return cfV3Application{}, err
+	}
+	m := cfV3Manifest{}
+	if err := yaml.Unmarshal(rawManifest, &m); err != nil {
+		log.Debugf("UnMarshalling error %s", err)
+		return cfV3Application{}, err
+	}
+	for _, application := range m.Applications {
+		if application.Name == appName || len(m.Applications) == 1 {
+			return application, nil
+		}
+	}
+	return cfV3Application{}, nil
+}
+
+// addProcesses maps the processes of the app to services. The web process is the service of the app, the task processes
+// become jobs and the other processes become deployments without ports. The sidecars are added as containers to the services
+// of the processes they belong to, since the pods of those services run the same image.
+func addProcesses(ir *irtypes.IR, path string, serviceName string) {
+	application, err := readV3Application(path, serviceName)
+	if err != nil {
+		log.Debugf("Unable to read the processes of the app %s in the manifest at path %s . Error: %q", serviceName, path, err)
+		return
+	}
+	if len(application.Processes) == 0 && len(application.Sidecars) == 0 {
+		return
+	}
+	webService, ok := ir.Services[serviceName]
+	if !ok || len(webService.Containers) == 0 {
+		return
+	}
+	processServices := map[string]string{webProcessType: serviceName} // [process type][service name]
+	for _, process := range application.Processes {
+		if process.Type == "" || process.Type == webProcessType {
+			setProcess(&webService, &webService.Containers[0], process)
+			continue
+		}
+		name := common.NormalizeForServiceName(serviceName + "-" + process.Type)
+		processService := irtypes.NewServiceWithName(name)
+		processService.ServiceRelPath = ""
+		processService.Annotations = common.MergeStringMaps(webService.Annotations, nil)
+		delete(processService.Annotations, routeServiceTODOKey) // Only the web process serves the routes
+		processService.Labels = common.MergeStringMaps(webService.Labels, nil)
+		processService.Networks = append([]string{}, webService.Networks...)
+		for _, volume := range webService.Volumes {
+			processService.Volumes = append(processService.Volumes, *volume.DeepCopy())
+		}
+		container := *webService.Containers[0].DeepCopy()
+		container.Name = name
+		container.Ports = nil
+		container.Resources = core.ResourceRequirements{}
+		setProcess(&processService, &container, process)
+		if process.Type == taskProcessType {
+			processService.RestartPolicy = core.RestartPolicyOnFailure
+		}
+		processService.Containers = []core.Container{container}
+		ir.Services[name] = processService
+		processServices[process.Type] = name
+		log.Debugf("Translated the %s process of the app %s to the service %s", process.Type, serviceName, name)
+	}
+	ir.Services[serviceName] = webService
+	for _, sidecar := range application.Sidecars {
+		processTypes := sidecar.ProcessTypes
+		if len(processTypes) == 0 {
+			processTypes = []string{webProcessType}
+		}
+		for _, processType := range processTypes {
+			name, ok := processServices[processType]
+			if !ok {
+				log.Warnf("Ignoring the sidecar %s for the process type %s of the app %s since there is no such process", sidecar.Name, processType, serviceName)
+				continue
+			}
+			processService := ir.Services[name]
+			container := *processService.Containers[0].DeepCopy()
+			container.Name = common.NormalizeForServiceName(sidecar.Name)
+			container.Command = []string{"/bin/sh", "-c", sidecar.Command}
+			container.Args = nil
+			container.Ports = nil
+			container.Resources = core.ResourceRequirements{}
+			setMemoryLimit(&container, sidecar.Memory)
+			processService.Containers = append(processService.Containers, container)
+			ir.Services[name] = processService
+		}
+	}
+}
+
+// setProcess applies the settings of the process to the service and its container
+func setProcess(service *irtypes.Service, container *core.Container, process cfProcess) {
+	if process.Instances != nil {
+		service.Replicas = *process.Instances
+	}
+	if process.Command != "" {
+		container.Command = []string{"/bin/sh", "-c", process.Command}
+		container.Args = nil
+	}
+	setMemoryLimit(container, process.Memory)
+}
+
+// setMemoryLimit sets the memory limit of the container to the memory in the cf format, like 512M or 1G
+func setMemoryLimit(container *core.Container, memory string) {
+	if memory == "" {
+		return
+	}
+	bytes, err := bytefmt.ToBytes(memory)
+	if err != nil {
+		log.Warnf("Ignoring the invalid memory %s of the container %s . Error: %q", memory, container.Name, err)
+		return
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = core.ResourceList{}
+	}
+	container.Resources.Limits[core.ResourceMemory] = *resource.NewQuantity(int64(bytes), resource.BinarySI)
+}
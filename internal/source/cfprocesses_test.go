@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestAddProcesses(t *testing.T) {
+	manifest := `applications:
+- name: myapp
+  processes:
+  - type: web
+    instances: 3
+    memory: 512M
+  - type: worker
+    command: bundle exec rake worker
+    instances: 2
+    memory: 1G
+  - type: task
+    command: bundle exec rake migrate
+  sidecars:
+  - name: config-server
+    process_types: [web, worker]
+    command: ./config-server
+    memory: 64M
+`
+	dir := writeSessionHintFiles(t, map[string]string{"manifest.yml": manifest})
+	ir := irtypes.IR{Services: map[string]irtypes.Service{}}
+	web := irtypes.NewServiceWithName("myapp")
+	web.Containers = []core.Container{{Name: "myapp", Image: "myapp:latest", Ports: []core.ContainerPort{{ContainerPort: 8080}}}}
+	ir.Services["myapp"] = web
+
+	addProcesses(&ir, filepath.Join(dir, "manifest.yml"), "myapp")
+
+	if len(ir.Services) != 3 {
+		t.Fatalf("Expected 3 services. Actual: %d", len(ir.Services))
+	}
+	web = ir.Services["myapp"]
+	if web.Replicas != 3 || len(web.Containers) != 2 {
+		t.Fatalf("Expected the web service to have 3 replicas and 2 containers. Actual: %d replicas and %d containers", web.Replicas, len(web.Containers))
+	}
+	if limit := web.Containers[0].Resources.Limits[core.ResourceMemory]; limit.Cmp(resource.MustParse("512Mi")) != 0 {
+		t.Fatalf("Expected the memory limit of the web process to be 512Mi. Actual: %s", limit.String())
+	}
+	worker, ok := ir.Services["myapp-worker"]
+	if !ok {
+		t.Fatalf("Expected a service for the worker process")
+	}
+	if worker.Replicas != 2 || len(worker.Containers) != 2 || len(worker.Containers[0].Ports) != 0 || !worker.IsLongRunning() {
+		t.Fatalf("Expected the worker to be a long running service with 2 replicas, 2 containers and no ports. Actual: %+v", worker)
+	}
+	if command := worker.Containers[0].Command; len(command) != 3 || command[2] != "bundle exec rake worker" {
+		t.Fatalf("Expected the command of the worker process to be run using a shell. Actual: %v", command)
+	}
+	task, ok := ir.Services["myapp-task"]
+	if !ok {
+		t.Fatalf("Expected a service for the task process")
+	}
+	if task.IsLongRunning() || len(task.Containers) != 1 {
+		t.Fatalf("Expected the task to be a job with a single container. Actual: %+v", task)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// wildcardHostPrefix is the prefix of the hosts of the wildcard routes, like *.example.com
+	wildcardHostPrefix = "*."
+	// routeServiceTODOKey flags the services whose requests were proxied through route services
+	routeServiceTODOKey = common.TODOAnnotation + "routeservice"
+)
+
+// cfRoute is a route of an app in the manifest, like myapp.example.com/path or *.example.com
+type cfRoute struct {
+	Route string `yaml:"route"`
+}
+
+// getManifestRoutes returns the routes of the app in the manifest
+func getManifestRoutes(path string, appName string) []string {
+	application, err := readV3Application(path, appName)
+	if err != nil {
+		log.Debugf("Unable to read the routes of the app %s in the manifest at path %s . Error: %q", appName, path, err)
+		return nil
+	}
+	routes := []string{}
+	for _, route := range application.Routes {
+		if route.Route != "" {
+			routes = append(routes, route.Route)
+		}
+	}
+	return routes
+}
+
+// addRoutes exposes the service on the wildcard hosts of its routes. Kubernetes has no equivalent of route services,
+// so the service is flagged with the ways to replace the route services bound to its routes.
+func addRoutes(service *irtypes.Service, routes []string, routeServices []string) {
+	for _, route := range routes {
+		host := getRouteHost(route)
+		if strings.HasPrefix(host, wildcardHostPrefix) && !common.IsStringPresent(service.WildcardHosts, host) {
+			service.WildcardHosts = append(service.WildcardHosts, host)
+		}
+	}
+	routeServiceURLs := []string{}
+	for _, routeService := range routeServices {
+		if !common.IsStringPresent(routeServiceURLs, routeService) {
+			routeServiceURLs = append(routeServiceURLs, routeService)
+		}
+	}
+	if len(routeServiceURLs) == 0 {
+		return
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[routeServiceTODOKey] = fmt.Sprintf("The requests to this service were proxied through the route services %s . "+
+		"Replace them with ingress annotations, like nginx.ingress.kubernetes.io/auth-url for authentication or nginx.ingress.kubernetes.io/limit-rps for rate limiting, "+
+		"or with an oauth2-proxy sidecar in front of the app.", strings.Join(routeServiceURLs, ", "))
+	log.Warnf("The service %s uses the route services %v which are not translated. Replace them with ingress annotations or an oauth2-proxy sidecar.", service.Name, routeServiceURLs)
+}
+
+// getRouteHost returns the host of a route, without the scheme, port and path
+func getRouteHost(route string) string {
+	if idx := strings.Index(route, "://"); idx >= 0 {
+		route = route[idx+len("://"):]
+	}
+	if idx := strings.IndexAny(route, ":/"); idx >= 0 {
+		route = route[:idx]
+	}
+	return strings.ToLower(route)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+)
+
+func TestAddRoutes(t *testing.T) {
+	manifest := `applications:
+- name: myapp
+  routes:
+  - route: myapp.example.com/api
+  - route: "*.example.com"
+`
+	dir := writeSessionHintFiles(t, map[string]string{"manifest.yml": manifest})
+	routes := getManifestRoutes(filepath.Join(dir, "manifest.yml"), "myapp")
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes in the manifest. Actual: %v", routes)
+	}
+
+	service := irtypes.NewServiceWithName("myapp")
+	addRoutes(&service, append(routes, "https://*.Example.com:443"), nil)
+	if len(service.WildcardHosts) != 1 || service.WildcardHosts[0] != "*.example.com" {
+		t.Fatalf("Expected the wildcard host *.example.com . Actual: %v", service.WildcardHosts)
+	}
+	if _, ok := service.Annotations[routeServiceTODOKey]; ok {
+		t.Fatalf("Expected no route service to be flagged. Actual: %+v", service.Annotations)
+	}
+
+	addRoutes(&service, nil, []string{"https://ratelimiter.example.com", "https://ratelimiter.example.com"})
+	if _, ok := service.Annotations[routeServiceTODOKey]; !ok {
+		t.Fatalf("Expected the route service to be flagged. Actual: %+v", service.Annotations)
+	}
+}
@@ -0,0 +1,522 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	"github.com/konveyor/move2kube/internal/k8sschema"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	core "k8s.io/kubernetes/pkg/apis/core"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const (
+	cfnECSTaskDefinitionType  = "AWS::ECS::TaskDefinition"
+	cfnECSServiceType         = "AWS::ECS::Service"
+	cfnLambdaFunctionType     = "AWS::Lambda::Function"
+	cfnServerlessFunctionType = "AWS::Serverless::Function"
+
+	// lambdaImagePackageType is the package type of the functions deployed as container images
+	lambdaImagePackageType = "Image"
+	// lambdaRuntimePort is the port of the runtime interface emulator of the Lambda base images
+	lambdaRuntimePort = 8080
+	// lambdaInvocationPath is the path of the invocations of the runtime interface emulator
+	lambdaInvocationPath = "/2015-03-31/functions/function/invocations"
+	// lambdaBaseImagePrefix precedes the runtime in the name of the Lambda base images
+	lambdaBaseImagePrefix = "public.ecr.aws/lambda/"
+	// lambdaMaxScaleAnnotation sets the maximum number of pods of a revision of the Knative service
+	lambdaMaxScaleAnnotation = "autoscaling.knative.dev/maxScale"
+
+	// cfnUnresolvedTODOKey lists the values of the template which are only known once the stack is deployed
+	cfnUnresolvedTODOKey = common.TODOAnnotation + "cloudformationunresolved"
+	// lambdaImageTODOKey explains how to build the image of a function deployed as a zip file
+	lambdaImageTODOKey = common.TODOAnnotation + "lambdaimage"
+	// lambdaInvocationTODOKey explains how the function is invoked in the Knative service
+	lambdaInvocationTODOKey = common.TODOAnnotation + "lambdainvocation"
+	// lambdaUnsupportedTODOKey lists the settings of the function which have no equivalent in the Knative service
+	lambdaUnsupportedTODOKey = common.TODOAnnotation + "lambdaunsupported"
+)
+
+var (
+	// cfnTranslatedResourceTypes are the types of the resources which are translated, or which need no equivalent
+	cfnTranslatedResourceTypes = []string{
+		cfnECSTaskDefinitionType, cfnECSServiceType, cfnLambdaFunctionType, cfnServerlessFunctionType,
+		"AWS::ECS::Cluster", "AWS::Lambda::Permission", "AWS::Lambda::Version", "AWS::Lambda::Alias",
+		"AWS::Serverless::Api", "AWS::Serverless::HttpApi", "AWS::Logs::LogGroup",
+	}
+	// cfnResourceReplacements suggest a replacement for the resources of the services of AWS which are not translated
+	cfnResourceReplacements = map[string]string{
+		"AWS::RDS::":                      "a database deployed using an operator, or the database kept on RDS",
+		"AWS::DynamoDB::":                 "the table kept on DynamoDB",
+		"AWS::S3::":                       "an object store, like MinIO, or the bucket kept on S3",
+		"AWS::SQS::":                      "a message broker, like RabbitMQ, or the queue kept on SQS, along with a KEDA ScaledObject scaling its consumers",
+		"AWS::SNS::":                      "a message broker, like RabbitMQ, or Knative Eventing",
+		"AWS::ElastiCache::":              "Redis deployed in the cluster",
+		"AWS::ElasticLoadBalancing::":     "the ingress of the cluster",
+		"AWS::ElasticLoadBalancingV2::":   "the ingress of the cluster",
+		"AWS::ApiGateway::":               "the ingress of the cluster",
+		"AWS::ApiGatewayV2::":             "the ingress of the cluster",
+		"AWS::IAM::":                      "the IAM roles for service accounts of EKS, or the RBAC of the cluster",
+		"AWS::EC2::":                      "the network of the cluster and network policies",
+		"AWS::Events::":                   "a Knative PingSource or a cron job",
+		"AWS::SecretsManager::":           "a secret synced using the External Secrets Operator",
+		"AWS::SSM::":                      "a config map, or a secret synced using the External Secrets Operator",
+		"AWS::ServiceDiscovery::":         "the DNS names of the Kubernetes services",
+		"AWS::ApplicationAutoScaling::":   "a horizontal pod autoscaler",
+		"AWS::Serverless::SimpleTable":    "the table kept on DynamoDB",
+		"AWS::Serverless::StateMachine":   "a workflow engine, like Argo Workflows",
+		"AWS::StepFunctions::":            "a workflow engine, like Argo Workflows",
+		"AWS::CloudFront::":               "a CDN in front of the ingress of the cluster",
+		"AWS::Route53::":                  "the DNS records of the ingress, managed using ExternalDNS",
+		"AWS::CertificateManager::":       "the certificates of the ingress, managed using cert-manager",
+		"AWS::Cognito::":                  "an identity provider, like Keycloak, or the user pool kept on Cognito",
+		"AWS::Kinesis::":                  "a message broker, like Kafka",
+		"AWS::MSK::":                      "Kafka deployed using an operator, like Strimzi",
+		"AWS::AmazonMQ::":                 "a message broker, like RabbitMQ or ActiveMQ",
+		"AWS::Elasticsearch::":            "Elasticsearch or OpenSearch deployed using an operator",
+		"AWS::OpenSearchService::":        "OpenSearch deployed using an operator",
+		"AWS::EFS::":                      "a persistent volume claim of a ReadWriteMany storage class",
+		"AWS::CloudWatch::":               "the alerts of the monitoring of the cluster, like Prometheus",
+		"AWS::AutoScaling::":              "the node pools of the cluster",
+		"AWS::Serverless::LayerVersion":   "the files of the layer added to the images of the functions",
+		"AWS::Lambda::LayerVersion":       "the files of the layer added to the images of the functions",
+		"AWS::Lambda::EventSourceMapping": "a Knative event source, or a KEDA ScaledObject",
+	}
+)
+
+// CloudFormationTranslator implements Translator interface for the AWS CloudFormation templates, in yaml or JSON, and the
+// SAM templates. The ECS task definitions of the templates are translated like the task definitions of the ECS translator,
+// and the Lambda functions become Knative services.
+type CloudFormationTranslator struct {
+}
+
+// cfnWorkload is a resource of a template which is translated to a service: a task definition, along with the ECS service
+// running it, or a function
+type cfnWorkload struct {
+	Name           string
+	TaskDefinition *ecsTaskDefinition
+	ECSService     *ecsService
+	Function       *lambdaFunction
+	// Unresolved are the values of the resources which are only known once the stack is deployed
+	Unresolved []string
+}
+
+// lambdaFunction contains the properties of an AWS::Lambda::Function or an AWS::Serverless::Function which are used in the translation.
+// The memory is in MiB and the timeout in seconds.
+type lambdaFunction struct {
+	FunctionName string `yaml:"FunctionName"`
+	PackageType  string `yaml:"PackageType"`
+	Code         struct {
+		ImageURI string `yaml:"ImageUri"`
+		S3Bucket string `yaml:"S3Bucket"`
+		S3Key    string `yaml:"S3Key"`
+		ZipFile  string `yaml:"ZipFile"`
+	} `yaml:"Code"`
+	ImageURI    string      `yaml:"ImageUri"`
+	CodeURI     interface{} `yaml:"CodeUri"`
+	InlineCode  string      `yaml:"InlineCode"`
+	Runtime     string      `yaml:"Runtime"`
+	Handler     string      `yaml:"Handler"`
+	MemorySize  int64       `yaml:"MemorySize"`
+	Timeout     int64       `yaml:"Timeout"`
+	Environment struct {
+		Variables map[string]string `yaml:"Variables"`
+	} `yaml:"Environment"`
+	ReservedConcurrentExecutions *int64 `yaml:"ReservedConcurrentExecutions"`
+	ImageConfig                  struct {
+		EntryPoint       []string `yaml:"EntryPoint"`
+		Command          []string `yaml:"Command"`
+		WorkingDirectory string   `yaml:"WorkingDirectory"`
+	} `yaml:"ImageConfig"`
+	Role      string                 `yaml:"Role"`
+	Policies  interface{}            `yaml:"Policies"`
+	VpcConfig map[string]interface{} `yaml:"VpcConfig"`
+	Layers    []string               `yaml:"Layers"`
+	Events    map[string]struct {
+		Type string `yaml:"Type"`
+	} `yaml:"Events"`
+}
+
+// GetTranslatorType returns the translator type
+func (*CloudFormationTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.CloudFormation2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the task definitions and of the functions of the CloudFormation templates. The functions
+// deployed as zip files have no image, and are built manually.
+func (cfnTranslator *CloudFormationTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByExt(inputPath, []string{".yaml", ".yml", ".json", ".template"})
+	if err != nil {
+		log.Warnf("Unable to fetch the yaml and json files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	sort.Strings(filePaths)
+	serviceNames := []string{}
+	for _, filePath := range filePaths {
+		template, ok := readCFNTemplate(filePath)
+		if !ok {
+			continue
+		}
+		for _, workload := range getCFNWorkloads(template) {
+			if common.IsStringPresent(serviceNames, workload.Name) {
+				log.Warnf("Ignoring the resource %s in %s , since a service with the same name was already found", workload.Name, filePath)
+				continue
+			}
+			serviceNames = append(serviceNames, workload.Name)
+			service := cfnTranslator.newService(workload.Name)
+			if workload.Function != nil {
+				service.Image = workload.Function.getImage()
+				if service.Image == "" {
+					service.ContainerBuildType = plantypes.ManualContainerBuildTypeValue
+					service.Image = workload.Name + ":latest"
+				}
+			} else {
+				service.Image = getECSContainerDefinitions(*workload.TaskDefinition)[0].Image
+			}
+			service.AddSourceArtifact(plantypes.CloudFormationTemplateArtifactType, filePath)
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// Translate translates the CloudFormation templates to IR. The resources of the templates which are not translated, like the
+// databases, are reported.
+func (cfnTranslator *CloudFormationTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	templatePaths := []string{}
+	templates := map[string]cfnTemplate{}
+	for _, service := range services {
+		if service.TranslationType != cfnTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		paths := service.SourceArtifacts[plantypes.CloudFormationTemplateArtifactType]
+		if len(paths) == 0 {
+			log.Warnf("No CloudFormation template found for the service %s", service.ServiceName)
+			continue
+		}
+		template, ok := readCFNTemplate(paths[0])
+		if !ok {
+			log.Warnf("Unable to read the CloudFormation template at path %s", paths[0])
+			continue
+		}
+		var workload *cfnWorkload
+		for _, w := range getCFNWorkloads(template) {
+			if w.Name == service.ServiceName {
+				workload = &w
+				break
+			}
+		}
+		if workload == nil {
+			log.Warnf("No task definition or function found for the service %s in the CloudFormation template at path %s", service.ServiceName, paths[0])
+			continue
+		}
+		if _, ok := templates[paths[0]]; !ok {
+			templatePaths = append(templatePaths, paths[0])
+			templates[paths[0]] = template
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			if service.ContainerBuildType == plantypes.ManualContainerBuildTypeValue {
+				// The image of a function deployed as a zip file is built manually, following the TODO of its Knative service
+				container, err = new(containerizer.ManualContainerizer).GetContainer(plan, service)
+				return err
+			}
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		ir.AddContainer(container)
+		if workload.Function != nil {
+			ir.CachedObjects = append(ir.CachedObjects, translateLambdaFunction(service.ServiceName, service.Image, *workload.Function, workload.Unresolved))
+			continue
+		}
+		for _, containerDef := range getECSContainerDefinitions(*workload.TaskDefinition)[1:] {
+			ir.AddContainer(irtypes.NewContainer(plantypes.ReuseContainerBuildTypeValue, containerDef.Image, false))
+		}
+		irService := irtypes.NewServiceFromPlanService(service)
+		unsupported := translateECSTaskDefinition(&ir, &irService, service.Image, *workload.TaskDefinition)
+		if workload.ECSService != nil {
+			unsupported = append(unsupported, translateECSService(&irService, *workload.ECSService)...)
+		}
+		addECSUnsupportedTODO(&irService, unsupported)
+		irService.Annotations = common.MergeStringMaps(irService.Annotations, getCFNUnresolvedTODO(workload.Unresolved))
+		ir.Services[service.ServiceName] = irService
+	}
+	for _, path := range templatePaths {
+		reportCFNUntranslatedResources(path, templates[path])
+	}
+	return ir, nil
+}
+
+func (cfnTranslator *CloudFormationTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, cfnTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.CloudFormationSourceTypeValue)
+	service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+	service.UpdateContainerBuildPipeline = false
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// readCFNTemplate reads the template at the path, and returns false if it is not a CloudFormation template
+func readCFNTemplate(path string) (cfnTemplate, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the file at path %s . Error: %q", path, err)
+		return cfnTemplate{}, false
+	}
+	return parseCFNTemplate(data)
+}
+
+// getCFNWorkloads returns the task definitions and the functions of the template, sorted by their logical IDs. A task definition
+// is named after the ECS service running it, or after its family, and a function after its name, or after their logical IDs.
+func getCFNWorkloads(template cfnTemplate) []cfnWorkload {
+	workloads := []cfnWorkload{}
+	ecsServices := map[string]cfnWorkload{}
+	for _, logicalID := range template.getLogicalIDs(cfnECSServiceType) {
+		unresolved := []string{}
+		ecsSvc := ecsService{}
+		if err := decodeCFNProperties(template.resolve(template.Resources[logicalID].Properties, &unresolved), &ecsSvc, true); err != nil {
+			log.Debugf("Unable to decode the properties of the ECS service %s . Error: %q", logicalID, err)
+			continue
+		}
+		if _, ok := ecsServices[ecsSvc.TaskDefinition]; ok {
+			log.Warnf("Ignoring the ECS service %s , since the task definition %s is already run by another service", logicalID, ecsSvc.TaskDefinition)
+			continue
+		}
+		ecsServices[ecsSvc.TaskDefinition] = cfnWorkload{ECSService: &ecsSvc, Unresolved: unresolved}
+	}
+	for _, logicalID := range template.getLogicalIDs(cfnECSTaskDefinitionType) {
+		unresolved := []string{}
+		taskDef := ecsTaskDefinition{}
+		if err := decodeCFNProperties(template.resolve(template.Resources[logicalID].Properties, &unresolved), &taskDef, true); err != nil {
+			log.Debugf("Unable to decode the properties of the ECS task definition %s . Error: %q", logicalID, err)
+			continue
+		}
+		if len(taskDef.ContainerDefinitions) == 0 {
+			continue
+		}
+		if taskDef.Family == "" {
+			taskDef.Family = logicalID
+		}
+		workload := cfnWorkload{Name: common.NormalizeForServiceName(taskDef.Family), TaskDefinition: &taskDef, Unresolved: unresolved}
+		if ecsSvc, ok := ecsServices[logicalID]; ok {
+			workload.ECSService = ecsSvc.ECSService
+			workload.Unresolved = append(workload.Unresolved, ecsSvc.Unresolved...)
+			if ecsSvc.ECSService.ServiceName != "" {
+				workload.Name = common.NormalizeForServiceName(ecsSvc.ECSService.ServiceName)
+			}
+		}
+		workloads = append(workloads, workload)
+	}
+	for _, logicalID := range template.getLogicalIDs(cfnLambdaFunctionType, cfnServerlessFunctionType) {
+		resource := template.Resources[logicalID]
+		properties := map[string]interface{}{}
+		if resource.Type == cfnServerlessFunctionType {
+			for key, value := range template.Globals.Function {
+				properties[key] = value
+			}
+		}
+		for key, value := range resource.Properties {
+			properties[key] = value
+		}
+		unresolved := []string{}
+		function := lambdaFunction{}
+		if err := decodeCFNProperties(template.resolve(properties, &unresolved), &function, false); err != nil {
+			log.Debugf("Unable to decode the properties of the function %s . Error: %q", logicalID, err)
+			continue
+		}
+		name := function.FunctionName
+		if name == "" {
+			name = logicalID
+		}
+		workloads = append(workloads, cfnWorkload{Name: common.NormalizeForServiceName(name), Function: &function, Unresolved: unresolved})
+	}
+	return workloads
+}
+
+// getImage returns the image of the function, or an empty string if the function is deployed as a zip file
+func (function lambdaFunction) getImage() string {
+	if function.ImageURI != "" {
+		return function.ImageURI
+	}
+	return function.Code.ImageURI
+}
+
+// getCode returns the location of the code of a function deployed as a zip file
+func (function lambdaFunction) getCode() string {
+	switch {
+	case function.Code.S3Bucket != "":
+		return fmt.Sprintf("s3://%s/%s", function.Code.S3Bucket, function.Code.S3Key)
+	case function.Code.ZipFile != "" || function.InlineCode != "":
+		return "the inline code of the template"
+	}
+	if codeURI, ok := function.CodeURI.(map[string]interface{}); ok {
+		return fmt.Sprintf("s3://%v/%v", codeURI["Bucket"], codeURI["Key"])
+	}
+	if codeURI := cast.ToString(function.CodeURI); codeURI != "" {
+		return codeURI
+	}
+	return "its source directory"
+}
+
+// translateLambdaFunction returns the Knative service running the function. Each pod handles one request at a time, like
+// an instance of a function, and the reserved concurrency bounds the number of pods.
+func translateLambdaFunction(name, image string, function lambdaFunction, unresolved []string) *knativev1.Service {
+	container := core.Container{
+		Name:       name,
+		Image:      image,
+		Command:    function.ImageConfig.EntryPoint,
+		Args:       function.ImageConfig.Command,
+		WorkingDir: function.ImageConfig.WorkingDirectory,
+		Ports:      []core.ContainerPort{{ContainerPort: lambdaRuntimePort, Protocol: core.ProtocolTCP}},
+	}
+	envNames := []string{}
+	for envName := range function.Environment.Variables {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		container.Env = append(container.Env, core.EnvVar{Name: envName, Value: function.Environment.Variables[envName]})
+	}
+	if function.MemorySize > 0 {
+		container.Resources.Limits = core.ResourceList{core.ResourceMemory: *resource.NewQuantity(function.MemorySize*1024*1024, resource.BinarySI)}
+	}
+	podSpec := core.PodSpec{Containers: []core.Container{container}}
+	containerConcurrency := int64(1)
+	template := knativev1.RevisionTemplateSpec{
+		Spec: knativev1.RevisionSpec{
+			PodSpec:              k8sschema.ConvertToV1PodSpec(&podSpec),
+			ContainerConcurrency: &containerConcurrency,
+		},
+	}
+	if function.Timeout > 0 {
+		template.Spec.TimeoutSeconds = &function.Timeout
+	}
+	if function.ReservedConcurrentExecutions != nil && *function.ReservedConcurrentExecutions > 0 {
+		template.ObjectMeta.Annotations = map[string]string{lambdaMaxScaleAnnotation: cast.ToString(*function.ReservedConcurrentExecutions)}
+	}
+	annotations := map[string]string{
+		lambdaInvocationTODOKey: fmt.Sprintf("The function is served by the runtime interface of the Lambda base images, which is invoked by the POST requests to %s on port %d with the event as their body. Add the AWS Lambda Web Adapter to the image to serve the HTTP requests directly.", lambdaInvocationPath, lambdaRuntimePort),
+	}
+	if function.getImage() == "" {
+		annotations[lambdaImageTODOKey] = fmt.Sprintf("Build the image %s of the function from %s , using the base image %s%s with the handler %s as its command, and push it to the registry.", image, function.getCode(), lambdaBaseImagePrefix, function.Runtime, function.Handler)
+	}
+	if unsupported := getLambdaUnsupported(function); len(unsupported) > 0 {
+		annotations[lambdaUnsupportedTODOKey] = fmt.Sprintf("The settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", "))
+	}
+	return &knativev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       common.ServiceKind,
+			APIVersion: knativev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: common.MergeStringMaps(annotations, getCFNUnresolvedTODO(unresolved)),
+		},
+		Spec: knativev1.ServiceSpec{
+			ConfigurationSpec: knativev1.ConfigurationSpec{
+				Template: template,
+			},
+		},
+	}
+}
+
+// getLambdaUnsupported returns the settings of the function which have no equivalent. The Api and HttpApi events are served by
+// the route of the Knative service, and the other events, like Schedule or SQS, need an event source.
+func getLambdaUnsupported(function lambdaFunction) []string {
+	unsupported := []string{}
+	for name, event := range function.Events {
+		if event.Type != "Api" && event.Type != "HttpApi" {
+			unsupported = append(unsupported, fmt.Sprintf("the %s event %s", event.Type, name))
+		}
+	}
+	for _, layer := range function.Layers {
+		unsupported = append(unsupported, "the layer "+layer)
+	}
+	if function.Role != "" {
+		unsupported = append(unsupported, "the execution role "+function.Role)
+	}
+	if function.Policies != nil {
+		unsupported = append(unsupported, "the policies of the execution role")
+	}
+	if len(function.VpcConfig) > 0 {
+		unsupported = append(unsupported, "the VPC configuration")
+	}
+	sort.Strings(unsupported)
+	return unsupported
+}
+
+// getCFNUnresolvedTODO returns the TODO listing the values which are only known once the stack is deployed
+func getCFNUnresolvedTODO(unresolved []string) map[string]string {
+	if len(unresolved) == 0 {
+		return nil
+	}
+	unresolved = common.UniqueStrings(unresolved)
+	sort.Strings(unresolved)
+	return map[string]string{
+		cfnUnresolvedTODOKey: fmt.Sprintf("The values of %s are only known once the CloudFormation stack is deployed, and were left empty. Set them in the translated resources.", strings.Join(unresolved, ", ")),
+	}
+}
+
+// reportCFNUntranslatedResources reports the resources of the template which have no equivalent in the translated resources,
+// along with a replacement for the resources of the common services of AWS
+func reportCFNUntranslatedResources(path string, template cfnTemplate) {
+	logicalIDs := []string{}
+	for logicalID, resource := range template.Resources {
+		if !common.IsStringPresent(cfnTranslatedResourceTypes, resource.Type) {
+			logicalIDs = append(logicalIDs, logicalID)
+		}
+	}
+	sort.Strings(logicalIDs)
+	for _, logicalID := range logicalIDs {
+		resourceType := template.Resources[logicalID].Type
+		message := fmt.Sprintf("The %s resource %s of the CloudFormation template %s was not translated.", resourceType, logicalID, path)
+		if replacement := getCFNResourceReplacement(resourceType); replacement != "" {
+			message += " Replace it by " + replacement + "."
+		}
+		codedErr := common.NewError(common.UnconvertibleResourceErrorCode, nil, "%s", message)
+		codedErr.Warning = true
+		common.ReportError(codedErr)
+	}
+}
+
+// getCFNResourceReplacement returns the replacement of the resource type, matched by its full type or by the prefix of its service
+func getCFNResourceReplacement(resourceType string) string {
+	if replacement, ok := cfnResourceReplacements[resourceType]; ok {
+		return replacement
+	}
+	parts := strings.Split(resourceType, "::")
+	if len(parts) < 2 {
+		return ""
+	}
+	return cfnResourceReplacements[parts[0]+"::"+parts[1]+"::"]
+}
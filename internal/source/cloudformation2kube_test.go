@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const testCFNTemplate = `AWSTemplateFormatVersion: 2010-09-09
+Transform: AWS::Serverless-2016-10-31
+Parameters:
+  Image:
+    Type: String
+    Default: 123456789012.dkr.ecr.us-east-1.amazonaws.com/tickets:v1
+Globals:
+  Function:
+    Timeout: 30
+    MemorySize: 256
+Resources:
+  Database:
+    Type: AWS::RDS::DBInstance
+    Properties:
+      Engine: postgres
+  Cluster:
+    Type: AWS::ECS::Cluster
+  TicketsTask:
+    Type: AWS::ECS::TaskDefinition
+    Properties:
+      Cpu: "512"
+      Memory: "1024"
+      ContainerDefinitions:
+        - Name: app
+          Image: !Ref Image
+          Cpu: "256"
+          PortMappings:
+            - ContainerPort: 3000
+          Environment:
+            - Name: DB_HOST
+              Value: !GetAtt Database.Endpoint.Address
+            - Name: TICKETS_URL
+              Value: !Sub "https://${AWS::Region}.example.com/${!path}"
+      Volumes:
+        - Name: uploads
+          EFSVolumeConfiguration:
+            FilesystemId: fs-12345678
+  TicketsService:
+    Type: AWS::ECS::Service
+    Properties:
+      ServiceName: tickets
+      Cluster: !Ref Cluster
+      TaskDefinition: !Ref TicketsTask
+      DesiredCount: 2
+      LoadBalancers:
+        - ContainerName: app
+          ContainerPort: 3000
+  Thumbnails:
+    Type: AWS::Serverless::Function
+    Properties:
+      CodeUri: thumbnails/
+      Runtime: python3.9
+      Handler: app.handler
+      ReservedConcurrentExecutions: 5
+      Environment:
+        Variables:
+          BUCKET: !Join ["-", [!Ref Image, thumbnails]]
+      Events:
+        Api:
+          Type: Api
+        Nightly:
+          Type: Schedule
+  Resizer:
+    Type: AWS::Lambda::Function
+    Properties:
+      FunctionName: resizer
+      PackageType: Image
+      Code:
+        ImageUri: 123456789012.dkr.ecr.us-east-1.amazonaws.com/resizer:v2
+`
+
+func TestCloudFormationTemplate(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"infra/template.yaml": testCFNTemplate,
+		"k8s/service.yaml":    "apiVersion: v1\nkind: Service\nmetadata:\n  name: tickets\n",
+	})
+	services, err := new(CloudFormationTranslator).GetServiceOptions(dir, plantypes.NewPlan())
+	if err != nil {
+		t.Fatalf("Failed to get the services. Error: %q", err)
+	}
+	if len(services) != 3 || services[0].ServiceName != "tickets" || services[1].ServiceName != "resizer" || services[2].ServiceName != "thumbnails" {
+		t.Fatalf("Expected the tickets, resizer and thumbnails services. Actual: %+v", services)
+	}
+	if services[0].Image != "123456789012.dkr.ecr.us-east-1.amazonaws.com/tickets:v1" || services[1].Image != "123456789012.dkr.ecr.us-east-1.amazonaws.com/resizer:v2" {
+		t.Fatalf("Expected the services to reuse the images of the task definition and of the function. Actual: %+v", services)
+	}
+	if services[2].ContainerBuildType != plantypes.ManualContainerBuildTypeValue || services[2].Image != "thumbnails:latest" {
+		t.Fatalf("Expected the function deployed as a zip file to be built manually. Actual: %+v", services[2])
+	}
+	if paths := services[0].SourceArtifacts[plantypes.CloudFormationTemplateArtifactType]; !reflect.DeepEqual(paths, []string{filepath.Join(dir, "infra", "template.yaml")}) {
+		t.Fatalf("Failed to add the template to the service. Actual: %v", paths)
+	}
+
+	containerizer.InitContainerizers(dir, []string{string(plantypes.ReuseContainerBuildTypeValue)})
+	ir, err := new(CloudFormationTranslator).Translate(services[:1], plantypes.NewPlan())
+	if err != nil {
+		t.Fatalf("Failed to translate the services. Error: %q", err)
+	}
+	service := ir.Services["tickets"]
+	container := service.Containers[0]
+	if container.Ports[0].ContainerPort != 3000 || len(container.Env) != 2 || container.Env[0].Value != "" || container.Env[1].Value != "https://${AWS::Region}.example.com/${path}" {
+		t.Fatalf("Failed to translate the container of the task definition. Actual: %+v", container)
+	}
+	if service.Replicas != 2 || !service.HasValidAnnotation(common.ExposeSelector) {
+		t.Fatalf("Failed to translate the ECS service. Actual: %+v", service)
+	}
+	if want := "The settings the EFS volume uploads of the file system fs-12345678 have no equivalent and were not translated."; service.Annotations[ecsUnsupportedTODOKey] != want {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", want, service.Annotations[ecsUnsupportedTODOKey])
+	}
+	wantUnresolved := "The values of !GetAtt Database.Endpoint.Address, ${AWS::Region} are only known once the CloudFormation stack is deployed, and were left empty. Set them in the translated resources."
+	if service.Annotations[cfnUnresolvedTODOKey] != wantUnresolved {
+		t.Fatalf("Failed to list the unresolved values. Expected: %s Actual: %s", wantUnresolved, service.Annotations[cfnUnresolvedTODOKey])
+	}
+	reported := common.GetReportedErrors()
+	if len(reported) == 0 || reported[len(reported)-1].Code != common.UnconvertibleResourceErrorCode {
+		t.Fatalf("Expected the database to be reported. Actual: %v", reported)
+	}
+}
+
+func TestTranslateLambdaFunction(t *testing.T) {
+	template, ok := parseCFNTemplate([]byte(testCFNTemplate))
+	if !ok {
+		t.Fatalf("Failed to parse the template")
+	}
+	workloads := getCFNWorkloads(template)
+	if len(workloads) != 3 || workloads[2].Function == nil {
+		t.Fatalf("Expected the task definition and the functions. Actual: %+v", workloads)
+	}
+	function := *workloads[2].Function
+	knativeService := translateLambdaFunction("thumbnails", "thumbnails:latest", function, workloads[2].Unresolved)
+	revision := knativeService.Spec.Template
+	container := revision.Spec.Containers[0]
+	if container.Ports[0].ContainerPort != lambdaRuntimePort || container.Env[0].Value != "123456789012.dkr.ecr.us-east-1.amazonaws.com/tickets:v1-thumbnails" {
+		t.Fatalf("Failed to translate the container of the function. Actual: %+v", container)
+	}
+	if memory := container.Resources.Limits.Memory(); memory.String() != "256Mi" || *revision.Spec.TimeoutSeconds != 30 || *revision.Spec.ContainerConcurrency != 1 {
+		t.Fatalf("Failed to apply the globals of the function. Actual: %+v", revision.Spec)
+	}
+	if revision.Annotations[lambdaMaxScaleAnnotation] != "5" {
+		t.Fatalf("Failed to bound the scale by the reserved concurrency. Actual: %v", revision.Annotations)
+	}
+	wantImageTODO := "Build the image thumbnails:latest of the function from thumbnails/ , using the base image public.ecr.aws/lambda/python3.9 with the handler app.handler as its command, and push it to the registry."
+	if knativeService.Annotations[lambdaImageTODOKey] != wantImageTODO {
+		t.Fatalf("Failed to explain how to build the image. Expected: %s Actual: %s", wantImageTODO, knativeService.Annotations[lambdaImageTODOKey])
+	}
+	if want := "The settings the Schedule event Nightly have no equivalent and were not translated."; knativeService.Annotations[lambdaUnsupportedTODOKey] != want {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", want, knativeService.Annotations[lambdaUnsupportedTODOKey])
+	}
+}
+
+func TestResolveCFNValue(t *testing.T) {
+	template, _ := parseCFNTemplate([]byte(testCFNTemplate))
+	unresolved := []string{}
+	value := template.resolve(map[string]interface{}{
+		"Fn::Sub": []interface{}{"${Name}-${Image}", map[string]interface{}{"Name": "tickets"}},
+	}, &unresolved)
+	if value != "tickets-123456789012.dkr.ecr.us-east-1.amazonaws.com/tickets:v1" || len(unresolved) != 0 {
+		t.Fatalf("Failed to resolve Fn::Sub. Actual: %v %v", value, unresolved)
+	}
+	if value := template.resolve(map[string]interface{}{"Fn::ImportValue": "shared-vpc"}, &unresolved); value != nil || !reflect.DeepEqual(unresolved, []string{"!ImportValue shared-vpc"}) {
+		t.Fatalf("Expected Fn::ImportValue to be unresolved. Actual: %v %v", value, unresolved)
+	}
+	if key := toCFNLowerCamelCase("EFSVolumeConfiguration"); key != "efsVolumeConfiguration" {
+		t.Fatalf("Failed to convert the key to the lower camel case. Actual: %s", key)
+	}
+}
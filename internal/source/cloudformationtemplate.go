@@ -0,0 +1,310 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// cfnServerlessTransform is the transform of the SAM templates
+	cfnServerlessTransform = "AWS::Serverless-2016-10-31"
+	// cfnNoValue is the pseudo parameter removing a property
+	cfnNoValue = "AWS::NoValue"
+)
+
+var (
+	// cfnSubVariableRegex matches the variables of Fn::Sub, like ${Env} or ${Database.Endpoint.Address}
+	cfnSubVariableRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+	// cfnKeyRenames are the keys of CloudFormation whose lower camel case differs from the key of the AWS API
+	cfnKeyRenames = map[string]string{"filesystemId": "fileSystemId"}
+)
+
+// cfnTemplate is a CloudFormation template, in yaml or JSON, or a SAM template. The values of the properties are strings,
+// numbers, bools, lists as []interface{} and objects as map[string]interface{}, with the short form of the intrinsic
+// functions, like !Ref Image, expanded to their full form, like {"Ref": "Image"}.
+type cfnTemplate struct {
+	AWSTemplateFormatVersion string      `yaml:"AWSTemplateFormatVersion"`
+	Transform                interface{} `yaml:"Transform"`
+	Parameters               map[string]struct {
+		Default interface{} `yaml:"Default"`
+	} `yaml:"Parameters"`
+	Globals struct {
+		Function map[string]interface{} `yaml:"Function"`
+	} `yaml:"Globals"`
+	Resources map[string]cfnResource `yaml:"Resources"`
+}
+
+// cfnResource is a resource of a template, like an AWS::ECS::TaskDefinition
+type cfnResource struct {
+	Type       string                 `yaml:"Type"`
+	Properties map[string]interface{} `yaml:"Properties"`
+}
+
+// parseCFNTemplate parses the template, and returns false if it is not a CloudFormation template. A template has
+// the AWSTemplateFormatVersion, the transform of SAM, or resources of AWS.
+func parseCFNTemplate(data []byte) (cfnTemplate, bool) {
+	template := cfnTemplate{}
+	node := yaml.Node{}
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return template, false
+	}
+	expandCFNShortForms(&node)
+	if err := node.Decode(&template); err != nil {
+		return template, false
+	}
+	if template.AWSTemplateFormatVersion != "" || template.isServerless() {
+		return template, true
+	}
+	for _, resource := range template.Resources {
+		if strings.HasPrefix(resource.Type, "AWS::") {
+			return template, true
+		}
+	}
+	return template, false
+}
+
+// expandCFNShortForms replaces the tags of the short form of the intrinsic functions, like !Ref Image or !GetAtt Database.Endpoint.Address,
+// by their full form, like {"Ref": "Image"} or {"Fn::GetAtt": ["Database", "Endpoint.Address"]}
+func expandCFNShortForms(node *yaml.Node) {
+	for _, child := range node.Content {
+		expandCFNShortForms(child)
+	}
+	if !strings.HasPrefix(node.Tag, "!") || strings.HasPrefix(node.Tag, "!!") {
+		return
+	}
+	function := "Fn::" + strings.TrimPrefix(node.Tag, "!")
+	if node.Tag == "!Ref" || node.Tag == "!Condition" {
+		function = strings.TrimPrefix(node.Tag, "!")
+	}
+	arg := *node
+	arg.Tag = ""
+	if node.Tag == "!GetAtt" && arg.Kind == yaml.ScalarNode {
+		parts := strings.SplitN(arg.Value, ".", 2)
+		arg = yaml.Node{Kind: yaml.SequenceNode}
+		for _, part := range parts {
+			arg.Content = append(arg.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part})
+		}
+	}
+	*node = yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: function}, &arg}}
+}
+
+// isServerless returns true if the template is a SAM template
+func (template cfnTemplate) isServerless() bool {
+	switch transform := template.Transform.(type) {
+	case string:
+		return transform == cfnServerlessTransform
+	case []interface{}:
+		for _, t := range transform {
+			if t == cfnServerlessTransform {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getLogicalIDs returns the logical IDs of the resources of the type, sorted
+func (template cfnTemplate) getLogicalIDs(resourceTypes ...string) []string {
+	logicalIDs := []string{}
+	for logicalID, resource := range template.Resources {
+		for _, resourceType := range resourceTypes {
+			if resource.Type == resourceType {
+				logicalIDs = append(logicalIDs, logicalID)
+			}
+		}
+	}
+	sort.Strings(logicalIDs)
+	return logicalIDs
+}
+
+// resolve replaces the references to the parameters, by their defaults, and to the resources, by their logical IDs, along with
+// the Fn::Sub and Fn::Join functions, by their values. The other functions, like Fn::GetAtt or Fn::ImportValue, and the pseudo
+// parameters, like AWS::Region, are only known once the stack is deployed. They are removed, or kept as is in the strings of
+// Fn::Sub, and appended to unresolved.
+func (template cfnTemplate) resolve(value interface{}, unresolved *[]string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if len(value) == 1 {
+			for function, arg := range value {
+				if function == "Ref" || function == "Condition" || strings.HasPrefix(function, "Fn::") {
+					return template.resolveFunction(function, arg, unresolved)
+				}
+			}
+		}
+		resolved := map[string]interface{}{}
+		for key, v := range value {
+			if v = template.resolve(v, unresolved); v != nil {
+				resolved[key] = v
+			}
+		}
+		return resolved
+	case []interface{}:
+		resolved := []interface{}{}
+		for _, v := range value {
+			if v = template.resolve(v, unresolved); v != nil {
+				resolved = append(resolved, v)
+			}
+		}
+		return resolved
+	}
+	return value
+}
+
+func (template cfnTemplate) resolveFunction(function string, arg interface{}, unresolved *[]string) interface{} {
+	switch function {
+	case "Ref":
+		name := fmt.Sprint(arg)
+		if name == cfnNoValue {
+			return nil
+		}
+		if value, ok := template.resolveName(name, nil, unresolved); ok {
+			return value
+		}
+	case "Fn::Sub":
+		format, vars := arg, map[string]interface{}{}
+		if args, ok := arg.([]interface{}); ok && len(args) == 2 {
+			format = args[0]
+			if m, ok := args[1].(map[string]interface{}); ok {
+				vars = m
+			}
+		}
+		if format, ok := format.(string); ok {
+			return cfnSubVariableRegex.ReplaceAllStringFunc(format, func(variable string) string {
+				name := strings.TrimSpace(variable[2 : len(variable)-1])
+				if strings.HasPrefix(name, "!") {
+					return "${" + name[1:] + "}"
+				}
+				if value, ok := template.resolveName(name, vars, unresolved); ok && value != nil {
+					return fmt.Sprint(value)
+				}
+				return variable
+			})
+		}
+	case "Fn::Join":
+		if args, ok := arg.([]interface{}); ok && len(args) == 2 {
+			if values, ok := template.resolve(args[1], unresolved).([]interface{}); ok {
+				parts := []string{}
+				for _, value := range values {
+					parts = append(parts, fmt.Sprint(value))
+				}
+				return strings.Join(parts, fmt.Sprint(args[0]))
+			}
+		}
+	}
+	*unresolved = append(*unresolved, getCFNFunctionString(function, arg))
+	return nil
+}
+
+// resolveName returns the value of the variable of Fn::Sub, or of the parameter, or the logical ID of the resource, with the name
+func (template cfnTemplate) resolveName(name string, vars map[string]interface{}, unresolved *[]string) (interface{}, bool) {
+	if value, ok := vars[name]; ok {
+		value = template.resolve(value, unresolved)
+		return value, value != nil
+	}
+	if parameter, ok := template.Parameters[name]; ok && parameter.Default != nil {
+		return parameter.Default, true
+	}
+	if _, ok := template.Resources[name]; ok {
+		return name, true
+	}
+	if vars != nil {
+		*unresolved = append(*unresolved, "${"+name+"}")
+	}
+	return nil, false
+}
+
+// getCFNFunctionString returns the short form of the function, like !GetAtt Database.Endpoint.Address
+func getCFNFunctionString(function string, arg interface{}) string {
+	if args, ok := arg.([]interface{}); ok && function == "Fn::GetAtt" {
+		parts := []string{}
+		for _, a := range args {
+			parts = append(parts, fmt.Sprint(a))
+		}
+		arg = strings.Join(parts, ".")
+	}
+	if data, err := yaml.Marshal(arg); err == nil {
+		if s := strings.TrimSpace(string(data)); !strings.Contains(s, "\n") {
+			arg = s
+		}
+	}
+	return fmt.Sprintf("!%s %v", strings.TrimPrefix(function, "Fn::"), arg)
+}
+
+// decodeCFNProperties decodes the resolved properties into out. With lowerCamel, the keys are converted to the lower camel case,
+// like the keys of the AWS API, so that the properties of a task definition are decoded like the JSON of the task definition.
+// The numbers in strings, like the Cpu of a task definition, are decoded as numbers, since the properties of CloudFormation
+// accept both.
+func decodeCFNProperties(properties interface{}, out interface{}, lowerCamel bool) error {
+	data, err := yaml.Marshal(normalizeCFNProperties(properties, lowerCamel))
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+func normalizeCFNProperties(value interface{}, lowerCamel bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		normalized := map[string]interface{}{}
+		for key, v := range value {
+			if lowerCamel {
+				key = toCFNLowerCamelCase(key)
+			}
+			normalized[key] = normalizeCFNProperties(v, lowerCamel)
+		}
+		return normalized
+	case []interface{}:
+		normalized := []interface{}{}
+		for _, v := range value {
+			normalized = append(normalized, normalizeCFNProperties(v, lowerCamel))
+		}
+		return normalized
+	case string:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(i, 10) == value {
+			return i
+		}
+	}
+	return value
+}
+
+// toCFNLowerCamelCase converts the key to the lower camel case, like ContainerDefinitions to containerDefinitions,
+// or EFSVolumeConfiguration to efsVolumeConfiguration
+func toCFNLowerCamelCase(key string) string {
+	runes := []rune(key)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	if renamed, ok := cfnKeyRenames[string(runes)]; ok {
+		return renamed
+	}
+	return string(runes)
+}
@@ -0,0 +1,338 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// cloudRunAPIVersionPrefix is the group of the Knative services, which describe the Cloud Run services
+	cloudRunAPIVersionPrefix = "serving.knative.dev/"
+	cloudRunServiceKind      = "Service"
+	// cloudRunAnnotationPrefix is the prefix of the annotations of the settings specific to Cloud Run
+	cloudRunAnnotationPrefix = "run.googleapis.com/"
+
+	cloudRunMinScaleAnnotation        = "autoscaling.knative.dev/minScale"
+	cloudRunMaxScaleAnnotation        = "autoscaling.knative.dev/maxScale"
+	cloudRunVPCConnectorAnnotation    = "run.googleapis.com/vpc-access-connector"
+	cloudRunVPCEgressAnnotation       = "run.googleapis.com/vpc-access-egress"
+	cloudRunCloudSQLAnnotation        = "run.googleapis.com/cloudsql-instances"
+	cloudRunSessionAffinityAnnotation = "run.googleapis.com/sessionAffinity"
+
+	// cloudRunDefaultConcurrency is the maximum number of concurrent requests of an instance when containerConcurrency is not set
+	cloudRunDefaultConcurrency = 80
+	// cloudRunDefaultMaxInstances is the maximum number of instances when maxScale is not set
+	cloudRunDefaultMaxInstances = 100
+	// cloudRunCPUTarget is the cpu utilization above which Cloud Run adds instances, in addition to the concurrency
+	cloudRunCPUTarget = 60
+	// cloudRunShutdownGracePeriodSeconds is the time given by Cloud Run to the instances to shut down after SIGTERM, before they are killed
+	cloudRunShutdownGracePeriodSeconds = 10
+)
+
+var (
+	// cloudRunIgnoredAnnotations are the annotations added by Cloud Run which do not configure the service
+	cloudRunIgnoredAnnotations = []string{
+		"run.googleapis.com/client-name",
+		"run.googleapis.com/client-version",
+		"run.googleapis.com/creator",
+		"run.googleapis.com/lastModifier",
+		"run.googleapis.com/operation-id",
+		"run.googleapis.com/urls",
+	}
+)
+
+// CloudRunTranslator implements Translator interface for Google Cloud Run services, described by the yaml exported by gcloud run services describe
+type CloudRunTranslator struct {
+}
+
+// cloudRunService contains the fields of a Cloud Run service which are used in the translation
+type cloudRunService struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name        string            `yaml:"name"`
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Template struct {
+			Metadata struct {
+				Annotations map[string]string `yaml:"annotations"`
+			} `yaml:"metadata"`
+			Spec struct {
+				ContainerConcurrency *int                `yaml:"containerConcurrency"`
+				TimeoutSeconds       int                 `yaml:"timeoutSeconds"`
+				ServiceAccountName   string              `yaml:"serviceAccountName"`
+				Containers           []cloudRunContainer `yaml:"containers"`
+				Volumes              []struct {
+					Name string `yaml:"name"`
+				} `yaml:"volumes"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// cloudRunContainer is a container of a Cloud Run service
+type cloudRunContainer struct {
+	Name    string   `yaml:"name"`
+	Image   string   `yaml:"image"`
+	Command []string `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Ports   []struct {
+		ContainerPort int32 `yaml:"containerPort"`
+	} `yaml:"ports"`
+	Env []struct {
+		Name      string `yaml:"name"`
+		Value     string `yaml:"value"`
+		ValueFrom *struct {
+			SecretKeyRef *struct {
+				Name string `yaml:"name"`
+				Key  string `yaml:"key"`
+			} `yaml:"secretKeyRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	Resources struct {
+		Limits map[string]string `yaml:"limits"`
+	} `yaml:"resources"`
+}
+
+// GetTranslatorType returns the translator type
+func (*CloudRunTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.CloudRun2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the Cloud Run service yamls
+func (cloudRunTranslator *CloudRunTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByExt(inputPath, []string{".yaml", ".yml"})
+	if err != nil {
+		log.Warnf("Unable to fetch the yaml files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	sort.Strings(filePaths)
+	for _, filePath := range filePaths {
+		crService, ok := readCloudRunService(filePath)
+		if !ok {
+			continue
+		}
+		serviceName := crService.Metadata.Name
+		if serviceName == "" {
+			serviceName = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		}
+		service := cloudRunTranslator.newService(common.NormalizeForServiceName(serviceName))
+		service.Image = crService.Spec.Template.Spec.Containers[0].Image
+		service.AddSourceArtifact(plantypes.CloudRunServiceArtifactType, filePath)
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// Translate translates the Cloud Run services to IR
+func (cloudRunTranslator *CloudRunTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != cloudRunTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		paths := service.SourceArtifacts[plantypes.CloudRunServiceArtifactType]
+		if len(paths) == 0 {
+			log.Warnf("No Cloud Run service yaml found for the service %s", service.ServiceName)
+			continue
+		}
+		crService, ok := readCloudRunService(paths[0])
+		if !ok {
+			log.Warnf("Unable to read the Cloud Run service yaml at path %s", paths[0])
+			continue
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		ir.AddContainer(container)
+		irService := irtypes.NewServiceFromPlanService(service)
+		translateCloudRunService(&ir, &irService, service.Image, crService)
+		ir.Services[service.ServiceName] = irService
+	}
+	return ir, nil
+}
+
+func (cloudRunTranslator *CloudRunTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, cloudRunTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.CloudRunSourceTypeValue)
+	service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+	service.UpdateContainerBuildPipeline = false
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// readCloudRunService reads the yaml at the path, and returns false if it does not describe a Cloud Run service
+func readCloudRunService(path string) (cloudRunService, bool) {
+	crService := cloudRunService{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the file at path %s . Error: %q", path, err)
+		return crService, false
+	}
+	if err := yaml.Unmarshal(data, &crService); err != nil {
+		log.Debugf("Unable to parse the file at path %s as a Cloud Run service. Error: %q", path, err)
+		return crService, false
+	}
+	if !strings.HasPrefix(crService.APIVersion, cloudRunAPIVersionPrefix) || crService.Kind != cloudRunServiceKind || len(crService.Spec.Template.Spec.Containers) == 0 {
+		return crService, false
+	}
+	return crService, true
+}
+
+// translateCloudRunService sets the containers, the scaling and the TODOs of the service from the Cloud Run service.
+// The secrets of Secret Manager become secrets without values.
+func translateCloudRunService(ir *irtypes.IR, service *irtypes.Service, image string, crService cloudRunService) {
+	spec := crService.Spec.Template.Spec
+	annotations := common.MergeStringMaps(crService.Metadata.Annotations, crService.Spec.Template.Metadata.Annotations)
+	unsupported := []string{}
+	terminationGracePeriodSeconds := int64(cloudRunShutdownGracePeriodSeconds)
+	service.TerminationGracePeriodSeconds = &terminationGracePeriodSeconds
+	secretKeys := map[string][]string{}
+	for i, crContainer := range spec.Containers {
+		container := core.Container{Name: common.NormalizeForServiceName(crContainer.Name), Image: crContainer.Image, Command: crContainer.Command, Args: crContainer.Args}
+		if i == 0 {
+			container.Name = service.Name
+			if image != "" {
+				container.Image = image
+			}
+		}
+		// The ingress container is the first one, or the one with a port if there are sidecars
+		if len(crContainer.Ports) > 0 || len(spec.Containers) == 1 {
+			port := int32(gcpDefaultPort)
+			if len(crContainer.Ports) > 0 && crContainer.Ports[0].ContainerPort != 0 {
+				port = crContainer.Ports[0].ContainerPort
+			}
+			container.Ports = []core.ContainerPort{{ContainerPort: port}}
+			service.AddPortForwarding(irtypes.Port{Number: port}, irtypes.Port{Number: port})
+			container.Env = append(container.Env, core.EnvVar{Name: "PORT", Value: cast.ToString(port)})
+		}
+		for _, env := range crContainer.Env {
+			if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+				container.Env = append(container.Env, core.EnvVar{Name: env.Name, Value: env.Value})
+				continue
+			}
+			secretName := common.MakeFileNameCompliant(env.ValueFrom.SecretKeyRef.Name)
+			secretKeys[secretName] = append(secretKeys[secretName], env.ValueFrom.SecretKeyRef.Key)
+			container.Env = append(container.Env, core.EnvVar{Name: env.Name, ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: secretName},
+				Key:                  env.ValueFrom.SecretKeyRef.Key,
+			}}})
+		}
+		for name, value := range crContainer.Resources.Limits {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				log.Warnf("Ignoring the invalid %s limit %s of the container %s . Error: %q", name, value, container.Name, err)
+				continue
+			}
+			if container.Resources.Limits == nil {
+				container.Resources.Limits = core.ResourceList{}
+			}
+			container.Resources.Limits[core.ResourceName(name)] = quantity
+		}
+		service.Containers = append(service.Containers, container)
+	}
+	secretNames := []string{}
+	for secretName, keys := range secretKeys {
+		secretNames = append(secretNames, secretName)
+		content := map[string][]byte{}
+		for _, key := range keys {
+			content[key] = []byte{}
+		}
+		ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: content})
+	}
+	if len(secretNames) > 0 {
+		sort.Strings(secretNames)
+		unsupported = append(unsupported, fmt.Sprintf("the values of the Secret Manager secrets %s", strings.Join(secretNames, ", ")))
+	}
+
+	service.Autoscaling, service.ContainerConcurrency = getCloudRunAutoscaling(annotations, spec.ContainerConcurrency)
+	service.Replicas = service.Autoscaling.MinReplicas
+	if annotations[cloudRunSessionAffinityAnnotation] == "true" {
+		service.StickySessions = true
+	}
+	if spec.TimeoutSeconds > 0 {
+		unsupported = append(unsupported, fmt.Sprintf("the request timeout of %d seconds", spec.TimeoutSeconds))
+	}
+	if spec.ServiceAccountName != "" {
+		unsupported = append(unsupported, fmt.Sprintf("the service account %s", spec.ServiceAccountName))
+	}
+	for _, volume := range spec.Volumes {
+		unsupported = append(unsupported, fmt.Sprintf("the volume %s", volume.Name))
+	}
+	for name, value := range annotations {
+		if !strings.HasPrefix(name, cloudRunAnnotationPrefix) || common.IsStringPresent(cloudRunIgnoredAnnotations, name) {
+			continue
+		}
+		switch name {
+		case cloudRunVPCConnectorAnnotation, cloudRunVPCEgressAnnotation, cloudRunCloudSQLAnnotation, cloudRunSessionAffinityAnnotation:
+			continue
+		}
+		unsupported = append(unsupported, name+"="+value)
+	}
+	addGCPTODOs(service, annotations[cloudRunVPCConnectorAnnotation], annotations[cloudRunVPCEgressAnnotation], splitCloudSQLInstances(annotations[cloudRunCloudSQLAnnotation]), unsupported)
+}
+
+// getCloudRunAutoscaling returns the autoscaling and the container concurrency of the service. Cloud Run scales on the concurrent
+// requests and on the cpu utilization. The concurrency is kept by Knative, but it is an unsupported rule of the autoscalers of the
+// deployments, as is the scaling to zero.
+func getCloudRunAutoscaling(annotations map[string]string, containerConcurrency *int) (*irtypes.Autoscaling, int) {
+	autoscaling := &irtypes.Autoscaling{
+		MinReplicas: cast.ToInt(annotations[cloudRunMinScaleAnnotation]),
+		MaxReplicas: cloudRunDefaultMaxInstances,
+		Metrics:     []irtypes.AutoscalingMetric{{Resource: core.ResourceCPU, AverageUtilization: cloudRunCPUTarget}},
+	}
+	if maxScale := cast.ToInt(annotations[cloudRunMaxScaleAnnotation]); maxScale > 0 {
+		autoscaling.MaxReplicas = maxScale
+	}
+	if autoscaling.MinReplicas < 1 {
+		autoscaling.MinReplicas = 1
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, "scale to zero")
+	}
+	if autoscaling.MaxReplicas < autoscaling.MinReplicas {
+		autoscaling.MaxReplicas = autoscaling.MinReplicas
+	}
+	concurrency := cloudRunDefaultConcurrency
+	if containerConcurrency != nil {
+		concurrency = *containerConcurrency
+	}
+	if concurrency > 0 {
+		autoscaling.UnsupportedRules = append(autoscaling.UnsupportedRules, fmt.Sprintf("concurrency %d", concurrency))
+	}
+	return autoscaling, concurrency
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const testCloudRunService = `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: tickets
+  annotations:
+    run.googleapis.com/client-name: gcloud
+    run.googleapis.com/ingress: internal
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/maxScale: "10"
+        run.googleapis.com/vpc-access-connector: tickets-connector
+        run.googleapis.com/vpc-access-egress: all-traffic
+        run.googleapis.com/cloudsql-instances: project:us-central1:tickets-db
+        run.googleapis.com/sessionAffinity: "true"
+    spec:
+      containerConcurrency: 40
+      timeoutSeconds: 600
+      containers:
+        - image: gcr.io/project/tickets:v1
+          ports:
+            - containerPort: 3000
+          env:
+            - name: NODE_ENV
+              value: production
+            - name: DB_PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: db-password
+                  key: latest
+          resources:
+            limits:
+              cpu: "2"
+              memory: 1Gi
+`
+
+func TestCloudRunService(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"tickets.yaml":   testCloudRunService,
+		"k8s/pod.yaml":   "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\n",
+		"knative/ks.yml": "apiVersion: serving.knative.dev/v1\nkind: Service\nmetadata:\n  name: empty\n",
+	})
+	services, err := new(CloudRunTranslator).GetServiceOptions(dir, plantypes.NewPlan())
+	if err != nil {
+		t.Fatalf("Failed to get the services. Error: %q", err)
+	}
+	if len(services) != 1 || services[0].ServiceName != "tickets" || services[0].Image != "gcr.io/project/tickets:v1" || services[0].ContainerBuildType != plantypes.ReuseContainerBuildTypeValue {
+		t.Fatalf("Expected the tickets service reusing its image. Actual: %+v", services)
+	}
+	if paths := services[0].SourceArtifacts[plantypes.CloudRunServiceArtifactType]; !reflect.DeepEqual(paths, []string{filepath.Join(dir, "tickets.yaml")}) {
+		t.Fatalf("Failed to add the service yaml to the service. Actual: %v", paths)
+	}
+
+	crService, _ := readCloudRunService(filepath.Join(dir, "tickets.yaml"))
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	service := irtypes.NewServiceWithName("tickets")
+	translateCloudRunService(&ir, &service, "gcr.io/project/tickets:v1", crService)
+	container := service.Containers[0]
+	if container.Name != "tickets" || len(container.Ports) != 1 || container.Ports[0].ContainerPort != 3000 || container.Env[0].Value != "3000" {
+		t.Fatalf("Failed to set the port of the container. Actual: %+v", container)
+	}
+	if container.Env[1].Value != "production" || container.Env[2].ValueFrom.SecretKeyRef.Name != "db-password" || container.Env[2].ValueFrom.SecretKeyRef.Key != "latest" {
+		t.Fatalf("Failed to set the env of the container. Actual: %+v", container.Env)
+	}
+	if len(ir.Storages) != 1 || ir.Storages[0].StorageType != irtypes.SecretKind || ir.Storages[0].Name != "db-password" {
+		t.Fatalf("Expected a secret for the Secret Manager secret. Actual: %+v", ir.Storages)
+	}
+	if cpu, memory := container.Resources.Limits[core.ResourceCPU], container.Resources.Limits[core.ResourceMemory]; cpu.String() != "2" || memory.String() != "1Gi" {
+		t.Fatalf("Failed to set the limits of the container. Actual: %+v", container.Resources.Limits)
+	}
+
+	wantAutoscaling := &irtypes.Autoscaling{
+		MinReplicas:      1,
+		MaxReplicas:      10,
+		Metrics:          []irtypes.AutoscalingMetric{{Resource: core.ResourceCPU, AverageUtilization: cloudRunCPUTarget}},
+		UnsupportedRules: []string{"scale to zero", "concurrency 40"},
+	}
+	if !reflect.DeepEqual(service.Autoscaling, wantAutoscaling) || service.ContainerConcurrency != 40 || service.Replicas != 1 {
+		t.Fatalf("Failed to set the scaling of the service. Expected: %+v Actual: %+v %d", wantAutoscaling, service.Autoscaling, service.ContainerConcurrency)
+	}
+	if !service.StickySessions || *service.TerminationGracePeriodSeconds != cloudRunShutdownGracePeriodSeconds {
+		t.Fatalf("Failed to set the session affinity and the grace period of the service. Actual: %+v", service)
+	}
+	if service.Annotations[gcpVPCTODOKey] == "" || service.Annotations[gcpCloudSQLTODOKey] == "" {
+		t.Fatalf("Expected TODOs for the VPC connector and the Cloud SQL instance. Actual: %v", service.Annotations)
+	}
+	wantUnsupported := "The settings run.googleapis.com/ingress=internal, the request timeout of 600 seconds, the values of the Secret Manager secrets db-password have no equivalent and were not translated."
+	if service.Annotations[gcpUnsupportedTODOKey] != wantUnsupported {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", wantUnsupported, service.Annotations[gcpUnsupportedTODOKey])
+	}
+}
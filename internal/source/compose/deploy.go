@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// restartTODOKey flags the services whose restart settings have no equivalent
+	restartTODOKey = common.TODOAnnotation + "restartpolicy"
+	// swarmStopFirstOrder stops the old tasks before starting the new ones, which is the default order of the updates
+	swarmStopFirstOrder  = "stop-first"
+	swarmStartFirstOrder = "start-first"
+)
+
+// addUpdateConfig sets the update strategy of the deployment from the update config of the service, and returns the settings which have
+// no equivalent. The tasks updated together become the pods which can be unavailable, or the extra pods when the new tasks are started first.
+// The delay between the updates is approximated by the time for which the new pods have to be ready.
+func addUpdateConfig(service *irtypes.Service, updateConfig *types.UpdateConfig) []string {
+	unsupported := []string{}
+	if updateConfig == nil {
+		return unsupported
+	}
+	parallelism := 1
+	if updateConfig.Parallelism != nil {
+		parallelism = int(*updateConfig.Parallelism)
+	}
+	updateStrategy := &irtypes.UpdateStrategy{MinReadySeconds: int32(time.Duration(updateConfig.Delay).Seconds())}
+	switch updateConfig.Order {
+	case swarmStartFirstOrder:
+		// A parallelism of 0 updates all the tasks at once
+		updateStrategy.MaxSurge = intstr.FromString("100%")
+		if parallelism > 0 {
+			updateStrategy.MaxSurge = intstr.FromInt(parallelism)
+		}
+		updateStrategy.MaxUnavailable = intstr.FromInt(0)
+	case "", swarmStopFirstOrder:
+		if parallelism == 0 {
+			updateStrategy.Recreate = true
+		} else {
+			updateStrategy.MaxSurge = intstr.FromInt(0)
+			updateStrategy.MaxUnavailable = intstr.FromInt(parallelism)
+		}
+	default:
+		unsupported = append(unsupported, "update_config order="+updateConfig.Order)
+	}
+	service.UpdateStrategy = updateStrategy
+	if updateConfig.FailureAction != "" && updateConfig.FailureAction != "pause" {
+		unsupported = append(unsupported, "update_config failure_action="+updateConfig.FailureAction)
+	}
+	if updateConfig.Monitor != 0 {
+		unsupported = append(unsupported, "update_config monitor="+time.Duration(updateConfig.Monitor).String())
+	}
+	if updateConfig.MaxFailureRatio != 0 {
+		unsupported = append(unsupported, fmt.Sprintf("update_config max_failure_ratio=%g", updateConfig.MaxFailureRatio))
+	}
+	return unsupported
+}
+
+// addRestartPolicy sets the restart policy of the pods from the restart setting of the service, or from the condition of its restart policy,
+// and annotates the service with the settings which have no equivalent. The services which are not always restarted run as jobs, whose pods
+// are retried up to the maximum attempts, unless they serve ports, since the jobs are not restarted once they succeed.
+func addRestartPolicy(service *irtypes.Service, restart string, restartPolicy *types.RestartPolicy, servesPorts bool) {
+	unsupported := []string{}
+	condition := restart
+	var maxAttempts *int32
+	// The restart setting can limit the retries of the on-failure condition, like on-failure:3
+	if parts := strings.SplitN(restart, ":", 2); len(parts) == 2 {
+		condition = parts[0]
+		attempts := cast.ToInt32(parts[1])
+		maxAttempts = &attempts
+	}
+	if restartPolicy != nil {
+		if restartPolicy.Condition != "" {
+			condition = restartPolicy.Condition
+		}
+		if restartPolicy.MaxAttempts != nil {
+			attempts := int32(*restartPolicy.MaxAttempts)
+			maxAttempts = &attempts
+		}
+		if restartPolicy.Delay != nil {
+			unsupported = append(unsupported, "restart_policy delay="+time.Duration(*restartPolicy.Delay).String())
+		}
+		if restartPolicy.Window != nil {
+			unsupported = append(unsupported, "restart_policy window="+time.Duration(*restartPolicy.Window).String())
+		}
+	}
+	policy := core.RestartPolicyAlways
+	switch condition {
+	case "unless-stopped":
+		log.Warnf("Restart policy 'unless-stopped' in service %s is not supported, convert it to 'always'", service.Name)
+		service.RestartPolicy = core.RestartPolicyAlways
+	case "on-failure":
+		policy = core.RestartPolicyOnFailure
+	case "no", "none":
+		policy = core.RestartPolicyNever
+	}
+	if policy != core.RestartPolicyAlways {
+		if servesPorts {
+			unsupported = append(unsupported, fmt.Sprintf("the restart condition %s of a service serving ports", condition))
+		} else {
+			service.RestartPolicy = policy
+			service.BackoffLimit = maxAttempts
+			if policy == core.RestartPolicyNever && maxAttempts == nil {
+				noRetries := int32(0)
+				service.BackoffLimit = &noRetries
+			}
+			maxAttempts = nil
+		}
+	}
+	if maxAttempts != nil && *maxAttempts > 0 {
+		unsupported = append(unsupported, fmt.Sprintf("max_attempts=%d", *maxAttempts))
+	}
+	if len(unsupported) > 0 {
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+			restartTODOKey: fmt.Sprintf("The restart settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", ")),
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestAddUpdateConfig(t *testing.T) {
+	two := uint64(2)
+	service := irtypes.NewServiceWithName("web")
+	unsupported := addUpdateConfig(&service, &types.UpdateConfig{Parallelism: &two, Delay: types.Duration(10 * time.Second), Order: "start-first", FailureAction: "rollback"})
+	want := irtypes.UpdateStrategy{MaxSurge: intstr.FromInt(2), MaxUnavailable: intstr.FromInt(0), MinReadySeconds: 10}
+	if service.UpdateStrategy == nil || *service.UpdateStrategy != want {
+		t.Fatalf("Failed to start the new pods first. Expected: %+v Actual: %+v", want, service.UpdateStrategy)
+	}
+	if len(unsupported) != 1 || unsupported[0] != "update_config failure_action=rollback" {
+		t.Fatalf("Failed to flag the failure action. Actual: %v", unsupported)
+	}
+
+	zero := uint64(0)
+	service = irtypes.NewServiceWithName("web")
+	addUpdateConfig(&service, &types.UpdateConfig{Parallelism: &zero})
+	if service.UpdateStrategy == nil || !service.UpdateStrategy.Recreate {
+		t.Fatalf("Expected the pods to be recreated when all the tasks are stopped at once. Actual: %+v", service.UpdateStrategy)
+	}
+	service = irtypes.NewServiceWithName("web")
+	addUpdateConfig(&service, &types.UpdateConfig{})
+	if want := (irtypes.UpdateStrategy{MaxSurge: intstr.FromInt(0), MaxUnavailable: intstr.FromInt(1)}); *service.UpdateStrategy != want {
+		t.Fatalf("Failed to stop the old pods first. Expected: %+v Actual: %+v", want, service.UpdateStrategy)
+	}
+}
+
+func TestAddRestartPolicy(t *testing.T) {
+	three := uint64(3)
+	delay := types.Duration(5 * time.Second)
+	service := irtypes.NewServiceWithName("migrate")
+	addRestartPolicy(&service, "always", &types.RestartPolicy{Condition: "on-failure", MaxAttempts: &three, Delay: &delay}, false)
+	if service.RestartPolicy != core.RestartPolicyOnFailure || service.BackoffLimit == nil || *service.BackoffLimit != 3 {
+		t.Fatalf("Expected a job retried 3 times. Actual: %s %v", service.RestartPolicy, service.BackoffLimit)
+	}
+	if todo := service.Annotations[restartTODOKey]; todo != "The restart settings restart_policy delay=5s have no equivalent and were not translated." {
+		t.Fatalf("Failed to flag the restart delay. Actual: %s", todo)
+	}
+
+	service = irtypes.NewServiceWithName("worker")
+	addRestartPolicy(&service, "no", nil, false)
+	if service.RestartPolicy != core.RestartPolicyNever || service.BackoffLimit == nil || *service.BackoffLimit != 0 {
+		t.Fatalf("Expected a job which is not retried. Actual: %s %v", service.RestartPolicy, service.BackoffLimit)
+	}
+
+	service = irtypes.NewServiceWithName("web")
+	addRestartPolicy(&service, "on-failure:5", nil, true)
+	if service.RestartPolicy != "" || service.BackoffLimit != nil {
+		t.Fatalf("Expected the service serving ports to stay a deployment. Actual: %s %v", service.RestartPolicy, service.BackoffLimit)
+	}
+	wantTODO := "The restart settings the restart condition on-failure of a service serving ports, max_attempts=5 have no equivalent and were not translated."
+	if todo := service.Annotations[restartTODOKey]; todo != wantTODO {
+		t.Fatalf("Failed to flag the restart settings. Expected: %s Actual: %s", wantTODO, todo)
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	extendsKey = "extends"
+	// extensionFieldPrefix is the prefix of the extension fields, which are usually used only to hold yaml anchors
+	extensionFieldPrefix = "x-"
+	// extendsTODOKey flags the services whose values were merged from the services they extend
+	extendsTODOKey = common.TODOAnnotation + "extends"
+)
+
+// notInheritedKeys are the keys of a service that are never inherited by the services extending it
+var notInheritedKeys = []string{"links", "volumes_from", "depends_on", "container_name"}
+
+// concatenatedKeys are the sequences that are concatenated with the sequences of the extended service instead of being overridden
+var concatenatedKeys = []string{"ports", "expose", "external_links", "dns", "dns_search", "tmpfs", "volumes", "devices", "env_file", "cap_add", "cap_drop", "security_opt"}
+
+// keyValueKeys are the keys that can either be a mapping or a list of KEY=value strings
+var keyValueKeys = []string{"environment", "labels", "extra_hosts", "sysctls"}
+
+// extendsResolver resolves the extends of the services of a compose file
+type extendsResolver struct {
+	resolved map[string]map[string]interface{} // [file path#service name][service]
+	visiting map[string]bool
+	files    map[string]map[string]interface{} // [file path][services]
+}
+
+// resolveExtendsV3 merges the services extended by the services of the compose file into them and removes the
+// extension fields, since the v3 loader supports neither extends nor, for the older versions, extension fields.
+// The yaml anchors and aliases are already resolved by the yaml parser. It returns the descriptions of the extends.
+func resolveExtendsV3(path string, parsedComposeFile map[string]interface{}) map[string]string {
+	removeExtensionFields(parsedComposeFile)
+	services, ok := parsedComposeFile["services"].(map[string]interface{})
+	if !ok {
+		return map[string]string{}
+	}
+	r := extendsResolver{resolved: map[string]map[string]interface{}{}, visiting: map[string]bool{}, files: map[string]map[string]interface{}{path: services}}
+	descriptions := map[string]string{}
+	for serviceName := range services {
+		service, err := r.resolve(path, serviceName)
+		if err != nil {
+			log.Warnf("Unable to resolve the extends of the service %s in the compose file at path %s . Ignoring the extends. Error: %q", serviceName, path, err)
+			if service, ok := services[serviceName].(map[string]interface{}); ok {
+				delete(service, extendsKey)
+			}
+			continue
+		}
+		if description := getExtendsDescription(path, services[serviceName]); description != "" {
+			descriptions[serviceName] = description
+		}
+		services[serviceName] = service
+	}
+	return descriptions
+}
+
+// getExtendedServices returns the descriptions of the extends of the services of the compose file.
+// It is used for the compose files whose loader resolves the extends.
+func getExtendedServices(path string) map[string]string {
+	descriptions := map[string]string{}
+	services, err := readComposeServices(path)
+	if err != nil {
+		return descriptions
+	}
+	for serviceName, service := range services {
+		if description := getExtendsDescription(path, service); description != "" {
+			descriptions[serviceName] = description
+		}
+	}
+	return descriptions
+}
+
+// getExtendsDescription returns the description of the service extended by the service, or an empty string if it extends none
+func getExtendsDescription(path string, service interface{}) string {
+	serviceMap, ok := service.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	baseFilePath, baseServiceName, ok := getExtends(path, serviceMap)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("The values of the service %s in the file %s, which this service extends, were merged into this service. Review the merged values.", baseServiceName, filepath.Base(baseFilePath))
+}
+
+// getExtends returns the file path and the name of the service extended by the service
+func getExtends(path string, service map[string]interface{}) (string, string, bool) {
+	switch extends := service[extendsKey].(type) {
+	case string:
+		return path, extends, extends != ""
+	case map[string]interface{}:
+		baseServiceName, _ := extends["service"].(string)
+		if baseServiceName == "" {
+			return "", "", false
+		}
+		baseFilePath := path
+		if file, ok := extends["file"].(string); ok && file != "" {
+			baseFilePath = file
+			if !filepath.IsAbs(baseFilePath) {
+				baseFilePath = filepath.Join(filepath.Dir(path), baseFilePath)
+			}
+		}
+		return baseFilePath, baseServiceName, true
+	}
+	return "", "", false
+}
+
+// resolve returns the service with the values of the services it extends, recursively, merged into it
+func (r *extendsResolver) resolve(path string, serviceName string) (map[string]interface{}, error) {
+	id := path + "#" + serviceName
+	if service, ok := r.resolved[id]; ok {
+		return service, nil
+	}
+	if r.visiting[id] {
+		return nil, fmt.Errorf("the service %s in the file %s extends itself", serviceName, path)
+	}
+	r.visiting[id] = true
+	defer delete(r.visiting, id)
+	services, ok := r.files[path]
+	if !ok {
+		var err error
+		if services, err = readComposeServices(path); err != nil {
+			return nil, err
+		}
+		removeExtensionFields(map[string]interface{}{"services": services})
+		r.files[path] = services
+	}
+	service, ok := services[serviceName].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("there is no service %s in the file %s", serviceName, path)
+	}
+	baseFilePath, baseServiceName, ok := getExtends(path, service)
+	if !ok {
+		r.resolved[id] = service
+		return service, nil
+	}
+	base, err := r.resolve(baseFilePath, baseServiceName)
+	if err != nil {
+		return nil, err
+	}
+	merged := mergeServices(base, service)
+	r.resolved[id] = merged
+	return merged, nil
+}
+
+// readComposeServices returns the services of the compose file. The version 1 files have the services at the top level.
+func readComposeServices(path string) (map[string]interface{}, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	parsedComposeFile, err := loader.ParseYAML(fileData)
+	if err != nil {
+		return nil, err
+	}
+	if services, ok := parsedComposeFile["services"].(map[string]interface{}); ok {
+		return services, nil
+	}
+	if _, ok := parsedComposeFile["version"]; ok {
+		return map[string]interface{}{}, nil
+	}
+	return parsedComposeFile, nil
+}
+
+// removeExtensionFields removes the top level extension fields and the extension fields of the services
+func removeExtensionFields(parsedComposeFile map[string]interface{}) {
+	for key := range parsedComposeFile {
+		if strings.HasPrefix(key, extensionFieldPrefix) {
+			delete(parsedComposeFile, key)
+		}
+	}
+	services, ok := parsedComposeFile["services"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, service := range services {
+		if serviceMap, ok := service.(map[string]interface{}); ok {
+			for key := range serviceMap {
+				if strings.HasPrefix(key, extensionFieldPrefix) {
+					delete(serviceMap, key)
+				}
+			}
+		}
+	}
+}
+
+// mergeServices returns the service with the values of the base service it extends merged into it.
+// The mappings are merged, some sequences are concatenated and the other values of the service override those of the base.
+func mergeServices(base map[string]interface{}, service map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range base {
+		if common.IsStringPresent(notInheritedKeys, key) {
+			continue
+		}
+		merged[key] = value
+	}
+	for key, value := range service {
+		if key == extendsKey {
+			continue
+		}
+		baseValue, ok := merged[key]
+		if !ok {
+			merged[key] = value
+			continue
+		}
+		if common.IsStringPresent(keyValueKeys, key) {
+			baseValue = toMapping(baseValue)
+			value = toMapping(value)
+		}
+		merged[key] = mergeValues(key, baseValue, value)
+	}
+	return merged
+}
+
+// mergeValues merges the value of a key of a service into the value of the service it extends
+func mergeValues(key string, baseValue interface{}, value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		baseMap, ok := baseValue.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		merged := map[string]interface{}{}
+		for k, v := range baseMap {
+			merged[k] = v
+		}
+		for k, v := range value {
+			if bv, ok := merged[k]; ok {
+				merged[k] = mergeValues(k, bv, v)
+				continue
+			}
+			merged[k] = v
+		}
+		return merged
+	case []interface{}:
+		baseSlice, ok := baseValue.([]interface{})
+		if !ok || !common.IsStringPresent(concatenatedKeys, key) {
+			return value
+		}
+		merged := append([]interface{}{}, baseSlice...)
+		for _, v := range value {
+			found := false
+			for _, bv := range baseSlice {
+				if fmt.Sprint(bv) == fmt.Sprint(v) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	}
+	return value
+}
+
+// toMapping converts a list of KEY=value strings to a mapping
+func toMapping(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	mapping := map[string]interface{}{}
+	for _, item := range list {
+		s := fmt.Sprint(item)
+		if idx := strings.Index(s, "="); idx >= 0 {
+			mapping[s[:idx]] = s[idx+1:]
+		} else if idx := strings.Index(s, ":"); idx >= 0 {
+			// extra_hosts use the HOST:IP format
+			mapping[s[:idx]] = s[idx+1:]
+		} else {
+			mapping[s] = nil
+		}
+	}
+	return mapping
+}
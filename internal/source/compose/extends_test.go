@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseV3WithExtendsAndAnchors(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "m2k-compose-extends-test-")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory. Error: %q", err)
+	}
+	defer os.RemoveAll(tempDir)
+	common := `version: "3"
+services:
+  base:
+    image: base:latest
+    environment:
+      - LOG_LEVEL=info
+    ports:
+      - "8080:8080"
+    depends_on:
+      - db
+  db:
+    image: postgres
+`
+	compose := `version: "3.0"
+x-logging: &default-env
+  REGION: us-east
+services:
+  web:
+    extends:
+      file: common.yml
+      service: base
+    environment:
+      <<: *default-env
+      LOG_LEVEL: debug
+    ports:
+      - "9090:9090"
+  worker:
+    extends: web
+    image: worker:latest
+`
+	for name, contents := range map[string]string{"common.yml": common, "docker-compose.yml": compose} {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("Failed to write the file %s . Error: %q", name, err)
+		}
+	}
+	config, extends, err := parseV3(filepath.Join(tempDir, "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Failed to parse the compose file with extends and anchors. Error: %q", err)
+	}
+	if len(config.Services) != 2 {
+		t.Fatalf("Expected 2 services. Actual: %+v", config.Services)
+	}
+	for _, service := range config.Services {
+		if service.Environment["LOG_LEVEL"] == nil || *service.Environment["LOG_LEVEL"] != "debug" || service.Environment["REGION"] == nil {
+			t.Fatalf("Expected the environment of the service %s to be merged. Actual: %+v", service.Name, service.Environment)
+		}
+		if len(service.Ports) != 2 || len(service.DependsOn) != 0 {
+			t.Fatalf("Expected the service %s to have the ports of both services and no depends_on. Actual: %+v", service.Name, service)
+		}
+		if _, ok := extends[service.Name]; !ok {
+			t.Fatalf("Expected the extends of the service %s to be described. Actual: %+v", service.Name, extends)
+		}
+		if service.Name == "worker" && service.Image != "worker:latest" {
+			t.Fatalf("Expected the image of the worker to override the image of the service it extends. Actual: %s", service.Image)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// swarmDeployTODOKey flags the services whose Docker Swarm deploy settings have no equivalent
+	swarmDeployTODOKey = common.TODOAnnotation + "swarmdeploy"
+	// swarmNodeLabelPrefix is the prefix of the placement constraints on the labels of the nodes
+	swarmNodeLabelPrefix = "node.labels."
+	swarmManagerRole     = "manager"
+	swarmWorkerRole      = "worker"
+	controlPlaneLabel    = "node-role.kubernetes.io/control-plane"
+)
+
+var (
+	// swarmNodeAttributeLabels maps the node attributes of the placement constraints to the well known labels of the kubernetes nodes
+	swarmNodeAttributeLabels = map[string]string{
+		"node.hostname":      "kubernetes.io/hostname",
+		"node.platform.os":   "kubernetes.io/os",
+		"node.platform.arch": "kubernetes.io/arch",
+	}
+	// swarmArchitectures maps the architectures reported by the Docker engine to the architectures of the kubernetes nodes
+	swarmArchitectures = map[string]string{
+		"x86_64":  "amd64",
+		"aarch64": "arm64",
+		"armv7l":  "arm",
+	}
+)
+
+// IsV3SwarmStack checks if the file is a compose file using the deploy settings which are only used by Docker Swarm, like the placement constraints
+func IsV3SwarmStack(path string) bool {
+	config, err := ParseV3(path)
+	if err != nil {
+		return false
+	}
+	for _, service := range config.Services {
+		deploy := service.Deploy
+		if deploy.Mode == "global" || deploy.EndpointMode != "" || deploy.UpdateConfig != nil || deploy.RollbackConfig != nil ||
+			len(deploy.Placement.Constraints) > 0 || len(deploy.Placement.Preferences) > 0 || deploy.Placement.MaxReplicas > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// addSwarmPlacement adds a node affinity for the placement constraints of the service, sets its update strategy, and flags the deploy settings which have no equivalent
+func addSwarmPlacement(service *irtypes.Service, deploy types.DeployConfig) {
+	requirements, unsupported := getSwarmNodeSelectorRequirements(deploy.Placement.Constraints)
+	if len(requirements) > 0 {
+		service.Affinity = &core.Affinity{NodeAffinity: &core.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+				NodeSelectorTerms: []core.NodeSelectorTerm{{MatchExpressions: requirements}},
+			},
+		}}
+	}
+	for _, preference := range deploy.Placement.Preferences {
+		unsupported = append(unsupported, "placement preference spread="+preference.Spread)
+	}
+	if deploy.Placement.MaxReplicas > 0 {
+		unsupported = append(unsupported, fmt.Sprintf("max_replicas_per_node=%d", deploy.Placement.MaxReplicas))
+	}
+	unsupported = append(unsupported, addUpdateConfig(service, deploy.UpdateConfig)...)
+	if deploy.RollbackConfig != nil {
+		unsupported = append(unsupported, "rollback_config")
+	}
+	if deploy.EndpointMode != "" && deploy.EndpointMode != "vip" {
+		unsupported = append(unsupported, "endpoint_mode="+deploy.EndpointMode)
+	}
+	if len(unsupported) > 0 {
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+			swarmDeployTODOKey: fmt.Sprintf("The Docker Swarm deploy settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", ")),
+		})
+	}
+}
+
+// getSwarmNodeSelectorRequirements converts the placement constraints, like node.labels.zone==east, to node selector requirements.
+// The constraints which cannot be converted, like the ones on the node ids, are returned separately.
+func getSwarmNodeSelectorRequirements(constraints []string) ([]core.NodeSelectorRequirement, []string) {
+	requirements := []core.NodeSelectorRequirement{}
+	unsupported := []string{}
+	for _, constraint := range constraints {
+		requirement, ok := getSwarmNodeSelectorRequirement(constraint)
+		if !ok {
+			unsupported = append(unsupported, "placement constraint "+constraint)
+			continue
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, unsupported
+}
+
+func getSwarmNodeSelectorRequirement(constraint string) (core.NodeSelectorRequirement, bool) {
+	operator := core.NodeSelectorOpIn
+	parts := strings.SplitN(constraint, "==", 2)
+	if len(parts) != 2 {
+		operator = core.NodeSelectorOpNotIn
+		parts = strings.SplitN(constraint, "!=", 2)
+		if len(parts) != 2 {
+			return core.NodeSelectorRequirement{}, false
+		}
+	}
+	attribute, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if attribute == "node.role" {
+		if value != swarmManagerRole && value != swarmWorkerRole {
+			return core.NodeSelectorRequirement{}, false
+		}
+		// The managers are the control plane nodes, which have the control plane label
+		if (value == swarmManagerRole) == (operator == core.NodeSelectorOpIn) {
+			return core.NodeSelectorRequirement{Key: controlPlaneLabel, Operator: core.NodeSelectorOpExists}, true
+		}
+		return core.NodeSelectorRequirement{Key: controlPlaneLabel, Operator: core.NodeSelectorOpDoesNotExist}, true
+	}
+	key := ""
+	if strings.HasPrefix(attribute, swarmNodeLabelPrefix) {
+		key = strings.TrimPrefix(attribute, swarmNodeLabelPrefix)
+	} else if label, ok := swarmNodeAttributeLabels[attribute]; ok {
+		key = label
+		if arch, ok := swarmArchitectures[value]; ok && attribute == "node.platform.arch" {
+			value = arch
+		}
+		if attribute == "node.platform.os" {
+			value = strings.ToLower(value)
+		}
+	}
+	if key == "" || value == "" {
+		return core.NodeSelectorRequirement{}, false
+	}
+	return core.NodeSelectorRequirement{Key: key, Operator: operator, Values: []string{value}}, true
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestIsV3SwarmStack(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "m2k-compose-swarm-test-")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory. Error: %q", err)
+	}
+	defer os.RemoveAll(tempDir)
+	stackPath := filepath.Join(tempDir, "stack.yml")
+	stack := `version: "3.8"
+services:
+  web:
+    image: nginx:latest
+    deploy:
+      replicas: 3
+      placement:
+        constraints:
+          - node.labels.zone == east
+`
+	if err := ioutil.WriteFile(stackPath, []byte(stack), 0644); err != nil {
+		t.Fatalf("Failed to write the stack file. Error: %q", err)
+	}
+	composePath := filepath.Join(tempDir, "docker-compose.yml")
+	compose := `version: "3.8"
+services:
+  web:
+    image: nginx:latest
+    deploy:
+      replicas: 3
+`
+	if err := ioutil.WriteFile(composePath, []byte(compose), 0644); err != nil {
+		t.Fatalf("Failed to write the compose file. Error: %q", err)
+	}
+	if !IsV3SwarmStack(stackPath) {
+		t.Fatalf("Expected the file with placement constraints to be a Docker Swarm stack file")
+	}
+	if IsV3SwarmStack(composePath) {
+		t.Fatalf("Expected the file with only the replicas not to be a Docker Swarm stack file")
+	}
+}
+
+func TestAddSwarmPlacement(t *testing.T) {
+	service := irtypes.NewServiceWithName("web")
+	deploy := types.DeployConfig{
+		Placement: types.Placement{
+			Constraints: []string{"node.role == manager", "node.labels.zone==east", "node.platform.arch != x86_64", "node.id == 2ivku8v2gvtg4"},
+			Preferences: []types.PlacementPreferences{{Spread: "node.labels.datacenter"}},
+		},
+		EndpointMode: "dnsrr",
+	}
+	addSwarmPlacement(&service, deploy)
+	want := []core.NodeSelectorRequirement{
+		{Key: controlPlaneLabel, Operator: core.NodeSelectorOpExists},
+		{Key: "zone", Operator: core.NodeSelectorOpIn, Values: []string{"east"}},
+		{Key: "kubernetes.io/arch", Operator: core.NodeSelectorOpNotIn, Values: []string{"amd64"}},
+	}
+	if service.Affinity == nil || service.Affinity.NodeAffinity == nil || service.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		t.Fatalf("Expected a node affinity for the placement constraints. Actual: %+v", service.Affinity)
+	}
+	terms := service.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || !reflect.DeepEqual(terms[0].MatchExpressions, want) {
+		t.Fatalf("Failed to convert the placement constraints. Expected: %+v Actual: %+v", want, terms)
+	}
+	wantTODO := "The Docker Swarm deploy settings placement constraint node.id == 2ivku8v2gvtg4, placement preference spread=node.labels.datacenter, endpoint_mode=dnsrr have no equivalent and were not translated."
+	if todo := service.Annotations[swarmDeployTODOKey]; todo != wantTODO {
+		t.Fatalf("Failed to flag the unsupported deploy settings. Expected: %s Actual: %s", wantTODO, todo)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
@@ -30,7 +31,7 @@ import (
 const (
 	modeReadOnly          string = "ro"
 	tmpFsPath             string = "tmpfs"
-	defaultSecretBasePath string = "/var/secrets"
+	defaultSecretBasePath string = "/run/secrets"
 	envFile               string = "env_file"
 )
 
@@ -123,3 +124,32 @@ func getHash(data []byte) uint64 {
 	hasher.Write(data)
 	return hasher.Sum64()
 }
+
+// getStopSignalLifecycle returns a preStop hook which sends the stop signal to the main process of the container, since
+// Kubernetes always stops the containers using SIGTERM. Returns nil if the stop signal is SIGTERM.
+func getStopSignalLifecycle(stopSignal string) *core.Lifecycle {
+	signal := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(stopSignal)), "SIG")
+	if signal == "" || signal == "TERM" || signal == "15" {
+		return nil
+	}
+	command := "kill -s " + signal + " 1"
+	if _, err := strconv.Atoi(signal); err == nil {
+		command = "kill -" + signal + " 1"
+	}
+	return &core.Lifecycle{PreStop: &core.Handler{Exec: &core.ExecAction{Command: []string{"/bin/sh", "-c", command}}}}
+}
+
+// getDependsOn returns the normalized names of the services in depends_on and links. The links can have an alias, like db:database.
+func getDependsOn(dependsOn []string, links []string) []string {
+	names := append([]string{}, dependsOn...)
+	for _, link := range links {
+		names = append(names, strings.SplitN(link, ":", 2)[0])
+	}
+	services := []string{}
+	for _, service := range names {
+		if service = common.NormalizeForServiceName(strings.TrimSpace(service)); service != "" && !common.IsStringPresent(services, service) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
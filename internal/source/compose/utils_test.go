@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetStopSignalLifecycle(t *testing.T) {
+	for _, stopSignal := range []string{"", "SIGTERM", "term", "15"} {
+		if lifecycle := getStopSignalLifecycle(stopSignal); lifecycle != nil {
+			t.Fatalf("Expected no preStop hook for the stop signal %q . Actual: %+v", stopSignal, lifecycle)
+		}
+	}
+	wants := map[string][]string{
+		"SIGQUIT": {"/bin/sh", "-c", "kill -s QUIT 1"},
+		"2":       {"/bin/sh", "-c", "kill -2 1"},
+	}
+	for stopSignal, want := range wants {
+		lifecycle := getStopSignalLifecycle(stopSignal)
+		if lifecycle == nil || lifecycle.PreStop == nil || lifecycle.PreStop.Exec == nil || !reflect.DeepEqual(lifecycle.PreStop.Exec.Command, want) {
+			t.Fatalf("Expected the preStop hook %v for the stop signal %s . Actual: %+v", want, stopSignal, lifecycle)
+		}
+	}
+}
+
+func TestGetDependsOn(t *testing.T) {
+	want := []string{"db", "cache", "queue"}
+	if dependsOn := getDependsOn([]string{"db", "cache"}, []string{"db:database", "queue"}); !reflect.DeepEqual(dependsOn, want) {
+		t.Fatalf("Failed to get the services of depends_on and links. Expected: %v Actual: %v", want, dependsOn)
+	}
+}
@@ -50,6 +50,12 @@ func removeNonExistentEnvFilesV2(path string) preprocessFunc {
 	return func(rawServiceMap config.RawServiceMap) (config.RawServiceMap, error) {
 		// Remove unresolvable env files, so that the parser does not throw error
 		for serviceName, vals := range rawServiceMap {
+			// The extension fields only hold yaml anchors, which are already resolved, and fail the validation
+			for key := range vals {
+				if strings.HasPrefix(key, extensionFieldPrefix) {
+					delete(vals, key)
+				}
+			}
 			if envfilesvals, ok := vals[envFile]; ok {
 				// env_file can be a string or list of strings
 				// https://docs.docker.com/compose/compose-file/compose-file-v2/#env_file
@@ -127,16 +133,38 @@ func ParseV2(path string) (*project.Project, error) {
 	return proj, nil
 }
 
+// GetV1V2ContainerPorts returns the ports published and exposed by the container of a compose service
+func GetV1V2ContainerPorts(service *config.ServiceConfig) []core.ContainerPort {
+	return new(V1V2Loader).getPorts(service.Ports, service.Expose)
+}
+
+// GetV1V2Runtime returns the entrypoint, the command, the env vars, the working directory and the user of a compose service
+func GetV1V2Runtime(service *config.ServiceConfig) plantypes.RuntimeSpec {
+	runtime := plantypes.RuntimeSpec{Command: service.Entrypoint, Args: service.Command, WorkingDir: service.WorkingDir, User: service.User}
+	for _, env := range new(V1V2Loader).getEnvs(service.Environment) {
+		// The env vars without values are taken from the environment in which docker compose is run
+		if env.Value != "unknown" {
+			runtime.SetEnv(env.Name, env.Value)
+		}
+	}
+	return runtime
+}
+
+// GetV1V2DependsOn returns the services which a compose service depends on or links to
+func GetV1V2DependsOn(service *config.ServiceConfig) []string {
+	return getDependsOn(service.DependsOn, service.Links)
+}
+
 // ConvertToIR loads a compose file to IR
 func (c *V1V2Loader) ConvertToIR(composefilepath string, plan plantypes.Plan, service plantypes.Service) (ir irtypes.IR, err error) {
 	proj, err := ParseV2(composefilepath)
 	if err != nil {
 		return irtypes.IR{}, err
 	}
-	return c.convertToIR(filepath.Dir(composefilepath), proj, plan, service)
+	return c.convertToIR(filepath.Dir(composefilepath), proj, getExtendedServices(composefilepath), plan, service)
 }
 
-func (c *V1V2Loader) convertToIR(filedir string, composeObject *project.Project, plan plantypes.Plan, service plantypes.Service) (ir irtypes.IR, err error) {
+func (c *V1V2Loader) convertToIR(filedir string, composeObject *project.Project, extends map[string]string, plan plantypes.Plan, service plantypes.Service) (ir irtypes.IR, err error) {
 	serviceName := service.ServiceName
 	ir = irtypes.IR{
 		Services: map[string]irtypes.Service{},
@@ -147,7 +175,10 @@ func (c *V1V2Loader) convertToIR(filedir string, composeObject *project.Project,
 			continue
 		}
 		serviceConfig := irtypes.NewServiceWithName(common.NormalizeForServiceName(name))
-		serviceConfig.Annotations = map[string]string(composeServiceConfig.Labels)
+		serviceConfig.Annotations = common.MergeStringMaps(composeServiceConfig.Labels, nil)
+		if description, ok := extends[name]; ok {
+			serviceConfig.Annotations[extendsTODOKey] = description
+		}
 		if composeServiceConfig.Hostname != "" {
 			serviceConfig.Hostname = composeServiceConfig.Hostname
 		}
@@ -163,10 +194,14 @@ func (c *V1V2Loader) convertToIR(filedir string, composeObject *project.Project,
 			//TODO: Add support for args and labels
 			// filedir, name, serviceContainer.Image, composeServiceConfig.Build.Dockerfile, composeServiceConfig.Build.Context
 
-			con, err := new(containerizer.ReuseDockerfileContainerizer).GetContainer(plan, service)
-			if err != nil {
-				log.Warnf("Unable to get containization script even though build parameters are present : %s", err)
-			} else {
+			var con irtypes.Container
+			if err := containerizer.RunServiceStep(service.ServiceName, "reuse the Dockerfile of", func() (err error) {
+				con, err = new(containerizer.ReuseDockerfileContainerizer).GetContainer(plan, service)
+				return err
+			}); err == nil {
+				if err := containerizer.CommitServiceArtifacts(service.ServiceName, con); err != nil {
+					log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+				}
 				ir.AddContainer(con)
 			}
 		}
@@ -233,6 +268,7 @@ func (c *V1V2Loader) convertToIR(filedir string, composeObject *project.Project,
 				log.Warnf("Failed to parse duration %v for service %v", composeServiceConfig.StopGracePeriod, name)
 			}
 		}
+		serviceContainer.Lifecycle = getStopSignalLifecycle(composeServiceConfig.StopSignal)
 		if composeServiceConfig.MemLimit != 0 {
 			resourceLimit := core.ResourceList{}
 			if composeServiceConfig.MemLimit != 0 {
@@ -241,11 +277,7 @@ func (c *V1V2Loader) convertToIR(filedir string, composeObject *project.Project,
 			serviceContainer.Resources.Limits = resourceLimit
 		}
 
-		restart := composeServiceConfig.Restart
-		if restart == "unless-stopped" {
-			log.Warnf("Restart policy 'unless-stopped' in service %s is not supported, convert it to 'always'", name)
-			serviceConfig.RestartPolicy = core.RestartPolicyAlways
-		}
+		addRestartPolicy(&serviceConfig, composeServiceConfig.Restart, nil, len(serviceContainer.Ports) > 0)
 
 		if composeServiceConfig.Networks != nil && len(composeServiceConfig.Networks.Networks) > 0 {
 			for _, value := range composeServiceConfig.Networks.Networks {
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/internal/containerizer"
+	"github.com/konveyor/move2kube/internal/qaengine"
 	irtypes "github.com/konveyor/move2kube/internal/types"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	"github.com/pkg/errors"
@@ -91,20 +93,54 @@ func removeNonExistentEnvFilesV3(path string, parsedComposeFile map[string]inter
 
 // ParseV3 parses version 3 compose files
 func ParseV3(path string) (*types.Config, error) {
+	config, _, err := parseV3(path)
+	return config, err
+}
+
+// GetV3ContainerPorts returns the ports published and exposed by the container of a compose service
+func GetV3ContainerPorts(service types.ServiceConfig) []core.ContainerPort {
+	return new(V3Loader).getPorts(service.Ports, service.Expose)
+}
+
+// GetV3Runtime returns the entrypoint, the command, the env vars, the working directory and the user of a compose service
+func GetV3Runtime(service types.ServiceConfig) plantypes.RuntimeSpec {
+	runtime := plantypes.RuntimeSpec{Command: service.Entrypoint, Args: service.Command, WorkingDir: service.WorkingDir, User: service.User}
+	// The env vars without values are taken from the environment in which docker compose is run
+	names := []string{}
+	for name, value := range service.Environment {
+		if value != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		runtime.SetEnv(name, *service.Environment[name])
+	}
+	return runtime
+}
+
+// GetV3DependsOn returns the services which a compose service depends on or links to
+func GetV3DependsOn(service types.ServiceConfig) []string {
+	return getDependsOn(service.DependsOn, service.Links)
+}
+
+// parseV3 parses version 3 compose files and returns the descriptions of the extends of the services
+func parseV3(path string) (*types.Config, map[string]string, error) {
 	fileData, err := ioutil.ReadFile(path)
 	if err != nil {
 		err := fmt.Errorf("Unable to load Compose file at path %s Error: %q", path, err)
 		log.Debug(err)
-		return nil, err
+		return nil, nil, err
 	}
 	// Parse the Compose File
 	parsedComposeFile, err := loader.ParseYAML(fileData)
 	if err != nil {
 		err := fmt.Errorf("Unable to load Compose file at path %s Error: %q", path, err)
 		log.Debug(err)
-		return nil, err
+		return nil, nil, err
 	}
 	parsedComposeFile = removeNonExistentEnvFilesV3(path, parsedComposeFile)
+	extends := resolveExtendsV3(path, parsedComposeFile)
 	// Config details
 	configDetails := types.ConfigDetails{
 		WorkingDir:  filepath.Dir(path),
@@ -115,24 +151,24 @@ func ParseV3(path string) (*types.Config, error) {
 	if err != nil {
 		err := fmt.Errorf("Unable to load Compose file at path %s Error: %q", path, err)
 		log.Debug(err)
-		return nil, err
+		return nil, nil, err
 	}
-	return config, nil
+	return config, extends, nil
 }
 
 // ConvertToIR loads an v3 compose file into IR
 func (c *V3Loader) ConvertToIR(composefilepath string, plan plantypes.Plan, service plantypes.Service) (irtypes.IR, error) {
 	log.Debugf("About to load configuration from docker compose file at path %s", composefilepath)
-	config, err := ParseV3(composefilepath)
+	config, extends, err := parseV3(composefilepath)
 	if err != nil {
 		log.Warnf("Error while loading docker compose config : %s", err)
 		return irtypes.IR{}, err
 	}
 	log.Debugf("About to start loading docker compose to intermediate rep")
-	return c.convertToIR(filepath.Dir(composefilepath), *config, plan, service)
+	return c.convertToIR(filepath.Dir(composefilepath), *config, extends, plan, service)
 }
 
-func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan plantypes.Plan, service plantypes.Service) (irtypes.IR, error) {
+func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, extends map[string]string, plan plantypes.Plan, service plantypes.Service) (irtypes.IR, error) {
 	ir := irtypes.IR{
 		Services: map[string]irtypes.Service{},
 	}
@@ -159,10 +195,14 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 			//TODO: Add support for args and labels
 			// filedir, name, serviceContainer.Image, composeServiceConfig.Build.Dockerfile, composeServiceConfig.Build.Context
 
-			con, err := new(containerizer.ReuseDockerfileContainerizer).GetContainer(plan, service)
-			if err != nil {
-				log.Warnf("Unable to get containization script even though build parameters are present : %s", err)
-			} else {
+			var con irtypes.Container
+			if err := containerizer.RunServiceStep(service.ServiceName, "reuse the Dockerfile of", func() (err error) {
+				con, err = new(containerizer.ReuseDockerfileContainerizer).GetContainer(plan, service)
+				return err
+			}); err == nil {
+				if err := containerizer.CommitServiceArtifacts(service.ServiceName, con); err != nil {
+					log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+				}
 				ir.AddContainer(con)
 			}
 		}
@@ -178,7 +218,10 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 		serviceContainer.Ports = c.getPorts(composeServiceConfig.Ports, composeServiceConfig.Expose)
 		c.addPorts(composeServiceConfig.Ports, composeServiceConfig.Expose, &serviceConfig)
 
-		serviceConfig.Annotations = map[string]string(composeServiceConfig.Labels)
+		serviceConfig.Annotations = common.MergeStringMaps(composeServiceConfig.Labels, nil)
+		if description, ok := extends[composeServiceConfig.Name]; ok {
+			serviceConfig.Annotations[extendsTODOKey] = description
+		}
 		serviceConfig.Labels = common.MergeStringMaps(composeServiceConfig.Labels, composeServiceConfig.Deploy.Labels)
 		if composeServiceConfig.Hostname != "" {
 			serviceConfig.Hostname = composeServiceConfig.Hostname
@@ -232,6 +275,7 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 		if composeServiceConfig.Deploy.Mode == "global" {
 			serviceConfig.Daemon = true
 		}
+		addSwarmPlacement(&serviceConfig, composeServiceConfig.Deploy)
 
 		serviceConfig.Networks = c.getNetworks(composeServiceConfig, composeObject)
 
@@ -283,14 +327,12 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 				serviceContainer.LivenessProbe = &probe
 			}
 		}
-		restart := composeServiceConfig.Restart
-		if composeServiceConfig.Deploy.RestartPolicy != nil {
-			restart = composeServiceConfig.Deploy.RestartPolicy.Condition
-		}
-		if restart == "unless-stopped" {
-			log.Warnf("Restart policy 'unless-stopped' in service %s is not supported, convert it to 'always'", name)
-			serviceConfig.RestartPolicy = core.RestartPolicyAlways
+		addRestartPolicy(&serviceConfig, composeServiceConfig.Restart, composeServiceConfig.Deploy.RestartPolicy, len(serviceContainer.Ports) > 0)
+		if composeServiceConfig.StopGracePeriod != nil {
+			terminationGracePeriodSeconds := int64(composeServiceConfig.StopGracePeriod.Seconds())
+			serviceConfig.TerminationGracePeriodSeconds = &terminationGracePeriodSeconds
 		}
+		serviceContainer.Lifecycle = getStopSignalLifecycle(composeServiceConfig.StopSignal)
 		// replicas:
 		if composeServiceConfig.Deploy.Replicas != nil {
 			serviceConfig.Replicas = int(*composeServiceConfig.Deploy.Replicas)
@@ -304,80 +346,67 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 		serviceContainer.VolumeMounts = append(serviceContainer.VolumeMounts, vml...)
 
 		for _, secret := range composeServiceConfig.Secrets {
-			target := filepath.Join(defaultSecretBasePath, secret.Source)
-			src := secret.Source
-			if secret.Target != "" {
-				tokens := strings.Split(secret.Source, "/")
-				var prefix string
-				if !strings.HasPrefix(secret.Target, "/") {
-					prefix = defaultSecretBasePath + "/"
-				}
-				if tokens[len(tokens)-1] == secret.Target {
-					target = prefix + secret.Source
-				} else {
-					target = prefix + strings.TrimSuffix(secret.Target, "/"+tokens[len(tokens)-1])
-				}
-				src = tokens[len(tokens)-1]
+			// Secrets are mounted at /run/secrets/<source> by default. A relative target is a file name inside /run/secrets.
+			target := secret.Target
+			if target == "" {
+				target = secret.Source
 			}
-
-			vSrc := core.VolumeSource{
-				Secret: &core.SecretVolumeSource{
-					SecretName: secret.Source,
-					Items: []core.KeyToPath{{
-						Key:  secret.Source,
-						Path: src,
-					}},
-				},
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(defaultSecretBasePath, target)
+			}
+			secretObj, ok := composeObject.Secrets[secret.Source]
+			if !ok {
+				log.Errorf("Unable to find the top level secret %s used by the service %s", secret.Source, name)
+			}
+			vSrc := core.SecretVolumeSource{SecretName: getFileObjectName(secret.Source, types.FileObjectConfig(secretObj))}
+			volumeMount := core.VolumeMount{Name: common.MakeFileNameCompliant(secret.Source), MountPath: target, ReadOnly: true}
+			if isFileObjectDir(ir.Storages, irtypes.SecretKind, secret.Source, types.FileObjectConfig(secretObj)) {
+				// Every file in the directory is a key in the secret
+				log.Debugf("The secret %s is a directory. Mounting all the files in it at %s", secret.Source, target)
+			} else {
+				vSrc.Items = []core.KeyToPath{{Key: secret.Source, Path: filepath.Base(target)}}
+				volumeMount.SubPath = filepath.Base(target)
 			}
-
 			if secret.Mode != nil {
-				mode := cast.ToInt32(*secret.Mode)
-				vSrc.Secret.DefaultMode = &mode
+				mode := int32(*secret.Mode)
+				vSrc.DefaultMode = &mode
 			}
-
 			serviceConfig.AddVolume(core.Volume{
-				Name:         secret.Source,
-				VolumeSource: vSrc,
-			})
-
-			serviceContainer.VolumeMounts = append(serviceContainer.VolumeMounts, core.VolumeMount{
-				Name:      secret.Source,
-				MountPath: target,
+				Name:         volumeMount.Name,
+				VolumeSource: core.VolumeSource{Secret: &vSrc},
 			})
+			serviceContainer.VolumeMounts = append(serviceContainer.VolumeMounts, volumeMount)
 		}
 
-		for _, c := range composeServiceConfig.Configs {
-			target := c.Target
+		for _, config := range composeServiceConfig.Configs {
+			// Configs are mounted at /<source> by default
+			target := config.Target
 			if target == "" {
-				target = "/" + c.Source
+				target = "/" + config.Source
+			}
+			configObj, ok := composeObject.Configs[config.Source]
+			if !ok {
+				log.Errorf("Unable to find the top level config %s used by the service %s", config.Source, name)
 			}
 			vSrc := core.ConfigMapVolumeSource{}
-			vSrc.Name = common.MakeFileNameCompliant(c.Source)
-			if o, ok := composeObject.Configs[c.Source]; ok {
-				if o.External.External {
-					log.Errorf("Config metadata %s has an external source", c.Source)
-				} else {
-					srcBaseName := filepath.Base(o.File)
-					vSrc.Items = []core.KeyToPath{{Key: srcBaseName, Path: filepath.Base(target)}}
-					if c.Mode != nil {
-						signedMode := int32(*c.Mode)
-						vSrc.DefaultMode = &signedMode
-					}
-				}
+			vSrc.Name = getFileObjectName(config.Source, types.FileObjectConfig(configObj))
+			volumeMount := core.VolumeMount{Name: common.MakeFileNameCompliant(config.Source), MountPath: target}
+			if isFileObjectDir(ir.Storages, irtypes.ConfigMapKind, config.Source, types.FileObjectConfig(configObj)) {
+				// Every file in the directory is a key in the config map
+				log.Debugf("The config %s is a directory. Mounting all the files in it at %s", config.Source, target)
 			} else {
-				log.Errorf("Unable to find configmap object for %s", vSrc.Name)
+				vSrc.Items = []core.KeyToPath{{Key: config.Source, Path: filepath.Base(target)}}
+				volumeMount.SubPath = filepath.Base(target)
+			}
+			if config.Mode != nil {
+				mode := int32(*config.Mode)
+				vSrc.DefaultMode = &mode
 			}
 			serviceConfig.AddVolume(core.Volume{
-				Name:         vSrc.Name,
+				Name:         volumeMount.Name,
 				VolumeSource: core.VolumeSource{ConfigMap: &vSrc},
 			})
-
-			serviceContainer.VolumeMounts = append(serviceContainer.VolumeMounts,
-				core.VolumeMount{
-					Name:      vSrc.Name,
-					MountPath: target,
-					SubPath:   filepath.Base(target),
-				})
+			serviceContainer.VolumeMounts = append(serviceContainer.VolumeMounts, volumeMount)
 		}
 
 		for _, vol := range composeServiceConfig.Volumes {
@@ -394,6 +423,7 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 				volumeName := fmt.Sprintf("%s%d", common.VolumePrefix, hashID)
 				serviceContainer.VolumeMounts = append(serviceContainer.VolumeMounts, core.VolumeMount{
 					Name:      volumeName,
+					ReadOnly:  vol.ReadOnly,
 					MountPath: vol.Target,
 				})
 
@@ -430,63 +460,99 @@ func (c *V3Loader) convertToIR(filedir string, composeObject types.Config, plan
 }
 
 func (c *V3Loader) getSecretStorages(secrets map[string]types.SecretConfig) []irtypes.Storage {
-	storages := make([]irtypes.Storage, len(secrets))
+	storages := []irtypes.Storage{}
 	for secretName, secretObj := range secrets {
-		storage := irtypes.Storage{
-			Name:        secretName,
-			StorageType: irtypes.SecretKind,
-		}
-
-		if !secretObj.External.External {
-			content, err := ioutil.ReadFile(secretObj.File)
-			if err != nil {
-				log.Warnf("Could not read the secret file [%s]", secretObj.File)
-			} else {
-				storage.Content = map[string][]byte{secretName: content}
-			}
+		fileObj := types.FileObjectConfig(secretObj)
+		content, ok := c.getFileObjectContent(irtypes.SecretKind, secretName, fileObj)
+		if !ok {
+			continue
 		}
-
-		storages = append(storages, storage)
+		storages = append(storages, irtypes.Storage{
+			Name:        getFileObjectName(secretName, fileObj),
+			StorageType: irtypes.SecretKind,
+			Content:     content,
+		})
 	}
-
 	return storages
 }
 
 func (c *V3Loader) getConfigStorages(configs map[string]types.ConfigObjConfig) []irtypes.Storage {
-	Storages := make([]irtypes.Storage, len(configs))
-
+	storages := []irtypes.Storage{}
 	for cfgName, cfgObj := range configs {
-		storage := irtypes.Storage{
-			Name:        cfgName,
+		fileObj := types.FileObjectConfig(cfgObj)
+		content, ok := c.getFileObjectContent(irtypes.ConfigMapKind, cfgName, fileObj)
+		if !ok {
+			continue
+		}
+		storages = append(storages, irtypes.Storage{
+			Name:        getFileObjectName(cfgName, fileObj),
 			StorageType: irtypes.ConfigMapKind,
+			Content:     content,
+		})
+	}
+	return storages
+}
+
+// getFileObjectContent returns the content of a top level secret or config keyed by its name, or by the file names if it is a directory.
+// The content of an external object is not available, so the user is asked for a file containing it.
+// If no file is given, false is returned and the object is expected to be created in the cluster before deploying.
+func (c *V3Loader) getFileObjectContent(kind irtypes.StorageKindType, name string, fileObj types.FileObjectConfig) (map[string][]byte, bool) {
+	path := fileObj.File
+	if fileObj.External.External {
+		key := common.ConfigStoragesKey + common.Delim + `"` + name + `"` + common.Delim + common.ConfigStoragesContentPathKeySegment
+		desc := fmt.Sprintf("Enter the path of the file containing the external %s %s:", strings.ToLower(string(kind)), name)
+		hints := []string{fmt.Sprintf("Leave it empty if the %s %s will be created in the cluster before deploying.", kind, getFileObjectName(name, fileObj))}
+		path = strings.TrimSpace(qaengine.FetchStringAnswer(key, desc, hints, ""))
+		if path == "" {
+			log.Infof("The %s %s is external. It should be created in the cluster with the key %s before deploying.", kind, getFileObjectName(name, fileObj), name)
+			return nil, false
+		}
+	}
+	if isDir(path) {
+		dataMap, err := c.getAllDirContentAsMap(path)
+		if err != nil {
+			log.Warnf("Could not read the %s directory [%s]. Encountered [%s]", kind, path, err)
+			return nil, true
 		}
+		return dataMap, true
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Warnf("Could not read the %s file [%s]. Encountered [%s]", kind, path, err)
+		return nil, true
+	}
+	return map[string][]byte{name: content}, true
+}
 
-		if !cfgObj.External.External {
-			fileInfo, err := os.Stat(cfgObj.File)
-			if err != nil {
-				log.Warnf("Could not identify the type of secret artifact [%s]. Encountered [%s]", cfgObj.File, err)
-			} else {
-				if !fileInfo.IsDir() {
-					content, err := ioutil.ReadFile(cfgObj.File)
-					if err != nil {
-						log.Warnf("Could not read the secret file [%s]. Encountered [%s]", cfgObj.File, err)
-					} else {
-						storage.Content = map[string][]byte{cfgName: content}
-					}
-				} else {
-					dataMap, err := c.getAllDirContentAsMap(cfgObj.File)
-					if err != nil {
-						log.Warnf("Could not read the secret directory [%s]. Encountered [%s]", cfgObj.File, err)
-					} else {
-						storage.Content = dataMap
-					}
-				}
-			}
+// getFileObjectName returns the name of the kubernetes resource for a top level secret or config
+func getFileObjectName(name string, fileObj types.FileObjectConfig) string {
+	if fileObj.External.External && fileObj.External.Name != "" {
+		name = fileObj.External.Name
+	}
+	return common.MakeFileNameCompliant(name)
+}
+
+// isFileObjectDir returns true if the content of a top level secret or config was read from a directory.
+// The content is then keyed by the file names instead of the name of the object, so the whole object has to be mounted.
+// The content of an external object may have been read from the directory given by the user, so the file in the compose file is not enough.
+func isFileObjectDir(storages []irtypes.Storage, kind irtypes.StorageKindType, name string, fileObj types.FileObjectConfig) bool {
+	storageName := getFileObjectName(name, fileObj)
+	for _, storage := range storages {
+		if storage.StorageType != kind || storage.Name != storageName {
+			continue
 		}
-		Storages = append(Storages, storage)
+		_, ok := storage.Content[name]
+		return !ok && storage.Content != nil
 	}
+	return false
+}
 
-	return Storages
+func isDir(path string) bool {
+	if path == "" {
+		return false
+	}
+	fileInfo, err := os.Stat(path)
+	return err == nil && fileInfo.IsDir()
 }
 
 func (*V3Loader) getPorts(ports []types.ServicePortConfig, expose []string) []core.ContainerPort {
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatalf("Failed to create the directory for the file %s . Error: %q", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write the file %s . Error: %q", path, err)
+		}
+	}
+}
+
+func TestConvertSecretsAndConfigsToIR(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"dbpass.txt":            "password",
+		"nginx/nginx.conf":      "server {}",
+		"nginx/mime.types":      "types {}",
+		"apikeys/public.key":    "public",
+		"apikeys/private.key":   "private",
+		"m2kconfig/config.yaml": "move2kube:\n  storages:\n    apikey:\n      contentpath: " + filepath.Join(dir, "apikeys") + "\n",
+	})
+	qaengine.AddEngine(qaengine.NewDefaultEngine())
+	qaengine.SetupConfigFile(t.TempDir(), nil, []string{filepath.Join(dir, "m2kconfig", "config.yaml")}, nil)
+
+	composeObject := types.Config{
+		Services: []types.ServiceConfig{{
+			Name:  "web",
+			Image: "web:latest",
+			Secrets: []types.ServiceSecretConfig{
+				{Source: "dbpass"},
+				{Source: "apikey", Target: "keys"},
+			},
+			Configs: []types.ServiceConfigObjConfig{
+				{Source: "nginx", Target: "/etc/nginx"},
+				{Source: "appconf"},
+			},
+		}},
+		Secrets: map[string]types.SecretConfig{
+			"dbpass": {File: filepath.Join(dir, "dbpass.txt")},
+			"apikey": {External: types.External{External: true, Name: "prod-apikey"}},
+		},
+		Configs: map[string]types.ConfigObjConfig{
+			"nginx":   {File: filepath.Join(dir, "nginx")},
+			"appconf": {External: types.External{External: true}},
+		},
+	}
+	ir, err := new(V3Loader).convertToIR(dir, composeObject, nil, plantypes.NewPlan(), plantypes.Service{ServiceName: "web"})
+	if err != nil {
+		t.Fatalf("Failed to convert the compose file to IR. Error: %q", err)
+	}
+
+	wantStorages := map[string]irtypes.Storage{
+		"dbpass":      {Name: "dbpass", StorageType: irtypes.SecretKind, Content: map[string][]byte{"dbpass": []byte("password")}},
+		"prod-apikey": {Name: "prod-apikey", StorageType: irtypes.SecretKind, Content: map[string][]byte{"public.key": []byte("public"), "private.key": []byte("private")}},
+		"nginx":       {Name: "nginx", StorageType: irtypes.ConfigMapKind, Content: map[string][]byte{"nginx.conf": []byte("server {}"), "mime.types": []byte("types {}")}},
+	}
+	if len(ir.Storages) != len(wantStorages) {
+		t.Fatalf("Expected the storages %+v . Actual: %+v", wantStorages, ir.Storages)
+	}
+	for _, storage := range ir.Storages {
+		if want, ok := wantStorages[storage.Name]; !ok || !reflect.DeepEqual(storage, want) {
+			t.Fatalf("Expected the storage %+v . Actual: %+v", want, storage)
+		}
+	}
+
+	service, ok := ir.Services["web"]
+	if !ok || len(service.Containers) != 1 {
+		t.Fatalf("Expected the service web with one container. Actual: %+v", ir.Services)
+	}
+	wantMounts := []core.VolumeMount{
+		{Name: "dbpass", MountPath: "/run/secrets/dbpass", ReadOnly: true, SubPath: "dbpass"},
+		{Name: "apikey", MountPath: "/run/secrets/keys", ReadOnly: true},
+		{Name: "nginx", MountPath: "/etc/nginx"},
+		{Name: "appconf", MountPath: "/appconf", SubPath: "appconf"},
+	}
+	if !reflect.DeepEqual(service.Containers[0].VolumeMounts, wantMounts) {
+		t.Fatalf("Expected the volume mounts %+v . Actual: %+v", wantMounts, service.Containers[0].VolumeMounts)
+	}
+	wantVolumes := []core.Volume{
+		{Name: "dbpass", VolumeSource: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: "dbpass", Items: []core.KeyToPath{{Key: "dbpass", Path: "dbpass"}}}}},
+		{Name: "apikey", VolumeSource: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: "prod-apikey"}}},
+		{Name: "nginx", VolumeSource: core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: "nginx"}}}},
+		{Name: "appconf", VolumeSource: core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: "appconf"}, Items: []core.KeyToPath{{Key: "appconf", Path: "appconf"}}}}},
+	}
+	if !reflect.DeepEqual(service.Volumes, wantVolumes) {
+		t.Fatalf("Expected the volumes %+v . Actual: %+v", wantVolumes, service.Volumes)
+	}
+}
@@ -98,6 +98,9 @@ func (c *ComposeTranslator) getServicesFromComposeFile(composeFilePath string, i
 		log.Debugf("Found a docker compose file at path %s", composeFilePath)
 		for _, service := range dc.Services {
 			currServices := c.getReuseAndReuseDockerfileServices(composeFilePath, service.Name, service.Image, service.Build.Context, service.Build.Dockerfile, imageMetadataPaths)
+			addContainerPortsToServices(currServices, compose.GetV3ContainerPorts(service))
+			setRuntimeOfServices(currServices, compose.GetV3Runtime(service))
+			setDependsOnOfServices(currServices, compose.GetV3DependsOn(service))
 			services = append(services, currServices...)
 		}
 	} else if dc, errV1V2 := compose.ParseV2(composeFilePath); errV1V2 == nil {
@@ -105,6 +108,9 @@ func (c *ComposeTranslator) getServicesFromComposeFile(composeFilePath string, i
 		servicesMap := dc.ServiceConfigs.All()
 		for serviceName, service := range servicesMap {
 			currServices := c.getReuseAndReuseDockerfileServices(composeFilePath, serviceName, service.Image, service.Build.Context, service.Build.Dockerfile, imageMetadataPaths)
+			addContainerPortsToServices(currServices, compose.GetV1V2ContainerPorts(service))
+			setRuntimeOfServices(currServices, compose.GetV1V2Runtime(service))
+			setDependsOnOfServices(currServices, compose.GetV1V2DependsOn(service))
 			services = append(services, currServices...)
 		}
 	} else {
@@ -127,20 +133,19 @@ func (c *ComposeTranslator) GetServiceOptions(inputPath string, plan plantypes.P
 		return nil, err
 	}
 
-	imageMetadataPaths := map[string]string{}
-	for _, path := range yamlpaths {
-		im := collecttypes.ImageInfo{}
-		if err := common.ReadMove2KubeYaml(path, &im); err != nil || im.Kind != string(collecttypes.ImageMetadataKind) {
-			continue
-		}
-		for _, imagetag := range im.Spec.Tags {
-			imageMetadataPaths[imagetag] = path
-		}
+	imageMetadataPaths, err := getImageMetadataPaths(inputPath)
+	if err != nil {
+		return nil, err
 	}
 
 	//Fill data into plan
 	services := []plantypes.Service{}
 	for _, path := range yamlpaths {
+		// The Docker Swarm stack files are planned by the SwarmTranslator
+		if compose.IsV3SwarmStack(path) {
+			log.Debugf("Skipping the Docker Swarm stack file at path %s", path)
+			continue
+		}
 		currServices := c.getServicesFromComposeFile(path, imageMetadataPaths)
 		services = append(services, currServices...)
 	}
@@ -148,6 +153,26 @@ func (c *ComposeTranslator) GetServiceOptions(inputPath string, plan plantypes.P
 	return services, nil
 }
 
+// getImageMetadataPaths returns the paths of the image metadata collected in the directory, keyed by the image tags
+func getImageMetadataPaths(inputPath string) (map[string]string, error) {
+	metadataPaths, err := common.GetFilesByExt(inputPath, common.Move2KubeFileExts)
+	if err != nil {
+		log.Errorf("Unable to fetch the image metadata files at path %s Error: %q", inputPath, err)
+		return nil, err
+	}
+	imageMetadataPaths := map[string]string{}
+	for _, path := range metadataPaths {
+		im := collecttypes.ImageInfo{}
+		if err := common.ReadMove2KubeYaml(path, &im); err != nil || im.Kind != string(collecttypes.ImageMetadataKind) {
+			continue
+		}
+		for _, imagetag := range im.Spec.Tags {
+			imageMetadataPaths[imagetag] = path
+		}
+	}
+	return imageMetadataPaths, nil
+}
+
 // Translate translates the service to IR
 func (c *ComposeTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
 	ir := irtypes.NewIR(plan)
@@ -170,15 +195,20 @@ func (c *ComposeTranslator) Translate(services []plantypes.Service, plan plantyp
 				log.Errorf("Unable to parse the docker compose file at path %s Error V3: %q Error V1V2: %q", path, errV3, errV1V2)
 			}
 		}
-		for _, path := range service.SourceArtifacts[plantypes.ImageInfoArtifactType] {
-			imgMD := collecttypes.ImageInfo{}
-			if err := common.ReadMove2KubeYaml(path, &imgMD); err != nil {
-				log.Errorf("Failed to read image info yaml at path %s Error: %q", path, err)
-				continue
-			}
-			ir.AddContainer(irtypes.NewContainerFromImageInfo(imgMD))
-		}
+		addImageInfoContainers(&ir, service)
 	}
 
 	return ir, nil
 }
+
+// addImageInfoContainers adds the containers of the image metadata collected for the service
+func addImageInfoContainers(ir *irtypes.IR, service plantypes.Service) {
+	for _, path := range service.SourceArtifacts[plantypes.ImageInfoArtifactType] {
+		imgMD := collecttypes.ImageInfo{}
+		if err := common.ReadMove2KubeYaml(path, &imgMD); err != nil {
+			log.Errorf("Failed to read image info yaml at path %s Error: %q", path, err)
+			continue
+		}
+		ir.AddContainer(irtypes.NewContainerFromImageInfo(imgMD))
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	dockerparser "github.com/moby/buildkit/frontend/dockerfile/parser"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+var (
+	// cronInstallRegex matches the commands installing cron using the package managers
+	cronInstallRegex = regexp.MustCompile(`\b(apt-get|apt|apk|yum|dnf|microdnf)\s+(install|add)\b[^&;|]*\b(cron|cronie|dcron|cronie-noanacron)\b`)
+	// cronRunRegex matches the commands running the cron daemon
+	cronRunRegex = regexp.MustCompile(`\b(cron|crond)\b`)
+	// crontabInstallRegex matches the commands installing a crontab file, like crontab /etc/cron.d/jobs
+	crontabInstallRegex = regexp.MustCompile(`\bcrontab\s+(-u\s+\S+\s+)?([^\s;&|-]\S*)`)
+	// crontabEnvRegex matches the environment variables set in a crontab
+	crontabEnvRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+	// cronOutputRegex matches the redirection of the output of a cron command to a file, which is not needed since the logs of the pods are collected
+	cronOutputRegex = regexp.MustCompile(`\s*(\d?>>?|&>>?)\s*\S+(\s+2>&1)?\s*$`)
+	// systemCrontabDirs are the directories of the system crontabs, whose entries contain the user running the command
+	systemCrontabDirs = []string{"/etc/crontab", "/etc/cron.d"}
+	// userCrontabDirs are the directories of the user crontabs
+	userCrontabDirs = []string{"/etc/crontabs", "/var/spool/cron"}
+	// crontabDirs are the directories into which the crontabs are copied
+	crontabDirs = []string{"/etc/cron.d", "/etc/crontabs", "/var/spool/cron", "/var/spool/cron/crontabs"}
+	// cronMacros are the schedule macros supported by Kubernetes CronJobs
+	cronMacros = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+	// ignoredCrontabEnvs are the environment variables of the crontabs that only configure cron
+	ignoredCrontabEnvs = []string{"SHELL", "MAILTO", "CRON_TZ"}
+)
+
+// getCronEntries returns the entries of the crontabs copied into the image of the service, if the Dockerfile of the service installs or runs cron
+func getCronEntries(service plantypes.Service) []irtypes.CronEntry {
+	entries := []irtypes.CronEntry{}
+	for _, dockerfilePath := range getServiceDockerfiles(service) {
+		crontabs := getDockerfileCrontabs(dockerfilePath, getBuildContextDir(service, dockerfilePath))
+		imagePaths := []string{}
+		for imagePath := range crontabs {
+			imagePaths = append(imagePaths, imagePath)
+		}
+		sort.Strings(imagePaths)
+		for _, imagePath := range imagePaths {
+			crontabPath := crontabs[imagePath]
+			crontabEntries, err := parseCrontab(crontabPath, isPathIn(imagePath, systemCrontabDirs))
+			if err != nil {
+				log.Debugf("Failed to parse the crontab at path %s . Error: %q", crontabPath, err)
+				continue
+			}
+			entries = append(entries, crontabEntries...)
+		}
+	}
+	return entries
+}
+
+// getServiceDockerfiles returns the Dockerfiles which are used to build the image of the service
+func getServiceDockerfiles(service plantypes.Service) []string {
+	dockerfilePaths := append([]string{}, service.SourceArtifacts[plantypes.DockerfileArtifactType]...)
+	if service.ContainerBuildType == plantypes.ReuseDockerFileContainerBuildTypeValue && len(service.ContainerizationTargetOptions) > 0 && !common.IsStringPresent(dockerfilePaths, service.ContainerizationTargetOptions[0]) {
+		dockerfilePaths = append(dockerfilePaths, service.ContainerizationTargetOptions[0])
+	}
+	return dockerfilePaths
+}
+
+// getBuildContextDir returns the directory of the build context of the Dockerfile
+func getBuildContextDir(service plantypes.Service, dockerfilePath string) string {
+	if sourceDirs := service.BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType]; len(sourceDirs) > 0 {
+		return sourceDirs[0]
+	}
+	return filepath.Dir(dockerfilePath)
+}
+
+// getDockerfileCrontabs returns the crontabs copied into the image in the final stage of the Dockerfile, if the stage installs or runs cron.
+// The crontabs are returned as [path in the image][path in the build context].
+func getDockerfileCrontabs(dockerfilePath, contextDir string) map[string]string {
+	crontabs := map[string]string{}
+	f, err := os.Open(dockerfilePath)
+	if err != nil {
+		log.Debugf("Unable to open file %s : %s", dockerfilePath, err)
+		return crontabs
+	}
+	defer f.Close()
+	res, err := dockerparser.Parse(f)
+	if err != nil {
+		log.Debugf("Unable to parse file %s as Docker files : %s", dockerfilePath, err)
+		return crontabs
+	}
+	usesCron := false
+	copiedFiles := map[string]string{} // [path in the image][path in the build context]
+	installedCrontabs := []string{}
+	for _, dfchild := range res.AST.Children {
+		args := []string{}
+		for n := dfchild.Next; n != nil; n = n.Next {
+			args = append(args, n.Value)
+		}
+		switch dfchild.Value {
+		case "from":
+			usesCron = false
+			copiedFiles = map[string]string{}
+			installedCrontabs = []string{}
+		case "run":
+			command := strings.Join(args, " ")
+			if cronInstallRegex.MatchString(command) {
+				usesCron = true
+			}
+			for _, match := range crontabInstallRegex.FindAllStringSubmatch(command, -1) {
+				installedCrontabs = append(installedCrontabs, match[2])
+			}
+		case "cmd", "entrypoint":
+			if cronRunRegex.MatchString(strings.Join(args, " ")) {
+				usesCron = true
+			}
+		case "copy", "add":
+			if len(args) < 2 || hasCopyFromFlag(dfchild.Flags) {
+				continue
+			}
+			dest := args[len(args)-1]
+			for _, src := range args[:len(args)-1] {
+				for imagePath, contextPath := range getCopiedFiles(filepath.Join(contextDir, filepath.FromSlash(src)), dest, len(args) > 2, crontabDirs) {
+					copiedFiles[imagePath] = contextPath
+				}
+			}
+		}
+	}
+	if !usesCron {
+		return crontabs
+	}
+	for imagePath, contextPath := range copiedFiles {
+		if isPathIn(imagePath, systemCrontabDirs) || isPathIn(imagePath, userCrontabDirs) || common.IsStringPresent(installedCrontabs, imagePath) {
+			crontabs[imagePath] = contextPath
+		}
+	}
+	return crontabs
+}
+
+// getCopiedFiles returns the files copied into the image by a COPY or ADD instruction, as [path in the image][path in the build context].
+// The files of a directory are copied into the destination directory, as well as the files copied into one of the dirs.
+func getCopiedFiles(src, dest string, multipleSrcs bool, dirs []string) map[string]string {
+	files := map[string]string{}
+	info, err := os.Stat(src)
+	if err != nil {
+		log.Debugf("Failed to stat the file %s copied into the image. Error: %q", src, err)
+		return files
+	}
+	if !info.IsDir() {
+		imagePath := dest
+		if strings.HasSuffix(dest, "/") || multipleSrcs || common.IsStringPresent(dirs, path.Clean(dest)) {
+			imagePath = path.Join(dest, filepath.Base(src))
+		}
+		files[path.Clean(imagePath)] = src
+		return files
+	}
+	fileInfos, err := ioutil.ReadDir(src)
+	if err != nil {
+		log.Debugf("Failed to read the directory %s copied into the image. Error: %q", src, err)
+		return files
+	}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			files[path.Join(dest, fileInfo.Name())] = filepath.Join(src, fileInfo.Name())
+		}
+	}
+	return files
+}
+
+// hasCopyFromFlag checks if the COPY instruction copies from another stage or image
+func hasCopyFromFlag(flags []string) bool {
+	for _, flag := range flags {
+		if strings.HasPrefix(flag, "--from") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCrontab returns the entries of the crontab. The entries of the system crontabs contain the user running the command after the schedule.
+// The entries run at reboot are skipped, since they have no equivalent schedule.
+func parseCrontab(crontabPath string, isSystemCrontab bool) ([]irtypes.CronEntry, error) {
+	f, err := os.Open(crontabPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries := []irtypes.CronEntry{}
+	envs := []core.EnvVar{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := crontabEnvRegex.FindStringSubmatch(line); match != nil {
+			if !common.IsStringPresent(ignoredCrontabEnvs, match[1]) {
+				envs = append(envs, core.EnvVar{Name: match[1], Value: unquoteDockerfileValue(strings.TrimSpace(match[2]))})
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		scheduleFields := 5
+		if strings.HasPrefix(fields[0], "@") {
+			if !common.IsStringPresent(cronMacros, fields[0]) {
+				log.Warnf("Skipping the entry %q of the crontab %s, since it can't be run by a CronJob", line, crontabPath)
+				continue
+			}
+			scheduleFields = 1
+		}
+		commandField := scheduleFields
+		if isSystemCrontab {
+			commandField++
+		}
+		if len(fields) <= commandField {
+			log.Debugf("Skipping the invalid entry %q of the crontab %s", line, crontabPath)
+			continue
+		}
+		// The command is taken from the line, to keep its spacing
+		command := line
+		for i := 0; i < commandField; i++ {
+			command = strings.TrimSpace(strings.TrimPrefix(command, fields[i]))
+		}
+		entries = append(entries, irtypes.CronEntry{
+			Schedule: strings.Join(fields[:scheduleFields], " "),
+			Command:  cronOutputRegex.ReplaceAllString(command, ""),
+			Env:      append([]core.EnvVar{}, envs...),
+		})
+	}
+	return entries, scanner.Err()
+}
+
+// isPathIn checks if the path is one of the paths or inside one of them
+func isPathIn(p string, paths []string) bool {
+	for _, dir := range paths {
+		if p == dir || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetCronEntries(t *testing.T) {
+	crontab := `SHELL=/bin/bash
+PATH=/usr/local/bin:/usr/bin:/bin
+# Clean up the temporary files
+*/15 * * * * root /app/cleanup.sh --older-than 1h >> /var/log/cron.log 2>&1
+@daily root /app/report.sh
+@reboot root /app/warmup.sh
+`
+	testcases := []struct {
+		name       string
+		dockerfile string
+		want       []irtypes.CronEntry
+	}{
+		{
+			name:       "cron is installed and the crontab is copied into cron.d",
+			dockerfile: "FROM debian:buster\nRUN apt-get update && apt-get install -y cron\nCOPY jobs /etc/cron.d/\nCMD [\"cron\", \"-f\"]\n",
+			want: []irtypes.CronEntry{
+				{Schedule: "*/15 * * * *", Command: "/app/cleanup.sh --older-than 1h", Env: []core.EnvVar{{Name: "PATH", Value: "/usr/local/bin:/usr/bin:/bin"}}},
+				{Schedule: "@daily", Command: "/app/report.sh", Env: []core.EnvVar{{Name: "PATH", Value: "/usr/local/bin:/usr/bin:/bin"}}},
+			},
+		},
+		{
+			name:       "cron is not used",
+			dockerfile: "FROM debian:buster\nCOPY jobs /etc/cron.d/\nCMD [\"/app/server\"]\n",
+			want:       []irtypes.CronEntry{},
+		},
+		{
+			name:       "cron is used only in the build stage",
+			dockerfile: "FROM debian:buster AS build\nRUN apt-get install -y cron\nCOPY jobs /etc/cron.d/\nFROM debian:buster\nCMD [\"/app/server\"]\n",
+			want:       []irtypes.CronEntry{},
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := writeSessionHintFiles(t, map[string]string{"Dockerfile": testcase.dockerfile, "jobs": crontab})
+			service := plantypes.Service{SourceArtifacts: map[plantypes.SourceArtifactTypeValue][]string{plantypes.DockerfileArtifactType: {filepath.Join(dir, "Dockerfile")}}}
+			if entries := getCronEntries(service); !reflect.DeepEqual(entries, testcase.want) {
+				t.Fatalf("Failed to get the crontab entries properly. Expected: %+v Actual: %+v", testcase.want, entries)
+			}
+		})
+	}
+}
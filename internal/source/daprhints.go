@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/dapr"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxDaprHintFileSize is the size above which files are not searched for Dapr hints
+	maxDaprHintFileSize = 1024 * 1024
+)
+
+// daprSDKPatterns are the patterns, per dependency file name or extension, that show that the app uses a Dapr SDK
+var daprSDKPatterns = map[string]daprSDKPattern{
+	"package.json":     {hint: "Dapr JavaScript SDK", pattern: regexp.MustCompile(`"(@dapr/dapr|dapr-client)"\s*:`)},
+	"requirements.txt": {hint: "Dapr Python SDK", pattern: regexp.MustCompile(`(?m)^\s*dapr(-ext-[\w-]+)?\s*([=<>~!]|$)`)},
+	"pyproject.toml":   {hint: "Dapr Python SDK", pattern: regexp.MustCompile(`(?m)^\s*"?dapr(-ext-[\w-]+)?"?\s*[=<>~!"]`)},
+	"pom.xml":          {hint: "Dapr Java SDK", pattern: regexp.MustCompile(`<groupId>\s*io\.dapr\s*</groupId>`)},
+	"build.gradle":     {hint: "Dapr Java SDK", pattern: regexp.MustCompile(`['"]io\.dapr:`)},
+	"build.gradle.kts": {hint: "Dapr Java SDK", pattern: regexp.MustCompile(`"io\.dapr:`)},
+	"go.mod":           {hint: "Dapr Go SDK", pattern: regexp.MustCompile(`github\.com/dapr/go-sdk\b`)},
+	".csproj":          {hint: "Dapr .NET SDK", pattern: regexp.MustCompile(`Include="Dapr\.(Client|AspNetCore|Actors)`)},
+}
+
+type daprSDKPattern struct {
+	hint    string
+	pattern *regexp.Regexp
+}
+
+// daprComponentPattern matches the yaml files defining Dapr components, which are used when running the app locally
+var daprComponentPattern = regexp.MustCompile(`(?m)^apiVersion:\s*['"]?` + regexp.QuoteMeta(dapr.SchemeGroupVersion.String()) + `['"]?\s*$`)
+
+// daprComponentFile is the subset of a Dapr component read from the source
+type daprComponentFile struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Type     string `yaml:"type"`
+		Version  string `yaml:"version"`
+		Metadata []struct {
+			Name         string `yaml:"name"`
+			Value        string `yaml:"value"`
+			SecretKeyRef struct {
+				Name string `yaml:"name"`
+				Key  string `yaml:"key"`
+			} `yaml:"secretKeyRef"`
+		} `yaml:"metadata"`
+	} `yaml:"spec"`
+}
+
+// getDaprHints looks for the Dapr SDKs in the dependencies of a service, and for the Dapr components defined in its source
+func getDaprHints(service plantypes.Service) ([]string, []irtypes.DaprComponent) {
+	hints := []string{}
+	components := []irtypes.DaprComponent{}
+	for _, dir := range service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] {
+		dirHints, dirComponents := getDaprHintsInDir(dir)
+		for _, hint := range dirHints {
+			if !common.IsStringPresent(hints, hint) {
+				hints = append(hints, hint)
+			}
+		}
+		components = append(components, dirComponents...)
+	}
+	sort.Strings(hints)
+	return hints, components
+}
+
+func getDaprHintsInDir(dir string) ([]string, []irtypes.DaprComponent) {
+	hints := []string{}
+	components := []irtypes.DaprComponent{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Skipping the path %s while looking for Dapr hints. Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && common.IsStringPresent(sessionHintSkipDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Size() > maxDaprHintFileSize {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		pattern, ok := daprSDKPatterns[info.Name()]
+		if !ok {
+			pattern, ok = daprSDKPatterns[ext]
+		}
+		if !ok && ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Debugf("Failed to read the file at path %s while looking for Dapr hints. Error: %q", path, err)
+			return nil
+		}
+		if ok {
+			if !common.IsStringPresent(hints, pattern.hint) && pattern.pattern.Match(content) {
+				hints = append(hints, pattern.hint)
+			}
+			return nil
+		}
+		if !daprComponentPattern.Match(content) {
+			return nil
+		}
+		component, ok := readDaprComponent(path, content)
+		if !ok {
+			return nil
+		}
+		components = append(components, component)
+		if hint := "Dapr component " + component.Name + " (" + component.Type + ")"; !common.IsStringPresent(hints, hint) {
+			hints = append(hints, hint)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("Failed to look for Dapr hints in the directory %s . Error: %q", dir, err)
+	}
+	return hints, components
+}
+
+// readDaprComponent reads a Dapr component file of the source. The metadata is kept, since it may be reused on the target cluster.
+func readDaprComponent(path string, content []byte) (irtypes.DaprComponent, bool) {
+	componentFile := daprComponentFile{}
+	if err := yaml.Unmarshal(content, &componentFile); err != nil {
+		log.Debugf("Failed to parse the Dapr component at path %s . Error: %q", path, err)
+		return irtypes.DaprComponent{}, false
+	}
+	if componentFile.Kind != dapr.ComponentKind || componentFile.Metadata.Name == "" || componentFile.Spec.Type == "" {
+		return irtypes.DaprComponent{}, false
+	}
+	component := irtypes.DaprComponent{
+		Name:    componentFile.Metadata.Name,
+		Type:    strings.TrimSpace(componentFile.Spec.Type),
+		Version: componentFile.Spec.Version,
+	}
+	for _, metadata := range componentFile.Spec.Metadata {
+		component.Metadata = append(component.Metadata, irtypes.DaprMetadata{
+			Name:       metadata.Name,
+			Value:      metadata.Value,
+			SecretName: metadata.SecretKeyRef.Name,
+			SecretKey:  metadata.SecretKeyRef.Key,
+		})
+	}
+	return component, true
+}
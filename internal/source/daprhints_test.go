@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetDaprHints(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"package.json":  `{"dependencies": {"@dapr/dapr": "^3.0.0", "express": "^4.17.1"}}`,
+		"worker/go.mod": "module example.com/worker\n\nrequire github.com/dapr/go-sdk v1.8.0\n",
+		"components/redis.yaml": `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+  version: v1
+  metadata:
+    - name: redisHost
+      value: localhost:6379
+    - name: redisPassword
+      secretKeyRef:
+        name: redis
+        key: password
+`,
+		"deploy/service.yaml":            "apiVersion: v1\nkind: Service\nmetadata:\n  name: tickets\n",
+		"node_modules/dapr/package.json": `{"dependencies": {"dapr-client": "1.0.0"}}`,
+	})
+	service := plantypes.NewService("tickets", plantypes.Any2KubeTranslation)
+	service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] = []string{dir}
+	hints, components := getDaprHints(service)
+	wantHints := []string{"Dapr Go SDK", "Dapr JavaScript SDK", "Dapr component statestore (state.redis)"}
+	if !reflect.DeepEqual(hints, wantHints) {
+		t.Fatalf("Failed to get the Dapr hints. Expected: %v Actual: %v", wantHints, hints)
+	}
+	wantComponents := []irtypes.DaprComponent{{
+		Name:    "statestore",
+		Type:    "state.redis",
+		Version: "v1",
+		Metadata: []irtypes.DaprMetadata{
+			{Name: "redisHost", Value: "localhost:6379"},
+			{Name: "redisPassword", SecretName: "redis", SecretKey: "password"},
+		},
+	}}
+	if !reflect.DeepEqual(components, wantComponents) {
+		t.Fatalf("Failed to read the Dapr components. Expected: %+v Actual: %+v", wantComponents, components)
+	}
+
+	noDapr := writeSessionHintFiles(t, map[string]string{"requirements.txt": "flask==2.0.1\ndaprlike==1.0\n"})
+	service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] = []string{noDapr}
+	if hints, components := getDaprHints(service); len(hints) != 0 || len(components) != 0 {
+		t.Fatalf("Expected no Dapr hints. Actual: %v %v", hints, components)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
@@ -59,6 +60,10 @@ func (dockerfileTranslator *DockerfileTranslator) GetServiceOptions(inputPath st
 			ns.AddSourceArtifact(plantypes.DockerfileArtifactType, p)
 			ns.ContainerizationTargetOptions = append(ns.ContainerizationTargetOptions, p)
 		}
+		ns.Detection = containerizer.GetDetection(ns.ContainerBuildType, relpath, ns.ContainerizationTargetOptions, nil)
+		for _, port := range getDockerfilePorts(dfs[0].path) {
+			ns.AddPort(port)
+		}
 		if foundRepo, err := ns.GatherGitInfo(dfs[0].path, plan); foundRepo && err != nil {
 			log.Warnf("Error while parsing the git repo at path %q Error: %q", dfs[0].path, err)
 		}
@@ -80,26 +85,28 @@ func (dockerfileTranslator *DockerfileTranslator) Translate(services []plantypes
 			continue
 		}
 		log.Debugf("Translating %s", service.ServiceName)
-		irContainer, err := new(containerizer.ReuseDockerfileContainerizer).GetContainer(plan, service)
-		if err != nil {
-			log.Warnf("Unable to get reuse the Dockerfile for service %s even though build parameters are present. Error: %q", service.ServiceName, err)
+		var irContainer irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "reuse the Dockerfile of", func() (err error) {
+			irContainer, err = new(containerizer.ReuseDockerfileContainerizer).GetContainer(plan, service)
+			return err
+		}); err != nil {
 			continue
 		}
 		irContainer.RepoInfo = service.RepoInfo
 		irContainer.RepoInfo.TargetPath = service.ContainerizationTargetOptions[0]
+		if err := containerizer.CommitServiceArtifacts(service.ServiceName, irContainer); err != nil {
+			log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+		}
 		ir.AddContainer(irContainer)
 
 		irService := irtypes.NewServiceFromPlanService(service)
+		// The entrypoint, the cmd, the env vars, the working directory and the user of the Dockerfile are built into the image,
+		// so only the runtime of the plan is added to the container
 		container := core.Container{Name: service.ServiceName, Image: service.Image}
+		irService.Containers = []core.Container{container}
 		for _, port := range irContainer.ExposedPorts {
-			// Add the port to the k8s pod.
-			container.Ports = append(container.Ports, core.ContainerPort{ContainerPort: int32(port)})
-			// Forward the port on the k8s service to the k8s pod.
-			podPort := irtypes.Port{Number: int32(port)}
-			servicePort := podPort
-			irService.AddPortForwarding(servicePort, podPort)
+			irService.AddContainerPort(0, core.ContainerPort{ContainerPort: int32(port)})
 		}
-		irService.Containers = []core.Container{container}
 		ir.Services[service.ServiceName] = irService
 	}
 	return ir, nil
@@ -114,6 +121,62 @@ func (dockerfileTranslator *DockerfileTranslator) newService(serviceName string)
 	return service
 }
 
+// getDockerfilePorts returns the ports exposed in the final stage of the Dockerfile
+func getDockerfilePorts(path string) []plantypes.Port {
+	ports := []plantypes.Port{}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Debugf("Unable to open file %s : %s", path, err)
+		return ports
+	}
+	defer f.Close()
+	res, err := dockerparser.Parse(f)
+	if err != nil {
+		log.Debugf("Unable to parse file %s as Docker files : %s", path, err)
+		return ports
+	}
+	for _, dfchild := range res.AST.Children {
+		if dfchild.Value == "from" {
+			ports = []plantypes.Port{}
+			continue
+		}
+		if dfchild.Value != "expose" {
+			continue
+		}
+		// The arguments of EXPOSE are ports with an optional protocol, like 8080 or 53/udp
+		for n := dfchild.Next; n != nil; n = n.Next {
+			parts := strings.SplitN(n.Value, "/", 2)
+			number, err := strconv.ParseInt(parts[0], 10, 32)
+			if err != nil {
+				log.Debugf("Unable to parse the port %s in the Dockerfile %s", n.Value, path)
+				continue
+			}
+			port := plantypes.Port{ContainerPort: int32(number)}
+			if len(parts) == 2 {
+				port.Protocol = plantypes.PortProtocolTypeValue(strings.ToUpper(parts[1]))
+			}
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// unquoteDockerfileValue removes the quotes around a value in a Dockerfile
+func unquoteDockerfileValue(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	if value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	if value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
 func isDockerFile(path string) (isDockerfile bool, err error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,549 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// ecsTaskDefinitionARNPrefix precedes the family and the revision in the ARN of a task definition
+	ecsTaskDefinitionARNPrefix = "task-definition/"
+	// ecsCPUUnitsPerCore is the number of cpu units of a vCPU
+	ecsCPUUnitsPerCore = 1024
+	// ecsIAMRoleAnnotation binds the service account to an IAM role, using the IAM roles for service accounts of EKS
+	ecsIAMRoleAnnotation = "eks.amazonaws.com/role-arn"
+
+	// ecsIAMRoleTODOKey explains how the task role is bound to the pods
+	ecsIAMRoleTODOKey = common.TODOAnnotation + "ecsiamrole"
+	// ecsSecretsTODOKey lists the parameters and the secrets of AWS whose values have to be added to the secret
+	ecsSecretsTODOKey = common.TODOAnnotation + "ecssecrets"
+	// ecsUnsupportedTODOKey lists the settings of the source which have no equivalent in the translated resources
+	ecsUnsupportedTODOKey = common.TODOAnnotation + "ecsunsupported"
+)
+
+// ECSTranslator implements Translator interface for Amazon ECS task definitions, described by the JSON registered using
+// aws ecs register-task-definition or returned by aws ecs describe-task-definition. The ECS services running the task
+// definitions, returned by aws ecs describe-services, set the number of replicas and the exposure of the services.
+type ECSTranslator struct {
+}
+
+// ecsTaskDefinitionDocument is the output of describe-task-definition, or the input of register-task-definition
+type ecsTaskDefinitionDocument struct {
+	TaskDefinition    *ecsTaskDefinition `yaml:"taskDefinition"`
+	ecsTaskDefinition `yaml:",inline"`
+}
+
+// ecsTaskDefinition contains the fields of a task definition which are used in the translation
+type ecsTaskDefinition struct {
+	Family               string                   `yaml:"family"`
+	TaskRoleArn          string                   `yaml:"taskRoleArn"`
+	ExecutionRoleArn     string                   `yaml:"executionRoleArn"`
+	NetworkMode          string                   `yaml:"networkMode"`
+	CPU                  string                   `yaml:"cpu"`
+	Memory               string                   `yaml:"memory"`
+	ContainerDefinitions []ecsContainerDefinition `yaml:"containerDefinitions"`
+	Volumes              []ecsVolume              `yaml:"volumes"`
+	PlacementConstraints []struct {
+		Expression string `yaml:"expression"`
+	} `yaml:"placementConstraints"`
+}
+
+// ecsContainerDefinition is a container of a task definition. The cpu is in cpu units, and the memory in MiB.
+type ecsContainerDefinition struct {
+	Name              string   `yaml:"name"`
+	Image             string   `yaml:"image"`
+	CPU               int64    `yaml:"cpu"`
+	Memory            int64    `yaml:"memory"`
+	MemoryReservation int64    `yaml:"memoryReservation"`
+	EntryPoint        []string `yaml:"entryPoint"`
+	Command           []string `yaml:"command"`
+	WorkingDirectory  string   `yaml:"workingDirectory"`
+	User              string   `yaml:"user"`
+	PortMappings      []struct {
+		ContainerPort int32  `yaml:"containerPort"`
+		Protocol      string `yaml:"protocol"`
+	} `yaml:"portMappings"`
+	Environment []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"environment"`
+	EnvironmentFiles []struct {
+		Value string `yaml:"value"`
+	} `yaml:"environmentFiles"`
+	Secrets     []ecsSecret `yaml:"secrets"`
+	HealthCheck *struct {
+		Command     []string `yaml:"command"`
+		Interval    int32    `yaml:"interval"`
+		Timeout     int32    `yaml:"timeout"`
+		Retries     int32    `yaml:"retries"`
+		StartPeriod int32    `yaml:"startPeriod"`
+	} `yaml:"healthCheck"`
+	MountPoints []struct {
+		SourceVolume  string `yaml:"sourceVolume"`
+		ContainerPath string `yaml:"containerPath"`
+		ReadOnly      bool   `yaml:"readOnly"`
+	} `yaml:"mountPoints"`
+	Links     []string `yaml:"links"`
+	DependsOn []struct {
+		ContainerName string `yaml:"containerName"`
+		Condition     string `yaml:"condition"`
+	} `yaml:"dependsOn"`
+}
+
+// ecsSecret is an env var read from a parameter of SSM Parameter Store or from a secret of Secrets Manager
+type ecsSecret struct {
+	Name      string `yaml:"name"`
+	ValueFrom string `yaml:"valueFrom"`
+}
+
+// ecsVolume is a volume of a task definition. The volumes with no configuration are bind mounts scoped to the task.
+type ecsVolume struct {
+	Name string `yaml:"name"`
+	Host *struct {
+		SourcePath string `yaml:"sourcePath"`
+	} `yaml:"host"`
+	DockerVolumeConfiguration *struct {
+		Scope string `yaml:"scope"`
+	} `yaml:"dockerVolumeConfiguration"`
+	EFSVolumeConfiguration *struct {
+		FileSystemID string `yaml:"fileSystemId"`
+	} `yaml:"efsVolumeConfiguration"`
+}
+
+// ecsServicesDocument is the output of describe-services, or the input of create-service
+type ecsServicesDocument struct {
+	Services   []ecsService `yaml:"services"`
+	ecsService `yaml:",inline"`
+}
+
+// ecsService contains the fields of an ECS service which are used in the translation
+type ecsService struct {
+	ServiceName    string `yaml:"serviceName"`
+	TaskDefinition string `yaml:"taskDefinition"`
+	DesiredCount   *int   `yaml:"desiredCount"`
+	LoadBalancers  []struct {
+		ContainerName string `yaml:"containerName"`
+		ContainerPort int32  `yaml:"containerPort"`
+	} `yaml:"loadBalancers"`
+	ServiceRegistries []struct {
+		RegistryArn string `yaml:"registryArn"`
+	} `yaml:"serviceRegistries"`
+}
+
+// GetTranslatorType returns the translator type
+func (*ECSTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.ECS2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the ECS task definitions. A task definition run by an ECS service is named after the ECS service.
+func (ecsTranslator *ECSTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByExt(inputPath, []string{".json"})
+	if err != nil {
+		log.Warnf("Unable to fetch the json files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	sort.Strings(filePaths)
+	ecsServicePaths := map[string]string{}
+	ecsServices := map[string]ecsService{}
+	for _, filePath := range filePaths {
+		for _, ecsSvc := range readECSServices(filePath) {
+			family := getECSTaskDefinitionFamily(ecsSvc.TaskDefinition)
+			if _, ok := ecsServices[family]; ok {
+				log.Warnf("Ignoring the ECS service %s in %s , since the task definition %s is already run by another service", ecsSvc.ServiceName, filePath, family)
+				continue
+			}
+			ecsServicePaths[family] = filePath
+			ecsServices[family] = ecsSvc
+		}
+	}
+	serviceNames := []string{}
+	for _, filePath := range filePaths {
+		taskDef, ok := readECSTaskDefinition(filePath)
+		if !ok {
+			continue
+		}
+		serviceName := common.NormalizeForServiceName(taskDef.Family)
+		if ecsSvc, ok := ecsServices[taskDef.Family]; ok && ecsSvc.ServiceName != "" {
+			serviceName = common.NormalizeForServiceName(ecsSvc.ServiceName)
+		}
+		if common.IsStringPresent(serviceNames, serviceName) {
+			log.Warnf("Ignoring the task definition %s in %s , since a service with the same name was already found", taskDef.Family, filePath)
+			continue
+		}
+		serviceNames = append(serviceNames, serviceName)
+		service := ecsTranslator.newService(serviceName)
+		service.Image = getECSContainerDefinitions(taskDef)[0].Image
+		service.AddSourceArtifact(plantypes.ECSTaskDefinitionArtifactType, filePath)
+		if ecsServicePath, ok := ecsServicePaths[taskDef.Family]; ok {
+			service.AddSourceArtifact(plantypes.ECSServiceArtifactType, ecsServicePath)
+		}
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// Translate translates the ECS task definitions to IR
+func (ecsTranslator *ECSTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != ecsTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		paths := service.SourceArtifacts[plantypes.ECSTaskDefinitionArtifactType]
+		if len(paths) == 0 {
+			log.Warnf("No ECS task definition found for the service %s", service.ServiceName)
+			continue
+		}
+		taskDef, ok := readECSTaskDefinition(paths[0])
+		if !ok {
+			log.Warnf("Unable to read the ECS task definition at path %s", paths[0])
+			continue
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		ir.AddContainer(container)
+		for _, containerDef := range getECSContainerDefinitions(taskDef)[1:] {
+			ir.AddContainer(irtypes.NewContainer(plantypes.ReuseContainerBuildTypeValue, containerDef.Image, false))
+		}
+		irService := irtypes.NewServiceFromPlanService(service)
+		unsupported := translateECSTaskDefinition(&ir, &irService, service.Image, taskDef)
+		for _, path := range service.SourceArtifacts[plantypes.ECSServiceArtifactType] {
+			for _, ecsSvc := range readECSServices(path) {
+				if getECSTaskDefinitionFamily(ecsSvc.TaskDefinition) == taskDef.Family {
+					unsupported = append(unsupported, translateECSService(&irService, ecsSvc)...)
+					break
+				}
+			}
+		}
+		addECSUnsupportedTODO(&irService, unsupported)
+		ir.Services[service.ServiceName] = irService
+	}
+	return ir, nil
+}
+
+func (ecsTranslator *ECSTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, ecsTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.ECSSourceTypeValue)
+	service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+	service.UpdateContainerBuildPipeline = false
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// readECSTaskDefinition reads the JSON at the path, and returns false if it does not describe a task definition with containers
+func readECSTaskDefinition(path string) (ecsTaskDefinition, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the file at path %s . Error: %q", path, err)
+		return ecsTaskDefinition{}, false
+	}
+	doc := ecsTaskDefinitionDocument{}
+	// JSON is valid yaml, so the task definitions are parsed the same way as the ARM templates
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		log.Debugf("Unable to parse the file at path %s as an ECS task definition. Error: %q", path, err)
+		return ecsTaskDefinition{}, false
+	}
+	taskDef := doc.ecsTaskDefinition
+	if doc.TaskDefinition != nil {
+		taskDef = *doc.TaskDefinition
+	}
+	if taskDef.Family == "" || len(taskDef.ContainerDefinitions) == 0 {
+		return taskDef, false
+	}
+	return taskDef, true
+}
+
+// readECSServices returns the ECS services in the JSON at the path
+func readECSServices(path string) []ecsService {
+	ecsServices := []ecsService{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the file at path %s . Error: %q", path, err)
+		return ecsServices
+	}
+	doc := ecsServicesDocument{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		log.Debugf("Unable to parse the file at path %s as ECS services. Error: %q", path, err)
+		return ecsServices
+	}
+	for _, ecsSvc := range append(doc.Services, doc.ecsService) {
+		if ecsSvc.ServiceName != "" && ecsSvc.TaskDefinition != "" {
+			ecsServices = append(ecsServices, ecsSvc)
+		}
+	}
+	return ecsServices
+}
+
+// getECSTaskDefinitionFamily returns the family of the task definition, given its ARN, its family and revision, or its family
+func getECSTaskDefinitionFamily(taskDefinition string) string {
+	if i := strings.LastIndex(taskDefinition, ecsTaskDefinitionARNPrefix); i >= 0 {
+		taskDefinition = taskDefinition[i+len(ecsTaskDefinitionARNPrefix):]
+	}
+	return strings.SplitN(taskDefinition, ":", 2)[0]
+}
+
+// getECSContainerDefinitions returns the containers of the task definition. The first container with port mappings
+// serves the service and is moved to the front, and the other containers become its sidecars.
+func getECSContainerDefinitions(taskDef ecsTaskDefinition) []ecsContainerDefinition {
+	containerDefs := append([]ecsContainerDefinition{}, taskDef.ContainerDefinitions...)
+	for i, containerDef := range containerDefs {
+		if len(containerDef.PortMappings) > 0 {
+			containerDefs[0], containerDefs[i] = containerDefs[i], containerDefs[0]
+			break
+		}
+	}
+	return containerDefs
+}
+
+// translateECSTaskDefinition sets the containers, the ports, the secrets, the volumes and the service account of the service
+// from the task definition, and returns the settings which have no equivalent. The parameters of SSM Parameter Store and
+// the secrets of Secrets Manager become keys without values of the secret of the service.
+func translateECSTaskDefinition(ir *irtypes.IR, service *irtypes.Service, image string, taskDef ecsTaskDefinition) []string {
+	unsupported := []string{}
+	secretName := common.MakeFileNameCompliant(service.Name + "-secrets")
+	secrets := map[string]string{}
+	containerDefs := getECSContainerDefinitions(taskDef)
+	for i, containerDef := range containerDefs {
+		container := core.Container{Name: common.NormalizeForServiceName(containerDef.Name), Image: containerDef.Image, Command: containerDef.EntryPoint, Args: containerDef.Command, WorkingDir: containerDef.WorkingDirectory}
+		if i == 0 {
+			container.Name = service.Name
+			if image != "" {
+				container.Image = image
+			}
+		}
+		for _, portMapping := range containerDef.PortMappings {
+			protocol := core.ProtocolTCP
+			if strings.EqualFold(portMapping.Protocol, string(core.ProtocolUDP)) {
+				protocol = core.ProtocolUDP
+				service.NonHTTPPorts = append(service.NonHTTPPorts, irtypes.NonHTTPPort{Number: portMapping.ContainerPort, Protocol: protocol, Hint: "the UDP port mapping of the task definition"})
+			}
+			container.Ports = append(container.Ports, core.ContainerPort{ContainerPort: portMapping.ContainerPort, Protocol: protocol})
+			service.AddPortForwarding(irtypes.Port{Number: portMapping.ContainerPort}, irtypes.Port{Number: portMapping.ContainerPort})
+		}
+		for _, env := range containerDef.Environment {
+			container.Env = append(container.Env, core.EnvVar{Name: env.Name, Value: env.Value})
+		}
+		for _, secret := range containerDef.Secrets {
+			secrets[secret.Name] = secret.ValueFrom
+			container.Env = append(container.Env, core.EnvVar{Name: secret.Name, ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: secretName},
+				Key:                  secret.Name,
+			}}})
+		}
+		for _, envFile := range containerDef.EnvironmentFiles {
+			unsupported = append(unsupported, "the environment file "+envFile.Value)
+		}
+		container.Resources = getECSContainerResources(containerDef)
+		if len(containerDefs) == 1 && len(container.Resources.Limits) == 0 {
+			container.Resources.Limits = getECSTaskResources(taskDef)
+		}
+		if containerDef.HealthCheck != nil {
+			container.LivenessProbe = getECSHealthCheckProbe(containerDef.HealthCheck.Command, containerDef.HealthCheck.Interval, containerDef.HealthCheck.Timeout, containerDef.HealthCheck.Retries, containerDef.HealthCheck.StartPeriod)
+		}
+		for _, mountPoint := range containerDef.MountPoints {
+			container.VolumeMounts = append(container.VolumeMounts, core.VolumeMount{Name: common.NormalizeForServiceName(mountPoint.SourceVolume), MountPath: mountPoint.ContainerPath, ReadOnly: mountPoint.ReadOnly})
+		}
+		if containerDef.User != "" {
+			unsupported = append(unsupported, fmt.Sprintf("the user %s of the container %s", containerDef.User, containerDef.Name))
+		}
+		for _, dependency := range containerDef.DependsOn {
+			unsupported = append(unsupported, fmt.Sprintf("the %s dependency of the container %s on %s", dependency.Condition, containerDef.Name, dependency.ContainerName))
+		}
+		if len(containerDef.Links) > 0 {
+			unsupported = append(unsupported, "the links of the container "+containerDef.Name)
+		}
+		service.Containers = append(service.Containers, container)
+	}
+	for _, volume := range taskDef.Volumes {
+		name := common.NormalizeForServiceName(volume.Name)
+		switch {
+		case volume.EFSVolumeConfiguration != nil:
+			unsupported = append(unsupported, fmt.Sprintf("the EFS volume %s of the file system %s", volume.Name, volume.EFSVolumeConfiguration.FileSystemID))
+		case volume.Host != nil && volume.Host.SourcePath != "":
+			unsupported = append(unsupported, fmt.Sprintf("the host volume %s of %s", volume.Name, volume.Host.SourcePath))
+		case volume.DockerVolumeConfiguration != nil && volume.DockerVolumeConfiguration.Scope == "shared":
+			unsupported = append(unsupported, fmt.Sprintf("the shared docker volume %s", volume.Name))
+		default:
+			service.Volumes = append(service.Volumes, core.Volume{Name: name, VolumeSource: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{}}})
+		}
+	}
+	if len(secrets) > 0 {
+		addECSSecrets(ir, service, secretName, secrets, taskDef.ExecutionRoleArn)
+	}
+	if taskDef.TaskRoleArn != "" {
+		addECSTaskRole(ir, service, taskDef.TaskRoleArn)
+	}
+	if taskDef.NetworkMode == "host" {
+		unsupported = append(unsupported, "the host network mode")
+	}
+	for _, constraint := range taskDef.PlacementConstraints {
+		unsupported = append(unsupported, "the placement constraint "+constraint.Expression)
+	}
+	return unsupported
+}
+
+// getECSContainerResources returns the resources of the container. The cpu units are reserved for the container, the memory is
+// its hard limit, and the memory reservation is its soft limit.
+func getECSContainerResources(containerDef ecsContainerDefinition) core.ResourceRequirements {
+	resources := core.ResourceRequirements{}
+	if containerDef.CPU > 0 || containerDef.MemoryReservation > 0 {
+		resources.Requests = core.ResourceList{}
+		if containerDef.CPU > 0 {
+			resources.Requests[core.ResourceCPU] = *resource.NewMilliQuantity(containerDef.CPU*1000/ecsCPUUnitsPerCore, resource.DecimalSI)
+		}
+		if containerDef.MemoryReservation > 0 {
+			resources.Requests[core.ResourceMemory] = *resource.NewQuantity(containerDef.MemoryReservation*1024*1024, resource.BinarySI)
+		}
+	}
+	if containerDef.Memory > 0 {
+		resources.Limits = core.ResourceList{core.ResourceMemory: *resource.NewQuantity(containerDef.Memory*1024*1024, resource.BinarySI)}
+	}
+	return resources
+}
+
+// getECSTaskResources returns the cpu and memory of the task, which are set in cpu units and MiB, or like 1 vCPU and 2 GB
+func getECSTaskResources(taskDef ecsTaskDefinition) core.ResourceList {
+	resources := core.ResourceList{}
+	if cpu := strings.Fields(taskDef.CPU); len(cpu) > 0 {
+		units := cast.ToFloat64(cpu[0])
+		if len(cpu) > 1 && strings.EqualFold(cpu[1], "vCPU") {
+			units *= ecsCPUUnitsPerCore
+		}
+		if units > 0 {
+			resources[core.ResourceCPU] = *resource.NewMilliQuantity(int64(units*1000/ecsCPUUnitsPerCore), resource.DecimalSI)
+		}
+	}
+	if memory := strings.Fields(taskDef.Memory); len(memory) > 0 {
+		mib := cast.ToFloat64(memory[0])
+		if len(memory) > 1 && strings.EqualFold(memory[1], "GB") {
+			mib *= 1024
+		}
+		if mib > 0 {
+			resources[core.ResourceMemory] = *resource.NewQuantity(int64(mib)*1024*1024, resource.BinarySI)
+		}
+	}
+	return resources
+}
+
+// getECSHealthCheckProbe returns the liveness probe running the command of the health check. The command starts with CMD,
+// which runs the rest of the command, or with CMD-SHELL, which runs it using the shell of the container.
+func getECSHealthCheckProbe(command []string, interval, timeout, retries, startPeriod int32) *core.Probe {
+	if len(command) < 2 {
+		return nil
+	}
+	probe := &core.Probe{PeriodSeconds: interval, TimeoutSeconds: timeout, FailureThreshold: retries, InitialDelaySeconds: startPeriod}
+	if command[0] == "CMD-SHELL" {
+		probe.Exec = &core.ExecAction{Command: []string{"/bin/sh", "-c", strings.Join(command[1:], " ")}}
+	} else {
+		probe.Exec = &core.ExecAction{Command: command[1:]}
+	}
+	return probe
+}
+
+// addECSSecrets adds the secret of the service, with a key for each env var read from AWS, and the TODO to fill in the values
+func addECSSecrets(ir *irtypes.IR, service *irtypes.Service, secretName string, secrets map[string]string, executionRoleArn string) {
+	names := []string{}
+	content := map[string][]byte{}
+	for name := range secrets {
+		names = append(names, name)
+		content[name] = []byte{}
+	}
+	sort.Strings(names)
+	ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: content})
+	references := []string{}
+	for _, name := range names {
+		references = append(references, fmt.Sprintf("%s from %s %s", name, getECSSecretStore(secrets[name]), secrets[name]))
+	}
+	todo := fmt.Sprintf("The env vars %s were read from AWS. Fill in their values in the secret %s , or sync them using the External Secrets Operator.", strings.Join(references, ", "), secretName)
+	if executionRoleArn != "" {
+		todo += fmt.Sprintf(" The execution role %s allowed the task to read them.", executionRoleArn)
+	}
+	service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{ecsSecretsTODOKey: todo})
+}
+
+// getECSSecretStore returns the store of the value of an env var, which is a secret of Secrets Manager, or a parameter of SSM Parameter Store
+func getECSSecretStore(valueFrom string) string {
+	if strings.Contains(valueFrom, ":secretsmanager:") {
+		return "Secrets Manager"
+	}
+	return "SSM Parameter Store"
+}
+
+// addECSTaskRole runs the service using a service account annotated with the task role, which the pods assume using the
+// IAM roles for service accounts of EKS
+func addECSTaskRole(ir *irtypes.IR, service *irtypes.Service, taskRoleArn string) {
+	serviceAccountName := common.MakeFileNameCompliant(service.Name)
+	ir.CachedObjects = append(ir.CachedObjects, &core.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: core.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        serviceAccountName,
+			Annotations: map[string]string{ecsIAMRoleAnnotation: taskRoleArn},
+		},
+	})
+	service.ServiceAccountName = serviceAccountName
+	service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+		ecsIAMRoleTODOKey: fmt.Sprintf("The service account %s is annotated with the task role %s . Associate an IAM OIDC provider with the EKS cluster, and allow the service account to assume the role in its trust policy. On other clusters, give the pods the permissions of the role in another way.", serviceAccountName, taskRoleArn),
+	})
+}
+
+// translateECSService sets the replicas and the exposure of the service from the ECS service, and returns the settings which have no equivalent
+func translateECSService(service *irtypes.Service, ecsSvc ecsService) []string {
+	unsupported := []string{}
+	if ecsSvc.DesiredCount != nil && *ecsSvc.DesiredCount > 0 {
+		service.Replicas = *ecsSvc.DesiredCount
+	}
+	if len(ecsSvc.LoadBalancers) > 0 {
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{common.ExposeSelector: common.AnnotationLabelValue})
+	}
+	for _, registry := range ecsSvc.ServiceRegistries {
+		unsupported = append(unsupported, "the Cloud Map service registry "+registry.RegistryArn)
+	}
+	return unsupported
+}
+
+// addECSUnsupportedTODO annotates the service with the settings which were lost in the translation
+func addECSUnsupportedTODO(service *irtypes.Service, unsupported []string) {
+	if len(unsupported) == 0 {
+		return
+	}
+	sort.Strings(unsupported)
+	service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+		ecsUnsupportedTODOKey: fmt.Sprintf("The settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", ")),
+	})
+}
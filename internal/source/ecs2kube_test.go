@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const testECSTaskDefinition = `{
+  "taskDefinition": {
+    "family": "tickets-task",
+    "taskRoleArn": "arn:aws:iam::123456789012:role/tickets-task",
+    "executionRoleArn": "arn:aws:iam::123456789012:role/ecsTaskExecutionRole",
+    "networkMode": "awsvpc",
+    "cpu": "512",
+    "memory": "1 GB",
+    "containerDefinitions": [
+      {
+        "name": "log-router",
+        "image": "amazon/aws-for-fluent-bit:stable",
+        "essential": true
+      },
+      {
+        "name": "app",
+        "image": "123456789012.dkr.ecr.us-east-1.amazonaws.com/tickets:v1",
+        "command": ["npm", "start"],
+        "portMappings": [{"containerPort": 3000, "hostPort": 3000, "protocol": "tcp"}],
+        "environment": [{"name": "NODE_ENV", "value": "production"}],
+        "secrets": [
+          {"name": "DB_PASSWORD", "valueFrom": "arn:aws:secretsmanager:us-east-1:123456789012:secret:tickets-db-AbCdEf"},
+          {"name": "API_KEY", "valueFrom": "arn:aws:ssm:us-east-1:123456789012:parameter/tickets/api-key"}
+        ],
+        "healthCheck": {"command": ["CMD-SHELL", "curl -f http://localhost:3000/healthz || exit 1"], "interval": 30, "timeout": 5, "retries": 3},
+        "mountPoints": [{"sourceVolume": "scratch", "containerPath": "/tmp/scratch"}],
+        "dependsOn": [{"containerName": "log-router", "condition": "START"}]
+      }
+    ],
+    "volumes": [
+      {"name": "scratch"},
+      {"name": "uploads", "efsVolumeConfiguration": {"fileSystemId": "fs-12345678"}}
+    ]
+  }
+}`
+
+const testECSServices = `{
+  "services": [
+    {
+      "serviceName": "tickets",
+      "taskDefinition": "arn:aws:ecs:us-east-1:123456789012:task-definition/tickets-task:7",
+      "desiredCount": 3,
+      "loadBalancers": [{"targetGroupArn": "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tickets/1", "containerName": "app", "containerPort": 3000}]
+    }
+  ]
+}`
+
+func TestECSTaskDefinition(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"ecs/taskdef.json":  testECSTaskDefinition,
+		"ecs/services.json": testECSServices,
+		"package.json":      `{"name": "tickets"}`,
+	})
+	services, err := new(ECSTranslator).GetServiceOptions(dir, plantypes.NewPlan())
+	if err != nil {
+		t.Fatalf("Failed to get the services. Error: %q", err)
+	}
+	if len(services) != 1 || services[0].ServiceName != "tickets" || services[0].Image != "123456789012.dkr.ecr.us-east-1.amazonaws.com/tickets:v1" {
+		t.Fatalf("Expected the tickets service reusing the image of the app container. Actual: %+v", services)
+	}
+	wantArtifacts := map[plantypes.SourceArtifactTypeValue][]string{
+		plantypes.ECSTaskDefinitionArtifactType: {filepath.Join(dir, "ecs", "taskdef.json")},
+		plantypes.ECSServiceArtifactType:        {filepath.Join(dir, "ecs", "services.json")},
+	}
+	if !reflect.DeepEqual(services[0].SourceArtifacts, wantArtifacts) {
+		t.Fatalf("Failed to add the task definition and the ECS service to the service. Expected: %v Actual: %v", wantArtifacts, services[0].SourceArtifacts)
+	}
+
+	taskDef, ok := readECSTaskDefinition(filepath.Join(dir, "ecs", "taskdef.json"))
+	if !ok {
+		t.Fatalf("Failed to read the task definition")
+	}
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	service := irtypes.NewServiceWithName("tickets")
+	unsupported := translateECSTaskDefinition(&ir, &service, "", taskDef)
+	unsupported = append(unsupported, translateECSService(&service, readECSServices(filepath.Join(dir, "ecs", "services.json"))[0])...)
+	addECSUnsupportedTODO(&service, unsupported)
+
+	if len(service.Containers) != 2 || service.Containers[1].Name != "log-router" {
+		t.Fatalf("Expected the app container followed by the log router. Actual: %+v", service.Containers)
+	}
+	container := service.Containers[0]
+	if container.Name != "tickets" || !reflect.DeepEqual(container.Args, []string{"npm", "start"}) || container.Ports[0].ContainerPort != 3000 {
+		t.Fatalf("Failed to translate the app container. Actual: %+v", container)
+	}
+	if len(container.Env) != 3 || container.Env[0].Value != "production" || container.Env[1].ValueFrom.SecretKeyRef.Name != "tickets-secrets" || container.Env[2].ValueFrom.SecretKeyRef.Key != "API_KEY" {
+		t.Fatalf("Failed to set the env of the container. Actual: %+v", container.Env)
+	}
+	wantProbe := []string{"/bin/sh", "-c", "curl -f http://localhost:3000/healthz || exit 1"}
+	if container.LivenessProbe == nil || !reflect.DeepEqual(container.LivenessProbe.Exec.Command, wantProbe) || container.LivenessProbe.PeriodSeconds != 30 {
+		t.Fatalf("Failed to translate the health check. Actual: %+v", container.LivenessProbe)
+	}
+	if len(service.Volumes) != 1 || service.Volumes[0].EmptyDir == nil || container.VolumeMounts[0].Name != "scratch" {
+		t.Fatalf("Failed to translate the task volume. Actual: %+v", service.Volumes)
+	}
+	if len(ir.Storages) != 1 || len(ir.Storages[0].Content) != 2 || service.Annotations[ecsSecretsTODOKey] == "" {
+		t.Fatalf("Failed to add the secret without values. Actual: %+v", ir.Storages)
+	}
+	if len(ir.CachedObjects) != 1 || service.ServiceAccountName != "tickets" {
+		t.Fatalf("Failed to add the service account of the task role. Actual: %+v", ir.CachedObjects)
+	}
+	if serviceAccount := ir.CachedObjects[0].(*core.ServiceAccount); serviceAccount.Annotations[ecsIAMRoleAnnotation] != "arn:aws:iam::123456789012:role/tickets-task" {
+		t.Fatalf("Failed to annotate the service account with the task role. Actual: %+v", serviceAccount)
+	}
+	if service.Replicas != 3 || !service.HasValidAnnotation(common.ExposeSelector) {
+		t.Fatalf("Failed to translate the ECS service. Actual: %+v", service)
+	}
+	wantUnsupported := "The settings the EFS volume uploads of the file system fs-12345678, the START dependency of the container app on log-router have no equivalent and were not translated."
+	if service.Annotations[ecsUnsupportedTODOKey] != wantUnsupported {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", wantUnsupported, service.Annotations[ecsUnsupportedTODOKey])
+	}
+}
+
+func TestGetECSTaskResources(t *testing.T) {
+	resources := getECSTaskResources(ecsTaskDefinition{CPU: "0.5 vCPU", Memory: "2048"})
+	if cpu, memory := resources[core.ResourceCPU], resources[core.ResourceMemory]; cpu.String() != "500m" || memory.String() != "2Gi" {
+		t.Fatalf("Failed to get the resources of the task. Actual: %+v", resources)
+	}
+	if family := getECSTaskDefinitionFamily("arn:aws:ecs:us-east-1:123456789012:task-definition/tickets-task:7"); family != "tickets-task" {
+		t.Fatalf("Failed to get the family of the task definition. Actual: %s", family)
+	}
+}
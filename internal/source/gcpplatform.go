@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+)
+
+const (
+	// gcpBuildpacksBuilder is the CNB builder which builds the apps deployed from source to Cloud Run and App Engine
+	gcpBuildpacksBuilder = "gcr.io/buildpacks/builder:v1"
+	// gcpDefaultPort is the port in the PORT env var on Cloud Run and App Engine
+	gcpDefaultPort = 8080
+
+	// gcpVPCTODOKey flags the services which reached a VPC network through a Serverless VPC Access connector
+	gcpVPCTODOKey = common.TODOAnnotation + "gcpvpc"
+	// gcpCloudSQLTODOKey flags the services which connected to Cloud SQL instances
+	gcpCloudSQLTODOKey = common.TODOAnnotation + "gcpcloudsql"
+	// gcpUnsupportedTODOKey lists the settings of the source which have no equivalent in the translated resources
+	gcpUnsupportedTODOKey = common.TODOAnnotation + "gcpunsupported"
+)
+
+// addGCPTODOs annotates the service with the steps needed to replace the VPC connector and the Cloud SQL connections,
+// and with the settings which were lost in the translation
+func addGCPTODOs(service *irtypes.Service, vpcConnector, vpcEgress string, cloudSQLInstances, unsupported []string) {
+	todos := map[string]string{}
+	if vpcConnector != "" {
+		if vpcEgress == "" {
+			vpcEgress = "private-ranges-only"
+		}
+		todos[gcpVPCTODOKey] = fmt.Sprintf("The service reached the VPC network through the Serverless VPC Access connector %s, with the egress setting %s. Make sure that the pods can reach the same private addresses, for example by running the cluster in the VPC network.", vpcConnector, vpcEgress)
+	}
+	if len(cloudSQLInstances) > 0 {
+		todos[gcpCloudSQLTODOKey] = fmt.Sprintf("The service connected to the Cloud SQL instances %s. Run the Cloud SQL Auth Proxy as a sidecar container, or connect to the private IP addresses of the instances.", strings.Join(cloudSQLInstances, ", "))
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		todos[gcpUnsupportedTODOKey] = fmt.Sprintf("The settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", "))
+	}
+	if len(todos) > 0 {
+		service.Annotations = common.MergeStringMaps(service.Annotations, todos)
+	}
+}
+
+// splitCloudSQLInstances returns the connection names of the Cloud SQL instances in the comma separated list, like project:region:instance
+func splitCloudSQLInstances(instances string) []string {
+	connectionNames := []string{}
+	for _, instance := range strings.Split(instances, ",") {
+		// The instances of App Engine flexible can be followed by the port on which they are exposed, like =tcp:5432
+		instance = strings.TrimSpace(strings.SplitN(instance, "=", 2)[0])
+		if instance != "" {
+			connectionNames = append(connectionNames, instance)
+		}
+	}
+	return connectionNames
+}
@@ -0,0 +1,771 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	herokuAppJSONName = "app.json"
+	herokuYamlName    = "heroku.yml"
+
+	// releaseProcessType is the process that Heroku runs once before each release of the app
+	releaseProcessType = "release"
+	// postdeployProcessType is the script in app.json that Heroku runs once after the app is created
+	postdeployProcessType = "postdeploy"
+	// herokuDefaultStack is the stack used by the apps which don't specify one
+	herokuDefaultStack = "heroku-20"
+	// herokuSecretGenerator is the generator of the config vars whose values are generated secrets
+	herokuSecretGenerator = "secret"
+	// herokuShutdownGracePeriodSeconds is the time given by Heroku to the dynos to shut down after SIGTERM, before they are killed
+	herokuShutdownGracePeriodSeconds = 30
+
+	// herokuConfigVarsTODOKey flags the services whose config vars have no value in the source
+	herokuConfigVarsTODOKey = common.TODOAnnotation + "herokuconfigvars"
+	// herokuAddOnsTODOKey flags the services whose add-ons have to be replaced
+	herokuAddOnsTODOKey = common.TODOAnnotation + "herokuaddons"
+)
+
+var (
+	// herokuStackBuilders maps the Heroku stacks to the CNB builders which build the apps the same way
+	herokuStackBuilders = map[string]string{
+		"heroku-18": "heroku/buildpacks:18",
+		"heroku-20": "heroku/buildpacks:20",
+		"heroku-22": "heroku/builder:22",
+	}
+	// herokuSecretConfigVarRegex matches the names of the config vars that are likely to contain secrets
+	herokuSecretConfigVarRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private|credential)`)
+	// herokuDynoMemory is the memory of each dyno size
+	herokuDynoMemory = map[string]string{
+		"free":          "512M",
+		"eco":           "512M",
+		"hobby":         "512M",
+		"basic":         "512M",
+		"standard-1x":   "512M",
+		"standard-2x":   "1G",
+		"performance-m": "2560M",
+		"performance-l": "14G",
+		"private-s":     "1G",
+		"private-m":     "2560M",
+		"private-l":     "14G",
+		"shield-s":      "1G",
+		"shield-m":      "2560M",
+		"shield-l":      "14G",
+	}
+)
+
+// HerokuTranslator implements Translator interface for Heroku apps, described by a Procfile, an app.json and a heroku.yml
+type HerokuTranslator struct {
+}
+
+// herokuAppJSON contains the fields of the app.json of a Heroku app which are used in the translation
+type herokuAppJSON struct {
+	Name       string                     `json:"name"`
+	Stack      string                     `json:"stack"`
+	Env        map[string]herokuConfigVar `json:"env"`
+	Formation  map[string]herokuFormation `json:"formation"`
+	Buildpacks []herokuBuildpack          `json:"buildpacks"`
+	AddOns     []herokuAddOn              `json:"addons"`
+	Scripts    map[string]herokuScript    `json:"scripts"`
+}
+
+// herokuAddOn is an add-on provisioned for the app. It is either the plan or an object with the plan and the name of the attachment.
+type herokuAddOn struct {
+	Plan string `json:"plan"`
+	As   string `json:"as"`
+}
+
+// herokuScript is a script run by Heroku during the lifecycle of the app. It is either the command or an object with the command and the dyno size.
+type herokuScript struct {
+	Command string `json:"command"`
+	Size    string `json:"size"`
+}
+
+// herokuConfigVar is a config var of the app. It is either a value or an object describing the value.
+type herokuConfigVar struct {
+	Description string `json:"description"`
+	Value       string `json:"value"`
+	Generator   string `json:"generator"`
+	Required    *bool  `json:"required"`
+}
+
+// herokuFormation is the number and the size of the dynos running a process type
+type herokuFormation struct {
+	Quantity *int   `json:"quantity"`
+	Size     string `json:"size"`
+}
+
+// herokuBuildpack is a buildpack building the app
+type herokuBuildpack struct {
+	URL string `json:"url"`
+}
+
+// herokuYaml contains the fields of the heroku.yml of a Heroku app built from Dockerfiles
+type herokuYaml struct {
+	Setup struct {
+		Config map[string]string `yaml:"config"`
+	} `yaml:"setup"`
+	Build struct {
+		Docker map[string]string `yaml:"docker"`
+	} `yaml:"build"`
+	Release herokuRunCommand            `yaml:"release"`
+	Run     map[string]herokuRunCommand `yaml:"run"`
+}
+
+// herokuRunCommand is the command of a process in heroku.yml. It is either a command or an object with the commands and the image running them.
+type herokuRunCommand struct {
+	Command []string `yaml:"command"`
+	Image   string   `yaml:"image"`
+}
+
+// UnmarshalJSON reads the config var from a value or from an object
+func (configVar *herokuConfigVar) UnmarshalJSON(data []byte) error {
+	value := ""
+	if err := json.Unmarshal(data, &value); err == nil {
+		configVar.Value = value
+		return nil
+	}
+	type rawConfigVar herokuConfigVar
+	raw := rawConfigVar{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*configVar = herokuConfigVar(raw)
+	return nil
+}
+
+// UnmarshalJSON reads the add-on from its plan or from an object
+func (addOn *herokuAddOn) UnmarshalJSON(data []byte) error {
+	plan := ""
+	if err := json.Unmarshal(data, &plan); err == nil {
+		addOn.Plan = plan
+		return nil
+	}
+	type rawAddOn herokuAddOn
+	raw := rawAddOn{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*addOn = herokuAddOn(raw)
+	return nil
+}
+
+// UnmarshalJSON reads the script from a command or from an object
+func (script *herokuScript) UnmarshalJSON(data []byte) error {
+	command := ""
+	if err := json.Unmarshal(data, &command); err == nil {
+		script.Command = command
+		return nil
+	}
+	type rawScript herokuScript
+	raw := rawScript{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*script = herokuScript(raw)
+	return nil
+}
+
+// UnmarshalYAML reads the command from a string or from an object
+func (runCommand *herokuRunCommand) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		runCommand.Command = []string{value.Value}
+		return nil
+	}
+	type rawRunCommand herokuRunCommand
+	raw := rawRunCommand{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*runCommand = herokuRunCommand(raw)
+	return nil
+}
+
+// GetTranslatorType returns the translator type
+func (*HerokuTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.Heroku2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the Heroku apps in the directories containing a Procfile, a heroku.yml or the app.json of a Heroku app
+func (herokuTranslator *HerokuTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByName(inputPath, []string{procfileName, herokuYamlName, herokuAppJSONName})
+	if err != nil {
+		log.Warnf("Unable to fetch the Procfiles, heroku.yml and app.json files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	appDirs := []string{}
+	for _, filePath := range filePaths {
+		// Other tools, like Expo, also use app.json, so the apps without a Procfile are only detected by the fields specific to Heroku
+		if filepath.Base(filePath) == herokuAppJSONName && !isHerokuAppJSON(filePath) {
+			continue
+		}
+		appDirs = append(appDirs, filepath.Dir(filePath))
+	}
+	appDirs = common.UniqueStrings(appDirs)
+	sort.Strings(appDirs)
+	collectedApps := getCollectedHerokuApps(inputPath)
+	appsCovered := []string{}
+	for _, appDir := range appDirs {
+		appFiles := getHerokuAppFiles(appDir)
+		appJSON, heroku := readHerokuApp(appFiles)
+		serviceName := filepath.Base(appDir)
+		if appJSON.Name != "" {
+			serviceName = appJSON.Name
+		}
+		serviceName = common.NormalizeForServiceName(serviceName)
+		collectedAppsPath, collectedApp := getCollectedHerokuApp(collectedApps, serviceName)
+		if collectedAppsPath != "" {
+			appsCovered = append(appsCovered, collectedApp.Name)
+			if appJSON.Stack == "" {
+				appJSON.Stack = collectedApp.Stack
+			}
+			if len(appJSON.Buildpacks) == 0 {
+				for _, buildpack := range collectedApp.Buildpacks {
+					appJSON.Buildpacks = append(appJSON.Buildpacks, herokuBuildpack{URL: buildpack})
+				}
+			}
+		}
+		newAppService := func() plantypes.Service {
+			service := herokuTranslator.newService(serviceName)
+			for artifactType, path := range appFiles {
+				service.AddSourceArtifact(artifactType, path)
+			}
+			if collectedAppsPath != "" {
+				service.AddSourceArtifact(plantypes.HerokuAppsArtifactType, collectedAppsPath)
+			}
+			service.AddSourceArtifact(plantypes.SourceDirectoryArtifactType, appDir)
+			service.AddBuildArtifact(plantypes.SourceDirectoryBuildArtifactType, appDir)
+			if foundRepo, err := service.GatherGitInfo(appDir, plan); foundRepo && err != nil {
+				log.Warnf("Error while parsing the git repo at path %q Error: %q", appDir, err)
+			}
+			return service
+		}
+		if dockerfile, ok := heroku.Build.Docker[webProcessType]; ok {
+			dockerfilePath := filepath.Join(appDir, filepath.FromSlash(dockerfile))
+			service := newAppService()
+			service.ContainerBuildType = plantypes.ReuseDockerFileContainerBuildTypeValue
+			service.ContainerizationTargetOptions = []string{dockerfilePath}
+			service.AddSourceArtifact(plantypes.DockerfileArtifactType, dockerfilePath)
+			services = append(services, service)
+			continue
+		}
+		// The app is built by the Heroku buildpacks, so the CNB builder of its stack is preferred over the detected containerization options
+		cnbService := newAppService()
+		cnbService.ContainerBuildType = plantypes.CNBContainerBuildTypeValue
+		cnbService.ContainerizationTargetOptions = []string{getHerokuBuilder(appJSON.Stack)}
+		otherServices := []plantypes.Service{}
+		for _, cop := range sortByBuildpacks(containerizer.GetContainerizationOptions(plan, appDir), getHerokuBuildpacks(appJSON)) {
+			if cop.ContainerizationType == plantypes.CNBContainerBuildTypeValue {
+				for _, builder := range cop.TargetOptions {
+					if !common.IsStringPresent(cnbService.ContainerizationTargetOptions, builder) {
+						cnbService.ContainerizationTargetOptions = append(cnbService.ContainerizationTargetOptions, builder)
+					}
+				}
+				cnbService.Detection = cop.Detection
+				continue
+			}
+			service := newAppService()
+			service.ContainerBuildType = cop.ContainerizationType
+			service.ContainerizationTargetOptions = cop.TargetOptions
+			service.Detection = cop.Detection
+			otherServices = append(otherServices, service)
+		}
+		services = append(append(services, cnbService), otherServices...)
+	}
+	// The collected apps without source code are built manually
+	for path, apps := range collectedApps {
+		for _, app := range apps {
+			if common.IsStringPresent(appsCovered, app.Name) {
+				continue
+			}
+			log.Warnf("No source code found for the Heroku app %s collected in %s ; Defaulting to manual", app.Name, filepath.Base(path))
+			service := herokuTranslator.newService(common.NormalizeForServiceName(app.Name))
+			service.ContainerBuildType = plantypes.ManualContainerBuildTypeValue
+			service.AddSourceArtifact(plantypes.HerokuAppsArtifactType, path)
+			services = append(services, service)
+			appsCovered = append(appsCovered, app.Name)
+		}
+	}
+	return services, nil
+}
+
+// Translate translates the Heroku apps to IR
+func (herokuTranslator *HerokuTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != herokuTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		appFiles := map[plantypes.SourceArtifactTypeValue]string{}
+		for _, artifactType := range []plantypes.SourceArtifactTypeValue{plantypes.ProcfileArtifactType, plantypes.HerokuAppJSONArtifactType, plantypes.HerokuYamlArtifactType} {
+			if paths := service.SourceArtifacts[artifactType]; len(paths) > 0 {
+				appFiles[artifactType] = paths[0]
+			}
+		}
+		appJSON, heroku := readHerokuApp(appFiles)
+		collectedApp := collecttypes.HerokuApplication{}
+		if paths := service.SourceArtifacts[plantypes.HerokuAppsArtifactType]; len(paths) > 0 {
+			_, collectedApp = getCollectedHerokuApp(map[string][]collecttypes.HerokuApplication{paths[0]: readCollectedHerokuApps(paths[0])}, service.ServiceName)
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		if err := containerizer.CommitServiceArtifacts(service.ServiceName, container); err != nil {
+			log.Warnf("Unable to commit the artifacts of the service %s . Error: %q", service.ServiceName, err)
+		}
+		ir.AddContainer(container)
+		irService := irtypes.NewServiceFromPlanService(service)
+		terminationGracePeriodSeconds := int64(herokuShutdownGracePeriodSeconds)
+		irService.TerminationGracePeriodSeconds = &terminationGracePeriodSeconds
+		serviceContainer := core.Container{Name: service.ServiceName, Image: service.Image}
+		// The web process listens on the port in the PORT env var
+		port := int32(common.DefaultServicePort)
+		if len(container.ExposedPorts) > 0 {
+			port = int32(container.ExposedPorts[0])
+		}
+		serviceContainer.Ports = []core.ContainerPort{{ContainerPort: port}}
+		podPort := irtypes.Port{Number: port}
+		servicePort := podPort
+		irService.AddPortForwarding(servicePort, podPort)
+		serviceContainer.Env = []core.EnvVar{{Name: "PORT", Value: cast.ToString(port)}}
+		configs, secrets, unset := getHerokuConfigVars(appJSON, heroku, collectedApp)
+		addHerokuConfigVars(&ir, &serviceContainer, service.ServiceName, configs, secrets)
+		if len(unset) > 0 {
+			irService.Annotations = common.MergeStringMaps(irService.Annotations, map[string]string{
+				herokuConfigVarsTODOKey: fmt.Sprintf("Set the values of the config vars %s, which are required by the app but have no value in the source.", strings.Join(unset, ", ")),
+			})
+		}
+		addOns := collectedApp.AddOns
+		if len(addOns) == 0 {
+			addOns = getHerokuAppJSONAddOns(appJSON)
+		}
+		if addOns := getHerokuAddOnsTODO(addOns); addOns != "" {
+			irService.Annotations = common.MergeStringMaps(irService.Annotations, map[string]string{herokuAddOnsTODOKey: addOns})
+		}
+		irService.Containers = []core.Container{serviceContainer}
+		ir.Services[service.ServiceName] = irService
+		processes := getHerokuProcesses(appFiles[plantypes.ProcfileArtifactType], heroku)
+		if postdeploy := appJSON.Scripts[postdeployProcessType]; postdeploy.Command != "" {
+			processes = append(processes, irtypes.Process{Name: postdeployProcessType, Command: postdeploy.Command})
+		}
+		addHerokuProcesses(&ir, service.ServiceName, addCollectedHerokuProcesses(processes, collectedApp), getHerokuFormation(appJSON, collectedApp))
+	}
+	return ir, nil
+}
+
+func (herokuTranslator *HerokuTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, herokuTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.DirectorySourceTypeValue)
+	service.AddSourceType(plantypes.HerokuSourceTypeValue)
+	service.UpdateContainerBuildPipeline = true
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// getHerokuAppFiles returns the paths of the Procfile, app.json and heroku.yml of the app in the directory, if they exist
+func getHerokuAppFiles(appDir string) map[plantypes.SourceArtifactTypeValue]string {
+	appFiles := map[plantypes.SourceArtifactTypeValue]string{}
+	for artifactType, name := range map[plantypes.SourceArtifactTypeValue]string{
+		plantypes.ProcfileArtifactType:      procfileName,
+		plantypes.HerokuAppJSONArtifactType: herokuAppJSONName,
+		plantypes.HerokuYamlArtifactType:    herokuYamlName,
+	} {
+		path := filepath.Join(appDir, name)
+		if _, err := os.Stat(path); err == nil {
+			appFiles[artifactType] = path
+		}
+	}
+	return appFiles
+}
+
+// isHerokuAppJSON returns true if the app.json at the path has the fields of the app.json of a Heroku app
+func isHerokuAppJSON(path string) bool {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the app.json at path %s . Error: %q", path, err)
+		return false
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	for _, field := range []string{"stack", "buildpacks", "formation", "addons", "scripts", "environments"} {
+		if _, ok := fields[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readHerokuApp reads the app.json and the heroku.yml of the app. The files which are missing or invalid are read as empty.
+func readHerokuApp(appFiles map[plantypes.SourceArtifactTypeValue]string) (herokuAppJSON, herokuYaml) {
+	appJSON := herokuAppJSON{}
+	if path, ok := appFiles[plantypes.HerokuAppJSONArtifactType]; ok {
+		if data, err := ioutil.ReadFile(path); err != nil {
+			log.Debugf("Unable to read the app.json at path %s . Error: %q", path, err)
+		} else if err := json.Unmarshal(data, &appJSON); err != nil {
+			log.Warnf("Unable to parse the app.json at path %s . Error: %q", path, err)
+		}
+	}
+	heroku := herokuYaml{}
+	if path, ok := appFiles[plantypes.HerokuYamlArtifactType]; ok {
+		if data, err := ioutil.ReadFile(path); err != nil {
+			log.Debugf("Unable to read the heroku.yml at path %s . Error: %q", path, err)
+		} else if err := yaml.Unmarshal(data, &heroku); err != nil {
+			log.Warnf("Unable to parse the heroku.yml at path %s . Error: %q", path, err)
+		}
+	}
+	return appJSON, heroku
+}
+
+// getHerokuBuilder returns the CNB builder for the Heroku stack
+func getHerokuBuilder(stack string) string {
+	if builder, ok := herokuStackBuilders[stack]; ok {
+		return builder
+	}
+	return herokuStackBuilders[herokuDefaultStack]
+}
+
+// getHerokuBuildpacks returns the buildpacks of the app, in the format of the cf buildpacks, like heroku/nodejs or nodejs_buildpack
+func getHerokuBuildpacks(appJSON herokuAppJSON) []string {
+	buildpacks := []string{}
+	for _, buildpack := range appJSON.Buildpacks {
+		// The buildpacks in git repos are named like heroku-buildpack-nodejs
+		buildpacks = append(buildpacks, strings.Replace(buildpack.URL, "heroku-buildpack-", "", 1))
+	}
+	return buildpacks
+}
+
+// getHerokuConfigVars returns the values of the config vars of the app, split into the plain ones and the secret ones,
+// along with the names of the required config vars which have no value. The generated secrets have no value either.
+// The values collected from the running app take precedence over the ones in the source.
+func getHerokuConfigVars(appJSON herokuAppJSON, heroku herokuYaml, collectedApp collecttypes.HerokuApplication) (map[string]string, map[string]string, []string) {
+	values := map[string]string{}
+	secretNames := []string{}
+	unsetNames := []string{}
+	for name, value := range heroku.Setup.Config {
+		values[name] = value
+	}
+	for name, configVar := range appJSON.Env {
+		isRequired := configVar.Required == nil || *configVar.Required
+		if configVar.Value == "" && configVar.Generator != herokuSecretGenerator && !isRequired {
+			continue
+		}
+		values[name] = configVar.Value
+		if configVar.Generator == herokuSecretGenerator {
+			secretNames = append(secretNames, name)
+		}
+	}
+	// The values of the collected config vars are empty when only their names were collected
+	for name, value := range collectedApp.ConfigVars {
+		if value != "" || values[name] == "" {
+			values[name] = value
+		}
+	}
+	configs := map[string]string{}
+	secrets := map[string]string{}
+	for name, value := range values {
+		if value == "" {
+			unsetNames = append(unsetNames, name)
+		}
+		if common.IsStringPresent(secretNames, name) || herokuSecretConfigVarRegex.MatchString(name) {
+			secrets[name] = value
+		} else {
+			configs[name] = value
+		}
+	}
+	sort.Strings(unsetNames)
+	return configs, secrets, unsetNames
+}
+
+// addHerokuConfigVars stores the config vars of the app in a config map and a secret, and adds them to the env of the container
+func addHerokuConfigVars(ir *irtypes.IR, container *core.Container, serviceName string, configs, secrets map[string]string) {
+	configMapName := common.MakeFileNameCompliant(serviceName + "-config")
+	secretName := common.MakeFileNameCompliant(serviceName + "-secrets")
+	names := []string{}
+	content := map[string][]byte{}
+	for name, value := range configs {
+		names = append(names, name)
+		content[name] = []byte(value)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		container.Env = append(container.Env, core.EnvVar{Name: name, ValueFrom: &core.EnvVarSource{ConfigMapKeyRef: &core.ConfigMapKeySelector{
+			LocalObjectReference: core.LocalObjectReference{Name: configMapName},
+			Key:                  name,
+		}}})
+	}
+	if len(names) > 0 {
+		ir.AddStorage(irtypes.Storage{Name: configMapName, StorageType: irtypes.ConfigMapKind, Content: content})
+	}
+	names = []string{}
+	content = map[string][]byte{}
+	for name, value := range secrets {
+		names = append(names, name)
+		content[name] = []byte(value)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		container.Env = append(container.Env, core.EnvVar{Name: name, ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+			LocalObjectReference: core.LocalObjectReference{Name: secretName},
+			Key:                  name,
+		}}})
+	}
+	if len(names) > 0 {
+		ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: content})
+	}
+}
+
+// getHerokuProcesses returns the process types of the app, from the Procfile and the run and release sections of heroku.yml
+func getHerokuProcesses(procfilePath string, heroku herokuYaml) []irtypes.Process {
+	processes := []irtypes.Process{}
+	if procfilePath != "" {
+		procfileProcesses, err := parseProcfile(procfilePath)
+		if err != nil {
+			log.Debugf("Failed to parse the Procfile at path %s . Error: %q", procfilePath, err)
+		} else {
+			processes = procfileProcesses
+		}
+	}
+	setProcess := func(process irtypes.Process) {
+		for i := range processes {
+			if processes[i].Name == process.Name {
+				processes[i] = process
+				return
+			}
+		}
+		processes = append(processes, process)
+	}
+	processTypes := []string{}
+	for processType := range heroku.Run {
+		processTypes = append(processTypes, processType)
+	}
+	sort.Strings(processTypes)
+	for _, processType := range processTypes {
+		runCommand := heroku.Run[processType]
+		if len(runCommand.Command) == 0 {
+			continue
+		}
+		if runCommand.Image != "" && runCommand.Image != webProcessType {
+			log.Warnf("The %s process is run in the image built for the web process, instead of the %s image", processType, runCommand.Image)
+		}
+		setProcess(irtypes.Process{Name: common.NormalizeForServiceName(processType), Command: strings.Join(runCommand.Command, " ")})
+	}
+	if len(heroku.Release.Command) > 0 {
+		setProcess(irtypes.Process{Name: releaseProcessType, Command: strings.Join(heroku.Release.Command, " ")})
+	}
+	return processes
+}
+
+// addCollectedHerokuProcesses adds the process types of the formation of the collected app which are not in the source
+func addCollectedHerokuProcesses(processes []irtypes.Process, collectedApp collecttypes.HerokuApplication) []irtypes.Process {
+	for _, processType := range collectedApp.Formation {
+		name := common.NormalizeForServiceName(processType.Type)
+		found := false
+		for _, process := range processes {
+			if process.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found && processType.Command != "" {
+			processes = append(processes, irtypes.Process{Name: name, Command: processType.Command})
+		}
+	}
+	return processes
+}
+
+// getHerokuFormation returns the number and the size of the dynos of each process type. The collected formation takes precedence over app.json.
+func getHerokuFormation(appJSON herokuAppJSON, collectedApp collecttypes.HerokuApplication) map[string]herokuFormation {
+	formation := map[string]herokuFormation{}
+	for processType, processFormation := range appJSON.Formation {
+		formation[common.NormalizeForServiceName(processType)] = processFormation
+	}
+	if size := appJSON.Scripts[postdeployProcessType].Size; size != "" {
+		formation[postdeployProcessType] = herokuFormation{Size: size}
+	}
+	for _, processType := range collectedApp.Formation {
+		quantity := processType.Quantity
+		formation[common.NormalizeForServiceName(processType.Type)] = herokuFormation{Quantity: &quantity, Size: processType.Size}
+	}
+	return formation
+}
+
+// getHerokuAddOnsTODO returns the add-ons of the app along with their suggested replacements
+func getHerokuAddOnsTODO(addOns []collecttypes.HerokuAddOn) string {
+	if len(addOns) == 0 {
+		return ""
+	}
+	suggestions := []string{}
+	for _, addOn := range addOns {
+		suggestion := fmt.Sprintf("%s (%s): %s", addOn.Name, addOn.Service, addOn.Replacement)
+		if len(addOn.ConfigVars) > 0 {
+			suggestion += fmt.Sprintf(", and update the config vars %s", strings.Join(addOn.ConfigVars, ", "))
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return "Replace the Heroku add-ons: " + strings.Join(suggestions, "; ")
+}
+
+// getHerokuAppJSONAddOns returns the add-ons provisioned for the app by app.json, along with their suggested replacements.
+// The add-ons attached with a name set the config var named after it, like DATABASE_URL for DATABASE.
+func getHerokuAppJSONAddOns(appJSON herokuAppJSON) []collecttypes.HerokuAddOn {
+	addOns := []collecttypes.HerokuAddOn{}
+	for _, addOn := range appJSON.AddOns {
+		service := strings.SplitN(addOn.Plan, ":", 2)[0]
+		if service == "" {
+			continue
+		}
+		collectedAddOn := collecttypes.HerokuAddOn{Name: addOn.Plan, Service: service, Plan: addOn.Plan, Replacement: collecttypes.GetHerokuAddOnReplacement(service)}
+		if addOn.As != "" {
+			collectedAddOn.ConfigVars = []string{strings.ToUpper(addOn.As) + "_URL"}
+		}
+		addOns = append(addOns, collectedAddOn)
+	}
+	return addOns
+}
+
+// addHerokuProcesses maps the process types of the app to services. The web process is the service of the app, the release
+// process and the postdeploy script become jobs and the other processes, like workers, become deployments without ports.
+func addHerokuProcesses(ir *irtypes.IR, serviceName string, processes []irtypes.Process, formation map[string]herokuFormation) {
+	webService, ok := ir.Services[serviceName]
+	if !ok || len(webService.Containers) == 0 {
+		return
+	}
+	if quantity := formation[webProcessType].Quantity; quantity != nil {
+		webService.Replicas = *quantity
+	}
+	setDynoMemoryLimit(&webService.Containers[0], formation[webProcessType].Size)
+	for _, process := range processes {
+		if process.Name == webProcessType {
+			webService.Containers[0].Command = []string{"/bin/sh", "-c", process.Command}
+			webService.Containers[0].Args = nil
+			continue
+		}
+		name := common.NormalizeForServiceName(serviceName + "-" + process.Name)
+		if _, ok := ir.Services[name]; ok {
+			log.Warnf("Unable to translate the %s process of the app %s, since the service %s already exists.", process.Name, serviceName, name)
+			continue
+		}
+		processService := irtypes.NewServiceWithName(name)
+		processService.ServiceRelPath = ""
+		processService.Annotations = common.MergeStringMaps(webService.Annotations, nil)
+		processService.Labels = common.MergeStringMaps(webService.Labels, nil)
+		processService.Networks = append([]string{}, webService.Networks...)
+		processService.TerminationGracePeriodSeconds = webService.TerminationGracePeriodSeconds
+		container := *webService.Containers[0].DeepCopy()
+		container.Name = name
+		container.Command = []string{"/bin/sh", "-c", process.Command}
+		container.Args = nil
+		container.Ports = nil
+		if process.Name == releaseProcessType || process.Name == postdeployProcessType {
+			processService.RestartPolicy = core.RestartPolicyOnFailure
+		} else if quantity := formation[process.Name].Quantity; quantity != nil {
+			processService.Replicas = *quantity
+		}
+		container.Resources = core.ResourceRequirements{}
+		setDynoMemoryLimit(&container, formation[process.Name].Size)
+		processService.Containers = []core.Container{container}
+		ir.Services[name] = processService
+		log.Debugf("Translated the %s process of the app %s to the service %s", process.Name, serviceName, name)
+	}
+	ir.Services[serviceName] = webService
+}
+
+// setDynoMemoryLimit sets the memory limit of the container to the memory of the dyno size
+func setDynoMemoryLimit(container *core.Container, size string) {
+	if size == "" {
+		return
+	}
+	memory, ok := herokuDynoMemory[strings.ToLower(size)]
+	if !ok {
+		log.Debugf("Unknown dyno size %s of the container %s", size, container.Name)
+		return
+	}
+	setMemoryLimit(container, memory)
+}
+
+// getCollectedHerokuApps returns the apps collected from Heroku in the yaml and json files in the directory
+func getCollectedHerokuApps(inputPath string) map[string][]collecttypes.HerokuApplication {
+	collectedApps := map[string][]collecttypes.HerokuApplication{} // [path][apps]
+	filePaths, err := common.GetFilesByExt(inputPath, common.Move2KubeFileExts)
+	if err != nil {
+		log.Warnf("Unable to fetch yaml files and recognize the collected Heroku apps at path %q Error: %q", inputPath, err)
+		return collectedApps
+	}
+	for _, filePath := range filePaths {
+		if apps := readCollectedHerokuApps(filePath); len(apps) > 0 {
+			collectedApps[filePath] = apps
+		}
+	}
+	return collectedApps
+}
+
+// readCollectedHerokuApps returns the apps in the file if it is a Heroku apps file
+func readCollectedHerokuApps(path string) []collecttypes.HerokuApplication {
+	herokuApps := collecttypes.HerokuApps{}
+	if err := common.ReadMove2KubeYaml(path, &herokuApps); err != nil {
+		log.Debugf("Failed to read the yaml file at path %q Error: %q", path, err)
+		return nil
+	}
+	if herokuApps.Kind != string(collecttypes.HerokuAppsMetadataKind) {
+		log.Debugf("%q is not a valid Heroku apps file. Expected kind: %s Actual Kind: %s", path, string(collecttypes.HerokuAppsMetadataKind), herokuApps.Kind)
+		return nil
+	}
+	return herokuApps.Spec.HerokuApplications
+}
+
+// getCollectedHerokuApp returns the collected app of the service, along with the path of its file
+func getCollectedHerokuApp(collectedApps map[string][]collecttypes.HerokuApplication, serviceName string) (string, collecttypes.HerokuApplication) {
+	paths := []string{}
+	for path := range collectedApps {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, app := range collectedApps[path] {
+			if common.NormalizeForServiceName(app.Name) == serviceName {
+				return path, app
+			}
+		}
+	}
+	return "", collecttypes.HerokuApplication{}
+}
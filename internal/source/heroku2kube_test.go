@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const testHerokuAppJSON = `{
+  "name": "Ticket App",
+  "stack": "heroku-18",
+  "buildpacks": [{"url": "heroku/nodejs"}],
+  "formation": {"web": {"quantity": 2}, "worker": {"quantity": 3}},
+  "env": {
+    "NODE_ENV": "production",
+    "SESSION_SECRET": {"description": "The key signing the sessions", "generator": "secret"},
+    "DATABASE_PASSWORD": {"description": "The password of the database"},
+    "LOG_LEVEL": {"value": "info", "required": false},
+    "SENTRY_DSN": {"required": false}
+  }
+}`
+
+func TestReadHerokuApp(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"Procfile":   "web: npm start\nworker: node worker.js\n",
+		"app.json":   testHerokuAppJSON,
+		"heroku.yml": "setup:\n  config:\n    S3_BUCKET: tickets\nbuild:\n  docker:\n    web: Dockerfile\nrelease:\n  image: web\n  command:\n    - ./migrate.sh\nrun:\n  worker:\n    command:\n      - node worker.js --queue=default\n    image: web\n",
+	})
+	appFiles := getHerokuAppFiles(dir)
+	wantFiles := map[plantypes.SourceArtifactTypeValue]string{
+		plantypes.ProcfileArtifactType:      filepath.Join(dir, "Procfile"),
+		plantypes.HerokuAppJSONArtifactType: filepath.Join(dir, "app.json"),
+		plantypes.HerokuYamlArtifactType:    filepath.Join(dir, "heroku.yml"),
+	}
+	if !reflect.DeepEqual(appFiles, wantFiles) {
+		t.Fatalf("Failed to get the files of the app. Expected: %v Actual: %v", wantFiles, appFiles)
+	}
+	appJSON, heroku := readHerokuApp(appFiles)
+	if appJSON.Name != "Ticket App" || getHerokuBuilder(appJSON.Stack) != "heroku/buildpacks:18" {
+		t.Fatalf("Failed to read the name and the stack of the app. Actual: %+v", appJSON)
+	}
+	if buildpacks := getHerokuBuildpacks(appJSON); !reflect.DeepEqual(buildpacks, []string{"heroku/nodejs"}) {
+		t.Fatalf("Failed to get the buildpacks of the app. Actual: %v", buildpacks)
+	}
+	if heroku.Build.Docker[webProcessType] != "Dockerfile" {
+		t.Fatalf("Failed to read the Dockerfile of the web process. Actual: %+v", heroku.Build)
+	}
+
+	wantConfigs := map[string]string{"NODE_ENV": "production", "LOG_LEVEL": "info", "S3_BUCKET": "tickets"}
+	wantSecrets := map[string]string{"SESSION_SECRET": "", "DATABASE_PASSWORD": ""}
+	wantUnset := []string{"DATABASE_PASSWORD", "SESSION_SECRET"}
+	configs, secrets, unset := getHerokuConfigVars(appJSON, heroku, collecttypes.HerokuApplication{})
+	if !reflect.DeepEqual(configs, wantConfigs) || !reflect.DeepEqual(secrets, wantSecrets) || !reflect.DeepEqual(unset, wantUnset) {
+		t.Fatalf("Failed to get the config vars of the app. Expected: %v %v %v Actual: %v %v %v", wantConfigs, wantSecrets, wantUnset, configs, secrets, unset)
+	}
+
+	wantProcesses := []irtypes.Process{
+		{Name: "web", Command: "npm start"},
+		{Name: "worker", Command: "node worker.js --queue=default"},
+		{Name: "release", Command: "./migrate.sh"},
+	}
+	if processes := getHerokuProcesses(appFiles[plantypes.ProcfileArtifactType], heroku); !reflect.DeepEqual(processes, wantProcesses) {
+		t.Fatalf("Failed to get the processes of the app. Expected: %+v Actual: %+v", wantProcesses, processes)
+	}
+}
+
+func TestAddHerokuProcesses(t *testing.T) {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	web := irtypes.NewServiceWithName("tickets")
+	web.Containers = []core.Container{{Name: "tickets", Image: "tickets:latest", Ports: []core.ContainerPort{{ContainerPort: 8080}}}}
+	addHerokuConfigVars(&ir, &web.Containers[0], web.Name, map[string]string{"NODE_ENV": "production"}, map[string]string{"SESSION_SECRET": "s3cr3t"})
+	ir.Services[web.Name] = web
+	two, three := 2, 3
+	processes := []irtypes.Process{{Name: "web", Command: "npm start"}, {Name: "worker", Command: "node worker.js"}, {Name: "release", Command: "./migrate.sh"}}
+	addHerokuProcesses(&ir, web.Name, processes, map[string]herokuFormation{"web": {Quantity: &two}, "worker": {Quantity: &three}})
+
+	if len(ir.Services) != 3 {
+		t.Fatalf("Expected the web, worker and release services. Actual: %v", ir.Services)
+	}
+	web = ir.Services["tickets"]
+	if web.Replicas != 2 || !reflect.DeepEqual(web.Containers[0].Command, []string{"/bin/sh", "-c", "npm start"}) || len(web.Containers[0].Ports) != 1 {
+		t.Fatalf("Failed to set the web process of the app. Actual: %+v", web)
+	}
+	if len(web.Containers[0].Env) != 2 || web.Containers[0].Env[0].ValueFrom.ConfigMapKeyRef.Name != "tickets-config" || web.Containers[0].Env[1].ValueFrom.SecretKeyRef.Name != "tickets-secrets" {
+		t.Fatalf("Failed to add the config vars to the env of the web process. Actual: %+v", web.Containers[0].Env)
+	}
+	if len(ir.Storages) != 2 || ir.Storages[0].StorageType != irtypes.ConfigMapKind || ir.Storages[1].StorageType != irtypes.SecretKind || string(ir.Storages[1].Content["SESSION_SECRET"]) != "s3cr3t" {
+		t.Fatalf("Failed to store the config vars. Actual: %+v", ir.Storages)
+	}
+	worker, ok := ir.Services["tickets-worker"]
+	if !ok || worker.Replicas != 3 || worker.RestartPolicy != "" || len(worker.Containers[0].Ports) != 0 || !reflect.DeepEqual(worker.Containers[0].Command, []string{"/bin/sh", "-c", "node worker.js"}) {
+		t.Fatalf("Failed to translate the worker process to a deployment. Actual: %+v", worker)
+	}
+	if len(worker.Containers[0].Env) != 2 {
+		t.Fatalf("Expected the worker process to share the config vars. Actual: %+v", worker.Containers[0].Env)
+	}
+	release, ok := ir.Services["tickets-release"]
+	if !ok || release.RestartPolicy != core.RestartPolicyOnFailure {
+		t.Fatalf("Failed to translate the release process to a job. Actual: %+v", release)
+	}
+}
+
+func TestCollectedHerokuApp(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"m2k_collect/heroku/herokuapps.yaml": `apiVersion: move2kube.konveyor.io/v1alpha1
+kind: HerokuApps
+spec:
+  applications:
+    - name: Tickets
+      stack: heroku-22
+      configVars:
+        NODE_ENV: staging
+        DATABASE_URL: ""
+      formation:
+        - type: web
+          quantity: 2
+          size: standard-2x
+        - type: clock
+          quantity: 1
+          size: basic
+          command: node clock.js
+      addOns:
+        - name: postgresql-curved-12345
+          service: heroku-postgresql
+          plan: heroku-postgresql:standard-0
+          configVars: [DATABASE_URL]
+          replacement: A managed PostgreSQL database
+`,
+	})
+	path, app := getCollectedHerokuApp(getCollectedHerokuApps(dir), "tickets")
+	if path != filepath.Join(dir, "m2k_collect", "heroku", "herokuapps.yaml") || app.Stack != "heroku-22" {
+		t.Fatalf("Failed to find the collected app. Actual: %s %+v", path, app)
+	}
+
+	appJSON := herokuAppJSON{Env: map[string]herokuConfigVar{"NODE_ENV": {Value: "production"}, "LOG_LEVEL": {Value: "info"}}}
+	wantConfigs := map[string]string{"NODE_ENV": "staging", "LOG_LEVEL": "info", "DATABASE_URL": ""}
+	configs, secrets, unset := getHerokuConfigVars(appJSON, herokuYaml{}, app)
+	if !reflect.DeepEqual(configs, wantConfigs) || len(secrets) != 0 || !reflect.DeepEqual(unset, []string{"DATABASE_URL"}) {
+		t.Fatalf("Failed to merge the collected config vars. Expected: %v Actual: %v %v %v", wantConfigs, configs, secrets, unset)
+	}
+
+	processes := addCollectedHerokuProcesses([]irtypes.Process{{Name: "web", Command: "npm start"}}, app)
+	wantProcesses := []irtypes.Process{{Name: "web", Command: "npm start"}, {Name: "clock", Command: "node clock.js"}}
+	if !reflect.DeepEqual(processes, wantProcesses) {
+		t.Fatalf("Failed to add the collected process types. Expected: %+v Actual: %+v", wantProcesses, processes)
+	}
+	formation := getHerokuFormation(appJSON, app)
+	if *formation["web"].Quantity != 2 || formation["web"].Size != "standard-2x" || *formation["clock"].Quantity != 1 {
+		t.Fatalf("Failed to get the collected formation. Actual: %+v", formation)
+	}
+
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	web := irtypes.NewServiceWithName("tickets")
+	web.Containers = []core.Container{{Name: "tickets"}}
+	ir.Services[web.Name] = web
+	addHerokuProcesses(&ir, web.Name, processes, formation)
+	if memory := ir.Services["tickets"].Containers[0].Resources.Limits[core.ResourceMemory]; memory.String() != "1Gi" {
+		t.Fatalf("Failed to set the memory of the standard-2x dyno. Actual: %s", memory.String())
+	}
+	if memory := ir.Services["tickets-clock"].Containers[0].Resources.Limits[core.ResourceMemory]; memory.String() != "512Mi" {
+		t.Fatalf("Failed to set the memory of the basic dyno. Actual: %s", memory.String())
+	}
+
+	wantTODO := "Replace the Heroku add-ons: postgresql-curved-12345 (heroku-postgresql): A managed PostgreSQL database, and update the config vars DATABASE_URL"
+	if todo := getHerokuAddOnsTODO(app.AddOns); todo != wantTODO {
+		t.Fatalf("Failed to suggest the replacements of the add-ons. Expected: %s Actual: %s", wantTODO, todo)
+	}
+}
+
+func TestHerokuAppJSONOnly(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"api/app.json": `{
+  "name": "api",
+  "addons": ["heroku-postgresql:essential-0", {"plan": "heroku-redis", "as": "cache"}],
+  "scripts": {"postdeploy": {"command": "bundle exec rake db:seed", "size": "standard-2x"}}
+}`,
+		"mobile/app.json": `{"expo": {"name": "mobile", "slug": "mobile"}}`,
+	})
+	if !isHerokuAppJSON(filepath.Join(dir, "api", "app.json")) || isHerokuAppJSON(filepath.Join(dir, "mobile", "app.json")) {
+		t.Fatalf("Expected only the app.json with the fields of Heroku to be detected")
+	}
+	appJSON, _ := readHerokuApp(getHerokuAppFiles(filepath.Join(dir, "api")))
+	addOns := getHerokuAppJSONAddOns(appJSON)
+	if len(addOns) != 2 || addOns[0].Service != "heroku-postgresql" || addOns[1].Service != "heroku-redis" || !reflect.DeepEqual(addOns[1].ConfigVars, []string{"CACHE_URL"}) {
+		t.Fatalf("Failed to read the add-ons of app.json. Actual: %+v", addOns)
+	}
+	if addOns[0].Replacement != collecttypes.HerokuAddOnReplacements["heroku-postgresql"] {
+		t.Fatalf("Failed to suggest the replacement of the add-on. Actual: %s", addOns[0].Replacement)
+	}
+
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	web := irtypes.NewServiceWithName("api")
+	web.Containers = []core.Container{{Name: "api", Image: "api:latest"}}
+	ir.Services[web.Name] = web
+	processes := []irtypes.Process{{Name: postdeployProcessType, Command: appJSON.Scripts[postdeployProcessType].Command}}
+	addHerokuProcesses(&ir, web.Name, processes, getHerokuFormation(appJSON, collecttypes.HerokuApplication{}))
+	postdeploy, ok := ir.Services["api-postdeploy"]
+	if !ok || postdeploy.RestartPolicy != core.RestartPolicyOnFailure || !reflect.DeepEqual(postdeploy.Containers[0].Command, []string{"/bin/sh", "-c", "bundle exec rake db:seed"}) {
+		t.Fatalf("Failed to translate the postdeploy script to a job. Actual: %+v", postdeploy)
+	}
+	if memory := postdeploy.Containers[0].Resources.Limits[core.ResourceMemory]; memory.String() != "1Gi" {
+		t.Fatalf("Failed to set the memory of the dyno of the postdeploy script. Actual: %s", memory.String())
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	webXMLName         = "web.xml"
+	ejbJarXMLName      = "ejb-jar.xml"
+	applicationXMLName = "application.xml"
+
+	// jeeResourcesTODOKey flags the services whose resource references have to be filled in the secret
+	jeeResourcesTODOKey = common.TODOAnnotation + "jeeresources"
+	// jeeJMSTODOKey flags the services which use JMS destinations that have to be provisioned
+	jeeJMSTODOKey = common.TODOAnnotation + "jeejms"
+	// jeeSecurityRolesTODOKey flags the services whose security roles have to be mapped to users and groups
+	jeeSecurityRolesTODOKey = common.TODOAnnotation + "jeesecurityroles"
+	// jeeAppServerTODOKey flags the services which use features specific to their app server
+	jeeAppServerTODOKey = common.TODOAnnotation + "jeeappserver"
+)
+
+var (
+	// jeeAppServerDescriptors maps the deployment descriptors specific to an app server to the app server
+	jeeAppServerDescriptors = map[string]string{
+		"weblogic.xml":                   "WebLogic",
+		"weblogic-application.xml":       "WebLogic",
+		"weblogic-ejb-jar.xml":           "WebLogic",
+		"ibm-web-bnd.xml":                "WebSphere",
+		"ibm-web-bnd.xmi":                "WebSphere",
+		"ibm-web-ext.xml":                "WebSphere",
+		"ibm-web-ext.xmi":                "WebSphere",
+		"ibm-application-bnd.xml":        "WebSphere",
+		"ibm-application-bnd.xmi":        "WebSphere",
+		"ibm-ejb-jar-bnd.xml":            "WebSphere",
+		"ibm-ejb-jar-bnd.xmi":            "WebSphere",
+		"jboss-web.xml":                  "JBoss",
+		"jboss-deployment-structure.xml": "JBoss",
+		"jboss-ejb3.xml":                 "JBoss",
+	}
+	// jeeAppServerFeatures maps the elements of the app server descriptors to the features which need manual work
+	jeeAppServerFeatures = map[string]string{
+		"session-descriptor":        "session settings",
+		"work-manager":              "work managers",
+		"wl-dispatch-policy":        "work managers",
+		"container-descriptor":      "class loading settings",
+		"exclusions":                "class loading settings",
+		"security-role-assignment":  "security role assignments",
+		"run-as-role-assignment":    "run-as role assignments",
+		"security-role":             "security role mappings",
+		"security-domain":           "security domains",
+		"resource-description":      "JNDI resource bindings",
+		"resource-env-description":  "JNDI resource bindings",
+		"resource-ref":              "JNDI resource bindings",
+		"resRefBindings":            "JNDI resource bindings",
+		"virtual-host":              "virtual hosts",
+		"virtual-directory-mapping": "virtual directories",
+	}
+	// jeeDataSourceTypes are the types of the resource references which connect to a database
+	jeeDataSourceTypes = []string{"javax.sql.DataSource", "javax.sql.XADataSource", "javax.sql.ConnectionPoolDataSource"}
+	// jeeJMSDestinationTypes are the types of the JMS queues and topics
+	jeeJMSDestinationTypes = []string{"javax.jms.Queue", "javax.jms.Topic", "jakarta.jms.Queue", "jakarta.jms.Topic"}
+	// jeeMessageDrivenDestinationProperties are the activation config properties of the message driven beans naming their destination
+	jeeMessageDrivenDestinationProperties = []string{"destination", "destinationLookup", "destinationName"}
+
+	jeeEnvVarRegex = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// jeeDescriptors is what is extracted from the deployment descriptors of a JEE app
+type jeeDescriptors struct {
+	ContextRoots      []string
+	ResourceRefs      []jeeResourceRef
+	JMSDestinations   []string
+	SecurityRoles     []string
+	AppServerFeatures []string
+}
+
+// jeeResourceRef is a reference of the app to a resource, like a data source, looked up using JNDI
+type jeeResourceRef struct {
+	Name string
+	Type string
+}
+
+// xmlNode is a generic xml element, which is used since the descriptors exist in several versions and namespaces
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+// find returns the descendants of the element with the local name
+func (n xmlNode) find(name string) []xmlNode {
+	nodes := []xmlNode{}
+	for _, child := range n.Nodes {
+		if child.XMLName.Local == name {
+			nodes = append(nodes, child)
+		}
+		nodes = append(nodes, child.find(name)...)
+	}
+	return nodes
+}
+
+// text returns the trimmed content of the first descendant of the element with the local name
+func (n xmlNode) text(name string) string {
+	nodes := n.find(name)
+	if len(nodes) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(nodes[0].Content)
+}
+
+// attr returns the value of the attribute of the element with the local name
+func (n xmlNode) attr(name string) string {
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == name {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+// getJEEDescriptors reads the deployment descriptors of a JEE app in the source of a service
+func getJEEDescriptors(service plantypes.Service) jeeDescriptors {
+	descriptors := jeeDescriptors{}
+	for _, dir := range service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] {
+		getJEEDescriptorsInDir(dir, &descriptors)
+	}
+	return descriptors
+}
+
+func getJEEDescriptorsInDir(dir string, descriptors *jeeDescriptors) {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Skipping the path %s while looking for deployment descriptors. Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && common.IsStringPresent(sessionHintSkipDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		appServer, isAppServerDescriptor := jeeAppServerDescriptors[info.Name()]
+		parentDir := filepath.Base(filepath.Dir(path))
+		isStandardDescriptor := (info.Name() == webXMLName && parentDir == "WEB-INF") || ((info.Name() == ejbJarXMLName || info.Name() == applicationXMLName) && parentDir == "META-INF")
+		if !isStandardDescriptor && !isAppServerDescriptor {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Debugf("Failed to read the deployment descriptor at path %s . Error: %q", path, err)
+			return nil
+		}
+		root := xmlNode{}
+		if err := xml.Unmarshal(content, &root); err != nil {
+			log.Debugf("Failed to parse the deployment descriptor at path %s . Error: %q", path, err)
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			relPath = path
+		}
+		log.Debugf("Found the deployment descriptor %s", path)
+		if isAppServerDescriptor {
+			addJEEAppServerDescriptor(descriptors, root, appServer, filepath.ToSlash(relPath))
+		} else {
+			addJEEStandardDescriptor(descriptors, root)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("Failed to look for deployment descriptors in the directory %s . Error: %q", dir, err)
+	}
+}
+
+// addJEEStandardDescriptor adds the resource references, the JMS destinations, the security roles and the context roots of web.xml, ejb-jar.xml or application.xml
+func addJEEStandardDescriptor(descriptors *jeeDescriptors, root xmlNode) {
+	for _, web := range root.find("web") {
+		descriptors.addContextRoot(web.text("context-root"))
+	}
+	for _, ref := range root.find("resource-ref") {
+		descriptors.addResourceRef(jeeResourceRef{Name: ref.text("res-ref-name"), Type: ref.text("res-type")})
+	}
+	for _, ref := range root.find("resource-env-ref") {
+		refType := ref.text("resource-env-ref-type")
+		if common.IsStringPresent(jeeJMSDestinationTypes, refType) {
+			descriptors.addJMSDestination(ref.text("resource-env-ref-name"), refType)
+		}
+	}
+	for _, ref := range root.find("message-destination-ref") {
+		descriptors.addJMSDestination(ref.text("message-destination-ref-name"), ref.text("message-destination-type"))
+	}
+	for _, bean := range root.find("message-driven") {
+		destination := bean.text("message-destination-link")
+		for _, property := range bean.find("activation-config-property") {
+			if common.IsStringPresent(jeeMessageDrivenDestinationProperties, property.text("activation-config-property-name")) {
+				destination = property.text("activation-config-property-value")
+			}
+		}
+		descriptors.addJMSDestination(destination, bean.text("message-destination-type"))
+	}
+	for _, role := range root.find("security-role") {
+		if roleName := role.text("role-name"); roleName != "" && !common.IsStringPresent(descriptors.SecurityRoles, roleName) {
+			descriptors.SecurityRoles = append(descriptors.SecurityRoles, roleName)
+		}
+	}
+}
+
+// addJEEAppServerDescriptor adds the context root and the app server specific features of a descriptor like weblogic.xml
+func addJEEAppServerDescriptor(descriptors *jeeDescriptors, root xmlNode, appServer, relPath string) {
+	descriptors.addContextRoot(root.text("context-root"))
+	for _, contextRoot := range root.find("context-root") {
+		descriptors.addContextRoot(contextRoot.attr("uri"))
+	}
+	features := []string{}
+	for element, feature := range jeeAppServerFeatures {
+		if len(root.find(element)) > 0 && !common.IsStringPresent(features, feature) {
+			features = append(features, feature)
+		}
+	}
+	sort.Strings(features)
+	if len(features) == 0 {
+		descriptors.AppServerFeatures = append(descriptors.AppServerFeatures, fmt.Sprintf("%s descriptor (%s)", appServer, relPath))
+		return
+	}
+	for _, feature := range features {
+		descriptors.AppServerFeatures = append(descriptors.AppServerFeatures, fmt.Sprintf("%s %s (%s)", appServer, feature, relPath))
+	}
+}
+
+func (descriptors *jeeDescriptors) addContextRoot(contextRoot string) {
+	contextRoot = strings.Trim(strings.TrimSpace(contextRoot), "/")
+	if contextRoot == "" {
+		return
+	}
+	contextRoot = "/" + contextRoot
+	if !common.IsStringPresent(descriptors.ContextRoots, contextRoot) {
+		descriptors.ContextRoots = append(descriptors.ContextRoots, contextRoot)
+	}
+}
+
+func (descriptors *jeeDescriptors) addResourceRef(ref jeeResourceRef) {
+	if ref.Name == "" {
+		return
+	}
+	for _, r := range descriptors.ResourceRefs {
+		if r.Name == ref.Name {
+			return
+		}
+	}
+	descriptors.ResourceRefs = append(descriptors.ResourceRefs, ref)
+}
+
+// isDataSource returns true if the resource reference connects to a database
+func (ref jeeResourceRef) isDataSource() bool {
+	return common.IsStringPresent(jeeDataSourceTypes, ref.Type)
+}
+
+func (descriptors *jeeDescriptors) addJMSDestination(name, destinationType string) {
+	if name == "" {
+		return
+	}
+	if destinationType != "" {
+		name = fmt.Sprintf("%s (%s)", name, destinationType[strings.LastIndex(destinationType, ".")+1:])
+	}
+	if !common.IsStringPresent(descriptors.JMSDestinations, name) {
+		descriptors.JMSDestinations = append(descriptors.JMSDestinations, name)
+	}
+}
+
+// getJEEResourceEnvVarPrefix returns the prefix of the env vars holding the connection of a resource reference, like JDBC_TICKETSDB for jdbc/TicketsDB
+func getJEEResourceEnvVarPrefix(ref jeeResourceRef) string {
+	return strings.Trim(strings.ToUpper(jeeEnvVarRegex.ReplaceAllString(ref.Name, "_")), "_")
+}
+
+// getJEEResourceEnvVarNames returns the env vars holding the connection of a resource reference, like JDBC_TICKETSDB_URL for jdbc/TicketsDB
+func getJEEResourceEnvVarNames(ref jeeResourceRef) []string {
+	prefix := getJEEResourceEnvVarPrefix(ref)
+	if ref.isDataSource() {
+		return []string{prefix + "_URL", prefix + "_USERNAME", prefix + "_PASSWORD"}
+	}
+	return []string{prefix + "_URL"}
+}
+
+// addJEEDescriptorsToService uses the context root as the path of the ingress, adds a secret holding the connections of the resource references,
+// and flags the JMS destinations, the security roles and the app server specific features which need manual work
+func addJEEDescriptorsToService(ir *irtypes.IR, irService *irtypes.Service, descriptors jeeDescriptors) {
+	if len(descriptors.ContextRoots) > 0 {
+		irService.ContextRoot = descriptors.ContextRoots[0]
+		if len(descriptors.ContextRoots) > 1 {
+			log.Warnf("The service %s has the context roots %v . Only the first is used as the path of the ingress.", irService.Name, descriptors.ContextRoots)
+		}
+	}
+	todos := map[string]string{}
+	if len(descriptors.ResourceRefs) > 0 {
+		secretName := common.MakeFileNameCompliant(irService.Name + "-resources")
+		refNames := []string{}
+		content := map[string][]byte{}
+		for _, ref := range descriptors.ResourceRefs {
+			refNames = append(refNames, ref.Name)
+			for _, envVarName := range getJEEResourceEnvVarNames(ref) {
+				content[envVarName] = []byte{}
+				if len(irService.Containers) == 0 {
+					continue
+				}
+				irService.Containers[0].Env = append(irService.Containers[0].Env, core.EnvVar{Name: envVarName, ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+					LocalObjectReference: core.LocalObjectReference{Name: secretName},
+					Key:                  envVarName,
+				}}})
+			}
+		}
+		ir.AddStorage(irtypes.Storage{Name: secretName, StorageType: irtypes.SecretKind, Content: content})
+		todos[jeeResourcesTODOKey] = fmt.Sprintf("Fill the secret %s with the connections of the resources %s, and configure the app server in the image to create them from the env vars.", secretName, strings.Join(refNames, ", "))
+	}
+	if len(descriptors.JMSDestinations) > 0 {
+		todos[jeeJMSTODOKey] = fmt.Sprintf("Provision the JMS destinations %s on a message broker, and configure the app server in the image to connect to it.", strings.Join(descriptors.JMSDestinations, ", "))
+	}
+	if len(descriptors.SecurityRoles) > 0 {
+		todos[jeeSecurityRolesTODOKey] = fmt.Sprintf("Map the security roles %s to the users and groups of the identity provider.", strings.Join(descriptors.SecurityRoles, ", "))
+	}
+	if len(descriptors.AppServerFeatures) > 0 {
+		todos[jeeAppServerTODOKey] = fmt.Sprintf("Migrate the app server specific features: %s.", strings.Join(descriptors.AppServerFeatures, ", "))
+	}
+	if len(todos) > 0 {
+		irService.Annotations = common.MergeStringMaps(irService.Annotations, todos)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	testWebXML = `<?xml version="1.0" encoding="UTF-8"?>
+<web-app xmlns="http://xmlns.jcp.org/xml/ns/javaee" version="3.1">
+  <resource-ref>
+    <res-ref-name>jdbc/TicketsDB</res-ref-name>
+    <res-type>javax.sql.DataSource</res-type>
+  </resource-ref>
+  <resource-env-ref>
+    <resource-env-ref-name>jms/Orders</resource-env-ref-name>
+    <resource-env-ref-type>javax.jms.Queue</resource-env-ref-type>
+  </resource-env-ref>
+  <security-role>
+    <role-name>admin</role-name>
+  </security-role>
+</web-app>`
+	testEjbJarXML = `<ejb-jar xmlns="http://xmlns.jcp.org/xml/ns/javaee" version="3.2">
+  <enterprise-beans>
+    <message-driven>
+      <ejb-name>OrderListener</ejb-name>
+      <message-destination-type>javax.jms.Topic</message-destination-type>
+      <activation-config>
+        <activation-config-property>
+          <activation-config-property-name>destinationLookup</activation-config-property-name>
+          <activation-config-property-value>jms/Events</activation-config-property-value>
+        </activation-config-property>
+      </activation-config>
+    </message-driven>
+    <session>
+      <ejb-name>Mailer</ejb-name>
+      <resource-ref>
+        <res-ref-name>mail/Notifications</res-ref-name>
+        <res-type>javax.mail.Session</res-type>
+      </resource-ref>
+    </session>
+  </enterprise-beans>
+  <assembly-descriptor>
+    <security-role>
+      <role-name>user</role-name>
+    </security-role>
+  </assembly-descriptor>
+</ejb-jar>`
+	testWeblogicXML = `<weblogic-web-app xmlns="http://xmlns.oracle.com/weblogic/weblogic-web-app">
+  <context-root>/tickets/</context-root>
+  <session-descriptor>
+    <persistent-store-type>replicated_if_clustered</persistent-store-type>
+  </session-descriptor>
+  <security-role-assignment>
+    <role-name>admin</role-name>
+    <principal-name>Administrators</principal-name>
+  </security-role-assignment>
+</weblogic-web-app>`
+)
+
+func TestGetJEEDescriptors(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"src/main/webapp/WEB-INF/web.xml":         testWebXML,
+		"src/main/webapp/WEB-INF/weblogic.xml":    testWeblogicXML,
+		"src/main/resources/META-INF/ejb-jar.xml": testEjbJarXML,
+		"target/tickets/WEB-INF/web.xml":          `<web-app><security-role><role-name>stale</role-name></security-role></web-app>`,
+		"src/main/resources/web.xml":              `<web-app><security-role><role-name>unused</role-name></security-role></web-app>`,
+	})
+	service := plantypes.NewService("tickets", plantypes.Any2KubeTranslation)
+	service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] = []string{dir}
+	descriptors := getJEEDescriptors(service)
+	want := jeeDescriptors{
+		ContextRoots:      []string{"/tickets"},
+		ResourceRefs:      []jeeResourceRef{{Name: "mail/Notifications", Type: "javax.mail.Session"}, {Name: "jdbc/TicketsDB", Type: "javax.sql.DataSource"}},
+		JMSDestinations:   []string{"jms/Events (Topic)", "jms/Orders (Queue)"},
+		SecurityRoles:     []string{"user", "admin"},
+		AppServerFeatures: []string{"WebLogic security role assignments (src/main/webapp/WEB-INF/weblogic.xml)", "WebLogic session settings (src/main/webapp/WEB-INF/weblogic.xml)"},
+	}
+	if !reflect.DeepEqual(descriptors, want) {
+		t.Fatalf("Failed to read the deployment descriptors. Expected: %+v Actual: %+v", want, descriptors)
+	}
+
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	irService := irtypes.NewServiceWithName("tickets")
+	irService.Containers = []core.Container{{Name: "tickets"}}
+	addJEEDescriptorsToService(&ir, &irService, descriptors)
+	if irService.ContextRoot != "/tickets" {
+		t.Fatalf("Failed to set the context root of the service. Actual: %s", irService.ContextRoot)
+	}
+	wantEnv := []string{"MAIL_NOTIFICATIONS_URL", "JDBC_TICKETSDB_URL", "JDBC_TICKETSDB_USERNAME", "JDBC_TICKETSDB_PASSWORD"}
+	env := []string{}
+	for _, envVar := range irService.Containers[0].Env {
+		if envVar.ValueFrom == nil || envVar.ValueFrom.SecretKeyRef == nil || envVar.ValueFrom.SecretKeyRef.Name != "tickets-resources" {
+			t.Fatalf("Expected the env var %s to be read from the secret tickets-resources. Actual: %+v", envVar.Name, envVar.ValueFrom)
+		}
+		env = append(env, envVar.Name)
+	}
+	if !reflect.DeepEqual(env, wantEnv) {
+		t.Fatalf("Failed to add the env vars of the resource references. Expected: %v Actual: %v", wantEnv, env)
+	}
+	if len(ir.Storages) != 1 || ir.Storages[0].Name != "tickets-resources" || ir.Storages[0].StorageType != irtypes.SecretKind || len(ir.Storages[0].Content) != 4 {
+		t.Fatalf("Failed to add the secret of the resource references. Actual: %+v", ir.Storages)
+	}
+	for _, key := range []string{jeeResourcesTODOKey, jeeJMSTODOKey, jeeSecurityRolesTODOKey, jeeAppServerTODOKey} {
+		if irService.Annotations[key] == "" {
+			t.Fatalf("Expected the annotation %s on the service. Actual: %v", key, irService.Annotations)
+		}
+	}
+}
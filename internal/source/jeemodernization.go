@@ -0,0 +1,290 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer/scripts"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// jeeServerConfigDir is the directory, next to the Dockerfile, which the Dockerfiles of the modernization containerizers copy into the app server
+	jeeServerConfigDir = "m2kserverconfig"
+	// jeeMigrationReportSuffix is the suffix of the report listing the migration gaps of a service
+	jeeMigrationReportSuffix = "-migration-gaps.md"
+	// jeeMigrationMaxFileSize is the size above which the files are not checked for migration gaps
+	jeeMigrationMaxFileSize = 1024 * 1024
+
+	// jeeMigrationTODOKey flags the services whose gaps of the migration to the target app server have to be fixed
+	jeeMigrationTODOKey = common.TODOAnnotation + "jeemigration"
+)
+
+// jeeModernizationTarget is an app server to which the traditional WebSphere and WebLogic apps are migrated
+type jeeModernizationTarget struct {
+	Name string
+	// ServerConfigName is the name of the file in the server config directory, generated from the ServerConfigTemplate
+	ServerConfigName     string
+	ServerConfigTemplate string
+}
+
+// jeeModernizationTargets maps the Dockerfile containerizers modernizing the traditional apps to their app servers
+var jeeModernizationTargets = map[string]jeeModernizationTarget{
+	"java-war-openliberty": {Name: "Open Liberty", ServerConfigName: "server.xml", ServerConfigTemplate: scripts.LibertyServer_xml},
+	"java-war-wildfly":     {Name: "WildFly", ServerConfigName: "server.cli", ServerConfigTemplate: scripts.WildFlyServer_cli},
+}
+
+// jeeMigrationRule is a check for a use of the traditional app servers which has to be changed when migrating the app
+type jeeMigrationRule struct {
+	ID          string
+	Description string
+	// Files are the glob patterns of the names of the files which are checked
+	Files []string
+	// Patterns are the strings whose occurrence in a line of the files is a gap
+	Patterns []string
+}
+
+var (
+	jeeSourceFiles = []string{"*.java", "*.jsp"}
+	jeeConfigFiles = []string{"*.xml", "*.properties"}
+
+	// jeeMigrationRules are the rules checking the source of the traditional apps
+	jeeMigrationRules = []jeeMigrationRule{{
+		ID:          "websphere-apis",
+		Description: "The proprietary APIs of WebSphere are not available on the target app server.",
+		Files:       jeeSourceFiles,
+		Patterns:    []string{"com.ibm.websphere.", "com.ibm.ws.", "com.ibm.wsspi."},
+	}, {
+		ID:          "weblogic-apis",
+		Description: "The proprietary APIs of WebLogic are not available on the target app server.",
+		Files:       jeeSourceFiles,
+		Patterns:    []string{"import weblogic.", "weblogic.jndi.WLInitialContextFactory"},
+	}, {
+		ID:          "remote-protocols",
+		Description: "The T3 and IIOP protocols of the traditional app servers have to be replaced by HTTP or a message broker.",
+		Files:       append(jeeSourceFiles, jeeConfigFiles...),
+		Patterns:    []string{"t3://", "t3s://", "iiop://", "corbaloc:", "corbaname:"},
+	}, {
+		ID:          "remote-jndi",
+		Description: "The lookups of the JNDI resources of a remote app server have to be replaced by the resources of the target app server.",
+		Files:       append(jeeSourceFiles, jeeConfigFiles...),
+		Patterns:    []string{"Context.PROVIDER_URL", "java.naming.provider.url"},
+	}, {
+		ID:          "ejb-entity-beans",
+		Description: "The EJB 2.x entity beans are not supported by the target app server and have to be migrated to JPA.",
+		Files:       append(jeeSourceFiles, "ejb-jar.xml"),
+		Patterns:    []string{"javax.ejb.EntityBean", "<entity>"},
+	}, {
+		ID:          "ejb-homes",
+		Description: "The home interfaces of the EJB 2.x session beans have to be migrated to the business interfaces of EJB 3.",
+		Files:       append(jeeSourceFiles, "ejb-jar.xml"),
+		Patterns:    []string{"javax.ejb.EJBHome", "javax.ejb.EJBLocalHome", "<home>", "<local-home>"},
+	}, {
+		ID:          "jax-rpc",
+		Description: "The JAX-RPC web services are not supported by the target app server and have to be migrated to JAX-WS or JAX-RS.",
+		Files:       append(jeeSourceFiles, "webservices.xml"),
+		Patterns:    []string{"javax.xml.rpc."},
+	}, {
+		ID:          "commonj",
+		Description: "The CommonJ work managers and timers have to be replaced by the managed executors of the Concurrency Utilities.",
+		Files:       append(jeeSourceFiles, jeeConfigFiles...),
+		Patterns:    []string{"commonj.work.", "commonj.timers."},
+	}, {
+		ID:          "ibm-mq",
+		Description: "The IBM MQ client needs the IBM MQ resource adapter in the image.",
+		Files:       append(jeeSourceFiles, jeeConfigFiles...),
+		Patterns:    []string{"com.ibm.mq."},
+	}}
+)
+
+// jeeMigrationGap is a line of the source of an app matching a migration rule
+type jeeMigrationGap struct {
+	Rule string
+	Path string
+	Line int
+}
+
+// matches returns true if the rule checks the file with the name
+func (r jeeMigrationRule) matches(fileName string) bool {
+	for _, pattern := range r.Files {
+		if matched, err := filepath.Match(pattern, fileName); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// getJEEMigrationGaps checks the source directories of an app using the migration rules
+func getJEEMigrationGaps(dirs []string) []jeeMigrationGap {
+	gaps := []jeeMigrationGap{}
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				log.Debugf("Skipping the path %s while looking for migration gaps. Error: %q", path, err)
+				return nil
+			}
+			if info.IsDir() {
+				if path != dir && common.IsStringPresent(sessionHintSkipDirs, info.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rules := []jeeMigrationRule{}
+			for _, rule := range jeeMigrationRules {
+				if rule.matches(info.Name()) {
+					rules = append(rules, rule)
+				}
+			}
+			if len(rules) == 0 || info.Size() > jeeMigrationMaxFileSize {
+				return nil
+			}
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				relPath = path
+			}
+			gaps = append(gaps, getJEEMigrationGapsInFile(path, filepath.ToSlash(relPath), rules)...)
+			return nil
+		})
+		if err != nil {
+			log.Debugf("Failed to look for migration gaps in the directory %s . Error: %q", dir, err)
+		}
+	}
+	return gaps
+}
+
+func getJEEMigrationGapsInFile(path, relPath string, rules []jeeMigrationRule) []jeeMigrationGap {
+	gaps := []jeeMigrationGap{}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Debugf("Failed to open the file at path %s . Error: %q", path, err)
+		return gaps
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		for _, rule := range rules {
+			for _, pattern := range rule.Patterns {
+				if strings.Contains(scanner.Text(), pattern) {
+					gaps = append(gaps, jeeMigrationGap{Rule: rule.ID, Path: relPath, Line: line})
+					break
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Debugf("Failed to read the file at path %s . Error: %q", path, err)
+	}
+	return gaps
+}
+
+// getJEEMigrationReport lists the gaps of the migration of a service to the target app server, grouped by rule
+func getJEEMigrationReport(serviceName string, target jeeModernizationTarget, gaps []jeeMigrationGap) string {
+	report := strings.Builder{}
+	report.WriteString(fmt.Sprintf("# Migration of %s to %s\n\n", serviceName, target.Name))
+	if len(gaps) == 0 {
+		report.WriteString("No gaps were found by the rules.\n")
+		return report.String()
+	}
+	report.WriteString(fmt.Sprintf("%d gaps were found by the rules. They have to be fixed for the app to run on %s.\n", len(gaps), target.Name))
+	for _, rule := range jeeMigrationRules {
+		locations := []string{}
+		for _, gap := range gaps {
+			if gap.Rule == rule.ID {
+				locations = append(locations, fmt.Sprintf("- %s:%d\n", gap.Path, gap.Line))
+			}
+		}
+		if len(locations) == 0 {
+			continue
+		}
+		report.WriteString(fmt.Sprintf("\n## %s\n\n%s\n\n%s", rule.ID, rule.Description, strings.Join(locations, "")))
+	}
+	return report.String()
+}
+
+// addJEEModernization adds the configuration of the target app server and the report of the migration gaps to the container of a
+// traditional app, if it is containerized using one of the modernization containerizers, like the one targeting Open Liberty
+func addJEEModernization(ir *irtypes.IR, irService *irtypes.Service, plan plantypes.Plan, service plantypes.Service, descriptors jeeDescriptors) error {
+	if service.ContainerBuildType != plantypes.DockerFileContainerBuildTypeValue || len(service.ContainerizationTargetOptions) == 0 || len(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType]) == 0 {
+		return nil
+	}
+	target, ok := jeeModernizationTargets[filepath.Base(service.ContainerizationTargetOptions[0])]
+	if !ok {
+		return nil
+	}
+	containerIdx := -1
+	for i, container := range ir.Containers {
+		if common.IsStringPresent(container.ImageNames, service.Image) {
+			containerIdx = i
+			break
+		}
+	}
+	if containerIdx == -1 {
+		return fmt.Errorf("the container of the service %s with the image %s was not found", service.ServiceName, service.Image)
+	}
+	relOutputPath, err := plan.GetRelativePath(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType][0])
+	if err != nil {
+		return err
+	}
+
+	type dataSource struct {
+		Name         string
+		EnvVarPrefix string
+	}
+	dataSources := []dataSource{}
+	dataSourceNames := []string{}
+	for _, ref := range descriptors.ResourceRefs {
+		if ref.isDataSource() {
+			dataSources = append(dataSources, dataSource{Name: ref.Name, EnvVarPrefix: getJEEResourceEnvVarPrefix(ref)})
+			dataSourceNames = append(dataSourceNames, ref.Name)
+		}
+	}
+	serverConfig, err := common.GetStringFromTemplate(target.ServerConfigTemplate, struct {
+		ServiceName string
+		DataSources []dataSource
+	}{
+		ServiceName: service.ServiceName,
+		DataSources: dataSources,
+	})
+	if err != nil {
+		return err
+	}
+	ir.Containers[containerIdx].AddFile(filepath.Join(relOutputPath, jeeServerConfigDir, target.ServerConfigName), serverConfig)
+
+	gaps := getJEEMigrationGaps(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType])
+	reportPath := filepath.Join(relOutputPath, service.ServiceName+jeeMigrationReportSuffix)
+	ir.Containers[containerIdx].AddFile(reportPath, getJEEMigrationReport(service.ServiceName, target, gaps))
+
+	todos := []string{}
+	if len(gaps) > 0 {
+		todos = append(todos, fmt.Sprintf("Fix the %d gaps of the migration to %s listed in %s.", len(gaps), target.Name, filepath.ToSlash(reportPath)))
+	}
+	if len(dataSources) > 0 {
+		todos = append(todos, fmt.Sprintf("Add the JDBC drivers of the data sources %s to the image, as described in %s.", strings.Join(dataSourceNames, ", "), filepath.ToSlash(filepath.Join(relOutputPath, jeeServerConfigDir, target.ServerConfigName))))
+	}
+	if len(todos) > 0 {
+		irService.Annotations = common.MergeStringMaps(irService.Annotations, map[string]string{jeeMigrationTODOKey: strings.Join(todos, " ")})
+	}
+	return nil
+}
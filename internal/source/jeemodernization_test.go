@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const testLookupJava = `package com.acme.tickets;
+
+import java.util.Hashtable;
+import javax.naming.Context;
+import weblogic.jndi.Environment;
+
+public class Lookup {
+    public Context getContext() throws Exception {
+        Environment env = new Environment();
+        env.setProviderUrl("t3://legacy:7001");
+        return env.getInitialContext();
+    }
+}
+`
+
+func TestGetJEEMigrationGaps(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"src/main/java/com/acme/tickets/Lookup.java": testLookupJava,
+		"src/main/resources/jndi.properties":         "java.naming.provider.url=iiop://legacy:2809\n",
+		"target/classes/jndi.properties":             "java.naming.provider.url=iiop://stale:2809\n",
+		"README.md":                                  "Migrated from com.ibm.websphere.\n",
+	})
+	want := []jeeMigrationGap{
+		{Rule: "weblogic-apis", Path: "src/main/java/com/acme/tickets/Lookup.java", Line: 5},
+		{Rule: "remote-protocols", Path: "src/main/java/com/acme/tickets/Lookup.java", Line: 10},
+		{Rule: "remote-protocols", Path: "src/main/resources/jndi.properties", Line: 1},
+		{Rule: "remote-jndi", Path: "src/main/resources/jndi.properties", Line: 1},
+	}
+	if gaps := getJEEMigrationGaps([]string{dir}); !reflect.DeepEqual(gaps, want) {
+		t.Fatalf("Failed to check the source for migration gaps. Expected: %+v Actual: %+v", want, gaps)
+	}
+}
+
+func TestAddJEEModernization(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"WEB-INF/web.xml":                            testWebXML,
+		"WEB-INF/weblogic.xml":                       testWeblogicXML,
+		"src/main/java/com/acme/tickets/Lookup.java": testLookupJava,
+	})
+	plan := plantypes.NewPlan()
+	plan.Spec.Inputs.RootDir = filepath.Dir(dir)
+	service := plantypes.NewService("tickets", plantypes.Any2KubeTranslation)
+	service.Image = "tickets:latest"
+	service.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+	service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] = []string{dir}
+	descriptors := getJEEDescriptors(service)
+
+	for _, tc := range []struct {
+		containerizer string
+		configName    string
+		wantConfig    []string
+	}{{
+		containerizer: "java-war-openliberty",
+		configName:    "server.xml",
+		wantConfig:    []string{`<dataSource id="JDBC_TICKETSDB" jndiName="jdbc/TicketsDB">`, `URL="${env.JDBC_TICKETSDB_URL}"`},
+	}, {
+		containerizer: "java-war-wildfly",
+		configName:    "server.cli",
+		wantConfig:    []string{"data-source add --name=JDBC_TICKETSDB --jndi-name=java:/jdbc/TicketsDB", "--connection-url=${env.JDBC_TICKETSDB_URL}"},
+	}} {
+		t.Run(tc.containerizer, func(t *testing.T) {
+			service.ContainerizationTargetOptions = []string{filepath.Join(t.TempDir(), tc.containerizer)}
+			ir := irtypes.NewIR(plan)
+			ir.AddContainer(irtypes.NewContainer(plantypes.DockerFileContainerBuildTypeValue, service.Image, true))
+			irService := irtypes.NewServiceWithName("tickets")
+			if err := addJEEModernization(&ir, &irService, plan, service, descriptors); err != nil {
+				t.Fatalf("Failed to add the configuration of the app server. Error: %q", err)
+			}
+			relDir := filepath.Base(dir)
+			config := ir.Containers[0].NewFiles[filepath.Join(relDir, jeeServerConfigDir, tc.configName)]
+			for _, want := range tc.wantConfig {
+				if !strings.Contains(config, want) {
+					t.Fatalf("Expected %s in the configuration of the app server. Actual: %s", want, config)
+				}
+			}
+			report := ir.Containers[0].NewFiles[filepath.Join(relDir, "tickets"+jeeMigrationReportSuffix)]
+			if !strings.Contains(report, "## weblogic-apis") || !strings.Contains(report, "- src/main/java/com/acme/tickets/Lookup.java:10") {
+				t.Fatalf("Failed to write the migration gaps in the report. Actual: %s", report)
+			}
+			if todo := irService.Annotations[jeeMigrationTODOKey]; !strings.Contains(todo, "2 gaps") || !strings.Contains(todo, "jdbc/TicketsDB") {
+				t.Fatalf("Expected the migration gaps and the JDBC drivers in the annotation %s . Actual: %v", jeeMigrationTODOKey, irService.Annotations)
+			}
+		})
+	}
+
+	service.ContainerizationTargetOptions = []string{"java-war-tomcat"}
+	ir := irtypes.NewIR(plan)
+	ir.AddContainer(irtypes.NewContainer(plantypes.DockerFileContainerBuildTypeValue, service.Image, true))
+	irService := irtypes.NewServiceWithName("tickets")
+	if err := addJEEModernization(&ir, &irService, plan, service, descriptors); err != nil || len(ir.Containers[0].NewFiles) != 0 || len(irService.Annotations) != 0 {
+		t.Fatalf("Expected no configuration for a generic containerizer. Error: %v Files: %v Annotations: %v", err, ir.Containers[0].NewFiles, irService.Annotations)
+	}
+}
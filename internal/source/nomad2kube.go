@@ -0,0 +1,582 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	nomadServiceJobType = "service"
+	nomadBatchJobType   = "batch"
+	nomadSystemJobType  = "system"
+	// nomadSecretsDir is the directory of the task in which the templates rendering secrets are written
+	nomadSecretsDir = "secrets/"
+
+	// nomadTemplatesTODOKey lists the templates using the functions of consul-template, which were not rendered
+	nomadTemplatesTODOKey = common.TODOAnnotation + "nomadtemplates"
+	// nomadUnsupportedTODOKey lists the settings of the source which have no equivalent in the translated resources
+	nomadUnsupportedTODOKey = common.TODOAnnotation + "nomadunsupported"
+)
+
+var (
+	// nomadDockerDrivers are the task drivers running the images of the tasks
+	nomadDockerDrivers = []string{"docker", "podman"}
+	// nomadExposedTagPrefixes are the prefixes of the tags of the Nomad services which route the traffic from outside to the service
+	nomadExposedTagPrefixes = []string{"urlprefix-", "traefik.enable=true", "traefik.http.routers."}
+)
+
+// NomadTranslator implements Translator interface for HashiCorp Nomad jobs, described by the HCL job files. Each task group
+// with docker tasks becomes a service.
+type NomadTranslator struct {
+}
+
+// nomadGroup is a task group of a Nomad job, with its docker tasks. The main task is the first one.
+type nomadGroup struct {
+	ServiceName string
+	Job         hclBlock
+	Group       hclBlock
+	Tasks       []hclBlock
+}
+
+// GetTranslatorType returns the translator type
+func (*NomadTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.Nomad2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the task groups of the Nomad jobs
+func (nomadTranslator *NomadTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	filePaths, err := common.GetFilesByExt(inputPath, []string{".nomad", ".hcl"})
+	if err != nil {
+		log.Warnf("Unable to fetch the Nomad job files at path %q Error: %q", inputPath, err)
+		return services, err
+	}
+	sort.Strings(filePaths)
+	serviceNames := []string{}
+	for _, filePath := range filePaths {
+		for _, group := range readNomadGroups(filePath) {
+			if common.IsStringPresent(serviceNames, group.ServiceName) {
+				log.Warnf("Ignoring the task group %s in %s , since a service with the same name was already found", group.Group.Labels[0], filePath)
+				continue
+			}
+			serviceNames = append(serviceNames, group.ServiceName)
+			service := nomadTranslator.newService(group.ServiceName)
+			service.Image = getNomadTaskConfig(group.Tasks[0]).getString("image")
+			if strings.Contains(service.Image, "${") || strings.HasPrefix(service.Image, "var.") {
+				log.Warnf("The image %s of the task group %s in %s is set using a variable. Set the image of the service %s in the plan.", service.Image, group.Group.Labels[0], filePath, service.ServiceName)
+			}
+			service.AddSourceArtifact(plantypes.NomadJobArtifactType, filePath)
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// Translate translates the task groups of the Nomad jobs to IR
+func (nomadTranslator *NomadTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != nomadTranslator.GetTranslatorType() {
+			continue
+		}
+		log.Debugf("Translating %s", service.ServiceName)
+		var group *nomadGroup
+		for _, path := range service.SourceArtifacts[plantypes.NomadJobArtifactType] {
+			for _, g := range readNomadGroups(path) {
+				if g.ServiceName == service.ServiceName {
+					g := g
+					group = &g
+					break
+				}
+			}
+		}
+		if group == nil {
+			log.Warnf("No Nomad task group found for the service %s", service.ServiceName)
+			continue
+		}
+		var container irtypes.Container
+		if err := containerizer.RunServiceStep(service.ServiceName, "containerize", func() (err error) {
+			container, err = containerizer.GetContainer(plan, service)
+			return err
+		}); err != nil {
+			continue
+		}
+		ir.AddContainer(container)
+		for _, task := range group.Tasks[1:] {
+			ir.AddContainer(irtypes.NewContainer(plantypes.ReuseContainerBuildTypeValue, getNomadTaskConfig(task).getString("image"), false))
+		}
+		irService := irtypes.NewServiceFromPlanService(service)
+		translateNomadGroup(&ir, &irService, service.Image, *group)
+		ir.Services[service.ServiceName] = irService
+	}
+	return ir, nil
+}
+
+func (nomadTranslator *NomadTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, nomadTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.NomadSourceTypeValue)
+	service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+	service.UpdateContainerBuildPipeline = false
+	service.UpdateDeployPipeline = true
+	return service
+}
+
+// readNomadGroups returns the task groups with docker tasks of the jobs in the file at the path. A task group is named after its
+// first Nomad service, or after its job, followed by the name of the group when the job has several groups.
+func readNomadGroups(path string) []nomadGroup {
+	groups := []nomadGroup{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read the file at path %s . Error: %q", path, err)
+		return groups
+	}
+	body, err := parseHCL(string(data))
+	if err != nil {
+		log.Debugf("Unable to parse the file at path %s as a Nomad job. Error: %q", path, err)
+		return groups
+	}
+	for _, job := range body.getBlocks("job") {
+		if len(job.Labels) == 0 {
+			continue
+		}
+		jobGroups := job.Body.getBlocks("group")
+		for _, group := range jobGroups {
+			if len(group.Labels) == 0 {
+				continue
+			}
+			tasks := getNomadDockerTasks(group)
+			if len(tasks) == 0 {
+				log.Debugf("Ignoring the task group %s of the job %s in %s , since it has no docker tasks", group.Labels[0], job.Labels[0], path)
+				continue
+			}
+			serviceName := job.Labels[0]
+			if len(jobGroups) > 1 {
+				serviceName += "-" + group.Labels[0]
+			}
+			if name := getNomadServiceName(group, tasks); name != "" {
+				serviceName = name
+			}
+			groups = append(groups, nomadGroup{ServiceName: common.NormalizeForServiceName(serviceName), Job: job, Group: group, Tasks: tasks})
+		}
+	}
+	return groups
+}
+
+// getNomadDockerTasks returns the tasks of the group run by the docker driver. The main task, which is not run by a lifecycle hook, is moved to the front.
+func getNomadDockerTasks(group hclBlock) []hclBlock {
+	tasks := []hclBlock{}
+	for _, task := range group.Body.getBlocks("task") {
+		if len(task.Labels) == 0 || !common.IsStringPresent(nomadDockerDrivers, task.Body.getString("driver")) || getNomadTaskConfig(task).getString("image") == "" {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	for i, task := range tasks {
+		if _, ok := task.Body.getBlock("lifecycle"); !ok {
+			tasks[0], tasks[i] = tasks[i], tasks[0]
+			break
+		}
+	}
+	return tasks
+}
+
+// getNomadServiceName returns the name of the first Nomad service of the group, unless it is interpolated
+func getNomadServiceName(group hclBlock, tasks []hclBlock) string {
+	nomadServices := group.Body.getBlocks("service")
+	for _, task := range tasks {
+		nomadServices = append(nomadServices, task.Body.getBlocks("service")...)
+	}
+	for _, nomadService := range nomadServices {
+		if name := nomadService.Body.getString("name"); name != "" && !strings.Contains(name, "${") {
+			return name
+		}
+	}
+	return ""
+}
+
+func getNomadTaskConfig(task hclBlock) hclBody {
+	config, _ := task.Body.getBlock("config")
+	return config.Body
+}
+
+// translateNomadGroup sets the containers, the ports, the config maps and the scheduling of the service from the task group
+func translateNomadGroup(ir *irtypes.IR, service *irtypes.Service, image string, group nomadGroup) {
+	unsupported := []string{}
+	ports, dynamicPorts := getNomadPorts(group)
+	for _, label := range dynamicPorts {
+		unsupported = append(unsupported, "the dynamic port "+label)
+	}
+	templated := []string{}
+	for i, task := range group.Tasks {
+		config := getNomadTaskConfig(task)
+		container := core.Container{Name: common.NormalizeForServiceName(task.Labels[0]), Image: config.getString("image"), Command: config.getStrings("entrypoint"), WorkingDir: config.getString("work_dir")}
+		if i == 0 {
+			container.Name = service.Name
+			if image != "" {
+				container.Image = image
+			}
+		}
+		if command := config.getString("command"); command != "" {
+			container.Args = append(container.Args, command)
+		}
+		container.Args = append(container.Args, config.getStrings("args")...)
+		for j, arg := range container.Args {
+			container.Args[j] = replaceNomadPortVariables(arg, ports)
+		}
+		for _, label := range config.getStrings("ports") {
+			if port, ok := ports[label]; ok {
+				container.Ports = append(container.Ports, core.ContainerPort{Name: common.NormalizeForServiceName(label), ContainerPort: port})
+			}
+		}
+		for label, port := range config.getMap("port_map") {
+			container.Ports = append(container.Ports, core.ContainerPort{Name: common.NormalizeForServiceName(label), ContainerPort: cast.ToInt32(port)})
+		}
+		env := task.Body.getMap("env")
+		envNames := []string{}
+		for name := range env {
+			envNames = append(envNames, name)
+		}
+		sort.Strings(envNames)
+		for _, name := range envNames {
+			value := replaceNomadPortVariables(env[name], ports)
+			if strings.Contains(value, "${") {
+				unsupported = append(unsupported, fmt.Sprintf("the interpolation of the env var %s of the task %s", name, task.Labels[0]))
+			}
+			container.Env = append(container.Env, core.EnvVar{Name: name, Value: value})
+		}
+		for _, template := range task.Body.getBlocks("template") {
+			name, ok := addNomadTemplate(ir, service, &container, template.Body)
+			if !ok {
+				unsupported = append(unsupported, fmt.Sprintf("the template %s of the task %s", template.Body.getString("destination"), task.Labels[0]))
+				continue
+			}
+			if strings.Contains(template.Body.getString("data"), "{{") {
+				templated = append(templated, name)
+			}
+		}
+		container.Resources = getNomadResources(task)
+		lifecycle, hasLifecycle := task.Body.getBlock("lifecycle")
+		switch {
+		case i == 0 || !hasLifecycle || lifecycle.Body.getBool("sidecar"):
+			service.Containers = append(service.Containers, container)
+		case lifecycle.Body.getString("hook") == "prestart":
+			service.InitContainers = append(service.InitContainers, container)
+		default:
+			unsupported = append(unsupported, fmt.Sprintf("the %s task %s", lifecycle.Body.getString("hook"), task.Labels[0]))
+		}
+		for _, artifact := range task.Body.getBlocks("artifact") {
+			unsupported = append(unsupported, fmt.Sprintf("the artifact %s of the task %s", artifact.Body.getString("source"), task.Labels[0]))
+		}
+		if _, ok := task.Body.getBlock("vault"); ok {
+			unsupported = append(unsupported, "the Vault policies of the task "+task.Labels[0])
+		}
+		for _, volumeMount := range task.Body.getBlocks("volume_mount") {
+			unsupported = append(unsupported, fmt.Sprintf("the volume %s mounted at %s", volumeMount.Body.getString("volume"), volumeMount.Body.getString("destination")))
+		}
+		unsupported = append(unsupported, getNomadConstraints(task.Body)...)
+	}
+	unsupported = append(unsupported, addNomadServices(service, group, ports)...)
+	unsupported = append(unsupported, addNomadScheduling(service, group)...)
+	if len(templated) > 0 {
+		sort.Strings(templated)
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+			nomadTemplatesTODOKey: fmt.Sprintf("The templates %s use the functions of consul-template, like key and secret, which read the values from Consul and Vault. They were copied as is: replace the functions by the values, or sync the values using the External Secrets Operator.", strings.Join(templated, ", ")),
+		})
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{
+			nomadUnsupportedTODOKey: fmt.Sprintf("The settings %s have no equivalent and were not translated.", strings.Join(unsupported, ", ")),
+		})
+	}
+}
+
+// getNomadPorts returns the container ports of the port labels of the network of the group, or of the tasks in the older jobs, and
+// the labels of the dynamic ports, which are not mapped to a port of the container
+func getNomadPorts(group nomadGroup) (map[string]int32, []string) {
+	networks := group.Group.Body.getBlocks("network")
+	for _, task := range group.Tasks {
+		if resources, ok := task.Body.getBlock("resources"); ok {
+			networks = append(networks, resources.Body.getBlocks("network")...)
+		}
+	}
+	ports := map[string]int32{}
+	dynamicPorts := []string{}
+	for _, network := range networks {
+		for _, port := range network.Body.getBlocks("port") {
+			if len(port.Labels) == 0 {
+				continue
+			}
+			number := port.Body.getInt("to", 0)
+			if number <= 0 {
+				number = port.Body.getInt("static", 0)
+			}
+			if number <= 0 {
+				dynamicPorts = append(dynamicPorts, port.Labels[0])
+				continue
+			}
+			ports[port.Labels[0]] = int32(number)
+		}
+	}
+	for _, task := range group.Tasks {
+		for label, port := range getNomadTaskConfig(task).getMap("port_map") {
+			ports[label] = cast.ToInt32(port)
+		}
+	}
+	return ports, dynamicPorts
+}
+
+// replaceNomadPortVariables replaces the variables of the ports, like ${NOMAD_PORT_http}, by the numbers of the ports
+func replaceNomadPortVariables(value string, ports map[string]int32) string {
+	for label, port := range ports {
+		for _, variable := range []string{"NOMAD_PORT_", "NOMAD_HOST_PORT_"} {
+			value = strings.ReplaceAll(value, "${"+variable+label+"}", cast.ToString(port))
+		}
+	}
+	return value
+}
+
+// addNomadTemplate adds the config map, or the secret for the templates in the secrets directory, with the content of the template.
+// The template is mounted at its destination, or read as env vars if env is set. It returns false if the template has no content.
+func addNomadTemplate(ir *irtypes.IR, service *irtypes.Service, container *core.Container, template hclBody) (string, bool) {
+	data, destination := template.getString("data"), template.getString("destination")
+	if data == "" || destination == "" {
+		return "", false
+	}
+	key := filepath.Base(destination)
+	name := common.MakeFileNameCompliant(service.Name + "-" + strings.TrimSuffix(key, filepath.Ext(key)))
+	storageType := irtypes.ConfigMapKind
+	if strings.HasPrefix(strings.TrimPrefix(destination, "/"), nomadSecretsDir) {
+		storageType = irtypes.SecretKind
+	}
+	if template.getBool("env") {
+		content := map[string][]byte{}
+		for _, line := range strings.Split(data, "\n") {
+			parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+			if len(parts) == 2 && !strings.HasPrefix(parts[0], "#") {
+				content[strings.TrimSpace(parts[0])] = []byte(strings.TrimSpace(parts[1]))
+			}
+		}
+		ir.AddStorage(irtypes.Storage{Name: name, StorageType: storageType, Content: content})
+		envFrom := core.EnvFromSource{ConfigMapRef: &core.ConfigMapEnvSource{LocalObjectReference: core.LocalObjectReference{Name: name}}}
+		if storageType == irtypes.SecretKind {
+			envFrom = core.EnvFromSource{SecretRef: &core.SecretEnvSource{LocalObjectReference: core.LocalObjectReference{Name: name}}}
+		}
+		container.EnvFrom = append(container.EnvFrom, envFrom)
+		return name, true
+	}
+	ir.AddStorage(irtypes.Storage{Name: name, StorageType: storageType, Content: map[string][]byte{key: []byte(data)}})
+	// The relative destinations are in the directory of the task, which is the root of the container
+	mountPath := destination
+	if !filepath.IsAbs(mountPath) {
+		mountPath = "/" + mountPath
+	}
+	volume := core.Volume{Name: name}
+	if storageType == irtypes.SecretKind {
+		volume.VolumeSource = core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: name}}
+	} else {
+		volume.VolumeSource = core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: name}}}
+	}
+	service.AddVolume(volume)
+	container.VolumeMounts = append(container.VolumeMounts, core.VolumeMount{Name: name, MountPath: mountPath, SubPath: key})
+	return name, true
+}
+
+// getNomadResources returns the resources of the task. The cpu is reserved in MHz, which are approximated as a thousandth of a core,
+// and the memory in MB, which can go up to memory_max.
+func getNomadResources(task hclBlock) core.ResourceRequirements {
+	resources := core.ResourceRequirements{}
+	taskResources, ok := task.Body.getBlock("resources")
+	if !ok {
+		return resources
+	}
+	if cpu := taskResources.Body.getInt("cpu", 0); cpu > 0 {
+		resources.Requests = core.ResourceList{core.ResourceCPU: *resource.NewMilliQuantity(int64(cpu), resource.DecimalSI)}
+	}
+	if memory := taskResources.Body.getInt("memory", 0); memory > 0 {
+		if resources.Requests == nil {
+			resources.Requests = core.ResourceList{}
+		}
+		resources.Requests[core.ResourceMemory] = *resource.NewQuantity(int64(memory)*1024*1024, resource.BinarySI)
+		limit := taskResources.Body.getInt("memory_max", memory)
+		resources.Limits = core.ResourceList{core.ResourceMemory: *resource.NewQuantity(int64(limit)*1024*1024, resource.BinarySI)}
+	}
+	return resources
+}
+
+// addNomadServices adds the ports of the Nomad services of the group and of its tasks to the service, and the probe of their first check.
+// The services tagged for the load balancers routing the traffic from outside, like Fabio and Traefik, are exposed.
+func addNomadServices(service *irtypes.Service, group nomadGroup, ports map[string]int32) []string {
+	unsupported := []string{}
+	nomadServices := group.Group.Body.getBlocks("service")
+	for _, task := range group.Tasks {
+		nomadServices = append(nomadServices, task.Body.getBlocks("service")...)
+	}
+	for _, nomadService := range nomadServices {
+		name := nomadService.Body.getString("name")
+		port, ok := ports[nomadService.Body.getString("port")]
+		if !ok {
+			port = cast.ToInt32(nomadService.Body.getString("port"))
+		}
+		if port > 0 {
+			service.AddPortForwarding(irtypes.Port{Number: port}, irtypes.Port{Number: port})
+		}
+		for _, tag := range nomadService.Body.getStrings("tags") {
+			for _, prefix := range nomadExposedTagPrefixes {
+				if strings.HasPrefix(tag, prefix) {
+					service.Annotations = common.MergeStringMaps(service.Annotations, map[string]string{common.ExposeSelector: common.AnnotationLabelValue})
+				}
+			}
+		}
+		if _, ok := nomadService.Body.getBlock("connect"); ok {
+			unsupported = append(unsupported, "the Consul Connect sidecar of the service "+name)
+		}
+		for _, check := range nomadService.Body.getBlocks("check") {
+			if len(service.Containers) == 0 || service.Containers[0].ReadinessProbe != nil {
+				break
+			}
+			checkPort := port
+			if label := check.Body.getString("port"); label != "" {
+				checkPort = ports[label]
+			}
+			service.Containers[0].ReadinessProbe = getNomadCheckProbe(check.Body, checkPort)
+		}
+	}
+	return unsupported
+}
+
+// getNomadCheckProbe returns the probe of the http, tcp, grpc or script check
+func getNomadCheckProbe(check hclBody, port int32) *core.Probe {
+	probe := &core.Probe{PeriodSeconds: getNomadDurationSeconds(check.getString("interval")), TimeoutSeconds: getNomadDurationSeconds(check.getString("timeout"))}
+	switch check.getString("type") {
+	case "http":
+		probe.HTTPGet = &core.HTTPGetAction{Path: check.getString("path"), Port: intstr.FromInt(int(port))}
+		if strings.EqualFold(check.getString("protocol"), "https") {
+			probe.HTTPGet.Scheme = core.URISchemeHTTPS
+		}
+	case "tcp", "grpc":
+		probe.TCPSocket = &core.TCPSocketAction{Port: intstr.FromInt(int(port))}
+	case "script":
+		probe.Exec = &core.ExecAction{Command: append([]string{check.getString("command")}, check.getStrings("args")...)}
+	default:
+		return nil
+	}
+	return probe
+}
+
+// addNomadScheduling sets the replicas, the type of workload and the update strategy of the service from the job and the group,
+// and returns the settings which have no equivalent
+func addNomadScheduling(service *irtypes.Service, group nomadGroup) []string {
+	unsupported := []string{}
+	job, groupBody := group.Job.Body, group.Group.Body
+	service.Replicas = groupBody.getInt("count", 1)
+	switch jobType := job.getString("type"); jobType {
+	case "", nomadServiceJobType:
+	case nomadSystemJobType:
+		service.Daemon = true
+	case nomadBatchJobType:
+		service.RestartPolicy = core.RestartPolicyOnFailure
+		if restart, ok := groupBody.getBlock("restart"); ok {
+			attempts := int32(restart.Body.getInt("attempts", 0))
+			service.BackoffLimit = &attempts
+		}
+		if periodic, ok := job.getBlock("periodic"); ok {
+			crons := append([]string{periodic.Body.getString("cron")}, periodic.Body.getStrings("crons")...)
+			for _, cron := range crons {
+				if cron == "" {
+					continue
+				}
+				if service.Schedule == "" {
+					service.Schedule = cron
+				} else {
+					unsupported = append(unsupported, "the periodic schedule "+cron)
+				}
+			}
+		}
+	default:
+		unsupported = append(unsupported, "the job type "+jobType)
+	}
+	update, ok := groupBody.getBlock("update")
+	if !ok {
+		update, ok = job.getBlock("update")
+	}
+	if ok && service.RestartPolicy == "" && !service.Daemon {
+		// The allocations are stopped before being replaced, and max_parallel 0 disables the rolling updates
+		updateStrategy := &irtypes.UpdateStrategy{MinReadySeconds: getNomadDurationSeconds(update.Body.getString("min_healthy_time"))}
+		if maxParallel := update.Body.getInt("max_parallel", 1); maxParallel > 0 {
+			updateStrategy.MaxSurge = intstr.FromInt(0)
+			updateStrategy.MaxUnavailable = intstr.FromInt(maxParallel)
+		} else {
+			updateStrategy.Recreate = true
+		}
+		service.UpdateStrategy = updateStrategy
+		if canary := update.Body.getInt("canary", 0); canary > 0 {
+			unsupported = append(unsupported, fmt.Sprintf("the %d canaries of the updates", canary))
+		}
+	}
+	if scaling, ok := groupBody.getBlock("scaling"); ok {
+		unsupported = append(unsupported, fmt.Sprintf("the scaling policy between %d and %d allocations", scaling.Body.getInt("min", service.Replicas), scaling.Body.getInt("max", service.Replicas)))
+	}
+	for _, volume := range groupBody.getBlocks("volume") {
+		unsupported = append(unsupported, fmt.Sprintf("the %s volume %s", volume.Body.getString("type"), volume.Body.getString("source")))
+	}
+	unsupported = append(unsupported, getNomadConstraints(job)...)
+	unsupported = append(unsupported, getNomadConstraints(groupBody)...)
+	return unsupported
+}
+
+// getNomadConstraints returns the constraints on the clients running the job, the group or the task
+func getNomadConstraints(body hclBody) []string {
+	constraints := []string{}
+	for _, constraint := range body.getBlocks("constraint") {
+		operator := constraint.Body.getString("operator")
+		if operator == "" {
+			operator = "="
+		}
+		constraints = append(constraints, strings.TrimSpace(fmt.Sprintf("the constraint %s %s %s", constraint.Body.getString("attribute"), operator, constraint.Body.getString("value"))))
+	}
+	return constraints
+}
+
+// getNomadDurationSeconds returns the seconds of the duration, like 10s
+func getNomadDurationSeconds(duration string) int32 {
+	if duration == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		log.Debugf("Ignoring the invalid duration %s . Error: %q", duration, err)
+		return 0
+	}
+	return int32(d.Seconds())
+}
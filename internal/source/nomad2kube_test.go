@@ -0,0 +1,241 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const testNomadJob = `# The tickets app
+job "tickets" {
+  datacenters = ["dc1"]
+  type        = "service"
+
+  constraint {
+    attribute = "${attr.kernel.name}"
+    value     = "linux"
+  }
+
+  group "web" {
+    count = 3
+
+    network {
+      port "http" {
+        to = 3000
+      }
+      port "metrics" {}
+    }
+
+    update {
+      max_parallel     = 1
+      min_healthy_time = "10s"
+      canary           = 1
+    }
+
+    service {
+      name = "tickets-web"
+      port = "http"
+      tags = ["urlprefix-/tickets"]
+
+      check {
+        type     = "http"
+        path     = "/healthz"
+        interval = "10s"
+        timeout  = "2s"
+      }
+    }
+
+    task "migrate" {
+      driver = "docker"
+      lifecycle {
+        hook = "prestart"
+      }
+      config {
+        image   = "tickets:v1"
+        command = "./migrate.sh"
+      }
+    }
+
+    task "app" {
+      driver = "docker"
+      config {
+        image = "tickets:v1"
+        ports = ["http"]
+        args  = ["--port", "${NOMAD_PORT_http}"]
+      }
+      env {
+        NODE_ENV = "production"
+        PORT     = "${NOMAD_PORT_http}"
+      }
+      template {
+        data        = <<-EOF
+          DB_HOST={{ key "tickets/db/host" }}
+          EOF
+        destination = "secrets/db.env"
+        env         = true
+      }
+      template {
+        data        = "log_level: info\n"
+        destination = "local/config.yml"
+      }
+      resources {
+        cpu        = 500
+        memory     = 256
+        memory_max = 512
+      }
+    }
+  }
+}
+`
+
+func TestParseHCL(t *testing.T) {
+	body, err := parseHCL(`
+// A comment
+name = "app" /* inline */
+count = 2
+list = ["a", "b"]
+object = { KEY = "value", OTHER = 3 }
+script = <<EOF
+echo ${HOME}
+EOF
+block "a" "b" {
+  enabled = true
+  nested {}
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse the HCL. Error: %q", err)
+	}
+	if body.getString("name") != "app" || body.getInt("count", 0) != 2 || !reflect.DeepEqual(body.getStrings("list"), []string{"a", "b"}) {
+		t.Fatalf("Failed to parse the attributes. Actual: %+v", body.Attributes)
+	}
+	if object := body.getMap("object"); !reflect.DeepEqual(object, map[string]string{"KEY": "value", "OTHER": "3"}) {
+		t.Fatalf("Failed to parse the object. Actual: %v", object)
+	}
+	if body.getString("script") != "echo ${HOME}\n" {
+		t.Fatalf("Failed to parse the heredoc. Actual: %q", body.getString("script"))
+	}
+	block, ok := body.getBlock("block")
+	if !ok || !reflect.DeepEqual(block.Labels, []string{"a", "b"}) || !block.Body.getBool("enabled") || len(block.Body.getBlocks("nested")) != 1 {
+		t.Fatalf("Failed to parse the block. Actual: %+v", block)
+	}
+	if _, err := parseHCL(`name = "unterminated`); err == nil {
+		t.Fatalf("Expected an error for the unterminated string")
+	}
+}
+
+func TestNomadTranslator(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{"tickets.nomad": testNomadJob, "other.hcl": `variable "region" {}`})
+	nomadTranslator := NomadTranslator{}
+	services, err := nomadTranslator.GetServiceOptions(dir, plantypes.NewPlan())
+	if err != nil || len(services) != 1 {
+		t.Fatalf("Expected a service for the task group. Actual: %+v Error: %q", services, err)
+	}
+	service := services[0]
+	if service.ServiceName != "tickets-web" || service.Image != "tickets:v1" || service.SourceArtifacts[plantypes.NomadJobArtifactType][0] != filepath.Join(dir, "tickets.nomad") {
+		t.Fatalf("Failed to create the service of the task group. Actual: %+v", service)
+	}
+
+	groups := readNomadGroups(filepath.Join(dir, "tickets.nomad"))
+	if len(groups) != 1 || groups[0].Tasks[0].Labels[0] != "app" {
+		t.Fatalf("Expected the main task first. Actual: %+v", groups)
+	}
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	irService := irtypes.NewServiceWithName(service.ServiceName)
+	translateNomadGroup(&ir, &irService, service.Image, groups[0])
+
+	if irService.Replicas != 3 || len(irService.Containers) != 1 || len(irService.InitContainers) != 1 || irService.InitContainers[0].Args[0] != "./migrate.sh" {
+		t.Fatalf("Failed to translate the tasks. Actual: %+v", irService)
+	}
+	container := irService.Containers[0]
+	if !reflect.DeepEqual(container.Ports, []core.ContainerPort{{Name: "http", ContainerPort: 3000}}) || !reflect.DeepEqual(container.Args, []string{"--port", "3000"}) {
+		t.Fatalf("Failed to translate the ports of the task. Actual: %+v %v", container.Ports, container.Args)
+	}
+	wantEnv := []core.EnvVar{{Name: "NODE_ENV", Value: "production"}, {Name: "PORT", Value: "3000"}}
+	if !reflect.DeepEqual(container.Env, wantEnv) {
+		t.Fatalf("Failed to translate the env of the task. Expected: %+v Actual: %+v", wantEnv, container.Env)
+	}
+	if len(container.EnvFrom) != 1 || container.EnvFrom[0].SecretRef.Name != "tickets-web-db" {
+		t.Fatalf("Failed to read the env template from the secret. Actual: %+v", container.EnvFrom)
+	}
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != "/local/config.yml" || container.VolumeMounts[0].SubPath != "config.yml" {
+		t.Fatalf("Failed to mount the config template. Actual: %+v", container.VolumeMounts)
+	}
+	if len(ir.Storages) != 2 || ir.Storages[0].StorageType != irtypes.SecretKind || string(ir.Storages[0].Content["DB_HOST"]) != `{{ key "tickets/db/host" }}` || ir.Storages[1].StorageType != irtypes.ConfigMapKind {
+		t.Fatalf("Failed to store the templates. Actual: %+v", ir.Storages)
+	}
+	if memory := container.Resources.Limits[core.ResourceMemory]; memory.String() != "512Mi" {
+		t.Fatalf("Failed to set the memory limit. Actual: %s", memory.String())
+	}
+	if cpu := container.Resources.Requests[core.ResourceCPU]; cpu.String() != "500m" {
+		t.Fatalf("Failed to set the cpu request. Actual: %s", cpu.String())
+	}
+	if probe := container.ReadinessProbe; probe == nil || probe.HTTPGet.Path != "/healthz" || probe.HTTPGet.Port.IntValue() != 3000 || probe.PeriodSeconds != 10 {
+		t.Fatalf("Failed to translate the check to a probe. Actual: %+v", probe)
+	}
+	if len(irService.ServiceToPodPortForwardings) != 1 || irService.Annotations[common.ExposeSelector] != common.AnnotationLabelValue {
+		t.Fatalf("Failed to expose the service. Actual: %+v %v", irService.ServiceToPodPortForwardings, irService.Annotations)
+	}
+	if strategy := irService.UpdateStrategy; strategy == nil || strategy.Recreate || strategy.MaxUnavailable.IntValue() != 1 || strategy.MinReadySeconds != 10 {
+		t.Fatalf("Failed to translate the update strategy. Actual: %+v", strategy)
+	}
+	if !strings.Contains(irService.Annotations[nomadTemplatesTODOKey], "tickets-web-db") {
+		t.Fatalf("Expected a TODO for the consul-template functions. Actual: %v", irService.Annotations)
+	}
+	wantUnsupported := "The settings the 1 canaries of the updates, the constraint ${attr.kernel.name} = linux, the dynamic port metrics have no equivalent and were not translated."
+	if irService.Annotations[nomadUnsupportedTODOKey] != wantUnsupported {
+		t.Fatalf("Failed to list the unsupported settings. Expected: %s Actual: %s", wantUnsupported, irService.Annotations[nomadUnsupportedTODOKey])
+	}
+}
+
+func TestNomadBatchJob(t *testing.T) {
+	body, err := parseHCL(`job "report" {
+  type = "batch"
+  periodic {
+    cron = "0 2 * * *"
+  }
+  group "report" {
+    restart {
+      attempts = 2
+    }
+    task "report" {
+      driver = "docker"
+      config {
+        image = "report:v1"
+      }
+    }
+  }
+}`)
+	if err != nil {
+		t.Fatalf("Failed to parse the job. Error: %q", err)
+	}
+	job, _ := body.getBlock("job")
+	group, _ := job.Body.getBlock("group")
+	service := irtypes.NewServiceWithName("report")
+	unsupported := addNomadScheduling(&service, nomadGroup{Job: job, Group: group, Tasks: getNomadDockerTasks(group)})
+	if len(unsupported) != 0 || service.RestartPolicy != core.RestartPolicyOnFailure || service.Schedule != "0 2 * * *" || service.BackoffLimit == nil || *service.BackoffLimit != 2 || service.UpdateStrategy != nil {
+		t.Fatalf("Failed to translate the periodic batch job. Actual: %+v %v", service, unsupported)
+	}
+}
@@ -0,0 +1,473 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// hclBody is the body of an HCL file or of a block, with its attributes and its nested blocks. The values of the attributes are
+// strings, numbers as float64, bools, lists as []interface{} and objects as map[string]interface{}. The expressions which are not
+// literals, like var.image or file("config.yml"), are kept as strings of their source.
+type hclBody struct {
+	Attributes map[string]interface{}
+	Blocks     []hclBlock
+}
+
+// hclBlock is a block, like group "web" { ... }
+type hclBlock struct {
+	Type   string
+	Labels []string
+	Body   hclBody
+}
+
+type hclTokenType int
+
+const (
+	hclEOF hclTokenType = iota
+	hclNewline
+	hclIdent
+	hclString
+	hclNumber
+	hclPunct
+)
+
+type hclToken struct {
+	Type  hclTokenType
+	Value string
+	Line  int
+}
+
+// parseHCL parses the subset of the HCL syntax used by the job files of Nomad: the attributes, the blocks, the literals, the lists,
+// the objects, the heredocs and the comments
+func parseHCL(data string) (hclBody, error) {
+	tokens, err := tokenizeHCL(data)
+	if err != nil {
+		return hclBody{}, err
+	}
+	p := &hclParser{tokens: tokens}
+	body, err := p.parseBody()
+	if err != nil {
+		return body, err
+	}
+	if t := p.peek(); t.Type != hclEOF {
+		return body, fmt.Errorf("unexpected %q on line %d", t.Value, t.Line)
+	}
+	return body, nil
+}
+
+func tokenizeHCL(data string) ([]hclToken, error) {
+	tokens := []hclToken{}
+	runes := []rune(data)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, hclToken{Type: hclNewline, Value: "\n", Line: line})
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '#' || (r == '/' && i+1 < len(runes) && runes[i+1] == '/'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := strings.Index(string(runes[i+2:]), "*/")
+			if end < 0 {
+				return tokens, fmt.Errorf("unterminated comment on line %d", line)
+			}
+			comment := string(runes[i+2:])[:end]
+			line += strings.Count(comment, "\n")
+			i += len([]rune(comment)) + 4
+		case r == '"':
+			value, n, err := readHCLString(runes[i:])
+			if err != nil {
+				return tokens, fmt.Errorf("%s on line %d", err, line)
+			}
+			tokens = append(tokens, hclToken{Type: hclString, Value: value, Line: line})
+			line += strings.Count(string(runes[i:i+n]), "\n")
+			i += n
+		case r == '<' && i+1 < len(runes) && runes[i+1] == '<':
+			value, n, err := readHCLHeredoc(runes[i:])
+			if err != nil {
+				return tokens, fmt.Errorf("%s on line %d", err, line)
+			}
+			tokens = append(tokens, hclToken{Type: hclString, Value: value, Line: line})
+			line += strings.Count(string(runes[i:i+n]), "\n")
+			i += n
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, hclToken{Type: hclNumber, Value: string(runes[i:j]), Line: line})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '-' || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, hclToken{Type: hclIdent, Value: string(runes[i:j]), Line: line})
+			i = j
+		default:
+			tokens = append(tokens, hclToken{Type: hclPunct, Value: string(r), Line: line})
+			i++
+		}
+	}
+	return append(tokens, hclToken{Type: hclEOF, Line: line}), nil
+}
+
+// readHCLString reads the quoted string at the start of the runes, and returns its value and its length in the source.
+// The interpolations, like ${NOMAD_PORT_http}, are kept as is, and can contain quotes.
+func readHCLString(runes []rune) (string, int, error) {
+	value := strings.Builder{}
+	depth := 0
+	for i := 1; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'n':
+				value.WriteRune('\n')
+			case 't':
+				value.WriteRune('\t')
+			case 'r':
+				value.WriteRune('\r')
+			default:
+				value.WriteRune(runes[i])
+			}
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '{':
+			depth++
+			value.WriteString("${")
+			i++
+		case r == '}' && depth > 0:
+			depth--
+			value.WriteRune(r)
+		case r == '"' && depth == 0:
+			return value.String(), i + 1, nil
+		case r == '\n' && depth == 0:
+			return "", 0, fmt.Errorf("unterminated string")
+		default:
+			value.WriteRune(r)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// readHCLHeredoc reads the heredoc at the start of the runes, like <<EOF or <<-EOF, and returns its value and its length in the source.
+// The indentation of the lines of the indented heredocs is removed.
+func readHCLHeredoc(runes []rune) (string, int, error) {
+	text := string(runes)
+	newline := strings.Index(text, "\n")
+	if newline < 0 {
+		return "", 0, fmt.Errorf("unterminated heredoc")
+	}
+	marker := strings.TrimSpace(text[2:newline])
+	indented := strings.HasPrefix(marker, "-")
+	marker = strings.TrimPrefix(marker, "-")
+	if marker == "" {
+		return "", 0, fmt.Errorf("heredoc without a marker")
+	}
+	lines := []string{}
+	offset := newline + 1
+	for offset <= len(text) {
+		end := strings.Index(text[offset:], "\n")
+		lineText := text[offset:]
+		if end >= 0 {
+			lineText = text[offset : offset+end]
+		}
+		if strings.TrimSpace(lineText) == marker {
+			if indented {
+				lines = removeHCLIndentation(lines)
+			}
+			value := strings.Join(lines, "\n")
+			if len(lines) > 0 {
+				value += "\n"
+			}
+			return value, len([]rune(text[:offset+len(lineText)])), nil
+		}
+		lines = append(lines, lineText)
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	return "", 0, fmt.Errorf("unterminated heredoc %s", marker)
+}
+
+func removeHCLIndentation(lines []string) []string {
+	indentation := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indentation < 0 || n < indentation {
+			indentation = n
+		}
+	}
+	unindented := []string{}
+	for _, line := range lines {
+		if len(line) >= indentation && indentation > 0 {
+			line = line[indentation:]
+		}
+		unindented = append(unindented, line)
+	}
+	return unindented
+}
+
+type hclParser struct {
+	tokens []hclToken
+	pos    int
+}
+
+func (p *hclParser) peek() hclToken {
+	return p.tokens[p.pos]
+}
+
+func (p *hclParser) next() hclToken {
+	t := p.tokens[p.pos]
+	if t.Type != hclEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *hclParser) skipNewlines() {
+	for p.peek().Type == hclNewline {
+		p.pos++
+	}
+}
+
+func (p *hclParser) isPunct(value string) bool {
+	t := p.peek()
+	return t.Type == hclPunct && t.Value == value
+}
+
+// parseBody parses the attributes and the blocks until the end of the block or of the file
+func (p *hclParser) parseBody() (hclBody, error) {
+	body := hclBody{Attributes: map[string]interface{}{}}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		if t.Type == hclEOF || (t.Type == hclPunct && t.Value == "}") {
+			return body, nil
+		}
+		if t.Type != hclIdent && t.Type != hclString {
+			return body, fmt.Errorf("expected an attribute or a block on line %d, found %q", t.Line, t.Value)
+		}
+		name := p.next().Value
+		if p.isPunct("=") || p.isPunct(":") {
+			p.next()
+			value, err := p.parseValue()
+			if err != nil {
+				return body, err
+			}
+			body.Attributes[name] = value
+			continue
+		}
+		block := hclBlock{Type: name}
+		for p.peek().Type == hclString || p.peek().Type == hclIdent {
+			block.Labels = append(block.Labels, p.next().Value)
+		}
+		if !p.isPunct("{") {
+			return body, fmt.Errorf("expected { after the block %s on line %d", name, p.peek().Line)
+		}
+		p.next()
+		blockBody, err := p.parseBody()
+		if err != nil {
+			return body, err
+		}
+		if !p.isPunct("}") {
+			return body, fmt.Errorf("unterminated block %s", name)
+		}
+		p.next()
+		block.Body = blockBody
+		body.Blocks = append(body.Blocks, block)
+	}
+}
+
+func (p *hclParser) parseValue() (interface{}, error) {
+	p.skipNewlines()
+	t := p.next()
+	switch {
+	case t.Type == hclString:
+		return t.Value, nil
+	case t.Type == hclNumber:
+		value, err := strconv.ParseFloat(t.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s on line %d", t.Value, t.Line)
+		}
+		return value, nil
+	case t.Type == hclIdent && (t.Value == "true" || t.Value == "false"):
+		return t.Value == "true", nil
+	case t.Type == hclIdent:
+		// The references and the function calls are kept as their source
+		expression := t.Value
+		if p.isPunct("(") {
+			depth := 0
+			for {
+				arg := p.next()
+				if arg.Type == hclEOF {
+					return nil, fmt.Errorf("unterminated call of %s on line %d", t.Value, t.Line)
+				}
+				switch {
+				case arg.Type == hclString:
+					expression += strconv.Quote(arg.Value)
+				case arg.Type != hclNewline:
+					expression += arg.Value
+				}
+				if arg.Type == hclPunct && arg.Value == "(" {
+					depth++
+				} else if arg.Type == hclPunct && arg.Value == ")" {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+		}
+		return expression, nil
+	case t.Type == hclPunct && t.Value == "[":
+		list := []interface{}{}
+		for {
+			p.skipNewlines()
+			if p.isPunct("]") {
+				p.next()
+				return list, nil
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			p.skipNewlines()
+			if p.isPunct(",") {
+				p.next()
+			}
+		}
+	case t.Type == hclPunct && t.Value == "{":
+		object := map[string]interface{}{}
+		for {
+			p.skipNewlines()
+			if p.isPunct("}") {
+				p.next()
+				return object, nil
+			}
+			key := p.next()
+			if key.Type != hclIdent && key.Type != hclString {
+				return nil, fmt.Errorf("expected a key on line %d, found %q", key.Line, key.Value)
+			}
+			if !p.isPunct("=") && !p.isPunct(":") {
+				return nil, fmt.Errorf("expected = after the key %s on line %d", key.Value, key.Line)
+			}
+			p.next()
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			object[key.Value] = value
+			if p.isPunct(",") {
+				p.next()
+			}
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q on line %d", t.Value, t.Line)
+}
+
+// getBlocks returns the nested blocks of the type
+func (body hclBody) getBlocks(blockType string) []hclBlock {
+	blocks := []hclBlock{}
+	for _, block := range body.Blocks {
+		if block.Type == blockType {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// getBlock returns the first nested block of the type
+func (body hclBody) getBlock(blockType string) (hclBlock, bool) {
+	blocks := body.getBlocks(blockType)
+	if len(blocks) == 0 {
+		return hclBlock{}, false
+	}
+	return blocks[0], true
+}
+
+// getString returns the attribute as a string
+func (body hclBody) getString(name string) string {
+	switch value := body.Attributes[name].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return ""
+}
+
+// getInt returns the attribute as an int, or the default value if it is not a number
+func (body hclBody) getInt(name string, defaultValue int) int {
+	if value, ok := body.Attributes[name].(float64); ok {
+		return int(value)
+	}
+	return defaultValue
+}
+
+// getBool returns the attribute as a bool
+func (body hclBody) getBool(name string) bool {
+	value, _ := body.Attributes[name].(bool)
+	return value
+}
+
+// getStrings returns the attribute as a list of strings. A single string is a list of one string.
+func (body hclBody) getStrings(name string) []string {
+	values := []string{}
+	switch value := body.Attributes[name].(type) {
+	case string:
+		values = append(values, value)
+	case []interface{}:
+		for _, item := range value {
+			values = append(values, (hclBody{Attributes: map[string]interface{}{"item": item}}).getString("item"))
+		}
+	}
+	return values
+}
+
+// getMap returns the attribute as a map of strings. The attributes of a block of the same name, like env { KEY = "value" }, are added.
+func (body hclBody) getMap(name string) map[string]string {
+	values := map[string]string{}
+	if object, ok := body.Attributes[name].(map[string]interface{}); ok {
+		objectBody := hclBody{Attributes: object}
+		for key := range object {
+			values[key] = objectBody.getString(key)
+		}
+	}
+	for _, block := range body.getBlocks(name) {
+		for key := range block.Body.Attributes {
+			values[key] = block.Body.getString(key)
+		}
+	}
+	return values
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// getPlanPort returns the port of the plan for the port of a container
+func getPlanPort(containerPort core.ContainerPort) plantypes.Port {
+	port := plantypes.Port{Name: containerPort.Name, ContainerPort: containerPort.ContainerPort}
+	if containerPort.Protocol != "" && containerPort.Protocol != core.ProtocolTCP {
+		port.Protocol = plantypes.PortProtocolTypeValue(containerPort.Protocol)
+	}
+	return port
+}
+
+// addContainerPortsToServices adds the ports of the container to the ports of the service options
+func addContainerPortsToServices(services []plantypes.Service, containerPorts []core.ContainerPort) {
+	for i := range services {
+		for _, containerPort := range containerPorts {
+			services[i].AddPort(getPlanPort(containerPort))
+		}
+	}
+}
+
+// addPlanPortsToService adds the ports of the plan to the main container of the service, and forwards them on the k8s service
+func addPlanPortsToService(irService *irtypes.Service, ports []plantypes.Port) {
+	irService.Ports = append([]plantypes.Port{}, ports...)
+	if len(irService.Containers) == 0 {
+		return
+	}
+	for _, port := range ports {
+		irService.AddContainerPort(0, core.ContainerPort{Name: port.Name, ContainerPort: port.ContainerPort, Protocol: core.Protocol(port.Protocol)})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetDockerfilePorts(t *testing.T) {
+	dir := writeSessionHintFiles(t, map[string]string{
+		"Dockerfile": "FROM golang AS builder\nEXPOSE 9090\nFROM alpine\nEXPOSE 8080 53/udp ${PORT}\n",
+	})
+	ports := getDockerfilePorts(filepath.Join(dir, "Dockerfile"))
+	want := []plantypes.Port{{ContainerPort: 8080}, {ContainerPort: 53, Protocol: plantypes.UDPPortProtocol}}
+	if !reflect.DeepEqual(ports, want) {
+		t.Fatalf("Failed to get the ports of the final stage. Expected: %+v Actual: %+v", want, ports)
+	}
+}
+
+func TestAddPlanPortsToService(t *testing.T) {
+	irService := irtypes.NewServiceWithName("svc1")
+	irService.Containers = []core.Container{{Name: "svc1"}}
+	irService.AddContainerPort(0, core.ContainerPort{ContainerPort: 8080})
+	ports := []plantypes.Port{{Name: "http", ContainerPort: 8080}, {ContainerPort: 53, Protocol: plantypes.UDPPortProtocol, Expose: plantypes.IngressNginxPortExpose}}
+	addPlanPortsToService(&irService, ports)
+
+	wantContainerPorts := []core.ContainerPort{{Name: "http", ContainerPort: 8080}, {ContainerPort: 53, Protocol: core.ProtocolUDP}}
+	if !reflect.DeepEqual(irService.Containers[0].Ports, wantContainerPorts) {
+		t.Fatalf("Failed to add the ports to the container. Expected: %+v Actual: %+v", wantContainerPorts, irService.Containers[0].Ports)
+	}
+	if len(irService.ServiceToPodPortForwardings) != 2 || irService.ServiceToPodPortForwardings[1].ServicePort.Number != 53 {
+		t.Fatalf("Failed to forward the ports on the k8s service. Actual: %+v", irService.ServiceToPodPortForwardings)
+	}
+	if !reflect.DeepEqual(irService.Ports, ports) {
+		t.Fatalf("Failed to keep the ports of the plan. Expected: %+v Actual: %+v", ports, irService.Ports)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	dockerparser "github.com/moby/buildkit/frontend/dockerfile/parser"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	supervisordProcessManager = "supervisord"
+	foremanProcessManager     = "foreman"
+
+	procfileName = "Procfile"
+)
+
+var (
+	// foremanRegex matches the commands running the processes of a Procfile
+	foremanRegex = regexp.MustCompile(`\b(foreman|honcho|forego)\s+start\b`)
+	// supervisordConfigPaths are the paths of the main configs of supervisord
+	supervisordConfigPaths = []string{"/etc/supervisord.conf", "/etc/supervisor/supervisord.conf"}
+	// supervisordConfigDirs are the directories of the included configs of supervisord
+	supervisordConfigDirs = []string{"/etc/supervisor/conf.d", "/etc/supervisord.d"}
+	// supervisordEnvRegex matches an environment variable in the environment of a supervisord program, like KEY="value"
+	supervisordEnvRegex = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)=("[^"]*"|'[^']*'|[^,]*)`)
+	// procfileRegex matches a process of a Procfile, like web: bundle exec rails server
+	procfileRegex = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+)
+
+// getProcesses returns the process manager and the processes run in the image of the service, if the Dockerfile of the
+// service runs supervisord or foreman
+func getProcesses(service plantypes.Service) (string, []irtypes.Process) {
+	for _, dockerfilePath := range getServiceDockerfiles(service) {
+		contextDir := getBuildContextDir(service, dockerfilePath)
+		processManager, configs := getDockerfileProcessConfigs(dockerfilePath, contextDir)
+		processes := []irtypes.Process{}
+		for _, config := range configs {
+			var configProcesses []irtypes.Process
+			var err error
+			if processManager == supervisordProcessManager {
+				configProcesses, err = parseSupervisordConfig(config)
+			} else {
+				configProcesses, err = parseProcfile(config)
+			}
+			if err != nil {
+				log.Debugf("Failed to parse the %s config at path %s . Error: %q", processManager, config, err)
+				continue
+			}
+			processes = append(processes, configProcesses...)
+		}
+		if len(processes) > 0 {
+			return processManager, processes
+		}
+	}
+	return "", nil
+}
+
+// getDockerfileProcessConfigs returns the process manager run by the final stage of the Dockerfile, along with the paths
+// of its configs in the build context
+func getDockerfileProcessConfigs(dockerfilePath, contextDir string) (string, []string) {
+	f, err := os.Open(dockerfilePath)
+	if err != nil {
+		log.Debugf("Unable to open file %s : %s", dockerfilePath, err)
+		return "", nil
+	}
+	defer f.Close()
+	res, err := dockerparser.Parse(f)
+	if err != nil {
+		log.Debugf("Unable to parse file %s as Docker files : %s", dockerfilePath, err)
+		return "", nil
+	}
+	processManager := ""
+	copiedFiles := map[string]string{} // [path in the image][path in the build context]
+	for _, dfchild := range res.AST.Children {
+		args := []string{}
+		for n := dfchild.Next; n != nil; n = n.Next {
+			args = append(args, n.Value)
+		}
+		switch dfchild.Value {
+		case "from":
+			processManager = ""
+			copiedFiles = map[string]string{}
+		case "cmd", "entrypoint":
+			command := strings.Join(args, " ")
+			if strings.Contains(command, supervisordProcessManager) {
+				processManager = supervisordProcessManager
+			} else if foremanRegex.MatchString(command) {
+				processManager = foremanProcessManager
+			}
+		case "copy", "add":
+			if len(args) < 2 || hasCopyFromFlag(dfchild.Flags) {
+				continue
+			}
+			dest := args[len(args)-1]
+			for _, src := range args[:len(args)-1] {
+				for imagePath, contextPath := range getCopiedFiles(filepath.Join(contextDir, filepath.FromSlash(src)), dest, len(args) > 2, supervisordConfigDirs) {
+					copiedFiles[imagePath] = contextPath
+				}
+			}
+		}
+	}
+	configs := []string{}
+	switch processManager {
+	case supervisordProcessManager:
+		for imagePath, contextPath := range copiedFiles {
+			if common.IsStringPresent(supervisordConfigPaths, imagePath) || isPathIn(path.Dir(imagePath), supervisordConfigDirs) {
+				configs = append(configs, contextPath)
+			}
+		}
+	case foremanProcessManager:
+		for imagePath, contextPath := range copiedFiles {
+			if path.Base(imagePath) == procfileName {
+				configs = append(configs, contextPath)
+			}
+		}
+		if len(configs) == 0 {
+			if _, err := os.Stat(filepath.Join(contextDir, procfileName)); err == nil {
+				configs = append(configs, filepath.Join(contextDir, procfileName))
+			}
+		}
+	}
+	sort.Strings(configs)
+	return processManager, configs
+}
+
+// parseSupervisordConfig returns the programs of the supervisord config which are started automatically
+func parseSupervisordConfig(configPath string) ([]irtypes.Process, error) {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	processes := []irtypes.Process{}
+	var process *irtypes.Process
+	autostart := true
+	addProcess := func() {
+		if process != nil && process.Command != "" && autostart {
+			processes = append(processes, *process)
+		}
+		process = nil
+		autostart = true
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			addProcess()
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			if strings.HasPrefix(section, "program:") {
+				process = &irtypes.Process{Name: common.NormalizeForServiceName(strings.TrimPrefix(section, "program:"))}
+			}
+			continue
+		}
+		if process == nil {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(kv[1])
+		switch strings.TrimSpace(kv[0]) {
+		case "command":
+			process.Command = value
+		case "directory":
+			process.WorkingDir = value
+		case "autostart":
+			autostart = strings.ToLower(value) != "false"
+		case "environment":
+			for _, match := range supervisordEnvRegex.FindAllStringSubmatch(value, -1) {
+				process.Env = append(process.Env, core.EnvVar{Name: match[1], Value: unquoteDockerfileValue(strings.TrimSpace(match[2]))})
+			}
+		}
+	}
+	addProcess()
+	return processes, scanner.Err()
+}
+
+// parseProcfile returns the processes of the Procfile
+func parseProcfile(procfilePath string) ([]irtypes.Process, error) {
+	f, err := os.Open(procfilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	processes := []irtypes.Process{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := procfileRegex.FindStringSubmatch(line); match != nil {
+			processes = append(processes, irtypes.Process{Name: common.NormalizeForServiceName(match[1]), Command: strings.TrimSpace(match[2])})
+		}
+	}
+	return processes, scanner.Err()
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestGetProcesses(t *testing.T) {
+	testcases := []struct {
+		name               string
+		files              map[string]string
+		wantProcessManager string
+		want               []irtypes.Process
+	}{
+		{
+			name: "supervisord programs",
+			files: map[string]string{
+				"Dockerfile": "FROM python:3.8\nRUN pip install supervisor\nCOPY conf/app.conf /etc/supervisor/conf.d/\nCMD [\"/usr/local/bin/supervisord\", \"-n\"]\n",
+				"conf/app.conf": `[supervisord]
+nodaemon=true
+
+[program:web]
+command=gunicorn app:app -b 0.0.0.0:8000
+directory=/app
+
+[program:worker]
+command=celery -A app worker
+environment=CELERY_QUEUE="default",LOG_LEVEL=info
+
+[program:disabled]
+command=/app/debug.sh
+autostart=false
+`,
+			},
+			wantProcessManager: supervisordProcessManager,
+			want: []irtypes.Process{
+				{Name: "web", Command: "gunicorn app:app -b 0.0.0.0:8000", WorkingDir: "/app"},
+				{Name: "worker", Command: "celery -A app worker", Env: []core.EnvVar{{Name: "CELERY_QUEUE", Value: "default"}, {Name: "LOG_LEVEL", Value: "info"}}},
+			},
+		},
+		{
+			name: "foreman procfile",
+			files: map[string]string{
+				"Dockerfile": "FROM ruby:2.7\nCOPY . /app\nCMD [\"foreman\", \"start\"]\n",
+				"Procfile":   "web: bundle exec rails server -p 3000\n# comment\nworker: bundle exec sidekiq\n",
+			},
+			wantProcessManager: foremanProcessManager,
+			want: []irtypes.Process{
+				{Name: "web", Command: "bundle exec rails server -p 3000"},
+				{Name: "worker", Command: "bundle exec sidekiq"},
+			},
+		},
+		{
+			name: "no process manager",
+			files: map[string]string{
+				"Dockerfile": "FROM ruby:2.7\nCOPY . /app\nCMD [\"bundle\", \"exec\", \"rails\", \"server\"]\n",
+				"Procfile":   "web: bundle exec rails server -p 3000\n",
+			},
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := writeSessionHintFiles(t, testcase.files)
+			service := plantypes.Service{SourceArtifacts: map[plantypes.SourceArtifactTypeValue][]string{plantypes.DockerfileArtifactType: {filepath.Join(dir, "Dockerfile")}}}
+			processManager, processes := getProcesses(service)
+			if processManager != testcase.wantProcessManager || !reflect.DeepEqual(processes, testcase.want) {
+				t.Fatalf("Failed to get the processes properly. Expected: %s %+v Actual: %s %+v", testcase.wantProcessManager, testcase.want, processManager, processes)
+			}
+		})
+	}
+}
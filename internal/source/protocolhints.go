@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// protoFilesHint is used when the source contains protocol buffer definitions
+	protoFilesHint = "gRPC service definitions (.proto files)"
+	protoFileExt   = ".proto"
+)
+
+// protocolLibrary is a library, declared in a dependency file, whose usage implies the protocol served by the app
+type protocolLibrary struct {
+	files    []string
+	library  string
+	protocol irtypes.ServiceProtocol
+}
+
+var (
+	protocolLibraries = []protocolLibrary{
+		{files: []string{"go.mod"}, library: "google.golang.org/grpc", protocol: irtypes.GRPCServiceProtocol},
+		{files: []string{"go.mod"}, library: "github.com/gorilla/websocket", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"go.mod"}, library: "nhooyr.io/websocket", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"pom.xml", "build.gradle"}, library: "io.grpc", protocol: irtypes.GRPCServiceProtocol},
+		{files: []string{"pom.xml", "build.gradle"}, library: "spring-boot-starter-websocket", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"pom.xml", "build.gradle"}, library: "javax.websocket", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"pom.xml", "build.gradle"}, library: "jakarta.websocket", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"requirements.txt", "Pipfile"}, library: "grpcio", protocol: irtypes.GRPCServiceProtocol},
+		{files: []string{"requirements.txt", "Pipfile"}, library: "websockets", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"requirements.txt", "Pipfile"}, library: "flask-socketio", protocol: irtypes.WebSocketServiceProtocol},
+		{files: []string{"requirements.txt", "Pipfile"}, library: "channels", protocol: irtypes.WebSocketServiceProtocol},
+	}
+	// protocolModules are the npm modules whose usage implies the protocol served by the app
+	protocolModules = map[string]irtypes.ServiceProtocol{
+		"@grpc/grpc-js": irtypes.GRPCServiceProtocol,
+		"grpc":          irtypes.GRPCServiceProtocol,
+		"ws":            irtypes.WebSocketServiceProtocol,
+		"socket.io":     irtypes.WebSocketServiceProtocol,
+		"sockjs":        irtypes.WebSocketServiceProtocol,
+	}
+	// protocolPortNames are the port names which imply the protocol served on the port
+	protocolPortNames = map[string]irtypes.ServiceProtocol{
+		"grpc":      irtypes.GRPCServiceProtocol,
+		"ws":        irtypes.WebSocketServiceProtocol,
+		"websocket": irtypes.WebSocketServiceProtocol,
+	}
+)
+
+// getProtocolHints looks for signs that the service serves gRPC or WebSocket instead of plain HTTP and returns the protocol along with the hints.
+// gRPC is preferred when both are found since it cannot be served without HTTP/2.
+func getProtocolHints(service plantypes.Service, irService irtypes.Service) (irtypes.ServiceProtocol, []string) {
+	hints := map[irtypes.ServiceProtocol][]string{}
+	addHint := func(protocol irtypes.ServiceProtocol, hint string) {
+		if !common.IsStringPresent(hints[protocol], hint) {
+			hints[protocol] = append(hints[protocol], hint)
+		}
+	}
+	if value, ok := irService.Annotations[common.ProtocolAnnotation]; ok {
+		protocol := irtypes.ServiceProtocol(strings.ToLower(value))
+		if protocol == irtypes.GRPCServiceProtocol || protocol == irtypes.WebSocketServiceProtocol {
+			// The protocol is labeled explicitly, so the other hints are not needed
+			return protocol, []string{"label " + common.ProtocolAnnotation + "=" + value}
+		}
+		log.Warnf("Ignoring the unsupported protocol %s in the label %s of the service %s", value, common.ProtocolAnnotation, service.ServiceName)
+	}
+	for _, forwarding := range irService.ServiceToPodPortForwardings {
+		for _, name := range []string{forwarding.ServicePort.Name, forwarding.PodPort.Name} {
+			if protocol, ok := protocolPortNames[strings.ToLower(name)]; ok {
+				addHint(protocol, "port named "+name)
+			}
+		}
+	}
+	for _, dir := range service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] {
+		for protocol, dirHints := range getProtocolHintsInDir(dir) {
+			for _, hint := range dirHints {
+				addHint(protocol, hint)
+			}
+		}
+	}
+	for _, protocol := range []irtypes.ServiceProtocol{irtypes.GRPCServiceProtocol, irtypes.WebSocketServiceProtocol} {
+		if len(hints[protocol]) > 0 {
+			return protocol, hints[protocol]
+		}
+	}
+	return irtypes.HTTPServiceProtocol, nil
+}
+
+func getProtocolHintsInDir(dir string) map[irtypes.ServiceProtocol][]string {
+	hints := map[irtypes.ServiceProtocol][]string{}
+	addHint := func(protocol irtypes.ServiceProtocol, hint string) {
+		if !common.IsStringPresent(hints[protocol], hint) {
+			hints[protocol] = append(hints[protocol], hint)
+		}
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Skipping the path %s while looking for protocol hints. Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && common.IsStringPresent(sessionHintSkipDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == protoFileExt {
+			addHint(irtypes.GRPCServiceProtocol, protoFilesHint)
+			return nil
+		}
+		if info.Size() > maxSessionHintFileSize {
+			return nil
+		}
+		if info.Name() == packageJSONFile {
+			packageJSON := struct {
+				Dependencies map[string]string `json:"dependencies"`
+			}{}
+			if err := common.ReadJSON(path, &packageJSON); err != nil {
+				log.Debugf("Failed to parse the package.json file at path %s . Error: %q", path, err)
+				return nil
+			}
+			for module, protocol := range protocolModules {
+				if _, ok := packageJSON.Dependencies[module]; ok {
+					addHint(protocol, module+" in "+packageJSONFile)
+				}
+			}
+			return nil
+		}
+		var content []byte
+		for _, library := range protocolLibraries {
+			if !common.IsStringPresent(library.files, info.Name()) {
+				continue
+			}
+			if content == nil {
+				if content, err = ioutil.ReadFile(path); err != nil {
+					log.Debugf("Failed to read the file at path %s while looking for protocol hints. Error: %q", path, err)
+					return nil
+				}
+			}
+			if strings.Contains(string(content), library.library) {
+				addHint(library.protocol, library.library+" in "+info.Name())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("Failed to look for protocol hints in the directory %s . Error: %q", dir, err)
+	}
+	return hints
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetProtocolHints(t *testing.T) {
+	testcases := []struct {
+		name         string
+		files        map[string]string
+		annotations  map[string]string
+		portName     string
+		wantProtocol irtypes.ServiceProtocol
+		wantHints    []string
+	}{
+		{
+			name: "grpc go service",
+			files: map[string]string{
+				"go.mod":             "module example.com/orders\n\nrequire google.golang.org/grpc v1.34.0\n",
+				"proto/orders.proto": "syntax = \"proto3\";\nservice Orders {}\n",
+			},
+			wantProtocol: irtypes.GRPCServiceProtocol,
+			wantHints:    []string{"google.golang.org/grpc in go.mod", protoFilesHint},
+		},
+		{
+			name:         "node websocket server",
+			files:        map[string]string{"package.json": `{"dependencies": {"express": "^4.17.1", "socket.io": "^3.0.4"}}`},
+			wantProtocol: irtypes.WebSocketServiceProtocol,
+			wantHints:    []string{"socket.io in package.json"},
+		},
+		{
+			name:         "labeled in the compose file",
+			files:        map[string]string{"package.json": `{"dependencies": {"ws": "^7.4.1"}}`},
+			annotations:  map[string]string{common.ProtocolAnnotation: "grpc"},
+			wantProtocol: irtypes.GRPCServiceProtocol,
+			wantHints:    []string{"label " + common.ProtocolAnnotation + "=grpc"},
+		},
+		{
+			name:         "named port",
+			portName:     "grpc",
+			wantProtocol: irtypes.GRPCServiceProtocol,
+			wantHints:    []string{"port named grpc"},
+		},
+		{
+			name:         "plain http",
+			files:        map[string]string{"package.json": `{"dependencies": {"express": "^4.17.1"}}`},
+			wantProtocol: irtypes.HTTPServiceProtocol,
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			service := plantypes.NewService("svc", plantypes.Any2KubeTranslation)
+			service.AddSourceArtifact(plantypes.SourceDirectoryArtifactType, writeSessionHintFiles(t, testcase.files))
+			irService := irtypes.NewServiceWithName("svc")
+			irService.Annotations = testcase.annotations
+			irService.AddPortForwarding(irtypes.Port{Number: 8080, Name: testcase.portName}, irtypes.Port{Number: 8080})
+			protocol, hints := getProtocolHints(service, irService)
+			if protocol != testcase.wantProtocol || !reflect.DeepEqual(hints, testcase.wantHints) {
+				t.Fatalf("Failed to detect the protocol. Expected: %q %v Actual: %q %v", testcase.wantProtocol, testcase.wantHints, protocol, hints)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"strconv"
+	"strings"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// setRuntimeOfServices sets the runtime of the service options
+func setRuntimeOfServices(services []plantypes.Service, runtime plantypes.RuntimeSpec) {
+	for i := range services {
+		services[i].Runtime = runtime
+	}
+}
+
+// setDependsOnOfServices sets the services on which the service options depend
+func setDependsOnOfServices(services []plantypes.Service, dependsOn []string) {
+	for i := range services {
+		services[i].DependsOn = dependsOn
+	}
+}
+
+// addRuntimeToContainer overrides the command, the args, the working directory and the user of the container with the ones of the runtime
+// which are set, and sets the env vars of the runtime in the container. Kubernetes does not expand the shell variables, like $PATH, in the
+// values of the env vars, so the values containing a $ are skipped instead of setting the env vars to the literal text.
+func addRuntimeToContainer(container *core.Container, runtime plantypes.RuntimeSpec) {
+	if len(runtime.Command) > 0 {
+		container.Command = runtime.Command
+	}
+	if len(runtime.Args) > 0 {
+		container.Args = runtime.Args
+	}
+	if runtime.WorkingDir != "" {
+		container.WorkingDir = runtime.WorkingDir
+	}
+	for _, env := range runtime.Env {
+		if strings.Contains(env.Value, "$") {
+			log.Warnf("Not setting the env var %s of the container %s , since its value %q refers to other variables, which are not expanded by Kubernetes", env.Name, container.Name, env.Value)
+			continue
+		}
+		found := false
+		for i, containerEnv := range container.Env {
+			if containerEnv.Name == env.Name {
+				container.Env[i] = core.EnvVar{Name: env.Name, Value: env.Value}
+				found = true
+				break
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, core.EnvVar{Name: env.Name, Value: env.Value})
+		}
+	}
+	if runtime.User == "" {
+		return
+	}
+	// Kubernetes runs the container as a UID and a GID, but not as a user name
+	userAndGroup := strings.SplitN(runtime.User, ":", 2)
+	uid, err := strconv.ParseInt(userAndGroup[0], 10, 64)
+	if err != nil {
+		log.Debugf("Not running the container %s as the user %s , since it is not a UID (numeric)", container.Name, runtime.User)
+		return
+	}
+	if container.SecurityContext == nil {
+		container.SecurityContext = &core.SecurityContext{}
+	}
+	container.SecurityContext.RunAsUser = &uid
+	if len(userAndGroup) == 2 {
+		if gid, err := strconv.ParseInt(userAndGroup[1], 10, 64); err == nil {
+			container.SecurityContext.RunAsGroup = &gid
+		}
+	}
+}
+
+// addResourcesToContainer overrides the cpu and memory requests and limits of the container with the ones of the plan which are set
+func addResourcesToContainer(container *core.Container, resources plantypes.ResourceSpec) {
+	container.Resources.Requests = addResourceQuantities(container.Name, container.Resources.Requests, resources.Requests)
+	container.Resources.Limits = addResourceQuantities(container.Name, container.Resources.Limits, resources.Limits)
+}
+
+func addResourceQuantities(containerName string, list core.ResourceList, quantities plantypes.ResourceQuantities) core.ResourceList {
+	for name, value := range map[core.ResourceName]string{core.ResourceCPU: quantities.CPU, core.ResourceMemory: quantities.Memory} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			log.Warnf("Ignoring the %s %s of the container %s . Error: %q", name, value, containerName, err)
+			continue
+		}
+		if list == nil {
+			list = core.ResourceList{}
+		}
+		list[name] = quantity
+	}
+	return list
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	"k8s.io/apimachinery/pkg/api/resource"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestAddRuntimeToContainer(t *testing.T) {
+	container := core.Container{Name: "svc1", Args: []string{"start"}, Env: []core.EnvVar{{Name: "PORT", Value: "3000"}, {Name: "LOG_LEVEL", Value: "info"}}}
+	runtime := plantypes.RuntimeSpec{Command: []string{"node"}, Env: []plantypes.EnvVar{{Name: "PORT", Value: "8080"}, {Name: "NODE_ENV", Value: "production"}}, User: "1000:2000"}
+	addRuntimeToContainer(&container, runtime)
+
+	if !reflect.DeepEqual(container.Command, []string{"node"}) || !reflect.DeepEqual(container.Args, []string{"start"}) {
+		t.Fatalf("Failed to override the command of the container. Actual: %v %v", container.Command, container.Args)
+	}
+	wantEnv := []core.EnvVar{{Name: "PORT", Value: "8080"}, {Name: "LOG_LEVEL", Value: "info"}, {Name: "NODE_ENV", Value: "production"}}
+	if !reflect.DeepEqual(container.Env, wantEnv) {
+		t.Fatalf("Failed to set the env vars of the container. Expected: %+v Actual: %+v", wantEnv, container.Env)
+	}
+	if container.SecurityContext == nil || *container.SecurityContext.RunAsUser != 1000 || *container.SecurityContext.RunAsGroup != 2000 {
+		t.Fatalf("Failed to run the container as the user of the runtime. Actual: %+v", container.SecurityContext)
+	}
+
+	container = core.Container{Name: "svc2"}
+	addRuntimeToContainer(&container, plantypes.RuntimeSpec{User: "node"})
+	if container.SecurityContext != nil {
+		t.Fatalf("Expected the user name to be ignored. Actual: %+v", container.SecurityContext)
+	}
+
+	container = core.Container{Name: "svc3", Env: []core.EnvVar{{Name: "PATH", Value: "/usr/bin"}}}
+	addRuntimeToContainer(&container, plantypes.RuntimeSpec{Env: []plantypes.EnvVar{{Name: "PATH", Value: "/app/bin:$PATH"}, {Name: "HOME_DIR", Value: "${HOME}/app"}, {Name: "PORT", Value: "8080"}}})
+	wantEnv = []core.EnvVar{{Name: "PATH", Value: "/usr/bin"}, {Name: "PORT", Value: "8080"}}
+	if !reflect.DeepEqual(container.Env, wantEnv) {
+		t.Fatalf("Expected the env vars referring to other variables to be skipped. Expected: %+v Actual: %+v", wantEnv, container.Env)
+	}
+}
+
+func TestAddResourcesToContainer(t *testing.T) {
+	container := core.Container{Name: "svc1", Resources: core.ResourceRequirements{Limits: core.ResourceList{core.ResourceMemory: resource.MustParse("512Mi")}}}
+	addResourcesToContainer(&container, plantypes.ResourceSpec{Requests: plantypes.ResourceQuantities{CPU: "250m", Memory: "256Mi"}, Limits: plantypes.ResourceQuantities{CPU: "1", Memory: "one"}})
+
+	wantRequests := core.ResourceList{core.ResourceCPU: resource.MustParse("250m"), core.ResourceMemory: resource.MustParse("256Mi")}
+	if !reflect.DeepEqual(container.Resources.Requests, wantRequests) {
+		t.Fatalf("Failed to set the requests of the container. Expected: %v Actual: %v", wantRequests, container.Resources.Requests)
+	}
+	wantLimits := core.ResourceList{core.ResourceCPU: resource.MustParse("1"), core.ResourceMemory: resource.MustParse("512Mi")}
+	if !reflect.DeepEqual(container.Resources.Limits, wantLimits) {
+		t.Fatalf("Expected the invalid memory limit to be ignored. Expected: %v Actual: %v", wantLimits, container.Resources.Limits)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// cfStickySessionsHint is used when a CF app sets the JSESSIONID cookie, which makes the CF router use sticky sessions
+	cfStickySessionsHint = "Cloud Foundry sticky sessions (JSESSIONID cookie)"
+	// jsessionIDHint is used when the app sets the JSESSIONID cookie
+	jsessionIDHint = "JSESSIONID cookie"
+	// httpSessionHint is used when a Java app uses the servlet HttpSession
+	httpSessionHint = "Java HttpSession"
+	// expressSessionHint is used when a Node.js app uses express-session with the default in memory store
+	expressSessionHint = "express-session without a session store"
+
+	packageJSONFile      = "package.json"
+	expressSessionModule = "express-session"
+	// maxSessionHintFileSize is the size above which files are not searched for session hints
+	maxSessionHintFileSize = 1024 * 1024
+)
+
+var (
+	// sessionStoreModules are the npm modules that store express sessions outside the app instance
+	sessionStoreModules = []string{"connect-redis", "connect-mongo", "connect-mongodb-session", "connect-pg-simple", "express-mysql-session", "connect-session-sequelize", "connect-dynamodb", "@google-cloud/connect-firestore", "connect-memcached"}
+	javaSessionHintExts = []string{".java", ".jsp", ".xml", ".properties", ".groovy", ".kt"}
+	sessionHintSkipDirs = []string{"node_modules", ".git", "target", "build"}
+)
+
+// getSessionHints looks for signs in the source of a service that it keeps user sessions in the memory of the app instance
+func getSessionHints(service plantypes.Service) []string {
+	hints := []string{}
+	isCfApp := len(service.SourceArtifacts[plantypes.CfManifestArtifactType]) > 0 || len(service.SourceArtifacts[plantypes.CfRunningManifestArtifactType]) > 0
+	for _, dir := range service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] {
+		for _, hint := range getSessionHintsInDir(dir) {
+			if hint == jsessionIDHint && isCfApp {
+				hint = cfStickySessionsHint
+			}
+			if !common.IsStringPresent(hints, hint) {
+				hints = append(hints, hint)
+			}
+		}
+	}
+	return hints
+}
+
+func getSessionHintsInDir(dir string) []string {
+	hints := []string{}
+	addHint := func(hint string) {
+		if !common.IsStringPresent(hints, hint) {
+			hints = append(hints, hint)
+		}
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Skipping the path %s while looking for session hints. Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && common.IsStringPresent(sessionHintSkipDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Size() > maxSessionHintFileSize {
+			return nil
+		}
+		if info.Name() == packageJSONFile {
+			if usesExpressSessionWithoutStore(path) {
+				addHint(expressSessionHint)
+			}
+			return nil
+		}
+		if !common.IsStringPresent(javaSessionHintExts, filepath.Ext(path)) {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Debugf("Failed to read the file at path %s while looking for session hints. Error: %q", path, err)
+			return nil
+		}
+		if strings.Contains(string(content), "JSESSIONID") {
+			addHint(jsessionIDHint)
+		}
+		if strings.Contains(string(content), "HttpSession") || strings.Contains(string(content), "<session-config>") {
+			addHint(httpSessionHint)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("Failed to look for session hints in the directory %s . Error: %q", dir, err)
+	}
+	return hints
+}
+
+// usesExpressSessionWithoutStore checks if the package.json depends on express-session but not on any external session store
+func usesExpressSessionWithoutStore(path string) bool {
+	packageJSON := struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}{}
+	if err := common.ReadJSON(path, &packageJSON); err != nil {
+		log.Debugf("Failed to parse the package.json file at path %s . Error: %q", path, err)
+		return false
+	}
+	if _, ok := packageJSON.Dependencies[expressSessionModule]; !ok {
+		return false
+	}
+	for _, module := range sessionStoreModules {
+		if _, ok := packageJSON.Dependencies[module]; ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func writeSessionHintFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create the directory for %s . Error: %q", fullPath, err)
+		}
+		if err := ioutil.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write the file %s . Error: %q", fullPath, err)
+		}
+	}
+	return dir
+}
+
+func TestGetSessionHints(t *testing.T) {
+	testcases := []struct {
+		name    string
+		files   map[string]string
+		isCfApp bool
+		want    []string
+	}{
+		{
+			name:  "express-session without a store",
+			files: map[string]string{"package.json": `{"dependencies": {"express": "^4.17.1", "express-session": "^1.17.1"}}`},
+			want:  []string{expressSessionHint},
+		},
+		{
+			name:  "express-session with a redis store",
+			files: map[string]string{"package.json": `{"dependencies": {"express-session": "^1.17.1", "connect-redis": "^5.0.0"}}`},
+			want:  []string{},
+		},
+		{
+			name: "dependencies of dependencies are ignored",
+			files: map[string]string{
+				"package.json":                         `{"dependencies": {"express": "^4.17.1"}}`,
+				"node_modules/somemodule/package.json": `{"dependencies": {"express-session": "^1.17.1"}}`,
+			},
+			want: []string{},
+		},
+		{
+			name:  "java http session",
+			files: map[string]string{"src/main/java/Cart.java": "import javax.servlet.http.HttpSession;"},
+			want:  []string{httpSessionHint},
+		},
+		{
+			name:    "cf app with the JSESSIONID cookie",
+			files:   map[string]string{"src/main/webapp/WEB-INF/web.xml": "<session-config><cookie-config><name>JSESSIONID</name></cookie-config></session-config>"},
+			isCfApp: true,
+			want:    []string{cfStickySessionsHint, httpSessionHint},
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := writeSessionHintFiles(t, testcase.files)
+			service := plantypes.Service{SourceArtifacts: map[plantypes.SourceArtifactTypeValue][]string{plantypes.SourceDirectoryArtifactType: {dir}}}
+			if testcase.isCfApp {
+				service.SourceArtifacts[plantypes.CfManifestArtifactType] = []string{filepath.Join(dir, "manifest.yml")}
+			}
+			if hints := getSessionHints(service); !reflect.DeepEqual(hints, testcase.want) {
+				t.Fatalf("Failed to get the session hints properly. Expected: %v Actual: %v", testcase.want, hints)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// goSignalHint is used when a Go app is notified of SIGTERM
+	goSignalHint = "Go SIGTERM handler"
+	// nodeSignalHint is used when a Node.js app listens for SIGTERM
+	nodeSignalHint = "Node.js SIGTERM handler"
+	// pythonSignalHint is used when a Python app handles SIGTERM
+	pythonSignalHint = "Python SIGTERM handler"
+	// rubySignalHint is used when a Ruby app traps SIGTERM
+	rubySignalHint = "Ruby SIGTERM handler"
+	// javaShutdownHookHint is used when a Java app adds a shutdown hook or a @PreDestroy method, which are run on SIGTERM
+	javaShutdownHookHint = "Java shutdown hook"
+	// springGracefulShutdownHint is used when a Spring Boot app enables the graceful shutdown of its web server
+	springGracefulShutdownHint = "Spring Boot graceful shutdown"
+
+	// maxShutdownHintFileSize is the size above which files are not searched for shutdown hints
+	maxShutdownHintFileSize = 1024 * 1024
+)
+
+// shutdownHintPatterns are the patterns, per file extension, that show that the app shuts down gracefully on SIGTERM
+var shutdownHintPatterns = map[string]shutdownHintPattern{
+	".go":         {hint: goSignalHint, pattern: regexp.MustCompile(`signal\.Notify\([^)]*SIGTERM`)},
+	".js":         {hint: nodeSignalHint, pattern: regexp.MustCompile(`process\.(on|once)\(\s*['"]SIGTERM['"]`)},
+	".ts":         {hint: nodeSignalHint, pattern: regexp.MustCompile(`process\.(on|once)\(\s*['"]SIGTERM['"]`)},
+	".py":         {hint: pythonSignalHint, pattern: regexp.MustCompile(`signal\.signal\(\s*signal\.SIGTERM`)},
+	".rb":         {hint: rubySignalHint, pattern: regexp.MustCompile(`trap\(\s*['"](SIG)?TERM['"]`)},
+	".java":       {hint: javaShutdownHookHint, pattern: regexp.MustCompile(`addShutdownHook\(|@PreDestroy`)},
+	".kt":         {hint: javaShutdownHookHint, pattern: regexp.MustCompile(`addShutdownHook\(|@PreDestroy`)},
+	".properties": {hint: springGracefulShutdownHint, pattern: regexp.MustCompile(`server\.shutdown\s*[=:]\s*graceful`)},
+	".yml":        {hint: springGracefulShutdownHint, pattern: regexp.MustCompile(`shutdown:\s*graceful`)},
+	".yaml":       {hint: springGracefulShutdownHint, pattern: regexp.MustCompile(`shutdown:\s*graceful`)},
+}
+
+type shutdownHintPattern struct {
+	hint    string
+	pattern *regexp.Regexp
+}
+
+// getShutdownHints looks for signs in the source of a service that it handles SIGTERM to shut down gracefully
+func getShutdownHints(service plantypes.Service) []string {
+	hints := []string{}
+	for _, dir := range service.SourceArtifacts[plantypes.SourceDirectoryArtifactType] {
+		for _, hint := range getShutdownHintsInDir(dir) {
+			if !common.IsStringPresent(hints, hint) {
+				hints = append(hints, hint)
+			}
+		}
+	}
+	sort.Strings(hints)
+	return hints
+}
+
+func getShutdownHintsInDir(dir string) []string {
+	hints := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Skipping the path %s while looking for shutdown hints. Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && common.IsStringPresent(sessionHintSkipDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		pattern, ok := shutdownHintPatterns[filepath.Ext(path)]
+		if !ok || common.IsStringPresent(hints, pattern.hint) || info.Size() > maxShutdownHintFileSize {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Debugf("Failed to read the file at path %s while looking for shutdown hints. Error: %q", path, err)
+			return nil
+		}
+		if pattern.pattern.Match(content) {
+			hints = append(hints, pattern.hint)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debugf("Failed to look for shutdown hints in the directory %s . Error: %q", dir, err)
+	}
+	return hints
+}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetShutdownHints(t *testing.T) {
+	testcases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "go signal handler",
+			files: map[string]string{"main.go": "signal.Notify(stop, os.Interrupt, syscall.SIGTERM)"},
+			want:  []string{goSignalHint},
+		},
+		{
+			name: "node.js signal handler and spring boot graceful shutdown",
+			files: map[string]string{
+				"server.js": "process.on('SIGTERM', () => server.close())",
+				"api/src/main/resources/application.properties": "server.shutdown=graceful",
+			},
+			want: []string{nodeSignalHint, springGracefulShutdownHint},
+		},
+		{
+			name:  "handlers in the dependencies are ignored",
+			files: map[string]string{"node_modules/somemodule/index.js": "process.once(\"SIGTERM\", cleanup)"},
+			want:  []string{},
+		},
+		{
+			name:  "no signal handler",
+			files: map[string]string{"app.py": "import signal\nsignal.signal(signal.SIGINT, handler)"},
+			want:  []string{},
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := writeSessionHintFiles(t, testcase.files)
+			service := plantypes.Service{SourceArtifacts: map[plantypes.SourceArtifactTypeValue][]string{plantypes.SourceDirectoryArtifactType: {dir}}}
+			if hints := getShutdownHints(service); !reflect.DeepEqual(hints, testcase.want) {
+				t.Fatalf("Failed to get the shutdown hints properly. Expected: %v Actual: %v", testcase.want, hints)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/containerizer/scripts"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	angularJSONFile = "angular.json"
+	// staticSitePort is the port on which the nginx image serves the static site
+	staticSitePort = 8080
+	// StaticSiteBuildPath is the directory in which the static site is built, in the build stage of the generated Dockerfile
+	StaticSiteBuildPath = "/opt/app-root/src"
+)
+
+// staticSiteFrameworks are the dependencies of the frameworks which build static sites, along with the default build directories
+var staticSiteFrameworks = []struct {
+	name       string
+	dependency string
+	buildDir   string
+}{
+	{name: "create-react-app", dependency: "react-scripts", buildDir: "build"},
+	{name: "angular", dependency: "@angular/core", buildDir: "dist"},
+	{name: "vue", dependency: "@vue/cli-service", buildDir: "dist"},
+	{name: "vue", dependency: "vue", buildDir: "dist"},
+}
+
+// serverDependencies are the dependencies of the apps which render on a server, instead of being built into static sites
+var serverDependencies = []string{"express", "koa", "fastify", "@nestjs/core", "next", "nuxt"}
+
+// getStaticSite returns the framework and the build directory if the directory contains a frontend built into a static site
+func getStaticSite(dir string) (plantypes.StaticSite, bool) {
+	packageJSONPath := filepath.Join(dir, packageJSONFile)
+	if _, err := os.Stat(packageJSONPath); err != nil {
+		return plantypes.StaticSite{}, false
+	}
+	packageJSON := struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}{}
+	if err := common.ReadJSON(packageJSONPath, &packageJSON); err != nil {
+		log.Debugf("Unable to parse the file at path %s . Error: %q", packageJSONPath, err)
+		return plantypes.StaticSite{}, false
+	}
+	hasDependency := func(dependency string) bool {
+		_, ok := packageJSON.Dependencies[dependency]
+		_, devOk := packageJSON.DevDependencies[dependency]
+		return ok || devOk
+	}
+	for _, dependency := range serverDependencies {
+		if hasDependency(dependency) {
+			return plantypes.StaticSite{}, false
+		}
+	}
+	for _, framework := range staticSiteFrameworks {
+		if !hasDependency(framework.dependency) {
+			continue
+		}
+		staticSite := plantypes.StaticSite{Framework: framework.name, BuildDir: framework.buildDir}
+		if framework.dependency == "@angular/core" {
+			if outputPath := getAngularOutputPath(dir); outputPath != "" {
+				staticSite.BuildDir = outputPath
+			}
+		}
+		return staticSite, true
+	}
+	return plantypes.StaticSite{}, false
+}
+
+// getAngularOutputPath returns the output path of the build of the first project in the angular.json file
+func getAngularOutputPath(dir string) string {
+	angularJSONPath := filepath.Join(dir, angularJSONFile)
+	if _, err := os.Stat(angularJSONPath); err != nil {
+		return ""
+	}
+	angularJSON := struct {
+		Projects map[string]struct {
+			Architect struct {
+				Build struct {
+					Options struct {
+						OutputPath string `json:"outputPath"`
+					} `json:"options"`
+				} `json:"build"`
+			} `json:"architect"`
+		} `json:"projects"`
+	}{}
+	if err := common.ReadJSON(angularJSONPath, &angularJSON); err != nil {
+		log.Debugf("Unable to parse the file at path %s . Error: %q", angularJSONPath, err)
+		return ""
+	}
+	projectNames := []string{}
+	for projectName := range angularJSON.Projects {
+		projectNames = append(projectNames, projectName)
+	}
+	sort.Strings(projectNames)
+	for _, projectName := range projectNames {
+		if outputPath := angularJSON.Projects[projectName].Architect.Build.Options.OutputPath; outputPath != "" {
+			return filepath.Clean(outputPath)
+		}
+	}
+	return ""
+}
+
+// getStaticSiteContainer returns the container which builds the static site. If it is served by nginx, the built
+// files are copied into an nginx image. Otherwise the image only contains the build, to be copied by its backend.
+func getStaticSiteContainer(plan plantypes.Plan, service plantypes.Service) (irtypes.Container, error) {
+	container := irtypes.NewContainer(plantypes.DockerFileContainerBuildTypeValue, service.Image, true)
+	container.RepoInfo = service.RepoInfo
+	if len(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType]) == 0 {
+		return container, fmt.Errorf("the static site %s has no source directory", service.ServiceName)
+	}
+	sourceCodeDir := service.SourceArtifacts[plantypes.SourceDirectoryArtifactType][0]
+	relOutputPath, err := plan.GetRelativePath(sourceCodeDir)
+	if err != nil {
+		return container, err
+	}
+	nginx := service.StaticSite.Serving == plantypes.NginxStaticSiteServing
+	dockerfileContents, err := common.GetStringFromTemplate(scripts.StaticSite_Dockerfile, struct {
+		Nginx    bool
+		BuildDir string
+		Port     int
+	}{
+		Nginx:    nginx,
+		BuildDir: filepath.ToSlash(service.StaticSite.BuildDir),
+		Port:     staticSitePort,
+	})
+	if err != nil {
+		return container, err
+	}
+	dockerfileName := dockerfilePrefix + service.ServiceName
+	dockerfilePath := filepath.Join(relOutputPath, dockerfileName)
+	container.AddFile(dockerfilePath, dockerfileContents)
+	dockerBuildScript, err := common.GetStringFromTemplate(scripts.Dockerbuild_sh, struct {
+		Dockerfilename string
+		ImageName      string
+		Context        string
+	}{
+		Dockerfilename: dockerfileName,
+		ImageName:      service.Image,
+		Context:        ".",
+	})
+	if err != nil {
+		return container, err
+	}
+	container.AddFile(filepath.Join(relOutputPath, service.ServiceName+dockerBuildScriptName), dockerBuildScript)
+	container.RepoInfo.TargetPath = filepath.Join(container.RepoInfo.GitRepoDir, dockerfilePath)
+	if nginx {
+		container.AddExposedPort(staticSitePort)
+	}
+	return container, nil
+}
+
+// addStaticSiteSync adds the sync of the static site to its bucket, instead of deploying it as a service
+func addStaticSiteSync(ir *irtypes.IR, plan plantypes.Plan, service plantypes.Service) error {
+	if len(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType]) == 0 {
+		return fmt.Errorf("the static site %s has no source directory", service.ServiceName)
+	}
+	relSourceDir, err := plan.GetRelativePath(service.SourceArtifacts[plantypes.SourceDirectoryArtifactType][0])
+	if err != nil {
+		return err
+	}
+	ir.StaticSiteSyncs = append(ir.StaticSiteSyncs, irtypes.StaticSiteSync{
+		ServiceName: service.ServiceName,
+		SourceDir:   relSourceDir,
+		BuildDir:    service.StaticSite.BuildDir,
+		BucketURL:   service.StaticSite.BucketURL,
+	})
+	return nil
+}
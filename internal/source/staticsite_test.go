@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGetStaticSite(t *testing.T) {
+	testcases := []struct {
+		name        string
+		packageJSON string
+		angularJSON string
+		want        plantypes.StaticSite
+		wantOk      bool
+	}{
+		{
+			name:        "create-react-app",
+			packageJSON: `{"dependencies": {"react": "^17.0.1", "react-scripts": "4.0.1"}}`,
+			want:        plantypes.StaticSite{Framework: "create-react-app", BuildDir: "build"},
+			wantOk:      true,
+		},
+		{
+			name:        "angular with an output path",
+			packageJSON: `{"dependencies": {"@angular/core": "~11.0.0"}}`,
+			angularJSON: `{"projects": {"shop": {"architect": {"build": {"options": {"outputPath": "dist/shop"}}}}}}`,
+			want:        plantypes.StaticSite{Framework: "angular", BuildDir: "dist/shop"},
+			wantOk:      true,
+		},
+		{
+			name:        "vue cli",
+			packageJSON: `{"dependencies": {"vue": "^2.6.11"}, "devDependencies": {"@vue/cli-service": "~4.5.0"}}`,
+			want:        plantypes.StaticSite{Framework: "vue", BuildDir: "dist"},
+			wantOk:      true,
+		},
+		{
+			name:        "server side rendering",
+			packageJSON: `{"dependencies": {"react": "^17.0.1", "next": "10.0.3"}}`,
+		},
+		{
+			name:        "node backend",
+			packageJSON: `{"dependencies": {"express": "^4.17.1"}}`,
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := ioutil.WriteFile(filepath.Join(dir, packageJSONFile), []byte(testcase.packageJSON), 0644); err != nil {
+				t.Fatalf("Failed to write the package.json file. Error: %q", err)
+			}
+			if testcase.angularJSON != "" {
+				if err := ioutil.WriteFile(filepath.Join(dir, angularJSONFile), []byte(testcase.angularJSON), 0644); err != nil {
+					t.Fatalf("Failed to write the angular.json file. Error: %q", err)
+				}
+			}
+			staticSite, ok := getStaticSite(dir)
+			if ok != testcase.wantOk || staticSite != testcase.want {
+				t.Fatalf("Failed to detect the static site. Expected: %+v %t Actual: %+v %t", testcase.want, testcase.wantOk, staticSite, ok)
+			}
+		})
+	}
+}
+
+func TestGetStaticSiteContainer(t *testing.T) {
+	plan := plantypes.NewPlan()
+	plan.Spec.Inputs.RootDir = t.TempDir()
+	service := plantypes.NewService("frontend", plantypes.Any2KubeTranslation)
+	service.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+	service.AddSourceArtifact(plantypes.SourceDirectoryArtifactType, filepath.Join(plan.Spec.Inputs.RootDir, "frontend"))
+	service.StaticSite = plantypes.StaticSite{Framework: "create-react-app", BuildDir: "build", Serving: plantypes.NginxStaticSiteServing}
+
+	container, err := getStaticSiteContainer(plan, service)
+	if err != nil {
+		t.Fatalf("Failed to get the container of the static site. Error: %q", err)
+	}
+	dockerfile := container.NewFiles[filepath.Join("frontend", "Dockerfile.frontend")]
+	if !strings.Contains(dockerfile, "COPY --from=build /opt/app-root/src/build .") {
+		t.Fatalf("Expected the built files to be copied into the nginx image. Actual:\n%s", dockerfile)
+	}
+	if len(container.ExposedPorts) != 1 || container.ExposedPorts[0] != staticSitePort {
+		t.Fatalf("Expected the port %d to be exposed. Actual: %v", staticSitePort, container.ExposedPorts)
+	}
+
+	// The image built for a backend only contains the build stage
+	service.StaticSite.Serving = plantypes.BackendStaticSiteServing
+	container, err = getStaticSiteContainer(plan, service)
+	if err != nil {
+		t.Fatalf("Failed to get the container of the static site. Error: %q", err)
+	}
+	dockerfile = container.NewFiles[filepath.Join("frontend", "Dockerfile.frontend")]
+	if strings.Contains(dockerfile, "nginx") || len(container.ExposedPorts) != 0 {
+		t.Fatalf("Expected only the build stage for a static site served by a backend. Actual:\n%s", dockerfile)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/source/compose"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+// SwarmTranslator implements Translator interface for the Docker Swarm stack files.
+// The stack files are compose files whose deploy settings, like the placement constraints, are used by docker stack deploy.
+type SwarmTranslator struct {
+}
+
+// GetTranslatorType returns the translator type
+func (*SwarmTranslator) GetTranslatorType() plantypes.TranslationTypeValue {
+	return plantypes.Swarm2KubeTranslation
+}
+
+// GetServiceOptions returns the services of the Docker Swarm stack files
+func (swarmTranslator *SwarmTranslator) GetServiceOptions(inputPath string, plan plantypes.Plan) ([]plantypes.Service, error) {
+	services := []plantypes.Service{}
+	yamlPaths, err := common.GetFilesByExt(inputPath, []string{".yaml", ".yml"})
+	if err != nil {
+		log.Errorf("Unable to fetch yaml files at path %s Error: %q", inputPath, err)
+		return services, err
+	}
+	imageMetadataPaths, err := getImageMetadataPaths(inputPath)
+	if err != nil {
+		return services, err
+	}
+	sort.Strings(yamlPaths)
+	for _, path := range yamlPaths {
+		if !compose.IsV3SwarmStack(path) {
+			continue
+		}
+		log.Debugf("Found a Docker Swarm stack file at path %s", path)
+		// The services of a stack are planned like the services of a compose file
+		for _, service := range new(ComposeTranslator).getServicesFromComposeFile(path, imageMetadataPaths) {
+			services = append(services, swarmTranslator.toSwarmService(service))
+		}
+	}
+	return services, nil
+}
+
+// toSwarmService changes the translation type, the source type and the artifacts of a compose service to the ones of a stack service
+func (swarmTranslator *SwarmTranslator) toSwarmService(service plantypes.Service) plantypes.Service {
+	service.TranslationType = swarmTranslator.GetTranslatorType()
+	sourceTypes := []plantypes.SourceTypeValue{}
+	for _, sourceType := range service.SourceTypes {
+		if sourceType == plantypes.ComposeSourceTypeValue {
+			sourceType = plantypes.SwarmStackSourceTypeValue
+		}
+		sourceTypes = append(sourceTypes, sourceType)
+	}
+	service.SourceTypes = sourceTypes
+	service.SourceArtifacts[plantypes.SwarmStackArtifactType] = service.SourceArtifacts[plantypes.ComposeFileArtifactType]
+	delete(service.SourceArtifacts, plantypes.ComposeFileArtifactType)
+	return service
+}
+
+// Translate translates the services of the Docker Swarm stack files to IR
+func (swarmTranslator *SwarmTranslator) Translate(services []plantypes.Service, plan plantypes.Plan) (irtypes.IR, error) {
+	ir := irtypes.NewIR(plan)
+	for _, service := range services {
+		if service.TranslationType != swarmTranslator.GetTranslatorType() {
+			log.Debugf("Expected service to have %s translation type. Got %s . Skipping.", swarmTranslator.GetTranslatorType(), service.TranslationType)
+			continue
+		}
+		for _, path := range service.SourceArtifacts[plantypes.SwarmStackArtifactType] {
+			log.Debugf("File %s being loaded from Docker Swarm stack service : %s", path, service.ServiceName)
+			sir, err := new(compose.V3Loader).ConvertToIR(path, plan, service)
+			if err != nil {
+				log.Errorf("Unable to parse the Docker Swarm stack file at path %s Error: %q", path, err)
+				continue
+			}
+			ir.Merge(sir)
+		}
+		addImageInfoContainers(&ir, service)
+	}
+	return ir, nil
+}
+
+func (swarmTranslator *SwarmTranslator) newService(serviceName string) plantypes.Service {
+	service := plantypes.NewService(serviceName, swarmTranslator.GetTranslatorType())
+	service.AddSourceType(plantypes.SwarmStackSourceTypeValue)
+	service.ContainerBuildType = plantypes.ReuseContainerBuildTypeValue
+	return service
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package source
 
 import (
+	"fmt"
+	"sort"
+
 	log "github.com/sirupsen/logrus"
 
+	"github.com/konveyor/move2kube/internal/common"
 	irtypes "github.com/konveyor/move2kube/internal/types"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 )
@@ -33,7 +37,7 @@ type Translator interface {
 
 // GetTranslators returns translator for given format
 func GetTranslators() []Translator {
-	var l = []Translator{new(DockerfileTranslator), new(ComposeTranslator), new(CfManifestTranslator), new(Any2KubeTranslator)} //Any2Kube should be the last option
+	var l = []Translator{new(DockerfileTranslator), new(SwarmTranslator), new(ComposeTranslator), new(CfManifestTranslator), new(HerokuTranslator), new(CloudRunTranslator), new(AppEngineTranslator), new(AzureTranslator), new(ECSTranslator), new(NomadTranslator), new(CloudFormationTranslator), new(Any2KubeTranslator)} //Any2Kube should be the last option
 	return l
 }
 
@@ -57,12 +61,17 @@ func Translate(p plantypes.Plan) (irtypes.IR, error) {
 		for _, services := range p.Spec.Inputs.Services {
 			//Choose the first service even if there are multiple options
 			service := services[0]
-			if service.TranslationType == l.GetTranslatorType() {
-				validservices = append(validservices, service)
+			// The build steps are translated like the services and composed with their services after the translation
+			for _, s := range append([]plantypes.Service{service}, service.BuildSteps.Steps...) {
+				if s.TranslationType == l.GetTranslatorType() {
+					validservices = append(validservices, s)
+				}
 			}
 		}
 		log.Debugf("Services to translate : %d", len(validservices))
+		endRegion := common.TraceRegion(fmt.Sprintf("translate %T", l))
 		currir, err := l.Translate(validservices, p)
+		endRegion()
 		log.Debugf("Services translated : %d", len(currir.Services))
 		log.Debugf("Containers translated : %d", len(currir.Containers))
 		if err != nil {
@@ -74,7 +83,196 @@ func Translate(p plantypes.Plan) (irtypes.IR, error) {
 		log.Debugf("Total Services after translation : %d", len(ir.Services))
 		log.Debugf("Total Containers after translation : %d", len(ir.Containers))
 	}
+	composeBuildSteps(&ir, p)
+	addPlanPorts(&ir, p)
+	addPlanRuntime(&ir, p)
+	addPlanResources(&ir, p)
+	addSessionHints(&ir, p)
+	addProtocolHints(&ir, p)
+	addShutdownHints(&ir, p)
+	addCronEntries(&ir, p)
+	addProcessManagers(&ir, p)
+	addDaprHints(&ir, p)
+	addJEEDescriptors(&ir, p)
+	addPlanDependencies(&ir, p)
 	log.Infoln("Translation done")
 
 	return ir, nil
 }
+
+// addPlanPorts adds the ports of the services in the plan to the translated services
+func addPlanPorts(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 || len(services[0].Ports) == 0 {
+			continue
+		}
+		addPlanPortsToService(&irService, services[0].Ports)
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addPlanRuntime adds the runtime of the services in the plan, like their command and env vars, to the main containers of the translated services
+func addPlanRuntime(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 || len(irService.Containers) == 0 {
+			continue
+		}
+		addRuntimeToContainer(&irService.Containers[0], services[0].Runtime)
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addPlanDependencies adds the services on which the services in the plan depend to the translated services
+func addPlanDependencies(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 || len(services[0].DependsOn) == 0 {
+			continue
+		}
+		irService.DependsOn = services[0].DependsOn
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addPlanResources adds the cpu and memory requests and limits of the services in the plan to the main containers of the translated services
+func addPlanResources(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 || len(irService.Containers) == 0 {
+			continue
+		}
+		addResourcesToContainer(&irService.Containers[0], services[0].Resources)
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addSessionHints adds to the translated services the hints that they keep user sessions in memory
+func addSessionHints(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		irService.SessionHints = getSessionHints(services[0])
+		if len(irService.SessionHints) > 0 {
+			log.Debugf("Found session hints %v for the service %s", irService.SessionHints, serviceName)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addProtocolHints adds to the translated services the protocol they serve, when it is gRPC or WebSocket, along with the hints
+func addProtocolHints(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		irService.Protocol, irService.ProtocolHints = getProtocolHints(services[0], irService)
+		if len(irService.ProtocolHints) > 0 {
+			log.Debugf("Found the protocol %s for the service %s from %v", irService.Protocol, serviceName, irService.ProtocolHints)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addShutdownHints adds to the translated services the hints that they shut down gracefully on SIGTERM
+func addShutdownHints(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		irService.ShutdownHints = getShutdownHints(services[0])
+		if len(irService.ShutdownHints) > 0 {
+			log.Debugf("Found shutdown hints %v for the service %s", irService.ShutdownHints, serviceName)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addCronEntries adds to the translated services the entries of the crontabs run by cron in their images
+func addCronEntries(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		irService.CronEntries = getCronEntries(services[0])
+		if len(irService.CronEntries) > 0 {
+			log.Debugf("Found %d crontab entries for the service %s", len(irService.CronEntries), serviceName)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addProcessManagers adds to the translated services the processes run by process managers, like supervisord, in their images
+func addProcessManagers(ir *irtypes.IR, p plantypes.Plan) {
+	for serviceName, services := range p.Spec.Inputs.Services {
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		irService.ProcessManager, irService.Processes = getProcesses(services[0])
+		if len(irService.Processes) > 0 {
+			log.Debugf("Found %d processes run by %s for the service %s", len(irService.Processes), irService.ProcessManager, serviceName)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addDaprHints adds to the translated services the hints that they use Dapr, and adds the Dapr components found in their source.
+// The services are visited in order, so that the first service defining a component wins.
+func addDaprHints(ir *irtypes.IR, p plantypes.Plan) {
+	serviceNames := []string{}
+	for serviceName := range p.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		services := p.Spec.Inputs.Services[serviceName]
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		hints, components := getDaprHints(services[0])
+		irService.DaprHints = hints
+		if len(irService.DaprHints) > 0 {
+			log.Debugf("Found Dapr hints %v for the service %s", irService.DaprHints, serviceName)
+		}
+		for _, component := range components {
+			if !ir.AddDaprComponent(component) {
+				log.Debugf("Ignoring the Dapr component %s of the service %s, since a component with the same name already exists", component.Name, serviceName)
+			}
+		}
+		ir.Services[serviceName] = irService
+	}
+}
+
+// addJEEDescriptors adds to the translated services what is extracted from the deployment descriptors of the JEE apps, like their context roots,
+// and the configuration of the app server of the traditional apps which are modernized
+func addJEEDescriptors(ir *irtypes.IR, p plantypes.Plan) {
+	serviceNames := []string{}
+	for serviceName := range p.Spec.Inputs.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		services := p.Spec.Inputs.Services[serviceName]
+		irService, ok := ir.Services[serviceName]
+		if !ok || len(services) == 0 {
+			continue
+		}
+		descriptors := getJEEDescriptors(services[0])
+		if len(descriptors.ContextRoots) > 0 || len(descriptors.ResourceRefs) > 0 || len(descriptors.JMSDestinations) > 0 || len(descriptors.SecurityRoles) > 0 || len(descriptors.AppServerFeatures) > 0 {
+			log.Debugf("Found the deployment descriptors of the service %s : %+v", serviceName, descriptors)
+			addJEEDescriptorsToService(ir, &irService, descriptors)
+		}
+		if err := addJEEModernization(ir, &irService, p, services[0], descriptors); err != nil {
+			log.Errorf("Failed to add the configuration of the app server to the service %s . Error: %q", serviceName, err)
+		}
+		ir.Services[serviceName] = irService
+	}
+}
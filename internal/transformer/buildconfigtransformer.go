@@ -44,6 +44,7 @@ type BuildconfigTransformer struct {
 	TargetClusterSpec             collecttypes.ClusterMetadataSpec
 	IgnoreUnsupportedKinds        bool
 	extraFiles                    map[string]string // file path: file contents
+	complianceReporting
 }
 
 const (
@@ -88,7 +89,7 @@ func (bcTransformer *BuildconfigTransformer) WriteObjects(outputPath string, tra
 	cicdPath := filepath.Join(outputPath, common.DeployDir, "cicd")
 	// deploy/cicd/buildconfig/
 	bcPath := filepath.Join(cicdPath, "buildconfig")
-	if _, err := writeTransformedObjects(bcPath, bcTransformer.transformedBuildConfigObjects, bcTransformer.TargetClusterSpec, false, transformPaths); err != nil {
+	if _, err := writeTransformedObjects(bcPath, bcTransformer.transformedBuildConfigObjects, bcTransformer.TargetClusterSpec, false, transformPaths, bcTransformer.violations); err != nil {
 		log.Errorf("Error occurred while writing transformed objects. Error: %q", err)
 		return err
 	}
@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// configChecksumAnnotation is the annotation of the pod templates containing the checksum of their config, like the charts created by helm create
+	configChecksumAnnotation = "checksum/config"
+	configMapKind            = "ConfigMap"
+	secretKind               = "Secret"
+)
+
+// addConfigChecksums annotates the pod templates of the workloads with the checksum of the config maps and secrets they
+// use, so that the workloads are rolled out when the config changes. If helm is true, the checksum is computed by Helm
+// from the rendered templates of the config maps and secrets, since their content depends on the values.
+func addConfigChecksums(objs []runtime.Object, helm bool) []runtime.Object {
+	configs := map[string]runtime.Object{} // [kind/name][config map or secret]
+	for _, obj := range objs {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		if kind == configMapKind || kind == secretKind {
+			configs[kind+"/"+common.GetRuntimeObjectMetadata(obj).Name] = obj
+		}
+	}
+	if len(configs) == 0 {
+		return objs
+	}
+	for i, obj := range objs {
+		unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			log.Debugf("Failed to convert the object %+v to unstructured. Error: %q", obj.GetObjectKind(), err)
+			continue
+		}
+		template := getPodTemplate(unstructuredObj)
+		if template == nil {
+			continue
+		}
+		podSpec, _ := template["spec"].(map[string]interface{})
+		refs := []string{}
+		for _, ref := range getPodSpecConfigRefs(podSpec) {
+			if _, ok := configs[ref]; ok {
+				refs = append(refs, ref)
+			}
+		}
+		if len(refs) == 0 {
+			continue
+		}
+		checksum := ""
+		if helm {
+			includes := []string{}
+			for _, ref := range refs {
+				includes = append(includes, fmt.Sprintf(`(include (print $.Template.BasePath "/%s") .)`, getFilename(configs[ref])))
+			}
+			checksum = "{{ print " + strings.Join(includes, " ") + " | sha256sum }}"
+		} else {
+			hash := sha256.New()
+			for _, ref := range refs {
+				configYamlBytes, err := common.MarshalObjToYaml(configs[ref])
+				if err != nil {
+					log.Errorf("Failed to marshal the %s to yaml. Error: %q", ref, err)
+					continue
+				}
+				hash.Write(configYamlBytes)
+			}
+			checksum = fmt.Sprintf("%x", hash.Sum(nil))
+		}
+		metadata, ok := template["metadata"].(map[string]interface{})
+		if !ok {
+			metadata = map[string]interface{}{}
+			template["metadata"] = metadata
+		}
+		annotations, ok := metadata["annotations"].(map[string]interface{})
+		if !ok {
+			annotations = map[string]interface{}{}
+			metadata["annotations"] = annotations
+		}
+		annotations[configChecksumAnnotation] = checksum
+		newObj := obj.DeepCopyObject()
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj, newObj); err != nil {
+			log.Errorf("Failed to add the config checksum to the object %+v . Error: %q", obj.GetObjectKind(), err)
+			continue
+		}
+		objs[i] = newObj
+	}
+	return objs
+}
+
+// getPodTemplate returns the pod template of the workload, like a deployment or a cron job
+func getPodTemplate(obj map[string]interface{}) map[string]interface{} {
+	spec, _ := obj["spec"].(map[string]interface{})
+	if jobTemplate, ok := spec["jobTemplate"].(map[string]interface{}); ok {
+		spec, _ = jobTemplate["spec"].(map[string]interface{})
+	}
+	template, _ := spec["template"].(map[string]interface{})
+	return template
+}
+
+// getPodSpecConfigRefs returns the sorted config maps and secrets used by the pod spec, as kind/name
+func getPodSpecConfigRefs(podSpec map[string]interface{}) []string {
+	refs := []string{}
+	add := func(kind string, source interface{}, nameKey string) {
+		sourceMap, ok := source.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if name, ok := sourceMap[nameKey].(string); ok && name != "" && !common.IsStringPresent(refs, kind+"/"+name) {
+			refs = append(refs, kind+"/"+name)
+		}
+	}
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, volume := range volumes {
+		volumeMap, _ := volume.(map[string]interface{})
+		add(configMapKind, volumeMap["configMap"], "name")
+		add(secretKind, volumeMap["secret"], "secretName")
+		projected, _ := volumeMap["projected"].(map[string]interface{})
+		sources, _ := projected["sources"].([]interface{})
+		for _, source := range sources {
+			sourceMap, _ := source.(map[string]interface{})
+			add(configMapKind, sourceMap["configMap"], "name")
+			add(secretKind, sourceMap["secret"], "name")
+		}
+	}
+	containers, _ := podSpec["initContainers"].([]interface{})
+	moreContainers, _ := podSpec["containers"].([]interface{})
+	for _, container := range append(containers, moreContainers...) {
+		containerMap, _ := container.(map[string]interface{})
+		envFroms, _ := containerMap["envFrom"].([]interface{})
+		for _, envFrom := range envFroms {
+			envFromMap, _ := envFrom.(map[string]interface{})
+			add(configMapKind, envFromMap["configMapRef"], "name")
+			add(secretKind, envFromMap["secretRef"], "name")
+		}
+		envs, _ := containerMap["env"].([]interface{})
+		for _, env := range envs {
+			envMap, _ := env.(map[string]interface{})
+			valueFrom, _ := envMap["valueFrom"].(map[string]interface{})
+			add(configMapKind, valueFrom["configMapKeyRef"], "name")
+			add(secretKind, valueFrom["secretKeyRef"], "name")
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAddConfigChecksums(t *testing.T) {
+	newObjs := func(config string) []runtime.Object {
+		configMap := &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web-config"},
+			Data:       map[string]string{"app.conf": config},
+		}
+		secret := &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web-secret"},
+		}
+		deployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}}
+		deployment.Name = "web"
+		deployment.Spec.Template.Spec = corev1.PodSpec{
+			Volumes: []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}}},
+			Containers: []corev1.Container{{
+				Name:    "web",
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-secret"}}}},
+			}},
+		}
+		worker := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}}
+		worker.Name = "worker"
+		return []runtime.Object{configMap, secret, deployment, worker}
+	}
+	getChecksum := func(objs []runtime.Object, i int) string {
+		return objs[i].(*appsv1.Deployment).Spec.Template.Annotations[configChecksumAnnotation]
+	}
+
+	objs := addConfigChecksums(newObjs("a=1"), false)
+	checksum := getChecksum(objs, 2)
+	if len(checksum) != 64 {
+		t.Fatalf("Expected the deployment to be annotated with the sha256 checksum of its config. Actual: %q", checksum)
+	}
+	if getChecksum(objs, 3) != "" {
+		t.Fatalf("Expected the deployment without config not to be annotated")
+	}
+	if changed := getChecksum(addConfigChecksums(newObjs("a=2"), false), 2); changed == checksum {
+		t.Fatalf("Expected the checksum to change along with the config")
+	}
+	want := `{{ print (include (print $.Template.BasePath "/web-config-configmap.yaml") .) (include (print $.Template.BasePath "/web-secret-secret.yaml") .) | sha256sum }}`
+	if helmChecksum := getChecksum(addConfigChecksums(newObjs("a=1"), true), 2); helmChecksum != want {
+		t.Fatalf("Expected the checksum to be computed by Helm. Expected: %s Actual: %s", want, helmChecksum)
+	}
+}
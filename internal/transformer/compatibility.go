@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/starlark/gettransformdata"
+	startypes "github.com/konveyor/move2kube/internal/starlark/types"
+	"github.com/konveyor/move2kube/internal/transformer/compliance"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/internal/types/gatewayapi"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Incompatibility is a change an object generated for a service needs to run on the target cluster
+type Incompatibility struct {
+	Kind    string
+	Name    string
+	Message string
+}
+
+func (i Incompatibility) String() string {
+	if i.Name == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s %s : %s", i.Kind, i.Name, i.Message)
+}
+
+// ServiceCompatibility has the kinds generated for a service for the target cluster and the changes they need
+type ServiceCompatibility struct {
+	Kinds             []string
+	Incompatibilities []Incompatibility
+}
+
+// IsCompatible returns true if the objects of the service run on the target cluster without changes
+func (c ServiceCompatibility) IsCompatible() bool {
+	return len(c.Incompatibilities) == 0
+}
+
+// CheckCompatibility converts the IR to objects for the target cluster of the IR, without writing them, and returns
+// the kinds generated for each service and the changes they need. The objects not named after a service are under the empty name.
+func CheckCompatibility(ir irtypes.IR) map[string]ServiceCompatibility {
+	clusterSpec := ir.TargetClusterSpec
+	objs := convertIRToObjects(irtypes.NewEnhancedIRFromIR(ir), NewK8sTransformer().getAPIResources())
+	compatibilities := map[string]ServiceCompatibility{}
+	for serviceName := range ir.Services {
+		compatibilities[serviceName] = ServiceCompatibility{Kinds: []string{}, Incompatibilities: []Incompatibility{}}
+	}
+	addIncompatibility := func(serviceName string, incompatibility Incompatibility) {
+		c := compatibilities[serviceName]
+		c.Incompatibilities = append(c.Incompatibilities, incompatibility)
+		compatibilities[serviceName] = c
+	}
+	k8sResources := []startypes.K8sResourceT{}
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		name := ""
+		if accessor, err := meta.Accessor(obj); err == nil {
+			name = accessor.GetName()
+		}
+		serviceName := getObjectServiceName(name, ir)
+		c := compatibilities[serviceName]
+		if !common.IsStringPresent(c.Kinds, gvk.Kind) {
+			c.Kinds = append(c.Kinds, gvk.Kind)
+		}
+		compatibilities[serviceName] = c
+		versions := clusterSpec.GetSupportedVersions(gvk.Kind)
+		if len(versions) == 0 {
+			addIncompatibility(serviceName, Incompatibility{Kind: gvk.Kind, Name: name, Message: "The kind is not supported by the target cluster."})
+		} else if gv := gvk.GroupVersion().String(); gv != "" && !common.IsStringPresent(versions, gv) {
+			addIncompatibility(serviceName, Incompatibility{Kind: gvk.Kind, Name: name, Message: fmt.Sprintf("The version %s is not supported by the target cluster, which supports %s .", gv, strings.Join(versions, ", "))})
+		}
+		k8sResource, err := gettransformdata.GetK8sResourceFromObject(obj)
+		if err != nil {
+			log.Debugf("Failed to convert the object into a K8sResourceT. Object:\n%+v\nError: %q", obj, err)
+			continue
+		}
+		k8sResources = append(k8sResources, k8sResource)
+	}
+	for _, violation := range compliance.Check(k8sResources, clusterSpec.Constraints) {
+		message := fmt.Sprintf("Violates the %s restriction of the target cluster: %s", violation.Restriction, violation.Message)
+		addIncompatibility(getObjectServiceName(violation.Name, ir), Incompatibility{Kind: violation.Kind, Name: violation.Name, Message: message})
+	}
+	for _, storage := range ir.Storages {
+		if storage.StorageType != irtypes.PVCKind || storage.PersistentVolumeClaimSpec.StorageClassName == nil || *storage.PersistentVolumeClaimSpec.StorageClassName == "" {
+			continue
+		}
+		if !common.IsStringPresent(clusterSpec.StorageClasses, *storage.PersistentVolumeClaimSpec.StorageClassName) {
+			message := fmt.Sprintf("The storage class %s does not exist in the target cluster, which has the storage classes %s .", *storage.PersistentVolumeClaimSpec.StorageClassName, strings.Join(clusterSpec.StorageClasses, ", "))
+			addIncompatibility(getObjectServiceName(storage.Name, ir), Incompatibility{Kind: string(irtypes.PVCKind), Name: storage.Name, Message: message})
+		}
+	}
+	exposeKinds := []string{common.IngressKind, common.RouteKind, gatewayapi.HTTPRouteKind}
+	for serviceName, service := range ir.Services {
+		if !service.HasValidAnnotation(common.ExposeSelector) {
+			continue
+		}
+		exposed := false
+		for _, kind := range exposeKinds {
+			exposed = exposed || common.IsStringPresent(compatibilities[serviceName].Kinds, kind)
+		}
+		if !exposed {
+			addIncompatibility(serviceName, Incompatibility{Message: fmt.Sprintf("The service is exposed, but the target cluster supports none of the kinds %s .", strings.Join(exposeKinds, ", "))})
+		}
+	}
+	for serviceName, c := range compatibilities {
+		sort.Strings(c.Kinds)
+		compatibilities[serviceName] = c
+	}
+	return compatibilities
+}
+
+// getObjectServiceName returns the service an object is named after, preferring the longest matching service name
+func getObjectServiceName(name string, ir irtypes.IR) string {
+	if _, ok := ir.Services[name]; ok {
+		return name
+	}
+	serviceName := ""
+	for candidate := range ir.Services {
+		if strings.HasPrefix(name, candidate+"-") && len(candidate) > len(serviceName) {
+			serviceName = candidate
+		}
+	}
+	return serviceName
+}
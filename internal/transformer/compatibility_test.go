@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	getIR := func(clusterSpec collecttypes.ClusterMetadataSpec) irtypes.IR {
+		ir := irtypes.NewIR(plantypes.NewPlan())
+		ir.Name = "myproject"
+		ir.TargetClusterSpec = clusterSpec
+		svc := irtypes.NewServiceWithName("web")
+		svc.Annotations = map[string]string{common.ExposeSelector: common.AnnotationLabelValue}
+		svc.Containers = []core.Container{{Name: "web", Image: "web:latest"}}
+		svc.AddPortForwarding(irtypes.Port{Number: 8080}, irtypes.Port{Number: 8080})
+		ir.Services["web"] = svc
+		storageClass := "fast"
+		storage := irtypes.Storage{Name: "web-data", StorageType: irtypes.PVCKind}
+		storage.PersistentVolumeClaimSpec.StorageClassName = &storageClass
+		ir.AddStorage(storage)
+		return ir
+	}
+	getMessages := func(c ServiceCompatibility, substr string) []string {
+		messages := []string{}
+		for _, incompatibility := range c.Incompatibilities {
+			if strings.Contains(incompatibility.Message, substr) {
+				messages = append(messages, incompatibility.String())
+			}
+		}
+		return messages
+	}
+	kindVersions := map[string][]string{
+		common.DeploymentKind:   {"apps/v1"},
+		common.ServiceKind:      {"v1"},
+		"PersistentVolumeClaim": {"v1"},
+	}
+
+	t.Run("compatible target", func(t *testing.T) {
+		apiKindVersionMap := map[string][]string{common.IngressKind: {"networking.k8s.io/v1"}}
+		for kind, versions := range kindVersions {
+			apiKindVersionMap[kind] = versions
+		}
+		compatibilities := CheckCompatibility(getIR(collecttypes.ClusterMetadataSpec{StorageClasses: []string{"fast"}, APIKindVersionMap: apiKindVersionMap}))
+		web, ok := compatibilities["web"]
+		if !ok {
+			t.Fatalf("Expected the compatibility of the service web. Actual: %+v", compatibilities)
+		}
+		if !common.IsStringPresent(web.Kinds, common.DeploymentKind) || !common.IsStringPresent(web.Kinds, common.IngressKind) {
+			t.Fatalf("Expected a deployment and an ingress for the service web. Actual: %v", web.Kinds)
+		}
+		if messages := append(getMessages(web, "storage class"), getMessages(web, "exposed")...); len(messages) != 0 {
+			t.Fatalf("Expected no storage class or exposure changes. Actual: %v", messages)
+		}
+	})
+
+	t.Run("incompatible target", func(t *testing.T) {
+		compatibilities := CheckCompatibility(getIR(collecttypes.ClusterMetadataSpec{StorageClasses: []string{"standard"}, APIKindVersionMap: kindVersions}))
+		web := compatibilities["web"]
+		if web.IsCompatible() {
+			t.Fatalf("Expected the service web to need changes. Actual: %+v", web)
+		}
+		if messages := getMessages(web, "The storage class fast does not exist"); len(messages) != 1 {
+			t.Fatalf("Expected the missing storage class to be reported once. Actual: %v", web.Incompatibilities)
+		}
+		if messages := getMessages(web, "The service is exposed"); len(messages) != 1 {
+			t.Fatalf("Expected the exposure without ingress to be reported once. Actual: %v", web.Incompatibilities)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliance
+
+import (
+	"fmt"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/starlark/types"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+)
+
+// Violation is a generated resource that does not satisfy a constraint of the target cluster
+type Violation struct {
+	Kind        string
+	Name        string
+	Restriction string
+	Message     string
+	Fixed       bool
+}
+
+func (v Violation) String() string {
+	status := "Reported"
+	if v.Fixed {
+		status = "Fixed"
+	}
+	return fmt.Sprintf("[%s] %s %s violates the %s restriction of the target cluster: %s", status, v.Kind, v.Name, v.Restriction, v.Message)
+}
+
+// Check checks the resources against the constraints of the target cluster.
+// Violations are fixed in place unless the action for the restriction is to only report them.
+func Check(resources []types.K8sResourceT, constraints collecttypes.ClusterConstraints) []Violation {
+	violations := []Violation{}
+	for _, resource := range resources {
+		podSpec := getPodSpec(resource)
+		if podSpec == nil {
+			continue
+		}
+		c := checker{resource: resource, constraints: constraints}
+		c.checkPodSpec(podSpec)
+		violations = append(violations, c.violations...)
+	}
+	return violations
+}
+
+type checker struct {
+	resource    types.K8sResourceT
+	constraints collecttypes.ClusterConstraints
+	violations  []Violation
+}
+
+// violate records a violation and returns true if it should be fixed
+func (c *checker) violate(restriction string, format string, args ...interface{}) bool {
+	kind, _ := c.resource["kind"].(string)
+	name := ""
+	if metadata, ok := c.resource["metadata"].(types.MapT); ok {
+		name, _ = metadata["name"].(string)
+	}
+	fix := c.constraints.GetAction(restriction) == collecttypes.FixComplianceAction
+	c.violations = append(c.violations, Violation{Kind: kind, Name: name, Restriction: restriction, Message: fmt.Sprintf(format, args...), Fixed: fix})
+	return fix
+}
+
+func (c *checker) checkPodSpec(podSpec types.MapT) {
+	if c.constraints.DisallowHostNetwork {
+		if hostNetwork, _ := podSpec["hostNetwork"].(bool); hostNetwork {
+			if c.violate(collecttypes.HostNetworkRestriction, "host networking is enabled") {
+				delete(podSpec, "hostNetwork")
+			}
+		}
+	}
+	if c.constraints.DisallowHostPath {
+		for _, volume := range getMaps(podSpec["volumes"]) {
+			if _, ok := volume["hostPath"]; !ok {
+				continue
+			}
+			if c.violate(collecttypes.HostPathRestriction, "the volume %v is a hostPath volume", volume["name"]) {
+				delete(volume, "hostPath")
+				volume["emptyDir"] = types.MapT{}
+			}
+		}
+	}
+	for _, containersKey := range []string{"initContainers", "containers"} {
+		for _, container := range getMaps(podSpec[containersKey]) {
+			c.checkContainer(container)
+		}
+	}
+}
+
+func (c *checker) checkContainer(container types.MapT) {
+	name := container["name"]
+	if c.constraints.DisallowHostNetwork {
+		for _, port := range getMaps(container["ports"]) {
+			if _, ok := port["hostPort"]; !ok {
+				continue
+			}
+			if c.violate(collecttypes.HostNetworkRestriction, "the container %v uses the host port %v", name, port["hostPort"]) {
+				delete(port, "hostPort")
+			}
+		}
+	}
+	if securityContext, ok := container["securityContext"].(types.MapT); ok {
+		if privileged, _ := securityContext["privileged"].(bool); privileged && c.constraints.DisallowPrivileged {
+			if c.violate(collecttypes.PrivilegedRestriction, "the container %v is privileged", name) {
+				securityContext["privileged"] = false
+				securityContext["allowPrivilegeEscalation"] = false
+			}
+		}
+		if capabilities, ok := securityContext["capabilities"].(types.MapT); ok && len(c.constraints.AllowedCapabilities) > 0 {
+			if added, ok := capabilities["add"].([]interface{}); ok {
+				allowed := []interface{}{}
+				for _, capability := range added {
+					capabilityStr, _ := capability.(string)
+					if common.IsStringPresent(c.constraints.AllowedCapabilities, capabilityStr) {
+						allowed = append(allowed, capability)
+						continue
+					}
+					if !c.violate(collecttypes.CapabilitiesRestriction, "the container %v adds the capability %s", name, capabilityStr) {
+						allowed = append(allowed, capability)
+					}
+				}
+				capabilities["add"] = allowed
+			}
+		}
+	}
+	if c.constraints.RequireResourceRequests {
+		resources, ok := container["resources"].(types.MapT)
+		if !ok {
+			resources = types.MapT{}
+		}
+		requests, ok := resources["requests"].(types.MapT)
+		if !ok {
+			requests = types.MapT{}
+		}
+		changed := false
+		for _, request := range [][2]string{{"cpu", common.DefaultCPURequest}, {"memory", common.DefaultMemoryRequest}} {
+			if _, ok := requests[request[0]]; ok {
+				continue
+			}
+			if c.violate(collecttypes.ResourceRequestsRestriction, "the container %v does not have a %s request", name, request[0]) {
+				requests[request[0]] = request[1]
+				changed = true
+			}
+		}
+		if changed {
+			resources["requests"] = requests
+			container["resources"] = resources
+		}
+	}
+}
+
+// getPodSpec returns the pod spec of workload resources and nil for other resources
+func getPodSpec(resource types.K8sResourceT) types.MapT {
+	kind, _ := resource["kind"].(string)
+	spec, ok := resource["spec"].(types.MapT)
+	if !ok {
+		return nil
+	}
+	if kind == "Pod" {
+		return spec
+	}
+	if kind == "CronJob" {
+		jobTemplate, ok := spec["jobTemplate"].(types.MapT)
+		if !ok {
+			return nil
+		}
+		if spec, ok = jobTemplate["spec"].(types.MapT); !ok {
+			return nil
+		}
+	}
+	template, ok := spec["template"].(types.MapT)
+	if !ok {
+		return nil
+	}
+	podSpec, ok := template["spec"].(types.MapT)
+	if !ok {
+		return nil
+	}
+	if _, ok := podSpec["containers"]; !ok {
+		// Eg: the template of an OpenShift Template or a Tekton TriggerTemplate
+		return nil
+	}
+	return podSpec
+}
+
+func getMaps(value interface{}) []types.MapT {
+	maps := []types.MapT{}
+	list, ok := value.([]interface{})
+	if !ok {
+		return maps
+	}
+	for _, item := range list {
+		if m, ok := item.(types.MapT); ok {
+			maps = append(maps, m)
+		}
+	}
+	return maps
+}
@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliance_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/starlark/types"
+	"github.com/konveyor/move2kube/internal/transformer/compliance"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+)
+
+const deployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "web"},
+  "spec": {"template": {"spec": {
+    "hostNetwork": true,
+    "volumes": [{"name": "data", "hostPath": {"path": "/data"}}],
+    "containers": [{
+      "name": "web",
+      "ports": [{"containerPort": 8080, "hostPort": 8080}],
+      "securityContext": {"privileged": true, "capabilities": {"add": ["NET_ADMIN", "NET_BIND_SERVICE"]}},
+      "resources": {"requests": {"cpu": "1"}}
+    }]
+  }}}
+}`
+
+const fixedDeployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "web"},
+  "spec": {"template": {"spec": {
+    "volumes": [{"name": "data", "emptyDir": {}}],
+    "containers": [{
+      "name": "web",
+      "ports": [{"containerPort": 8080}],
+      "securityContext": {"privileged": false, "allowPrivilegeEscalation": false, "capabilities": {"add": ["NET_BIND_SERVICE"]}},
+      "resources": {"requests": {"cpu": "1", "memory": "512Mi"}}
+    }]
+  }}}
+}`
+
+func getResource(t *testing.T, data string) types.K8sResourceT {
+	resource := types.K8sResourceT{}
+	if err := json.Unmarshal([]byte(data), &resource); err != nil {
+		t.Fatalf("Failed to unmarshal the resource. Error: %q", err)
+	}
+	return resource
+}
+
+func TestCheck(t *testing.T) {
+	constraints := collecttypes.ClusterConstraints{
+		DisallowPrivileged:      true,
+		DisallowHostNetwork:     true,
+		DisallowHostPath:        true,
+		RequireResourceRequests: true,
+		AllowedCapabilities:     []string{"NET_BIND_SERVICE"},
+	}
+
+	t.Run("resources without a pod spec are ignored", func(t *testing.T) {
+		resource := getResource(t, `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "web"}, "spec": {"ports": [{"port": 80}]}}`)
+		want := getResource(t, `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "web"}, "spec": {"ports": [{"port": 80}]}}`)
+		if violations := compliance.Check([]types.K8sResourceT{resource}, constraints); len(violations) != 0 {
+			t.Fatalf("Expected no violations. Actual: %v", violations)
+		}
+		if !reflect.DeepEqual(resource, want) {
+			t.Fatalf("The resource should not have been modified. Actual: %v", resource)
+		}
+	})
+
+	t.Run("violations are fixed by default", func(t *testing.T) {
+		resource := getResource(t, deployment)
+		violations := compliance.Check([]types.K8sResourceT{resource}, constraints)
+		if len(violations) != 6 {
+			t.Fatalf("Expected 6 violations. Actual: %v", violations)
+		}
+		for _, violation := range violations {
+			if !violation.Fixed {
+				t.Fatalf("Expected the violation to be fixed. Actual: %v", violation)
+			}
+		}
+		if want := getResource(t, fixedDeployment); !reflect.DeepEqual(resource, want) {
+			t.Fatalf("Failed to fix the resource. Expected: %v Actual: %v", want, resource)
+		}
+	})
+
+	t.Run("violations are only reported when configured", func(t *testing.T) {
+		reportOnly := constraints
+		reportOnly.Actions = map[string]collecttypes.ComplianceAction{}
+		for _, restriction := range []string{collecttypes.PrivilegedRestriction, collecttypes.HostNetworkRestriction, collecttypes.HostPathRestriction, collecttypes.ResourceRequestsRestriction, collecttypes.CapabilitiesRestriction} {
+			reportOnly.Actions[restriction] = collecttypes.ReportComplianceAction
+		}
+		resource := getResource(t, deployment)
+		violations := compliance.Check([]types.K8sResourceT{resource}, reportOnly)
+		if len(violations) != 6 {
+			t.Fatalf("Expected 6 violations. Actual: %v", violations)
+		}
+		for _, violation := range violations {
+			if violation.Fixed {
+				t.Fatalf("Expected the violation to only be reported. Actual: %v", violation)
+			}
+		}
+		if want := getResource(t, deployment); !reflect.DeepEqual(resource, want) {
+			t.Fatalf("The resource should not have been modified. Actual: %v", resource)
+		}
+	})
+}
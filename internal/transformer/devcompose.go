@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	devComposeFileName   = "docker-compose.dev.yaml"
+	devComposeVersion    = "3.8"
+	devContainerDir      = ".devcontainer"
+	devContainerFileName = "devcontainer.json"
+	// devContainerWorkspace is where the source is mounted in the dev container, when the Dockerfile has no working directory
+	devContainerWorkspace = "/workspace"
+)
+
+// devDebugSetting enables the debugger of a runtime, which is detected using the base image of the Dockerfile
+type devDebugSetting struct {
+	images []string
+	port   int32
+	env    map[string]string
+	// preservedDirs are the directories of the image, relative to the working directory, which are not hidden by the source mount
+	preservedDirs []string
+}
+
+// devDebugSettings are the debug settings of the runtimes which can be debugged by only setting an env var
+var devDebugSettings = []devDebugSetting{
+	{images: []string{"node"}, port: 9229, env: map[string]string{"NODE_OPTIONS": "--inspect=0.0.0.0:9229"}, preservedDirs: []string{"node_modules"}},
+	{images: []string{"openjdk", "jdk", "jre", "temurin", "maven", "gradle"}, port: 5005, env: map[string]string{"JAVA_TOOL_OPTIONS": "-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=*:5005"}},
+}
+
+// devComposeConfig is a slim docker compose file, which runs the services locally during development
+type devComposeConfig struct {
+	Version  string                       `yaml:"version"`
+	Services map[string]devComposeService `yaml:"services"`
+}
+
+type devComposeService struct {
+	Image       string            `yaml:"image,omitempty"`
+	Build       *devComposeBuild  `yaml:"build,omitempty"`
+	Ports       []string          `yaml:"ports,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+}
+
+type devComposeBuild struct {
+	Context    string `yaml:"context"`
+	Dockerfile string `yaml:"dockerfile"`
+}
+
+// devContainerConfig is a devcontainer.json opening the source in a service of the compose file
+type devContainerConfig struct {
+	Name              string   `json:"name"`
+	DockerComposeFile []string `json:"dockerComposeFile"`
+	Service           string   `json:"service"`
+	WorkspaceFolder   string   `json:"workspaceFolder"`
+	ForwardPorts      []int32  `json:"forwardPorts,omitempty"`
+	ShutdownAction    string   `json:"shutdownAction"`
+}
+
+// getDockerfileBaseImageAndWorkDir returns the base image and the working directory of the last stage of the Dockerfile
+func getDockerfileBaseImageAndWorkDir(container irtypes.Container, relDockerfilePath string) (string, string) {
+	content, ok := container.NewFiles[relDockerfilePath]
+	if !ok {
+		contentBytes, err := ioutil.ReadFile(container.RepoInfo.TargetPath)
+		if err != nil {
+			log.Debugf("Failed to read the Dockerfile at path %s . Error: %q", container.RepoInfo.TargetPath, err)
+			return "", ""
+		}
+		content = string(contentBytes)
+	}
+	return parseDockerfileBaseImageAndWorkDir(content)
+}
+
+func parseDockerfileBaseImageAndWorkDir(content string) (string, string) {
+	type stage struct{ baseImage, workDir string }
+	stages := map[string]stage{}
+	current, currentName := stage{}, ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "FROM":
+			args := []string{}
+			for _, field := range fields[1:] {
+				if !strings.HasPrefix(field, "--") {
+					args = append(args, field)
+				}
+			}
+			if len(args) == 0 {
+				continue
+			}
+			// A stage based on a previous stage inherits its base image and working directory
+			if previous, ok := stages[strings.ToLower(args[0])]; ok {
+				current = previous
+			} else {
+				current = stage{baseImage: args[0]}
+			}
+			currentName = ""
+			if len(args) == 3 && strings.EqualFold(args[1], "AS") {
+				currentName = strings.ToLower(args[2])
+			}
+		case "WORKDIR":
+			workDir := strings.Trim(fields[1], `"`)
+			if !path.IsAbs(workDir) {
+				workDir = path.Join("/", current.workDir, workDir)
+			}
+			current.workDir = workDir
+		}
+		if currentName != "" {
+			stages[currentName] = current
+		}
+	}
+	return current.baseImage, current.workDir
+}
+
+// getDevDebugSetting returns the debug setting of the runtime of the base image, if it is known
+func getDevDebugSetting(baseImage string) *devDebugSetting {
+	if baseImage == "" {
+		return nil
+	}
+	name := path.Base(getImageRepoName(strings.ToLower(baseImage)))
+	for i, setting := range devDebugSettings {
+		for _, image := range setting.images {
+			if strings.Contains(name, image) {
+				return &devDebugSettings[i]
+			}
+		}
+	}
+	return nil
+}
+
+// getDevComposeConfig returns the compose file running the services locally, along with the dev container opening the
+// source of the first service built using a Dockerfile. The source of the services built using Dockerfiles is mounted
+// on the working directory of their images for hot reload, and the debug ports of their runtimes are published.
+func getDevComposeConfig(ir irtypes.IR, artifacts []devArtifact) (devComposeConfig, devContainerConfig) {
+	compose := devComposeConfig{Version: devComposeVersion, Services: map[string]devComposeService{}}
+	devContainer := devContainerConfig{Name: ir.Name, DockerComposeFile: []string{"../" + devComposeFileName}, ShutdownAction: "stopCompose"}
+	usedPorts := map[int32]bool{}
+	publish := func(port int32) string {
+		localPort := port
+		for usedPorts[localPort] {
+			localPort++
+		}
+		usedPorts[localPort] = true
+		devContainer.ForwardPorts = append(devContainer.ForwardPorts, localPort)
+		return fmt.Sprintf("%d:%d", localPort, port)
+	}
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		if len(service.Containers) == 0 || !service.IsLongRunning() {
+			continue
+		}
+		container := service.Containers[0]
+		composeService := devComposeService{Image: container.Image, Environment: map[string]string{}}
+		unsetEnv := []string{}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				unsetEnv = append(unsetEnv, env.Name)
+				continue
+			}
+			composeService.Environment[env.Name] = env.Value
+		}
+		for _, port := range container.Ports {
+			composeService.Ports = append(composeService.Ports, publish(port.ContainerPort))
+		}
+		for _, artifact := range artifacts {
+			if !common.IsStringPresent(artifact.imageNames, getImageRepoName(container.Image)) {
+				continue
+			}
+			composeService.Build = &devComposeBuild{Context: "./" + artifact.context, Dockerfile: artifact.dockerfile}
+			workDir := artifact.workDir
+			if workDir == "" && devContainer.Service == "" {
+				workDir = devContainerWorkspace
+			}
+			if workDir != "" {
+				composeService.Volumes = append(composeService.Volumes, "./"+artifact.context+":"+workDir)
+			}
+			if debug := getDevDebugSetting(artifact.baseImage); debug != nil {
+				composeService.Ports = append(composeService.Ports, publish(debug.port))
+				for name, value := range debug.env {
+					composeService.Environment[name] = value
+				}
+				if artifact.workDir != "" {
+					for _, dir := range debug.preservedDirs {
+						composeService.Volumes = append(composeService.Volumes, path.Join(artifact.workDir, dir))
+					}
+				}
+			}
+			if devContainer.Service == "" {
+				devContainer.Service = serviceName
+				devContainer.WorkspaceFolder = workDir
+			}
+			break
+		}
+		if len(unsetEnv) > 0 {
+			log.Warnf("The env vars %s of the service %s are read from config maps or secrets. Set them in %s to run the service locally.", strings.Join(unsetEnv, ", "), serviceName, devComposeFileName)
+		}
+		if len(composeService.Environment) == 0 {
+			composeService.Environment = nil
+		}
+		compose.Services[serviceName] = composeService
+	}
+	return compose, devContainer
+}
+
+// writeDevContainerConfig writes the devcontainer.json
+func writeDevContainerConfig(configPath string, config devContainerConfig) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	configBytes, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configPath, append(configBytes, '\n'), common.DefaultFilePermission)
+}
@@ -0,0 +1,358 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	"github.com/konveyor/move2kube/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	skaffoldAPIVersion = "skaffold/v2beta10"
+	skaffoldFileName   = "skaffold.yaml"
+	tiltFileName       = "Tiltfile"
+	devSpaceVersion    = "v1beta10"
+	devSpaceFileName   = "devspace.yaml"
+	// devServiceSelector is the label selecting the pods of a service, which is set by the apiresources
+	devServiceSelector = types.GroupName + "/service"
+)
+
+// DevWorkflowTransformer generates the configs of the inner loop developer workflow, which rebuild the images using the
+// Dockerfiles and redeploy the yamls on each change, for the tools chosen by the user, like Skaffold, Tilt and DevSpace.
+type DevWorkflowTransformer struct {
+	name         string
+	tools        []irtypes.DevToolType
+	artifacts    []devArtifact
+	compose      devComposeConfig
+	devContainer devContainerConfig
+}
+
+// devArtifact is an image built using a Dockerfile, along with the ports of the services running it
+type devArtifact struct {
+	name         string
+	image        string // Image name without the tag, as it is in the yamls
+	context      string // Path of the build context, relative to the output directory
+	dockerfile   string // Path of the Dockerfile, relative to the build context
+	portForwards []devPortForward
+	imageNames   []string // Names of the image, without the tags, used to find the services running it
+	baseImage    string   // Base image of the last stage of the Dockerfile
+	workDir      string   // Working directory of the last stage of the Dockerfile
+}
+
+// devPortForward is a port of a service forwarded to localhost during development
+type devPortForward struct {
+	service     string
+	servicePort int32
+	podPort     int32
+}
+
+// skaffoldConfig is a skaffold.yaml
+type skaffoldConfig struct {
+	APIVersion  string                `yaml:"apiVersion"`
+	Kind        string                `yaml:"kind"`
+	Metadata    skaffoldMetadata      `yaml:"metadata"`
+	Build       skaffoldBuild         `yaml:"build"`
+	Deploy      skaffoldDeploy        `yaml:"deploy"`
+	PortForward []skaffoldPortForward `yaml:"portForward,omitempty"`
+}
+
+type skaffoldMetadata struct {
+	Name string `yaml:"name"`
+}
+
+type skaffoldBuild struct {
+	Artifacts []skaffoldArtifact `yaml:"artifacts"`
+}
+
+type skaffoldArtifact struct {
+	Image   string         `yaml:"image"`
+	Context string         `yaml:"context"`
+	Docker  skaffoldDocker `yaml:"docker"`
+}
+
+type skaffoldDocker struct {
+	Dockerfile string `yaml:"dockerfile"`
+}
+
+type skaffoldDeploy struct {
+	Kubectl skaffoldKubectl `yaml:"kubectl"`
+}
+
+type skaffoldKubectl struct {
+	Manifests []string `yaml:"manifests"`
+}
+
+type skaffoldPortForward struct {
+	ResourceType string `yaml:"resourceType"`
+	ResourceName string `yaml:"resourceName"`
+	Port         int32  `yaml:"port"`
+}
+
+// devSpaceConfig is a devspace.yaml
+type devSpaceConfig struct {
+	Version     string                   `yaml:"version"`
+	Images      map[string]devSpaceImage `yaml:"images"`
+	Deployments []devSpaceDeployment     `yaml:"deployments"`
+	Dev         devSpaceDev              `yaml:"dev,omitempty"`
+}
+
+type devSpaceImage struct {
+	Image      string `yaml:"image"`
+	Dockerfile string `yaml:"dockerfile"`
+	Context    string `yaml:"context"`
+}
+
+type devSpaceDeployment struct {
+	Name    string          `yaml:"name"`
+	Kubectl devSpaceKubectl `yaml:"kubectl"`
+}
+
+type devSpaceKubectl struct {
+	Manifests []string `yaml:"manifests"`
+}
+
+type devSpaceDev struct {
+	Ports []devSpacePort `yaml:"ports,omitempty"`
+}
+
+type devSpacePort struct {
+	LabelSelector map[string]string     `yaml:"labelSelector"`
+	Forward       []devSpacePortForward `yaml:"forward"`
+}
+
+type devSpacePortForward struct {
+	Port int32 `yaml:"port"`
+}
+
+// Transform translates intermediate representation to destination objects
+func (dt *DevWorkflowTransformer) Transform(ir irtypes.IR) error {
+	dt.name = ir.Name
+	dt.tools = ir.DevTools
+	dt.artifacts = []devArtifact{}
+	if len(dt.tools) == 0 {
+		return nil
+	}
+	for _, container := range ir.Containers {
+		if !container.New || len(container.ImageNames) == 0 {
+			continue
+		}
+		if container.ContainerBuildType != plantypes.DockerFileContainerBuildTypeValue && container.ContainerBuildType != plantypes.ReuseDockerFileContainerBuildTypeValue {
+			log.Debugf("Only the images built using Dockerfiles are supported in the developer workflow. Skipping the image %s", container.ImageNames[0])
+			continue
+		}
+		relDockerfilePath, err := filepath.Rel(ir.RootDir, container.RepoInfo.TargetPath)
+		if container.RepoInfo.TargetPath == "" || err != nil || strings.HasPrefix(relDockerfilePath, "..") {
+			log.Debugf("The Dockerfile %s of the image %s is not in the source directory. Skipping it in the developer workflow.", container.RepoInfo.TargetPath, container.ImageNames[0])
+			continue
+		}
+		imageNames := []string{}
+		for _, imageName := range container.ImageNames {
+			imageNames = append(imageNames, getImageRepoName(imageName), getImageRepoName(ir.GetFullImageName(imageName)))
+		}
+		artifact := devArtifact{
+			name:         common.NormalizeForServiceName(common.MakeFileNameCompliant(getImageRepoName(container.ImageNames[0]))),
+			image:        getImageRepoName(ir.GetFullImageName(container.ImageNames[0])),
+			context:      filepath.ToSlash(filepath.Join(common.SourceDir, filepath.Dir(relDockerfilePath))),
+			dockerfile:   filepath.Base(relDockerfilePath),
+			portForwards: getDevPortForwards(ir, imageNames),
+			imageNames:   imageNames,
+		}
+		artifact.baseImage, artifact.workDir = getDockerfileBaseImageAndWorkDir(container, relDockerfilePath)
+		dt.artifacts = append(dt.artifacts, artifact)
+	}
+	sort.Slice(dt.artifacts, func(i, j int) bool { return dt.artifacts[i].name < dt.artifacts[j].name })
+	if len(dt.artifacts) > 0 && (dt.hasTool(irtypes.ComposeDevTool) || dt.hasTool(irtypes.DevContainerDevTool)) {
+		dt.compose, dt.devContainer = getDevComposeConfig(ir, dt.artifacts)
+	}
+	if len(dt.artifacts) > 0 {
+		log.Infof("Generating the configs of the developer workflow")
+	}
+	return nil
+}
+
+// WriteObjects writes Transformed objects to filesystem. Also does some final transformations on the generated yamls.
+func (dt *DevWorkflowTransformer) WriteObjects(outputPath string, transformPaths []string) error {
+	if len(dt.artifacts) == 0 {
+		return nil
+	}
+	if dt.hasTool(irtypes.ComposeDevTool) || dt.hasTool(irtypes.DevContainerDevTool) {
+		composePath := filepath.Join(outputPath, devComposeFileName)
+		if err := common.WriteYaml(composePath, dt.compose); err != nil {
+			log.Errorf("Failed to write the compose file for development to the file at path %s Error: %q", composePath, err)
+			return err
+		}
+	}
+	for _, tool := range dt.tools {
+		var err error
+		var fileName string
+		switch tool {
+		case irtypes.SkaffoldDevTool:
+			fileName = skaffoldFileName
+			err = common.WriteYaml(filepath.Join(outputPath, fileName), dt.getSkaffoldConfig())
+		case irtypes.TiltDevTool:
+			fileName = tiltFileName
+			err = ioutil.WriteFile(filepath.Join(outputPath, fileName), []byte(dt.getTiltfile()), common.DefaultFilePermission)
+		case irtypes.DevSpaceDevTool:
+			fileName = devSpaceFileName
+			err = common.WriteYaml(filepath.Join(outputPath, fileName), dt.getDevSpaceConfig())
+		case irtypes.ComposeDevTool:
+			fileName = devComposeFileName
+		case irtypes.DevContainerDevTool:
+			if dt.devContainer.Service == "" {
+				log.Warnf("None of the services built using Dockerfiles runs as a service. Skipping the dev container.")
+				continue
+			}
+			fileName = filepath.Join(devContainerDir, devContainerFileName)
+			err = writeDevContainerConfig(filepath.Join(outputPath, fileName), dt.devContainer)
+		default:
+			log.Warnf("Unknown developer workflow tool %s . Skipping it.", tool)
+			continue
+		}
+		if err != nil {
+			log.Errorf("Failed to write the %s config to the file at path %s Error: %q", tool, filepath.Join(outputPath, fileName), err)
+			return err
+		}
+		log.Infof("Run %s in the directory %s to start the developer workflow.", getDevToolCommand(tool), outputPath)
+	}
+	return nil
+}
+
+// hasTool checks if the config of the tool has to be generated
+func (dt *DevWorkflowTransformer) hasTool(tool irtypes.DevToolType) bool {
+	for _, t := range dt.tools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// getDevToolCommand returns the command starting the developer workflow of the tool
+func getDevToolCommand(tool irtypes.DevToolType) string {
+	switch tool {
+	case irtypes.TiltDevTool:
+		return "tilt up"
+	case irtypes.DevSpaceDevTool:
+		return "devspace dev"
+	case irtypes.ComposeDevTool:
+		return "docker-compose -f " + devComposeFileName + " up"
+	case irtypes.DevContainerDevTool:
+		return "devcontainer up --workspace-folder ."
+	}
+	return "skaffold dev"
+}
+
+// getDevPortForwards returns the first port of each service running one of the images
+func getDevPortForwards(ir irtypes.IR, imageNames []string) []devPortForward {
+	serviceNames := []string{}
+	for serviceName := range ir.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	portForwards := []devPortForward{}
+	for _, serviceName := range serviceNames {
+		service := ir.Services[serviceName]
+		if len(service.ServiceToPodPortForwardings) == 0 {
+			continue
+		}
+		for _, container := range service.Containers {
+			if common.IsStringPresent(imageNames, getImageRepoName(container.Image)) {
+				forwarding := service.ServiceToPodPortForwardings[0]
+				portForwards = append(portForwards, devPortForward{service: serviceName, servicePort: forwarding.ServicePort.Number, podPort: forwarding.PodPort.Number})
+				break
+			}
+		}
+	}
+	return portForwards
+}
+
+// getManifestsPath returns the path of the yamls, relative to the output directory
+func getManifestsPath() string {
+	return filepath.ToSlash(filepath.Join(common.DeployDir, "yamls"))
+}
+
+func (dt *DevWorkflowTransformer) getSkaffoldConfig() skaffoldConfig {
+	config := skaffoldConfig{
+		APIVersion: skaffoldAPIVersion,
+		Kind:       "Config",
+		Metadata:   skaffoldMetadata{Name: dt.name},
+		Deploy:     skaffoldDeploy{Kubectl: skaffoldKubectl{Manifests: []string{getManifestsPath() + "/*.yaml"}}},
+	}
+	for _, artifact := range dt.artifacts {
+		config.Build.Artifacts = append(config.Build.Artifacts, skaffoldArtifact{Image: artifact.image, Context: artifact.context, Docker: skaffoldDocker{Dockerfile: artifact.dockerfile}})
+		for _, portForward := range artifact.portForwards {
+			config.PortForward = append(config.PortForward, skaffoldPortForward{ResourceType: "service", ResourceName: portForward.service, Port: portForward.servicePort})
+		}
+	}
+	return config
+}
+
+// getTiltfile returns the Tiltfile. The local ports are the ports of the pods, unless they are already used by another service.
+func (dt *DevWorkflowTransformer) getTiltfile() string {
+	lines := []string{
+		"# Run `tilt up` in this directory to rebuild and redeploy the services on each change",
+		fmt.Sprintf("k8s_yaml(listdir('%s'))", getManifestsPath()),
+		"",
+	}
+	for _, artifact := range dt.artifacts {
+		lines = append(lines, fmt.Sprintf("docker_build('%s', '%s', dockerfile='%s/%s')", artifact.image, artifact.context, artifact.context, artifact.dockerfile))
+	}
+	usedPorts := map[int32]bool{}
+	resources := []string{}
+	for _, artifact := range dt.artifacts {
+		for _, portForward := range artifact.portForwards {
+			localPort := portForward.podPort
+			for usedPorts[localPort] {
+				localPort++
+			}
+			usedPorts[localPort] = true
+			resources = append(resources, fmt.Sprintf("k8s_resource('%s', port_forwards=['%d:%d'])", portForward.service, localPort, portForward.podPort))
+		}
+	}
+	if len(resources) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, resources...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (dt *DevWorkflowTransformer) getDevSpaceConfig() devSpaceConfig {
+	config := devSpaceConfig{
+		Version:     devSpaceVersion,
+		Images:      map[string]devSpaceImage{},
+		Deployments: []devSpaceDeployment{{Name: dt.name, Kubectl: devSpaceKubectl{Manifests: []string{getManifestsPath() + "/"}}}},
+	}
+	for _, artifact := range dt.artifacts {
+		config.Images[artifact.name] = devSpaceImage{Image: artifact.image, Dockerfile: artifact.context + "/" + artifact.dockerfile, Context: artifact.context}
+		for _, portForward := range artifact.portForwards {
+			config.Dev.Ports = append(config.Dev.Ports, devSpacePort{
+				LabelSelector: map[string]string{devServiceSelector: portForward.service},
+				Forward:       []devSpacePortForward{{Port: portForward.podPort}},
+			})
+		}
+	}
+	return config
+}
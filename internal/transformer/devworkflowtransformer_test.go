@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestDevWorkflowConfigs(t *testing.T) {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	ir.Name = "tickets"
+	ir.RootDir = "/src"
+	ir.Kubernetes.RegistryURL = "quay.io"
+	ir.Kubernetes.RegistryNamespace = "myproject"
+	ir.DevTools = []irtypes.DevToolType{irtypes.SkaffoldDevTool, irtypes.TiltDevTool, irtypes.DevSpaceDevTool}
+	for _, name := range []string{"web", "api"} {
+		ir.Containers = append(ir.Containers, irtypes.Container{
+			ContainerBuildType: plantypes.DockerFileContainerBuildTypeValue,
+			ImageNames:         []string{name + ":latest"},
+			New:                true,
+			RepoInfo:           plantypes.RepoInfo{GitRepoDir: "/src", TargetPath: "/src/" + name + "/Dockerfile"},
+		})
+		service := irtypes.NewServiceWithName(name)
+		service.Containers = []core.Container{{Name: name, Image: "quay.io/myproject/" + name + ":latest"}}
+		service.AddPortForwarding(irtypes.Port{Number: 80}, irtypes.Port{Number: 8080})
+		ir.Services[name] = service
+	}
+	ir.Containers = append(ir.Containers, irtypes.Container{ContainerBuildType: plantypes.CNBContainerBuildTypeValue, ImageNames: []string{"worker:latest"}, New: true})
+
+	dt := new(DevWorkflowTransformer)
+	if err := dt.Transform(ir); err != nil {
+		t.Fatalf("Failed to transform the IR. Error: %q", err)
+	}
+	if len(dt.artifacts) != 2 || dt.artifacts[0].name != "api" || dt.artifacts[0].image != "quay.io/myproject/api" || dt.artifacts[0].context != "source/api" || dt.artifacts[0].dockerfile != "Dockerfile" {
+		t.Fatalf("Expected the artifacts of the images built using Dockerfiles. Actual: %+v", dt.artifacts)
+	}
+	skaffold := dt.getSkaffoldConfig()
+	if len(skaffold.Build.Artifacts) != 2 || skaffold.Deploy.Kubectl.Manifests[0] != "deploy/yamls/*.yaml" || len(skaffold.PortForward) != 2 || skaffold.PortForward[1].ResourceName != "web" || skaffold.PortForward[1].Port != 80 {
+		t.Fatalf("Failed to create the skaffold config. Actual: %+v", skaffold)
+	}
+	tiltfile := dt.getTiltfile()
+	for _, line := range []string{"docker_build('quay.io/myproject/web', 'source/web', dockerfile='source/web/Dockerfile')", "k8s_resource('api', port_forwards=['8080:8080'])", "k8s_resource('web', port_forwards=['8081:8080'])"} {
+		if !strings.Contains(tiltfile, line) {
+			t.Fatalf("Expected the Tiltfile to contain %s . Actual: %s", line, tiltfile)
+		}
+	}
+
+	outputPath := t.TempDir()
+	if err := dt.WriteObjects(outputPath, nil); err != nil {
+		t.Fatalf("Failed to write the configs. Error: %q", err)
+	}
+	for _, fileName := range []string{skaffoldFileName, tiltFileName, devSpaceFileName} {
+		if _, err := ioutil.ReadFile(filepath.Join(outputPath, fileName)); err != nil {
+			t.Fatalf("Expected the file %s to be written. Error: %q", fileName, err)
+		}
+	}
+	devSpace, err := ioutil.ReadFile(filepath.Join(outputPath, devSpaceFileName))
+	if err != nil || !strings.Contains(string(devSpace), "move2kube.konveyor.io/service: web") {
+		t.Fatalf("Expected the DevSpace config to forward the ports of the pods of the service web. Actual: %s", string(devSpace))
+	}
+}
+
+func TestDevComposeConfig(t *testing.T) {
+	dockerfile := "FROM node:14 AS build\nWORKDIR /app\nCOPY . .\nRUN npm ci\n\nFROM build\nWORKDIR src\nCMD [\"npm\", \"start\"]\n"
+	if baseImage, workDir := parseDockerfileBaseImageAndWorkDir(dockerfile); baseImage != "node:14" || workDir != "/app/src" {
+		t.Fatalf("Failed to parse the base image and the working directory of the Dockerfile. Actual: %s %s", baseImage, workDir)
+	}
+
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	ir.Name = "tickets"
+	web := irtypes.NewServiceWithName("web")
+	web.Containers = []core.Container{{
+		Name:  "web",
+		Image: "web:latest",
+		Ports: []core.ContainerPort{{ContainerPort: 8080}},
+		Env:   []core.EnvVar{{Name: "DB_HOST", Value: "db"}, {Name: "DB_PASSWORD", ValueFrom: &core.EnvVarSource{}}},
+	}}
+	ir.Services[web.Name] = web
+	db := irtypes.NewServiceWithName("db")
+	db.Containers = []core.Container{{Name: "db", Image: "postgres:13", Ports: []core.ContainerPort{{ContainerPort: 8080}}}}
+	ir.Services[db.Name] = db
+	artifacts := []devArtifact{{name: "web", context: "source/web", dockerfile: "Dockerfile", imageNames: []string{"web"}, baseImage: "node:14", workDir: "/app"}}
+
+	compose, devContainer := getDevComposeConfig(ir, artifacts)
+	if service := compose.Services["db"]; service.Build != nil || len(service.Ports) != 1 || service.Ports[0] != "8080:8080" {
+		t.Fatalf("Expected the db service to reuse its image. Actual: %+v", service)
+	}
+	service := compose.Services["web"]
+	if service.Build == nil || service.Build.Context != "./source/web" || service.Environment["DB_HOST"] != "db" || service.Environment["NODE_OPTIONS"] == "" {
+		t.Fatalf("Expected the web service to be built from the source with the debugger enabled. Actual: %+v", service)
+	}
+	if _, ok := service.Environment["DB_PASSWORD"]; ok {
+		t.Fatalf("Expected the env vars read from secrets not to be set. Actual: %+v", service.Environment)
+	}
+	wantPorts := []string{"8081:8080", "9229:9229"}
+	wantVolumes := []string{"./source/web:/app", "/app/node_modules"}
+	if !reflect.DeepEqual(service.Ports, wantPorts) || !reflect.DeepEqual(service.Volumes, wantVolumes) {
+		t.Fatalf("Failed to set the ports and the volumes of the web service. Expected: %v %v Actual: %v %v", wantPorts, wantVolumes, service.Ports, service.Volumes)
+	}
+	if devContainer.Service != "web" || devContainer.WorkspaceFolder != "/app" || !reflect.DeepEqual(devContainer.ForwardPorts, []int32{8080, 8081, 9229}) {
+		t.Fatalf("Failed to create the dev container config. Actual: %+v", devContainer)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	githubActionsDefaultBranch     = "main"
+	githubActionsWorkflowSuffix    = "-build-push.yaml"
+	githubActionsRegistryUserVar   = "${{ secrets.IMAGE_REGISTRY_USERNAME }}"
+	githubActionsRegistryPassVar   = "${{ secrets.IMAGE_REGISTRY_PASSWORD }}"
+	githubActionsRegistryURLEnvVar = "IMAGE_REGISTRY_URL"
+)
+
+// GitHubActionsTransformer generates GitHub Actions workflows that build and push the new images.
+// The layers of each image are cached in the GitHub Actions cache, in a scope per image, so that only the first build is cold.
+type GitHubActionsTransformer struct {
+	workflows map[string]githubWorkflow // file name: workflow
+}
+
+// githubWorkflow is a GitHub Actions workflow
+type githubWorkflow struct {
+	Name string               `yaml:"name"`
+	On   githubWorkflowOn     `yaml:"on"`
+	Env  map[string]string    `yaml:"env,omitempty"`
+	Jobs map[string]githubJob `yaml:"jobs"`
+}
+
+// githubWorkflowOn contains the events that trigger the workflow
+type githubWorkflowOn struct {
+	Push githubWorkflowPush `yaml:"push"`
+}
+
+// githubWorkflowPush filters the pushes that trigger the workflow
+type githubWorkflowPush struct {
+	Branches []string `yaml:"branches"`
+}
+
+// githubJob is a job of a GitHub Actions workflow
+type githubJob struct {
+	RunsOn string       `yaml:"runs-on"`
+	Steps  []githubStep `yaml:"steps"`
+}
+
+// githubStep is a step of a GitHub Actions job
+type githubStep struct {
+	Name string            `yaml:"name,omitempty"`
+	Uses string            `yaml:"uses"`
+	With map[string]string `yaml:"with,omitempty"`
+}
+
+// Transform translates intermediate representation to destination objects
+func (ghSet *GitHubActionsTransformer) Transform(ir irtypes.IR) error {
+	ghSet.workflows = map[string]githubWorkflow{}
+	registryURL := ir.Kubernetes.RegistryURL
+	if registryURL == "" {
+		registryURL = common.DefaultRegistryURL
+	}
+	imageRegistryURL := registryURL
+	if ir.Kubernetes.RegistryNamespace != "" {
+		imageRegistryURL += "/" + ir.Kubernetes.RegistryNamespace
+	}
+	for _, container := range ir.Containers {
+		if !container.New || len(container.ImageNames) == 0 {
+			continue
+		}
+		if container.ContainerBuildType != plantypes.DockerFileContainerBuildTypeValue && container.ContainerBuildType != plantypes.ReuseDockerFileContainerBuildTypeValue {
+			log.Debugf("Only the images built using Dockerfiles are supported in the GitHub Actions workflows. Skipping the image %s", container.ImageNames[0])
+			continue
+		}
+		if container.RepoInfo.GitRepoDir == "" {
+			log.Debugf("The image %s is not in a git repo. Skipping it in the GitHub Actions workflows.", container.ImageNames[0])
+			continue
+		}
+		relDockerfilePath, err := filepath.Rel(container.RepoInfo.GitRepoDir, container.RepoInfo.TargetPath)
+		if err != nil {
+			log.Debugf("Failed to make the path %s relative to the path %s Error: %q", container.RepoInfo.TargetPath, container.RepoInfo.GitRepoDir, err)
+			continue
+		}
+		// A workflow only runs on the repo it is in, so there is a workflow per git repo
+		fileName := common.MakeFileNameCompliant(filepath.Base(container.RepoInfo.GitRepoDir)) + githubActionsWorkflowSuffix
+		workflow, ok := ghSet.workflows[fileName]
+		if !ok {
+			branch := container.RepoInfo.GitRepoBranch
+			if branch == "" {
+				branch = githubActionsDefaultBranch
+			}
+			workflow = githubWorkflow{
+				Name: strings.TrimSuffix(fileName, filepath.Ext(fileName)),
+				On:   githubWorkflowOn{Push: githubWorkflowPush{Branches: []string{branch}}},
+				Env:  map[string]string{githubActionsRegistryURLEnvVar: imageRegistryURL},
+				Jobs: map[string]githubJob{},
+			}
+		}
+		imageName := container.ImageNames[0]
+		scope := getImageRepoName(imageName)
+		jobName := common.NormalizeForServiceName(common.MakeFileNameCompliant(scope))
+		workflow.Jobs[jobName] = githubJob{
+			RunsOn: "ubuntu-latest",
+			Steps: []githubStep{
+				{Uses: "actions/checkout@v2"},
+				{Uses: "docker/setup-buildx-action@v1"},
+				{Uses: "docker/login-action@v1", With: map[string]string{
+					"registry": registryURL,
+					"username": githubActionsRegistryUserVar,
+					"password": githubActionsRegistryPassVar,
+				}},
+				{Name: "Build and push " + imageName, Uses: "docker/build-push-action@v2", With: map[string]string{
+					"context":    filepath.ToSlash(filepath.Dir(relDockerfilePath)),
+					"file":       filepath.ToSlash(relDockerfilePath),
+					"push":       "true",
+					"tags":       "${{ env." + githubActionsRegistryURLEnvVar + " }}/" + imageName,
+					"cache-from": "type=gha,scope=" + scope,
+					"cache-to":   "type=gha,mode=max,scope=" + scope,
+				}},
+			},
+		}
+		ghSet.workflows[fileName] = workflow
+	}
+	if len(ghSet.workflows) > 0 {
+		log.Infof("Generating GitHub Actions workflows for CI")
+	}
+	return nil
+}
+
+// WriteObjects writes Transformed objects to filesystem. Also does some final transformations on the generated yamls.
+func (ghSet *GitHubActionsTransformer) WriteObjects(outputPath string, transformPaths []string) error {
+	if len(ghSet.workflows) == 0 {
+		return nil
+	}
+	// deploy/cicd/github-actions/
+	workflowsPath := filepath.Join(outputPath, common.DeployDir, "cicd", "github-actions")
+	if err := os.MkdirAll(workflowsPath, common.DefaultDirectoryPermission); err != nil {
+		log.Errorf("Unable to create directory at path %s Error: %q", workflowsPath, err)
+		return err
+	}
+	fileNames := []string{}
+	for fileName := range ghSet.workflows {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		filePath := filepath.Join(workflowsPath, fileName)
+		if err := common.WriteYaml(filePath, ghSet.workflows[fileName]); err != nil {
+			log.Errorf("Failed to write the GitHub Actions workflow to the file at path %s Error: %q", filePath, err)
+			return err
+		}
+	}
+	log.Infof("Copy the workflows in %s to the .github/workflows directory of the git repos and add the IMAGE_REGISTRY_USERNAME and IMAGE_REGISTRY_PASSWORD secrets to the repos.", workflowsPath)
+	return nil
+}
+
+// getImageRepoName returns the image name without the tag
+func getImageRepoName(imageName string) string {
+	ref := common.ParseImageReference(imageName)
+	ref.Tag = ""
+	ref.Digest = ""
+	return ref.String()
+}
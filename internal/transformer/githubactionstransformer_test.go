@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"testing"
+
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestGitHubActionsCachePerImage(t *testing.T) {
+	ir := irtypes.NewIR(plantypes.NewPlan())
+	ir.Kubernetes.RegistryURL = "quay.io"
+	ir.Kubernetes.RegistryNamespace = "myproject"
+	for _, name := range []string{"web", "api"} {
+		ir.Containers = append(ir.Containers, irtypes.Container{
+			ContainerBuildType: plantypes.DockerFileContainerBuildTypeValue,
+			ImageNames:         []string{name + ":latest"},
+			New:                true,
+			RepoInfo:           plantypes.RepoInfo{GitRepoDir: "/src/myrepo", TargetPath: "/src/myrepo/" + name + "/Dockerfile"},
+		})
+	}
+	ghSet := new(GitHubActionsTransformer)
+	if err := ghSet.Transform(ir); err != nil {
+		t.Fatalf("Failed to transform the IR. Error: %q", err)
+	}
+	workflow, ok := ghSet.workflows["myrepo"+githubActionsWorkflowSuffix]
+	if !ok || len(ghSet.workflows) != 1 {
+		t.Fatalf("Expected a single workflow for the repo myrepo. Actual: %+v", ghSet.workflows)
+	}
+	if workflow.Env[githubActionsRegistryURLEnvVar] != "quay.io/myproject" {
+		t.Fatalf("Expected the registry url to be quay.io/myproject . Actual: %s", workflow.Env[githubActionsRegistryURLEnvVar])
+	}
+	for _, name := range []string{"web", "api"} {
+		job, ok := workflow.Jobs[name]
+		if !ok {
+			t.Fatalf("Expected a job for the image %s . Actual: %+v", name, workflow.Jobs)
+		}
+		with := job.Steps[len(job.Steps)-1].With
+		if with["cache-from"] != "type=gha,scope="+name || with["cache-to"] != "type=gha,mode=max,scope="+name {
+			t.Fatalf("Expected the cache of the image %s to be in its own scope. Actual: %+v", name, with)
+		}
+		if with["file"] != name+"/Dockerfile" || with["context"] != name {
+			t.Fatalf("Expected the Dockerfile and context of the image %s to be relative to the repo. Actual: %+v", name, with)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/transformer/templates"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+	core "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	ingressNginxDir                  = "ingress-nginx"
+	ingressNginxNamespace            = "ingress-nginx"
+	ingressNginxTCPConfigMapName     = "tcp-services"
+	ingressNginxUDPConfigMapName     = "udp-services"
+	ingressNginxNamespacePlaceholder = "<namespace>"
+)
+
+// writeIngressNginxPorts writes the tcp-services and udp-services config maps of the ingress-nginx controller, which
+// expose the non HTTP ports of the services, along with a readme explaining how to configure the controller.
+func writeIngressNginxPorts(outputPath string, ports []irtypes.IngressNginxPort) error {
+	if len(ports) == 0 {
+		return nil
+	}
+	// deploy/ingress-nginx/
+	ingressNginxPath := filepath.Join(outputPath, common.DeployDir, ingressNginxDir)
+	if err := os.MkdirAll(ingressNginxPath, common.DefaultDirectoryPermission); err != nil {
+		log.Errorf("Unable to create directory %s : %s", ingressNginxPath, err)
+		return err
+	}
+	configMaps := map[string]map[string]interface{}{ingressNginxTCPConfigMapName: {}, ingressNginxUDPConfigMapName: {}} // [config map name][controller port][service]
+	type readmePort struct {
+		irtypes.IngressNginxPort
+		Name string
+	}
+	readmePorts := []readmePort{}
+	for _, port := range ports {
+		configMapName := ingressNginxTCPConfigMapName
+		if port.Protocol == core.ProtocolUDP {
+			configMapName = ingressNginxUDPConfigMapName
+		} else if port.Protocol != core.ProtocolTCP {
+			log.Warnf("The ingress-nginx controller only forwards TCP and UDP ports. Ignoring the port %d/%s of the service %s", port.Number, port.Protocol, port.ServiceName)
+			continue
+		}
+		controllerPort := fmt.Sprintf("%d", port.Number)
+		if existing, ok := configMaps[configMapName][controllerPort]; ok {
+			log.Warnf("The port %s of the ingress-nginx controller is already used for %s . Ignoring the port of the service %s", controllerPort, existing, port.ServiceName)
+			continue
+		}
+		configMaps[configMapName][controllerPort] = fmt.Sprintf("%s/%s:%d", ingressNginxNamespacePlaceholder, port.ServiceName, port.Number)
+		readmePorts = append(readmePorts, readmePort{IngressNginxPort: port, Name: strings.ToLower(string(port.Protocol)) + "-" + controllerPort})
+	}
+	for configMapName, data := range configMaps {
+		if len(data) == 0 {
+			continue
+		}
+		configMapPath := filepath.Join(ingressNginxPath, configMapName+"-configmap.yaml")
+		if err := common.WriteYaml(configMapPath, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": configMapName},
+			"data":       data,
+		}); err != nil {
+			log.Errorf("Failed to write the ingress-nginx config map at path %s . Error: %q", configMapPath, err)
+			return err
+		}
+	}
+	readmePath := filepath.Join(ingressNginxPath, "README.md")
+	if err := common.WriteTemplateToFile(templates.IngressNginxPorts_md, struct {
+		Ports                []readmePort
+		NamespacePlaceholder string
+		ControllerNamespace  string
+		TCPConfigMapName     string
+		UDPConfigMapName     string
+	}{
+		Ports:                readmePorts,
+		NamespacePlaceholder: ingressNginxNamespacePlaceholder,
+		ControllerNamespace:  ingressNginxNamespace,
+		TCPConfigMapName:     ingressNginxTCPConfigMapName,
+		UDPConfigMapName:     ingressNginxUDPConfigMapName,
+	}, readmePath, common.DefaultFilePermission); err != nil {
+		log.Errorf("Failed to write the ingress-nginx readme at path %s . Error: %q", readmePath, err)
+		return err
+	}
+	log.Infof("The ingress-nginx controller has to be configured to expose the non HTTP ports. Refer to %s", readmePath)
+	return nil
+}
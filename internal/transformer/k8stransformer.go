@@ -22,6 +22,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/konveyor/move2kube/internal/apiresource"
 	"github.com/konveyor/move2kube/internal/common"
@@ -32,8 +34,10 @@ import (
 	irtypes "github.com/konveyor/move2kube/internal/types"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
 	outputtypes "github.com/konveyor/move2kube/types/output"
+	plantypes "github.com/konveyor/move2kube/types/plan"
 	templatev1 "github.com/openshift/api/template/v1"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -53,6 +57,17 @@ type K8sTransformer struct {
 	Name                            string
 	IgnoreUnsupportedKinds          bool
 	ExposedServicePaths             map[string]string
+	ImageMirrors                    map[string]string
+	RegistryURL                     string
+	VolumeMigrations                []irtypes.VolumeMigration
+	StaticSiteSyncs                 []irtypes.StaticSiteSync
+	IngressNginxPorts               []irtypes.IngressNginxPort
+	ConfigChecksums                 bool
+	// HelmOnly writes only the Helm chart and the build scripts of the images
+	HelmOnly          bool
+	RegistryNamespace string
+	Profiles          []plantypes.Profile
+	complianceReporting
 }
 
 // NewK8sTransformer creates a new instance of K8sTransformer
@@ -75,6 +90,14 @@ func (kt *K8sTransformer) Transform(ir irtypes.IR) error {
 	kt.Containers = ir.Containers
 	kt.TargetClusterSpec = ir.TargetClusterSpec
 	kt.IgnoreUnsupportedKinds = ir.Kubernetes.IgnoreUnsupportedKinds
+	kt.ImageMirrors = ir.ImageMirrors
+	kt.RegistryURL = ir.Kubernetes.RegistryURL
+	kt.RegistryNamespace = ir.Kubernetes.RegistryNamespace
+	kt.Profiles = ir.Profiles
+	kt.VolumeMigrations = ir.VolumeMigrations
+	kt.StaticSiteSyncs = ir.StaticSiteSyncs
+	kt.IngressNginxPorts = ir.IngressNginxPorts
+	kt.ConfigChecksums = ir.Kubernetes.ConfigChecksums
 
 	kt.TransformedObjects = convertIRToObjects(irtypes.NewEnhancedIRFromIR(ir), kt.getAPIResources())
 
@@ -92,6 +115,7 @@ func (kt *K8sTransformer) Transform(ir irtypes.IR) error {
 			len(kt.TransformedObjects), kt.TransformedObjects, len(kt.ParameterizedTransformedObjects), kt.ParameterizedTransformedObjects,
 		)
 	}
+	kt.TransformedObjects = sortObjectsByDependencies(kt.TransformedObjects, ir)
 	kt.reorderParameterizedObjects()
 
 	kt.RootDir = ir.RootDir
@@ -107,6 +131,29 @@ func (kt *K8sTransformer) Transform(ir irtypes.IR) error {
 	return nil
 }
 
+// sortObjectsByDependencies moves the objects of the services after the objects of the services on which they depend,
+// so that the services are created in order. The objects not named after a service, like the config maps, come first.
+func sortObjectsByDependencies(objs []runtime.Object, ir irtypes.IR) []runtime.Object {
+	dependencies := map[string][]string{}
+	for serviceName, service := range ir.Services {
+		dependencies[serviceName] = service.DependsOn
+	}
+	levels, err := common.GetDependencyLevels(dependencies)
+	if err != nil {
+		log.Warnf("Not ordering the objects by the dependencies of the services. Error: %q", err)
+		return objs
+	}
+	getLevel := func(obj runtime.Object) int {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return 0
+		}
+		return levels[accessor.GetName()]
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return getLevel(objs[i]) < getLevel(objs[j]) })
+	return objs
+}
+
 func (kt *K8sTransformer) reorderParameterizedObjects() {
 	reorderedObjects := []runtime.Object{}
 	usedSet := map[int]bool{}
@@ -152,7 +199,7 @@ func (kt *K8sTransformer) getAllMatchingParameterizedObjects(obj runtime.Object)
 }
 
 func (kt *K8sTransformer) getAPIResources() []apiresource.IAPIResource {
-	return []apiresource.IAPIResource{&apiresource.Deployment{}, &apiresource.Storage{}, &apiresource.Service{}, &apiresource.ImageStream{}, &apiresource.NetworkPolicy{}}
+	return []apiresource.IAPIResource{&apiresource.Deployment{}, &apiresource.Rollout{}, &apiresource.HorizontalPodAutoscaler{}, &apiresource.ScaledObject{}, &apiresource.DaprComponent{}, &apiresource.Storage{}, &apiresource.Service{}, &apiresource.ImageStream{}, &apiresource.NetworkPolicy{}}
 }
 
 // WriteObjects writes the transformed objects to files.
@@ -174,6 +221,7 @@ func (kt *K8sTransformer) getAPIResources() []apiresource.IAPIResource {
 //       argocd/
 //   scripts/
 //   source/
+//   m2khistory/
 func (kt *K8sTransformer) WriteObjects(outputPath string, transformPaths []string) error {
 	deployPath := filepath.Join(outputPath, common.DeployDir)
 	if err := os.MkdirAll(deployPath, common.DefaultDirectoryPermission); err != nil {
@@ -185,20 +233,53 @@ func (kt *K8sTransformer) WriteObjects(outputPath string, transformPaths []strin
 
 	// deploy/helm/ and scripts/deployhelm.sh
 	helmPath := filepath.Join(deployPath, common.HelmDir, kt.Name)
+	if kt.HelmOnly {
+		if err := kt.generateHelmArtifacts(helmPath, outputPath, kt.Values, transformPaths); err != nil {
+			log.Errorf("Failed to generate the Helm chart. Error: %q", err)
+		}
+		return nil
+	}
+
+	// scripts/mirrorimages.sh
+	kt.writeMirrorImagesScript(outputPath)
+
+	// deploy/volume-migration/
+	if err := writeVolumeMigrations(outputPath, kt.VolumeMigrations); err != nil {
+		log.Errorf("Failed to write the volume migration manifests. Error: %q", err)
+	}
+
+	// scripts/<service>-sync-static-site.sh
+	if err := writeStaticSiteSyncs(outputPath, kt.RootDir, kt.StaticSiteSyncs); err != nil {
+		log.Errorf("Failed to write the static site sync scripts. Error: %q", err)
+	}
+
+	// deploy/ingress-nginx/
+	if err := writeIngressNginxPorts(outputPath, kt.IngressNginxPorts); err != nil {
+		log.Errorf("Failed to write the ingress-nginx config maps. Error: %q", err)
+	}
+
+	// deploy/helm/ and scripts/deployhelm.sh
 	if err := kt.generateHelmArtifacts(helmPath, outputPath, kt.Values, transformPaths); err != nil {
 		log.Debugf("Failed to generate helm metadata properly, continuing anyway. Error: %q", err)
 	}
 
 	// deploy/yamls/
 	log.Debugf("Total %d services to be serialized.", len(kt.TransformedObjects))
-	fixedConvertedTransformedObjs, err := fixConvertAndTransformObjs(kt.TransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths)
+	fixedConvertedTransformedObjs, err := fixConvertAndTransformObjs(kt.TransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths, kt.violations)
 	if err != nil {
 		log.Errorf("Failed to fix, convert and transform the objects. Error: %q", err)
 	}
+	if kt.ConfigChecksums {
+		fixedConvertedTransformedObjs = addConfigChecksums(fixedConvertedTransformedObjs, false)
+	}
 	k8sArtifactsPath := filepath.Join(deployPath, "yamls")
 	if _, err := writeObjects(k8sArtifactsPath, fixedConvertedTransformedObjs); err != nil {
 		log.Errorf("Failed to write the transformed objects to the directory at path %s . Error: %q", k8sArtifactsPath, err)
 	}
+	// scripts/prune.sh and m2khistory/
+	if err := writePruneArtifacts(outputPath, kt.Name, fixedConvertedTransformedObjs, []string{k8sArtifactsPath, filepath.Join(helmPath, templatesDir)}); err != nil {
+		log.Errorf("Failed to write the artifacts pruning the obsolete resources. Error: %q", err)
+	}
 	// scripts/deploy.sh
 	kt.writeDeployScript(kt.Name, outputPath)
 
@@ -287,7 +368,15 @@ func (kt *K8sTransformer) generateHelmArtifacts(helmPath string, outputPath stri
 
 	// templates/
 	helmArtifactsPath := filepath.Join(helmPath, templatesDir)
-	if _, err := writeTransformedObjects(helmArtifactsPath, kt.ParameterizedTransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths); err != nil {
+	helmObjs, err := fixConvertAndTransformObjs(kt.ParameterizedTransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths, kt.violations)
+	if err != nil {
+		log.Errorf("Failed to fix, convert and transform objects. Error: %q", err)
+		return err
+	}
+	if kt.ConfigChecksums {
+		helmObjs = addConfigChecksums(helmObjs, true)
+	}
+	if _, err := writeObjects(helmArtifactsPath, helmObjs); err != nil {
 		log.Errorf("Error occurred while writing transformed objects. Error: %q", err)
 		return err
 	}
@@ -303,9 +392,7 @@ func (kt *K8sTransformer) createOperator(projectName string, operatorPath string
 		log.Errorf("Failed to create the operator directory at path %s . Error: %q", operatorPath, err)
 		return err
 	}
-	cmd := exec.Command("operator-sdk", "init", "--plugins=helm", "--helm-chart="+helmPath, "--domain=io", "--group="+projectName, "--version=v1alpha1")
-	cmd.Dir = operatorPath
-	output, err := cmd.Output()
+	output, err := common.RunCommand(operatorPath, "operator-sdk", "init", "--plugins=helm", "--helm-chart="+helmPath, "--domain=io", "--group="+projectName, "--version=v1alpha1")
 	if err != nil {
 		log.Warnf("Failed to create the operator. Output:\n%s\nError: %q", string(output), err)
 		return err
@@ -324,7 +411,12 @@ func (kt *K8sTransformer) writeDeployScript(proj string, outputPath string) {
 		log.Errorf("Failed to write the deploy script at path %s . Error: %q", deployScriptPath, err)
 	}
 	deployKustomizeScriptPath := filepath.Join(scriptspath, "deploykustomize.sh")
-	if err := ioutil.WriteFile(deployKustomizeScriptPath, []byte(templates.DeployKustomize_sh), common.DefaultExecutablePermission); err != nil {
+	// The last profile is deployed by default, like prod when there are no profiles
+	overlay := "prod"
+	if len(kt.Profiles) > 0 {
+		overlay = kt.Profiles[len(kt.Profiles)-1].Name
+	}
+	if err := common.WriteTemplateToFile(templates.DeployKustomize_sh, struct{ Overlay string }{Overlay: overlay}, deployKustomizeScriptPath, common.DefaultExecutablePermission); err != nil {
 		log.Errorf("Failed to write the deploy kustomize script at path %s . Error: %q", deployKustomizeScriptPath, err)
 	}
 	deployKnativeScriptPath := filepath.Join(scriptspath, "deployknative.sh")
@@ -358,6 +450,50 @@ func (kt *K8sTransformer) writeDeployScript(proj string, outputPath string) {
 	}
 }
 
+// writeMirrorImagesScript writes the script that copies the images of the rewritten kubernetes artifacts to the new registry
+func (kt *K8sTransformer) writeMirrorImagesScript(outputPath string) {
+	if len(kt.ImageMirrors) == 0 {
+		return
+	}
+	type mirror struct {
+		Source string
+		Target string
+	}
+	mirrors := []mirror{}
+	for source, target := range kt.ImageMirrors {
+		mirrors = append(mirrors, mirror{Source: source, Target: getMirrorTarget(target)})
+	}
+	sort.Slice(mirrors, func(i, j int) bool { return mirrors[i].Source < mirrors[j].Source })
+	scriptsPath := filepath.Join(outputPath, common.ScriptsDir)
+	if err := os.MkdirAll(scriptsPath, common.DefaultDirectoryPermission); err != nil {
+		log.Errorf("Unable to create directory %s : %s", scriptsPath, err)
+	}
+	mirrorImagesScriptPath := filepath.Join(scriptsPath, "mirrorimages.sh")
+	if err := common.WriteTemplateToFile(templates.Mirrorimages_sh, struct {
+		Mirrors     []mirror
+		RegistryURL string
+	}{
+		Mirrors:     mirrors,
+		RegistryURL: kt.RegistryURL,
+	}, mirrorImagesScriptPath, common.DefaultExecutablePermission); err != nil {
+		log.Errorf("Failed to write the script to mirror the images at path %s . Error: %q", mirrorImagesScriptPath, err)
+	}
+}
+
+// getMirrorTarget returns the reference the image is pushed to. The images can't be pushed to a digest, so the images
+// pinned to a digest are pushed to a tag derived from the digest if they have no tag. The digest does not change when copying.
+func getMirrorTarget(target string) string {
+	ref := common.ParseImageReference(target)
+	if ref.Digest == "" {
+		return target
+	}
+	if ref.Tag == "" {
+		ref.Tag = strings.Replace(ref.Digest, ":", "-", 1)
+	}
+	ref.Digest = ""
+	return ref.String()
+}
+
 // generateKustomize generates all the kustomize artifacts given both the original and parameterized objects.
 func (kt *K8sTransformer) generateKustomize(kustomizePath string, transformPaths []string) error {
 	if err := os.MkdirAll(kustomizePath, common.DefaultDirectoryPermission); err != nil {
@@ -366,8 +502,15 @@ func (kt *K8sTransformer) generateKustomize(kustomizePath string, transformPaths
 	}
 	// deploy/kustomize/base/
 	kustomizeBaseDir := filepath.Join(kustomizePath, "base")
-	if _, err := writeTransformedObjects(kustomizeBaseDir, kt.TransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths); err != nil {
-		log.Errorf("Error occurred while writing transformed objects. Error: %q", err)
+	if baseObjs, err := fixConvertAndTransformObjs(kt.TransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths, kt.violations); err != nil {
+		log.Errorf("Failed to fix, convert and transform objects. Error: %q", err)
+	} else {
+		if kt.ConfigChecksums {
+			baseObjs = addConfigChecksums(baseObjs, false)
+		}
+		if _, err := writeObjects(kustomizeBaseDir, baseObjs); err != nil {
+			log.Errorf("Error occurred while writing transformed objects. Error: %q", err)
+		}
 	}
 
 	filenames := []string{}
@@ -389,7 +532,49 @@ func (kt *K8sTransformer) generateKustomize(kustomizePath string, transformPaths
 		fixedConvertedParamObjs = append(fixedConvertedParamObjs, fixedParamObj)
 	}
 
-	return kustomize.GenerateKustomize(kustomizePath, filenames, fixedConvertedObjs, fixedConvertedParamObjs)
+	return kustomize.GenerateKustomize(kustomizePath, filenames, fixedConvertedObjs, fixedConvertedParamObjs, kt.getKustomizeOverlays(fixedConvertedObjs))
+}
+
+// getKustomizeOverlays returns the overlays applying the overrides of the profiles to the objects
+func (kt *K8sTransformer) getKustomizeOverlays(objs []runtime.Object) []kustomize.Overlay {
+	overlays := []kustomize.Overlay{}
+	for _, profile := range kt.Profiles {
+		overlay := kustomize.Overlay{Name: profile.Name, Namespace: profile.Namespace, Replacements: map[string]string{}}
+		if profile.RegistryURL != "" || profile.RegistryNamespace != "" {
+			registryURL, registryNamespace := kt.RegistryURL, kt.RegistryNamespace
+			if profile.RegistryURL != "" {
+				registryURL = profile.RegistryURL
+			}
+			if profile.RegistryNamespace != "" {
+				registryNamespace = profile.RegistryNamespace
+			}
+			for _, container := range kt.Containers {
+				if !container.New {
+					continue
+				}
+				for _, imageName := range container.ImageNames {
+					name, _ := common.GetImageNameAndTag(kt.RegistryURL + "/" + kt.RegistryNamespace + "/" + imageName)
+					newName, _ := common.GetImageNameAndTag(registryURL + "/" + registryNamespace + "/" + imageName)
+					overlay.Images = append(overlay.Images, kustomize.ImageT{Name: name, NewName: newName})
+				}
+			}
+		}
+		if profile.Replicas > 0 {
+			for _, obj := range objs {
+				if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != common.DeploymentKind && kind != common.StatefulSetKind {
+					continue
+				}
+				if accessor, err := meta.Accessor(obj); err == nil {
+					overlay.Replicas = append(overlay.Replicas, kustomize.ReplicaT{Name: accessor.GetName(), Count: profile.Replicas})
+				}
+			}
+		}
+		if profile.IngressHost != "" && kt.TargetClusterSpec.Host != "" {
+			overlay.Replacements[kt.TargetClusterSpec.Host] = profile.IngressHost
+		}
+		overlays = append(overlays, overlay)
+	}
+	return overlays
 }
 
 func (kt *K8sTransformer) writeReadMe(project string, areNewImages bool, outpath string) {
@@ -40,6 +40,9 @@ type KnativeTransformer struct {
 	TargetClusterSpec      collecttypes.ClusterMetadataSpec
 	Name                   string
 	IgnoreUnsupportedKinds bool
+	// WriteContainers writes the build scripts of the images, when the Knative services are the only output
+	WriteContainers bool
+	complianceReporting
 }
 
 // Transform translates intermediate representation to destination objects
@@ -65,9 +68,12 @@ func (kt *KnativeTransformer) getAPIResources() []apiresource.IAPIResource {
 
 // WriteObjects writes the transformed knative resources to files
 func (kt *KnativeTransformer) WriteObjects(outputPath string, transformPaths []string) error {
+	if kt.WriteContainers {
+		writeContainers(kt.Containers, outputPath, kt.RootDir, kt.Values.RegistryURL, kt.Values.RegistryNamespace)
+	}
 	artifactspath := filepath.Join(outputPath, common.DeployDir, "knative")
 	log.Debugf("Total services to be serialized : %d", len(kt.TransformedObjects))
-	if _, err := writeTransformedObjects(artifactspath, kt.TransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths); err != nil {
+	if _, err := writeTransformedObjects(artifactspath, kt.TransformedObjects, kt.TargetClusterSpec, kt.IgnoreUnsupportedKinds, transformPaths, kt.violations); err != nil {
 		log.Errorf("Error occurred while writing knative transformed objects. Error: %q", err)
 	}
 	kt.writeDeployScript(kt.Name, outputPath)
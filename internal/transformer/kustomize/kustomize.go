@@ -80,13 +80,28 @@ var humanReadableNodeKind = map[yaml.Kind]string{
 }
 
 // GenerateKustomize generates all the kustomize artifacts given both the original and parameterized objects.
-func GenerateKustomize(kustomizePath string, filenames []string, objs, paramObjs []runtime.Object) error {
+// If there are overlays for the environment profiles, they are generated instead of the dev, staging and prod overlays.
+func GenerateKustomize(kustomizePath string, filenames []string, objs, paramObjs []runtime.Object, overlays []Overlay) error {
 	// deploy/kustomize/base/
 	kustomizeBaseDir := filepath.Join(kustomizePath, "base")
 	if err := os.MkdirAll(kustomizeBaseDir, common.DefaultDirectoryPermission); err != nil {
 		log.Errorf("Failed to make the kustomize base directory at path %s . Error: %q", kustomizeBaseDir, err)
 		return err
 	}
+	// deploy/kustomize/base/kustomization.yaml
+	kustFilePath := filepath.Join(kustomizeBaseDir, "kustomization.yaml")
+	kustBase := map[string][]string{"resources": filenames}
+	if err := common.WriteYaml(kustFilePath, kustBase); err != nil {
+		log.Errorf("Failed to write the base kustomization.yaml to file at path %s:\n%+v\nError: %q", kustBase, kustFilePath, err)
+	}
+	if len(overlays) > 0 {
+		for _, overlay := range overlays {
+			if err := writeOverlay(kustomizePath, overlay, filenames, objs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	// deploy/kustomize/overlay/dev/
 	kustomizeOverlayDevDir := filepath.Join(kustomizePath, "overlay", "dev")
 	if err := os.MkdirAll(kustomizeOverlayDevDir, common.DefaultDirectoryPermission); err != nil {
@@ -141,14 +156,6 @@ func GenerateKustomize(kustomizePath string, filenames []string, objs, paramObjs
 		}
 	}
 
-	// Base
-	// deploy/kustomize/base/kustomization.yaml
-	kustFilePath := filepath.Join(kustomizeBaseDir, "kustomization.yaml")
-	kustBase := map[string][]string{"resources": filenames}
-	if err := common.WriteYaml(kustFilePath, kustBase); err != nil {
-		log.Errorf("Failed to write the base kustomization.yaml to file at path %s:\n%+v\nError: %q", kustBase, kustFilePath, err)
-	}
-
 	// Overlays
 	kustOverlay := map[string]interface{}{
 		"resources": []string{"../../base"},
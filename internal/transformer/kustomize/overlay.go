@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Overlay is the overlay of an environment profile, like dev or prod
+type Overlay struct {
+	Name      string
+	Namespace string
+	Images    []ImageT
+	Replicas  []ReplicaT
+	// Replacements contains the values of the base which are replaced in the overlay, like the ingress host
+	Replacements map[string]string // [old value][new value]
+}
+
+// ImageT changes the name of an image https://kubectl.docs.kubernetes.io/references/kustomize/kustomization/images/
+type ImageT struct {
+	Name    string `yaml:"name"`
+	NewName string `yaml:"newName"`
+}
+
+// ReplicaT changes the replica count of a deployment or a stateful set https://kubectl.docs.kubernetes.io/references/kustomize/kustomization/replicas/
+type ReplicaT struct {
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+}
+
+type overlayKustomizationT struct {
+	Resources []string         `yaml:"resources"`
+	Namespace string           `yaml:"namespace,omitempty"`
+	Images    []ImageT         `yaml:"images,omitempty"`
+	Replicas  []ReplicaT       `yaml:"replicas,omitempty"`
+	Patches   []PatchMetadataT `yaml:"patches,omitempty"`
+}
+
+// writeOverlay writes the kustomization of the overlay and the patches replacing the values of the base objects
+func writeOverlay(kustomizePath string, overlay Overlay, filenames []string, objs []runtime.Object) error {
+	// deploy/kustomize/overlay/<profile>/
+	overlayDir := filepath.Join(kustomizePath, "overlay", overlay.Name)
+	if err := os.MkdirAll(overlayDir, common.DefaultDirectoryPermission); err != nil {
+		log.Errorf("Failed to make the kustomize overlay directory at path %s . Error: %q", overlayDir, err)
+		return err
+	}
+	kustomization := overlayKustomizationT{
+		Resources: []string{"../../base"},
+		Namespace: overlay.Namespace,
+		Images:    overlay.Images,
+		Replicas:  overlay.Replicas,
+	}
+	if len(overlay.Replacements) > 0 {
+		for i, obj := range objs {
+			patches, err := getReplacementPatches(obj, overlay.Replacements)
+			if err != nil {
+				log.Errorf("Failed to get the patches of the overlay %s for the file %s . Error: %q", overlay.Name, filenames[i], err)
+				continue
+			}
+			if len(patches) == 0 {
+				continue
+			}
+			patchPath := filepath.Join(overlayDir, filenames[i])
+			if err := common.WriteYaml(patchPath, patches); err != nil {
+				log.Errorf("Failed to write the patches to the file at path %s . Error: %q", patchPath, err)
+				continue
+			}
+			kustomization.Patches = append(kustomization.Patches, getMetadata(filenames[i], obj))
+		}
+	}
+	kustomizationPath := filepath.Join(overlayDir, "kustomization.yaml")
+	if err := common.WriteYaml(kustomizationPath, kustomization); err != nil {
+		log.Errorf("Failed to write the overlay kustomization.yaml to the file at path %s . Error: %q", kustomizationPath, err)
+		return err
+	}
+	return nil
+}
+
+// getReplacementPatches returns the json patches replacing the string values of the object which have replacements
+func getReplacementPatches(obj runtime.Object, replacements map[string]string) ([]PatchT, error) {
+	objJSONBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(objJSONBytes, &value); err != nil {
+		return nil, err
+	}
+	patches := []PatchT{}
+	var walk func(jsonPointer string, value interface{})
+	walk = func(jsonPointer string, value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			keys := []string{}
+			for key := range value {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(jsonPointer+"/"+escapeJSONPointer(key), value[key])
+			}
+		case []interface{}:
+			for i, item := range value {
+				walk(jsonPointer+"/"+cast.ToString(i), item)
+			}
+		case string:
+			if newValue, ok := replacements[value]; ok && newValue != value {
+				patches = append(patches, PatchT{Op: "replace", Path: jsonPointer, Value: newValue})
+			}
+		}
+	}
+	walk("", value)
+	return patches, nil
+}
+
+// escapeJSONPointer escapes a key as a reference token of a json pointer https://tools.ietf.org/html/rfc6901
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
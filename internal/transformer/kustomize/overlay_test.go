@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGenerateKustomizeOverlays(t *testing.T) {
+	ingress := &networkingv1.Ingress{
+		TypeMeta:   metav1.TypeMeta{Kind: "Ingress", APIVersion: "networking.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: networkingv1.IngressSpec{
+			TLS:   []networkingv1.IngressTLS{{Hosts: []string{"myproject.example.com"}}},
+			Rules: []networkingv1.IngressRule{{Host: "myproject.example.com"}},
+		},
+	}
+	objs := []runtime.Object{ingress}
+	filenames := []string{"web-ingress.yaml"}
+	overlays := []Overlay{
+		{Name: "dev", Namespace: "dev", Replicas: []ReplicaT{{Name: "web", Count: 1}}},
+		{
+			Name:         "prod",
+			Images:       []ImageT{{Name: "quay.io/myproject/web", NewName: "registry.example.com/prod/web"}},
+			Replacements: map[string]string{"myproject.example.com": "shop.example.com"},
+		},
+	}
+	kustomizePath := t.TempDir()
+	if err := GenerateKustomize(kustomizePath, filenames, objs, objs, overlays); err != nil {
+		t.Fatalf("Failed to generate the kustomize artifacts. Error: %q", err)
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(kustomizePath, "overlay", "staging", "kustomization.yaml")); err == nil {
+		t.Fatalf("Expected only the overlays of the profiles")
+	}
+
+	dev := overlayKustomizationT{}
+	if err := common.ReadYaml(filepath.Join(kustomizePath, "overlay", "dev", "kustomization.yaml"), &dev); err != nil {
+		t.Fatalf("Failed to read the kustomization of the dev overlay. Error: %q", err)
+	}
+	wantDev := overlayKustomizationT{Resources: []string{"../../base"}, Namespace: "dev", Replicas: []ReplicaT{{Name: "web", Count: 1}}}
+	if !reflect.DeepEqual(dev, wantDev) {
+		t.Fatalf("Failed to write the dev overlay. Expected: %+v Actual: %+v", wantDev, dev)
+	}
+
+	prod := overlayKustomizationT{}
+	if err := common.ReadYaml(filepath.Join(kustomizePath, "overlay", "prod", "kustomization.yaml"), &prod); err != nil {
+		t.Fatalf("Failed to read the kustomization of the prod overlay. Error: %q", err)
+	}
+	if !reflect.DeepEqual(prod.Images, overlays[1].Images) || len(prod.Patches) != 1 || prod.Patches[0].Path != "web-ingress.yaml" || prod.Patches[0].Target.Kind != "Ingress" {
+		t.Fatalf("Failed to write the prod overlay. Actual: %+v", prod)
+	}
+	patches := []PatchT{}
+	if err := common.ReadYaml(filepath.Join(kustomizePath, "overlay", "prod", "web-ingress.yaml"), &patches); err != nil {
+		t.Fatalf("Failed to read the patches of the prod overlay. Error: %q", err)
+	}
+	wantPatches := []PatchT{
+		{Op: "replace", Path: "/spec/rules/0/host", Value: "shop.example.com"},
+		{Op: "replace", Path: "/spec/tls/0/hosts/0", Value: "shop.example.com"},
+	}
+	if !reflect.DeepEqual(patches, wantPatches) {
+		t.Fatalf("Failed to replace the ingress host. Expected: %+v Actual: %+v", wantPatches, patches)
+	}
+}
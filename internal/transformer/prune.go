@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/transformer/templates"
+	outputtypes "github.com/konveyor/move2kube/types/output"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const pruneScriptName = "prune.sh"
+
+// writePruneArtifacts compares the resources with the snapshots of the resources generated by the earlier translations
+// into the output directory. The resources which are no longer generated, like the ones of a service removed from the
+// plan, get deleted by scripts/prune.sh, and their stale yamls are removed from the directories of the artifacts, so
+// that they are not applied again. A snapshot of the resources is then added to the history.
+func writePruneArtifacts(outputPath, name string, objs []runtime.Object, artifactsPaths []string) error {
+	resources := getResourceRefs(objs)
+	snapshots, err := outputtypes.ReadResourcesSnapshots(outputPath)
+	if err != nil {
+		log.Warnf("Failed to read the snapshots of the resources generated by the earlier translations. Error: %q", err)
+	}
+	obsolete := outputtypes.GetObsoleteResources(snapshots, resources)
+	pruneScriptPath := filepath.Join(outputPath, common.ScriptsDir, pruneScriptName)
+	if len(obsolete) == 0 {
+		// a prune script from an earlier translation could delete resources which are generated again
+		if err := os.Remove(pruneScriptPath); err != nil && !os.IsNotExist(err) {
+			log.Warnf("Failed to remove the prune script at path %s . Error: %q", pruneScriptPath, err)
+		}
+	} else {
+		removeStaleYamls(obsolete, resources, artifactsPaths)
+		if err := os.MkdirAll(filepath.Dir(pruneScriptPath), common.DefaultDirectoryPermission); err != nil {
+			return err
+		}
+		if err := common.WriteTemplateToFile(templates.Prune_sh, struct{ Resources []outputtypes.ResourceRef }{Resources: obsolete}, pruneScriptPath, common.DefaultExecutablePermission); err != nil {
+			return err
+		}
+		names := []string{}
+		for _, resource := range obsolete {
+			names = append(names, resource.KubectlName())
+		}
+		log.Warnf("The resources %s were generated by earlier translations, but are no longer part of the artifacts. Use %s to delete them from the cluster.", strings.Join(names, ", "), filepath.Join(common.ScriptsDir, pruneScriptName))
+	}
+	if _, err := outputtypes.WriteResourcesSnapshot(outputPath, outputtypes.NewResourcesSnapshot(name, resources)); err != nil {
+		return fmt.Errorf("failed to write the snapshot of the resources. Error: %q", err)
+	}
+	return nil
+}
+
+// getResourceRefs returns the references to the objects
+func getResourceRefs(objs []runtime.Object) []outputtypes.ResourceRef {
+	resources := []outputtypes.ResourceRef{}
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			log.Debugf("Unable to get the metadata of the object %+v . Error: %q", obj, err)
+			continue
+		}
+		apiVersion, kind := obj.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+		resources = append(resources, outputtypes.ResourceRef{APIVersion: apiVersion, Kind: kind, Name: accessor.GetName(), Namespace: accessor.GetNamespace()})
+	}
+	return resources
+}
+
+// removeStaleYamls removes the yamls of the obsolete resources from the directories, unless a current resource is written to the same file
+func removeStaleYamls(obsolete, current []outputtypes.ResourceRef, dirs []string) {
+	currentFilenames := map[string]bool{}
+	for _, resource := range current {
+		currentFilenames[getResourceFilename(resource)] = true
+	}
+	for _, resource := range obsolete {
+		filename := getResourceFilename(resource)
+		if currentFilenames[filename] {
+			continue
+		}
+		for _, dir := range dirs {
+			path := filepath.Join(dir, filename)
+			if err := os.Remove(path); err == nil {
+				log.Infof("Removed the stale yaml at path %s", path)
+			} else if !os.IsNotExist(err) {
+				log.Warnf("Failed to remove the stale yaml at path %s . Error: %q", path, err)
+			}
+		}
+	}
+}
+
+// getResourceFilename returns the name of the file the resource is written to, like getFilename does for the objects
+func getResourceFilename(resource outputtypes.ResourceRef) string {
+	return fmt.Sprintf("%s-%s.yaml", resource.Name, strings.ToLower(resource.Kind))
+}
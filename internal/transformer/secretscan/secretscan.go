@@ -0,0 +1,268 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretscan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// AllowlistFilename is the name of the file in the source directory which lists the false positives
+	AllowlistFilename = "." + types.AppNameShort + "secretsallow"
+	// maxFileSize is the size above which the files are not scanned, since they are rarely source code
+	maxFileSize = 10 * 1024 * 1024
+	// minEntropyValueLength and minEntropy are the thresholds of the values of the secret like assignments
+	minEntropyValueLength = 20
+	minEntropy            = 4.0
+)
+
+// Detector finds a kind of credential in a line of a file
+type Detector interface {
+	// Name is the kind of credential, like AWS access key ID
+	Name() string
+	// Detect returns true if the line contains the credential
+	Detect(line string) bool
+}
+
+// Finding is a line of a file containing a credential. The credential itself is not recorded, so that it does not end up in the logs.
+type Finding struct {
+	// Path is the slash separated path of the file relative to the scanned directory
+	Path     string
+	Line     int
+	Detector string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s found at %s:%d", f.Detector, f.Path, f.Line)
+}
+
+type regexDetector struct {
+	name   string
+	regexp *regexp.Regexp
+}
+
+func (d regexDetector) Name() string {
+	return d.name
+}
+
+func (d regexDetector) Detect(line string) bool {
+	return d.regexp.MatchString(line)
+}
+
+// entropyDetector finds the assignments of random looking values to variables named like secrets
+type entropyDetector struct {
+}
+
+var secretAssignmentRegex = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|api_?key|access_?key|private_?key)[A-Za-z0-9_.-]*["']?\s*[:=]\s*["']?([A-Za-z0-9+/=_\-]+)`)
+
+func (d entropyDetector) Name() string {
+	return "high entropy secret"
+}
+
+func (d entropyDetector) Detect(line string) bool {
+	for _, match := range secretAssignmentRegex.FindAllStringSubmatch(line, -1) {
+		value := match[3]
+		if len(value) >= minEntropyValueLength && getShannonEntropy(value) > minEntropy {
+			return true
+		}
+	}
+	return false
+}
+
+// getShannonEntropy returns the number of bits of information per character of the string
+func getShannonEntropy(value string) float64 {
+	counts := map[rune]int{}
+	for _, c := range value {
+		counts[c]++
+	}
+	entropy := 0.0
+	length := float64(len(value))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+var (
+	detectors = []Detector{
+		regexDetector{name: "AWS access key ID", regexp: regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)},
+		regexDetector{name: "AWS secret access key", regexp: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)},
+		regexDetector{name: "private key", regexp: regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+		regexDetector{name: "GitHub token", regexp: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		regexDetector{name: "Slack token", regexp: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+		regexDetector{name: "Google API key", regexp: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+		entropyDetector{},
+	}
+	detectorsMutex = sync.Mutex{}
+)
+
+// RegisterDetector adds a detector to the ones run by Scan
+func RegisterDetector(detector Detector) {
+	detectorsMutex.Lock()
+	defer detectorsMutex.Unlock()
+	detectors = append(detectors, detector)
+}
+
+func getDetectors() []Detector {
+	detectorsMutex.Lock()
+	defer detectorsMutex.Unlock()
+	return append([]Detector{}, detectors...)
+}
+
+// allowlistEntry is a line of the allowlist file. The pattern matches the relative path of the file, or its name if the
+// pattern has no slash. A line number of 0 allows the whole file.
+type allowlistEntry struct {
+	pattern string
+	line    int
+}
+
+// readAllowlist reads the allowlist file in the directory, if there is one
+func readAllowlist(rootDir string) []allowlistEntry {
+	allowlistPath := filepath.Join(rootDir, AllowlistFilename)
+	data, err := ioutil.ReadFile(allowlistPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("Failed to read the secret scan allowlist at path %s . Error: %q", allowlistPath, err)
+		}
+		return nil
+	}
+	entries := []allowlistEntry{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := allowlistEntry{pattern: line}
+		if idx := strings.LastIndex(line, ":"); idx != -1 {
+			if lineNumber, err := strconv.Atoi(line[idx+1:]); err == nil {
+				entry = allowlistEntry{pattern: line[:idx], line: lineNumber}
+			}
+		}
+		if _, err := filepath.Match(entry.pattern, ""); err != nil {
+			log.Warnf("Ignoring the invalid pattern %s in the secret scan allowlist at path %s . Error: %q", entry.pattern, allowlistPath, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func isAllowed(allowlist []allowlistEntry, relPath string, line int) bool {
+	for _, entry := range allowlist {
+		if entry.line != 0 && entry.line != line {
+			continue
+		}
+		name := relPath
+		if !strings.Contains(entry.pattern, "/") {
+			name = filepath.Base(relPath)
+		}
+		if matched, _ := filepath.Match(entry.pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan scans the files in the directory, which are about to be copied into the build contexts, for embedded credentials.
+// The lines listed in the allowlist file of the directory are not reported.
+func Scan(rootDir string) ([]Finding, error) {
+	findings := []Finding{}
+	allowlist := readAllowlist(rootDir)
+	activeDetectors := getDetectors()
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Warnf("Skipping the path %s while scanning for secrets. Error: %q", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxFileSize || info.Name() == AllowlistFilename {
+			return nil
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		fileFindings, err := scanFile(path, relPath, activeDetectors, allowlist)
+		if err != nil {
+			log.Warnf("Failed to scan the file at path %s for secrets. Error: %q", path, err)
+			return nil
+		}
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, err
+}
+
+func scanFile(path, relPath string, detectors []Detector, allowlist []allowlistEntry) ([]Finding, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Binary files have null bytes near the start
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) != -1 {
+		return nil, nil
+	}
+	findings := []Finding{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		for _, detector := range detectors {
+			if !detector.Detect(line) {
+				continue
+			}
+			if isAllowed(allowlist, relPath, lineNumber) {
+				log.Debugf("Allowing the %s at %s:%d", detector.Name(), relPath, lineNumber)
+				break
+			}
+			findings = append(findings, Finding{Path: relPath, Line: lineNumber, Detector: detector.Name()})
+			break
+		}
+	}
+	return findings, scanner.Err()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/transformer/templates"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const staticSiteSyncScriptSuffix = "-sync-static-site.sh"
+
+// writeStaticSiteSyncs writes the scripts which build the static sites and upload them to their buckets
+func writeStaticSiteSyncs(outputPath, rootDir string, syncs []irtypes.StaticSiteSync) error {
+	if len(syncs) == 0 {
+		return nil
+	}
+	scriptsPath := filepath.Join(outputPath, common.ScriptsDir)
+	if err := os.MkdirAll(scriptsPath, common.DefaultDirectoryPermission); err != nil {
+		log.Errorf("Unable to create directory %s : %s", scriptsPath, err)
+		return err
+	}
+	sourcePath := filepath.Join(outputPath, common.SourceDir)
+	for _, sync := range syncs {
+		// The scripts build the sites from the copy of the sources in the output
+		if _, err := os.Stat(filepath.Join(sourcePath, sync.SourceDir)); os.IsNotExist(err) {
+			if err := os.MkdirAll(sourcePath, common.DefaultDirectoryPermission); err != nil {
+				log.Errorf("Failed to create the source directory at path %s . Error: %q", sourcePath, err)
+				return err
+			}
+			if err := common.CopyPath(sourcePath, rootDir); err != nil {
+				log.Errorf("Failed to copy the sources over to the folder at path %s Error: %q", sourcePath, err)
+				return err
+			}
+		}
+		scriptPath := filepath.Join(scriptsPath, sync.ServiceName+staticSiteSyncScriptSuffix)
+		if err := common.WriteTemplateToFile(templates.StaticSiteSync_sh, struct {
+			ServiceName string
+			BucketURL   string
+			SourcesDir  string
+			SourceDir   string
+			SyncCommand string
+		}{
+			ServiceName: sync.ServiceName,
+			BucketURL:   sync.BucketURL,
+			SourcesDir:  common.SourceDir,
+			SourceDir:   filepath.ToSlash(sync.SourceDir),
+			SyncCommand: getStaticSiteSyncCommand(filepath.ToSlash(sync.BuildDir), sync.BucketURL),
+		}, scriptPath, common.DefaultExecutablePermission); err != nil {
+			log.Errorf("Failed to write the static site sync script at path %s . Error: %q", scriptPath, err)
+			return err
+		}
+		log.Infof("The static site %s has to be uploaded to %s using the script %s", sync.ServiceName, sync.BucketURL, scriptPath)
+	}
+	return nil
+}
+
+// getStaticSiteSyncCommand returns the command which uploads the built files to the bucket, based on the object storage of the bucket
+func getStaticSiteSyncCommand(buildDir, bucketURL string) string {
+	switch {
+	case strings.HasPrefix(bucketURL, "s3://"):
+		return fmt.Sprintf("aws s3 sync --delete %s %s", buildDir, bucketURL)
+	case strings.HasPrefix(bucketURL, "gs://"):
+		return fmt.Sprintf("gsutil -m rsync -r -d %s %s", buildDir, bucketURL)
+	case strings.Contains(bucketURL, ".blob.core.windows.net"):
+		return fmt.Sprintf("azcopy sync %s '%s' --delete-destination=true", buildDir, bucketURL)
+	}
+	return fmt.Sprintf("echo 'TODO: upload the files in %s to the bucket %s'", buildDir, bucketURL)
+}
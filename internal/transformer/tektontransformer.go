@@ -46,6 +46,7 @@ type TektonTransformer struct {
 	TargetClusterSpec        collecttypes.ClusterMetadataSpec
 	IgnoreUnsupportedKinds   bool
 	extraFiles               map[string]string // file path: file contents
+	complianceReporting
 }
 
 const (
@@ -100,7 +101,7 @@ func (tekSet *TektonTransformer) WriteObjects(outputPath string, transformPaths
 	cicdPath := filepath.Join(outputPath, common.DeployDir, "cicd")
 	// deploy/cicd/tekton/
 	tektonPath := filepath.Join(cicdPath, "tekton")
-	if _, err := writeTransformedObjects(tektonPath, tekSet.transformedTektonObjects, tekSet.TargetClusterSpec, false, transformPaths); err != nil {
+	if _, err := writeTransformedObjects(tektonPath, tekSet.transformedTektonObjects, tekSet.TargetClusterSpec, false, transformPaths, tekSet.violations); err != nil {
 		log.Errorf("Error occurred while writing transformed objects. Error: %q", err)
 		return err
 	}
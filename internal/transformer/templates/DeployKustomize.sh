@@ -13,7 +13,7 @@
 #   See the License for the specific language governing permissions and
 #   limitations under the License.
 
-overlay="${1:-prod}"
+overlay="${1:-{{ .Overlay }}}"
 echo "Deploying the overlay ${overlay} using Kustomize..."
 kubectl apply -k deploy/kustomize/overlay/"${overlay}"
 cat deploy/kustomize/NOTES.txt
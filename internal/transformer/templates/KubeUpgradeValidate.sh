@@ -0,0 +1,33 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Validates the upgraded manifests against the cluster of the current kubectl context, which should run Kubernetes {{.ToVersion}}.
+# The manifests are only sent with a server side dry run, so nothing is changed in the cluster.
+
+set -e
+cd "$(dirname "$0")"/..
+
+SERVED_API_VERSIONS="$(kubectl api-versions)"
+MISSING_API_VERSIONS=0
+{{range $apiVersion := .APIVersions}}if ! echo "$SERVED_API_VERSIONS" | grep -qx '{{$apiVersion}}'; then
+  echo "The API version {{$apiVersion}} is not served by the cluster"
+  MISSING_API_VERSIONS=1
+fi
+{{end}}if [ "$MISSING_API_VERSIONS" -ne 0 ]; then
+  exit 1
+fi
+
+kubectl apply --dry-run=server --recursive -f {{.ManifestsDir}}
+echo "The upgraded manifests are valid for the cluster"
@@ -0,0 +1,23 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Copies the images used by the kubernetes artifacts to the new registry using skopeo.
+# All the architectures are copied without modifications, so that the digests stay the same.
+
+# Uncomment the below line if you want to enable login before copying
+# skopeo login {{ .RegistryURL }}
+
+{{range $mirror := .Mirrors}}skopeo copy --all docker://{{$mirror.Source}} docker://{{$mirror.Target}}
+{{end}}
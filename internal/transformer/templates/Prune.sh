@@ -0,0 +1,21 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Deletes the resources which earlier translations generated, but which are no longer part of the artifacts,
+# like the resources of the services which were removed or renamed in the plan.
+# Review the list before running the script against a cluster.
+
+{{range $resource := .Resources}}kubectl delete --ignore-not-found {{$resource.KubectlName}}{{if $resource.Namespace}} -n {{$resource.Namespace}}{{end}}
+{{end}}
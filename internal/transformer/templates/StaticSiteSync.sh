@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Builds the static site of the service {{ .ServiceName }} and uploads it to {{ .BucketURL }}
+# The files which are no longer part of the site are deleted from the bucket.
+# Takes as input the directory containing the sources, which defaults to the source directory of the output.
+
+set -e
+SOURCE_DIR="${1:-$(dirname "$0")/../{{ .SourcesDir }}}"
+cd "$SOURCE_DIR/{{ .SourceDir }}"
+npm install
+npm run build
+{{ .SyncCommand }}
+
+# TODO: invalidate the cache of the CDN in front of the bucket, like
+# aws cloudfront create-invalidation --distribution-id <distribution id> --paths "/*"
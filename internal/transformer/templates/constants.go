@@ -129,7 +129,7 @@ cat NOTES.txt
 #   See the License for the specific language governing permissions and
 #   limitations under the License.
 
-overlay="${1:-prod}"
+overlay="${1:-{{ .Overlay }}}"
 echo "Deploying the overlay ${overlay} using Kustomize..."
 kubectl apply -k deploy/kustomize/overlay/"${overlay}"
 cat deploy/kustomize/NOTES.txt
@@ -201,6 +201,91 @@ Next Steps
 * The k8s yamls are in "./deploy/yamls/". Use "./scripts/deploy.sh" to deploy them into a kubernetes cluster.
 * The helm chart is at "./deploy/helm-charts/". Use "./scripts/deployhelm.sh" to install it.
 * The operator is at "./deploy/operator/".
+`
+
+	IngressNginxPorts_md = `Ingress-nginx TCP and UDP ports
+-------------------------------
+The below ports of the services do not serve HTTP. They are exposed on the ingress-nginx controller, which forwards
+the TCP and UDP streams using the config maps in this directory.
+
+{{range $port := .Ports}}- {{$port.Number}}/{{$port.Protocol}} : {{$port.ServiceName}} ({{$port.Hint}})
+{{end}}
+1. Replace the {{ .NamespacePlaceholder }} placeholder in the config maps with the namespace of the services.
+2. If the controller already uses the config maps, merge these entries into them. Otherwise create them in the
+   namespace of the controller:
+
+        kubectl apply -n {{ .ControllerNamespace }} -f .
+
+3. Start the controller with the config maps, by adding the below arguments to its deployment:
+
+        --tcp-services-configmap=$(POD_NAMESPACE)/{{ .TCPConfigMapName }}
+        --udp-services-configmap=$(POD_NAMESPACE)/{{ .UDPConfigMapName }}
+
+4. Add the ports to the service of the controller, so that they are reachable from outside the cluster:
+
+        ports:
+{{- range $port := .Ports}}
+        - name: {{$port.Name}}
+          port: {{$port.Number}}
+          targetPort: {{$port.Number}}
+          protocol: {{$port.Protocol}}
+{{- end}}
+
+With the ingress-nginx helm chart, set the "tcp" and "udp" values of the chart to the same entries instead.
+`
+
+	KubeUpgradeValidate_sh = `#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Validates the upgraded manifests against the cluster of the current kubectl context, which should run Kubernetes {{.ToVersion}}.
+# The manifests are only sent with a server side dry run, so nothing is changed in the cluster.
+
+set -e
+cd "$(dirname "$0")"/..
+
+SERVED_API_VERSIONS="$(kubectl api-versions)"
+MISSING_API_VERSIONS=0
+{{range $apiVersion := .APIVersions}}if ! echo "$SERVED_API_VERSIONS" | grep -qx '{{$apiVersion}}'; then
+  echo "The API version {{$apiVersion}} is not served by the cluster"
+  MISSING_API_VERSIONS=1
+fi
+{{end}}if [ "$MISSING_API_VERSIONS" -ne 0 ]; then
+  exit 1
+fi
+
+kubectl apply --dry-run=server --recursive -f {{.ManifestsDir}}
+echo "The upgraded manifests are valid for the cluster"
+`
+
+	KubeUpgrade_md = `Kubernetes upgrade from {{.FromVersion}} to {{.ToVersion}}
+------------------------------------------------
+The manifests in the {{.ManifestsDir}} directory use the API versions served by Kubernetes {{.ToVersion}}, except for the resources which have to be rewritten manually.
+Validate them against the upgraded cluster using {{.ValidatePath}} .
+
+{{if .Upgrades}}| File | Kind | Name | API version | Status |
+| --- | --- | --- | --- | --- |
+{{range $upgrade := .Upgrades}}| {{$upgrade.FilePath}} | {{$upgrade.Kind}} | {{$upgrade.Name}} | {{$upgrade.APIVersion}} | {{$upgrade.Status}} |
+{{end}}{{else}}None of the resources use an API which is deprecated in Kubernetes {{.ToVersion}}.
+{{end}}{{if .Changes}}
+Changed fields and defaults
+---------------------------
+Review the resources of these kinds, since they behave differently in the new API versions.
+{{range $change := .Changes}}
+{{$change.Kind}} {{$change.APIVersion}}{{if $change.Replacement}} to {{$change.Replacement}}{{end}}:
+{{range $note := $change.Notes}}- {{$note}}
+{{end}}{{end}}{{end}}
 `
 
 	Manualimages_md = `Manual containers
@@ -210,6 +295,31 @@ There is no known automated containerization approach for the below container re
 {{range $image := .Images}}{{$image}}
 {{end}}`
 
+	Mirrorimages_sh = `#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Copies the images used by the kubernetes artifacts to the new registry using skopeo.
+# All the architectures are copied without modifications, so that the digests stay the same.
+
+# Uncomment the below line if you want to enable login before copying
+# skopeo login {{ .RegistryURL }}
+
+{{range $mirror := .Mirrors}}skopeo copy --all docker://{{$mirror.Source}} docker://{{$mirror.Target}}
+{{end}}
+`
+
 	NOTES_txt = `{{if .IsHelm}}
 {{if .ExposedServicePaths}}
 The services are accessible on the following paths:
@@ -228,6 +338,29 @@ The services are accessible on the following paths:
 This app has no exposed services.
 {{end}}
 {{end}}
+`
+
+	Prune_sh = `#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Deletes the resources which earlier translations generated, but which are no longer part of the artifacts,
+# like the resources of the services which were removed or renamed in the plan.
+# Review the list before running the script against a cluster.
+
+{{range $resource := .Resources}}kubectl delete --ignore-not-found {{$resource.KubectlName}}{{if $resource.Namespace}} -n {{$resource.Namespace}}{{end}}
+{{end}}
 `
 
 	Pushimages_sh = `#!/usr/bin/env bash
@@ -263,4 +396,72 @@ docker push ${REGISTRY_URL}/${REGISTRY_NAMESPACE}/{{$image}}
 {{end}}
 `
 
+	StaticSiteSync_sh = `#!/usr/bin/env bash
+#   Copyright IBM Corporation 2020
+#
+#   Licensed under the Apache License, Version 2.0 (the "License");
+#   you may not use this file except in compliance with the License.
+#   You may obtain a copy of the License at
+#
+#        http://www.apache.org/licenses/LICENSE-2.0
+#
+#   Unless required by applicable law or agreed to in writing, software
+#   distributed under the License is distributed on an "AS IS" BASIS,
+#   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+#   See the License for the specific language governing permissions and
+#   limitations under the License.
+
+# Builds the static site of the service {{ .ServiceName }} and uploads it to {{ .BucketURL }}
+# The files which are no longer part of the site are deleted from the bucket.
+# Takes as input the directory containing the sources, which defaults to the source directory of the output.
+
+set -e
+SOURCE_DIR="${1:-$(dirname "$0")/../{{ .SourcesDir }}}"
+cd "$SOURCE_DIR/{{ .SourceDir }}"
+npm install
+npm run build
+{{ .SyncCommand }}
+
+# TODO: invalidate the cache of the CDN in front of the bucket, like
+# aws cloudfront create-invalidation --distribution-id <distribution id> --paths "/*"
+`
+
+	VolumeMigration_md = `Volume migration
+----------------
+The data of the below persistent volume claims has to be moved from the source cluster to the target cluster.
+The claims are not in the generated yamls, since the manifests in this directory restore them along with their data.
+
+{{range $claim := .Claims}}- {{$claim.Namespace}}/{{$claim.ClaimName}} : {{$claim.Size}}, storage class "{{$claim.SourceStorageClass}}" in the source cluster and "{{$claim.TargetStorageClass}}" in the target cluster
+{{end}}
+Using CSI volume snapshots
+--------------------------
+Both clusters need the CSI snapshot controller and a storage backend that is reachable from both clusters.
+
+1. Create the snapshots in the source cluster:
+
+        kubectl apply -f snapshot/source
+
+2. Get the snapshot handles and the CSI drivers of the snapshots in the source cluster:
+
+        kubectl get volumesnapshotcontent -o custom-columns=SNAPSHOT:.spec.volumeSnapshotRef.name,NAMESPACE:.spec.volumeSnapshotRef.namespace,DRIVER:.spec.driver,HANDLE:.status.snapshotHandle
+
+3. Replace the {{ .DriverPlaceholder }} and {{ .HandlePlaceholder }} placeholders in the snapshot/target/*-volumesnapshotcontent.yaml files.
+4. Restore the claims in the target cluster:
+
+        kubectl apply -f snapshot/target
+
+Using velero
+------------
+Both clusters need velero installed with access to the same backup storage location.
+The backups contain all the persistent volume claims of the namespaces of the claims.
+
+1. Schedule the backups in the source cluster:
+
+        kubectl apply -f velero/source
+
+2. Restore the latest backup in the target cluster. The storage classes of the claims are changed to those of the target cluster:
+
+        kubectl apply -f velero/target
+`
+
 )
\ No newline at end of file
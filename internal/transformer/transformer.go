@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/a8m/tree"
 	"github.com/a8m/tree/ostree"
@@ -36,11 +37,13 @@ import (
 	"github.com/konveyor/move2kube/internal/starlark/gettransformdata"
 	"github.com/konveyor/move2kube/internal/starlark/runtransforms"
 	startypes "github.com/konveyor/move2kube/internal/starlark/types"
+	"github.com/konveyor/move2kube/internal/transformer/compliance"
+	"github.com/konveyor/move2kube/internal/transformer/secretscan"
 	"github.com/konveyor/move2kube/internal/transformer/templates"
 	"github.com/konveyor/move2kube/internal/transformer/transformations"
 	irtypes "github.com/konveyor/move2kube/internal/types"
 	collecttypes "github.com/konveyor/move2kube/types/collection"
-	"github.com/otiai10/copy"
+	plantypes "github.com/konveyor/move2kube/types/plan"
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -58,9 +61,24 @@ type Transformer interface {
 
 // Transform transforms the IR into runtime.Objects and write all the deployments artifacts to files.
 func Transform(ir irtypes.IR, outputPath string, transformPaths []string) error {
-	transformers := GetTransformers()
+	return runTransformers(GetTransformers(), ir, outputPath, transformPaths)
+}
+
+// TransformTarget transforms the IR into the artifacts of an output target and writes them to files.
+func TransformTarget(ir irtypes.IR, outputPath string, transformPaths []string, targetType plantypes.OutputTargetTypeValue) error {
+	return runTransformers(GetTargetTransformers(targetType), ir, outputPath, transformPaths)
+}
+
+func runTransformers(transformers []Transformer, ir irtypes.IR, outputPath string, transformPaths []string) error {
+	violations := newViolationReporter()
 	for _, transformer := range transformers {
-		if err := transformer.Transform(ir); err != nil {
+		if setter, ok := transformer.(violationReporterSetter); ok {
+			setter.setViolationReporter(violations)
+		}
+		endRegion := common.TraceRegion(fmt.Sprintf("transform %T", transformer))
+		err := transformer.Transform(ir)
+		endRegion()
+		if err != nil {
 			log.Errorf("Error during translate. Error: %q", err)
 			return err
 		} else if err := transformer.WriteObjects(outputPath, transformPaths); err != nil {
@@ -73,7 +91,20 @@ func Transform(ir irtypes.IR, outputPath string, transformPaths []string) error
 
 // GetTransformers returns all the transformers that can operate on the IR
 func GetTransformers() []Transformer {
-	return []Transformer{new(TektonTransformer), NewBuildconfigTransformer(), new(KnativeTransformer), NewK8sTransformer()}
+	return []Transformer{new(TektonTransformer), new(GitHubActionsTransformer), new(DevWorkflowTransformer), NewBuildconfigTransformer(), new(KnativeTransformer), NewK8sTransformer()}
+}
+
+// GetTargetTransformers returns the transformers generating the artifacts of an output target
+func GetTargetTransformers(targetType plantypes.OutputTargetTypeValue) []Transformer {
+	switch targetType {
+	case plantypes.KnativeOutputTargetType:
+		return []Transformer{&KnativeTransformer{WriteContainers: true}}
+	case plantypes.HelmOutputTargetType:
+		kt := NewK8sTransformer()
+		kt.HelmOnly = true
+		return []Transformer{kt}
+	}
+	return GetTransformers()
 }
 
 // ConvertIRToObjects converts IR to a runtime objects
@@ -89,6 +120,28 @@ func convertIRToObjects(ir irtypes.EnhancedIR, apis []apiresource.IAPIResource)
 	return targetObjs
 }
 
+// scanSourcesForSecrets scans the sources for credentials before they are copied into the build contexts, and reports them.
+// It returns false if the sources should not be copied.
+func scanSourcesForSecrets(rootDir string) bool {
+	if common.SecretScanMode == common.OffSecretScanMode {
+		return true
+	}
+	findings, err := secretscan.Scan(rootDir)
+	if err != nil {
+		log.Warnf("Failed to scan the sources in the directory %s for secrets. Error: %q", rootDir, err)
+	}
+	if len(findings) == 0 {
+		return true
+	}
+	block := common.SecretScanMode == common.BlockSecretScanMode
+	for _, finding := range findings {
+		codedErr := common.NewError(common.SecretsFoundErrorCode, nil, "%s", finding)
+		codedErr.Warning = !block
+		common.ReportError(codedErr)
+	}
+	return !block
+}
+
 // writeContainers returns true if any scripts were written
 func writeContainers(containers []irtypes.Container, outputPath, rootDir, registryURL, registryNamespace string) bool {
 	sourcePath := filepath.Join(outputPath, common.SourceDir)
@@ -180,9 +233,11 @@ func writeContainers(containers []irtypes.Container, outputPath, rootDir, regist
 
 		// copy all the sources into source/
 		sourcePath := filepath.Join(outputPath, common.SourceDir)
-		if err := os.MkdirAll(sourcePath, common.DefaultDirectoryPermission); err != nil {
+		if !scanSourcesForSecrets(rootDir) {
+			log.Errorf("The sources were not copied over to the folder at path %s , since they contain credentials.", sourcePath)
+		} else if err := os.MkdirAll(sourcePath, common.DefaultDirectoryPermission); err != nil {
 			log.Errorf("Failed to create the source directory at path %s . Error: %q", sourcePath, err)
-		} else if err := copy.Copy(rootDir, sourcePath); err != nil {
+		} else if err := common.CopyPath(sourcePath, rootDir); err != nil {
 			log.Errorf("Failed to copy the sources over to the folder at path %s Error: %q", sourcePath, err)
 		}
 	}
@@ -211,7 +266,7 @@ func fixAndConvert(obj runtime.Object, clusterSpec collecttypes.ClusterMetadataS
 }
 
 // fixConvertAndTransformObjs runs fixers, converts to a supported version and runs transformations on the objects
-func fixConvertAndTransformObjs(objs []runtime.Object, clusterSpec collecttypes.ClusterMetadataSpec, ignoreUnsupportedKinds bool, transformPaths []string) ([]runtime.Object, error) {
+func fixConvertAndTransformObjs(objs []runtime.Object, clusterSpec collecttypes.ClusterMetadataSpec, ignoreUnsupportedKinds bool, transformPaths []string, violations *violationReporter) ([]runtime.Object, error) {
 	// Fix and convert
 	fixedAndConvertedObjs := []runtime.Object{}
 	for _, obj := range objs {
@@ -245,6 +300,8 @@ func fixConvertAndTransformObjs(objs []runtime.Object, clusterSpec collecttypes.
 		log.Errorf("Failed to apply the transformations. Error: %q", err)
 		return nil, err
 	}
+	// Check the compliance with the constraints of the target cluster
+	violations.report(compliance.Check(transformedK8sResources, clusterSpec.Constraints))
 	fixedConvertedAndTransformedObjs := []runtime.Object{}
 	for i, transformedK8sResource := range transformedK8sResources {
 		fixedConvertedAndTransformedObj, err := gettransformdata.GetObjectFromK8sResource(transformedK8sResource, fixedAndConvertedObjs[i])
@@ -257,6 +314,55 @@ func fixConvertAndTransformObjs(objs []runtime.Object, clusterSpec collecttypes.
 	return fixedConvertedAndTransformedObjs, nil
 }
 
+// violationReporter reports the violations of the constraints of the target cluster of a translation.
+// Each violation is reported only once, since the same objects are written in multiple formats.
+type violationReporter struct {
+	reportedMutex sync.Mutex
+	reported      map[string]bool
+}
+
+func newViolationReporter() *violationReporter {
+	return &violationReporter{reported: map[string]bool{}}
+}
+
+// violationReporterSetter is implemented by the transformers which check the objects they write against the constraints
+type violationReporterSetter interface {
+	setViolationReporter(violations *violationReporter)
+}
+
+// complianceReporting is embedded by the transformers to share the violation reporter of the translation
+type complianceReporting struct {
+	violations *violationReporter
+}
+
+func (c *complianceReporting) setViolationReporter(violations *violationReporter) {
+	c.violations = violations
+}
+
+// report records the violations for the report of the translation. A nil reporter reports all the violations.
+func (r *violationReporter) report(violations []compliance.Violation) {
+	for _, violation := range violations {
+		msg := violation.String()
+		if r != nil && !r.markReported(msg) {
+			continue
+		}
+		err := common.NewError(common.ConstraintViolationErrorCode, nil, "%s", msg)
+		err.Warning = violation.Fixed
+		common.ReportError(err)
+	}
+}
+
+// markReported returns false if the violation was already reported
+func (r *violationReporter) markReported(msg string) bool {
+	r.reportedMutex.Lock()
+	defer r.reportedMutex.Unlock()
+	if r.reported[msg] {
+		return false
+	}
+	r.reported[msg] = true
+	return true
+}
+
 // writeObjects writes the runtime objects to yaml files
 func writeObjects(outputPath string, objs []runtime.Object) ([]string, error) {
 	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
@@ -286,8 +392,8 @@ func getFilename(obj runtime.Object) string {
 	return fmt.Sprintf("%s-%s.yaml", objectMeta.Name, strings.ToLower(typeMeta.Kind))
 }
 
-func writeTransformedObjects(outputPath string, objs []runtime.Object, clusterSpec collecttypes.ClusterMetadataSpec, ignoreUnsupportedKinds bool, transformPaths []string) ([]string, error) {
-	fixedConvertedAndTransformedObjs, err := fixConvertAndTransformObjs(objs, clusterSpec, ignoreUnsupportedKinds, transformPaths)
+func writeTransformedObjects(outputPath string, objs []runtime.Object, clusterSpec collecttypes.ClusterMetadataSpec, ignoreUnsupportedKinds bool, transformPaths []string, violations *violationReporter) ([]string, error) {
+	fixedConvertedAndTransformedObjs, err := fixConvertAndTransformObjs(objs, clusterSpec, ignoreUnsupportedKinds, transformPaths, violations)
 	if err != nil {
 		log.Errorf("Failed to fix, convert and transform objects. Error: %q", err)
 		return nil, err
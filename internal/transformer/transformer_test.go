@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/transformer/compliance"
+	collecttypes "github.com/konveyor/move2kube/types/collection"
+)
+
+func TestViolationReporter(t *testing.T) {
+	violations := []compliance.Violation{
+		{Kind: "Deployment", Name: "web", Restriction: collecttypes.PrivilegedRestriction, Message: "the container web is privileged", Fixed: true},
+		{Kind: "Deployment", Name: "web", Restriction: collecttypes.HostPathRestriction, Message: "the volume data is a hostPath volume"},
+	}
+	getNewErrors := func(report func()) []*common.Error {
+		before := len(common.GetReportedErrors())
+		report()
+		return common.GetReportedErrors()[before:]
+	}
+
+	t.Run("violations are reported as errors of the translation", func(t *testing.T) {
+		errs := getNewErrors(func() { newViolationReporter().report(violations) })
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 reported errors. Actual: %+v", errs)
+		}
+		for i, err := range errs {
+			if err.Code != common.ConstraintViolationErrorCode || err.Message != violations[i].String() || err.Warning != violations[i].Fixed {
+				t.Fatalf("Expected the error of the violation %+v . Actual: %+v", violations[i], err)
+			}
+		}
+	})
+	t.Run("violations are reported once per translation", func(t *testing.T) {
+		reporter := newViolationReporter()
+		errs := getNewErrors(func() {
+			reporter.report(violations)
+			reporter.report(violations)
+		})
+		if len(errs) != 2 {
+			t.Fatalf("Expected the violations to be reported once. Actual: %+v", errs)
+		}
+		if errs := getNewErrors(func() { newViolationReporter().report(violations) }); len(errs) != 2 {
+			t.Fatalf("Expected the violations to be reported again in a new translation. Actual: %+v", errs)
+		}
+	})
+	t.Run("transformers share the reporter of the translation", func(t *testing.T) {
+		kt, knt := NewK8sTransformer(), new(KnativeTransformer)
+		reporter := newViolationReporter()
+		for _, transformer := range []Transformer{kt, knt, new(TektonTransformer), NewBuildconfigTransformer()} {
+			setter, ok := transformer.(violationReporterSetter)
+			if !ok {
+				t.Fatalf("Expected the transformer %T to check the constraints of the target cluster", transformer)
+			}
+			setter.setViolationReporter(reporter)
+		}
+		if kt.violations != reporter || knt.violations != reporter {
+			t.Fatalf("Expected the transformers to use the reporter of the translation")
+		}
+	})
+}
@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/internal/transformer/templates"
+	irtypes "github.com/konveyor/move2kube/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	volumeMigrationDir        = "volume-migration"
+	volumeMigrationSuffix     = "-migration"
+	snapshotAPIGroup          = "snapshot.storage.k8s.io"
+	snapshotAPIVersion        = snapshotAPIGroup + "/v1beta1"
+	veleroAPIVersion          = "velero.io/v1"
+	veleroNamespace           = "velero"
+	veleroBackupSchedule      = "0 * * * *"
+	snapshotDriverPlaceholder = "<csi driver>"
+	snapshotHandlePlaceholder = "<snapshot handle>"
+)
+
+// veleroResources are the resources backed up and restored by velero
+var veleroResources = []string{"persistentvolumeclaims", "persistentvolumes"}
+
+// writeVolumeMigrations writes the manifests that move the data of the persistent volume claims from the source cluster
+// to the target cluster, using either CSI volume snapshots or velero backups, along with a readme explaining their usage.
+func writeVolumeMigrations(outputPath string, migrations []irtypes.VolumeMigration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	// deploy/volume-migration/
+	migrationPath := filepath.Join(outputPath, common.DeployDir, volumeMigrationDir)
+	files := map[string]map[string]interface{}{} // [path relative to the migration directory][manifest]
+	namespaces := map[string]map[string]string{} // [namespace][source storage class][target storage class]
+	for _, migration := range migrations {
+		snapshotName := migration.ClaimName + volumeMigrationSuffix
+		contentName := migration.Namespace + "-" + snapshotName
+		files[filepath.Join("snapshot", "source", snapshotName+"-volumesnapshot.yaml")] = map[string]interface{}{
+			"apiVersion": snapshotAPIVersion,
+			"kind":       "VolumeSnapshot",
+			"metadata":   map[string]interface{}{"name": snapshotName, "namespace": migration.Namespace},
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{"persistentVolumeClaimName": migration.ClaimName},
+			},
+		}
+		// The snapshot taken in the source cluster is imported in the target cluster as a pre-provisioned snapshot
+		files[filepath.Join("snapshot", "target", contentName+"-volumesnapshotcontent.yaml")] = map[string]interface{}{
+			"apiVersion": snapshotAPIVersion,
+			"kind":       "VolumeSnapshotContent",
+			"metadata":   map[string]interface{}{"name": contentName},
+			"spec": map[string]interface{}{
+				"deletionPolicy":    "Retain",
+				"driver":            snapshotDriverPlaceholder,
+				"source":            map[string]interface{}{"snapshotHandle": snapshotHandlePlaceholder},
+				"volumeSnapshotRef": map[string]interface{}{"name": snapshotName, "namespace": migration.Namespace},
+			},
+		}
+		files[filepath.Join("snapshot", "target", snapshotName+"-volumesnapshot.yaml")] = map[string]interface{}{
+			"apiVersion": snapshotAPIVersion,
+			"kind":       "VolumeSnapshot",
+			"metadata":   map[string]interface{}{"name": snapshotName, "namespace": migration.Namespace},
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{"volumeSnapshotContentName": contentName},
+			},
+		}
+		files[filepath.Join("snapshot", "target", migration.ClaimName+"-persistentvolumeclaim.yaml")] = getRestoredClaim(migration, snapshotName)
+		if _, ok := namespaces[migration.Namespace]; !ok {
+			namespaces[migration.Namespace] = map[string]string{}
+		}
+		if migration.SourceStorageClass != "" && migration.TargetStorageClass != "" && migration.SourceStorageClass != migration.TargetStorageClass {
+			namespaces[migration.Namespace][migration.SourceStorageClass] = migration.TargetStorageClass
+		}
+	}
+	storageClassChanges := map[string]interface{}{}
+	for namespace, changes := range namespaces {
+		scheduleName := namespace + "-volumes"
+		files[filepath.Join("velero", "source", scheduleName+"-schedule.yaml")] = map[string]interface{}{
+			"apiVersion": veleroAPIVersion,
+			"kind":       "Schedule",
+			"metadata":   map[string]interface{}{"name": scheduleName, "namespace": veleroNamespace},
+			"spec": map[string]interface{}{
+				"schedule": veleroBackupSchedule,
+				"template": map[string]interface{}{
+					"includedNamespaces": []string{namespace},
+					"includedResources":  veleroResources,
+					"snapshotVolumes":    true,
+				},
+			},
+		}
+		files[filepath.Join("velero", "target", scheduleName+"-restore.yaml")] = map[string]interface{}{
+			"apiVersion": veleroAPIVersion,
+			"kind":       "Restore",
+			"metadata":   map[string]interface{}{"name": scheduleName, "namespace": veleroNamespace},
+			"spec": map[string]interface{}{
+				"scheduleName":       scheduleName,
+				"includedNamespaces": []string{namespace},
+				"includedResources":  veleroResources,
+				"restorePVs":         true,
+			},
+		}
+		for source, target := range changes {
+			storageClassChanges[source] = target
+		}
+	}
+	if len(storageClassChanges) > 0 {
+		// The velero plugin config that changes the storage classes of the restored claims
+		files[filepath.Join("velero", "target", "change-storage-class-config-configmap.yaml")] = map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "change-storage-class-config",
+				"namespace": veleroNamespace,
+				"labels": map[string]interface{}{
+					"velero.io/plugin-config":        "",
+					"velero.io/change-storage-class": "RestoreItemAction",
+				},
+			},
+			"data": storageClassChanges,
+		}
+	}
+	relPaths := []string{}
+	for relPath := range files {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+	for _, relPath := range relPaths {
+		filePath := filepath.Join(migrationPath, relPath)
+		if err := os.MkdirAll(filepath.Dir(filePath), common.DefaultDirectoryPermission); err != nil {
+			log.Errorf("Unable to create directory %s : %s", filepath.Dir(filePath), err)
+			return err
+		}
+		if err := common.WriteYaml(filePath, files[relPath]); err != nil {
+			log.Errorf("Failed to write the volume migration manifest at path %s . Error: %q", filePath, err)
+			return err
+		}
+	}
+	readmePath := filepath.Join(migrationPath, "README.md")
+	if err := common.WriteTemplateToFile(templates.VolumeMigration_md, struct {
+		Claims            []irtypes.VolumeMigration
+		DriverPlaceholder string
+		HandlePlaceholder string
+	}{
+		Claims:            migrations,
+		DriverPlaceholder: snapshotDriverPlaceholder,
+		HandlePlaceholder: snapshotHandlePlaceholder,
+	}, readmePath, common.DefaultFilePermission); err != nil {
+		log.Errorf("Failed to write the volume migration readme at path %s . Error: %q", readmePath, err)
+		return err
+	}
+	log.Infof("The data of the persistent volume claims has to be moved to the target cluster. Refer to %s", readmePath)
+	return nil
+}
+
+// getRestoredClaim returns the persistent volume claim that restores the snapshot in the target cluster
+func getRestoredClaim(migration irtypes.VolumeMigration, snapshotName string) map[string]interface{} {
+	spec := map[string]interface{}{
+		"resources": map[string]interface{}{"requests": map[string]interface{}{"storage": migration.Size}},
+		"dataSource": map[string]interface{}{
+			"apiGroup": snapshotAPIGroup,
+			"kind":     "VolumeSnapshot",
+			"name":     snapshotName,
+		},
+	}
+	if len(migration.AccessModes) > 0 {
+		spec["accessModes"] = migration.AccessModes
+	}
+	if migration.TargetStorageClass != "" {
+		spec["storageClassName"] = migration.TargetStorageClass
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       string(irtypes.PVCKind),
+		"metadata":   map[string]interface{}{"name": migration.ClaimName, "namespace": migration.Namespace},
+		"spec":       spec,
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package argorollouts contains the subset of the Argo Rollouts (argoproj.io) types generated by move2kube
+package argorollouts
+
+import (
+	"github.com/konveyor/move2kube/internal/common/deepcopy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// RolloutKind is the kind of a Rollout
+	RolloutKind = "Rollout"
+	// AnalysisTemplateKind is the kind of an AnalysisTemplate
+	AnalysisTemplateKind = "AnalysisTemplate"
+)
+
+// SchemeGroupVersion is the group version of the Argo Rollouts resources
+var SchemeGroupVersion = schema.GroupVersion{Group: "argoproj.io", Version: "v1alpha1"}
+
+// Rollout is a replacement for a Deployment that supports canary and blue green updates
+type Rollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RolloutSpec `json:"spec"`
+}
+
+// RolloutSpec is the spec for a Rollout
+type RolloutSpec struct {
+	Replicas *int32                 `json:"replicas,omitempty"`
+	Selector *metav1.LabelSelector  `json:"selector"`
+	Template corev1.PodTemplateSpec `json:"template"`
+	Strategy RolloutStrategy        `json:"strategy"`
+}
+
+// RolloutStrategy defines the strategy to update the pods of a Rollout. Only one of the strategies should be set.
+type RolloutStrategy struct {
+	BlueGreen *BlueGreenStrategy `json:"blueGreen,omitempty"`
+	Canary    *CanaryStrategy    `json:"canary,omitempty"`
+}
+
+// BlueGreenStrategy switches the active service to the new version once it has been promoted
+type BlueGreenStrategy struct {
+	ActiveService         string           `json:"activeService"`
+	PreviewService        string           `json:"previewService,omitempty"`
+	AutoPromotionEnabled  *bool            `json:"autoPromotionEnabled,omitempty"`
+	PrePromotionAnalysis  *RolloutAnalysis `json:"prePromotionAnalysis,omitempty"`
+	PostPromotionAnalysis *RolloutAnalysis `json:"postPromotionAnalysis,omitempty"`
+}
+
+// CanaryStrategy shifts the traffic to the new version step by step
+type CanaryStrategy struct {
+	CanaryService string       `json:"canaryService,omitempty"`
+	StableService string       `json:"stableService,omitempty"`
+	Steps         []CanaryStep `json:"steps,omitempty"`
+}
+
+// CanaryStep is a single step of a canary update. Only one of the fields should be set.
+type CanaryStep struct {
+	SetWeight *int32           `json:"setWeight,omitempty"`
+	Pause     *RolloutPause    `json:"pause,omitempty"`
+	Analysis  *RolloutAnalysis `json:"analysis,omitempty"`
+}
+
+// RolloutPause pauses the update for the duration or, if the duration is empty, until it is promoted
+type RolloutPause struct {
+	Duration string `json:"duration,omitempty"`
+}
+
+// RolloutAnalysis runs the analysis templates during an update
+type RolloutAnalysis struct {
+	Templates []RolloutAnalysisTemplate `json:"templates"`
+	Args      []AnalysisRunArgument     `json:"args,omitempty"`
+}
+
+// RolloutAnalysisTemplate refers to an AnalysisTemplate in the same namespace
+type RolloutAnalysisTemplate struct {
+	TemplateName string `json:"templateName"`
+}
+
+// AnalysisRunArgument is the value of an argument of an AnalysisTemplate
+type AnalysisRunArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *Rollout) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*Rollout)
+}
+
+// AnalysisTemplate holds the metrics that decide whether an update is successful
+type AnalysisTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              AnalysisTemplateSpec `json:"spec"`
+}
+
+// AnalysisTemplateSpec is the spec for an AnalysisTemplate
+type AnalysisTemplateSpec struct {
+	Args    []Argument `json:"args,omitempty"`
+	Metrics []Metric   `json:"metrics"`
+}
+
+// Argument is an argument of an AnalysisTemplate
+type Argument struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
+}
+
+// Metric is a measurement taken during an analysis
+type Metric struct {
+	Name             string         `json:"name"`
+	Interval         string         `json:"interval,omitempty"`
+	Count            *int32         `json:"count,omitempty"`
+	SuccessCondition string         `json:"successCondition,omitempty"`
+	FailureLimit     *int32         `json:"failureLimit,omitempty"`
+	Provider         MetricProvider `json:"provider"`
+}
+
+// MetricProvider is the source of a metric. Only one of the providers should be set.
+type MetricProvider struct {
+	Web *WebMetric `json:"web,omitempty"`
+}
+
+// WebMetric gets the metric from a HTTP endpoint that returns JSON
+type WebMetric struct {
+	URL            string `json:"url"`
+	JSONPath       string `json:"jsonPath,omitempty"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *AnalysisTemplate) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*AnalysisTemplate)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dapr contains the subset of the Dapr (dapr.io) types generated by move2kube
+package dapr
+
+import (
+	"github.com/konveyor/move2kube/internal/common/deepcopy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ComponentKind is the kind of a Component
+	ComponentKind = "Component"
+)
+
+// SchemeGroupVersion is the group version of the Dapr resources
+var SchemeGroupVersion = schema.GroupVersion{Group: "dapr.io", Version: "v1alpha1"}
+
+// Component connects the Dapr sidecars to a backing service, like a state store or a pub/sub broker
+type Component struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ComponentSpec `json:"spec"`
+}
+
+// ComponentSpec is the spec for a Component
+type ComponentSpec struct {
+	Type     string         `json:"type"`
+	Version  string         `json:"version"`
+	Metadata []MetadataItem `json:"metadata,omitempty"`
+}
+
+// MetadataItem is a setting of a component. Only one of the value and the secret key ref should be set.
+type MetadataItem struct {
+	Name         string        `json:"name"`
+	Value        string        `json:"value,omitempty"`
+	SecretKeyRef *SecretKeyRef `json:"secretKeyRef,omitempty"`
+}
+
+// SecretKeyRef refers to a key of a secret in the same namespace
+type SecretKeyRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *Component) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*Component)
+}
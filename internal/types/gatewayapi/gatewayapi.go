@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gatewayapi contains the subset of the Kubernetes Gateway API (gateway.networking.k8s.io) types generated by move2kube
+package gatewayapi
+
+import (
+	"github.com/konveyor/move2kube/internal/common/deepcopy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GatewayKind is the kind of a Gateway
+	GatewayKind = "Gateway"
+	// HTTPRouteKind is the kind of a HTTPRoute
+	HTTPRouteKind = "HTTPRoute"
+	// PathMatchPathPrefix matches based on a URL path prefix split by /
+	PathMatchPathPrefix = "PathPrefix"
+	// HTTPProtocolType accepts cleartext HTTP/1.1 sessions over TCP
+	HTTPProtocolType = "HTTP"
+	// HTTPSProtocolType accepts HTTP/1.1 or HTTP/2 sessions over TLS
+	HTTPSProtocolType = "HTTPS"
+)
+
+// SchemeGroupVersion is the group version of the Gateway API resources
+var SchemeGroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1"}
+
+// Versions are the versions of the Gateway API having the fields generated by move2kube, from the most preferred
+var Versions = []string{"v1", "v1beta1"}
+
+// GetPreferredGroupVersion returns the most preferred version of the Gateway API in the group versions supported by the cluster for a kind.
+// It returns false if the cluster supports none of them.
+func GetPreferredGroupVersion(supportedGroupVersions []string) (schema.GroupVersion, bool) {
+	for _, version := range Versions {
+		gv := schema.GroupVersion{Group: SchemeGroupVersion.Group, Version: version}
+		for _, supportedGroupVersion := range supportedGroupVersions {
+			if supportedGroupVersion == gv.String() {
+				return gv, true
+			}
+		}
+	}
+	return SchemeGroupVersion, false
+}
+
+// Gateway represents an instance of a service-traffic handling infrastructure by binding Listeners to a set of IP addresses
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              GatewaySpec `json:"spec"`
+}
+
+// GatewaySpec defines the desired state of a Gateway
+type GatewaySpec struct {
+	GatewayClassName string     `json:"gatewayClassName"`
+	Listeners        []Listener `json:"listeners"`
+}
+
+// Listener embodies the concept of a logical endpoint where a Gateway accepts network connections
+type Listener struct {
+	Name     string            `json:"name"`
+	Hostname string            `json:"hostname,omitempty"`
+	Port     int32             `json:"port"`
+	Protocol string            `json:"protocol"`
+	TLS      *GatewayTLSConfig `json:"tls,omitempty"`
+}
+
+// GatewayTLSConfig describes a TLS configuration of a Listener
+type GatewayTLSConfig struct {
+	CertificateRefs []SecretObjectReference `json:"certificateRefs,omitempty"`
+}
+
+// SecretObjectReference identifies a Secret containing the TLS certificate
+type SecretObjectReference struct {
+	Name string `json:"name"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *Gateway) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*Gateway)
+}
+
+// HTTPRoute provides a way to route HTTP requests from a Gateway to a backend
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              HTTPRouteSpec `json:"spec"`
+}
+
+// HTTPRouteSpec defines the desired state of a HTTPRoute
+type HTTPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+// ParentReference identifies the Gateway that a HTTPRoute attaches to
+type ParentReference struct {
+	Name        string `json:"name"`
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// HTTPRouteRule defines the conditions and the backends of a route
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch `json:"matches,omitempty"`
+	BackendRefs []HTTPBackendRef `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteMatch defines the predicate used to match requests to a given action
+type HTTPRouteMatch struct {
+	Path *HTTPPathMatch `json:"path,omitempty"`
+}
+
+// HTTPPathMatch describes how to select a HTTP route by matching the HTTP request path
+type HTTPPathMatch struct {
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// HTTPBackendRef defines how a HTTPRoute forwards a HTTP request to a Service
+type HTTPBackendRef struct {
+	Name string `json:"name"`
+	Port int32  `json:"port,omitempty"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *HTTPRoute) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*HTTPRoute)
+}
@@ -28,7 +28,9 @@ import (
 	"github.com/konveyor/move2kube/types/plan"
 	plantypes "github.com/konveyor/move2kube/types/plan"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	core "k8s.io/kubernetes/pkg/apis/core"
 	networking "k8s.io/kubernetes/pkg/apis/networking"
 )
@@ -42,6 +44,8 @@ type IR struct {
 	Storages   []Storage
 
 	Kubernetes plan.KubernetesOutput
+	// Profiles contains the environments for which kustomize overlays are generated
+	Profiles []plan.Profile
 
 	TargetClusterSpec collecttypes.ClusterMetadataSpec
 	CachedObjects     []runtime.Object
@@ -49,8 +53,147 @@ type IR struct {
 	Values outputtypes.HelmValues
 
 	IngressTLSSecretName string
+
+	// RegistryUsernames contains the usernames used to pull the existing images from the registries, like the docker username of cf apps
+	RegistryUsernames map[string]string
+
+	// ImageMirrors contains the images that have to be copied to the new registry, since the artifacts were rewritten to use the copies
+	ImageMirrors map[string]string // [source image][target image]
+
+	// HostPathRemediations contains the decisions taken for the host path volumes, which are recorded in the plan
+	HostPathRemediations map[string]plantypes.HostPathRemediationTypeValue // [host path][remediation]
+
+	// VolumeMigrations contains the persistent volume claims of the source cluster whose data has to be moved to the target cluster
+	VolumeMigrations []VolumeMigration
+
+	// StaticSiteSyncs contains the static sites which are uploaded to buckets instead of being deployed as services
+	StaticSiteSyncs []StaticSiteSync
+
+	// IngressNginxPorts contains the non HTTP ports exposed using the tcp-services and udp-services config maps of ingress-nginx
+	IngressNginxPorts []IngressNginxPort
+
+	// IngressController is the controller implementing the ingresses, which decides the annotations used to configure them
+	IngressController IngressControllerType
+	IngressTimeout    int    // Seconds after which the idle connections to the backends are closed
+	IngressBodySize   string // Maximum size of the request bodies, like 10m
+
+	// Gateway API is used instead of Ingress if the gateway class name is set
+	GatewayClassName string
+	SharedGateway    bool
+
+	// Argo Rollouts are used instead of Deployments if the strategy is canary or blue green
+	DeploymentStrategy DeploymentStrategyType
+	MetricsPath        string
+
+	// DaprComponents contains the Dapr components, like the state stores and the pub/sub brokers, used by the services with a Dapr sidecar
+	DaprComponents []DaprComponent
+
+	// DevTools are the tools, like Skaffold, for which the configs of the inner loop developer workflow are generated
+	DevTools []DevToolType
+}
+
+// DevToolType is a tool which builds and deploys the services on each change during development
+type DevToolType string
+
+const (
+	// SkaffoldDevTool generates skaffold.yaml
+	SkaffoldDevTool DevToolType = "Skaffold"
+	// TiltDevTool generates a Tiltfile
+	TiltDevTool DevToolType = "Tilt"
+	// DevSpaceDevTool generates devspace.yaml
+	DevSpaceDevTool DevToolType = "DevSpace"
+	// ComposeDevTool generates docker-compose.dev.yaml, which runs the services locally with the source mounted
+	ComposeDevTool DevToolType = "DockerCompose"
+	// DevContainerDevTool generates .devcontainer/devcontainer.json, which opens the source in a service of docker-compose.dev.yaml
+	DevContainerDevTool DevToolType = "DevContainer"
+)
+
+// DaprComponent connects the Dapr sidecars to a backing service
+type DaprComponent struct {
+	Name     string
+	Type     string // Type of the component, like state.redis or pubsub.kafka
+	Version  string
+	Metadata []DaprMetadata
+	TODO     string // Steps to be done manually before deploying the component
+}
+
+// DaprMetadata is a setting of a Dapr component, whose value is read from the key of a secret if the secret name is set
+type DaprMetadata struct {
+	Name       string
+	Value      string
+	SecretName string
+	SecretKey  string
+}
+
+// DeploymentStrategyType is the strategy used to update the services to a new version
+type DeploymentStrategyType string
+
+const (
+	// RollingUpdateDeploymentStrategy replaces the pods gradually using a Deployment
+	RollingUpdateDeploymentStrategy DeploymentStrategyType = "RollingUpdate"
+	// CanaryDeploymentStrategy shifts the traffic to the new version step by step using an Argo Rollout
+	CanaryDeploymentStrategy DeploymentStrategyType = "Canary"
+	// BlueGreenDeploymentStrategy switches the traffic to the new version once it has been promoted using an Argo Rollout
+	BlueGreenDeploymentStrategy DeploymentStrategyType = "BlueGreen"
+)
+
+// IngressControllerType is the ingress controller of the target cluster
+type IngressControllerType string
+
+const (
+	// NginxIngressController is the NGINX ingress controller, configured using annotations on the ingress
+	NginxIngressController IngressControllerType = "nginx"
+	// HAProxyIngressController is the HAProxy ingress controller, configured using annotations on the ingress
+	HAProxyIngressController IngressControllerType = "haproxy"
+	// TraefikIngressController is the Traefik ingress controller, configured using annotations on the services and middlewares
+	TraefikIngressController IngressControllerType = "traefik"
+	// ALBIngressController is the AWS Load Balancer Controller, which provisions an application load balancer for the ingress
+	ALBIngressController IngressControllerType = "alb"
+	// IstioIngressController is the Istio ingress gateway, configured using VirtualServices and DestinationRules
+	IstioIngressController IngressControllerType = "istio"
+	// OtherIngressController is any other ingress controller, which is not configured using annotations
+	OtherIngressController IngressControllerType = "other"
+)
+
+// PortExposureType is how the non HTTP ports of a service, like the ports of databases and MQTT brokers, are exposed
+type PortExposureType string
+
+const (
+	// ClusterIPPortExposure exposes the ports only inside the cluster
+	ClusterIPPortExposure PortExposureType = "ClusterIP"
+	// LoadBalancerPortExposure exposes the ports using another service of type LoadBalancer
+	LoadBalancerPortExposure PortExposureType = "LoadBalancer"
+	// NodePortPortExposure exposes the ports using another service of type NodePort
+	NodePortPortExposure PortExposureType = "NodePort"
+	// IngressNginxPortExposure exposes the ports on the ingress-nginx controller using its tcp-services and udp-services config maps
+	IngressNginxPortExposure PortExposureType = "IngressNginx"
+)
+
+// NonHTTPPort is a port of a service which does not serve HTTP
+type NonHTTPPort struct {
+	Number   int32
+	Protocol core.Protocol
+	Hint     string // Why the port is not considered HTTP, like the well known service using the port
 }
 
+// IngressNginxPort is a non HTTP port of a service exposed on the same port of the ingress-nginx controller
+type IngressNginxPort struct {
+	ServiceName string
+	NonHTTPPort
+}
+
+// ServiceProtocol is the application protocol served by a service, when it is not plain HTTP
+type ServiceProtocol string
+
+const (
+	// HTTPServiceProtocol is plain HTTP
+	HTTPServiceProtocol ServiceProtocol = ""
+	// GRPCServiceProtocol is gRPC, which requires HTTP/2 up to the pods
+	GRPCServiceProtocol ServiceProtocol = "grpc"
+	// WebSocketServiceProtocol is WebSocket, whose long lived connections must not be closed by the proxies
+	WebSocketServiceProtocol ServiceProtocol = "websocket"
+)
+
 // EnhancedIR is IR with extra data specific to API resource sets
 type EnhancedIR struct {
 	IR
@@ -82,9 +225,93 @@ type Service struct {
 	ServiceToPodPortForwardings []ServiceToPodPortForwarding
 	Replicas                    int
 	Networks                    []string
-	ServiceRelPath              string //Ingress fan-out path
+	ServiceRelPath              string   //Ingress fan-out path
+	IngressPaths                []string // Other ingress fan-out paths of the service, like the paths of the handlers of the source platform
 	OnlyIngress                 bool
-	Daemon                      bool //Gets converted to DaemonSet
+	Daemon                      bool            //Gets converted to DaemonSet
+	SessionHints                []string        // Hints found in the source that the app keeps user sessions in memory
+	ShutdownHints               []string        // Hints found in the source that the app shuts down gracefully on SIGTERM
+	StickySessions              bool            // Route the requests of a client to the same pod
+	WildcardHosts               []string        // Wildcard hosts, like *.example.com, on which the service is exposed in addition to the cluster host
+	Autoscaling                 *Autoscaling    // Optional field to scale the service horizontally
+	ContainerConcurrency        int             // Maximum number of concurrent requests served by each pod. Zero means no limit.
+	UpdateStrategy              *UpdateStrategy // Optional field to set how the pods of a deployment are replaced by a new version
+	BackoffLimit                *int32          // Optional field to set the number of retries of the pods of a job
+
+	ProtocolHints []string        // Hints found in the source that the app serves gRPC or WebSocket
+	Protocol      ServiceProtocol // The protocol served on the ports of the service
+
+	NonHTTPPorts    []NonHTTPPort    // Ports serving TCP or UDP protocols other than HTTP
+	NonHTTPExposure PortExposureType // How the non HTTP ports are exposed outside the cluster
+
+	CronEntries []CronEntry // Entries of the crontab run by cron in the image of the service
+	Schedule    string      // Cron schedule of the service. If set, the service is run as a CronJob.
+
+	ProcessManager string    // Process manager, like supervisord or foreman, which runs several processes in the container of the service
+	Processes      []Process // Processes run by the process manager
+
+	DaprHints []string // Hints found in the source that the app uses Dapr
+
+	ContextRoot string // Context root of the JEE app, from its deployment descriptors, which is the default path of the ingress
+
+	DependsOn []string // Services which have to be ready before the service is started
+
+	Ports []plantypes.Port // Ports of the service in the plan, with how they are exposed
+}
+
+// Process is a process run by a process manager in the container of a service
+type Process struct {
+	Name       string
+	Command    string
+	WorkingDir string
+	Env        []core.EnvVar
+}
+
+// CronEntry is an entry of a crontab, which runs the command on the schedule
+type CronEntry struct {
+	Schedule string
+	Command  string
+	Env      []core.EnvVar // Environment variables set in the crontab
+}
+
+// Autoscaling defines the bounds and the metrics used to scale a service horizontally
+type Autoscaling struct {
+	MinReplicas      int
+	MaxReplicas      int
+	Metrics          []AutoscalingMetric
+	Triggers         []AutoscalingTrigger // Event sources, like queues, on which the service is scaled using KEDA
+	UnsupportedRules []string             // Rules of the source platform which could not be converted to metrics
+}
+
+// UpdateStrategy defines how the pods of a deployment are replaced by a new version. The pods are replaced in batches
+// bounded by the surge and the unavailable pods, unless they are all recreated at once.
+type UpdateStrategy struct {
+	Recreate        bool
+	MaxSurge        intstr.IntOrString
+	MaxUnavailable  intstr.IntOrString
+	MinReadySeconds int32 // Time for which a new pod has to be ready before the next pods are replaced
+}
+
+// AutoscalingTrigger is a KEDA scaler, like azure-servicebus, with its metadata and the secrets holding its credentials
+type AutoscalingTrigger struct {
+	Name       string
+	Type       string
+	Metadata   map[string]string
+	SecretRefs []AutoscalingSecretRef
+}
+
+// AutoscalingSecretRef sets a parameter of a trigger, like connection, to the value of a key of a secret
+type AutoscalingSecretRef struct {
+	Parameter  string
+	SecretName string
+	Key        string
+}
+
+// AutoscalingMetric is a resource whose average usage across the pods is kept at the target
+type AutoscalingMetric struct {
+	Resource           core.ResourceName
+	AverageUtilization int32              // Percentage of the resource requests of the pods. Used when AverageValue is not set.
+	AverageValue       *resource.Quantity // Absolute value of the resource
 }
 
 // Port is a port number with an optional port name.
@@ -119,6 +346,25 @@ type Storage struct {
 	StorageType                    StorageKindType   //Type of storage cfgmap, secret, pvc
 	SecretType                     core.SecretType   // Optional field to store the type of secret data
 	Content                        map[string][]byte //Optional field meant to store content for cfgmap or secret
+	Immutable                      bool              //Optional field to make the cfgmap immutable
+}
+
+// VolumeMigration holds the details of a persistent volume claim whose data is moved from the source cluster to the target cluster
+type VolumeMigration struct {
+	ClaimName          string
+	Namespace          string
+	SourceStorageClass string
+	TargetStorageClass string
+	Size               string
+	AccessModes        []string
+}
+
+// StaticSiteSync holds the details of a static site which is built and uploaded to a bucket
+type StaticSiteSync struct {
+	ServiceName string
+	SourceDir   string // The source directory relative to the root directory
+	BuildDir    string // The directory of the built files relative to the source directory
+	BucketURL   string
 }
 
 // ServiceAccount holds the details about the service account resource
@@ -195,6 +441,63 @@ func (service *Service) AddPortForwarding(servicePort Port, podPort Port) error
 	return nil
 }
 
+// AddContainerPort adds the port to the container of the service and forwards the same port on the k8s service to it,
+// unless the port is already forwarded
+func (service *Service) AddContainerPort(containerIdx int, port core.ContainerPort) {
+	container := &service.Containers[containerIdx]
+	found := false
+	for i, containerPort := range container.Ports {
+		if containerPort.ContainerPort == port.ContainerPort && getProtocol(containerPort.Protocol) == getProtocol(port.Protocol) {
+			if containerPort.Name == "" {
+				container.Ports[i].Name = port.Name
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		container.Ports = append(container.Ports, port)
+	}
+	for _, forwarding := range service.ServiceToPodPortForwardings {
+		if forwarding.PodPort.Number == port.ContainerPort {
+			return
+		}
+	}
+	podPort := Port{Number: port.ContainerPort}
+	servicePort := Port{Number: port.ContainerPort, Name: port.Name}
+	service.AddPortForwarding(servicePort, podPort)
+}
+
+// GetPlanPort returns the port of the service in the plan with the number and the protocol
+func (service *Service) GetPlanPort(number int32, protocol core.Protocol) (plantypes.Port, bool) {
+	for _, port := range service.Ports {
+		if port.ContainerPort == number && string(port.GetProtocol()) == string(getProtocol(protocol)) {
+			return port, true
+		}
+	}
+	return plantypes.Port{}, false
+}
+
+// HasOnlyNonIngressPorts returns true if the plan exposes all the ports of the service, but none of them using the ingress
+func (service *Service) HasOnlyNonIngressPorts() bool {
+	if len(service.Ports) == 0 {
+		return false
+	}
+	for _, port := range service.Ports {
+		if port.Expose == "" || port.Expose == plantypes.IngressPortExpose {
+			return false
+		}
+	}
+	return true
+}
+
+func getProtocol(protocol core.Protocol) core.Protocol {
+	if protocol == "" {
+		return core.ProtocolTCP
+	}
+	return protocol
+}
+
 // AddVolume adds a volume to a service
 func (service *Service) AddVolume(volume core.Volume) {
 	merged := false
@@ -210,6 +513,11 @@ func (service *Service) AddVolume(volume core.Volume) {
 	}
 }
 
+// IsLongRunning returns true if the service is neither a daemon nor a job
+func (service *Service) IsLongRunning() bool {
+	return !service.Daemon && service.RestartPolicy != core.RestartPolicyNever && service.RestartPolicy != core.RestartPolicyOnFailure
+}
+
 // HasValidAnnotation returns if an annotation is set for the service
 func (service *Service) HasValidAnnotation(annotation string) bool {
 	val, ok := service.Annotations[annotation]
@@ -320,6 +628,7 @@ func NewIR(p plan.Plan) IR {
 	ir.Name = p.Name
 	ir.RootDir = p.Spec.Inputs.RootDir
 	ir.Kubernetes = p.Spec.Outputs.Kubernetes
+	ir.Profiles = p.Spec.Profiles
 	ir.Containers = []Container{}
 	ir.Services = map[string]Service{}
 	ir.Storages = []Storage{}
@@ -329,6 +638,11 @@ func NewIR(p plan.Plan) IR {
 		Host:              "",
 	}
 	ir.Values.GlobalVariables = map[string]string{}
+	ir.RegistryUsernames = map[string]string{}
+	ir.HostPathRemediations = map[string]plantypes.HostPathRemediationTypeValue{}
+	for hostPath, remediation := range p.Spec.Outputs.HostPathRemediations {
+		ir.HostPathRemediations[hostPath] = remediation
+	}
 	return ir
 }
 
@@ -340,6 +654,9 @@ func (ir *IR) Merge(newir IR) {
 		}
 	}
 	ir.Kubernetes.Merge(newir.Kubernetes)
+	if len(ir.Profiles) == 0 {
+		ir.Profiles = newir.Profiles
+	}
 	for scname, sc := range newir.Services {
 		if _, ok := ir.Services[scname]; ok {
 			log.Warnf("Two services of same service name %s. Using the new object.", scname)
@@ -355,6 +672,26 @@ func (ir *IR) Merge(newir IR) {
 	ir.TargetClusterSpec.Merge(newir.TargetClusterSpec)
 	ir.CachedObjects = append(ir.CachedObjects, newir.CachedObjects...)
 	ir.Values.Merge(newir.Values)
+	if len(newir.RegistryUsernames) > 0 && ir.RegistryUsernames == nil {
+		ir.RegistryUsernames = map[string]string{}
+	}
+	for registry, username := range newir.RegistryUsernames {
+		ir.RegistryUsernames[registry] = username
+	}
+	ir.StaticSiteSyncs = append(ir.StaticSiteSyncs, newir.StaticSiteSyncs...)
+	for _, component := range newir.DaprComponents {
+		ir.AddDaprComponent(component)
+	}
+}
+
+// IsGatewayAPIEnabled checks if the Gateway API should be used instead of Ingress.
+func (ir *IR) IsGatewayAPIEnabled() bool {
+	return ir.GatewayClassName != ""
+}
+
+// IsRolloutEnabled checks if Argo Rollouts should be used instead of Deployments.
+func (ir *IR) IsRolloutEnabled() bool {
+	return ir.DeploymentStrategy == CanaryDeploymentStrategy || ir.DeploymentStrategy == BlueGreenDeploymentStrategy
 }
 
 // IsIngressTLSEnabled checks if TLS is enabled for the ingress.
@@ -380,6 +717,7 @@ func (s *Storage) Merge(newst Storage) bool {
 		}
 		s.StorageType = newst.StorageType
 		s.PersistentVolumeClaimSpec = newst.PersistentVolumeClaimSpec
+		s.Immutable = s.Immutable || newst.Immutable
 		return true
 	}
 	log.Debugf("Mismatching storages [%s, %s]", s.Name, newst.Name)
@@ -414,15 +752,31 @@ func (ir *IR) AddStorage(st Storage) {
 	}
 }
 
+// AddDaprComponent adds a Dapr component, unless a component with the same name already exists
+func (ir *IR) AddDaprComponent(component DaprComponent) bool {
+	for _, c := range ir.DaprComponents {
+		if c.Name == component.Name {
+			return false
+		}
+	}
+	ir.DaprComponents = append(ir.DaprComponents, component)
+	return true
+}
+
 // GetContainer returns container which has the imagename
 func (ir *IR) GetContainer(imagename string) (con Container, exists bool) {
 	for _, c := range ir.Containers {
 		if common.IsStringPresent(c.ImageNames, imagename) {
 			return c, true
 		} else if c.New {
-			parts := strings.Split(imagename, "/")
-			if len(parts) > 2 && parts[0] == ir.Kubernetes.RegistryURL && common.IsStringPresent(c.ImageNames, parts[len(parts)-1]) {
-				return c, true
+			ref := common.ParseImageReference(imagename)
+			if ref.Registry == "" || ref.Registry != ir.Kubernetes.RegistryURL {
+				continue
+			}
+			for _, imageName := range c.ImageNames {
+				if common.ParseImageReference(imageName).WithRegistry(ref.Registry, ref.Namespace) == ref {
+					return c, true
+				}
 			}
 		}
 	}
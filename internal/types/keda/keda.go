@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keda contains the subset of the KEDA (keda.sh) types generated by move2kube
+package keda
+
+import (
+	"github.com/konveyor/move2kube/internal/common/deepcopy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ScaledObjectKind is the kind of a ScaledObject
+	ScaledObjectKind = "ScaledObject"
+	// TriggerAuthenticationKind is the kind of a TriggerAuthentication
+	TriggerAuthenticationKind = "TriggerAuthentication"
+)
+
+// SchemeGroupVersion is the group version of the KEDA resources
+var SchemeGroupVersion = schema.GroupVersion{Group: "keda.sh", Version: "v1alpha1"}
+
+// ScaledObject scales a workload on the events of the triggers. KEDA manages the HorizontalPodAutoscaler of the workload.
+type ScaledObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ScaledObjectSpec `json:"spec"`
+}
+
+// ScaledObjectSpec is the spec for a ScaledObject
+type ScaledObjectSpec struct {
+	ScaleTargetRef  *ScaleTarget    `json:"scaleTargetRef"`
+	MinReplicaCount *int32          `json:"minReplicaCount,omitempty"`
+	MaxReplicaCount *int32          `json:"maxReplicaCount,omitempty"`
+	Triggers        []ScaleTriggers `json:"triggers"`
+}
+
+// ScaleTarget is the workload scaled by a ScaledObject
+type ScaleTarget struct {
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+}
+
+// ScaleTriggers is an event source on which the workload is scaled, like the length of a queue
+type ScaleTriggers struct {
+	Type              string               `json:"type"`
+	Name              string               `json:"name,omitempty"`
+	MetricType        string               `json:"metricType,omitempty"`
+	Metadata          map[string]string    `json:"metadata"`
+	AuthenticationRef *ScaledObjectAuthRef `json:"authenticationRef,omitempty"`
+}
+
+// ScaledObjectAuthRef refers to a TriggerAuthentication in the same namespace
+type ScaledObjectAuthRef struct {
+	Name string `json:"name"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *ScaledObject) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*ScaledObject)
+}
+
+// TriggerAuthentication holds the credentials used by the triggers to connect to the event sources
+type TriggerAuthentication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TriggerAuthenticationSpec `json:"spec"`
+}
+
+// TriggerAuthenticationSpec is the spec for a TriggerAuthentication
+type TriggerAuthenticationSpec struct {
+	SecretTargetRef []AuthSecretTargetRef `json:"secretTargetRef,omitempty"`
+}
+
+// AuthSecretTargetRef sets a parameter of the triggers to the value of a key of a secret
+type AuthSecretTargetRef struct {
+	Parameter string `json:"parameter"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// DeepCopyObject implements the runtime.Object interface
+func (in *TriggerAuthentication) DeepCopyObject() runtime.Object {
+	return deepcopy.DeepCopy(in).(*TriggerAuthentication)
+}
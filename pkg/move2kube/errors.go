@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+// Error is a failure with an error code and a remediation hint
+type Error = common.Error
+
+// ErrorCode identifies a kind of failure. The codes are stable, so that they can be scripted around.
+type ErrorCode = common.ErrorCode
+
+const (
+	// RegistryAuthMissingErrorCode is used when the registry of the new images has no credentials
+	RegistryAuthMissingErrorCode = common.RegistryAuthMissingErrorCode
+	// ImageBuildFailedErrorCode is used when a new image cannot be built locally
+	ImageBuildFailedErrorCode = common.ImageBuildFailedErrorCode
+	// ImageNotFoundErrorCode is used when an image used by the artifacts is not found in its registry during the deployment
+	ImageNotFoundErrorCode = common.ImageNotFoundErrorCode
+	// NoServicesFoundErrorCode is used when no services or kubernetes artifacts are found in the source directory
+	NoServicesFoundErrorCode = common.NoServicesFoundErrorCode
+	// InvalidPlanErrorCode is used when the plan file is invalid
+	InvalidPlanErrorCode = common.InvalidPlanErrorCode
+	// ContainerizationFailedErrorCode is used when a service cannot be containerized
+	ContainerizationFailedErrorCode = common.ContainerizationFailedErrorCode
+	// TranslationFailedErrorCode is used when the plan cannot be translated
+	TranslationFailedErrorCode = common.TranslationFailedErrorCode
+	// ClusterAccessFailedErrorCode is used when the cluster metadata cannot be collected
+	ClusterAccessFailedErrorCode = common.ClusterAccessFailedErrorCode
+	// CFAuthMissingErrorCode is used when there is no CF API endpoint or access token
+	CFAuthMissingErrorCode = common.CFAuthMissingErrorCode
+	// HerokuAuthMissingErrorCode is used when there is no Heroku API key
+	HerokuAuthMissingErrorCode = common.HerokuAuthMissingErrorCode
+	// QADefaultsMissingErrorCode is used when the defaults manifest has no answers for the disabled QA categories
+	QADefaultsMissingErrorCode = common.QADefaultsMissingErrorCode
+	// ToolTimeoutErrorCode is used when an external tool does not finish in time
+	ToolTimeoutErrorCode = common.ToolTimeoutErrorCode
+	// ToolNotFoundErrorCode is used when an external tool is not installed
+	ToolNotFoundErrorCode = common.ToolNotFoundErrorCode
+	// UnresolvedReferenceErrorCode is used when a kubernetes resource refers to a resource missing in its target namespace
+	UnresolvedReferenceErrorCode = common.UnresolvedReferenceErrorCode
+	// UnmappedAnnotationErrorCode is used when an annotation of the source cloud provider has no equivalent on the target cloud provider
+	UnmappedAnnotationErrorCode = common.UnmappedAnnotationErrorCode
+	// ConstraintViolationErrorCode is used when a generated resource violates a constraint of the target cluster
+	ConstraintViolationErrorCode = common.ConstraintViolationErrorCode
+	// SecretsFoundErrorCode is used when credentials are found in the sources copied into the build contexts
+	SecretsFoundErrorCode = common.SecretsFoundErrorCode
+	// UnconvertibleResourceErrorCode is used when a resource of an infrastructure template, like a database of a CloudFormation template, has no equivalent in the translated resources
+	UnconvertibleResourceErrorCode = common.UnconvertibleResourceErrorCode
+	// DeploymentNotReadyErrorCode is used when a deployed workload does not become ready within the timeout
+	DeploymentNotReadyErrorCode = common.DeploymentNotReadyErrorCode
+)
+
+// AsError returns the error with a code in the chain of the error, or nil if the error has no code
+func AsError(err error) *Error {
+	return common.AsError(err)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube
+
+import (
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/internal/common"
+	internalmove2kube "github.com/konveyor/move2kube/internal/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+	projecttypes "github.com/konveyor/move2kube/types/project"
+	log "github.com/sirupsen/logrus"
+)
+
+// MigrateResult contains the paths of the files generated by Migrate
+type MigrateResult struct {
+	PlanPath    string
+	OutputPath  string
+	ReportPath  string
+	ArchivePath string // Empty if the artifacts were not packaged
+}
+
+// Migrate plans the source directory, curates the plan, translates it into the output directory and packages the artifacts
+// next to the output directory if a format is given. The hooks in the project config file of the source directory are run
+// at the phase boundaries. The QA engine has to be set up, using SetupQA, before calling it.
+func Migrate(srcPath string, name string, outputPath string, format string) (MigrateResult, error) {
+	result := MigrateResult{OutputPath: outputPath}
+	log.Infof("Planning the migration of the source directory %s", srcPath)
+	if err := internalmove2kube.RunHooks(srcPath, projecttypes.PrePlanHookPhase, srcPath); err != nil {
+		return result, err
+	}
+	p, err := CreatePlan(srcPath, name)
+	if err != nil {
+		return result, err
+	}
+	if len(p.Spec.Inputs.Services) == 0 && len(p.Spec.Inputs.K8sFiles) == 0 && len(p.Spec.Inputs.HelmCharts) == 0 {
+		return result, common.NewError(common.NoServicesFoundErrorCode, nil, "Failed to find any services or kubernetes artifacts in the source directory %s", srcPath)
+	}
+	if p, err = CuratePlan(p); err != nil {
+		return result, err
+	}
+	result.PlanPath = filepath.Join(outputPath, common.DefaultPlanFile)
+	if err := plantypes.WritePlan(result.PlanPath, p); err != nil {
+		log.Errorf("Failed to write the plan to the file at path %s . Error: %q", result.PlanPath, err)
+		return result, err
+	}
+	if err := internalmove2kube.RunHooks(srcPath, projecttypes.PostPlanHookPhase, result.PlanPath); err != nil {
+		return result, err
+	}
+	if err := internalmove2kube.RunHooks(srcPath, projecttypes.PreTranslateHookPhase, result.PlanPath, outputPath); err != nil {
+		return result, err
+	}
+	// The problems found while planning are kept in the report
+	if err := translate(p, outputPath, nil); err != nil {
+		return result, err
+	}
+	if err := internalmove2kube.RunHooks(srcPath, projecttypes.PostTranslateHookPhase, result.PlanPath, outputPath); err != nil {
+		return result, err
+	}
+	result.ReportPath = filepath.Join(outputPath, common.ReportFile)
+	if format == "" {
+		return result, nil
+	}
+	archivePath, err := internalmove2kube.PackageOutput(outputPath, filepath.Dir(outputPath), format, "", "")
+	if err != nil {
+		return result, err
+	}
+	result.ArchivePath = archivePath
+	return result, nil
+}
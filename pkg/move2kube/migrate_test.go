@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/pkg/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const testMigrateDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+        - name: web
+          image: quay.io/myns/web:latest
+          ports:
+            - containerPort: 8080
+`
+
+func TestMigrate(t *testing.T) {
+	assetsPath, tempPath, err := common.CreateAssetsData()
+	if err != nil {
+		t.Fatalf("Unable to create the assets directory. Error: %q", err)
+	}
+	common.TempPath = tempPath
+	common.AssetsPath = assetsPath
+	defer os.RemoveAll(common.TempPath)
+	if err := move2kube.SetupQA(t.TempDir(), move2kube.QAOptions{SkipQA: true}); err != nil {
+		t.Fatalf("Failed to set up the QA engine. Error: %q", err)
+	}
+
+	t.Run("source without services", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "myproject")
+		_, err := move2kube.Migrate(t.TempDir(), "myproject", outputPath, "")
+		if codedErr := move2kube.AsError(err); codedErr == nil || codedErr.Code != move2kube.NoServicesFoundErrorCode {
+			t.Fatalf("Expected the error code %s . Actual: %v", move2kube.NoServicesFoundErrorCode, err)
+		}
+		if _, err := os.Stat(filepath.Join(outputPath, common.DefaultPlanFile)); err == nil {
+			t.Fatalf("Expected no plan to be written for a source without services")
+		}
+	})
+
+	srcPath := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(srcPath, "deployment.yaml"), []byte(testMigrateDeployment), common.DefaultFilePermission); err != nil {
+		t.Fatalf("Failed to write the kubernetes yamls of the source. Error: %q", err)
+	}
+
+	t.Run("migrate without packaging", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "myproject")
+		result, err := move2kube.Migrate(srcPath, "myproject", outputPath, "")
+		if err != nil {
+			t.Fatalf("Failed to migrate the source directory %s . Error: %q", srcPath, err)
+		}
+		want := move2kube.MigrateResult{
+			PlanPath:   filepath.Join(outputPath, common.DefaultPlanFile),
+			OutputPath: outputPath,
+			ReportPath: filepath.Join(outputPath, common.ReportFile),
+		}
+		if result != want {
+			t.Fatalf("Expected the result %+v . Actual: %+v", want, result)
+		}
+		p, err := plantypes.ReadPlan(result.PlanPath)
+		if err != nil {
+			t.Fatalf("Failed to read the plan at path %s . Error: %q", result.PlanPath, err)
+		}
+		if p.Name != "myproject" || len(p.Spec.Inputs.K8sFiles) != 1 {
+			t.Fatalf("Expected the plan of the project with the kubernetes yamls of the source. Actual: %+v", p)
+		}
+		if _, err := os.Stat(result.ReportPath); err != nil {
+			t.Fatalf("Expected the report at path %s . Error: %q", result.ReportPath, err)
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(outputPath), "myproject."+common.ZipArchiveFormat)); err == nil {
+			t.Fatalf("Expected the artifacts not to be packaged")
+		}
+	})
+
+	t.Run("migrate and package", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "myproject")
+		result, err := move2kube.Migrate(srcPath, "myproject", outputPath, common.ZipArchiveFormat)
+		if err != nil {
+			t.Fatalf("Failed to migrate the source directory %s . Error: %q", srcPath, err)
+		}
+		wantArchivePath := filepath.Join(filepath.Dir(outputPath), "myproject."+common.ZipArchiveFormat)
+		if result.ArchivePath != wantArchivePath || result.ReportPath != filepath.Join(outputPath, common.ReportFile) {
+			t.Fatalf("Expected the archive at path %s . Actual: %+v", wantArchivePath, result)
+		}
+		for _, path := range []string{result.PlanPath, result.ReportPath, result.ArchivePath, result.ArchivePath + common.SHA256SumSuffix} {
+			if _, err := os.Stat(path); err != nil {
+				t.Fatalf("Expected the file at path %s . Error: %q", path, err)
+			}
+		}
+	})
+}
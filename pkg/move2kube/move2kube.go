@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package move2kube is the Go API of move2kube. It creates the plan of a source directory and translates the plan to
+// Kubernetes artifacts, like the move2kube translate command does, so that other tools can embed move2kube.
+//
+// The questions asked while planning and translating are answered by the QA engine, which has to be set up once,
+// using SetupQA, before calling the other functions.
+//
+// The failures with a known remediation are returned as an *Error with an ErrorCode, which can be found using AsError.
+package move2kube
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/internal/common"
+	internalmove2kube "github.com/konveyor/move2kube/internal/move2kube"
+	"github.com/konveyor/move2kube/internal/qaengine"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+// QAOptions configures how the questions are answered
+type QAOptions struct {
+	// SkipQA answers the questions, which are not answered by the configs or the caches, using their defaults.
+	// Else, the questions are asked on the command line.
+	SkipQA bool
+	// Configs are the answers given as key=value, like move2kube.target.clustertype=Kubernetes
+	Configs []string
+	// ConfigFiles are the paths or URLs of the config files holding the answers
+	ConfigFiles []string
+	// Presets are the names of the preset configs, like cicd
+	Presets []string
+	// CacheFiles are the paths of the QA caches of previous runs
+	CacheFiles []string
+	// Storage is where the config and the cache written by the QA engine are kept, like configmap://<namespace>/<name>.
+	// It defaults to the output directory. The answers already in the storage are reused.
+	Storage string
+}
+
+// SetupQA sets up the QA engine. The answers are written to the config and cache files in the output directory, or in the storage.
+func SetupQA(outputPath string, options QAOptions) error {
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the output directory at path %s . Error: %q", outputPath, err)
+	}
+	qaengine.StartEngine(options.SkipQA, 0, false, "")
+	if err := qaengine.SetupStorage(options.Storage); err != nil {
+		return err
+	}
+	qaengine.SetupConfigFile(outputPath, options.Configs, options.ConfigFiles, options.Presets)
+	qaengine.SetupCacheFile(outputPath, options.CacheFiles)
+	return qaengine.WriteStoresToDisk()
+}
+
+// CreatePlan creates the plan of the services found in the source directory
+func CreatePlan(sourcePath, name string) (plantypes.Plan, error) {
+	common.ResetReportedErrors()
+	sourcePath, err := getSourcePath(sourcePath)
+	if err != nil {
+		return plantypes.Plan{}, err
+	}
+	if name == "" {
+		name = common.DefaultProjectName
+	}
+	return internalmove2kube.CreatePlan(sourcePath, name, true)
+}
+
+// CuratePlan asks which services of the plan are translated, and how their images are built.
+// It returns an error with the NoServicesFoundErrorCode if no services are left to translate.
+func CuratePlan(p plantypes.Plan) (plantypes.Plan, error) {
+	return internalmove2kube.CuratePlan(p)
+}
+
+// MergePlans reads the plan files, like the plans of the teams sharing a monorepo, and merges them into one plan.
+// The services with the same name in several plans are merged when they are the same service, and renamed otherwise.
+func MergePlans(planPaths []string, name string) (plantypes.Plan, error) {
+	p, err := plantypes.MergePlanFiles(name, planPaths)
+	if err != nil {
+		return p, common.NewError(common.InvalidPlanErrorCode, err, "Failed to merge the plans %v .", planPaths)
+	}
+	return p, nil
+}
+
+// Translate translates the plan and writes the artifacts to the output directory. The transform paths are the paths
+// of the starlark transforms run on the generated yamls. The errors with a code can be found using AsError.
+func Translate(p plantypes.Plan, outputPath string, transformPaths []string) error {
+	common.ResetReportedErrors()
+	return translate(p, outputPath, transformPaths)
+}
+
+// translate checks the plan and the output directory, and translates the plan. The errors reported before are kept in the report.
+func translate(p plantypes.Plan, outputPath string, transformPaths []string) error {
+	if err := internalmove2kube.ValidatePlanServices(p); err != nil {
+		return common.NewError(common.InvalidPlanErrorCode, err, "The plan %s is invalid.", p.Name)
+	}
+	if len(p.Spec.Inputs.Services) == 0 && len(p.Spec.Inputs.K8sFiles) == 0 && len(p.Spec.Inputs.HelmCharts) == 0 {
+		return common.NewError(common.NoServicesFoundErrorCode, nil, "The plan %s has no services.", p.Name)
+	}
+	rootDir, err := getSourcePath(p.Spec.Inputs.RootDir)
+	if err != nil {
+		return err
+	}
+	if outputPath, err = filepath.Abs(outputPath); err != nil {
+		return fmt.Errorf("failed to make the output directory path %s absolute. Error: %q", outputPath, err)
+	}
+	if rootDir == outputPath || common.IsParent(outputPath, rootDir) || common.IsParent(rootDir, outputPath) {
+		return fmt.Errorf("the source path %s and the output path %s overlap", rootDir, outputPath)
+	}
+	if err := os.MkdirAll(outputPath, common.DefaultDirectoryPermission); err != nil {
+		return fmt.Errorf("failed to create the output directory at path %s . Error: %q", outputPath, err)
+	}
+	return internalmove2kube.Translate(p, outputPath, true, transformPaths)
+}
+
+// getSourcePath returns the absolute path of the source directory, after checking that it is a directory
+func getSourcePath(sourcePath string) (string, error) {
+	sourcePath, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return sourcePath, fmt.Errorf("failed to make the source directory path %s absolute. Error: %q", sourcePath, err)
+	}
+	fi, err := os.Stat(sourcePath)
+	if err != nil {
+		return sourcePath, fmt.Errorf("failed to access the source directory %s . Error: %q", sourcePath, err)
+	}
+	if !fi.IsDir() {
+		return sourcePath, fmt.Errorf("the source path %s is a file. Expected a directory", sourcePath)
+	}
+	return sourcePath, nil
+}
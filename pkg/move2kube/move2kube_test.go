@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package move2kube_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/move2kube/pkg/move2kube"
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestCreatePlanInvalidSource(t *testing.T) {
+	tempDir := t.TempDir()
+	if _, err := move2kube.CreatePlan(filepath.Join(tempDir, "missing"), "myproject"); err == nil {
+		t.Fatalf("Expected an error for the missing source directory")
+	}
+	filePath := filepath.Join(tempDir, "app.py")
+	if err := ioutil.WriteFile(filePath, []byte("print('hello')\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the file %s . Error: %q", filePath, err)
+	}
+	if _, err := move2kube.CreatePlan(filePath, "myproject"); err == nil {
+		t.Fatalf("Expected an error for the source path %s which is a file", filePath)
+	}
+}
+
+func TestTranslateInvalidPlan(t *testing.T) {
+	sourceDir := t.TempDir()
+	p := plantypes.NewPlan()
+	p.Name = "myproject"
+	p.Spec.Inputs.RootDir = sourceDir
+	err := move2kube.Translate(p, filepath.Join(t.TempDir(), "output"), nil)
+	if codedErr := move2kube.AsError(err); codedErr == nil || codedErr.Code != move2kube.NoServicesFoundErrorCode || codedErr.Remediation() == "" {
+		t.Fatalf("Expected the error code %s for the plan without services. Actual: %v", move2kube.NoServicesFoundErrorCode, err)
+	}
+	p.Spec.Inputs.K8sFiles = []string{filepath.Join(sourceDir, "deployment.yaml")}
+	if err := move2kube.Translate(p, filepath.Join(sourceDir, "output"), nil); err == nil {
+		t.Fatalf("Expected an error for the output directory inside the source directory")
+	}
+}
@@ -19,12 +19,8 @@ limitations under the License.
 package main
 
 import (
-	"crypto/sha256"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 
@@ -33,10 +29,6 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const (
-	checksumSuffix = ".sha256sum"
-)
-
 var (
 	// binName is the name of the exectuable
 	binName string
@@ -46,46 +38,11 @@ var (
 	outputDir string
 )
 
-func sha256sum(source, target string) error {
-	file, err := os.Open(source)
-	if err != nil {
-		return fmt.Errorf("Failed to open the archive at path %q Error %q", source, err)
-	}
-	defer file.Close()
-	hasher := sha256.New()
-	if _, err := io.Copy(hasher, file); err != nil {
-		return fmt.Errorf("Failed to caculate the checksum for the archive at path %q Error %q", source, err)
-	}
-	filename := filepath.Base(source)
-	hashAndFilename := fmt.Sprintf("%x  %s", hasher.Sum(nil), filename) // Same format as the output of shasum -a 256 myarchive.tar.gz
-	if err := ioutil.WriteFile(target, []byte(hashAndFilename), common.DefaultFilePermission); err != nil {
-		return fmt.Errorf("Failed to write the checksum to file at path %q Error %q", target, err)
-	}
-	return file.Close()
-}
-
-func createZip(source, target string) error {
-	cmd := exec.Command("zip", "-r", target, source)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("Failed to create tar archive %q using files from %q. Output: %q Error %q", target, source, string(out), err)
-	}
-	return nil
-}
-
-func createTar(source, target string) error {
-	cmd := exec.Command("tar", "-zcf", target, source)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("Failed to create tar archive %q using files from %q. Output: %q Error %q", target, source, string(out), err)
-	}
-	return nil
-}
-
 func copy(sourceFiles []string, target string) error {
-	args := append([]string{"-r"}, sourceFiles...)
-	args = append(args, target)
-	cmd := exec.Command("cp", args...)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("Failed to copy files from source files %v to target %q Output: %q Error %q", sourceFiles, target, string(out), err)
+	for _, sourceFile := range sourceFiles {
+		if err := common.CopyPath(filepath.Join(target, filepath.Base(sourceFile)), sourceFile); err != nil {
+			return fmt.Errorf("Failed to copy the file at path %q to the directory %q Error %q", sourceFile, target, err)
+		}
 	}
 	return nil
 }
@@ -162,21 +119,21 @@ func createArchives(distDirs []string) {
 		tarArchivePath := filepath.Join(outputDir, tarArchiveName)
 		log.Debug("osArch:", osArch)
 		log.Debug("tarArchivePath:", tarArchivePath)
-		if err := createTar(tempDir, tarArchivePath); err != nil {
+		if err := common.CreateTarGz(tarArchivePath, tempDir); err != nil {
 			log.Fatal(err)
 		}
 		zipArchiveName := fmt.Sprintf("%s-%s-%s.zip", binName, version, osArch)
 		zipArchivePath := filepath.Join(outputDir, zipArchiveName)
 		log.Debug("zipArchivePath:", zipArchivePath)
-		if err := createZip(tempDir, zipArchivePath); err != nil {
+		if err := common.CreateZip(zipArchivePath, tempDir); err != nil {
 			log.Fatal(err)
 		}
 
 		log.Debug("Calculate and write the checksums to files.")
-		if err := sha256sum(tarArchivePath, filepath.Join(outputDir, tarArchiveName+checksumSuffix)); err != nil {
+		if err := common.CreateSHA256SumFile(tarArchivePath, filepath.Join(outputDir, tarArchiveName+common.SHA256SumSuffix)); err != nil {
 			log.Fatal(err)
 		}
-		if err := sha256sum(zipArchivePath, filepath.Join(outputDir, zipArchiveName+checksumSuffix)); err != nil {
+		if err := common.CreateSHA256SumFile(zipArchivePath, filepath.Join(outputDir, zipArchiveName+common.SHA256SumSuffix)); err != nil {
 			log.Fatal(err)
 		}
 	}
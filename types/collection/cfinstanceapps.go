@@ -45,6 +45,25 @@ type CfApplication struct {
 	DockerImage       string            `yaml:"dockerImage,omitempty"`
 	Ports             []int32           `yaml:"ports"`
 	Env               map[string]string `yaml:"env,omitempty"`
+	Routes            []string          `yaml:"routes,omitempty"`
+	RouteServices     []string          `yaml:"routeServices,omitempty"`
+	Autoscaling       *CfAutoscaling    `yaml:"autoscaling,omitempty"`
+}
+
+// CfAutoscaling defines the policy used by the App Autoscaler to scale a cf runtime application
+type CfAutoscaling struct {
+	InstanceMinCount int             `yaml:"instanceMinCount"`
+	InstanceMaxCount int             `yaml:"instanceMaxCount"`
+	ScalingRules     []CfScalingRule `yaml:"scalingRules,omitempty"`
+	Scheduled        bool            `yaml:"scheduled,omitempty"` // The instance counts are changed by schedules
+}
+
+// CfScalingRule adds or removes instances when the metric breaches the threshold
+type CfScalingRule struct {
+	MetricType string `yaml:"metricType"`
+	Threshold  int64  `yaml:"threshold"`
+	Operator   string `yaml:"operator"`
+	Adjustment string `yaml:"adjustment"`
 }
 
 // NewCfInstanceApps creates a new instance of CfInstanceApps
@@ -17,6 +17,7 @@ limitations under the License.
 package collection
 
 import (
+	semver "github.com/Masterminds/semver/v3"
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/types"
 )
@@ -24,6 +25,24 @@ import (
 // ClusterMetadataKind defines the kind of cluster metadata file
 const ClusterMetadataKind types.Kind = "ClusterMetadata"
 
+// ClusterFlavor is the distribution of Kubernetes running on the cluster
+type ClusterFlavor string
+
+const (
+	// KubernetesClusterFlavor is a cluster without any known distribution specific APIs
+	KubernetesClusterFlavor ClusterFlavor = "Kubernetes"
+	// OpenShiftClusterFlavor is an OpenShift cluster
+	OpenShiftClusterFlavor ClusterFlavor = "OpenShift"
+	// K3sClusterFlavor is a Rancher K3s cluster
+	K3sClusterFlavor ClusterFlavor = "K3s"
+	// EKSClusterFlavor is an Amazon Elastic Kubernetes Service cluster
+	EKSClusterFlavor ClusterFlavor = "EKS"
+	// GKEClusterFlavor is a Google Kubernetes Engine cluster
+	GKEClusterFlavor ClusterFlavor = "GKE"
+	// AKSClusterFlavor is an Azure Kubernetes Service cluster
+	AKSClusterFlavor ClusterFlavor = "AKS"
+)
+
 // ClusterMetadata for collect output
 type ClusterMetadata struct {
 	types.TypeMeta   `yaml:",inline"`
@@ -36,6 +55,61 @@ type ClusterMetadataSpec struct {
 	StorageClasses    []string            `yaml:"storageClasses"`
 	APIKindVersionMap map[string][]string `yaml:"apiKindVersionMap"` //[kubernetes kind]["gv1", "gv2",...,"gvn"] prioritized group-version
 	Host              string              `yaml:"host,omitempty"`    // Optional field, either collected with move2kube collect or by asking the user.
+	Flavor            ClusterFlavor       `yaml:"flavor,omitempty"`
+	FlavorVersion     string              `yaml:"flavorVersion,omitempty"` // Version of the distribution. Eg: 4.6.8 for OpenShift
+	KubernetesVersion string              `yaml:"kubernetesVersion,omitempty"`
+	Constraints       ClusterConstraints  `yaml:"constraints,omitempty"`
+	WorkloadUsage     []WorkloadUsage     `yaml:"workloadUsage,omitempty"` // Collected from the metrics API, if enabled
+}
+
+// WorkloadUsage is the highest cpu and memory used by a pod of a workload when the cluster was collected
+type WorkloadUsage struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+	CPU       string `yaml:"cpu,omitempty"`    // Like 120m
+	Memory    string `yaml:"memory,omitempty"` // Like 300Mi
+}
+
+// ComplianceAction is what is done when a generated resource violates a constraint of the cluster
+type ComplianceAction string
+
+const (
+	// FixComplianceAction modifies the resource to satisfy the constraint
+	FixComplianceAction ComplianceAction = "fix"
+	// ReportComplianceAction only reports the violation
+	ReportComplianceAction ComplianceAction = "report"
+)
+
+const (
+	// PrivilegedRestriction disallows privileged containers
+	PrivilegedRestriction = "privileged"
+	// HostNetworkRestriction disallows host networking and host ports
+	HostNetworkRestriction = "hostNetwork"
+	// HostPathRestriction disallows hostPath volumes
+	HostPathRestriction = "hostPath"
+	// ResourceRequestsRestriction requires cpu and memory requests on all containers
+	ResourceRequestsRestriction = "resourceRequests"
+	// CapabilitiesRestriction disallows capabilities that are not in the allowed list
+	CapabilitiesRestriction = "capabilities"
+)
+
+// ClusterConstraints are the restrictions that the cluster places on workloads. Eg: GKE Autopilot does not allow privileged containers.
+type ClusterConstraints struct {
+	DisallowPrivileged      bool     `yaml:"disallowPrivileged,omitempty"`
+	DisallowHostNetwork     bool     `yaml:"disallowHostNetwork,omitempty"` // Also disallows host ports
+	DisallowHostPath        bool     `yaml:"disallowHostPath,omitempty"`
+	RequireResourceRequests bool     `yaml:"requireResourceRequests,omitempty"`
+	AllowedCapabilities     []string `yaml:"allowedCapabilities,omitempty"` // If empty, all capabilities are allowed
+	// Actions contains the action to take for each restriction when a generated resource violates it. Defaults to fix.
+	Actions map[string]ComplianceAction `yaml:"actions,omitempty"`
+}
+
+// GetAction returns the action to take when a generated resource violates the restriction
+func (c *ClusterConstraints) GetAction(restriction string) ComplianceAction {
+	if action, ok := c.Actions[restriction]; ok && action == ReportComplianceAction {
+		return ReportComplianceAction
+	}
+	return FixComplianceAction
 }
 
 // Merge helps merge clustermetadata
@@ -74,6 +148,7 @@ func (c *ClusterMetadata) Merge(newc ClusterMetadata) bool {
 	}
 	c.Spec.APIKindVersionMap = apiversionkindmap
 	c.Spec.Host = newc.Spec.Host
+	c.Spec.mergeDistributionInfo(newc.Spec)
 	return true
 }
 
@@ -96,9 +171,36 @@ func (c *ClusterMetadataSpec) Merge(newc ClusterMetadataSpec) bool {
 	}
 	c.APIKindVersionMap = apiversionkindmap
 	c.Host = newc.Host
+	c.mergeDistributionInfo(newc)
 	return true
 }
 
+func (c *ClusterMetadataSpec) mergeDistributionInfo(newc ClusterMetadataSpec) {
+	if newc.Flavor != "" {
+		c.Flavor = newc.Flavor
+		c.FlavorVersion = newc.FlavorVersion
+	}
+	if newc.KubernetesVersion != "" {
+		c.KubernetesVersion = newc.KubernetesVersion
+	}
+	c.Constraints.DisallowPrivileged = c.Constraints.DisallowPrivileged || newc.Constraints.DisallowPrivileged
+	c.Constraints.DisallowHostNetwork = c.Constraints.DisallowHostNetwork || newc.Constraints.DisallowHostNetwork
+	c.Constraints.DisallowHostPath = c.Constraints.DisallowHostPath || newc.Constraints.DisallowHostPath
+	c.Constraints.RequireResourceRequests = c.Constraints.RequireResourceRequests || newc.Constraints.RequireResourceRequests
+	if len(newc.Constraints.AllowedCapabilities) > 0 {
+		c.Constraints.AllowedCapabilities = newc.Constraints.AllowedCapabilities
+	}
+	for restriction, action := range newc.Constraints.Actions {
+		if c.Constraints.Actions == nil {
+			c.Constraints.Actions = map[string]ComplianceAction{}
+		}
+		c.Constraints.Actions[restriction] = action
+	}
+	if len(newc.WorkloadUsage) > 0 {
+		c.WorkloadUsage = newc.WorkloadUsage
+	}
+}
+
 func (c *ClusterMetadata) isEmpty() bool {
 	return c.Kind == ""
 }
@@ -123,6 +225,24 @@ func (c *ClusterMetadataSpec) IsBuildConfigSupported() bool {
 	return len(c.GetSupportedVersions("BuildConfig")) > 0
 }
 
+// IsOpenShift returns true if the cluster is known to be OpenShift
+func (c *ClusterMetadataSpec) IsOpenShift() bool {
+	return c.Flavor == OpenShiftClusterFlavor
+}
+
+// IsDeploymentConfigPreferred returns false for OpenShift 4 and later, where Deployments are the recommended default.
+// If the flavor or version is unknown, the DeploymentConfig is preferred whenever the cluster supports it.
+func (c *ClusterMetadataSpec) IsDeploymentConfigPreferred() bool {
+	if !c.IsOpenShift() || c.FlavorVersion == "" {
+		return true
+	}
+	version, err := semver.NewVersion(c.FlavorVersion)
+	if err != nil {
+		return true
+	}
+	return version.Major() < 4
+}
+
 // NewClusterMetadata creates a new cluster metadata instance
 func NewClusterMetadata(contextName string) ClusterMetadata {
 	return ClusterMetadata{
@@ -139,3 +139,29 @@ func TestNewClusterMetadata(t *testing.T) {
 		t.Fatal("Failed to initialize ClusterMetadata properly.")
 	}
 }
+
+func TestIsDeploymentConfigPreferred(t *testing.T) {
+	testcases := []struct {
+		name    string
+		flavor  collection.ClusterFlavor
+		version string
+		want    bool
+	}{
+		{name: "unknown flavor", want: true},
+		{name: "kubernetes", flavor: collection.KubernetesClusterFlavor, version: "", want: true},
+		{name: "openshift without version", flavor: collection.OpenShiftClusterFlavor, want: true},
+		{name: "openshift 3", flavor: collection.OpenShiftClusterFlavor, version: "3.11.0", want: true},
+		{name: "openshift 4", flavor: collection.OpenShiftClusterFlavor, version: "4.6.8", want: false},
+		{name: "invalid version", flavor: collection.OpenShiftClusterFlavor, version: "foo", want: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmeta := collection.NewClusterMetadata("")
+			cmeta.Spec.Flavor = tc.flavor
+			cmeta.Spec.FlavorVersion = tc.version
+			if got := cmeta.Spec.IsDeploymentConfigPreferred(); got != tc.want {
+				t.Fatalf("Expected %t. Actual: %t", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collection
+
+import (
+	"github.com/konveyor/move2kube/types"
+)
+
+// HerokuAppsMetadataKind defines kind of heroku apps file
+const HerokuAppsMetadataKind types.Kind = "HerokuApps"
+
+// HerokuApps defines definition of heroku apps file
+type HerokuApps struct {
+	types.TypeMeta   `yaml:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty"`
+	Spec             HerokuAppsSpec `yaml:"spec,omitempty"`
+}
+
+// HerokuAppsSpec stores the data
+type HerokuAppsSpec struct {
+	HerokuApplications []HerokuApplication `yaml:"applications"`
+}
+
+// HerokuApplication defines the structure of a heroku application
+type HerokuApplication struct {
+	Name       string              `yaml:"name"`
+	Stack      string              `yaml:"stack,omitempty"`
+	Region     string              `yaml:"region,omitempty"`
+	Buildpacks []string            `yaml:"buildpacks,omitempty"`
+	ConfigVars map[string]string   `yaml:"configVars,omitempty"` // The values are empty unless they were collected with consent
+	Formation  []HerokuProcessType `yaml:"formation,omitempty"`
+	AddOns     []HerokuAddOn       `yaml:"addOns,omitempty"`
+}
+
+// HerokuProcessType defines the dynos running a process type of a heroku application
+type HerokuProcessType struct {
+	Type     string `yaml:"type"`
+	Quantity int    `yaml:"quantity"`
+	Size     string `yaml:"size,omitempty"`
+	Command  string `yaml:"command,omitempty"`
+}
+
+// HerokuAddOn defines an add-on attached to a heroku application, along with the suggested replacement on Kubernetes
+type HerokuAddOn struct {
+	Name        string   `yaml:"name"`
+	Service     string   `yaml:"service"`
+	Plan        string   `yaml:"plan,omitempty"`
+	ConfigVars  []string `yaml:"configVars,omitempty"` // The config vars set by the add-on, like DATABASE_URL
+	Replacement string   `yaml:"replacement,omitempty"`
+}
+
+// HerokuAddOnReplacements are the suggested replacements on Kubernetes of the add-on services
+var HerokuAddOnReplacements = map[string]string{
+	"heroku-postgresql": "A managed PostgreSQL database of the cloud provider, or the bitnami/postgresql Helm chart",
+	"heroku-redis":      "A managed Redis of the cloud provider, or the bitnami/redis Helm chart",
+	"heroku-kafka":      "A managed Kafka of the cloud provider, or Kafka deployed by the Strimzi operator",
+	"jawsdb":            "A managed MySQL database of the cloud provider, or the bitnami/mysql Helm chart",
+	"cleardb":           "A managed MySQL database of the cloud provider, or the bitnami/mysql Helm chart",
+	"mongolab":          "MongoDB Atlas, or the bitnami/mongodb Helm chart",
+	"cloudamqp":         "RabbitMQ deployed by the RabbitMQ cluster operator",
+	"memcachier":        "The bitnami/memcached Helm chart",
+	"bonsai":            "Elasticsearch deployed by the ECK operator, or a managed OpenSearch of the cloud provider",
+	"searchbox":         "Elasticsearch deployed by the ECK operator, or a managed OpenSearch of the cloud provider",
+	"bucketeer":         "The object storage of the cloud provider, or MinIO",
+	"scheduler":         "CronJobs running the scheduled commands",
+	"papertrail":        "The logging stack of the cluster, like Loki or Elasticsearch with Fluent Bit",
+	"logdna":            "The logging stack of the cluster, like Loki or Elasticsearch with Fluent Bit",
+	"sumologic":         "The Sumo Logic Kubernetes collection, or the logging stack of the cluster",
+	"newrelic":          "The New Relic Kubernetes integration",
+}
+
+// HerokuDefaultAddOnReplacement is suggested for the add-on services without a known replacement
+const HerokuDefaultAddOnReplacement = "Keep using the add-on provider as an external service, or deploy an equivalent service in the cluster"
+
+// GetHerokuAddOnReplacement returns the suggested replacement on Kubernetes of the add-on service, like heroku-postgresql
+func GetHerokuAddOnReplacement(service string) string {
+	if replacement, ok := HerokuAddOnReplacements[service]; ok {
+		return replacement
+	}
+	return HerokuDefaultAddOnReplacement
+}
+
+// NewHerokuApps creates a new instance of HerokuApps
+func NewHerokuApps() HerokuApps {
+	return HerokuApps{
+		TypeMeta: types.TypeMeta{
+			Kind:       string(HerokuAppsMetadataKind),
+			APIVersion: types.SchemeGroupVersion.String(),
+		},
+	}
+}
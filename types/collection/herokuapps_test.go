@@ -0,0 +1,31 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/konveyor/move2kube/types"
+	"github.com/konveyor/move2kube/types/collection"
+)
+
+func TestNewHerokuApps(t *testing.T) {
+	herokuapps := collection.NewHerokuApps()
+	if herokuapps.Kind != string(collection.HerokuAppsMetadataKind) || herokuapps.APIVersion != types.SchemeGroupVersion.String() {
+		t.Fatal("Failed to initialize HerokuApps properly.")
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package defaults lets applications that embed move2kube set organization wide defaults
+package defaults
+
+import (
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+// Defaults are the defaults used by move2kube. Empty fields keep the current value.
+type Defaults = common.Defaults
+
+// Get returns the defaults currently in use
+func Get() Defaults {
+	return common.GetDefaults()
+}
+
+// Set changes the defaults. The permissions are octal strings. Eg: 0644
+func Set(defaults Defaults) error {
+	return common.SetDefaults(defaults)
+}
+
+// Load changes the defaults using the defaults file at the given path, if any, and then the environment variables
+func Load(path string) error {
+	return common.LoadDefaults(path)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ResourcesSnapshotKind is kind of the snapshots of the generated resources
+const ResourcesSnapshotKind types.Kind = "ResourcesSnapshot"
+
+// snapshotTimeFormat is the format of the names of the snapshot files, which sort in the order of the translations
+const snapshotTimeFormat = "20060102T150405.000000000Z"
+
+// ResourcesSnapshot records the kubernetes resources generated by a translation, so that the later translations can
+// find the resources which are no longer generated
+type ResourcesSnapshot struct {
+	types.TypeMeta   `yaml:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty"`
+	Spec             ResourcesSnapshotSpec `yaml:"spec"`
+}
+
+// ResourcesSnapshotSpec stores the generated resources
+type ResourcesSnapshotSpec struct {
+	Resources []ResourceRef `yaml:"resources"`
+}
+
+// ResourceRef identifies a kubernetes resource
+type ResourceRef struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Name       string `yaml:"name"`
+	Namespace  string `yaml:"namespace,omitempty"`
+}
+
+// KubectlName returns the fully qualified name of the resource used by kubectl, like deployment.v1.apps/web
+func (r ResourceRef) KubectlName() string {
+	resourceType := strings.ToLower(r.Kind)
+	if i := strings.Index(r.APIVersion, "/"); i >= 0 {
+		resourceType += "." + r.APIVersion[i+1:] + "." + r.APIVersion[:i]
+	}
+	return resourceType + "/" + r.Name
+}
+
+// NewResourcesSnapshot creates a new snapshot of the resources
+func NewResourcesSnapshot(name string, resources []ResourceRef) ResourcesSnapshot {
+	resources = append([]ResourceRef{}, resources...)
+	sortResourceRefs(resources)
+	return ResourcesSnapshot{
+		TypeMeta: types.TypeMeta{
+			Kind:       string(ResourcesSnapshotKind),
+			APIVersion: types.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: types.ObjectMeta{
+			Name: name,
+		},
+		Spec: ResourcesSnapshotSpec{
+			Resources: resources,
+		},
+	}
+}
+
+// ReadResourcesSnapshots reads the snapshots in the history directory of the output directory, from the oldest to the newest
+func ReadResourcesSnapshots(outputPath string) ([]ResourcesSnapshot, error) {
+	snapshots := []ResourcesSnapshot{}
+	historyPath := filepath.Join(outputPath, common.HistoryDir)
+	files, err := ioutil.ReadDir(historyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshots, nil
+		}
+		return snapshots, err
+	}
+	// ReadDir sorts the files by name
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+		snapshotPath := filepath.Join(historyPath, file.Name())
+		snapshot := ResourcesSnapshot{}
+		if err := common.ReadMove2KubeYaml(snapshotPath, &snapshot); err != nil {
+			log.Warnf("Failed to read the snapshot of the resources at path %s . Error: %q", snapshotPath, err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// WriteResourcesSnapshot adds the snapshot to the history directory of the output directory and returns its path
+func WriteResourcesSnapshot(outputPath string, snapshot ResourcesSnapshot) (string, error) {
+	historyPath := filepath.Join(outputPath, common.HistoryDir)
+	if err := os.MkdirAll(historyPath, common.DefaultDirectoryPermission); err != nil {
+		return "", err
+	}
+	snapshotPath := filepath.Join(historyPath, time.Now().UTC().Format(snapshotTimeFormat)+".yaml")
+	return snapshotPath, common.WriteYaml(snapshotPath, snapshot)
+}
+
+// GetObsoleteResources returns the resources in any of the snapshots which are not among the current resources. The
+// APIs are ignored, since a resource is the same when it is generated using a newer API, like a deployment moved from
+// extensions/v1beta1 to apps/v1.
+func GetObsoleteResources(snapshots []ResourcesSnapshot, current []ResourceRef) []ResourceRef {
+	seen := map[string]bool{}
+	for _, resource := range current {
+		seen[resource.getKey()] = true
+	}
+	obsolete := []ResourceRef{}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		for _, resource := range snapshots[i].Spec.Resources {
+			if key := resource.getKey(); !seen[key] {
+				seen[key] = true
+				obsolete = append(obsolete, resource)
+			}
+		}
+	}
+	sortResourceRefs(obsolete)
+	return obsolete
+}
+
+// getKey returns the kind, namespace and name of the resource
+func (r ResourceRef) getKey() string {
+	return strings.Join([]string{r.Kind, r.Namespace, r.Name}, "/")
+}
+
+func sortResourceRefs(resources []ResourceRef) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Namespace != resources[j].Namespace {
+			return resources[i].Namespace < resources[j].Namespace
+		}
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		if resources[i].Name != resources[j].Name {
+			return resources[i].Name < resources[j].Name
+		}
+		return resources[i].APIVersion < resources[j].APIVersion
+	})
+}
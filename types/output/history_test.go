@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/move2kube/types/output"
+)
+
+func TestResourcesSnapshots(t *testing.T) {
+	outputPath := t.TempDir()
+	if snapshots, err := output.ReadResourcesSnapshots(outputPath); err != nil || len(snapshots) != 0 {
+		t.Fatalf("Expected no snapshots before the first translation. Actual: %+v Error: %v", snapshots, err)
+	}
+
+	web := output.ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+	webService := output.ResourceRef{APIVersion: "v1", Kind: "Service", Name: "web"}
+	worker := output.ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "worker", Namespace: "jobs"}
+	oldWorker := output.ResourceRef{APIVersion: "extensions/v1beta1", Kind: "Deployment", Name: "worker", Namespace: "jobs"}
+	for _, resources := range [][]output.ResourceRef{{web, webService}, {web, webService, worker}} {
+		if _, err := output.WriteResourcesSnapshot(outputPath, output.NewResourcesSnapshot("myproject", resources)); err != nil {
+			t.Fatalf("Failed to write the snapshot. Error: %q", err)
+		}
+	}
+	snapshots, err := output.ReadResourcesSnapshots(outputPath)
+	if err != nil || len(snapshots) != 2 || len(snapshots[1].Spec.Resources) != 3 {
+		t.Fatalf("Failed to read the snapshots in order. Actual: %+v Error: %v", snapshots, err)
+	}
+
+	if obsolete := output.GetObsoleteResources(snapshots, []output.ResourceRef{web, webService, worker}); len(obsolete) != 0 {
+		t.Fatalf("Expected no obsolete resources. Actual: %+v", obsolete)
+	}
+	if obsolete := output.GetObsoleteResources(snapshots, []output.ResourceRef{web, oldWorker}); !reflect.DeepEqual(obsolete, []output.ResourceRef{webService}) {
+		t.Fatalf("Expected only the removed service to be obsolete, regardless of the API versions. Actual: %+v", obsolete)
+	}
+	obsolete := output.GetObsoleteResources(snapshots, []output.ResourceRef{webService})
+	if !reflect.DeepEqual(obsolete, []output.ResourceRef{web, worker}) {
+		t.Fatalf("Failed to get the obsolete resources. Actual: %+v", obsolete)
+	}
+	if name := obsolete[0].KubectlName(); name != "deployment.v1.apps/web" {
+		t.Fatalf("Failed to get the kubectl name of the resource. Actual: %s", name)
+	}
+	if name := webService.KubectlName(); name != "service/web" {
+		t.Fatalf("Failed to get the kubectl name of the core resource. Actual: %s", name)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ManifestKind is kind of the manifest file
+const ManifestKind types.Kind = "Manifest"
+
+// Manifest records the checksums of the generated artifacts and the inputs used to generate them
+type Manifest struct {
+	types.TypeMeta   `yaml:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty"`
+	Spec             ManifestSpec `yaml:"spec"`
+}
+
+// ManifestSpec stores the provenance and the checksums of the generated artifacts
+type ManifestSpec struct {
+	GeneratorVersion string         `yaml:"generatorVersion"`
+	PlanSHA256       string         `yaml:"planSHA256"`
+	QAAnswersSHA256  string         `yaml:"qaAnswersSHA256,omitempty"`
+	Files            []ManifestFile `yaml:"files"`
+	// Images are the images built locally during the translation, when --build-images is used
+	Images []ManifestImage `yaml:"images,omitempty"`
+}
+
+// ManifestFile is a generated file along with its checksum
+type ManifestFile struct {
+	// Path is the slash separated path of the file relative to the output directory
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// ManifestImage is an image built locally along with its digest
+type ManifestImage struct {
+	Name string `yaml:"name"`
+	// Digest is the sha256 digest of the image config, which is the image ID shown by docker and podman
+	Digest string `yaml:"digest"`
+}
+
+// ManifestDiff contains the files that changed since the manifest was created
+type ManifestDiff struct {
+	Modified []string
+	Missing  []string
+	Added    []string
+}
+
+// IsEmpty returns true if none of the files changed
+func (d ManifestDiff) IsEmpty() bool {
+	return len(d.Modified) == 0 && len(d.Missing) == 0 && len(d.Added) == 0
+}
+
+// NewManifest creates a new manifest
+func NewManifest(name, generatorVersion string) Manifest {
+	return Manifest{
+		TypeMeta: types.TypeMeta{
+			Kind:       string(ManifestKind),
+			APIVersion: types.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: types.ObjectMeta{
+			Name: name,
+		},
+		Spec: ManifestSpec{
+			GeneratorVersion: generatorVersion,
+			Files:            []ManifestFile{},
+		},
+	}
+}
+
+// AddFiles records the checksums of all the files in the output directory except the manifest file
+func (m *Manifest) AddFiles(outputPath string) error {
+	checksums, err := getChecksums(outputPath)
+	if err != nil {
+		return err
+	}
+	paths := []string{}
+	for path := range checksums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		m.Spec.Files = append(m.Spec.Files, ManifestFile{Path: path, SHA256: checksums[path]})
+	}
+	return nil
+}
+
+// Verify compares the files in the output directory with the checksums in the manifest
+func (m *Manifest) Verify(outputPath string) (ManifestDiff, error) {
+	diff := ManifestDiff{Modified: []string{}, Missing: []string{}, Added: []string{}}
+	checksums, err := getChecksums(outputPath)
+	if err != nil {
+		return diff, err
+	}
+	for _, file := range m.Spec.Files {
+		checksum, ok := checksums[file.Path]
+		if !ok {
+			diff.Missing = append(diff.Missing, file.Path)
+			continue
+		}
+		if checksum != file.SHA256 {
+			diff.Modified = append(diff.Modified, file.Path)
+		}
+		delete(checksums, file.Path)
+	}
+	for path := range checksums {
+		diff.Added = append(diff.Added, path)
+	}
+	sort.Strings(diff.Added)
+	return diff, nil
+}
+
+// ReadManifest reads the manifest file in the output directory
+func ReadManifest(outputPath string) (Manifest, error) {
+	manifest := Manifest{}
+	manifestPath := filepath.Join(outputPath, common.ManifestFile)
+	if err := common.ReadMove2KubeYaml(manifestPath, &manifest); err != nil {
+		log.Errorf("Failed to read the manifest file at path %s . Error: %q", manifestPath, err)
+		return manifest, err
+	}
+	return manifest, nil
+}
+
+// WriteManifest writes the manifest file to the output directory
+func WriteManifest(outputPath string, manifest Manifest) error {
+	return common.WriteYaml(filepath.Join(outputPath, common.ManifestFile), manifest)
+}
+
+// getChecksums returns the checksums of the files in the output directory keyed by their slash separated relative paths
+func getChecksums(outputPath string) (map[string]string, error) {
+	checksums := map[string]string{}
+	err := filepath.Walk(outputPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(outputPath, path)
+		if err != nil {
+			return err
+		}
+		if relPath == common.ManifestFile {
+			return nil
+		}
+		checksum, err := common.GetFileSHA256Hash(path)
+		if err != nil {
+			log.Errorf("Failed to calculate the checksum of the file at path %s . Error: %q", path, err)
+			return err
+		}
+		checksums[filepath.ToSlash(relPath)] = checksum
+		return nil
+	})
+	return checksums, err
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/konveyor/move2kube/types/output"
+)
+
+func TestManifest(t *testing.T) {
+	outputPath := t.TempDir()
+	files := map[string]string{
+		"deploy/svc1-deployment.yaml": "kind: Deployment",
+		"deploy/svc1-service.yaml":    "kind: Service",
+		"scripts/deploy.sh":           "kubectl apply -f deploy",
+	}
+	for path, contents := range files {
+		fullPath := filepath.Join(outputPath, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create the directory for %s . Error: %q", path, err)
+		}
+		if err := ioutil.WriteFile(fullPath, []byte(contents), 0644); err != nil {
+			t.Fatalf("Failed to write the file %s . Error: %q", path, err)
+		}
+	}
+
+	manifest := output.NewManifest("myproject", "v0.1.0")
+	if err := manifest.AddFiles(outputPath); err != nil {
+		t.Fatalf("Failed to add the files to the manifest. Error: %q", err)
+	}
+	if len(manifest.Spec.Files) != 3 || manifest.Spec.Files[0].Path != "deploy/svc1-deployment.yaml" || manifest.Spec.Files[2].Path != "scripts/deploy.sh" {
+		t.Fatalf("Expected the files to be sorted by path. Actual: %+v", manifest.Spec.Files)
+	}
+	manifest.Spec.Images = []output.ManifestImage{{Name: "quay.io/myproject/svc1:latest", Digest: "sha256:4c2f1a"}}
+	if err := output.WriteManifest(outputPath, manifest); err != nil {
+		t.Fatalf("Failed to write the manifest. Error: %q", err)
+	}
+
+	t.Run("read the manifest with the built images", func(t *testing.T) {
+		readManifest, err := output.ReadManifest(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read the manifest. Error: %q", err)
+		}
+		if !reflect.DeepEqual(readManifest.Spec.Images, manifest.Spec.Images) {
+			t.Fatalf("Failed to read the built images. Difference:\n%s", cmp.Diff(manifest.Spec.Images, readManifest.Spec.Images))
+		}
+	})
+
+	t.Run("verify unchanged artifacts", func(t *testing.T) {
+		diff, err := manifest.Verify(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to verify the artifacts. Error: %q", err)
+		}
+		if !diff.IsEmpty() {
+			t.Fatalf("Expected no changes. Actual: %+v", diff)
+		}
+	})
+
+	t.Run("verify manually edited artifacts", func(t *testing.T) {
+		if err := ioutil.WriteFile(filepath.Join(outputPath, "deploy", "svc1-service.yaml"), []byte("kind: Service\n# edited"), 0644); err != nil {
+			t.Fatalf("Failed to edit the file. Error: %q", err)
+		}
+		if err := os.Remove(filepath.Join(outputPath, "scripts", "deploy.sh")); err != nil {
+			t.Fatalf("Failed to remove the file. Error: %q", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(outputPath, "deploy", "svc1-route.yaml"), []byte("kind: Route"), 0644); err != nil {
+			t.Fatalf("Failed to add the file. Error: %q", err)
+		}
+		want := output.ManifestDiff{
+			Modified: []string{"deploy/svc1-service.yaml"},
+			Missing:  []string{"scripts/deploy.sh"},
+			Added:    []string{"deploy/svc1-route.yaml"},
+		}
+		diff, err := manifest.Verify(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to verify the artifacts. Error: %q", err)
+		}
+		if !reflect.DeepEqual(diff, want) {
+			t.Fatalf("Failed to detect the changes. Difference:\n%s", cmp.Diff(want, diff))
+		}
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ProgressKind is kind of the progress file
+const ProgressKind types.Kind = "Progress"
+
+// ProgressStatus is the status of the translation recorded in the progress file
+type ProgressStatus string
+
+const (
+	// InProgressProgressStatus is the status of a translation which is running or which was interrupted
+	InProgressProgressStatus ProgressStatus = "InProgress"
+	// CompletedProgressStatus is the status of a translation which wrote all its artifacts
+	CompletedProgressStatus ProgressStatus = "Completed"
+)
+
+// Progress records the services whose artifacts are committed to the output directory
+type Progress struct {
+	types.TypeMeta   `yaml:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty"`
+	Spec             ProgressSpec `yaml:"spec"`
+}
+
+// ProgressSpec stores the status of the translation of each service
+type ProgressSpec struct {
+	Status ProgressStatus `yaml:"status"`
+	// Committed are the services whose artifacts are complete in the output directory
+	Committed []string `yaml:"committed"`
+	// Failed are the services which were skipped because one of their steps failed
+	Failed []string `yaml:"failed,omitempty"`
+	// Remaining are the services which were not translated yet
+	Remaining []string `yaml:"remaining"`
+}
+
+// NewProgress creates a new progress with all the services remaining
+func NewProgress(name string, services []string) Progress {
+	remaining := append([]string{}, services...)
+	sort.Strings(remaining)
+	return Progress{
+		TypeMeta: types.TypeMeta{
+			Kind:       string(ProgressKind),
+			APIVersion: types.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: types.ObjectMeta{
+			Name: name,
+		},
+		Spec: ProgressSpec{
+			Status:    InProgressProgressStatus,
+			Committed: []string{},
+			Remaining: remaining,
+		},
+	}
+}
+
+// Commit records that the artifacts of the service are complete in the output directory
+func (p *Progress) Commit(service string) {
+	p.Spec.Remaining = removeString(p.Spec.Remaining, service)
+	p.Spec.Failed = removeString(p.Spec.Failed, service)
+	if !common.IsStringPresent(p.Spec.Committed, service) {
+		p.Spec.Committed = append(p.Spec.Committed, service)
+	}
+}
+
+// Fail records that the service was skipped
+func (p *Progress) Fail(service string) {
+	p.Spec.Remaining = removeString(p.Spec.Remaining, service)
+	if !common.IsStringPresent(p.Spec.Failed, service) && !common.IsStringPresent(p.Spec.Committed, service) {
+		p.Spec.Failed = append(p.Spec.Failed, service)
+	}
+}
+
+// Complete records that the translation wrote all its artifacts. The services which are still remaining were
+// translated without containerization artifacts, so they are committed.
+func (p *Progress) Complete() {
+	for _, service := range append([]string{}, p.Spec.Remaining...) {
+		p.Commit(service)
+	}
+	p.Spec.Status = CompletedProgressStatus
+}
+
+// ReadProgress reads the progress file in the output directory
+func ReadProgress(outputPath string) (Progress, error) {
+	progress := Progress{}
+	progressPath := filepath.Join(outputPath, common.ProgressFile)
+	if err := common.ReadMove2KubeYaml(progressPath, &progress); err != nil {
+		log.Debugf("Failed to read the progress file at path %s . Error: %q", progressPath, err)
+		return progress, err
+	}
+	return progress, nil
+}
+
+// WriteProgress writes the progress file to the output directory. The file is replaced atomically, so that it is
+// complete even if the translation crashes while writing it.
+func WriteProgress(outputPath string, progress Progress) error {
+	progressPath := filepath.Join(outputPath, common.ProgressFile)
+	tempPath := progressPath + ".tmp"
+	if err := common.WriteYaml(tempPath, progress); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, progressPath)
+}
+
+// removeString returns the slice without the value
+func removeString(xs []string, value string) []string {
+	filtered := []string{}
+	for _, x := range xs {
+		if x != value {
+			filtered = append(filtered, x)
+		}
+	}
+	return filtered
+}
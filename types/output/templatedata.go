@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+// TemplateData is the data model passed to the templates of the generated artifacts and to the custom templates in the
+// templates directory of the customizations. The custom templates get it as their data, like {{ .Name }}, and all the
+// templates can access it using the project function, like {{ project.Name }}.
+// Fields are only ever added to the data model, so that the custom templates keep working across versions.
+type TemplateData struct {
+	// Name is the name of the project
+	Name string `yaml:"name"`
+	// Services are the services being translated, sorted by name
+	Services []TemplateService `yaml:"services"`
+	// Images are the images used by the services, sorted by name
+	Images []TemplateImage `yaml:"images"`
+	// Cluster is the target cluster
+	Cluster TemplateCluster `yaml:"cluster"`
+}
+
+// TemplateService is a service in the template data model
+type TemplateService struct {
+	// Name is the name of the service
+	Name string `yaml:"name"`
+	// Replicas is the number of pods of the service
+	Replicas int `yaml:"replicas"`
+	// Exposed is true if the service is exposed outside the cluster
+	Exposed bool `yaml:"exposed"`
+	// Path is the path on which the service is exposed
+	Path string `yaml:"path,omitempty"`
+	// Containers are the containers of the pods of the service
+	Containers []TemplateContainer `yaml:"containers"`
+}
+
+// TemplateContainer is a container of a service in the template data model
+type TemplateContainer struct {
+	// Name is the name of the container
+	Name string `yaml:"name"`
+	// Image is the image of the container
+	Image string `yaml:"image"`
+	// Ports are the ports the container listens on
+	Ports []int32 `yaml:"ports,omitempty"`
+	// Envs are the environment variables with literal values. Those read from secrets or config maps are not included.
+	Envs map[string]string `yaml:"envs,omitempty"`
+}
+
+// TemplateImage is an image in the template data model
+type TemplateImage struct {
+	// Name is the name of the image including the tag
+	Name string `yaml:"name"`
+	// New is true if the image is built from the source instead of reusing an existing image
+	New bool `yaml:"new"`
+	// BuildType is the method used to build the image, like Dockerfile or CNB
+	BuildType string `yaml:"buildType,omitempty"`
+}
+
+// TemplateCluster is the target cluster in the template data model
+type TemplateCluster struct {
+	// Type is the type of the target cluster, like Kubernetes or Openshift
+	Type string `yaml:"type,omitempty"`
+	// Host is the host on which the services are exposed
+	Host string `yaml:"host,omitempty"`
+	// StorageClasses are the storage classes available in the cluster
+	StorageClasses []string `yaml:"storageClasses,omitempty"`
+	// RegistryURL is the registry to which the new images are pushed
+	RegistryURL string `yaml:"registryURL,omitempty"`
+	// RegistryNamespace is the namespace in the registry to which the new images are pushed
+	RegistryNamespace string `yaml:"registryNamespace,omitempty"`
+}
@@ -0,0 +1,24 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+// SetPlanMigration replaces the migrations of the older plan versions with the migration of the given version and returns a function which restores them
+func SetPlanMigration(version string, migrate func(plan map[string]interface{}) error) func() {
+	origMigrations := planMigrations
+	planMigrations = []planMigration{{version: version, migrate: migrate}}
+	return func() { planMigrations = origMigrations }
+}
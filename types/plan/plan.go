@@ -17,12 +17,16 @@ limitations under the License.
 package plan
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/types"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // SourceTypeValue defines the type of source
@@ -60,6 +64,22 @@ const (
 	Kube2KubeTranslation TranslationTypeValue = "Kubernetes"
 	// Dockerfile2KubeTranslation translation type is used when source is Knative
 	Dockerfile2KubeTranslation TranslationTypeValue = "Dockerfile"
+	// Heroku2KubeTranslation translation type is used when source is a Heroku app
+	Heroku2KubeTranslation TranslationTypeValue = "Heroku"
+	// CloudRun2KubeTranslation translation type is used when source is a Google Cloud Run service
+	CloudRun2KubeTranslation TranslationTypeValue = "CloudRun"
+	// AppEngine2KubeTranslation translation type is used when source is a Google App Engine app
+	AppEngine2KubeTranslation TranslationTypeValue = "AppEngine"
+	// Azure2KubeTranslation translation type is used when source is an Azure container app or container group
+	Azure2KubeTranslation TranslationTypeValue = "Azure"
+	// Swarm2KubeTranslation translation type is used when source is a Docker Swarm stack file
+	Swarm2KubeTranslation TranslationTypeValue = "DockerSwarm"
+	// ECS2KubeTranslation translation type is used when source is an Amazon ECS task definition
+	ECS2KubeTranslation TranslationTypeValue = "ECS"
+	// Nomad2KubeTranslation translation type is used when source is a HashiCorp Nomad job
+	Nomad2KubeTranslation TranslationTypeValue = "Nomad"
+	// CloudFormation2KubeTranslation translation type is used when source is an AWS CloudFormation or SAM template
+	CloudFormation2KubeTranslation TranslationTypeValue = "CloudFormation"
 )
 
 const (
@@ -73,6 +93,24 @@ const (
 	KNativeSourceTypeValue SourceTypeValue = "Knative"
 	// K8sSourceTypeValue defines the source as Kubernetes
 	K8sSourceTypeValue SourceTypeValue = "Kubernetes"
+	// HerokuSourceTypeValue defines the source as a Heroku app
+	HerokuSourceTypeValue SourceTypeValue = "Heroku"
+	// CloudRunSourceTypeValue defines the source as a Google Cloud Run service
+	CloudRunSourceTypeValue SourceTypeValue = "CloudRun"
+	// AppEngineSourceTypeValue defines the source as a Google App Engine app
+	AppEngineSourceTypeValue SourceTypeValue = "AppEngine"
+	// AzureContainerAppsSourceTypeValue defines the source as an Azure Container Apps app
+	AzureContainerAppsSourceTypeValue SourceTypeValue = "AzureContainerApps"
+	// AzureContainerInstancesSourceTypeValue defines the source as an Azure Container Instances container group
+	AzureContainerInstancesSourceTypeValue SourceTypeValue = "AzureContainerInstances"
+	// SwarmStackSourceTypeValue defines the source as a Docker Swarm stack
+	SwarmStackSourceTypeValue SourceTypeValue = "DockerSwarmStack"
+	// ECSSourceTypeValue defines the source as an Amazon ECS task definition
+	ECSSourceTypeValue SourceTypeValue = "ECS"
+	// NomadSourceTypeValue defines the source as a HashiCorp Nomad job
+	NomadSourceTypeValue SourceTypeValue = "Nomad"
+	// CloudFormationSourceTypeValue defines the source as an AWS CloudFormation or SAM template
+	CloudFormationSourceTypeValue SourceTypeValue = "CloudFormation"
 )
 
 const (
@@ -107,6 +145,30 @@ const (
 	SourceDirectoryArtifactType SourceArtifactTypeValue = "SourceCode"
 	// DockerfileArtifactType defines the source artifact type of dockerfile
 	DockerfileArtifactType SourceArtifactTypeValue = "Dockerfile"
+	// ProcfileArtifactType defines the source artifact type of the Procfile of a Heroku app
+	ProcfileArtifactType SourceArtifactTypeValue = "Procfile"
+	// HerokuAppJSONArtifactType defines the source artifact type of the app.json of a Heroku app
+	HerokuAppJSONArtifactType SourceArtifactTypeValue = "HerokuAppJSON"
+	// HerokuYamlArtifactType defines the source artifact type of the heroku.yml of a Heroku app
+	HerokuYamlArtifactType SourceArtifactTypeValue = "HerokuYaml"
+	// HerokuAppsArtifactType defines the source artifact type of the apps collected from Heroku
+	HerokuAppsArtifactType SourceArtifactTypeValue = "HerokuApps"
+	// CloudRunServiceArtifactType defines the source artifact type of the yaml of a Cloud Run service
+	CloudRunServiceArtifactType SourceArtifactTypeValue = "CloudRunService"
+	// AppEngineAppYamlArtifactType defines the source artifact type of the app.yaml of an App Engine app
+	AppEngineAppYamlArtifactType SourceArtifactTypeValue = "AppEngineAppYaml"
+	// AzureResourcesArtifactType defines the source artifact type of an ARM template or export containing Azure container apps or container groups
+	AzureResourcesArtifactType SourceArtifactTypeValue = "AzureResources"
+	// SwarmStackArtifactType defines the source artifact type of a Docker Swarm stack file
+	SwarmStackArtifactType SourceArtifactTypeValue = "DockerSwarmStack"
+	// ECSTaskDefinitionArtifactType defines the source artifact type of the JSON of an ECS task definition
+	ECSTaskDefinitionArtifactType SourceArtifactTypeValue = "ECSTaskDefinition"
+	// ECSServiceArtifactType defines the source artifact type of the JSON of an ECS service running a task definition
+	ECSServiceArtifactType SourceArtifactTypeValue = "ECSService"
+	// NomadJobArtifactType defines the source artifact type of the HCL file of a Nomad job
+	NomadJobArtifactType SourceArtifactTypeValue = "NomadJob"
+	// CloudFormationTemplateArtifactType defines the source artifact type of an AWS CloudFormation or SAM template
+	CloudFormationTemplateArtifactType SourceArtifactTypeValue = "CloudFormationTemplate"
 )
 
 const (
@@ -128,21 +190,141 @@ type Plan struct {
 
 // PlanSpec stores the data about the plan
 type PlanSpec struct {
-	Inputs  Inputs  `yaml:"inputs"`
-	Outputs Outputs `yaml:"outputs"`
+	Inputs   Inputs    `yaml:"inputs"`
+	Outputs  Outputs   `yaml:"outputs"`
+	Profiles []Profile `yaml:"profiles,omitempty"` // The environments, like dev and prod, for which kustomize overlays are generated
+}
+
+// Profile defines the overrides of an environment, which are applied by its kustomize overlay
+type Profile struct {
+	Name              string `yaml:"name"`
+	RegistryURL       string `yaml:"registryURL,omitempty"`
+	RegistryNamespace string `yaml:"registryNamespace,omitempty"`
+	Namespace         string `yaml:"namespace,omitempty"`
+	Replicas          int    `yaml:"replicas,omitempty"` // The replica count of the deployments and the stateful sets
+	IngressHost       string `yaml:"ingressHost,omitempty"`
+}
+
+// ValidateProfiles checks that the profiles have unique names which can be used as directory names
+func (spec PlanSpec) ValidateProfiles() error {
+	names := map[string]bool{}
+	for _, profile := range spec.Profiles {
+		if !isDirectoryName(profile.Name) {
+			return fmt.Errorf("the profile name %q is not a valid directory name", profile.Name)
+		}
+		if names[profile.Name] {
+			return fmt.Errorf("there are multiple profiles with the name %s", profile.Name)
+		}
+		names[profile.Name] = true
+		if profile.Replicas < 0 {
+			return fmt.Errorf("the profile %s has the negative replica count %d", profile.Name, profile.Replicas)
+		}
+	}
+	return nil
+}
+
+func isDirectoryName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
 }
 
 // Outputs defines the output section of plan
 type Outputs struct {
-	Kubernetes KubernetesOutput `yaml:"kubernetes"`
+	Kubernetes           KubernetesOutput                        `yaml:"kubernetes"`
+	HostPathRemediations map[string]HostPathRemediationTypeValue `yaml:"hostPathRemediations,omitempty"` // [host path][remediation] The decisions taken for the host paths, so that a translation can be reproduced
+	Targets              []OutputTarget                          `yaml:"targets,omitempty"`              // The outputs generated in the subdirectories named after them. If empty, a single Kubernetes output is generated.
+}
+
+// OutputTargetTypeValue defines the type of the artifacts generated for an output target
+type OutputTargetTypeValue string
+
+const (
+	// KubernetesOutputTargetType generates the yamls, the Helm chart, the kustomize and the scripts for a Kubernetes cluster
+	KubernetesOutputTargetType OutputTargetTypeValue = "Kubernetes"
+	// OpenShiftOutputTargetType generates the Kubernetes artifacts along with the OpenShift templates, routes and build configs
+	OpenShiftOutputTargetType OutputTargetTypeValue = "OpenShift"
+	// KnativeOutputTargetType generates the Knative services
+	KnativeOutputTargetType OutputTargetTypeValue = "Knative"
+	// HelmOutputTargetType generates only the Helm chart
+	HelmOutputTargetType OutputTargetTypeValue = "Helm"
+)
+
+// openShiftClusterType is the cluster type of the OpenShift output targets which do not specify a target cluster
+const openShiftClusterType = "Openshift"
+
+var outputTargetTypes = []OutputTargetTypeValue{KubernetesOutputTargetType, OpenShiftOutputTargetType, KnativeOutputTargetType, HelmOutputTargetType}
+
+// OutputTarget defines an output generated into its own subdirectory of the output directory
+type OutputTarget struct {
+	Name       string                `yaml:"name"`
+	Type       OutputTargetTypeValue `yaml:"type"`
+	Kubernetes KubernetesOutput      `yaml:"kubernetes,omitempty"` // Overrides the kubernetes output of the plan for this target
+}
+
+// GetKubernetesOutput returns the kubernetes output of the plan with the overrides of the target
+func (target OutputTarget) GetKubernetesOutput(output KubernetesOutput) KubernetesOutput {
+	if target.Kubernetes.RegistryURL != "" {
+		output.RegistryURL = target.Kubernetes.RegistryURL
+	}
+	if target.Kubernetes.RegistryNamespace != "" {
+		output.RegistryNamespace = target.Kubernetes.RegistryNamespace
+	}
+	if target.Kubernetes.TargetCluster != (TargetClusterType{}) {
+		output.TargetCluster = target.Kubernetes.TargetCluster
+	} else if target.Type == OpenShiftOutputTargetType {
+		output.TargetCluster = TargetClusterType{Type: openShiftClusterType}
+	}
+	output.IgnoreUnsupportedKinds = output.IgnoreUnsupportedKinds || target.Kubernetes.IgnoreUnsupportedKinds
+	output.ConfigChecksums = output.ConfigChecksums || target.Kubernetes.ConfigChecksums
+	return output
 }
 
+// ValidateTargets checks that the output targets have known types and unique names which can be used as directory names
+func (outputs Outputs) ValidateTargets() error {
+	names := map[string]bool{}
+	for _, target := range outputs.Targets {
+		if !isDirectoryName(target.Name) {
+			return fmt.Errorf("the output target name %q is not a valid directory name", target.Name)
+		}
+		if names[target.Name] {
+			return fmt.Errorf("there are multiple output targets with the name %s", target.Name)
+		}
+		names[target.Name] = true
+		isKnown := false
+		for _, targetType := range outputTargetTypes {
+			if target.Type == targetType {
+				isKnown = true
+				break
+			}
+		}
+		if !isKnown {
+			return fmt.Errorf("the output target %s has the unsupported type %s . Supported types are %v", target.Name, target.Type, outputTargetTypes)
+		}
+	}
+	return nil
+}
+
+// HostPathRemediationTypeValue defines how a host path volume is translated
+type HostPathRemediationTypeValue string
+
+const (
+	// PVCHostPathRemediation replaces the host path with a persistent volume claim
+	PVCHostPathRemediation HostPathRemediationTypeValue = "PersistentVolumeClaim"
+	// ConfigMapHostPathRemediation replaces the host path with a config map containing the files at the host path
+	ConfigMapHostPathRemediation HostPathRemediationTypeValue = "ConfigMap"
+	// EmptyDirHostPathRemediation replaces the host path with an empty directory that is deleted along with the pod
+	EmptyDirHostPathRemediation HostPathRemediationTypeValue = "EmptyDir"
+	// RetainHostPathRemediation retains the host path, after the user acknowledged that the pods depend on the files on the nodes
+	RetainHostPathRemediation HostPathRemediationTypeValue = "HostPath"
+)
+
 // KubernetesOutput defines the output format for kubernetes deployable artifacts
 type KubernetesOutput struct {
 	RegistryURL            string            `yaml:"registryURL,omitempty"`
 	RegistryNamespace      string            `yaml:"registryNamespace,omitempty"`
 	TargetCluster          TargetClusterType `yaml:"targetCluster,omitempty"`
 	IgnoreUnsupportedKinds bool              `yaml:"ignoreUnsupportedKinds,omitempty"`
+	// ConfigChecksums annotates the pod templates with the checksum of the config maps and secrets they use, so that the config changes trigger rollouts
+	ConfigChecksums bool `yaml:"configChecksums,omitempty"`
 }
 
 // TargetClusterType contains either the type of the target cluster or path to a file containing the target cluster metadata.
@@ -162,6 +344,7 @@ func (output *KubernetesOutput) Merge(newoutput KubernetesOutput) {
 			output.RegistryNamespace = newoutput.RegistryNamespace
 		}
 		output.IgnoreUnsupportedKinds = newoutput.IgnoreUnsupportedKinds
+		output.ConfigChecksums = newoutput.ConfigChecksums
 		if newoutput.TargetCluster.Type != "" {
 			output.TargetCluster = newoutput.TargetCluster
 		}
@@ -172,10 +355,19 @@ func (output *KubernetesOutput) Merge(newoutput KubernetesOutput) {
 type Inputs struct {
 	RootDir             string                                   `yaml:"rootDir"`
 	K8sFiles            []string                                 `yaml:"kubernetesYamls,omitempty" m2kpath:"normal"`
+	HelmCharts          []HelmChart                              `yaml:"helmCharts,omitempty"`                           // Charts rendered using helm template and translated like the kubernetes yamls
 	Services            map[string][]Service                     `yaml:"services"`                                       // [serviceName][Services]
 	TargetInfoArtifacts map[TargetInfoArtifactTypeValue][]string `yaml:"targetInfoArtifacts,omitempty" m2kpath:"normal"` //[targetinfoartifacttype][List of artifacts]
 }
 
+// HelmChart is a Helm chart whose rendered resources are translated like the kubernetes yamls
+type HelmChart struct {
+	Path        string   `yaml:"path" m2kpath:"normal"`                  // The directory containing the Chart.yaml
+	ReleaseName string   `yaml:"releaseName,omitempty"`                  // Defaults to the name of the chart directory
+	Namespace   string   `yaml:"namespace,omitempty"`                    // The namespace the chart is rendered for
+	ValuesFiles []string `yaml:"valuesFiles,omitempty" m2kpath:"normal"` // Values files applied in order over the values.yaml of the chart
+}
+
 // RepoInfo contains information specific to creating the CI/CD pipeline.
 type RepoInfo struct {
 	GitRepoDir    string `yaml:"gitRepoDir" m2kpath:"normal"`
@@ -193,11 +385,424 @@ type Service struct {
 	ContainerBuildType            ContainerBuildTypeValue              `yaml:"containerBuildType"`
 	SourceTypes                   []SourceTypeValue                    `yaml:"sourceType"`
 	ContainerizationTargetOptions []string                             `yaml:"targetOptions,omitempty" m2kpath:"if:ContainerBuildType:in:NewDockerfile,ReuseDockerfile,S2I"`
-	SourceArtifacts               map[SourceArtifactTypeValue][]string `yaml:"sourceArtifacts" m2kpath:"keys:Kubernetes,Knative,DockerCompose,CfManifest,CfRunningManifest,SourceCode,Dockerfile"` //[translationartifacttype][List of artifacts]
-	BuildArtifacts                map[BuildArtifactTypeValue][]string  `yaml:"buildArtifacts,omitempty" m2kpath:"normal"`                                                                          //[buildartifacttype][List of artifacts]
+	SourceArtifacts               map[SourceArtifactTypeValue][]string `yaml:"sourceArtifacts" m2kpath:"keys:Kubernetes,Knative,DockerCompose,CfManifest,CfRunningManifest,SourceCode,Dockerfile,Procfile,HerokuAppJSON,HerokuYaml,HerokuApps,CloudRunService,AppEngineAppYaml,AzureResources,DockerSwarmStack,ECSTaskDefinition,ECSService,NomadJob,CloudFormationTemplate"` //[translationartifacttype][List of artifacts]
+	BuildArtifacts                map[BuildArtifactTypeValue][]string  `yaml:"buildArtifacts,omitempty" m2kpath:"normal"`                                                                                                                                                                                                                                                     //[buildartifacttype][List of artifacts]
 	UpdateContainerBuildPipeline  bool                                 `yaml:"updateContainerBuildPipeline"`
 	UpdateDeployPipeline          bool                                 `yaml:"updateDeployPipeline"`
 	RepoInfo                      RepoInfo                             `yaml:"repoInfo,omitempty"`
+	Detection                     Detection                            `yaml:"detection,omitempty"`
+	BuildSteps                    BuildSteps                           `yaml:"buildSteps,omitempty"`
+	StaticSite                    StaticSite                           `yaml:"staticSite,omitempty"`
+	Ports                         []Port                               `yaml:"ports,omitempty"`
+	Runtime                       RuntimeSpec                          `yaml:"runtime,omitempty"`
+	DependsOn                     []string                             `yaml:"dependsOn,omitempty"` // Services which have to be ready before the service is started
+	Resources                     ResourceSpec                         `yaml:"resources,omitempty"`
+	Analysis                      Analysis                             `yaml:"analysis,omitempty"` // The issues found in the source by a rule engine
+}
+
+// PortProtocolTypeValue defines the protocol of a port
+type PortProtocolTypeValue string
+
+const (
+	// TCPPortProtocol is the TCP protocol, which is used when the protocol of a port is not set
+	TCPPortProtocol PortProtocolTypeValue = "TCP"
+	// UDPPortProtocol is the UDP protocol
+	UDPPortProtocol PortProtocolTypeValue = "UDP"
+	// SCTPPortProtocol is the SCTP protocol
+	SCTPPortProtocol PortProtocolTypeValue = "SCTP"
+)
+
+// PortExposeTypeValue defines how a port of a service is exposed
+type PortExposeTypeValue string
+
+const (
+	// IngressPortExpose exposes the port using the HTTP paths of the ingress
+	IngressPortExpose PortExposeTypeValue = "Ingress"
+	// ClusterIPPortExpose exposes the port only inside the cluster
+	ClusterIPPortExpose PortExposeTypeValue = "ClusterIP"
+	// LoadBalancerPortExpose exposes the port using another service of type LoadBalancer
+	LoadBalancerPortExpose PortExposeTypeValue = "LoadBalancer"
+	// NodePortPortExpose exposes the port using another service of type NodePort
+	NodePortPortExpose PortExposeTypeValue = "NodePort"
+	// IngressNginxPortExpose exposes the port on the ingress-nginx controller using its tcp-services and udp-services config maps
+	IngressNginxPortExpose PortExposeTypeValue = "IngressNginx"
+)
+
+// portNamePattern is the format of the names of the container ports
+var portNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Port defines a port served by the container of a service
+type Port struct {
+	Name          string                `yaml:"name,omitempty"`
+	ContainerPort int32                 `yaml:"containerPort"`
+	Protocol      PortProtocolTypeValue `yaml:"protocol,omitempty"`
+	Expose        PortExposeTypeValue   `yaml:"expose,omitempty"` // When empty, how the port is exposed is decided during the translation
+}
+
+// GetProtocol returns the protocol of the port, which defaults to TCP
+func (port Port) GetProtocol() PortProtocolTypeValue {
+	if port.Protocol == "" {
+		return TCPPortProtocol
+	}
+	return port.Protocol
+}
+
+// validate checks the number, the name, the protocol and the exposure of the port
+func (port Port) validate() error {
+	if port.ContainerPort <= 0 || port.ContainerPort > 65535 {
+		return fmt.Errorf("the port number %d is not between 1 and 65535", port.ContainerPort)
+	}
+	if port.Name != "" && (len(port.Name) > 15 || !portNamePattern.MatchString(port.Name)) {
+		return fmt.Errorf("the port name %s must have at most 15 lowercase alphanumeric characters or '-'", port.Name)
+	}
+	switch port.GetProtocol() {
+	case TCPPortProtocol, UDPPortProtocol, SCTPPortProtocol:
+	default:
+		return fmt.Errorf("the port %d has the unsupported protocol %s", port.ContainerPort, port.Protocol)
+	}
+	switch port.Expose {
+	case "", ClusterIPPortExpose, LoadBalancerPortExpose, NodePortPortExpose, IngressNginxPortExpose:
+	case IngressPortExpose:
+		if port.GetProtocol() != TCPPortProtocol {
+			return fmt.Errorf("the %s port %d cannot be exposed using the HTTP paths of the ingress", port.GetProtocol(), port.ContainerPort)
+		}
+	default:
+		return fmt.Errorf("the port %d has the unsupported exposure %s", port.ContainerPort, port.Expose)
+	}
+	return nil
+}
+
+// AddPort adds a port to the service. If the service already has the port, the name and the exposure are set if they are missing.
+func (service *Service) AddPort(port Port) {
+	for i, existingPort := range service.Ports {
+		if existingPort.ContainerPort != port.ContainerPort || existingPort.GetProtocol() != port.GetProtocol() {
+			continue
+		}
+		if existingPort.Name == "" {
+			service.Ports[i].Name = port.Name
+		}
+		if existingPort.Expose == "" {
+			service.Ports[i].Expose = port.Expose
+		}
+		return
+	}
+	service.Ports = append(service.Ports, port)
+}
+
+// StaticSiteServingTypeValue defines how a static site is served
+type StaticSiteServingTypeValue string
+
+const (
+	// NginxStaticSiteServing builds the static site into an nginx image
+	NginxStaticSiteServing StaticSiteServingTypeValue = "Nginx"
+	// ObjectStorageStaticSiteServing uploads the static site to a bucket, which is usually served through a CDN
+	ObjectStorageStaticSiteServing StaticSiteServingTypeValue = "ObjectStorage"
+	// BackendStaticSiteServing builds the static site into the image of a backend service, which serves it
+	BackendStaticSiteServing StaticSiteServingTypeValue = "Backend"
+)
+
+// StaticSite defines a frontend, like a create-react-app, Angular or Vue app, which is built into static files
+type StaticSite struct {
+	Framework string                     `yaml:"framework,omitempty"`
+	BuildDir  string                     `yaml:"buildDir,omitempty"` // The directory of the built files, relative to the source directory
+	Serving   StaticSiteServingTypeValue `yaml:"serving,omitempty"`
+	BucketURL string                     `yaml:"bucketURL,omitempty"` // The bucket to which the built files are uploaded, for the ObjectStorage serving
+}
+
+// RuntimeSpec defines how the main container of a service is run. The fields which are set override the ones of the image.
+type RuntimeSpec struct {
+	Command    []string `yaml:"command,omitempty"`    // Overrides the entrypoint of the image
+	Args       []string `yaml:"args,omitempty"`       // Overrides the cmd of the image
+	Env        []EnvVar `yaml:"env,omitempty"`        // Added to the env of the container, replacing the env vars with the same names
+	WorkingDir string   `yaml:"workingDir,omitempty"` // Absolute path of the working directory
+	User       string   `yaml:"user,omitempty"`       // UID, with an optional GID, like 1000 or 1000:1000
+}
+
+// EnvVar defines an env var of the main container of a service
+type EnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// SetEnv sets the value of the env var, adding it if it is missing
+func (runtime *RuntimeSpec) SetEnv(name, value string) {
+	for i, env := range runtime.Env {
+		if env.Name == name {
+			runtime.Env[i].Value = value
+			return
+		}
+	}
+	runtime.Env = append(runtime.Env, EnvVar{Name: name, Value: value})
+}
+
+// merge sets the fields of the runtime which are missing, and adds the missing env vars
+func (runtime *RuntimeSpec) merge(newruntime RuntimeSpec) {
+	if len(runtime.Command) == 0 {
+		runtime.Command = newruntime.Command
+	}
+	if len(runtime.Args) == 0 {
+		runtime.Args = newruntime.Args
+	}
+	if runtime.WorkingDir == "" {
+		runtime.WorkingDir = newruntime.WorkingDir
+	}
+	if runtime.User == "" {
+		runtime.User = newruntime.User
+	}
+	names := map[string]bool{}
+	for _, env := range runtime.Env {
+		names[env.Name] = true
+	}
+	for _, env := range newruntime.Env {
+		if !names[env.Name] {
+			runtime.Env = append(runtime.Env, env)
+		}
+	}
+}
+
+// validate checks that the env vars have names and that the working directory is absolute
+func (runtime RuntimeSpec) validate() error {
+	for _, env := range runtime.Env {
+		if env.Name == "" {
+			return fmt.Errorf("the env var with the value %s has no name", env.Value)
+		}
+	}
+	if runtime.WorkingDir != "" && !strings.HasPrefix(runtime.WorkingDir, "/") {
+		return fmt.Errorf("the working directory %s is not an absolute path", runtime.WorkingDir)
+	}
+	return nil
+}
+
+// ResourceSpec defines the compute resources of the main container of a service
+type ResourceSpec struct {
+	Requests ResourceQuantities `yaml:"requests,omitempty"` // Reserved for the container when scheduling the pods of the service
+	Limits   ResourceQuantities `yaml:"limits,omitempty"`   // The container is throttled above the cpu limit and killed above the memory limit
+}
+
+// ResourceQuantities defines the cpu and the memory of a container using the quantities of kubernetes
+type ResourceQuantities struct {
+	CPU    string `yaml:"cpu,omitempty"`    // Like 250m or 1
+	Memory string `yaml:"memory,omitempty"` // Like 256Mi or 1Gi
+}
+
+// IsEmpty returns true if neither the cpu nor the memory is set
+func (quantities ResourceQuantities) IsEmpty() bool {
+	return quantities.CPU == "" && quantities.Memory == ""
+}
+
+// merge sets the quantities which are missing
+func (quantities *ResourceQuantities) merge(newquantities ResourceQuantities) {
+	if quantities.CPU == "" {
+		quantities.CPU = newquantities.CPU
+	}
+	if quantities.Memory == "" {
+		quantities.Memory = newquantities.Memory
+	}
+}
+
+// merge sets the requests and the limits which are missing
+func (resources *ResourceSpec) merge(newresources ResourceSpec) {
+	resources.Requests.merge(newresources.Requests)
+	resources.Limits.merge(newresources.Limits)
+}
+
+// validate checks that the quantities are valid and that the requests do not exceed the limits
+func (resources ResourceSpec) validate() error {
+	for _, quantities := range []struct{ name, request, limit string }{
+		{name: "cpu", request: resources.Requests.CPU, limit: resources.Limits.CPU},
+		{name: "memory", request: resources.Requests.Memory, limit: resources.Limits.Memory},
+	} {
+		request, err := parseQuantity(quantities.request)
+		if err != nil {
+			return fmt.Errorf("the %s request %s is not a valid quantity", quantities.name, quantities.request)
+		}
+		limit, err := parseQuantity(quantities.limit)
+		if err != nil {
+			return fmt.Errorf("the %s limit %s is not a valid quantity", quantities.name, quantities.limit)
+		}
+		if request != nil && limit != nil && request.Cmp(*limit) > 0 {
+			return fmt.Errorf("the %s request %s exceeds the %s limit %s", quantities.name, quantities.request, quantities.name, quantities.limit)
+		}
+	}
+	return nil
+}
+
+// parseQuantity returns nil if the quantity is not set
+func parseQuantity(value string) (*resource.Quantity, error) {
+	if value == "" {
+		return nil, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, err
+	}
+	return &quantity, nil
+}
+
+// BuildCompositionTypeValue defines how the build steps of a service are composed with the service
+type BuildCompositionTypeValue string
+
+const (
+	// MultiStageBuildComposition builds the steps as stages of the Dockerfile of the service, so that one image is built
+	MultiStageBuildComposition BuildCompositionTypeValue = "MultiStage"
+	// MultipleImagesBuildComposition builds an image for every step, which runs as another container in the pods of the service
+	MultipleImagesBuildComposition BuildCompositionTypeValue = "MultipleImages"
+)
+
+// BuildSteps defines the build contexts, like a frontend or shared libraries, that are built along with a service
+type BuildSteps struct {
+	Composition BuildCompositionTypeValue `yaml:"composition,omitempty"`
+	Steps       []Service                 `yaml:"steps,omitempty"`     // The steps are built in order, before the service
+	Artifacts   map[string][]string       `yaml:"artifacts,omitempty"` // [step name][source:destination] The paths copied from the stage of a step into the image of the service
+}
+
+// Detection records how confident the analyzers are about the detected container build type of a service, and why
+type Detection struct {
+	Confidence int      `yaml:"confidence"`         // The confidence in percent
+	Evidence   []string `yaml:"evidence,omitempty"` // The files matched and the detectors that matched them
+	Ports      []int    `yaml:"ports,omitempty"`    // The ports inferred from the source
+}
+
+// AnalysisCategoryValue defines how much an issue found by a rule engine blocks the migration
+type AnalysisCategoryValue string
+
+const (
+	// MandatoryAnalysisCategory issues have to be fixed for the service to run on the target
+	MandatoryAnalysisCategory AnalysisCategoryValue = "mandatory"
+	// OptionalAnalysisCategory issues should be fixed to follow the practices of the target
+	OptionalAnalysisCategory AnalysisCategoryValue = "optional"
+	// PotentialAnalysisCategory issues have to be reviewed, since they may block the migration
+	PotentialAnalysisCategory AnalysisCategoryValue = "potential"
+)
+
+// Analysis is the summary of the issues found in the source of a service by a rule engine, like the Konveyor analyzer
+type Analysis struct {
+	Effort int             `yaml:"effort,omitempty"` // The story points of all the incidents
+	Issues []AnalysisIssue `yaml:"issues,omitempty"`
+}
+
+// AnalysisIssue is a rule of the rule engine violated in the source of a service
+type AnalysisIssue struct {
+	RuleSet     string                `yaml:"ruleSet,omitempty"`
+	RuleID      string                `yaml:"ruleID"`
+	Description string                `yaml:"description,omitempty"`
+	Category    AnalysisCategoryValue `yaml:"category,omitempty"`
+	Effort      int                   `yaml:"effort,omitempty"` // The story points of each incident
+	Incidents   int                   `yaml:"incidents"`        // The number of places in the source violating the rule
+}
+
+// GetIssues returns the issues of the category
+func (analysis Analysis) GetIssues(category AnalysisCategoryValue) []AnalysisIssue {
+	issues := []AnalysisIssue{}
+	for _, issue := range analysis.Issues {
+		if issue.Category == category {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// supportedContainerBuildTypes are the container build types each translation type can translate a service with
+var supportedContainerBuildTypes = map[TranslationTypeValue][]ContainerBuildTypeValue{
+	Any2KubeTranslation:            {DockerFileContainerBuildTypeValue, S2IContainerBuildTypeValue, CNBContainerBuildTypeValue},
+	CfManifest2KubeTranslation:     {ReuseContainerBuildTypeValue, DockerFileContainerBuildTypeValue, S2IContainerBuildTypeValue, CNBContainerBuildTypeValue, ManualContainerBuildTypeValue},
+	Compose2KubeTranslation:        {ReuseContainerBuildTypeValue, ReuseDockerFileContainerBuildTypeValue},
+	Dockerfile2KubeTranslation:     {ReuseDockerFileContainerBuildTypeValue},
+	Heroku2KubeTranslation:         {ReuseDockerFileContainerBuildTypeValue, CNBContainerBuildTypeValue, DockerFileContainerBuildTypeValue, S2IContainerBuildTypeValue, ManualContainerBuildTypeValue},
+	CloudRun2KubeTranslation:       {ReuseContainerBuildTypeValue},
+	AppEngine2KubeTranslation:      {ReuseDockerFileContainerBuildTypeValue, CNBContainerBuildTypeValue, DockerFileContainerBuildTypeValue, S2IContainerBuildTypeValue, ManualContainerBuildTypeValue},
+	Azure2KubeTranslation:          {ReuseContainerBuildTypeValue},
+	Swarm2KubeTranslation:          {ReuseContainerBuildTypeValue, ReuseDockerFileContainerBuildTypeValue},
+	ECS2KubeTranslation:            {ReuseContainerBuildTypeValue},
+	Nomad2KubeTranslation:          {ReuseContainerBuildTypeValue},
+	CloudFormation2KubeTranslation: {ReuseContainerBuildTypeValue, ManualContainerBuildTypeValue},
+}
+
+// containerBuildTypesRequiringTargets are the container build types that cannot build an image without a target option
+var containerBuildTypesRequiringTargets = []ContainerBuildTypeValue{
+	DockerFileContainerBuildTypeValue,
+	ReuseDockerFileContainerBuildTypeValue,
+	S2IContainerBuildTypeValue,
+	CNBContainerBuildTypeValue,
+}
+
+// Validate checks that the translation type supports the container build type of the service,
+// since both can be overridden by editing the plan
+func (service *Service) Validate() error {
+	supported, ok := supportedContainerBuildTypes[service.TranslationType]
+	if !ok {
+		return fmt.Errorf("the service %s has the unsupported translation type %s", service.ServiceName, service.TranslationType)
+	}
+	isSupported := false
+	for _, containerBuildType := range supported {
+		if containerBuildType == service.ContainerBuildType {
+			isSupported = true
+			break
+		}
+	}
+	if !isSupported {
+		return fmt.Errorf("the service %s has the container build type %s which is not supported by the translation type %s . Supported container build types: %v", service.ServiceName, service.ContainerBuildType, service.TranslationType, supported)
+	}
+	switch service.StaticSite.Serving {
+	case "":
+	case NginxStaticSiteServing, BackendStaticSiteServing:
+		if service.ContainerBuildType != DockerFileContainerBuildTypeValue {
+			return fmt.Errorf("the static site %s is served by %s and requires the container build type %s", service.ServiceName, service.StaticSite.Serving, DockerFileContainerBuildTypeValue)
+		}
+	case ObjectStorageStaticSiteServing:
+		if service.StaticSite.BucketURL == "" {
+			return fmt.Errorf("the static site %s is uploaded to object storage but has no bucket url", service.ServiceName)
+		}
+	default:
+		return fmt.Errorf("the static site %s has the unsupported serving type %s", service.ServiceName, service.StaticSite.Serving)
+	}
+	for _, port := range service.Ports {
+		if err := port.validate(); err != nil {
+			return fmt.Errorf("invalid port of the service %s : %s", service.ServiceName, err)
+		}
+	}
+	if err := service.Runtime.validate(); err != nil {
+		return fmt.Errorf("invalid runtime of the service %s : %s", service.ServiceName, err)
+	}
+	if err := service.Resources.validate(); err != nil {
+		return fmt.Errorf("invalid resources of the service %s : %s", service.ServiceName, err)
+	}
+	// The Dockerfiles of the static sites are generated from the static site template, without a target option
+	if service.StaticSite.Serving == "" {
+		for _, containerBuildType := range containerBuildTypesRequiringTargets {
+			if containerBuildType == service.ContainerBuildType && len(service.ContainerizationTargetOptions) == 0 {
+				return fmt.Errorf("the service %s has the container build type %s which requires a target option", service.ServiceName, service.ContainerBuildType)
+			}
+		}
+	}
+	if len(service.BuildSteps.Steps) == 0 {
+		return nil
+	}
+	switch service.BuildSteps.Composition {
+	case MultiStageBuildComposition:
+		if !service.IsDockerfileBuild() {
+			return fmt.Errorf("the build steps of the service %s can only be composed as stages of a Dockerfile when the service and the steps are built using Dockerfiles", service.ServiceName)
+		}
+	case MultipleImagesBuildComposition:
+	default:
+		return fmt.Errorf("the build steps of the service %s have the unsupported composition %s", service.ServiceName, service.BuildSteps.Composition)
+	}
+	for _, step := range service.BuildSteps.Steps {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("invalid build step of the service %s : %s", service.ServiceName, err)
+		}
+	}
+	return nil
+}
+
+// IsDockerfileBuild returns true if the service and all its build steps are built using Dockerfiles, which is required to compose them as stages
+func (service *Service) IsDockerfileBuild() bool {
+	for _, s := range append([]Service{*service}, service.BuildSteps.Steps...) {
+		if s.ContainerBuildType != DockerFileContainerBuildTypeValue && s.ContainerBuildType != ReuseDockerFileContainerBuildTypeValue {
+			return false
+		}
+	}
+	return true
 }
 
 // NewService creates a new service
@@ -257,22 +862,50 @@ func (service *Service) GatherGitInfo(path string, plan Plan) (bool, error) {
 	return true, nil
 }
 
-func (service *Service) merge(newservice Service) bool {
+// isMergeable returns true if both services are the same option of the same service, built from the same source directory
+func (service *Service) isMergeable(newservice Service) bool {
 	if service.ServiceName != newservice.ServiceName || service.Image != newservice.Image || service.TranslationType != newservice.TranslationType || service.ContainerBuildType != newservice.ContainerBuildType {
 		return false
 	}
 	if len(service.BuildArtifacts[SourceDirectoryBuildArtifactType]) > 0 && len(newservice.BuildArtifacts[SourceDirectoryBuildArtifactType]) > 0 && service.BuildArtifacts[SourceDirectoryBuildArtifactType][0] != newservice.BuildArtifacts[SourceDirectoryBuildArtifactType][0] {
 		return false
 	}
+	return true
+}
+
+func (service *Service) merge(newservice Service) bool {
+	if !service.isMergeable(newservice) {
+		return false
+	}
 	service.UpdateContainerBuildPipeline = service.UpdateContainerBuildPipeline || newservice.UpdateContainerBuildPipeline
 	service.UpdateDeployPipeline = service.UpdateDeployPipeline || newservice.UpdateDeployPipeline
 	service.addSourceTypes(newservice.SourceTypes)
 	service.addTargetOptions(newservice.ContainerizationTargetOptions)
 	service.addSourceArtifacts(newservice.SourceArtifacts)
 	service.addBuildArtifacts(newservice.BuildArtifacts)
+	service.Detection.merge(newservice.Detection)
+	for _, port := range newservice.Ports {
+		service.AddPort(port)
+	}
+	service.Runtime.merge(newservice.Runtime)
+	service.DependsOn = common.MergeStringSlices(service.DependsOn, newservice.DependsOn)
+	service.Resources.merge(newservice.Resources)
 	return true
 }
 
+// merge keeps the highest confidence and the evidence of both detections
+func (detection *Detection) merge(newdetection Detection) {
+	if newdetection.Confidence > detection.Confidence {
+		detection.Confidence = newdetection.Confidence
+	}
+	detection.Evidence = common.MergeStringSlices(detection.Evidence, newdetection.Evidence)
+	for _, port := range newdetection.Ports {
+		if !common.IsIntPresent(detection.Ports, port) {
+			detection.Ports = append(detection.Ports, port)
+		}
+	}
+}
+
 // AddSourceArtifact adds a source artifact to a plan service
 func (service *Service) AddSourceArtifact(sat SourceArtifactTypeValue, value string) {
 	if val, ok := service.SourceArtifacts[sat]; ok {
@@ -389,7 +1022,7 @@ func NewPlan() Plan {
 	plan := Plan{
 		TypeMeta: types.TypeMeta{
 			Kind:       string(PlanKind),
-			APIVersion: types.SchemeGroupVersion.String(),
+			APIVersion: SchemeGroupVersion.String(),
 		},
 		ObjectMeta: types.ObjectMeta{
 			Name: common.DefaultProjectName,
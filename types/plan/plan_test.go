@@ -35,7 +35,7 @@ func TestMerge(t *testing.T) {
 		}
 	})
 	t.Run("merge ignore supported kinds from new k8s output into filled k8s output", func(t *testing.T) {
-		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false}
+		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false, false}
 		out2 := plan.KubernetesOutput{IgnoreUnsupportedKinds: true}
 		want := out1
 		want.IgnoreUnsupportedKinds = true
@@ -45,7 +45,7 @@ func TestMerge(t *testing.T) {
 		}
 	})
 	t.Run("merge registry url from new k8s output into filled k8s output", func(t *testing.T) {
-		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false}
+		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false, false}
 		out2 := plan.KubernetesOutput{IgnoreUnsupportedKinds: true, RegistryURL: "url1"}
 		want := out1
 		want.IgnoreUnsupportedKinds = true
@@ -56,7 +56,7 @@ func TestMerge(t *testing.T) {
 		}
 	})
 	t.Run("merge registry namespace from new k8s output into filled k8s output", func(t *testing.T) {
-		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false}
+		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false, false}
 		out2 := plan.KubernetesOutput{IgnoreUnsupportedKinds: true, RegistryNamespace: "namespace1"}
 		want := out1
 		want.IgnoreUnsupportedKinds = true
@@ -67,7 +67,7 @@ func TestMerge(t *testing.T) {
 		}
 	})
 	t.Run("merge image pull secret from new k8s output into filled k8s output", func(t *testing.T) {
-		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false}
+		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false, false}
 		out2 := plan.KubernetesOutput{IgnoreUnsupportedKinds: true}
 		want := out1
 		want.IgnoreUnsupportedKinds = true
@@ -77,7 +77,7 @@ func TestMerge(t *testing.T) {
 		}
 	})
 	t.Run("merge cluster type from new k8s output into filled k8s output", func(t *testing.T) {
-		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false}
+		out1 := plan.KubernetesOutput{"111", "222", plan.TargetClusterType{Type: "444"}, false, false}
 		out2 := plan.KubernetesOutput{IgnoreUnsupportedKinds: true, TargetCluster: plan.TargetClusterType{Type: "clus_type1"}}
 		want := out1
 		want.IgnoreUnsupportedKinds = true
@@ -362,3 +362,183 @@ func TestNewService(t *testing.T) {
 		t.Error("Failed to instantiate the service fields properly. Actual:", s)
 	}
 }
+
+func TestValidate(t *testing.T) {
+	t.Run("detected container build type", func(t *testing.T) {
+		s := plan.NewService("foo", plan.Any2KubeTranslation)
+		s.ContainerBuildType = plan.DockerFileContainerBuildTypeValue
+		s.ContainerizationTargetOptions = []string{"m2kassets/dockerfiles/nodejs"}
+		if err := s.Validate(); err != nil {
+			t.Fatalf("Expected the service to be valid. Error: %q", err)
+		}
+	})
+	t.Run("container build type not supported by the translation type", func(t *testing.T) {
+		s := plan.NewService("foo", plan.Dockerfile2KubeTranslation)
+		s.ContainerBuildType = plan.CNBContainerBuildTypeValue
+		s.ContainerizationTargetOptions = []string{"gcr.io/buildpacks/builder"}
+		if err := s.Validate(); err == nil {
+			t.Fatalf("Expected an error since the Dockerfile translation cannot build using CNB")
+		}
+	})
+	t.Run("container build type without a target option", func(t *testing.T) {
+		s := plan.NewService("foo", plan.Any2KubeTranslation)
+		s.ContainerBuildType = plan.S2IContainerBuildTypeValue
+		if err := s.Validate(); err == nil {
+			t.Fatalf("Expected an error since S2I requires a target option")
+		}
+	})
+	t.Run("static site served by nginx", func(t *testing.T) {
+		s := plan.NewService("foo", plan.Any2KubeTranslation)
+		s.ContainerBuildType = plan.DockerFileContainerBuildTypeValue
+		s.StaticSite = plan.StaticSite{Framework: "vue", BuildDir: "dist", Serving: plan.NginxStaticSiteServing}
+		if err := s.Validate(); err != nil {
+			t.Fatalf("Expected the static site to be valid without a target option. Error: %q", err)
+		}
+	})
+	t.Run("static site uploaded without a bucket", func(t *testing.T) {
+		s := plan.NewService("foo", plan.Any2KubeTranslation)
+		s.ContainerBuildType = plan.DockerFileContainerBuildTypeValue
+		s.StaticSite = plan.StaticSite{Framework: "vue", BuildDir: "dist", Serving: plan.ObjectStorageStaticSiteServing}
+		if err := s.Validate(); err == nil {
+			t.Fatalf("Expected an error since the static site has no bucket url")
+		}
+	})
+}
+
+func TestValidateBuildSteps(t *testing.T) {
+	newService := func(name string, containerBuildType plan.ContainerBuildTypeValue) plan.Service {
+		s := plan.NewService(name, plan.Any2KubeTranslation)
+		s.ContainerBuildType = containerBuildType
+		s.ContainerizationTargetOptions = []string{"target"}
+		return s
+	}
+	s := newService("backend", plan.DockerFileContainerBuildTypeValue)
+	s.BuildSteps = plan.BuildSteps{Composition: plan.MultiStageBuildComposition, Steps: []plan.Service{newService("frontend", plan.DockerFileContainerBuildTypeValue)}}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected the build steps to be valid. Error: %q", err)
+	}
+	s.BuildSteps.Steps = []plan.Service{newService("frontend", plan.CNBContainerBuildTypeValue)}
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since a step built using CNB cannot be a stage of a Dockerfile")
+	}
+	s.BuildSteps.Composition = plan.MultipleImagesBuildComposition
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected the build steps to be valid as separate images. Error: %q", err)
+	}
+}
+
+func TestPorts(t *testing.T) {
+	s := plan.NewService("foo", plan.Any2KubeTranslation)
+	s.ContainerBuildType = plan.DockerFileContainerBuildTypeValue
+	s.ContainerizationTargetOptions = []string{"m2kassets/dockerfiles/nodejs"}
+	s.AddPort(plan.Port{ContainerPort: 8080})
+	s.AddPort(plan.Port{ContainerPort: 8080, Protocol: plan.TCPPortProtocol, Name: "http", Expose: plan.IngressPortExpose})
+	s.AddPort(plan.Port{ContainerPort: 8080, Protocol: plan.UDPPortProtocol})
+	want := []plan.Port{{ContainerPort: 8080, Name: "http", Expose: plan.IngressPortExpose}, {ContainerPort: 8080, Protocol: plan.UDPPortProtocol}}
+	if !reflect.DeepEqual(s.Ports, want) {
+		t.Fatalf("Failed to add the ports. Expected: %+v Actual: %+v", want, s.Ports)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected the ports to be valid. Error: %q", err)
+	}
+	s.Ports[1].Expose = plan.IngressPortExpose
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since a UDP port cannot be exposed using the ingress")
+	}
+	s.Ports[1].Expose = plan.LoadBalancerPortExpose
+	s.Ports[0].Name = "http_port"
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since the port name is invalid")
+	}
+	s.Ports[0].Name = ""
+	s.Ports[0].ContainerPort = 70000
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since the port number is out of range")
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	s := plan.NewService("foo", plan.Dockerfile2KubeTranslation)
+	s.ContainerBuildType = plan.ReuseDockerFileContainerBuildTypeValue
+	s.ContainerizationTargetOptions = []string{"Dockerfile"}
+	s.Runtime.SetEnv("PORT", "3000")
+	s.Runtime.SetEnv("PORT", "8080")
+	s.Runtime.WorkingDir = "/app"
+	if !reflect.DeepEqual(s.Runtime.Env, []plan.EnvVar{{Name: "PORT", Value: "8080"}}) {
+		t.Fatalf("Failed to set the env var. Actual: %+v", s.Runtime.Env)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected the runtime to be valid. Error: %q", err)
+	}
+	s.Runtime.WorkingDir = "app"
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since the working directory is relative")
+	}
+}
+
+func TestResources(t *testing.T) {
+	s := plan.NewService("foo", plan.Dockerfile2KubeTranslation)
+	s.ContainerBuildType = plan.ReuseDockerFileContainerBuildTypeValue
+	s.ContainerizationTargetOptions = []string{"Dockerfile"}
+	s.Resources = plan.ResourceSpec{Requests: plan.ResourceQuantities{CPU: "250m", Memory: "256Mi"}, Limits: plan.ResourceQuantities{Memory: "1Gi"}}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Expected the resources to be valid. Error: %q", err)
+	}
+	s.Resources.Limits.CPU = "100m"
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since the cpu request exceeds the cpu limit")
+	}
+	s.Resources.Limits.CPU = "one"
+	if err := s.Validate(); err == nil {
+		t.Fatalf("Expected an error since the cpu limit is not a quantity")
+	}
+}
+
+func TestOutputTargets(t *testing.T) {
+	outputs := plan.Outputs{
+		Kubernetes: plan.KubernetesOutput{RegistryURL: "quay.io", RegistryNamespace: "apps", ConfigChecksums: true, TargetCluster: plan.TargetClusterType{Type: "Kubernetes"}},
+		Targets: []plan.OutputTarget{
+			{Name: "k8s", Type: plan.KubernetesOutputTargetType},
+			{Name: "openshift", Type: plan.OpenShiftOutputTargetType, Kubernetes: plan.KubernetesOutput{RegistryURL: "image-registry.openshift-image-registry.svc:5000"}},
+			{Name: "chart", Type: plan.HelmOutputTargetType},
+		},
+	}
+	if err := outputs.ValidateTargets(); err != nil {
+		t.Fatalf("Expected the output targets to be valid. Error: %q", err)
+	}
+	if output := outputs.Targets[0].GetKubernetesOutput(outputs.Kubernetes); output != outputs.Kubernetes {
+		t.Fatalf("Expected the kubernetes output of the plan. Actual: %+v", output)
+	}
+	want := plan.KubernetesOutput{RegistryURL: "image-registry.openshift-image-registry.svc:5000", RegistryNamespace: "apps", ConfigChecksums: true, TargetCluster: plan.TargetClusterType{Type: "Openshift"}}
+	if output := outputs.Targets[1].GetKubernetesOutput(outputs.Kubernetes); output != want {
+		t.Fatalf("Failed to override the kubernetes output of the plan. Expected: %+v Actual: %+v", want, output)
+	}
+
+	outputs.Targets = append(outputs.Targets, plan.OutputTarget{Name: "chart", Type: plan.KnativeOutputTargetType})
+	if err := outputs.ValidateTargets(); err == nil {
+		t.Fatalf("Expected an error since the output target names are not unique")
+	}
+	outputs.Targets[3] = plan.OutputTarget{Name: "../knative", Type: plan.KnativeOutputTargetType}
+	if err := outputs.ValidateTargets(); err == nil {
+		t.Fatalf("Expected an error since the output target name is not a directory name")
+	}
+	outputs.Targets[3] = plan.OutputTarget{Name: "swarm", Type: "DockerSwarm"}
+	if err := outputs.ValidateTargets(); err == nil {
+		t.Fatalf("Expected an error since the output target type is not supported")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	spec := plan.PlanSpec{Profiles: []plan.Profile{{Name: "dev", Replicas: 1}, {Name: "prod", Namespace: "shop", Replicas: 3, IngressHost: "shop.example.com"}}}
+	if err := spec.ValidateProfiles(); err != nil {
+		t.Fatalf("Expected the profiles to be valid. Error: %q", err)
+	}
+	spec.Profiles = append(spec.Profiles, plan.Profile{Name: "prod"})
+	if err := spec.ValidateProfiles(); err == nil {
+		t.Fatalf("Expected an error since the profile names are not unique")
+	}
+	spec.Profiles[2] = plan.Profile{Name: "stage", Replicas: -1}
+	if err := spec.ValidateProfiles(); err == nil {
+		t.Fatalf("Expected an error since the replica count is negative")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// MergePlans merges several plans, like the plans of the teams sharing a monorepo, into one plan with the name.
+// The paths of the plans have to be absolute, like the paths of the plans read using ReadPlan. The root directory
+// of the merged plan is the common ancestor of the root directories of the plans.
+// The options of the services with the same name are merged like when planning. When the plans have different
+// services with the same name, the services of the later plans are renamed using the names of their plans.
+// The outputs and the profiles of the first plan are used, and the host path remediations of all the plans are kept.
+func MergePlans(name string, plans []Plan) (Plan, error) {
+	merged := NewPlan()
+	if len(plans) == 0 {
+		return merged, fmt.Errorf("no plans to merge")
+	}
+	rootDirs := []string{}
+	for _, plan := range plans {
+		if !filepath.IsAbs(plan.Spec.Inputs.RootDir) {
+			return merged, fmt.Errorf("the root directory %s of the plan %s is not an absolute path", plan.Spec.Inputs.RootDir, plan.Name)
+		}
+		rootDirs = append(rootDirs, plan.Spec.Inputs.RootDir)
+	}
+	merged.Name = name
+	if merged.Name == "" {
+		merged.Name = plans[0].Name
+	}
+	merged.Spec.Inputs.RootDir = common.CleanAndFindCommonDirectory(rootDirs)
+	merged.Spec.Outputs = plans[0].Spec.Outputs
+	merged.Spec.Profiles = plans[0].Spec.Profiles
+	merged.Spec.Outputs.HostPathRemediations = map[string]HostPathRemediationTypeValue{}
+	for i, plan := range plans {
+		if i > 0 && !reflect.DeepEqual(plan.Spec.Outputs.Kubernetes, merged.Spec.Outputs.Kubernetes) {
+			log.Warnf("The kubernetes outputs of the plan %s differ from the ones of the plan %s . Using the ones of the plan %s .", plan.Name, plans[0].Name, plans[0].Name)
+		}
+		for hostPath, remediation := range plan.Spec.Outputs.HostPathRemediations {
+			if existingRemediation, ok := merged.Spec.Outputs.HostPathRemediations[hostPath]; ok && existingRemediation != remediation {
+				log.Warnf("The plan %s remediates the host path %s using %s instead of %s . Using %s .", plan.Name, hostPath, remediation, existingRemediation, existingRemediation)
+				continue
+			}
+			merged.Spec.Outputs.HostPathRemediations[hostPath] = remediation
+		}
+		merged.Spec.Inputs.K8sFiles = common.MergeStringSlices(merged.Spec.Inputs.K8sFiles, plan.Spec.Inputs.K8sFiles)
+		for _, chart := range plan.Spec.Inputs.HelmCharts {
+			if !hasHelmChart(merged.Spec.Inputs.HelmCharts, chart.Path) {
+				merged.Spec.Inputs.HelmCharts = append(merged.Spec.Inputs.HelmCharts, chart)
+			}
+		}
+		for artifactType, artifacts := range plan.Spec.Inputs.TargetInfoArtifacts {
+			merged.Spec.Inputs.TargetInfoArtifacts[artifactType] = common.MergeStringSlices(merged.Spec.Inputs.TargetInfoArtifacts[artifactType], artifacts)
+		}
+		serviceNames := []string{}
+		for serviceName := range plan.Spec.Inputs.Services {
+			serviceNames = append(serviceNames, serviceName)
+		}
+		sort.Strings(serviceNames)
+		for _, serviceName := range serviceNames {
+			services := plan.Spec.Inputs.Services[serviceName]
+			if existingServices, ok := merged.Spec.Inputs.Services[serviceName]; ok && !areMergeable(existingServices, services) {
+				newServiceName := getUniqueServiceName(merged, serviceName+"-"+plan.Name)
+				log.Warnf("The plans have different services named %s . Renaming the service %s of the plan %s to %s .", serviceName, serviceName, plan.Name, newServiceName)
+				services = renameServices(services, serviceName, newServiceName)
+			}
+			merged.AddServicesToPlan(services)
+		}
+	}
+	return merged, nil
+}
+
+// MergePlanFiles reads the plan files and merges the plans into one plan with the name. See MergePlans.
+func MergePlanFiles(name string, planPaths []string) (Plan, error) {
+	plans := []Plan{}
+	for _, planPath := range planPaths {
+		plan, err := ReadPlan(planPath)
+		if err != nil {
+			return Plan{}, fmt.Errorf("failed to read the plan file at path %s . Error: %q", planPath, err)
+		}
+		plans = append(plans, plan)
+	}
+	return MergePlans(name, plans)
+}
+
+// areMergeable returns true if an option of the new services can be merged into an option of the existing services
+func areMergeable(existingServices, newServices []Service) bool {
+	for _, existingService := range existingServices {
+		for _, newService := range newServices {
+			if existingService.isMergeable(newService) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getUniqueServiceName returns a name for a service which is not used by the services of the plan
+func getUniqueServiceName(plan Plan, serviceName string) string {
+	serviceName = common.NormalizeForServiceName(serviceName)
+	uniqueServiceName := serviceName
+	for i := 2; ; i++ {
+		if _, ok := plan.Spec.Inputs.Services[uniqueServiceName]; !ok {
+			return uniqueServiceName
+		}
+		uniqueServiceName = fmt.Sprintf("%s-%d", serviceName, i)
+	}
+}
+
+// renameServices returns copies of the options of a service with the new name
+func renameServices(services []Service, serviceName, newServiceName string) []Service {
+	renamedServices := []Service{}
+	for _, service := range services {
+		service.ServiceName = newServiceName
+		if service.ServiceRelPath == "/"+serviceName {
+			service.ServiceRelPath = "/" + newServiceName
+		}
+		renamedServices = append(renamedServices, service)
+	}
+	return renamedServices
+}
+
+// hasHelmChart checks if one of the charts is at the path
+func hasHelmChart(charts []HelmChart, path string) bool {
+	for _, chart := range charts {
+		if chart.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+func TestMergePlans(t *testing.T) {
+	newPlan := func(name, rootDir string, serviceDirs map[string]string) plantypes.Plan {
+		p := plantypes.NewPlan()
+		p.Name = name
+		p.Spec.Inputs.RootDir = rootDir
+		for serviceName, serviceDir := range serviceDirs {
+			s := plantypes.NewService(serviceName, plantypes.Any2KubeTranslation)
+			s.ContainerBuildType = plantypes.DockerFileContainerBuildTypeValue
+			s.ServiceRelPath = "/" + serviceName
+			s.AddBuildArtifact(plantypes.SourceDirectoryBuildArtifactType, serviceDir)
+			p.AddServicesToPlan([]plantypes.Service{s})
+		}
+		return p
+	}
+	repoDir := filepath.Join(t.TempDir(), "repo")
+	frontend := newPlan("frontend", filepath.Join(repoDir, "frontend"), map[string]string{
+		"web": filepath.Join(repoDir, "frontend", "web"),
+		"api": filepath.Join(repoDir, "frontend", "api"),
+	})
+	backend := newPlan("backend", filepath.Join(repoDir, "backend"), map[string]string{
+		"web": filepath.Join(repoDir, "frontend", "web"),
+		"api": filepath.Join(repoDir, "backend", "api"),
+	})
+
+	merged, err := plantypes.MergePlans("shop", []plantypes.Plan{frontend, backend})
+	if err != nil {
+		t.Fatalf("Failed to merge the plans. Error: %q", err)
+	}
+	if merged.Name != "shop" || merged.Spec.Inputs.RootDir != repoDir {
+		t.Fatalf("Expected the plan shop with the root directory %s . Actual: %s %s", repoDir, merged.Name, merged.Spec.Inputs.RootDir)
+	}
+	if len(merged.Spec.Inputs.Services) != 3 || len(merged.Spec.Inputs.Services["web"]) != 1 {
+		t.Fatalf("Expected the services web, api and api-backend, with web merged. Actual: %+v", merged.Spec.Inputs.Services)
+	}
+	renamed, ok := merged.Spec.Inputs.Services["api-backend"]
+	if !ok || renamed[0].ServiceName != "api-backend" || renamed[0].ServiceRelPath != "/api-backend" || renamed[0].BuildArtifacts[plantypes.SourceDirectoryBuildArtifactType][0] != filepath.Join(repoDir, "backend", "api") {
+		t.Fatalf("Expected the api of the backend to be renamed to api-backend. Actual: %+v", merged.Spec.Inputs.Services)
+	}
+
+	if _, err := plantypes.MergePlans("shop", nil); err == nil {
+		t.Fatalf("Expected an error since there are no plans to merge")
+	}
+}
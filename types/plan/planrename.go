@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+// hostPortRegex matches the connection strings without a scheme, like redis:6379
+var hostPortRegex = regexp.MustCompile(`^([a-zA-Z0-9][a-zA-Z0-9.-]*)(:[0-9]+(/.*)?)$`)
+
+// RenameService renames the service of the plan, along with the references of the other services to it, like their
+// dependencies and the hosts of the connection strings in their env vars
+func (plan *Plan) RenameService(serviceName, newServiceName string) error {
+	services, ok := plan.Spec.Inputs.Services[serviceName]
+	if !ok {
+		return fmt.Errorf("the service %s is not in the plan", serviceName)
+	}
+	if normalizedName := common.NormalizeForServiceName(newServiceName); normalizedName != newServiceName {
+		return fmt.Errorf("the name %s is not a valid service name. Use %s instead", newServiceName, normalizedName)
+	}
+	if _, ok := plan.Spec.Inputs.Services[newServiceName]; ok {
+		return fmt.Errorf("the plan already has a service named %s", newServiceName)
+	}
+	delete(plan.Spec.Inputs.Services, serviceName)
+	plan.Spec.Inputs.Services[newServiceName] = renameServices(services, serviceName, newServiceName)
+	for _, services := range plan.Spec.Inputs.Services {
+		for i := range services {
+			for j, dependency := range services[i].DependsOn {
+				if dependency == serviceName {
+					services[i].DependsOn[j] = newServiceName
+				}
+			}
+			for j, env := range services[i].Runtime.Env {
+				services[i].Runtime.Env[j].Value = renameHost(env.Value, serviceName, newServiceName)
+			}
+		}
+	}
+	return nil
+}
+
+// RenameImage changes the image of the services which use the image, and returns the names of those services
+func (plan *Plan) RenameImage(image, newImage string) ([]string, error) {
+	if newImage == "" {
+		return nil, fmt.Errorf("the new name of the image %s is empty", image)
+	}
+	serviceNames := []string{}
+	for serviceName, services := range plan.Spec.Inputs.Services {
+		for i := range services {
+			if services[i].Image == image {
+				services[i].Image = newImage
+				if !common.IsStringPresent(serviceNames, serviceName) {
+					serviceNames = append(serviceNames, serviceName)
+				}
+			}
+		}
+	}
+	if len(serviceNames) == 0 {
+		return nil, fmt.Errorf("no service of the plan uses the image %s", image)
+	}
+	sort.Strings(serviceNames)
+	return serviceNames, nil
+}
+
+// renameHost replaces the host of the value, if it is the host, like db, or the host of a connection string, like
+// postgres://user:password@db:5432/tickets or db:5432
+func renameHost(value, host, newHost string) string {
+	if value == host {
+		return newHost
+	}
+	if u, err := url.Parse(value); err == nil && u.Host != "" {
+		if !strings.EqualFold(u.Hostname(), host) {
+			return value
+		}
+		port := u.Port()
+		u.Host = newHost
+		if port != "" {
+			u.Host += ":" + port
+		}
+		return u.String()
+	}
+	if match := hostPortRegex.FindStringSubmatch(value); match != nil && strings.EqualFold(match[1], host) {
+		return newHost + match[2]
+	}
+	return value
+}
@@ -18,6 +18,7 @@ package plan
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -162,9 +163,24 @@ func convertPathsEncode(plan *Plan) error {
 }
 
 // ReadPlan decodes the plan from yaml converting relative paths to absolute.
+// Plans written using older plan versions are migrated to the current version.
+// The plan can be yaml or json, since json is a subset of yaml.
 func ReadPlan(path string) (Plan, error) {
 	plan := Plan{}
-	if err := common.ReadMove2KubeYaml(path, &plan); err != nil {
+	planBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the plan file at path %q Error %q", path, err)
+		return plan, err
+	}
+	planBytes, apiVersion, err := MigratePlanYaml(planBytes)
+	if err != nil {
+		log.Errorf("Failed to load the plan file at path %q Error %q", path, err)
+		return plan, err
+	}
+	if apiVersion != SchemeGroupVersion.String() {
+		log.Warnf("The plan file at path %s has the older apiVersion %s and was migrated to %s . Use the plan upgrade command to rewrite it.", path, apiVersion, SchemeGroupVersion.String())
+	}
+	if err := decodePlan(planBytes, &plan); err != nil {
 		log.Errorf("Failed to load the plan file at path %q Error %q", path, err)
 		return plan, err
 	}
@@ -188,7 +204,18 @@ func (plan *Plan) Copy() (Plan, error) {
 }
 
 // WritePlan encodes the plan to yaml converting absolute paths to relative.
+// The plan is encoded to json instead if the path has the .json extension.
 func WritePlan(path string, plan Plan) error {
+	outputFormat := common.YamlOutputFormat
+	if filepath.Ext(path) == ".json" {
+		outputFormat = common.JSONOutputFormat
+	}
+	return WritePlanWithFormat(path, plan, outputFormat)
+}
+
+// WritePlanWithFormat encodes the plan to yaml or json converting absolute paths to relative.
+// Both can be read using ReadPlan.
+func WritePlanWithFormat(path string, plan Plan, outputFormat string) error {
 	copy, err := plan.Copy()
 	if err != nil {
 		log.Errorf("Failed to create a copy of the plan before writing. Error: %q", err)
@@ -197,7 +224,27 @@ func WritePlan(path string, plan Plan) error {
 	if err := convertPathsEncode(&copy); err != nil {
 		return err
 	}
-	return common.WriteYaml(path, copy)
+	return common.WriteYamlOrJSON(path, copy, outputFormat)
+}
+
+// GetSHA256Hash returns the SHA256 hash of the plan encoded to yaml.
+// The paths are relative to the root directory, so the hash does not depend on where the source directory is.
+func (plan *Plan) GetSHA256Hash() (string, error) {
+	copy, err := plan.Copy()
+	if err != nil {
+		log.Errorf("Failed to create a copy of the plan before hashing. Error: %q", err)
+		return "", err
+	}
+	if err := convertPathsEncode(&copy); err != nil {
+		return "", err
+	}
+	copy.Spec.Inputs.RootDir = "."
+	planBytes, err := yaml.Marshal(copy)
+	if err != nil {
+		log.Errorf("Failed to marshal the plan to yaml. Error: %q", err)
+		return "", err
+	}
+	return common.GetSHA256Hash(string(planBytes)), nil
 }
 
 // IsAssetsPath returns true if it is a m2kassets path.
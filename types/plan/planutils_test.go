@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -75,6 +76,41 @@ func TestWritePlan(t *testing.T) {
 			t.Fatalf("Failed to reset the root directory to the old root directory. Difference:\n%s", cmp.Diff(string(wantBytes), string(actualBytes)))
 		}
 	})
+
+	t.Run("write the plan as json and read it again", func(t *testing.T) {
+		// Setup
+		setupAssets(t)
+		defer os.RemoveAll(common.TempPath)
+
+		outputPath := filepath.Join(t.TempDir(), "m2k.plan.json")
+		testDataPlanPath, err := filepath.Abs("testdata/setrootdir/nodejsplan.yaml")
+		if err != nil {
+			t.Fatalf("Failed to make the test data plan path absolute. Error: %q", err)
+		}
+		want, err := plantypes.ReadPlan(testDataPlanPath)
+		if err != nil {
+			t.Fatalf("Failed to read the test data plan at path %q Error: %q", testDataPlanPath, err)
+		}
+
+		// Test
+		if err := plantypes.WritePlan(outputPath, want); err != nil {
+			t.Fatalf("Failed to write the plan to the path %q Error %q", outputPath, err)
+		}
+		actualBytes, err := ioutil.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read the plan we wrote at path %q Error: %q", outputPath, err)
+		}
+		if !strings.HasPrefix(string(actualBytes), "{\n  \"apiVersion\": ") {
+			t.Fatalf("Expected the plan to be written as json. Actual:\n%s", string(actualBytes))
+		}
+		actual, err := plantypes.ReadPlan(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read the json plan at path %q Error: %q", outputPath, err)
+		}
+		if !cmp.Equal(actual, want) {
+			t.Fatalf("Failed to read the json plan. Difference:\n%s", cmp.Diff(want, actual))
+		}
+	})
 }
 
 func TestSetRootDir(t *testing.T) {
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the plans written by this version of move2kube.
+	// It changes independently of the other move2kube files whenever the schema of the plan changes.
+	SchemeGroupVersion = schema.GroupVersion{Group: types.GroupName, Version: "v1alpha1"}
+)
+
+// planMigration migrates a plan from its version to the next version
+type planMigration struct {
+	version string
+	migrate func(plan map[string]interface{}) error
+}
+
+// planMigrations are the migrations of the older plan versions, in order. There are none yet, since v1alpha1 is the first version.
+// Whenever the version of the plan is changed, add the migration from the previous version here.
+var planMigrations = []planMigration{}
+
+// IsOlderPlanVersion returns true if the plan version can be migrated to the current version
+func IsOlderPlanVersion(apiVersion string) bool {
+	_, ok := getPlanMigrationIndex(apiVersion)
+	return ok
+}
+
+func getPlanMigrationIndex(apiVersion string) (int, bool) {
+	groupVersion, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || groupVersion.Group != SchemeGroupVersion.Group {
+		return 0, false
+	}
+	for i, migration := range planMigrations {
+		if migration.version == groupVersion.Version {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// MigratePlanYaml migrates the yaml of a plan of any supported version to the current version.
+// It returns the migrated yaml and the version the plan had.
+func MigratePlanYaml(planBytes []byte) ([]byte, string, error) {
+	plan := map[string]interface{}{}
+	if err := yaml.Unmarshal(planBytes, &plan); err != nil {
+		return nil, "", fmt.Errorf("failed to parse the plan. Error: %q", err)
+	}
+	apiVersion, _ := plan["apiVersion"].(string)
+	if apiVersion == "" {
+		return nil, "", fmt.Errorf("did not find the apiVersion of the plan")
+	}
+	if kind, _ := plan["kind"].(string); kind != string(PlanKind) {
+		return nil, apiVersion, fmt.Errorf("expected the kind %s but found the kind %s", PlanKind, kind)
+	}
+	if apiVersion == SchemeGroupVersion.String() {
+		return planBytes, apiVersion, nil
+	}
+	index, ok := getPlanMigrationIndex(apiVersion)
+	if !ok {
+		return nil, apiVersion, fmt.Errorf("the plan has the apiVersion %s which is not supported by this version of %s. The supported apiVersion is %s . A newer version of %s may be required", apiVersion, types.AppName, SchemeGroupVersion.String(), types.AppName)
+	}
+	for _, migration := range planMigrations[index:] {
+		log.Debugf("Migrating the plan from the version %s", migration.version)
+		if err := migration.migrate(plan); err != nil {
+			return nil, apiVersion, fmt.Errorf("failed to migrate the plan from the version %s . Error: %q", migration.version, err)
+		}
+	}
+	plan["apiVersion"] = SchemeGroupVersion.String()
+	migratedBytes, err := yaml.Marshal(plan)
+	if err != nil {
+		return nil, apiVersion, fmt.Errorf("failed to marshal the migrated plan. Error: %q", err)
+	}
+	return migratedBytes, apiVersion, nil
+}
+
+// decodePlan decodes the plan, warning about the fields that are not part of the schema instead of silently dropping them
+func decodePlan(planBytes []byte, plan *Plan) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(planBytes))
+	decoder.KnownFields(true)
+	err := decoder.Decode(plan)
+	if err == nil {
+		return nil
+	}
+	log.Warnf("The plan has fields which are not part of the schema of the version %s and are ignored. Error: %q", SchemeGroupVersion.Version, err)
+	*plan = Plan{}
+	return yaml.Unmarshal(planBytes, plan)
+}
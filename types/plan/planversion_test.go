@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	plantypes "github.com/konveyor/move2kube/types/plan"
+)
+
+const testV1alpha0Plan = `apiVersion: move2kube.konveyor.io/v1alpha0
+kind: Plan
+metadata:
+  name: tickets
+spec:
+  inputs:
+    rootDir: ROOTDIR
+    services:
+      tickets:
+        - serviceName: tickets
+          image: tickets:latest
+          translationType: Containerize
+          containerBuildType: NewDockerfile
+          sourceType:
+            - Directory
+          targetOptions:
+            - m2kassets/dockerfiles/nodejs
+  outputs:
+    kubernetes:
+      targetCluster:
+        type: Kubernetes
+`
+
+func TestReadOlderPlan(t *testing.T) {
+	migrated := false
+	restore := plantypes.SetPlanMigration("v1alpha0", func(plan map[string]interface{}) error {
+		migrated = true
+		return nil
+	})
+	defer restore()
+
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "m2k.plan")
+	if err := ioutil.WriteFile(planPath, []byte(strings.Replace(testV1alpha0Plan, "ROOTDIR", dir, 1)), 0644); err != nil {
+		t.Fatalf("Failed to write the plan file at path %s . Error: %q", planPath, err)
+	}
+	plan, err := plantypes.ReadPlan(planPath)
+	if err != nil {
+		t.Fatalf("Failed to read the older plan. Error: %q", err)
+	}
+	if !migrated {
+		t.Fatalf("Expected the migration of the version v1alpha0 to be run")
+	}
+	if plan.APIVersion != plantypes.SchemeGroupVersion.String() {
+		t.Fatalf("Expected the plan to be migrated to %s . Actual: %s", plantypes.SchemeGroupVersion.String(), plan.APIVersion)
+	}
+	services := plan.Spec.Inputs.Services["tickets"]
+	if len(services) != 1 || !reflect.DeepEqual(services[0].SourceTypes, []plantypes.SourceTypeValue{plantypes.DirectorySourceTypeValue}) {
+		t.Fatalf("Failed to read the source types of the service. Actual: %+v", services)
+	}
+}
+
+func TestMigratePlanYaml(t *testing.T) {
+	current := "apiVersion: " + plantypes.SchemeGroupVersion.String() + "\nkind: Plan\n"
+	planBytes, apiVersion, err := plantypes.MigratePlanYaml([]byte(current))
+	if err != nil || apiVersion != plantypes.SchemeGroupVersion.String() || string(planBytes) != current {
+		t.Fatalf("Expected the current plan to be left as is. Actual: %s %s %v", apiVersion, string(planBytes), err)
+	}
+	if plantypes.IsOlderPlanVersion("move2kube.konveyor.io/v1alpha0") || plantypes.IsOlderPlanVersion(plantypes.SchemeGroupVersion.String()) {
+		t.Fatalf("Expected no older plan versions")
+	}
+
+	restore := plantypes.SetPlanMigration("v1alpha0", func(plan map[string]interface{}) error { return nil })
+	defer restore()
+	if !plantypes.IsOlderPlanVersion("move2kube.konveyor.io/v1alpha0") || plantypes.IsOlderPlanVersion(plantypes.SchemeGroupVersion.String()) {
+		t.Fatalf("Failed to detect the older plan versions")
+	}
+	planBytes, apiVersion, err = plantypes.MigratePlanYaml([]byte("apiVersion: move2kube.konveyor.io/v1alpha0\nkind: Plan\n"))
+	if err != nil || apiVersion != "move2kube.konveyor.io/v1alpha0" || !strings.Contains(string(planBytes), "apiVersion: "+plantypes.SchemeGroupVersion.String()) {
+		t.Fatalf("Failed to migrate the older plan. Actual: %s %s %v", apiVersion, string(planBytes), err)
+	}
+	for _, plan := range []string{
+		"apiVersion: move2kube.konveyor.io/v9\nkind: Plan\n",
+		"apiVersion: example.com/v1alpha0\nkind: Plan\n",
+		"apiVersion: move2kube.konveyor.io/v1alpha0\nkind: QACache\n",
+		"kind: Plan\n",
+	} {
+		if _, _, err := plantypes.MigratePlanYaml([]byte(plan)); err == nil {
+			t.Fatalf("Expected the plan to be rejected. Actual: %s", plan)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ProjectKind is kind of the project config file
+const ProjectKind types.Kind = "Project"
+
+// HookPhase is the phase boundary at which a hook is run
+type HookPhase string
+
+const (
+	// PrePlanHookPhase runs the hooks before planning. The argument is the source directory.
+	PrePlanHookPhase HookPhase = "prePlan"
+	// PostPlanHookPhase runs the hooks after planning. The argument is the plan file.
+	PostPlanHookPhase HookPhase = "postPlan"
+	// PreTranslateHookPhase runs the hooks before translating. The arguments are the plan file and the output directory.
+	PreTranslateHookPhase HookPhase = "preTranslate"
+	// PostTranslateHookPhase runs the hooks after translating. The arguments are the plan file and the output directory.
+	PostTranslateHookPhase HookPhase = "postTranslate"
+)
+
+// Project contains the settings of a project that are kept along with its source
+type Project struct {
+	types.TypeMeta   `yaml:",inline"`
+	types.ObjectMeta `yaml:"metadata,omitempty"`
+	Spec             ProjectSpec `yaml:"spec"`
+}
+
+// ProjectSpec contains the hooks run at the phase boundaries
+type ProjectSpec struct {
+	Hooks map[HookPhase][]Hook `yaml:"hooks,omitempty"`
+}
+
+// Hook is a script or an external tool run at a phase boundary. The arguments of the phase are appended to the command.
+// Relative paths in the command are relative to the source directory.
+type Hook struct {
+	Name    string   `yaml:"name"`
+	Command []string `yaml:"command"`
+}
+
+// NewProject creates a new project config
+func NewProject(name string) Project {
+	return Project{
+		TypeMeta: types.TypeMeta{
+			Kind:       string(ProjectKind),
+			APIVersion: types.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: types.ObjectMeta{
+			Name: name,
+		},
+		Spec: ProjectSpec{
+			Hooks: map[HookPhase][]Hook{},
+		},
+	}
+}
+
+// ReadProject reads the project config file in the source directory. A project without hooks is returned if there is no such file.
+func ReadProject(srcPath string) (Project, error) {
+	project := NewProject("")
+	projectPath := filepath.Join(srcPath, common.ProjectConfigFile)
+	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		return project, nil
+	}
+	if err := common.ReadMove2KubeYaml(projectPath, &project); err != nil {
+		log.Errorf("Failed to read the project config file at path %s . Error: %q", projectPath, err)
+		return project, err
+	}
+	return project, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/konveyor/move2kube/internal/common"
 	"github.com/konveyor/move2kube/types"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
 // QACacheKind defines kind of QA Cache
@@ -36,7 +37,8 @@ type Cache struct {
 
 // CacheSpec stores the cache data
 type CacheSpec struct {
-	file string `yaml:"-"`
+	file    string  `yaml:"-"`
+	storage Storage `yaml:"-"`
 	// Problems stores the list of problems with resolutions
 	Problems []Problem `yaml:"solutions"`
 }
@@ -52,20 +54,42 @@ func NewCache(file string) (cache *Cache) {
 	}
 }
 
+// NewCacheInStorage creates new cache instance which is read from and written to the storage
+func NewCacheInStorage(file string, storage Storage) (cache *Cache) {
+	cache = NewCache(file)
+	cache.Spec.storage = storage
+	return cache
+}
+
 // Load loads and merges cache
 func (cache *Cache) Load() error {
 	c := Cache{}
-	if err := common.ReadMove2KubeYaml(cache.Spec.file, &c); err != nil {
-		log.Errorf("Unable to load the cache file at path %s Error: %q", cache.Spec.file, err)
-		return err
+	if IsFileStorage(cache.Spec.storage) {
+		if err := common.ReadMove2KubeYaml(cache.Spec.file, &c); err != nil {
+			log.Errorf("Unable to load the cache file at path %s Error: %q", cache.Spec.file, err)
+			return err
+		}
+	} else {
+		data, err := cache.Spec.storage.Read(cache.Spec.file)
+		if err != nil {
+			log.Debugf("Unable to load the cache file %s from the storage. Error: %q", cache.Spec.file, err)
+			return err
+		}
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			log.Errorf("Unable to parse the cache file %s from the storage. Error: %q", cache.Spec.file, err)
+			return err
+		}
+		if c.Kind != string(QACacheKind) {
+			return fmt.Errorf("the file %s in the storage is not a QA cache. Expected kind %s Actual kind %s", cache.Spec.file, QACacheKind, c.Kind)
+		}
 	}
 	cache.merge(c)
 	return nil
 }
 
-// Write writes cache to disk
+// Write writes cache to its storage
 func (cache *Cache) Write() error {
-	err := common.WriteYaml(cache.Spec.file, cache)
+	err := writeYamlToStorage(cache.Spec.storage, cache.Spec.file, cache)
 	if err != nil {
 		log.Warnf("Unable to write cache : %s", err)
 	}
@@ -74,7 +98,7 @@ func (cache *Cache) Write() error {
 
 // AddSolution adds a problem to solution cache
 func (cache *Cache) AddSolution(p Problem) error {
-	if p.Type == PasswordSolutionFormType {
+	if p.Type == PasswordSolutionFormType && len(p.ValueFrom) == 0 {
 		err := fmt.Errorf("passwords are not added to the cache")
 		log.Debug(err)
 		return err
@@ -84,6 +108,7 @@ func (cache *Cache) AddSolution(p Problem) error {
 		log.Warn(err)
 		return err
 	}
+	p.Answer = p.getStoredAnswer()
 	added := false
 	for i, cp := range cache.Spec.Problems {
 		if cp.ID == p.ID {
@@ -103,6 +128,19 @@ func (cache *Cache) AddSolution(p Problem) error {
 	return nil
 }
 
+// AddProblem adds a problem without its answer, like a deferred problem which is answered later by filling in its answer
+func (cache *Cache) AddProblem(p Problem) error {
+	p.Answer = nil
+	for i, cp := range cache.Spec.Problems {
+		if cp.ID == p.ID {
+			cache.Spec.Problems[i] = p
+			return cache.Write()
+		}
+	}
+	cache.Spec.Problems = append(cache.Spec.Problems, p)
+	return cache.Write()
+}
+
 // GetSolution reads a solution for the problem
 func (cache *Cache) GetSolution(p Problem) (Problem, error) {
 	if p.Answer != nil {
@@ -111,8 +149,11 @@ func (cache *Cache) GetSolution(p Problem) (Problem, error) {
 	}
 	for _, cp := range cache.Spec.Problems {
 		if (cp.ID == p.ID || cp.matches(p)) && cp.Answer != nil {
-			p.Answer = cp.Answer
-			return p, nil
+			resolvedProb, err := resolveAnswer(p, cp.Answer)
+			if err != nil {
+				log.Errorf("Unable to resolve the answer in the cache. Error: %q", err)
+			}
+			return resolvedProb, err
 		}
 	}
 	return p, fmt.Errorf("the problem %+v was not found in the cache", p)
@@ -43,6 +43,7 @@ type Config struct {
 	configStrings []string
 	yamlMap       mapT
 	writeYamlMap  mapT
+	storage       Storage
 	OutputPath    string
 }
 
@@ -89,8 +90,11 @@ func (c *Config) Load() (err error) {
 }
 
 func (c *Config) convertAnswer(p Problem, value interface{}) (Problem, error) {
-	p.Answer = value
-	return p, nil
+	resolvedProb, err := resolveAnswer(p, value)
+	if err != nil {
+		log.Errorf("Unable to resolve the answer in the config. Error: %q", err)
+	}
+	return resolvedProb, err
 }
 
 func (c *Config) normalGetSolution(p Problem) (Problem, error) {
@@ -181,16 +185,16 @@ func (c *Config) GetSolution(p Problem) (Problem, error) {
 	return c.normalGetSolution(p)
 }
 
-// Write writes the config to disk
+// Write writes the config to its storage
 func (c *Config) Write() error {
 	log.Debugf("Config.Write write the file out")
-	return common.WriteYaml(c.OutputPath, c.writeYamlMap)
+	return writeYamlToStorage(c.storage, c.OutputPath, c.writeYamlMap)
 }
 
 // AddSolution adds a problem to the config
 func (c *Config) AddSolution(p Problem) error {
 	log.Debugf("Config.AddSolution the problem is:\n%+v", p)
-	if p.Type == PasswordSolutionFormType {
+	if p.Type == PasswordSolutionFormType && len(p.ValueFrom) == 0 {
 		err := fmt.Errorf("passwords will not be added to the config")
 		log.Debug(err)
 		return err
@@ -201,8 +205,8 @@ func (c *Config) AddSolution(p Problem) error {
 		return err
 	}
 	if p.Type != MultiSelectSolutionFormType {
-		set(p.ID, p.Answer, c.yamlMap)
-		set(p.ID, p.Answer, c.writeYamlMap)
+		set(p.ID, p.getStoredAnswer(), c.yamlMap)
+		set(p.ID, p.getStoredAnswer(), c.writeYamlMap)
 		err := c.Write()
 		if err != nil {
 			log.Errorf("Failed to write to the config file. Error: %q", err)
@@ -216,8 +220,8 @@ func (c *Config) AddSolution(p Problem) error {
 	idx := strings.LastIndex(key, common.Special)
 	if idx < 0 {
 		// normal case key1 = [val1, val2, val3, ...]
-		set(key, p.Answer, c.yamlMap)
-		set(key, p.Answer, c.writeYamlMap)
+		set(key, p.getStoredAnswer(), c.yamlMap)
+		set(key, p.getStoredAnswer(), c.writeYamlMap)
 		return nil
 	}
 
@@ -254,6 +258,13 @@ func NewConfig(outputPath string, configStrings, configFiles []string) (config *
 	}
 }
 
+// NewConfigInStorage creates a new config instance which is written to the storage
+func NewConfigInStorage(outputPath string, configStrings, configFiles []string, storage Storage) (config *Config) {
+	config = NewConfig(outputPath, configStrings, configFiles)
+	config.storage = storage
+	return config
+}
+
 func (*nullLogBackend) Log(logging.Level, int, *logging.Record) error {
 	return nil
 }
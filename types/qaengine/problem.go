@@ -46,6 +46,10 @@ const (
 const (
 	// OtherAnswer - Use as one of the answers, when there is a option to enter the answer in Select Question Type
 	OtherAnswer = "Other (specify custom option)"
+	// DeferAnswer - Use as the answer to any question, to answer it later in a second pass
+	DeferAnswer = "defer"
+	// deferredPlaceholderFormat is the answer used in the output for the deferred text questions, until they are answered
+	deferredPlaceholderFormat = "<deferred: %s>"
 )
 
 // Problem defines the QA problem
@@ -57,6 +61,8 @@ type Problem struct {
 	Options []string         `yaml:"options,omitempty" json:"options,omitempty"`
 	Default interface{}      `yaml:"default,omitempty" json:"default,omitempty"`
 	Answer  interface{}      `yaml:"answer,omitempty" json:"answer,omitempty"`
+	// ValueFrom contains the reference to the value provider which the answer was resolved from
+	ValueFrom map[string]string `yaml:"-" json:"-"`
 }
 
 // SetAnswer sets the answer
@@ -64,6 +70,10 @@ func (p *Problem) SetAnswer(ansI interface{}) error {
 	if ansI == nil {
 		return fmt.Errorf("the answer is nil")
 	}
+	if ans, ok := ansI.(string); ok && ans == DeferAnswer {
+		p.Answer = DeferAnswer
+		return nil
+	}
 	switch p.Type {
 	case InputSolutionFormType, PasswordSolutionFormType, MultilineSolutionFormType, SelectSolutionFormType:
 		ans, ok := ansI.(string)
@@ -104,6 +114,50 @@ func (p *Problem) SetAnswer(ansI interface{}) error {
 	return nil
 }
 
+// getStoredAnswer returns the answer written to the answers files. The answers resolved from a value provider are written as their references.
+func (p *Problem) getStoredAnswer() interface{} {
+	if len(p.ValueFrom) > 0 {
+		return mapT{valueFromKey: p.ValueFrom}
+	}
+	return p.Answer
+}
+
+// IsDeferred returns true if the answer to the problem was deferred
+func (p *Problem) IsDeferred() bool {
+	ans, ok := p.Answer.(string)
+	return ok && ans == DeferAnswer
+}
+
+// GetDeferredAnswer returns the answer used in the output until the deferred problem is answered. The text questions are
+// answered with a placeholder which is clearly marked, and the other questions with their defaults.
+func (p *Problem) GetDeferredAnswer() interface{} {
+	switch p.Type {
+	case SelectSolutionFormType:
+		if def, ok := p.Default.(string); ok && def != "" {
+			return def
+		}
+		if len(p.Options) > 0 {
+			return p.Options[0]
+		}
+		return ""
+	case MultiSelectSolutionFormType:
+		if def, err := common.ConvertInterfaceToSliceOfStrings(p.Default); err == nil {
+			return def
+		}
+		return []string{}
+	case ConfirmSolutionFormType:
+		def, _ := p.Default.(bool)
+		return def
+	default:
+		return GetDeferredPlaceholder(p.ID)
+	}
+}
+
+// GetDeferredPlaceholder returns the placeholder written to the output for the deferred text question
+func GetDeferredPlaceholder(id string) string {
+	return fmt.Sprintf(deferredPlaceholderFormat, id)
+}
+
 // Matches checks if the problems are same
 func (p *Problem) matches(np Problem) bool {
 	return p.Type == np.Type && p.matchString(p.Desc, np.Desc)
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"io/ioutil"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+// Storage reads and writes the files of the stores, so that the answers can be kept outside the local filesystem.
+// The read of a file missing from the storage fails with an error wrapping os.ErrNotExist.
+type Storage interface {
+	Read(path string) ([]byte, error)
+	Write(path string, data []byte) error
+}
+
+// FileStorage stores the files in the local filesystem
+type FileStorage struct{}
+
+// Read reads the file at the path
+func (FileStorage) Read(path string) ([]byte, error) {
+	return ioutil.ReadFile(path)
+}
+
+// Write writes the file at the path
+func (FileStorage) Write(path string, data []byte) error {
+	return ioutil.WriteFile(path, data, common.DefaultFilePermission)
+}
+
+// IsFileStorage returns true if the storage is the local filesystem
+func IsFileStorage(storage Storage) bool {
+	if storage == nil {
+		return true
+	}
+	_, ok := storage.(FileStorage)
+	return ok
+}
+
+// writeYamlToStorage encodes the data as yaml and writes it to the storage
+func writeYamlToStorage(storage Storage, path string, data interface{}) error {
+	if IsFileStorage(storage) {
+		return common.WriteYaml(path, data)
+	}
+	yamlBytes, err := common.ObjectToYamlBytes(data)
+	if err != nil {
+		return err
+	}
+	return storage.Write(path, yamlBytes)
+}
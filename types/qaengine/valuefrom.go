@@ -0,0 +1,293 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/spf13/cast"
+)
+
+const (
+	// valueFromKey is the key of the answers which reference a value provider, like valueFrom: {env: REGISTRY_URL}
+	valueFromKey = "valueFrom"
+	// EnvValueProviderName reads the answers from the environment variables
+	EnvValueProviderName = "env"
+	// VaultValueProviderName reads the answers from the secrets of Vault, referenced as path#key
+	VaultValueProviderName = "vault"
+	// HTTPValueProviderName reads the answers from a URL, or from a field of the json returned by the URL, referenced as url#field
+	HTTPValueProviderName = "http"
+
+	vaultAddrEnvVar      = "VAULT_ADDR"
+	vaultTokenEnvVar     = "VAULT_TOKEN"
+	vaultNamespaceEnvVar = "VAULT_NAMESPACE"
+	vaultTokenFile       = ".vault-token"
+
+	// maxValueSize limits the size of the responses of the value providers
+	maxValueSize = 1024 * 1024
+)
+
+// ValueProvider resolves the references to the answers kept outside the answers files, so that the secrets and the values
+// specific to an environment are never committed with the answers
+type ValueProvider interface {
+	GetValue(ref string) (string, error)
+}
+
+var (
+	// valueProviders are the providers of the references, by their names in valueFrom
+	valueProviders = map[string]ValueProvider{
+		EnvValueProviderName:   envValueProvider{},
+		VaultValueProviderName: vaultValueProvider{},
+		HTTPValueProviderName:  httpValueProvider{},
+	}
+	valueProvidersMutex = sync.RWMutex{}
+)
+
+// AddValueProvider adds the provider of the references named name in valueFrom, or replaces the existing one
+func AddValueProvider(name string, provider ValueProvider) {
+	valueProvidersMutex.Lock()
+	defer valueProvidersMutex.Unlock()
+	valueProviders[name] = provider
+}
+
+// getValueProvider returns the provider named name, or the sorted names of the providers if there is none
+func getValueProvider(name string) (ValueProvider, []string) {
+	valueProvidersMutex.RLock()
+	defer valueProvidersMutex.RUnlock()
+	if provider, ok := valueProviders[name]; ok {
+		return provider, nil
+	}
+	names := []string{}
+	for name := range valueProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, names
+}
+
+// getValueFrom returns the references of the answer, if the answer is a valueFrom object like {valueFrom: {env: REGISTRY_URL}}
+func getValueFrom(answer interface{}) (map[string]string, bool) {
+	answerMap, ok := answer.(mapT)
+	if !ok || len(answerMap) != 1 {
+		return nil, false
+	}
+	refs, ok := answerMap[valueFromKey]
+	if !ok {
+		return nil, false
+	}
+	valueFrom := map[string]string{}
+	switch refs := refs.(type) {
+	case mapT:
+		for name, ref := range refs {
+			valueFrom[name] = cast.ToString(ref)
+		}
+	case map[string]string:
+		for name, ref := range refs {
+			valueFrom[name] = ref
+		}
+	default:
+		return nil, false
+	}
+	return valueFrom, true
+}
+
+// resolveAnswer sets the answer of the problem, resolving it using its value provider if it is a valueFrom object.
+// The references are kept in the problem, so that they are written to the answers files instead of the values.
+func resolveAnswer(p Problem, answer interface{}) (Problem, error) {
+	valueFrom, ok := getValueFrom(answer)
+	if !ok {
+		p.Answer = answer
+		return p, nil
+	}
+	if len(valueFrom) != 1 {
+		return p, fmt.Errorf("the answer to %s must reference exactly one value provider. Actual: %v", p.ID, valueFrom)
+	}
+	for name, ref := range valueFrom {
+		provider, names := getValueProvider(name)
+		if provider == nil {
+			return p, fmt.Errorf("the answer to %s references the unknown value provider %s . Valid providers are %s", p.ID, name, strings.Join(names, ", "))
+		}
+		value, err := provider.GetValue(ref)
+		if err != nil {
+			return p, fmt.Errorf("failed to get the answer to %s from the %s value provider. Error: %q", p.ID, name, err)
+		}
+		var typedValue interface{} = value
+		switch p.Type {
+		case ConfirmSolutionFormType:
+			if typedValue, err = strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+				return p, fmt.Errorf("the answer to %s from the %s value provider is not a bool. Error: %q", p.ID, name, err)
+			}
+		case MultiSelectSolutionFormType:
+			values := []string{}
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			typedValue = values
+		}
+		if err := p.SetAnswer(typedValue); err != nil {
+			return p, fmt.Errorf("the answer to %s from the %s value provider is invalid. Error: %q", p.ID, name, err)
+		}
+	}
+	p.ValueFrom = valueFrom
+	return p, nil
+}
+
+// envValueProvider reads the answers from the environment variables
+type envValueProvider struct{}
+
+// GetValue returns the value of the environment variable
+func (envValueProvider) GetValue(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("the environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// vaultValueProvider reads the answers from the secrets of Vault at VAULT_ADDR, using the token in VAULT_TOKEN or the one saved by vault login
+type vaultValueProvider struct{}
+
+// GetValue returns the key of the secret, referenced as path#key, like secret/data/registry#password for a KV version 2 secrets engine
+func (vaultValueProvider) GetValue(ref string) (string, error) {
+	path, key, ok := splitValueRef(ref)
+	if !ok {
+		return "", fmt.Errorf("the Vault reference %s is not of the form <path>#<key>", ref)
+	}
+	addr := os.Getenv(vaultAddrEnvVar)
+	if addr == "" {
+		return "", fmt.Errorf("the %s environment variable is not set", vaultAddrEnvVar)
+	}
+	token := os.Getenv(vaultTokenEnvVar)
+	if token == "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			if data, err := ioutil.ReadFile(filepath.Join(homeDir, vaultTokenFile)); err == nil {
+				token = strings.TrimSpace(string(data))
+			}
+		}
+	}
+	if token == "" {
+		return "", fmt.Errorf("no Vault token found. Either login using the vault CLI or set the %s environment variable", vaultTokenEnvVar)
+	}
+	headers := map[string]string{"X-Vault-Token": token}
+	if namespace := os.Getenv(vaultNamespaceEnvVar); namespace != "" {
+		headers["X-Vault-Namespace"] = namespace
+	}
+	body, err := getValueProviderURL(strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), headers)
+	if err != nil {
+		return "", err
+	}
+	secret := struct {
+		Data mapT `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse the Vault secret at %s . Error: %q", path, err)
+	}
+	data := secret.Data
+	// The secrets of the KV version 2 secrets engine are nested in data, along with their metadata
+	if nestedData, ok := data["data"].(mapT); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nestedData
+		}
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("the key %s is missing in the Vault secret at %s", key, path)
+	}
+	return cast.ToString(value), nil
+}
+
+// httpValueProvider reads the answers from a URL
+type httpValueProvider struct{}
+
+// GetValue returns the body returned by the URL, or the field of the json returned by the URL when it is referenced as url#field
+func (httpValueProvider) GetValue(ref string) (string, error) {
+	valueURL, field, hasField := splitValueRef(ref)
+	if !hasField {
+		valueURL = ref
+	}
+	if common.Offline {
+		return "", fmt.Errorf("unable to get %s in offline mode", valueURL)
+	}
+	body, err := getValueProviderURL(valueURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if !hasField {
+		return strings.TrimSpace(string(body)), nil
+	}
+	fields := mapT{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", fmt.Errorf("failed to parse the json returned by %s . Error: %q", valueURL, err)
+	}
+	value, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("the field %s is missing in the json returned by %s", field, valueURL)
+	}
+	return cast.ToString(value), nil
+}
+
+// splitValueRef splits the reference at its last #, into the location of the value and its key
+func splitValueRef(ref string) (string, string, bool) {
+	idx := strings.LastIndex(ref, "#")
+	if idx <= 0 || idx == len(ref)-1 {
+		return ref, "", false
+	}
+	return ref[:idx], ref[idx+1:], true
+}
+
+func getValueProviderURL(valueURL string, headers map[string]string) ([]byte, error) {
+	client := &http.Client{}
+	if common.CommandTimeout > 0 {
+		client.Timeout = common.CommandTimeout
+	}
+	req, err := http.NewRequest(http.MethodGet, valueURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("the request to get %s failed with status code %d", valueURL, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxValueSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxValueSize {
+		return nil, fmt.Errorf("the response of %s is larger than %d bytes", valueURL, maxValueSize)
+	}
+	return body, nil
+}
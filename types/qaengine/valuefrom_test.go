@@ -0,0 +1,140 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qaengine_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/move2kube/internal/common"
+	"github.com/konveyor/move2kube/types/qaengine"
+)
+
+func TestValueFrom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/secret/data/registry":
+			if r.Header.Get("X-Vault-Token") != "token1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"data": {"data": {"password": "s3cr3t"}, "metadata": {"version": 1}}}`))
+		case "/namespace":
+			w.Write([]byte("staging\n"))
+		case "/large":
+			w.Write([]byte(strings.Repeat("a", 2*1024*1024)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	os.Setenv("M2K_TEST_REGISTRY_URL", "quay.io")
+	os.Setenv("VAULT_ADDR", server.URL)
+	os.Setenv("VAULT_TOKEN", "token1")
+	defer os.Unsetenv("M2K_TEST_REGISTRY_URL")
+	defer os.Unsetenv("VAULT_ADDR")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	configData := `move2kube:
+  registry:
+    url:
+      valueFrom:
+        env: M2K_TEST_REGISTRY_URL
+    password:
+      valueFrom:
+        vault: secret/data/registry#password
+  namespace:
+    valueFrom:
+      http: ` + server.URL + `/namespace
+  missing:
+    valueFrom:
+      env: M2K_TEST_MISSING
+  large:
+    valueFrom:
+      http: ` + server.URL + `/large
+`
+	if err := ioutil.WriteFile(configPath, []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to write the config. Error: %q", err)
+	}
+	outputPath := filepath.Join(dir, "m2kconfig.yaml")
+	config := qaengine.NewConfig(outputPath, nil, []string{configPath})
+	if err := config.Load(); err != nil {
+		t.Fatalf("Failed to load the config. Error: %q", err)
+	}
+
+	urlProblem, _ := qaengine.NewInputProblem("move2kube.registry.url", "Registry?", nil, "")
+	urlProblem, err := config.GetSolution(urlProblem)
+	if err != nil || urlProblem.Answer != "quay.io" || !reflect.DeepEqual(urlProblem.ValueFrom, map[string]string{"env": "M2K_TEST_REGISTRY_URL"}) {
+		t.Fatalf("Failed to resolve the answer from the environment. Actual: %+v Error: %v", urlProblem, err)
+	}
+	passwordProblem, _ := qaengine.NewPasswordProblem("move2kube.registry.password", "Password?", nil)
+	passwordProblem, err = config.GetSolution(passwordProblem)
+	if err != nil || passwordProblem.Answer != "s3cr3t" {
+		t.Fatalf("Failed to resolve the answer from Vault. Actual: %+v Error: %v", passwordProblem, err)
+	}
+	namespaceProblem, _ := qaengine.NewInputProblem("move2kube.namespace", "Namespace?", nil, "")
+	if namespaceProblem, err = config.GetSolution(namespaceProblem); err != nil || namespaceProblem.Answer != "staging" {
+		t.Fatalf("Failed to resolve the answer from the URL. Actual: %+v Error: %v", namespaceProblem, err)
+	}
+	missingProblem, _ := qaengine.NewInputProblem("move2kube.missing", "Missing?", nil, "")
+	if _, err := config.GetSolution(missingProblem); err == nil {
+		t.Fatalf("Expected an error for the unset environment variable")
+	}
+	largeProblem, _ := qaengine.NewInputProblem("move2kube.large", "Large?", nil, "")
+	if _, err := config.GetSolution(largeProblem); err == nil {
+		t.Fatalf("Expected an error for the response larger than the limit")
+	}
+	common.Offline = true
+	namespaceProblem.Answer, namespaceProblem.ValueFrom = nil, nil
+	_, err = config.GetSolution(namespaceProblem)
+	common.Offline = false
+	if err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Fatalf("Expected the URL not to be requested in offline mode. Actual: %v", err)
+	}
+
+	// The references are written instead of the values
+	if err := config.AddSolution(urlProblem); err != nil {
+		t.Fatalf("Failed to add the answer. Error: %q", err)
+	}
+	if err := config.AddSolution(passwordProblem); err != nil {
+		t.Fatalf("Failed to add the password referencing Vault. Error: %q", err)
+	}
+	written, err := ioutil.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read the written config. Error: %q", err)
+	}
+	if strings.Contains(string(written), "s3cr3t") || strings.Contains(string(written), "quay.io") || !strings.Contains(string(written), "vault: secret/data/registry#password") {
+		t.Fatalf("Expected only the references in the written config. Actual:\n%s", written)
+	}
+
+	cache := qaengine.NewCache(filepath.Join(dir, "m2kqacache.yaml"))
+	if err := cache.AddSolution(urlProblem); err != nil {
+		t.Fatalf("Failed to add the answer to the cache. Error: %q", err)
+	}
+	urlProblem.Answer, urlProblem.ValueFrom = nil, nil
+	if urlProblem, err = cache.GetSolution(urlProblem); err != nil || urlProblem.Answer != "quay.io" {
+		t.Fatalf("Failed to resolve the answer in the cache. Actual: %+v Error: %v", urlProblem, err)
+	}
+}
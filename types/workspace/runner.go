@@ -0,0 +1,372 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCommand is the move2kube binary used by the runner
+	DefaultCommand = "move2kube"
+	jobSourceDir   = "source"
+	jobInputsDir   = "inputs"
+	jobOutputDir   = "output"
+)
+
+// Runner runs the plan and translate jobs of the projects. Each job runs the move2kube command in its own process,
+// since the QA engine is global to a process, using a local copy of the inputs of the project in the work directory.
+type Runner struct {
+	Store   *Store
+	Command string
+	WorkDir string
+}
+
+// NewRunner creates a runner for the projects in the store
+func NewRunner(store *Store, workDir string) *Runner {
+	return &Runner{Store: store, Command: DefaultCommand, WorkDir: workDir}
+}
+
+// StartJob creates a job for the project and runs it in the background. A project runs only one job at a time.
+// The questions of a translate job are served by the QA REST API on the port if it is not 0, and use the defaults otherwise.
+func (r *Runner) StartJob(workspaceID, projectID string, jobType JobType, qaPort int) (Job, error) {
+	job := NewJob(workspaceID, projectID, jobType)
+	job.QAPort = qaPort
+	_, err := r.Store.UpdateProject(workspaceID, projectID, func(project *Project) error {
+		if project.Status[PlanningProjectStatus] || project.Status[TranslatingProjectStatus] {
+			return fmt.Errorf("the project %s already has a running job", projectID)
+		}
+		switch jobType {
+		case PlanJobType:
+			if !project.Status[SourcesProjectStatus] {
+				return fmt.Errorf("the project %s has no sources to plan", projectID)
+			}
+			project.Status[PlanningProjectStatus] = true
+		case TranslateJobType:
+			if !project.Status[PlanProjectStatus] {
+				return fmt.Errorf("the project %s has no plan to translate", projectID)
+			}
+			project.Status[TranslatingProjectStatus] = true
+		default:
+			return fmt.Errorf("unsupported job type %s", jobType)
+		}
+		return nil
+	})
+	if err != nil {
+		return job, err
+	}
+	if err := r.Store.WriteJob(job); err != nil {
+		r.finishProject(job)
+		return job, err
+	}
+	go func() {
+		if err := r.Run(job); err != nil {
+			log.Errorf("The %s job %s of the project %s failed. Error: %q", job.Type, job.ID, job.ProjectID, err)
+		}
+	}()
+	return job, nil
+}
+
+// Run runs the job and records its status, its log and its results in the store
+func (r *Runner) Run(job Job) error {
+	job.Status = RunningJobStatus
+	job.StartedAt = time.Now()
+	if err := r.Store.WriteJob(job); err != nil {
+		log.Warnf("Failed to update the status of the job %s . Error: %q", job.ID, err)
+	}
+	runErr := r.run(job)
+	job.FinishedAt = time.Now()
+	job.Status = SucceededJobStatus
+	if runErr != nil {
+		job.Status = FailedJobStatus
+		job.Error = runErr.Error()
+	}
+	if err := r.Store.WriteJob(job); err != nil {
+		log.Warnf("Failed to update the status of the job %s . Error: %q", job.ID, err)
+	}
+	r.finishProject(job)
+	return runErr
+}
+
+// finishProject clears the running job status of the project
+func (r *Runner) finishProject(job Job) {
+	if _, err := r.Store.UpdateProject(job.WorkspaceID, job.ProjectID, func(project *Project) error {
+		delete(project.Status, PlanningProjectStatus)
+		delete(project.Status, TranslatingProjectStatus)
+		return nil
+	}); err != nil {
+		log.Warnf("Failed to update the status of the project %s . Error: %q", job.ProjectID, err)
+	}
+}
+
+func (r *Runner) run(job Job) error {
+	project, err := r.Store.ReadProject(job.WorkspaceID, job.ProjectID)
+	if err != nil {
+		return err
+	}
+	jobDir, err := filepath.Abs(filepath.Join(r.WorkDir, job.WorkspaceID, job.ProjectID, job.ID))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(jobDir, common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	defer os.RemoveAll(jobDir)
+	sourceDir := filepath.Join(jobDir, jobSourceDir)
+	planPath := filepath.Join(jobDir, common.DefaultPlanFile)
+	files, err := r.getInputs(project, jobDir)
+	if err != nil {
+		return err
+	}
+	args := []string{}
+	switch job.Type {
+	case PlanJobType:
+		args = []string{"plan", "-s", sourceDir, "-p", planPath, "-n", common.MakeStringDNSLabelNameCompliant(project.Name)}
+	case TranslateJobType:
+		if err := r.copyFromStore(planPath, func() (io.ReadCloser, error) { return r.Store.ReadPlan(job.WorkspaceID, job.ProjectID) }); err != nil {
+			return err
+		}
+		args = []string{"translate", "-p", planPath, "-s", sourceDir, "-o", filepath.Join(jobDir, jobOutputDir)}
+		for _, configPath := range files[ConfigProjectInput] {
+			args = append(args, "-f", configPath)
+		}
+		for _, cachePath := range files[QACacheProjectInput] {
+			args = append(args, "-q", cachePath)
+		}
+		if job.QAPort != 0 {
+			args = append(args, "--qadisablecli", fmt.Sprintf("--qaport=%d", job.QAPort))
+		} else {
+			args = append(args, "--qaskip")
+		}
+	default:
+		return fmt.Errorf("unsupported job type %s", job.Type)
+	}
+	log.Infof("Running the %s job %s of the project %s : %s %s", job.Type, job.ID, job.ProjectID, r.Command, strings.Join(args, " "))
+	// The job is subject to the timeout and the output limit of the external tools
+	output, cmdErr := common.RunCommandCombinedOutput(jobDir, r.Command, args...)
+	if err := r.Store.Storage.Write(path.Join(getJobKey(job.WorkspaceID, job.ProjectID, job.ID), jobLogFile), bytes.NewReader(output)); err != nil {
+		log.Warnf("Failed to store the log of the job %s . Error: %q", job.ID, err)
+	}
+	if cmdErr != nil {
+		return fmt.Errorf("the %s command failed. Error: %q", job.Type, cmdErr)
+	}
+	if job.Type == PlanJobType {
+		f, err := os.Open(planPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = r.Store.WritePlan(job.WorkspaceID, job.ProjectID, f)
+		return err
+	}
+	// The output directory contains the directory of the translated project
+	entries, err := ioutil.ReadDir(filepath.Join(jobDir, jobOutputDir))
+	if err != nil {
+		return err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return fmt.Errorf("expected the translated project in the output directory of the job %s", job.ID)
+	}
+	archivePath := filepath.Join(jobDir, job.ID+outputFileSuffix)
+	if err := common.CreateArchive(archivePath, filepath.Join(jobDir, jobOutputDir, entries[0].Name()), common.ZipArchiveFormat); err != nil {
+		return err
+	}
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = r.Store.addOutput(job, f)
+	return err
+}
+
+// getInputs extracts the sources of the project into the source directory of the job and copies the other inputs into
+// its inputs directory. The inputs are processed in the order in which they were uploaded.
+func (r *Runner) getInputs(project Project, jobDir string) (map[ProjectInputType][]string, error) {
+	inputs := []ProjectInput{}
+	for _, input := range project.Inputs {
+		inputs = append(inputs, input)
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Timestamp.Before(inputs[j].Timestamp) })
+	sourceDir := filepath.Join(jobDir, jobSourceDir)
+	if err := os.MkdirAll(sourceDir, common.DefaultDirectoryPermission); err != nil {
+		return nil, err
+	}
+	files := map[ProjectInputType][]string{}
+	for _, input := range inputs {
+		read := func() (io.ReadCloser, error) { return r.Store.ReadInput(project.WorkspaceID, project.ID, input.ID) }
+		if input.Type == SourcesProjectInput {
+			rc, err := read()
+			if err != nil {
+				return nil, err
+			}
+			err = extractArchive(rc, sourceDir)
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract the sources %s . Error: %q", input.Name, err)
+			}
+			continue
+		}
+		inputPath := filepath.Join(jobDir, jobInputsDir, input.ID+"-"+common.NormalizeForFilename(input.Name))
+		if err := r.copyFromStore(inputPath, read); err != nil {
+			return nil, err
+		}
+		files[input.Type] = append(files[input.Type], inputPath)
+	}
+	return files, nil
+}
+
+// copyFromStore copies the contents read from the store into a local file
+func (r *Runner) copyFromStore(filePath string, read func() (io.ReadCloser, error)) error {
+	rc, err := read()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := os.MkdirAll(filepath.Dir(filePath), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, common.DefaultFilePermission)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// extractArchive extracts a zip or a gzip compressed tar archive into the directory. The format is detected from the contents.
+func extractArchive(r io.Reader, dir string) error {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil {
+		return err
+	}
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		return extractTarGz(br, dir)
+	}
+	// The zip reader needs random access
+	contents, err := ioutil.ReadAll(br)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		return fmt.Errorf("the archive is neither a zip nor a tar.gz archive. Error: %q", err)
+	}
+	for _, f := range zr.File {
+		target, err := getExtractPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, common.DefaultDirectoryPermission); err != nil {
+				return err
+			}
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeExtractedFile(target, rc, f.Mode().Perm())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTarGz(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := getExtractPath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, common.DefaultDirectoryPermission); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeExtractedFile(target, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return err
+			}
+		default:
+			log.Debugf("Skipping the entry %s of the archive since it is not a regular file or a directory", hdr.Name)
+		}
+	}
+}
+
+// getExtractPath returns the path of the archive entry in the directory. Entries outside the directory are rejected.
+func getExtractPath(dir, name string) (string, error) {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if target != filepath.Clean(dir) && !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("the archive entry %s is outside the extraction directory", name)
+	}
+	return target, nil
+}
+
+func writeExtractedFile(target string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = common.DefaultFilePermission
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
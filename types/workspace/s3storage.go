@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service         = "s3"
+	s3SigningAlgo     = "AWS4-HMAC-SHA256"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3ContentHashName = "X-Amz-Content-Sha256"
+)
+
+// S3Storage stores the files as the objects of a bucket, in AWS S3 or any storage with a compatible API like MinIO or
+// IBM Cloud Object Storage. The requests are signed using AWS signature version 4.
+type S3Storage struct {
+	// Endpoint is the URL of the storage. Eg: https://s3.us-east-1.amazonaws.com
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Prefix is prepended to all the keys, to share a bucket
+	Prefix string
+	Client *http.Client
+}
+
+// NewS3Storage creates a storage in the bucket. The bucket is addressed using the path style, which all the compatible storages support.
+func NewS3Storage(endpoint, region, bucket, accessKeyID, secretAccessKey string) *S3Storage {
+	return &S3Storage{
+		Endpoint:        strings.TrimSuffix(endpoint, "/"),
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Client:          http.DefaultClient,
+	}
+}
+
+// Read returns the contents of the object of the key
+func (s *S3Storage) Read(key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	resp, err := s.do(http.MethodGet, s.Prefix+key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if err := checkS3Response(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Write uploads the object of the key
+func (s *S3Storage) Write(key string, contents io.Reader) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	body, err := ioutil.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodPut, s.Prefix+key, nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkS3Response(resp)
+}
+
+// Delete deletes the object of the key and all the objects under it
+func (s *S3Storage) Delete(key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	keys, err := s.List(key + "/")
+	if err != nil {
+		return err
+	}
+	for _, k := range append(keys, key) {
+		resp, err := s.do(http.MethodDelete, s.Prefix+k, nil, nil)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			err = checkS3Response(resp)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the keys of the objects under the prefix, following the continuation tokens of the listings
+func (s *S3Storage) List(prefix string) ([]string, error) {
+	keys := []string{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.Prefix + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkS3Response(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		result := struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}{}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the listing of the bucket %s . Error: %q", s.Bucket, err)
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(content.Key, s.Prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// do sends a signed request for the object of the key, or for the bucket if the key is empty
+func (s *S3Storage) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	objectPath := "/" + s.Bucket
+	if key != "" {
+		objectPath += "/" + key
+	}
+	reqURL := s.Endpoint + (&url.URL{Path: objectPath}).EscapedPath()
+	if len(query) > 0 {
+		reqURL += "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+	}
+	req, err := http.NewRequest(method, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now().UTC())
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// sign adds the AWS signature version 4 of the request
+func (s *S3Storage) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set(s3ContentHashName, hex.EncodeToString(payloadHash[:]))
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+
+	headerNames := []string{"host", strings.ToLower(s3ContentHashName), "x-amz-date"}
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		strings.ToLower(s3ContentHashName) + ":" + req.Header.Get(s3ContentHashName) + "\n" +
+		"x-amz-date:" + req.Header.Get("X-Amz-Date") + "\n"
+	signedHeaders := strings.Join(headerNames, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		req.Header.Get(s3ContentHashName),
+	}, "\n")
+	scope := strings.Join([]string{now.Format(s3DateFormat), s.Region, s3Service, "aws4_request"}, "/")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3SigningAlgo, now.Format(s3TimeFormat), scope, hex.EncodeToString(canonicalRequestHash[:])}, "\n")
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), now.Format(s3DateFormat))
+	for _, part := range []string{s.Region, s3Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3SigningAlgo, s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// checkS3Response returns an error with the message of the storage if the request failed. The caller closes the body.
+func checkS3Response(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("the object storage returned the status %s . Response: %s", resp.Status, strings.TrimSpace(string(message)))
+}
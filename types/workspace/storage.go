@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+// ErrNotFound is returned by the storages when there is nothing stored for a key
+var ErrNotFound = errors.New("not found")
+
+// Storage stores the files of the workspaces. The keys are slash separated paths, like workspaces/<id>/workspace.json
+type Storage interface {
+	// Read returns the contents stored for the key, or ErrNotFound
+	Read(key string) (io.ReadCloser, error)
+	// Write replaces the contents stored for the key
+	Write(key string, contents io.Reader) error
+	// Delete deletes the key along with all the keys under it. Deleting a missing key is not an error.
+	Delete(key string) error
+	// List returns the sorted keys under the prefix
+	List(prefix string) ([]string, error)
+}
+
+// validateKey checks that the key is a clean relative path
+func validateKey(key string) error {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return nil
+}
+
+// FileSystemStorage stores the files in a directory
+type FileSystemStorage struct {
+	RootDir string
+}
+
+// NewFileSystemStorage creates a storage in the directory, creating it if required
+func NewFileSystemStorage(rootDir string) (*FileSystemStorage, error) {
+	if err := os.MkdirAll(rootDir, common.DefaultDirectoryPermission); err != nil {
+		return nil, err
+	}
+	return &FileSystemStorage{RootDir: rootDir}, nil
+}
+
+func (s *FileSystemStorage) getPath(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.RootDir, filepath.FromSlash(key)), nil
+}
+
+// Read returns the contents of the file of the key
+func (s *FileSystemStorage) Read(key string) (io.ReadCloser, error) {
+	filePath, err := s.getPath(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Write writes the file of the key. The contents are written to a temporary file first, so readers never see a partial file.
+func (s *FileSystemStorage) Write(key string, contents io.Reader) error {
+	filePath, err := s.getPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), common.DefaultDirectoryPermission); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, contents); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filePath)
+}
+
+// Delete deletes the file or the directory of the key
+func (s *FileSystemStorage) Delete(key string) error {
+	filePath, err := s.getPath(key)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filePath)
+}
+
+// List returns the keys of the files under the prefix
+func (s *FileSystemStorage) List(prefix string) ([]string, error) {
+	dirPath := s.RootDir
+	if prefix != "" {
+		var err error
+		if dirPath, err = s.getPath(strings.TrimSuffix(prefix, "/")); err != nil {
+			return nil, err
+		}
+	}
+	keys := []string{}
+	err := filepath.Walk(dirPath, func(currPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		relPath, err := filepath.Rel(s.RootDir, currPath)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,356 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/konveyor/move2kube/internal/common"
+)
+
+const (
+	workspacesKey    = "workspaces"
+	projectsKey      = "projects"
+	inputsKey        = "inputs"
+	outputsKey       = "outputs"
+	jobsKey          = "jobs"
+	workspaceFile    = "workspace.json"
+	projectFile      = "project.json"
+	jobFile          = "job.json"
+	jobLogFile       = "job.log"
+	outputFileSuffix = ".zip"
+)
+
+// Store keeps the workspaces, the projects and the jobs in a storage. The updates of the projects are serialized, so
+// a store has to be shared by all the components writing to the same storage in a process.
+type Store struct {
+	Storage Storage
+	mutex   sync.Mutex
+}
+
+// NewStore creates a store using the storage
+func NewStore(storage Storage) *Store {
+	return &Store{Storage: storage}
+}
+
+func getWorkspaceKey(workspaceID string) string {
+	return path.Join(workspacesKey, workspaceID)
+}
+
+func getProjectKey(workspaceID, projectID string) string {
+	return path.Join(getWorkspaceKey(workspaceID), projectsKey, projectID)
+}
+
+func getJobKey(workspaceID, projectID, jobID string) string {
+	return path.Join(getProjectKey(workspaceID, projectID), jobsKey, jobID)
+}
+
+func validateIDs(ids ...string) error {
+	for _, id := range ids {
+		if !IsValidID(id) {
+			return fmt.Errorf("invalid id %q", id)
+		}
+	}
+	return nil
+}
+
+func (s *Store) readJSON(key string, out interface{}) error {
+	r, err := s.Storage.Read(key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return json.NewDecoder(r).Decode(out)
+}
+
+func (s *Store) writeJSON(key string, in interface{}) error {
+	contents, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.Storage.Write(key, bytes.NewReader(contents))
+}
+
+// ListWorkspaces returns all the workspaces
+func (s *Store) ListWorkspaces() ([]Workspace, error) {
+	keys, err := s.Storage.List(workspacesKey + "/")
+	if err != nil {
+		return nil, err
+	}
+	workspaces := []Workspace{}
+	for _, key := range keys {
+		// workspaces/<id>/workspace.json
+		parts := strings.Split(key, "/")
+		if len(parts) != 3 || parts[2] != workspaceFile {
+			continue
+		}
+		workspace, err := s.ReadWorkspace(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, nil
+}
+
+// ReadWorkspace returns the workspace, or ErrNotFound
+func (s *Store) ReadWorkspace(workspaceID string) (Workspace, error) {
+	workspace := Workspace{}
+	if err := validateIDs(workspaceID); err != nil {
+		return workspace, err
+	}
+	err := s.readJSON(path.Join(getWorkspaceKey(workspaceID), workspaceFile), &workspace)
+	return workspace, err
+}
+
+// WriteWorkspace creates or updates the workspace
+func (s *Store) WriteWorkspace(workspace Workspace) error {
+	if err := validateIDs(workspace.ID); err != nil {
+		return err
+	}
+	return s.writeJSON(path.Join(getWorkspaceKey(workspace.ID), workspaceFile), workspace)
+}
+
+// DeleteWorkspace deletes the workspace along with its projects
+func (s *Store) DeleteWorkspace(workspaceID string) error {
+	if err := validateIDs(workspaceID); err != nil {
+		return err
+	}
+	return s.Storage.Delete(getWorkspaceKey(workspaceID))
+}
+
+// ReadProject returns the project, or ErrNotFound
+func (s *Store) ReadProject(workspaceID, projectID string) (Project, error) {
+	project := Project{}
+	if err := validateIDs(workspaceID, projectID); err != nil {
+		return project, err
+	}
+	err := s.readJSON(path.Join(getProjectKey(workspaceID, projectID), projectFile), &project)
+	return project, err
+}
+
+// CreateProject adds the project to its workspace
+func (s *Store) CreateProject(project Project) error {
+	if err := validateIDs(project.WorkspaceID, project.ID); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, err := s.ReadWorkspace(project.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := s.writeJSON(path.Join(getProjectKey(project.WorkspaceID, project.ID), projectFile), project); err != nil {
+		return err
+	}
+	if !common.IsStringPresent(workspace.ProjectIDs, project.ID) {
+		workspace.ProjectIDs = append(workspace.ProjectIDs, project.ID)
+	}
+	return s.WriteWorkspace(workspace)
+}
+
+// UpdateProject reads the project, applies the update and writes it back. The updates are serialized.
+func (s *Store) UpdateProject(workspaceID, projectID string, update func(*Project) error) (Project, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	project, err := s.ReadProject(workspaceID, projectID)
+	if err != nil {
+		return project, err
+	}
+	if project.Inputs == nil {
+		project.Inputs = map[string]ProjectInput{}
+	}
+	if project.Outputs == nil {
+		project.Outputs = map[string]ProjectOutput{}
+	}
+	if project.Status == nil {
+		project.Status = map[ProjectStatus]bool{}
+	}
+	if err := update(&project); err != nil {
+		return project, err
+	}
+	err = s.writeJSON(path.Join(getProjectKey(workspaceID, projectID), projectFile), project)
+	return project, err
+}
+
+// DeleteProject removes the project from its workspace and deletes its inputs, plan, jobs and outputs
+func (s *Store) DeleteProject(workspaceID, projectID string) error {
+	if err := validateIDs(workspaceID, projectID); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	workspace, err := s.ReadWorkspace(workspaceID)
+	if err != nil {
+		return err
+	}
+	if err := s.Storage.Delete(getProjectKey(workspaceID, projectID)); err != nil {
+		return err
+	}
+	projectIDs := []string{}
+	for _, id := range workspace.ProjectIDs {
+		if id != projectID {
+			projectIDs = append(projectIDs, id)
+		}
+	}
+	workspace.ProjectIDs = projectIDs
+	return s.WriteWorkspace(workspace)
+}
+
+// AddInput stores the contents of the input and adds it to the project
+func (s *Store) AddInput(workspaceID, projectID string, input ProjectInput, contents io.Reader) (Project, error) {
+	if err := validateIDs(workspaceID, projectID, input.ID); err != nil {
+		return Project{}, err
+	}
+	if err := s.Storage.Write(path.Join(getProjectKey(workspaceID, projectID), inputsKey, input.ID), contents); err != nil {
+		return Project{}, err
+	}
+	return s.UpdateProject(workspaceID, projectID, func(project *Project) error {
+		project.Inputs[input.ID] = input
+		if input.Type == SourcesProjectInput {
+			project.Status[SourcesProjectStatus] = true
+		}
+		return nil
+	})
+}
+
+// ReadInput returns the contents of the input
+func (s *Store) ReadInput(workspaceID, projectID, inputID string) (io.ReadCloser, error) {
+	if err := validateIDs(workspaceID, projectID, inputID); err != nil {
+		return nil, err
+	}
+	return s.Storage.Read(path.Join(getProjectKey(workspaceID, projectID), inputsKey, inputID))
+}
+
+// DeleteInput removes the input from the project and deletes its contents
+func (s *Store) DeleteInput(workspaceID, projectID, inputID string) (Project, error) {
+	if err := validateIDs(workspaceID, projectID, inputID); err != nil {
+		return Project{}, err
+	}
+	project, err := s.UpdateProject(workspaceID, projectID, func(project *Project) error {
+		delete(project.Inputs, inputID)
+		hasSources := false
+		for _, input := range project.Inputs {
+			hasSources = hasSources || input.Type == SourcesProjectInput
+		}
+		project.Status[SourcesProjectStatus] = hasSources
+		return nil
+	})
+	if err != nil {
+		return project, err
+	}
+	return project, s.Storage.Delete(path.Join(getProjectKey(workspaceID, projectID), inputsKey, inputID))
+}
+
+// ReadPlan returns the plan of the project, or ErrNotFound
+func (s *Store) ReadPlan(workspaceID, projectID string) (io.ReadCloser, error) {
+	if err := validateIDs(workspaceID, projectID); err != nil {
+		return nil, err
+	}
+	return s.Storage.Read(path.Join(getProjectKey(workspaceID, projectID), common.DefaultPlanFile))
+}
+
+// WritePlan replaces the plan of the project, like when the plan is edited in the UI
+func (s *Store) WritePlan(workspaceID, projectID string, plan io.Reader) (Project, error) {
+	if err := validateIDs(workspaceID, projectID); err != nil {
+		return Project{}, err
+	}
+	if err := s.Storage.Write(path.Join(getProjectKey(workspaceID, projectID), common.DefaultPlanFile), plan); err != nil {
+		return Project{}, err
+	}
+	return s.UpdateProject(workspaceID, projectID, func(project *Project) error {
+		project.Status[PlanProjectStatus] = true
+		return nil
+	})
+}
+
+// ReadOutput returns the zip archive of the output of the project
+func (s *Store) ReadOutput(workspaceID, projectID, outputID string) (io.ReadCloser, error) {
+	if err := validateIDs(workspaceID, projectID, outputID); err != nil {
+		return nil, err
+	}
+	return s.Storage.Read(path.Join(getProjectKey(workspaceID, projectID), outputsKey, outputID+outputFileSuffix))
+}
+
+// addOutput stores the zip archive of the output of the job and adds it to the project
+func (s *Store) addOutput(job Job, archive io.Reader) (Project, error) {
+	output := ProjectOutput{ID: job.ID, JobID: job.ID, Timestamp: time.Now()}
+	if err := s.Storage.Write(path.Join(getProjectKey(job.WorkspaceID, job.ProjectID), outputsKey, output.ID+outputFileSuffix), archive); err != nil {
+		return Project{}, err
+	}
+	return s.UpdateProject(job.WorkspaceID, job.ProjectID, func(project *Project) error {
+		project.Outputs[output.ID] = output
+		project.Status[OutputsProjectStatus] = true
+		return nil
+	})
+}
+
+// ReadJob returns the job, or ErrNotFound
+func (s *Store) ReadJob(workspaceID, projectID, jobID string) (Job, error) {
+	job := Job{}
+	if err := validateIDs(workspaceID, projectID, jobID); err != nil {
+		return job, err
+	}
+	err := s.readJSON(path.Join(getJobKey(workspaceID, projectID, jobID), jobFile), &job)
+	return job, err
+}
+
+// WriteJob creates or updates the job
+func (s *Store) WriteJob(job Job) error {
+	if err := validateIDs(job.WorkspaceID, job.ProjectID, job.ID); err != nil {
+		return err
+	}
+	return s.writeJSON(path.Join(getJobKey(job.WorkspaceID, job.ProjectID, job.ID), jobFile), job)
+}
+
+// ListJobs returns the jobs of the project
+func (s *Store) ListJobs(workspaceID, projectID string) ([]Job, error) {
+	if err := validateIDs(workspaceID, projectID); err != nil {
+		return nil, err
+	}
+	keys, err := s.Storage.List(path.Join(getProjectKey(workspaceID, projectID), jobsKey) + "/")
+	if err != nil {
+		return nil, err
+	}
+	jobs := []Job{}
+	for _, key := range keys {
+		if path.Base(key) != jobFile {
+			continue
+		}
+		job := Job{}
+		if err := s.readJSON(key, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// ReadJobLog returns the output of the move2kube command of the job
+func (s *Store) ReadJobLog(workspaceID, projectID, jobID string) (io.ReadCloser, error) {
+	if err := validateIDs(workspaceID, projectID, jobID); err != nil {
+		return nil, err
+	}
+	return s.Storage.Read(path.Join(getJobKey(workspaceID, projectID, jobID), jobLogFile))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workspace contains the workspaces, projects and jobs of the move2kube UI and API server, the storages in
+// which they are kept and the runner of the plan and translate jobs, so that all of them share the same semantics.
+package workspace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Workspace groups the projects of a team
+type Workspace struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	ProjectIDs  []string  `json:"projectIds,omitempty"`
+}
+
+// ProjectInputType is the type of an input of a project
+type ProjectInputType string
+
+const (
+	// SourcesProjectInput is a zip or tar.gz archive of the source directory, including the output of move2kube collect
+	SourcesProjectInput ProjectInputType = "sources"
+	// ConfigProjectInput is a config file with the answers to the questions
+	ConfigProjectInput ProjectInputType = "config"
+	// QACacheProjectInput is a cache file with the answers of a previous translation
+	QACacheProjectInput ProjectInputType = "qacache"
+)
+
+// ProjectInput is a file uploaded to a project
+type ProjectInput struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Type      ProjectInputType `json:"type"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// ProjectOutput is the packaged output of a translate job
+type ProjectOutput struct {
+	ID        string    `json:"id"`
+	JobID     string    `json:"jobId"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ProjectStatus is a milestone reached by a project
+type ProjectStatus string
+
+const (
+	// SourcesProjectStatus is set when the project has sources to plan
+	SourcesProjectStatus ProjectStatus = "sources"
+	// PlanningProjectStatus is set while a plan job is running
+	PlanningProjectStatus ProjectStatus = "planning"
+	// PlanProjectStatus is set when the project has a plan to translate
+	PlanProjectStatus ProjectStatus = "plan"
+	// TranslatingProjectStatus is set while a translate job is running
+	TranslatingProjectStatus ProjectStatus = "translating"
+	// OutputsProjectStatus is set when the project has outputs to download
+	OutputsProjectStatus ProjectStatus = "outputs"
+)
+
+// Project contains the inputs, the plan and the outputs of a migration
+type Project struct {
+	ID          string                   `json:"id"`
+	WorkspaceID string                   `json:"workspaceId"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Timestamp   time.Time                `json:"timestamp"`
+	Inputs      map[string]ProjectInput  `json:"inputs,omitempty"`
+	Outputs     map[string]ProjectOutput `json:"outputs,omitempty"`
+	Status      map[ProjectStatus]bool   `json:"status,omitempty"`
+}
+
+// JobType is the move2kube command run by a job
+type JobType string
+
+const (
+	// PlanJobType plans the sources of the project
+	PlanJobType JobType = "plan"
+	// TranslateJobType translates the plan of the project
+	TranslateJobType JobType = "translate"
+)
+
+// JobStatus is the state of a job
+type JobStatus string
+
+const (
+	// PendingJobStatus is the status of a job which has not started yet
+	PendingJobStatus JobStatus = "pending"
+	// RunningJobStatus is the status of a running job
+	RunningJobStatus JobStatus = "running"
+	// SucceededJobStatus is the status of a job which finished successfully
+	SucceededJobStatus JobStatus = "succeeded"
+	// FailedJobStatus is the status of a job which failed. The error is in the job.
+	FailedJobStatus JobStatus = "failed"
+)
+
+// Job is a run of a move2kube command for a project
+type Job struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	ProjectID   string    `json:"projectId"`
+	Type        JobType   `json:"type"`
+	Status      JobStatus `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	// QAPort is the port of the QA REST API of a translate job. The questions are answered using the defaults if it is 0.
+	QAPort     int       `json:"qaPort,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	StartedAt  time.Time `json:"startedAt,omitempty"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+var idRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// NewID returns a new random id for a workspace, a project, an input or a job
+func NewID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// IsValidID returns true if the id can be used as a key in the storages
+func IsValidID(id string) bool {
+	return idRegexp.MatchString(id)
+}
+
+// NewWorkspace creates a new workspace with a new id
+func NewWorkspace(name, description string) Workspace {
+	return Workspace{ID: NewID(), Name: name, Description: description, Timestamp: time.Now()}
+}
+
+// NewProject creates a new project in the workspace with a new id
+func NewProject(workspaceID, name, description string) Project {
+	return Project{
+		ID:          NewID(),
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Description: description,
+		Timestamp:   time.Now(),
+		Inputs:      map[string]ProjectInput{},
+		Outputs:     map[string]ProjectOutput{},
+		Status:      map[ProjectStatus]bool{},
+	}
+}
+
+// NewJob creates a new pending job for the project
+func NewJob(workspaceID, projectID string, jobType JobType) Job {
+	return Job{ID: NewID(), WorkspaceID: workspaceID, ProjectID: projectID, Type: jobType, Status: PendingJobStatus, CreatedAt: time.Now()}
+}
+
+// IsFinished returns true if the job succeeded or failed
+func (job Job) IsFinished() bool {
+	return job.Status == SucceededJobStatus || job.Status == FailedJobStatus
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corporation 2020
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace_test
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/konveyor/move2kube/types/workspace"
+)
+
+func testStorage(t *testing.T, storage workspace.Storage) {
+	if _, err := storage.Read("workspaces/w1/workspace.json"); err != workspace.ErrNotFound {
+		t.Fatalf("Expected ErrNotFound for a missing key. Actual: %v", err)
+	}
+	for _, key := range []string{"a/b/c", "a/d", "e"} {
+		if err := storage.Write(key, strings.NewReader(key)); err != nil {
+			t.Fatalf("Failed to write the key %s . Error: %q", key, err)
+		}
+	}
+	rc, err := storage.Read("a/b/c")
+	if err != nil {
+		t.Fatalf("Failed to read the key. Error: %q", err)
+	}
+	contents, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	if string(contents) != "a/b/c" {
+		t.Fatalf("Expected the contents a/b/c . Actual: %s", contents)
+	}
+	keys, err := storage.List("a/")
+	if err != nil || strings.Join(keys, ",") != "a/b/c,a/d" {
+		t.Fatalf("Expected the keys a/b/c and a/d . Actual: %v Error: %v", keys, err)
+	}
+	if err := storage.Delete("a"); err != nil {
+		t.Fatalf("Failed to delete the key. Error: %q", err)
+	}
+	if keys, err := storage.List(""); err != nil || strings.Join(keys, ",") != "e" {
+		t.Fatalf("Expected only the key e after deleting a. Actual: %v Error: %v", keys, err)
+	}
+	if err := storage.Write("../outside", strings.NewReader("")); err == nil {
+		t.Fatalf("Expected a key outside the storage to be rejected")
+	}
+}
+
+func TestFileSystemStorage(t *testing.T) {
+	storage, err := workspace.NewFileSystemStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create the storage. Error: %q", err)
+	}
+	testStorage(t, storage)
+}
+
+// newFakeS3Server serves the objects of a bucket from memory
+func newFakeS3Server(t *testing.T, bucket string) *httptest.Server {
+	objects := map[string]string{}
+	mutex := sync.Mutex{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			t.Errorf("Expected the request to be signed. Actual: %s", r.Header.Get("Authorization"))
+		}
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+bucket), "/")
+		switch {
+		case r.Method == http.MethodGet && key == "":
+			result := struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []struct {
+					Key string `xml:"Key"`
+				} `xml:"Contents"`
+			}{}
+			keys := []string{}
+			for k := range objects {
+				if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				result.Contents = append(result.Contents, struct {
+					Key string `xml:"Key"`
+				}{Key: k})
+			}
+			xml.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodGet:
+			contents, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(contents))
+		case r.Method == http.MethodPut:
+			contents, _ := ioutil.ReadAll(r.Body)
+			objects[key] = string(contents)
+		case r.Method == http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestS3Storage(t *testing.T) {
+	server := newFakeS3Server(t, "bucket")
+	defer server.Close()
+	testStorage(t, workspace.NewS3Storage(server.URL, "us-east-1", "bucket", "key", "secret"))
+}
+
+func TestStore(t *testing.T) {
+	storage, err := workspace.NewFileSystemStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create the storage. Error: %q", err)
+	}
+	store := workspace.NewStore(storage)
+	w := workspace.NewWorkspace("team", "")
+	if err := store.WriteWorkspace(w); err != nil {
+		t.Fatalf("Failed to write the workspace. Error: %q", err)
+	}
+	p := workspace.NewProject(w.ID, "app", "")
+	if err := store.CreateProject(p); err != nil {
+		t.Fatalf("Failed to create the project. Error: %q", err)
+	}
+	input := workspace.ProjectInput{ID: workspace.NewID(), Name: "src.zip", Type: workspace.SourcesProjectInput}
+	p, err = store.AddInput(w.ID, p.ID, input, strings.NewReader("zip"))
+	if err != nil {
+		t.Fatalf("Failed to add the input. Error: %q", err)
+	}
+	if _, ok := p.Inputs[input.ID]; !ok || !p.Status[workspace.SourcesProjectStatus] {
+		t.Fatalf("Expected the project to have the sources. Actual: %+v", p)
+	}
+	workspaces, err := store.ListWorkspaces()
+	if err != nil || len(workspaces) != 1 || len(workspaces[0].ProjectIDs) != 1 || workspaces[0].ProjectIDs[0] != p.ID {
+		t.Fatalf("Expected the workspace with the project. Actual: %+v Error: %v", workspaces, err)
+	}
+	job := workspace.NewJob(w.ID, p.ID, workspace.PlanJobType)
+	if err := store.WriteJob(job); err != nil {
+		t.Fatalf("Failed to write the job. Error: %q", err)
+	}
+	if jobs, err := store.ListJobs(w.ID, p.ID); err != nil || len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Fatalf("Expected the job of the project. Actual: %+v Error: %v", jobs, err)
+	}
+	if err := store.DeleteProject(w.ID, p.ID); err != nil {
+		t.Fatalf("Failed to delete the project. Error: %q", err)
+	}
+	if _, err := store.ReadProject(w.ID, p.ID); err != workspace.ErrNotFound {
+		t.Fatalf("Expected the project to be deleted. Actual: %v", err)
+	}
+	if _, err := store.ReadWorkspace("../w"); err == nil {
+		t.Fatalf("Expected an invalid id to be rejected")
+	}
+}